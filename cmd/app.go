@@ -9,16 +9,31 @@ import (
 	"syscall"
 	"time"
 
+	"cinematique/internal/accesslog"
+	"cinematique/internal/apperror"
 	"cinematique/internal/auth"
+	"cinematique/internal/bodylimit"
+	"cinematique/internal/cachebus"
 	"cinematique/internal/config"
 	"cinematique/internal/controller"
+	"cinematique/internal/domain"
+	"cinematique/internal/eventbus"
 	"cinematique/internal/handlers"
+	"cinematique/internal/health"
+	"cinematique/internal/idempotency"
+	"cinematique/internal/integrations/tmdb"
+	"cinematique/internal/jobs"
 	"cinematique/internal/kafka"
+	"cinematique/internal/kafka/events"
 	"cinematique/internal/keycloak"
+	"cinematique/internal/mailer"
 	"cinematique/internal/postgres"
 	"cinematique/internal/ratelimit"
 	"cinematique/internal/repository"
 	"cinematique/internal/service"
+	"cinematique/internal/storage"
+	"cinematique/internal/tracing"
+	"strings"
 	"sync"
 
 	"github.com/gin-gonic/gin"
@@ -28,7 +43,7 @@ import (
 )
 
 const (
-	UserRegistrationTopic = "user-registration"
+	UserRegistrationTopic = handlers.UserRegistrationTopic
 	MovieViewsTopic       = "movie-views"
 	MovieSearchesTopic    = "movie-searches"
 
@@ -75,11 +90,110 @@ func PrometheusMiddleware() gin.HandlerFunc {
 	}
 }
 
+// passwordHasher собирает параметры хэширования паролей из настроек. Неизвестный алгоритм
+// заменяется на argon2id, непригодные числовые параметры — значениями по умолчанию
+func passwordHasher(cfg config.AuthConfig) service.PasswordHasher {
+	algorithm, ok := service.ParsePasswordHashAlgorithm(cfg.PasswordHash)
+	if !ok {
+		log.Printf("Unknown AUTH_PASSWORD_HASH %q, new passwords are hashed with argon2id", cfg.PasswordHash)
+		algorithm = service.PasswordHashArgon2id
+	}
+	hasher := service.PasswordHasher{Algorithm: algorithm, BcryptCost: cfg.BcryptCost}
+	if cfg.Argon2MemoryKB > 0 {
+		hasher.Argon2.Memory = uint32(cfg.Argon2MemoryKB)
+	}
+	if cfg.Argon2Iterations > 0 {
+		hasher.Argon2.Iterations = uint32(cfg.Argon2Iterations)
+	}
+	if cfg.Argon2Parallelism > 0 && cfg.Argon2Parallelism <= 255 {
+		hasher.Argon2.Parallelism = uint8(cfg.Argon2Parallelism)
+	}
+	return hasher
+}
+
+// keycloakDirectory отдаёт пользователей realm синхронизации service.UserSync
+type keycloakDirectory struct {
+	client *keycloak.Client
+}
+
+func (d keycloakDirectory) ListUsers(ctx context.Context) ([]domain.ExternalUser, error) {
+	users, err := d.client.ListUsers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	external := make([]domain.ExternalUser, 0, len(users))
+	for _, user := range users {
+		external = append(external, domain.ExternalUser{
+			ExternalID: user.ID,
+			Username:   user.Username,
+			Email:      user.Email,
+			Role:       user.LocalRole,
+		})
+	}
+	return external, nil
+}
+
+// movieViewedHandler засчитывает просмотры фильмов из событий movie_viewed
+func movieViewedHandler(movieService *service.MovieService) kafka.EventHandler {
+	return func(ctx context.Context, event kafka.Event) error {
+		if event.Type != events.TypeMovieViewed {
+			return nil
+		}
+		var viewed events.MovieViewed
+		if err := events.Bind(event, &viewed); err != nil {
+			return err
+		}
+		return movieService.RecordMovieView(ctx, viewed.MovieID)
+	}
+}
+
+// movieSearchedHandler учитывает поисковые запросы в поисковой статистике
+func movieSearchedHandler(searchService *service.SearchService) kafka.EventHandler {
+	return func(ctx context.Context, event kafka.Event) error {
+		if event.Type != events.TypeMovieSearched {
+			return nil
+		}
+		var searched events.MovieSearched
+		if err := events.Bind(event, &searched); err != nil {
+			return err
+		}
+		term := firstValue(searched.Query["title"])
+		if term == "" {
+			term = firstValue(searched.Query["actorName"])
+		}
+		if searched.Timestamp.IsZero() {
+			searched.Timestamp = time.Now()
+		}
+		return searchService.RecordSearch(ctx, term, searched.Results, searched.Timestamp)
+	}
+}
+
+func firstValue(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
 // Run инициализирует и запускает приложение с поддержкой корректного завершения (graceful shutdown)
 func Run() error {
 	// Загружаем конфигурацию
 	cfg := config.LoadConfig()
 
+	// Трассировка OpenTelemetry: HTTP-запросы, сервисы, запросы к БД и отправка в Kafka
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg.Tracing.ToTracingConfig())
+	if err != nil {
+		log.Printf("Failed to initialize tracing: %v", err)
+		return err
+	}
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := shutdownTracing(ctx); err != nil {
+			log.Printf("Error flushing traces: %v", err)
+		}
+	}()
+
 	// Инициализируем JWT-ключ
 	if err := auth.InitJWTKey(); err != nil {
 		log.Fatalf("Failed to initialize JWT key: %v", err)
@@ -94,7 +208,12 @@ func Run() error {
 	}
 
 	// Подключаемся к базе данных
-	db, err := postgres.Connect()
+	dbCfg, err := postgres.GetConfig()
+	if err != nil {
+		log.Printf("Failed to load database configuration: %v", err)
+		return err
+	}
+	db, err := postgres.Open(dbCfg)
 	if err != nil {
 		log.Printf("Failed to connect to database: %v", err)
 		return err
@@ -104,6 +223,17 @@ func Run() error {
 	// Регистрируем метрики базы данных
 	postgres.RegisterDBMetrics(db)
 
+	// Реплика для чтения необязательна: если она не настроена или не отвечает при старте,
+	// все запросы идут в основную БД
+	var readReplica *repository.ReadReplica
+	replicaDB, err := postgres.OpenReplica(dbCfg)
+	if err != nil {
+		log.Printf("Read replica unavailable, reading from primary: %v", err)
+	} else if replicaDB != nil {
+		defer replicaDB.Close()
+		readReplica = repository.NewReadReplica(replicaDB)
+	}
+
 	// Инициализируем Redis клиента
 	redisClient := redis.NewClient(&redis.Options{
 		Addr:     "redis:6379",
@@ -116,6 +246,9 @@ func Run() error {
 		log.Fatalf("Failed to connect to Redis: %v", err)
 	}
 
+	// Отзывы токенов (смена пароля, удаление учётной записи) должны видеть все экземпляры
+	auth.SetRevocationStore(auth.NewRedisRevocationStore(redisClient))
+
 	// Инициализируем rate limiter
 	rateLimiter := ratelimit.NewRedisRateLimiter(
 		redisClient,
@@ -128,89 +261,304 @@ func Run() error {
 		Enabled:             cfg.RateLimit.Enabled,
 		RestrictedEndpoints: cfg.RateLimit.RestrictedEndpoints,
 		GetUserID: func(c *gin.Context) string {
-			if userID, exists := c.Get("user_id"); exists {
-				if id, ok := userID.(string); ok {
-					return id
-				}
+			if user, ok := auth.CurrentUser(c); ok && user.UserID != "" {
+				return user.UserID
 			}
 			return "anonymous"
 		},
 	}
 
+	// Поиск ограничивается ещё и своей корзиной с меньшим лимитом и отдельными ключами в Redis
+	searchLimiter := ratelimit.NewRedisRateLimiter(
+		redisClient,
+		cfg.RateLimit.SearchRequestsPerMinute,
+		time.Duration(cfg.RateLimit.WindowSeconds)*time.Second,
+	).WithKeyPrefix("ratelimit:search")
+	searchLimitConfig := rateLimitConfig
+	searchLimitConfig.Enabled = cfg.RateLimit.Enabled && cfg.RateLimit.SearchRequestsPerMinute > 0 && len(cfg.RateLimit.SearchEndpoints) > 0
+	searchLimitConfig.RestrictedEndpoints = cfg.RateLimit.SearchEndpoints
+
 	// Инициализируем Kafka-продюсер и пул
 	kafkaBrokerAddress := os.Getenv("KAFKA_BROKER_ADDRESS")
 	if kafkaBrokerAddress == "" {
 		kafkaBrokerAddress = "localhost:9092" // Адрес по умолчанию для Kafka в docker-compose
 	}
 	producerCfg := kafka.NewProducerConfig(kafkaBrokerAddress)
+	deadLetterTopic := producerCfg.DLQTopic
+	producerCfg.DLQTopic = "" // недоставленные сообщения отправляет в DLQ пул, после своих повторов
 	eventProducer := kafka.NewProducer(producerCfg)
-	eventProducerPool := kafka.NewProducerPool(eventProducer, 2, 256) // 2 воркера, буфер на 256 сообщений
-	defer eventProducerPool.Close()                                   // Корректно закрываем пул при завершении приложения
+	// События изменений каталога дублируются подписчикам потока /api/events
+	catalogBroadcaster := kafka.NewBroadcaster(handlers.CatalogChangesTopic)
+	backpressure, ok := kafka.ParseBackpressurePolicy(cfg.Kafka.BackpressurePolicy)
+	if !ok {
+		log.Printf("Unknown KAFKA_BACKPRESSURE_POLICY %q, new events are dropped when the buffer is full", cfg.Kafka.BackpressurePolicy)
+		backpressure = kafka.BackpressureDropNew
+	}
+	eventProducerPool := kafka.NewProducerPool(eventProducer, cfg.Kafka.ProducerWorkers, cfg.Kafka.ProducerBufferSize,
+		kafka.WithRetry(3, 200*time.Millisecond, 5*time.Second),
+		kafka.WithDeadLetterTopic(deadLetterTopic),
+		kafka.WithBroadcaster(catalogBroadcaster),
+		kafka.WithBackpressure(backpressure, cfg.Kafka.BlockTimeout))
+	defer eventProducerPool.Close() // Корректно закрываем пул при завершении приложения
+
+	// Обработчики публикуют доменные события в шину; отправка в Kafka — один из её подписчиков
+	eventBus := eventbus.New()
+	eventBus.Subscribe("kafka", eventbus.Kafka(eventProducerPool))
 
-	// Инициализация Kafka-консьюмеров
-	userRegConsumer := kafka.NewConsumer(kafka.NewConsumerConfig(kafkaBrokerAddress, UserEventsGroup, UserRegistrationTopic))
-	movieViewsConsumer := kafka.NewConsumer(kafka.NewConsumerConfig(kafkaBrokerAddress, MovieEventsGroup, MovieViewsTopic))
-	movieSearchesConsumer := kafka.NewConsumer(kafka.NewConsumerConfig(kafkaBrokerAddress, MovieEventsGroup, MovieSearchesTopic))
+	// Декодер входящих событий. Версии схем описаны в пакете events; при изменении схемы
+	// события там повышается версия, а здесь регистрируется upcaster с предыдущей версии
+	eventDecoder := events.RegisterSchemas(kafka.NewDecoder())
+
+	// Инициализация репозиториев
+	repository.SetQueryTimeouts(repository.QueryTimeouts{Read: dbCfg.ReadTimeout, Write: dbCfg.WriteTimeout})
+	movieRepo := repository.NewMovie(db).WithReadReplica(readReplica)
+	actorRepo := repository.NewActor(db).WithReadReplica(readReplica)
+	if dbCfg.PreparedStatements {
+		movieRepo.WithPreparedStatements()
+		actorRepo.WithPreparedStatements()
+		defer movieRepo.Close()
+		defer actorRepo.Close()
+	}
+	userRepo := repository.NewUserRepository(db)
+	searchRepo := repository.NewSearch(db).WithReadReplica(readReplica)
+	collectionRepo := repository.NewCollection(db).WithReadReplica(readReplica)
+	statsRepo := repository.NewStats(db).WithReadReplica(readReplica)
+	// Служебные операции выполняются на основной базе: реплика доступна только для чтения
+	maintenanceRepo := repository.NewMaintenance(db)
+
+	// Хранилище файлов: фотографии актёров
+	fileStorage, err := storage.NewLocal(cfg.Storage.Dir, cfg.Storage.BaseURL)
+	if err != nil {
+		log.Printf("Failed to initialize file storage: %v", err)
+		return err
+	}
+
+	// Кэш списка актёров с фильмами: сбрасывается сервисами при изменении актёров и фильмов
+	var actorsCache *service.ActorsWithMoviesCache
+	if cfg.Cache.ActorsWithMoviesTTL > 0 {
+		actorsCache = service.NewActorsWithMoviesCache(cfg.Cache.ActorsWithMoviesTTL)
+	}
+	// Другие экземпляры узнают о сбросе кэша через канал Redis; сообщения с других
+	// экземпляров сбрасывают только локальную копию
+	cacheBus := cachebus.New(redisClient, cachebus.DefaultChannel)
+	if actorsCache != nil {
+		actorsCache.WithPublisher(cacheBus)
+		cacheBus.Handle(service.ActorsWithMoviesCacheKey, actorsCache.InvalidateLocal)
+	}
+
+	// Инициализация сервисов
+	movieService := service.NewMovie(movieRepo, actorRepo).
+		WithTitleSearchCanary(cfg.Canary.TitleSearchPercent).
+		WithActorsCache(actorsCache).
+		WithRatingWeights(domain.RatingWeights{
+			domain.RatingSourceInternal:       cfg.Ratings.InternalWeight,
+			domain.RatingSourceIMDb:           cfg.Ratings.IMDbWeight,
+			domain.RatingSourceRottenTomatoes: cfg.Ratings.RottenTomatoesWeight,
+		})
+	if policy, ok := domain.ParseMovieDeletePolicy(cfg.Catalog.MovieDeletePolicy); ok {
+		movieService.WithDeletePolicy(policy)
+	} else {
+		log.Printf("Unknown MOVIE_DELETE_POLICY %q, movies are deleted together with their references", cfg.Catalog.MovieDeletePolicy)
+	}
+	if cfg.TMDB.APIKey != "" {
+		movieService.WithExternalSource(tmdb.NewClient(cfg.TMDB.BaseURL, cfg.TMDB.APIKey).WithMaxCast(cfg.TMDB.MaxCast))
+	}
+	actorService := service.NewActor(actorRepo, fileStorage).
+		WithActorsCache(actorsCache).
+		WithOrphanPurgeThreshold(cfg.Admin.OrphanPurgeThreshold)
+	authService := service.NewAuthService(userRepo).
+		WithPasswordHasher(passwordHasher(cfg.Auth)).
+		WithPasswordPolicy(service.PasswordPolicy{
+			MinLength:     cfg.Auth.PasswordMinLength,
+			RequireUpper:  cfg.Auth.PasswordRequireUpper,
+			RequireLower:  cfg.Auth.PasswordRequireLower,
+			RequireDigit:  cfg.Auth.PasswordRequireDigit,
+			RequireSymbol: cfg.Auth.PasswordRequireSymbol,
+		}).
+		WithLockout(cfg.Auth.MaxFailedLogins, cfg.Auth.LockoutDuration, handlers.NewLockoutPublisher(eventBus))
+	if cfg.Mailer.SMTPHost != "" {
+		authService.WithPasswordReset(mailer.NewSMTP(cfg.Mailer.ToSMTPConfig()), cfg.Auth.PasswordResetURL, cfg.Auth.PasswordResetTTL)
+	} else {
+		log.Println("SMTP_HOST is not set, password reset by email is disabled")
+	}
+	// Версия токенов сверяется с учётной записью, чтобы смена роли действовала без повторного входа
+	auth.SetTokenVersionSource(auth.NewCachedTokenVersions(authService, cfg.Auth.TokenVersionCacheTTL))
+	searchService := service.NewSearch(searchRepo)
+	collectionService := service.NewCollection(collectionRepo, movieRepo)
+	statsService := service.NewStats(statsRepo, cfg.Cache.StatsTTL)
+	maintenanceService := service.NewMaintenance(maintenanceRepo)
+
+	// Инициализация Kafka-консьюмеров. Журнал обработанных сообщений защищает
+	// обработчики от повторной доставки после падений и перемотки смещений
+	messageLedger := repository.NewMessageLedger(db)
+	userRegConsumer := kafka.NewConsumer(kafka.NewConsumerConfig(kafkaBrokerAddress, UserEventsGroup, UserRegistrationTopic)).
+		WithDecoder(eventDecoder, nil).
+		WithLedger(messageLedger)
+	movieViewsConsumer := kafka.NewConsumer(kafka.NewConsumerConfig(kafkaBrokerAddress, MovieEventsGroup, MovieViewsTopic)).
+		WithDecoder(eventDecoder, movieViewedHandler(movieService)).
+		WithLedger(messageLedger)
+	movieSearchesConsumer := kafka.NewConsumer(kafka.NewConsumerConfig(kafkaBrokerAddress, MovieEventsGroup, MovieSearchesTopic)).
+		WithDecoder(eventDecoder, movieSearchedHandler(searchService)).
+		WithLedger(messageLedger)
 
 	consumers := []*kafka.Consumer{userRegConsumer, movieViewsConsumer, movieSearchesConsumer}
 
 	// Запускаем консьюмеры в отдельных горутинах
 	consumerCtx, consumerCancel := context.WithCancel(context.Background())
 	var wg sync.WaitGroup
-	for _, c := range consumers {
+	startConsumer := func(consumer *kafka.Consumer) {
 		wg.Add(1)
-		go func(consumer *kafka.Consumer) {
+		go func() {
 			defer wg.Done()
 			consumer.ConsumeMessages(consumerCtx)
-		}(c)
+		}()
+	}
+	for _, c := range consumers {
+		startConsumer(c)
 	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		cacheBus.Run(consumerCtx)
+	}()
 
-	// Инициализация репозиториев
-	movieRepo := repository.NewMovie(db)
-	actorRepo := repository.NewActor(db)
-	userRepo := repository.NewUserRepository(db)
+	// Пользователи Keycloak получают записи в users, и их ID попадает в контекст запроса
+	if cfg.Keycloak.Enabled && cfg.Keycloak.ClientSecret != "" && cfg.Keycloak.UserSyncInterval > 0 {
+		directory := keycloakDirectory{client: keycloak.NewClient(cfg.Keycloak.ToKeycloakConfig())}
+		userSync := service.NewUserSync(directory, userRepo, cfg.Keycloak.UserSyncInterval)
+		auth.SetKeycloakUserSource(userSync)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			userSync.Run(consumerCtx)
+		}()
+	} else if cfg.Keycloak.Enabled && cfg.Keycloak.ClientSecret == "" {
+		log.Println("KEYCLOAK_CLIENT_SECRET is not set, Keycloak users are not synced to the users table")
+	}
 
-	// Инициализация сервисов
-	movieService := service.NewMovie(movieRepo, actorRepo)
-	actorService := service.NewActor(actorRepo)
-	authService := service.NewAuthService(userRepo)
+	// Супервизор следит за подсистемами и перезапускает упавшие внутри процесса
+	supervisor := health.NewSupervisor(10*time.Second, time.Second, time.Minute)
+	supervisor.Register("database", func(ctx context.Context) error { return db.PingContext(ctx) }, health.Critical())
+	supervisor.Register("cache", func(ctx context.Context) error { return redisClient.Ping(ctx).Err() })
+	if readReplica != nil {
+		// Проверка переключает чтения на основную БД, пока реплика недоступна
+		supervisor.Register("database_replica", readReplica.Check)
+	}
+	supervisor.Register("kafka_producer_pool",
+		func(ctx context.Context) error { return eventProducerPool.Healthy() },
+		health.WithRestart(func(ctx context.Context) error { return eventProducerPool.Restart() }))
+	for _, consumer := range consumers {
+		supervisor.Register("kafka_consumer_"+consumer.Topic(),
+			func(ctx context.Context) error { return consumer.Healthy() },
+			health.WithRestart(func(ctx context.Context) error {
+				startConsumer(consumer)
+				return nil
+			}))
+	}
+	supervisorCtx, supervisorCancel := context.WithCancel(context.Background())
+	go supervisor.Run(supervisorCtx)
 
 	// Инициализация контроллеров
-	actorController := controller.NewActorController(actorService)
-	movieController := controller.NewMovieController(movieService)
+	listDefaults := controller.ListDefaults{
+		MovieSort:   cfg.Listing.MovieSort,
+		Movies:      controller.PageSize{Default: cfg.Listing.MoviesPageSize, Max: cfg.Listing.MoviesMaxPageSize},
+		MovieCursor: controller.PageSize{Default: cfg.Listing.MovieCursorPageSize, Max: cfg.Listing.MovieCursorMaxPageSize},
+		Popular:     controller.PageSize{Default: cfg.Listing.PopularPageSize, Max: cfg.Listing.PopularMaxPageSize},
+		Actors:      controller.PageSize{Default: cfg.Listing.ActorsPageSize, Max: cfg.Listing.ActorsMaxPageSize},
+		Cast:        controller.PageSize{Default: cfg.Listing.CastPageSize, Max: cfg.Listing.CastMaxPageSize},
+		Search:      controller.PageSize{Default: cfg.Listing.SearchPageSize, Max: cfg.Listing.SearchMaxPageSize},
+	}
+	actorController := controller.NewActorController(actorService).WithListDefaults(listDefaults)
+	movieController := controller.NewMovieController(movieService).WithSearch(searchService).WithListDefaults(listDefaults)
+	collectionController := controller.NewCollectionController(collectionService)
+	statsController := controller.NewStatsController(statsService, searchService)
+	searchController := controller.NewSearchController(searchService).WithListDefaults(listDefaults)
 
 	// Инициализация хендлеров, передавая Kafka продюсер
-	actorHandler := handlers.NewActorHandler(actorController)
-	movieHandler := handlers.NewMovieHandler(movieController, eventProducerPool)
-	authHandler := handlers.NewAuthHandler(authService, eventProducerPool)
+	actorHandler := handlers.NewActorHandler(actorController, eventBus)
+	// Ответы на запросы с Idempotency-Key хранятся в Redis, общем для всех экземпляров
+	idempotencyGuard := idempotency.New(idempotency.NewRedisStore(redisClient), cfg.Idempotency.TTL)
+	movieHandler := handlers.NewMovieHandler(movieController, eventBus).
+		WithIdempotency(idempotencyGuard).
+		WithStrictEvents(cfg.Catalog.StrictEvents)
+	authHandler := handlers.NewAuthHandler(authService, eventBus).WithIdempotency(idempotencyGuard)
+	jobManager := jobs.NewManager(time.Hour) // завершённые задачи хранятся час
+	defer jobManager.Close()
+	adminHandler := handlers.NewAdminHandler(actorController, movieController, jobManager, eventBus).
+		WithStrictEvents(cfg.Catalog.StrictEvents).
+		WithMaintenance(controller.NewMaintenanceController(maintenanceService))
+	healthHandler := handlers.NewHealthHandler(supervisor)
+	collectionHandler := handlers.NewCollectionHandler(collectionController)
+	eventsHandler := handlers.NewEventsHandler(catalogBroadcaster)
+	statsHandler := handlers.NewStatsHandler(statsController)
+	searchHandler := handlers.NewSearchHandler(searchController)
 
 	// Настраиваем логирование
 	log.SetOutput(os.Stdout)
 	log.Println("Logging to stdout is configured")
 
 	// Настраиваем роутер
-	router := gin.Default()
+	router := gin.New()
+	router.Use(gin.Recovery())
+
+	// Span запроса открывается первым, чтобы в него попали все остальные middleware
+	router.Use(tracing.Middleware())
+
+	// Журнал запросов вместо стандартного логгера gin: с пользователем и порогом медленных запросов
+	router.Use(accesslog.Middleware(accesslog.Config{SlowThreshold: cfg.AccessLog.SlowThreshold}))
 
 	// Добавляем middleware для Prometheus
 	router.Use(PrometheusMiddleware())
 
 	// Добавляем Rate Limiting middleware
 	router.Use(ratelimit.Middleware(rateLimiter, rateLimitConfig))
+	router.Use(ratelimit.Middleware(searchLimiter, searchLimitConfig))
+
+	// Ошибки, переданные обработчиками через c.Error, отдаются в формате RFC 7807
+	router.Use(apperror.Middleware())
+
+	// Лимиты тела запроса; фото актёра загружается multipart-формой и получает свой лимит
+	router.Use(bodylimit.Middleware(bodylimit.Config{
+		MaxBytes:     cfg.RequestLimits.MaxBodyBytes,
+		MaxJSONDepth: cfg.RequestLimits.MaxJSONDepth,
+		RouteLimits:  map[string]int64{"/api/actors/:id/photo": storage.MaxImageSize + 64<<10},
+	}))
+
+	// Метрики Prometheus; в production закрываются токеном или basic auth
+	metricsAuth := handlers.MetricsAuth{
+		Username: cfg.Metrics.Username,
+		Password: cfg.Metrics.Password,
+		Token:    cfg.Metrics.Token,
+	}
+	if !metricsAuth.Enabled() {
+		log.Println("METRICS_TOKEN and METRICS_USERNAME are not set, /metrics is served without authentication")
+	}
+	handlers.RegisterMetricsRoutes(router, promhttp.Handler(), metricsAuth)
+
+	// Раздаём загруженные файлы, если они хранятся локально, а не за CDN
+	if strings.HasPrefix(cfg.Storage.BaseURL, "/") {
+		router.Static(cfg.Storage.BaseURL, cfg.Storage.Dir)
+	}
 
-	// Добавляем endpoint для метрик Prometheus
-	router.GET("/metrics", gin.WrapH(promhttp.Handler()))
+	// Проверки живости и готовности
+	handlers.RegisterHealthRoutes(router, healthHandler)
 
 	// Создаём основную группу API с префиксом /api
 	api := router.Group("/api")
 
 	// Регистрируем все маршруты (публичные и защищённые)
-	handlers.RegisterAllRoutes(api, actorHandler, movieHandler, authHandler, nil)
+	if cfg.Auth.PublicReads {
+		log.Println("AUTH_PUBLIC_READS is set, catalog GET routes are served without authentication")
+	}
+	handlers.RegisterAllRoutes(api, cfg.Auth.PublicReads, actorHandler, movieHandler, authHandler, nil, adminHandler, collectionHandler, eventsHandler, statsHandler, searchHandler)
 
 	// Создаём HTTP-сервер с настройками
 	srv := &http.Server{
 		Addr:    ":8080",
 		Handler: router,
 	}
+	// Открытые потоки событий иначе держали бы Shutdown до истечения таймаута
+	srv.RegisterOnShutdown(eventsHandler.Close)
 
 	// Канал для корректного завершения приложения
 	done := make(chan os.Signal, 1)
@@ -237,6 +585,16 @@ func Run() error {
 		log.Fatal("Server forced to shutdown: ", err)
 	}
 
+	// Останавливаем супервизор, чтобы он не перезапускал останавливаемые подсистемы
+	supervisorCancel()
+
+	// Дожидаемся отправки событий, поставленных в очередь последними запросами
+	flushCtx, flushCancel := context.WithTimeout(context.Background(), cfg.Kafka.FlushTimeout)
+	if err := eventProducerPool.Flush(flushCtx); err != nil {
+		log.Printf("Kafka producer pool: %v", err)
+	}
+	flushCancel()
+
 	// Останавливаем Kafka-консьюмеры
 	log.Println("Stopping Kafka consumers...")
 	consumerCancel()
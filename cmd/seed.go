@@ -0,0 +1,186 @@
+package cmd
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"math/rand"
+	"strings"
+	"time"
+
+	"cinematique/internal/config"
+	"cinematique/internal/domain"
+	"cinematique/internal/postgres"
+	"cinematique/internal/repository"
+	"cinematique/internal/service"
+)
+
+// seedOptions — параметры команды seed
+type seedOptions struct {
+	movies        int
+	actors        int
+	maxCast       int
+	adminUsername string
+	adminEmail    string
+	adminPassword string
+	randomSeed    int64
+}
+
+// Seed заполняет базу данных тестовыми фильмами, актёрами, их связями и администратором.
+// Запуск: cinematique seed [-movies N] [-actors M] [-cast K] [-admin-username ...] [-admin-password ...].
+// Подключение к БД берётся из тех же переменных окружения, что и у сервера
+func Seed(args []string) error {
+	opts := seedOptions{}
+	fs := flag.NewFlagSet("seed", flag.ContinueOnError)
+	fs.IntVar(&opts.movies, "movies", 50, "number of movies to create")
+	fs.IntVar(&opts.actors, "actors", 100, "number of actors to create")
+	fs.IntVar(&opts.maxCast, "cast", 6, "maximum number of actors per movie")
+	fs.StringVar(&opts.adminUsername, "admin-username", "admin", "admin username; empty skips the admin user")
+	fs.StringVar(&opts.adminEmail, "admin-email", "admin@cinematique.local", "admin email")
+	fs.StringVar(&opts.adminPassword, "admin-password", "admin12345", "admin password")
+	fs.Int64Var(&opts.randomSeed, "seed", time.Now().UnixNano(), "random seed; the same seed produces the same dataset")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if opts.movies < 0 || opts.actors < 0 || opts.maxCast < 0 {
+		return fmt.Errorf("movies, actors and cast must not be negative")
+	}
+
+	cfg := config.LoadConfig()
+	dbCfg, err := postgres.GetConfig()
+	if err != nil {
+		return fmt.Errorf("loading database configuration: %w", err)
+	}
+	db, err := postgres.Open(dbCfg)
+	if err != nil {
+		return fmt.Errorf("connecting to database: %w", err)
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+	movieRepo := repository.NewMovie(db)
+	actorRepo := repository.NewActor(db)
+	userRepo := repository.NewUserRepository(db)
+
+	actors, movies, casts := generateSeedData(rand.New(rand.NewSource(opts.randomSeed)), opts.actors, opts.movies, opts.maxCast)
+
+	actorIDs := make([]int, len(actors))
+	for i, actor := range actors {
+		id, err := actorRepo.Create(ctx, actor)
+		if err != nil {
+			return fmt.Errorf("creating actor %q: %w", actor.Name, err)
+		}
+		actorIDs[i] = id
+	}
+	log.Printf("Seed: created %d actors", len(actorIDs))
+
+	relations := 0
+	for i, movie := range movies {
+		cast := make([]int, len(casts[i]))
+		for j, actorIndex := range casts[i] {
+			cast[j] = actorIDs[actorIndex]
+		}
+		if _, err := movieRepo.CreateMovieWithActors(ctx, movie, cast); err != nil {
+			return fmt.Errorf("creating movie %q: %w", movie.Title, err)
+		}
+		relations += len(cast)
+	}
+	log.Printf("Seed: created %d movies with %d movie-actor relations", len(movies), relations)
+
+	if opts.adminUsername == "" {
+		return nil
+	}
+	if _, err := userRepo.GetByUsername(opts.adminUsername); err == nil {
+		log.Printf("Seed: user %q already exists, skipping admin", opts.adminUsername)
+		return nil
+	}
+	authService := service.NewAuthService(userRepo).WithPasswordHasher(passwordHasher(cfg.Auth))
+	if _, err := authService.Register(opts.adminUsername, opts.adminEmail, opts.adminPassword, domain.RoleAdmin); err != nil {
+		return fmt.Errorf("creating admin user: %w", err)
+	}
+	log.Printf("Seed: created admin user %q", opts.adminUsername)
+	return nil
+}
+
+var (
+	seedFirstNames = []string{
+		"Anna", "Boris", "Clara", "Daniel", "Elena", "Felix", "Greta", "Hugo", "Irina", "James",
+		"Katya", "Leon", "Maria", "Nikita", "Olga", "Pavel", "Quinn", "Rosa", "Sergei", "Tatiana",
+		"Ulrich", "Vera", "Walter", "Xenia", "Yuri", "Zoe",
+	}
+	seedLastNames = []string{
+		"Ivanova", "Petrov", "Moreau", "Fischer", "Rossi", "Novak", "Kowalski", "Lindqvist", "Hartmann",
+		"Sokolova", "Dubois", "Marino", "Volkov", "Berg", "Castillo", "Orlova", "Keller", "Laurent",
+	}
+	// Пол соответствует имени из seedFirstNames с тем же индексом
+	seedGenders = []string{
+		"female", "male", "female", "male", "female", "male", "female", "male", "female", "male",
+		"female", "male", "female", "male", "female", "male", "other", "female", "male", "female",
+		"male", "female", "male", "female", "male", "female",
+	}
+	seedTitleAdjectives = []string{
+		"Silent", "Crimson", "Last", "Hidden", "Frozen", "Endless", "Broken", "Golden", "Distant",
+		"Midnight", "Forgotten", "Electric", "Northern", "Paper", "Wild",
+	}
+	seedTitleNouns = []string{
+		"River", "Station", "Summer", "Garden", "Horizon", "Letters", "Harbor", "Orchestra", "Empire",
+		"Lighthouse", "Winter", "Passenger", "Mirror", "Frontier", "Dreams",
+	}
+	seedPlots = []string{
+		"A retired detective is pulled back for one final case that hits close to home.",
+		"Two estranged siblings cross the country to scatter their father's ashes.",
+		"A young composer discovers an unfinished symphony hidden in an old piano.",
+		"The crew of a research station loses contact with the mainland during a storm.",
+		"A small-town baker becomes the unlikely witness to an international heist.",
+		"An aging actress agrees to one last role that blurs the line between stage and life.",
+		"A cartographer sets out to map an island that does not appear on any chart.",
+		"Strangers stuck on a night train uncover what connects them.",
+	}
+)
+
+// generateSeedData строит набор актёров и фильмов; casts[i] содержит индексы актёров фильма i.
+// Результат зависит только от rng, так что одинаковый -seed воспроизводит тот же набор
+func generateSeedData(rng *rand.Rand, actorCount, movieCount, maxCast int) ([]domain.Actor, []domain.Movie, [][]int) {
+	actors := make([]domain.Actor, actorCount)
+	for i := range actors {
+		first := rng.Intn(len(seedFirstNames))
+		actors[i] = domain.Actor{
+			Name:      seedFirstNames[first] + " " + seedLastNames[rng.Intn(len(seedLastNames))],
+			Gender:    seedGenders[first],
+			BirthDate: time.Date(1940+rng.Intn(65), time.Month(1+rng.Intn(12)), 1+rng.Intn(28), 0, 0, 0, 0, time.UTC),
+		}
+	}
+
+	movies := make([]domain.Movie, movieCount)
+	casts := make([][]int, movieCount)
+	for i := range movies {
+		releaseDate := time.Date(1950+rng.Intn(76), time.Month(1+rng.Intn(12)), 1+rng.Intn(28), 0, 0, 0, 0, time.UTC)
+		movies[i] = domain.Movie{
+			Title:       seedTitle(rng),
+			Description: seedPlots[rng.Intn(len(seedPlots))],
+			ReleaseYear: releaseDate.Year(),
+			ReleaseDate: &releaseDate,
+			Rating:      float64(10+rng.Intn(91)) / 10, // 1.0–10.0 с шагом 0.1
+		}
+
+		castSize := 0
+		if maxCast > 0 && actorCount > 0 {
+			castSize = 1 + rng.Intn(min(maxCast, actorCount))
+		}
+		casts[i] = rng.Perm(actorCount)[:castSize]
+	}
+	return actors, movies, casts
+}
+
+// seedTitle составляет название вида «The Silent River» или «Crimson Station II»
+func seedTitle(rng *rand.Rand) string {
+	parts := []string{seedTitleAdjectives[rng.Intn(len(seedTitleAdjectives))], seedTitleNouns[rng.Intn(len(seedTitleNouns))]}
+	if rng.Intn(3) == 0 {
+		parts = append([]string{"The"}, parts...)
+	}
+	if rng.Intn(8) == 0 {
+		parts = append(parts, []string{"II", "III", "Returns"}[rng.Intn(3)])
+	}
+	return strings.Join(parts, " ")
+}
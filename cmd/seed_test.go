@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateSeedData(t *testing.T) {
+	actors, movies, casts := generateSeedData(rand.New(rand.NewSource(42)), 20, 30, 4)
+
+	require.Len(t, actors, 20)
+	require.Len(t, movies, 30)
+	require.Len(t, casts, 30)
+	assert.Len(t, seedGenders, len(seedFirstNames))
+
+	for _, actor := range actors {
+		assert.NotEmpty(t, actor.Name)
+		assert.Contains(t, []string{"male", "female", "other"}, actor.Gender)
+	}
+	for i, movie := range movies {
+		assert.NotEmpty(t, movie.Title)
+		assert.Equal(t, movie.ReleaseDate.Year(), movie.ReleaseYear)
+		assert.True(t, movie.Rating >= 1 && movie.Rating <= 10, "rating %v", movie.Rating)
+
+		require.NotEmpty(t, casts[i])
+		assert.LessOrEqual(t, len(casts[i]), 4)
+		seen := map[int]bool{}
+		for _, actorIndex := range casts[i] {
+			assert.True(t, actorIndex >= 0 && actorIndex < len(actors))
+			assert.False(t, seen[actorIndex], "actor %d repeated in movie %d", actorIndex, i)
+			seen[actorIndex] = true
+		}
+	}
+
+	// Одинаковый seed воспроизводит тот же набор
+	sameActors, sameMovies, sameCasts := generateSeedData(rand.New(rand.NewSource(42)), 20, 30, 4)
+	assert.Equal(t, actors, sameActors)
+	assert.Equal(t, movies, sameMovies)
+	assert.Equal(t, casts, sameCasts)
+}
+
+func TestGenerateSeedData_NoActors(t *testing.T) {
+	_, movies, casts := generateSeedData(rand.New(rand.NewSource(1)), 0, 3, 5)
+
+	assert.Len(t, movies, 3)
+	for _, cast := range casts {
+		assert.Empty(t, cast)
+	}
+}
@@ -0,0 +1,75 @@
+// Package accesslog пишет строку журнала на каждый HTTP-запрос: метод, шаблон маршрута,
+// статус, длительность, пользователя и размер ответа. Запросы дольше порога дополнительно
+// отмечаются предупреждением и счётчиком, чтобы медленные эндпоинты были видны без трассировки
+package accesslog
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// DefaultSlowThreshold — порог медленного запроса по умолчанию
+const DefaultSlowThreshold = 500 * time.Millisecond
+
+var slowRequestsTotal = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "http_slow_requests_total",
+		Help: "Requests that took longer than the slow request threshold, by method and route.",
+	},
+	[]string{"method", "route"},
+)
+
+func init() {
+	prometheus.MustRegister(slowRequestsTotal)
+}
+
+// Config задаёт порог медленного запроса и журнал. Нулевой SlowThreshold выключает
+// предупреждения; без Logger строки пишутся в стандартный журнал
+type Config struct {
+	SlowThreshold time.Duration
+	Logger        *log.Logger
+}
+
+// Middleware записывает запрос в журнал после его обработки. Пользователь берётся из
+// ключа user_id, который выставляет middleware аутентификации; для анонимных запросов — "-".
+// Путь пишется шаблоном маршрута, чтобы строки одного эндпоинта группировались вместе
+func Middleware(cfg Config) gin.HandlerFunc {
+	logger := cfg.Logger
+	if logger == nil {
+		logger = log.Default()
+	}
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+		latency := time.Since(start)
+
+		route := c.FullPath()
+		if route == "" {
+			route = c.Request.URL.Path
+		}
+		userID := "-"
+		if value, ok := c.Get("user_id"); ok {
+			userID = fmt.Sprint(value)
+		}
+		line := fmt.Sprintf("method=%s path=%s status=%d latency=%s user_id=%s bytes=%d",
+			c.Request.Method, route, c.Writer.Status(), latency, userID, bodySize(c))
+		logger.Printf("Access: %s", line)
+
+		if cfg.SlowThreshold > 0 && latency > cfg.SlowThreshold {
+			slowRequestsTotal.WithLabelValues(c.Request.Method, route).Inc()
+			logger.Printf("Warning: slow request (threshold %s): %s", cfg.SlowThreshold, line)
+		}
+	}
+}
+
+// bodySize возвращает размер тела ответа; gin отдаёт -1, если тело не записывалось
+func bodySize(c *gin.Context) int {
+	if size := c.Writer.Size(); size > 0 {
+		return size
+	}
+	return 0
+}
@@ -0,0 +1,60 @@
+package accesslog
+
+import (
+	"bytes"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func newRouter(cfg Config) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(Middleware(cfg))
+	r.GET("/movies/:id", func(c *gin.Context) {
+		c.Set("user_id", 42)
+		c.String(http.StatusOK, "hello")
+	})
+	r.GET("/slow", func(c *gin.Context) {
+		time.Sleep(20 * time.Millisecond)
+		c.Status(http.StatusNoContent)
+	})
+	return r
+}
+
+func TestMiddleware(t *testing.T) {
+	var out bytes.Buffer
+	r := newRouter(Config{SlowThreshold: 10 * time.Millisecond, Logger: log.New(&out, "", 0)})
+	slowBefore := testutil.ToFloat64(slowRequestsTotal.WithLabelValues(http.MethodGet, "/slow"))
+
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/movies/7", nil))
+	assert.Regexp(t, `^Access: method=GET path=/movies/:id status=200 latency=\S+ user_id=42 bytes=5\n$`, out.String())
+	assert.Equal(t, slowBefore, testutil.ToFloat64(slowRequestsTotal.WithLabelValues(http.MethodGet, "/slow")))
+
+	out.Reset()
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/slow", nil))
+	assert.Contains(t, out.String(), "Access: method=GET path=/slow status=204")
+	assert.Contains(t, out.String(), "user_id=- bytes=0")
+	assert.Contains(t, out.String(), "Warning: slow request (threshold 10ms): method=GET path=/slow")
+	assert.Equal(t, slowBefore+1, testutil.ToFloat64(slowRequestsTotal.WithLabelValues(http.MethodGet, "/slow")))
+
+	// Неизвестный маршрут пишется фактическим путём
+	out.Reset()
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/missing", nil))
+	assert.Contains(t, out.String(), "path=/missing status=404")
+}
+
+func TestMiddleware_ThresholdDisabled(t *testing.T) {
+	var out bytes.Buffer
+	r := newRouter(Config{Logger: log.New(&out, "", 0)})
+
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/slow", nil))
+	assert.Contains(t, out.String(), "Access: method=GET path=/slow")
+	assert.NotContains(t, out.String(), "Warning")
+}
@@ -0,0 +1,137 @@
+// Package apperror содержит типизированные ошибки приложения со стабильными кодами
+// и их представление в HTTP-ответах в формате RFC 7807 (application/problem+json)
+package apperror
+
+import (
+	"errors"
+	"net/http"
+)
+
+// Kind — вид ошибки; определяет HTTP-статус ответа
+type Kind int
+
+const (
+	KindInternal Kind = iota
+	KindValidation
+	KindNotFound
+	KindConflict
+	KindUnauthorized
+	KindForbidden
+	KindUnavailable
+	KindTooLarge
+	KindUnprocessable
+)
+
+// Status возвращает HTTP-статус для вида ошибки
+func (k Kind) Status() int {
+	switch k {
+	case KindValidation:
+		return http.StatusBadRequest
+	case KindNotFound:
+		return http.StatusNotFound
+	case KindConflict:
+		return http.StatusConflict
+	case KindUnauthorized:
+		return http.StatusUnauthorized
+	case KindForbidden:
+		return http.StatusForbidden
+	case KindUnavailable:
+		return http.StatusServiceUnavailable
+	case KindTooLarge:
+		return http.StatusRequestEntityTooLarge
+	case KindUnprocessable:
+		return http.StatusUnprocessableEntity
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// CodeInternal — код для ошибок, которые не удалось классифицировать
+const CodeInternal = "internal_error"
+
+// Error — ошибка приложения. Code — стабильный машиночитаемый код (movie_not_found),
+// на который могут опираться клиенты; Message может меняться.
+// Ошибки-образцы объявляются как переменные пакета и сравниваются через errors.Is
+type Error struct {
+	Kind    Kind
+	Code    string
+	Message string
+	// Extensions — дополнительные поля тела ответа (ошибки по полям, ID существующего ресурса)
+	Extensions map[string]interface{}
+}
+
+// Error возвращает сообщение ошибки
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// New создаёт ошибку приложения заданного вида
+func New(kind Kind, code, message string) *Error {
+	return &Error{Kind: kind, Code: code, Message: message}
+}
+
+// Validation создаёт ошибку некорректного запроса (400)
+func Validation(code, message string) *Error {
+	return New(KindValidation, code, message)
+}
+
+// NotFound создаёт ошибку отсутствующего ресурса (404)
+func NotFound(code, message string) *Error {
+	return New(KindNotFound, code, message)
+}
+
+// Conflict создаёт ошибку конфликта с текущим состоянием ресурса (409)
+func Conflict(code, message string) *Error {
+	return New(KindConflict, code, message)
+}
+
+// Unauthorized создаёт ошибку отсутствующей или неверной аутентификации (401)
+func Unauthorized(code, message string) *Error {
+	return New(KindUnauthorized, code, message)
+}
+
+// Forbidden создаёт ошибку недостаточных прав (403)
+func Forbidden(code, message string) *Error {
+	return New(KindForbidden, code, message)
+}
+
+// Unavailable создаёт ошибку недоступной зависимости: выключенной функции или внешнего сервиса (503)
+func Unavailable(code, message string) *Error {
+	return New(KindUnavailable, code, message)
+}
+
+// TooLarge создаёт ошибку слишком большого тела запроса (413)
+func TooLarge(code, message string) *Error {
+	return New(KindTooLarge, code, message)
+}
+
+// Unprocessable создаёт ошибку корректного по форме запроса, который нельзя выполнить:
+// например, он ссылается на несуществующую запись (422)
+func Unprocessable(code, message string) *Error {
+	return New(KindUnprocessable, code, message)
+}
+
+// Typed реализуют ошибки других пакетов, которые сами сообщают своё представление
+// (например, dto.ValidationErrors или domain.DuplicateMovieError)
+type Typed interface {
+	AppError() *Error
+}
+
+// From находит в цепочке err ошибку приложения. Ошибки, реализующие Typed, проверяются
+// первыми: они уточняют обёрнутые ими образцы. Неклассифицированные ошибки считаются внутренними
+func From(err error) *Error {
+	var typed Typed
+	if errors.As(err, &typed) {
+		return typed.AppError()
+	}
+	var appErr *Error
+	if errors.As(err, &appErr) {
+		return appErr
+	}
+	return New(KindInternal, CodeInternal, http.StatusText(http.StatusInternalServerError))
+}
+
+// KindOf возвращает вид ошибки err
+func KindOf(err error) Kind {
+	return From(err).Kind
+}
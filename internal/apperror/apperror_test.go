@@ -0,0 +1,174 @@
+package apperror
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errThingNotFound = NotFound("thing_not_found", "thing not found")
+
+// conflictWithID — ошибка другого пакета, уточняющая обёрнутый образец
+type conflictWithID struct{ id int }
+
+func (e *conflictWithID) Error() string { return fmt.Sprintf("conflicts with %d", e.id) }
+func (e *conflictWithID) Unwrap() error { return errThingNotFound }
+func (e *conflictWithID) AppError() *Error {
+	appErr := Conflict("thing_conflict", e.Error())
+	appErr.Extensions = map[string]interface{}{"existing_id": e.id}
+	return appErr
+}
+
+func TestFrom(t *testing.T) {
+	wrapped := fmt.Errorf("loading thing 5: %w", errThingNotFound)
+	assert.True(t, errors.Is(wrapped, errThingNotFound))
+	assert.Same(t, errThingNotFound, From(wrapped))
+	assert.Equal(t, KindNotFound, KindOf(wrapped))
+
+	// Typed проверяется раньше обёрнутого образца
+	typed := From(&conflictWithID{id: 7})
+	assert.Equal(t, KindConflict, typed.Kind)
+	assert.Equal(t, 7, typed.Extensions["existing_id"])
+
+	internal := From(errors.New("pq: connection refused"))
+	assert.Equal(t, KindInternal, internal.Kind)
+	assert.Equal(t, CodeInternal, internal.Code)
+}
+
+func TestKindStatus(t *testing.T) {
+	assert.Equal(t, http.StatusBadRequest, KindValidation.Status())
+	assert.Equal(t, http.StatusNotFound, KindNotFound.Status())
+	assert.Equal(t, http.StatusConflict, KindConflict.Status())
+	assert.Equal(t, http.StatusUnauthorized, KindUnauthorized.Status())
+	assert.Equal(t, http.StatusForbidden, KindForbidden.Status())
+	assert.Equal(t, http.StatusServiceUnavailable, KindUnavailable.Status())
+	assert.Equal(t, http.StatusRequestEntityTooLarge, KindTooLarge.Status())
+	assert.Equal(t, http.StatusUnprocessableEntity, KindUnprocessable.Status())
+	assert.Equal(t, http.StatusInternalServerError, KindInternal.Status())
+}
+
+func TestMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(Middleware())
+	r.GET("/not-found", func(c *gin.Context) {
+		_ = c.Error(fmt.Errorf("loading thing 5: %w", errThingNotFound))
+	})
+	r.GET("/internal", func(c *gin.Context) {
+		_ = c.Error(errors.New("pq: connection refused"))
+	})
+	r.GET("/conflict", func(c *gin.Context) {
+		_ = c.Error(&conflictWithID{id: 7})
+	})
+	r.GET("/written", func(c *gin.Context) {
+		_ = c.Error(errThingNotFound)
+		c.JSON(http.StatusOK, gin.H{"ok": true})
+	})
+
+	tests := []struct {
+		path         string
+		expectedCode int
+		expectedBody string
+	}{
+		{
+			path:         "/not-found",
+			expectedCode: http.StatusNotFound,
+			expectedBody: `{"type":"about:blank","title":"Not Found","status":404,"code":"thing_not_found","detail":"loading thing 5: thing not found"}`,
+		},
+		{
+			// Текст внутренней ошибки клиенту не отдаётся
+			path:         "/internal",
+			expectedCode: http.StatusInternalServerError,
+			expectedBody: `{"type":"about:blank","title":"Internal Server Error","status":500,"code":"internal_error","detail":"Internal Server Error"}`,
+		},
+		{
+			path:         "/conflict",
+			expectedCode: http.StatusConflict,
+			expectedBody: `{"type":"about:blank","title":"Conflict","status":409,"code":"thing_conflict","detail":"conflicts with 7","existing_id":7}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			assert.Equal(t, ContentType, w.Header().Get("Content-Type"))
+			assert.JSONEq(t, tt.expectedBody, w.Body.String())
+		})
+	}
+
+	// Ответ, уже записанный обработчиком, не перезаписывается
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/written", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	var body map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, true, body["ok"])
+}
+
+// greeting — расширение, которое переводит себя само
+type greeting string
+
+func (g greeting) Localize(lang string) interface{} { return string(g) + ":" + lang }
+
+func TestMiddleware_Localized(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(Middleware())
+	r.GET("/internal", func(c *gin.Context) {
+		_ = c.Error(errors.New("pq: connection refused"))
+	})
+	r.GET("/extension", func(c *gin.Context) {
+		appErr := Validation("thing_invalid", "thing is invalid")
+		appErr.Extensions = map[string]interface{}{"hint": greeting("hello")}
+		_ = c.Error(appErr)
+	})
+
+	tests := []struct {
+		path           string
+		acceptLanguage string
+		expectedLang   string
+		expectedBody   string
+	}{
+		{
+			path:           "/internal",
+			acceptLanguage: "ru-RU,ru;q=0.9,en;q=0.8",
+			expectedLang:   "ru",
+			expectedBody:   `{"type":"about:blank","title":"Internal Server Error","status":500,"code":"internal_error","detail":"внутренняя ошибка сервера"}`,
+		},
+		{
+			path:         "/internal",
+			expectedLang: "en",
+			expectedBody: `{"type":"about:blank","title":"Internal Server Error","status":500,"code":"internal_error","detail":"Internal Server Error"}`,
+		},
+		{
+			// Кода нет в каталоге: остаётся английское описание, расширение переводится само
+			path:           "/extension",
+			acceptLanguage: "ru",
+			expectedLang:   "ru",
+			expectedBody:   `{"type":"about:blank","title":"Bad Request","status":400,"code":"thing_invalid","detail":"thing is invalid","hint":"hello:ru"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path+" "+tt.acceptLanguage, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.acceptLanguage != "" {
+				req.Header.Set("Accept-Language", tt.acceptLanguage)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedLang, w.Header().Get("Content-Language"))
+			assert.JSONEq(t, tt.expectedBody, w.Body.String())
+		})
+	}
+}
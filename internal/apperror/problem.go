@@ -0,0 +1,106 @@
+package apperror
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"cinematique/internal/i18n"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ContentType — MIME-тип ответа с описанием ошибки по RFC 7807
+const ContentType = "application/problem+json"
+
+// Problem — тело ответа с ошибкой по RFC 7807. Code — стабильный код ошибки,
+// Extensions добавляются в тело как отдельные поля
+type Problem struct {
+	Type       string
+	Title      string
+	Status     int
+	Detail     string
+	Code       string
+	Extensions map[string]interface{}
+}
+
+// NewProblem описывает err для ответа клиенту. Текст внутренних ошибок клиенту
+// не отдаётся: он может содержать детали SQL-запросов и инфраструктуры
+func NewProblem(err error) Problem {
+	appErr := From(err)
+	status := appErr.Kind.Status()
+	detail := appErr.Message
+	if appErr.Kind != KindInternal {
+		detail = err.Error()
+	}
+	return Problem{
+		Type:       "about:blank",
+		Title:      http.StatusText(status),
+		Status:     status,
+		Detail:     detail,
+		Code:       appErr.Code,
+		Extensions: appErr.Extensions,
+	}
+}
+
+// MarshalJSON сериализует стандартные поля и расширения на одном уровне
+func (p Problem) MarshalJSON() ([]byte, error) {
+	body := make(map[string]interface{}, len(p.Extensions)+5)
+	for key, value := range p.Extensions {
+		body[key] = value
+	}
+	body["type"] = p.Type
+	body["title"] = p.Title
+	body["status"] = p.Status
+	body["code"] = p.Code
+	if p.Detail != "" {
+		body["detail"] = p.Detail
+	}
+	return json.Marshal(body)
+}
+
+// Localizer — значение расширения, которое умеет перевести свои сообщения на язык ответа
+type Localizer interface {
+	Localize(lang string) interface{}
+}
+
+// Localize переводит описание ошибки на язык lang по её коду. Если перевода нет,
+// остаётся исходное английское описание
+func (p Problem) Localize(lang string) Problem {
+	if p.Detail != "" {
+		p.Detail = i18n.Translate(lang, p.Code, p.Detail)
+	}
+	if len(p.Extensions) > 0 {
+		extensions := make(map[string]interface{}, len(p.Extensions))
+		for key, value := range p.Extensions {
+			if localizer, ok := value.(Localizer); ok {
+				value = localizer.Localize(lang)
+			}
+			extensions[key] = value
+		}
+		p.Extensions = extensions
+	}
+	return p
+}
+
+// Middleware отвечает application/problem+json на ошибку, добавленную обработчиком
+// через c.Error, если обработчик сам ничего не записал в ответ
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		if len(c.Errors) == 0 || c.Writer.Written() {
+			return
+		}
+		err := c.Errors.Last().Err
+		problem := NewProblem(err)
+		if problem.Status >= http.StatusInternalServerError {
+			log.Printf("%s %s: %v", c.Request.Method, c.Request.URL.Path, err)
+		}
+		lang := i18n.FromContext(c)
+		problem = problem.Localize(lang)
+		c.Header("Content-Type", ContentType)
+		c.Header("Content-Language", lang)
+		c.JSON(problem.Status, problem)
+	}
+}
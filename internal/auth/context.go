@@ -0,0 +1,75 @@
+package auth
+
+import (
+	"cinematique/internal/keycloak"
+	"errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Способы аутентификации
+const (
+	AuthTypeJWT      = "jwt"
+	AuthTypeKeycloak = "keycloak"
+)
+
+// userContextKey — ключ gin.Context, под которым хранится *UserContext
+const userContextKey = "auth_user"
+
+// ErrNoUser возвращается, если запрос не прошёл аутентификацию
+var ErrNoUser = errors.New("user not found in context")
+
+// UserContext содержит информацию об аутентифицированном пользователе запроса
+type UserContext struct {
+	AuthType         string             `json:"auth_type"`
+	UserID           string             `json:"user_id"`                 // ID пользователя: числовой для JWT, UUID для Keycloak
+	LocalUserID      int                `json:"local_user_id,omitempty"` // ID в таблице users; 0, если неизвестен
+	Username         string             `json:"username"`
+	Role             string             `json:"role"`
+	KeycloakUserInfo *keycloak.UserInfo `json:"keycloak_user_info,omitempty"`
+	JWTClaims        *Claims            `json:"jwt_claims,omitempty"`
+}
+
+// SetUser сохраняет пользователя в контексте запроса.
+// Отдельные ключи (user_id, username, role, ...) выставляются для обратной совместимости
+func SetUser(c *gin.Context, user *UserContext) {
+	c.Set(userContextKey, user)
+	c.Set("auth_type", user.AuthType)
+	c.Set("username", user.Username)
+	c.Set("role", user.Role)
+	switch user.AuthType {
+	case AuthTypeKeycloak:
+		c.Set("user_id", user.UserID)
+		c.Set("user_info", user.KeycloakUserInfo)
+	case AuthTypeJWT:
+		c.Set("user_id", user.LocalUserID)
+		c.Set("jwt_claims", user.JWTClaims)
+	}
+}
+
+// CurrentUser возвращает аутентифицированного пользователя запроса
+func CurrentUser(c *gin.Context) (*UserContext, bool) {
+	value, exists := c.Get(userContextKey)
+	if !exists {
+		return nil, false
+	}
+	user, ok := value.(*UserContext)
+	return user, ok && user != nil
+}
+
+// CurrentRole возвращает роль пользователя запроса или пустую строку для анонимного запроса
+func CurrentRole(c *gin.Context) string {
+	if user, ok := CurrentUser(c); ok {
+		return user.Role
+	}
+	return ""
+}
+
+// GetUserFromContext извлекает информацию о пользователе из контекста
+func GetUserFromContext(c *gin.Context) (*UserContext, error) {
+	user, ok := CurrentUser(c)
+	if !ok {
+		return nil, ErrNoUser
+	}
+	return user, nil
+}
@@ -55,6 +55,8 @@ type Claims struct {
 	Username   string `json:"username"`
 	Role       string `json:"role"`
 	IsRefresh  bool   `json:"is_refresh,omitempty"`
+	TokenVersion int  `json:"token_version,omitempty"` // версия токенов пользователя на момент выпуска
+	IssuedAtNano int64 `json:"iat_ns,omitempty"`       // момент выпуска в наносекундах: iat хранит только секунды
 	jwt.RegisteredClaims
 }
 
@@ -63,18 +65,25 @@ type TokenPair struct {
 	AccessToken  string `json:"access_token"`
 	RefreshToken string `json:"refresh_token,omitempty"`
 	ExpiresIn    int64  `json:"expires_in"` // в секундах
+	UserID       int    `json:"-"`          // владелец токенов; клиенту не отдаётся
 }
 
 // GenerateJWT создает новый JWT-токен с указанными данными пользователя
 func GenerateJWT(userID int, username, role string) (*TokenPair, error) {
+	return GenerateJWTWithVersion(userID, username, role, 0)
+}
+
+// GenerateJWTWithVersion создает пару токенов с версией токенов пользователя: после смены
+// роли версия растёт, и токены доступа со старой ролью перестают приниматься
+func GenerateJWTWithVersion(userID int, username, role string, tokenVersion int) (*TokenPair, error) {
 	// Генерация токена доступа
-	accessToken, _, err := generateToken(userID, username, role, AccessTokenExpiry, false)
+	accessToken, _, err := generateToken(userID, username, role, tokenVersion, AccessTokenExpiry, false)
 	if err != nil {
 		return nil, err
 	}
 
 	// Генерация токена обновления
-	refreshToken, _, err := generateToken(userID, username, role, RefreshTokenExpiry, true)
+	refreshToken, _, err := generateToken(userID, username, role, tokenVersion, RefreshTokenExpiry, true)
 	if err != nil {
 		return nil, err
 	}
@@ -83,13 +92,15 @@ func GenerateJWT(userID int, username, role string) (*TokenPair, error) {
 		AccessToken:  accessToken,
 		RefreshToken: refreshToken,
 		ExpiresIn:    int64(AccessTokenExpiry.Seconds()),
+		UserID:       userID,
 	}, nil
 }
 
 // generateToken генерирует JWT-токен с указанными параметрами
-func generateToken(userID int, username, role string, expiry time.Duration, isRefresh bool) (string, time.Time, error) {
+func generateToken(userID int, username, role string, tokenVersion int, expiry time.Duration, isRefresh bool) (string, time.Time, error) {
 	// Установка времени истечения токена
-	expirationTime := time.Now().Add(expiry)
+	issuedAt := time.Now()
+	expirationTime := issuedAt.Add(expiry)
 
 	// Создание претензий с данными пользователя
 	claims := &Claims{
@@ -97,9 +108,11 @@ func generateToken(userID int, username, role string, expiry time.Duration, isRe
 		Username:   username,
 		Role:       role,
 		IsRefresh:  isRefresh,
+		TokenVersion: tokenVersion,
+		IssuedAtNano: issuedAt.UnixNano(),
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(expirationTime),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			IssuedAt:  jwt.NewNumericDate(issuedAt),
 			Issuer:    "cinematique",
 			Subject:   "user_auth",
 		},
@@ -0,0 +1,33 @@
+package auth
+
+import "sync"
+
+// KeycloakUserSource сопоставляет пользователя Keycloak с его записью в таблице users
+type KeycloakUserSource interface {
+	LocalUserID(keycloakID string) (int, bool)
+}
+
+var (
+	keycloakUsersMu sync.RWMutex
+	keycloakUsers   KeycloakUserSource
+)
+
+// SetKeycloakUserSource задаёт источник локальных ID пользователей Keycloak. Без источника
+// у запросов с токеном Keycloak LocalUserID остаётся нулевым
+func SetKeycloakUserSource(source KeycloakUserSource) {
+	keycloakUsersMu.Lock()
+	defer keycloakUsersMu.Unlock()
+	keycloakUsers = source
+}
+
+// keycloakLocalUserID возвращает ID записи в users для пользователя Keycloak; 0, если записи нет
+func keycloakLocalUserID(keycloakID string) int {
+	keycloakUsersMu.RLock()
+	source := keycloakUsers
+	keycloakUsersMu.RUnlock()
+	if source == nil {
+		return 0
+	}
+	id, _ := source.LocalUserID(keycloakID)
+	return id
+}
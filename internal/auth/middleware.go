@@ -1,9 +1,12 @@
 package auth
 
 import (
+	"cinematique/internal/domain"
+	"cinematique/internal/i18n"
 	"cinematique/internal/keycloak"
-	"fmt"
+	"errors"
 	"net/http"
+	"strconv"
 	"strings"
 
 	"github.com/gin-gonic/gin"
@@ -14,7 +17,7 @@ func HybridAuthMiddleware(keycloakClient keycloak.KeycloakClient) gin.HandlerFun
 	return func(c *gin.Context) {
 		header := c.GetHeader("Authorization")
 		if header == "" || !strings.HasPrefix(header, "Bearer ") {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "отсутствует или неверный токен"})
+			c.AbortWithStatusJSON(http.StatusUnauthorized, i18n.ErrorJSON(c, "missing_token", "missing or invalid token"))
 			return
 		}
 
@@ -24,16 +27,19 @@ func HybridAuthMiddleware(keycloakClient keycloak.KeycloakClient) gin.HandlerFun
 		if keycloakClient != nil && keycloakClient.IsKeycloakToken(tokenStr) {
 			userInfo, err := keycloakClient.ValidateTokenWithOptions(tokenStr, keycloak.DefaultValidationOptions())
 			if err != nil {
-				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "неверный Keycloak токен"})
+				c.AbortWithStatusJSON(http.StatusUnauthorized, i18n.ErrorJSON(c, "invalid_keycloak_token", "invalid Keycloak token"))
 				return
 			}
 
 			// Устанавливаем контекст для Keycloak токена
-			c.Set("auth_type", "keycloak")
-			c.Set("user_id", userInfo.ID)
-			c.Set("username", userInfo.Username)
-			c.Set("role", userInfo.LocalRole)
-			c.Set("user_info", userInfo)
+			SetUser(c, &UserContext{
+				AuthType:         AuthTypeKeycloak,
+				UserID:           userInfo.ID,
+				LocalUserID:      keycloakLocalUserID(userInfo.ID),
+				Username:         userInfo.Username,
+				Role:             userInfo.LocalRole,
+				KeycloakUserInfo: userInfo,
+			})
 			c.Next()
 			return
 		}
@@ -41,40 +47,92 @@ func HybridAuthMiddleware(keycloakClient keycloak.KeycloakClient) gin.HandlerFun
 		// Если не Keycloak токен, пробуем как обычный JWT
 		claims, err := ParseJWT(tokenStr)
 		if err != nil {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "неверный токен"})
+			c.AbortWithStatusJSON(http.StatusUnauthorized, i18n.ErrorJSON(c, "invalid_token", "invalid token"))
 			return
 		}
 
 		// Проверяем, что токен не является refresh-токеном
 		if claims.IsRefresh {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "refresh-токен не может быть использован для аутентификации"})
+			c.AbortWithStatusJSON(http.StatusUnauthorized, i18n.ErrorJSON(c, "refresh_token_not_allowed", "refresh token cannot be used for authentication"))
+			return
+		}
+
+		// Токены, выпущенные до смены пароля или удаления учётной записи, отозваны
+		if err := CheckRevoked(c.Request.Context(), claims); err != nil {
+			if errors.Is(err, ErrRevocationUnavailable) {
+				c.AbortWithStatusJSON(http.StatusServiceUnavailable, i18n.ErrorJSON(c, "revocation_unavailable", "token revocation check is unavailable"))
+				return
+			}
+			c.AbortWithStatusJSON(http.StatusUnauthorized, i18n.ErrorJSON(c, "token_revoked", "token has been revoked"))
+			return
+		}
+		if err := CheckTokenVersion(c.Request.Context(), claims); err != nil {
+			if errors.Is(err, ErrTokenVersionUnavailable) {
+				c.AbortWithStatusJSON(http.StatusServiceUnavailable, i18n.ErrorJSON(c, "token_version_unavailable", "token version check is unavailable"))
+				return
+			}
+			c.AbortWithStatusJSON(http.StatusUnauthorized, i18n.ErrorJSON(c, "token_outdated", "user role has changed, refresh the token"))
 			return
 		}
 
 		// Устанавливаем контекст для обычного JWT токена
-		c.Set("auth_type", "jwt")
-		c.Set("user_id", claims.UserID)
-		c.Set("username", claims.Username)
-		c.Set("role", claims.Role)
-		c.Set("jwt_claims", claims)
+		SetUser(c, &UserContext{
+			AuthType:    AuthTypeJWT,
+			UserID:      strconv.Itoa(claims.UserID),
+			LocalUserID: claims.UserID,
+			Username:    claims.Username,
+			Role:        claims.Role,
+			JWTClaims:   claims,
+		})
 		c.Next()
 	}
 }
 
+// OptionalAuthMiddleware проверяет токен только если он передан.
+// Запросы без заголовка Authorization пропускаются как анонимные (без роли в контексте)
+func OptionalAuthMiddleware(keycloakClient keycloak.KeycloakClient) gin.HandlerFunc {
+	hybrid := HybridAuthMiddleware(keycloakClient)
+	return func(c *gin.Context) {
+		if c.GetHeader("Authorization") == "" {
+			c.Next()
+			return
+		}
+		hybrid(c)
+	}
+}
+
+// PublicReadsMiddleware пропускает чтение (GET, HEAD, OPTIONS) без токена, как OptionalAuthMiddleware,
+// а для остальных методов требует токен, как HybridAuthMiddleware. Права на запись по-прежнему
+// проверяют RequirePermission и RequireRole на самих маршрутах
+func PublicReadsMiddleware(keycloakClient keycloak.KeycloakClient) gin.HandlerFunc {
+	optional := OptionalAuthMiddleware(keycloakClient)
+	required := HybridAuthMiddleware(keycloakClient)
+	return func(c *gin.Context) {
+		switch c.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			optional(c)
+		default:
+			required(c)
+		}
+	}
+}
+
 // JWTAuthMiddleware оставляем для обратной совместимости
 func JWTAuthMiddleware() gin.HandlerFunc {
 	return HybridAuthMiddleware(nil)
 }
 
-// OnlyAdminOrReadOnly разрешает только GET/OPTIONS/HEAD для обычных пользователей, а все методы — для админов
+// OnlyAdminOrReadOnly разрешает только GET/OPTIONS/HEAD для обычных пользователей, а все методы — для админов.
+//
+// Deprecated: маршруты каталога проверяют права через RequirePermission, чтобы модераторы тоже могли вносить правки
 func OnlyAdminOrReadOnly() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		role, ok := c.Get("role")
-		if !ok {
-			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "нет роли в токене"})
+		user, ok := CurrentUser(c)
+		if !ok || user.Role == "" {
+			c.AbortWithStatusJSON(http.StatusForbidden, i18n.ErrorJSON(c, "missing_role", "token has no role"))
 			return
 		}
-		if role == "admin" {
+		if user.Role == "admin" {
 			c.Next()
 			return
 		}
@@ -83,21 +141,39 @@ func OnlyAdminOrReadOnly() gin.HandlerFunc {
 			c.Next()
 			return
 		}
-		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "только администратор может изменять данные"})
+		c.AbortWithStatusJSON(http.StatusForbidden, i18n.ErrorJSON(c, "admin_only", "only an administrator can modify data"))
+	}
+}
+
+// RequirePermission пропускает запрос, только если роли пользователя выдано указанное право
+func RequirePermission(permission domain.Permission) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		user, ok := CurrentUser(c)
+		if !ok || user.Role == "" {
+			c.AbortWithStatusJSON(http.StatusForbidden, i18n.ErrorJSON(c, "missing_role", "token has no role"))
+			return
+		}
+
+		if !domain.RoleHasPermission(user.Role, permission) {
+			c.AbortWithStatusJSON(http.StatusForbidden, i18n.ErrorJSON(c, "insufficient_permission", "insufficient permissions: %s required", permission))
+			return
+		}
+
+		c.Next()
 	}
 }
 
 // RequireRole требует определенную роль для доступа
 func RequireRole(requiredRole string) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		role, ok := c.Get("role")
-		if !ok {
-			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "нет роли в токене"})
+		user, ok := CurrentUser(c)
+		if !ok || user.Role == "" {
+			c.AbortWithStatusJSON(http.StatusForbidden, i18n.ErrorJSON(c, "missing_role", "token has no role"))
 			return
 		}
 
-		if role != requiredRole {
-			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": fmt.Sprintf("требуется роль %s", requiredRole)})
+		if user.Role != requiredRole {
+			c.AbortWithStatusJSON(http.StatusForbidden, i18n.ErrorJSON(c, "role_required", "role %s required", requiredRole))
 			return
 		}
 
@@ -108,68 +184,19 @@ func RequireRole(requiredRole string) gin.HandlerFunc {
 // RequireAnyRole требует любую из указанных ролей
 func RequireAnyRole(roles ...string) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		userRole, ok := c.Get("role")
-		if !ok {
-			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "нет роли в токене"})
-			return
-		}
-
-		userRoleStr, ok := userRole.(string)
-		if !ok {
-			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "неверный формат роли"})
+		user, ok := CurrentUser(c)
+		if !ok || user.Role == "" {
+			c.AbortWithStatusJSON(http.StatusForbidden, i18n.ErrorJSON(c, "missing_role", "token has no role"))
 			return
 		}
 
 		for _, role := range roles {
-			if userRoleStr == role {
+			if user.Role == role {
 				c.Next()
 				return
 			}
 		}
 
-		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": fmt.Sprintf("требуется одна из ролей: %v", roles)})
-	}
-}
-
-// GetUserFromContext извлекает информацию о пользователе из контекста
-func GetUserFromContext(c *gin.Context) (*UserContext, error) {
-	authType, exists := c.Get("auth_type")
-	if !exists {
-		return nil, fmt.Errorf("auth_type not found in context")
-	}
-
-	userID, _ := c.Get("user_id")
-	username, _ := c.Get("username")
-	role, _ := c.Get("role")
-
-	userContext := &UserContext{
-		AuthType: authType.(string),
-		UserID:   userID.(string),
-		Username: username.(string),
-		Role:     role.(string),
-	}
-
-	// Добавляем дополнительную информацию в зависимости от типа аутентификации
-	switch authType {
-	case "keycloak":
-		if userInfo, exists := c.Get("user_info"); exists {
-			userContext.KeycloakUserInfo = userInfo.(*keycloak.UserInfo)
-		}
-	case "jwt":
-		if claims, exists := c.Get("jwt_claims"); exists {
-			userContext.JWTClaims = claims.(*Claims)
-		}
+		c.AbortWithStatusJSON(http.StatusForbidden, i18n.ErrorJSON(c, "any_role_required", "one of the roles is required: %v", roles))
 	}
-
-	return userContext, nil
-}
-
-// UserContext содержит информацию о пользователе из контекста
-type UserContext struct {
-	AuthType         string             `json:"auth_type"`
-	UserID           string             `json:"user_id"`
-	Username         string             `json:"username"`
-	Role             string             `json:"role"`
-	KeycloakUserInfo *keycloak.UserInfo `json:"keycloak_user_info,omitempty"`
-	JWTClaims        *Claims            `json:"jwt_claims,omitempty"`
 }
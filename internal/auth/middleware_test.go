@@ -5,6 +5,8 @@ import (
 	"net/http/httptest"
 	"testing"
 
+	"cinematique/internal/domain"
+
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 )
@@ -49,7 +51,7 @@ func TestJWTAuthMiddleware(t *testing.T) {
 				return req
 			},
 			expectedStatus: http.StatusUnauthorized,
-			expectedBody:   `{"error":"отсутствует или неверный токен"}`,
+			expectedBody:   `{"code":"missing_token","error":"missing or invalid token"}`,
 			shouldSetUser: false,
 		},
 		{
@@ -60,7 +62,7 @@ func TestJWTAuthMiddleware(t *testing.T) {
 				return req
 			},
 			expectedStatus: http.StatusUnauthorized,
-			expectedBody:   `{"error":"отсутствует или неверный токен"}`,
+			expectedBody:   `{"code":"missing_token","error":"missing or invalid token"}`,
 			shouldSetUser: false,
 		},
 		{
@@ -71,7 +73,7 @@ func TestJWTAuthMiddleware(t *testing.T) {
 				return req
 			},
 			expectedStatus: http.StatusUnauthorized,
-			expectedBody:   `{"error":"неверный токен"}`,
+			expectedBody:   `{"code":"invalid_token","error":"invalid token"}`,
 			shouldSetUser: false,
 		},
 	}
@@ -125,7 +127,7 @@ func TestOnlyAdminOrReadOnly(t *testing.T) {
 			name:    "admin can POST",
 			method:  "POST",
 			setupContext: func(c *gin.Context) {
-				c.Set("role", "admin")
+				SetUser(c, &UserContext{Role: "admin"})
 			},
 			expectedStatus: http.StatusOK,
 			shouldAllow:   true,
@@ -134,7 +136,7 @@ func TestOnlyAdminOrReadOnly(t *testing.T) {
 			name:    "user can GET",
 			method:  "GET",
 			setupContext: func(c *gin.Context) {
-				c.Set("role", "user")
+				SetUser(c, &UserContext{Role: "user"})
 			},
 			expectedStatus: http.StatusOK,
 			shouldAllow:   true,
@@ -143,7 +145,7 @@ func TestOnlyAdminOrReadOnly(t *testing.T) {
 			name:    "user cannot POST",
 			method:  "POST",
 			setupContext: func(c *gin.Context) {
-				c.Set("role", "user")
+				SetUser(c, &UserContext{Role: "user"})
 			},
 			expectedStatus: http.StatusForbidden,
 			shouldAllow:   false,
@@ -183,3 +185,159 @@ func TestOnlyAdminOrReadOnly(t *testing.T) {
 		})
 	}
 }
+
+func TestRequirePermission(t *testing.T) {
+	tests := []struct {
+		name           string
+		role           string
+		permission     domain.Permission
+		expectedStatus int
+	}{
+		{"user can read", domain.RoleUser, domain.PermissionCatalogRead, http.StatusOK},
+		{"user cannot write", domain.RoleUser, domain.PermissionCatalogWrite, http.StatusForbidden},
+		{"moderator can write", domain.RoleModerator, domain.PermissionCatalogWrite, http.StatusOK},
+		{"moderator cannot delete", domain.RoleModerator, domain.PermissionCatalogDelete, http.StatusForbidden},
+		{"moderator cannot manage users", domain.RoleModerator, domain.PermissionManageUsers, http.StatusForbidden},
+		{"admin can delete", domain.RoleAdmin, domain.PermissionCatalogDelete, http.StatusOK},
+		{"unknown role has no permissions", "guest", domain.PermissionCatalogRead, http.StatusForbidden},
+		{"missing role", "", domain.PermissionCatalogRead, http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := setupRouter()
+			r.POST("/test", func(c *gin.Context) {
+				if tt.role != "" {
+					SetUser(c, &UserContext{Role: tt.role})
+				}
+			}, RequirePermission(tt.permission), func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			req, _ := http.NewRequest(http.MethodPost, "/test", nil)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
+
+func TestOptionalAuthMiddleware(t *testing.T) {
+	originalKey := make([]byte, len(JWTKey))
+	copy(originalKey, JWTKey)
+	defer func() { JWTKey = originalKey }()
+	JWTKey = []byte("test_secret_key")
+
+	r := setupRouter()
+	r.Use(OptionalAuthMiddleware(nil))
+	r.GET("/test", func(c *gin.Context) {
+		role, _ := c.Get("role")
+		c.JSON(http.StatusOK, gin.H{"role": role})
+	})
+
+	// Без токена запрос проходит анонимно
+	req, _ := http.NewRequest("GET", "/test", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"role":null}`, w.Body.String())
+
+	// С валидным токеном роль попадает в контекст
+	tokenPair, _ := GenerateJWT(1, "admin", "admin")
+	req, _ = http.NewRequest("GET", "/test", nil)
+	req.Header.Set("Authorization", "Bearer "+tokenPair.AccessToken)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"role":"admin"}`, w.Body.String())
+
+	// Невалидный токен по-прежнему отклоняется
+	req, _ = http.NewRequest("GET", "/test", nil)
+	req.Header.Set("Authorization", "Bearer invalid.token.here")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestCurrentUser_FromJWT(t *testing.T) {
+	originalKey := make([]byte, len(JWTKey))
+	copy(originalKey, JWTKey)
+	defer func() { JWTKey = originalKey }()
+	JWTKey = []byte("test_secret_key")
+
+	r := setupRouter()
+	var user *UserContext
+	var found bool
+	r.GET("/test", JWTAuthMiddleware(), func(c *gin.Context) {
+		user, found = CurrentUser(c)
+		c.Status(http.StatusOK)
+	})
+
+	tokenPair, err := GenerateJWT(123, "testuser", "user")
+	assert.NoError(t, err)
+	req, _ := http.NewRequest("GET", "/test", nil)
+	req.Header.Set("Authorization", "Bearer "+tokenPair.AccessToken)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	if assert.True(t, found) {
+		assert.Equal(t, AuthTypeJWT, user.AuthType)
+		assert.Equal(t, "123", user.UserID)
+		assert.Equal(t, 123, user.LocalUserID)
+		assert.Equal(t, "testuser", user.Username)
+		assert.Equal(t, "user", user.Role)
+		assert.NotNil(t, user.JWTClaims)
+	}
+}
+
+func TestGetUserFromContext(t *testing.T) {
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+
+	_, err := GetUserFromContext(c)
+	assert.ErrorIs(t, err, ErrNoUser)
+	assert.Equal(t, "", CurrentRole(c))
+
+	SetUser(c, &UserContext{AuthType: AuthTypeKeycloak, UserID: "kc-uuid", Username: "kc", Role: "admin"})
+	user, err := GetUserFromContext(c)
+	assert.NoError(t, err)
+	assert.Equal(t, "kc-uuid", user.UserID)
+	assert.Equal(t, "admin", CurrentRole(c))
+
+	// Отдельные ключи сохраняются для обратной совместимости
+	assert.Equal(t, "kc-uuid", c.GetString("user_id"))
+	assert.Equal(t, "admin", c.GetString("role"))
+}
+
+func TestPublicReadsMiddleware(t *testing.T) {
+	originalKey := make([]byte, len(JWTKey))
+	copy(originalKey, JWTKey)
+	defer func() { JWTKey = originalKey }()
+	JWTKey = []byte("test_secret_key")
+
+	r := setupRouter()
+	r.Use(PublicReadsMiddleware(nil))
+	r.GET("/test", func(c *gin.Context) { c.Status(http.StatusOK) })
+	r.POST("/test", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	// Чтение без токена разрешено
+	req, _ := http.NewRequest(http.MethodGet, "/test", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// Запись без токена отклоняется
+	req, _ = http.NewRequest(http.MethodPost, "/test", nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	// С токеном запись проходит
+	tokenPair, _ := GenerateJWT(1, "moderator", "moderator")
+	req, _ = http.NewRequest(http.MethodPost, "/test", nil)
+	req.Header.Set("Authorization", "Bearer "+tokenPair.AccessToken)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+}
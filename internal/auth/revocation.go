@@ -0,0 +1,154 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+var (
+	// ErrTokenRevoked возвращается для токена, выпущенного до отзыва токенов пользователя
+	ErrTokenRevoked = errors.New("token revoked")
+	// ErrRevocationUnavailable возвращается, если хранилище отзывов не ответило: без проверки
+	// токен мог быть отозван, поэтому он не принимается
+	ErrRevocationUnavailable = errors.New("token revocation store unavailable")
+)
+
+// RevocationStore хранит момент отзыва токенов пользователя: токены, выпущенные
+// не позже этого момента, недействительны. Отзыв нужен при смене пароля и удалении
+// учётной записи, иначе украденный токен оставался бы рабочим до истечения срока
+type RevocationStore interface {
+	RevokeUserTokens(ctx context.Context, userID int, at time.Time) error
+	RevokedAt(ctx context.Context, userID int) (time.Time, bool, error)
+}
+
+var (
+	revocationsMu sync.RWMutex
+	revocations   RevocationStore = NewMemoryRevocationStore()
+)
+
+// SetRevocationStore задаёт хранилище отзывов. По умолчанию отзывы хранятся в памяти процесса,
+// что подходит только для единственного экземпляра сервиса
+func SetRevocationStore(store RevocationStore) {
+	revocationsMu.Lock()
+	defer revocationsMu.Unlock()
+	revocations = store
+}
+
+func revocationStore() RevocationStore {
+	revocationsMu.RLock()
+	defer revocationsMu.RUnlock()
+	return revocations
+}
+
+// RevokeUserTokens отзывает все токены пользователя, выпущенные до текущего момента.
+// Момент хранится с точностью до наносекунды, поэтому токены, выпущенные сразу после
+// отзыва (например, при повторном входе в ту же секунду), остаются действительными
+func RevokeUserTokens(ctx context.Context, userID int) error {
+	return revocationStore().RevokeUserTokens(ctx, userID, time.Now())
+}
+
+// CheckRevoked возвращает ErrTokenRevoked, если токен выпущен не позже отзыва токенов его
+// пользователя, и ErrRevocationUnavailable, если хранилище отзывов недоступно
+func CheckRevoked(ctx context.Context, claims *Claims) error {
+	revokedAt, ok, err := revocationStore().RevokedAt(ctx, claims.UserID)
+	if err != nil {
+		log.Printf("Token revocation store unavailable, rejecting token: %v", err)
+		return fmt.Errorf("%w: %v", ErrRevocationUnavailable, err)
+	}
+	if ok && !tokenIssuedAt(claims).After(revokedAt) {
+		return ErrTokenRevoked
+	}
+	return nil
+}
+
+// tokenIssuedAt возвращает момент выпуска токена. У токенов без iat_ns, выпущенных до его
+// появления, берётся iat с точностью до секунды: он не позже настоящего момента выпуска,
+// поэтому такой токен из секунды отзыва отклоняется
+func tokenIssuedAt(claims *Claims) time.Time {
+	if claims.IssuedAtNano != 0 {
+		return time.Unix(0, claims.IssuedAtNano)
+	}
+	if claims.IssuedAt == nil {
+		return time.Time{}
+	}
+	return claims.IssuedAt.Time
+}
+
+// RedisRevocationClient — команды Redis, нужные хранилищу отзывов
+type RedisRevocationClient interface {
+	Set(ctx context.Context, key string, value interface{}, expiration time.Duration) *redis.StatusCmd
+	Get(ctx context.Context, key string) *redis.StringCmd
+}
+
+// RedisRevocationStore хранит отзывы в Redis, общем для всех экземпляров сервиса.
+// Запись живёт столько же, сколько refresh-токен: после этого отозванные токены истекают сами
+type RedisRevocationStore struct {
+	client RedisRevocationClient
+}
+
+// NewRedisRevocationStore создаёт хранилище отзывов в Redis
+func NewRedisRevocationStore(client RedisRevocationClient) *RedisRevocationStore {
+	return &RedisRevocationStore{client: client}
+}
+
+func revocationKey(userID int) string {
+	return "auth:tokens_revoked:" + strconv.Itoa(userID)
+}
+
+// legacyRevocationBound — значения меньше этого хранят момент отзыва в секундах,
+// как до перехода на наносекунды; в наносекундах так записывается только 1970 год
+const legacyRevocationBound = 1 << 40
+
+// RevokeUserTokens сохраняет момент отзыва в наносекундах
+func (s *RedisRevocationStore) RevokeUserTokens(ctx context.Context, userID int, at time.Time) error {
+	return s.client.Set(ctx, revocationKey(userID), at.UnixNano(), RefreshTokenExpiry).Err()
+}
+
+// RevokedAt возвращает момент отзыва токенов пользователя, если он был
+func (s *RedisRevocationStore) RevokedAt(ctx context.Context, userID int) (time.Time, bool, error) {
+	value, err := s.client.Get(ctx, revocationKey(userID)).Int64()
+	if errors.Is(err, redis.Nil) {
+		return time.Time{}, false, nil
+	}
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("reading token revocation: %w", err)
+	}
+	if value < legacyRevocationBound {
+		return time.Unix(value, 0), true, nil
+	}
+	return time.Unix(0, value), true, nil
+}
+
+// MemoryRevocationStore хранит отзывы в памяти процесса
+type MemoryRevocationStore struct {
+	mu      sync.RWMutex
+	revoked map[int]time.Time
+}
+
+// NewMemoryRevocationStore создаёт хранилище отзывов в памяти
+func NewMemoryRevocationStore() *MemoryRevocationStore {
+	return &MemoryRevocationStore{revoked: make(map[int]time.Time)}
+}
+
+// RevokeUserTokens сохраняет момент отзыва
+func (s *MemoryRevocationStore) RevokeUserTokens(_ context.Context, userID int, at time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.revoked[userID] = at
+	return nil
+}
+
+// RevokedAt возвращает момент отзыва токенов пользователя, если он был
+func (s *MemoryRevocationStore) RevokedAt(_ context.Context, userID int) (time.Time, bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	at, ok := s.revoked[userID]
+	return at, ok, nil
+}
@@ -0,0 +1,120 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckRevoked(t *testing.T) {
+	store := NewMemoryRevocationStore()
+	SetRevocationStore(store)
+	defer SetRevocationStore(NewMemoryRevocationStore())
+
+	revokedAt := time.Date(2024, 5, 1, 12, 0, 0, 500, time.UTC)
+	require.NoError(t, store.RevokeUserTokens(context.Background(), 1, revokedAt))
+
+	claimsAt := func(userID int, issuedAt time.Time) *Claims {
+		return &Claims{
+			UserID:           userID,
+			IssuedAtNano:     issuedAt.UnixNano(),
+			RegisteredClaims: jwt.RegisteredClaims{IssuedAt: jwt.NewNumericDate(issuedAt)},
+		}
+	}
+	legacyClaimsAt := func(userID int, issuedAt time.Time) *Claims {
+		return &Claims{UserID: userID, RegisteredClaims: jwt.RegisteredClaims{IssuedAt: jwt.NewNumericDate(issuedAt)}}
+	}
+
+	assert.ErrorIs(t, CheckRevoked(context.Background(), claimsAt(1, revokedAt.Add(-time.Hour))), ErrTokenRevoked)
+	assert.ErrorIs(t, CheckRevoked(context.Background(), claimsAt(1, revokedAt)), ErrTokenRevoked)
+	// Токен, выпущенный в ту же секунду сразу после отзыва (повторный вход), действителен
+	assert.NoError(t, CheckRevoked(context.Background(), claimsAt(1, revokedAt.Add(time.Millisecond))))
+	assert.NoError(t, CheckRevoked(context.Background(), claimsAt(2, revokedAt.Add(-time.Hour))))
+	// Без iat_ns точность — секунда: токен из секунды отзыва отклоняется
+	assert.ErrorIs(t, CheckRevoked(context.Background(), legacyClaimsAt(1, revokedAt.Add(time.Millisecond))), ErrTokenRevoked)
+	assert.NoError(t, CheckRevoked(context.Background(), legacyClaimsAt(1, revokedAt.Add(time.Second))))
+}
+
+type failingRevocationStore struct{}
+
+func (failingRevocationStore) RevokeUserTokens(context.Context, int, time.Time) error {
+	return errors.New("connection refused")
+}
+
+func (failingRevocationStore) RevokedAt(context.Context, int) (time.Time, bool, error) {
+	return time.Time{}, false, errors.New("connection refused")
+}
+
+func TestCheckRevoked_StoreUnavailable(t *testing.T) {
+	SetRevocationStore(failingRevocationStore{})
+	defer SetRevocationStore(NewMemoryRevocationStore())
+
+	claims := &Claims{UserID: 1, IssuedAtNano: time.Now().UnixNano()}
+	assert.ErrorIs(t, CheckRevoked(context.Background(), claims), ErrRevocationUnavailable)
+}
+
+func TestJWTAuthMiddleware_LoginRightAfterRevocation(t *testing.T) {
+	SetRevocationStore(NewMemoryRevocationStore())
+	defer SetRevocationStore(NewMemoryRevocationStore())
+
+	require.NoError(t, RevokeUserTokens(context.Background(), 42))
+	tokens, err := GenerateJWT(42, "neo", "user")
+	require.NoError(t, err)
+
+	r := setupRouter()
+	r.GET("/test", JWTAuthMiddleware(), func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	req := httptest.NewRequest(http.MethodGet, "/test", nil)
+	req.Header.Set("Authorization", "Bearer "+tokens.AccessToken)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestJWTAuthMiddleware_RevocationStoreUnavailable(t *testing.T) {
+	SetRevocationStore(failingRevocationStore{})
+	defer SetRevocationStore(NewMemoryRevocationStore())
+
+	tokens, err := GenerateJWT(42, "neo", "user")
+	require.NoError(t, err)
+
+	r := setupRouter()
+	r.GET("/test", JWTAuthMiddleware(), func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	req := httptest.NewRequest(http.MethodGet, "/test", nil)
+	req.Header.Set("Authorization", "Bearer "+tokens.AccessToken)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.JSONEq(t, `{"code":"revocation_unavailable","error":"token revocation check is unavailable"}`, w.Body.String())
+}
+
+func TestJWTAuthMiddleware_RevokedToken(t *testing.T) {
+	SetRevocationStore(NewMemoryRevocationStore())
+	defer SetRevocationStore(NewMemoryRevocationStore())
+
+	tokens, err := GenerateJWT(42, "neo", "user")
+	require.NoError(t, err)
+	require.NoError(t, RevokeUserTokens(context.Background(), 42))
+
+	r := setupRouter()
+	r.GET("/test", JWTAuthMiddleware(), func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	req := httptest.NewRequest(http.MethodGet, "/test", nil)
+	req.Header.Set("Authorization", "Bearer "+tokens.AccessToken)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.JSONEq(t, `{"code":"token_revoked","error":"token has been revoked"}`, w.Body.String())
+}
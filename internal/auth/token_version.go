@@ -0,0 +1,135 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrTokenOutdated возвращается для access-токена, выпущенного до смены роли пользователя
+	ErrTokenOutdated = errors.New("token outdated")
+	// ErrTokenVersionUnavailable возвращается, если источник версий не ответил: без проверки
+	// токен мог нести отобранную роль, поэтому он не принимается
+	ErrTokenVersionUnavailable = errors.New("token version source unavailable")
+)
+
+// DefaultTokenVersionTTL — сколько экземпляр помнит версию токенов пользователя. Столько же
+// в худшем случае действует старая роль на других экземплярах после её смены
+const DefaultTokenVersionTTL = 5 * time.Second
+
+// TokenVersionSource возвращает текущую версию токенов пользователя. Версия растёт при смене
+// роли: access-токен с меньшей версией отклоняется, и клиент получает новую роль через refresh
+type TokenVersionSource interface {
+	TokenVersion(ctx context.Context, userID int) (int, error)
+}
+
+var (
+	tokenVersionsMu sync.RWMutex
+	tokenVersions   TokenVersionSource
+)
+
+// SetTokenVersionSource задаёт источник версий токенов. Без источника версия не проверяется
+func SetTokenVersionSource(source TokenVersionSource) {
+	tokenVersionsMu.Lock()
+	defer tokenVersionsMu.Unlock()
+	tokenVersions = source
+}
+
+func tokenVersionSource() TokenVersionSource {
+	tokenVersionsMu.RLock()
+	defer tokenVersionsMu.RUnlock()
+	return tokenVersions
+}
+
+// CheckTokenVersion возвращает ErrTokenOutdated, если токен выпущен с версией меньше текущей,
+// и ErrTokenVersionUnavailable, если источник версий недоступен
+func CheckTokenVersion(ctx context.Context, claims *Claims) error {
+	source := tokenVersionSource()
+	if source == nil {
+		return nil
+	}
+	version, err := source.TokenVersion(ctx, claims.UserID)
+	if err != nil {
+		log.Printf("Token version source unavailable, rejecting token: %v", err)
+		return fmt.Errorf("%w: %v", ErrTokenVersionUnavailable, err)
+	}
+	if claims.TokenVersion < version {
+		return ErrTokenOutdated
+	}
+	return nil
+}
+
+// ForgetTokenVersion сбрасывает закэшированную версию токенов пользователя, чтобы смена
+// роли сразу действовала на экземпляре, который её выполнил
+func ForgetTokenVersion(userID int) {
+	if cached, ok := tokenVersionSource().(*CachedTokenVersions); ok {
+		cached.Forget(userID)
+	}
+}
+
+type cachedVersion struct {
+	version   int
+	expiresAt time.Time
+}
+
+// CachedTokenVersions кэширует версии токенов на ttl, чтобы не читать учётную запись
+// на каждый запрос
+type CachedTokenVersions struct {
+	source TokenVersionSource
+	ttl    time.Duration
+	now    func() time.Time
+
+	mu       sync.Mutex
+	versions map[int]cachedVersion
+}
+
+// NewCachedTokenVersions создаёт кэш версий поверх source; неположительный ttl заменяется
+// значением DefaultTokenVersionTTL
+func NewCachedTokenVersions(source TokenVersionSource, ttl time.Duration) *CachedTokenVersions {
+	if ttl <= 0 {
+		ttl = DefaultTokenVersionTTL
+	}
+	return &CachedTokenVersions{
+		source:   source,
+		ttl:      ttl,
+		now:      time.Now,
+		versions: make(map[int]cachedVersion),
+	}
+}
+
+// TokenVersion возвращает версию из кэша или читает её из источника
+func (c *CachedTokenVersions) TokenVersion(ctx context.Context, userID int) (int, error) {
+	now := c.now()
+	c.mu.Lock()
+	cached, ok := c.versions[userID]
+	c.mu.Unlock()
+	if ok && now.Before(cached.expiresAt) {
+		return cached.version, nil
+	}
+
+	version, err := c.source.TokenVersion(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	// Истёкшие записи удаляются при записи, чтобы кэш не рос вместе с числом пользователей
+	for id, entry := range c.versions {
+		if !now.Before(entry.expiresAt) {
+			delete(c.versions, id)
+		}
+	}
+	c.versions[userID] = cachedVersion{version: version, expiresAt: now.Add(c.ttl)}
+	return version, nil
+}
+
+// Forget удаляет версию пользователя из кэша
+func (c *CachedTokenVersions) Forget(userID int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.versions, userID)
+}
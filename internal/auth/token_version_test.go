@@ -0,0 +1,111 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeTokenVersions хранит версии в памяти и считает обращения
+type fakeTokenVersions struct {
+	versions map[int]int
+	err      error
+	calls    int
+}
+
+func (f *fakeTokenVersions) TokenVersion(_ context.Context, userID int) (int, error) {
+	f.calls++
+	return f.versions[userID], f.err
+}
+
+func TestCheckTokenVersion(t *testing.T) {
+	source := &fakeTokenVersions{versions: map[int]int{1: 2}}
+	SetTokenVersionSource(source)
+	defer SetTokenVersionSource(nil)
+
+	assert.ErrorIs(t, CheckTokenVersion(context.Background(), &Claims{UserID: 1, TokenVersion: 1}), ErrTokenOutdated)
+	assert.NoError(t, CheckTokenVersion(context.Background(), &Claims{UserID: 1, TokenVersion: 2}))
+	assert.NoError(t, CheckTokenVersion(context.Background(), &Claims{UserID: 2}))
+
+	// Без ответа источника роль в токене не проверить, поэтому токен не принимается
+	source.err = errors.New("connection refused")
+	assert.ErrorIs(t, CheckTokenVersion(context.Background(), &Claims{UserID: 1, TokenVersion: 2}), ErrTokenVersionUnavailable)
+
+	SetTokenVersionSource(nil)
+	assert.NoError(t, CheckTokenVersion(context.Background(), &Claims{UserID: 1}))
+}
+
+func TestCachedTokenVersions(t *testing.T) {
+	source := &fakeTokenVersions{versions: map[int]int{1: 1}}
+	cached := NewCachedTokenVersions(source, 5*time.Second)
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	cached.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	version, err := cached.TokenVersion(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, 1, version)
+
+	source.versions[1] = 2
+	version, _ = cached.TokenVersion(ctx, 1)
+	assert.Equal(t, 1, version, "version is cached within ttl")
+
+	now = now.Add(5 * time.Second)
+	version, _ = cached.TokenVersion(ctx, 1)
+	assert.Equal(t, 2, version, "version is reloaded after ttl")
+
+	source.versions[1] = 3
+	cached.Forget(1)
+	version, _ = cached.TokenVersion(ctx, 1)
+	assert.Equal(t, 3, version)
+	assert.Equal(t, 3, source.calls)
+}
+
+func TestJWTAuthMiddleware_OutdatedTokenVersion(t *testing.T) {
+	SetTokenVersionSource(&fakeTokenVersions{versions: map[int]int{42: 1}})
+	defer SetTokenVersionSource(nil)
+
+	r := setupRouter()
+	r.GET("/test", JWTAuthMiddleware(), func(c *gin.Context) { c.String(http.StatusOK, CurrentRole(c)) })
+	request := func(tokenVersion int) *httptest.ResponseRecorder {
+		tokens, err := GenerateJWTWithVersion(42, "neo", "moderator", tokenVersion)
+		require.NoError(t, err)
+		req := httptest.NewRequest(http.MethodGet, "/test", nil)
+		req.Header.Set("Authorization", "Bearer "+tokens.AccessToken)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := request(0)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.JSONEq(t, `{"code":"token_outdated","error":"user role has changed, refresh the token"}`, w.Body.String())
+
+	w = request(1)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "moderator", w.Body.String())
+}
+
+func TestJWTAuthMiddleware_TokenVersionUnavailable(t *testing.T) {
+	SetTokenVersionSource(&fakeTokenVersions{err: errors.New("connection refused")})
+	defer SetTokenVersionSource(nil)
+
+	r := setupRouter()
+	r.GET("/test", JWTAuthMiddleware(), func(c *gin.Context) { c.Status(http.StatusOK) })
+	tokens, err := GenerateJWTWithVersion(42, "neo", "moderator", 1)
+	require.NoError(t, err)
+	req := httptest.NewRequest(http.MethodGet, "/test", nil)
+	req.Header.Set("Authorization", "Bearer "+tokens.AccessToken)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.JSONEq(t, `{"code":"token_version_unavailable","error":"token version check is unavailable"}`, w.Body.String())
+}
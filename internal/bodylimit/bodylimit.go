@@ -0,0 +1,146 @@
+// Package bodylimit ограничивает размер тела запроса и вложенность JSON до того,
+// как запрос дойдёт до обработчиков: гигантское или глубоко вложенное тело иначе
+// целиком разбиралось бы в память на эндпоинтах создания и массовых операций
+package bodylimit
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"cinematique/internal/apperror"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Значения по умолчанию
+const (
+	DefaultMaxBytes     = 1 << 20
+	DefaultMaxJSONDepth = 32
+)
+
+var (
+	// ErrTooLarge — тело запроса больше допустимого (413)
+	ErrTooLarge = apperror.TooLarge("request_body_too_large", "request body is too large")
+	// ErrTooDeep — JSON вложен глубже допустимого (400)
+	ErrTooDeep = apperror.Validation("json_too_deep", "JSON nesting is too deep")
+	// ErrMalformedJSON — тело с Content-Type application/json не является корректным JSON (400)
+	ErrMalformedJSON = apperror.Validation("malformed_json", "request body is not valid JSON")
+)
+
+var rejectedTotal = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "http_request_body_rejected_total",
+		Help: "Requests rejected before reaching handlers by reason (too_large, too_deep, malformed_json).",
+	},
+	[]string{"reason"},
+)
+
+func init() {
+	prometheus.MustRegister(rejectedTotal)
+}
+
+// Config задаёт ограничения. Нулевые значения выключают соответствующую проверку
+type Config struct {
+	MaxBytes     int64 // максимальный размер тела запроса
+	MaxJSONDepth int   // максимальная вложенность объектов и массивов JSON
+	// RouteLimits переопределяет MaxBytes для отдельных маршрутов; ключ — шаблон
+	// маршрута gin (c.FullPath()), например /api/actors/:id/photo
+	RouteLimits map[string]int64
+}
+
+// Middleware отклоняет запросы с телом больше лимита (413), а тела с Content-Type
+// application/json дополнительно проверяет на вложенность и корректность (400).
+// Проверенное JSON-тело подставляется обратно, и обработчики читают его как обычно.
+// Ошибки отдаются через c.Error, поэтому middleware подключается после apperror.Middleware
+func Middleware(cfg Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Body == nil || c.Request.Body == http.NoBody {
+			c.Next()
+			return
+		}
+
+		limit := cfg.MaxBytes
+		if routeLimit, ok := cfg.RouteLimits[c.FullPath()]; ok {
+			limit = routeLimit
+		}
+		if limit > 0 {
+			if c.Request.ContentLength > limit {
+				reject(c, "too_large", ErrTooLarge)
+				return
+			}
+			// Content-Length может отсутствовать (chunked) или не совпадать с телом
+			c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, limit)
+		}
+
+		if c.ContentType() != gin.MIMEJSON {
+			c.Next()
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				reject(c, "too_large", ErrTooLarge)
+				return
+			}
+			c.Error(apperror.Validation("invalid_body", "failed to read request body"))
+			c.Abort()
+			return
+		}
+		if len(bytes.TrimSpace(body)) > 0 {
+			if cfg.MaxJSONDepth > 0 && exceedsDepth(body, cfg.MaxJSONDepth) {
+				reject(c, "too_deep", ErrTooDeep)
+				return
+			}
+			if !json.Valid(body) {
+				reject(c, "malformed_json", ErrMalformedJSON)
+				return
+			}
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+		c.Next()
+	}
+}
+
+func reject(c *gin.Context, reason string, err error) {
+	rejectedTotal.WithLabelValues(reason).Inc()
+	c.Error(err)
+	c.Abort()
+}
+
+// exceedsDepth проверяет вложенность без разбора документа: скобки внутри строк
+// не учитываются. Некорректный JSON отсекается следующей проверкой
+func exceedsDepth(body []byte, maxDepth int) bool {
+	depth := 0
+	inString, escaped := false, false
+	for _, b := range body {
+		if inString {
+			switch {
+			case escaped:
+				escaped = false
+			case b == '\\':
+				escaped = true
+			case b == '"':
+				inString = false
+			}
+			continue
+		}
+		switch b {
+		case '"':
+			inString = true
+		case '{', '[':
+			depth++
+			if depth > maxDepth {
+				return true
+			}
+		case '}', ']':
+			depth--
+		}
+	}
+	return false
+}
@@ -0,0 +1,75 @@
+package bodylimit
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"cinematique/internal/apperror"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func newRouter(cfg Config) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(apperror.Middleware(), Middleware(cfg))
+	echo := func(c *gin.Context) {
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+		c.String(http.StatusOK, string(body))
+	}
+	r.POST("/movies", echo)
+	r.POST("/actors/:id/photo", echo)
+	return r
+}
+
+func TestMiddleware(t *testing.T) {
+	cfg := Config{MaxBytes: 64, MaxJSONDepth: 3, RouteLimits: map[string]int64{"/actors/:id/photo": 1024}}
+
+	tests := []struct {
+		name           string
+		path           string
+		contentType    string
+		body           string
+		chunked        bool
+		expectedStatus int
+		expectedCode   string
+	}{
+		{name: "small json", path: "/movies", contentType: "application/json", body: `{"title":"Heat"}`, expectedStatus: http.StatusOK},
+		{name: "json with charset", path: "/movies", contentType: "application/json; charset=utf-8", body: `{"a":[1]}`, expectedStatus: http.StatusOK},
+		{name: "too large by content length", path: "/movies", contentType: "application/json", body: `{"title":"` + strings.Repeat("x", 100) + `"}`, expectedStatus: http.StatusRequestEntityTooLarge, expectedCode: "request_body_too_large"},
+		{name: "too large chunked", path: "/movies", contentType: "application/json", body: `{"title":"` + strings.Repeat("x", 100) + `"}`, chunked: true, expectedStatus: http.StatusRequestEntityTooLarge, expectedCode: "request_body_too_large"},
+		{name: "route limit", path: "/actors/1/photo", contentType: "application/octet-stream", body: strings.Repeat("x", 500), expectedStatus: http.StatusOK},
+		{name: "too deep", path: "/movies", contentType: "application/json", body: `{"a":{"b":{"c":{}}}}`, expectedStatus: http.StatusBadRequest, expectedCode: "json_too_deep"},
+		{name: "brackets inside strings do not count", path: "/movies", contentType: "application/json", body: `{"a":"[[[[{{\"{{"}`, expectedStatus: http.StatusOK},
+		{name: "malformed", path: "/movies", contentType: "application/json", body: `{"title":`, expectedStatus: http.StatusBadRequest, expectedCode: "malformed_json"},
+		{name: "non-json body is not parsed", path: "/movies", contentType: "text/csv", body: `{"title":`, expectedStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", tt.contentType)
+			if tt.chunked {
+				req.ContentLength = -1
+			}
+			w := httptest.NewRecorder()
+			newRouter(cfg).ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedCode != "" {
+				assert.Equal(t, apperror.ContentType, w.Header().Get("Content-Type"))
+				assert.Contains(t, w.Body.String(), `"code":"`+tt.expectedCode+`"`)
+			} else {
+				assert.Equal(t, tt.body, w.Body.String())
+			}
+		})
+	}
+}
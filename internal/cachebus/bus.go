@@ -0,0 +1,144 @@
+// Package cachebus рассылает сбросы кэшей в памяти между экземплярами сервиса через
+// канал Redis (pub/sub). Экземпляр, изменивший данные, сбрасывает свой кэш сам и публикует
+// ключ кэша; остальные получают сообщение и сбрасывают свои копии.
+//
+// Pub/sub не хранит сообщения: экземпляр, отключённый от Redis в момент публикации,
+// сброс пропустит. Поэтому кэши, которые рассылают сбросы, всё равно должны иметь TTL —
+// он ограничивает время, в течение которого такой экземпляр отдаёт устаревшие данные
+package cachebus
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/redis/go-redis/v9"
+)
+
+// DefaultChannel — канал Redis, в который публикуются сбросы кэшей
+const DefaultChannel = "cinematique:cache-invalidation"
+
+var invalidationsTotal = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "cache_invalidations_total",
+		Help: "Total number of cache invalidation messages by cache key and direction (published, received).",
+	},
+	[]string{"key", "direction"},
+)
+
+func init() {
+	prometheus.MustRegister(invalidationsTotal)
+}
+
+// RedisClient — команды Redis, нужные шине
+type RedisClient interface {
+	Publish(ctx context.Context, channel string, message interface{}) *redis.IntCmd
+	Subscribe(ctx context.Context, channels ...string) *redis.PubSub
+}
+
+// message — сообщение о сбросе кэша. Instance отличает собственные сообщения экземпляра,
+// которые Redis тоже доставляет подписчику
+type message struct {
+	Instance string `json:"instance"`
+	Key      string `json:"key"`
+}
+
+// Bus публикует сбросы кэшей и вызывает обработчики сбросов, пришедших от других экземпляров
+type Bus struct {
+	client   RedisClient
+	channel  string
+	instance string
+
+	mu       sync.RWMutex
+	handlers map[string][]func()
+}
+
+// New создаёт шину на канале channel
+func New(client RedisClient, channel string) *Bus {
+	return &Bus{
+		client:   client,
+		channel:  channel,
+		instance: newInstanceID(),
+		handlers: make(map[string][]func()),
+	}
+}
+
+// Handle регистрирует сброс локального кэша key по сообщению от другого экземпляра.
+// Обработчик не должен публиковать сброс повторно, иначе экземпляры будут пересылать его друг другу
+func (b *Bus) Handle(key string, invalidate func()) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.handlers[key] = append(b.handlers[key], invalidate)
+}
+
+// Publish сообщает другим экземплярам, что кэш key нужно сбросить
+func (b *Bus) Publish(ctx context.Context, key string) error {
+	payload, err := json.Marshal(message{Instance: b.instance, Key: key})
+	if err != nil {
+		return err
+	}
+	if err := b.client.Publish(ctx, b.channel, payload).Err(); err != nil {
+		return fmt.Errorf("publishing cache invalidation %q: %w", key, err)
+	}
+	invalidationsTotal.WithLabelValues(key, "published").Inc()
+	return nil
+}
+
+// Run подписывается на канал и обрабатывает сообщения до отмены ctx. Клиент go-redis
+// сам переподключается к Redis после обрыва соединения
+func (b *Bus) Run(ctx context.Context) {
+	pubsub := b.client.Subscribe(ctx, b.channel)
+	defer pubsub.Close()
+	b.consume(ctx, pubsub.Channel())
+}
+
+// consume вызывает обработчики для сообщений из messages до отмены ctx или закрытия канала
+func (b *Bus) consume(ctx context.Context, messages <-chan *redis.Message) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case msg, ok := <-messages:
+			if !ok {
+				return
+			}
+			b.dispatch(msg.Payload)
+		}
+	}
+}
+
+func (b *Bus) dispatch(payload string) {
+	var msg message
+	if err := json.Unmarshal([]byte(payload), &msg); err != nil {
+		log.Printf("Ignoring malformed cache invalidation message: %v", err)
+		return
+	}
+	// Свой кэш экземпляр уже сбросил до публикации
+	if msg.Instance == b.instance {
+		return
+	}
+	b.mu.RLock()
+	handlers := b.handlers[msg.Key]
+	b.mu.RUnlock()
+	if len(handlers) == 0 {
+		return
+	}
+	invalidationsTotal.WithLabelValues(msg.Key, "received").Inc()
+	for _, invalidate := range handlers {
+		invalidate()
+	}
+}
+
+func newInstanceID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return time.Now().Format("20060102150405.000000000")
+	}
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,104 @@
+package cachebus
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockRedisClient мок для Redis клиента
+type MockRedisClient struct {
+	mock.Mock
+}
+
+func (m *MockRedisClient) Publish(ctx context.Context, channel string, message interface{}) *redis.IntCmd {
+	args := m.Called(ctx, channel, message)
+	return args.Get(0).(*redis.IntCmd)
+}
+
+func (m *MockRedisClient) Subscribe(ctx context.Context, channels ...string) *redis.PubSub {
+	args := m.Called(ctx, channels)
+	return args.Get(0).(*redis.PubSub)
+}
+
+func TestBus_Publish(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("publishes key with instance id", func(t *testing.T) {
+		client := new(MockRedisClient)
+		bus := New(client, DefaultChannel)
+		var payload []byte
+		client.On("Publish", ctx, DefaultChannel, mock.Anything).
+			Run(func(args mock.Arguments) { payload = args.Get(2).([]byte) }).
+			Return(redis.NewIntResult(2, nil))
+
+		require.NoError(t, bus.Publish(ctx, "actors_with_movies"))
+		var msg message
+		require.NoError(t, json.Unmarshal(payload, &msg))
+		assert.Equal(t, message{Instance: bus.instance, Key: "actors_with_movies"}, msg)
+	})
+
+	t.Run("redis error", func(t *testing.T) {
+		client := new(MockRedisClient)
+		client.On("Publish", ctx, DefaultChannel, mock.Anything).Return(redis.NewIntResult(0, errors.New("connection refused")))
+
+		err := New(client, DefaultChannel).Publish(ctx, "actors_with_movies")
+		assert.ErrorContains(t, err, "connection refused")
+	})
+}
+
+func TestBus_Consume(t *testing.T) {
+	bus := New(new(MockRedisClient), DefaultChannel)
+	invalidated := make(chan string, 4)
+	bus.Handle("actors_with_movies", func() { invalidated <- "actors_with_movies" })
+
+	encode := func(instance, key string) *redis.Message {
+		payload, _ := json.Marshal(message{Instance: instance, Key: key})
+		return &redis.Message{Channel: DefaultChannel, Payload: string(payload)}
+	}
+	messages := make(chan *redis.Message, 4)
+	messages <- encode(bus.instance, "actors_with_movies") // собственное сообщение
+	messages <- &redis.Message{Channel: DefaultChannel, Payload: "not json"}
+	messages <- encode("other", "unknown_cache")
+	messages <- encode("other", "actors_with_movies")
+	close(messages)
+
+	done := make(chan struct{})
+	go func() {
+		bus.consume(context.Background(), messages)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("consume did not return after the channel was closed")
+	}
+
+	// Сбрасывает кэш только сообщение другого экземпляра с известным ключом
+	require.Len(t, invalidated, 1)
+	assert.Equal(t, "actors_with_movies", <-invalidated)
+}
+
+func TestBus_ConsumeStopsOnCancel(t *testing.T) {
+	bus := New(new(MockRedisClient), DefaultChannel)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		bus.consume(ctx, make(chan *redis.Message))
+		close(done)
+	}()
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("consume did not stop after cancel")
+	}
+}
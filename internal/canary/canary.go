@@ -0,0 +1,172 @@
+package canary
+
+import (
+	"log"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Варианты реализации в метриках
+const (
+	VariantControl   = "control"
+	VariantCandidate = "candidate"
+)
+
+// Исходы вызовов, попавших в канарейку, в метриках
+const (
+	OutcomeMatch          = "match"
+	OutcomeMismatch       = "mismatch"
+	OutcomeCandidateError = "candidate_error"
+	OutcomeControlError   = "control_error"
+)
+
+var (
+	canaryCallsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "canary_calls_total",
+			Help: "Total number of calls served by each variant of a canary experiment.",
+		},
+		[]string{"experiment", "variant"},
+	)
+	canaryRunsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "canary_runs_total",
+			Help: "Total number of canary calls that ran both variants, by outcome of the comparison.",
+		},
+		[]string{"experiment", "outcome"},
+	)
+	canaryMismatchesTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "canary_mismatches_total",
+			Help: "Total number of canary calls where candidate and control results differed.",
+		},
+		[]string{"experiment"},
+	)
+	canaryCandidateErrorsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "canary_candidate_errors_total",
+			Help: "Total number of canary calls where the candidate failed and control result was served.",
+		},
+		[]string{"experiment"},
+	)
+	canaryDurationSeconds = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "canary_duration_seconds",
+			Help:    "Duration of each variant of a canary experiment.",
+			Buckets: prometheus.DefBuckets,
+		},
+		[]string{"experiment", "variant"},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(canaryCallsTotal, canaryRunsTotal, canaryMismatchesTotal, canaryCandidateErrorsTotal, canaryDurationSeconds)
+}
+
+// Experiment направляет заданный процент вызовов в новую реализацию (candidate) вместо текущей (control).
+//
+// Для вызовов, попавших в канарейку, обе реализации выполняются параллельно: исход сравнения и время
+// каждой пишутся в метрики, а вызывающему возвращается результат candidate. В лог попадают только
+// ошибки, расхождения и возврат к совпадающим результатам после расхождения.
+// Если candidate завершился ошибкой, возвращается результат control, так что новая реализация
+// не может сломать запрос. Остальные вызовы обслуживает только control
+type Experiment[T any] struct {
+	name    string
+	percent atomic.Int32
+	diff    func(control, candidate T) string
+	roll    func() int // случайное число 0..99
+	// diverged — последний вызов в канарейке завершился ошибкой или расхождением
+	diverged atomic.Bool
+}
+
+// New создаёт эксперимент name с долей percent (0–100) вызовов, направляемых в candidate.
+// diff сравнивает результаты двух реализаций и возвращает краткое описание расхождения
+// или пустую строку, если результаты совпадают
+func New[T any](name string, percent int, diff func(control, candidate T) string) *Experiment[T] {
+	e := &Experiment[T]{name: name, diff: diff, roll: func() int { return rand.Intn(100) }}
+	e.SetPercent(percent)
+	return e
+}
+
+// SetPercent меняет долю вызовов, направляемых в candidate; значение ограничивается 0–100
+func (e *Experiment[T]) SetPercent(percent int) {
+	if percent < 0 {
+		percent = 0
+	}
+	if percent > 100 {
+		percent = 100
+	}
+	e.percent.Store(int32(percent))
+}
+
+// Percent возвращает текущую долю вызовов, направляемых в candidate
+func (e *Experiment[T]) Percent() int {
+	return int(e.percent.Load())
+}
+
+// Run выполняет вызов через control или, с вероятностью Percent, через обе реализации
+func (e *Experiment[T]) Run(control, candidate func() (T, error)) (T, error) {
+	if e.roll() >= e.Percent() {
+		canaryCallsTotal.WithLabelValues(e.name, VariantControl).Inc()
+		return e.timed(VariantControl, control)
+	}
+	canaryCallsTotal.WithLabelValues(e.name, VariantCandidate).Inc()
+
+	var (
+		wg                       sync.WaitGroup
+		controlRes, candidateRes T
+		controlErr, candidateErr error
+		controlDur, candidateDur time.Duration
+	)
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		start := time.Now()
+		controlRes, controlErr = e.timed(VariantControl, control)
+		controlDur = time.Since(start)
+	}()
+	go func() {
+		defer wg.Done()
+		start := time.Now()
+		candidateRes, candidateErr = e.timed(VariantCandidate, candidate)
+		candidateDur = time.Since(start)
+	}()
+	wg.Wait()
+
+	if candidateErr != nil {
+		e.record(OutcomeCandidateError)
+		canaryCandidateErrorsTotal.WithLabelValues(e.name).Inc()
+		log.Printf("canary %s: candidate failed after %v, serving control (%v): %v", e.name, candidateDur, controlDur, candidateErr)
+		return controlRes, controlErr
+	}
+	if controlErr != nil {
+		e.record(OutcomeControlError)
+		log.Printf("canary %s: control failed after %v, candidate succeeded in %v: %v", e.name, controlDur, candidateDur, controlErr)
+	} else if diff := e.diff(controlRes, candidateRes); diff != "" {
+		e.record(OutcomeMismatch)
+		canaryMismatchesTotal.WithLabelValues(e.name).Inc()
+		log.Printf("canary %s: results differ (control %v, candidate %v): %s", e.name, controlDur, candidateDur, diff)
+	} else if e.record(OutcomeMatch) {
+		log.Printf("canary %s: results match again (control %v, candidate %v)", e.name, controlDur, candidateDur)
+	}
+	return candidateRes, nil
+}
+
+// record учитывает исход вызова в метрике и запоминает, разошлись ли реализации.
+// Возвращает true, если исход сменил состояние эксперимента с расхождения на совпадение
+func (e *Experiment[T]) record(outcome string) bool {
+	canaryRunsTotal.WithLabelValues(e.name, outcome).Inc()
+	return e.diverged.Swap(outcome != OutcomeMatch) && outcome == OutcomeMatch
+}
+
+// timed выполняет реализацию и записывает её длительность в метрику
+func (e *Experiment[T]) timed(variant string, fn func() (T, error)) (T, error) {
+	start := time.Now()
+	res, err := fn()
+	canaryDurationSeconds.WithLabelValues(e.name, variant).Observe(time.Since(start).Seconds())
+	return res, err
+}
@@ -0,0 +1,105 @@
+package canary
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func diffInts(control, candidate int) string {
+	if control == candidate {
+		return ""
+	}
+	return fmt.Sprintf("%d != %d", control, candidate)
+}
+
+func TestExperiment_RoutesByPercent(t *testing.T) {
+	e := New("routing", 30, diffInts)
+	calls := map[string]int{}
+	control := func() (int, error) { calls[VariantControl]++; return 1, nil }
+	candidate := func() (int, error) { calls[VariantCandidate]++; return 1, nil }
+
+	// Детерминированный «кубик»: значения 0..99 по кругу
+	next := 0
+	e.roll = func() int { v := next % 100; next++; return v }
+	for i := 0; i < 100; i++ {
+		_, _ = e.Run(control, candidate)
+	}
+
+	// В канарейку попадают 30 вызовов; для них выполняются обе реализации
+	assert.Equal(t, 30, calls[VariantCandidate])
+	assert.Equal(t, 100, calls[VariantControl])
+	assert.Equal(t, float64(30), testutil.ToFloat64(canaryCallsTotal.WithLabelValues("routing", VariantCandidate)))
+	assert.Equal(t, float64(70), testutil.ToFloat64(canaryCallsTotal.WithLabelValues("routing", VariantControl)))
+}
+
+func TestExperiment_ServesCandidateAndCountsMismatches(t *testing.T) {
+	e := New("mismatch", 100, diffInts)
+
+	res, err := e.Run(func() (int, error) { return 1, nil }, func() (int, error) { return 2, nil })
+	assert.NoError(t, err)
+	assert.Equal(t, 2, res)
+	assert.Equal(t, float64(1), testutil.ToFloat64(canaryMismatchesTotal.WithLabelValues("mismatch")))
+
+	res, err = e.Run(func() (int, error) { return 3, nil }, func() (int, error) { return 3, nil })
+	assert.NoError(t, err)
+	assert.Equal(t, 3, res)
+	assert.Equal(t, float64(1), testutil.ToFloat64(canaryMismatchesTotal.WithLabelValues("mismatch")))
+}
+
+func TestExperiment_FallsBackToControlOnCandidateError(t *testing.T) {
+	e := New("fallback", 100, diffInts)
+
+	res, err := e.Run(func() (int, error) { return 1, nil }, func() (int, error) { return 0, errors.New("boom") })
+	assert.NoError(t, err)
+	assert.Equal(t, 1, res)
+	assert.Equal(t, float64(1), testutil.ToFloat64(canaryCandidateErrorsTotal.WithLabelValues("fallback")))
+}
+
+func TestExperiment_ZeroPercentNeverCallsCandidate(t *testing.T) {
+	e := New("disabled", 0, diffInts)
+	for i := 0; i < 50; i++ {
+		_, _ = e.Run(func() (int, error) { return 1, nil }, func() (int, error) {
+			t.Fatal("candidate must not be called")
+			return 0, nil
+		})
+	}
+
+	e.SetPercent(150)
+	assert.Equal(t, 100, e.Percent())
+	e.SetPercent(-5)
+	assert.Equal(t, 0, e.Percent())
+}
+
+func TestExperiment_LogsOnlyFailuresAndStateChanges(t *testing.T) {
+	var buf bytes.Buffer
+	defer log.SetOutput(log.Writer())
+	log.SetOutput(&buf)
+
+	e := New("logging", 100, diffInts)
+	match := func() (int, error) { return 1, nil }
+	differ := func() (int, error) { return 2, nil }
+	fail := func() (int, error) { return 0, errors.New("boom") }
+
+	for _, candidate := range []func() (int, error){match, match, differ, match, match, fail, match} {
+		_, _ = e.Run(match, candidate)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 4)
+	assert.Contains(t, lines[0], "canary logging: results differ")
+	assert.Contains(t, lines[1], "canary logging: results match again")
+	assert.Contains(t, lines[2], "canary logging: candidate failed")
+	assert.Contains(t, lines[3], "canary logging: results match again")
+
+	assert.Equal(t, float64(5), testutil.ToFloat64(canaryRunsTotal.WithLabelValues("logging", OutcomeMatch)))
+	assert.Equal(t, float64(1), testutil.ToFloat64(canaryRunsTotal.WithLabelValues("logging", OutcomeMismatch)))
+	assert.Equal(t, float64(1), testutil.ToFloat64(canaryRunsTotal.WithLabelValues("logging", OutcomeCandidateError)))
+}
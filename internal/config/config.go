@@ -1,9 +1,14 @@
 package config
 
 import (
+	"cinematique/internal/accesslog"
+	"cinematique/internal/bodylimit"
 	"cinematique/internal/keycloak"
+	"cinematique/internal/mailer"
+	"cinematique/internal/tracing"
 	"os"
 	"strconv"
+	"time"
 )
 
 type Config struct {
@@ -13,6 +18,27 @@ type Config struct {
 	Password string
 	DBName   string
 	SSLMode  string
+
+	// Реплика для чтения: те же пользователь, пароль и БД на другом хосте.
+	// Пустой ReplicaHost — реплики нет, всё читается из основной БД
+	ReplicaHost string
+	ReplicaPort string
+
+	// PreparedStatements включает подготовленные выражения для горячих запросов репозиториев.
+	// Выключается за PgBouncer в режиме transaction pooling, который их не поддерживает
+	PreparedStatements bool
+
+	// Таймауты одного вызова репозитория: ReadTimeout для чтений, WriteTimeout для изменений.
+	// 0 выключает ограничение
+	ReadTimeout  time.Duration
+	WriteTimeout time.Duration
+
+	// Пул подключений; 0 — значение по умолчанию пакета postgres. MaxIdleConns меньше
+	// MaxOpenConns под нагрузкой закрывает вернувшиеся в пул соединения и открывает их заново
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+	ConnMaxIdleTime time.Duration
 }
 
 // KeycloakConfig содержит настройки Keycloak
@@ -21,6 +47,16 @@ type KeycloakConfig struct {
 	ServerURL string `json:"server_url"`
 	Realm     string `json:"realm"`
 	ClientID  string `json:"client_id"`
+
+	Timeout        time.Duration `json:"timeout"`          // таймаут запроса к Keycloak
+	MaxRetries     int           `json:"max_retries"`      // повторы неудачного запроса ключей
+	RetryDelay     time.Duration `json:"retry_delay"`      // задержка перед первым повтором
+	VerifyCacheTTL time.Duration `json:"verify_cache_ttl"` // время жизни кэша проверенных токенов; 0 — без кэша
+
+	// Синхронизация пользователей в таблицу users через Admin API; нужен секрет сервисной
+	// учётной записи клиента. 0 в UserSyncInterval или пустой секрет выключают синхронизацию
+	ClientSecret     string        `json:"-"`
+	UserSyncInterval time.Duration `json:"user_sync_interval"`
 }
 
 // RedisConfig содержит настройки Redis
@@ -37,14 +73,172 @@ type RateLimitConfig struct {
 	RequestsPerMinute   int      `json:"requests_per_minute"`
 	WindowSeconds       int      `json:"window_seconds"`
 	RestrictedEndpoints []string `json:"restricted_endpoints"`
+
+	// Отдельная, более строгая корзина для поиска: такие запросы дороже остальных.
+	// 0 выключает её, и поиск ограничивается только общей корзиной
+	SearchRequestsPerMinute int      `json:"search_requests_per_minute"`
+	SearchEndpoints         []string `json:"search_endpoints"`
+}
+
+// StorageConfig содержит настройки хранилища файлов (фото актёров, постеры)
+type StorageConfig struct {
+	Dir     string `json:"dir"`      // каталог с файлами
+	BaseURL string `json:"base_url"` // публичный префикс адресов файлов
+}
+
+// CanaryConfig содержит доли запросов (0–100), направляемых в новые реализации
+type CanaryConfig struct {
+	TitleSearchPercent int `json:"title_search_percent"` // триграммный поиск по названию вместо ILIKE
+}
+
+// CacheConfig содержит настройки кэшей в памяти процесса; нулевой TTL выключает кэш
+type CacheConfig struct {
+	ActorsWithMoviesTTL time.Duration `json:"actors_with_movies_ttl"`
+	StatsTTL            time.Duration `json:"stats_ttl"` // статистика каталога (GET /stats)
+}
+
+// IdempotencyConfig содержит настройки заголовка Idempotency-Key
+type IdempotencyConfig struct {
+	TTL time.Duration `json:"ttl"` // сколько хранится ответ на запрос с ключом
+}
+
+// TMDBConfig содержит настройки импорта фильмов из TMDB; пустой ключ выключает импорт
+type TMDBConfig struct {
+	APIKey  string `json:"-"`
+	BaseURL string `json:"base_url"`
+	MaxCast int    `json:"max_cast"` // сколько актёров из начала титров импортировать
+}
+
+// AuthConfig содержит парольную политику и настройки блокировки после неудачных входов
+type AuthConfig struct {
+	BcryptCost            int           `json:"bcrypt_cost"`
+	PasswordHash          string        `json:"password_hash"` // алгоритм новых хэшей: argon2id или bcrypt
+	Argon2MemoryKB        int           `json:"argon2_memory_kb"`
+	Argon2Iterations      int           `json:"argon2_iterations"`
+	Argon2Parallelism     int           `json:"argon2_parallelism"`
+	PasswordMinLength     int           `json:"password_min_length"`
+	PasswordRequireUpper  bool          `json:"password_require_upper"`
+	PasswordRequireLower  bool          `json:"password_require_lower"`
+	PasswordRequireDigit  bool          `json:"password_require_digit"`
+	PasswordRequireSymbol bool          `json:"password_require_symbol"`
+	MaxFailedLogins       int           `json:"max_failed_logins"` // 0 выключает блокировку
+	LockoutDuration       time.Duration `json:"lockout_duration"`
+	PasswordResetURL      string        `json:"password_reset_url"` // страница клиента, куда ведёт ссылка из письма
+	PasswordResetTTL      time.Duration `json:"password_reset_ttl"`
+	TokenVersionCacheTTL  time.Duration `json:"token_version_cache_ttl"` // задержка, с которой смена роли действует на других экземплярах
+	// PublicReads — режим публичного чтения: GET-маршруты каталога доступны без токена
+	PublicReads bool `json:"public_reads"`
+}
+
+// MailerConfig содержит настройки SMTP-сервера для писем пользователям; пустой хост выключает отправку
+type MailerConfig struct {
+	SMTPHost     string `json:"smtp_host"`
+	SMTPPort     string `json:"smtp_port"`
+	SMTPUsername string `json:"smtp_username"`
+	SMTPPassword string `json:"-"`
+	From         string `json:"from"`
+}
+
+// RequestLimitsConfig содержит ограничения тела HTTP-запроса; 0 выключает проверку
+type RequestLimitsConfig struct {
+	MaxBodyBytes int64 `json:"max_body_bytes"`
+	MaxJSONDepth int   `json:"max_json_depth"` // вложенность объектов и массивов JSON
+}
+
+// AccessLogConfig содержит настройки журнала запросов; 0 в SlowThreshold выключает предупреждения
+type AccessLogConfig struct {
+	SlowThreshold time.Duration `json:"slow_threshold"` // запросы дольше порога отмечаются как медленные
+}
+
+// MetricsConfig содержит учётные данные для /metrics: Bearer-токен и (или) basic auth.
+// Если ничего не задано, метрики доступны без аутентификации
+type MetricsConfig struct {
+	Username string `json:"username"`
+	Password string `json:"-"`
+	Token    string `json:"-"`
+}
+
+// AdminConfig содержит настройки административных операций
+type AdminConfig struct {
+	// OrphanPurgeThreshold — сколько актёров без фильмов удаляется без confirm_count
+	OrphanPurgeThreshold int `json:"orphan_purge_threshold"`
+}
+
+// CatalogConfig содержит правила изменения каталога, которые выбираются при развёртывании
+type CatalogConfig struct {
+	// MovieDeletePolicy — cascade (ссылки удаляются вместе с фильмом) или restrict (удаление отклоняется с 409)
+	MovieDeletePolicy string `json:"movie_delete_policy"`
+	// StrictEvents — фильм создаётся, только если событие movie_created принято в очередь Kafka;
+	// иначе созданная запись удаляется и клиент получает 503
+	StrictEvents bool `json:"strict_events"`
+}
+
+// ListingConfig содержит сортировку и размеры страниц списков по умолчанию. *PageSize отдаётся
+// без ?limit=, *MaxPageSize — наибольший размер страницы, который может запросить клиент
+type ListingConfig struct {
+	MovieSort              string `json:"movie_sort"` // GET /movies/sorted без sort, например rating:desc
+	MoviesPageSize         int    `json:"movies_page_size"`
+	MoviesMaxPageSize      int    `json:"movies_max_page_size"`
+	MovieCursorPageSize    int    `json:"movie_cursor_page_size"` // GET /movies
+	MovieCursorMaxPageSize int    `json:"movie_cursor_max_page_size"`
+	PopularPageSize        int    `json:"popular_page_size"`
+	PopularMaxPageSize     int    `json:"popular_max_page_size"`
+	ActorsPageSize         int    `json:"actors_page_size"` // список и поиск актёров, список актёров без фильмов
+	ActorsMaxPageSize      int    `json:"actors_max_page_size"`
+	CastPageSize           int    `json:"cast_page_size"` // GET /movies/:id/cast
+	CastMaxPageSize        int    `json:"cast_max_page_size"`
+	SearchPageSize         int    `json:"search_page_size"` // GET /search
+	SearchMaxPageSize      int    `json:"search_max_page_size"`
+}
+
+// RatingsConfig содержит веса источников в отображаемом рейтинге фильма; 0 исключает источник
+type RatingsConfig struct {
+	InternalWeight       float64 `json:"internal_weight"`
+	IMDbWeight           float64 `json:"imdb_weight"`
+	RottenTomatoesWeight float64 `json:"rotten_tomatoes_weight"`
+}
+
+// KafkaConfig содержит настройки пула продюсеров Kafka. BackpressurePolicy — что делать
+// при заполненном буфере: drop_new, drop_oldest или block (ждать не дольше BlockTimeout)
+type KafkaConfig struct {
+	ProducerWorkers    int           `json:"producer_workers"`
+	ProducerBufferSize int           `json:"producer_buffer_size"`
+	BackpressurePolicy string        `json:"backpressure_policy"`
+	BlockTimeout       time.Duration `json:"block_timeout"`
+	FlushTimeout       time.Duration `json:"flush_timeout"` // сколько ждать отправки очереди при остановке
+}
+
+// TracingConfig содержит настройки трассировки OpenTelemetry
+type TracingConfig struct {
+	Enabled     bool    `json:"enabled"`
+	ServiceName string  `json:"service_name"`
+	Endpoint    string  `json:"endpoint"`     // OTLP/HTTP коллектор, например http://jaeger:4318
+	SampleRatio float64 `json:"sample_ratio"` // доля записываемых трасс (0–1)
 }
 
 // AppConfig содержит всю конфигурацию приложения
 type AppConfig struct {
-	Database  Config          `json:"database"`
-	Keycloak  KeycloakConfig  `json:"keycloak"`
-	Redis     RedisConfig     `json:"redis"`
-	RateLimit RateLimitConfig `json:"rate_limit"`
+	Database    Config            `json:"database"`
+	Keycloak    KeycloakConfig    `json:"keycloak"`
+	Redis       RedisConfig       `json:"redis"`
+	RateLimit   RateLimitConfig   `json:"rate_limit"`
+	Storage     StorageConfig     `json:"storage"`
+	Canary      CanaryConfig      `json:"canary"`
+	Cache       CacheConfig       `json:"cache"`
+	Idempotency IdempotencyConfig `json:"idempotency"`
+	TMDB        TMDBConfig        `json:"tmdb"`
+	Auth        AuthConfig        `json:"auth"`
+	Mailer      MailerConfig      `json:"mailer"`
+	Tracing     TracingConfig     `json:"tracing"`
+	Metrics     MetricsConfig     `json:"metrics"`
+	Admin       AdminConfig       `json:"admin"`
+	Catalog     CatalogConfig     `json:"catalog"`
+	Listing     ListingConfig     `json:"listing"`
+	Ratings     RatingsConfig     `json:"ratings"`
+	Kafka       KafkaConfig       `json:"kafka"`
+
+	RequestLimits RequestLimitsConfig `json:"request_limits"`
+	AccessLog     AccessLogConfig     `json:"access_log"`
 }
 
 // LoadConfig загружает конфигурацию из переменных окружения
@@ -57,12 +251,33 @@ func LoadConfig() *AppConfig {
 			Password: getEnv("DB_PASSWORD", ""),
 			DBName:   getEnv("DB_NAME", "cinematique"),
 			SSLMode:  getEnv("DB_SSLMODE", "disable"),
+
+			ReplicaHost: getEnv("DB_REPLICA_HOST", ""),
+			ReplicaPort: getEnv("DB_REPLICA_PORT", getEnv("DB_PORT", "5432")),
+
+			PreparedStatements: getEnvBool("DB_PREPARED_STATEMENTS", true),
+
+			ReadTimeout:  getEnvDuration("DB_READ_TIMEOUT", 2*time.Second),
+			WriteTimeout: getEnvDuration("DB_WRITE_TIMEOUT", 5*time.Second),
+
+			MaxOpenConns:    getEnvInt("DB_MAX_OPEN_CONNS", 25),
+			MaxIdleConns:    getEnvInt("DB_MAX_IDLE_CONNS", 25),
+			ConnMaxLifetime: getEnvDuration("DB_CONN_MAX_LIFETIME", 30*time.Minute),
+			ConnMaxIdleTime: getEnvDuration("DB_CONN_MAX_IDLE_TIME", 5*time.Minute),
 		},
 		Keycloak: KeycloakConfig{
 			Enabled:   getEnvBool("KEYCLOAK_ENABLED", false),
 			ServerURL: getEnv("KEYCLOAK_SERVER_URL", ""),
 			Realm:     getEnv("KEYCLOAK_REALM", ""),
 			ClientID:  getEnv("KEYCLOAK_CLIENT_ID", ""),
+
+			Timeout:        getEnvDuration("KEYCLOAK_TIMEOUT", 5*time.Second),
+			MaxRetries:     getEnvInt("KEYCLOAK_MAX_RETRIES", 3),
+			RetryDelay:     getEnvDuration("KEYCLOAK_RETRY_DELAY", 200*time.Millisecond),
+			VerifyCacheTTL: getEnvDuration("KEYCLOAK_VERIFY_CACHE_TTL", 30*time.Second),
+
+			ClientSecret:     getEnv("KEYCLOAK_CLIENT_SECRET", ""),
+			UserSyncInterval: getEnvDuration("KEYCLOAK_USER_SYNC_INTERVAL", 15*time.Minute),
 		},
 		Redis: RedisConfig{
 			Host:     getEnv("REDIS_HOST", "localhost"),
@@ -78,7 +293,129 @@ func LoadConfig() *AppConfig {
 				"/api/movies",
 				"/api/actors",
 			},
+			SearchRequestsPerMinute: getEnvInt("RATE_LIMIT_SEARCH_REQUESTS_PER_MINUTE", 30),
+			SearchEndpoints: []string{
+				"/api/movies/search",
+				"/api/actors/search",
+			},
+		},
+		Storage: StorageConfig{
+			Dir:     getEnv("STORAGE_DIR", "./data/media"),
+			BaseURL: getEnv("STORAGE_BASE_URL", "/media"),
+		},
+		Canary: CanaryConfig{
+			TitleSearchPercent: getEnvInt("CANARY_TITLE_SEARCH_PERCENT", 0),
+		},
+		Cache: CacheConfig{
+			ActorsWithMoviesTTL: getEnvDuration("CACHE_ACTORS_WITH_MOVIES_TTL", 30*time.Second),
+			StatsTTL:            getEnvDuration("CACHE_STATS_TTL", 5*time.Minute),
+		},
+		Idempotency: IdempotencyConfig{
+			TTL: getEnvDuration("IDEMPOTENCY_TTL", 24*time.Hour),
+		},
+		TMDB: TMDBConfig{
+			APIKey:  getEnv("TMDB_API_KEY", ""),
+			BaseURL: getEnv("TMDB_BASE_URL", "https://api.themoviedb.org/3"),
+			MaxCast: getEnvInt("TMDB_MAX_CAST", 10),
+		},
+		Auth: AuthConfig{
+			BcryptCost:            getEnvInt("AUTH_BCRYPT_COST", 10),
+			PasswordHash:          getEnv("AUTH_PASSWORD_HASH", "argon2id"),
+			Argon2MemoryKB:        getEnvInt("AUTH_ARGON2_MEMORY_KB", 64*1024),
+			Argon2Iterations:      getEnvInt("AUTH_ARGON2_ITERATIONS", 3),
+			Argon2Parallelism:     getEnvInt("AUTH_ARGON2_PARALLELISM", 2),
+			PasswordMinLength:     getEnvInt("AUTH_PASSWORD_MIN_LENGTH", 8),
+			PasswordRequireUpper:  getEnvBool("AUTH_PASSWORD_REQUIRE_UPPER", false),
+			PasswordRequireLower:  getEnvBool("AUTH_PASSWORD_REQUIRE_LOWER", false),
+			PasswordRequireDigit:  getEnvBool("AUTH_PASSWORD_REQUIRE_DIGIT", false),
+			PasswordRequireSymbol: getEnvBool("AUTH_PASSWORD_REQUIRE_SYMBOL", false),
+			MaxFailedLogins:       getEnvInt("AUTH_MAX_FAILED_LOGINS", 5),
+			LockoutDuration:       getEnvDuration("AUTH_LOCKOUT_DURATION", 15*time.Minute),
+			PasswordResetURL:      getEnv("AUTH_PASSWORD_RESET_URL", "http://localhost:3000/reset-password"),
+			PasswordResetTTL:      getEnvDuration("AUTH_PASSWORD_RESET_TTL", time.Hour),
+			TokenVersionCacheTTL:  getEnvDuration("AUTH_TOKEN_VERSION_CACHE_TTL", 5*time.Second),
+			PublicReads:           getEnvBool("AUTH_PUBLIC_READS", false),
+		},
+		Mailer: MailerConfig{
+			SMTPHost:     getEnv("SMTP_HOST", ""),
+			SMTPPort:     getEnv("SMTP_PORT", "587"),
+			SMTPUsername: getEnv("SMTP_USERNAME", ""),
+			SMTPPassword: getEnv("SMTP_PASSWORD", ""),
+			From:         getEnv("SMTP_FROM", "Cinematique <no-reply@cinematique.local>"),
 		},
+		Tracing: TracingConfig{
+			Enabled:     getEnvBool("TRACING_ENABLED", false),
+			ServiceName: getEnv("OTEL_SERVICE_NAME", "cinematique"),
+			Endpoint:    getEnv("OTEL_EXPORTER_OTLP_ENDPOINT", ""),
+			SampleRatio: getEnvFloat("TRACING_SAMPLE_RATIO", 1),
+		},
+		Metrics: MetricsConfig{
+			Username: getEnv("METRICS_USERNAME", ""),
+			Password: getEnv("METRICS_PASSWORD", ""),
+			Token:    getEnv("METRICS_TOKEN", ""),
+		},
+		Admin: AdminConfig{
+			OrphanPurgeThreshold: getEnvInt("ADMIN_ORPHAN_PURGE_THRESHOLD", 20),
+		},
+		Catalog: CatalogConfig{
+			MovieDeletePolicy: getEnv("MOVIE_DELETE_POLICY", "cascade"),
+			StrictEvents:      getEnvBool("STRICT_EVENTS", false),
+		},
+		Listing: ListingConfig{
+			MovieSort:              getEnv("LIST_MOVIES_SORT", "rating:desc"),
+			MoviesPageSize:         getEnvInt("LIST_MOVIES_PAGE_SIZE", 20),
+			MoviesMaxPageSize:      getEnvInt("LIST_MOVIES_MAX_PAGE_SIZE", 100),
+			MovieCursorPageSize:    getEnvInt("LIST_MOVIES_CURSOR_PAGE_SIZE", 50),
+			MovieCursorMaxPageSize: getEnvInt("LIST_MOVIES_CURSOR_MAX_PAGE_SIZE", 100),
+			PopularPageSize:        getEnvInt("LIST_POPULAR_PAGE_SIZE", 10),
+			PopularMaxPageSize:     getEnvInt("LIST_POPULAR_MAX_PAGE_SIZE", 100),
+			ActorsPageSize:         getEnvInt("LIST_ACTORS_PAGE_SIZE", 20),
+			ActorsMaxPageSize:      getEnvInt("LIST_ACTORS_MAX_PAGE_SIZE", 100),
+			CastPageSize:           getEnvInt("LIST_CAST_PAGE_SIZE", 50),
+			CastMaxPageSize:        getEnvInt("LIST_CAST_MAX_PAGE_SIZE", 200),
+			SearchPageSize:         getEnvInt("LIST_SEARCH_PAGE_SIZE", 10),
+			SearchMaxPageSize:      getEnvInt("LIST_SEARCH_MAX_PAGE_SIZE", 50),
+		},
+		Ratings: RatingsConfig{
+			InternalWeight:       getEnvFloat("RATING_WEIGHT_INTERNAL", 1),
+			IMDbWeight:           getEnvFloat("RATING_WEIGHT_IMDB", 1),
+			RottenTomatoesWeight: getEnvFloat("RATING_WEIGHT_ROTTEN_TOMATOES", 1),
+		},
+		Kafka: KafkaConfig{
+			ProducerWorkers:    getEnvInt("KAFKA_PRODUCER_WORKERS", 2),
+			ProducerBufferSize: getEnvInt("KAFKA_PRODUCER_BUFFER_SIZE", 256),
+			BackpressurePolicy: getEnv("KAFKA_BACKPRESSURE_POLICY", "drop_new"),
+			BlockTimeout:       getEnvDuration("KAFKA_BLOCK_TIMEOUT", time.Second),
+			FlushTimeout:       getEnvDuration("KAFKA_FLUSH_TIMEOUT", 5*time.Second),
+		},
+		RequestLimits: RequestLimitsConfig{
+			MaxBodyBytes: int64(getEnvInt("REQUEST_MAX_BODY_BYTES", bodylimit.DefaultMaxBytes)),
+			MaxJSONDepth: getEnvInt("REQUEST_MAX_JSON_DEPTH", bodylimit.DefaultMaxJSONDepth),
+		},
+		AccessLog: AccessLogConfig{
+			SlowThreshold: getEnvDuration("SLOW_REQUEST_THRESHOLD", accesslog.DefaultSlowThreshold),
+		},
+	}
+}
+
+// ToTracingConfig преобразует в конфигурацию пакета tracing
+func (tc *TracingConfig) ToTracingConfig() tracing.Config {
+	return tracing.Config{
+		Enabled:     tc.Enabled,
+		ServiceName: tc.ServiceName,
+		Endpoint:    tc.Endpoint,
+		SampleRatio: tc.SampleRatio,
+	}
+}
+
+// ToSMTPConfig преобразует в конфигурацию SMTP-отправителя писем
+func (mc *MailerConfig) ToSMTPConfig() mailer.SMTPConfig {
+	return mailer.SMTPConfig{
+		Host:     mc.SMTPHost,
+		Port:     mc.SMTPPort,
+		Username: mc.SMTPUsername,
+		Password: mc.SMTPPassword,
+		From:     mc.From,
 	}
 }
 
@@ -88,6 +425,13 @@ func (kc *KeycloakConfig) ToKeycloakConfig() keycloak.Config {
 		ServerURL: kc.ServerURL,
 		Realm:     kc.Realm,
 		ClientID:  kc.ClientID,
+
+		ClientSecret: kc.ClientSecret,
+
+		Timeout:    kc.Timeout,
+		MaxRetries: kc.MaxRetries,
+		RetryDelay: kc.RetryDelay,
+		CacheTTL:   kc.VerifyCacheTTL,
 	}
 }
 
@@ -118,3 +462,23 @@ func getEnvInt(key string, defaultValue int) int {
 	}
 	return defaultValue
 }
+
+// getEnvFloat получает дробную переменную окружения
+func getEnvFloat(key string, defaultValue float64) float64 {
+	if value := os.Getenv(key); value != "" {
+		if parsed, err := strconv.ParseFloat(value, 64); err == nil {
+			return parsed
+		}
+	}
+	return defaultValue
+}
+
+// getEnvDuration получает длительность из переменной окружения (например, 30s, 5m)
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if parsed, err := time.ParseDuration(value); err == nil {
+			return parsed
+		}
+	}
+	return defaultValue
+}
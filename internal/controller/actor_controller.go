@@ -4,27 +4,31 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 
 	"cinematique/internal/controller/dto"
+	"cinematique/internal/controller/mapper"
 	"cinematique/internal/domain"
+	"cinematique/internal/storage"
 )
 
 // actorController контроллер актёров.
 type actorController struct {
 	actorService ServiceActor
+	lists        ListDefaults
 }
 
 // PartialUpdateActor частично обновляет данные актёра
 func (c *actorController) PartialUpdateActor(ctx *gin.Context, id int, update dto.ActorUpdate) (dto.ActorResponse, error) {
 	// Логируем входные данные
 	log.Printf("PartialUpdateActor вызван с id=%d, update=%+v", id, update)
-	
+
 	// Получаем текущие данные актёра
-	actor, err := c.actorService.GetByID(id)
+	actor, err := c.actorService.GetByID(requestContext(ctx), id)
 	if err != nil {
 		log.Printf("Ошибка получения актёра с id=%d: %v", id, err)
 		if errors.Is(err, domain.ErrActorNotFound) {
@@ -35,8 +39,8 @@ func (c *actorController) PartialUpdateActor(ctx *gin.Context, id int, update dt
 	log.Printf("Текущие данные актёра: %+v", actor)
 
 	// Логируем обновляемые поля
-	log.Printf("Обновляем актёра с полями: Name=%v, Gender=%v, BirthDate=%v", 
-		update.Name, update.Gender, update.BirthDate)
+	log.Printf("Обновляем актёра с полями: Name=%v, Gender=%v, BirthDate=%v, DeathDate=%v",
+		update.Name, update.Gender, update.BirthDate, update.DeathDate)
 
 	// Создаем обновленную структуру актёра
 	updatedActor := domain.Actor{
@@ -44,8 +48,9 @@ func (c *actorController) PartialUpdateActor(ctx *gin.Context, id int, update dt
 		Name:      actor.Name,
 		Gender:    actor.Gender,
 		BirthDate: actor.BirthDate,
+		DeathDate: actor.DeathDate,
 	}
-	
+
 	// Обновляем только переданные поля
 	if update.Name != nil {
 		updatedActor.Name = *update.Name
@@ -56,77 +61,128 @@ func (c *actorController) PartialUpdateActor(ctx *gin.Context, id int, update dt
 	if update.BirthDate != nil {
 		updatedActor.BirthDate = *update.BirthDate
 	}
+	if update.DeathDate != nil {
+		updatedActor.DeathDate = update.DeathDate
+	}
 
 	// Валидируем обновленные данные
-	if err := validateActorInput(updatedActor.Name, updatedActor.Gender, updatedActor.BirthDate.Format("2006-01-02")); err != nil {
+	if err := validateActorInput(updatedActor.Name, updatedActor.Gender, updatedActor.BirthDate.Format(mapper.DateLayout),
+		mapper.FormatOptionalDate(updatedActor.DeathDate)); err != nil {
 		log.Printf("Ошибка валидации для актёра (ID: %d): %v", id, err)
-		return dto.ActorResponse{}, fmt.Errorf("ошибка валидации: %w", err)
+		return dto.ActorResponse{}, fmt.Errorf("validation error: %w", err)
 	}
 
 	// Обновляем актёра в хранилище
-	if err := c.actorService.Update(updatedActor); err != nil {
+	if err := c.actorService.Update(requestContext(ctx), updatedActor); err != nil {
 		log.Printf("Ошибка обновления актёра (ID: %d): %v", id, err)
 		return dto.ActorResponse{}, fmt.Errorf("обновление актёра: %w", err)
 	}
 
 	// Получаем обновленные данные актёра
-	updated, err := c.actorService.GetByID(id)
+	updated, err := c.actorService.GetByID(requestContext(ctx), id)
 	if err != nil {
 		log.Printf("Ошибка получения обновлённых данных актёра (ID: %d): %v", id, err)
 		return dto.ActorResponse{}, fmt.Errorf("получение обновлённых данных актёра: %w", err)
 	}
 
 	// Преобразуем в DTO и возвращаем
-	return dto.ActorResponse{
-		ID:        updated.ID,
-		Name:      updated.Name,
-		Gender:    updated.Gender,
-		BirthDate: updated.BirthDate.Format("2006-01-02"),
-	}, nil
+	return c.toActorResponse(updated), nil
 }
 
 // NewActorController создаёт новый контроллер актёров.
 func NewActorController(actorService ServiceActor) *actorController {
 	return &actorController{
 		actorService: actorService,
+		lists:        DefaultListDefaults(),
 	}
 }
 
-// validateActorInput проверяет корректность входных данных актёра.
-func validateActorInput(name, gender, birthDate string) error {
+// WithListDefaults задаёт размеры страниц списков актёров
+func (c *actorController) WithListDefaults(lists ListDefaults) *actorController {
+	c.lists = lists.normalize(DefaultListDefaults())
+	return c
+}
+
+// validateActorInput проверяет корректность входных данных актёра и возвращает ошибки по всем полям.
+// Пустая deathDate означает, что актёр жив; иначе дата смерти должна быть позже даты рождения
+func validateActorInput(name, gender, birthDate, deathDate string) error {
+	var errs dto.ValidationErrors
+
 	name = strings.TrimSpace(name)
 	if len(name) == 0 || len(name) > 100 {
-		return fmt.Errorf("имя: должно быть от 1 до 100 символов")
+		errs.Add(dto.KeyActorNameLength)
 	}
 
 	gender = strings.ToLower(strings.TrimSpace(gender))
 	if gender != "male" && gender != "female" && gender != "other" {
-		return fmt.Errorf("пол: должно быть 'male', 'female' или 'other'")
+		errs.Add(dto.KeyActorGenderInvalid)
+	}
+
+	birth, err := mapper.ParseDate(birthDate)
+	switch {
+	case err != nil:
+		errs.Add(dto.KeyActorBirthDateInvalid)
+	case birth.After(time.Now()):
+		errs.Add(dto.KeyActorBirthDateInFuture)
+	case birth.Before(time.Date(1900, 1, 1, 0, 0, 0, 0, time.UTC)):
+		errs.Add(dto.KeyActorBirthDateTooEarly)
+	}
+
+	if deathDate != "" {
+		death, deathErr := mapper.ParseDate(deathDate)
+		switch {
+		case deathErr != nil:
+			errs.Add(dto.KeyActorDeathDateInvalid)
+		case death.After(time.Now()):
+			errs.Add(dto.KeyActorDeathDateInFuture)
+		case err == nil && !death.After(birth):
+			errs.Add(dto.KeyActorDeathBeforeBirth)
+		}
 	}
 
-	birth, err := time.Parse("2006-01-02", birthDate)
+	return errs.Err()
+}
+
+// parseDeathDate разбирает дату смерти, уже проверенную validateActorInput; пустая строка — nil
+func parseDeathDate(value string) *time.Time {
+	if value == "" {
+		return nil
+	}
+	date, err := mapper.ParseDate(value)
 	if err != nil {
-		return fmt.Errorf("дата рождения: должна быть в формате YYYY-MM-DD")
+		return nil
 	}
+	return &date
+}
 
-	if birth.After(time.Now()) {
-		return fmt.Errorf("дата рождения: не может быть в будущем")
+// parseActorFilter читает фильтры списка актёров: ?living=true оставляет только живых
+func parseActorFilter(ctx *gin.Context) (domain.ActorFilter, error) {
+	var filter domain.ActorFilter
+	if raw := ctx.Query("living"); raw != "" {
+		living, err := strconv.ParseBool(raw)
+		if err != nil {
+			return domain.ActorFilter{}, fmt.Errorf("validation error: %w", dto.ValidationErrors{dto.NewFieldError(dto.KeyActorLivingInvalid)})
+		}
+		filter.Living = living
 	}
+	return filter, nil
+}
 
-	minDate := time.Date(1900, 1, 1, 0, 0, 0, 0, time.UTC)
-	if birth.Before(minDate) {
-		return fmt.Errorf("дата рождения: не может быть раньше 1900-01-01")
+// toActorResponse конвертирует Actor в DTO
+func (c *actorController) toActorResponse(actor domain.Actor) dto.ActorResponse {
+	resp := mapper.Actor(actor)
+	if actor.PhotoKey != "" {
+		resp.PhotoURL = c.actorService.PhotoURL(actor.PhotoKey)
 	}
-
-	return nil
+	return resp
 }
 
 // CreateActor создаёт нового актёра.
 func (c *actorController) CreateActor(ctx *gin.Context, req dto.CreateActorRequest) (dto.ActorResponse, error) {
-	if err := validateActorInput(req.Name, req.Gender, req.BirthDate); err != nil {
+	if err := validateActorInput(req.Name, req.Gender, req.BirthDate, req.DeathDate); err != nil {
 		return dto.ActorResponse{}, err
 	}
-	birthDate, err := time.Parse("2006-01-02", req.BirthDate)
+	birthDate, err := mapper.ParseDate(req.BirthDate)
 	if err != nil {
 		return dto.ActorResponse{}, err
 	}
@@ -134,39 +190,67 @@ func (c *actorController) CreateActor(ctx *gin.Context, req dto.CreateActorReque
 		Name:      req.Name,
 		Gender:    req.Gender,
 		BirthDate: birthDate,
+		DeathDate: parseDeathDate(req.DeathDate),
 	}
-	id, err := c.actorService.Create(actor)
+	id, err := c.actorService.Create(requestContext(ctx), actor)
 	if err != nil {
 		return dto.ActorResponse{}, err
 	}
-	return dto.ActorResponse{
-		ID:        id,
-		Name:      actor.Name,
-		Gender:    actor.Gender,
-		BirthDate: req.BirthDate,
-	}, nil
+	actor.ID = id
+	return mapper.Actor(actor), nil
 }
 
 // GetActorByID возвращает актёра по ID.
 func (c *actorController) GetActorByID(ctx *gin.Context, id int) (dto.ActorResponse, error) {
-	actor, err := c.actorService.GetByID(id)
+	actor, err := c.actorService.GetByID(requestContext(ctx), id)
 	if err != nil {
 		if errors.Is(err, domain.ErrActorNotFound) {
 			return dto.ActorResponse{}, domain.ErrActorNotFound
 		}
 		return dto.ActorResponse{}, fmt.Errorf("получение актёра: %w", err)
 	}
-	return dto.ActorResponse{
-		ID:        actor.ID,
-		Name:      actor.Name,
-		Gender:    actor.Gender,
-		BirthDate: actor.BirthDate.Format("2006-01-02"),
-	}, nil
+	return c.toActorResponse(actor), nil
+}
+
+// GetActorStats возвращает сводку по фильмам актёра
+func (c *actorController) GetActorStats(ctx *gin.Context, id int) (dto.ActorStatsResponse, error) {
+	stats, err := c.actorService.GetStats(requestContext(ctx), id)
+	if err != nil {
+		if errors.Is(err, domain.ErrActorNotFound) {
+			return dto.ActorStatsResponse{}, domain.ErrActorNotFound
+		}
+		return dto.ActorStatsResponse{}, fmt.Errorf("получение статистики актёра: %w", err)
+	}
+	return mapper.ActorStats(stats), nil
+}
+
+// GetActorHistory возвращает версии профиля актёра с отличиями между ними
+func (c *actorController) GetActorHistory(ctx *gin.Context, id int) (dto.ActorHistoryResponse, error) {
+	versions, err := c.actorService.GetHistory(requestContext(ctx), id)
+	if err != nil {
+		if errors.Is(err, domain.ErrActorNotFound) {
+			return dto.ActorHistoryResponse{}, domain.ErrActorNotFound
+		}
+		return dto.ActorHistoryResponse{}, fmt.Errorf("получение истории актёра: %w", err)
+	}
+	return mapper.ActorHistory(id, versions), nil
+}
+
+// GetActorBySlug возвращает актёра по slug.
+func (c *actorController) GetActorBySlug(ctx *gin.Context, slug string) (dto.ActorResponse, error) {
+	actor, err := c.actorService.GetBySlug(requestContext(ctx), slug)
+	if err != nil {
+		if errors.Is(err, domain.ErrActorNotFound) {
+			return dto.ActorResponse{}, domain.ErrActorNotFound
+		}
+		return dto.ActorResponse{}, fmt.Errorf("получение актёра по slug: %w", err)
+	}
+	return c.toActorResponse(actor), nil
 }
 
 // UpdateActor обновляет данные актёра.
 func (c *actorController) UpdateActor(ctx *gin.Context, id int, req dto.UpdateActorRequest) (dto.ActorResponse, error) {
-	actor, err := c.actorService.GetByID(id)
+	actor, err := c.actorService.GetByID(requestContext(ctx), id)
 	if err != nil {
 		if errors.Is(err, domain.ErrActorNotFound) {
 			return dto.ActorResponse{}, domain.ErrActorNotFound
@@ -178,6 +262,7 @@ func (c *actorController) UpdateActor(ctx *gin.Context, id int, req dto.UpdateAc
 	updatedName := actor.Name
 	updatedGender := actor.Gender
 	updatedBirthDate := actor.BirthDate
+	updatedDeathDate := mapper.FormatOptionalDate(actor.DeathDate)
 
 	// Обновляем только переданные поля
 	if req.Name != nil {
@@ -187,36 +272,36 @@ func (c *actorController) UpdateActor(ctx *gin.Context, id int, req dto.UpdateAc
 		updatedGender = *req.Gender
 	}
 	if req.BirthDate != nil {
-		birthDate, err := time.Parse("2006-01-02", *req.BirthDate)
+		birthDate, err := mapper.ParseDate(*req.BirthDate)
 		if err != nil {
 			return dto.ActorResponse{}, fmt.Errorf("неверный формат даты рождения: %w", err)
 		}
 		updatedBirthDate = birthDate
 	}
+	if req.DeathDate != nil {
+		updatedDeathDate = *req.DeathDate
+	}
 
 	// Валидируем все поля разом
 	if err := validateActorInput(
 		updatedName,
 		updatedGender,
-		updatedBirthDate.Format("2006-01-02"),
+		updatedBirthDate.Format(mapper.DateLayout),
+		updatedDeathDate,
 	); err != nil {
-		return dto.ActorResponse{}, fmt.Errorf("ошибка валидации: %w", err)
+		return dto.ActorResponse{}, fmt.Errorf("validation error: %w", err)
 	}
 
 	// Применяем обновления
 	actor.Name = updatedName
 	actor.Gender = updatedGender
 	actor.BirthDate = updatedBirthDate
-	err = c.actorService.Update(actor)
+	actor.DeathDate = parseDeathDate(updatedDeathDate)
+	err = c.actorService.Update(requestContext(ctx), actor)
 	if err != nil {
 		return dto.ActorResponse{}, err
 	}
-	return dto.ActorResponse{
-		ID:        actor.ID,
-		Name:      actor.Name,
-		Gender:    actor.Gender,
-		BirthDate: actor.BirthDate.Format("2006-01-02"),
-	}, nil
+	return c.toActorResponse(actor), nil
 }
 
 // DeleteActor удаляет актёра
@@ -224,7 +309,7 @@ func (c *actorController) DeleteActor(ctx *gin.Context, id int) error {
 	log.Printf("Попытка удаления актёра с ID: %d", id)
 
 	// Проверяем существование актёра
-	_, err := c.actorService.GetByID(id)
+	_, err := c.actorService.GetByID(requestContext(ctx), id)
 	if err != nil {
 		if errors.Is(err, domain.ErrActorNotFound) {
 			return domain.ErrActorNotFound
@@ -233,7 +318,7 @@ func (c *actorController) DeleteActor(ctx *gin.Context, id int) error {
 	}
 
 	// Проверяем, есть ли у актёра связанные фильмы
-	movies, err := c.actorService.GetMovies(id)
+	movies, err := c.actorService.GetMovies(requestContext(ctx), id)
 	if err != nil {
 		return fmt.Errorf("получение фильмов актёра для удаления: %w", err)
 	}
@@ -242,7 +327,7 @@ func (c *actorController) DeleteActor(ctx *gin.Context, id int) error {
 		return domain.ErrActorHasMovies
 	}
 
-	err = c.actorService.Delete(id)
+	err = c.actorService.Delete(requestContext(ctx), id)
 	if err != nil {
 		return fmt.Errorf("ошибка удаления актёра (ID: %d): %w", id, err)
 	}
@@ -251,77 +336,275 @@ func (c *actorController) DeleteActor(ctx *gin.Context, id int) error {
 	return nil
 }
 
-// ListActors возвращает всех актёров.
+// ExportActors передаёт fn всех актёров в формате ответа API
+func (c *actorController) ExportActors(ctx *gin.Context, fn func(dto.ActorResponse) error) error {
+	return c.actorService.ExportActors(requestContext(ctx), func(actor domain.Actor) error {
+		return fn(c.toActorResponse(actor))
+	})
+}
+
+// ListActors возвращает страницу актёров по имени (?limit=, ?offset=); ?living=true оставляет
+// только живых. Без limit возвращается первая страница размера из настроек
 func (c *actorController) ListActors(ctx *gin.Context) (dto.ActorsListResponse, error) {
-	actors, err := c.actorService.GetAll()
+	limit, err := c.lists.Actors.limit(ctx)
+	if err != nil {
+		return dto.ActorsListResponse{}, err
+	}
+	offset, err := parseNonNegativeQuery(ctx, "offset", dto.KeyListOffsetInvalid)
+	if err != nil {
+		return dto.ActorsListResponse{}, fmt.Errorf("validation error: %w", err)
+	}
+	filter, err := parseActorFilter(ctx)
+	if err != nil {
+		return dto.ActorsListResponse{}, err
+	}
+
+	// Пустой фрагмент имени подходит всем актёрам
+	actors, err := c.actorService.SearchActorsByName(requestContext(ctx), "", filter, limit, offset)
+	if err != nil {
+		return dto.ActorsListResponse{}, err
+	}
+	total, err := pageTotal(limit, offset, len(actors), func() (int, error) {
+		return c.actorService.CountActors(requestContext(ctx), "", filter)
+	})
 	if err != nil {
 		return dto.ActorsListResponse{}, err
 	}
 
 	response := dto.ActorsListResponse{
-		Actors: make([]dto.ActorResponse, 0, len(actors)),
+		Actors:     make([]dto.ActorResponse, 0, len(actors)),
+		Pagination: newPagination(limit, offset, total),
 	}
 
 	for _, actor := range actors {
-		response.Actors = append(response.Actors, dto.ActorResponse{
-			ID:        actor.ID,
-			Name:      actor.Name,
-			Gender:    actor.Gender,
-			BirthDate: actor.BirthDate.Format("2006-01-02"),
-		})
+		response.Actors = append(response.Actors, c.toActorResponse(actor))
 	}
 
 	return response, nil
 }
 
-// GetAllActorsWithMovies возвращает актёров с фильмами.
+// SearchActorsByName ищет актёров по фрагменту имени (?name=), ?living=true оставляет только
+// живых. Пагинация задаётся параметрами limit и offset; без limit возвращается первая
+// страница размера из настроек
+func (c *actorController) SearchActorsByName(ctx *gin.Context) (dto.ActorsListResponse, error) {
+	name := strings.TrimSpace(ctx.Query("name"))
+	if name == "" {
+		return dto.ActorsListResponse{}, fmt.Errorf("validation error: %w", dto.ValidationErrors{dto.NewFieldError(dto.KeyActorSearchNameRequired)})
+	}
+	if searchQueryTooShort(name) {
+		return dto.ActorsListResponse{}, fmt.Errorf("validation error: %w", dto.ValidationErrors{dto.NewFieldError(dto.KeyActorSearchNameShort)})
+	}
+	limit, err := c.lists.Actors.limit(ctx)
+	if err != nil {
+		return dto.ActorsListResponse{}, err
+	}
+	offset, err := parseNonNegativeQuery(ctx, "offset", dto.KeyListOffsetInvalid)
+	if err != nil {
+		return dto.ActorsListResponse{}, fmt.Errorf("validation error: %w", err)
+	}
+	filter, err := parseActorFilter(ctx)
+	if err != nil {
+		return dto.ActorsListResponse{}, err
+	}
+
+	actors, err := c.actorService.SearchActorsByName(requestContext(ctx), name, filter, limit, offset)
+	if err != nil {
+		return dto.ActorsListResponse{}, err
+	}
+	total, err := pageTotal(limit, offset, len(actors), func() (int, error) {
+		return c.actorService.CountActors(requestContext(ctx), name, filter)
+	})
+	if err != nil {
+		return dto.ActorsListResponse{}, err
+	}
+	response := dto.ActorsListResponse{
+		Actors:     make([]dto.ActorResponse, 0, len(actors)),
+		Pagination: newPagination(limit, offset, total),
+	}
+	for _, actor := range actors {
+		response.Actors = append(response.Actors, c.toActorResponse(actor))
+	}
+	return response, nil
+}
+
+// Размеры списка подсказок актёров: по умолчанию и максимальный
+const (
+	defaultActorSuggestLimit = 10
+	maxActorSuggestLimit     = 50
+)
+
+// SuggestActors возвращает подсказки для автодополнения имени актёра (?q=, ?limit=)
+func (c *actorController) SuggestActors(ctx *gin.Context) (dto.ActorSuggestionsResponse, error) {
+	query := strings.TrimSpace(ctx.Query("q"))
+	if query == "" {
+		return dto.ActorSuggestionsResponse{}, fmt.Errorf("validation error: %w", dto.ValidationErrors{dto.NewFieldError(dto.KeyActorSuggestQRequired)})
+	}
+	limit := defaultActorSuggestLimit
+	if raw := ctx.Query("limit"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed < 1 || parsed > maxActorSuggestLimit {
+			return dto.ActorSuggestionsResponse{}, fmt.Errorf("validation error: %w", dto.ValidationErrors{dto.NewFieldError(dto.KeyActorSuggestLimit)})
+		}
+		limit = parsed
+	}
+
+	actors, err := c.actorService.SuggestActors(requestContext(ctx), query, limit)
+	if err != nil {
+		return dto.ActorSuggestionsResponse{}, err
+	}
+	response := dto.ActorSuggestionsResponse{Actors: make([]dto.ActorSuggestion, 0, len(actors))}
+	for _, actor := range actors {
+		response.Actors = append(response.Actors, dto.ActorSuggestion{ID: actor.ID, Name: actor.Name})
+	}
+	return response, nil
+}
+
+// ListActorsByBirthMonth возвращает актёров, родившихся в месяце ?month= (1–12, допускается
+// ведущий ноль). Без параметра используется текущий месяц
+func (c *actorController) ListActorsByBirthMonth(ctx *gin.Context) (dto.ActorsListResponse, error) {
+	month := int(time.Now().Month())
+	if raw := ctx.Query("month"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed < 1 || parsed > 12 {
+			return dto.ActorsListResponse{}, fmt.Errorf("validation error: %w", dto.ValidationErrors{dto.NewFieldError(dto.KeyActorBirthMonthInvalid)})
+		}
+		month = parsed
+	}
+
+	actors, err := c.actorService.GetActorsBornInMonth(requestContext(ctx), month)
+	if err != nil {
+		return dto.ActorsListResponse{}, err
+	}
+	response := dto.ActorsListResponse{Actors: make([]dto.ActorResponse, 0, len(actors))}
+	for _, actor := range actors {
+		response.Actors = append(response.Actors, c.toActorResponse(actor))
+	}
+	return response, nil
+}
+
+// maxMoviesPerActor — наибольшее значение ?movies_per_actor= в списке актёров с фильмами
+const maxMoviesPerActor = 50
+
+// GetAllActorsWithMovies возвращает страницу актёров с фильмами. Страница считается по актёрам
+// (?limit=, ?offset=), ?name= фильтрует по фрагменту имени, ?movies_per_actor= оставляет
+// у каждого актёра только столько последних фильмов
 func (c *actorController) GetAllActorsWithMovies(ctx *gin.Context) (dto.ActorsWithFilmsListResponse, error) {
-	actors, err := c.actorService.GetAllActorsWithMovies()
+	limit, err := c.lists.Actors.limit(ctx)
+	if err != nil {
+		return dto.ActorsWithFilmsListResponse{}, err
+	}
+	offset, err := parseNonNegativeQuery(ctx, "offset", dto.KeyListOffsetInvalid)
+	if err != nil {
+		return dto.ActorsWithFilmsListResponse{}, fmt.Errorf("validation error: %w", err)
+	}
+	query := domain.ActorsWithMoviesQuery{Name: strings.TrimSpace(ctx.Query("name")), Limit: limit, Offset: offset}
+	if raw := ctx.Query("movies_per_actor"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed < 1 || parsed > maxMoviesPerActor {
+			return dto.ActorsWithFilmsListResponse{}, fmt.Errorf("validation error: %w", dto.ValidationErrors{dto.NewFieldError(dto.KeyActorMoviesPerActor)})
+		}
+		query.MoviesPerActor = parsed
+	}
+
+	actors, err := c.actorService.GetAllActorsWithMovies(requestContext(ctx), query)
 	if err != nil {
 		return dto.ActorsWithFilmsListResponse{}, fmt.Errorf("получение актёров с фильмами: %w", err)
 	}
 
-	// Преобразуем доменные структуры в DTO
+	total, err := pageTotal(limit, offset, len(actors), func() (int, error) {
+		return c.actorService.CountActors(requestContext(ctx), query.Name, domain.ActorFilter{})
+	})
+	if err != nil {
+		return dto.ActorsWithFilmsListResponse{}, fmt.Errorf("подсчёт актёров: %w", err)
+	}
+
 	result := make([]dto.ActorWithFilms, 0, len(actors))
 	for _, actor := range actors {
-		// Преобразуем фильмы актёра
-		movies := make([]dto.MovieResponse, 0, len(actor.Movies))
-		for _, movie := range actor.Movies {
-			// Преобразуем актёров фильма (если есть)
-			var actorsList []dto.ActorPreview
-			if len(movie.Actors) > 0 {
-				actorsList = make([]dto.ActorPreview, 0, len(movie.Actors))
-				for _, actor := range movie.Actors {
-					actorsList = append(actorsList, dto.ActorPreview{
-						ID:   actor.ID,
-						Name: actor.Name,
-					})
-				}
-			} else {
-				actorsList = nil
-			}
-
-			movies = append(movies, dto.MovieResponse{
-				ID:          movie.ID,
-				Title:       movie.Title,
-				Description: movie.Description,
-				ReleaseYear: movie.ReleaseYear,
-				Rating:      movie.Rating,
-				Actors:      actorsList,
-			})
-		}
+		result = append(result, mapper.ActorWithFilms(actor))
+	}
+
+	return dto.ActorsWithFilmsListResponse{Actors: result, Pagination: newPagination(limit, offset, total)}, nil
+}
 
-		// Создаём DTO актёра с фильмами
-		actorDTO := dto.ActorWithFilms{
-			ID:        actor.ID,
-			Name:      actor.Name,
-			Gender:    actor.Gender,
-			BirthDate: actor.BirthDate.Format("2006-01-02"),
-			Movies:    movies,
+// MergeActors объединяет актёра-дубликата с основным актёром.
+func (c *actorController) MergeActors(ctx *gin.Context, keepID, dupID int) (dto.ActorMergeResponse, error) {
+	result, err := c.actorService.MergeActors(requestContext(ctx), keepID, dupID)
+	if err != nil {
+		if errors.Is(err, domain.ErrActorNotFound) || errors.Is(err, domain.ErrMergeSameActor) {
+			return dto.ActorMergeResponse{}, err
 		}
+		return dto.ActorMergeResponse{}, fmt.Errorf("слияние актёров: %w", err)
+	}
+
+	return dto.ActorMergeResponse{
+		Actor:            c.toActorResponse(result.Actor),
+		DuplicateID:      result.DuplicateID,
+		MoviesReassigned: result.MoviesReassigned,
+	}, nil
+}
+
+// ListOrphanActors возвращает актёров без фильмов (?limit=, ?offset=), их общее число
+// и порог, выше которого удаление нужно подтверждать
+func (c *actorController) ListOrphanActors(ctx *gin.Context) (dto.OrphanActorsResponse, error) {
+	limit, err := c.lists.Actors.limit(ctx)
+	if err != nil {
+		return dto.OrphanActorsResponse{}, err
+	}
+	offset, err := parseNonNegativeQuery(ctx, "offset", dto.KeyListOffsetInvalid)
+	if err != nil {
+		return dto.OrphanActorsResponse{}, fmt.Errorf("validation error: %w", err)
+	}
+
+	actors, total, err := c.actorService.ListOrphans(requestContext(ctx), limit, offset)
+	if err != nil {
+		return dto.OrphanActorsResponse{}, err
+	}
+	response := dto.OrphanActorsResponse{
+		Actors:     make([]dto.ActorResponse, 0, len(actors)),
+		Total:      total,
+		Threshold:  c.actorService.OrphanPurgeThreshold(),
+		Pagination: newPagination(limit, offset, total),
+	}
+	for _, actor := range actors {
+		response.Actors = append(response.Actors, c.toActorResponse(actor))
+	}
+	return response, nil
+}
 
-		result = append(result, actorDTO)
+// PurgeOrphanActors удаляет актёров без фильмов. Если их больше порога, возвращается
+// конфликт с их числом, которое нужно повторить в confirm_count
+func (c *actorController) PurgeOrphanActors(ctx *gin.Context, req dto.PurgeOrphanActorsRequest) (dto.PurgeOrphanActorsResponse, error) {
+	if req.ConfirmCount < 0 {
+		return dto.PurgeOrphanActorsResponse{}, fmt.Errorf("validation error: %w", dto.ValidationErrors{dto.NewFieldError(dto.KeyActorOrphanConfirm)})
 	}
+	purged, err := c.actorService.PurgeOrphans(requestContext(ctx), req.ConfirmCount)
+	if err != nil {
+		return dto.PurgeOrphanActorsResponse{}, err
+	}
+	response := dto.PurgeOrphanActorsResponse{Deleted: len(purged), IDs: make([]int, 0, len(purged))}
+	for _, actor := range purged {
+		response.IDs = append(response.IDs, actor.ID)
+	}
+	return response, nil
+}
 
-	return dto.ActorsWithFilmsListResponse{Actors: result}, nil
+// UploadActorPhoto проверяет и сохраняет фотографию актёра. Формат определяется
+// по содержимому файла; поддерживаются JPEG, PNG и WebP размером до storage.MaxImageSize
+func (c *actorController) UploadActorPhoto(ctx *gin.Context, id int, data []byte) (dto.ActorResponse, error) {
+	if len(data) > storage.MaxImageSize {
+		return dto.ActorResponse{}, fmt.Errorf("validation error: %w", dto.ValidationErrors{dto.NewFieldError(dto.KeyActorPhotoTooLarge)})
+	}
+	contentType, ext, err := storage.DetectImage(data)
+	if err != nil {
+		return dto.ActorResponse{}, fmt.Errorf("validation error: %w", dto.ValidationErrors{dto.NewFieldError(dto.KeyActorPhotoUnsupported)})
+	}
+
+	actor, err := c.actorService.SetPhoto(requestContext(ctx), id, data, contentType, ext)
+	if err != nil {
+		if errors.Is(err, domain.ErrActorNotFound) {
+			return dto.ActorResponse{}, domain.ErrActorNotFound
+		}
+		return dto.ActorResponse{}, fmt.Errorf("загрузка фото актёра: %w", err)
+	}
+	return c.toActorResponse(actor), nil
 }
@@ -1,7 +1,9 @@
 package controller
 
 import (
+	"context"
 	"errors"
+	"net/http/httptest"
 	"testing"
 	"time"
 
@@ -11,6 +13,8 @@ import (
 
 	"cinematique/internal/domain"
 	"cinematique/internal/controller/dto"
+	"cinematique/internal/controller/mapper"
+	"cinematique/internal/storage"
 )
 
 // MockActorService - мок сервиса актеров
@@ -18,41 +22,131 @@ type MockActorService struct {
 	mock.Mock
 }
 
-func (m *MockActorService) Create(actor domain.Actor) (int, error) {
+func (m *MockActorService) Create(_ context.Context, actor domain.Actor) (int, error) {
 	args := m.Called(actor)
 	return args.Int(0), args.Error(1)
 }
 
-func (m *MockActorService) GetByID(id int) (domain.Actor, error) {
+func (m *MockActorService) GetByID(_ context.Context, id int) (domain.Actor, error) {
 	args := m.Called(id)
 	return args.Get(0).(domain.Actor), args.Error(1)
 }
 
-func (m *MockActorService) Update(actor domain.Actor) error {
+func (m *MockActorService) GetBySlug(_ context.Context, slug string) (domain.Actor, error) {
+	args := m.Called(slug)
+	return args.Get(0).(domain.Actor), args.Error(1)
+}
+
+func (m *MockActorService) Update(_ context.Context, actor domain.Actor) error {
 	args := m.Called(actor)
 	return args.Error(0)
 }
 
-func (m *MockActorService) Delete(id int) error {
+func (m *MockActorService) Delete(_ context.Context, id int) error {
 	args := m.Called(id)
 	return args.Error(0)
 }
 
-func (m *MockActorService) GetAll() ([]domain.Actor, error) {
+// ExportActors передаёт fn актёров из первого аргумента Return и возвращает ошибку из второго
+func (m *MockActorService) ExportActors(_ context.Context, fn func(domain.Actor) error) error {
 	args := m.Called()
+	for _, actor := range args.Get(0).([]domain.Actor) {
+		if err := fn(actor); err != nil {
+			return err
+		}
+	}
+	return args.Error(1)
+}
+
+func (m *MockActorService) GetAll(_ context.Context, filter domain.ActorFilter) ([]domain.Actor, error) {
+	args := m.Called(filter)
 	return args.Get(0).([]domain.Actor), args.Error(1)
 }
 
-func (m *MockActorService) GetAllActorsWithMovies() ([]domain.Actor, error) {
-	args := m.Called()
+func (m *MockActorService) SearchActorsByName(_ context.Context, nameFragment string, filter domain.ActorFilter, limit, offset int) ([]domain.Actor, error) {
+	args := m.Called(nameFragment, filter, limit, offset)
+	return args.Get(0).([]domain.Actor), args.Error(1)
+}
+
+func (m *MockActorService) CountActors(_ context.Context, nameFragment string, filter domain.ActorFilter) (int, error) {
+	args := m.Called(nameFragment, filter)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockActorService) SuggestActors(_ context.Context, prefix string, limit int) ([]domain.Actor, error) {
+	args := m.Called(prefix, limit)
+	return args.Get(0).([]domain.Actor), args.Error(1)
+}
+
+func (m *MockActorService) GetActorsBornInMonth(_ context.Context, month int) ([]domain.Actor, error) {
+	args := m.Called(month)
+	return args.Get(0).([]domain.Actor), args.Error(1)
+}
+
+func (m *MockActorService) GetAllActorsWithMovies(_ context.Context, query domain.ActorsWithMoviesQuery) ([]domain.Actor, error) {
+	args := m.Called(query)
 	return args.Get(0).([]domain.Actor), args.Error(1)
 }
 
-func (m *MockActorService) GetMovies(actorID int) ([]domain.Movie, error) {
+func (m *MockActorService) GetMovies(_ context.Context, actorID int) ([]domain.Movie, error) {
 	args := m.Called(actorID)
 	return args.Get(0).([]domain.Movie), args.Error(1)
 }
 
+func (m *MockActorService) GetStats(_ context.Context, actorID int) (domain.ActorStats, error) {
+	args := m.Called(actorID)
+	return args.Get(0).(domain.ActorStats), args.Error(1)
+}
+
+func (m *MockActorService) GetHistory(_ context.Context, actorID int) ([]domain.ActorVersion, error) {
+	args := m.Called(actorID)
+	return args.Get(0).([]domain.ActorVersion), args.Error(1)
+}
+
+func (m *MockActorService) MergeActors(_ context.Context, keepID, dupID int) (domain.ActorMergeResult, error) {
+	args := m.Called(keepID, dupID)
+	return args.Get(0).(domain.ActorMergeResult), args.Error(1)
+}
+
+func (m *MockActorService) ListOrphans(_ context.Context, limit, offset int) ([]domain.Actor, int, error) {
+	args := m.Called(limit, offset)
+	return args.Get(0).([]domain.Actor), args.Int(1), args.Error(2)
+}
+
+func (m *MockActorService) PurgeOrphans(_ context.Context, confirmCount int) ([]domain.Actor, error) {
+	args := m.Called(confirmCount)
+	return args.Get(0).([]domain.Actor), args.Error(1)
+}
+
+func (m *MockActorService) OrphanPurgeThreshold() int {
+	return 20
+}
+
+func (m *MockActorService) SetPhoto(ctx context.Context, id int, data []byte, contentType, ext string) (domain.Actor, error) {
+	args := m.Called(ctx, id, data, contentType, ext)
+	return args.Get(0).(domain.Actor), args.Error(1)
+}
+
+func (m *MockActorService) PhotoURL(key string) string {
+	return "/media/" + key
+}
+
+func (m *MockActorService) MatchExistingActors(_ context.Context, actors []domain.Actor) ([]int, error) {
+	args := m.Called(actors)
+	return args.Get(0).([]int), args.Error(1)
+}
+
+func (m *MockActorService) ImportActors(_ context.Context, actors []domain.Actor) ([]int, error) {
+	args := m.Called(actors)
+	return args.Get(0).([]int), args.Error(1)
+}
+
+// ageToday возвращает возраст актёра с датой рождения date на сегодня
+func ageToday(date string) int {
+	birthDate, _ := mapper.ParseDate(date)
+	return mapper.Age(birthDate, time.Now())
+}
+
 func TestActorController_CreateActor(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -83,6 +177,43 @@ func TestActorController_CreateActor(t *testing.T) {
 			setupMock:     func(mas *MockActorService) {},
 			expectedError: true,
 		},
+		{
+			name: "with death date",
+			req: dto.CreateActorRequest{
+				Name:      "Test Actor",
+				Gender:    "male",
+				BirthDate: "1930-05-31",
+				DeathDate: "2020-03-01",
+			},
+			setupMock: func(mas *MockActorService) {
+				mas.On("Create", mock.MatchedBy(func(actor domain.Actor) bool {
+					return actor.DeathDate != nil && actor.DeathDate.Equal(time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC))
+				})).Return(1, nil)
+			},
+			expectedError: false,
+		},
+		{
+			name: "death date before birth date",
+			req: dto.CreateActorRequest{
+				Name:      "Test Actor",
+				Gender:    "male",
+				BirthDate: "1990-01-01",
+				DeathDate: "1989-12-31",
+			},
+			setupMock:     func(mas *MockActorService) {},
+			expectedError: true,
+		},
+		{
+			name: "invalid death date",
+			req: dto.CreateActorRequest{
+				Name:      "Test Actor",
+				Gender:    "male",
+				BirthDate: "1990-01-01",
+				DeathDate: "31.12.2020",
+			},
+			setupMock:     func(mas *MockActorService) {},
+			expectedError: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -353,63 +484,71 @@ func TestActorController_PartialUpdateActor(t *testing.T) {
 
 func TestActorController_ListActors(t *testing.T) {
 	tests := []struct {
-		name           string
-		setupMock      func(*MockActorService)
-		expectedResult dto.ActorsListResponse
-		expectedError  bool
+		name          string
+		query         string
+		setupMock     func(*MockActorService)
+		expected      dto.ActorsListResponse
+		expectedError string
 	}{
 		{
-			name: "success",
+			name:  "default page without limit",
+			query: "",
 			setupMock: func(mas *MockActorService) {
-				mas.On("GetAll").Return([]domain.Actor{
-					{
-						ID:        1,
-						Name:      "Actor 1",
-						Gender:    "male",
-						BirthDate: time.Date(1990, 1, 1, 0, 0, 0, 0, time.UTC),
-					},
-					{
-						ID:        2,
-						Name:      "Actor 2",
-						Gender:    "female",
-						BirthDate: time.Date(1995, 5, 5, 0, 0, 0, 0, time.UTC),
-					},
+				mas.On("SearchActorsByName", "", domain.ActorFilter{}, 20, 0).Return([]domain.Actor{
+					{ID: 1, Name: "Actor 1", Gender: "male", BirthDate: time.Date(1990, 1, 1, 0, 0, 0, 0, time.UTC)},
+					{ID: 2, Name: "Actor 2", Gender: "female", BirthDate: time.Date(1995, 5, 5, 0, 0, 0, 0, time.UTC)},
 				}, nil)
 			},
-			expectedResult: dto.ActorsListResponse{
+			expected: dto.ActorsListResponse{
 				Actors: []dto.ActorResponse{
-					{
-						ID:        1,
-						Name:      "Actor 1",
-						Gender:    "male",
-						BirthDate: "1990-01-01",
-					},
-					{
-						ID:        2,
-						Name:      "Actor 2",
-						Gender:    "female",
-						BirthDate: "1995-05-05",
-					},
+					{ID: 1, Name: "Actor 1", Gender: "male", BirthDate: "1990-01-01", Age: ageToday("1990-01-01")},
+					{ID: 2, Name: "Actor 2", Gender: "female", BirthDate: "1995-05-05", Age: ageToday("1995-05-05")},
 				},
+				Pagination: &dto.Pagination{Limit: 20, Offset: 0, Total: 2, TotalPages: 1},
 			},
-			expectedError: false,
 		},
 		{
-			name: "empty list",
+			name:  "full page counts the rest",
+			query: "limit=2&offset=2&living=true",
 			setupMock: func(mas *MockActorService) {
-				mas.On("GetAll").Return([]domain.Actor{}, nil)
+				mas.On("SearchActorsByName", "", domain.ActorFilter{Living: true}, 2, 2).Return([]domain.Actor{
+					{ID: 3, Name: "Actor 3", Gender: "male", BirthDate: time.Date(1990, 1, 1, 0, 0, 0, 0, time.UTC)},
+					{ID: 4, Name: "Actor 4", Gender: "male", BirthDate: time.Date(1990, 1, 1, 0, 0, 0, 0, time.UTC)},
+				}, nil)
+				mas.On("CountActors", "", domain.ActorFilter{Living: true}).Return(5, nil)
 			},
-			expectedResult: dto.ActorsListResponse{
-				Actors: []dto.ActorResponse{},
+			expected: dto.ActorsListResponse{
+				Actors: []dto.ActorResponse{
+					{ID: 3, Name: "Actor 3", Gender: "male", BirthDate: "1990-01-01", Age: ageToday("1990-01-01")},
+					{ID: 4, Name: "Actor 4", Gender: "male", BirthDate: "1990-01-01", Age: ageToday("1990-01-01")},
+				},
+				Pagination: &dto.Pagination{Limit: 2, Offset: 2, Total: 5, TotalPages: 3},
 			},
-			expectedError: false,
 		},
 		{
-			name: "service error",
+			name:  "empty list",
+			query: "",
 			setupMock: func(mas *MockActorService) {
-				mas.On("GetAll").Return([]domain.Actor{}, errors.New("database error"))
+				mas.On("SearchActorsByName", "", domain.ActorFilter{}, 20, 0).Return([]domain.Actor{}, nil)
 			},
-			expectedError: true,
+			expected: dto.ActorsListResponse{
+				Actors:     []dto.ActorResponse{},
+				Pagination: &dto.Pagination{Limit: 20, Offset: 0, Total: 0, TotalPages: 0},
+			},
+		},
+		{
+			name:          "limit too large",
+			query:         "limit=101",
+			setupMock:     func(mas *MockActorService) {},
+			expectedError: "validation error: limit: exceeds the maximum page size",
+		},
+		{
+			name:  "service error",
+			query: "",
+			setupMock: func(mas *MockActorService) {
+				mas.On("SearchActorsByName", "", domain.ActorFilter{}, 20, 0).Return([]domain.Actor{}, errors.New("database error"))
+			},
+			expectedError: "database error",
 		},
 	}
 
@@ -417,18 +556,256 @@ func TestActorController_ListActors(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			mockService := &MockActorService{}
 			tt.setupMock(mockService)
+			controller := NewActorController(mockService)
+
+			ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+			ctx.Request = httptest.NewRequest("GET", "/actors?"+tt.query, nil)
+
+			result, err := controller.ListActors(ctx)
+
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, result)
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestActorController_SearchActorsByName(t *testing.T) {
+	tests := []struct {
+		name          string
+		query         string
+		setupMock     func(*MockActorService)
+		expected      dto.ActorsListResponse
+		expectedError string
+	}{
+		{
+			name:  "default page",
+			query: "name=reev",
+			setupMock: func(mas *MockActorService) {
+				mas.On("SearchActorsByName", "reev", domain.ActorFilter{}, 20, 0).Return([]domain.Actor{
+					{ID: 7, Name: "Keanu Reeves", Gender: "male", BirthDate: time.Date(1964, 9, 2, 0, 0, 0, 0, time.UTC)},
+				}, nil)
+			},
+			expected: dto.ActorsListResponse{
+				Actors:     []dto.ActorResponse{{ID: 7, Name: "Keanu Reeves", Gender: "male", BirthDate: "1964-09-02", Age: ageToday("1964-09-02")}},
+				Pagination: &dto.Pagination{Limit: 20, Offset: 0, Total: 1, TotalPages: 1},
+			},
+		},
+		{
+			name:  "explicit page without matches",
+			query: "name=zzz&limit=5&offset=10",
+			setupMock: func(mas *MockActorService) {
+				mas.On("SearchActorsByName", "zzz", domain.ActorFilter{}, 5, 10).Return([]domain.Actor{}, nil)
+				mas.On("CountActors", "zzz", domain.ActorFilter{}).Return(7, nil)
+			},
+			expected: dto.ActorsListResponse{
+				Actors:     []dto.ActorResponse{},
+				Pagination: &dto.Pagination{Limit: 5, Offset: 10, Total: 7, TotalPages: 2},
+			},
+		},
+		{
+			name:  "living actors only",
+			query: "name=reev&living=true",
+			setupMock: func(mas *MockActorService) {
+				mas.On("SearchActorsByName", "reev", domain.ActorFilter{Living: true}, 20, 0).Return([]domain.Actor{}, nil)
+			},
+			expected: dto.ActorsListResponse{
+				Actors:     []dto.ActorResponse{},
+				Pagination: &dto.Pagination{Limit: 20, Offset: 0, Total: 0, TotalPages: 0},
+			},
+		},
+		{
+			name:          "invalid living",
+			query:         "name=reev&living=maybe",
+			setupMock:     func(mas *MockActorService) {},
+			expectedError: "validation error: living: must be true or false",
+		},
+		{
+			name:          "missing name",
+			query:         "name=%20",
+			setupMock:     func(mas *MockActorService) {},
+			expectedError: "validation error: name: search parameter is required",
+		},
+		{
+			name:          "name too short",
+			query:         "name=%20a%20",
+			setupMock:     func(mas *MockActorService) {},
+			expectedError: "validation error: name: must be at least 2 characters",
+		},
+		{
+			name:          "limit too large",
+			query:         "name=reev&limit=101",
+			setupMock:     func(mas *MockActorService) {},
+			expectedError: "validation error: limit: exceeds the maximum page size",
+		},
+		{
+			name:  "service error",
+			query: "name=reev",
+			setupMock: func(mas *MockActorService) {
+				mas.On("SearchActorsByName", "reev", domain.ActorFilter{}, 20, 0).Return([]domain.Actor{}, errors.New("database error"))
+			},
+			expectedError: "database error",
+		},
+	}
 
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockActorService{}
+			tt.setupMock(mockService)
 			controller := NewActorController(mockService)
 
-			result, err := controller.ListActors(&gin.Context{})
+			ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+			ctx.Request = httptest.NewRequest("GET", "/actors/search?"+tt.query, nil)
 
-			if tt.expectedError {
-				assert.Error(t, err)
+			result, err := controller.SearchActorsByName(ctx)
+
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, result)
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestActorController_SuggestActors(t *testing.T) {
+	tests := []struct {
+		name          string
+		query         string
+		setupMock     func(*MockActorService)
+		expected      dto.ActorSuggestionsResponse
+		expectedError string
+	}{
+		{
+			name:  "default limit",
+			query: "q=leo",
+			setupMock: func(mas *MockActorService) {
+				mas.On("SuggestActors", "leo", 10).Return([]domain.Actor{{ID: 3, Name: "Leonardo DiCaprio"}}, nil)
+			},
+			expected: dto.ActorSuggestionsResponse{Actors: []dto.ActorSuggestion{{ID: 3, Name: "Leonardo DiCaprio"}}},
+		},
+		{
+			name:  "explicit limit without matches",
+			query: "q=zz&limit=50",
+			setupMock: func(mas *MockActorService) {
+				mas.On("SuggestActors", "zz", 50).Return([]domain.Actor{}, nil)
+			},
+			expected: dto.ActorSuggestionsResponse{Actors: []dto.ActorSuggestion{}},
+		},
+		{
+			name:          "missing query",
+			query:         "q=%20",
+			setupMock:     func(mas *MockActorService) {},
+			expectedError: "validation error: q: search parameter is required",
+		},
+		{
+			name:          "limit out of range",
+			query:         "q=leo&limit=51",
+			setupMock:     func(mas *MockActorService) {},
+			expectedError: "validation error: limit: must be a number from 1 to 50",
+		},
+		{
+			name:          "zero limit",
+			query:         "q=leo&limit=0",
+			setupMock:     func(mas *MockActorService) {},
+			expectedError: "validation error: limit: must be a number from 1 to 50",
+		},
+		{
+			name:  "service error",
+			query: "q=leo",
+			setupMock: func(mas *MockActorService) {
+				mas.On("SuggestActors", "leo", 10).Return([]domain.Actor{}, errors.New("database error"))
+			},
+			expectedError: "database error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockActorService{}
+			tt.setupMock(mockService)
+			controller := NewActorController(mockService)
+
+			ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+			ctx.Request = httptest.NewRequest("GET", "/actors/suggest?"+tt.query, nil)
+
+			result, err := controller.SuggestActors(ctx)
+
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
 			} else {
 				assert.NoError(t, err)
-				assert.Equal(t, tt.expectedResult, result)
+				assert.Equal(t, tt.expected, result)
 			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestActorController_ListActorsByBirthMonth(t *testing.T) {
+	tests := []struct {
+		name          string
+		query         string
+		setupMock     func(*MockActorService)
+		expected      dto.ActorsListResponse
+		expectedError string
+	}{
+		{
+			name:  "month with leading zero",
+			query: "month=09",
+			setupMock: func(mas *MockActorService) {
+				mas.On("GetActorsBornInMonth", 9).Return([]domain.Actor{
+					{ID: 7, Name: "Keanu Reeves", Gender: "male", BirthDate: time.Date(1964, 9, 2, 0, 0, 0, 0, time.UTC)},
+				}, nil)
+			},
+			expected: dto.ActorsListResponse{
+				Actors: []dto.ActorResponse{{ID: 7, Name: "Keanu Reeves", Gender: "male", BirthDate: "1964-09-02", Age: ageToday("1964-09-02")}},
+			},
+		},
+		{
+			name: "current month by default",
+			setupMock: func(mas *MockActorService) {
+				mas.On("GetActorsBornInMonth", int(time.Now().Month())).Return([]domain.Actor{}, nil)
+			},
+			expected: dto.ActorsListResponse{Actors: []dto.ActorResponse{}},
+		},
+		{
+			name:          "month out of range",
+			query:         "month=13",
+			setupMock:     func(mas *MockActorService) {},
+			expectedError: "validation error: month: must be a number from 1 to 12",
+		},
+		{
+			name:          "month is not a number",
+			query:         "month=sep",
+			setupMock:     func(mas *MockActorService) {},
+			expectedError: "validation error: month: must be a number from 1 to 12",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockActorService{}
+			tt.setupMock(mockService)
+			controller := NewActorController(mockService)
+
+			ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+			ctx.Request = httptest.NewRequest("GET", "/actors/birthdays?"+tt.query, nil)
 
+			result, err := controller.ListActorsByBirthMonth(ctx)
+
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, result)
+			}
 			mockService.AssertExpectations(t)
 		})
 	}
@@ -436,15 +813,17 @@ func TestActorController_ListActors(t *testing.T) {
 
 func TestActorController_GetAllActorsWithMovies(t *testing.T) {
 	tests := []struct {
-		name           string
-		setupMock      func(*MockActorService)
-		expectedResult dto.ActorsWithFilmsListResponse
-		expectedError  bool
+		name          string
+		query         string
+		setupMock     func(*MockActorService)
+		expected      dto.ActorsWithFilmsListResponse
+		expectedError string
 	}{
 		{
-			name: "success",
+			name:  "default page",
+			query: "",
 			setupMock: func(mas *MockActorService) {
-				mas.On("GetAllActorsWithMovies").Return([]domain.Actor{
+				mas.On("GetAllActorsWithMovies", domain.ActorsWithMoviesQuery{Limit: 20}).Return([]domain.Actor{
 					{
 						ID:        1,
 						Name:      "Actor 1",
@@ -462,7 +841,7 @@ func TestActorController_GetAllActorsWithMovies(t *testing.T) {
 					},
 				}, nil)
 			},
-			expectedResult: dto.ActorsWithFilmsListResponse{
+			expected: dto.ActorsWithFilmsListResponse{
 				Actors: []dto.ActorWithFilms{
 					{
 						ID:        1,
@@ -480,15 +859,110 @@ func TestActorController_GetAllActorsWithMovies(t *testing.T) {
 						},
 					},
 				},
+				Pagination: &dto.Pagination{Limit: 20, Offset: 0, Total: 1, TotalPages: 1},
 			},
-			expectedError: false,
 		},
 		{
-			name: "service error",
+			name:  "filtered page with latest movies",
+			query: "name=%20keanu%20&limit=5&offset=10&movies_per_actor=3",
 			setupMock: func(mas *MockActorService) {
-				mas.On("GetAllActorsWithMovies").Return([]domain.Actor{}, errors.New("database error"))
+				mas.On("GetAllActorsWithMovies", domain.ActorsWithMoviesQuery{Name: "keanu", Limit: 5, Offset: 10, MoviesPerActor: 3}).Return([]domain.Actor{}, nil)
+				mas.On("CountActors", "keanu", domain.ActorFilter{}).Return(10, nil)
 			},
-			expectedError: true,
+			expected: dto.ActorsWithFilmsListResponse{
+				Actors:     []dto.ActorWithFilms{},
+				Pagination: &dto.Pagination{Limit: 5, Offset: 10, Total: 10, TotalPages: 2},
+			},
+		},
+		{
+			name:          "movies per actor out of range",
+			query:         "movies_per_actor=51",
+			setupMock:     func(mas *MockActorService) {},
+			expectedError: "validation error: movies_per_actor: must be a number from 1 to 50",
+		},
+		{
+			name:          "negative offset",
+			query:         "offset=-1",
+			setupMock:     func(mas *MockActorService) {},
+			expectedError: "validation error: offset: must be a non-negative integer",
+		},
+		{
+			name:  "service error",
+			query: "",
+			setupMock: func(mas *MockActorService) {
+				mas.On("GetAllActorsWithMovies", domain.ActorsWithMoviesQuery{Limit: 20}).Return([]domain.Actor{}, errors.New("database error"))
+			},
+			expectedError: "получение актёров с фильмами: database error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockActorService{}
+			tt.setupMock(mockService)
+			controller := NewActorController(mockService)
+
+			ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+			ctx.Request = httptest.NewRequest("GET", "/actors/with-movies?"+tt.query, nil)
+
+			result, err := controller.GetAllActorsWithMovies(ctx)
+
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, result)
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestActorController_MergeActors(t *testing.T) {
+	birthDate := time.Date(1974, 11, 11, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name          string
+		keepID        int
+		dupID         int
+		setupMock     func(*MockActorService)
+		expected      dto.ActorMergeResponse
+		expectedError error
+	}{
+		{
+			name:   "success",
+			keepID: 1,
+			dupID:  2,
+			setupMock: func(mas *MockActorService) {
+				mas.On("MergeActors", 1, 2).Return(domain.ActorMergeResult{
+					Actor:            domain.Actor{ID: 1, Name: "Leonardo DiCaprio", Gender: "male", BirthDate: birthDate},
+					DuplicateID:      2,
+					MoviesReassigned: 3,
+				}, nil)
+			},
+			expected: dto.ActorMergeResponse{
+				Actor:            dto.ActorResponse{ID: 1, Name: "Leonardo DiCaprio", Gender: "male", BirthDate: "1974-11-11", Age: ageToday("1974-11-11")},
+				DuplicateID:      2,
+				MoviesReassigned: 3,
+			},
+		},
+		{
+			name:   "actor not found",
+			keepID: 1,
+			dupID:  999,
+			setupMock: func(mas *MockActorService) {
+				mas.On("MergeActors", 1, 999).Return(domain.ActorMergeResult{}, domain.ErrActorNotFound)
+			},
+			expectedError: domain.ErrActorNotFound,
+		},
+		{
+			name:   "same actor",
+			keepID: 1,
+			dupID:  1,
+			setupMock: func(mas *MockActorService) {
+				mas.On("MergeActors", 1, 1).Return(domain.ActorMergeResult{}, domain.ErrMergeSameActor)
+			},
+			expectedError: domain.ErrMergeSameActor,
 		},
 	}
 
@@ -499,16 +973,179 @@ func TestActorController_GetAllActorsWithMovies(t *testing.T) {
 
 			controller := NewActorController(mockService)
 
-			result, err := controller.GetAllActorsWithMovies(&gin.Context{})
+			result, err := controller.MergeActors(&gin.Context{}, tt.keepID, tt.dupID)
 
-			if tt.expectedError {
-				assert.Error(t, err)
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, result)
+			}
+
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestActorController_PurgeOrphanActors(t *testing.T) {
+	tests := []struct {
+		name          string
+		req           dto.PurgeOrphanActorsRequest
+		setupMock     func(*MockActorService)
+		expected      dto.PurgeOrphanActorsResponse
+		expectedError string
+	}{
+		{
+			name: "success",
+			req:  dto.PurgeOrphanActorsRequest{ConfirmCount: 2},
+			setupMock: func(mas *MockActorService) {
+				mas.On("PurgeOrphans", 2).Return([]domain.Actor{{ID: 4}, {ID: 9}}, nil)
+			},
+			expected: dto.PurgeOrphanActorsResponse{Deleted: 2, IDs: []int{4, 9}},
+		},
+		{
+			name: "confirmation required",
+			setupMock: func(mas *MockActorService) {
+				mas.On("PurgeOrphans", 0).Return([]domain.Actor(nil), &domain.OrphanPurgeError{Count: 25})
+			},
+			expectedError: "too many actors to purge without confirmation: 25 actors",
+		},
+		{
+			name:          "negative confirm count",
+			req:           dto.PurgeOrphanActorsRequest{ConfirmCount: -1},
+			setupMock:     func(mas *MockActorService) {},
+			expectedError: "validation error: confirm_count: must be a non-negative integer",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockActorService{}
+			tt.setupMock(mockService)
+
+			controller := NewActorController(mockService)
+
+			result, err := controller.PurgeOrphanActors(&gin.Context{}, tt.req)
+
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
 			} else {
 				assert.NoError(t, err)
-				assert.Equal(t, tt.expectedResult, result)
+				assert.Equal(t, tt.expected, result)
 			}
 
 			mockService.AssertExpectations(t)
 		})
 	}
 }
+
+func TestActorController_UploadActorPhoto(t *testing.T) {
+	png := []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+	birthDate := time.Date(1974, 11, 11, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name          string
+		data          []byte
+		setupMock     func(*MockActorService)
+		expected      dto.ActorResponse
+		expectedKey   string
+		expectedError error
+	}{
+		{
+			name: "success",
+			data: png,
+			setupMock: func(mas *MockActorService) {
+				mas.On("SetPhoto", mock.Anything, 1, png, "image/png", ".png").Return(domain.Actor{
+					ID: 1, Name: "Leonardo DiCaprio", Gender: "male", BirthDate: birthDate, PhotoKey: "actors/1/photo-1.png",
+				}, nil)
+			},
+			expected: dto.ActorResponse{
+				ID: 1, Name: "Leonardo DiCaprio", Gender: "male", BirthDate: "1974-11-11", Age: ageToday("1974-11-11"), PhotoURL: "/media/actors/1/photo-1.png",
+			},
+		},
+		{
+			name:        "unsupported format",
+			data:        []byte("GIF89a......"),
+			setupMock:   func(mas *MockActorService) {},
+			expectedKey: dto.KeyActorPhotoUnsupported,
+		},
+		{
+			name:        "too large",
+			data:        make([]byte, storage.MaxImageSize+1),
+			setupMock:   func(mas *MockActorService) {},
+			expectedKey: dto.KeyActorPhotoTooLarge,
+		},
+		{
+			name: "actor not found",
+			data: png,
+			setupMock: func(mas *MockActorService) {
+				mas.On("SetPhoto", mock.Anything, 1, png, "image/png", ".png").Return(domain.Actor{}, domain.ErrActorNotFound)
+			},
+			expectedError: domain.ErrActorNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockActorService{}
+			tt.setupMock(mockService)
+
+			controller := NewActorController(mockService)
+
+			result, err := controller.UploadActorPhoto(&gin.Context{}, 1, tt.data)
+
+			switch {
+			case tt.expectedKey != "":
+				var verrs dto.ValidationErrors
+				assert.ErrorAs(t, err, &verrs)
+				assert.Equal(t, tt.expectedKey, verrs[0].Key)
+			case tt.expectedError != nil:
+				assert.ErrorIs(t, err, tt.expectedError)
+			default:
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, result)
+			}
+
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestActorController_GetActorBySlug(t *testing.T) {
+	mockService := &MockActorService{}
+	mockService.On("GetBySlug", "keanu-reeves").
+		Return(domain.Actor{ID: 7, Name: "Keanu Reeves", Slug: "keanu-reeves"}, nil)
+	mockService.On("GetBySlug", "nobody").Return(domain.Actor{}, domain.ErrActorNotFound)
+	controller := NewActorController(mockService)
+
+	resp, err := controller.GetActorBySlug(&gin.Context{}, "keanu-reeves")
+	assert.NoError(t, err)
+	assert.Equal(t, "keanu-reeves", resp.Slug)
+
+	_, err = controller.GetActorBySlug(&gin.Context{}, "nobody")
+	assert.ErrorIs(t, err, domain.ErrActorNotFound)
+	mockService.AssertExpectations(t)
+}
+
+func TestActorController_GetActorStats(t *testing.T) {
+	mockService := &MockActorService{}
+	averageRating := 7.66666
+	mockService.On("GetStats", 7).
+		Return(domain.ActorStats{ActorID: 7, MovieCount: 3, AverageRating: &averageRating, FirstYear: 1972, LatestYear: 1995}, nil)
+	mockService.On("GetStats", 8).Return(domain.ActorStats{ActorID: 8}, nil)
+	mockService.On("GetStats", 999).Return(domain.ActorStats{}, domain.ErrActorNotFound)
+	controller := NewActorController(mockService)
+
+	resp, err := controller.GetActorStats(&gin.Context{}, 7)
+	assert.NoError(t, err)
+	rounded := 7.67
+	assert.Equal(t, dto.ActorStatsResponse{ActorID: 7, MovieCount: 3, AverageRating: &rounded, FirstYear: 1972, LatestYear: 1995}, resp)
+
+	resp, err = controller.GetActorStats(&gin.Context{}, 8)
+	assert.NoError(t, err)
+	assert.Equal(t, dto.ActorStatsResponse{ActorID: 8}, resp)
+
+	_, err = controller.GetActorStats(&gin.Context{}, 999)
+	assert.ErrorIs(t, err, domain.ErrActorNotFound)
+	mockService.AssertExpectations(t)
+}
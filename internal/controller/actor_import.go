@@ -0,0 +1,146 @@
+package controller
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"cinematique/internal/controller/dto"
+	"cinematique/internal/controller/mapper"
+	"cinematique/internal/domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxActorImportRows — сколько актёров можно импортировать одним файлом
+const maxActorImportRows = 1000
+
+// Статусы строк в отчёте об импорте актёров
+const (
+	importRowValid   = "valid"
+	importRowInvalid = "invalid"
+	importRowCreated = "created"
+)
+
+// actorImportColumns — обязательные колонки CSV. Остальные колонки (например, id и photo_url
+// из файла экспорта) пропускаются
+var actorImportColumns = []string{"name", "gender", "birth_date"}
+
+// ImportActorsCSV проверяет актёров из CSV: формат полей, повторы внутри файла и совпадения
+// с каталогом по имени и дате рождения. Отчёт содержит результат по каждой строке.
+// Если dryRun == false и все строки корректны, актёры создаются в одной транзакции
+func (c *actorController) ImportActorsCSV(ctx *gin.Context, r io.Reader, dryRun bool) (dto.ActorImportResponse, error) {
+	rows, actors, err := readActorImport(r)
+	if err != nil {
+		return dto.ActorImportResponse{}, err
+	}
+
+	existing, err := c.actorService.MatchExistingActors(requestContext(ctx), actors)
+	if err != nil {
+		return dto.ActorImportResponse{}, err
+	}
+	response := dto.ActorImportResponse{DryRun: dryRun, Total: len(rows), Rows: rows}
+	for i := range response.Rows {
+		row := &response.Rows[i]
+		if row.Status == importRowValid && existing[i] != 0 {
+			row.Status = importRowInvalid
+			row.Errors = append(row.Errors, dto.NewFieldError(dto.KeyActorImportExists))
+		}
+		if row.Status == importRowValid {
+			response.Valid++
+		} else {
+			response.Invalid++
+		}
+	}
+	if dryRun || response.Invalid > 0 {
+		return response, nil
+	}
+
+	ids, err := c.actorService.ImportActors(requestContext(ctx), actors)
+	if err != nil {
+		return dto.ActorImportResponse{}, err
+	}
+	for i, id := range ids {
+		response.Rows[i].Status = importRowCreated
+		response.Rows[i].ID = id
+	}
+	response.Created = len(ids)
+	return response, nil
+}
+
+// readActorImport разбирает CSV и проверяет каждую строку правилами создания актёра.
+// Возвращает отчёт по строкам и актёров в том же порядке; поля актёра из некорректной
+// строки остаются пустыми и не используются. Ошибка возвращается, только если не читается сам файл
+func readActorImport(r io.Reader) ([]dto.ActorImportRow, []domain.Actor, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, nil, fmt.Errorf("validation error: %w", dto.ValidationErrors{dto.NewFieldError(dto.KeyActorImportEmpty)})
+		}
+		return nil, nil, fmt.Errorf("validation error: %w", dto.ValidationErrors{dto.NewFieldError(dto.KeyActorImportFile)})
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		// Excel сохраняет UTF-8 с BOM в начале первой колонки
+		columns[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+	for _, name := range actorImportColumns {
+		if _, ok := columns[name]; !ok {
+			return nil, nil, fmt.Errorf("validation error: %w", dto.ValidationErrors{dto.NewFieldError(dto.KeyActorImportFile)})
+		}
+	}
+	field := func(record []string, name string) string {
+		if i := columns[name]; i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	rows := make([]dto.ActorImportRow, 0)
+	actors := make([]domain.Actor, 0)
+	seen := make(map[string]bool)
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("validation error: %w", dto.ValidationErrors{dto.NewFieldError(dto.KeyActorImportFile)})
+		}
+		if len(rows) == maxActorImportRows {
+			return nil, nil, fmt.Errorf("validation error: %w", dto.ValidationErrors{dto.NewFieldError(dto.KeyActorImportTooManyRows)})
+		}
+
+		line, _ := reader.FieldPos(0)
+		name, gender, birthDate := field(record, "name"), field(record, "gender"), field(record, "birth_date")
+		row := dto.ActorImportRow{Row: line, Name: name, Status: importRowValid}
+		var actor domain.Actor
+		if err := validateActorInput(name, gender, birthDate, ""); err != nil {
+			var fieldErrs dto.ValidationErrors
+			errors.As(err, &fieldErrs)
+			row.Status = importRowInvalid
+			row.Errors = fieldErrs
+		} else {
+			birth, _ := mapper.ParseDate(birthDate)
+			actor = domain.Actor{Name: name, Gender: strings.ToLower(gender), BirthDate: birth}
+			key := strings.ToLower(name) + "|" + birthDate
+			if seen[key] {
+				row.Status = importRowInvalid
+				row.Errors = dto.ValidationErrors{dto.NewFieldError(dto.KeyActorImportDuplicate)}
+			}
+			seen[key] = true
+		}
+		rows = append(rows, row)
+		actors = append(actors, actor)
+	}
+	if len(rows) == 0 {
+		return nil, nil, fmt.Errorf("validation error: %w", dto.ValidationErrors{dto.NewFieldError(dto.KeyActorImportEmpty)})
+	}
+	return rows, actors, nil
+}
@@ -0,0 +1,96 @@
+package controller
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"cinematique/internal/controller/dto"
+	"cinematique/internal/domain"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestActorController_ImportActorsCSV(t *testing.T) {
+	keanu := domain.Actor{Name: "Keanu Reeves", Gender: "male", BirthDate: time.Date(1964, 9, 2, 0, 0, 0, 0, time.UTC)}
+	carrie := domain.Actor{Name: "Carrie-Anne Moss", Gender: "female", BirthDate: time.Date(1967, 8, 21, 0, 0, 0, 0, time.UTC)}
+	const valid = "id,name,gender,birth_date\n" +
+		"1,Keanu Reeves,male,1964-09-02\n" +
+		"2,Carrie-Anne Moss,Female,1967-08-21\n"
+
+	t.Run("dry run reports rows without writing", func(t *testing.T) {
+		mockService := new(MockActorService)
+		mockService.On("MatchExistingActors", []domain.Actor{keanu, carrie}).Return([]int{0, 0}, nil)
+
+		resp, err := NewActorController(mockService).ImportActorsCSV(&gin.Context{}, strings.NewReader(valid), true)
+		require.NoError(t, err)
+		assert.Equal(t, dto.ActorImportResponse{DryRun: true, Total: 2, Valid: 2, Rows: []dto.ActorImportRow{
+			{Row: 2, Name: "Keanu Reeves", Status: "valid"},
+			{Row: 3, Name: "Carrie-Anne Moss", Status: "valid"},
+		}}, resp)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("commit creates all actors", func(t *testing.T) {
+		mockService := new(MockActorService)
+		mockService.On("MatchExistingActors", []domain.Actor{keanu, carrie}).Return([]int{0, 0}, nil)
+		mockService.On("ImportActors", []domain.Actor{keanu, carrie}).Return([]int{10, 11}, nil)
+
+		resp, err := NewActorController(mockService).ImportActorsCSV(&gin.Context{}, strings.NewReader(valid), false)
+		require.NoError(t, err)
+		assert.Equal(t, 2, resp.Created)
+		assert.Equal(t, dto.ActorImportRow{Row: 3, Name: "Carrie-Anne Moss", Status: "created", ID: 11}, resp.Rows[1])
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("invalid rows block the commit", func(t *testing.T) {
+		body := "name,gender,birth_date\n" +
+			"Keanu Reeves,male,1964-09-02\n" +
+			"keanu reeves,male,1964-09-02\n" +
+			"Carrie-Anne Moss,unknown,21.08.1967\n" +
+			"Carrie-Anne Moss,female,1967-08-21\n"
+		mockService := new(MockActorService)
+		mockService.On("MatchExistingActors", mock.Anything).Return([]int{0, 0, 0, 5}, nil)
+
+		resp, err := NewActorController(mockService).ImportActorsCSV(&gin.Context{}, strings.NewReader(body), false)
+		require.NoError(t, err)
+		assert.Equal(t, 4, resp.Total)
+		assert.Equal(t, 1, resp.Valid)
+		assert.Equal(t, 3, resp.Invalid)
+		assert.Equal(t, 0, resp.Created)
+
+		keys := func(row dto.ActorImportRow) []string {
+			result := make([]string, 0, len(row.Errors))
+			for _, fieldErr := range row.Errors {
+				result = append(result, fieldErr.Key)
+			}
+			return result
+		}
+		assert.Empty(t, keys(resp.Rows[0]))
+		assert.Equal(t, []string{dto.KeyActorImportDuplicate}, keys(resp.Rows[1]))
+		assert.Equal(t, []string{dto.KeyActorGenderInvalid, dto.KeyActorBirthDateInvalid}, keys(resp.Rows[2]))
+		assert.Equal(t, []string{dto.KeyActorImportExists}, keys(resp.Rows[3]))
+		mockService.AssertNotCalled(t, "ImportActors", mock.Anything)
+	})
+
+	t.Run("malformed file", func(t *testing.T) {
+		for name, body := range map[string]string{
+			"empty":          "",
+			"header only":    "name,gender,birth_date\n",
+			"missing column": "name,gender\nKeanu Reeves,male\n",
+			"bad quoting":    "name,gender,birth_date\n\"Keanu,male,1964-09-02\n",
+		} {
+			_, err := NewActorController(new(MockActorService)).ImportActorsCSV(&gin.Context{}, strings.NewReader(body), true)
+			assert.ErrorContains(t, err, "validation error", name)
+		}
+
+		body := "name,gender,birth_date\n" + strings.Repeat("Keanu Reeves,male,1964-09-02\n", maxActorImportRows+1)
+		_, err := NewActorController(new(MockActorService)).ImportActorsCSV(&gin.Context{}, strings.NewReader(body), true)
+		var validationErrs dto.ValidationErrors
+		require.ErrorAs(t, err, &validationErrs)
+		assert.Equal(t, dto.KeyActorImportTooManyRows, validationErrs[0].Key)
+	})
+}
@@ -0,0 +1,99 @@
+package controller
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+
+	"cinematique/internal/controller/dto"
+	"cinematique/internal/controller/mapper"
+	"cinematique/internal/domain"
+)
+
+// collectionController обрабатывает запросы, связанные с подборками фильмов
+type collectionController struct {
+	collectionService ServiceCollection
+}
+
+// NewCollectionController создаёт контроллер подборок
+func NewCollectionController(collectionService ServiceCollection) *collectionController {
+	return &collectionController{collectionService: collectionService}
+}
+
+// validateCollectionInput проверяет название и описание подборки
+func validateCollectionInput(name, description string) error {
+	var errs dto.ValidationErrors
+	if length := utf8.RuneCountInString(strings.TrimSpace(name)); length == 0 || length > 150 {
+		errs.Add(dto.KeyCollectionNameLength)
+	}
+	if utf8.RuneCountInString(description) > 1000 {
+		errs.Add(dto.KeyCollectionDescTooLong)
+	}
+	return errs.Err()
+}
+
+// CreateCollection создаёт подборку и возвращает её вместе с фильмами
+func (c *collectionController) CreateCollection(ctx *gin.Context, req dto.CreateCollectionRequest) (dto.CollectionResponse, error) {
+	if err := validateCollectionInput(req.Name, req.Description); err != nil {
+		return dto.CollectionResponse{}, fmt.Errorf("validation error: %w", err)
+	}
+
+	id, err := c.collectionService.Create(requestContext(ctx), domain.Collection{
+		Name:        strings.TrimSpace(req.Name),
+		Description: req.Description,
+	}, req.MovieIDs)
+	if err != nil {
+		return dto.CollectionResponse{}, err
+	}
+	return c.GetCollectionByID(ctx, id)
+}
+
+// GetCollectionByID возвращает подборку с фильмами в порядке просмотра
+func (c *collectionController) GetCollectionByID(ctx *gin.Context, id int) (dto.CollectionResponse, error) {
+	collection, err := c.collectionService.GetByID(requestContext(ctx), id)
+	if err != nil {
+		return dto.CollectionResponse{}, err
+	}
+	return mapper.Collection(collection), nil
+}
+
+// ListCollections возвращает все подборки без фильмов
+func (c *collectionController) ListCollections(ctx *gin.Context) (dto.CollectionsListResponse, error) {
+	collections, err := c.collectionService.GetAll(requestContext(ctx))
+	if err != nil {
+		return dto.CollectionsListResponse{}, fmt.Errorf("listing collections: %w", err)
+	}
+	return dto.CollectionsListResponse{Collections: mapper.Collections(collections)}, nil
+}
+
+// UpdateCollection обновляет название и описание подборки
+func (c *collectionController) UpdateCollection(ctx *gin.Context, id int, req dto.UpdateCollectionRequest) (dto.CollectionResponse, error) {
+	if err := validateCollectionInput(req.Name, req.Description); err != nil {
+		return dto.CollectionResponse{}, fmt.Errorf("validation error: %w", err)
+	}
+
+	err := c.collectionService.Update(requestContext(ctx), domain.Collection{
+		ID:          id,
+		Name:        strings.TrimSpace(req.Name),
+		Description: req.Description,
+	})
+	if err != nil {
+		return dto.CollectionResponse{}, err
+	}
+	return c.GetCollectionByID(ctx, id)
+}
+
+// DeleteCollection удаляет подборку
+func (c *collectionController) DeleteCollection(ctx *gin.Context, id int) error {
+	return c.collectionService.Delete(requestContext(ctx), id)
+}
+
+// SetCollectionMovies заменяет фильмы подборки и возвращает подборку в новом порядке
+func (c *collectionController) SetCollectionMovies(ctx *gin.Context, id int, req dto.SetCollectionMoviesRequest) (dto.CollectionResponse, error) {
+	if err := c.collectionService.SetMovies(requestContext(ctx), id, req.MovieIDs); err != nil {
+		return dto.CollectionResponse{}, err
+	}
+	return c.GetCollectionByID(ctx, id)
+}
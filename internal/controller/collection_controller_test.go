@@ -0,0 +1,119 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"cinematique/internal/controller/dto"
+	"cinematique/internal/domain"
+)
+
+// MockCollectionService мок сервиса подборок
+type MockCollectionService struct {
+	mock.Mock
+}
+
+func (m *MockCollectionService) Create(_ context.Context, collection domain.Collection, movieIDs []int) (int, error) {
+	args := m.Called(collection, movieIDs)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockCollectionService) GetByID(_ context.Context, id int) (domain.Collection, error) {
+	args := m.Called(id)
+	return args.Get(0).(domain.Collection), args.Error(1)
+}
+
+func (m *MockCollectionService) GetAll(_ context.Context) ([]domain.Collection, error) {
+	args := m.Called()
+	return args.Get(0).([]domain.Collection), args.Error(1)
+}
+
+func (m *MockCollectionService) Update(_ context.Context, collection domain.Collection) error {
+	return m.Called(collection).Error(0)
+}
+
+func (m *MockCollectionService) Delete(_ context.Context, id int) error {
+	return m.Called(id).Error(0)
+}
+
+func (m *MockCollectionService) SetMovies(_ context.Context, collectionID int, movieIDs []int) error {
+	return m.Called(collectionID, movieIDs).Error(0)
+}
+
+var matrixTrilogy = domain.Collection{
+	ID:   3,
+	Name: "The Matrix Trilogy",
+	Movies: []domain.Movie{
+		{ID: 10, Title: "The Matrix", ReleaseYear: 1999},
+		{ID: 11, Title: "The Matrix Reloaded", ReleaseYear: 2003},
+	},
+}
+
+func TestCollectionController_CreateCollection(t *testing.T) {
+	t.Run("created", func(t *testing.T) {
+		mockService := &MockCollectionService{}
+		mockService.On("Create", domain.Collection{Name: "The Matrix Trilogy"}, []int{10, 11}).Return(3, nil)
+		mockService.On("GetByID", 3).Return(matrixTrilogy, nil)
+
+		resp, err := NewCollectionController(mockService).CreateCollection(&gin.Context{}, dto.CreateCollectionRequest{
+			Name:     "  The Matrix Trilogy ",
+			MovieIDs: []int{10, 11},
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, 3, resp.ID)
+		require.Len(t, resp.Movies, 2)
+		assert.Equal(t, "The Matrix", resp.Movies[0].Title)
+		assert.Equal(t, "The Matrix Reloaded", resp.Movies[1].Title)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("empty name", func(t *testing.T) {
+		mockService := &MockCollectionService{}
+		_, err := NewCollectionController(mockService).CreateCollection(&gin.Context{}, dto.CreateCollectionRequest{Name: " "})
+
+		var validationErrs dto.ValidationErrors
+		require.ErrorAs(t, err, &validationErrs)
+		assert.Equal(t, dto.KeyCollectionNameLength, validationErrs[0].Key)
+		mockService.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("unknown movie", func(t *testing.T) {
+		mockService := &MockCollectionService{}
+		mockService.On("Create", mock.Anything, []int{99}).Return(0, domain.ErrMovieNotFound)
+
+		_, err := NewCollectionController(mockService).CreateCollection(&gin.Context{}, dto.CreateCollectionRequest{Name: "Trilogy", MovieIDs: []int{99}})
+		assert.ErrorIs(t, err, domain.ErrMovieNotFound)
+	})
+}
+
+func TestCollectionController_ListCollections(t *testing.T) {
+	mockService := &MockCollectionService{}
+	mockService.On("GetAll").Return([]domain.Collection{{ID: 3, Name: "The Matrix Trilogy"}}, nil)
+
+	resp, err := NewCollectionController(mockService).ListCollections(&gin.Context{})
+
+	require.NoError(t, err)
+	assert.Equal(t, []dto.CollectionResponse{{ID: 3, Name: "The Matrix Trilogy"}}, resp.Collections)
+}
+
+func TestCollectionController_SetCollectionMovies(t *testing.T) {
+	mockService := &MockCollectionService{}
+	mockService.On("SetMovies", 3, []int{11, 10}).Return(nil)
+	mockService.On("GetByID", 3).Return(domain.Collection{ID: 3, Name: "The Matrix Trilogy", Movies: []domain.Movie{
+		{ID: 11, Title: "The Matrix Reloaded"},
+		{ID: 10, Title: "The Matrix"},
+	}}, nil)
+
+	resp, err := NewCollectionController(mockService).SetCollectionMovies(&gin.Context{}, 3, dto.SetCollectionMoviesRequest{MovieIDs: []int{11, 10}})
+
+	require.NoError(t, err)
+	assert.Equal(t, 11, resp.Movies[0].ID)
+	assert.Equal(t, 10, resp.Movies[1].ID)
+	mockService.AssertExpectations(t)
+}
@@ -0,0 +1,15 @@
+package controller
+
+import (
+	"context"
+
+	"github.com/gin-gonic/gin"
+)
+
+// requestContext возвращает контекст HTTP-запроса со span трассировки для передачи в сервисы
+func requestContext(ctx *gin.Context) context.Context {
+	if ctx.Request == nil {
+		return context.Background()
+	}
+	return ctx.Request.Context()
+}
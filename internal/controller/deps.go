@@ -1,34 +1,123 @@
 package controller
 
-import "cinematique/internal/domain"
+import (
+	"cinematique/internal/domain"
+	"context"
+	"time"
+)
 
 // ServiceActor интерфейс сервисного слоя для Actor
 type ServiceActor interface {
-	Create(actor domain.Actor) (int, error)
-	GetByID(id int) (domain.Actor, error)
-	Update(actor domain.Actor) error
-	Delete(id int) error
-	GetAll() ([]domain.Actor, error)
-	GetMovies(actorID int) ([]domain.Movie, error)
-	GetAllActorsWithMovies() ([]domain.Actor, error)
+	Create(ctx context.Context, actor domain.Actor) (int, error)
+	GetByID(ctx context.Context, id int) (domain.Actor, error)
+	GetBySlug(ctx context.Context, slug string) (domain.Actor, error)
+	Update(ctx context.Context, actor domain.Actor) error
+	Delete(ctx context.Context, id int) error
+	ExportActors(ctx context.Context, fn func(domain.Actor) error) error
+	SearchActorsByName(ctx context.Context, nameFragment string, filter domain.ActorFilter, limit, offset int) ([]domain.Actor, error)
+	CountActors(ctx context.Context, nameFragment string, filter domain.ActorFilter) (int, error)
+	SuggestActors(ctx context.Context, prefix string, limit int) ([]domain.Actor, error)
+	GetActorsBornInMonth(ctx context.Context, month int) ([]domain.Actor, error)
+	GetMovies(ctx context.Context, actorID int) ([]domain.Movie, error)
+	GetStats(ctx context.Context, actorID int) (domain.ActorStats, error)
+	GetHistory(ctx context.Context, actorID int) ([]domain.ActorVersion, error)
+	GetAllActorsWithMovies(ctx context.Context, query domain.ActorsWithMoviesQuery) ([]domain.Actor, error)
+	MergeActors(ctx context.Context, keepID, dupID int) (domain.ActorMergeResult, error)
+	ListOrphans(ctx context.Context, limit, offset int) ([]domain.Actor, int, error)
+	PurgeOrphans(ctx context.Context, confirmCount int) ([]domain.Actor, error)
+	OrphanPurgeThreshold() int
+	SetPhoto(ctx context.Context, id int, data []byte, contentType, ext string) (domain.Actor, error)
+	PhotoURL(key string) string
+	MatchExistingActors(ctx context.Context, actors []domain.Actor) ([]int, error)
+	ImportActors(ctx context.Context, actors []domain.Actor) ([]int, error)
 }
 
 // ServiceMovie интерфейс сервисного слоя для Movie
 type ServiceMovie interface {
-	Create(movie domain.Movie, actorIDs []int) (int, error)
-	GetByID(id int) (domain.Movie, error)
-	Update(movie domain.Movie, actorIDs []int) error
-	Delete(id int) error
-	GetAll() ([]domain.Movie, error)
-	AddActor(movieID, actorID int) error
-	RemoveActor(movieID, actorID int) error
-	GetActors(movieID int) ([]domain.Actor, error)
-	GetActorsForMovieByID(movieID int) ([]domain.Actor, error)
-	GetMoviesForActor(actorID int) ([]domain.Movie, error)
-	SearchMoviesByTitle(titleFragment string) ([]domain.Movie, error)
-	SearchMoviesByActorName(actorNameFragment string) ([]domain.Movie, error)
-	GetAllMoviesSorted(sortField, sortOrder string) ([]domain.Movie, error)
-	CreateMovieWithActors(movie domain.Movie, actorIDs []int) (int, error)
-	UpdateMovieActors(movieID int, actorIDs []int) error
-	PartialUpdateMovie(id int, update domain.MovieUpdate) error
+	Create(ctx context.Context, movie domain.Movie, actorIDs []int, force bool) (int, error)
+	GetByID(ctx context.Context, id int) (domain.Movie, error)
+	GetBySlug(ctx context.Context, slug string) (domain.Movie, error)
+	Update(ctx context.Context, movie domain.Movie, actorIDs []int) error
+	Delete(ctx context.Context, id int) error
+	DeleteCreated(ctx context.Context, movieID int, createdActorIDs []int) error
+	GetMoviesAfterID(ctx context.Context, afterID, limit int) ([]domain.Movie, bool, error)
+	ExportMovies(ctx context.Context, fn func(domain.Movie) error) error
+	AddActor(ctx context.Context, movieID int, member domain.CastMember) error
+	RemoveActor(ctx context.Context, movieID, actorID int) error
+	GetActors(ctx context.Context, movieID int) ([]domain.Actor, error)
+	GetActorsForMovieByID(ctx context.Context, movieID int) ([]domain.Actor, error)
+	GetMoviesForActor(ctx context.Context, actorID int) ([]domain.Movie, error)
+	SearchMoviesByTitle(ctx context.Context, titleFragment string, filter domain.MovieFilter) ([]domain.Movie, error)
+	SearchMoviesByActorName(ctx context.Context, actorNameFragment string, filter domain.MovieFilter) ([]domain.Movie, error)
+	GetMatchingActors(ctx context.Context, movieIDs []int, nameFragment string) (map[int][]domain.Actor, error)
+	GetAllMoviesSorted(ctx context.Context, query domain.MovieListQuery) ([]domain.Movie, error)
+	CountMovies(ctx context.Context, filter domain.MovieFilter) (int, error)
+	CreateMovieWithActors(ctx context.Context, movie domain.Movie, actorIDs []int) (int, error)
+	CreateFull(ctx context.Context, movie domain.Movie, cast []domain.Actor, force bool) (domain.MovieImportResult, error)
+	UpdateMovieActors(ctx context.Context, movieID int, cast []domain.CastMember) error
+	ChangeMovieActors(ctx context.Context, movieID int, add []domain.CastMember, remove []int) error
+	ReorderActors(ctx context.Context, movieID int, actorIDs []int) ([]domain.Actor, error)
+	PartialUpdateMovie(ctx context.Context, id int, update domain.MovieUpdate) error
+	MergeMovies(ctx context.Context, keepID, dupID int) (domain.MovieMergeResult, error)
+	BulkDeleteMovies(ctx context.Context, ids []int) ([]domain.BulkItemResult, error)
+	BulkUpdateMovies(ctx context.Context, ids []int, update domain.MovieUpdate) ([]domain.BulkItemResult, error)
+	ResolveMergedMovieID(ctx context.Context, id int) (int, error)
+	GetUpcomingMovies(ctx context.Context) ([]domain.Movie, error)
+	GetMovieAsOf(ctx context.Context, id int, asOf time.Time) (domain.Movie, error)
+	GetRatingHistory(ctx context.Context, movieID int) ([]domain.RatingChange, error)
+	GetMovieHistory(ctx context.Context, id int) ([]domain.MovieVersion, error)
+	GetRatings(ctx context.Context, movieID int) ([]domain.MovieRating, *float64, error)
+	SetRating(ctx context.Context, rating domain.MovieRating) error
+	DeleteRating(ctx context.Context, movieID int, source string) error
+	GetAvailability(ctx context.Context, movieID int) ([]domain.Availability, error)
+	AddAvailability(ctx context.Context, window domain.Availability) (domain.Availability, error)
+	UpdateAvailability(ctx context.Context, window domain.Availability) error
+	DeleteAvailability(ctx context.Context, movieID, windowID int) error
+	GetPopularMovies(ctx context.Context, limit int) ([]domain.Movie, error)
+	ListByYears(ctx context.Context, fromYear, toYear, limit, offset int) ([]domain.Movie, int, error)
+	ImportExternal(ctx context.Context, imdbID string) (domain.MovieImportResult, error)
+	UpsertByExternalID(ctx context.Context, movie domain.Movie, actorIDs []int) (int, domain.MovieUpsertAction, error)
+	GetByExternalID(ctx context.Context, externalID string) (domain.Movie, error)
+	GetTags(ctx context.Context, movieID int) ([]string, error)
+	AddTag(ctx context.Context, movieID int, tag string) ([]string, error)
+	RemoveTag(ctx context.Context, movieID int, tag string) error
+	ListByTag(ctx context.Context, tag string, limit, offset int) ([]domain.Movie, int, error)
+	ListCast(ctx context.Context, movieID, limit, offset int) ([]domain.Actor, int, error)
+	SuggestTags(ctx context.Context, prefix string, limit int) ([]domain.Tag, error)
+}
+
+// ServiceCollection интерфейс сервисного слоя для подборок фильмов
+type ServiceCollection interface {
+	Create(ctx context.Context, collection domain.Collection, movieIDs []int) (int, error)
+	GetByID(ctx context.Context, id int) (domain.Collection, error)
+	GetAll(ctx context.Context) ([]domain.Collection, error)
+	Update(ctx context.Context, collection domain.Collection) error
+	Delete(ctx context.Context, id int) error
+	SetMovies(ctx context.Context, collectionID int, movieIDs []int) error
+}
+
+// ServiceSearch интерфейс сервиса поисковых подсказок
+type ServiceSearch interface {
+	SuggestForTitle(ctx context.Context, query string) (domain.SearchSuggestions, error)
+	SuggestForActorName(ctx context.Context, query string) (domain.SearchSuggestions, error)
+}
+
+// ServiceCatalogSearch интерфейс общего поиска по фильмам и актёрам
+type ServiceCatalogSearch interface {
+	Search(ctx context.Context, query string, limit int) ([]domain.SearchHit, error)
+}
+
+// ServiceStats интерфейс сервиса статистики каталога
+type ServiceStats interface {
+	Get(ctx context.Context) (domain.CatalogStats, error)
+}
+
+// ServiceMaintenance интерфейс служебных операций над базой данных
+type ServiceMaintenance interface {
+	Reindex(ctx context.Context, progress func(done, total int, step string)) (domain.ReindexResult, error)
+}
+
+// ServiceSearchStats интерфейс поисковой аналитики
+type ServiceSearchStats interface {
+	TopSearches(ctx context.Context, days, limit int) (domain.SearchAnalytics, error)
 }
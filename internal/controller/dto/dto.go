@@ -6,57 +6,248 @@ type CreateActorRequest struct {
 	Name      string `json:"name"`
 	Gender    string `json:"gender"`
 	BirthDate string `json:"birth_date"`
+	DeathDate string `json:"death_date,omitempty"` // YYYY-MM-DD; пусто, если актёр жив
 }
 
 type UpdateActorRequest struct {
 	Name      *string `json:"name,omitempty"`
 	Gender    *string `json:"gender,omitempty"`
 	BirthDate *string `json:"birth_date,omitempty"`
+	DeathDate *string `json:"death_date,omitempty"` // пустая строка сбрасывает дату смерти
 }
 
 type ActorResponse struct {
-	ID        int    `json:"id"`
-	Name      string `json:"name"`
-	Gender    string `json:"gender"`
-	BirthDate string `json:"birth_date"`
+	ID            int        `json:"id"`
+	Name          string     `json:"name"`
+	Gender        string     `json:"gender"`
+	BirthDate     string     `json:"birth_date"`
+	DeathDate     string     `json:"death_date,omitempty"`
+	Age           int        `json:"age,omitempty"` // полных лет на текущую дату или на дату смерти
+	PhotoURL      string     `json:"photo_url,omitempty"`
+	Slug          string     `json:"slug,omitempty"`           // адрес /actors/slug/:slug; в составе фильма не заполняется
+	CharacterName string     `json:"character_name,omitempty"` // только в составе фильма
+	BillingOrder  int        `json:"billing_order,omitempty"`  // только в составе фильма
+	UpdatedAt     *time.Time `json:"updated_at,omitempty"`     // только для одного актёра
 }
 
 type ActorsListResponse struct {
-	Actors []ActorResponse `json:"actors"`
+	Actors     []ActorResponse `json:"actors"`
+	Pagination *Pagination     `json:"pagination,omitempty"`
+}
+
+// ActorMergeResponse - результат слияния актёра-дубликата
+type ActorMergeResponse struct {
+	Actor            ActorResponse `json:"actor"`
+	DuplicateID      int           `json:"duplicate_id"`
+	MoviesReassigned int           `json:"movies_reassigned"`
+}
+
+// OrphanActorsResponse - страница актёров без фильмов и их общее число
+type OrphanActorsResponse struct {
+	Actors     []ActorResponse `json:"actors"`
+	Total      int             `json:"total"`
+	Threshold  int             `json:"threshold"` // сколько актёров можно удалить без confirm_count
+	Pagination *Pagination     `json:"pagination,omitempty"`
+}
+
+// PurgeOrphanActorsRequest - запрос на удаление актёров без фильмов. ConfirmCount
+// подтверждает удаление, когда актёров больше порога: оно должно быть не меньше их числа
+type PurgeOrphanActorsRequest struct {
+	ConfirmCount int `json:"confirm_count"`
+}
+
+// PurgeOrphanActorsResponse - итог удаления актёров без фильмов
+type PurgeOrphanActorsResponse struct {
+	Deleted int   `json:"deleted"`
+	IDs     []int `json:"ids"`
+}
+
+// ActorImportResponse - отчёт об импорте актёров из CSV. При dry_run, а также если хотя бы
+// одна строка не прошла проверку, актёры не создаются и Created равно нулю
+type ActorImportResponse struct {
+	DryRun  bool             `json:"dry_run"`
+	Total   int              `json:"total"`
+	Valid   int              `json:"valid"`
+	Invalid int              `json:"invalid"`
+	Created int              `json:"created"`
+	Rows    []ActorImportRow `json:"rows"`
+}
+
+// ActorImportRow - результат проверки одной строки файла импорта
+type ActorImportRow struct {
+	Row    int          `json:"row"` // номер строки в файле; заголовок - строка 1
+	Name   string       `json:"name"`
+	Status string       `json:"status"`       // valid, invalid или created
+	ID     int          `json:"id,omitempty"` // ID созданного актёра
+	Errors []FieldError `json:"errors,omitempty"`
 }
 
 type CreateMovieRequest struct {
-	Title       string  `json:"title" validate:"required,min=1,max=150"`
-	Description string  `json:"description" validate:"max=1000"`
-	ReleaseYear int     `json:"release_year" validate:"required"`
-	Rating      float64 `json:"rating" validate:"min=0,max=10"`
-	ActorIDs    []int   `json:"actor_ids"`
+	Title            string  `json:"title" validate:"required,min=1,max=150"`
+	Description      string  `json:"description" validate:"max=1000"`
+	ReleaseYear      int     `json:"release_year" validate:"required"`
+	ReleaseDate      string  `json:"release_date,omitempty"` // YYYY-MM-DD
+	Rating           float64 `json:"rating" validate:"min=0,max=10"`
+	ActorIDs         []int   `json:"actor_ids"`
+	Force            bool    `json:"-"`                           // из параметра ?force=true: создать фильм, даже если похож на существующий
+	OriginalLanguage string  `json:"original_language,omitempty"` // ISO 639-1, например "en"
+	Country          string  `json:"country,omitempty"`           // ISO 3166-1 alpha-2, например "US"
+	RuntimeMinutes   int     `json:"runtime_minutes,omitempty"`   // продолжительность в минутах, от 1 до 999
 }
 
 type UpdateMovieRequest struct {
-	Title       *string  `json:"title,omitempty" validate:"omitempty,min=1,max=150"`
-	Description *string  `json:"description,omitempty" validate:"omitempty,max=1000"`
-	ReleaseYear *int     `json:"release_year,omitempty"`
-	Rating      *float64 `json:"rating,omitempty" validate:"omitempty,min=0,max=10"`
-	ActorIDs    *[]int   `json:"actor_ids,omitempty"`
+	Title            *string  `json:"title,omitempty" validate:"omitempty,min=1,max=150"`
+	Description      *string  `json:"description,omitempty" validate:"omitempty,max=1000"`
+	ReleaseYear      *int     `json:"release_year,omitempty"`
+	ReleaseDate      *string  `json:"release_date,omitempty"` // YYYY-MM-DD
+	Rating           *float64 `json:"rating,omitempty" validate:"omitempty,min=0,max=10"`
+	ActorIDs         *[]int   `json:"actor_ids,omitempty"`
+	OriginalLanguage *string  `json:"original_language,omitempty"` // пустая строка сбрасывает значение
+	Country          *string  `json:"country,omitempty"`
+	RuntimeMinutes   *int     `json:"runtime_minutes,omitempty"` // 0 сбрасывает значение
 }
 
 type MovieResponse struct {
-	ID          int            `json:"id"`
-	Title       string         `json:"title"`
-	Description string         `json:"description"`
-	ReleaseYear int            `json:"release_year"`
-	Rating      float64        `json:"rating"`
-	Actors      []ActorPreview `json:"actors,omitempty"`
+	ID               int                `json:"id"`
+	Title            string             `json:"title"`
+	Description      string             `json:"description"`
+	ReleaseYear      int                `json:"release_year"`
+	ReleaseDate      string             `json:"release_date,omitempty"`
+	Rating           float64            `json:"rating"`
+	Ratings          map[string]float64 `json:"ratings,omitempty"`        // рейтинги по источникам; только для одного фильма
+	DisplayRating    *float64           `json:"display_rating,omitempty"` // взвешенный рейтинг по источникам; только для одного фильма
+	ViewCount        int64              `json:"view_count"`
+	OriginalLanguage string             `json:"original_language,omitempty"`
+	Country          string             `json:"country,omitempty"`
+	RuntimeMinutes   int                `json:"runtime_minutes,omitempty"`
+	Slug             string             `json:"slug,omitempty"`        // адрес /movies/slug/:slug
+	ExternalID       string             `json:"external_id,omitempty"` // ID в каталоге-источнике; только для одного фильма
+	Actors           []ActorPreview     `json:"actors,omitempty"`
+	UpdatedAt        *time.Time         `json:"updated_at,omitempty"` // только для одного фильма
+	Matches          []SearchMatch      `json:"matches,omitempty"`    // только для поиска с ?highlight=true
+}
+
+// SearchMatch - поле найденного фильма, в котором совпал поисковый запрос
+type SearchMatch struct {
+	Field   string       `json:"field"`              // title или actor
+	Value   string       `json:"value"`              // название фильма или имя актёра
+	ActorID int          `json:"actor_id,omitempty"` // только для field=actor
+	Ranges  []MatchRange `json:"ranges"`             // пусто, если название найдено поиском с учётом опечаток
+}
+
+// MatchRange - найденная подстрока Value: [Start, End) в символах, а не в байтах
+type MatchRange struct {
+	Start int `json:"start"`
+	End   int `json:"end"`
 }
 
 type ActorPreview struct {
+	ID            int    `json:"id"`
+	Name          string `json:"name"`
+	CharacterName string `json:"character_name,omitempty"`
+}
+
+// ActorSuggestion — подсказка автодополнения: только то, что нужно для выбора актёра
+type ActorSuggestion struct {
 	ID   int    `json:"id"`
 	Name string `json:"name"`
 }
 
+type ActorSuggestionsResponse struct {
+	Actors []ActorSuggestion `json:"actors"`
+}
+
 type MoviesListResponse struct {
-	Movies []MovieResponse `json:"movies"`
+	Movies      []MovieResponse    `json:"movies"`
+	Pagination  *Pagination        `json:"pagination,omitempty"`
+	NextCursor  string             `json:"next_cursor,omitempty"` // только для постраничного чтения по cursor; пусто на последней странице
+	Suggestions *SearchSuggestions `json:"suggestions,omitempty"` // только для поиска без результатов
+}
+
+// MoviesByPeriodResponse - страница фильмов, вышедших за год или десятилетие, и их общее число
+type MoviesByPeriodResponse struct {
+	FromYear   int             `json:"from_year"`
+	ToYear     int             `json:"to_year"`
+	Total      int             `json:"total"`
+	Movies     []MovieResponse `json:"movies"`
+	Pagination *Pagination     `json:"pagination,omitempty"`
+}
+
+// SearchSuggestions - подсказки для поиска без результатов
+type SearchSuggestions struct {
+	DidYouMean     []string `json:"did_you_mean"`
+	RelatedQueries []string `json:"related_queries"`
+}
+
+// Pagination - параметры возвращённой страницы списка и размер всего списка
+type Pagination struct {
+	Limit      int `json:"limit"`
+	Offset     int `json:"offset"`
+	Total      int `json:"total"`       // элементов во всём списке с учётом фильтров
+	TotalPages int `json:"total_pages"` // страниц размера limit
+}
+
+// MergeMoviesRequest - запрос на слияние фильма-дубликата с основным фильмом
+type MergeMoviesRequest struct {
+	KeepID      int `json:"keep_id" binding:"required"`
+	DuplicateID int `json:"duplicate_id" binding:"required"`
+}
+
+// MovieMergeResponse - результат слияния фильма-дубликата
+type MovieMergeResponse struct {
+	Movie            MovieResponse `json:"movie"`
+	DuplicateID      int           `json:"duplicate_id"`
+	ActorsReassigned int           `json:"actors_reassigned"`
+}
+
+// BulkDeleteMoviesRequest - запрос на удаление нескольких фильмов
+type BulkDeleteMoviesRequest struct {
+	IDs []int `json:"ids"`
+}
+
+// BulkUpdateMoviesRequest - запрос на применение одних и тех же изменений к нескольким фильмам
+type BulkUpdateMoviesRequest struct {
+	IDs     []int            `json:"ids"`
+	Changes BulkMovieChanges `json:"changes"`
+}
+
+// BulkMovieChanges - поля, которые можно изменить массово
+type BulkMovieChanges struct {
+	Rating           *float64 `json:"rating,omitempty"`
+	OriginalLanguage *string  `json:"original_language,omitempty"`
+	Country          *string  `json:"country,omitempty"`
+}
+
+// BulkOperationResponse - итог массовой операции с результатом по каждому фильму
+type BulkOperationResponse struct {
+	Succeeded int              `json:"succeeded"`
+	Failed    int              `json:"failed"`
+	Results   []BulkItemResult `json:"results"`
+}
+
+// BulkItemResult - результат массовой операции для одного фильма.
+// Для неудачных элементов Code и Error совпадают с полями code и detail ответа об ошибке
+type BulkItemResult struct {
+	ID     int    `json:"id"`
+	Status string `json:"status"` // ok или failed
+	Code   string `json:"code,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// MovieImportResponse - фильм, импортированный из внешнего каталога
+type MovieImportResponse struct {
+	Movie         MovieResponse `json:"movie"`
+	ActorsCreated int           `json:"actors_created"`
+
+	// ID созданных импортом актёров. В ответ не попадают: их удаляет откат импорта,
+	// если событие movie_created не удалось поставить в очередь
+	CreatedActorIDs []int `json:"-"`
+}
+
+// MovieUpsertResponse - фильм после PUT /movies/external/:externalId и что с ним сделано
+type MovieUpsertResponse struct {
+	Action string        `json:"action"` // created, updated или unchanged
+	Movie  MovieResponse `json:"movie"`
 }
 
 // DTO для поиска и фильтрации фильмов
@@ -79,26 +270,231 @@ type ActorWithFilms struct {
 }
 
 type ActorsWithFilmsListResponse struct {
-	Actors []ActorWithFilms `json:"actors"`
+	Actors     []ActorWithFilms `json:"actors"`
+	Pagination *Pagination      `json:"pagination,omitempty"`
 }
 
 // MovieWithActorsRequest - запрос на создание фильма с актёрами
 type MovieWithActorsRequest struct {
-	Title       string  `json:"title" binding:"required"`
-	Description string  `json:"description"`
-	ReleaseYear int     `json:"release_year" binding:"required"`
-	Rating      float64 `json:"rating" binding:"required"`
-	ActorIDs    []int   `json:"actor_ids" binding:"required,min=1"`
+	Title            string  `json:"title" binding:"required"`
+	Description      string  `json:"description"`
+	ReleaseYear      int     `json:"release_year" binding:"required"`
+	ReleaseDate      string  `json:"release_date,omitempty"` // YYYY-MM-DD
+	Rating           float64 `json:"rating" binding:"required"`
+	ActorIDs         []int   `json:"actor_ids" binding:"required,min=1"`
+	OriginalLanguage string  `json:"original_language,omitempty"` // ISO 639-1
+	Country          string  `json:"country,omitempty"`           // ISO 3166-1 alpha-2
+	RuntimeMinutes   int     `json:"runtime_minutes,omitempty"`
+}
+
+// FullMovieRequest - запрос на создание фильма вместе с составом (POST /movies/full).
+// Актёры из actors ищутся в каталоге по имени и дате рождения, недостающие создаются;
+// actor_ids ссылаются на уже существующих актёров и добавляются без роли
+type FullMovieRequest struct {
+	Title            string                 `json:"title" binding:"required"`
+	Description      string                 `json:"description"`
+	ReleaseYear      int                    `json:"release_year" binding:"required"`
+	ReleaseDate      string                 `json:"release_date,omitempty"` // YYYY-MM-DD
+	Rating           float64                `json:"rating"`
+	OriginalLanguage string                 `json:"original_language,omitempty"` // ISO 639-1
+	Country          string                 `json:"country,omitempty"`           // ISO 3166-1 alpha-2
+	RuntimeMinutes   int                    `json:"runtime_minutes,omitempty"`
+	Actors           []NewCastMemberRequest `json:"actors" binding:"dive"`
+	ActorIDs         []int                  `json:"actor_ids,omitempty"`
+	Force            bool                   `json:"-"` // из параметра ?force=true, как у POST /movies
+}
+
+// NewCastMemberRequest - актёр в составе создаваемого фильма, заданный целиком
+type NewCastMemberRequest struct {
+	Name          string `json:"name" binding:"required"`
+	Gender        string `json:"gender" binding:"required"`
+	BirthDate     string `json:"birth_date" binding:"required"` // YYYY-MM-DD
+	CharacterName string `json:"character_name,omitempty"`
+	BillingOrder  int    `json:"billing_order,omitempty"`
 }
 
-// UpdateMovieActorsRequest - запрос на обновление списка актёров фильма
+// FullMovieResponse - фильм, созданный вместе с составом: актёры отдаются полностью,
+// с ролями и в порядке титров
+type FullMovieResponse struct {
+	Movie         MovieResponse   `json:"movie"`
+	Actors        []ActorResponse `json:"actors"`
+	ActorsCreated int             `json:"actors_created"` // сколько актёров не было в каталоге
+
+	// ID созданных вместе с фильмом актёров. В ответ не попадают: их удаляет откат
+	// создания, если событие movie_created не удалось поставить в очередь
+	CreatedActorIDs []int `json:"-"`
+}
+
+// UpdateMovieActorsRequest - запрос на замену состава фильма. Актёры из actor_ids
+// добавляются без роли; для ролей и порядка в титрах используется cast
 type UpdateMovieActorsRequest struct {
-	ActorIDs []int `json:"actor_ids" binding:"required,min=1"`
+	ActorIDs []int               `json:"actor_ids,omitempty"`
+	Cast     []CastMemberRequest `json:"cast,omitempty"`
+}
+
+// CastMemberRequest - актёр в составе фильма
+type CastMemberRequest struct {
+	ActorID       int    `json:"actor_id"`
+	CharacterName string `json:"character_name,omitempty"`
+	BillingOrder  int    `json:"billing_order,omitempty"` // место в титрах начиная с 1; 0 — не задано
+}
+
+// AddActorToMovieRequest - необязательное тело запроса на добавление актёра в фильм
+type AddActorToMovieRequest struct {
+	CharacterName string `json:"character_name,omitempty"`
+	BillingOrder  int    `json:"billing_order,omitempty"`
+}
+
+// ChangeMovieActorsRequest - добавление и удаление актёров без замены всего состава.
+// Уже входящие в состав актёры из add и отсутствующие в нём актёры из remove пропускаются
+type ChangeMovieActorsRequest struct {
+	Add    []CastMemberRequest `json:"add,omitempty"`
+	Remove []int               `json:"remove,omitempty"` // ID актёров
+}
+
+// ReorderMovieActorsRequest - порядок актёров в титрах. Перечисленные актёры идут первыми
+// в указанном порядке, остальные - после них в прежнем порядке
+type ReorderMovieActorsRequest struct {
+	ActorIDs []int `json:"actor_ids"`
+}
+
+// CastMemberResponse - актёр в составе фильма
+type CastMemberResponse struct {
+	ID            int    `json:"id"`
+	Name          string `json:"name"`
+	CharacterName string `json:"character_name,omitempty"`
+	BillingOrder  int    `json:"billing_order,omitempty"` // место в титрах; не заполняется, если не задано
+}
+
+// MovieCastResponse - страница состава фильма в порядке титров
+type MovieCastResponse struct {
+	MovieID    int                  `json:"movie_id"`
+	CastCount  int                  `json:"cast_count"` // актёров во всём составе, а не на странице
+	Cast       []CastMemberResponse `json:"cast"`
+	Pagination *Pagination          `json:"pagination,omitempty"`
 }
 
 // MovieActorsResponse - ответ со списком актёров фильма
 type MovieActorsResponse struct {
 	Actors []ActorResponse `json:"actors"`
+
+	// ID актёров, которых замена состава добавила в фильм и убрала из него. В ответ
+	// не попадают: по ним отправляются события movie_actor_added и movie_actor_removed
+	AddedActorIDs   []int `json:"-"`
+	RemovedActorIDs []int `json:"-"`
+}
+
+// RatingChangeResponse - изменение рейтинга фильма. old_rating отсутствует у начальной точки истории
+type RatingChangeResponse struct {
+	OldRating *float64  `json:"old_rating,omitempty"`
+	NewRating float64   `json:"new_rating"`
+	ChangedAt time.Time `json:"changed_at"`
+}
+
+// RatingHistoryResponse - история рейтинга фильма от старых изменений к новым
+type RatingHistoryResponse struct {
+	MovieID int                    `json:"movie_id"`
+	History []RatingChangeResponse `json:"history"`
+}
+
+// FieldChangeResponse - изменение поля между соседними версиями записи. Даты передаются
+// в формате YYYY-MM-DD, пустая дата — null; у первой версии old всегда null
+type FieldChangeResponse struct {
+	Field string      `json:"field"`
+	Old   interface{} `json:"old"`
+	New   interface{} `json:"new"`
+}
+
+// MovieVersionResponse - версия фильма: состояние после изменения и отличия от предыдущей версии.
+// У версии-удаления movie отсутствует
+type MovieVersionResponse struct {
+	Version   int                   `json:"version"`
+	ChangedAt time.Time             `json:"changed_at"`
+	Deleted   bool                  `json:"deleted,omitempty"`
+	Movie     *MovieResponse        `json:"movie,omitempty"`
+	Changes   []FieldChangeResponse `json:"changes"`
+}
+
+// MovieHistoryResponse - версии фильма от старых к новым
+type MovieHistoryResponse struct {
+	MovieID  int                    `json:"movie_id"`
+	Versions []MovieVersionResponse `json:"versions"`
+}
+
+// ActorVersionResponse - версия актёра: профиль после изменения и отличия от предыдущей версии.
+// У версии-удаления actor отсутствует
+type ActorVersionResponse struct {
+	Version   int                   `json:"version"`
+	ChangedAt time.Time             `json:"changed_at"`
+	Deleted   bool                  `json:"deleted,omitempty"`
+	Actor     *ActorResponse        `json:"actor,omitempty"`
+	Changes   []FieldChangeResponse `json:"changes"`
+}
+
+// ActorHistoryResponse - версии актёра от старых к новым
+type ActorHistoryResponse struct {
+	ActorID  int                    `json:"actor_id"`
+	Versions []ActorVersionResponse `json:"versions"`
+}
+
+// MovieRatingRequest - рейтинг фильма из внешнего источника в шкале источника:
+// до 10 для imdb, проценты до 100 для rotten_tomatoes
+type MovieRatingRequest struct {
+	Rating *float64 `json:"rating"`
+}
+
+// MovieRatingsResponse - рейтинги фильма по источникам и взвешенный рейтинг по шкале до 10.
+// display_rating отсутствует, если ни у одного источника с рейтингом нет веса
+type MovieRatingsResponse struct {
+	MovieID       int                `json:"movie_id"`
+	Ratings       map[string]float64 `json:"ratings"`
+	DisplayRating *float64           `json:"display_rating,omitempty"`
+}
+
+// AvailabilityRequest - окно доступности фильма в регионе. Даты в формате YYYY-MM-DD;
+// без available_until окно бессрочное
+type AvailabilityRequest struct {
+	Region         string `json:"region"`
+	AvailableFrom  string `json:"available_from"`
+	AvailableUntil string `json:"available_until,omitempty"`
+}
+
+// AvailabilityResponse - окно доступности фильма
+type AvailabilityResponse struct {
+	ID             int    `json:"id"`
+	Region         string `json:"region"`
+	AvailableFrom  string `json:"available_from"`
+	AvailableUntil string `json:"available_until,omitempty"`
+}
+
+// AvailabilityListResponse - окна доступности фильма по регионам
+type AvailabilityListResponse struct {
+	MovieID      int                    `json:"movie_id"`
+	Availability []AvailabilityResponse `json:"availability"`
+}
+
+// MovieTagsResponse - теги фильма в алфавитном порядке
+type MovieTagsResponse struct {
+	MovieID int      `json:"movie_id"`
+	Tags    []string `json:"tags"`
+}
+
+// MoviesByTagResponse - страница фильмов с тегом и их общее число
+type MoviesByTagResponse struct {
+	Tag        string          `json:"tag"`
+	Total      int             `json:"total"`
+	Movies     []MovieResponse `json:"movies"`
+	Pagination *Pagination     `json:"pagination,omitempty"`
+}
+
+// TagSuggestion — подсказка автодополнения тега и число фильмов с ним
+type TagSuggestion struct {
+	Name       string `json:"name"`
+	MovieCount int    `json:"movie_count"`
+}
+
+type TagSuggestionsResponse struct {
+	Tags []TagSuggestion `json:"tags"`
 }
 
 // ActorMoviesResponse - ответ со списком фильмов актёра
@@ -111,14 +507,124 @@ type ActorUpdate struct {
 	Name      *string    `json:"name,omitempty"`
 	Gender    *string    `json:"gender,omitempty"`
 	BirthDate *time.Time `json:"birth_date,omitempty"`
+	DeathDate *time.Time `json:"death_date,omitempty"`
 }
 
 // MovieUpdate используется для частичного обновления фильма
 type MovieUpdate struct {
-	Title       *string  `json:"title,omitempty"`
-	Description *string  `json:"description,omitempty"`
-	ReleaseYear *int     `json:"release_year,omitempty"`
-	Rating      *float64 `json:"rating,omitempty"`
+	Title            *string  `json:"title,omitempty"`
+	Description      *string  `json:"description,omitempty"`
+	ReleaseYear      *int     `json:"release_year,omitempty"`
+	ReleaseDate      *string  `json:"release_date,omitempty"` // YYYY-MM-DD
+	Rating           *float64 `json:"rating,omitempty"`
+	OriginalLanguage *string  `json:"original_language,omitempty"` // пустая строка сбрасывает значение
+	Country          *string  `json:"country,omitempty"`
+	RuntimeMinutes   *int     `json:"runtime_minutes,omitempty"` // 0 сбрасывает значение
+}
+
+// --- COLLECTION DTOs ---
+
+// CreateCollectionRequest - запрос на создание подборки; movie_ids задают порядок просмотра
+type CreateCollectionRequest struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	MovieIDs    []int  `json:"movie_ids"`
+}
+
+// UpdateCollectionRequest - запрос на обновление названия и описания подборки
+type UpdateCollectionRequest struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// SetCollectionMoviesRequest - новый упорядоченный список фильмов подборки
+type SetCollectionMoviesRequest struct {
+	MovieIDs []int `json:"movie_ids"`
+}
+
+// CollectionResponse - подборка; movies — фильмы в порядке просмотра (только для одной подборки)
+type CollectionResponse struct {
+	ID          int             `json:"id"`
+	Name        string          `json:"name"`
+	Description string          `json:"description"`
+	Movies      []MovieResponse `json:"movies,omitempty"`
+}
+
+type CollectionsListResponse struct {
+	Collections []CollectionResponse `json:"collections"`
+}
+
+// --- STATS DTOs ---
+
+// StatsResponse - агрегированная статистика каталога; average_rating округлён до сотых
+type StatsResponse struct {
+	MovieCount      int              `json:"movie_count"`
+	ActorCount      int              `json:"actor_count"`
+	AverageRating   float64          `json:"average_rating"`
+	MoviesPerDecade []DecadeCount    `json:"movies_per_decade"`
+	TopActors       []ActorFilmCount `json:"top_actors"`
+}
+
+// DecadeCount - число фильмов десятилетия; decade — первый год десятилетия
+type DecadeCount struct {
+	Decade int `json:"decade"`
+	Movies int `json:"movies"`
+}
+
+// ActorFilmCount - актёр и число фильмов с его участием
+type ActorFilmCount struct {
+	ID     int    `json:"id"`
+	Name   string `json:"name"`
+	Movies int    `json:"movies"`
+}
+
+// ActorStatsResponse - сводка по фильмам актёра; average_rating округлён до сотых и равен null,
+// если фильмов нет, first_year и latest_year не выводятся, если ни у одного фильма нет года
+type ActorStatsResponse struct {
+	ActorID       int      `json:"actor_id"`
+	MovieCount    int      `json:"movie_count"`
+	AverageRating *float64 `json:"average_rating"`
+	FirstYear     int      `json:"first_year,omitempty"`
+	LatestYear    int      `json:"latest_year,omitempty"`
+}
+
+// ReindexResponse - итог перестроения индексов поиска (результат задачи reindex)
+type ReindexResponse struct {
+	Indexes    []string `json:"indexes"`
+	DurationMS int64    `json:"duration_ms"`
+}
+
+// TopSearchesResponse - самые частые поисковые запросы за окно window, начиная с дня since
+type TopSearchesResponse struct {
+	Window         string       `json:"window"`
+	Since          string       `json:"since"`
+	Top            []SearchStat `json:"top"`
+	TopZeroResults []SearchStat `json:"top_zero_results"`
+}
+
+// SearchStat - нормализованный запрос, число поисков и сколько из них не дали результатов
+type SearchStat struct {
+	Query       string `json:"query"`
+	Searches    int64  `json:"searches"`
+	ZeroResults int64  `json:"zero_results"`
+}
+
+// SearchResponse - результаты общего поиска по фильмам и актёрам, по убыванию score
+type SearchResponse struct {
+	Query   string         `json:"query"`
+	Results []SearchResult `json:"results"`
+}
+
+// SearchResult - найденный фильм (type=movie, title и release_year) или актёр (type=actor, name).
+// Score - похожесть на запрос от 0 до 1
+type SearchResult struct {
+	Type        string  `json:"type"`
+	ID          int     `json:"id"`
+	Title       string  `json:"title,omitempty"`
+	Name        string  `json:"name,omitempty"`
+	Slug        string  `json:"slug,omitempty"`
+	ReleaseYear int     `json:"release_year,omitempty"`
+	Score       float64 `json:"score"`
 }
 
 // --- AUTH DTOs ---
@@ -144,3 +650,39 @@ type AuthResponse struct {
 	RefreshToken string `json:"refresh_token,omitempty"`
 	ExpiresIn    int64  `json:"expires_in"` // in seconds
 }
+
+// UserProfileResponse - учётная запись текущего пользователя
+type UserProfileResponse struct {
+	ID       int    `json:"id"`
+	Username string `json:"username"`
+	Email    string `json:"email"`
+	Role     string `json:"role"`
+}
+
+// UpdateProfileRequest - изменение email и (или) пароля; текущий пароль обязателен
+type UpdateProfileRequest struct {
+	CurrentPassword string  `json:"current_password" binding:"required"`
+	Email           *string `json:"email,omitempty" binding:"omitempty,email"`
+	NewPassword     *string `json:"new_password,omitempty" binding:"omitempty,min=6,max=64"`
+}
+
+// ForgotPasswordRequest - запрос ссылки сброса пароля на email
+type ForgotPasswordRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+// ResetPasswordRequest - новый пароль и токен из письма
+type ResetPasswordRequest struct {
+	Token       string `json:"token" binding:"required"`
+	NewPassword string `json:"new_password" binding:"required,min=6,max=64"`
+}
+
+// ChangeRoleRequest - новая роль пользователя: user, moderator или admin
+type ChangeRoleRequest struct {
+	Role string `json:"role" binding:"required"`
+}
+
+// DeleteAccountRequest - подтверждение удаления учётной записи паролем
+type DeleteAccountRequest struct {
+	CurrentPassword string `json:"current_password" binding:"required"`
+}
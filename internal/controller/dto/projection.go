@@ -0,0 +1,119 @@
+package dto
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+)
+
+// Projection - поля ответа, выбранные параметром fields (GET /movies?fields=id,title,rating).
+// Клиентам, которые показывают только список, не нужно получать описание и состав каждого фильма.
+// Пустая проекция оставляет ответ без изменений
+type Projection []string
+
+// MovieFields - поля фильма, которые можно выбрать параметром fields
+var MovieFields = jsonFieldNames(reflect.TypeOf(MovieResponse{}))
+
+// ProjectedMoviesListResponse - MoviesListResponse, в котором у фильмов оставлены только поля проекции
+type ProjectedMoviesListResponse struct {
+	Movies      []map[string]json.RawMessage `json:"movies"`
+	Pagination  *Pagination                  `json:"pagination,omitempty"`
+	NextCursor  string                       `json:"next_cursor,omitempty"`
+	Suggestions *SearchSuggestions           `json:"suggestions,omitempty"`
+}
+
+// ParseProjection разбирает список полей через запятую. Пустые элементы и повторы пропускаются;
+// каждое поле не из allowed описывается ошибкой с индексом в списке
+func ParseProjection(raw string, allowed []string) (Projection, error) {
+	if strings.TrimSpace(raw) == "" {
+		return nil, nil
+	}
+	known := make(map[string]bool, len(allowed))
+	for _, name := range allowed {
+		known[name] = true
+	}
+
+	var projection Projection
+	var errs ValidationErrors
+	seen := make(map[string]bool)
+	for i, name := range strings.Split(raw, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		if !known[name] {
+			fieldErr := NewFieldError(KeyListFieldsUnknown)
+			index := i
+			fieldErr.Index = &index
+			errs = append(errs, fieldErr)
+			continue
+		}
+		projection = append(projection, name)
+	}
+	if err := errs.Err(); err != nil {
+		return nil, err
+	}
+	return projection, nil
+}
+
+// Apply оставляет в сериализованном item только поля проекции. Пустая проекция возвращает item как есть
+func (p Projection) Apply(item interface{}) (interface{}, error) {
+	if len(p) == 0 {
+		return item, nil
+	}
+	return p.project(item)
+}
+
+// ApplyMovies оставляет у каждого фильма списка только поля проекции
+func (p Projection) ApplyMovies(resp MoviesListResponse) (interface{}, error) {
+	if len(p) == 0 {
+		return resp, nil
+	}
+	projected := ProjectedMoviesListResponse{
+		Movies:      make([]map[string]json.RawMessage, 0, len(resp.Movies)),
+		Pagination:  resp.Pagination,
+		NextCursor:  resp.NextCursor,
+		Suggestions: resp.Suggestions,
+	}
+	for _, movie := range resp.Movies {
+		fields, err := p.project(movie)
+		if err != nil {
+			return nil, err
+		}
+		projected.Movies = append(projected.Movies, fields)
+	}
+	return projected, nil
+}
+
+// project сериализует item и выбирает из него поля проекции. Поля, опущенные из-за omitempty,
+// в результат не попадают, как и в полном ответе
+func (p Projection) project(item interface{}) (map[string]json.RawMessage, error) {
+	payload, err := json.Marshal(item)
+	if err != nil {
+		return nil, err
+	}
+	var all map[string]json.RawMessage
+	if err := json.Unmarshal(payload, &all); err != nil {
+		return nil, err
+	}
+	fields := make(map[string]json.RawMessage, len(p))
+	for _, name := range p {
+		if value, ok := all[name]; ok {
+			fields[name] = value
+		}
+	}
+	return fields, nil
+}
+
+// jsonFieldNames возвращает имена полей структуры в JSON
+func jsonFieldNames(typ reflect.Type) []string {
+	names := make([]string, 0, typ.NumField())
+	for i := 0; i < typ.NumField(); i++ {
+		name, _, _ := strings.Cut(typ.Field(i).Tag.Get("json"), ",")
+		if name != "" && name != "-" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
@@ -0,0 +1,271 @@
+package dto
+
+import (
+	"strconv"
+	"strings"
+
+	"cinematique/internal/apperror"
+	"cinematique/internal/i18n"
+)
+
+// FieldError - ошибка валидации одного поля запроса. Key - машиночитаемый ключ
+// (например, movie.title.too_long), по которому клиент может показать свой локализованный текст.
+// Для ошибок разбора тела Index указывает элемент массива, а Expected - ожидаемый тип
+// или нарушенное правило (integer, min=1)
+type FieldError struct {
+	Field    string `json:"field"`
+	Key      string `json:"key"`
+	Message  string `json:"message"`
+	Index    *int   `json:"index,omitempty"`
+	Expected string `json:"expected,omitempty"`
+}
+
+// ValidationErrors - ошибки валидации по полям запроса
+type ValidationErrors []FieldError
+
+// Error возвращает ошибки в виде "поле: сообщение" (для элемента массива - "поле[индекс]: сообщение"),
+// разделённые точкой с запятой
+func (e ValidationErrors) Error() string {
+	parts := make([]string, 0, len(e))
+	for _, fieldErr := range e {
+		field := fieldErr.Field
+		if fieldErr.Index != nil {
+			field += "[" + strconv.Itoa(*fieldErr.Index) + "]"
+		}
+		parts = append(parts, field+": "+fieldErr.Message)
+	}
+	return strings.Join(parts, "; ")
+}
+
+// Err возвращает nil, если ошибок нет, иначе сами ошибки
+func (e ValidationErrors) Err() error {
+	if len(e) == 0 {
+		return nil
+	}
+	return e
+}
+
+// Add добавляет ошибку поля по ключу из каталога ValidationKeys
+func (e *ValidationErrors) Add(key string) {
+	*e = append(*e, NewFieldError(key))
+}
+
+// AppError представляет ошибки валидации в ответе API: список ошибок по полям
+// передаётся в поле errors
+func (e ValidationErrors) AppError() *apperror.Error {
+	appErr := apperror.Validation(CodeValidationFailed, e.Error())
+	appErr.Extensions = map[string]interface{}{"errors": e}
+	return appErr
+}
+
+// Localize переводит сообщения ошибок полей на язык lang по их ключам
+func (e ValidationErrors) Localize(lang string) interface{} {
+	localized := make([]FieldError, len(e))
+	for i, fieldErr := range e {
+		fieldErr.Message = i18n.Translate(lang, fieldErr.Key, fieldErr.Message)
+		localized[i] = fieldErr
+	}
+	return localized
+}
+
+// CodeValidationFailed - код ошибки ответа с ошибками валидации полей
+const CodeValidationFailed = "validation_failed"
+
+// ValidationKey - запись каталога ключей ошибок валидации
+type ValidationKey struct {
+	Key     string `json:"key"`
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// Ключи ошибок валидации
+const (
+	KeyMovieTitleRequired      = "movie.title.required"
+	KeyMovieTitleTooLong       = "movie.title.too_long"
+	KeyMovieDescriptionTooLong = "movie.description.too_long"
+	KeyMovieRatingOutOfRange   = "movie.rating.out_of_range"
+	KeyMovieReleaseDateInvalid = "movie.release_date.invalid_format"
+	KeyMovieAsOfInvalid        = "movie.as_of.invalid_format"
+	KeyMovieIMDbIDInvalid      = "movie.imdb_id.invalid_format"
+	KeyMovieLanguageInvalid    = "movie.original_language.invalid"
+	KeyMovieCountryInvalid     = "movie.country.invalid"
+	KeyMovieSearchLanguage     = "movie.search.language_invalid"
+	KeyMovieSearchRegion       = "movie.search.region_invalid"
+	KeyMovieSearchTitleShort   = "movie.search.title_too_short"
+	KeyMovieSearchActorShort   = "movie.search.actor_name_too_short"
+	KeyMovieSearchAvailable    = "movie.search.available_invalid"
+	KeyMovieRuntimeInvalid     = "movie.runtime_minutes.invalid"
+	KeyMovieSearchRuntimeMin   = "movie.search.runtime_min_invalid"
+	KeyMovieSearchRuntimeMax   = "movie.search.runtime_max_invalid"
+	KeyMovieSearchRuntimeRange = "movie.search.runtime_range_invalid"
+	KeyMovieSearchHighlight    = "movie.search.highlight_invalid"
+	KeyMovieYearInvalid        = "movie.year.invalid"
+	KeyMovieDecadeInvalid      = "movie.decade.invalid"
+	KeyMovieExternalIDInvalid  = "movie.external_id.invalid"
+	KeyRatingSourceInvalid     = "rating.source.invalid"
+	KeyRatingValueRequired     = "rating.value.required"
+	KeyRatingValueOutOfRange   = "rating.value.out_of_range"
+	KeyAvailabilityRegion      = "availability.region.invalid"
+	KeyAvailabilityFrom        = "availability.available_from.invalid_format"
+	KeyAvailabilityUntil       = "availability.available_until.invalid_format"
+	KeyAvailabilityUntilBefore = "availability.available_until.before_from"
+	KeyBulkIDsRequired         = "bulk.ids.required"
+	KeyBulkIDsTooMany          = "bulk.ids.too_many"
+	KeyBulkIDInvalid           = "bulk.ids.invalid"
+	KeyBulkChangesRequired     = "bulk.changes.required"
+	KeyCastRequired            = "cast.required"
+	KeyCastActorInvalid        = "cast.actor_id.invalid"
+	KeyCastCharacterTooLong    = "cast.character_name.too_long"
+	KeyCastBillingInvalid      = "cast.billing_order.invalid"
+	KeyFullCastRequired        = "cast.full.required"
+	KeyCastOrderRequired       = "cast.order.required"
+	KeyCastOrderInvalid        = "cast.order.invalid"
+	KeyCastOrderDuplicate      = "cast.order.duplicate"
+	KeyCastChangeRequired      = "cast.change.required"
+	KeyCastRemoveInvalid       = "cast.remove.invalid"
+	KeyCastChangeConflict      = "cast.change.conflict"
+	KeyActorNameLength         = "actor.name.length"
+	KeyActorGenderInvalid      = "actor.gender.invalid"
+	KeyActorBirthDateInvalid   = "actor.birth_date.invalid_format"
+	KeyActorBirthDateInFuture  = "actor.birth_date.in_future"
+	KeyActorBirthDateTooEarly  = "actor.birth_date.too_early"
+	KeyActorDeathDateInvalid   = "actor.death_date.invalid_format"
+	KeyActorDeathDateInFuture  = "actor.death_date.in_future"
+	KeyActorDeathBeforeBirth   = "actor.death_date.before_birth"
+	KeyActorPhotoTooLarge      = "actor.photo.too_large"
+	KeyActorPhotoUnsupported   = "actor.photo.unsupported_type"
+	KeyActorSearchNameRequired = "actor.search.name_required"
+	KeyActorSearchNameShort    = "actor.search.name_too_short"
+	KeyActorLivingInvalid      = "actor.list.living_invalid"
+	KeyActorBirthMonthInvalid  = "actor.birthdays.month_invalid"
+	KeyActorSuggestQRequired   = "actor.suggest.q_required"
+	KeyActorSuggestLimit       = "actor.suggest.limit_invalid"
+	KeyActorOrphanConfirm      = "actor.orphans.confirm_count_invalid"
+	KeyActorMoviesPerActor     = "actor.with_movies.movies_per_actor_invalid"
+	KeyActorImportFile         = "actor.import.file.invalid"
+	KeyActorImportEmpty        = "actor.import.file.empty"
+	KeyActorImportTooManyRows  = "actor.import.file.too_many_rows"
+	KeyActorImportDuplicate    = "actor.import.duplicate_row"
+	KeyActorImportExists       = "actor.import.already_exists"
+	KeyStatsWindowInvalid      = "stats.window.invalid"
+	KeySearchQRequired         = "search.q.required"
+	KeySearchQTooShort         = "search.q.too_short"
+	KeyCollectionNameLength    = "collection.name.length"
+	KeyCollectionDescTooLong   = "collection.description.too_long"
+	KeyTagInvalid              = "tag.name.invalid"
+	KeyTagSuggestQRequired     = "tag.suggest.q_required"
+	KeyTagSuggestLimit         = "tag.suggest.limit_invalid"
+	KeyListSortEmptyField      = "list.sort.empty_field"
+	KeyListLimitInvalid        = "list.limit.invalid"
+	KeyListLimitTooLarge       = "list.limit.too_large"
+	KeyListOffsetInvalid       = "list.offset.invalid"
+	KeyListCursorInvalid       = "list.cursor.invalid"
+	KeyListFieldsUnknown       = "list.fields.unknown"
+
+	// Ошибки разбора тела запроса; поле подставляется из тела
+	KeyRequestFieldRequired = "request.field.required"
+	KeyRequestFieldType     = "request.field.invalid_type"
+	KeyRequestFieldRange    = "request.field.out_of_range"
+	KeyRequestFieldInvalid  = "request.field.invalid"
+)
+
+// ValidationKeys - каталог всех ключей ошибок валидации с полем и английским сообщением.
+// Публикуется в OpenAPI-спецификации, чтобы клиенты могли подготовить свои переводы;
+// русские сообщения берутся из каталога пакета i18n
+var ValidationKeys = []ValidationKey{
+	{KeyMovieTitleRequired, "title", "must be 1-150 characters"},
+	{KeyMovieTitleTooLong, "title", "must be 1-150 characters"},
+	{KeyMovieDescriptionTooLong, "description", "too long (max 1000 characters)"},
+	{KeyMovieRatingOutOfRange, "rating", "must be between 0 and 10"},
+	{KeyMovieReleaseDateInvalid, "release_date", "must be in YYYY-MM-DD format"},
+	{KeyMovieAsOfInvalid, "as_of", "must be a date (YYYY-MM-DD) or RFC 3339 timestamp"},
+	{KeyMovieIMDbIDInvalid, "imdb_id", "must be an IMDb ID like tt0133093"},
+	{KeyMovieLanguageInvalid, "original_language", "must be an ISO 639-1 language code like en"},
+	{KeyMovieCountryInvalid, "country", "must be an ISO 3166-1 alpha-2 country code like US"},
+	{KeyMovieSearchLanguage, "language", "must be an ISO 639-1 language code like en"},
+	{KeyMovieSearchRegion, "region", "must be an ISO 3166-1 alpha-2 country code like DE"},
+	{KeyMovieSearchAvailable, "available", "must be true or false"},
+	{KeyMovieRuntimeInvalid, "runtime_minutes", "must be between 1 and 999 minutes"},
+	{KeyMovieSearchRuntimeMin, "runtime_min", "must be a whole number of minutes between 1 and 999"},
+	{KeyMovieSearchRuntimeMax, "runtime_max", "must be a whole number of minutes between 1 and 999"},
+	{KeyMovieSearchRuntimeRange, "runtime_max", "must not be less than runtime_min"},
+	{KeyMovieSearchHighlight, "highlight", "must be true or false"},
+	{KeyMovieSearchTitleShort, "title", "must be at least 2 characters"},
+	{KeyMovieSearchActorShort, "actorName", "must be at least 2 characters"},
+	{KeyMovieYearInvalid, "year", "must be a year from 1000 to 9999"},
+	{KeyMovieDecadeInvalid, "decade", "must be the first year of a decade like 1990 or 1990s"},
+	{KeyMovieExternalIDInvalid, "external_id", "must be 1-64 ASCII letters, digits, dots, underscores, colons or hyphens"},
+	{KeyRatingSourceInvalid, "source", "must be imdb or rotten_tomatoes"},
+	{KeyRatingValueRequired, "rating", "is required"},
+	{KeyRatingValueOutOfRange, "rating", "must be between 0 and the maximum of the source scale"},
+	{KeyAvailabilityRegion, "region", "must be an ISO 3166-1 alpha-2 country code like DE"},
+	{KeyAvailabilityFrom, "available_from", "must be in YYYY-MM-DD format"},
+	{KeyAvailabilityUntil, "available_until", "must be in YYYY-MM-DD format"},
+	{KeyAvailabilityUntilBefore, "available_until", "must not be earlier than available_from"},
+	{KeyBulkIDsRequired, "ids", "at least one movie ID is required"},
+	{KeyBulkIDsTooMany, "ids", "must not contain more than 500 IDs"},
+	{KeyBulkIDInvalid, "ids", "must contain only positive IDs"},
+	{KeyBulkChangesRequired, "changes", "at least one field to change is required"},
+	{KeyCastRequired, "cast", "at least one actor is required in actor_ids or cast"},
+	{KeyCastActorInvalid, "actor_id", "must be a positive actor ID"},
+	{KeyCastCharacterTooLong, "character_name", "too long (max 255 characters)"},
+	{KeyCastBillingInvalid, "billing_order", "must be a non-negative integer"},
+	{KeyFullCastRequired, "actors", "at least one actor is required in actors or actor_ids"},
+	{KeyCastOrderRequired, "actor_ids", "at least one actor ID is required"},
+	{KeyCastOrderInvalid, "actor_ids", "must contain only positive actor IDs"},
+	{KeyCastOrderDuplicate, "actor_ids", "must not list an actor twice"},
+	{KeyCastChangeRequired, "add", "at least one actor is required in add or remove"},
+	{KeyCastRemoveInvalid, "remove", "must contain only positive actor IDs"},
+	{KeyCastChangeConflict, "remove", "must not list an actor that is also in add"},
+	{KeyActorNameLength, "name", "must be 1-100 characters"},
+	{KeyActorGenderInvalid, "gender", "must be 'male', 'female' or 'other'"},
+	{KeyActorBirthDateInvalid, "birth_date", "must be in YYYY-MM-DD format"},
+	{KeyActorBirthDateInFuture, "birth_date", "must not be in the future"},
+	{KeyActorBirthDateTooEarly, "birth_date", "must not be earlier than 1900-01-01"},
+	{KeyActorDeathDateInvalid, "death_date", "must be in YYYY-MM-DD format"},
+	{KeyActorDeathDateInFuture, "death_date", "must not be in the future"},
+	{KeyActorDeathBeforeBirth, "death_date", "must be after birth_date"},
+	{KeyActorPhotoTooLarge, "photo", "file is larger than 5 MB"},
+	{KeyActorPhotoUnsupported, "photo", "only JPEG, PNG and WebP are supported"},
+	{KeyActorSearchNameRequired, "name", "search parameter is required"},
+	{KeyActorSearchNameShort, "name", "must be at least 2 characters"},
+	{KeyActorLivingInvalid, "living", "must be true or false"},
+	{KeyActorBirthMonthInvalid, "month", "must be a number from 1 to 12"},
+	{KeyActorSuggestQRequired, "q", "search parameter is required"},
+	{KeyActorSuggestLimit, "limit", "must be a number from 1 to 50"},
+	{KeyActorOrphanConfirm, "confirm_count", "must be a non-negative integer"},
+	{KeyActorMoviesPerActor, "movies_per_actor", "must be a number from 1 to 50"},
+	{KeyActorImportFile, "file", "must be a CSV file with a header row containing name, gender and birth_date"},
+	{KeyActorImportEmpty, "file", "must contain at least one actor row"},
+	{KeyActorImportTooManyRows, "file", "must contain at most 1000 actor rows"},
+	{KeyActorImportDuplicate, "name", "repeats an earlier row with the same name and birth date"},
+	{KeyActorImportExists, "name", "an actor with the same name and birth date already exists"},
+	{KeyStatsWindowInvalid, "window", "must be a number of days from 1d to 365d"},
+	{KeySearchQRequired, "q", "search parameter is required"},
+	{KeySearchQTooShort, "q", "must be at least 2 characters"},
+	{KeyCollectionNameLength, "name", "must be 1-150 characters"},
+	{KeyCollectionDescTooLong, "description", "too long (max 1000 characters)"},
+	{KeyTagInvalid, "tag", "must be 1-50 letters or digits, words separated by hyphens"},
+	{KeyTagSuggestQRequired, "q", "search parameter is required"},
+	{KeyTagSuggestLimit, "limit", "must be a number from 1 to 50"},
+	{KeyListSortEmptyField, "sort", "empty field name"},
+	{KeyListLimitInvalid, "limit", "must be a non-negative integer"},
+	{KeyListLimitTooLarge, "limit", "exceeds the maximum page size"},
+	{KeyListOffsetInvalid, "offset", "must be a non-negative integer"},
+	{KeyListCursorInvalid, "cursor", "must be a next_cursor value from a previous page"},
+	{KeyListFieldsUnknown, "fields", "unknown field"},
+	{KeyRequestFieldRequired, "*", "is required"},
+	{KeyRequestFieldType, "*", "has the wrong type"},
+	{KeyRequestFieldRange, "*", "is out of the allowed range"},
+	{KeyRequestFieldInvalid, "*", "is invalid"},
+}
+
+// NewFieldError создаёт ошибку поля по ключу из каталога ValidationKeys
+func NewFieldError(key string) FieldError {
+	for _, entry := range ValidationKeys {
+		if entry.Key == key {
+			return FieldError{Field: entry.Field, Key: entry.Key, Message: entry.Message}
+		}
+	}
+	return FieldError{Key: key, Message: key}
+}
@@ -0,0 +1,50 @@
+package controller
+
+import (
+	"cinematique/internal/auth"
+	"cinematique/internal/domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+// movieFieldPermissions — поля фильма, для изменения которых мало права catalog:write.
+// Остальные поля (название, описание и т.д.) может менять любая роль с правом записи
+var movieFieldPermissions = map[string]domain.Permission{
+	"rating": domain.PermissionEditRatings,
+}
+
+// authorizeMovieFields проверяет, что роль пользователя запроса может менять поля fields.
+// Иначе возвращает *domain.FieldsForbiddenError со всеми запрещёнными полями в порядке fields
+func authorizeMovieFields(ctx *gin.Context, fields ...string) error {
+	role := auth.CurrentRole(ctx)
+	var forbidden []string
+	for _, field := range fields {
+		if permission, ok := movieFieldPermissions[field]; ok && !domain.RoleHasPermission(role, permission) {
+			forbidden = append(forbidden, field)
+		}
+	}
+	if len(forbidden) > 0 {
+		return &domain.FieldsForbiddenError{Fields: forbidden}
+	}
+	return nil
+}
+
+// movieUpdateFields возвращает имена (как в JSON) полей, которые затрагивает частичное обновление.
+// Значения не сравниваются с текущими: переданное поле считается изменяемым
+func movieUpdateFields(update domain.MovieUpdate) []string {
+	var fields []string
+	add := func(set bool, name string) {
+		if set {
+			fields = append(fields, name)
+		}
+	}
+	add(update.Title != nil, "title")
+	add(update.Description != nil, "description")
+	add(update.ReleaseYear != nil, "release_year")
+	add(update.ReleaseDate != nil || update.ClearReleaseDate, "release_date")
+	add(update.Rating != nil, "rating")
+	add(update.OriginalLanguage != nil, "original_language")
+	add(update.Country != nil, "country")
+	add(update.RuntimeMinutes != nil, "runtime_minutes")
+	return fields
+}
@@ -0,0 +1,116 @@
+package controller
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+
+	"cinematique/internal/controller/dto"
+	"cinematique/internal/domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PageSize — размер страницы списка: Default отдаётся без ?limit=, больше Max запросить нельзя
+type PageSize struct {
+	Default int
+	Max     int
+}
+
+// ListDefaults — сортировка и размеры страниц списков по эндпоинтам. Задаются при развёртывании,
+// чтобы клиент не мог запросить страницу без ограничения размера
+type ListDefaults struct {
+	MovieSort   string   // сортировка GET /movies/sorted без sort, например rating:desc,title:asc
+	Movies      PageSize // GET /movies/sorted и фильмы по годам и десятилетиям
+	MovieCursor PageSize // GET /movies
+	Popular     PageSize // GET /movies/popular
+	Actors      PageSize // GET /actors, поиск актёров по имени и список актёров без фильмов
+	Cast        PageSize // GET /movies/:id/cast
+	Search      PageSize // GET /search
+}
+
+// DefaultListDefaults возвращает настройки списков, которые действуют без конфигурации
+func DefaultListDefaults() ListDefaults {
+	return ListDefaults{
+		MovieSort:   "rating:desc",
+		Movies:      PageSize{Default: 20, Max: 100},
+		MovieCursor: PageSize{Default: 50, Max: 100},
+		Popular:     PageSize{Default: 10, Max: 100},
+		Actors:      PageSize{Default: 20, Max: 100},
+		Cast:        PageSize{Default: 50, Max: 200},
+		Search:      PageSize{Default: 10, Max: 50},
+	}
+}
+
+// normalize заменяет незаданные и неверные значения значениями fallback
+func (l ListDefaults) normalize(fallback ListDefaults) ListDefaults {
+	if _, err := parseSortFields(l.MovieSort, "", ""); err != nil || l.MovieSort == "" {
+		if l.MovieSort != "" {
+			log.Printf("Invalid default movie sort %q, using %q", l.MovieSort, fallback.MovieSort)
+		}
+		l.MovieSort = fallback.MovieSort
+	}
+	l.Movies = l.Movies.normalize(fallback.Movies)
+	l.MovieCursor = l.MovieCursor.normalize(fallback.MovieCursor)
+	l.Popular = l.Popular.normalize(fallback.Popular)
+	l.Actors = l.Actors.normalize(fallback.Actors)
+	l.Cast = l.Cast.normalize(fallback.Cast)
+	l.Search = l.Search.normalize(fallback.Search)
+	return l
+}
+
+// normalize подставляет значения fallback вместо неположительных; Default не больше Max
+func (p PageSize) normalize(fallback PageSize) PageSize {
+	if p.Max <= 0 {
+		p.Max = fallback.Max
+	}
+	if p.Default <= 0 {
+		p.Default = fallback.Default
+	}
+	if p.Default > p.Max {
+		p.Default = p.Max
+	}
+	return p
+}
+
+// limit разбирает ?limit=: без параметра возвращается Default, значение больше Max отклоняется
+func (p PageSize) limit(ctx *gin.Context) (int, error) {
+	limit, err := parseNonNegativeQuery(ctx, "limit", dto.KeyListLimitInvalid)
+	if err != nil {
+		return 0, fmt.Errorf("validation error: %w", err)
+	}
+	if limit > p.Max {
+		fieldErr := dto.NewFieldError(dto.KeyListLimitTooLarge)
+		fieldErr.Expected = "max=" + strconv.Itoa(p.Max)
+		return 0, fmt.Errorf("validation error: %w", dto.ValidationErrors{fieldErr})
+	}
+	if limit == 0 {
+		limit = p.Default
+	}
+	return limit, nil
+}
+
+// defaultMovieSort возвращает сортировку фильмов из настроек; строка проверена в normalize
+func (l ListDefaults) defaultMovieSort() []domain.SortField {
+	fields, _ := parseSortFields(l.MovieSort, "", "")
+	return fields
+}
+
+// newPagination описывает страницу списка из total элементов; limit всегда положителен
+func newPagination(limit, offset, total int) *dto.Pagination {
+	return &dto.Pagination{
+		Limit:      limit,
+		Offset:     offset,
+		Total:      total,
+		TotalPages: (total + limit - 1) / limit,
+	}
+}
+
+// pageTotal возвращает число элементов во всём списке. Непустая неполная страница — последняя,
+// как и пустая первая, поэтому их total известен без подсчёта; иначе вызывается count
+func pageTotal(limit, offset, pageLen int, count func() (int, error)) (int, error) {
+	if (pageLen > 0 && pageLen < limit) || (pageLen == 0 && offset == 0) {
+		return offset + pageLen, nil
+	}
+	return count()
+}
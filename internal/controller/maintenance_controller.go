@@ -0,0 +1,30 @@
+package controller
+
+import (
+	"context"
+
+	"cinematique/internal/controller/dto"
+)
+
+// maintenanceController выполняет служебные операции над базой данных
+type maintenanceController struct {
+	maintenanceService ServiceMaintenance
+}
+
+// NewMaintenanceController создаёт контроллер служебных операций
+func NewMaintenanceController(maintenanceService ServiceMaintenance) *maintenanceController {
+	return &maintenanceController{maintenanceService: maintenanceService}
+}
+
+// Reindex перестраивает индексы поиска. Выполняется фоновой задачей, поэтому принимает
+// её контекст вместо контекста запроса; progress получает ход перестроения
+func (c *maintenanceController) Reindex(ctx context.Context, progress func(done, total int, step string)) (dto.ReindexResponse, error) {
+	result, err := c.maintenanceService.Reindex(ctx, progress)
+	if err != nil {
+		return dto.ReindexResponse{}, err
+	}
+	return dto.ReindexResponse{
+		Indexes:    result.Indexes,
+		DurationMS: result.Duration.Milliseconds(),
+	}, nil
+}
@@ -0,0 +1,39 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"cinematique/internal/controller/dto"
+	"cinematique/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubMaintenanceService передаёт progress один шаг и возвращает заданный итог
+type stubMaintenanceService struct {
+	result domain.ReindexResult
+	err    error
+}
+
+func (s stubMaintenanceService) Reindex(_ context.Context, progress func(done, total int, step string)) (domain.ReindexResult, error) {
+	progress(0, 1, "analyze")
+	return s.result, s.err
+}
+
+func TestMaintenanceController_Reindex(t *testing.T) {
+	service := stubMaintenanceService{result: domain.ReindexResult{Indexes: []string{"idx_films_title_trgm"}, Duration: 1500 * time.Millisecond}}
+	var steps []string
+	resp, err := NewMaintenanceController(service).Reindex(context.Background(), func(_, _ int, step string) {
+		steps = append(steps, step)
+	})
+	require.NoError(t, err)
+	assert.Equal(t, dto.ReindexResponse{Indexes: []string{"idx_films_title_trgm"}, DurationMS: 1500}, resp)
+	assert.Equal(t, []string{"analyze"}, steps)
+
+	_, err = NewMaintenanceController(stubMaintenanceService{err: errors.New("lock timeout")}).Reindex(context.Background(), func(int, int, string) {})
+	assert.EqualError(t, err, "lock timeout")
+}
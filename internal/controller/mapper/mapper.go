@@ -0,0 +1,368 @@
+// Package mapper преобразует доменные модели в DTO ответов API.
+// Все даты в ответах форматируются одинаково — DateLayout (YYYY-MM-DD)
+package mapper
+
+import (
+	"math"
+	"time"
+
+	"cinematique/internal/controller/dto"
+	"cinematique/internal/domain"
+)
+
+// DateLayout — формат дат (рождения актёра, выхода фильма) в запросах и ответах API
+const DateLayout = "2006-01-02"
+
+// FormatDate форматирует дату в DateLayout
+func FormatDate(t time.Time) string {
+	return t.Format(DateLayout)
+}
+
+// FormatOptionalDate форматирует необязательную дату; nil превращается в пустую строку
+func FormatOptionalDate(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return FormatDate(*t)
+}
+
+// ParseDate разбирает дату в формате DateLayout
+func ParseDate(value string) (time.Time, error) {
+	return time.Parse(DateLayout, value)
+}
+
+// Age возвращает число полных лет на дату now. Родившиеся 29 февраля
+// в невисокосный год становятся на год старше 1 марта
+func Age(birthDate, now time.Time) int {
+	if birthDate.IsZero() || now.Before(birthDate) {
+		return 0
+	}
+	age := now.Year() - birthDate.Year()
+	if now.Month() < birthDate.Month() || (now.Month() == birthDate.Month() && now.Day() < birthDate.Day()) {
+		age--
+	}
+	return age
+}
+
+// ageOn возвращает возраст актёра на дату now, а для умершего — возраст на дату смерти
+func ageOn(actor domain.Actor, now time.Time) int {
+	if actor.DeathDate != nil && actor.DeathDate.Before(now) {
+		return Age(actor.BirthDate, *actor.DeathDate)
+	}
+	return Age(actor.BirthDate, now)
+}
+
+// optionalTime возвращает момент в UTC или nil для нулевого времени
+func optionalTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	utc := t.UTC()
+	return &utc
+}
+
+// Actor конвертирует актёра в DTO. Адрес фотографии не заполняется:
+// он зависит от хранилища и выставляется контроллером
+func Actor(actor domain.Actor) dto.ActorResponse {
+	return dto.ActorResponse{
+		ID:            actor.ID,
+		Name:          actor.Name,
+		Gender:        actor.Gender,
+		BirthDate:     FormatDate(actor.BirthDate),
+		DeathDate:     FormatOptionalDate(actor.DeathDate),
+		Age:           ageOn(actor, time.Now()),
+		Slug:          actor.Slug,
+		CharacterName: actor.CharacterName,
+		BillingOrder:  actor.BillingOrder,
+		UpdatedAt:     optionalTime(actor.UpdatedAt),
+	}
+}
+
+// Actors конвертирует список актёров в DTO
+func Actors(actors []domain.Actor) []dto.ActorResponse {
+	responses := make([]dto.ActorResponse, 0, len(actors))
+	for _, actor := range actors {
+		responses = append(responses, Actor(actor))
+	}
+	return responses
+}
+
+// ActorPreviews конвертирует актёров фильма в краткие DTO; для пустого списка возвращает nil,
+// чтобы поле actors не попадало в JSON
+func ActorPreviews(actors []domain.Actor) []dto.ActorPreview {
+	if len(actors) == 0 {
+		return nil
+	}
+	previews := make([]dto.ActorPreview, 0, len(actors))
+	for _, actor := range actors {
+		previews = append(previews, dto.ActorPreview{ID: actor.ID, Name: actor.Name, CharacterName: actor.CharacterName})
+	}
+	return previews
+}
+
+// MovieCast конвертирует страницу состава фильма в DTO; total — число актёров во всём составе
+func MovieCast(movieID int, cast []domain.Actor, total int) dto.MovieCastResponse {
+	resp := dto.MovieCastResponse{MovieID: movieID, CastCount: total, Cast: make([]dto.CastMemberResponse, 0, len(cast))}
+	for _, actor := range cast {
+		resp.Cast = append(resp.Cast, dto.CastMemberResponse{
+			ID:            actor.ID,
+			Name:          actor.Name,
+			CharacterName: actor.CharacterName,
+			BillingOrder:  actor.BillingOrder,
+		})
+	}
+	return resp
+}
+
+// Movie конвертирует фильм в DTO
+func Movie(movie domain.Movie) dto.MovieResponse {
+	return dto.MovieResponse{
+		ID:               movie.ID,
+		Title:            movie.Title,
+		Description:      movie.Description,
+		ReleaseYear:      movie.ReleaseYear,
+		ReleaseDate:      FormatOptionalDate(movie.ReleaseDate),
+		Rating:           movie.Rating,
+		Ratings:          RatingsMap(movie.Ratings),
+		DisplayRating:    movie.DisplayRating,
+		ViewCount:        movie.ViewCount,
+		OriginalLanguage: movie.OriginalLanguage,
+		Country:          movie.Country,
+		RuntimeMinutes:   movie.RuntimeMinutes,
+		Slug:             movie.Slug,
+		ExternalID:       movie.ExternalID,
+		Actors:           ActorPreviews(movie.Actors),
+		UpdatedAt:        optionalTime(movie.UpdatedAt),
+	}
+}
+
+// Movies конвертирует список фильмов в DTO
+func Movies(movies []domain.Movie) []dto.MovieResponse {
+	responses := make([]dto.MovieResponse, 0, len(movies))
+	for _, movie := range movies {
+		responses = append(responses, Movie(movie))
+	}
+	return responses
+}
+
+// RatingsMap конвертирует рейтинги фильма по источникам в словарь источник → рейтинг;
+// без рейтингов возвращает nil, и поле не попадает в ответ
+func RatingsMap(ratings []domain.MovieRating) map[string]float64 {
+	if len(ratings) == 0 {
+		return nil
+	}
+	bySource := make(map[string]float64, len(ratings))
+	for _, rating := range ratings {
+		bySource[rating.Source] = rating.Rating
+	}
+	return bySource
+}
+
+// MovieRatings конвертирует рейтинги фильма по источникам в DTO
+func MovieRatings(movieID int, ratings []domain.MovieRating, display *float64) dto.MovieRatingsResponse {
+	resp := dto.MovieRatingsResponse{MovieID: movieID, Ratings: RatingsMap(ratings), DisplayRating: display}
+	if resp.Ratings == nil {
+		resp.Ratings = map[string]float64{}
+	}
+	return resp
+}
+
+// RatingHistory конвертирует историю рейтинга фильма в DTO
+func RatingHistory(movieID int, history []domain.RatingChange) dto.RatingHistoryResponse {
+	resp := dto.RatingHistoryResponse{MovieID: movieID, History: make([]dto.RatingChangeResponse, 0, len(history))}
+	for _, change := range history {
+		resp.History = append(resp.History, dto.RatingChangeResponse{
+			OldRating: change.OldRating,
+			NewRating: change.NewRating,
+			ChangedAt: change.ChangedAt.UTC(),
+		})
+	}
+	return resp
+}
+
+// MovieHistory конвертирует версии фильма в DTO
+func MovieHistory(movieID int, versions []domain.MovieVersion) dto.MovieHistoryResponse {
+	resp := dto.MovieHistoryResponse{MovieID: movieID, Versions: make([]dto.MovieVersionResponse, 0, len(versions))}
+	for _, version := range versions {
+		item := dto.MovieVersionResponse{
+			Version:   version.Version,
+			ChangedAt: version.ChangedAt.UTC(),
+			Deleted:   version.Deleted,
+			Changes:   FieldChanges(version.Changes),
+		}
+		if !version.Deleted {
+			movie := Movie(version.Movie)
+			item.Movie = &movie
+		}
+		resp.Versions = append(resp.Versions, item)
+	}
+	return resp
+}
+
+// ActorHistory конвертирует версии актёра в DTO
+func ActorHistory(actorID int, versions []domain.ActorVersion) dto.ActorHistoryResponse {
+	resp := dto.ActorHistoryResponse{ActorID: actorID, Versions: make([]dto.ActorVersionResponse, 0, len(versions))}
+	for _, version := range versions {
+		item := dto.ActorVersionResponse{
+			Version:   version.Version,
+			ChangedAt: version.ChangedAt.UTC(),
+			Deleted:   version.Deleted,
+			Changes:   FieldChanges(version.Changes),
+		}
+		if !version.Deleted {
+			actor := Actor(version.Actor)
+			item.Actor = &actor
+		}
+		resp.Versions = append(resp.Versions, item)
+	}
+	return resp
+}
+
+// FieldChanges конвертирует отличия между версиями в DTO, приводя даты к формату DateLayout
+func FieldChanges(changes []domain.FieldChange) []dto.FieldChangeResponse {
+	resp := make([]dto.FieldChangeResponse, 0, len(changes))
+	for _, change := range changes {
+		resp = append(resp, dto.FieldChangeResponse{
+			Field: change.Field,
+			Old:   changeValue(change.Old),
+			New:   changeValue(change.New),
+		})
+	}
+	return resp
+}
+
+// changeValue форматирует даты из domain.FieldChange; пустая дата становится null
+func changeValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case time.Time:
+		if v.IsZero() {
+			return nil
+		}
+		return FormatDate(v)
+	case *time.Time:
+		if v == nil {
+			return nil
+		}
+		return FormatDate(*v)
+	}
+	return value
+}
+
+// Availability конвертирует окно доступности фильма в DTO
+func Availability(window domain.Availability) dto.AvailabilityResponse {
+	return dto.AvailabilityResponse{
+		ID:             window.ID,
+		Region:         window.Region,
+		AvailableFrom:  FormatDate(window.AvailableFrom),
+		AvailableUntil: FormatOptionalDate(window.AvailableUntil),
+	}
+}
+
+// AvailabilityList конвертирует окна доступности фильма в DTO
+func AvailabilityList(movieID int, windows []domain.Availability) dto.AvailabilityListResponse {
+	resp := dto.AvailabilityListResponse{MovieID: movieID, Availability: make([]dto.AvailabilityResponse, 0, len(windows))}
+	for _, window := range windows {
+		resp.Availability = append(resp.Availability, Availability(window))
+	}
+	return resp
+}
+
+// Collection конвертирует подборку в DTO; фильмы сохраняют порядок просмотра
+func Collection(collection domain.Collection) dto.CollectionResponse {
+	resp := dto.CollectionResponse{
+		ID:          collection.ID,
+		Name:        collection.Name,
+		Description: collection.Description,
+	}
+	if len(collection.Movies) > 0 {
+		resp.Movies = Movies(collection.Movies)
+	}
+	return resp
+}
+
+// Collections конвертирует список подборок в DTO
+func Collections(collections []domain.Collection) []dto.CollectionResponse {
+	responses := make([]dto.CollectionResponse, 0, len(collections))
+	for _, collection := range collections {
+		responses = append(responses, Collection(collection))
+	}
+	return responses
+}
+
+// Stats конвертирует статистику каталога в DTO
+func Stats(stats domain.CatalogStats) dto.StatsResponse {
+	resp := dto.StatsResponse{
+		MovieCount:      stats.MovieCount,
+		ActorCount:      stats.ActorCount,
+		AverageRating:   math.Round(stats.AverageRating*100) / 100,
+		MoviesPerDecade: make([]dto.DecadeCount, 0, len(stats.MoviesPerDecade)),
+		TopActors:       make([]dto.ActorFilmCount, 0, len(stats.TopActors)),
+	}
+	for _, d := range stats.MoviesPerDecade {
+		resp.MoviesPerDecade = append(resp.MoviesPerDecade, dto.DecadeCount{Decade: d.Decade, Movies: d.Movies})
+	}
+	for _, a := range stats.TopActors {
+		resp.TopActors = append(resp.TopActors, dto.ActorFilmCount{ID: a.ActorID, Name: a.Name, Movies: a.Movies})
+	}
+	return resp
+}
+
+// ActorStats конвертирует сводку по фильмам актёра в DTO
+func ActorStats(stats domain.ActorStats) dto.ActorStatsResponse {
+	resp := dto.ActorStatsResponse{
+		ActorID:    stats.ActorID,
+		MovieCount: stats.MovieCount,
+		FirstYear:  stats.FirstYear,
+		LatestYear: stats.LatestYear,
+	}
+	if stats.AverageRating != nil {
+		rounded := math.Round(*stats.AverageRating*100) / 100
+		resp.AverageRating = &rounded
+	}
+	return resp
+}
+
+// TopSearches конвертирует поисковую аналитику в DTO; window — окно из запроса
+func TopSearches(window string, analytics domain.SearchAnalytics) dto.TopSearchesResponse {
+	return dto.TopSearchesResponse{
+		Window:         window,
+		Since:          FormatDate(analytics.Since),
+		Top:            searchStats(analytics.Top),
+		TopZeroResults: searchStats(analytics.TopZeroResults),
+	}
+}
+
+func searchStats(stats []domain.SearchStat) []dto.SearchStat {
+	resp := make([]dto.SearchStat, 0, len(stats))
+	for _, s := range stats {
+		resp = append(resp, dto.SearchStat{Query: s.Query, Searches: s.Searches, ZeroResults: s.ZeroResults})
+	}
+	return resp
+}
+
+// Search конвертирует результаты общего поиска в DTO; название фильма и имя актёра
+// отдаются в разных полях
+func Search(query string, hits []domain.SearchHit) dto.SearchResponse {
+	resp := dto.SearchResponse{Query: query, Results: make([]dto.SearchResult, 0, len(hits))}
+	for _, hit := range hits {
+		result := dto.SearchResult{Type: hit.Kind, ID: hit.ID, Slug: hit.Slug, ReleaseYear: hit.ReleaseYear, Score: hit.Score}
+		if hit.Kind == domain.SearchHitMovie {
+			result.Title = hit.Name
+		} else {
+			result.Name = hit.Name
+		}
+		resp.Results = append(resp.Results, result)
+	}
+	return resp
+}
+
+// ActorWithFilms конвертирует актёра вместе с его фильмами в DTO
+func ActorWithFilms(actor domain.Actor) dto.ActorWithFilms {
+	return dto.ActorWithFilms{
+		ID:        actor.ID,
+		Name:      actor.Name,
+		Gender:    actor.Gender,
+		BirthDate: FormatDate(actor.BirthDate),
+		Movies:    Movies(actor.Movies),
+	}
+}
@@ -0,0 +1,158 @@
+package mapper
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"cinematique/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDates(t *testing.T) {
+	date := time.Date(1964, 9, 2, 15, 30, 0, 0, time.UTC)
+	assert.Equal(t, "1964-09-02", FormatDate(date))
+	assert.Equal(t, "1964-09-02", FormatOptionalDate(&date))
+	assert.Equal(t, "", FormatOptionalDate(nil))
+
+	parsed, err := ParseDate("1964-09-02")
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(1964, 9, 2, 0, 0, 0, 0, time.UTC), parsed)
+
+	_, err = ParseDate("1964-09-02T00:00:00Z")
+	assert.Error(t, err)
+}
+
+func TestActor(t *testing.T) {
+	actor := domain.Actor{
+		ID:        7,
+		Name:      "Keanu Reeves",
+		Gender:    "male",
+		BirthDate: time.Date(1964, 9, 2, 0, 0, 0, 0, time.UTC),
+		PhotoKey:  "actors/7/photo.jpg",
+	}
+
+	resp := Actor(actor)
+	assert.Equal(t, 7, resp.ID)
+	assert.Equal(t, "Keanu Reeves", resp.Name)
+	assert.Equal(t, "male", resp.Gender)
+	assert.Equal(t, "1964-09-02", resp.BirthDate)
+	assert.Equal(t, Age(actor.BirthDate, time.Now()), resp.Age)
+	assert.Empty(t, resp.PhotoURL, "адрес фото выставляет контроллер")
+
+	assert.Len(t, Actors([]domain.Actor{actor, actor}), 2)
+	assert.NotNil(t, Actors(nil), "пустой список сериализуется как []")
+
+	death := time.Date(2000, 9, 1, 0, 0, 0, 0, time.UTC)
+	actor.DeathDate = &death
+	resp = Actor(actor)
+	assert.Equal(t, "2000-09-01", resp.DeathDate)
+	assert.Equal(t, 35, resp.Age, "возраст на дату смерти")
+}
+
+func TestAge(t *testing.T) {
+	birth := time.Date(1964, 9, 2, 0, 0, 0, 0, time.UTC)
+	leapling := time.Date(2000, 2, 29, 0, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, 61, Age(birth, time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC)), "день рождения ещё не наступил")
+	assert.Equal(t, 62, Age(birth, time.Date(2026, 9, 2, 0, 0, 0, 0, time.UTC)))
+	assert.Equal(t, 62, Age(birth, time.Date(2026, 12, 31, 0, 0, 0, 0, time.UTC)))
+	assert.Equal(t, 24, Age(leapling, time.Date(2025, 2, 28, 0, 0, 0, 0, time.UTC)))
+	assert.Equal(t, 25, Age(leapling, time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)))
+	assert.Equal(t, 0, Age(time.Time{}, time.Now()), "дата рождения неизвестна")
+}
+
+func TestMovie(t *testing.T) {
+	release := time.Date(1999, 3, 31, 0, 0, 0, 0, time.UTC)
+	movie := domain.Movie{
+		ID:          1,
+		Title:       "The Matrix",
+		Description: "Sci-fi",
+		ReleaseYear: 1999,
+		ReleaseDate: &release,
+		Rating:      8.7,
+		ViewCount:   42,
+		Actors:      []domain.Actor{{ID: 7, Name: "Keanu Reeves"}},
+	}
+
+	resp := Movie(movie)
+	assert.Equal(t, "1999-03-31", resp.ReleaseDate)
+	assert.Equal(t, int64(42), resp.ViewCount)
+	require.Len(t, resp.Actors, 1)
+	assert.Equal(t, 7, resp.Actors[0].ID)
+	assert.Equal(t, "Keanu Reeves", resp.Actors[0].Name)
+
+	// Фильм без даты выхода и актёров: поля release_date и actors не попадают в JSON
+	movie.ReleaseDate = nil
+	movie.Actors = nil
+	data, err := json.Marshal(Movie(movie))
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "release_date")
+	assert.NotContains(t, string(data), "actors")
+	// updated_at заполняется только при чтении одного фильма
+	assert.NotContains(t, string(data), "updated_at")
+
+	movie.UpdatedAt = time.Date(2024, 5, 10, 15, 30, 0, 0, time.FixedZone("MSK", 3*3600))
+	resp = Movie(movie)
+	require.NotNil(t, resp.UpdatedAt)
+	assert.Equal(t, time.Date(2024, 5, 10, 12, 30, 0, 0, time.UTC), *resp.UpdatedAt)
+	assert.Equal(t, time.UTC, resp.UpdatedAt.Location())
+}
+
+func TestActorWithFilms(t *testing.T) {
+	release := time.Date(1999, 3, 31, 0, 0, 0, 0, time.UTC)
+	actor := domain.Actor{
+		ID:        7,
+		Name:      "Keanu Reeves",
+		Gender:    "male",
+		BirthDate: time.Date(1964, 9, 2, 0, 0, 0, 0, time.UTC),
+		Movies:    []domain.Movie{{ID: 1, Title: "The Matrix", ReleaseDate: &release}},
+	}
+
+	resp := ActorWithFilms(actor)
+	assert.Equal(t, "1964-09-02", resp.BirthDate)
+	require.Len(t, resp.Movies, 1)
+	assert.Equal(t, "1999-03-31", resp.Movies[0].ReleaseDate)
+}
+
+func TestCollection(t *testing.T) {
+	collection := domain.Collection{
+		ID:   3,
+		Name: "The Matrix Trilogy",
+		Movies: []domain.Movie{
+			{ID: 11, Title: "The Matrix Reloaded"},
+			{ID: 10, Title: "The Matrix"},
+		},
+	}
+
+	resp := Collection(collection)
+	require.Len(t, resp.Movies, 2)
+	assert.Equal(t, 11, resp.Movies[0].ID)
+	assert.Equal(t, 10, resp.Movies[1].ID)
+
+	// В списке подборок фильмы не загружаются и не попадают в JSON
+	data, err := json.Marshal(Collections([]domain.Collection{{ID: 3, Name: "The Matrix Trilogy"}}))
+	require.NoError(t, err)
+	assert.JSONEq(t, `[{"id":3,"name":"The Matrix Trilogy","description":""}]`, string(data))
+}
+
+func TestStats(t *testing.T) {
+	data, err := json.Marshal(Stats(domain.CatalogStats{
+		MovieCount:      3,
+		ActorCount:      5,
+		AverageRating:   7.666666,
+		MoviesPerDecade: []domain.DecadeCount{{Decade: 1990, Movies: 3}},
+		TopActors:       []domain.ActorFilmCount{{ActorID: 1, Name: "Keanu Reeves", Movies: 3}},
+	}))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"movie_count":3,"actor_count":5,"average_rating":7.67,`+
+		`"movies_per_decade":[{"decade":1990,"movies":3}],`+
+		`"top_actors":[{"id":1,"name":"Keanu Reeves","movies":3}]}`, string(data))
+
+	// Пустой каталог отдаёт пустые списки, а не null
+	data, err = json.Marshal(Stats(domain.CatalogStats{}))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"movie_count":0,"actor_count":0,"average_rating":0,"movies_per_decade":[],"top_actors":[]}`, string(data))
+}
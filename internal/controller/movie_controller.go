@@ -1,79 +1,176 @@
 package controller
 
 import (
+	"encoding/base64"
 	"errors"
 	"fmt"
 	"github.com/gin-gonic/gin"
+	"log"
+	"regexp"
+	"strconv"
 	"strings"
+	"time"
+	"unicode/utf8"
 
+	"cinematique/internal/apperror"
 	"cinematique/internal/controller/dto"
+	"cinematique/internal/controller/mapper"
 	"cinematique/internal/domain"
+	"cinematique/internal/isocode"
 )
 
 
 // movieController обрабатывает запросы, связанные с фильмами
 type movieController struct {
-	movieService ServiceMovie
+	movieService  ServiceMovie
+	searchService ServiceSearch // опционально: подсказки для поиска без результатов
+	lists         ListDefaults
 }
 
 // NewMovieController создаёт контроллер фильмов
 func NewMovieController(movieService ServiceMovie) *movieController {
 	return &movieController{
 		movieService: movieService,
+		lists:        DefaultListDefaults(),
 	}
 }
 
-// validateMovie проверяет валидность данных фильма
+// WithListDefaults задаёт сортировку и размеры страниц списков фильмов
+func (c *movieController) WithListDefaults(lists ListDefaults) *movieController {
+	c.lists = lists.normalize(DefaultListDefaults())
+	return c
+}
+
+// validateMovie проверяет валидность данных фильма и возвращает ошибки по всем полям
 func validateMovie(title, description string, rating float64) error {
+	var errs dto.ValidationErrors
+
 	title = strings.TrimSpace(title)
-	if len(title) < 1 || len(title) > 150 {
-		return fmt.Errorf("title: must be 1-150 characters")
+	if len(title) < 1 {
+		errs.Add(dto.KeyMovieTitleRequired)
+	} else if len(title) > 150 {
+		errs.Add(dto.KeyMovieTitleTooLong)
 	}
 
 	if len(description) > 1000 {
-		return fmt.Errorf("description: too long (max 1000 characters)")
+		errs.Add(dto.KeyMovieDescriptionTooLong)
 	}
 
 	if rating < 0 || rating > 10 {
-		return fmt.Errorf("rating: must be between 0 and 10")
+		errs.Add(dto.KeyMovieRatingOutOfRange)
 	}
 
-	return nil
+	return errs.Err()
 }
 
-// CreateMovie создаёт фильм
-func (c *movieController) CreateMovie(ctx *gin.Context, req dto.CreateMovieRequest) (dto.MovieResponse, error) {
-	// Валидация входных данных
+// parseReleaseDate разбирает дату выхода в формате YYYY-MM-DD; пустая строка означает «дата неизвестна»
+func parseReleaseDate(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	date, err := mapper.ParseDate(value)
+	if err != nil {
+		return nil, dto.ValidationErrors{dto.NewFieldError(dto.KeyMovieReleaseDateInvalid)}
+	}
+	return &date, nil
+}
+
+// normalizeLanguage приводит код языка к нижнему регистру и проверяет его по ISO 639-1.
+// Пустая строка допустима и означает «язык неизвестен»
+func normalizeLanguage(value, invalidKey string, errs *dto.ValidationErrors) string {
+	code := isocode.NormalizeLanguage(value)
+	if code != "" && !isocode.ValidLanguage(code) {
+		errs.Add(invalidKey)
+	}
+	return code
+}
+
+// normalizeCountry приводит код страны к верхнему регистру и проверяет его по ISO 3166-1 alpha-2.
+// Пустая строка допустима и означает «страна неизвестна»
+func normalizeCountry(value string, errs *dto.ValidationErrors) string {
+	code := isocode.NormalizeCountry(value)
+	if code != "" && !isocode.ValidCountry(code) {
+		errs.Add(dto.KeyMovieCountryInvalid)
+	}
+	return code
+}
+
+// maxRuntimeMinutes — граница продолжительности фильма в минутах (не включительно)
+const maxRuntimeMinutes = 1000
+
+// checkRuntime проверяет продолжительность фильма: 0 означает «неизвестна», иначе значение
+// должно быть положительным и меньше maxRuntimeMinutes
+func checkRuntime(minutes int, errs *dto.ValidationErrors) {
+	if minutes < 0 || minutes >= maxRuntimeMinutes {
+		errs.Add(dto.KeyMovieRuntimeInvalid)
+	}
+}
+
+// applyReleaseDate устанавливает дату выхода и, если год не задан, берёт его из даты
+func applyReleaseDate(movie *domain.Movie, date *time.Time) {
+	movie.ReleaseDate = date
+	if date != nil && movie.ReleaseYear == 0 {
+		movie.ReleaseYear = date.Year()
+	}
+}
+
+// movieFromCreateRequest проверяет поля фильма из запроса на создание и собирает domain.Movie
+func movieFromCreateRequest(req dto.CreateMovieRequest) (domain.Movie, error) {
 	if err := validateMovie(req.Title, req.Description, req.Rating); err != nil {
-		return dto.MovieResponse{}, fmt.Errorf("validation error: %w", err)
+		return domain.Movie{}, fmt.Errorf("validation error: %w", err)
+	}
+	releaseDate, err := parseReleaseDate(req.ReleaseDate)
+	if err != nil {
+		return domain.Movie{}, fmt.Errorf("validation error: %w", err)
+	}
+	var errs dto.ValidationErrors
+	language := normalizeLanguage(req.OriginalLanguage, dto.KeyMovieLanguageInvalid, &errs)
+	country := normalizeCountry(req.Country, &errs)
+	checkRuntime(req.RuntimeMinutes, &errs)
+	if err := errs.Err(); err != nil {
+		return domain.Movie{}, fmt.Errorf("validation error: %w", err)
 	}
 
 	movie := domain.Movie{
-		Title:       req.Title,
-		Description: req.Description,
-		ReleaseYear: req.ReleaseYear,
-		Rating:      req.Rating,
+		Title:            req.Title,
+		Description:      req.Description,
+		ReleaseYear:      req.ReleaseYear,
+		Rating:           req.Rating,
+		OriginalLanguage: language,
+		Country:          country,
+		RuntimeMinutes:   req.RuntimeMinutes,
+	}
+	applyReleaseDate(&movie, releaseDate)
+	return movie, nil
+}
+
+// CreateMovie создаёт фильм
+func (c *movieController) CreateMovie(ctx *gin.Context, req dto.CreateMovieRequest) (dto.MovieResponse, error) {
+	// Валидация входных данных
+	movie, err := movieFromCreateRequest(req)
+	if err != nil {
+		return dto.MovieResponse{}, err
 	}
 
 	// Создаем фильм и добавляем связи с актерами
-	id, err := c.movieService.Create(movie, req.ActorIDs)
+	id, err := c.movieService.Create(requestContext(ctx), movie, req.ActorIDs, req.Force)
 	if err != nil {
 		return dto.MovieResponse{}, err
 	}
 
 	// Получаем созданный фильм с актерами
-	createdMovie, err := c.movieService.GetByID(id)
+	createdMovie, err := c.movieService.GetByID(requestContext(ctx), id)
 	if err != nil {
 		return dto.MovieResponse{}, err
 	}
 
 	// Конвертируем в DTO
-	return c.toMovieResponse(createdMovie), nil
+	return mapper.Movie(createdMovie), nil
 }
 
 // GetMovieByID возвращает фильм по ID
 func (c *movieController) GetMovieByID(ctx *gin.Context, id int) (dto.MovieResponse, error) {
-	movie, err := c.movieService.GetByID(id)
+	movie, err := c.movieService.GetByID(requestContext(ctx), id)
 	if err != nil {
 		if errors.Is(err, domain.ErrMovieNotFound) {
 			return dto.MovieResponse{}, domain.ErrMovieNotFound
@@ -81,18 +178,59 @@ func (c *movieController) GetMovieByID(ctx *gin.Context, id int) (dto.MovieRespo
 		return dto.MovieResponse{}, fmt.Errorf("getting movie: %w", err)
 	}
 
-	return c.toMovieResponse(movie), nil
+	return mapper.Movie(movie), nil
+}
+
+// GetMovieBySlug возвращает фильм по slug
+func (c *movieController) GetMovieBySlug(ctx *gin.Context, slug string) (dto.MovieResponse, error) {
+	movie, err := c.movieService.GetBySlug(requestContext(ctx), slug)
+	if err != nil {
+		if errors.Is(err, domain.ErrMovieNotFound) {
+			return dto.MovieResponse{}, domain.ErrMovieNotFound
+		}
+		return dto.MovieResponse{}, fmt.Errorf("getting movie by slug: %w", err)
+	}
+	return mapper.Movie(movie), nil
+}
+
+// GetMovieByIDAsOf возвращает фильм в том виде, в каком он был на момент asOf.
+// asOf задаётся датой (YYYY-MM-DD, начало дня по UTC) или временем в RFC 3339
+func (c *movieController) GetMovieByIDAsOf(ctx *gin.Context, id int, asOf string) (dto.MovieResponse, error) {
+	moment, err := parseAsOf(asOf)
+	if err != nil {
+		return dto.MovieResponse{}, fmt.Errorf("validation error: %w", err)
+	}
+	movie, err := c.movieService.GetMovieAsOf(requestContext(ctx), id, moment)
+	if err != nil {
+		if errors.Is(err, domain.ErrMovieNotFound) {
+			return dto.MovieResponse{}, domain.ErrMovieNotFound
+		}
+		return dto.MovieResponse{}, fmt.Errorf("getting movie as of %s: %w", asOf, err)
+	}
+	return mapper.Movie(movie), nil
+}
+
+// parseAsOf разбирает момент времени для исторических запросов
+func parseAsOf(value string) (time.Time, error) {
+	if moment, err := time.Parse(time.RFC3339, value); err == nil {
+		return moment, nil
+	}
+	if date, err := mapper.ParseDate(value); err == nil {
+		return date, nil
+	}
+	return time.Time{}, dto.ValidationErrors{dto.NewFieldError(dto.KeyMovieAsOfInvalid)}
 }
 
 // UpdateMovie обновляет фильм
 func (c *movieController) UpdateMovie(ctx *gin.Context, id int, req dto.UpdateMovieRequest) (dto.MovieResponse, error) {
-	movie, err := c.movieService.GetByID(id)
+	movie, err := c.movieService.GetByID(requestContext(ctx), id)
 	if err != nil {
 		if errors.Is(err, domain.ErrMovieNotFound) {
 			return dto.MovieResponse{}, domain.ErrMovieNotFound
 		}
 		return dto.MovieResponse{}, fmt.Errorf("getting movie: %w", err)
 	}
+	before := movie
 
 	// Валидация обновляемых полей
 	title := movie.Title
@@ -112,6 +250,27 @@ func (c *movieController) UpdateMovie(ctx *gin.Context, id int, req dto.UpdateMo
 	if err := validateMovie(title, description, rating); err != nil {
 		return dto.MovieResponse{}, fmt.Errorf("validation error: %w", err)
 	}
+	if req.ReleaseDate != nil {
+		releaseDate, err := parseReleaseDate(*req.ReleaseDate)
+		if err != nil {
+			return dto.MovieResponse{}, fmt.Errorf("validation error: %w", err)
+		}
+		applyReleaseDate(&movie, releaseDate)
+	}
+	var errs dto.ValidationErrors
+	if req.OriginalLanguage != nil {
+		movie.OriginalLanguage = normalizeLanguage(*req.OriginalLanguage, dto.KeyMovieLanguageInvalid, &errs)
+	}
+	if req.Country != nil {
+		movie.Country = normalizeCountry(*req.Country, &errs)
+	}
+	if req.RuntimeMinutes != nil {
+		checkRuntime(*req.RuntimeMinutes, &errs)
+		movie.RuntimeMinutes = *req.RuntimeMinutes
+	}
+	if err := errs.Err(); err != nil {
+		return dto.MovieResponse{}, fmt.Errorf("validation error: %w", err)
+	}
 
 	// Обновляем только переданные поля
 	if req.Title != nil {
@@ -126,6 +285,10 @@ func (c *movieController) UpdateMovie(ctx *gin.Context, id int, req dto.UpdateMo
 	if req.Rating != nil {
 		movie.Rating = *req.Rating
 	}
+	// Клиенты часто присылают фильм целиком, поэтому проверяем только поля с новым значением
+	if err := authorizeMovieFields(ctx, before.ChangedFields(movie)...); err != nil {
+		return dto.MovieResponse{}, err
+	}
 
 	// Обновляем фильм и связи с актерами, если они были переданы
 	var actorIDs []int
@@ -133,119 +296,313 @@ func (c *movieController) UpdateMovie(ctx *gin.Context, id int, req dto.UpdateMo
 		actorIDs = *req.ActorIDs
 	}
 
-	err = c.movieService.Update(movie, actorIDs)
+	err = c.movieService.Update(requestContext(ctx), movie, actorIDs)
 	if err != nil {
 		return dto.MovieResponse{}, err
 	}
 
 	// Получаем обновленный фильм с актерами
-	updatedMovie, err := c.movieService.GetByID(id)
+	updatedMovie, err := c.movieService.GetByID(requestContext(ctx), id)
 	if err != nil {
 		return dto.MovieResponse{}, err
 	}
 
-	return c.toMovieResponse(updatedMovie), nil
+	return mapper.Movie(updatedMovie), nil
 }
 
 // DeleteMovie удаляет фильм
 func (c *movieController) DeleteMovie(ctx *gin.Context, id int) error {
-	if err := c.movieService.Delete(id); err != nil {
+	if err := c.movieService.Delete(requestContext(ctx), id); err != nil {
 		if errors.Is(err, domain.ErrMovieNotFound) {
 			return domain.ErrMovieNotFound
 		}
+		if errors.Is(err, domain.ErrMovieReferenced) {
+			return err
+		}
 		return fmt.Errorf("deleting movie: %w", err)
 	}
 	return nil
 }
 
-// ListMovies возвращает все фильмы
+// DeleteCreatedMovie откатывает создание фильма: удаляет его вместе с актёрами, созданными
+// в том же запросе
+func (c *movieController) DeleteCreatedMovie(ctx *gin.Context, movieID int, createdActorIDs []int) error {
+	if err := c.movieService.DeleteCreated(requestContext(ctx), movieID, createdActorIDs); err != nil {
+		if errors.Is(err, domain.ErrMovieNotFound) || errors.Is(err, domain.ErrMovieReferenced) {
+			return err
+		}
+		return fmt.Errorf("deleting created movie: %w", err)
+	}
+	return nil
+}
+
+// movieCursorPrefix отличает курсор фильмов от произвольной строки в base64
+const movieCursorPrefix = "movies:"
+
+// encodeMovieCursor кодирует ID последнего фильма страницы в непрозрачный курсор
+func encodeMovieCursor(lastID int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(movieCursorPrefix + strconv.Itoa(lastID)))
+}
+
+// decodeMovieCursor возвращает ID, после которого начинается страница; пустой курсор — первая страница
+func decodeMovieCursor(cursor string) (int, error) {
+	if cursor == "" {
+		return 0, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, dto.ValidationErrors{dto.NewFieldError(dto.KeyListCursorInvalid)}
+	}
+	idPart, ok := strings.CutPrefix(string(raw), movieCursorPrefix)
+	if !ok {
+		return 0, dto.ValidationErrors{dto.NewFieldError(dto.KeyListCursorInvalid)}
+	}
+	id, err := strconv.Atoi(idPart)
+	if err != nil || id < 0 {
+		return 0, dto.ValidationErrors{dto.NewFieldError(dto.KeyListCursorInvalid)}
+	}
+	return id, nil
+}
+
+// ListMovies возвращает страницу фильмов по возрастанию ID: без cursor (или с пустым) — первую,
+// иначе — следующую за cursor. Размер страницы задаёт ?limit= в пределах настроек; если страница
+// не последняя, ответ содержит next_cursor. Весь каталог одним ответом не отдаётся
 func (c *movieController) ListMovies(ctx *gin.Context) (dto.MoviesListResponse, error) {
-	movies, err := c.movieService.GetAll()
+	afterID, err := decodeMovieCursor(ctx.Query("cursor"))
+	if err != nil {
+		return dto.MoviesListResponse{}, fmt.Errorf("validation error: %w", err)
+	}
+	limit, err := c.lists.MovieCursor.limit(ctx)
 	if err != nil {
 		return dto.MoviesListResponse{}, err
 	}
 
-	response := dto.MoviesListResponse{
-		Movies: make([]dto.MovieResponse, 0, len(movies)),
+	movies, hasMore, err := c.movieService.GetMoviesAfterID(requestContext(ctx), afterID, limit)
+	if err != nil {
+		return dto.MoviesListResponse{}, err
+	}
+	resp := dto.MoviesListResponse{Movies: mapper.Movies(movies)}
+	if hasMore && len(movies) > 0 {
+		resp.NextCursor = encodeMovieCursor(movies[len(movies)-1].ID)
 	}
+	return resp, nil
+}
+
+// ExportMovies передаёт fn все фильмы каталога в формате ответа API
+func (c *movieController) ExportMovies(ctx *gin.Context, fn func(dto.MovieResponse) error) error {
+	return c.movieService.ExportMovies(requestContext(ctx), func(movie domain.Movie) error {
+		return fn(mapper.Movie(movie))
+	})
+}
+
+// WithSearch подключает сервис подсказок: поиск без результатов дополняется полем suggestions
+func (c *movieController) WithSearch(searchService ServiceSearch) *movieController {
+	c.searchService = searchService
+	return c
+}
+
+// minSearchQueryLength — наименьшая длина строки поиска в символах: фрагмент из одной буквы
+// совпадает почти с каждой записью каталога, а такой запрос дорого обходится базе
+const minSearchQueryLength = 2
 
-	for _, movie := range movies {
-		response.Movies = append(response.Movies, c.toMovieResponse(movie))
+// searchQueryTooShort сообщает, что строка поиска короче minSearchQueryLength
+func searchQueryTooShort(query string) bool {
+	return utf8.RuneCountInString(strings.TrimSpace(query)) < minSearchQueryLength
+}
+
+// parseMovieFilter разбирает фильтры поиска ?language=, ?country=, ?region=, ?available=,
+// ?runtime_min= и ?runtime_max=
+func parseMovieFilter(ctx *gin.Context) (domain.MovieFilter, error) {
+	var errs dto.ValidationErrors
+	filter := domain.MovieFilter{
+		OriginalLanguage: normalizeLanguage(ctx.Query("language"), dto.KeyMovieSearchLanguage, &errs),
+		Country:          normalizeCountry(ctx.Query("country"), &errs),
+		Region:           isocode.NormalizeCountry(ctx.Query("region")),
+	}
+	if filter.Region != "" && !isocode.ValidCountry(filter.Region) {
+		errs.Add(dto.KeyMovieSearchRegion)
+	}
+	// Доступность проверяется на сегодняшний день по UTC, как и даты окон
+	if value := ctx.Query("available"); value != "" {
+		available, err := strconv.ParseBool(value)
+		if err != nil {
+			errs.Add(dto.KeyMovieSearchAvailable)
+		} else {
+			filter.Available = &available
+			filter.AvailableOn = time.Now().UTC()
+		}
+	}
+	filter.RuntimeMin = parseRuntimeBound(ctx.Query("runtime_min"), dto.KeyMovieSearchRuntimeMin, &errs)
+	filter.RuntimeMax = parseRuntimeBound(ctx.Query("runtime_max"), dto.KeyMovieSearchRuntimeMax, &errs)
+	if filter.RuntimeMin > 0 && filter.RuntimeMax > 0 && filter.RuntimeMin > filter.RuntimeMax {
+		errs.Add(dto.KeyMovieSearchRuntimeRange)
+	}
+	if err := errs.Err(); err != nil {
+		return domain.MovieFilter{}, fmt.Errorf("validation error: %w", err)
 	}
+	return filter, nil
+}
 
-	return response, nil
+// parseRuntimeBound разбирает границу продолжительности в минутах. Пустое значение — без границы (0)
+func parseRuntimeBound(value, invalidKey string, errs *dto.ValidationErrors) int {
+	if value == "" {
+		return 0
+	}
+	minutes, err := strconv.Atoi(value)
+	if err != nil || minutes <= 0 || minutes >= maxRuntimeMinutes {
+		errs.Add(invalidKey)
+		return 0
+	}
+	return minutes
 }
 
-// SearchMoviesByTitle ищет фильмы по названию
+// SearchMoviesByTitle ищет фильмы по названию. Без названия, но с фильтром по языку,
+// стране или доступности возвращает все фильмы, подходящие под фильтр
 func (c *movieController) SearchMoviesByTitle(ctx *gin.Context) (dto.MoviesListResponse, error) {
+	filter, err := parseMovieFilter(ctx)
+	if err != nil {
+		return dto.MoviesListResponse{}, err
+	}
+	highlight, err := parseHighlight(ctx)
+	if err != nil {
+		return dto.MoviesListResponse{}, err
+	}
 	query := ctx.Query("title")
-	if query == "" {
-		return dto.MoviesListResponse{}, errors.New("title parameter is required")
+	if query == "" && filter.IsEmpty() {
+		return dto.MoviesListResponse{}, apperror.Validation("search_parameter_required", "title parameter is required")
+	}
+	if query != "" && searchQueryTooShort(query) {
+		return dto.MoviesListResponse{}, fmt.Errorf("validation error: %w", dto.ValidationErrors{dto.NewFieldError(dto.KeyMovieSearchTitleShort)})
 	}
-	movies, err := c.movieService.SearchMoviesByTitle(query)
+	movies, err := c.movieService.SearchMoviesByTitle(requestContext(ctx), query, filter)
 	if err != nil {
 		return dto.MoviesListResponse{}, err
 	}
-	return dto.MoviesListResponse{Movies: c.toMovieResponses(movies)}, nil
+	response := dto.MoviesListResponse{Movies: mapper.Movies(movies)}
+	if highlight && query != "" {
+		highlightTitles(response.Movies, query)
+	}
+	if len(movies) == 0 && query != "" && c.searchService != nil {
+		response.Suggestions = c.suggestions(c.searchService.SuggestForTitle(requestContext(ctx), query))
+	}
+	return response, nil
 }
 
 // SearchMoviesByActorName ищет фильмы по имени актёра
 func (c *movieController) SearchMoviesByActorName(ctx *gin.Context) (dto.MoviesListResponse, error) {
 	query := ctx.Query("actorName")
 	if query == "" {
-		return dto.MoviesListResponse{}, errors.New("actorName parameter is required")
+		return dto.MoviesListResponse{}, apperror.Validation("search_parameter_required", "actorName parameter is required")
 	}
-	movies, err := c.movieService.SearchMoviesByActorName(query)
+	if searchQueryTooShort(query) {
+		return dto.MoviesListResponse{}, fmt.Errorf("validation error: %w", dto.ValidationErrors{dto.NewFieldError(dto.KeyMovieSearchActorShort)})
+	}
+	filter, err := parseMovieFilter(ctx)
 	if err != nil {
 		return dto.MoviesListResponse{}, err
 	}
-	return dto.MoviesListResponse{Movies: c.toMovieResponses(movies)}, nil
+	highlight, err := parseHighlight(ctx)
+	if err != nil {
+		return dto.MoviesListResponse{}, err
+	}
+	movies, err := c.movieService.SearchMoviesByActorName(requestContext(ctx), query, filter)
+	if err != nil {
+		return dto.MoviesListResponse{}, err
+	}
+	response := dto.MoviesListResponse{Movies: mapper.Movies(movies)}
+	if highlight {
+		c.highlightActors(ctx, response.Movies, query)
+	}
+	if len(movies) == 0 && c.searchService != nil {
+		response.Suggestions = c.suggestions(c.searchService.SuggestForActorName(requestContext(ctx), query))
+	}
+	return response, nil
 }
 
-// GetAllMoviesSorted возвращает фильмы с сортировкой
+// suggestions конвертирует подсказки в DTO. Ошибка подсказок не мешает ответу на поиск
+func (c *movieController) suggestions(suggestions domain.SearchSuggestions, err error) *dto.SearchSuggestions {
+	if err != nil {
+		log.Printf("Не удалось получить подсказки для поиска: %v", err)
+		return nil
+	}
+	return &dto.SearchSuggestions{
+		DidYouMean:     suggestions.DidYouMean,
+		RelatedQueries: suggestions.RelatedQueries,
+	}
+}
+
+// GetAllMoviesSorted возвращает фильмы с сортировкой по нескольким полям и пагинацией.
+// Поля задаются как sort=rating:desc,title:asc; старые параметры sort_field и sort_order
+// по-прежнему поддерживаются. Без sort и limit действуют сортировка и размер страницы из настроек
 func (c *movieController) GetAllMoviesSorted(ctx *gin.Context) (dto.MoviesListResponse, error) {
-	sortField := ctx.DefaultQuery("sort_field", "rating")
-	sortOrder := ctx.DefaultQuery("sort_order", "DESC")
-	movies, err := c.movieService.GetAllMoviesSorted(sortField, sortOrder)
+	sortFields, err := parseSortFields(ctx.Query("sort"), ctx.Query("sort_field"), ctx.Query("sort_order"))
+	if err != nil {
+		return dto.MoviesListResponse{}, fmt.Errorf("validation error: %w", err)
+	}
+	if len(sortFields) == 0 {
+		sortFields = c.lists.defaultMovieSort()
+	}
+	limit, err := c.lists.Movies.limit(ctx)
 	if err != nil {
 		return dto.MoviesListResponse{}, err
 	}
-	return dto.MoviesListResponse{Movies: c.toMovieResponses(movies)}, nil
+	offset, err := parseNonNegativeQuery(ctx, "offset", dto.KeyListOffsetInvalid)
+	if err != nil {
+		return dto.MoviesListResponse{}, fmt.Errorf("validation error: %w", err)
+	}
+
+	movies, err := c.movieService.GetAllMoviesSorted(requestContext(ctx), domain.MovieListQuery{Sort: sortFields, Limit: limit, Offset: offset})
+	if err != nil {
+		return dto.MoviesListResponse{}, err
+	}
+	total, err := pageTotal(limit, offset, len(movies), func() (int, error) {
+		return c.movieService.CountMovies(requestContext(ctx), domain.MovieFilter{})
+	})
+	if err != nil {
+		return dto.MoviesListResponse{}, err
+	}
+	return dto.MoviesListResponse{
+		Movies:     mapper.Movies(movies),
+		Pagination: newPagination(limit, offset, total),
+	}, nil
 }
 
-// toMovieResponse конвертирует Movie в DTO
-func (c *movieController) toMovieResponse(movie domain.Movie) dto.MovieResponse {
-	// Конвертируем актеров в формат DTO
-	var actorPreviews []dto.ActorPreview
-	if len(movie.Actors) > 0 {
-		actorPreviews = make([]dto.ActorPreview, 0, len(movie.Actors))
-		for _, actor := range movie.Actors {
-			actorPreviews = append(actorPreviews, dto.ActorPreview{
-				ID:   actor.ID,
-				Name: actor.Name,
-			})
+// parseSortFields разбирает список полей вида "rating:desc,title". Если sort не задан,
+// используются устаревшие параметры sort_field и sort_order
+func parseSortFields(sort, legacyField, legacyOrder string) ([]domain.SortField, error) {
+	if sort == "" {
+		if legacyField == "" {
+			return nil, nil
 		}
-	} else {
-		actorPreviews = nil
+		if legacyOrder == "" {
+			legacyOrder = "desc"
+		}
+		return []domain.SortField{{Field: legacyField, Order: strings.ToLower(legacyOrder)}}, nil
 	}
 
-	return dto.MovieResponse{
-		ID:          movie.ID,
-		Title:       movie.Title,
-		Description: movie.Description,
-		ReleaseYear: movie.ReleaseYear,
-		Rating:      movie.Rating,
-		Actors:      actorPreviews,
+	var fields []domain.SortField
+	for _, item := range strings.Split(sort, ",") {
+		field, order, _ := strings.Cut(strings.TrimSpace(item), ":")
+		if field == "" {
+			return nil, dto.ValidationErrors{dto.NewFieldError(dto.KeyListSortEmptyField)}
+		}
+		fields = append(fields, domain.SortField{Field: field, Order: strings.ToLower(order)})
 	}
+	return fields, nil
 }
 
-// toMovieResponses конвертирует []Movie в []DTO
-func (c *movieController) toMovieResponses(movies []domain.Movie) []dto.MovieResponse {
-	responses := make([]dto.MovieResponse, 0, len(movies))
-	for _, m := range movies {
-		responses = append(responses, c.toMovieResponse(m))
+// parseNonNegativeQuery читает необязательный целочисленный параметр запроса (по умолчанию 0).
+// invalidKey — ключ ошибки валидации для некорректного значения
+func parseNonNegativeQuery(ctx *gin.Context, name, invalidKey string) (int, error) {
+	raw := ctx.Query(name)
+	if raw == "" {
+		return 0, nil
+	}
+	value, err := strconv.Atoi(raw)
+	if err != nil || value < 0 {
+		return 0, dto.ValidationErrors{dto.NewFieldError(invalidKey)}
 	}
-	return responses
+	return value, nil
 }
 
 // CreateMovieWithActors создаёт фильм с актёрами
@@ -254,61 +611,336 @@ func (c *movieController) CreateMovieWithActors(ctx *gin.Context, req dto.MovieW
 	if err := validateMovie(req.Title, req.Description, req.Rating); err != nil {
 		return dto.MovieResponse{}, fmt.Errorf("validation error: %w", err)
 	}
+	releaseDate, err := parseReleaseDate(req.ReleaseDate)
+	if err != nil {
+		return dto.MovieResponse{}, fmt.Errorf("validation error: %w", err)
+	}
+	var errs dto.ValidationErrors
+	language := normalizeLanguage(req.OriginalLanguage, dto.KeyMovieLanguageInvalid, &errs)
+	country := normalizeCountry(req.Country, &errs)
+	checkRuntime(req.RuntimeMinutes, &errs)
+	if err := errs.Err(); err != nil {
+		return dto.MovieResponse{}, fmt.Errorf("validation error: %w", err)
+	}
 
 	movie := domain.Movie{
-		Title:       req.Title,
-		Description: req.Description,
-		ReleaseYear: req.ReleaseYear,
-		Rating:      req.Rating,
+		Title:            req.Title,
+		Description:      req.Description,
+		ReleaseYear:      req.ReleaseYear,
+		Rating:           req.Rating,
+		OriginalLanguage: language,
+		Country:          country,
+		RuntimeMinutes:   req.RuntimeMinutes,
 	}
+	applyReleaseDate(&movie, releaseDate)
 
 	// Создаем фильм с актёрами
-	id, err := c.movieService.CreateMovieWithActors(movie, req.ActorIDs)
+	id, err := c.movieService.CreateMovieWithActors(requestContext(ctx), movie, req.ActorIDs)
 	if err != nil {
 		return dto.MovieResponse{}, err
 	}
 
 	// Получаем созданный фильм с актёрами
-	createdMovie, err := c.movieService.GetByID(id)
+	createdMovie, err := c.movieService.GetByID(requestContext(ctx), id)
 	if err != nil {
 		return dto.MovieResponse{}, err
 	}
 
-	return c.toMovieResponse(createdMovie), nil
+	return mapper.Movie(createdMovie), nil
+}
+
+// CreateMovieFull создаёт фильм вместе с составом, в том числе с актёрами, которых ещё нет
+// в каталоге, и возвращает фильм с полными данными актёров
+func (c *movieController) CreateMovieFull(ctx *gin.Context, req dto.FullMovieRequest) (dto.FullMovieResponse, error) {
+	if err := validateMovie(req.Title, req.Description, req.Rating); err != nil {
+		return dto.FullMovieResponse{}, fmt.Errorf("validation error: %w", err)
+	}
+	releaseDate, err := parseReleaseDate(req.ReleaseDate)
+	if err != nil {
+		return dto.FullMovieResponse{}, fmt.Errorf("validation error: %w", err)
+	}
+	var errs dto.ValidationErrors
+	language := normalizeLanguage(req.OriginalLanguage, dto.KeyMovieLanguageInvalid, &errs)
+	country := normalizeCountry(req.Country, &errs)
+	checkRuntime(req.RuntimeMinutes, &errs)
+	cast := fullCastFromRequest(req, &errs)
+	if err := errs.Err(); err != nil {
+		return dto.FullMovieResponse{}, fmt.Errorf("validation error: %w", err)
+	}
+
+	movie := domain.Movie{
+		Title:            req.Title,
+		Description:      req.Description,
+		ReleaseYear:      req.ReleaseYear,
+		Rating:           req.Rating,
+		OriginalLanguage: language,
+		Country:          country,
+		RuntimeMinutes:   req.RuntimeMinutes,
+	}
+	applyReleaseDate(&movie, releaseDate)
+
+	result, err := c.movieService.CreateFull(requestContext(ctx), movie, cast, req.Force)
+	if err != nil {
+		return dto.FullMovieResponse{}, err
+	}
+	created, err := c.movieService.GetByID(requestContext(ctx), result.MovieID)
+	if err != nil {
+		return dto.FullMovieResponse{}, fmt.Errorf("getting created movie: %w", err)
+	}
+	resp := dto.FullMovieResponse{Movie: mapper.Movie(created), Actors: mapper.Actors(created.Actors), ActorsCreated: result.ActorsCreated, CreatedActorIDs: result.CreatedActorIDs}
+	resp.Movie.Actors = nil // состав отдаётся полностью в actors
+	return resp, nil
 }
 
-// UpdateMovieActors обновляет актёров фильма
+// fullCastFromRequest проверяет состав из запроса POST /movies/full: сначала актёры,
+// заданные целиком, затем actor_ids. Ошибки полей актёра указывают его индекс (actors[1].birth_date)
+func fullCastFromRequest(req dto.FullMovieRequest, errs *dto.ValidationErrors) []domain.Actor {
+	cast := make([]domain.Actor, 0, len(req.Actors)+len(req.ActorIDs))
+	for i, member := range req.Actors {
+		var memberErrs dto.ValidationErrors
+		if err := validateActorInput(member.Name, member.Gender, member.BirthDate, ""); err != nil {
+			errors.As(err, &memberErrs)
+		}
+		characterName := strings.TrimSpace(member.CharacterName)
+		if utf8.RuneCountInString(characterName) > maxCharacterNameLength {
+			memberErrs.Add(dto.KeyCastCharacterTooLong)
+		}
+		if member.BillingOrder < 0 {
+			memberErrs.Add(dto.KeyCastBillingInvalid)
+		}
+		for _, fieldErr := range memberErrs {
+			fieldErr.Field = fmt.Sprintf("actors[%d].%s", i, fieldErr.Field)
+			*errs = append(*errs, fieldErr)
+		}
+		birthDate, _ := mapper.ParseDate(member.BirthDate)
+		cast = append(cast, domain.Actor{
+			Name:          strings.TrimSpace(member.Name),
+			Gender:        member.Gender,
+			BirthDate:     birthDate,
+			CharacterName: characterName,
+			BillingOrder:  member.BillingOrder,
+		})
+	}
+	for i, actorID := range req.ActorIDs {
+		if actorID <= 0 {
+			index := i
+			fieldErr := dto.NewFieldError(dto.KeyCastActorInvalid)
+			fieldErr.Field, fieldErr.Index = "actor_ids", &index
+			*errs = append(*errs, fieldErr)
+		}
+		cast = append(cast, domain.Actor{ID: actorID})
+	}
+	if len(cast) == 0 {
+		errs.Add(dto.KeyFullCastRequired)
+	}
+	return cast
+}
+
+// maxCharacterNameLength - максимальная длина имени персонажа (film_actor.character_name)
+const maxCharacterNameLength = 255
+
+// validateCastMember проверяет роль и место в титрах актёра в составе фильма
+func validateCastMember(member domain.CastMember, errs *dto.ValidationErrors) {
+	if member.ActorID <= 0 {
+		errs.Add(dto.KeyCastActorInvalid)
+	}
+	if utf8.RuneCountInString(member.CharacterName) > maxCharacterNameLength {
+		errs.Add(dto.KeyCastCharacterTooLong)
+	}
+	if member.BillingOrder < 0 {
+		errs.Add(dto.KeyCastBillingInvalid)
+	}
+}
+
+// castFromRequest собирает состав фильма из запроса: сначала cast, затем actor_ids без ролей.
+// Повторно указанный актёр пропускается
+func castFromRequest(req dto.UpdateMovieActorsRequest, errs *dto.ValidationErrors) []domain.CastMember {
+	cast := make([]domain.CastMember, 0, len(req.Cast)+len(req.ActorIDs))
+	seen := make(map[int]bool, cap(cast))
+	add := func(member domain.CastMember) {
+		member.CharacterName = strings.TrimSpace(member.CharacterName)
+		validateCastMember(member, errs)
+		if !seen[member.ActorID] {
+			seen[member.ActorID] = true
+			cast = append(cast, member)
+		}
+	}
+	for _, member := range req.Cast {
+		add(domain.CastMember{ActorID: member.ActorID, CharacterName: member.CharacterName, BillingOrder: member.BillingOrder})
+	}
+	for _, actorID := range req.ActorIDs {
+		add(domain.CastMember{ActorID: actorID})
+	}
+	if len(cast) == 0 {
+		errs.Add(dto.KeyCastRequired)
+	}
+	return cast
+}
+
+// UpdateMovieActors заменяет состав фильма
 func (c *movieController) UpdateMovieActors(ctx *gin.Context, movieID int, req dto.UpdateMovieActorsRequest) (dto.MovieActorsResponse, error) {
+	var errs dto.ValidationErrors
+	cast := castFromRequest(req, &errs)
+	if err := errs.Err(); err != nil {
+		return dto.MovieActorsResponse{}, fmt.Errorf("validation error: %w", err)
+	}
+
+	// Прежний состав нужен, чтобы сообщить, какие актёры добавлены и какие убраны
+	previous, err := c.movieService.GetActors(requestContext(ctx), movieID)
+	if err != nil {
+		return dto.MovieActorsResponse{}, err
+	}
+
 	// Обновляем связи фильма с актёрами
-	err := c.movieService.UpdateMovieActors(movieID, req.ActorIDs)
+	err = c.movieService.UpdateMovieActors(requestContext(ctx), movieID, cast)
 	if err != nil {
 		return dto.MovieActorsResponse{}, err
 	}
 
 	// Получаем обновлённый список актёров фильма
-	actors, err := c.movieService.GetActors(movieID)
+	actors, err := c.movieService.GetActors(requestContext(ctx), movieID)
+	if err != nil {
+		return dto.MovieActorsResponse{}, err
+	}
+
+	return dto.MovieActorsResponse{
+		Actors:          mapper.Actors(actors),
+		AddedActorIDs:   actorIDsMissingFrom(actors, previous),
+		RemovedActorIDs: actorIDsMissingFrom(previous, actors),
+	}, nil
+}
+
+// castChangeFromRequest проверяет запрос на изменение состава. Повторы в add и remove
+// пропускаются; актёра нельзя одновременно добавить и убрать
+func castChangeFromRequest(req dto.ChangeMovieActorsRequest) ([]domain.CastMember, []int, error) {
+	var errs dto.ValidationErrors
+	if len(req.Add) == 0 && len(req.Remove) == 0 {
+		errs.Add(dto.KeyCastChangeRequired)
+	}
+	add := make([]domain.CastMember, 0, len(req.Add))
+	added := make(map[int]bool, len(req.Add))
+	for _, member := range req.Add {
+		castMember := domain.CastMember{ActorID: member.ActorID, CharacterName: strings.TrimSpace(member.CharacterName), BillingOrder: member.BillingOrder}
+		validateCastMember(castMember, &errs)
+		if !added[castMember.ActorID] {
+			added[castMember.ActorID] = true
+			add = append(add, castMember)
+		}
+	}
+	remove := make([]int, 0, len(req.Remove))
+	removed := make(map[int]bool, len(req.Remove))
+	for i, actorID := range req.Remove {
+		key := ""
+		switch {
+		case actorID <= 0:
+			key = dto.KeyCastRemoveInvalid
+		case added[actorID]:
+			key = dto.KeyCastChangeConflict
+		}
+		if key != "" {
+			fieldErr := dto.NewFieldError(key)
+			index := i
+			fieldErr.Index = &index
+			errs = append(errs, fieldErr)
+			continue
+		}
+		if !removed[actorID] {
+			removed[actorID] = true
+			remove = append(remove, actorID)
+		}
+	}
+	if err := errs.Err(); err != nil {
+		return nil, nil, fmt.Errorf("validation error: %w", err)
+	}
+	return add, remove, nil
+}
+
+// ChangeMovieActors добавляет и убирает актёров фильма, не заменяя весь состав
+func (c *movieController) ChangeMovieActors(ctx *gin.Context, movieID int, req dto.ChangeMovieActorsRequest) (dto.MovieActorsResponse, error) {
+	add, remove, err := castChangeFromRequest(req)
 	if err != nil {
 		return dto.MovieActorsResponse{}, err
 	}
 
-	// Конвертируем актёров в DTO
-	actorResponses := make([]dto.ActorResponse, len(actors))
-	for i, actor := range actors {
-		actorResponses[i] = dto.ActorResponse{
-			ID:        actor.ID,
-			Name:      actor.Name,
-			Gender:    actor.Gender,
-			BirthDate: actor.BirthDate.Format("2006-01-02"),
+	previous, err := c.movieService.GetActors(requestContext(ctx), movieID)
+	if err != nil {
+		return dto.MovieActorsResponse{}, err
+	}
+	if err := c.movieService.ChangeMovieActors(requestContext(ctx), movieID, add, remove); err != nil {
+		return dto.MovieActorsResponse{}, err
+	}
+	actors, err := c.movieService.GetActors(requestContext(ctx), movieID)
+	if err != nil {
+		return dto.MovieActorsResponse{}, err
+	}
+
+	return dto.MovieActorsResponse{
+		Actors:          mapper.Actors(actors),
+		AddedActorIDs:   actorIDsMissingFrom(actors, previous),
+		RemovedActorIDs: actorIDsMissingFrom(previous, actors),
+	}, nil
+}
+
+// ReorderMovieActors задаёт порядок актёров в титрах и возвращает состав в новом порядке
+func (c *movieController) ReorderMovieActors(ctx *gin.Context, movieID int, req dto.ReorderMovieActorsRequest) (dto.MovieActorsResponse, error) {
+	var errs dto.ValidationErrors
+	if len(req.ActorIDs) == 0 {
+		errs.Add(dto.KeyCastOrderRequired)
+	}
+	seen := make(map[int]bool, len(req.ActorIDs))
+	for i, actorID := range req.ActorIDs {
+		key := ""
+		switch {
+		case actorID <= 0:
+			key = dto.KeyCastOrderInvalid
+		case seen[actorID]:
+			key = dto.KeyCastOrderDuplicate
+		}
+		seen[actorID] = true
+		if key != "" {
+			fieldErr := dto.NewFieldError(key)
+			index := i
+			fieldErr.Index = &index
+			errs = append(errs, fieldErr)
 		}
 	}
+	if err := errs.Err(); err != nil {
+		return dto.MovieActorsResponse{}, fmt.Errorf("validation error: %w", err)
+	}
 
-	return dto.MovieActorsResponse{Actors: actorResponses}, nil
+	actors, err := c.movieService.ReorderActors(requestContext(ctx), movieID, req.ActorIDs)
+	if err != nil {
+		return dto.MovieActorsResponse{}, err
+	}
+	return dto.MovieActorsResponse{Actors: mapper.Actors(actors)}, nil
+}
+
+// actorIDsMissingFrom возвращает ID актёров из actors, которых нет в other
+func actorIDsMissingFrom(actors, other []domain.Actor) []int {
+	present := make(map[int]bool, len(other))
+	for _, actor := range other {
+		present[actor.ID] = true
+	}
+	var missing []int
+	for _, actor := range actors {
+		if !present[actor.ID] {
+			missing = append(missing, actor.ID)
+		}
+	}
+	return missing
 }
 
 // AddActorToMovie добавляет актёра в фильм
-func (c *movieController) AddActorToMovie(ctx *gin.Context, movieID, actorID int) (dto.MovieResponse, error) {
+func (c *movieController) AddActorToMovie(ctx *gin.Context, movieID, actorID int, req dto.AddActorToMovieRequest) (dto.MovieResponse, error) {
+	member := domain.CastMember{ActorID: actorID, CharacterName: strings.TrimSpace(req.CharacterName), BillingOrder: req.BillingOrder}
+	var errs dto.ValidationErrors
+	validateCastMember(member, &errs)
+	if err := errs.Err(); err != nil {
+		return dto.MovieResponse{}, fmt.Errorf("validation error: %w", err)
+	}
+
 	// Добавляем актёра в фильм
-	err := c.movieService.AddActor(movieID, actorID)
+	err := c.movieService.AddActor(requestContext(ctx), movieID, member)
 	if err != nil {
 		if errors.Is(err, domain.ErrMovieNotFound) || errors.Is(err, domain.ErrActorNotFound) {
 			return dto.MovieResponse{}, err
@@ -317,7 +949,7 @@ func (c *movieController) AddActorToMovie(ctx *gin.Context, movieID, actorID int
 	}
 
 	// Получаем обновлённый фильм
-	updatedMovie, err := c.movieService.GetByID(movieID)
+	updatedMovie, err := c.movieService.GetByID(requestContext(ctx), movieID)
 	if err != nil {
 		if errors.Is(err, domain.ErrMovieNotFound) {
 			return dto.MovieResponse{}, domain.ErrMovieNotFound
@@ -325,13 +957,13 @@ func (c *movieController) AddActorToMovie(ctx *gin.Context, movieID, actorID int
 		return dto.MovieResponse{}, fmt.Errorf("getting updated movie: %w", err)
 	}
 
-	return c.toMovieResponse(updatedMovie), nil
+	return mapper.Movie(updatedMovie), nil
 }
 
 // RemoveActorFromMovie удаляет актёра из фильма
 func (c *movieController) RemoveActorFromMovie(ctx *gin.Context, movieID, actorID int) (dto.MovieResponse, error) {
 	// Удаляем актёра из фильма
-	err := c.movieService.RemoveActor(movieID, actorID)
+	err := c.movieService.RemoveActor(requestContext(ctx), movieID, actorID)
 	if err != nil {
 		if errors.Is(err, domain.ErrMovieNotFound) || errors.Is(err, domain.ErrActorNotFound) {
 			return dto.MovieResponse{}, err
@@ -340,7 +972,7 @@ func (c *movieController) RemoveActorFromMovie(ctx *gin.Context, movieID, actorI
 	}
 
 	// Получаем обновлённый фильм
-	updatedMovie, err := c.movieService.GetByID(movieID)
+	updatedMovie, err := c.movieService.GetByID(requestContext(ctx), movieID)
 	if err != nil {
 		if errors.Is(err, domain.ErrMovieNotFound) {
 			return dto.MovieResponse{}, domain.ErrMovieNotFound
@@ -348,13 +980,13 @@ func (c *movieController) RemoveActorFromMovie(ctx *gin.Context, movieID, actorI
 		return dto.MovieResponse{}, fmt.Errorf("getting updated movie: %w", err)
 	}
 
-	return c.toMovieResponse(updatedMovie), nil
+	return mapper.Movie(updatedMovie), nil
 }
 
 // GetActorsForMovieByID возвращает актёров фильма
 func (c *movieController) GetActorsForMovieByID(ctx *gin.Context, movieID int) (dto.MovieActorsResponse, error) {
 	// Проверяем существование фильма
-	_, err := c.movieService.GetByID(movieID)
+	_, err := c.movieService.GetByID(requestContext(ctx), movieID)
 	if err != nil {
 		if errors.Is(err, domain.ErrMovieNotFound) {
 			return dto.MovieActorsResponse{}, domain.ErrMovieNotFound
@@ -362,30 +994,214 @@ func (c *movieController) GetActorsForMovieByID(ctx *gin.Context, movieID int) (
 		return dto.MovieActorsResponse{}, domain.ErrMovieNotFound
 	}
 
-	actors, err := c.movieService.GetActorsForMovieByID(movieID)
+	actors, err := c.movieService.GetActorsForMovieByID(requestContext(ctx), movieID)
 	if err != nil {
 		return dto.MovieActorsResponse{}, fmt.Errorf("getting actors for movie: %w", err)
 	}
 
-	// Конвертируем актёров в DTO
-	actorResponses := make([]dto.ActorResponse, len(actors))
-	for i, actor := range actors {
-		actorResponses[i] = dto.ActorResponse{
-			ID:        actor.ID,
-			Name:      actor.Name,
-			Gender:    actor.Gender,
-			BirthDate: actor.BirthDate.Format("2006-01-02"),
+	return dto.MovieActorsResponse{Actors: mapper.Actors(actors)}, nil
+}
+
+// GetMovieCast возвращает страницу состава фильма в порядке титров (?limit=, ?offset=) и
+// размер всего состава, чтобы клиент знал, сколько актёров осталось
+func (c *movieController) GetMovieCast(ctx *gin.Context, movieID int) (dto.MovieCastResponse, error) {
+	limit, err := c.lists.Cast.limit(ctx)
+	if err != nil {
+		return dto.MovieCastResponse{}, err
+	}
+	offset, err := parseNonNegativeQuery(ctx, "offset", dto.KeyListOffsetInvalid)
+	if err != nil {
+		return dto.MovieCastResponse{}, fmt.Errorf("validation error: %w", err)
+	}
+
+	cast, total, err := c.movieService.ListCast(requestContext(ctx), movieID, limit, offset)
+	if err != nil {
+		return dto.MovieCastResponse{}, err
+	}
+	resp := mapper.MovieCast(movieID, cast, total)
+	resp.Pagination = newPagination(limit, offset, total)
+	return resp, nil
+}
+
+// GetRatingHistory возвращает историю изменений рейтинга фильма
+func (c *movieController) GetRatingHistory(ctx *gin.Context, movieID int) (dto.RatingHistoryResponse, error) {
+	history, err := c.movieService.GetRatingHistory(requestContext(ctx), movieID)
+	if err != nil {
+		if errors.Is(err, domain.ErrMovieNotFound) {
+			return dto.RatingHistoryResponse{}, domain.ErrMovieNotFound
 		}
+		return dto.RatingHistoryResponse{}, fmt.Errorf("getting rating history: %w", err)
 	}
+	return mapper.RatingHistory(movieID, history), nil
+}
 
-	return dto.MovieActorsResponse{Actors: actorResponses}, nil
+// GetMovieHistory возвращает версии фильма с отличиями между соседними версиями
+func (c *movieController) GetMovieHistory(ctx *gin.Context, movieID int) (dto.MovieHistoryResponse, error) {
+	versions, err := c.movieService.GetMovieHistory(requestContext(ctx), movieID)
+	if err != nil {
+		if errors.Is(err, domain.ErrMovieNotFound) {
+			return dto.MovieHistoryResponse{}, domain.ErrMovieNotFound
+		}
+		return dto.MovieHistoryResponse{}, fmt.Errorf("getting movie history: %w", err)
+	}
+	return mapper.MovieHistory(movieID, versions), nil
+}
+
+// parseAvailability проверяет регион и даты окна доступности
+func parseAvailability(movieID int, req dto.AvailabilityRequest) (domain.Availability, error) {
+	var errs dto.ValidationErrors
+	window := domain.Availability{MovieID: movieID, Region: isocode.NormalizeCountry(req.Region)}
+	if !isocode.ValidCountry(window.Region) {
+		errs.Add(dto.KeyAvailabilityRegion)
+	}
+	from, err := mapper.ParseDate(req.AvailableFrom)
+	if err != nil {
+		errs.Add(dto.KeyAvailabilityFrom)
+	}
+	window.AvailableFrom = from
+	if req.AvailableUntil != "" {
+		until, err := mapper.ParseDate(req.AvailableUntil)
+		switch {
+		case err != nil:
+			errs.Add(dto.KeyAvailabilityUntil)
+		case !from.IsZero() && until.Before(from):
+			errs.Add(dto.KeyAvailabilityUntilBefore)
+		default:
+			window.AvailableUntil = &until
+		}
+	}
+	if err := errs.Err(); err != nil {
+		return domain.Availability{}, fmt.Errorf("validation error: %w", err)
+	}
+	return window, nil
+}
+
+// GetAvailability возвращает окна доступности фильма
+func (c *movieController) GetAvailability(ctx *gin.Context, movieID int) (dto.AvailabilityListResponse, error) {
+	windows, err := c.movieService.GetAvailability(requestContext(ctx), movieID)
+	if err != nil {
+		if errors.Is(err, domain.ErrMovieNotFound) {
+			return dto.AvailabilityListResponse{}, domain.ErrMovieNotFound
+		}
+		return dto.AvailabilityListResponse{}, fmt.Errorf("getting availability: %w", err)
+	}
+	return mapper.AvailabilityList(movieID, windows), nil
+}
+
+// AddAvailability добавляет фильму окно доступности
+func (c *movieController) AddAvailability(ctx *gin.Context, movieID int, req dto.AvailabilityRequest) (dto.AvailabilityResponse, error) {
+	window, err := parseAvailability(movieID, req)
+	if err != nil {
+		return dto.AvailabilityResponse{}, err
+	}
+	window, err = c.movieService.AddAvailability(requestContext(ctx), window)
+	if err != nil {
+		if errors.Is(err, domain.ErrMovieNotFound) {
+			return dto.AvailabilityResponse{}, domain.ErrMovieNotFound
+		}
+		return dto.AvailabilityResponse{}, fmt.Errorf("adding availability: %w", err)
+	}
+	return mapper.Availability(window), nil
+}
+
+// UpdateAvailability заменяет регион и даты окна доступности фильма
+func (c *movieController) UpdateAvailability(ctx *gin.Context, movieID, windowID int, req dto.AvailabilityRequest) (dto.AvailabilityResponse, error) {
+	window, err := parseAvailability(movieID, req)
+	if err != nil {
+		return dto.AvailabilityResponse{}, err
+	}
+	window.ID = windowID
+	if err := c.movieService.UpdateAvailability(requestContext(ctx), window); err != nil {
+		if errors.Is(err, domain.ErrMovieNotFound) || errors.Is(err, domain.ErrAvailabilityNotFound) {
+			return dto.AvailabilityResponse{}, err
+		}
+		return dto.AvailabilityResponse{}, fmt.Errorf("updating availability: %w", err)
+	}
+	return mapper.Availability(window), nil
+}
+
+// DeleteAvailability удаляет окно доступности фильма
+func (c *movieController) DeleteAvailability(ctx *gin.Context, movieID, windowID int) error {
+	if err := c.movieService.DeleteAvailability(requestContext(ctx), movieID, windowID); err != nil {
+		if errors.Is(err, domain.ErrMovieNotFound) || errors.Is(err, domain.ErrAvailabilityNotFound) {
+			return err
+		}
+		return fmt.Errorf("deleting availability: %w", err)
+	}
+	return nil
+}
+
+// externalRatingSource проверяет источник рейтинга из адреса. Внутренний рейтинг — поле rating
+// фильма, он меняется через обновление фильма
+func externalRatingSource(source string) error {
+	if source == domain.RatingSourceInternal || domain.RatingScale(source) == 0 {
+		return fmt.Errorf("validation error: %w", dto.ValidationErrors{dto.NewFieldError(dto.KeyRatingSourceInvalid)})
+	}
+	return nil
+}
+
+// GetMovieRatings возвращает рейтинги фильма по источникам и отображаемый рейтинг
+func (c *movieController) GetMovieRatings(ctx *gin.Context, movieID int) (dto.MovieRatingsResponse, error) {
+	ratings, display, err := c.movieService.GetRatings(requestContext(ctx), movieID)
+	if err != nil {
+		if errors.Is(err, domain.ErrMovieNotFound) {
+			return dto.MovieRatingsResponse{}, domain.ErrMovieNotFound
+		}
+		return dto.MovieRatingsResponse{}, fmt.Errorf("getting movie ratings: %w", err)
+	}
+	return mapper.MovieRatings(movieID, ratings, display), nil
+}
+
+// SetMovieRating сохраняет рейтинг фильма из внешнего источника и возвращает все рейтинги фильма
+func (c *movieController) SetMovieRating(ctx *gin.Context, movieID int, source string, req dto.MovieRatingRequest) (dto.MovieRatingsResponse, error) {
+	if err := externalRatingSource(source); err != nil {
+		return dto.MovieRatingsResponse{}, err
+	}
+	if req.Rating == nil {
+		return dto.MovieRatingsResponse{}, fmt.Errorf("validation error: %w", dto.ValidationErrors{dto.NewFieldError(dto.KeyRatingValueRequired)})
+	}
+	if scale := domain.RatingScale(source); *req.Rating < 0 || *req.Rating > scale {
+		fieldErr := dto.NewFieldError(dto.KeyRatingValueOutOfRange)
+		fieldErr.Expected = "max=" + strconv.FormatFloat(scale, 'f', -1, 64)
+		return dto.MovieRatingsResponse{}, fmt.Errorf("validation error: %w", dto.ValidationErrors{fieldErr})
+	}
+	// Рейтинг из источника входит в отображаемый рейтинг, поэтому его меняют только те же роли
+	if err := authorizeMovieFields(ctx, "rating"); err != nil {
+		return dto.MovieRatingsResponse{}, err
+	}
+
+	rating := domain.MovieRating{MovieID: movieID, Source: source, Rating: *req.Rating}
+	if err := c.movieService.SetRating(requestContext(ctx), rating); err != nil {
+		if errors.Is(err, domain.ErrMovieNotFound) {
+			return dto.MovieRatingsResponse{}, domain.ErrMovieNotFound
+		}
+		return dto.MovieRatingsResponse{}, fmt.Errorf("setting movie rating: %w", err)
+	}
+	return c.GetMovieRatings(ctx, movieID)
+}
+
+// DeleteMovieRating удаляет рейтинг фильма из внешнего источника
+func (c *movieController) DeleteMovieRating(ctx *gin.Context, movieID int, source string) error {
+	if err := externalRatingSource(source); err != nil {
+		return err
+	}
+	if err := authorizeMovieFields(ctx, "rating"); err != nil {
+		return err
+	}
+	if err := c.movieService.DeleteRating(requestContext(ctx), movieID, source); err != nil {
+		if errors.Is(err, domain.ErrMovieNotFound) || errors.Is(err, domain.ErrRatingNotFound) {
+			return err
+		}
+		return fmt.Errorf("deleting movie rating: %w", err)
+	}
+	return nil
 }
 
 // GetMoviesForActor возвращает фильмы по актёру
 func (c *movieController) GetMoviesForActor(ctx *gin.Context, actorID int) (dto.ActorMoviesResponse, error) {
 	// TODO: Добавить проверку существования актёра, когда будет доступен сервис актёров
 
-	movies, err := c.movieService.GetMoviesForActor(actorID)
+	movies, err := c.movieService.GetMoviesForActor(requestContext(ctx), actorID)
 	if err != nil {
 		if errors.Is(err, domain.ErrActorNotFound) {
 			return dto.ActorMoviesResponse{}, domain.ErrActorNotFound
@@ -394,44 +1210,319 @@ func (c *movieController) GetMoviesForActor(ctx *gin.Context, actorID int) (dto.
 	}
 
 	return dto.ActorMoviesResponse{
-		Movies: c.toMovieResponses(movies),
+		Movies: mapper.Movies(movies),
 	}, nil
 }
 
-// PartialUpdateMovie частично обновляет фильм
-func (c *movieController) PartialUpdateMovie(ctx *gin.Context, id int, update dto.MovieUpdate) error {
-	// Получаем текущий фильм
-	movie, err := c.movieService.GetByID(id)
+// PartialUpdateMovie обновляет только переданные поля фильма и возвращает фильм после изменения
+func (c *movieController) PartialUpdateMovie(ctx *gin.Context, id int, update dto.MovieUpdate) (dto.MovieResponse, error) {
+	// Проверяем только переданные поля: остальные в базе не меняются
+	var errs dto.ValidationErrors
+	if update.Title != nil {
+		if title := strings.TrimSpace(*update.Title); len(title) < 1 {
+			errs.Add(dto.KeyMovieTitleRequired)
+		} else if len(title) > 150 {
+			errs.Add(dto.KeyMovieTitleTooLong)
+		}
+	}
+	if update.Description != nil && len(*update.Description) > 1000 {
+		errs.Add(dto.KeyMovieDescriptionTooLong)
+	}
+	if update.Rating != nil && (*update.Rating < 0 || *update.Rating > 10) {
+		errs.Add(dto.KeyMovieRatingOutOfRange)
+	}
+	changes := domain.MovieUpdate{
+		Title:       update.Title,
+		Description: update.Description,
+		ReleaseYear: update.ReleaseYear,
+		Rating:      update.Rating,
+	}
+	if update.OriginalLanguage != nil {
+		language := normalizeLanguage(*update.OriginalLanguage, dto.KeyMovieLanguageInvalid, &errs)
+		changes.OriginalLanguage = &language
+	}
+	if update.Country != nil {
+		country := normalizeCountry(*update.Country, &errs)
+		changes.Country = &country
+	}
+	if update.RuntimeMinutes != nil {
+		checkRuntime(*update.RuntimeMinutes, &errs)
+		changes.RuntimeMinutes = update.RuntimeMinutes
+	}
+	if err := errs.Err(); err != nil {
+		return dto.MovieResponse{}, fmt.Errorf("validation error: %w", err)
+	}
+
+	if update.ReleaseDate != nil {
+		releaseDate, err := parseReleaseDate(*update.ReleaseDate)
+		if err != nil {
+			return dto.MovieResponse{}, fmt.Errorf("validation error: %w", err)
+		}
+		changes.ReleaseDate = releaseDate
+		changes.ClearReleaseDate = releaseDate == nil
+	}
+	if err := authorizeMovieFields(ctx, movieUpdateFields(changes)...); err != nil {
+		return dto.MovieResponse{}, err
+	}
+
+	if err := c.movieService.PartialUpdateMovie(requestContext(ctx), id, changes); err != nil {
+		if errors.Is(err, domain.ErrMovieNotFound) || errors.Is(err, domain.ErrNoFieldsToUpdate) {
+			return dto.MovieResponse{}, err
+		}
+		return dto.MovieResponse{}, fmt.Errorf("updating movie: %w", err)
+	}
+
+	updatedMovie, err := c.movieService.GetByID(requestContext(ctx), id)
 	if err != nil {
-		if errors.Is(err, domain.ErrMovieNotFound) {
-			return domain.ErrMovieNotFound
+		return dto.MovieResponse{}, fmt.Errorf("getting updated movie: %w", err)
+	}
+	return mapper.Movie(updatedMovie), nil
+}
+
+// MergeMovies объединяет фильм-дубликат с основным фильмом
+func (c *movieController) MergeMovies(ctx *gin.Context, req dto.MergeMoviesRequest) (dto.MovieMergeResponse, error) {
+	result, err := c.movieService.MergeMovies(requestContext(ctx), req.KeepID, req.DuplicateID)
+	if err != nil {
+		if errors.Is(err, domain.ErrMovieNotFound) || errors.Is(err, domain.ErrMergeSameMovie) {
+			return dto.MovieMergeResponse{}, err
 		}
-		return fmt.Errorf("getting movie: %w", err)
+		return dto.MovieMergeResponse{}, fmt.Errorf("merging movies: %w", err)
 	}
 
-	// Обновляем только переданные поля
-	if update.Title != nil {
-		movie.Title = *update.Title
+	return dto.MovieMergeResponse{
+		Movie:            mapper.Movie(result.Movie),
+		DuplicateID:      result.DuplicateID,
+		ActorsReassigned: result.ActorsReassigned,
+	}, nil
+}
+
+// maxBulkMovies ограничивает число фильмов в одной массовой операции: все они
+// обрабатываются в одной транзакции и держат блокировки до её завершения
+const maxBulkMovies = 500
+
+// validateBulkIDs проверяет список ID массовой операции и убирает повторы, сохраняя порядок
+func validateBulkIDs(ids []int, errs *dto.ValidationErrors) []int {
+	if len(ids) == 0 {
+		errs.Add(dto.KeyBulkIDsRequired)
+		return nil
 	}
-	if update.Description != nil {
-		movie.Description = *update.Description
+	if len(ids) > maxBulkMovies {
+		errs.Add(dto.KeyBulkIDsTooMany)
+		return nil
 	}
-	if update.ReleaseYear != nil {
-		movie.ReleaseYear = *update.ReleaseYear
+	seen := make(map[int]bool, len(ids))
+	unique := make([]int, 0, len(ids))
+	for _, id := range ids {
+		if id <= 0 {
+			errs.Add(dto.KeyBulkIDInvalid)
+			return nil
+		}
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
 	}
-	if update.Rating != nil {
-		movie.Rating = *update.Rating
+	return unique
+}
+
+// bulkResponse собирает ответ массовой операции из результатов по фильмам
+func bulkResponse(results []domain.BulkItemResult) dto.BulkOperationResponse {
+	resp := dto.BulkOperationResponse{Results: make([]dto.BulkItemResult, 0, len(results))}
+	for _, result := range results {
+		item := dto.BulkItemResult{ID: result.ID, Status: "ok"}
+		if result.Err != nil {
+			appErr := apperror.From(result.Err)
+			item.Status = "failed"
+			item.Code = appErr.Code
+			item.Error = appErr.Message
+			resp.Failed++
+		} else {
+			resp.Succeeded++
+		}
+		resp.Results = append(resp.Results, item)
 	}
+	return resp
+}
 
-	// Валидация обновленных данных
-	if err := validateMovie(movie.Title, movie.Description, movie.Rating); err != nil {
-		return fmt.Errorf("validation error: %w", err)
+// BulkDeleteMovies удаляет несколько фильмов в одной транзакции
+func (c *movieController) BulkDeleteMovies(ctx *gin.Context, req dto.BulkDeleteMoviesRequest) (dto.BulkOperationResponse, error) {
+	var errs dto.ValidationErrors
+	ids := validateBulkIDs(req.IDs, &errs)
+	if err := errs.Err(); err != nil {
+		return dto.BulkOperationResponse{}, fmt.Errorf("validation error: %w", err)
 	}
 
-	// Сохраняем изменения (передаем пустой слайс actorIDs, так как мы не обновляем актеров)
-	if err := c.movieService.Update(movie, []int{}); err != nil {
-		return fmt.Errorf("updating movie: %w", err)
+	results, err := c.movieService.BulkDeleteMovies(requestContext(ctx), ids)
+	if err != nil {
+		return dto.BulkOperationResponse{}, fmt.Errorf("bulk deleting movies: %w", err)
 	}
+	return bulkResponse(results), nil
+}
 
-	return nil
+// BulkUpdateMovies применяет одни и те же изменения к нескольким фильмам в одной транзакции
+func (c *movieController) BulkUpdateMovies(ctx *gin.Context, req dto.BulkUpdateMoviesRequest) (dto.BulkOperationResponse, error) {
+	var errs dto.ValidationErrors
+	ids := validateBulkIDs(req.IDs, &errs)
+	changes := domain.MovieUpdate{Rating: req.Changes.Rating}
+	if changes.Rating != nil && (*changes.Rating < 0 || *changes.Rating > 10) {
+		errs.Add(dto.KeyMovieRatingOutOfRange)
+	}
+	if req.Changes.OriginalLanguage != nil {
+		language := normalizeLanguage(*req.Changes.OriginalLanguage, dto.KeyMovieLanguageInvalid, &errs)
+		changes.OriginalLanguage = &language
+	}
+	if req.Changes.Country != nil {
+		country := normalizeCountry(*req.Changes.Country, &errs)
+		changes.Country = &country
+	}
+	if changes.IsEmpty() {
+		errs.Add(dto.KeyBulkChangesRequired)
+	}
+	if err := errs.Err(); err != nil {
+		return dto.BulkOperationResponse{}, fmt.Errorf("validation error: %w", err)
+	}
+	if err := authorizeMovieFields(ctx, movieUpdateFields(changes)...); err != nil {
+		return dto.BulkOperationResponse{}, err
+	}
+
+	results, err := c.movieService.BulkUpdateMovies(requestContext(ctx), ids, changes)
+	if err != nil {
+		return dto.BulkOperationResponse{}, fmt.Errorf("bulk updating movies: %w", err)
+	}
+	return bulkResponse(results), nil
+}
+
+// imdbIDPattern — формат идентификатора фильма IMDb
+var imdbIDPattern = regexp.MustCompile(`^tt[0-9]{7,10}$`)
+
+// ImportExternalMovie импортирует фильм по IMDb ID из внешнего каталога
+// и возвращает созданный фильм с актёрами
+func (c *movieController) ImportExternalMovie(ctx *gin.Context, imdbID string) (dto.MovieImportResponse, error) {
+	if !imdbIDPattern.MatchString(imdbID) {
+		return dto.MovieImportResponse{}, fmt.Errorf("validation error: %w", dto.ValidationErrors{dto.NewFieldError(dto.KeyMovieIMDbIDInvalid)})
+	}
+
+	result, err := c.movieService.ImportExternal(requestContext(ctx), imdbID)
+	if err != nil {
+		return dto.MovieImportResponse{}, err
+	}
+
+	movie, err := c.movieService.GetByID(requestContext(ctx), result.MovieID)
+	if err != nil {
+		return dto.MovieImportResponse{}, fmt.Errorf("getting imported movie: %w", err)
+	}
+	return dto.MovieImportResponse{Movie: mapper.Movie(movie), ActorsCreated: result.ActorsCreated, CreatedActorIDs: result.CreatedActorIDs}, nil
+}
+
+// externalIDPattern — допустимый внешний ID фильма: IMDb ID, числовой ID TMDB, ключ вида feed:123
+var externalIDPattern = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,64}$`)
+
+// UpsertMovieByExternalID создаёт или обновляет фильм по ID в каталоге-источнике. Тело
+// такое же, как у POST /movies, и задаёт фильм целиком; actor_ids, если переданы, заменяют состав
+func (c *movieController) UpsertMovieByExternalID(ctx *gin.Context, externalID string, req dto.CreateMovieRequest) (dto.MovieUpsertResponse, error) {
+	if !externalIDPattern.MatchString(externalID) {
+		return dto.MovieUpsertResponse{}, fmt.Errorf("validation error: %w", dto.ValidationErrors{dto.NewFieldError(dto.KeyMovieExternalIDInvalid)})
+	}
+	movie, err := movieFromCreateRequest(req)
+	if err != nil {
+		return dto.MovieUpsertResponse{}, err
+	}
+	movie.ExternalID = externalID
+	// Новый фильм можно создать с любым рейтингом; у существующего проверяем изменённые поля
+	existing, err := c.movieService.GetByExternalID(requestContext(ctx), externalID)
+	switch {
+	case err == nil:
+		if err := authorizeMovieFields(ctx, existing.ChangedFields(movie)...); err != nil {
+			return dto.MovieUpsertResponse{}, err
+		}
+	case !errors.Is(err, domain.ErrMovieNotFound):
+		return dto.MovieUpsertResponse{}, fmt.Errorf("getting movie by external ID: %w", err)
+	}
+
+	id, action, err := c.movieService.UpsertByExternalID(requestContext(ctx), movie, req.ActorIDs)
+	if err != nil {
+		return dto.MovieUpsertResponse{}, err
+	}
+	saved, err := c.movieService.GetByID(requestContext(ctx), id)
+	if err != nil {
+		return dto.MovieUpsertResponse{}, fmt.Errorf("getting upserted movie: %w", err)
+	}
+	return dto.MovieUpsertResponse{Action: string(action), Movie: mapper.Movie(saved)}, nil
+}
+
+// ResolveMergedMovieID возвращает ID фильма, в который был слит фильм с заданным ID
+func (c *movieController) ResolveMergedMovieID(ctx *gin.Context, id int) (int, error) {
+	return c.movieService.ResolveMergedMovieID(requestContext(ctx), id)
+}
+
+// GetUpcomingMovies возвращает фильмы, которые ещё не вышли, от ближайших к дальним
+func (c *movieController) GetUpcomingMovies(ctx *gin.Context) (dto.MoviesListResponse, error) {
+	movies, err := c.movieService.GetUpcomingMovies(requestContext(ctx))
+	if err != nil {
+		return dto.MoviesListResponse{}, err
+	}
+	return dto.MoviesListResponse{Movies: mapper.Movies(movies)}, nil
+}
+
+// GetPopularMovies возвращает самые просматриваемые фильмы; размер списка задаётся параметром limit
+func (c *movieController) GetPopularMovies(ctx *gin.Context) (dto.MoviesListResponse, error) {
+	limit, err := c.lists.Popular.limit(ctx)
+	if err != nil {
+		return dto.MoviesListResponse{}, err
+	}
+
+	movies, err := c.movieService.GetPopularMovies(requestContext(ctx), limit)
+	if err != nil {
+		return dto.MoviesListResponse{}, err
+	}
+	return dto.MoviesListResponse{Movies: mapper.Movies(movies)}, nil
+}
+
+// Годы, по которым можно просматривать каталог: /movies/year/:year принимает только четырёхзначный год
+const (
+	minBrowseYear = 1000
+	maxBrowseYear = 9999
+)
+
+// GetMoviesByYear возвращает фильмы, вышедшие в году year, и их общее число (?limit=, ?offset=)
+func (c *movieController) GetMoviesByYear(ctx *gin.Context, year string) (dto.MoviesByPeriodResponse, error) {
+	value, err := strconv.Atoi(year)
+	if err != nil || value < minBrowseYear || value > maxBrowseYear {
+		return dto.MoviesByPeriodResponse{}, fmt.Errorf("validation error: %w", dto.ValidationErrors{dto.NewFieldError(dto.KeyMovieYearInvalid)})
+	}
+	return c.moviesByYears(ctx, value, value)
+}
+
+// GetMoviesByDecade возвращает фильмы десятилетия, заданного первым годом (1990 или 1990s),
+// и их общее число (?limit=, ?offset=)
+func (c *movieController) GetMoviesByDecade(ctx *gin.Context, decade string) (dto.MoviesByPeriodResponse, error) {
+	value, err := strconv.Atoi(strings.TrimSuffix(decade, "s"))
+	if err != nil || value < minBrowseYear || value > maxBrowseYear || value%10 != 0 {
+		return dto.MoviesByPeriodResponse{}, fmt.Errorf("validation error: %w", dto.ValidationErrors{dto.NewFieldError(dto.KeyMovieDecadeInvalid)})
+	}
+	return c.moviesByYears(ctx, value, value+9)
+}
+
+// moviesByYears отдаёт страницу фильмов, вышедших с fromYear по toYear
+func (c *movieController) moviesByYears(ctx *gin.Context, fromYear, toYear int) (dto.MoviesByPeriodResponse, error) {
+	limit, err := c.lists.Movies.limit(ctx)
+	if err != nil {
+		return dto.MoviesByPeriodResponse{}, err
+	}
+	offset, err := parseNonNegativeQuery(ctx, "offset", dto.KeyListOffsetInvalid)
+	if err != nil {
+		return dto.MoviesByPeriodResponse{}, fmt.Errorf("validation error: %w", err)
+	}
+
+	movies, total, err := c.movieService.ListByYears(requestContext(ctx), fromYear, toYear, limit, offset)
+	if err != nil {
+		return dto.MoviesByPeriodResponse{}, err
+	}
+	return dto.MoviesByPeriodResponse{
+		FromYear:   fromYear,
+		ToYear:     toYear,
+		Total:      total,
+		Movies:     mapper.Movies(movies),
+		Pagination: newPagination(limit, offset, total),
+	}, nil
 }
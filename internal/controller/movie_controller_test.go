@@ -1,17 +1,21 @@
 package controller
 
 import (
+	"context"
 	"errors"
-	"fmt"
 	"net/http"
+	"net/http/httptest"
 	"net/url"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 
+	"cinematique/internal/auth"
 	"cinematique/internal/controller/dto"
 	"cinematique/internal/domain"
 )
@@ -24,86 +28,274 @@ type MockMovieService struct {
 	mock.Mock
 }
 
-func (m *MockMovieService) Create(movie domain.Movie, actorIDs []int) (int, error) {
-	args := m.Called(movie, actorIDs)
+func (m *MockMovieService) Create(_ context.Context, movie domain.Movie, actorIDs []int, force bool) (int, error) {
+	args := m.Called(movie, actorIDs, force)
 	return args.Int(0), args.Error(1)
 }
 
-func (m *MockMovieService) GetByID(id int) (domain.Movie, error) {
+func (m *MockMovieService) GetByID(_ context.Context, id int) (domain.Movie, error) {
 	args := m.Called(id)
 	return args.Get(0).(domain.Movie), args.Error(1)
 }
 
-func (m *MockMovieService) Update(movie domain.Movie, actorIDs []int) error {
+func (m *MockMovieService) GetBySlug(_ context.Context, slug string) (domain.Movie, error) {
+	args := m.Called(slug)
+	return args.Get(0).(domain.Movie), args.Error(1)
+}
+
+func (m *MockMovieService) Update(_ context.Context, movie domain.Movie, actorIDs []int) error {
 	args := m.Called(movie, actorIDs)
 	return args.Error(0)
 }
 
-func (m *MockMovieService) Delete(id int) error {
+func (m *MockMovieService) Delete(_ context.Context, id int) error {
 	args := m.Called(id)
 	return args.Error(0)
 }
 
-func (m *MockMovieService) GetAll() ([]domain.Movie, error) {
+func (m *MockMovieService) DeleteCreated(_ context.Context, movieID int, createdActorIDs []int) error {
+	args := m.Called(movieID, createdActorIDs)
+	return args.Error(0)
+}
+
+func (m *MockMovieService) GetAll(_ context.Context) ([]domain.Movie, error) {
 	args := m.Called()
 	return args.Get(0).([]domain.Movie), args.Error(1)
 }
 
-func (m *MockMovieService) AddActor(movieID, actorID int) error {
-	args := m.Called(movieID, actorID)
+// ExportMovies передаёт fn фильмы из первого аргумента Return и возвращает ошибку из второго
+func (m *MockMovieService) ExportMovies(_ context.Context, fn func(domain.Movie) error) error {
+	args := m.Called()
+	for _, movie := range args.Get(0).([]domain.Movie) {
+		if err := fn(movie); err != nil {
+			return err
+		}
+	}
+	return args.Error(1)
+}
+
+func (m *MockMovieService) GetMoviesAfterID(_ context.Context, afterID, limit int) ([]domain.Movie, bool, error) {
+	args := m.Called(afterID, limit)
+	return args.Get(0).([]domain.Movie), args.Bool(1), args.Error(2)
+}
+
+func (m *MockMovieService) AddActor(_ context.Context, movieID int, member domain.CastMember) error {
+	args := m.Called(movieID, member)
 	return args.Error(0)
 }
 
-func (m *MockMovieService) RemoveActor(movieID, actorID int) error {
+func (m *MockMovieService) RemoveActor(_ context.Context, movieID, actorID int) error {
 	args := m.Called(movieID, actorID)
 	return args.Error(0)
 }
 
-func (m *MockMovieService) GetActors(movieID int) ([]domain.Actor, error) {
+func (m *MockMovieService) GetActors(_ context.Context, movieID int) ([]domain.Actor, error) {
 	args := m.Called(movieID)
 	return args.Get(0).([]domain.Actor), args.Error(1)
 }
 
-func (m *MockMovieService) GetActorsForMovieByID(movieID int) ([]domain.Actor, error) {
+func (m *MockMovieService) GetActorsForMovieByID(_ context.Context, movieID int) ([]domain.Actor, error) {
 	args := m.Called(movieID)
 	return args.Get(0).([]domain.Actor), args.Error(1)
 }
 
-func (m *MockMovieService) GetMoviesForActor(actorID int) ([]domain.Movie, error) {
+func (m *MockMovieService) GetMoviesForActor(_ context.Context, actorID int) ([]domain.Movie, error) {
 	args := m.Called(actorID)
 	return args.Get(0).([]domain.Movie), args.Error(1)
 }
 
-func (m *MockMovieService) SearchMoviesByTitle(titleFragment string) ([]domain.Movie, error) {
-	args := m.Called(titleFragment)
+func (m *MockMovieService) SearchMoviesByTitle(_ context.Context, titleFragment string, filter domain.MovieFilter) ([]domain.Movie, error) {
+	args := m.Called(titleFragment, filter)
 	return args.Get(0).([]domain.Movie), args.Error(1)
 }
 
-func (m *MockMovieService) SearchMoviesByActorName(actorNameFragment string) ([]domain.Movie, error) {
-	args := m.Called(actorNameFragment)
+func (m *MockMovieService) SearchMoviesByActorName(_ context.Context, actorNameFragment string, filter domain.MovieFilter) ([]domain.Movie, error) {
+	args := m.Called(actorNameFragment, filter)
 	return args.Get(0).([]domain.Movie), args.Error(1)
 }
 
-func (m *MockMovieService) GetAllMoviesSorted(sortField, sortOrder string) ([]domain.Movie, error) {
-	args := m.Called(sortField, sortOrder)
+func (m *MockMovieService) GetMatchingActors(_ context.Context, movieIDs []int, nameFragment string) (map[int][]domain.Actor, error) {
+	args := m.Called(movieIDs, nameFragment)
+	return args.Get(0).(map[int][]domain.Actor), args.Error(1)
+}
+
+func (m *MockMovieService) GetAllMoviesSorted(_ context.Context, query domain.MovieListQuery) ([]domain.Movie, error) {
+	args := m.Called(query)
 	return args.Get(0).([]domain.Movie), args.Error(1)
 }
 
-func (m *MockMovieService) CreateMovieWithActors(movie domain.Movie, actorIDs []int) (int, error) {
+func (m *MockMovieService) CountMovies(_ context.Context, filter domain.MovieFilter) (int, error) {
+	args := m.Called(filter)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockMovieService) CreateMovieWithActors(_ context.Context, movie domain.Movie, actorIDs []int) (int, error) {
 	args := m.Called(movie, actorIDs)
 	return args.Int(0), args.Error(1)
 }
 
-func (m *MockMovieService) UpdateMovieActors(movieID int, actorIDs []int) error {
-	args := m.Called(movieID, actorIDs)
+func (m *MockMovieService) CreateFull(_ context.Context, movie domain.Movie, cast []domain.Actor, force bool) (domain.MovieImportResult, error) {
+	args := m.Called(movie, cast, force)
+	return args.Get(0).(domain.MovieImportResult), args.Error(1)
+}
+
+func (m *MockMovieService) UpdateMovieActors(_ context.Context, movieID int, cast []domain.CastMember) error {
+	args := m.Called(movieID, cast)
+	return args.Error(0)
+}
+
+func (m *MockMovieService) ChangeMovieActors(_ context.Context, movieID int, add []domain.CastMember, remove []int) error {
+	args := m.Called(movieID, add, remove)
 	return args.Error(0)
 }
 
-func (m *MockMovieService) PartialUpdateMovie(id int, update domain.MovieUpdate) error {
+func (m *MockMovieService) ReorderActors(_ context.Context, movieID int, actorIDs []int) ([]domain.Actor, error) {
+	args := m.Called(movieID, actorIDs)
+	return args.Get(0).([]domain.Actor), args.Error(1)
+}
+
+func (m *MockMovieService) PartialUpdateMovie(_ context.Context, id int, update domain.MovieUpdate) error {
 	args := m.Called(id, update)
 	return args.Error(0)
 }
 
+func (m *MockMovieService) BulkDeleteMovies(_ context.Context, ids []int) ([]domain.BulkItemResult, error) {
+	args := m.Called(ids)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.BulkItemResult), args.Error(1)
+}
+
+func (m *MockMovieService) BulkUpdateMovies(_ context.Context, ids []int, update domain.MovieUpdate) ([]domain.BulkItemResult, error) {
+	args := m.Called(ids, update)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.BulkItemResult), args.Error(1)
+}
+
+func (m *MockMovieService) MergeMovies(_ context.Context, keepID, dupID int) (domain.MovieMergeResult, error) {
+	args := m.Called(keepID, dupID)
+	return args.Get(0).(domain.MovieMergeResult), args.Error(1)
+}
+
+func (m *MockMovieService) ResolveMergedMovieID(_ context.Context, id int) (int, error) {
+	args := m.Called(id)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockMovieService) GetMovieAsOf(_ context.Context, id int, asOf time.Time) (domain.Movie, error) {
+	args := m.Called(id, asOf)
+	return args.Get(0).(domain.Movie), args.Error(1)
+}
+
+func (m *MockMovieService) GetRatingHistory(_ context.Context, movieID int) ([]domain.RatingChange, error) {
+	args := m.Called(movieID)
+	return args.Get(0).([]domain.RatingChange), args.Error(1)
+}
+
+func (m *MockMovieService) GetMovieHistory(_ context.Context, id int) ([]domain.MovieVersion, error) {
+	args := m.Called(id)
+	return args.Get(0).([]domain.MovieVersion), args.Error(1)
+}
+
+func (m *MockMovieService) GetRatings(_ context.Context, movieID int) ([]domain.MovieRating, *float64, error) {
+	args := m.Called(movieID)
+	display, _ := args.Get(1).(*float64)
+	return args.Get(0).([]domain.MovieRating), display, args.Error(2)
+}
+
+func (m *MockMovieService) SetRating(_ context.Context, rating domain.MovieRating) error {
+	args := m.Called(rating)
+	return args.Error(0)
+}
+
+func (m *MockMovieService) DeleteRating(_ context.Context, movieID int, source string) error {
+	args := m.Called(movieID, source)
+	return args.Error(0)
+}
+
+func (m *MockMovieService) GetAvailability(_ context.Context, movieID int) ([]domain.Availability, error) {
+	args := m.Called(movieID)
+	return args.Get(0).([]domain.Availability), args.Error(1)
+}
+
+func (m *MockMovieService) AddAvailability(_ context.Context, window domain.Availability) (domain.Availability, error) {
+	args := m.Called(window)
+	return args.Get(0).(domain.Availability), args.Error(1)
+}
+
+func (m *MockMovieService) UpdateAvailability(_ context.Context, window domain.Availability) error {
+	args := m.Called(window)
+	return args.Error(0)
+}
+
+func (m *MockMovieService) DeleteAvailability(_ context.Context, movieID, windowID int) error {
+	args := m.Called(movieID, windowID)
+	return args.Error(0)
+}
+
+func (m *MockMovieService) GetUpcomingMovies(_ context.Context) ([]domain.Movie, error) {
+	args := m.Called()
+	return args.Get(0).([]domain.Movie), args.Error(1)
+}
+
+func (m *MockMovieService) GetPopularMovies(_ context.Context, limit int) ([]domain.Movie, error) {
+	args := m.Called(limit)
+	return args.Get(0).([]domain.Movie), args.Error(1)
+}
+
+func (m *MockMovieService) ListByYears(_ context.Context, fromYear, toYear, limit, offset int) ([]domain.Movie, int, error) {
+	args := m.Called(fromYear, toYear, limit, offset)
+	return args.Get(0).([]domain.Movie), args.Int(1), args.Error(2)
+}
+
+func (m *MockMovieService) ImportExternal(ctx context.Context, imdbID string) (domain.MovieImportResult, error) {
+	args := m.Called(ctx, imdbID)
+	return args.Get(0).(domain.MovieImportResult), args.Error(1)
+}
+
+func (m *MockMovieService) UpsertByExternalID(_ context.Context, movie domain.Movie, actorIDs []int) (int, domain.MovieUpsertAction, error) {
+	args := m.Called(movie, actorIDs)
+	return args.Int(0), args.Get(1).(domain.MovieUpsertAction), args.Error(2)
+}
+
+func (m *MockMovieService) GetByExternalID(_ context.Context, externalID string) (domain.Movie, error) {
+	args := m.Called(externalID)
+	return args.Get(0).(domain.Movie), args.Error(1)
+}
+
+func (m *MockMovieService) GetTags(_ context.Context, movieID int) ([]string, error) {
+	args := m.Called(movieID)
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockMovieService) AddTag(_ context.Context, movieID int, tag string) ([]string, error) {
+	args := m.Called(movieID, tag)
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockMovieService) RemoveTag(_ context.Context, movieID int, tag string) error {
+	args := m.Called(movieID, tag)
+	return args.Error(0)
+}
+
+func (m *MockMovieService) ListCast(_ context.Context, movieID, limit, offset int) ([]domain.Actor, int, error) {
+	args := m.Called(movieID, limit, offset)
+	return args.Get(0).([]domain.Actor), args.Int(1), args.Error(2)
+}
+
+func (m *MockMovieService) ListByTag(_ context.Context, tag string, limit, offset int) ([]domain.Movie, int, error) {
+	args := m.Called(tag, limit, offset)
+	return args.Get(0).([]domain.Movie), args.Int(1), args.Error(2)
+}
+
+func (m *MockMovieService) SuggestTags(_ context.Context, prefix string, limit int) ([]domain.Tag, error) {
+	args := m.Called(prefix, limit)
+	return args.Get(0).([]domain.Tag), args.Error(1)
+}
+
 func TestMovieController_CreateMovie(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -121,7 +313,7 @@ func TestMovieController_CreateMovie(t *testing.T) {
 				ActorIDs:    []int{1, 2},
 			},
 			setupMock: func(mms *MockMovieService) {
-				mms.On("Create", mock.AnythingOfType("domain.Movie"), []int{1, 2}).
+				mms.On("Create", mock.AnythingOfType("domain.Movie"), []int{1, 2}, false).
 					Return(1, nil)
 				mms.On("GetByID", 1).
 					Return(domain.Movie{
@@ -145,6 +337,99 @@ func TestMovieController_CreateMovie(t *testing.T) {
 			setupMock:     func(mms *MockMovieService) {},
 			expectedError: true,
 		},
+		{
+			name: "runtime out of range",
+			req: dto.CreateMovieRequest{
+				Title:          "Shoah",
+				ReleaseYear:    1985,
+				Rating:         8.7,
+				RuntimeMinutes: 1000,
+			},
+			setupMock:     func(mms *MockMovieService) {},
+			expectedError: true,
+		},
+		{
+			name: "release date sets year",
+			req: dto.CreateMovieRequest{
+				Title:       "Test Movie",
+				Description: "Test Description",
+				ReleaseDate: "2027-03-05",
+				Rating:      8.5,
+			},
+			setupMock: func(mms *MockMovieService) {
+				releaseDate := time.Date(2027, time.March, 5, 0, 0, 0, 0, time.UTC)
+				mms.On("Create", mock.MatchedBy(func(m domain.Movie) bool {
+					return m.ReleaseYear == 2027 && m.ReleaseDate != nil && m.ReleaseDate.Equal(releaseDate)
+				}), []int(nil), false).Return(1, nil)
+				mms.On("GetByID", 1).
+					Return(domain.Movie{ID: 1, Title: "Test Movie", ReleaseYear: 2027, ReleaseDate: &releaseDate}, nil)
+			},
+			expectedError: false,
+		},
+		{
+			name: "language and country are normalized",
+			req: dto.CreateMovieRequest{
+				Title:            "Amélie",
+				ReleaseYear:      2001,
+				OriginalLanguage: "FR",
+				Country:          " fr",
+			},
+			setupMock: func(mms *MockMovieService) {
+				mms.On("Create", mock.MatchedBy(func(m domain.Movie) bool {
+					return m.OriginalLanguage == "fr" && m.Country == "FR"
+				}), []int(nil), false).Return(3, nil)
+				mms.On("GetByID", 3).Return(domain.Movie{ID: 3, Title: "Amélie", ReleaseYear: 2001, OriginalLanguage: "fr", Country: "FR"}, nil)
+			},
+			expectedError: false,
+		},
+		{
+			name: "unknown language code",
+			req: dto.CreateMovieRequest{
+				Title:            "Test Movie",
+				ReleaseYear:      2023,
+				OriginalLanguage: "english",
+			},
+			setupMock:     func(mms *MockMovieService) {},
+			expectedError: true,
+		},
+		{
+			name: "invalid release date",
+			req: dto.CreateMovieRequest{
+				Title:       "Test Movie",
+				Description: "Test Description",
+				ReleaseYear: 2023,
+				ReleaseDate: "05.03.2027",
+			},
+			setupMock:     func(mms *MockMovieService) {},
+			expectedError: true,
+		},
+		{
+			name: "likely duplicate",
+			req: dto.CreateMovieRequest{
+				Title:       "the matrix",
+				Description: "Test Description",
+				ReleaseYear: 1999,
+			},
+			setupMock: func(mms *MockMovieService) {
+				mms.On("Create", mock.AnythingOfType("domain.Movie"), []int(nil), false).
+					Return(0, &domain.DuplicateMovieError{ExistingID: 7})
+			},
+			expectedError: true,
+		},
+		{
+			name: "forced duplicate",
+			req: dto.CreateMovieRequest{
+				Title:       "the matrix",
+				Description: "Test Description",
+				ReleaseYear: 1999,
+				Force:       true,
+			},
+			setupMock: func(mms *MockMovieService) {
+				mms.On("Create", mock.AnythingOfType("domain.Movie"), []int(nil), true).Return(8, nil)
+				mms.On("GetByID", 8).Return(domain.Movie{ID: 8, Title: "the matrix", ReleaseYear: 1999}, nil)
+			},
+			expectedError: false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -224,12 +509,14 @@ func TestMovieController_UpdateMovie(t *testing.T) {
 	movieID := 1
 	tests := []struct {
 		name          string
+		role          string
 		req           dto.UpdateMovieRequest
 		setupMock     func(*MockMovieService)
 		expectedError bool
 	}{
 		{
 			name: "success",
+			role: domain.RoleAdmin,
 			req: dto.UpdateMovieRequest{
 				Title:       ptr("Updated Movie"),
 				Description: ptr("Updated Description"),
@@ -263,6 +550,25 @@ func TestMovieController_UpdateMovie(t *testing.T) {
 			},
 			expectedError: false,
 		},
+		{
+			name: "moderator resends unchanged rating",
+			role: domain.RoleModerator,
+			req:  dto.UpdateMovieRequest{Title: ptr("Updated Movie"), Rating: ptr(8.5)},
+			setupMock: func(mms *MockMovieService) {
+				mms.On("GetByID", movieID).Return(domain.Movie{ID: movieID, Title: "Original Movie", ReleaseYear: 2023, Rating: 8.5}, nil)
+				mms.On("Update", domain.Movie{ID: movieID, Title: "Updated Movie", ReleaseYear: 2023, Rating: 8.5}, []int(nil)).Return(nil)
+			},
+			expectedError: false,
+		},
+		{
+			name: "moderator cannot change rating",
+			role: domain.RoleModerator,
+			req:  dto.UpdateMovieRequest{Title: ptr("Updated Movie"), Rating: ptr(9.0)},
+			setupMock: func(mms *MockMovieService) {
+				mms.On("GetByID", movieID).Return(domain.Movie{ID: movieID, Title: "Original Movie", ReleaseYear: 2023, Rating: 8.5}, nil)
+			},
+			expectedError: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -271,8 +577,10 @@ func TestMovieController_UpdateMovie(t *testing.T) {
 			tt.setupMock(mockService)
 
 			controller := NewMovieController(mockService)
+			ctx := &gin.Context{}
+			auth.SetUser(ctx, &auth.UserContext{Role: tt.role})
 
-			_, err := controller.UpdateMovie(&gin.Context{}, movieID, tt.req)
+			_, err := controller.UpdateMovie(ctx, movieID, tt.req)
 
 			if tt.expectedError {
 				assert.Error(t, err)
@@ -331,22 +639,282 @@ func TestMovieController_DeleteMovie(t *testing.T) {
 }
 
 func TestMovieController_ListMovies(t *testing.T) {
+	newContext := func(query string) *gin.Context {
+		ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+		ctx.Request = httptest.NewRequest(http.MethodGet, "/movies?"+query, nil)
+		return ctx
+	}
+
+	t.Run("without cursor returns the first page of default size", func(t *testing.T) {
+		mockService := &MockMovieService{}
+		mockService.On("GetMoviesAfterID", 0, DefaultListDefaults().MovieCursor.Default).Return([]domain.Movie{
+			{ID: 1, Title: "Movie 1", Description: "Description 1", ReleaseYear: 2020, Rating: 8.5},
+		}, false, nil)
+
+		result, err := NewMovieController(mockService).ListMovies(newContext(""))
+
+		require.NoError(t, err)
+		assert.Equal(t, dto.MoviesListResponse{Movies: []dto.MovieResponse{
+			{ID: 1, Title: "Movie 1", Description: "Description 1", ReleaseYear: 2020, Rating: 8.5},
+		}}, result)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("without cursor honours limit and links the next page", func(t *testing.T) {
+		mockService := &MockMovieService{}
+		mockService.On("GetMoviesAfterID", 0, 2).Return([]domain.Movie{{ID: 1}, {ID: 3}}, true, nil)
+
+		result, err := NewMovieController(mockService).ListMovies(newContext("limit=2"))
+
+		require.NoError(t, err)
+		assert.Len(t, result.Movies, 2)
+		assert.Equal(t, encodeMovieCursor(3), result.NextCursor)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("without cursor rejects limit above the maximum", func(t *testing.T) {
+		mockService := &MockMovieService{}
+
+		_, err := NewMovieController(mockService).ListMovies(newContext("limit=101"))
+
+		var validationErrs dto.ValidationErrors
+		require.ErrorAs(t, err, &validationErrs)
+		assert.Equal(t, dto.KeyListLimitTooLarge, validationErrs[0].Key)
+		mockService.AssertNotCalled(t, "GetMoviesAfterID", mock.Anything, mock.Anything)
+	})
+
+	t.Run("empty list", func(t *testing.T) {
+		mockService := &MockMovieService{}
+		mockService.On("GetMoviesAfterID", 0, DefaultListDefaults().MovieCursor.Default).Return([]domain.Movie{}, false, nil)
+
+		result, err := NewMovieController(mockService).ListMovies(newContext(""))
+
+		require.NoError(t, err)
+		assert.Empty(t, result.Movies)
+		assert.Empty(t, result.NextCursor)
+	})
+
+	t.Run("service error", func(t *testing.T) {
+		mockService := &MockMovieService{}
+		mockService.On("GetMoviesAfterID", 0, DefaultListDefaults().MovieCursor.Default).Return([]domain.Movie(nil), false, errors.New("database error"))
+
+		_, err := NewMovieController(mockService).ListMovies(newContext(""))
+
+		assert.Error(t, err)
+	})
+}
+
+func TestMovieController_ListMoviesByCursor(t *testing.T) {
+	newContext := func(query string) *gin.Context {
+		ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+		ctx.Request = httptest.NewRequest(http.MethodGet, "/movies?"+query, nil)
+		return ctx
+	}
+
+	t.Run("first page", func(t *testing.T) {
+		mockService := &MockMovieService{}
+		mockService.On("GetMoviesAfterID", 0, 2).Return([]domain.Movie{{ID: 1, Title: "A"}, {ID: 4, Title: "B"}}, true, nil)
+
+		result, err := NewMovieController(mockService).ListMovies(newContext("cursor=&limit=2"))
+
+		require.NoError(t, err)
+		assert.Len(t, result.Movies, 2)
+		assert.Equal(t, encodeMovieCursor(4), result.NextCursor)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("next page is the last one", func(t *testing.T) {
+		mockService := &MockMovieService{}
+		mockService.On("GetMoviesAfterID", 4, DefaultListDefaults().MovieCursor.Default).Return([]domain.Movie{{ID: 7, Title: "C"}}, false, nil)
+
+		result, err := NewMovieController(mockService).ListMovies(newContext("cursor=" + encodeMovieCursor(4)))
+
+		require.NoError(t, err)
+		assert.Len(t, result.Movies, 1)
+		assert.Empty(t, result.NextCursor)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("invalid cursor", func(t *testing.T) {
+		mockService := &MockMovieService{}
+
+		_, err := NewMovieController(mockService).ListMovies(newContext("cursor=42"))
+
+		var validationErrs dto.ValidationErrors
+		require.ErrorAs(t, err, &validationErrs)
+		assert.Equal(t, dto.KeyListCursorInvalid, validationErrs[0].Key)
+		mockService.AssertNotCalled(t, "GetMoviesAfterID", mock.Anything, mock.Anything)
+	})
+
+	t.Run("limit too large", func(t *testing.T) {
+		_, err := NewMovieController(&MockMovieService{}).ListMovies(newContext("cursor=&limit=500"))
+		assert.Error(t, err)
+	})
+}
+
+// MockSearchService - мок сервиса поисковых подсказок
+type MockSearchService struct {
+	mock.Mock
+}
+
+func (m *MockSearchService) SuggestForTitle(_ context.Context, query string) (domain.SearchSuggestions, error) {
+	args := m.Called(query)
+	return args.Get(0).(domain.SearchSuggestions), args.Error(1)
+}
+
+func (m *MockSearchService) SuggestForActorName(_ context.Context, query string) (domain.SearchSuggestions, error) {
+	args := m.Called(query)
+	return args.Get(0).(domain.SearchSuggestions), args.Error(1)
+}
+
+func TestMovieController_SearchSuggestions(t *testing.T) {
+	newCtx := func(rawQuery string) *gin.Context {
+		ctx := &gin.Context{}
+		ctx.Request = &http.Request{URL: &url.URL{RawQuery: rawQuery}}
+		return ctx
+	}
+
+	t.Run("zero results by title", func(t *testing.T) {
+		movies := &MockMovieService{}
+		search := &MockSearchService{}
+		movies.On("SearchMoviesByTitle", "matrx", domain.MovieFilter{}).Return([]domain.Movie{}, nil)
+		search.On("SuggestForTitle", "matrx").Return(domain.SearchSuggestions{
+			DidYouMean:     []string{"The Matrix"},
+			RelatedQueries: []string{"matrix"},
+		}, nil)
+
+		result, err := NewMovieController(movies).WithSearch(search).SearchMoviesByTitle(newCtx("title=matrx"))
+		assert.NoError(t, err)
+		assert.Equal(t, &dto.SearchSuggestions{DidYouMean: []string{"The Matrix"}, RelatedQueries: []string{"matrix"}}, result.Suggestions)
+		search.AssertExpectations(t)
+	})
+
+	t.Run("zero results by actor", func(t *testing.T) {
+		movies := &MockMovieService{}
+		search := &MockSearchService{}
+		movies.On("SearchMoviesByActorName", "keanu reevs", domain.MovieFilter{}).Return([]domain.Movie{}, nil)
+		search.On("SuggestForActorName", "keanu reevs").Return(domain.SearchSuggestions{DidYouMean: []string{"Keanu Reeves"}}, nil)
+
+		result, err := NewMovieController(movies).WithSearch(search).SearchMoviesByActorName(newCtx("actorName=keanu+reevs"))
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"Keanu Reeves"}, result.Suggestions.DidYouMean)
+	})
+
+	t.Run("results found, no suggestions", func(t *testing.T) {
+		movies := &MockMovieService{}
+		search := &MockSearchService{}
+		movies.On("SearchMoviesByTitle", "matrix", domain.MovieFilter{}).Return([]domain.Movie{{ID: 1, Title: "The Matrix"}}, nil)
+
+		result, err := NewMovieController(movies).WithSearch(search).SearchMoviesByTitle(newCtx("title=matrix"))
+		assert.NoError(t, err)
+		assert.Nil(t, result.Suggestions)
+		search.AssertNotCalled(t, "SuggestForTitle", mock.Anything)
+	})
+
+	t.Run("suggestion error is not fatal", func(t *testing.T) {
+		movies := &MockMovieService{}
+		search := &MockSearchService{}
+		movies.On("SearchMoviesByTitle", "matrx", domain.MovieFilter{}).Return([]domain.Movie{}, nil)
+		search.On("SuggestForTitle", "matrx").Return(domain.SearchSuggestions{}, errors.New("db down"))
+
+		result, err := NewMovieController(movies).WithSearch(search).SearchMoviesByTitle(newCtx("title=matrx"))
+		assert.NoError(t, err)
+		assert.Nil(t, result.Suggestions)
+	})
+}
+
+func TestMovieController_SearchHighlight(t *testing.T) {
+	newCtx := func(rawQuery string) *gin.Context {
+		ctx := &gin.Context{}
+		ctx.Request = &http.Request{URL: &url.URL{RawQuery: rawQuery}}
+		return ctx
+	}
+
+	t.Run("title matches", func(t *testing.T) {
+		movies := &MockMovieService{}
+		movies.On("SearchMoviesByTitle", "mat", domain.MovieFilter{}).Return([]domain.Movie{
+			{ID: 1, Title: "The Matrix"},
+			{ID: 2, Title: "Матрица"},
+		}, nil)
+
+		result, err := NewMovieController(movies).SearchMoviesByTitle(newCtx("title=mat&highlight=true"))
+		require.NoError(t, err)
+		assert.Equal(t, []dto.SearchMatch{{Field: "title", Value: "The Matrix", Ranges: []dto.MatchRange{{Start: 4, End: 7}}}}, result.Movies[0].Matches)
+		// Название найдено с учётом опечаток: поле указано, диапазонов нет
+		assert.Equal(t, []dto.SearchMatch{{Field: "title", Value: "Матрица", Ranges: []dto.MatchRange{}}}, result.Movies[1].Matches)
+	})
+
+	t.Run("no matches without highlight", func(t *testing.T) {
+		movies := &MockMovieService{}
+		movies.On("SearchMoviesByTitle", "mat", domain.MovieFilter{}).Return([]domain.Movie{{ID: 1, Title: "The Matrix"}}, nil)
+
+		result, err := NewMovieController(movies).SearchMoviesByTitle(newCtx("title=mat"))
+		require.NoError(t, err)
+		assert.Nil(t, result.Movies[0].Matches)
+	})
+
+	t.Run("actor matches", func(t *testing.T) {
+		movies := &MockMovieService{}
+		movies.On("SearchMoviesByActorName", "ee", domain.MovieFilter{}).Return([]domain.Movie{{ID: 1, Title: "The Matrix"}, {ID: 2, Title: "Speed"}}, nil)
+		movies.On("GetMatchingActors", []int{1, 2}, "ee").Return(map[int][]domain.Actor{
+			1: {{ID: 7, Name: "Keanu Reeves"}},
+		}, nil)
+
+		result, err := NewMovieController(movies).SearchMoviesByActorName(newCtx("actorName=ee&highlight=1"))
+		require.NoError(t, err)
+		assert.Equal(t, []dto.SearchMatch{{
+			Field:   "actor",
+			Value:   "Keanu Reeves",
+			ActorID: 7,
+			Ranges:  []dto.MatchRange{{Start: 7, End: 9}},
+		}}, result.Movies[0].Matches)
+		assert.Nil(t, result.Movies[1].Matches)
+	})
+
+	t.Run("actor match error is not fatal", func(t *testing.T) {
+		movies := &MockMovieService{}
+		movies.On("SearchMoviesByActorName", "keanu", domain.MovieFilter{}).Return([]domain.Movie{{ID: 1}}, nil)
+		movies.On("GetMatchingActors", []int{1}, "keanu").Return(map[int][]domain.Actor(nil), errors.New("db down"))
+
+		result, err := NewMovieController(movies).SearchMoviesByActorName(newCtx("actorName=keanu&highlight=true"))
+		require.NoError(t, err)
+		assert.Len(t, result.Movies, 1)
+	})
+
+	t.Run("invalid highlight", func(t *testing.T) {
+		_, err := NewMovieController(&MockMovieService{}).SearchMoviesByTitle(newCtx("title=matrix&highlight=yes"))
+		var fieldErrs dto.ValidationErrors
+		require.ErrorAs(t, err, &fieldErrs)
+		assert.Equal(t, dto.KeyMovieSearchHighlight, fieldErrs[0].Key)
+	})
+}
+
+func TestMatchRanges(t *testing.T) {
+	assert.Equal(t, []dto.MatchRange{{Start: 0, End: 2}, {Start: 2, End: 4}}, matchRanges("aaaa", "AA"))
+	// Позиции в символах: кириллица занимает по два байта
+	assert.Equal(t, []dto.MatchRange{{Start: 0, End: 4}, {Start: 6, End: 10}}, matchRanges("Брат, брат 2", "БРАТ"))
+	assert.Empty(t, matchRanges("The Matrix", ""))
+}
+
+func TestMovieController_SearchMoviesByTitle(t *testing.T) {
 	tests := []struct {
 		name           string
+		query          string
 		setupMock      func(*MockMovieService)
 		expectedResult dto.MoviesListResponse
 		expectedError  bool
 	}{
 		{
-			name: "success",
+			name:  "success",
+			query: "test",
 			setupMock: func(mms *MockMovieService) {
-				mms.On("GetAll").Return([]domain.Movie{
+				mms.On("SearchMoviesByTitle", "test", domain.MovieFilter{}).Return([]domain.Movie{
 					{
 						ID:          1,
-						Title:       "Movie 1",
-						Description: "Description 1",
-						ReleaseYear: 2020,
-						Rating:      8.5,
+						Title:       "Test Movie",
+						Description: "Test Description",
+						ReleaseYear: 2023,
+						Rating:      7.5,
 					},
 				}, nil)
 			},
@@ -354,30 +922,22 @@ func TestMovieController_ListMovies(t *testing.T) {
 				Movies: []dto.MovieResponse{
 					{
 						ID:          1,
-						Title:       "Movie 1",
-						Description: "Description 1",
-						ReleaseYear: 2020,
-						Rating:      8.5,
+						Title:       "Test Movie",
+						Description: "Test Description",
+						ReleaseYear: 2023,
+						Rating:      7.5,
 					},
 				},
 			},
 			expectedError: false,
 		},
 		{
-			name: "empty list",
-			setupMock: func(mms *MockMovieService) {
-				mms.On("GetAll").Return([]domain.Movie{}, nil)
-			},
+			name:      "empty_query",
+			query:     "",
+			setupMock: nil,
 			expectedResult: dto.MoviesListResponse{
 				Movies: []dto.MovieResponse{},
 			},
-			expectedError: false,
-		},
-		{
-			name: "service error",
-			setupMock: func(mms *MockMovieService) {
-				mms.On("GetAll").Return([]domain.Movie{}, errors.New("database error"))
-			},
 			expectedError: true,
 		},
 	}
@@ -385,84 +945,17 @@ func TestMovieController_ListMovies(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mockService := &MockMovieService{}
-			tt.setupMock(mockService)
+			if tt.setupMock != nil {
+				tt.setupMock(mockService)
+			}
 
 			controller := NewMovieController(mockService)
 
-			result, err := controller.ListMovies(&gin.Context{})
-
-			if tt.expectedError {
-				assert.Error(t, err)
-			} else {
-				assert.NoError(t, err)
-				assert.Equal(t, tt.expectedResult, result)
-			}
-
-			mockService.AssertExpectations(t)
-		})
-	}
-}
-
-func TestMovieController_SearchMoviesByTitle(t *testing.T) {
-	tests := []struct {
-		name           string
-		query          string
-		setupMock      func(*MockMovieService)
-		expectedResult dto.MoviesListResponse
-		expectedError  bool
-	}{
-		{
-			name:  "success",
-			query: "test",
-			setupMock: func(mms *MockMovieService) {
-				mms.On("SearchMoviesByTitle", "test").Return([]domain.Movie{
-					{
-						ID:          1,
-						Title:       "Test Movie",
-						Description: "Test Description",
-						ReleaseYear: 2023,
-						Rating:      7.5,
-					},
-				}, nil)
-			},
-			expectedResult: dto.MoviesListResponse{
-				Movies: []dto.MovieResponse{
-					{
-						ID:          1,
-						Title:       "Test Movie",
-						Description: "Test Description",
-						ReleaseYear: 2023,
-						Rating:      7.5,
-					},
-				},
-			},
-			expectedError: false,
-		},
-		{
-			name:      "empty_query",
-			query:     "",
-			setupMock: nil,
-			expectedResult: dto.MoviesListResponse{
-				Movies: []dto.MovieResponse{},
-			},
-			expectedError: true,
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			mockService := &MockMovieService{}
-			if tt.setupMock != nil {
-				tt.setupMock(mockService)
-			}
-
-			controller := NewMovieController(mockService)
-
-			// Устанавливаем query параметр 'title', а не 'query'
-			ctx := &gin.Context{}
-			ctx.Request = &http.Request{
-				URL: &url.URL{RawQuery: "title=" + tt.query},
-			}
+			// Устанавливаем query параметр 'title', а не 'query'
+			ctx := &gin.Context{}
+			ctx.Request = &http.Request{
+				URL: &url.URL{RawQuery: "title=" + tt.query},
+			}
 
 			result, err := controller.SearchMoviesByTitle(ctx)
 
@@ -483,42 +976,237 @@ func TestMovieController_SearchMoviesByTitle(t *testing.T) {
 	}
 }
 
+func TestMovieController_SearchQueryTooShort(t *testing.T) {
+	newCtx := func(rawQuery string) *gin.Context {
+		ctx := &gin.Context{}
+		ctx.Request = &http.Request{URL: &url.URL{RawQuery: rawQuery}}
+		return ctx
+	}
+	mockService := &MockMovieService{}
+	controller := NewMovieController(mockService)
+
+	_, err := controller.SearchMoviesByTitle(newCtx("title=a"))
+	var errs dto.ValidationErrors
+	require.ErrorAs(t, err, &errs)
+	assert.Equal(t, dto.KeyMovieSearchTitleShort, errs[0].Key)
+
+	_, err = controller.SearchMoviesByActorName(newCtx("actorName=%D0%AF"))
+	require.ErrorAs(t, err, &errs)
+	assert.Equal(t, dto.KeyMovieSearchActorShort, errs[0].Key)
+
+	// Двухбуквенный запрос на кириллице проходит: длина считается в символах, а не в байтах
+	mockService.On("SearchMoviesByTitle", "Яг", domain.MovieFilter{}).Return([]domain.Movie{}, nil)
+	_, err = controller.SearchMoviesByTitle(newCtx("title=%D0%AF%D0%B3"))
+	assert.NoError(t, err)
+	mockService.AssertExpectations(t)
+}
+
+func TestMovieController_SearchMoviesFilter(t *testing.T) {
+	newCtx := func(rawQuery string) *gin.Context {
+		ctx := &gin.Context{}
+		ctx.Request = &http.Request{URL: &url.URL{RawQuery: rawQuery}}
+		return ctx
+	}
+
+	t.Run("title with language and country", func(t *testing.T) {
+		movies := &MockMovieService{}
+		movies.On("SearchMoviesByTitle", "amelie", domain.MovieFilter{OriginalLanguage: "fr", Country: "FR"}).
+			Return([]domain.Movie{{ID: 3, Title: "Amélie", OriginalLanguage: "fr", Country: "FR"}}, nil)
+
+		result, err := NewMovieController(movies).SearchMoviesByTitle(newCtx("title=amelie&language=FR&country=fr"))
+		assert.NoError(t, err)
+		assert.Len(t, result.Movies, 1)
+		movies.AssertExpectations(t)
+	})
+
+	t.Run("filter without title skips suggestions", func(t *testing.T) {
+		movies := &MockMovieService{}
+		search := &MockSearchService{}
+		movies.On("SearchMoviesByTitle", "", domain.MovieFilter{Country: "KR"}).Return([]domain.Movie{}, nil)
+
+		result, err := NewMovieController(movies).WithSearch(search).SearchMoviesByTitle(newCtx("country=KR"))
+		assert.NoError(t, err)
+		assert.Nil(t, result.Suggestions)
+		search.AssertNotCalled(t, "SuggestForTitle", mock.Anything)
+	})
+
+	t.Run("actor name with language", func(t *testing.T) {
+		movies := &MockMovieService{}
+		movies.On("SearchMoviesByActorName", "toshiro", domain.MovieFilter{OriginalLanguage: "ja"}).Return([]domain.Movie{}, nil)
+
+		_, err := NewMovieController(movies).SearchMoviesByActorName(newCtx("actorName=toshiro&language=ja"))
+		assert.NoError(t, err)
+		movies.AssertExpectations(t)
+	})
+
+	t.Run("invalid codes", func(t *testing.T) {
+		movies := &MockMovieService{}
+
+		_, err := NewMovieController(movies).SearchMoviesByTitle(newCtx("title=amelie&language=fre&country=XX"))
+		var verrs dto.ValidationErrors
+		require.ErrorAs(t, err, &verrs)
+		assert.Equal(t, []string{"language", "country"}, []string{verrs[0].Field, verrs[1].Field})
+		movies.AssertNotCalled(t, "SearchMoviesByTitle", mock.Anything, mock.Anything)
+	})
+
+	t.Run("available in region", func(t *testing.T) {
+		movies := &MockMovieService{}
+		movies.On("SearchMoviesByTitle", "", mock.MatchedBy(func(filter domain.MovieFilter) bool {
+			return filter.Region == "DE" && filter.Available != nil && *filter.Available &&
+				filter.AvailableOn.Format("2006-01-02") == time.Now().UTC().Format("2006-01-02")
+		})).Return([]domain.Movie{}, nil)
+
+		_, err := NewMovieController(movies).SearchMoviesByTitle(newCtx("region=de&available=true"))
+		assert.NoError(t, err)
+		movies.AssertExpectations(t)
+	})
+
+	t.Run("invalid region and availability", func(t *testing.T) {
+		movies := &MockMovieService{}
+
+		_, err := NewMovieController(movies).SearchMoviesByTitle(newCtx("region=XX&available=maybe"))
+		var verrs dto.ValidationErrors
+		require.ErrorAs(t, err, &verrs)
+		assert.Equal(t, []string{dto.KeyMovieSearchRegion, dto.KeyMovieSearchAvailable}, []string{verrs[0].Key, verrs[1].Key})
+		movies.AssertNotCalled(t, "SearchMoviesByTitle", mock.Anything, mock.Anything)
+	})
+
+	t.Run("runtime range", func(t *testing.T) {
+		movies := &MockMovieService{}
+		movies.On("SearchMoviesByTitle", "", domain.MovieFilter{RuntimeMin: 90, RuntimeMax: 120}).Return([]domain.Movie{}, nil)
+
+		_, err := NewMovieController(movies).SearchMoviesByTitle(newCtx("runtime_min=90&runtime_max=120"))
+		assert.NoError(t, err)
+		movies.AssertExpectations(t)
+	})
+
+	t.Run("invalid runtime bounds", func(t *testing.T) {
+		tests := []struct {
+			query string
+			keys  []string
+		}{
+			{"runtime_min=0", []string{dto.KeyMovieSearchRuntimeMin}},
+			{"runtime_min=long&runtime_max=1000", []string{dto.KeyMovieSearchRuntimeMin, dto.KeyMovieSearchRuntimeMax}},
+			{"runtime_min=150&runtime_max=90", []string{dto.KeyMovieSearchRuntimeRange}},
+		}
+		for _, tt := range tests {
+			movies := &MockMovieService{}
+
+			_, err := NewMovieController(movies).SearchMoviesByTitle(newCtx(tt.query))
+			var verrs dto.ValidationErrors
+			require.ErrorAs(t, err, &verrs, tt.query)
+			var keys []string
+			for _, fe := range verrs {
+				keys = append(keys, fe.Key)
+			}
+			assert.Equal(t, tt.keys, keys, tt.query)
+			movies.AssertNotCalled(t, "SearchMoviesByTitle", mock.Anything, mock.Anything)
+		}
+	})
+}
+
 func TestMovieController_GetAllMoviesSorted(t *testing.T) {
+	movies := []domain.Movie{
+		{
+			ID:          1,
+			Title:       "A Movie",
+			Description: "Description",
+			ReleaseYear: 2020,
+			Rating:      8.0,
+		},
+	}
+	movieResponses := []dto.MovieResponse{
+		{
+			ID:          1,
+			Title:       "A Movie",
+			Description: "Description",
+			ReleaseYear: 2020,
+			Rating:      8.0,
+		},
+	}
+
 	tests := []struct {
 		name           string
-		sortField      string
-		sortOrder      string
+		rawQuery       string
 		setupMock      func(*MockMovieService)
 		expectedResult dto.MoviesListResponse
 		expectedError  bool
 	}{
 		{
-			name:      "default sort by rating desc",
-			sortField: "",
-			sortOrder: "",
+			name:     "default sort and page size",
+			rawQuery: "",
 			setupMock: func(mms *MockMovieService) {
-				mms.On("GetAllMoviesSorted", "rating", "DESC").Return([]domain.Movie{
-					{
-						ID:          1,
-						Title:       "A Movie",
-						Description: "Description",
-						ReleaseYear: 2020,
-						Rating:      8.0,
-					},
-				}, nil)
+				mms.On("GetAllMoviesSorted", domain.MovieListQuery{
+					Sort:  []domain.SortField{{Field: "rating", Order: "desc"}},
+					Limit: 20,
+				}).Return(movies, nil)
 			},
 			expectedResult: dto.MoviesListResponse{
-				Movies: []dto.MovieResponse{
-					{
-						ID:          1,
-						Title:       "A Movie",
-						Description: "Description",
-						ReleaseYear: 2020,
-						Rating:      8.0,
-					},
-				},
+				Movies:     movieResponses,
+				Pagination: &dto.Pagination{Limit: 20, Total: 1, TotalPages: 1},
 			},
-			expectedError: false,
+		},
+		{
+			name:     "multiple fields with pagination",
+			rawQuery: "sort=rating:DESC,title&limit=10&offset=20",
+			setupMock: func(mms *MockMovieService) {
+				mms.On("GetAllMoviesSorted", domain.MovieListQuery{
+					Sort:   []domain.SortField{{Field: "rating", Order: "desc"}, {Field: "title", Order: ""}},
+					Limit:  10,
+					Offset: 20,
+				}).Return(movies, nil)
+			},
+			expectedResult: dto.MoviesListResponse{
+				Movies:     movieResponses,
+				Pagination: &dto.Pagination{Limit: 10, Offset: 20, Total: 21, TotalPages: 3},
+			},
+		},
+		{
+			name:     "legacy parameters",
+			rawQuery: "sort_field=title&sort_order=ASC",
+			setupMock: func(mms *MockMovieService) {
+				mms.On("GetAllMoviesSorted", domain.MovieListQuery{
+					Sort:  []domain.SortField{{Field: "title", Order: "asc"}},
+					Limit: 20,
+				}).Return(movies, nil)
+			},
+			expectedResult: dto.MoviesListResponse{
+				Movies:     movieResponses,
+				Pagination: &dto.Pagination{Limit: 20, Total: 1, TotalPages: 1},
+			},
+		},
+		{
+			name:     "full page counts total",
+			rawQuery: "sort=title&limit=1",
+			setupMock: func(mms *MockMovieService) {
+				mms.On("GetAllMoviesSorted", domain.MovieListQuery{
+					Sort:  []domain.SortField{{Field: "title", Order: ""}},
+					Limit: 1,
+				}).Return(movies, nil)
+				mms.On("CountMovies", domain.MovieFilter{}).Return(4, nil)
+			},
+			expectedResult: dto.MoviesListResponse{
+				Movies:     movieResponses,
+				Pagination: &dto.Pagination{Limit: 1, Total: 4, TotalPages: 4},
+			},
+		},
+		{
+			name:          "empty sort field",
+			rawQuery:      "sort=rating:desc,,title",
+			setupMock:     func(mms *MockMovieService) {},
+			expectedError: true,
+		},
+		{
+			name:          "limit too large",
+			rawQuery:      "limit=1000",
+			setupMock:     func(mms *MockMovieService) {},
+			expectedError: true,
+		},
+		{
+			name:          "negative offset",
+			rawQuery:      "limit=10&offset=-1",
+			setupMock:     func(mms *MockMovieService) {},
+			expectedError: true,
 		},
 	}
 
@@ -532,15 +1220,14 @@ func TestMovieController_GetAllMoviesSorted(t *testing.T) {
 			// Устанавливаем query параметры
 			ctx := &gin.Context{}
 			ctx.Request = &http.Request{
-				URL: &url.URL{
-					RawQuery: fmt.Sprintf("sort_by=%s&order=%s", tt.sortField, tt.sortOrder),
-				},
+				URL: &url.URL{RawQuery: tt.rawQuery},
 			}
 
 			result, err := controller.GetAllMoviesSorted(ctx)
 
 			if tt.expectedError {
 				assert.Error(t, err)
+				assert.Contains(t, err.Error(), "validation error")
 			} else {
 				assert.NoError(t, err)
 				assert.Equal(t, tt.expectedResult, result)
@@ -636,7 +1323,7 @@ func TestMovieController_AddActorToMovie(t *testing.T) {
 			movieID: 1,
 			actorID: 2,
 			setupMock: func(mms *MockMovieService) {
-				mms.On("AddActor", 1, 2).Return(nil)
+				mms.On("AddActor", 1, domain.CastMember{ActorID: 2}).Return(nil)
 				mms.On("GetByID", 1).Return(domain.Movie{
 					ID:          1,
 					Title:       "Test Movie",
@@ -652,7 +1339,7 @@ func TestMovieController_AddActorToMovie(t *testing.T) {
 			movieID: 999,
 			actorID: 1,
 			setupMock: func(mms *MockMovieService) {
-				mms.On("AddActor", 999, 1).Return(errors.New("movie not found"))
+				mms.On("AddActor", 999, domain.CastMember{ActorID: 1}).Return(errors.New("movie not found"))
 			},
 			expectedError: true,
 		},
@@ -665,7 +1352,7 @@ func TestMovieController_AddActorToMovie(t *testing.T) {
 
 			controller := NewMovieController(mockService)
 
-			_, err := controller.AddActorToMovie(&gin.Context{}, tt.movieID, tt.actorID)
+			_, err := controller.AddActorToMovie(&gin.Context{}, tt.movieID, tt.actorID, dto.AddActorToMovieRequest{})
 
 			if tt.expectedError {
 				assert.Error(t, err)
@@ -764,6 +1451,7 @@ func TestMovieController_GetActorsForMovieByID(t *testing.T) {
 						Name:      "Actor 1",
 						Gender:    "male",
 						BirthDate: "1990-01-01",
+						Age:       ageToday("1990-01-01"),
 					},
 				},
 			},
@@ -868,12 +1556,16 @@ func TestMovieController_GetMoviesForActor(t *testing.T) {
 }
 
 func TestMovieController_PartialUpdateMovie(t *testing.T) {
+	releaseDate := time.Date(1999, 3, 31, 0, 0, 0, 0, time.UTC)
 	tests := []struct {
 		name          string
+		role          string
 		movieID       int
 		update        dto.MovieUpdate
 		setupMock     func(*MockMovieService)
-		expectedError bool
+		expected      dto.MovieResponse
+		expectedError error
+		expectedKeys  []string // ключи ошибок валидации
 	}{
 		{
 			name:    "update title",
@@ -882,22 +1574,82 @@ func TestMovieController_PartialUpdateMovie(t *testing.T) {
 				Title: ptr("Updated Title"),
 			},
 			setupMock: func(mms *MockMovieService) {
+				mms.On("PartialUpdateMovie", 1, domain.MovieUpdate{Title: ptr("Updated Title")}).Return(nil)
 				mms.On("GetByID", 1).Return(domain.Movie{
-					ID:          1,
-					Title:       "Old Title",
-					Description: "Description",
-					ReleaseYear: 2020,
-					Rating:      8.0,
-				}, nil)
-				mms.On("Update", domain.Movie{
 					ID:          1,
 					Title:       "Updated Title",
 					Description: "Description",
 					ReleaseYear: 2020,
 					Rating:      8.0,
-				}, []int{}).Return(nil)
+				}, nil)
 			},
-			expectedError: false,
+			expected: dto.MovieResponse{ID: 1, Title: "Updated Title", Description: "Description", ReleaseYear: 2020, Rating: 8.0},
+		},
+		{
+			name:    "set release date",
+			movieID: 2,
+			update:  dto.MovieUpdate{ReleaseDate: ptr("1999-03-31")},
+			setupMock: func(mms *MockMovieService) {
+				mms.On("PartialUpdateMovie", 2, domain.MovieUpdate{ReleaseDate: &releaseDate}).Return(nil)
+				mms.On("GetByID", 2).Return(domain.Movie{ID: 2, Title: "The Matrix", ReleaseYear: 1999, ReleaseDate: &releaseDate}, nil)
+			},
+			expected: dto.MovieResponse{ID: 2, Title: "The Matrix", ReleaseYear: 1999, ReleaseDate: "1999-03-31"},
+		},
+		{
+			name:    "clear release date",
+			movieID: 2,
+			update:  dto.MovieUpdate{ReleaseDate: ptr("")},
+			setupMock: func(mms *MockMovieService) {
+				mms.On("PartialUpdateMovie", 2, domain.MovieUpdate{ClearReleaseDate: true}).Return(nil)
+				mms.On("GetByID", 2).Return(domain.Movie{ID: 2, Title: "The Matrix", ReleaseYear: 1999}, nil)
+			},
+			expected: dto.MovieResponse{ID: 2, Title: "The Matrix", ReleaseYear: 1999},
+		},
+		{
+			name:    "set language and clear country",
+			movieID: 3,
+			update:  dto.MovieUpdate{OriginalLanguage: ptr("JA"), Country: ptr("")},
+			setupMock: func(mms *MockMovieService) {
+				mms.On("PartialUpdateMovie", 3, domain.MovieUpdate{OriginalLanguage: ptr("ja"), Country: ptr("")}).Return(nil)
+				mms.On("GetByID", 3).Return(domain.Movie{ID: 3, Title: "Spirited Away", ReleaseYear: 2001, OriginalLanguage: "ja"}, nil)
+			},
+			expected: dto.MovieResponse{ID: 3, Title: "Spirited Away", ReleaseYear: 2001, OriginalLanguage: "ja"},
+		},
+		{
+			name:    "set runtime",
+			movieID: 3,
+			update:  dto.MovieUpdate{RuntimeMinutes: ptr(125)},
+			setupMock: func(mms *MockMovieService) {
+				mms.On("PartialUpdateMovie", 3, domain.MovieUpdate{RuntimeMinutes: ptr(125)}).Return(nil)
+				mms.On("GetByID", 3).Return(domain.Movie{ID: 3, Title: "Spirited Away", ReleaseYear: 2001, RuntimeMinutes: 125}, nil)
+			},
+			expected: dto.MovieResponse{ID: 3, Title: "Spirited Away", ReleaseYear: 2001, RuntimeMinutes: 125},
+		},
+		{
+			name:    "admin sets rating",
+			role:    domain.RoleAdmin,
+			movieID: 1,
+			update:  dto.MovieUpdate{Rating: ptr(8.8)},
+			setupMock: func(mms *MockMovieService) {
+				mms.On("PartialUpdateMovie", 1, domain.MovieUpdate{Rating: ptr(8.8)}).Return(nil)
+				mms.On("GetByID", 1).Return(domain.Movie{ID: 1, Title: "Updated Title", ReleaseYear: 2020, Rating: 8.8}, nil)
+			},
+			expected: dto.MovieResponse{ID: 1, Title: "Updated Title", ReleaseYear: 2020, Rating: 8.8},
+		},
+		{
+			name:          "moderator cannot set rating",
+			role:          domain.RoleModerator,
+			movieID:       1,
+			update:        dto.MovieUpdate{Title: ptr("Updated Title"), Rating: ptr(8.8)},
+			setupMock:     func(mms *MockMovieService) {},
+			expectedError: domain.ErrFieldsForbidden,
+		},
+		{
+			name:         "invalid fields are rejected before the service",
+			movieID:      1,
+			update:       dto.MovieUpdate{Title: ptr(" "), Rating: ptr(11.0), Country: ptr("USA"), RuntimeMinutes: ptr(-90)},
+			setupMock:    func(mms *MockMovieService) {},
+			expectedKeys: []string{dto.KeyMovieTitleRequired, dto.KeyMovieRatingOutOfRange, dto.KeyMovieCountryInvalid, dto.KeyMovieRuntimeInvalid},
 		},
 		{
 			name:    "movie not found",
@@ -906,9 +1658,9 @@ func TestMovieController_PartialUpdateMovie(t *testing.T) {
 				Title: ptr("New Title"),
 			},
 			setupMock: func(mms *MockMovieService) {
-				mms.On("GetByID", 999).Return(domain.Movie{}, errors.New("movie not found"))
+				mms.On("PartialUpdateMovie", 999, domain.MovieUpdate{Title: ptr("New Title")}).Return(domain.ErrMovieNotFound)
 			},
-			expectedError: true,
+			expectedError: domain.ErrMovieNotFound,
 		},
 	}
 
@@ -919,15 +1671,1027 @@ func TestMovieController_PartialUpdateMovie(t *testing.T) {
 
 			controller := NewMovieController(mockService)
 
-			err := controller.PartialUpdateMovie(&gin.Context{}, tt.movieID, tt.update)
+			ctx := &gin.Context{}
+			auth.SetUser(ctx, &auth.UserContext{Role: tt.role})
+
+			resp, err := controller.PartialUpdateMovie(ctx, tt.movieID, tt.update)
+
+			switch {
+			case tt.expectedKeys != nil:
+				var verrs dto.ValidationErrors
+				require.ErrorAs(t, err, &verrs)
+				var keys []string
+				for _, fe := range verrs {
+					keys = append(keys, fe.Key)
+				}
+				assert.Equal(t, tt.expectedKeys, keys)
+			case tt.expectedError != nil:
+				assert.ErrorIs(t, err, tt.expectedError)
+			default:
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, resp)
+			}
 
-			if tt.expectedError {
-				assert.Error(t, err)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestMovieController_MergeMovies(t *testing.T) {
+	tests := []struct {
+		name          string
+		req           dto.MergeMoviesRequest
+		setupMock     func(*MockMovieService)
+		expected      dto.MovieMergeResponse
+		expectedError error
+	}{
+		{
+			name: "success",
+			req:  dto.MergeMoviesRequest{KeepID: 1, DuplicateID: 2},
+			setupMock: func(mms *MockMovieService) {
+				mms.On("MergeMovies", 1, 2).Return(domain.MovieMergeResult{
+					Movie: domain.Movie{
+						ID:          1,
+						Title:       "Inception",
+						Description: "Dream heist",
+						ReleaseYear: 2010,
+						Rating:      8.8,
+						Actors:      []domain.Actor{{ID: 5, Name: "Leonardo DiCaprio"}},
+					},
+					DuplicateID:      2,
+					ActorsReassigned: 1,
+				}, nil)
+			},
+			expected: dto.MovieMergeResponse{
+				Movie: dto.MovieResponse{
+					ID:          1,
+					Title:       "Inception",
+					Description: "Dream heist",
+					ReleaseYear: 2010,
+					Rating:      8.8,
+					Actors:      []dto.ActorPreview{{ID: 5, Name: "Leonardo DiCaprio"}},
+				},
+				DuplicateID:      2,
+				ActorsReassigned: 1,
+			},
+		},
+		{
+			name: "movie not found",
+			req:  dto.MergeMoviesRequest{KeepID: 1, DuplicateID: 999},
+			setupMock: func(mms *MockMovieService) {
+				mms.On("MergeMovies", 1, 999).Return(domain.MovieMergeResult{}, domain.ErrMovieNotFound)
+			},
+			expectedError: domain.ErrMovieNotFound,
+		},
+		{
+			name: "same movie",
+			req:  dto.MergeMoviesRequest{KeepID: 1, DuplicateID: 1},
+			setupMock: func(mms *MockMovieService) {
+				mms.On("MergeMovies", 1, 1).Return(domain.MovieMergeResult{}, domain.ErrMergeSameMovie)
+			},
+			expectedError: domain.ErrMergeSameMovie,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockMovieService{}
+			tt.setupMock(mockService)
+
+			controller := NewMovieController(mockService)
+
+			result, err := controller.MergeMovies(&gin.Context{}, tt.req)
+
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
 			} else {
 				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, result)
 			}
 
 			mockService.AssertExpectations(t)
 		})
 	}
 }
+
+func TestMovieController_BulkUpdateMovies(t *testing.T) {
+	txErr := errors.New("connection refused")
+	tests := []struct {
+		name          string
+		req           dto.BulkUpdateMoviesRequest
+		setupMock     func(*MockMovieService)
+		expected      dto.BulkOperationResponse
+		expectedKeys  []string
+		expectedError error
+	}{
+		{
+			name: "duplicates are removed and failures reported per movie",
+			req:  dto.BulkUpdateMoviesRequest{IDs: []int{1, 2, 1}, Changes: dto.BulkMovieChanges{Rating: ptr(7.5), Country: ptr("fr")}},
+			setupMock: func(mms *MockMovieService) {
+				mms.On("BulkUpdateMovies", []int{1, 2}, domain.MovieUpdate{Rating: ptr(7.5), Country: ptr("FR")}).Return([]domain.BulkItemResult{
+					{ID: 1},
+					{ID: 2, Err: domain.ErrMovieNotFound},
+				}, nil)
+			},
+			expected: dto.BulkOperationResponse{
+				Succeeded: 1,
+				Failed:    1,
+				Results: []dto.BulkItemResult{
+					{ID: 1, Status: "ok"},
+					{ID: 2, Status: "failed", Code: "movie_not_found", Error: "movie not found"},
+				},
+			},
+		},
+		{
+			name:         "empty ids and changes",
+			req:          dto.BulkUpdateMoviesRequest{},
+			setupMock:    func(mms *MockMovieService) {},
+			expectedKeys: []string{dto.KeyBulkIDsRequired, dto.KeyBulkChangesRequired},
+		},
+		{
+			name:         "invalid id and rating",
+			req:          dto.BulkUpdateMoviesRequest{IDs: []int{1, 0}, Changes: dto.BulkMovieChanges{Rating: ptr(11.0)}},
+			setupMock:    func(mms *MockMovieService) {},
+			expectedKeys: []string{dto.KeyBulkIDInvalid, dto.KeyMovieRatingOutOfRange},
+		},
+		{
+			name:         "too many ids",
+			req:          dto.BulkUpdateMoviesRequest{IDs: make([]int, maxBulkMovies+1), Changes: dto.BulkMovieChanges{Rating: ptr(5.0)}},
+			setupMock:    func(mms *MockMovieService) {},
+			expectedKeys: []string{dto.KeyBulkIDsTooMany},
+		},
+		{
+			name: "transaction error",
+			req:  dto.BulkUpdateMoviesRequest{IDs: []int{1}, Changes: dto.BulkMovieChanges{OriginalLanguage: ptr("en")}},
+			setupMock: func(mms *MockMovieService) {
+				mms.On("BulkUpdateMovies", []int{1}, domain.MovieUpdate{OriginalLanguage: ptr("en")}).Return(nil, txErr)
+			},
+			expectedError: txErr,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockMovieService{}
+			tt.setupMock(mockService)
+
+			controller := NewMovieController(mockService)
+
+			ctx := &gin.Context{}
+			auth.SetUser(ctx, &auth.UserContext{Role: domain.RoleAdmin})
+
+			resp, err := controller.BulkUpdateMovies(ctx, tt.req)
+
+			switch {
+			case tt.expectedKeys != nil:
+				var verrs dto.ValidationErrors
+				require.ErrorAs(t, err, &verrs)
+				var keys []string
+				for _, fe := range verrs {
+					keys = append(keys, fe.Key)
+				}
+				assert.Equal(t, tt.expectedKeys, keys)
+			case tt.expectedError != nil:
+				assert.ErrorIs(t, err, tt.expectedError)
+			default:
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, resp)
+			}
+
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestMovieController_BulkDeleteMovies(t *testing.T) {
+	mockService := &MockMovieService{}
+	mockService.On("BulkDeleteMovies", []int{3, 4}).Return([]domain.BulkItemResult{{ID: 3}, {ID: 4}}, nil)
+
+	controller := NewMovieController(mockService)
+	resp, err := controller.BulkDeleteMovies(&gin.Context{}, dto.BulkDeleteMoviesRequest{IDs: []int{3, 4}})
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, resp.Succeeded)
+	assert.Equal(t, 0, resp.Failed)
+	assert.Equal(t, []dto.BulkItemResult{{ID: 3, Status: "ok"}, {ID: 4, Status: "ok"}}, resp.Results)
+	mockService.AssertExpectations(t)
+}
+
+func TestMovieController_UpdateMovieActors(t *testing.T) {
+	t.Run("cast with roles then plain actor ids", func(t *testing.T) {
+		mockService := &MockMovieService{}
+		mockService.On("UpdateMovieActors", 1, []domain.CastMember{
+			{ActorID: 2, CharacterName: "Cobb", BillingOrder: 1},
+			{ActorID: 3},
+		}).Return(nil)
+		mockService.On("GetActors", 1).Return([]domain.Actor{
+			{ID: 2, Name: "Leonardo DiCaprio"},
+			{ID: 4, Name: "Ken Watanabe"},
+		}, nil).Once()
+		mockService.On("GetActors", 1).Return([]domain.Actor{
+			{ID: 2, Name: "Leonardo DiCaprio", CharacterName: "Cobb", BillingOrder: 1},
+			{ID: 3, Name: "Tom Hardy"},
+		}, nil).Once()
+
+		controller := NewMovieController(mockService)
+		resp, err := controller.UpdateMovieActors(&gin.Context{}, 1, dto.UpdateMovieActorsRequest{
+			Cast:     []dto.CastMemberRequest{{ActorID: 2, CharacterName: " Cobb ", BillingOrder: 1}},
+			ActorIDs: []int{3, 2},
+		})
+
+		require.NoError(t, err)
+		require.Len(t, resp.Actors, 2)
+		assert.Equal(t, "Cobb", resp.Actors[0].CharacterName)
+		assert.Equal(t, 1, resp.Actors[0].BillingOrder)
+		assert.Equal(t, []int{3}, resp.AddedActorIDs)
+		assert.Equal(t, []int{4}, resp.RemovedActorIDs)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("validation", func(t *testing.T) {
+		controller := NewMovieController(&MockMovieService{})
+
+		_, err := controller.UpdateMovieActors(&gin.Context{}, 1, dto.UpdateMovieActorsRequest{})
+		var errs dto.ValidationErrors
+		require.ErrorAs(t, err, &errs)
+		assert.Equal(t, dto.KeyCastRequired, errs[0].Key)
+
+		_, err = controller.UpdateMovieActors(&gin.Context{}, 1, dto.UpdateMovieActorsRequest{
+			Cast: []dto.CastMemberRequest{{ActorID: 2, CharacterName: strings.Repeat("x", 256), BillingOrder: -1}},
+		})
+		require.ErrorAs(t, err, &errs)
+		assert.Len(t, errs, 2)
+	})
+}
+
+func TestMovieController_ChangeMovieActors(t *testing.T) {
+	t.Run("adds and removes actors", func(t *testing.T) {
+		mockService := &MockMovieService{}
+		mockService.On("GetActors", 1).Return([]domain.Actor{
+			{ID: 2, Name: "Leonardo DiCaprio"},
+			{ID: 4, Name: "Ken Watanabe"},
+		}, nil).Once()
+		mockService.On("ChangeMovieActors", 1, []domain.CastMember{{ActorID: 3, CharacterName: "Eames"}}, []int{4}).Return(nil)
+		mockService.On("GetActors", 1).Return([]domain.Actor{
+			{ID: 2, Name: "Leonardo DiCaprio"},
+			{ID: 3, Name: "Tom Hardy", CharacterName: "Eames"},
+		}, nil).Once()
+
+		controller := NewMovieController(mockService)
+		resp, err := controller.ChangeMovieActors(&gin.Context{}, 1, dto.ChangeMovieActorsRequest{
+			Add:    []dto.CastMemberRequest{{ActorID: 3, CharacterName: " Eames "}, {ActorID: 3}},
+			Remove: []int{4, 4},
+		})
+
+		require.NoError(t, err)
+		require.Len(t, resp.Actors, 2)
+		assert.Equal(t, []int{3}, resp.AddedActorIDs)
+		assert.Equal(t, []int{4}, resp.RemovedActorIDs)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("validation", func(t *testing.T) {
+		controller := NewMovieController(&MockMovieService{})
+
+		_, err := controller.ChangeMovieActors(&gin.Context{}, 1, dto.ChangeMovieActorsRequest{})
+		var errs dto.ValidationErrors
+		require.ErrorAs(t, err, &errs)
+		assert.Equal(t, dto.KeyCastChangeRequired, errs[0].Key)
+
+		_, err = controller.ChangeMovieActors(&gin.Context{}, 1, dto.ChangeMovieActorsRequest{
+			Add:    []dto.CastMemberRequest{{ActorID: 3}},
+			Remove: []int{5, 0, 3},
+		})
+		require.ErrorAs(t, err, &errs)
+		require.Len(t, errs, 2)
+		assert.Equal(t, dto.KeyCastRemoveInvalid, errs[0].Key)
+		assert.Equal(t, 1, *errs[0].Index)
+		assert.Equal(t, dto.KeyCastChangeConflict, errs[1].Key)
+		assert.Equal(t, 2, *errs[1].Index)
+	})
+}
+
+func TestMovieController_ReorderMovieActors(t *testing.T) {
+	t.Run("reordered cast", func(t *testing.T) {
+		mockService := &MockMovieService{}
+		mockService.On("ReorderActors", 1, []int{3, 2}).Return([]domain.Actor{
+			{ID: 3, Name: "Tom Hardy", BillingOrder: 1},
+			{ID: 2, Name: "Leonardo DiCaprio", BillingOrder: 2},
+		}, nil)
+
+		controller := NewMovieController(mockService)
+		resp, err := controller.ReorderMovieActors(&gin.Context{}, 1, dto.ReorderMovieActorsRequest{ActorIDs: []int{3, 2}})
+
+		require.NoError(t, err)
+		require.Len(t, resp.Actors, 2)
+		assert.Equal(t, 3, resp.Actors[0].ID)
+		assert.Equal(t, 1, resp.Actors[0].BillingOrder)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("validation", func(t *testing.T) {
+		controller := NewMovieController(&MockMovieService{})
+
+		_, err := controller.ReorderMovieActors(&gin.Context{}, 1, dto.ReorderMovieActorsRequest{})
+		var errs dto.ValidationErrors
+		require.ErrorAs(t, err, &errs)
+		assert.Equal(t, dto.KeyCastOrderRequired, errs[0].Key)
+
+		_, err = controller.ReorderMovieActors(&gin.Context{}, 1, dto.ReorderMovieActorsRequest{ActorIDs: []int{2, 0, 2}})
+		require.ErrorAs(t, err, &errs)
+		require.Len(t, errs, 2)
+		assert.Equal(t, dto.KeyCastOrderInvalid, errs[0].Key)
+		assert.Equal(t, 1, *errs[0].Index)
+		assert.Equal(t, dto.KeyCastOrderDuplicate, errs[1].Key)
+		assert.Equal(t, 2, *errs[1].Index)
+	})
+}
+
+func TestMovieController_GetUpcomingMovies(t *testing.T) {
+	mockService := &MockMovieService{}
+	releaseDate := time.Date(2027, time.March, 5, 0, 0, 0, 0, time.UTC)
+	mockService.On("GetUpcomingMovies").
+		Return([]domain.Movie{{ID: 3, Title: "Sequel", ReleaseYear: 2027, ReleaseDate: &releaseDate}}, nil)
+
+	controller := NewMovieController(mockService)
+	resp, err := controller.GetUpcomingMovies(&gin.Context{})
+
+	assert.NoError(t, err)
+	assert.Len(t, resp.Movies, 1)
+	assert.Equal(t, "2027-03-05", resp.Movies[0].ReleaseDate)
+	mockService.AssertExpectations(t)
+}
+
+func TestMovieController_WithListDefaults(t *testing.T) {
+	query := func(raw string) *gin.Context {
+		ctx := &gin.Context{}
+		ctx.Request = &http.Request{URL: &url.URL{RawQuery: raw}}
+		return ctx
+	}
+
+	t.Run("configured sort and page sizes", func(t *testing.T) {
+		mockService := &MockMovieService{}
+		mockService.On("GetAllMoviesSorted", domain.MovieListQuery{
+			Sort:  []domain.SortField{{Field: "release_year", Order: "desc"}, {Field: "title", Order: "asc"}},
+			Limit: 30,
+		}).Return([]domain.Movie{}, nil)
+		controller := NewMovieController(mockService).WithListDefaults(ListDefaults{
+			MovieSort: "release_year:desc,title:asc",
+			Movies:    PageSize{Default: 30, Max: 50},
+		})
+
+		resp, err := controller.GetAllMoviesSorted(query(""))
+		require.NoError(t, err)
+		assert.Equal(t, &dto.Pagination{Limit: 30}, resp.Pagination)
+
+		_, err = controller.GetAllMoviesSorted(query("limit=51"))
+		var errs dto.ValidationErrors
+		require.ErrorAs(t, err, &errs)
+		assert.Equal(t, dto.KeyListLimitTooLarge, errs[0].Key)
+		assert.Equal(t, "max=50", errs[0].Expected)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("invalid values fall back to defaults", func(t *testing.T) {
+		mockService := &MockMovieService{}
+		mockService.On("GetAllMoviesSorted", domain.MovieListQuery{
+			Sort:  []domain.SortField{{Field: "rating", Order: "desc"}},
+			Limit: 5,
+		}).Return([]domain.Movie{}, nil)
+		controller := NewMovieController(mockService).WithListDefaults(ListDefaults{
+			MovieSort: "rating,,title",
+			Movies:    PageSize{Default: 200, Max: 5},
+		})
+
+		_, err := controller.GetAllMoviesSorted(query(""))
+		require.NoError(t, err)
+		mockService.AssertExpectations(t)
+	})
+}
+
+func TestMovieController_GetPopularMovies(t *testing.T) {
+	tests := []struct {
+		name      string
+		rawQuery  string
+		wantLimit int
+		wantErr   bool
+	}{
+		{name: "default limit", rawQuery: "", wantLimit: DefaultListDefaults().Popular.Default},
+		{name: "explicit limit", rawQuery: "limit=3", wantLimit: 3},
+		{name: "limit too large", rawQuery: "limit=1000", wantErr: true},
+		{name: "invalid limit", rawQuery: "limit=abc", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockMovieService{}
+			if !tt.wantErr {
+				mockService.On("GetPopularMovies", tt.wantLimit).
+					Return([]domain.Movie{{ID: 1, Title: "Inception", ViewCount: 42}}, nil)
+			}
+
+			ctx := &gin.Context{}
+			ctx.Request = &http.Request{URL: &url.URL{RawQuery: tt.rawQuery}}
+			resp, err := NewMovieController(mockService).GetPopularMovies(ctx)
+
+			if tt.wantErr {
+				assert.ErrorContains(t, err, "validation error")
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, []dto.MovieResponse{{ID: 1, Title: "Inception", ViewCount: 42}}, resp.Movies)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestMovieController_GetMoviesByPeriod(t *testing.T) {
+	defaultLimit := DefaultListDefaults().Movies.Default
+	tests := []struct {
+		name     string
+		decade   bool
+		param    string
+		rawQuery string
+		wantFrom int
+		wantTo   int
+		wantKey  string
+	}{
+		{name: "year", param: "1999", wantFrom: 1999, wantTo: 1999},
+		{name: "decade", decade: true, param: "1990", rawQuery: "limit=5&offset=10", wantFrom: 1990, wantTo: 1999},
+		{name: "decade with suffix", decade: true, param: "1980s", wantFrom: 1980, wantTo: 1989},
+		{name: "year not a number", param: "latest", wantKey: dto.KeyMovieYearInvalid},
+		{name: "year too short", param: "99", wantKey: dto.KeyMovieYearInvalid},
+		{name: "decade not aligned", decade: true, param: "1995", wantKey: dto.KeyMovieDecadeInvalid},
+		{name: "invalid offset", param: "1999", rawQuery: "offset=-1", wantKey: dto.KeyListOffsetInvalid},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockMovieService{}
+			limit, offset := defaultLimit, 0
+			if tt.rawQuery != "" {
+				limit, offset = 5, 10
+			}
+			if tt.wantKey == "" {
+				mockService.On("ListByYears", tt.wantFrom, tt.wantTo, limit, offset).
+					Return([]domain.Movie{{ID: 1, Title: "The Matrix", ReleaseYear: 1999}}, 31, nil)
+			}
+
+			ctx := &gin.Context{}
+			ctx.Request = &http.Request{URL: &url.URL{RawQuery: tt.rawQuery}}
+			controller := NewMovieController(mockService)
+			var resp dto.MoviesByPeriodResponse
+			var err error
+			if tt.decade {
+				resp, err = controller.GetMoviesByDecade(ctx, tt.param)
+			} else {
+				resp, err = controller.GetMoviesByYear(ctx, tt.param)
+			}
+
+			if tt.wantKey != "" {
+				var validationErrs dto.ValidationErrors
+				require.ErrorAs(t, err, &validationErrs)
+				assert.Equal(t, tt.wantKey, validationErrs[0].Key)
+				mockService.AssertNotCalled(t, "ListByYears", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, dto.MoviesByPeriodResponse{
+				FromYear:   tt.wantFrom,
+				ToYear:     tt.wantTo,
+				Total:      31,
+				Movies:     []dto.MovieResponse{{ID: 1, Title: "The Matrix", ReleaseYear: 1999}},
+				Pagination: &dto.Pagination{Limit: limit, Offset: offset, Total: 31, TotalPages: (31 + limit - 1) / limit},
+			}, resp)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestMovieController_GetMovieCast(t *testing.T) {
+	t.Run("page", func(t *testing.T) {
+		mockService := &MockMovieService{}
+		mockService.On("ListCast", 1, 2, 2).
+			Return([]domain.Actor{{ID: 5, Name: "Hugo Weaving", CharacterName: "Agent Smith", BillingOrder: 3}}, 3, nil)
+
+		ctx := &gin.Context{Request: &http.Request{URL: &url.URL{RawQuery: "limit=2&offset=2"}}}
+		resp, err := NewMovieController(mockService).GetMovieCast(ctx, 1)
+		require.NoError(t, err)
+		assert.Equal(t, dto.MovieCastResponse{
+			MovieID:    1,
+			CastCount:  3,
+			Cast:       []dto.CastMemberResponse{{ID: 5, Name: "Hugo Weaving", CharacterName: "Agent Smith", BillingOrder: 3}},
+			Pagination: &dto.Pagination{Limit: 2, Offset: 2, Total: 3, TotalPages: 2},
+		}, resp)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("limit above maximum", func(t *testing.T) {
+		ctx := &gin.Context{Request: &http.Request{URL: &url.URL{RawQuery: "limit=201"}}}
+		_, err := NewMovieController(&MockMovieService{}).GetMovieCast(ctx, 1)
+
+		var validationErrs dto.ValidationErrors
+		require.ErrorAs(t, err, &validationErrs)
+		assert.Equal(t, dto.KeyListLimitTooLarge, validationErrs[0].Key)
+	})
+}
+
+func TestMovieController_GetRatingHistory(t *testing.T) {
+	newCtx := func() *gin.Context {
+		return &gin.Context{Request: &http.Request{URL: &url.URL{}}}
+	}
+
+	t.Run("success", func(t *testing.T) {
+		mockService := &MockMovieService{}
+		oldRating := 8.5
+		changedAt := time.Date(2024, 3, 1, 12, 0, 0, 0, time.FixedZone("MSK", 3*60*60))
+		mockService.On("GetRatingHistory", 1).Return([]domain.RatingChange{
+			{ID: 1, MovieID: 1, NewRating: 8.5, ChangedAt: changedAt.Add(-time.Hour)},
+			{ID: 2, MovieID: 1, OldRating: &oldRating, NewRating: 8.8, ChangedAt: changedAt},
+		}, nil)
+
+		resp, err := NewMovieController(mockService).GetRatingHistory(newCtx(), 1)
+
+		require.NoError(t, err)
+		assert.Equal(t, 1, resp.MovieID)
+		require.Len(t, resp.History, 2)
+		assert.Nil(t, resp.History[0].OldRating)
+		assert.Equal(t, &oldRating, resp.History[1].OldRating)
+		assert.Equal(t, 8.8, resp.History[1].NewRating)
+		assert.Equal(t, time.UTC, resp.History[1].ChangedAt.Location())
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("movie not found", func(t *testing.T) {
+		mockService := &MockMovieService{}
+		mockService.On("GetRatingHistory", 999).Return([]domain.RatingChange(nil), domain.ErrMovieNotFound)
+
+		_, err := NewMovieController(mockService).GetRatingHistory(newCtx(), 999)
+
+		assert.ErrorIs(t, err, domain.ErrMovieNotFound)
+	})
+}
+
+func TestMovieController_GetMovieHistory(t *testing.T) {
+	newCtx := func() *gin.Context {
+		return &gin.Context{Request: &http.Request{URL: &url.URL{}}}
+	}
+
+	t.Run("success", func(t *testing.T) {
+		mockService := &MockMovieService{}
+		released := time.Date(1999, 3, 31, 0, 0, 0, 0, time.UTC)
+		changedAt := time.Date(2024, 3, 1, 12, 0, 0, 0, time.FixedZone("MSK", 3*60*60))
+		mockService.On("GetMovieHistory", 1).Return([]domain.MovieVersion{
+			{
+				Version: 1, ChangedAt: changedAt.Add(-time.Hour),
+				Movie:   domain.Movie{ID: 1, Title: "The Matrix", ReleaseYear: 1999},
+				Changes: []domain.FieldChange{{Field: "title", New: "The Matrix"}, {Field: "release_year", New: 1999}},
+			},
+			{
+				Version: 2, ChangedAt: changedAt,
+				Movie:   domain.Movie{ID: 1, Title: "The Matrix", ReleaseYear: 1999, ReleaseDate: &released},
+				Changes: []domain.FieldChange{{Field: "release_date", Old: (*time.Time)(nil), New: &released}},
+			},
+			{Version: 3, ChangedAt: changedAt.Add(time.Hour), Deleted: true, Movie: domain.Movie{ID: 1}},
+		}, nil)
+
+		resp, err := NewMovieController(mockService).GetMovieHistory(newCtx(), 1)
+
+		require.NoError(t, err)
+		assert.Equal(t, 1, resp.MovieID)
+		require.Len(t, resp.Versions, 3)
+		assert.Equal(t, "The Matrix", resp.Versions[0].Movie.Title)
+		assert.Equal(t, []dto.FieldChangeResponse{{Field: "release_date", Old: nil, New: "1999-03-31"}}, resp.Versions[1].Changes)
+		assert.Equal(t, time.UTC, resp.Versions[1].ChangedAt.Location())
+		assert.True(t, resp.Versions[2].Deleted)
+		assert.Nil(t, resp.Versions[2].Movie)
+		assert.Empty(t, resp.Versions[2].Changes)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("movie not found", func(t *testing.T) {
+		mockService := &MockMovieService{}
+		mockService.On("GetMovieHistory", 999).Return([]domain.MovieVersion(nil), domain.ErrMovieNotFound)
+
+		_, err := NewMovieController(mockService).GetMovieHistory(newCtx(), 999)
+
+		assert.ErrorIs(t, err, domain.ErrMovieNotFound)
+	})
+}
+
+func TestMovieController_Ratings(t *testing.T) {
+	newCtx := func() *gin.Context {
+		ctx := &gin.Context{Request: &http.Request{URL: &url.URL{}}}
+		auth.SetUser(ctx, &auth.UserContext{Role: domain.RoleAdmin})
+		return ctx
+	}
+	display := 8.3
+	ratings := []domain.MovieRating{
+		{MovieID: 1, Source: domain.RatingSourceInternal, Rating: 8},
+		{MovieID: 1, Source: domain.RatingSourceRottenTomatoes, Rating: 88},
+	}
+
+	t.Run("set returns all ratings", func(t *testing.T) {
+		mockService := &MockMovieService{}
+		mockService.On("SetRating", domain.MovieRating{MovieID: 1, Source: domain.RatingSourceRottenTomatoes, Rating: 88}).Return(nil)
+		mockService.On("GetRatings", 1).Return(ratings, &display, nil)
+
+		rating := 88.0
+		resp, err := NewMovieController(mockService).SetMovieRating(newCtx(), 1, domain.RatingSourceRottenTomatoes, dto.MovieRatingRequest{Rating: &rating})
+
+		require.NoError(t, err)
+		assert.Equal(t, dto.MovieRatingsResponse{
+			MovieID:       1,
+			Ratings:       map[string]float64{"internal": 8, "rotten_tomatoes": 88},
+			DisplayRating: &display,
+		}, resp)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("set validates source and scale", func(t *testing.T) {
+		mockService := &MockMovieService{}
+		controller := NewMovieController(mockService)
+		rating := 11.0
+
+		var verrs dto.ValidationErrors
+		_, err := controller.SetMovieRating(newCtx(), 1, domain.RatingSourceInternal, dto.MovieRatingRequest{Rating: &rating})
+		require.ErrorAs(t, err, &verrs)
+		assert.Equal(t, dto.KeyRatingSourceInvalid, verrs[0].Key)
+
+		_, err = controller.SetMovieRating(newCtx(), 1, domain.RatingSourceIMDb, dto.MovieRatingRequest{Rating: &rating})
+		require.ErrorAs(t, err, &verrs)
+		assert.Equal(t, dto.KeyRatingValueOutOfRange, verrs[0].Key)
+		assert.Equal(t, "max=10", verrs[0].Expected)
+
+		_, err = controller.SetMovieRating(newCtx(), 1, domain.RatingSourceIMDb, dto.MovieRatingRequest{})
+		require.ErrorAs(t, err, &verrs)
+		assert.Equal(t, dto.KeyRatingValueRequired, verrs[0].Key)
+		mockService.AssertNotCalled(t, "SetRating", mock.Anything)
+	})
+
+	t.Run("delete unknown rating", func(t *testing.T) {
+		mockService := &MockMovieService{}
+		mockService.On("DeleteRating", 1, domain.RatingSourceIMDb).Return(domain.ErrRatingNotFound)
+
+		err := NewMovieController(mockService).DeleteMovieRating(newCtx(), 1, domain.RatingSourceIMDb)
+
+		assert.ErrorIs(t, err, domain.ErrRatingNotFound)
+	})
+
+	t.Run("moderator cannot set or delete ratings", func(t *testing.T) {
+		mockService := &MockMovieService{}
+		controller := NewMovieController(mockService)
+		ctx := newCtx()
+		auth.SetUser(ctx, &auth.UserContext{Role: domain.RoleModerator})
+		rating := 7.5
+
+		var forbiddenErr *domain.FieldsForbiddenError
+		_, err := controller.SetMovieRating(ctx, 1, domain.RatingSourceIMDb, dto.MovieRatingRequest{Rating: &rating})
+		require.ErrorAs(t, err, &forbiddenErr)
+		assert.Equal(t, []string{"rating"}, forbiddenErr.Fields)
+
+		err = controller.DeleteMovieRating(ctx, 1, domain.RatingSourceIMDb)
+		require.ErrorAs(t, err, &forbiddenErr)
+		assert.Equal(t, []string{"rating"}, forbiddenErr.Fields)
+		mockService.AssertNotCalled(t, "SetRating", mock.Anything)
+		mockService.AssertNotCalled(t, "DeleteRating", mock.Anything, mock.Anything)
+	})
+}
+
+func TestMovieController_Availability(t *testing.T) {
+	newCtx := func() *gin.Context {
+		return &gin.Context{Request: &http.Request{URL: &url.URL{}}}
+	}
+	from := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	until := time.Date(2024, time.June, 30, 0, 0, 0, 0, time.UTC)
+
+	t.Run("list", func(t *testing.T) {
+		mockService := &MockMovieService{}
+		mockService.On("GetAvailability", 1).Return([]domain.Availability{
+			{ID: 1, MovieID: 1, Region: "DE", AvailableFrom: from, AvailableUntil: &until},
+			{ID: 2, MovieID: 1, Region: "US", AvailableFrom: from},
+		}, nil)
+
+		resp, err := NewMovieController(mockService).GetAvailability(newCtx(), 1)
+
+		require.NoError(t, err)
+		assert.Equal(t, dto.AvailabilityListResponse{MovieID: 1, Availability: []dto.AvailabilityResponse{
+			{ID: 1, Region: "DE", AvailableFrom: "2024-01-01", AvailableUntil: "2024-06-30"},
+			{ID: 2, Region: "US", AvailableFrom: "2024-01-01"},
+		}}, resp)
+	})
+
+	t.Run("add normalizes region", func(t *testing.T) {
+		mockService := &MockMovieService{}
+		window := domain.Availability{MovieID: 1, Region: "DE", AvailableFrom: from, AvailableUntil: &until}
+		saved := window
+		saved.ID = 5
+		mockService.On("AddAvailability", window).Return(saved, nil)
+
+		resp, err := NewMovieController(mockService).AddAvailability(newCtx(), 1, dto.AvailabilityRequest{
+			Region: "de", AvailableFrom: "2024-01-01", AvailableUntil: "2024-06-30",
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, dto.AvailabilityResponse{ID: 5, Region: "DE", AvailableFrom: "2024-01-01", AvailableUntil: "2024-06-30"}, resp)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("add validates region and dates", func(t *testing.T) {
+		mockService := &MockMovieService{}
+
+		_, err := NewMovieController(mockService).AddAvailability(newCtx(), 1, dto.AvailabilityRequest{
+			Region: "XX", AvailableFrom: "2024-06-30", AvailableUntil: "2024-01-01",
+		})
+		var verrs dto.ValidationErrors
+		require.ErrorAs(t, err, &verrs)
+		assert.Equal(t, []string{dto.KeyAvailabilityRegion, dto.KeyAvailabilityUntilBefore}, []string{verrs[0].Key, verrs[1].Key})
+
+		_, err = NewMovieController(mockService).AddAvailability(newCtx(), 1, dto.AvailabilityRequest{
+			Region: "DE", AvailableFrom: "01.01.2024", AvailableUntil: "soon",
+		})
+		require.ErrorAs(t, err, &verrs)
+		assert.Equal(t, []string{dto.KeyAvailabilityFrom, dto.KeyAvailabilityUntil}, []string{verrs[0].Key, verrs[1].Key})
+		mockService.AssertNotCalled(t, "AddAvailability", mock.Anything)
+	})
+
+	t.Run("update unknown window", func(t *testing.T) {
+		mockService := &MockMovieService{}
+		mockService.On("UpdateAvailability", domain.Availability{ID: 9, MovieID: 1, Region: "FR", AvailableFrom: from}).
+			Return(domain.ErrAvailabilityNotFound)
+
+		_, err := NewMovieController(mockService).UpdateAvailability(newCtx(), 1, 9, dto.AvailabilityRequest{Region: "FR", AvailableFrom: "2024-01-01"})
+
+		assert.ErrorIs(t, err, domain.ErrAvailabilityNotFound)
+	})
+
+	t.Run("delete on missing movie", func(t *testing.T) {
+		mockService := &MockMovieService{}
+		mockService.On("DeleteAvailability", 999, 1).Return(domain.ErrMovieNotFound)
+
+		err := NewMovieController(mockService).DeleteAvailability(newCtx(), 999, 1)
+
+		assert.ErrorIs(t, err, domain.ErrMovieNotFound)
+	})
+}
+
+func TestMovieController_ImportExternalMovie(t *testing.T) {
+	newCtx := func() *gin.Context {
+		return &gin.Context{Request: &http.Request{URL: &url.URL{}}}
+	}
+
+	t.Run("invalid imdb id", func(t *testing.T) {
+		mockService := &MockMovieService{}
+		_, err := NewMovieController(mockService).ImportExternalMovie(newCtx(), "0133093")
+
+		var validationErrs dto.ValidationErrors
+		assert.ErrorAs(t, err, &validationErrs)
+		assert.Equal(t, dto.KeyMovieIMDbIDInvalid, validationErrs[0].Key)
+		mockService.AssertNotCalled(t, "ImportExternal", mock.Anything, mock.Anything)
+	})
+
+	t.Run("imported", func(t *testing.T) {
+		mockService := &MockMovieService{}
+		mockService.On("ImportExternal", mock.Anything, "tt0133093").
+			Return(domain.MovieImportResult{MovieID: 5, ActorIDs: []int{1, 2}, ActorsCreated: 1, CreatedActorIDs: []int{2}}, nil)
+		mockService.On("GetByID", 5).
+			Return(domain.Movie{ID: 5, Title: "The Matrix", ReleaseYear: 1999, Actors: []domain.Actor{{ID: 1, Name: "Keanu Reeves"}, {ID: 2, Name: "Carrie-Anne Moss"}}}, nil)
+
+		resp, err := NewMovieController(mockService).ImportExternalMovie(newCtx(), "tt0133093")
+
+		assert.NoError(t, err)
+		assert.Equal(t, 5, resp.Movie.ID)
+		assert.Len(t, resp.Movie.Actors, 2)
+		assert.Equal(t, 1, resp.ActorsCreated)
+		assert.Equal(t, []int{2}, resp.CreatedActorIDs)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("duplicate", func(t *testing.T) {
+		mockService := &MockMovieService{}
+		mockService.On("ImportExternal", mock.Anything, "tt0133093").
+			Return(domain.MovieImportResult{}, &domain.DuplicateMovieError{ExistingID: 3})
+
+		_, err := NewMovieController(mockService).ImportExternalMovie(newCtx(), "tt0133093")
+
+		assert.ErrorIs(t, err, domain.ErrDuplicateMovie)
+	})
+}
+
+func TestMovieController_UpsertMovieByExternalID(t *testing.T) {
+	newCtx := func() *gin.Context {
+		return &gin.Context{Request: &http.Request{URL: &url.URL{}}}
+	}
+	req := dto.CreateMovieRequest{Title: "The Matrix", ReleaseYear: 1999, Rating: 8.7, OriginalLanguage: "EN"}
+
+	t.Run("invalid external id", func(t *testing.T) {
+		mockService := &MockMovieService{}
+		_, err := NewMovieController(mockService).UpsertMovieByExternalID(newCtx(), "tt 0133093", req)
+
+		var validationErrs dto.ValidationErrors
+		require.ErrorAs(t, err, &validationErrs)
+		assert.Equal(t, dto.KeyMovieExternalIDInvalid, validationErrs[0].Key)
+		mockService.AssertNotCalled(t, "UpsertByExternalID", mock.Anything, mock.Anything)
+	})
+
+	t.Run("invalid movie", func(t *testing.T) {
+		mockService := &MockMovieService{}
+		_, err := NewMovieController(mockService).UpsertMovieByExternalID(newCtx(), "tt0133093", dto.CreateMovieRequest{Rating: 11})
+
+		var validationErrs dto.ValidationErrors
+		require.ErrorAs(t, err, &validationErrs)
+		assert.Equal(t, dto.KeyMovieTitleRequired, validationErrs[0].Key)
+	})
+
+	t.Run("created", func(t *testing.T) {
+		mockService := &MockMovieService{}
+		mockService.On("GetByExternalID", "tt0133093").Return(domain.Movie{}, domain.ErrMovieNotFound)
+		mockService.On("UpsertByExternalID",
+			domain.Movie{Title: "The Matrix", ReleaseYear: 1999, Rating: 8.7, OriginalLanguage: "en", ExternalID: "tt0133093"}, []int(nil)).
+			Return(5, domain.MovieUpsertCreated, nil)
+		mockService.On("GetByID", 5).
+			Return(domain.Movie{ID: 5, Title: "The Matrix", ReleaseYear: 1999, Rating: 8.7, OriginalLanguage: "en", ExternalID: "tt0133093"}, nil)
+
+		resp, err := NewMovieController(mockService).UpsertMovieByExternalID(newCtx(), "tt0133093", req)
+
+		require.NoError(t, err)
+		assert.Equal(t, "created", resp.Action)
+		assert.Equal(t, 5, resp.Movie.ID)
+		assert.Equal(t, "tt0133093", resp.Movie.ExternalID)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("moderator cannot change rating", func(t *testing.T) {
+		mockService := &MockMovieService{}
+		mockService.On("GetByExternalID", "tt0133093").
+			Return(domain.Movie{ID: 5, Title: "Matrix", ReleaseYear: 1999, Rating: 8.5, OriginalLanguage: "en", ExternalID: "tt0133093"}, nil)
+		ctx := newCtx()
+		auth.SetUser(ctx, &auth.UserContext{Role: domain.RoleModerator})
+
+		_, err := NewMovieController(mockService).UpsertMovieByExternalID(ctx, "tt0133093", req)
+
+		var forbiddenErr *domain.FieldsForbiddenError
+		require.ErrorAs(t, err, &forbiddenErr)
+		assert.Equal(t, []string{"rating"}, forbiddenErr.Fields)
+		mockService.AssertNotCalled(t, "UpsertByExternalID", mock.Anything, mock.Anything)
+	})
+}
+
+func TestMovieController_GetMovieByIDAsOf(t *testing.T) {
+	tests := []struct {
+		name          string
+		asOf          string
+		setupMock     func(*MockMovieService)
+		expectedTitle string
+		expectedError error
+	}{
+		{
+			name: "date",
+			asOf: "2024-01-01",
+			setupMock: func(mms *MockMovieService) {
+				mms.On("GetMovieAsOf", 1, time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)).
+					Return(domain.Movie{ID: 1, Title: "Old Title", ReleaseYear: 2010}, nil)
+			},
+			expectedTitle: "Old Title",
+		},
+		{
+			name: "timestamp",
+			asOf: "2024-01-01T12:30:00Z",
+			setupMock: func(mms *MockMovieService) {
+				mms.On("GetMovieAsOf", 1, time.Date(2024, time.January, 1, 12, 30, 0, 0, time.UTC)).
+					Return(domain.Movie{ID: 1, Title: "Noon Title"}, nil)
+			},
+			expectedTitle: "Noon Title",
+		},
+		{
+			name: "not existing yet",
+			asOf: "1990-01-01",
+			setupMock: func(mms *MockMovieService) {
+				mms.On("GetMovieAsOf", 1, mock.Anything).Return(domain.Movie{}, domain.ErrMovieNotFound)
+			},
+			expectedError: domain.ErrMovieNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockMovieService{}
+			tt.setupMock(mockService)
+
+			controller := NewMovieController(mockService)
+			resp, err := controller.GetMovieByIDAsOf(&gin.Context{}, 1, tt.asOf)
+
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedTitle, resp.Title)
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+
+	_, err := NewMovieController(&MockMovieService{}).GetMovieByIDAsOf(&gin.Context{}, 1, "yesterday")
+	assert.ErrorContains(t, err, "validation error")
+}
+
+func TestValidateMovie_FieldErrors(t *testing.T) {
+	err := validateMovie("", string(make([]byte, 1001)), 11)
+
+	var fieldErrs dto.ValidationErrors
+	assert.True(t, errors.As(err, &fieldErrs))
+	keys := make([]string, 0, len(fieldErrs))
+	for _, fieldErr := range fieldErrs {
+		keys = append(keys, fieldErr.Key)
+	}
+	assert.Equal(t, []string{dto.KeyMovieTitleRequired, dto.KeyMovieDescriptionTooLong, dto.KeyMovieRatingOutOfRange}, keys)
+	assert.Equal(t, "title: must be 1-150 characters; description: too long (max 1000 characters); rating: must be between 0 and 10", err.Error())
+
+	assert.NoError(t, validateMovie("Inception", "", 8.8))
+}
+
+func TestMovieController_GetMovieBySlug(t *testing.T) {
+	mockService := &MockMovieService{}
+	mockService.On("GetBySlug", "the-matrix-1999").
+		Return(domain.Movie{ID: 1, Title: "The Matrix", ReleaseYear: 1999, Slug: "the-matrix-1999"}, nil)
+	mockService.On("GetBySlug", "missing").Return(domain.Movie{}, domain.ErrMovieNotFound)
+	controller := NewMovieController(mockService)
+
+	resp, err := controller.GetMovieBySlug(&gin.Context{}, "the-matrix-1999")
+	require.NoError(t, err)
+	assert.Equal(t, 1, resp.ID)
+	assert.Equal(t, "the-matrix-1999", resp.Slug)
+
+	_, err = controller.GetMovieBySlug(&gin.Context{}, "missing")
+	assert.ErrorIs(t, err, domain.ErrMovieNotFound)
+	mockService.AssertExpectations(t)
+}
+
+func TestMovieController_CreateMovieFull(t *testing.T) {
+	keanuBirth := time.Date(1964, 9, 2, 0, 0, 0, 0, time.UTC)
+
+	t.Run("creates movie with cast", func(t *testing.T) {
+		mockService := &MockMovieService{}
+		cast := []domain.Actor{
+			{Name: "Keanu Reeves", Gender: "male", BirthDate: keanuBirth, CharacterName: "Neo", BillingOrder: 1},
+			{ID: 5},
+		}
+		mockService.On("CreateFull", domain.Movie{Title: "The Matrix", ReleaseYear: 1999, Rating: 8.7}, cast, false).
+			Return(domain.MovieImportResult{MovieID: 10, ActorIDs: []int{9, 5}, ActorsCreated: 1, CreatedActorIDs: []int{9}}, nil)
+		mockService.On("GetByID", 10).Return(domain.Movie{
+			ID: 10, Title: "The Matrix", ReleaseYear: 1999, Rating: 8.7,
+			Actors: []domain.Actor{
+				{ID: 9, Name: "Keanu Reeves", Gender: "male", BirthDate: keanuBirth, CharacterName: "Neo", BillingOrder: 1},
+				{ID: 5, Name: "Carrie-Anne Moss", Gender: "female"},
+			},
+		}, nil)
+		controller := NewMovieController(mockService)
+
+		resp, err := controller.CreateMovieFull(&gin.Context{}, dto.FullMovieRequest{
+			Title: "The Matrix", ReleaseYear: 1999, Rating: 8.7,
+			Actors:   []dto.NewCastMemberRequest{{Name: " Keanu Reeves ", Gender: "male", BirthDate: "1964-09-02", CharacterName: "Neo", BillingOrder: 1}},
+			ActorIDs: []int{5},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 10, resp.Movie.ID)
+		assert.Nil(t, resp.Movie.Actors)
+		require.Len(t, resp.Actors, 2)
+		assert.Equal(t, "Neo", resp.Actors[0].CharacterName)
+		assert.Equal(t, "1964-09-02", resp.Actors[0].BirthDate)
+		assert.Equal(t, 1, resp.ActorsCreated)
+		assert.Equal(t, []int{9}, resp.CreatedActorIDs)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("reports actor errors with index", func(t *testing.T) {
+		mockService := &MockMovieService{}
+		controller := NewMovieController(mockService)
+
+		_, err := controller.CreateMovieFull(&gin.Context{}, dto.FullMovieRequest{
+			Title: "The Matrix", ReleaseYear: 1999,
+			Actors: []dto.NewCastMemberRequest{
+				{Name: "Keanu Reeves", Gender: "male", BirthDate: "1964-09-02"},
+				{Name: "Hugo Weaving", Gender: "robot", BirthDate: "04.04.1960", BillingOrder: -1},
+			},
+			ActorIDs: []int{0},
+		})
+
+		var errs dto.ValidationErrors
+		require.ErrorAs(t, err, &errs)
+		fields := make([]string, 0, len(errs))
+		for _, fieldErr := range errs {
+			fields = append(fields, fieldErr.Field)
+		}
+		assert.Equal(t, []string{"actors[1].gender", "actors[1].birth_date", "actors[1].billing_order", "actor_ids"}, fields)
+		require.NotNil(t, errs[3].Index)
+		assert.Equal(t, 0, *errs[3].Index)
+		mockService.AssertNotCalled(t, "CreateFull", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("requires cast", func(t *testing.T) {
+		controller := NewMovieController(&MockMovieService{})
+
+		_, err := controller.CreateMovieFull(&gin.Context{}, dto.FullMovieRequest{Title: "Empty", ReleaseYear: 2000})
+		var errs dto.ValidationErrors
+		require.ErrorAs(t, err, &errs)
+		assert.Equal(t, dto.KeyFullCastRequired, errs[0].Key)
+	})
+}
@@ -0,0 +1,123 @@
+package controller
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"cinematique/internal/controller/dto"
+	"cinematique/internal/controller/mapper"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxTagLength — длина тега в символах, совпадает с tags.name VARCHAR(50)
+const maxTagLength = 50
+
+// tagPattern — слова из букв и цифр, разделённые одиночными дефисами
+var tagPattern = regexp.MustCompile(`^[\p{L}\p{N}]+(-[\p{L}\p{N}]+)*$`)
+
+// Размеры списка подсказок тегов: по умолчанию и максимальный
+const (
+	defaultTagSuggestLimit = 10
+	maxTagSuggestLimit     = 50
+)
+
+// normalizeTag приводит тег к каноническому виду: нижний регистр, пробелы и подчёркивания
+// заменены дефисом. Так "Cult Classic" и "cult-classic" становятся одним тегом
+func normalizeTag(raw string) (string, error) {
+	tag := strings.ToLower(strings.TrimSpace(raw))
+	tag = strings.Join(strings.FieldsFunc(tag, func(r rune) bool { return r == ' ' || r == '_' }), "-")
+	if !tagPattern.MatchString(tag) || utf8.RuneCountInString(tag) > maxTagLength {
+		return "", fmt.Errorf("validation error: %w", dto.ValidationErrors{dto.NewFieldError(dto.KeyTagInvalid)})
+	}
+	return tag, nil
+}
+
+// GetMovieTags возвращает теги фильма
+func (c *movieController) GetMovieTags(ctx *gin.Context, movieID int) (dto.MovieTagsResponse, error) {
+	tags, err := c.movieService.GetTags(requestContext(ctx), movieID)
+	if err != nil {
+		return dto.MovieTagsResponse{}, err
+	}
+	return dto.MovieTagsResponse{MovieID: movieID, Tags: tags}, nil
+}
+
+// AddMovieTag добавляет фильму тег и возвращает все его теги
+func (c *movieController) AddMovieTag(ctx *gin.Context, movieID int, rawTag string) (dto.MovieTagsResponse, error) {
+	tag, err := normalizeTag(rawTag)
+	if err != nil {
+		return dto.MovieTagsResponse{}, err
+	}
+	tags, err := c.movieService.AddTag(requestContext(ctx), movieID, tag)
+	if err != nil {
+		return dto.MovieTagsResponse{}, err
+	}
+	return dto.MovieTagsResponse{MovieID: movieID, Tags: tags}, nil
+}
+
+// RemoveMovieTag снимает тег с фильма
+func (c *movieController) RemoveMovieTag(ctx *gin.Context, movieID int, rawTag string) error {
+	tag, err := normalizeTag(rawTag)
+	if err != nil {
+		return err
+	}
+	return c.movieService.RemoveTag(requestContext(ctx), movieID, tag)
+}
+
+// GetMoviesByTag возвращает страницу фильмов с тегом и их общее число (?limit=, ?offset=)
+func (c *movieController) GetMoviesByTag(ctx *gin.Context, rawTag string) (dto.MoviesByTagResponse, error) {
+	tag, err := normalizeTag(rawTag)
+	if err != nil {
+		return dto.MoviesByTagResponse{}, err
+	}
+	limit, err := c.lists.Movies.limit(ctx)
+	if err != nil {
+		return dto.MoviesByTagResponse{}, err
+	}
+	offset, err := parseNonNegativeQuery(ctx, "offset", dto.KeyListOffsetInvalid)
+	if err != nil {
+		return dto.MoviesByTagResponse{}, fmt.Errorf("validation error: %w", err)
+	}
+
+	movies, total, err := c.movieService.ListByTag(requestContext(ctx), tag, limit, offset)
+	if err != nil {
+		return dto.MoviesByTagResponse{}, err
+	}
+	return dto.MoviesByTagResponse{
+		Tag:        tag,
+		Total:      total,
+		Movies:     mapper.Movies(movies),
+		Pagination: newPagination(limit, offset, total),
+	}, nil
+}
+
+// SuggestTags возвращает подсказки для автодополнения тега (?q=, ?limit=). Префикс
+// нормализуется так же, как тег, но может обрываться на дефисе
+func (c *movieController) SuggestTags(ctx *gin.Context) (dto.TagSuggestionsResponse, error) {
+	prefix := strings.ToLower(strings.TrimSpace(ctx.Query("q")))
+	prefix = strings.NewReplacer(" ", "-", "_", "-").Replace(prefix)
+	if prefix == "" {
+		return dto.TagSuggestionsResponse{}, fmt.Errorf("validation error: %w", dto.ValidationErrors{dto.NewFieldError(dto.KeyTagSuggestQRequired)})
+	}
+	limit := defaultTagSuggestLimit
+	if raw := ctx.Query("limit"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed < 1 || parsed > maxTagSuggestLimit {
+			return dto.TagSuggestionsResponse{}, fmt.Errorf("validation error: %w", dto.ValidationErrors{dto.NewFieldError(dto.KeyTagSuggestLimit)})
+		}
+		limit = parsed
+	}
+
+	tags, err := c.movieService.SuggestTags(requestContext(ctx), prefix, limit)
+	if err != nil {
+		return dto.TagSuggestionsResponse{}, err
+	}
+	response := dto.TagSuggestionsResponse{Tags: make([]dto.TagSuggestion, 0, len(tags))}
+	for _, tag := range tags {
+		response.Tags = append(response.Tags, dto.TagSuggestion{Name: tag.Name, MovieCount: tag.MovieCount})
+	}
+	return response, nil
+}
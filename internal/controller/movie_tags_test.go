@@ -0,0 +1,120 @@
+package controller
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"cinematique/internal/controller/dto"
+	"cinematique/internal/domain"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeTag(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want string
+	}{
+		{raw: "Cult Classic", want: "cult-classic"},
+		{raw: "  sci_fi  ", want: "sci-fi"},
+		{raw: "Фантастика", want: "фантастика"},
+		{raw: "80s", want: "80s"},
+		{raw: "", want: ""},
+		{raw: "cult--classic", want: ""},
+		{raw: "-noir", want: ""},
+		{raw: "oscar!", want: ""},
+		{raw: strings.Repeat("a", maxTagLength+1), want: ""},
+	}
+	for _, tt := range tests {
+		got, err := normalizeTag(tt.raw)
+		if tt.want == "" {
+			var validationErrs dto.ValidationErrors
+			require.ErrorAs(t, err, &validationErrs, tt.raw)
+			assert.Equal(t, dto.KeyTagInvalid, validationErrs[0].Key)
+			continue
+		}
+		require.NoError(t, err, tt.raw)
+		assert.Equal(t, tt.want, got)
+	}
+}
+
+func TestMovieController_AddMovieTag(t *testing.T) {
+	mockService := &MockMovieService{}
+	mockService.On("AddTag", 1, "cult-classic").Return([]string{"cult-classic", "neo-noir"}, nil)
+
+	resp, err := NewMovieController(mockService).AddMovieTag(&gin.Context{}, 1, "Cult Classic")
+	require.NoError(t, err)
+	assert.Equal(t, dto.MovieTagsResponse{MovieID: 1, Tags: []string{"cult-classic", "neo-noir"}}, resp)
+
+	_, err = NewMovieController(mockService).AddMovieTag(&gin.Context{}, 1, "cult/classic")
+	assert.ErrorContains(t, err, "validation error")
+	mockService.AssertExpectations(t)
+}
+
+func TestMovieController_RemoveMovieTag(t *testing.T) {
+	mockService := &MockMovieService{}
+	mockService.On("RemoveTag", 1, "neo-noir").Return(domain.ErrTagNotFound)
+
+	err := NewMovieController(mockService).RemoveMovieTag(&gin.Context{}, 1, "Neo Noir")
+	assert.ErrorIs(t, err, domain.ErrTagNotFound)
+	mockService.AssertExpectations(t)
+}
+
+func TestMovieController_GetMoviesByTag(t *testing.T) {
+	mockService := &MockMovieService{}
+	mockService.On("ListByTag", "oscar-winner", 5, 10).
+		Return([]domain.Movie{{ID: 7, Title: "The Godfather"}}, 12, nil)
+
+	ctx := &gin.Context{}
+	ctx.Request = &http.Request{URL: &url.URL{RawQuery: "limit=5&offset=10"}}
+	resp, err := NewMovieController(mockService).GetMoviesByTag(ctx, "oscar-winner")
+	require.NoError(t, err)
+	assert.Equal(t, dto.MoviesByTagResponse{
+		Tag:        "oscar-winner",
+		Total:      12,
+		Movies:     []dto.MovieResponse{{ID: 7, Title: "The Godfather"}},
+		Pagination: &dto.Pagination{Limit: 5, Offset: 10, Total: 12, TotalPages: 3},
+	}, resp)
+	mockService.AssertExpectations(t)
+}
+
+func TestMovieController_SuggestTags(t *testing.T) {
+	tests := []struct {
+		name     string
+		rawQuery string
+		wantKey  string
+	}{
+		{name: "prefix", rawQuery: "q=Cult%20C&limit=5"},
+		{name: "missing q", rawQuery: "q=%20", wantKey: dto.KeyTagSuggestQRequired},
+		{name: "limit too large", rawQuery: "q=cult&limit=51", wantKey: dto.KeyTagSuggestLimit},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockMovieService{}
+			if tt.wantKey == "" {
+				mockService.On("SuggestTags", "cult-c", 5).
+					Return([]domain.Tag{{Name: "cult-classic", MovieCount: 3}}, nil)
+			}
+
+			ctx := &gin.Context{}
+			ctx.Request = &http.Request{URL: &url.URL{RawQuery: tt.rawQuery}}
+			resp, err := NewMovieController(mockService).SuggestTags(ctx)
+
+			if tt.wantKey != "" {
+				var validationErrs dto.ValidationErrors
+				require.ErrorAs(t, err, &validationErrs)
+				assert.Equal(t, tt.wantKey, validationErrs[0].Key)
+				mockService.AssertNotCalled(t, "SuggestTags", mock.Anything, mock.Anything)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, []dto.TagSuggestion{{Name: "cult-classic", MovieCount: 3}}, resp.Tags)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package controller
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"cinematique/internal/controller/dto"
+	"cinematique/internal/controller/mapper"
+)
+
+// searchController обрабатывает общий поиск по фильмам и актёрам
+type searchController struct {
+	searchService ServiceCatalogSearch
+	lists         ListDefaults
+}
+
+// NewSearchController создаёт контроллер общего поиска
+func NewSearchController(searchService ServiceCatalogSearch) *searchController {
+	return &searchController{searchService: searchService, lists: DefaultListDefaults()}
+}
+
+// WithListDefaults задаёт размер списка результатов поиска
+func (c *searchController) WithListDefaults(lists ListDefaults) *searchController {
+	c.lists = lists.normalize(DefaultListDefaults())
+	return c
+}
+
+// Search ищет фильмы и актёров по строке ?q= (не короче двух символов). ?limit= ограничивает
+// общее число результатов обоих видов
+func (c *searchController) Search(ctx *gin.Context) (dto.SearchResponse, error) {
+	query := strings.TrimSpace(ctx.Query("q"))
+	if query == "" {
+		return dto.SearchResponse{}, fmt.Errorf("validation error: %w", dto.ValidationErrors{dto.NewFieldError(dto.KeySearchQRequired)})
+	}
+	if searchQueryTooShort(query) {
+		return dto.SearchResponse{}, fmt.Errorf("validation error: %w", dto.ValidationErrors{dto.NewFieldError(dto.KeySearchQTooShort)})
+	}
+	limit, err := c.lists.Search.limit(ctx)
+	if err != nil {
+		return dto.SearchResponse{}, err
+	}
+
+	hits, err := c.searchService.Search(requestContext(ctx), query, limit)
+	if err != nil {
+		return dto.SearchResponse{}, fmt.Errorf("searching catalog: %w", err)
+	}
+	return mapper.Search(query, hits), nil
+}
@@ -0,0 +1,107 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"cinematique/internal/controller/dto"
+	"cinematique/internal/domain"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockCatalogSearchService мок общего поиска
+type MockCatalogSearchService struct {
+	mock.Mock
+}
+
+func (m *MockCatalogSearchService) Search(_ context.Context, query string, limit int) ([]domain.SearchHit, error) {
+	args := m.Called(query, limit)
+	hits, _ := args.Get(0).([]domain.SearchHit)
+	return hits, args.Error(1)
+}
+
+func TestSearchController_Search(t *testing.T) {
+	hits := []domain.SearchHit{
+		{Kind: domain.SearchHitMovie, ID: 1, Name: "The Matrix", Slug: "the-matrix-1999", ReleaseYear: 1999, Score: 0.6},
+		{Kind: domain.SearchHitActor, ID: 7, Name: "Matrix Fan", Slug: "matrix-fan", Score: 0.4},
+	}
+	tests := []struct {
+		name          string
+		query         string
+		setupMock     func(*MockCatalogSearchService)
+		expected      dto.SearchResponse
+		expectedError string
+	}{
+		{
+			name:  "movies and actors",
+			query: "q=+matrix+",
+			setupMock: func(m *MockCatalogSearchService) {
+				m.On("Search", "matrix", 10).Return(hits, nil)
+			},
+			expected: dto.SearchResponse{Query: "matrix", Results: []dto.SearchResult{
+				{Type: "movie", ID: 1, Title: "The Matrix", Slug: "the-matrix-1999", ReleaseYear: 1999, Score: 0.6},
+				{Type: "actor", ID: 7, Name: "Matrix Fan", Slug: "matrix-fan", Score: 0.4},
+			}},
+		},
+		{
+			name:  "nothing found",
+			query: "q=zz&limit=50",
+			setupMock: func(m *MockCatalogSearchService) {
+				m.On("Search", "zz", 50).Return([]domain.SearchHit{}, nil)
+			},
+			expected: dto.SearchResponse{Query: "zz", Results: []dto.SearchResult{}},
+		},
+		{
+			name:          "missing query",
+			query:         "q=++",
+			setupMock:     func(m *MockCatalogSearchService) {},
+			expectedError: "validation error: q: search parameter is required",
+		},
+		{
+			name:          "query too short",
+			query:         "q=m",
+			setupMock:     func(m *MockCatalogSearchService) {},
+			expectedError: "validation error: q: must be at least 2 characters",
+		},
+		{
+			name:          "limit too large",
+			query:         "q=matrix&limit=51",
+			setupMock:     func(m *MockCatalogSearchService) {},
+			expectedError: "validation error: limit: exceeds the maximum page size",
+		},
+		{
+			name:  "service error",
+			query: "q=matrix",
+			setupMock: func(m *MockCatalogSearchService) {
+				m.On("Search", "matrix", 10).Return(nil, errors.New("db down"))
+			},
+			expectedError: "searching catalog: db down",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			searchService := &MockCatalogSearchService{}
+			tt.setupMock(searchService)
+			controller := NewSearchController(searchService)
+
+			ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+			ctx.Request = httptest.NewRequest("GET", "/search?"+tt.query, nil)
+
+			result, err := controller.Search(ctx)
+
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, result)
+			}
+			searchService.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package controller
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"unicode"
+
+	"cinematique/internal/controller/dto"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Поля фильма, в которых ищет поиск
+const (
+	matchFieldTitle = "title"
+	matchFieldActor = "actor"
+)
+
+// parseHighlight разбирает ?highlight=: без параметра совпадения в ответ не добавляются
+func parseHighlight(ctx *gin.Context) (bool, error) {
+	value := ctx.Query("highlight")
+	if value == "" {
+		return false, nil
+	}
+	highlight, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("validation error: %w", dto.ValidationErrors{dto.NewFieldError(dto.KeyMovieSearchHighlight)})
+	}
+	return highlight, nil
+}
+
+// highlightTitles отмечает у каждого фильма совпадения запроса в названии.
+// Фильм, найденный с учётом опечаток, получает совпадение без диапазонов
+func highlightTitles(movies []dto.MovieResponse, query string) {
+	for i := range movies {
+		movies[i].Matches = []dto.SearchMatch{{
+			Field:  matchFieldTitle,
+			Value:  movies[i].Title,
+			Ranges: matchRanges(movies[i].Title, query),
+		}}
+	}
+}
+
+// highlightActors отмечает у каждого фильма актёров, в имени которых совпал запрос. Как и подсказки,
+// подсветка необязательна: при ошибке фильмы возвращаются без неё
+func (c *movieController) highlightActors(ctx *gin.Context, movies []dto.MovieResponse, query string) {
+	if len(movies) == 0 {
+		return
+	}
+	ids := make([]int, 0, len(movies))
+	for _, movie := range movies {
+		ids = append(ids, movie.ID)
+	}
+	actors, err := c.movieService.GetMatchingActors(requestContext(ctx), ids, query)
+	if err != nil {
+		log.Printf("Не удалось получить совпадения по актёрам для подсветки: %v", err)
+		return
+	}
+	for i := range movies {
+		for _, actor := range actors[movies[i].ID] {
+			movies[i].Matches = append(movies[i].Matches, dto.SearchMatch{
+				Field:   matchFieldActor,
+				Value:   actor.Name,
+				ActorID: actor.ID,
+				Ranges:  matchRanges(actor.Name, query),
+			})
+		}
+	}
+}
+
+// matchRanges находит непересекающиеся вхождения query в value без учёта регистра, как ILIKE.
+// Позиции считаются в символах: каждая руна сравнивается в нижнем регистре отдельно,
+// поэтому индексы совпадают с индексами исходной строки
+func matchRanges(value, query string) []dto.MatchRange {
+	ranges := make([]dto.MatchRange, 0)
+	text, needle := lowerRunes(value), lowerRunes(query)
+	if len(needle) == 0 {
+		return ranges
+	}
+	for i := 0; i+len(needle) <= len(text); {
+		if string(text[i:i+len(needle)]) == string(needle) {
+			ranges = append(ranges, dto.MatchRange{Start: i, End: i + len(needle)})
+			i += len(needle)
+			continue
+		}
+		i++
+	}
+	return ranges
+}
+
+func lowerRunes(s string) []rune {
+	runes := []rune(s)
+	for i, r := range runes {
+		runes[i] = unicode.ToLower(r)
+	}
+	return runes
+}
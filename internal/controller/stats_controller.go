@@ -0,0 +1,60 @@
+package controller
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"cinematique/internal/controller/dto"
+	"cinematique/internal/controller/mapper"
+)
+
+// statsController обрабатывает запросы статистики каталога и поисковой аналитики
+type statsController struct {
+	statsService  ServiceStats
+	searchService ServiceSearchStats
+}
+
+// NewStatsController создаёт контроллер статистики
+func NewStatsController(statsService ServiceStats, searchService ServiceSearchStats) *statsController {
+	return &statsController{statsService: statsService, searchService: searchService}
+}
+
+// GetStats возвращает агрегированную статистику каталога
+func (c *statsController) GetStats(ctx *gin.Context) (dto.StatsResponse, error) {
+	stats, err := c.statsService.Get(requestContext(ctx))
+	if err != nil {
+		return dto.StatsResponse{}, fmt.Errorf("getting catalog stats: %w", err)
+	}
+	return mapper.Stats(stats), nil
+}
+
+// Окно и размер списков поисковой аналитики
+const (
+	defaultTopSearchesWindow = "7d"
+	maxTopSearchesDays       = 365
+	defaultTopSearchesLimit  = 10
+	maxTopSearchesLimit      = 100
+)
+
+// GetTopSearches возвращает самые частые запросы за окно ?window= (число дней с суффиксом d,
+// по умолчанию 7d) и самые частые среди не давших результатов. ?limit= ограничивает оба списка
+func (c *statsController) GetTopSearches(ctx *gin.Context) (dto.TopSearchesResponse, error) {
+	window := ctx.DefaultQuery("window", defaultTopSearchesWindow)
+	days, err := strconv.Atoi(strings.TrimSuffix(window, "d"))
+	if !strings.HasSuffix(window, "d") || err != nil || days < 1 || days > maxTopSearchesDays {
+		return dto.TopSearchesResponse{}, fmt.Errorf("validation error: %w", dto.ValidationErrors{dto.NewFieldError(dto.KeyStatsWindowInvalid)})
+	}
+	limit, err := PageSize{Default: defaultTopSearchesLimit, Max: maxTopSearchesLimit}.limit(ctx)
+	if err != nil {
+		return dto.TopSearchesResponse{}, err
+	}
+
+	analytics, err := c.searchService.TopSearches(requestContext(ctx), days, limit)
+	if err != nil {
+		return dto.TopSearchesResponse{}, fmt.Errorf("getting top searches: %w", err)
+	}
+	return mapper.TopSearches(window, analytics), nil
+}
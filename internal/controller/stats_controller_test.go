@@ -0,0 +1,107 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"cinematique/internal/controller/dto"
+	"cinematique/internal/domain"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockSearchStatsService мок поисковой аналитики
+type MockSearchStatsService struct {
+	mock.Mock
+}
+
+func (m *MockSearchStatsService) TopSearches(_ context.Context, days, limit int) (domain.SearchAnalytics, error) {
+	args := m.Called(days, limit)
+	return args.Get(0).(domain.SearchAnalytics), args.Error(1)
+}
+
+func TestStatsController_GetTopSearches(t *testing.T) {
+	analytics := domain.SearchAnalytics{
+		Since: time.Date(2026, 2, 6, 0, 0, 0, 0, time.UTC),
+		Top:   []domain.SearchStat{{Query: "matrix", Searches: 42}},
+	}
+	tests := []struct {
+		name          string
+		query         string
+		setupMock     func(*MockSearchStatsService)
+		expected      dto.TopSearchesResponse
+		expectedError string
+	}{
+		{
+			name: "defaults",
+			setupMock: func(m *MockSearchStatsService) {
+				m.On("TopSearches", 7, 10).Return(analytics, nil)
+			},
+			expected: dto.TopSearchesResponse{
+				Window:         "7d",
+				Since:          "2026-02-06",
+				Top:            []dto.SearchStat{{Query: "matrix", Searches: 42}},
+				TopZeroResults: []dto.SearchStat{},
+			},
+		},
+		{
+			name:  "explicit window and limit",
+			query: "window=30d&limit=5",
+			setupMock: func(m *MockSearchStatsService) {
+				m.On("TopSearches", 30, 5).Return(domain.SearchAnalytics{Since: analytics.Since}, nil)
+			},
+			expected: dto.TopSearchesResponse{Window: "30d", Since: "2026-02-06", Top: []dto.SearchStat{}, TopZeroResults: []dto.SearchStat{}},
+		},
+		{
+			name:          "window without unit",
+			query:         "window=7",
+			setupMock:     func(m *MockSearchStatsService) {},
+			expectedError: "validation error: window: must be a number of days from 1d to 365d",
+		},
+		{
+			name:          "window too long",
+			query:         "window=366d",
+			setupMock:     func(m *MockSearchStatsService) {},
+			expectedError: "validation error: window: must be a number of days from 1d to 365d",
+		},
+		{
+			name:          "limit too large",
+			query:         "limit=101",
+			setupMock:     func(m *MockSearchStatsService) {},
+			expectedError: "validation error: limit: exceeds the maximum page size",
+		},
+		{
+			name: "service error",
+			setupMock: func(m *MockSearchStatsService) {
+				m.On("TopSearches", 7, 10).Return(domain.SearchAnalytics{}, errors.New("db down"))
+			},
+			expectedError: "getting top searches: db down",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			searchService := &MockSearchStatsService{}
+			tt.setupMock(searchService)
+			controller := NewStatsController(nil, searchService)
+
+			ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+			ctx.Request = httptest.NewRequest("GET", "/admin/stats/top-searches?"+tt.query, nil)
+
+			result, err := controller.GetTopSearches(ctx)
+
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, result)
+			}
+			searchService.AssertExpectations(t)
+		})
+	}
+}
@@ -2,43 +2,283 @@ package domain
 
 import (
 	"errors"
+	"fmt"
+	"math"
+	"strings"
 	"time"
+
+	"cinematique/internal/apperror"
 )
 
 // Actor — доменная модель для таблицы актёров
 // Отражает структуру таблицы actors в БД
 type Actor struct {
-	ID        int       `json:"id"`
-	Name      string    `json:"name"`
-	Gender    string    `json:"gender"`
-	BirthDate time.Time `json:"birth_date"`
-	Movies    []Movie   `json:"movies,omitempty"`
+	ID        int        `json:"id"`
+	Name      string     `json:"name"`
+	Gender    string     `json:"gender"`
+	BirthDate time.Time  `json:"birth_date"`
+	DeathDate *time.Time `json:"death_date,omitempty"` // дата смерти; nil — актёр жив или дата неизвестна
+	PhotoKey  string     `json:"-"`                    // ключ фотографии в хранилище объектов; пусто, если фото нет
+	Slug      string     `json:"slug,omitempty"`       // уникальный идентификатор для адресов (/actors/slug/keanu-reeves)
+	UpdatedAt time.Time  `json:"-"`                    // момент последнего изменения; заполняется только при чтении одного актёра
+	Movies    []Movie    `json:"movies,omitempty"`
+
+	// Заполняются только в составе фильма (GetActorsForMovieByID)
+	CharacterName string `json:"character_name,omitempty"` // имя персонажа
+	BillingOrder  int    `json:"billing_order,omitempty"`  // место в титрах; 0 — не задано
+}
+
+// CastMember — актёр в составе фильма: роль и место в титрах (0 — не задано)
+type CastMember struct {
+	ActorID       int
+	CharacterName string
+	BillingOrder  int
 }
 
 // Movie — доменная модель для таблицы фильмов
 // Отражает структуру таблицы movies в БД
 type Movie struct {
-	ID          int       `json:"id"`
-	Title       string    `json:"title"`
-	Description string    `json:"description"`
-	ReleaseYear int       `json:"release_year"`
-	Rating      float64   `json:"rating"`
-	Actors      []Actor   `json:"actors,omitempty"`
+	ID               int           `json:"id"`
+	Title            string        `json:"title"`
+	Description      string        `json:"description"`
+	ReleaseYear      int           `json:"release_year"`
+	ReleaseDate      *time.Time    `json:"release_date,omitempty"` // точная дата выхода, если известна
+	Rating           float64       `json:"rating"`
+	ViewCount        int64         `json:"view_count"`                  // число просмотров страницы фильма
+	OriginalLanguage string        `json:"original_language,omitempty"` // язык оригинала (ISO 639-1); пусто, если неизвестен
+	Country          string        `json:"country,omitempty"`           // страна производства (ISO 3166-1 alpha-2); пусто, если неизвестна
+	RuntimeMinutes   int           `json:"runtime_minutes,omitempty"`   // продолжительность в минутах; 0, если неизвестна
+	Slug             string        `json:"slug,omitempty"`              // уникальный идентификатор для адресов (/movies/slug/the-matrix-1999)
+	ExternalID       string        `json:"external_id,omitempty"`       // ID в каталоге-источнике (IMDb и т.п.); заполняется только при чтении одного фильма
+	Ratings          []MovieRating `json:"-"`                           // рейтинги по источникам; заполняются только при чтении одного фильма
+	DisplayRating    *float64      `json:"-"`                           // взвешенный рейтинг по источникам; nil, если рейтингов нет
+	UpdatedAt        time.Time     `json:"-"`                           // момент последнего изменения; заполняется только при чтении одного фильма
+	Actors           []Actor       `json:"actors,omitempty"`
+}
+
+// ChangedFields возвращает имена (как в JSON) редактируемых полей, значения которых в next
+// отличаются от m. Служебные поля, рейтинги по источникам и состав не сравниваются
+func (m Movie) ChangedFields(next Movie) []string {
+	var fields []string
+	for _, change := range m.Diff(next) {
+		fields = append(fields, change.Field)
+	}
+	return fields
+}
+
+// Diff возвращает изменения редактируемых полей фильма от m к next. Даты передаются
+// как *time.Time, остальные значения — в типах полей Movie
+func (m Movie) Diff(next Movie) []FieldChange {
+	var changes []FieldChange
+	add := func(changed bool, name string, old, new interface{}) {
+		if changed {
+			changes = append(changes, FieldChange{Field: name, Old: old, New: new})
+		}
+	}
+	add(m.Title != next.Title, "title", m.Title, next.Title)
+	add(m.Description != next.Description, "description", m.Description, next.Description)
+	add(m.ReleaseYear != next.ReleaseYear, "release_year", m.ReleaseYear, next.ReleaseYear)
+	add(!sameDate(m.ReleaseDate, next.ReleaseDate), "release_date", m.ReleaseDate, next.ReleaseDate)
+	add(m.Rating != next.Rating, "rating", m.Rating, next.Rating)
+	add(m.OriginalLanguage != next.OriginalLanguage, "original_language", m.OriginalLanguage, next.OriginalLanguage)
+	add(m.Country != next.Country, "country", m.Country, next.Country)
+	add(m.RuntimeMinutes != next.RuntimeMinutes, "runtime_minutes", m.RuntimeMinutes, next.RuntimeMinutes)
+	return changes
+}
+
+// Diff возвращает изменения профиля актёра от a к next. Фото сравнивается по ключу в хранилище,
+// slug и фильмы не сравниваются
+func (a Actor) Diff(next Actor) []FieldChange {
+	var changes []FieldChange
+	add := func(changed bool, name string, old, new interface{}) {
+		if changed {
+			changes = append(changes, FieldChange{Field: name, Old: old, New: new})
+		}
+	}
+	add(a.Name != next.Name, "name", a.Name, next.Name)
+	add(a.Gender != next.Gender, "gender", a.Gender, next.Gender)
+	add(!a.BirthDate.Equal(next.BirthDate), "birth_date", a.BirthDate, next.BirthDate)
+	add(!sameDate(a.DeathDate, next.DeathDate), "death_date", a.DeathDate, next.DeathDate)
+	add(a.PhotoKey != next.PhotoKey, "photo", a.PhotoKey, next.PhotoKey)
+	return changes
+}
+
+// sameDate сравнивает необязательные даты: две пустые даты равны
+func sameDate(a, b *time.Time) bool {
+	return a == nil && b == nil || a != nil && b != nil && a.Equal(*b)
+}
+
+// FieldChange — изменение одного поля между соседними версиями записи.
+// Field — имя поля как в JSON
+type FieldChange struct {
+	Field string
+	Old   interface{}
+	New   interface{}
+}
+
+// Collection — подборка или франшиза: упорядоченный список фильмов
+type Collection struct {
+	ID          int     `json:"id"`
+	Name        string  `json:"name"`
+	Description string  `json:"description"`
+	Movies      []Movie `json:"movies,omitempty"` // в порядке просмотра
+}
+
+// Tag — свободный тег фильмов и число фильмов с ним
+type Tag struct {
+	Name       string `json:"name"`
+	MovieCount int    `json:"movie_count"`
+}
+
+// ActorFilter — условия отбора в списке и поиске актёров
+type ActorFilter struct {
+	Living bool // только актёры без даты смерти
 }
 
 // ActorUpdate — доменная модель для обновления актёра
 type ActorUpdate struct {
-	Name      *string    `json:"name,omitempty"`
-	Gender    *string    `json:"gender,omitempty"`
-	BirthDate *string    `json:"birth_date,omitempty"`
+	Name      *string `json:"name,omitempty"`
+	Gender    *string `json:"gender,omitempty"`
+	BirthDate *string `json:"birth_date,omitempty"`
 }
 
 // MovieUpdate — доменная модель для обновления фильма
 type MovieUpdate struct {
-	Title       *string  `json:"title,omitempty"`
-	Description *string  `json:"description,omitempty"`
-	ReleaseYear *int     `json:"release_year,omitempty"`
-	Rating      *float64 `json:"rating,omitempty"`
+	Title            *string    `json:"title,omitempty"`
+	Description      *string    `json:"description,omitempty"`
+	ReleaseYear      *int       `json:"release_year,omitempty"`
+	ReleaseDate      *time.Time `json:"release_date,omitempty"`
+	ClearReleaseDate bool       `json:"clear_release_date,omitempty"` // сбросить дату выхода в «неизвестна»
+	Rating           *float64   `json:"rating,omitempty"`
+	OriginalLanguage *string    `json:"original_language,omitempty"`
+	Country          *string    `json:"country,omitempty"`
+	RuntimeMinutes   *int       `json:"runtime_minutes,omitempty"` // 0 сбрасывает продолжительность в «неизвестна»
+}
+
+// IsEmpty сообщает, что обновление не затрагивает ни одного поля
+func (u MovieUpdate) IsEmpty() bool {
+	return u.Title == nil && u.Description == nil && u.ReleaseYear == nil &&
+		u.ReleaseDate == nil && !u.ClearReleaseDate && u.Rating == nil &&
+		u.OriginalLanguage == nil && u.Country == nil && u.RuntimeMinutes == nil
+}
+
+// BulkItemResult — результат массовой операции над одним фильмом. Err == nil, если операция выполнена
+type BulkItemResult struct {
+	ID  int
+	Err error
+}
+
+// MovieRevision — запись истории изменений фильма: какие поля изменились и когда.
+// Первая ревизия содержит все поля фильма, последующие — только изменённые
+type MovieRevision struct {
+	ID        int         `json:"id"`
+	MovieID   int         `json:"movie_id"`
+	ChangedAt time.Time   `json:"changed_at"`
+	Changes   MovieUpdate `json:"changes"`
+	Deleted   bool        `json:"deleted,omitempty"`
+}
+
+// ActorRevision — запись истории изменений актёра. В отличие от MovieRevision хранит
+// профиль целиком; у записи об удалении Deleted = true, а Actor пуст
+type ActorRevision struct {
+	ID        int
+	ActorID   int
+	ChangedAt time.Time
+	Actor     Actor
+	Deleted   bool
+}
+
+// MovieVersion — состояние фильма после очередной ревизии и отличия от предыдущей версии.
+// У первой версии (и первой после удаления) в Changes все заполненные поля с пустым Old;
+// у версии-удаления Changes пуст
+type MovieVersion struct {
+	Version   int
+	ChangedAt time.Time
+	Deleted   bool
+	Movie     Movie
+	Changes   []FieldChange
+}
+
+// ActorVersion — состояние актёра после изменения и отличия от предыдущей версии
+type ActorVersion struct {
+	Version   int
+	ChangedAt time.Time
+	Deleted   bool
+	Actor     Actor
+	Changes   []FieldChange
+}
+
+// RatingChange — изменение рейтинга фильма. OldRating пуст у начальной точки истории
+type RatingChange struct {
+	ID        int       `json:"id"`
+	MovieID   int       `json:"movie_id"`
+	OldRating *float64  `json:"old_rating,omitempty"`
+	NewRating float64   `json:"new_rating"`
+	ChangedAt time.Time `json:"changed_at"`
+}
+
+// Источники рейтинга фильма. Внутренний рейтинг — поле rating самого фильма
+const (
+	RatingSourceInternal       = "internal"
+	RatingSourceIMDb           = "imdb"
+	RatingSourceRottenTomatoes = "rotten_tomatoes"
+)
+
+// RatingSources — все источники рейтинга в порядке вывода
+var RatingSources = []string{RatingSourceInternal, RatingSourceIMDb, RatingSourceRottenTomatoes}
+
+// RatingScale возвращает верхнюю границу шкалы источника: Rotten Tomatoes ставит проценты,
+// остальные — баллы до 10. Для неизвестного источника возвращает 0
+func RatingScale(source string) float64 {
+	switch source {
+	case RatingSourceInternal, RatingSourceIMDb:
+		return 10
+	case RatingSourceRottenTomatoes:
+		return 100
+	default:
+		return 0
+	}
+}
+
+// MovieRating — рейтинг фильма из одного источника в шкале этого источника
+type MovieRating struct {
+	MovieID   int
+	Source    string
+	Rating    float64
+	UpdatedAt time.Time
+}
+
+// RatingWeights — веса источников в отображаемом рейтинге. Источник с нулевым весом
+// в нём не учитывается
+type RatingWeights map[string]float64
+
+// DisplayRating возвращает средневзвешенный рейтинг по шкале до 10, округлённый до десятых.
+// Рейтинги приводятся к шкале 10 перед усреднением; ok ложно, если нет ни одного
+// рейтинга источника с положительным весом
+func (w RatingWeights) DisplayRating(ratings []MovieRating) (rating float64, ok bool) {
+	var sum, total float64
+	for _, r := range ratings {
+		weight, scale := w[r.Source], RatingScale(r.Source)
+		if weight <= 0 || scale == 0 {
+			continue
+		}
+		sum += weight * r.Rating * 10 / scale
+		total += weight
+	}
+	if total == 0 {
+		return 0, false
+	}
+	return math.Round(sum/total*10) / 10, true
+}
+
+// Availability — окно доступности фильма в регионе (права на показ).
+// AvailableUntil включительно; nil — бессрочно
+type Availability struct {
+	ID             int
+	MovieID        int
+	Region         string // ISO 3166-1 alpha-2 в верхнем регистре
+	AvailableFrom  time.Time
+	AvailableUntil *time.Time
 }
 
 // ActorWithFilms — актёр с фильмами (для сервисов и DTO)
@@ -50,26 +290,450 @@ type ActorWithFilms struct {
 	Movies    []Movie   `json:"movies,omitempty"`
 }
 
+// ActorMergeResult — результат слияния актёра-дубликата с основным актёром
+type ActorMergeResult struct {
+	Actor            Actor  `json:"actor"`
+	DuplicateID      int    `json:"duplicate_id"`
+	MoviesReassigned int    `json:"movies_reassigned"`
+	UnusedPhotoKey   string `json:"-"` // фото дубликата, которое больше не используется и подлежит удалению
+}
+
+// MovieMergeResult — результат слияния фильма-дубликата с основным фильмом
+type MovieMergeResult struct {
+	Movie            Movie `json:"movie"`
+	DuplicateID      int   `json:"duplicate_id"`
+	ActorsReassigned int   `json:"actors_reassigned"`
+}
+
+// MovieReference — сколько записей одного вида (например, подборок) ссылается на фильм
+type MovieReference struct {
+	Kind  string `json:"kind"`
+	Count int    `json:"count"`
+}
+
+// MovieDeletePolicy определяет, что происходит при удалении фильма, на который ссылаются
+// записи пользователей: подборки, а в будущем отзывы и списки просмотра
+type MovieDeletePolicy string
+
+const (
+	MovieDeleteCascade  MovieDeletePolicy = "cascade"  // ссылки удаляются вместе с фильмом
+	MovieDeleteRestrict MovieDeletePolicy = "restrict" // удаление отклоняется, пока ссылки есть
+)
+
+// ParseMovieDeletePolicy разбирает политику удаления; false — значение неизвестно
+func ParseMovieDeletePolicy(value string) (MovieDeletePolicy, bool) {
+	switch policy := MovieDeletePolicy(strings.ToLower(strings.TrimSpace(value))); policy {
+	case MovieDeleteCascade, MovieDeleteRestrict:
+		return policy, true
+	}
+	return "", false
+}
+
+// ExternalMovie — фильм из внешнего каталога (TMDB) вместе с актёрским составом
+type ExternalMovie struct {
+	Movie Movie
+	Cast  []Actor
+}
+
+// MovieImportResult — результат импорта фильма из внешнего каталога
+type MovieImportResult struct {
+	MovieID         int
+	ActorIDs        []int // актёры фильма в порядке титров
+	ActorsCreated   int   // сколько актёров не было в каталоге и было создано
+	CreatedActorIDs []int // ID созданных актёров: их удаляет откат создания фильма
+}
+
+// ReindexResult — итог перестроения индексов поиска: перестроенные индексы и время работы
+type ReindexResult struct {
+	Indexes  []string
+	Duration time.Duration
+}
+
+// SortField — поле сортировки списка и её направление ("asc" или "desc")
+type SortField struct {
+	Field string
+	Order string
+}
+
+// MovieListQuery — параметры сортированного постраничного списка фильмов.
+// Limit = 0 означает «без ограничения»
+type MovieListQuery struct {
+	Sort   []SortField
+	Limit  int
+	Offset int
+}
+
+// MovieFilter — дополнительные условия поиска фильмов. Пустое поле не ограничивает поиск
+type MovieFilter struct {
+	OriginalLanguage string // ISO 639-1 в нижнем регистре
+	Country          string // ISO 3166-1 alpha-2 в верхнем регистре
+	// Region — регион окна доступности. Без Available остаются фильмы, у которых
+	// есть хоть одно окно в регионе
+	Region string
+	// Available оставляет фильмы, доступные (true) или недоступные (false) на дату AvailableOn:
+	// в регионе Region, а без него — хотя бы в одном регионе
+	Available   *bool
+	AvailableOn time.Time
+	// RuntimeMin и RuntimeMax — границы продолжительности в минутах включительно; 0 — без границы.
+	// Фильмы с неизвестной продолжительностью под фильтр по продолжительности не попадают
+	RuntimeMin int
+	RuntimeMax int
+}
+
+// IsEmpty сообщает, что фильтр ничего не ограничивает
+func (f MovieFilter) IsEmpty() bool {
+	return f.OriginalLanguage == "" && f.Country == "" && f.Region == "" && f.Available == nil &&
+		f.RuntimeMin == 0 && f.RuntimeMax == 0
+}
+
+// SearchSuggestions — подсказки для поиска без результатов
+type SearchSuggestions struct {
+	DidYouMean     []string `json:"did_you_mean"`    // похожие названия фильмов или имена актёров
+	RelatedQueries []string `json:"related_queries"` // похожие популярные запросы, по которым что-то нашлось
+}
+
+// SearchStat — поисковый запрос и сколько раз его искали за период, в том числе без результатов
+type SearchStat struct {
+	Query       string
+	Searches    int64
+	ZeroResults int64
+}
+
+// SearchAnalytics — самые частые запросы с дня Since и самые частые среди не давших результатов
+type SearchAnalytics struct {
+	Since          time.Time
+	Top            []SearchStat
+	TopZeroResults []SearchStat
+}
+
+// Виды результатов общего поиска по каталогу
+const (
+	SearchHitMovie = "movie"
+	SearchHitActor = "actor"
+)
+
+// SearchHit — фильм или актёр, найденный общим поиском. Name — название фильма или имя актёра,
+// ReleaseYear заполнен только у фильмов. Score — триграммная похожесть на запрос от 0 до 1
+type SearchHit struct {
+	Kind        string
+	ID          int
+	Name        string
+	Slug        string
+	ReleaseYear int
+	Score       float64
+}
+
+// CatalogStats — агрегированные показатели каталога
+type CatalogStats struct {
+	MovieCount      int              `json:"movie_count"`
+	ActorCount      int              `json:"actor_count"`
+	AverageRating   float64          `json:"average_rating"`    // 0, если фильмов нет
+	MoviesPerDecade []DecadeCount    `json:"movies_per_decade"` // по возрастанию десятилетия; фильмы без года не учитываются
+	TopActors       []ActorFilmCount `json:"top_actors"`        // по убыванию числа фильмов
+}
+
+// DecadeCount — число фильмов, вышедших в десятилетии (1990 — фильмы 1990–1999 годов)
+type DecadeCount struct {
+	Decade int `json:"decade"`
+	Movies int `json:"movies"`
+}
+
+// ActorFilmCount — актёр и число фильмов, в которых он снялся
+type ActorFilmCount struct {
+	ActorID int    `json:"actor_id"`
+	Name    string `json:"name"`
+	Movies  int    `json:"movies"`
+}
+
+// ActorStats — сводка по фильмам актёра. AverageRating равен nil, если у актёра нет фильмов;
+// FirstYear и LatestYear равны 0, если ни у одного фильма не указан год
+type ActorStats struct {
+	ActorID       int      `json:"actor_id"`
+	MovieCount    int      `json:"movie_count"`
+	AverageRating *float64 `json:"average_rating"`
+	FirstYear     int      `json:"first_year"`
+	LatestYear    int      `json:"latest_year"`
+}
+
+// ActorsWithMoviesQuery — страница актёров с фильмами. Limit и Offset считаются по актёрам,
+// а не по строкам соединения; Name фильтрует по фрагменту имени. MoviesPerActor оставляет
+// у каждого актёра столько последних фильмов; 0 — все фильмы
+type ActorsWithMoviesQuery struct {
+	Name           string
+	Limit          int
+	Offset         int
+	MoviesPerActor int
+}
+
 // --- USER & AUTH ---
 
 type User struct {
-	ID           int    `json:"id"`
-	Username     string `json:"username"`
-	Email        string `json:"email"`
-	PasswordHash string `json:"-"`
-	Role         string `json:"role"` // "user" или "admin"
+	ID           int        `json:"id"`
+	Username     string     `json:"username"`
+	Email        string     `json:"email"`
+	PasswordHash string     `json:"-"`
+	Role         string     `json:"role"` // "user", "moderator" или "admin"
+	FailedLogins int        `json:"-"`    // неудачные попытки входа подряд
+	LockedUntil  *time.Time `json:"-"`    // вход запрещён до этого момента; nil — не заблокирован
+	TokenVersion int        `json:"-"`    // растёт при смене роли; токены с меньшей версией не принимаются
+}
+
+// UserUpdate — изменения учётной записи пользователем; nil — поле не меняется
+type UserUpdate struct {
+	Email        *string
+	PasswordHash *string
+}
+
+// ExternalUser — пользователь из Keycloak, которому нужна локальная запись в users.
+// Запись связывается с ним по ExternalID и не имеет пароля: вход только через Keycloak
+type ExternalUser struct {
+	ExternalID string
+	Username   string
+	Email      string
+	Role       string
+}
+
+// UserSyncAction — что синхронизация сделала с локальной записью внешнего пользователя
+type UserSyncAction string
+
+const (
+	UserSyncCreated   UserSyncAction = "created"
+	UserSyncUpdated   UserSyncAction = "updated"
+	UserSyncUnchanged UserSyncAction = "unchanged"
+)
+
+// MovieUpsertAction — что PUT /movies/external/:externalId сделал с фильмом
+type MovieUpsertAction string
+
+const (
+	MovieUpsertCreated   MovieUpsertAction = "created"
+	MovieUpsertUpdated   MovieUpsertAction = "updated"
+	MovieUpsertUnchanged MovieUpsertAction = "unchanged"
+)
+
+// UserSyncResult — итог одного прохода синхронизации пользователей Keycloak
+type UserSyncResult struct {
+	Created   int
+	Updated   int
+	Unchanged int
+	Skipped   int // без email или с именем либо email, занятыми локальной учётной записью
 }
 
 const (
-	RoleUser  = "user"
-	RoleAdmin = "admin"
+	RoleUser      = "user"
+	RoleModerator = "moderator" // редактирует каталог, но не удаляет записи и не управляет пользователями
+	RoleAdmin     = "admin"
 )
 
-// Ошибки доменного слоя
+// Permission — право на группу операций; маршруты проверяют права, а не конкретные роли
+type Permission string
+
+const (
+	PermissionCatalogRead   Permission = "catalog:read"   // чтение фильмов, актёров и подборок
+	PermissionCatalogWrite  Permission = "catalog:write"  // создание и изменение записей каталога и связей между ними
+	PermissionCatalogDelete Permission = "catalog:delete" // удаление фильмов, актёров и подборок
+	PermissionManageUsers   Permission = "users:manage"   // управление пользователями и их ролями
+	PermissionManageTags    Permission = "tags:manage"    // добавление и снятие тегов фильмов
+	PermissionEditRatings   Permission = "ratings:write"  // изменение рейтинга уже созданного фильма
+)
+
+// rolePermissions — права каждой роли; неизвестная роль не получает никаких прав
+var rolePermissions = map[string][]Permission{
+	RoleUser:      {PermissionCatalogRead},
+	RoleModerator: {PermissionCatalogRead, PermissionCatalogWrite},
+	RoleAdmin:     {PermissionCatalogRead, PermissionCatalogWrite, PermissionCatalogDelete, PermissionManageUsers, PermissionManageTags, PermissionEditRatings},
+}
+
+// IsKnownRole сообщает, что роль описана в rolePermissions
+func IsKnownRole(role string) bool {
+	_, ok := rolePermissions[role]
+	return ok
+}
+
+// RoleHasPermission сообщает, выдано ли роли указанное право
+func RoleHasPermission(role string, permission Permission) bool {
+	for _, p := range rolePermissions[role] {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// --- EVENTS ---
+
+// ProcessedMessage — запись журнала обработанных сообщений Kafka.
+// MessageID берётся из поля event_id события и может быть пустым
+type ProcessedMessage struct {
+	Group     string
+	Topic     string
+	Partition int
+	Offset    int64
+	MessageID string
+}
+
+// Ошибки доменного слоя. Ошибки, которые видит клиент API, типизированы
+// (см. apperror): их вид определяет HTTP-статус, а код не меняется между версиями
 var (
-	ErrActorNotFound      = errors.New("actor not found")
-	ErrMovieNotFound      = errors.New("movie not found")
-	ErrEmptyPassword      = errors.New("database password not set")
-	ErrEnvNotLoaded       = errors.New("environment variables could not be loaded")
-	ErrActorHasMovies     = errors.New("cannot delete actor: has related movies")
+	ErrActorNotFound        = apperror.NotFound("actor_not_found", "actor not found")
+	ErrMovieNotFound        = apperror.NotFound("movie_not_found", "movie not found")
+	ErrEmptyPassword        = errors.New("database password not set")
+	ErrEnvNotLoaded         = errors.New("environment variables could not be loaded")
+	ErrActorHasMovies       = apperror.Conflict("actor_has_movies", "cannot delete actor: has related movies")
+	ErrMergeSameActor       = apperror.Validation("merge_same_actor", "cannot merge actor with itself")
+	ErrMergeSameMovie       = apperror.Validation("merge_same_movie", "cannot merge movie with itself")
+	ErrInvalidSort          = apperror.Validation("invalid_sort", "invalid sort parameter")
+	ErrDuplicateMovie       = apperror.Conflict("duplicate_movie", "movie looks like a duplicate")
+	ErrActorAlreadyInMovie  = apperror.Conflict("actor_already_in_movie", "actor is already in the movie")
+	ErrActorNotInMovie      = apperror.NotFound("actor_not_in_movie", "actor is not in the movie")
+	ErrNoFieldsToUpdate     = apperror.Validation("no_fields_to_update", "no fields to update")
+	ErrExternalImportOff    = apperror.Unavailable("external_import_disabled", "external movie import is not configured")
+	ErrCollectionNotFound   = apperror.NotFound("collection_not_found", "collection not found")
+	ErrAvailabilityNotFound = apperror.NotFound("availability_not_found", "availability window not found")
+	ErrRatingNotFound       = apperror.NotFound("rating_not_found", "movie has no rating from this source")
+	ErrTagNotFound          = apperror.NotFound("tag_not_found", "movie has no such tag")
+	ErrCollectionDuplicate  = apperror.Validation("collection_duplicate_movie", "movie appears in the collection more than once")
+	ErrWeakPassword         = apperror.Validation("weak_password", "password does not meet the password policy")
+	ErrAccountLocked        = apperror.Forbidden("account_locked", "account is temporarily locked")
+	ErrUserNotFound         = apperror.NotFound("user_not_found", "user not found")
+	ErrInvalidRole          = apperror.Validation("invalid_role", "unknown user role")
+	ErrWrongPassword        = apperror.Forbidden("invalid_current_password", "current password is incorrect")
+	ErrExternalAccount      = apperror.Forbidden("external_account", "account is managed by the identity provider")
+	ErrInvalidResetToken    = apperror.Validation("invalid_reset_token", "password reset token is invalid or expired")
+	ErrPasswordResetOff     = apperror.Unavailable("password_reset_disabled", "password reset by email is not configured")
+	ErrOrphanPurgeTooLarge  = apperror.Conflict("orphan_purge_unconfirmed", "too many actors to purge without confirmation")
+	ErrMovieReferenced      = apperror.Conflict("movie_referenced", "cannot delete movie: it is referenced by other records")
+	ErrAlreadyExists        = apperror.Conflict("already_exists", "record already exists")
+	ErrReferenceNotFound    = apperror.Unprocessable("reference_not_found", "referenced record does not exist")
+	ErrUserAlreadyExists    = apperror.Conflict("user_already_exists", "username or email is already taken")
+	ErrEmailTaken           = apperror.Conflict("email_taken", "email is already taken")
+	ErrFieldsForbidden      = apperror.Forbidden("fields_forbidden", "role is not allowed to change these fields")
 )
+
+// WeakPasswordError перечисляет нарушенные правила парольной политики
+type WeakPasswordError struct {
+	Violations []string
+}
+
+func (e *WeakPasswordError) Error() string {
+	return fmt.Sprintf("%v: %s", ErrWeakPassword, strings.Join(e.Violations, ", "))
+}
+
+func (e *WeakPasswordError) Unwrap() error {
+	return ErrWeakPassword
+}
+
+// AppError дополняет ответ 400 списком нарушенных правил
+func (e *WeakPasswordError) AppError() *apperror.Error {
+	appErr := *ErrWeakPassword
+	appErr.Extensions = map[string]interface{}{"violations": e.Violations}
+	return &appErr
+}
+
+// AccountLockedError возвращается при входе в заблокированную учётную запись
+type AccountLockedError struct {
+	Until time.Time
+}
+
+func (e *AccountLockedError) Error() string {
+	return fmt.Sprintf("%v until %s", ErrAccountLocked, e.Until.UTC().Format(time.RFC3339))
+}
+
+func (e *AccountLockedError) Unwrap() error {
+	return ErrAccountLocked
+}
+
+// AppError дополняет ответ 403 моментом снятия блокировки
+func (e *AccountLockedError) AppError() *apperror.Error {
+	appErr := *ErrAccountLocked
+	appErr.Extensions = map[string]interface{}{"locked_until": e.Until.UTC().Format(time.RFC3339)}
+	return &appErr
+}
+
+// OrphanPurgeError возвращается, когда актёров без фильмов больше, чем разрешено удалить
+// без подтверждения. Count — сколько актёров удалилось бы; его передают в confirm_count
+type OrphanPurgeError struct {
+	Count int
+}
+
+func (e *OrphanPurgeError) Error() string {
+	return fmt.Sprintf("%v: %d actors", ErrOrphanPurgeTooLarge, e.Count)
+}
+
+func (e *OrphanPurgeError) Unwrap() error {
+	return ErrOrphanPurgeTooLarge
+}
+
+// AppError дополняет ответ 409 числом актёров, которое нужно подтвердить
+func (e *OrphanPurgeError) AppError() *apperror.Error {
+	appErr := *ErrOrphanPurgeTooLarge
+	appErr.Extensions = map[string]interface{}{"orphan_count": e.Count}
+	return &appErr
+}
+
+// MovieReferencedError возвращается при удалении фильма, на который ещё ссылаются другие записи,
+// если политика удаления — MovieDeleteRestrict. References перечисляет виды ссылок и их число
+type MovieReferencedError struct {
+	References []MovieReference
+}
+
+func (e *MovieReferencedError) Error() string {
+	parts := make([]string, 0, len(e.References))
+	for _, ref := range e.References {
+		parts = append(parts, fmt.Sprintf("%s (%d)", ref.Kind, ref.Count))
+	}
+	return fmt.Sprintf("%v: %s", ErrMovieReferenced, strings.Join(parts, ", "))
+}
+
+func (e *MovieReferencedError) Unwrap() error {
+	return ErrMovieReferenced
+}
+
+// AppError дополняет ответ 409 списком ссылок, которые мешают удалению
+func (e *MovieReferencedError) AppError() *apperror.Error {
+	appErr := *ErrMovieReferenced
+	appErr.Extensions = map[string]interface{}{"references": e.References}
+	return &appErr
+}
+
+// FieldsForbiddenError возвращается, когда запрос меняет поля, на которые у роли
+// пользователя нет отдельного права. Fields — имена полей в JSON
+type FieldsForbiddenError struct {
+	Fields []string
+}
+
+func (e *FieldsForbiddenError) Error() string {
+	return fmt.Sprintf("%v: %s", ErrFieldsForbidden, strings.Join(e.Fields, ", "))
+}
+
+func (e *FieldsForbiddenError) Unwrap() error {
+	return ErrFieldsForbidden
+}
+
+// AppError дополняет ответ 403 списком полей, которые роли менять нельзя
+func (e *FieldsForbiddenError) AppError() *apperror.Error {
+	appErr := *ErrFieldsForbidden
+	appErr.Extensions = map[string]interface{}{"fields": e.Fields}
+	return &appErr
+}
+
+// DuplicateMovieError возвращается при создании фильма с тем же нормализованным
+// названием и годом выпуска, что у существующего
+type DuplicateMovieError struct {
+	ExistingID int
+}
+
+func (e *DuplicateMovieError) Error() string {
+	return fmt.Sprintf("%v of movie %d", ErrDuplicateMovie, e.ExistingID)
+}
+
+func (e *DuplicateMovieError) Unwrap() error {
+	return ErrDuplicateMovie
+}
+
+// AppError дополняет ответ 409 ID существующего фильма
+func (e *DuplicateMovieError) AppError() *apperror.Error {
+	appErr := *ErrDuplicateMovie
+	appErr.Extensions = map[string]interface{}{"existing_id": e.ExistingID}
+	return &appErr
+}
@@ -0,0 +1,119 @@
+// Package eventbus доставляет доменные события внутри процесса. Обработчики HTTP публикуют
+// событие в шину и не знают, куда оно уйдёт дальше: отправку в Kafka, запись в лог или
+// вызов вебхука выполняют подписчики, и новый приёмник добавляется одной подпиской.
+//
+// Доставка синхронная: Publish вызывает подписчиков по очереди в порядке подписки и
+// возвращает их ошибки. Поэтому подписчик не должен надолго блокироваться — медленную
+// работу он ставит в собственную очередь, как это делает пул продюсеров Kafka
+package eventbus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"cinematique/internal/kafka/events"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var deliveriesTotal = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "eventbus_deliveries_total",
+		Help: "Total number of events delivered to in-process subscribers by subscriber and result (ok, failed).",
+	},
+	[]string{"subscriber", "result"},
+)
+
+func init() {
+	prometheus.MustRegister(deliveriesTotal)
+}
+
+// Message — опубликованное событие. Topic и Key — адрес доставки для подписчиков, которые
+// пересылают события во внешние системы: топик Kafka и ключ партиционирования
+type Message struct {
+	Topic string
+	Key   []byte
+	Event events.Event
+}
+
+// Handler обрабатывает опубликованное событие
+type Handler func(ctx context.Context, msg Message) error
+
+type subscriber struct {
+	id      int
+	name    string
+	handler Handler
+}
+
+// Bus — шина событий. Нулевое значение не используется, создавайте шину через New
+type Bus struct {
+	mu          sync.RWMutex
+	nextID      int
+	subscribers []subscriber
+}
+
+// New создаёт шину без подписчиков
+func New() *Bus {
+	return &Bus{}
+}
+
+// Subscribe подписывает handler на все события; name попадает в ошибки и метрики доставки.
+// Возвращает функцию отписки
+func (b *Bus) Subscribe(name string, handler Handler) (unsubscribe func()) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.nextID++
+	id := b.nextID
+	b.subscribers = append(b.subscribers, subscriber{id: id, name: name, handler: handler})
+	return func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		for i, sub := range b.subscribers {
+			if sub.id == id {
+				b.subscribers = append(b.subscribers[:i:i], b.subscribers[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// On подписывает fn только на события типа T, например *events.CatalogChanged
+func On[T events.Event](b *Bus, name string, fn func(ctx context.Context, msg Message, event T) error) (unsubscribe func()) {
+	return b.Subscribe(name, func(ctx context.Context, msg Message) error {
+		event, ok := msg.Event.(T)
+		if !ok {
+			return nil
+		}
+		return fn(ctx, msg, event)
+	})
+}
+
+// Publish проверяет событие по схеме и передаёт его всем подписчикам. Ошибка одного
+// подписчика не мешает доставке остальным; ошибки объединяются в возвращаемую.
+// Шина nil ничего не делает: обработчики без настроенных событий работают как раньше
+func (b *Bus) Publish(ctx context.Context, msg Message) error {
+	if b == nil {
+		return nil
+	}
+	if err := events.Check(msg.Event); err != nil {
+		return err
+	}
+
+	b.mu.RLock()
+	subscribers := append([]subscriber(nil), b.subscribers...)
+	b.mu.RUnlock()
+
+	var errs []error
+	for _, sub := range subscribers {
+		if err := sub.handler(ctx, msg); err != nil {
+			deliveriesTotal.WithLabelValues(sub.name, "failed").Inc()
+			errs = append(errs, fmt.Errorf("%s: %w", sub.name, err))
+			continue
+		}
+		deliveriesTotal.WithLabelValues(sub.name, "ok").Inc()
+	}
+	return errors.Join(errs...)
+}
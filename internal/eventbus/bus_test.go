@@ -0,0 +1,108 @@
+package eventbus
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"testing"
+
+	"cinematique/internal/kafka"
+	"cinematique/internal/kafka/events"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBus_PublishDeliversToAllSubscribers(t *testing.T) {
+	bus := New()
+	var got []string
+	bus.Subscribe("first", func(_ context.Context, msg Message) error {
+		got = append(got, "first:"+msg.Topic)
+		return nil
+	})
+	unsubscribe := bus.Subscribe("second", func(_ context.Context, msg Message) error {
+		got = append(got, "second:"+msg.Topic)
+		return nil
+	})
+
+	msg := Message{Topic: "movie-views", Key: []byte("7"), Event: events.NewMovieViewed(7)}
+	require.NoError(t, bus.Publish(context.Background(), msg))
+	assert.Equal(t, []string{"first:movie-views", "second:movie-views"}, got)
+
+	// После отписки событие получает только первый подписчик
+	unsubscribe()
+	got = nil
+	require.NoError(t, bus.Publish(context.Background(), msg))
+	assert.Equal(t, []string{"first:movie-views"}, got)
+}
+
+func TestBus_PublishJoinsSubscriberErrors(t *testing.T) {
+	bus := New()
+	delivered := false
+	bus.Subscribe("kafka", func(context.Context, Message) error { return kafka.ErrPoolClosed })
+	bus.Subscribe("webhook", func(context.Context, Message) error {
+		delivered = true
+		return nil
+	})
+
+	err := bus.Publish(context.Background(), Message{Event: events.NewMovieViewed(7)})
+	assert.ErrorIs(t, err, kafka.ErrPoolClosed)
+	assert.Contains(t, err.Error(), "kafka: ")
+	assert.True(t, delivered, "failure of one subscriber must not stop delivery to the rest")
+}
+
+func TestBus_PublishRejectsInvalidEvents(t *testing.T) {
+	bus := New()
+	bus.Subscribe("never", func(context.Context, Message) error {
+		t.Fatal("invalid event must not reach subscribers")
+		return nil
+	})
+
+	assert.ErrorIs(t, bus.Publish(context.Background(), Message{Event: events.NewMovieViewed(0)}), events.ErrInvalidEvent)
+	assert.ErrorIs(t, bus.Publish(context.Background(), Message{}), events.ErrInvalidEvent)
+}
+
+func TestBus_NilIsNoop(t *testing.T) {
+	var bus *Bus
+	assert.NoError(t, bus.Publish(context.Background(), Message{Event: events.NewMovieViewed(7)}))
+}
+
+func TestOn_FiltersByEventType(t *testing.T) {
+	bus := New()
+	var ids []int
+	On(bus, "catalog", func(_ context.Context, _ Message, event *events.CatalogChanged) error {
+		ids = append(ids, event.ID)
+		return nil
+	})
+
+	require.NoError(t, bus.Publish(context.Background(), Message{Event: events.NewMovieViewed(7)}))
+	require.NoError(t, bus.Publish(context.Background(), Message{Event: events.NewCatalogChanged(events.EntityMovie, events.ActionCreated, 3)}))
+	assert.Equal(t, []int{3}, ids)
+}
+
+func TestKafka_ProducesToTopicAndKey(t *testing.T) {
+	producer := kafka.NewMockProducer()
+	producer.On("Produce", mock.Anything, "movie-views", []byte("7"), mock.Anything).Return(nil).Once()
+	producer.On("Close").Return(nil)
+	pool := kafka.NewProducerPool(producer, 1, 10)
+
+	bus := New()
+	bus.Subscribe("kafka", Kafka(pool))
+	require.NoError(t, bus.Publish(context.Background(), Message{Topic: "movie-views", Key: []byte("7"), Event: events.NewMovieViewed(7)}))
+	pool.Close()
+	producer.AssertExpectations(t)
+
+	// Закрытый пул событие не принимает
+	err := bus.Publish(context.Background(), Message{Topic: "movie-views", Event: events.NewMovieViewed(7)})
+	assert.ErrorIs(t, err, kafka.ErrPoolClosed)
+}
+
+func TestLog_WritesEventSummary(t *testing.T) {
+	var buf bytes.Buffer
+	bus := New()
+	bus.Subscribe("log", Log(log.New(&buf, "", 0)))
+
+	require.NoError(t, bus.Publish(context.Background(), Message{Topic: "movie-views", Key: []byte("7"), Event: events.NewMovieViewed(7)}))
+	assert.Equal(t, "event movie_viewed (topic: movie-views, key: 7)\n", buf.String())
+}
@@ -0,0 +1,30 @@
+package eventbus
+
+import (
+	"context"
+	"log"
+
+	"cinematique/internal/kafka"
+	"cinematique/internal/kafka/events"
+)
+
+// Kafka возвращает подписчика, который сериализует событие и ставит его в очередь пула
+// продюсеров с топиком и ключом сообщения. Ошибка означает, что пул событие не принял
+func Kafka(pool *kafka.ProducerPool) Handler {
+	return func(ctx context.Context, msg Message) error {
+		payload, err := events.Marshal(msg.Event)
+		if err != nil {
+			return err
+		}
+		return pool.ProduceContext(ctx, msg.Topic, msg.Key, payload)
+	}
+}
+
+// Log возвращает подписчика, который пишет тип, топик и ключ каждого события в logger.
+// Удобен при локальной разработке без Kafka
+func Log(logger *log.Logger) Handler {
+	return func(_ context.Context, msg Message) error {
+		logger.Printf("event %s (topic: %s, key: %s)", events.TypeOf(msg.Event), msg.Topic, msg.Key)
+		return nil
+	}
+}
@@ -0,0 +1,129 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"cinematique/internal/apperror"
+	"cinematique/internal/auth"
+	"cinematique/internal/controller/dto"
+	"cinematique/internal/domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+// currentLocalUserID возвращает ID текущего пользователя в таблице users.
+// Учётными записями Keycloak управляет Keycloak, поэтому для них возвращается ErrExternalAccount
+func currentLocalUserID(c *gin.Context) (int, error) {
+	user, ok := auth.CurrentUser(c)
+	if !ok {
+		return 0, apperror.Unauthorized("unauthorized", "authentication required")
+	}
+	if user.AuthType != auth.AuthTypeJWT || user.LocalUserID == 0 {
+		return 0, domain.ErrExternalAccount
+	}
+	return user.LocalUserID, nil
+}
+
+func profileResponse(user domain.User) dto.UserProfileResponse {
+	return dto.UserProfileResponse{ID: user.ID, Username: user.Username, Email: user.Email, Role: user.Role}
+}
+
+// GetMe возвращает учётную запись текущего пользователя
+func (h *AuthHandler) GetMe(c *gin.Context) {
+	userID, err := currentLocalUserID(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	user, err := h.service.GetProfile(userID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, profileResponse(user))
+}
+
+// UpdateMe меняет email и (или) пароль текущего пользователя. После смены пароля
+// выпущенные ранее токены, включая текущий, перестают действовать
+func (h *AuthHandler) UpdateMe(c *gin.Context) {
+	userID, err := currentLocalUserID(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	var req dto.UpdateProfileRequest
+	if err := bindJSON(c, &req); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	user, err := h.service.UpdateProfile(c.Request.Context(), userID, req.CurrentPassword, req.Email, req.NewPassword)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, profileResponse(user))
+}
+
+// DeleteMe удаляет учётную запись текущего пользователя и отзывает её токены
+func (h *AuthHandler) DeleteMe(c *gin.Context) {
+	userID, err := currentLocalUserID(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	var req dto.DeleteAccountRequest
+	if err := bindJSON(c, &req); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	err = h.service.DeleteAccount(c.Request.Context(), userID, req.CurrentPassword)
+	respond(c, http.StatusNoContent, nil, err)
+}
+
+// ChangeUserRole назначает пользователю роль. Его токены доступа со старой ролью
+// отклоняются в течение нескольких секунд, и новая роль приходит с ближайшим refresh
+func (h *AuthHandler) ChangeUserRole(c *gin.Context) {
+	userID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		respondError(c, errInvalidID)
+		return
+	}
+	var req dto.ChangeRoleRequest
+	if err := bindJSON(c, &req); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	user, err := h.service.ChangeRole(c.Request.Context(), userID, req.Role)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, profileResponse(user))
+}
+
+// ForgotPassword отправляет ссылку сброса пароля. Ответ 202 не зависит от того,
+// зарегистрирован ли адрес, чтобы по нему нельзя было проверять чужие email
+func (h *AuthHandler) ForgotPassword(c *gin.Context) {
+	var req dto.ForgotPasswordRequest
+	if err := bindJSON(c, &req); err != nil {
+		respondError(c, err)
+		return
+	}
+	err := h.service.ForgotPassword(c.Request.Context(), req.Email)
+	respond(c, http.StatusAccepted, nil, err)
+}
+
+// ResetPassword задаёт новый пароль по токену из письма
+func (h *AuthHandler) ResetPassword(c *gin.Context) {
+	var req dto.ResetPasswordRequest
+	if err := bindJSON(c, &req); err != nil {
+		respondError(c, err)
+		return
+	}
+	err := h.service.ResetPassword(c.Request.Context(), req.Token, req.NewPassword)
+	respond(c, http.StatusNoContent, nil, err)
+}
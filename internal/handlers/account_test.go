@@ -0,0 +1,276 @@
+package handlers
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"cinematique/internal/apperror"
+	"cinematique/internal/auth"
+	"cinematique/internal/domain"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// newAccountRouter регистрирует маршруты учётных записей от имени заданного пользователя
+func newAccountRouter(service *MockAuthService, user *auth.UserContext) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(apperror.Middleware(), func(c *gin.Context) {
+		auth.SetUser(c, user)
+		c.Next()
+	})
+	RegisterAccountRoutes(&r.RouterGroup, NewAuthHandler(service, nil))
+	return r
+}
+
+func TestAuthHandler_Account(t *testing.T) {
+	local := &auth.UserContext{AuthType: auth.AuthTypeJWT, LocalUserID: 1, Username: "neo", Role: "user"}
+	neo := domain.User{ID: 1, Username: "neo", Email: "neo@zion.io", Role: "user"}
+	email := "neo@zion.io"
+
+	tests := []struct {
+		name           string
+		user           *auth.UserContext
+		method         string
+		body           string
+		setupMock      func(*MockAuthService)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:   "get profile",
+			user:   local,
+			method: http.MethodGet,
+			setupMock: func(m *MockAuthService) {
+				m.On("GetProfile", 1).Return(neo, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"id":1,"username":"neo","email":"neo@zion.io","role":"user"}`,
+		},
+		{
+			name:           "keycloak account is managed externally",
+			user:           &auth.UserContext{AuthType: auth.AuthTypeKeycloak, UserID: "kc-1", Username: "neo"},
+			method:         http.MethodGet,
+			setupMock:      func(m *MockAuthService) {},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:   "update email",
+			user:   local,
+			method: http.MethodPatch,
+			body:   `{"current_password":"password123","email":"neo@zion.io"}`,
+			setupMock: func(m *MockAuthService) {
+				m.On("UpdateProfile", 1, "password123", &email, (*string)(nil)).Return(neo, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"id":1,"username":"neo","email":"neo@zion.io","role":"user"}`,
+		},
+		{
+			name:           "update requires current password",
+			user:           local,
+			method:         http.MethodPatch,
+			body:           `{"email":"neo@zion.io"}`,
+			setupMock:      func(m *MockAuthService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "wrong current password",
+			user:   local,
+			method: http.MethodPatch,
+			body:   `{"current_password":"wrong","new_password":"correct-horse-42"}`,
+			setupMock: func(m *MockAuthService) {
+				m.On("UpdateProfile", 1, "wrong", (*string)(nil), mock.Anything).Return(domain.User{}, domain.ErrWrongPassword)
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:   "delete account",
+			user:   local,
+			method: http.MethodDelete,
+			body:   `{"current_password":"password123"}`,
+			setupMock: func(m *MockAuthService) {
+				m.On("DeleteAccount", 1, "password123").Return(nil)
+			},
+			expectedStatus: http.StatusNoContent,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := new(MockAuthService)
+			tt.setupMock(service)
+			r := newAccountRouter(service, tt.user)
+
+			req := httptest.NewRequest(tt.method, "/users/me", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedBody != "" {
+				assert.JSONEq(t, tt.expectedBody, w.Body.String())
+			}
+			service.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAuthHandler_ChangeUserRole(t *testing.T) {
+	admin := &auth.UserContext{AuthType: auth.AuthTypeJWT, LocalUserID: 1, Username: "morpheus", Role: domain.RoleAdmin}
+	moderator := &auth.UserContext{AuthType: auth.AuthTypeJWT, LocalUserID: 3, Username: "niobe", Role: domain.RoleModerator}
+
+	tests := []struct {
+		name           string
+		user           *auth.UserContext
+		path           string
+		body           string
+		setupMock      func(*MockAuthService)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "admin changes role",
+			user: admin,
+			path: "/users/2/role",
+			body: `{"role":"moderator"}`,
+			setupMock: func(m *MockAuthService) {
+				m.On("ChangeRole", 2, "moderator").
+					Return(domain.User{ID: 2, Username: "neo", Email: "neo@zion.io", Role: "moderator"}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"id":2,"username":"neo","email":"neo@zion.io","role":"moderator"}`,
+		},
+		{
+			name: "unknown role",
+			user: admin,
+			path: "/users/2/role",
+			body: `{"role":"superuser"}`,
+			setupMock: func(m *MockAuthService) {
+				m.On("ChangeRole", 2, "superuser").Return(domain.User{}, domain.ErrInvalidRole)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "user not found",
+			user: admin,
+			path: "/users/9/role",
+			body: `{"role":"user"}`,
+			setupMock: func(m *MockAuthService) {
+				m.On("ChangeRole", 9, "user").Return(domain.User{}, domain.ErrUserNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "invalid id",
+			user:           admin,
+			path:           "/users/neo/role",
+			body:           `{"role":"user"}`,
+			setupMock:      func(m *MockAuthService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "moderator cannot manage users",
+			user:           moderator,
+			path:           "/users/2/role",
+			body:           `{"role":"admin"}`,
+			setupMock:      func(m *MockAuthService) {},
+			expectedStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := new(MockAuthService)
+			tt.setupMock(service)
+			r := newAccountRouter(service, tt.user)
+
+			req := httptest.NewRequest(http.MethodPut, tt.path, bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedBody != "" {
+				assert.JSONEq(t, tt.expectedBody, w.Body.String())
+			}
+			service.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAuthHandler_PasswordReset(t *testing.T) {
+	tests := []struct {
+		name           string
+		path           string
+		body           string
+		setupMock      func(*MockAuthService)
+		expectedStatus int
+	}{
+		{
+			name: "forgot password",
+			path: "/auth/forgot-password",
+			body: `{"email":"neo@zion.io"}`,
+			setupMock: func(m *MockAuthService) {
+				m.On("ForgotPassword", "neo@zion.io").Return(nil)
+			},
+			expectedStatus: http.StatusAccepted,
+		},
+		{
+			name:           "forgot password requires valid email",
+			path:           "/auth/forgot-password",
+			body:           `{"email":"neo"}`,
+			setupMock:      func(m *MockAuthService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "forgot password without mailer",
+			path: "/auth/forgot-password",
+			body: `{"email":"neo@zion.io"}`,
+			setupMock: func(m *MockAuthService) {
+				m.On("ForgotPassword", "neo@zion.io").Return(domain.ErrPasswordResetOff)
+			},
+			expectedStatus: http.StatusServiceUnavailable,
+		},
+		{
+			name: "reset password",
+			path: "/auth/reset-password",
+			body: `{"token":"reset-token","new_password":"correct-horse-42"}`,
+			setupMock: func(m *MockAuthService) {
+				m.On("ResetPassword", "reset-token", "correct-horse-42").Return(nil)
+			},
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name: "reset password with used token",
+			path: "/auth/reset-password",
+			body: `{"token":"reset-token","new_password":"correct-horse-42"}`,
+			setupMock: func(m *MockAuthService) {
+				m.On("ResetPassword", "reset-token", "correct-horse-42").Return(domain.ErrInvalidResetToken)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			service := new(MockAuthService)
+			tt.setupMock(service)
+			r := gin.New()
+			r.Use(apperror.Middleware())
+			RegisterAuthRoutes(&r.RouterGroup, NewAuthHandler(service, nil))
+
+			req := httptest.NewRequest(http.MethodPost, tt.path, bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			service.AssertExpectations(t)
+		})
+	}
+}
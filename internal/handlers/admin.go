@@ -0,0 +1,291 @@
+package handlers
+
+import (
+	"context"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"cinematique/internal/apperror"
+	"cinematique/internal/controller/dto"
+	"cinematique/internal/eventbus"
+	"cinematique/internal/jobs"
+	"cinematique/internal/kafka/events"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AdminHandler обрабатывает административные операции над каталогом
+type AdminHandler struct {
+	actorController ActorController
+	movieController MovieController
+	jobs            *jobs.Manager
+	bus             *eventbus.Bus
+	strictEvents    bool                  // импорт фильма отменяется, если событие movie_created не поставлено в очередь
+	maintenance     MaintenanceController // опционально: служебные операции над базой данных
+}
+
+const (
+	defaultJobWaitTimeout = 30 * time.Second
+	maxJobWaitTimeout     = 60 * time.Second
+
+	maxActorImportSize = 1 << 20 // предельный размер CSV-файла импорта актёров
+)
+
+// NewAdminHandler создаёт обработчик (handler) для административных операций
+func NewAdminHandler(actorController ActorController, movieController MovieController, jobManager *jobs.Manager, bus *eventbus.Bus) *AdminHandler {
+	return &AdminHandler{
+		actorController: actorController,
+		movieController: movieController,
+		jobs:            jobManager,
+		bus:             bus,
+	}
+}
+
+// WithStrictEvents включает строгий режим событий для импорта фильмов (см. MovieHandler.WithStrictEvents)
+func (h *AdminHandler) WithStrictEvents(enabled bool) *AdminHandler {
+	h.strictEvents = enabled
+	return h
+}
+
+// MergeActors объединяет актёра-дубликата с основным актёром
+func (h *AdminHandler) MergeActors(c *gin.Context) {
+	keepID, err := strconv.Atoi(c.Param("keepId"))
+	if err != nil {
+		respondError(c, apperror.Validation("invalid_id", "invalid keep id"))
+		return
+	}
+	dupID, err := strconv.Atoi(c.Param("dupId"))
+	if err != nil {
+		respondError(c, apperror.Validation("invalid_id", "invalid duplicate id"))
+		return
+	}
+
+	resp, err := h.actorController.MergeActors(c, keepID, dupID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	// Отправляем событие слияния в Kafka, чтобы внешние системы обновили ссылки на дубликат
+	event := events.NewActorMerged(keepID, dupID, resp.MoviesReassigned)
+	if err := publishEvent(c.Request.Context(), h.bus, "actor-merges", []byte(strconv.Itoa(keepID)), event); err != nil {
+		// Слияние уже зафиксировано в БД, поэтому только логируем ошибку
+		log.Printf("Failed to send actor merge event (keep: %d, duplicate: %d): %v", keepID, dupID, err)
+	}
+	// Дубликат удалён, а основной актёр получил его фильмы
+	publishCatalogChange(c.Request.Context(), h.bus, "actor", catalogActionDeleted, dupID)
+	publishCatalogChange(c.Request.Context(), h.bus, "actor", catalogActionUpdated, keepID)
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// ListOrphanActors возвращает актёров, не связанных ни с одним фильмом
+func (h *AdminHandler) ListOrphanActors(c *gin.Context) {
+	resp, err := h.actorController.ListOrphanActors(c)
+	respond(c, http.StatusOK, resp, err)
+}
+
+// PurgeOrphanActors удаляет актёров без фильмов. Тело запроса необязательно: confirm_count
+// нужен, только когда актёров больше порога из GET /admin/actors/orphans
+func (h *AdminHandler) PurgeOrphanActors(c *gin.Context) {
+	var req dto.PurgeOrphanActorsRequest
+	if c.Request.ContentLength != 0 {
+		if err := bindJSON(c, &req); err != nil {
+			respondError(c, err)
+			return
+		}
+	}
+
+	resp, err := h.actorController.PurgeOrphanActors(c, req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	for _, id := range resp.IDs {
+		publishCatalogChange(c.Request.Context(), h.bus, "actor", catalogActionDeleted, id)
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// ImportActors импортирует актёров из CSV: тело запроса или поле file формы multipart.
+// С ?dry_run=true строки только проверяются. Ответ всегда содержит отчёт по строкам:
+// 200 — проверка без записи, 201 — актёры созданы, 422 — есть ошибки и ничего не записано
+func (h *AdminHandler) ImportActors(c *gin.Context) {
+	dryRun := false
+	if value := c.Query("dry_run"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			respondError(c, apperror.Validation("invalid_parameter", "invalid dry_run parameter"))
+			return
+		}
+		dryRun = parsed
+	}
+
+	// Файл больше лимита обрывается ошибкой чтения, а не молча усекается до части строк
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxActorImportSize)
+	var body io.Reader = c.Request.Body
+	if strings.HasPrefix(c.ContentType(), "multipart/") {
+		file, err := c.FormFile("file")
+		if err != nil {
+			respondError(c, apperror.Validation("field_required", "file is required"))
+			return
+		}
+		f, err := file.Open()
+		if err != nil {
+			respondError(c, apperror.Validation("invalid_request", "cannot read file"))
+			return
+		}
+		defer f.Close()
+		body = f
+	}
+
+	resp, err := h.actorController.ImportActorsCSV(c, body, dryRun)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	switch {
+	case resp.Invalid > 0 && !dryRun:
+		c.JSON(http.StatusUnprocessableEntity, resp)
+	case resp.Created > 0:
+		for _, row := range resp.Rows {
+			publishCatalogChange(c.Request.Context(), h.bus, "actor", catalogActionCreated, row.ID)
+		}
+		c.JSON(http.StatusCreated, resp)
+	default:
+		c.JSON(http.StatusOK, resp)
+	}
+}
+
+// MergeMovies объединяет фильм-дубликат с основным фильмом
+func (h *AdminHandler) MergeMovies(c *gin.Context) {
+	var req dto.MergeMoviesRequest
+	if err := bindJSON(c, &req); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	resp, err := h.movieController.MergeMovies(c, req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	// Отправляем событие слияния в Kafka, чтобы внешние системы перенесли данные дубликата
+	event := events.NewMovieMerged(req.KeepID, req.DuplicateID, resp.ActorsReassigned)
+	if err := publishEvent(c.Request.Context(), h.bus, "movie-merges", []byte(strconv.Itoa(req.KeepID)), event); err != nil {
+		// Слияние уже зафиксировано в БД, поэтому только логируем ошибку
+		log.Printf("Failed to send movie merge event (keep: %d, duplicate: %d): %v", req.KeepID, req.DuplicateID, err)
+	}
+	// Дубликат удалён, а основной фильм получил его связи и незаполненные поля
+	publishCatalogChange(c.Request.Context(), h.bus, "movie", catalogActionDeleted, req.DuplicateID)
+	publishCatalogChange(c.Request.Context(), h.bus, "movie", catalogActionUpdated, req.KeepID)
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// BulkDeleteMovies удаляет несколько фильмов в одной транзакции. Ответ содержит результат
+// по каждому фильму: отсутствующие фильмы не отменяют удаление остальных
+func (h *AdminHandler) BulkDeleteMovies(c *gin.Context) {
+	var req dto.BulkDeleteMoviesRequest
+	if err := bindJSON(c, &req); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	resp, err := h.movieController.BulkDeleteMovies(c, req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	for _, item := range resp.Results {
+		if item.Status == "ok" {
+			publishCatalogChange(c.Request.Context(), h.bus, "movie", catalogActionDeleted, item.ID)
+		}
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// BulkUpdateMovies применяет одни и те же изменения к нескольким фильмам в одной транзакции.
+// Ответ содержит результат по каждому фильму
+func (h *AdminHandler) BulkUpdateMovies(c *gin.Context) {
+	var req dto.BulkUpdateMoviesRequest
+	if err := bindJSON(c, &req); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	resp, err := h.movieController.BulkUpdateMovies(c, req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	for _, item := range resp.Results {
+		if item.Status == "ok" {
+			publishCatalogChange(c.Request.Context(), h.bus, "movie", catalogActionUpdated, item.ID)
+		}
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// ImportExternalMovie импортирует фильм из внешнего каталога по параметру imdb_id
+func (h *AdminHandler) ImportExternalMovie(c *gin.Context) {
+	imdbID := c.Query("imdb_id")
+	if imdbID == "" {
+		respondError(c, apperror.Validation("field_required", "imdb_id is required"))
+		return
+	}
+
+	resp, err := h.movieController.ImportExternalMovie(c, imdbID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	if err := publishMovieCreated(c, h.bus, h.strictEvents, h.movieController, resp.Movie.ID, resp.CreatedActorIDs); err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, resp)
+}
+
+// GetJob возвращает текущее состояние фоновой задачи
+func (h *AdminHandler) GetJob(c *gin.Context) {
+	job, err := h.jobs.Get(c.Param("id"))
+	respond(c, http.StatusOK, job, err)
+}
+
+// WaitJob ждёт завершения фоновой задачи (long polling) не дольше timeout
+// и возвращает её состояние; по истечении таймаута статус может быть running
+func (h *AdminHandler) WaitJob(c *gin.Context) {
+	timeout := defaultJobWaitTimeout
+	if raw := c.Query("timeout"); raw != "" {
+		parsed, err := parseWaitTimeout(raw)
+		if err != nil || parsed <= 0 {
+			respondError(c, apperror.Validation("invalid_parameter", "invalid timeout"))
+			return
+		}
+		timeout = parsed
+	}
+	if timeout > maxJobWaitTimeout {
+		timeout = maxJobWaitTimeout
+	}
+
+	// Контекст запроса отменяется, если клиент закрыл соединение
+	ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
+	defer cancel()
+
+	job, err := h.jobs.Wait(ctx, c.Param("id"))
+	respond(c, http.StatusOK, job, err)
+}
+
+// parseWaitTimeout разбирает таймаут в формате Go duration (30s, 1m) или в секундах (30)
+func parseWaitTimeout(raw string) (time.Duration, error) {
+	if seconds, err := strconv.Atoi(raw); err == nil {
+		return time.Duration(seconds) * time.Second, nil
+	}
+	return time.ParseDuration(raw)
+}
@@ -0,0 +1,620 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"cinematique/internal/apperror"
+	"cinematique/internal/controller/dto"
+	"cinematique/internal/domain"
+	"cinematique/internal/jobs"
+	"cinematique/internal/kafka"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestAdminHandler_MergeActors(t *testing.T) {
+	tests := []struct {
+		name           string
+		url            string
+		setupMock      func(*MockActorController)
+		expectEvent    bool
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "success",
+			url:  "/admin/actors/1/merge/2",
+			setupMock: func(m *MockActorController) {
+				m.On("MergeActors", mock.Anything, 1, 2).Return(dto.ActorMergeResponse{
+					Actor:            dto.ActorResponse{ID: 1, Name: "Test Actor", Gender: "male", BirthDate: "1990-01-01"},
+					DuplicateID:      2,
+					MoviesReassigned: 4,
+				}, nil)
+			},
+			expectEvent:    true,
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"actor":{"id":1,"name":"Test Actor","gender":"male","birth_date":"1990-01-01"},"duplicate_id":2,"movies_reassigned":4}`,
+		},
+		{
+			name:           "invalid keep id",
+			url:            "/admin/actors/abc/merge/2",
+			setupMock:      func(m *MockActorController) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   problem(http.StatusBadRequest, "invalid_id", "invalid keep id"),
+		},
+		{
+			name:           "invalid duplicate id",
+			url:            "/admin/actors/1/merge/abc",
+			setupMock:      func(m *MockActorController) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   problem(http.StatusBadRequest, "invalid_id", "invalid duplicate id"),
+		},
+		{
+			name: "actor not found",
+			url:  "/admin/actors/1/merge/999",
+			setupMock: func(m *MockActorController) {
+				m.On("MergeActors", mock.Anything, 1, 999).Return(dto.ActorMergeResponse{}, domain.ErrActorNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   problem(http.StatusNotFound, "actor_not_found", "actor not found"),
+		},
+		{
+			name: "same actor",
+			url:  "/admin/actors/1/merge/1",
+			setupMock: func(m *MockActorController) {
+				m.On("MergeActors", mock.Anything, 1, 1).Return(dto.ActorMergeResponse{}, domain.ErrMergeSameActor)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   problem(http.StatusBadRequest, "merge_same_actor", "cannot merge actor with itself"),
+		},
+		{
+			name: "controller error",
+			url:  "/admin/actors/1/merge/2",
+			setupMock: func(m *MockActorController) {
+				m.On("MergeActors", mock.Anything, 1, 2).Return(dto.ActorMergeResponse{}, errors.New("database error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   problem(http.StatusInternalServerError, apperror.CodeInternal, "Internal Server Error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			r := gin.New()
+			r.Use(apperror.Middleware())
+			mockCtrl := new(MockActorController)
+			tt.setupMock(mockCtrl)
+
+			producer := kafka.NewMockProducer()
+			producer.On("Produce", mock.Anything, "actor-merges", []byte("1"), mock.Anything).Return(nil)
+			producer.On("Produce", mock.Anything, CatalogChangesTopic, mock.Anything, mock.Anything).Return(nil)
+			producer.On("Close").Return(nil)
+			producerPool := kafka.NewProducerPool(producer, 1, 10)
+
+			handler := NewAdminHandler(mockCtrl, new(MockMovieController), nil, kafkaBus(producerPool))
+			r.POST("/admin/actors/:keepId/merge/:dupId", handler.MergeActors)
+
+			req, _ := http.NewRequest(http.MethodPost, tt.url, nil)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			// Закрываем пул, чтобы дождаться обработки события воркером
+			producerPool.Close()
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.JSONEq(t, tt.expectedBody, w.Body.String())
+			if tt.expectEvent {
+				producer.AssertCalled(t, "Produce", mock.Anything, "actor-merges", []byte("1"), mock.Anything)
+				producer.AssertCalled(t, "Produce", mock.Anything, CatalogChangesTopic, []byte("actor:2"), mock.MatchedBy(func(payload []byte) bool {
+					return bytes.Contains(payload, []byte(`"type":"actor_deleted"`))
+				}))
+				producer.AssertCalled(t, "Produce", mock.Anything, CatalogChangesTopic, []byte("actor:1"), mock.MatchedBy(func(payload []byte) bool {
+					return bytes.Contains(payload, []byte(`"type":"actor_updated"`))
+				}))
+			} else {
+				producer.AssertNotCalled(t, "Produce", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			}
+			mockCtrl.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAdminHandler_MergeMovies(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		setupMock      func(*MockMovieController)
+		expectEvent    bool
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "success",
+			body: `{"keep_id":1,"duplicate_id":2}`,
+			setupMock: func(m *MockMovieController) {
+				m.On("MergeMovies", mock.Anything, dto.MergeMoviesRequest{KeepID: 1, DuplicateID: 2}).Return(dto.MovieMergeResponse{
+					Movie:            dto.MovieResponse{ID: 1, Title: "Test Movie", Description: "Test Description", ReleaseYear: 2023, Rating: 8.5},
+					DuplicateID:      2,
+					ActorsReassigned: 3,
+				}, nil)
+			},
+			expectEvent:    true,
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"movie":{"id":1,"title":"Test Movie","description":"Test Description","release_year":2023,"rating":8.5,"view_count":0},"duplicate_id":2,"actors_reassigned":3}`,
+		},
+		{
+			name:           "missing duplicate id",
+			body:           `{"keep_id":1}`,
+			setupMock:      func(m *MockMovieController) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: `{"type":"about:blank","title":"Bad Request","status":400,"code":"validation_failed",` +
+				`"detail":"validation error: duplicate_id: is required","errors":[` +
+				`{"field":"duplicate_id","key":"request.field.required","message":"is required"}]}`,
+		},
+		{
+			name: "movie not found",
+			body: `{"keep_id":1,"duplicate_id":999}`,
+			setupMock: func(m *MockMovieController) {
+				m.On("MergeMovies", mock.Anything, dto.MergeMoviesRequest{KeepID: 1, DuplicateID: 999}).Return(dto.MovieMergeResponse{}, domain.ErrMovieNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   problem(http.StatusNotFound, "movie_not_found", "movie not found"),
+		},
+		{
+			name: "same movie",
+			body: `{"keep_id":1,"duplicate_id":1}`,
+			setupMock: func(m *MockMovieController) {
+				m.On("MergeMovies", mock.Anything, dto.MergeMoviesRequest{KeepID: 1, DuplicateID: 1}).Return(dto.MovieMergeResponse{}, domain.ErrMergeSameMovie)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   problem(http.StatusBadRequest, "merge_same_movie", "cannot merge movie with itself"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			r := gin.New()
+			r.Use(apperror.Middleware())
+			mockCtrl := new(MockMovieController)
+			tt.setupMock(mockCtrl)
+
+			producer := kafka.NewMockProducer()
+			producer.On("Produce", mock.Anything, "movie-merges", []byte("1"), mock.Anything).Return(nil)
+			producer.On("Produce", mock.Anything, CatalogChangesTopic, mock.Anything, mock.Anything).Return(nil)
+			producer.On("Close").Return(nil)
+			producerPool := kafka.NewProducerPool(producer, 1, 10)
+
+			handler := NewAdminHandler(new(MockActorController), mockCtrl, nil, kafkaBus(producerPool))
+			r.POST("/admin/movies/merge", handler.MergeMovies)
+
+			req, _ := http.NewRequest(http.MethodPost, "/admin/movies/merge", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			producerPool.Close()
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedBody != "" {
+				assert.JSONEq(t, tt.expectedBody, w.Body.String())
+			}
+			if tt.expectEvent {
+				producer.AssertCalled(t, "Produce", mock.Anything, "movie-merges", []byte("1"), mock.Anything)
+				producer.AssertCalled(t, "Produce", mock.Anything, CatalogChangesTopic, []byte("movie:2"), mock.MatchedBy(func(payload []byte) bool {
+					return bytes.Contains(payload, []byte(`"type":"movie_deleted"`))
+				}))
+				producer.AssertCalled(t, "Produce", mock.Anything, CatalogChangesTopic, []byte("movie:1"), mock.MatchedBy(func(payload []byte) bool {
+					return bytes.Contains(payload, []byte(`"type":"movie_updated"`))
+				}))
+			} else {
+				producer.AssertNotCalled(t, "Produce", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			}
+			mockCtrl.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAdminHandler_BulkMovies(t *testing.T) {
+	bulkResp := dto.BulkOperationResponse{
+		Succeeded: 1,
+		Failed:    1,
+		Results: []dto.BulkItemResult{
+			{ID: 1, Status: "ok"},
+			{ID: 2, Status: "failed", Code: "movie_not_found", Error: "movie not found"},
+		},
+	}
+	bulkBody := `{"succeeded":1,"failed":1,"results":[{"id":1,"status":"ok"},{"id":2,"status":"failed","code":"movie_not_found","error":"movie not found"}]}`
+
+	tests := []struct {
+		name           string
+		path           string
+		body           string
+		setupMock      func(*MockMovieController)
+		expectedEvent  string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "bulk delete",
+			path: "/admin/movies/bulk-delete",
+			body: `{"ids":[1,2]}`,
+			setupMock: func(m *MockMovieController) {
+				m.On("BulkDeleteMovies", mock.Anything, dto.BulkDeleteMoviesRequest{IDs: []int{1, 2}}).Return(bulkResp, nil)
+			},
+			expectedEvent:  "movie_deleted",
+			expectedStatus: http.StatusOK,
+			expectedBody:   bulkBody,
+		},
+		{
+			name: "bulk update",
+			path: "/admin/movies/bulk-update",
+			body: `{"ids":[1,2],"changes":{"rating":7}}`,
+			setupMock: func(m *MockMovieController) {
+				rating := 7.0
+				m.On("BulkUpdateMovies", mock.Anything, dto.BulkUpdateMoviesRequest{IDs: []int{1, 2}, Changes: dto.BulkMovieChanges{Rating: &rating}}).Return(bulkResp, nil)
+			},
+			expectedEvent:  "movie_updated",
+			expectedStatus: http.StatusOK,
+			expectedBody:   bulkBody,
+		},
+		{
+			name:           "malformed body",
+			path:           "/admin/movies/bulk-delete",
+			body:           `{"ids":"1,2"}`,
+			setupMock:      func(m *MockMovieController) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "validation error",
+			path: "/admin/movies/bulk-update",
+			body: `{"ids":[]}`,
+			setupMock: func(m *MockMovieController) {
+				m.On("BulkUpdateMovies", mock.Anything, dto.BulkUpdateMoviesRequest{IDs: []int{}}).
+					Return(dto.BulkOperationResponse{}, dto.ValidationErrors{dto.NewFieldError(dto.KeyBulkIDsRequired)})
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			r := gin.New()
+			r.Use(apperror.Middleware())
+			mockCtrl := new(MockMovieController)
+			tt.setupMock(mockCtrl)
+
+			producer := kafka.NewMockProducer()
+			producer.On("Produce", mock.Anything, CatalogChangesTopic, mock.Anything, mock.Anything).Return(nil)
+			producer.On("Close").Return(nil)
+			producerPool := kafka.NewProducerPool(producer, 1, 10)
+
+			handler := NewAdminHandler(new(MockActorController), mockCtrl, nil, kafkaBus(producerPool))
+			r.POST("/admin/movies/bulk-delete", handler.BulkDeleteMovies)
+			r.POST("/admin/movies/bulk-update", handler.BulkUpdateMovies)
+
+			req, _ := http.NewRequest(http.MethodPost, tt.path, bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			producerPool.Close()
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedBody != "" {
+				assert.JSONEq(t, tt.expectedBody, w.Body.String())
+			}
+			if tt.expectedEvent != "" {
+				// Событие отправляется только для фильма, операция над которым прошла успешно
+				producer.AssertNumberOfCalls(t, "Produce", 1)
+				producer.AssertCalled(t, "Produce", mock.Anything, CatalogChangesTopic, []byte("movie:1"), mock.MatchedBy(func(payload []byte) bool {
+					return bytes.Contains(payload, []byte(`"type":"`+tt.expectedEvent+`"`))
+				}))
+			} else {
+				producer.AssertNotCalled(t, "Produce", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			}
+			mockCtrl.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAdminHandler_PurgeOrphanActors(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		setupMock      func(*MockActorController)
+		expectedEvents int
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "purge without body",
+			setupMock: func(m *MockActorController) {
+				m.On("PurgeOrphanActors", mock.Anything, dto.PurgeOrphanActorsRequest{}).
+					Return(dto.PurgeOrphanActorsResponse{Deleted: 2, IDs: []int{4, 9}}, nil)
+			},
+			expectedEvents: 2,
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"deleted":2,"ids":[4,9]}`,
+		},
+		{
+			name: "confirmation required",
+			body: `{"confirm_count":3}`,
+			setupMock: func(m *MockActorController) {
+				m.On("PurgeOrphanActors", mock.Anything, dto.PurgeOrphanActorsRequest{ConfirmCount: 3}).
+					Return(dto.PurgeOrphanActorsResponse{}, &domain.OrphanPurgeError{Count: 25})
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody: `{"type":"about:blank","title":"Conflict","status":409,"code":"orphan_purge_unconfirmed",` +
+				`"detail":"too many actors to purge without confirmation: 25 actors","orphan_count":25}`,
+		},
+		{
+			name:           "malformed body",
+			body:           `{"confirm_count":"all"}`,
+			setupMock:      func(m *MockActorController) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			r := gin.New()
+			r.Use(apperror.Middleware())
+			mockCtrl := new(MockActorController)
+			tt.setupMock(mockCtrl)
+
+			producer := kafka.NewMockProducer()
+			producer.On("Produce", mock.Anything, CatalogChangesTopic, mock.Anything, mock.Anything).Return(nil)
+			producer.On("Close").Return(nil)
+			producerPool := kafka.NewProducerPool(producer, 1, 10)
+
+			handler := NewAdminHandler(mockCtrl, new(MockMovieController), nil, kafkaBus(producerPool))
+			r.POST("/admin/actors/orphans/purge", handler.PurgeOrphanActors)
+
+			req, _ := http.NewRequest(http.MethodPost, "/admin/actors/orphans/purge", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			producerPool.Close()
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedBody != "" {
+				assert.JSONEq(t, tt.expectedBody, w.Body.String())
+			}
+			producer.AssertNumberOfCalls(t, "Produce", tt.expectedEvents)
+			if tt.expectedEvents > 0 {
+				producer.AssertCalled(t, "Produce", mock.Anything, CatalogChangesTopic, []byte("actor:9"), mock.Anything)
+			}
+			mockCtrl.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAdminHandler_ImportActors(t *testing.T) {
+	const csvBody = "name,gender,birth_date\nKeanu Reeves,male,1964-09-02\n"
+	created := dto.ActorImportResponse{Total: 1, Valid: 1, Created: 1, Rows: []dto.ActorImportRow{
+		{Row: 2, Name: "Keanu Reeves", Status: "created", ID: 7},
+	}}
+	rejected := dto.ActorImportResponse{Total: 1, Invalid: 1, Rows: []dto.ActorImportRow{
+		{Row: 2, Name: "Keanu Reeves", Status: "invalid", Errors: []dto.FieldError{dto.NewFieldError(dto.KeyActorImportExists)}},
+	}}
+	tests := []struct {
+		name           string
+		url            string
+		setupMock      func(*MockActorController)
+		expectedEvents int
+		expectedStatus int
+	}{
+		{
+			name: "dry run",
+			url:  "/admin/import/actors?dry_run=true",
+			setupMock: func(m *MockActorController) {
+				m.On("ImportActorsCSV", mock.Anything, csvBody, true).
+					Return(dto.ActorImportResponse{DryRun: true, Total: 1, Valid: 1}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "committed",
+			url:  "/admin/import/actors",
+			setupMock: func(m *MockActorController) {
+				m.On("ImportActorsCSV", mock.Anything, csvBody, false).Return(created, nil)
+			},
+			expectedEvents: 1,
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name: "rejected rows",
+			url:  "/admin/import/actors",
+			setupMock: func(m *MockActorController) {
+				m.On("ImportActorsCSV", mock.Anything, csvBody, false).Return(rejected, nil)
+			},
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:           "invalid dry_run",
+			url:            "/admin/import/actors?dry_run=maybe",
+			setupMock:      func(m *MockActorController) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			r := gin.New()
+			r.Use(apperror.Middleware())
+			mockCtrl := new(MockActorController)
+			tt.setupMock(mockCtrl)
+
+			producer := kafka.NewMockProducer()
+			producer.On("Produce", mock.Anything, CatalogChangesTopic, mock.Anything, mock.Anything).Return(nil)
+			producer.On("Close").Return(nil)
+			producerPool := kafka.NewProducerPool(producer, 1, 10)
+
+			handler := NewAdminHandler(mockCtrl, new(MockMovieController), nil, kafkaBus(producerPool))
+			r.POST("/admin/import/actors", handler.ImportActors)
+
+			req, _ := http.NewRequest(http.MethodPost, tt.url, bytes.NewBufferString(csvBody))
+			req.Header.Set("Content-Type", "text/csv")
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			producerPool.Close()
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			producer.AssertNumberOfCalls(t, "Produce", tt.expectedEvents)
+			if tt.expectedEvents > 0 {
+				producer.AssertCalled(t, "Produce", mock.Anything, CatalogChangesTopic, []byte("actor:7"), mock.Anything)
+			}
+			mockCtrl.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAdminHandler_ImportExternalMovie(t *testing.T) {
+	tests := []struct {
+		name           string
+		url            string
+		setupMock      func(*MockMovieController)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "success",
+			url:  "/admin/movies/import-external?imdb_id=tt0133093",
+			setupMock: func(m *MockMovieController) {
+				m.On("ImportExternalMovie", mock.Anything, "tt0133093").Return(dto.MovieImportResponse{
+					Movie:         dto.MovieResponse{ID: 5, Title: "The Matrix", ReleaseYear: 1999, Rating: 8.2},
+					ActorsCreated: 2,
+				}, nil)
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   `{"movie":{"id":5,"title":"The Matrix","description":"","release_year":1999,"rating":8.2,"view_count":0},"actors_created":2}`,
+		},
+		{
+			name:           "missing imdb id",
+			url:            "/admin/movies/import-external",
+			setupMock:      func(m *MockMovieController) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   problem(http.StatusBadRequest, "field_required", "imdb_id is required"),
+		},
+		{
+			name: "duplicate movie",
+			url:  "/admin/movies/import-external?imdb_id=tt0133093",
+			setupMock: func(m *MockMovieController) {
+				m.On("ImportExternalMovie", mock.Anything, "tt0133093").Return(dto.MovieImportResponse{}, &domain.DuplicateMovieError{ExistingID: 3})
+			},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name: "import not configured",
+			url:  "/admin/movies/import-external?imdb_id=tt0133093",
+			setupMock: func(m *MockMovieController) {
+				m.On("ImportExternalMovie", mock.Anything, "tt0133093").Return(dto.MovieImportResponse{}, domain.ErrExternalImportOff)
+			},
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedBody:   problem(http.StatusServiceUnavailable, "external_import_disabled", "external movie import is not configured"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			r := gin.New()
+			r.Use(apperror.Middleware())
+			mockCtrl := new(MockMovieController)
+			tt.setupMock(mockCtrl)
+
+			handler := NewAdminHandler(new(MockActorController), mockCtrl, nil, nil)
+			r.POST("/admin/movies/import-external", handler.ImportExternalMovie)
+
+			req, _ := http.NewRequest(http.MethodPost, tt.url, nil)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedBody != "" {
+				assert.JSONEq(t, tt.expectedBody, w.Body.String())
+			}
+			mockCtrl.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAdminHandler_WaitJob(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	manager := jobs.NewManager(time.Hour)
+	defer manager.Close()
+
+	release := make(chan struct{})
+	running := manager.Start("import", func(ctx context.Context) (interface{}, error) {
+		<-release
+		return gin.H{"imported": 2}, nil
+	})
+	finished := manager.Start("export", func(ctx context.Context) (interface{}, error) {
+		return nil, nil
+	})
+	_, _ = manager.Wait(context.Background(), finished.ID)
+
+	handler := NewAdminHandler(nil, nil, manager, nil)
+	r := gin.New()
+	r.Use(apperror.Middleware())
+	r.GET("/admin/jobs/:id", handler.GetJob)
+	r.GET("/admin/jobs/:id/wait", handler.WaitJob)
+
+	tests := []struct {
+		name           string
+		url            string
+		expectedStatus int
+		expectedJob    jobs.Status
+	}{
+		{name: "finished job returns immediately", url: "/admin/jobs/" + finished.ID + "/wait", expectedStatus: http.StatusOK, expectedJob: jobs.StatusSucceeded},
+		{name: "timeout returns running job", url: "/admin/jobs/" + running.ID + "/wait?timeout=10ms", expectedStatus: http.StatusOK, expectedJob: jobs.StatusRunning},
+		{name: "timeout in seconds", url: "/admin/jobs/" + finished.ID + "/wait?timeout=1", expectedStatus: http.StatusOK, expectedJob: jobs.StatusSucceeded},
+		{name: "invalid timeout", url: "/admin/jobs/" + running.ID + "/wait?timeout=abc", expectedStatus: http.StatusBadRequest},
+		{name: "unknown job", url: "/admin/jobs/missing/wait", expectedStatus: http.StatusNotFound},
+		{name: "status endpoint", url: "/admin/jobs/" + running.ID, expectedStatus: http.StatusOK, expectedJob: jobs.StatusRunning},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest(http.MethodGet, tt.url, nil)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedJob != "" {
+				var job jobs.Job
+				assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &job))
+				assert.Equal(t, tt.expectedJob, job.Status)
+			}
+		})
+	}
+
+	// Ожидание завершается сразу после окончания задачи, не дожидаясь таймаута
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		close(release)
+	}()
+	startedAt := time.Now()
+	req, _ := http.NewRequest(http.MethodGet, "/admin/jobs/"+running.ID+"/wait?timeout=5s", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Less(t, time.Since(startedAt), 2*time.Second)
+	assert.Contains(t, w.Body.String(), `"status":"succeeded"`)
+}
@@ -1,12 +1,20 @@
 package handlers
 
 import (
-	"encoding/json"
+	"context"
+	"errors"
+	"log"
+	"math"
 	"net/http"
+	"strconv"
 	"time"
 
+	"cinematique/internal/apperror"
 	"cinematique/internal/controller/dto"
-	"cinematique/internal/kafka"
+	"cinematique/internal/domain"
+	"cinematique/internal/eventbus"
+	"cinematique/internal/idempotency"
+	"cinematique/internal/kafka/events"
 
 	"github.com/gin-gonic/gin"
 	"github.com/prometheus/client_golang/prometheus"
@@ -19,81 +27,127 @@ var (
 			Help: "Total number of user logins.",
 		},
 	)
+	userLockoutsTotal = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "user_lockouts_total",
+			Help: "Total number of accounts locked after repeated failed logins.",
+		},
+	)
 )
 
 func init() {
 	prometheus.MustRegister(userLoginsTotal)
+	prometheus.MustRegister(userLockoutsTotal)
+}
+
+// Топики Kafka с событиями учётных записей
+const (
+	SecurityEventsTopic   = "security-events"   // блокировки учётных записей
+	UserRegistrationTopic = "user-registration" // user_registered
+	UserEventsTopic       = "user_events"       // user_logged_in
+)
+
+// Ответы на отказ в регистрации и входе, когда сервис вернул ошибку без типа. Текст у них
+// постоянный, а исходная ошибка пишется в лог
+var (
+	errRegistrationFailed = apperror.Validation("registration_failed", "registration failed")
+	errLoginFailed        = apperror.Unauthorized("invalid_credentials", "invalid username or password")
+)
+
+// NewLockoutPublisher возвращает функцию, которая публикует событие account_locked в шину событий.
+// Передаётся в service.AuthService.WithLockout
+func NewLockoutPublisher(bus *eventbus.Bus) func(user domain.User, until time.Time) {
+	return func(user domain.User, until time.Time) {
+		userLockoutsTotal.Inc()
+		event := events.NewAccountLocked(user.ID, user.Username, until)
+		if err := publishEvent(context.Background(), bus, SecurityEventsTopic, []byte(user.Username), event); err != nil {
+			// Блокировка уже сохранена в БД, поэтому только логируем ошибку
+			log.Printf("Failed to send account lockout event (user: %d): %v", user.ID, err)
+		}
+	}
 }
 
 // AuthHandler отвечает за обработку запросов, связанных с аутентификацией.
 type AuthHandler struct {
 	service AuthService
-	producerPool *kafka.ProducerPool // Используем пул продюсеров
+	bus *eventbus.Bus // шина доменных событий; nil — события не публикуются
+	idempotency *idempotency.Guard // ключи идемпотентности для регистрации; nil — выключены
 }
 
 // NewAuthHandler создаёт новый обработчик аутентификации.
-func NewAuthHandler(service AuthService, producerPool *kafka.ProducerPool) *AuthHandler {
-	return &AuthHandler{service: service, producerPool: producerPool}
+func NewAuthHandler(service AuthService, bus *eventbus.Bus) *AuthHandler {
+	return &AuthHandler{service: service, bus: bus}
+}
+
+// WithIdempotency включает заголовок Idempotency-Key для регистрации
+func (h *AuthHandler) WithIdempotency(guard *idempotency.Guard) *AuthHandler {
+	h.idempotency = guard
+	return h
 }
 
 // Register обрабатывает регистрацию пользователя
 func (h *AuthHandler) Register(c *gin.Context) {
 	var req dto.RegisterRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "неверный запрос"})
+	if err := bindJSON(c, &req); err != nil {
+		respondError(c, err)
 		return
 	}
-	_, err := h.service.Register(req.Username, req.Email, req.Password, req.Role)
+	userID, err := h.service.Register(req.Username, req.Email, req.Password, req.Role)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		// Текст ошибки сервиса не отдаётся клиенту: в нём могут быть детали хранилища
+		log.Printf("Registration failed (username: %s): %v", req.Username, err)
+		respondError(c, typedOr(err, errRegistrationFailed))
 		return
 	}
 
-	// Отправляем событие регистрации в Kafka
-	event := map[string]interface{}{
-		"type":      "user_registered",
-		"username":  req.Username,
-		"timestamp": time.Now().Format(time.RFC3339),
-	}
-	eventBytes, _ := json.Marshal(event)
-	if err := h.producerPool.Produce("user-registration", []byte(req.Username), eventBytes); err != nil {
-		// Логируем ошибку, но не блокируем регистрацию пользователя
-		// В реальном приложении здесь может быть более сложная логика обработки ошибок
-		// например, отправка в Dead Letter Queue или повторная попытка
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to send registration event"})
-		return
+	// Пользователь уже создан, поэтому ошибка отправки события только логируется
+	event := events.NewUserRegistered(userActivity(c, userID))
+	if err := publishEvent(c.Request.Context(), h.bus, UserRegistrationTopic, []byte(strconv.Itoa(userID)), event); err != nil {
+		log.Printf("Failed to send registration event (user: %d): %v", userID, err)
 	}
 
 	c.Status(http.StatusCreated)
 }
 
+// userActivity собирает поля события учётной записи из запроса
+func userActivity(c *gin.Context, userID int) events.UserActivity {
+	return events.UserActivity{UserID: userID, IP: c.ClientIP(), UserAgent: c.Request.UserAgent()}
+}
+
+// typedOr возвращает err, если её тип известен apperror (например, нарушение парольной
+// политики или блокировка), а иначе fallback: сервис аутентификации отдаёт и простые ошибки
+func typedOr(err error, fallback *apperror.Error) error {
+	if apperror.KindOf(err) != apperror.KindInternal {
+		return err
+	}
+	return fallback
+}
+
 // Login обрабатывает вход пользователя и возвращает JWT токены
 func (h *AuthHandler) Login(c *gin.Context) {
 	var req dto.LoginRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "неверный запрос"})
+	if err := bindJSON(c, &req); err != nil {
+		respondError(c, err)
 		return
 	}
 
 	tokenPair, err := h.service.Login(req.Username, req.Password)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		var lockedErr *domain.AccountLockedError
+		if errors.As(err, &lockedErr) {
+			retryAfter := math.Ceil(time.Until(lockedErr.Until).Seconds())
+			c.Header("Retry-After", strconv.Itoa(int(math.Max(retryAfter, 1))))
+		}
+		// Причина отказа не раскрывается, чтобы по ответу нельзя было подобрать имя пользователя
+		log.Printf("Login failed (username: %s): %v", req.Username, err)
+		respondError(c, typedOr(err, errLoginFailed))
 		return
 	}
 
-	// Отправляем событие входа в систему в Kafka
-	event := map[string]interface{}{
-		"type":      "user_logged_in",
-		"username":  req.Username,
-		"timestamp": time.Now().Format(time.RFC3339),
-	}
-	eventBytes, _ := json.Marshal(event)
-	if err := h.producerPool.Produce("user_events", []byte(req.Username), eventBytes); err != nil {
-		// Логируем ошибку, но не блокируем вход пользователя
-		// В реальном приложении здесь может быть более сложная логика обработки ошибок
-		// например, отправка в Dead Letter Queue или повторная попытка
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to send login event"})
-		return
+	// Токены уже выданы, поэтому ошибка отправки события только логируется
+	event := events.NewUserLoggedIn(userActivity(c, tokenPair.UserID))
+	if err := publishEvent(c.Request.Context(), h.bus, UserEventsTopic, []byte(strconv.Itoa(tokenPair.UserID)), event); err != nil {
+		log.Printf("Failed to send login event (user: %d): %v", tokenPair.UserID, err)
 	}
 
 	// Увеличиваем счётчик входов в систему
@@ -110,14 +164,14 @@ func (h *AuthHandler) Login(c *gin.Context) {
 // Refresh обрабатывает запрос на обновление токена
 func (h *AuthHandler) Refresh(c *gin.Context) {
 	var req dto.RefreshRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "неверный запрос"})
+	if err := bindJSON(c, &req); err != nil {
+		respondError(c, err)
 		return
 	}
 
 	tokenPair, err := h.service.RefreshToken(req.RefreshToken)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid refresh token"})
+		respondError(c, apperror.Unauthorized("invalid_refresh_token", "invalid refresh token"))
 		return
 	}
 
@@ -131,13 +185,13 @@ func (h *AuthHandler) Refresh(c *gin.Context) {
 // Logout обрабатывает выход пользователя из системы
 func (h *AuthHandler) Logout(c *gin.Context) {
 	var req dto.RefreshRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request"})
+	if err := bindJSON(c, &req); err != nil {
+		respondError(c, err)
 		return
 	}
 
 	if err := h.service.Logout(req.RefreshToken); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to logout"})
+		respondError(c, apperror.New(apperror.KindInternal, "logout_failed", "failed to logout"))
 		return
 	}
 
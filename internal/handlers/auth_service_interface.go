@@ -1,6 +1,11 @@
 package handlers
 
-import "cinematique/internal/auth"
+import (
+	"context"
+
+	"cinematique/internal/auth"
+	"cinematique/internal/domain"
+)
 
 // AuthService Определяет интерфейс для операций аутентификации
 type AuthService interface {
@@ -12,4 +17,16 @@ type AuthService interface {
 	RefreshToken(refreshToken string) (*auth.TokenPair, error)
 	// Logout выполняет выход пользователя из системы
 	Logout(refreshToken string) error
+	// GetProfile возвращает учётную запись пользователя
+	GetProfile(userID int) (domain.User, error)
+	// UpdateProfile меняет email и (или) пароль после проверки текущего пароля
+	UpdateProfile(ctx context.Context, userID int, currentPassword string, email, newPassword *string) (domain.User, error)
+	// DeleteAccount удаляет учётную запись после проверки пароля и отзывает её токены
+	DeleteAccount(ctx context.Context, userID int, currentPassword string) error
+	// ForgotPassword отправляет ссылку сброса пароля, если адрес зарегистрирован
+	ForgotPassword(ctx context.Context, email string) error
+	// ResetPassword задаёт новый пароль по одноразовому токену из письма
+	ResetPassword(ctx context.Context, token, newPassword string) error
+	// ChangeRole назначает пользователю роль; токены доступа со старой ролью перестают приниматься
+	ChangeRole(ctx context.Context, userID int, role string) (domain.User, error)
 }
@@ -2,17 +2,23 @@ package handlers
 
 import (
 	"bytes"
+	"cinematique/internal/apperror"
 	"cinematique/internal/auth"
+	"cinematique/internal/controller/dto"
+	"cinematique/internal/domain"
 	"cinematique/internal/kafka"
+	"context"
 	"encoding/json"
 	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 // MockAuthService is a mock implementation of the AuthService interface
@@ -49,22 +55,61 @@ func (m *MockAuthService) Logout(refreshToken string) error {
 	return args.Error(0)
 }
 
+func (m *MockAuthService) GetProfile(userID int) (domain.User, error) {
+	args := m.Called(userID)
+	return args.Get(0).(domain.User), args.Error(1)
+}
+
+func (m *MockAuthService) UpdateProfile(_ context.Context, userID int, currentPassword string, email, newPassword *string) (domain.User, error) {
+	args := m.Called(userID, currentPassword, email, newPassword)
+	return args.Get(0).(domain.User), args.Error(1)
+}
+
+func (m *MockAuthService) DeleteAccount(_ context.Context, userID int, currentPassword string) error {
+	args := m.Called(userID, currentPassword)
+	return args.Error(0)
+}
+
+func (m *MockAuthService) ForgotPassword(_ context.Context, email string) error {
+	args := m.Called(email)
+	return args.Error(0)
+}
+
+func (m *MockAuthService) ResetPassword(_ context.Context, token, newPassword string) error {
+	args := m.Called(token, newPassword)
+	return args.Error(0)
+}
+
+func (m *MockAuthService) ChangeRole(_ context.Context, userID int, role string) (domain.User, error) {
+	args := m.Called(userID, role)
+	return args.Get(0).(domain.User), args.Error(1)
+}
+
 // Define error variables for testing
 var (
 	errUserAlreadyExists  = errors.New("user already exists")
 	errInvalidCredentials = errors.New("invalid credentials")
 )
 
+// requiredFieldProblem возвращает ожидаемое тело ответа 400 для незаполненного обязательного поля
+func requiredFieldProblem(field string) string {
+	fieldErr := dto.NewFieldError(dto.KeyRequestFieldRequired)
+	fieldErr.Field = field
+	fieldErrs := dto.ValidationErrors{fieldErr}
+	return string(problemJSON(http.StatusBadRequest, dto.CodeValidationFailed, "validation error: "+fieldErrs.Error(), fieldErrs))
+}
+
 func setupRouter() (*gin.Engine, *MockAuthService, *kafka.MockProducer, *AuthHandler) {
 	gin.SetMode(gin.TestMode)
 	r := gin.New()
+	r.Use(apperror.Middleware())
 	mockService := new(MockAuthService)
 	mockProducer := kafka.NewMockProducer()
 
 	// Create a producer pool with the mock producer
 	producerPool := kafka.NewProducerPool(mockProducer, 1, 10)
 	// Create a handler with the mock service and producer pool
-	handler := NewAuthHandler(mockService, producerPool)
+	handler := NewAuthHandler(mockService, kafkaBus(producerPool))
 
 	mockProducer.On("Produce", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe().Return(nil)
 
@@ -106,7 +151,7 @@ func TestAuthHandler_Register(t *testing.T) {
 				p.On("Produce", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe().Return(nil)
 			},
 			expectedStatus: http.StatusBadRequest,
-			expectedBody:   `{"error":"неверный запрос"}`,
+			expectedBody:   requiredFieldProblem("username"),
 		},
 		{
 			name: "registration error",
@@ -122,7 +167,21 @@ func TestAuthHandler_Register(t *testing.T) {
 				p.On("Produce", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe().Return(nil)
 			},
 			expectedStatus: http.StatusBadRequest,
-			expectedBody:   `{"error":"user already exists"}`,
+			expectedBody:   problem(http.StatusBadRequest, "registration_failed", "registration failed"),
+		},
+		{
+			name: "username taken",
+			requestBody: map[string]string{
+				"username": "testuser",
+				"email":    "test@example.com",
+				"password": "password123",
+				"role":     "user",
+			},
+			setupMock: func(m *MockAuthService, p *kafka.MockProducer) {
+				m.On("Register", "testuser", "test@example.com", "password123", "user").Return(0, domain.ErrUserAlreadyExists)
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody:   problem(http.StatusConflict, "user_already_exists", "username or email is already taken"),
 		},
 		{
 			name: "missing password",
@@ -136,7 +195,7 @@ func TestAuthHandler_Register(t *testing.T) {
 				p.On("Produce", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe().Return(nil)
 			},
 			expectedStatus: http.StatusBadRequest,
-			expectedBody:   `{"error":"неверный запрос"}`,
+			expectedBody:   requiredFieldProblem("password"),
 		},
 		{
 			name: "produce error",
@@ -180,6 +239,47 @@ func TestAuthHandler_Register(t *testing.T) {
 	}
 }
 
+func TestAuthHandler_UserEvents(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(apperror.Middleware())
+	mockService := new(MockAuthService)
+	mockService.On("Register", "testuser", "test@example.com", "password123", "user").Return(7, nil)
+	mockService.On("Login", "testuser", "password123").Return(&auth.TokenPair{AccessToken: "a", RefreshToken: "r", ExpiresIn: 3600, UserID: 7}, nil)
+	mockProducer := kafka.NewMockProducer()
+	mockProducer.On("Produce", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mockProducer.On("Close").Return(nil)
+	producerPool := kafka.NewProducerPool(mockProducer, 1, 10)
+	handler := NewAuthHandler(mockService, kafkaBus(producerPool))
+	r.POST("/register", handler.Register)
+	r.POST("/login", handler.Login)
+
+	for path, body := range map[string]string{
+		"/register": `{"username":"testuser","email":"test@example.com","password":"password123","role":"user"}`,
+		"/login":    `{"username":"testuser","password":"password123"}`,
+	} {
+		req, _ := http.NewRequest(http.MethodPost, path, bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("User-Agent", "curl/8.0")
+		req.RemoteAddr = "10.0.0.1:40000"
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		require.Less(t, w.Code, 300, path)
+	}
+	producerPool.Close()
+
+	// События ссылаются на пользователя по ID и не содержат имени, email или пароля
+	for topic, eventType := range map[string]string{UserRegistrationTopic: "user_registered", UserEventsTopic: "user_logged_in"} {
+		mockProducer.AssertCalled(t, "Produce", mock.Anything, topic, []byte("7"), mock.MatchedBy(func(payload []byte) bool {
+			var fields map[string]interface{}
+			require.NoError(t, json.Unmarshal(payload, &fields))
+			return fields["type"] == eventType && fields["user_id"] == float64(7) &&
+				fields["ip"] == "10.0.0.1" && fields["user_agent"] == "curl/8.0" &&
+				!bytes.Contains(payload, []byte("testuser")) && !bytes.Contains(payload, []byte("password123"))
+		}))
+	}
+}
+
 func TestAuthHandler_Login(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -199,6 +299,7 @@ func TestAuthHandler_Login(t *testing.T) {
 					AccessToken:  "test_access_token",
 					RefreshToken: "test_refresh_token",
 					ExpiresIn:    3600,
+					UserID:       1,
 				}, nil)
 				p.On("Produce", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe().Return(nil)
 			},
@@ -216,7 +317,7 @@ func TestAuthHandler_Login(t *testing.T) {
 				p.On("Produce", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe().Return(nil)
 			},
 			expectedStatus: http.StatusBadRequest,
-			expectedBody:   `{"error":"неверный запрос"}`,
+			expectedBody:   requiredFieldProblem("password"),
 		},
 		{
 			name: "service error",
@@ -230,7 +331,7 @@ func TestAuthHandler_Login(t *testing.T) {
 				p.On("Produce", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe().Return(nil)
 			},
 			expectedStatus: http.StatusUnauthorized,
-			expectedBody:   `{"error":"internal server error"}`,
+			expectedBody:   problem(http.StatusUnauthorized, "invalid_credentials", "invalid username or password"),
 		},
 		{
 			name: "invalid credentials",
@@ -244,7 +345,21 @@ func TestAuthHandler_Login(t *testing.T) {
 				p.On("Produce", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe().Return(nil)
 			},
 			expectedStatus: http.StatusUnauthorized,
-			expectedBody:   `{"error":"invalid credentials"}`,
+			expectedBody:   problem(http.StatusUnauthorized, "invalid_credentials", "invalid username or password"),
+		},
+		{
+			name: "account locked",
+			requestBody: map[string]string{
+				"username": "testuser",
+				"password": "password123",
+			},
+			setupMock: func(m *MockAuthService, p *kafka.MockProducer) {
+				lockedUntil := time.Date(2099, 1, 1, 0, 0, 0, 0, time.UTC)
+				m.On("Login", "testuser", "password123").Return((*auth.TokenPair)(nil), &domain.AccountLockedError{Until: lockedUntil})
+				p.On("Produce", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe().Return(nil)
+			},
+			expectedStatus: http.StatusForbidden,
+			expectedBody:   `{"type":"about:blank","title":"Forbidden","status":403,"code":"account_locked","detail":"account is temporarily locked until 2099-01-01T00:00:00Z","locked_until":"2099-01-01T00:00:00Z"}`,
 		},
 		{
 			name: "produce error",
@@ -257,6 +372,7 @@ func TestAuthHandler_Login(t *testing.T) {
 					AccessToken:  "test_access_token",
 					RefreshToken: "test_refresh_token",
 					ExpiresIn:    3600,
+					UserID:       1,
 				}, nil)
 				p.On("Produce", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe().Return(errors.New("kafka produce error"))
 			},
@@ -310,6 +426,7 @@ func TestAuthHandler_Refresh(t *testing.T) {
 					AccessToken:  "new_access_token",
 					RefreshToken: "new_refresh_token",
 					ExpiresIn:    3600,
+					UserID:       1,
 				}, nil)
 				p.On("Produce", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe().Return(nil)
 			},
@@ -326,7 +443,7 @@ func TestAuthHandler_Refresh(t *testing.T) {
 				p.On("Produce", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe().Return(nil)
 			},
 			expectedStatus: http.StatusUnauthorized,
-			expectedBody:   `{"error":"invalid refresh token"}`,
+			expectedBody:   problem(http.StatusUnauthorized, "invalid_refresh_token", "invalid refresh token"),
 		},
 		{
 			name:        "missing token",
@@ -335,7 +452,7 @@ func TestAuthHandler_Refresh(t *testing.T) {
 				// Продюсер не должен вызываться при невалидном запросе
 			},
 			expectedStatus: http.StatusBadRequest,
-			expectedBody:   `{"error":"неверный запрос"}`,
+			expectedBody:   requiredFieldProblem("refresh_token"),
 		},
 		{
 			name: "invalid request",
@@ -346,7 +463,7 @@ func TestAuthHandler_Refresh(t *testing.T) {
 				// Продюсер не должен вызываться при невалидном запросе
 			},
 			expectedStatus: http.StatusBadRequest,
-			expectedBody:   `{"error":"неверный запрос"}`,
+			expectedBody:   requiredFieldProblem("refresh_token"),
 		},
 	}
 
@@ -410,7 +527,7 @@ func TestAuthHandler_Logout(t *testing.T) {
 			},
 			expectedStatus: http.StatusInternalServerError,
 			expectBody:     true,
-			expectedBody:   `{"error":"failed to logout"}`,
+			expectedBody:   problem(http.StatusInternalServerError, "logout_failed", "failed to logout"),
 		},
 		{
 			name:        "missing token",
@@ -420,7 +537,7 @@ func TestAuthHandler_Logout(t *testing.T) {
 			},
 			expectedStatus: http.StatusBadRequest,
 			expectBody:     true,
-			expectedBody:   `{"error":"invalid request"}`,
+			expectedBody:   requiredFieldProblem("refresh_token"),
 		},
 		{
 			name: "invalid request",
@@ -432,7 +549,7 @@ func TestAuthHandler_Logout(t *testing.T) {
 			},
 			expectedStatus: http.StatusBadRequest,
 			expectBody:     true,
-			expectedBody:   `{"error":"invalid request"}`,
+			expectedBody:   requiredFieldProblem("refresh_token"),
 		},
 	}
 
@@ -461,3 +578,38 @@ func TestAuthHandler_Logout(t *testing.T) {
 		})
 	}
 }
+
+func TestAuthHandler_Login_LockedSetsRetryAfter(t *testing.T) {
+	r, mockService, _, handler := setupRouter()
+	mockService.On("Login", "testuser", "wrong").
+		Return((*auth.TokenPair)(nil), &domain.AccountLockedError{Until: time.Now().Add(90 * time.Second)})
+	r.POST("/login", handler.Login)
+
+	req, _ := http.NewRequest("POST", "/login", bytes.NewBufferString(`{"username":"testuser","password":"wrong"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Contains(t, []string{"89", "90"}, w.Header().Get("Retry-After"))
+}
+
+func TestNewLockoutPublisher(t *testing.T) {
+	producer := kafka.NewMockProducer()
+	produced := make(chan []byte, 1)
+	producer.On("Produce", mock.Anything, SecurityEventsTopic, []byte("neo"), mock.Anything).
+		Run(func(args mock.Arguments) { produced <- args.Get(3).([]byte) }).
+		Return(nil)
+	producer.On("Close").Return(nil)
+	producerPool := kafka.NewProducerPool(producer, 1, 10)
+
+	NewLockoutPublisher(kafkaBus(producerPool))(domain.User{ID: 7, Username: "neo"}, time.Date(2024, 5, 1, 12, 15, 0, 0, time.UTC))
+	producerPool.Close()
+
+	var event map[string]interface{}
+	require.NoError(t, json.Unmarshal(<-produced, &event))
+	assert.Equal(t, "account_locked", event["type"])
+	assert.Equal(t, float64(7), event["user_id"])
+	assert.Equal(t, "2024-05-01T12:15:00Z", event["locked_until"])
+	assert.NotEmpty(t, event["event_id"])
+}
@@ -0,0 +1,221 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"cinematique/internal/controller/dto"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// bindJSON разбирает JSON-тело запроса в obj и проверяет теги binding. Несовпадение типа
+// и нарушенные правила возвращаются как dto.ValidationErrors с путём поля в JSON, индексом
+// элемента массива и ожидаемым типом; синтаксически неверное тело — как errInvalidRequest
+func bindJSON(c *gin.Context, obj interface{}) error {
+	err := c.ShouldBindBodyWith(obj, binding.JSON)
+	if err == nil {
+		return nil
+	}
+	var body []byte
+	if cached, ok := c.Get(gin.BodyBytesKey); ok {
+		body, _ = cached.([]byte)
+	}
+
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) && typeErr.Field != "" {
+		segments := strings.Split(typeErr.Field, ".")
+		if !hasIndex(segments) {
+			if located, found := findInvalidValue(body, segments, typeErr.Type); found {
+				segments = located
+			}
+		}
+		fieldErr := dto.NewFieldError(dto.KeyRequestFieldType)
+		fieldErr.Expected = jsonTypeName(typeErr.Type)
+		fieldErr.Field, fieldErr.Index = jsonPath(segments)
+		return fmt.Errorf("validation error: %w", dto.ValidationErrors{fieldErr})
+	}
+
+	var validationErrs validator.ValidationErrors
+	if errors.As(err, &validationErrs) {
+		errs := make(dto.ValidationErrors, 0, len(validationErrs))
+		for _, ruleErr := range validationErrs {
+			errs = append(errs, ruleFieldError(reflect.TypeOf(obj), ruleErr))
+		}
+		return fmt.Errorf("validation error: %w", errs)
+	}
+	return errInvalidRequest
+}
+
+// ruleFieldError описывает нарушенное правило тега binding
+func ruleFieldError(root reflect.Type, ruleErr validator.FieldError) dto.FieldError {
+	var fieldErr dto.FieldError
+	switch ruleErr.Tag() {
+	case "required":
+		fieldErr = dto.NewFieldError(dto.KeyRequestFieldRequired)
+	case "min", "max", "len", "gt", "gte", "lt", "lte":
+		fieldErr = dto.NewFieldError(dto.KeyRequestFieldRange)
+	default:
+		fieldErr = dto.NewFieldError(dto.KeyRequestFieldInvalid)
+	}
+	if ruleErr.Tag() != "required" {
+		fieldErr.Expected = ruleErr.Tag()
+		if ruleErr.Param() != "" {
+			fieldErr.Expected += "=" + ruleErr.Param()
+		}
+	}
+	fieldErr.Field, fieldErr.Index = jsonPath(namespaceSegments(root, ruleErr.StructNamespace()))
+	return fieldErr
+}
+
+// namespaceSegments переводит путь валидатора (MovieWithActorsRequest.Cast[1].ActorID)
+// в сегменты пути по именам JSON с индексами массивов отдельными сегментами (cast, 1, actor_id)
+func namespaceSegments(root reflect.Type, namespace string) []string {
+	parts := strings.Split(namespace, ".")
+	if len(parts) > 0 {
+		parts = parts[1:] // имя корневой структуры
+	}
+	typ := root
+	segments := make([]string, 0, len(parts))
+	for _, part := range parts {
+		name, index, _ := strings.Cut(part, "[")
+		for typ != nil && (typ.Kind() == reflect.Ptr || typ.Kind() == reflect.Slice || typ.Kind() == reflect.Array) {
+			typ = typ.Elem()
+		}
+		var field reflect.StructField
+		found := false
+		if typ != nil && typ.Kind() == reflect.Struct {
+			field, found = typ.FieldByName(name)
+		}
+		if found {
+			segments = append(segments, jsonName(field))
+			typ = field.Type
+		} else {
+			segments = append(segments, name)
+			typ = nil
+		}
+		if index != "" {
+			segments = append(segments, strings.TrimSuffix(index, "]"))
+		}
+	}
+	return segments
+}
+
+// jsonPath собирает путь поля для ответа: индексы внутри пути записываются в скобках
+// (cast[1].actor_id), а индекс в конце пути возвращается отдельно — это элемент массива,
+// значение которого не подошло
+func jsonPath(segments []string) (string, *int) {
+	var path strings.Builder
+	for i, segment := range segments {
+		if index, err := strconv.Atoi(segment); err == nil {
+			if i == len(segments)-1 {
+				return path.String(), &index
+			}
+			path.WriteString("[" + segment + "]")
+			continue
+		}
+		if path.Len() > 0 {
+			path.WriteByte('.')
+		}
+		path.WriteString(segment)
+	}
+	return path.String(), nil
+}
+
+// hasIndex сообщает, есть ли в пути индексы массивов
+func hasIndex(segments []string) bool {
+	for _, segment := range segments {
+		if _, err := strconv.Atoi(segment); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// jsonName возвращает имя поля структуры в JSON с учётом тега json
+func jsonName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	if name == "" || name == "-" {
+		return field.Name
+	}
+	return name
+}
+
+// findInvalidValue ищет в теле значение по пути path, которое не разбирается в typ, и возвращает
+// полный путь к нему с индексами массивов. Версии encoding/json до Go 1.23 сообщают путь
+// без индексов, и элемент массива находится повторным разбором тела
+func findInvalidValue(raw []byte, path []string, typ reflect.Type) ([]string, bool) {
+	raw = bytes.TrimSpace(raw)
+	if len(raw) == 0 {
+		return nil, false
+	}
+	if raw[0] == '[' && (len(path) > 0 || typ.Kind() != reflect.Slice && typ.Kind() != reflect.Array) {
+		var elems []json.RawMessage
+		if err := json.Unmarshal(raw, &elems); err != nil {
+			return nil, false
+		}
+		for i, elem := range elems {
+			if rest, found := findInvalidValue(elem, path, typ); found {
+				return append([]string{strconv.Itoa(i)}, rest...), true
+			}
+		}
+		return nil, false
+	}
+	if len(path) == 0 {
+		return nil, json.Unmarshal(raw, reflect.New(typ).Interface()) != nil
+	}
+	if raw[0] != '{' {
+		return nil, false
+	}
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &object); err != nil {
+		return nil, false
+	}
+	value, ok := object[path[0]]
+	if !ok {
+		// encoding/json сопоставляет ключи без учёта регистра
+		for key, candidate := range object {
+			if strings.EqualFold(key, path[0]) {
+				value, ok = candidate, true
+				break
+			}
+		}
+	}
+	if !ok {
+		return nil, false
+	}
+	rest, found := findInvalidValue(value, path[1:], typ)
+	if !found {
+		return nil, false
+	}
+	return append([]string{path[0]}, rest...), true
+}
+
+// jsonTypeName называет тип JSON, в который разбирается значение типа typ
+func jsonTypeName(typ reflect.Type) string {
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	switch typ.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "integer"
+	case reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.String:
+		return "string"
+	case reflect.Slice, reflect.Array:
+		return "array"
+	default:
+		return "object"
+	}
+}
@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"cinematique/internal/apperror"
+	"cinematique/internal/controller/dto"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBindJSON(t *testing.T) {
+	tests := []struct {
+		name         string
+		body         string
+		target       func() interface{}
+		expectedBody string
+	}{
+		{
+			name:   "wrong element type in nested array",
+			body:   `{"cast":[{"actor_id":1},{"actor_id":"2"}]}`,
+			target: func() interface{} { return &dto.UpdateMovieActorsRequest{} },
+			expectedBody: `{"type":"about:blank","title":"Bad Request","status":400,"code":"validation_failed",` +
+				`"detail":"validation error: cast[1].actor_id: has the wrong type","errors":[` +
+				`{"field":"cast[1].actor_id","expected":"integer","key":"request.field.invalid_type","message":"has the wrong type"}]}`,
+		},
+		{
+			name:   "array expected",
+			body:   `{"actor_ids":"1,2"}`,
+			target: func() interface{} { return &dto.UpdateMovieActorsRequest{} },
+			expectedBody: `{"type":"about:blank","title":"Bad Request","status":400,"code":"validation_failed",` +
+				`"detail":"validation error: actor_ids: has the wrong type","errors":[` +
+				`{"field":"actor_ids","expected":"array","key":"request.field.invalid_type","message":"has the wrong type"}]}`,
+		},
+		{
+			name:   "binding rule",
+			body:   `{"title":"Heat","release_year":1995,"rating":8.3,"actor_ids":[]}`,
+			target: func() interface{} { return &dto.MovieWithActorsRequest{} },
+			expectedBody: `{"type":"about:blank","title":"Bad Request","status":400,"code":"validation_failed",` +
+				`"detail":"validation error: actor_ids: is out of the allowed range","errors":[` +
+				`{"field":"actor_ids","expected":"min=1","key":"request.field.out_of_range","message":"is out of the allowed range"}]}`,
+		},
+		{
+			name:         "malformed json",
+			body:         `{"actor_ids":[1,`,
+			target:       func() interface{} { return &dto.UpdateMovieActorsRequest{} },
+			expectedBody: problem(http.StatusBadRequest, "invalid_request", "invalid request"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			r := gin.New()
+			r.Use(apperror.Middleware())
+			r.POST("/bind", func(c *gin.Context) {
+				if err := bindJSON(c, tt.target()); err != nil {
+					respondError(c, err)
+					return
+				}
+				c.Status(http.StatusNoContent)
+			})
+
+			req, _ := http.NewRequest(http.MethodPost, "/bind", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			assert.JSONEq(t, tt.expectedBody, w.Body.String())
+		})
+	}
+}
+
+func TestFindInvalidValue(t *testing.T) {
+	// Путь без индексов, как его сообщают старые версии encoding/json
+	body := []byte(`{"cast":[{"actor_id":1},{"actor_id":2},{"actor_id":"x"}]}`)
+	segments, found := findInvalidValue(body, []string{"cast", "actor_id"}, reflect.TypeOf(0))
+	assert.True(t, found)
+	assert.Equal(t, []string{"cast", "2", "actor_id"}, segments)
+
+	segments, found = findInvalidValue([]byte(`{"actor_ids":[1,"2"]}`), []string{"actor_ids"}, reflect.TypeOf(0))
+	assert.True(t, found)
+	field, index := jsonPath(segments)
+	assert.Equal(t, "actor_ids", field)
+	assert.Equal(t, 1, *index)
+
+	_, found = findInvalidValue([]byte(`{"cast":[{"actor_id":1}]}`), []string{"cast", "actor_id"}, reflect.TypeOf(0))
+	assert.False(t, found)
+}
+
+func TestNamespaceSegments(t *testing.T) {
+	root := reflect.TypeOf(&dto.UpdateMovieActorsRequest{})
+
+	field, index := jsonPath(namespaceSegments(root, "UpdateMovieActorsRequest.Cast[1].ActorID"))
+	assert.Equal(t, "cast[1].actor_id", field)
+	assert.Nil(t, index)
+
+	field, index = jsonPath(namespaceSegments(root, "UpdateMovieActorsRequest.ActorIDs[3]"))
+	assert.Equal(t, "actor_ids", field)
+	assert.Equal(t, 3, *index)
+}
@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"cinematique/internal/auth"
+	"cinematique/internal/controller/dto"
+	"cinematique/internal/domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CollectionController описывает методы для работы с подборками фильмов
+type CollectionController interface {
+	CreateCollection(c *gin.Context, req dto.CreateCollectionRequest) (dto.CollectionResponse, error)
+	GetCollectionByID(c *gin.Context, id int) (dto.CollectionResponse, error)
+	ListCollections(c *gin.Context) (dto.CollectionsListResponse, error)
+	UpdateCollection(c *gin.Context, id int, req dto.UpdateCollectionRequest) (dto.CollectionResponse, error)
+	DeleteCollection(c *gin.Context, id int) error
+	SetCollectionMovies(c *gin.Context, id int, req dto.SetCollectionMoviesRequest) (dto.CollectionResponse, error)
+}
+
+// CollectionHandler обрабатывает запросы к подборкам фильмов
+type CollectionHandler struct {
+	controller CollectionController
+}
+
+// NewCollectionHandler создаёт обработчик (handler) для подборок
+func NewCollectionHandler(controller CollectionController) *CollectionHandler {
+	return &CollectionHandler{controller: controller}
+}
+
+// List возвращает все подборки
+func (h *CollectionHandler) List(c *gin.Context) {
+	resp, err := h.controller.ListCollections(c)
+	respond(c, http.StatusOK, resp, err)
+}
+
+// GetByID возвращает подборку с фильмами в порядке просмотра
+func (h *CollectionHandler) GetByID(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		respondError(c, errInvalidID)
+		return
+	}
+	resp, err := h.controller.GetCollectionByID(c, id)
+	respond(c, http.StatusOK, resp, err)
+}
+
+// Create создаёт подборку
+func (h *CollectionHandler) Create(c *gin.Context) {
+	var req dto.CreateCollectionRequest
+	if err := bindJSON(c, &req); err != nil {
+		respondError(c, err)
+		return
+	}
+	resp, err := h.controller.CreateCollection(c, req)
+	respond(c, http.StatusCreated, resp, err)
+}
+
+// Update обновляет название и описание подборки
+func (h *CollectionHandler) Update(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		respondError(c, errInvalidID)
+		return
+	}
+	var req dto.UpdateCollectionRequest
+	if err := bindJSON(c, &req); err != nil {
+		respondError(c, err)
+		return
+	}
+	resp, err := h.controller.UpdateCollection(c, id, req)
+	respond(c, http.StatusOK, resp, err)
+}
+
+// Delete удаляет подборку
+func (h *CollectionHandler) Delete(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		respondError(c, errInvalidID)
+		return
+	}
+	err = h.controller.DeleteCollection(c, id)
+	respond(c, http.StatusNoContent, nil, err)
+}
+
+// SetMovies заменяет упорядоченный список фильмов подборки
+func (h *CollectionHandler) SetMovies(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		respondError(c, errInvalidID)
+		return
+	}
+	var req dto.SetCollectionMoviesRequest
+	if err := bindJSON(c, &req); err != nil {
+		respondError(c, err)
+		return
+	}
+	resp, err := h.controller.SetCollectionMovies(c, id, req)
+	respond(c, http.StatusOK, resp, err)
+}
+
+// RegisterCollectionRoutes регистрирует маршруты для подборок фильмов
+func RegisterCollectionRoutes(router *gin.RouterGroup, handler *CollectionHandler) {
+	if handler == nil {
+		return
+	}
+	collections := router.Group("/collections")
+
+	collections.GET("", handler.List)
+	collections.GET(":id", handler.GetByID)
+
+	// Подборки редактируют модераторы и администраторы, удаляют — только администраторы
+	write := auth.RequirePermission(domain.PermissionCatalogWrite)
+	collections.POST("", write, handler.Create)
+	collections.PUT(":id", write, handler.Update)
+	collections.DELETE(":id", auth.RequirePermission(domain.PermissionCatalogDelete), handler.Delete)
+	collections.PUT(":id/movies", write, handler.SetMovies)
+}
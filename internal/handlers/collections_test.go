@@ -0,0 +1,203 @@
+package handlers
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"cinematique/internal/apperror"
+	"cinematique/internal/auth"
+	"cinematique/internal/controller/dto"
+	"cinematique/internal/domain"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockCollectionController мок контроллера подборок
+type MockCollectionController struct {
+	mock.Mock
+}
+
+func (m *MockCollectionController) CreateCollection(c *gin.Context, req dto.CreateCollectionRequest) (dto.CollectionResponse, error) {
+	args := m.Called(c, req)
+	return args.Get(0).(dto.CollectionResponse), args.Error(1)
+}
+
+func (m *MockCollectionController) GetCollectionByID(c *gin.Context, id int) (dto.CollectionResponse, error) {
+	args := m.Called(c, id)
+	return args.Get(0).(dto.CollectionResponse), args.Error(1)
+}
+
+func (m *MockCollectionController) ListCollections(c *gin.Context) (dto.CollectionsListResponse, error) {
+	args := m.Called(c)
+	return args.Get(0).(dto.CollectionsListResponse), args.Error(1)
+}
+
+func (m *MockCollectionController) UpdateCollection(c *gin.Context, id int, req dto.UpdateCollectionRequest) (dto.CollectionResponse, error) {
+	args := m.Called(c, id, req)
+	return args.Get(0).(dto.CollectionResponse), args.Error(1)
+}
+
+func (m *MockCollectionController) DeleteCollection(c *gin.Context, id int) error {
+	return m.Called(c, id).Error(0)
+}
+
+func (m *MockCollectionController) SetCollectionMovies(c *gin.Context, id int, req dto.SetCollectionMoviesRequest) (dto.CollectionResponse, error) {
+	args := m.Called(c, id, req)
+	return args.Get(0).(dto.CollectionResponse), args.Error(1)
+}
+
+// newCollectionRouter регистрирует маршруты подборок для пользователя с ролью role
+func newCollectionRouter(ctrl *MockCollectionController, role string) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(apperror.Middleware())
+	r.Use(func(c *gin.Context) {
+		auth.SetUser(c, &auth.UserContext{Role: role})
+		c.Next()
+	})
+	RegisterCollectionRoutes(r.Group(""), NewCollectionHandler(ctrl))
+	return r
+}
+
+func TestCollectionHandler(t *testing.T) {
+	trilogy := dto.CollectionResponse{
+		ID:   3,
+		Name: "The Matrix Trilogy",
+		Movies: []dto.MovieResponse{
+			{ID: 10, Title: "The Matrix", ReleaseYear: 1999},
+			{ID: 11, Title: "The Matrix Reloaded", ReleaseYear: 2003},
+		},
+	}
+
+	tests := []struct {
+		name           string
+		role           string
+		method         string
+		url            string
+		body           string
+		setupMock      func(*MockCollectionController)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:   "get collection with movies in order",
+			role:   domain.RoleUser,
+			method: http.MethodGet,
+			url:    "/collections/3",
+			setupMock: func(m *MockCollectionController) {
+				m.On("GetCollectionByID", mock.Anything, 3).Return(trilogy, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: `{"id":3,"name":"The Matrix Trilogy","description":"","movies":[` +
+				`{"id":10,"title":"The Matrix","description":"","release_year":1999,"rating":0,"view_count":0},` +
+				`{"id":11,"title":"The Matrix Reloaded","description":"","release_year":2003,"rating":0,"view_count":0}]}`,
+		},
+		{
+			name:   "collection not found",
+			role:   domain.RoleUser,
+			method: http.MethodGet,
+			url:    "/collections/9",
+			setupMock: func(m *MockCollectionController) {
+				m.On("GetCollectionByID", mock.Anything, 9).Return(dto.CollectionResponse{}, domain.ErrCollectionNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   problem(http.StatusNotFound, "collection_not_found", "collection not found"),
+		},
+		{
+			name:           "invalid id",
+			role:           domain.RoleUser,
+			method:         http.MethodGet,
+			url:            "/collections/abc",
+			setupMock:      func(m *MockCollectionController) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   problem(http.StatusBadRequest, "invalid_id", "invalid id"),
+		},
+		{
+			name:   "list",
+			role:   domain.RoleUser,
+			method: http.MethodGet,
+			url:    "/collections",
+			setupMock: func(m *MockCollectionController) {
+				m.On("ListCollections", mock.Anything).Return(dto.CollectionsListResponse{Collections: []dto.CollectionResponse{{ID: 3, Name: "The Matrix Trilogy"}}}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"collections":[{"id":3,"name":"The Matrix Trilogy","description":""}]}`,
+		},
+		{
+			name:   "create",
+			role:   domain.RoleAdmin,
+			method: http.MethodPost,
+			url:    "/collections",
+			body:   `{"name":"The Matrix Trilogy","movie_ids":[10,11]}`,
+			setupMock: func(m *MockCollectionController) {
+				m.On("CreateCollection", mock.Anything, dto.CreateCollectionRequest{Name: "The Matrix Trilogy", MovieIDs: []int{10, 11}}).Return(trilogy, nil)
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "create requires admin",
+			role:           domain.RoleUser,
+			method:         http.MethodPost,
+			url:            "/collections",
+			body:           `{"name":"The Matrix Trilogy"}`,
+			setupMock:      func(m *MockCollectionController) {},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:   "reorder movies",
+			role:   domain.RoleAdmin,
+			method: http.MethodPut,
+			url:    "/collections/3/movies",
+			body:   `{"movie_ids":[11,10]}`,
+			setupMock: func(m *MockCollectionController) {
+				m.On("SetCollectionMovies", mock.Anything, 3, dto.SetCollectionMoviesRequest{MovieIDs: []int{11, 10}}).Return(trilogy, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:   "duplicate movie in list",
+			role:   domain.RoleAdmin,
+			method: http.MethodPut,
+			url:    "/collections/3/movies",
+			body:   `{"movie_ids":[10,10]}`,
+			setupMock: func(m *MockCollectionController) {
+				m.On("SetCollectionMovies", mock.Anything, 3, dto.SetCollectionMoviesRequest{MovieIDs: []int{10, 10}}).Return(dto.CollectionResponse{}, domain.ErrCollectionDuplicate)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   problem(http.StatusBadRequest, "collection_duplicate_movie", "movie appears in the collection more than once"),
+		},
+		{
+			name:   "delete",
+			role:   domain.RoleAdmin,
+			method: http.MethodDelete,
+			url:    "/collections/3",
+			setupMock: func(m *MockCollectionController) {
+				m.On("DeleteCollection", mock.Anything, 3).Return(nil)
+			},
+			expectedStatus: http.StatusNoContent,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := new(MockCollectionController)
+			tt.setupMock(ctrl)
+			r := newCollectionRouter(ctrl, tt.role)
+
+			req, _ := http.NewRequest(tt.method, tt.url, bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedBody != "" {
+				assert.JSONEq(t, tt.expectedBody, w.Body.String())
+			}
+			ctrl.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,339 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"cinematique/internal/apperror"
+	"cinematique/internal/auth"
+	"cinematique/internal/controller/dto"
+	"cinematique/internal/domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+// routeAccess описывает, кому доступен маршрут
+type routeAccess int
+
+const (
+	accessPublic  routeAccess = iota // без аутентификации
+	accessRead                       // любой аутентифицированный пользователь
+	accessCatalog                    // чтение каталога: как accessRead, а в режиме публичного чтения — и без токена
+	accessWrite                      // право domain.PermissionCatalogWrite: модератор или администратор
+	accessDelete                     // право domain.PermissionCatalogDelete
+	accessAdmin                      // только администратор
+)
+
+// routeDoc — запись декларативной таблицы маршрутов и прав доступа
+type routeDoc struct {
+	Method  string
+	Path    string // путь в формате gin относительно базового пути API
+	Tag     string
+	Summary string
+	Access  routeAccess
+}
+
+// apiRoutes — таблица всех маршрутов API и прав доступа к ним.
+// Используется для генерации OpenAPI-документации; тест проверяет,
+// что таблица совпадает с фактически зарегистрированными маршрутами
+var apiRoutes = []routeDoc{
+	// Аутентификация
+	{http.MethodPost, "/auth/register", "auth", "Регистрация пользователя", accessPublic},
+	{http.MethodPost, "/auth/login", "auth", "Вход по логину и паролю", accessPublic},
+	{http.MethodPost, "/auth/refresh", "auth", "Обновление access-токена", accessPublic},
+	{http.MethodPost, "/auth/logout", "auth", "Выход", accessPublic},
+	{http.MethodPost, "/auth/forgot-password", "auth", "Ссылка сброса пароля на email", accessPublic},
+	{http.MethodPost, "/auth/reset-password", "auth", "Сброс пароля по токену из письма", accessPublic},
+
+	// Учётная запись
+	{http.MethodGet, "/users/me", "users", "Учётная запись текущего пользователя", accessRead},
+	{http.MethodPatch, "/users/me", "users", "Смена email или пароля с проверкой текущего пароля", accessRead},
+	{http.MethodDelete, "/users/me", "users", "Удаление своей учётной записи с отзывом токенов", accessRead},
+	{http.MethodPut, "/users/:id/role", "users", "Смена роли пользователя; старые токены доступа требуют refresh", accessAdmin},
+
+	// Документация
+	{http.MethodGet, "/docs", "docs", "Swagger UI", accessPublic},
+	{http.MethodGet, "/docs/openapi.json", "docs", "OpenAPI-спецификация для текущей роли", accessPublic},
+
+	// Актёры
+	{http.MethodGet, "/actors", "actors", "Список актёров", accessCatalog},
+	{http.MethodGet, "/actors/search", "actors", "Поиск актёров по фрагменту имени", accessCatalog},
+	{http.MethodGet, "/actors/suggest", "actors", "Подсказки имён актёров для автодополнения", accessCatalog},
+	{http.MethodGet, "/actors/birthdays", "actors", "Актёры, родившиеся в указанном месяце", accessCatalog},
+	{http.MethodGet, "/actors/:id", "actors", "Актёр по ID", accessCatalog},
+	{http.MethodGet, "/actors/:id/stats", "actors", "Число фильмов актёра, их средний рейтинг, первый и последний год", accessCatalog},
+	{http.MethodGet, "/actors/:id/history", "actors", "Версии профиля актёра и отличия между ними", accessCatalog},
+	{http.MethodGet, "/actors/slug/:slug", "actors", "Актёр по slug", accessCatalog},
+	{http.MethodGet, "/actors/with-movies", "actors", "Актёры с фильмами", accessCatalog},
+	{http.MethodPost, "/actors", "actors", "Создание актёра", accessWrite},
+	{http.MethodPut, "/actors/:id", "actors", "Обновление актёра", accessWrite},
+	{http.MethodPatch, "/actors/:id", "actors", "Частичное обновление актёра", accessWrite},
+	{http.MethodDelete, "/actors/:id", "actors", "Удаление актёра", accessDelete},
+	{http.MethodPost, "/actors/:id/photo", "actors", "Загрузка фотографии актёра", accessWrite},
+
+	// Фильмы
+	{http.MethodGet, "/movies", "movies", "Список фильмов", accessCatalog},
+	{http.MethodGet, "/movies/search", "movies", "Поиск фильмов по названию или актёру", accessCatalog},
+	{http.MethodGet, "/movies/sorted", "movies", "Список фильмов с сортировкой", accessCatalog},
+	{http.MethodGet, "/movies/upcoming", "movies", "Фильмы, которые ещё не вышли", accessCatalog},
+	{http.MethodGet, "/movies/popular", "movies", "Самые просматриваемые фильмы", accessCatalog},
+	{http.MethodGet, "/movies/year/:year", "movies", "Фильмы, вышедшие в году, и их число", accessCatalog},
+	{http.MethodGet, "/movies/decade/:decade", "movies", "Фильмы десятилетия и их число", accessCatalog},
+	{http.MethodGet, "/movies/actor/:id", "movies", "Фильмы актёра", accessCatalog},
+	{http.MethodGet, "/movies/:id", "movies", "Фильм по ID", accessCatalog},
+	{http.MethodGet, "/movies/slug/:slug", "movies", "Фильм по slug", accessCatalog},
+	{http.MethodGet, "/movies/:id/actors", "movies", "Актёры фильма", accessCatalog},
+	{http.MethodGet, "/movies/:id/cast", "movies", "Страница состава фильма в порядке титров", accessCatalog},
+	{http.MethodGet, "/movies/:id/rating-history", "movies", "История рейтинга фильма", accessCatalog},
+	{http.MethodGet, "/movies/:id/history", "movies", "Версии фильма и отличия между ними", accessCatalog},
+	{http.MethodGet, "/movies/:id/ratings", "movies", "Рейтинги фильма по источникам и взвешенный рейтинг", accessCatalog},
+	{http.MethodGet, "/movies/:id/availability", "movies", "Окна доступности фильма по регионам", accessCatalog},
+	{http.MethodGet, "/movies/:id/tags", "movies", "Теги фильма", accessCatalog},
+	{http.MethodPost, "/movies", "movies", "Создание фильма", accessWrite},
+	{http.MethodPost, "/movies/with-actors", "movies", "Создание фильма с актёрами", accessWrite},
+	{http.MethodPost, "/movies/full", "movies", "Создание фильма вместе с новыми актёрами", accessWrite},
+	{http.MethodPut, "/movies/:id", "movies", "Обновление фильма", accessWrite},
+	{http.MethodPut, "/movies/external/:externalId", "movies", "Создание или обновление фильма по внешнему ID", accessWrite},
+	{http.MethodPatch, "/movies/:id", "movies", "Частичное обновление фильма", accessWrite},
+	{http.MethodDelete, "/movies/:id", "movies", "Удаление фильма", accessDelete},
+	{http.MethodPost, "/movies/:id/actors", "movies", "Замена списка актёров фильма", accessWrite},
+	{http.MethodPatch, "/movies/:id/actors", "movies", "Добавление и удаление актёров фильма без замены состава", accessWrite},
+	{http.MethodPatch, "/movies/:id/actors/order", "movies", "Порядок актёров фильма в титрах", accessWrite},
+	{http.MethodPost, "/movies/add-actor/:movieId/:actorId", "movies", "Добавление актёра к фильму", accessWrite},
+	{http.MethodDelete, "/movies/remove-actor/:movieId/:actorId", "movies", "Удаление актёра из фильма", accessWrite},
+	{http.MethodPost, "/movies/:id/availability", "movies", "Добавление окна доступности фильма", accessWrite},
+	{http.MethodPut, "/movies/:id/availability/:windowId", "movies", "Изменение окна доступности фильма", accessWrite},
+	{http.MethodDelete, "/movies/:id/availability/:windowId", "movies", "Удаление окна доступности фильма", accessWrite},
+	{http.MethodPut, "/movies/:id/ratings/:source", "movies", "Рейтинг фильма из внешнего источника", accessAdmin},
+	{http.MethodDelete, "/movies/:id/ratings/:source", "movies", "Удаление рейтинга фильма из внешнего источника", accessAdmin},
+	{http.MethodPut, "/movies/:id/tags/:tag", "movies", "Добавление тега фильму", accessAdmin},
+	{http.MethodDelete, "/movies/:id/tags/:tag", "movies", "Снятие тега с фильма", accessAdmin},
+
+	// Теги
+	{http.MethodGet, "/tags/suggest", "tags", "Подсказки тегов для автодополнения", accessCatalog},
+	{http.MethodGet, "/tags/:tag/movies", "tags", "Фильмы с тегом и их число", accessCatalog},
+
+	// Подборки
+	{http.MethodGet, "/collections", "collections", "Список подборок", accessCatalog},
+	{http.MethodGet, "/collections/:id", "collections", "Подборка с фильмами по порядку", accessCatalog},
+	{http.MethodPost, "/collections", "collections", "Создание подборки", accessWrite},
+	{http.MethodPut, "/collections/:id", "collections", "Обновление подборки", accessWrite},
+	{http.MethodDelete, "/collections/:id", "collections", "Удаление подборки", accessDelete},
+	{http.MethodPut, "/collections/:id/movies", "collections", "Замена упорядоченного списка фильмов подборки", accessWrite},
+
+	// Статистика
+	{http.MethodGet, "/stats", "stats", "Агрегированная статистика каталога", accessCatalog},
+	{http.MethodGet, "/admin/stats/top-searches", "admin", "Самые частые поисковые запросы и запросы без результатов", accessAdmin},
+
+	// Общий поиск
+	{http.MethodGet, "/search", "search", "Поиск фильмов и актёров одной строкой, по убыванию похожести", accessCatalog},
+
+	// Rate limiting
+	{http.MethodGet, "/rate-limit/status", "rate-limit", "Статус лимита запросов", accessRead},
+
+	// Администрирование
+	{http.MethodPost, "/admin/actors/:keepId/merge/:dupId", "admin", "Слияние актёров-дубликатов", accessAdmin},
+	{http.MethodGet, "/admin/actors/orphans", "admin", "Актёры без фильмов и порог подтверждения удаления", accessAdmin},
+	{http.MethodPost, "/admin/actors/orphans/purge", "admin", "Удаление актёров без фильмов; выше порога — с confirm_count", accessAdmin},
+	{http.MethodPost, "/admin/movies/merge", "admin", "Слияние фильмов-дубликатов", accessAdmin},
+	{http.MethodPost, "/admin/movies/bulk-delete", "admin", "Удаление нескольких фильмов в одной транзакции", accessAdmin},
+	{http.MethodPost, "/admin/movies/bulk-update", "admin", "Изменение рейтинга, языка или страны у нескольких фильмов", accessAdmin},
+	{http.MethodPost, "/admin/movies/import-external", "admin", "Импорт фильма и актёров из TMDB по imdb_id", accessAdmin},
+	{http.MethodGet, "/admin/export/movies", "admin", "Потоковая выгрузка всех фильмов в NDJSON или CSV", accessAdmin},
+	{http.MethodGet, "/admin/export/actors", "admin", "Потоковая выгрузка всех актёров в NDJSON или CSV", accessAdmin},
+	{http.MethodPost, "/admin/import/actors", "admin", "Импорт актёров из CSV; с dry_run=true — только отчёт о проверке строк", accessAdmin},
+	{http.MethodGet, "/admin/jobs/:id", "admin", "Состояние фоновой задачи", accessAdmin},
+	{http.MethodGet, "/admin/jobs/:id/wait", "admin", "Ожидание завершения фоновой задачи (long polling)", accessAdmin},
+	{http.MethodPost, "/admin/maintenance/reindex", "admin", "Фоновое перестроение индексов поиска", accessAdmin},
+	{http.MethodGet, "/admin/maintenance/jobs/:id", "admin", "Ход и результат служебной задачи", accessAdmin},
+
+	// События
+	{http.MethodGet, "/events", "events", "Поток изменений каталога (Server-Sent Events)", accessAdmin},
+}
+
+// public сообщает, доступен ли маршрут без токена
+func (r routeDoc) public(publicReads bool) bool {
+	return r.Access == accessPublic || (publicReads && r.Access == accessCatalog)
+}
+
+// visibleFor сообщает, может ли пользователь с данной ролью вызвать маршрут
+func (r routeDoc) visibleFor(role string, publicReads bool) bool {
+	if r.public(publicReads) {
+		return true
+	}
+	switch r.Access {
+	case accessRead, accessCatalog:
+		return role != ""
+	case accessWrite:
+		return domain.RoleHasPermission(role, domain.PermissionCatalogWrite)
+	case accessDelete:
+		return domain.RoleHasPermission(role, domain.PermissionCatalogDelete)
+	case accessAdmin:
+		return role == domain.RoleAdmin
+	}
+	return false
+}
+
+// DocsHandler отдаёт OpenAPI-документацию, отфильтрованную по роли вызывающего
+type DocsHandler struct {
+	basePath    string
+	routes      []routeDoc
+	publicReads bool // каталог читается без токена, см. RegisterAllRoutes
+}
+
+// NewDocsHandler создаёт обработчик документации для API с заданным базовым путём
+func NewDocsHandler(basePath string) *DocsHandler {
+	return &DocsHandler{basePath: strings.TrimSuffix(basePath, "/"), routes: apiRoutes}
+}
+
+// OpenAPI возвращает спецификацию только с теми маршрутами, которые доступны текущей роли
+func (h *DocsHandler) OpenAPI(c *gin.Context) {
+	c.JSON(http.StatusOK, h.buildSpec(auth.CurrentRole(c)))
+}
+
+// SwaggerUI отдаёт страницу Swagger UI. Токен для фильтрации берётся из localStorage
+// (ключ cinematique_token) и передаётся при запросе спецификации
+func (h *DocsHandler) SwaggerUI(c *gin.Context) {
+	page := strings.ReplaceAll(swaggerUITemplate, "{{SPEC_URL}}", h.basePath+"/docs/openapi.json")
+	c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(page))
+}
+
+// buildSpec собирает документ OpenAPI 3 из таблицы маршрутов
+func (h *DocsHandler) buildSpec(role string) gin.H {
+	paths := gin.H{}
+	for _, route := range h.routes {
+		if !route.visibleFor(role, h.publicReads) {
+			continue
+		}
+
+		path, params := openAPIPath(route.Path)
+		operation := gin.H{
+			"summary":   route.Summary,
+			"tags":      []string{route.Tag},
+			"responses": gin.H{"200": gin.H{"description": "OK"}},
+		}
+		if len(params) > 0 {
+			parameters := make([]gin.H, 0, len(params))
+			for _, name := range params {
+				parameters = append(parameters, gin.H{
+					"name":     name,
+					"in":       "path",
+					"required": true,
+					"schema":   gin.H{"type": "string"},
+				})
+			}
+			operation["parameters"] = parameters
+		}
+		if route.Method != http.MethodGet && route.Method != http.MethodDelete {
+			operation["responses"].(gin.H)["400"] = gin.H{
+				"description": "Ошибка валидации",
+				"content": gin.H{apperror.ContentType: gin.H{
+					"schema": gin.H{"$ref": "#/components/schemas/Problem"},
+				}},
+			}
+		}
+		if !route.public(h.publicReads) {
+			operation["security"] = []gin.H{{"bearerAuth": []string{}}}
+		}
+
+		item, ok := paths[path].(gin.H)
+		if !ok {
+			item = gin.H{}
+			paths[path] = item
+		}
+		item[strings.ToLower(route.Method)] = operation
+	}
+
+	return gin.H{
+		"openapi": "3.0.3",
+		"info": gin.H{
+			"title":   "Cinematique API",
+			"version": "1.0.0",
+		},
+		"servers": []gin.H{{"url": h.basePath}},
+		"paths":   paths,
+		"components": gin.H{
+			"securitySchemes": gin.H{
+				"bearerAuth": gin.H{"type": "http", "scheme": "bearer", "bearerFormat": "JWT"},
+			},
+			"schemas": validationSchemas(),
+		},
+		// Каталог ключей ошибок валидации: клиенты строят по нему свои переводы
+		"x-validation-error-keys": dto.ValidationKeys,
+	}
+}
+
+// validationSchemas описывает тело ответа с ошибкой и ошибки по полям.
+// Список допустимых ключей берётся из каталога dto.ValidationKeys
+func validationSchemas() gin.H {
+	keys := make([]string, 0, len(dto.ValidationKeys))
+	for _, entry := range dto.ValidationKeys {
+		keys = append(keys, entry.Key)
+	}
+	return gin.H{
+		"FieldError": gin.H{
+			"type":     "object",
+			"required": []string{"field", "key", "message"},
+			"properties": gin.H{
+				"field":   gin.H{"type": "string"},
+				"key":     gin.H{"type": "string", "enum": keys},
+				"message": gin.H{"type": "string"},
+			},
+		},
+		// Ошибки отдаются по RFC 7807; errors заполняется только для code=validation_failed
+		"Problem": gin.H{
+			"type":     "object",
+			"required": []string{"type", "title", "status", "code"},
+			"properties": gin.H{
+				"type":   gin.H{"type": "string"},
+				"title":  gin.H{"type": "string"},
+				"status": gin.H{"type": "integer"},
+				"detail": gin.H{"type": "string"},
+				"code":   gin.H{"type": "string"},
+				"errors": gin.H{"type": "array", "items": gin.H{"$ref": "#/components/schemas/FieldError"}},
+			},
+		},
+	}
+}
+
+// openAPIPath переводит путь gin (/movies/:id) в формат OpenAPI (/movies/{id})
+// и возвращает имена параметров пути
+func openAPIPath(ginPath string) (string, []string) {
+	segments := strings.Split(ginPath, "/")
+	var params []string
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") {
+			name := strings.TrimPrefix(segment, ":")
+			params = append(params, name)
+			segments[i] = "{" + name + "}"
+		}
+	}
+	return strings.Join(segments, "/"), params
+}
+
+const swaggerUITemplate = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>Cinematique API</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js"></script>
+  <script>
+    window.ui = SwaggerUIBundle({
+      url: "{{SPEC_URL}}",
+      dom_id: "#swagger-ui",
+      requestInterceptor: function (req) {
+        var token = window.localStorage.getItem("cinematique_token");
+        if (token) {
+          req.headers["Authorization"] = "Bearer " + token;
+        }
+        return req;
+      }
+    });
+  </script>
+</body>
+</html>
+`
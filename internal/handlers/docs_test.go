@@ -0,0 +1,200 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"cinematique/internal/apperror"
+	"cinematique/internal/auth"
+	"cinematique/internal/controller/dto"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestAPIRoutesTableMatchesRouter проверяет, что таблица маршрутов для документации
+// совпадает с реально зарегистрированными маршрутами
+func TestAPIRoutesTableMatchesRouter(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	api := r.Group("/api")
+	RegisterAllRoutes(api, false, &ActorHandler{}, &MovieHandler{}, &AuthHandler{}, &RateLimitHandler{}, &AdminHandler{}, &CollectionHandler{}, &EventsHandler{}, &StatsHandler{}, &SearchHandler{})
+
+	registered := map[string]bool{}
+	for _, route := range r.Routes() {
+		registered[route.Method+" "+strings.TrimPrefix(route.Path, "/api")] = true
+	}
+
+	documented := map[string]bool{}
+	for _, route := range apiRoutes {
+		key := route.Method + " " + route.Path
+		assert.False(t, documented[key], "route %s is documented twice", key)
+		documented[key] = true
+	}
+
+	for key := range registered {
+		assert.True(t, documented[key], "route %s is registered but missing from apiRoutes", key)
+	}
+	for key := range documented {
+		assert.True(t, registered[key], "route %s is documented but not registered", key)
+	}
+}
+
+// TestRegisterAllRoutes_PublicReads проверяет, что в режиме публичного чтения без токена
+// доступно только чтение каталога
+func TestRegisterAllRoutes_PublicReads(t *testing.T) {
+	tests := []struct {
+		method, path string
+		publicReads  bool
+		wantStatus   int
+	}{
+		// Поиск без параметров отклоняется обработчиком, то есть запрос прошёл аутентификацию
+		{http.MethodGet, "/api/movies/search", true, http.StatusBadRequest},
+		{http.MethodGet, "/api/movies/search", false, http.StatusUnauthorized},
+		{http.MethodPost, "/api/movies", true, http.StatusUnauthorized},
+		{http.MethodDelete, "/api/actors/1", true, http.StatusUnauthorized},
+		{http.MethodGet, "/api/users/me", true, http.StatusUnauthorized},
+		{http.MethodGet, "/api/admin/export/movies", true, http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s %s public=%v", tt.method, tt.path, tt.publicReads), func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			r := gin.New()
+			r.Use(apperror.Middleware())
+			RegisterAllRoutes(r.Group("/api"), tt.publicReads, &ActorHandler{}, &MovieHandler{}, &AuthHandler{}, &RateLimitHandler{}, &AdminHandler{}, &CollectionHandler{}, &EventsHandler{}, &StatsHandler{}, &SearchHandler{})
+
+			req, _ := http.NewRequest(tt.method, tt.path, nil)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+		})
+	}
+}
+
+func TestDocsHandler_OpenAPI(t *testing.T) {
+	tests := []struct {
+		name        string
+		role        string
+		wantPaths   []string
+		hiddenPaths []string
+	}{
+		{
+			name:        "anonymous sees only public routes",
+			role:        "",
+			wantPaths:   []string{"/auth/login"},
+			hiddenPaths: []string{"/movies", "/admin/movies/merge"},
+		},
+		{
+			name:        "user sees read routes but not admin routes",
+			role:        "user",
+			wantPaths:   []string{"/auth/login", "/movies", "/movies/{id}"},
+			hiddenPaths: []string{"/admin/movies/merge", "/movies/add-actor/{movieId}/{actorId}"},
+		},
+		{
+			name:        "moderator sees write routes but not delete or admin routes",
+			role:        "moderator",
+			wantPaths:   []string{"/movies/{id}", "/movies/add-actor/{movieId}/{actorId}"},
+			hiddenPaths: []string{"/admin/movies/merge", "/events"},
+		},
+		{
+			name:      "admin sees everything",
+			role:      "admin",
+			wantPaths: []string{"/auth/login", "/movies/{id}", "/admin/movies/merge"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			r := gin.New()
+			handler := NewDocsHandler("/api")
+			r.GET("/api/docs/openapi.json", func(c *gin.Context) {
+				if tt.role != "" {
+					auth.SetUser(c, &auth.UserContext{Role: tt.role})
+				}
+				handler.OpenAPI(c)
+			})
+
+			req, _ := http.NewRequest(http.MethodGet, "/api/docs/openapi.json", nil)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			require.Equal(t, http.StatusOK, w.Code)
+			var spec struct {
+				Paths map[string]map[string]interface{} `json:"paths"`
+			}
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &spec))
+
+			for _, path := range tt.wantPaths {
+				assert.Contains(t, spec.Paths, path)
+			}
+			for _, path := range tt.hiddenPaths {
+				assert.NotContains(t, spec.Paths, path)
+			}
+			if tt.role == "user" {
+				// У обычного пользователя на /movies есть только чтение
+				assert.Contains(t, spec.Paths["/movies"], "get")
+				assert.NotContains(t, spec.Paths["/movies"], "post")
+			}
+			if tt.role == "moderator" {
+				// Модератор редактирует фильм, но не удаляет его
+				assert.Contains(t, spec.Paths["/movies/{id}"], "patch")
+				assert.NotContains(t, spec.Paths["/movies/{id}"], "delete")
+			}
+		})
+	}
+}
+
+func TestDocsHandler_OpenAPIValidationKeys(t *testing.T) {
+	spec := NewDocsHandler("/api").buildSpec("admin")
+	body, err := json.Marshal(spec)
+	require.NoError(t, err)
+
+	var doc struct {
+		Paths      map[string]map[string]map[string]interface{} `json:"paths"`
+		Components struct {
+			Schemas map[string]interface{} `json:"schemas"`
+		} `json:"components"`
+		Keys []dto.ValidationKey `json:"x-validation-error-keys"`
+	}
+	require.NoError(t, json.Unmarshal(body, &doc))
+
+	assert.Equal(t, dto.ValidationKeys, doc.Keys)
+	assert.Contains(t, doc.Components.Schemas, "Problem")
+	assert.Contains(t, doc.Paths["/movies"]["post"]["responses"], "400")
+	assert.NotContains(t, doc.Paths["/movies"]["get"]["responses"], "400")
+
+	seen := map[string]bool{}
+	for _, entry := range dto.ValidationKeys {
+		assert.False(t, seen[entry.Key], "key %s is listed twice", entry.Key)
+		seen[entry.Key] = true
+		assert.NotEmpty(t, entry.Field, entry.Key)
+		assert.NotEmpty(t, entry.Message, entry.Key)
+	}
+}
+
+func TestDocsHandler_OpenAPIPublicReads(t *testing.T) {
+	handler := NewDocsHandler("/api")
+	handler.publicReads = true
+	body, err := json.Marshal(handler.buildSpec(""))
+	require.NoError(t, err)
+
+	var doc struct {
+		Paths map[string]map[string]map[string]interface{} `json:"paths"`
+	}
+	require.NoError(t, json.Unmarshal(body, &doc))
+
+	// Аноним видит чтение каталога без требования токена, но не запись и не учётную запись
+	require.Contains(t, doc.Paths["/movies"], "get")
+	assert.NotContains(t, doc.Paths["/movies"]["get"], "security")
+	assert.NotContains(t, doc.Paths["/movies"], "post")
+	assert.NotContains(t, doc.Paths, "/users/me")
+	assert.NotContains(t, doc.Paths, "/admin/movies/merge")
+}
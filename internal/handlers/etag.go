@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// computeETag вычисляет сильный ETag по сериализованному телу ответа
+func computeETag(payload []byte) string {
+	sum := sha256.Sum256(payload)
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// etagVolatileFields — поля ответа, которые не входят в ETag: счётчик просмотров растёт
+// почти с каждым запросом, и с ним ETag менялся бы раньше, чем клиент успел бы им воспользоваться
+var etagVolatileFields = map[string]bool{
+	"view_count": true,
+}
+
+// etagInput возвращает тело ответа без полей etagVolatileFields на любом уровне вложенности
+func etagInput(payload []byte) []byte {
+	decoder := json.NewDecoder(bytes.NewReader(payload))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return payload
+	}
+	stripped, err := json.Marshal(stripVolatileFields(value))
+	if err != nil {
+		return payload
+	}
+	return stripped
+}
+
+func stripVolatileFields(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, field := range v {
+			if etagVolatileFields[key] {
+				delete(v, key)
+				continue
+			}
+			v[key] = stripVolatileFields(field)
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = stripVolatileFields(item)
+		}
+	}
+	return value
+}
+
+// etagMatches проверяет, совпадает ли ETag с одним из значений заголовка If-None-Match
+func etagMatches(ifNoneMatch, etag string) bool {
+	if ifNoneMatch == "" {
+		return false
+	}
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" {
+			return true
+		}
+		// Для GET допускается слабое сравнение, поэтому префикс W/ игнорируется
+		if strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// respondWithETag отправляет JSON-ответ с заголовком ETag, а если клиент
+// прислал совпадающий If-None-Match — пустой ответ 304 Not Modified.
+// ETag не учитывает etagVolatileFields, поэтому в кэше клиента они могут отставать
+func respondWithETag(c *gin.Context, body interface{}) {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	etag := computeETag(etagInput(payload))
+	c.Header("ETag", etag)
+	// Клиент обязан перепроверять кэш, но может делать это условным запросом
+	c.Header("Cache-Control", "no-cache")
+
+	if etagMatches(c.GetHeader("If-None-Match"), etag) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+	c.Data(http.StatusOK, "application/json; charset=utf-8", payload)
+}
+
+// respondNotModifiedSince выставляет заголовок Last-Modified по моменту последнего изменения
+// ресурса и отвечает 304, если клиент прислал If-Modified-Since не раньше этого момента.
+// Если есть If-None-Match, решает только ETag (RFC 9110, 13.1.3). Возвращает true, если
+// ответ уже отправлен
+func respondNotModifiedSince(c *gin.Context, updatedAt *time.Time) bool {
+	if updatedAt == nil || updatedAt.IsZero() {
+		return false
+	}
+	// HTTP-дата хранит время с точностью до секунды
+	modified := updatedAt.UTC().Truncate(time.Second)
+	c.Header("Last-Modified", modified.Format(http.TimeFormat))
+
+	if c.GetHeader("If-None-Match") != "" {
+		return false
+	}
+	since, err := http.ParseTime(c.GetHeader("If-Modified-Since"))
+	if err != nil || modified.After(since) {
+		return false
+	}
+	c.Header("Cache-Control", "no-cache")
+	c.Status(http.StatusNotModified)
+	return true
+}
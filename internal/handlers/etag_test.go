@@ -0,0 +1,205 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"cinematique/internal/controller/dto"
+	"cinematique/internal/kafka"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestEtagMatches(t *testing.T) {
+	etag := `"abc"`
+	tests := []struct {
+		name        string
+		ifNoneMatch string
+		want        bool
+	}{
+		{name: "empty header", ifNoneMatch: "", want: false},
+		{name: "exact match", ifNoneMatch: `"abc"`, want: true},
+		{name: "weak match", ifNoneMatch: `W/"abc"`, want: true},
+		{name: "match in list", ifNoneMatch: `"xyz", "abc"`, want: true},
+		{name: "wildcard", ifNoneMatch: "*", want: true},
+		{name: "no match", ifNoneMatch: `"xyz"`, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, etagMatches(tt.ifNoneMatch, etag))
+		})
+	}
+}
+
+func TestMovieHandler_GetByID_ETag(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	mockCtrl := new(MockMovieController)
+	producer := kafka.NewMockProducer()
+	producer.On("Produce", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	handler := newTestMovieHandler(mockCtrl, producer)
+
+	movie := dto.MovieResponse{ID: 1, Title: "Test Movie", Description: "Test Description", ReleaseYear: 2023, Rating: 8.5}
+	mockCtrl.On("GetMovieByID", mock.Anything, 1).Return(movie, nil)
+	r.GET("/movies/:id", handler.GetByID)
+
+	// Первый запрос возвращает тело и ETag
+	req, _ := http.NewRequest(http.MethodGet, "/movies/1", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	etag := w.Header().Get("ETag")
+	assert.NotEmpty(t, etag)
+
+	// Повторный запрос с тем же ETag возвращает 304 без тела
+	req, _ = http.NewRequest(http.MethodGet, "/movies/1", nil)
+	req.Header.Set("If-None-Match", etag)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotModified, w.Code)
+	assert.Empty(t, w.Body.String())
+	assert.Equal(t, etag, w.Header().Get("ETag"))
+}
+
+func TestMovieHandler_List_ETagChangesWithContent(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	mockCtrl := new(MockMovieController)
+	producer := kafka.NewMockProducer()
+	handler := newTestMovieHandler(mockCtrl, producer)
+
+	mockCtrl.On("ListMovies", mock.Anything).
+		Return(dto.MoviesListResponse{Movies: []dto.MovieResponse{{ID: 1, Title: "Old Title"}}}, nil).Once()
+	mockCtrl.On("ListMovies", mock.Anything).
+		Return(dto.MoviesListResponse{Movies: []dto.MovieResponse{{ID: 1, Title: "New Title"}}}, nil).Once()
+	r.GET("/movies", handler.List)
+
+	req, _ := http.NewRequest(http.MethodGet, "/movies", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	etag := w.Header().Get("ETag")
+
+	// После изменения данных старый ETag не совпадает, клиент получает новое тело
+	req, _ = http.NewRequest(http.MethodGet, "/movies", nil)
+	req.Header.Set("If-None-Match", etag)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotEqual(t, etag, w.Header().Get("ETag"))
+	assert.JSONEq(t, `{"movies":[{"id":1,"title":"New Title","description":"","release_year":0,"rating":0,"view_count":0}]}`, w.Body.String())
+}
+
+func TestMovieHandler_GetByID_ETagIgnoresViewCount(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	mockCtrl := new(MockMovieController)
+	producer := kafka.NewMockProducer()
+	producer.On("Produce", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	handler := newTestMovieHandler(mockCtrl, producer)
+
+	mockCtrl.On("GetMovieByID", mock.Anything, 1).
+		Return(dto.MovieResponse{ID: 1, Title: "Test Movie", ViewCount: 10}, nil).Once()
+	mockCtrl.On("GetMovieByID", mock.Anything, 1).
+		Return(dto.MovieResponse{ID: 1, Title: "Test Movie", ViewCount: 11}, nil).Once()
+	r.GET("/movies/:id", handler.GetByID)
+
+	req, _ := http.NewRequest(http.MethodGet, "/movies/1", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	etag := w.Header().Get("ETag")
+
+	// Просмотр увеличил счётчик, но остальное не изменилось — кэш клиента остаётся валидным
+	req, _ = http.NewRequest(http.MethodGet, "/movies/1", nil)
+	req.Header.Set("If-None-Match", etag)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotModified, w.Code)
+	assert.Equal(t, etag, w.Header().Get("ETag"))
+}
+
+func TestMovieHandler_GetByID_IfModifiedSince(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	mockCtrl := new(MockMovieController)
+	producer := kafka.NewMockProducer()
+	producer.On("Produce", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	handler := newTestMovieHandler(mockCtrl, producer)
+
+	updatedAt := time.Date(2024, 5, 10, 12, 30, 15, 500, time.UTC)
+	movie := dto.MovieResponse{ID: 1, Title: "Test Movie", UpdatedAt: &updatedAt}
+	mockCtrl.On("GetMovieByID", mock.Anything, 1).Return(movie, nil)
+	r.GET("/movies/:id", handler.GetByID)
+
+	tests := []struct {
+		name            string
+		ifModifiedSince string
+		ifNoneMatch     string
+		expectedStatus  int
+	}{
+		{name: "no condition", expectedStatus: http.StatusOK},
+		{name: "not modified since", ifModifiedSince: "Fri, 10 May 2024 12:30:15 GMT", expectedStatus: http.StatusNotModified},
+		{name: "modified later", ifModifiedSince: "Fri, 10 May 2024 12:30:14 GMT", expectedStatus: http.StatusOK},
+		{name: "invalid date", ifModifiedSince: "yesterday", expectedStatus: http.StatusOK},
+		// If-None-Match важнее If-Modified-Since: несовпавший ETag означает новое тело
+		{name: "etag takes precedence", ifModifiedSince: "Fri, 10 May 2024 12:30:15 GMT", ifNoneMatch: `"stale"`, expectedStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest(http.MethodGet, "/movies/1", nil)
+			if tt.ifModifiedSince != "" {
+				req.Header.Set("If-Modified-Since", tt.ifModifiedSince)
+			}
+			if tt.ifNoneMatch != "" {
+				req.Header.Set("If-None-Match", tt.ifNoneMatch)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.Equal(t, "Fri, 10 May 2024 12:30:15 GMT", w.Header().Get("Last-Modified"))
+			if tt.expectedStatus == http.StatusNotModified {
+				assert.Empty(t, w.Body.String())
+			} else {
+				assert.Contains(t, w.Body.String(), `"updated_at":"2024-05-10T12:30:15.0000005Z"`)
+			}
+		})
+	}
+}
+
+func TestActorHandler_GetByID_LastModified(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	mockCtrl := new(MockActorController)
+	handler := NewActorHandler(mockCtrl, nil)
+
+	updatedAt := time.Date(2024, 5, 10, 12, 30, 15, 0, time.UTC)
+	mockCtrl.On("GetActorByID", mock.Anything, 1).Return(dto.ActorResponse{ID: 1, Name: "Test Actor", UpdatedAt: &updatedAt}, nil)
+	r.GET("/actors/:id", handler.GetByID)
+
+	req, _ := http.NewRequest(http.MethodGet, "/actors/1", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	lastModified := w.Header().Get("Last-Modified")
+	assert.Equal(t, "Fri, 10 May 2024 12:30:15 GMT", lastModified)
+	assert.NotEmpty(t, w.Header().Get("ETag"))
+
+	req, _ = http.NewRequest(http.MethodGet, "/actors/1", nil)
+	req.Header.Set("If-Modified-Since", lastModified)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotModified, w.Code)
+	assert.Empty(t, w.Body.String())
+}
@@ -0,0 +1,188 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"cinematique/internal/auth"
+	"cinematique/internal/controller/dto"
+	"cinematique/internal/domain"
+	"cinematique/internal/eventbus"
+	"cinematique/internal/kafka"
+	"cinematique/internal/kafka/events"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CatalogChangesTopic — топик Kafka с событиями создания, изменения и удаления фильмов и актёров
+const CatalogChangesTopic = "catalog-changes"
+
+// MovieActorLinksTopic — топик Kafka с событиями добавления актёров в фильмы и удаления из них
+const MovieActorLinksTopic = "movie-actor-links"
+
+// Действия над сущностями каталога в событиях catalog-changes
+const (
+	catalogActionCreated = events.ActionCreated
+	catalogActionUpdated = events.ActionUpdated
+	catalogActionDeleted = events.ActionDeleted
+)
+
+const (
+	eventsSubscriberBuffer = 64
+	eventsHeartbeat        = 15 * time.Second
+)
+
+// publishEvent публикует событие в шину. topic и key — адрес сообщения для подписчика,
+// пересылающего события в Kafka
+func publishEvent(ctx context.Context, bus *eventbus.Bus, topic string, key []byte, event events.Event) error {
+	return bus.Publish(ctx, eventbus.Message{Topic: topic, Key: key, Event: event})
+}
+
+// publishCatalogChange публикует событие изменения каталога.
+// Запись уже зафиксирована в БД, поэтому ошибка доставки только логируется
+func publishCatalogChange(ctx context.Context, bus *eventbus.Bus, entity, action string, id int) {
+	if bus == nil {
+		return
+	}
+	event := events.NewCatalogChanged(entity, action, id)
+	if err := publishEvent(ctx, bus, CatalogChangesTopic, []byte(entity+":"+strconv.Itoa(id)), event); err != nil {
+		log.Printf("Failed to send %s %s event (id: %d): %v", entity, action, id, err)
+	}
+}
+
+// performer возвращает пользователя запроса для поля performed_by событий
+func performer(c *gin.Context) events.Performer {
+	user, ok := auth.CurrentUser(c)
+	if !ok || user.UserID == "" {
+		return events.Performer{UserID: "anonymous"}
+	}
+	return events.Performer{UserID: user.UserID, Username: user.Username, Role: user.Role}
+}
+
+// castLink — актёр, добавленный в фильм, и его роль для события movie_actor_added
+type castLink struct {
+	ActorID       int
+	CharacterName string
+	BillingOrder  int
+}
+
+// castLinksFromActors собирает события добавления из состава фильма в ответе
+func castLinksFromActors(actors []dto.ActorResponse) []castLink {
+	links := make([]castLink, 0, len(actors))
+	for _, actor := range actors {
+		links = append(links, castLink{ActorID: actor.ID, CharacterName: actor.CharacterName, BillingOrder: actor.BillingOrder})
+	}
+	return links
+}
+
+// castLinksFromPreviews собирает события добавления из краткого состава в ответе с фильмом
+func castLinksFromPreviews(actors []dto.ActorPreview) []castLink {
+	links := make([]castLink, 0, len(actors))
+	for _, actor := range actors {
+		links = append(links, castLink{ActorID: actor.ID, CharacterName: actor.CharacterName})
+	}
+	return links
+}
+
+// publishCastLinks публикует события добавления актёров в фильм и удаления из него.
+// Ключ сообщения — ID фильма, поэтому события одного фильма читаются в порядке отправки.
+// Состав уже сохранён в БД, поэтому ошибка доставки только логируется
+func publishCastLinks(c *gin.Context, bus *eventbus.Bus, movieID int, added []castLink, removed []int) {
+	if bus == nil {
+		return
+	}
+	by := performer(c)
+	linkEvents := make([]*events.MovieActorLink, 0, len(added)+len(removed))
+	for _, link := range added {
+		linkEvents = append(linkEvents, events.NewMovieActorAdded(movieID, link.ActorID, link.CharacterName, link.BillingOrder, by))
+	}
+	for _, actorID := range removed {
+		linkEvents = append(linkEvents, events.NewMovieActorRemoved(movieID, actorID, by))
+	}
+	key := []byte(strconv.Itoa(movieID))
+	for _, event := range linkEvents {
+		if err := publishEvent(c.Request.Context(), bus, MovieActorLinksTopic, key, event); err != nil {
+			log.Printf("Failed to send %s event (movie: %d, actor: %d): %v", event.Type, movieID, event.ActorID, err)
+		}
+	}
+}
+
+// EventsHandler отдаёт поток изменений каталога в формате Server-Sent Events
+type EventsHandler struct {
+	broadcaster *kafka.Broadcaster
+	heartbeat   time.Duration
+
+	closeOnce sync.Once
+	done      chan struct{}
+}
+
+// NewEventsHandler создаёт обработчик (handler) потока событий каталога
+func NewEventsHandler(broadcaster *kafka.Broadcaster) *EventsHandler {
+	return &EventsHandler{
+		broadcaster: broadcaster,
+		heartbeat:   eventsHeartbeat,
+		done:        make(chan struct{}),
+	}
+}
+
+// Close завершает открытые потоки, чтобы они не задерживали остановку сервера
+func (h *EventsHandler) Close() {
+	h.closeOnce.Do(func() { close(h.done) })
+}
+
+// Stream держит соединение открытым и пересылает клиенту события catalog-changes.
+// Имя SSE-события совпадает с полем type (например, movie_created), id — с event_id.
+// Раз в eventsHeartbeat отправляется комментарий, чтобы прокси не закрывали соединение
+func (h *EventsHandler) Stream(c *gin.Context) {
+	events, unsubscribe := h.broadcaster.Subscribe(eventsSubscriberBuffer)
+	defer unsubscribe()
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no") // отключаем буферизацию в nginx
+	c.Status(http.StatusOK)
+	c.Writer.Flush()
+
+	ticker := time.NewTicker(h.heartbeat)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case <-h.done:
+			return
+		case <-ticker.C:
+			fmt.Fprint(c.Writer, ": ping\n\n")
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			var meta struct {
+				Type    string `json:"type"`
+				EventID string `json:"event_id"`
+			}
+			if err := json.Unmarshal(event.Value, &meta); err != nil {
+				log.Printf("Skipping malformed catalog event: %v", err)
+				continue
+			}
+			fmt.Fprintf(c.Writer, "id: %s\nevent: %s\ndata: %s\n\n", meta.EventID, meta.Type, event.Value)
+		}
+		c.Writer.Flush()
+	}
+}
+
+// RegisterEventsRoutes регистрирует поток событий каталога (только для администраторов)
+func RegisterEventsRoutes(router *gin.RouterGroup, handler *EventsHandler) {
+	if handler == nil {
+		return
+	}
+	router.GET("/events", auth.RequireRole(domain.RoleAdmin), handler.Stream)
+}
@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"cinematique/internal/apperror"
+	"cinematique/internal/auth"
+	"cinematique/internal/domain"
+	"cinematique/internal/eventbus"
+	"cinematique/internal/kafka"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// kafkaBus возвращает шину событий, которая, как в приложении, пересылает события в пул продюсеров
+func kafkaBus(pool *kafka.ProducerPool) *eventbus.Bus {
+	bus := eventbus.New()
+	bus.Subscribe("kafka", eventbus.Kafka(pool))
+	return bus
+}
+
+// newEventsServer поднимает сервер с потоком событий для пользователя с ролью role
+func newEventsServer(t *testing.T, handler *EventsHandler, role string) *httptest.Server {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(apperror.Middleware())
+	r.Use(func(c *gin.Context) {
+		auth.SetUser(c, &auth.UserContext{Role: role})
+		c.Next()
+	})
+	RegisterEventsRoutes(r.Group(""), handler)
+	srv := httptest.NewServer(r)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestEventsHandler_StreamsCatalogChanges(t *testing.T) {
+	producer := kafka.NewMockProducer()
+	producer.On("Produce", mock.Anything, CatalogChangesTopic, []byte("movie:7"), mock.Anything).Return(nil).Maybe()
+	producer.On("Close").Return(nil)
+	broadcaster := kafka.NewBroadcaster(CatalogChangesTopic)
+	producerPool := kafka.NewProducerPool(producer, 1, 10, kafka.WithBroadcaster(broadcaster))
+	defer producerPool.Close()
+
+	handler := NewEventsHandler(broadcaster)
+	defer handler.Close()
+	srv := newEventsServer(t, handler, domain.RoleAdmin)
+
+	resp, err := http.Get(srv.URL + "/events")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	require.Eventually(t, func() bool { return broadcaster.Subscribers() == 1 }, time.Second, 10*time.Millisecond)
+
+	// Просмотры не относятся к изменениям каталога и в поток не попадают
+	require.NoError(t, producerPool.Produce("movie-views", []byte("7"), []byte(`{"type":"movie_viewed"}`)))
+	publishCatalogChange(context.Background(), kafkaBus(producerPool), "movie", catalogActionCreated, 7)
+
+	reader := bufio.NewReader(resp.Body)
+	var lines []string
+	for len(lines) < 3 {
+		line, err := reader.ReadString('\n')
+		require.NoError(t, err)
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+
+	assert.True(t, strings.HasPrefix(lines[0], "id: "))
+	assert.Equal(t, "event: movie_created", lines[1])
+	require.True(t, strings.HasPrefix(lines[2], "data: "))
+	var payload map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(strings.TrimPrefix(lines[2], "data: ")), &payload))
+	assert.Equal(t, "movie", payload["entity"])
+	assert.Equal(t, "created", payload["action"])
+	assert.Equal(t, float64(7), payload["id"])
+	assert.Equal(t, strings.TrimPrefix(lines[0], "id: "), payload["event_id"])
+}
+
+func TestEventsHandler_CloseEndsStream(t *testing.T) {
+	broadcaster := kafka.NewBroadcaster(CatalogChangesTopic)
+	handler := NewEventsHandler(broadcaster)
+	srv := newEventsServer(t, handler, domain.RoleAdmin)
+
+	resp, err := http.Get(srv.URL + "/events")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Eventually(t, func() bool { return broadcaster.Subscribers() == 1 }, time.Second, 10*time.Millisecond)
+
+	handler.Close()
+
+	require.Eventually(t, func() bool { return broadcaster.Subscribers() == 0 }, time.Second, 10*time.Millisecond)
+}
+
+func TestEventsHandler_RequiresAdmin(t *testing.T) {
+	srv := newEventsServer(t, NewEventsHandler(kafka.NewBroadcaster(CatalogChangesTopic)), domain.RoleUser)
+
+	resp, err := http.Get(srv.URL + "/events")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+}
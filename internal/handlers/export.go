@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+
+	"cinematique/internal/apperror"
+	"cinematique/internal/controller/dto"
+
+	"github.com/gin-gonic/gin"
+)
+
+// exportFlushEvery — через сколько строк выгрузка отправляется клиенту, не дожидаясь конца
+const exportFlushEvery = 500
+
+var (
+	movieExportHeader = []string{"id", "title", "description", "release_year", "release_date", "rating", "view_count", "original_language", "country", "runtime_minutes"}
+	actorExportHeader = []string{"id", "name", "gender", "birth_date", "photo_url"}
+)
+
+// ExportMovies выгружает весь каталог фильмов в NDJSON (по умолчанию) или CSV (?format=csv)
+func (h *AdminHandler) ExportMovies(c *gin.Context) {
+	streamExport(c, "movies", movieExportHeader, func(m dto.MovieResponse) []string {
+		// Неизвестная продолжительность выгружается пустой ячейкой, как и неизвестный язык
+		runtime := ""
+		if m.RuntimeMinutes > 0 {
+			runtime = strconv.Itoa(m.RuntimeMinutes)
+		}
+		return []string{
+			strconv.Itoa(m.ID), m.Title, m.Description, strconv.Itoa(m.ReleaseYear), m.ReleaseDate,
+			strconv.FormatFloat(m.Rating, 'f', -1, 64), strconv.FormatInt(m.ViewCount, 10),
+			m.OriginalLanguage, m.Country, runtime,
+		}
+	}, func(fn func(dto.MovieResponse) error) error {
+		return h.movieController.ExportMovies(c, fn)
+	})
+}
+
+// ExportActors выгружает всех актёров в NDJSON (по умолчанию) или CSV (?format=csv)
+func (h *AdminHandler) ExportActors(c *gin.Context) {
+	streamExport(c, "actors", actorExportHeader, func(a dto.ActorResponse) []string {
+		return []string{strconv.Itoa(a.ID), a.Name, a.Gender, a.BirthDate, a.PhotoURL}
+	}, func(fn func(dto.ActorResponse) error) error {
+		return h.actorController.ExportActors(c, fn)
+	})
+}
+
+// streamExport пишет записи в ответ по мере чтения из базы. Заголовки ответа отправляются
+// с первой записью: если выгрузка упала раньше, клиент получает обычную ошибку. После начала
+// передачи сменить статус уже нельзя, поэтому ошибка логируется и соединение обрывается
+// без завершающего блока — клиент увидит неполный ответ, а не усечённый, но «успешный» файл
+func streamExport[T any](c *gin.Context, name string, header []string, toRecord func(T) []string, export func(func(T) error) error) {
+	format := c.DefaultQuery("format", "ndjson")
+	if format != "ndjson" && format != "csv" {
+		respondError(c, apperror.Validation("invalid_export_format", "format must be ndjson or csv"))
+		return
+	}
+
+	var (
+		rows    int
+		csvOut  *csv.Writer
+		jsonOut *json.Encoder
+	)
+	start := func() {
+		c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.%s"`, name, format))
+		if format == "csv" {
+			c.Header("Content-Type", "text/csv; charset=utf-8")
+			c.Status(http.StatusOK)
+			csvOut = csv.NewWriter(c.Writer)
+			csvOut.Write(header)
+		} else {
+			c.Header("Content-Type", "application/x-ndjson")
+			c.Status(http.StatusOK)
+			jsonOut = json.NewEncoder(c.Writer)
+		}
+	}
+	flush := func() error {
+		if csvOut != nil {
+			csvOut.Flush()
+			if err := csvOut.Error(); err != nil {
+				return err
+			}
+		}
+		c.Writer.Flush()
+		return nil
+	}
+
+	err := export(func(item T) error {
+		if rows == 0 {
+			start()
+		}
+		rows++
+		var err error
+		if csvOut != nil {
+			err = csvOut.Write(toRecord(item))
+		} else {
+			err = jsonOut.Encode(item)
+		}
+		if err != nil {
+			return err
+		}
+		if rows%exportFlushEvery == 0 {
+			return flush()
+		}
+		return nil
+	})
+	if err != nil {
+		if rows == 0 {
+			respondError(c, err)
+			return
+		}
+		log.Printf("Export of %s aborted after %d rows: %v", name, rows, err)
+		abortConnection(c)
+		return
+	}
+
+	if rows == 0 {
+		start()
+	}
+	if err := flush(); err != nil {
+		log.Printf("Export of %s failed to flush: %v", name, err)
+	}
+}
+
+// abortConnection закрывает соединение посреди ответа, чтобы клиент получил ошибку чтения тела.
+// Hijack у gin паникует, если исходный writer его не поддерживает (HTTP/2, тесты), поэтому
+// проверяем исходный writer; без Hijack ответ просто завершается
+func abortConnection(c *gin.Context) {
+	c.Abort()
+	var w http.ResponseWriter = c.Writer
+	if u, ok := w.(interface{ Unwrap() http.ResponseWriter }); ok {
+		w = u.Unwrap()
+	}
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		return
+	}
+	if conn, _, err := hijacker.Hijack(); err == nil {
+		conn.Close()
+	}
+}
@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"cinematique/internal/apperror"
+	"cinematique/internal/controller/dto"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// newExportRouter регистрирует маршруты выгрузки без проверки роли
+func newExportRouter(actors *MockActorController, movies *MockMovieController) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(apperror.Middleware())
+	handler := NewAdminHandler(actors, movies, nil, nil)
+	r.GET("/admin/export/movies", handler.ExportMovies)
+	r.GET("/admin/export/actors", handler.ExportActors)
+	return r
+}
+
+func TestAdminHandler_ExportMovies(t *testing.T) {
+	movies := []dto.MovieResponse{
+		{ID: 1, Title: "The Matrix", ReleaseYear: 1999, ReleaseDate: "1999-03-31", Rating: 8.7, ViewCount: 12, OriginalLanguage: "en", Country: "US", RuntimeMinutes: 136},
+		{ID: 2, Title: "Heat, the movie", Description: `"Cops" and robbers`, ReleaseYear: 1995, Rating: 8.3},
+	}
+
+	tests := []struct {
+		name            string
+		query           string
+		setupMock       func(*MockMovieController)
+		expectedStatus  int
+		expectedType    string
+		expectedBody    string
+		expectedProblem bool
+	}{
+		{
+			name: "ndjson by default",
+			setupMock: func(m *MockMovieController) {
+				m.On("ExportMovies", mock.Anything).Return(movies, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedType:   "application/x-ndjson",
+			expectedBody: `{"id":1,"title":"The Matrix","description":"","release_year":1999,"release_date":"1999-03-31","rating":8.7,"view_count":12,"original_language":"en","country":"US","runtime_minutes":136}` + "\n" +
+				`{"id":2,"title":"Heat, the movie","description":"\"Cops\" and robbers","release_year":1995,"rating":8.3,"view_count":0}` + "\n",
+		},
+		{
+			name:  "csv with header and quoting",
+			query: "?format=csv",
+			setupMock: func(m *MockMovieController) {
+				m.On("ExportMovies", mock.Anything).Return(movies, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedType:   "text/csv; charset=utf-8",
+			expectedBody: "id,title,description,release_year,release_date,rating,view_count,original_language,country,runtime_minutes\n" +
+				"1,The Matrix,,1999,1999-03-31,8.7,12,en,US,136\n" +
+				`2,"Heat, the movie","""Cops"" and robbers",1995,,8.3,0,,,` + "\n",
+		},
+		{
+			name:  "empty catalog as csv still has header",
+			query: "?format=csv",
+			setupMock: func(m *MockMovieController) {
+				m.On("ExportMovies", mock.Anything).Return([]dto.MovieResponse{}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedType:   "text/csv; charset=utf-8",
+			expectedBody:   "id,title,description,release_year,release_date,rating,view_count,original_language,country,runtime_minutes\n",
+		},
+		{
+			name:            "unknown format",
+			query:           "?format=xml",
+			setupMock:       func(m *MockMovieController) {},
+			expectedStatus:  http.StatusBadRequest,
+			expectedProblem: true,
+		},
+		{
+			name: "error before first row",
+			setupMock: func(m *MockMovieController) {
+				m.On("ExportMovies", mock.Anything).Return([]dto.MovieResponse{}, errors.New("connection refused"))
+			},
+			expectedStatus:  http.StatusInternalServerError,
+			expectedProblem: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			movieCtrl := new(MockMovieController)
+			tt.setupMock(movieCtrl)
+			r := newExportRouter(new(MockActorController), movieCtrl)
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/export/movies"+tt.query, nil))
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedProblem {
+				assert.Equal(t, apperror.ContentType, w.Header().Get("Content-Type"))
+			} else {
+				assert.Equal(t, tt.expectedType, w.Header().Get("Content-Type"))
+				assert.Contains(t, w.Header().Get("Content-Disposition"), "attachment")
+				assert.Equal(t, tt.expectedBody, w.Body.String())
+			}
+			movieCtrl.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAdminHandler_ExportActors(t *testing.T) {
+	actorCtrl := new(MockActorController)
+	actorCtrl.On("ExportActors", mock.Anything).Return([]dto.ActorResponse{
+		{ID: 1, Name: "Keanu Reeves", Gender: "male", BirthDate: "1964-09-02", PhotoURL: "/media/actors/1.jpg"},
+	}, nil)
+	r := newExportRouter(actorCtrl, new(MockMovieController))
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/export/actors?format=csv", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `attachment; filename="actors.csv"`, w.Header().Get("Content-Disposition"))
+	assert.Equal(t, "id,name,gender,birth_date,photo_url\n1,Keanu Reeves,male,1964-09-02,/media/actors/1.jpg\n", w.Body.String())
+}
+
+func TestAdminHandler_ExportBreaksConnectionOnMidStreamError(t *testing.T) {
+	movieCtrl := new(MockMovieController)
+	movieCtrl.On("ExportMovies", mock.Anything).Return([]dto.MovieResponse{{ID: 1, Title: "The Matrix"}}, errors.New("replica went away"))
+	server := httptest.NewServer(newExportRouter(new(MockActorController), movieCtrl))
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/admin/export/movies")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	// Статус уже отправлен, но тело обрывается, и клиент не принимает неполную выгрузку за полную
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	_, err = io.ReadAll(resp.Body)
+	assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+}
@@ -1,20 +1,24 @@
 package handlers
 
 import (
-	"encoding/json" // Добавляем импорт encoding/json
 	"errors"
 	"fmt" // Добавляем импорт fmt
+	"io"
 	"log" // Добавляем импорт log
 	"net/http"
+	"slices"
 	"strconv"
 	"strings" // Добавляем импорт strings
-	"time"    // Добавляем импорт time
 
+	"cinematique/internal/apperror"
 	"cinematique/internal/auth"
 	"cinematique/internal/controller/dto"
 	"cinematique/internal/domain"
-	"cinematique/internal/kafka" // Добавляем импорт kafka
+	"cinematique/internal/eventbus"
+	"cinematique/internal/idempotency"
+	"cinematique/internal/kafka/events"
 	"cinematique/internal/keycloak"
+	"cinematique/internal/storage"
 
 	"github.com/gin-gonic/gin"
 	"github.com/prometheus/client_golang/prometheus"
@@ -44,80 +48,168 @@ func init() {
 type ActorController interface {
 	CreateActor(c *gin.Context, req dto.CreateActorRequest) (dto.ActorResponse, error)
 	GetActorByID(c *gin.Context, id int) (dto.ActorResponse, error)
+	GetActorBySlug(c *gin.Context, slug string) (dto.ActorResponse, error)
+	GetActorStats(c *gin.Context, id int) (dto.ActorStatsResponse, error)
+	GetActorHistory(c *gin.Context, id int) (dto.ActorHistoryResponse, error)
 	UpdateActor(c *gin.Context, id int, req dto.UpdateActorRequest) (dto.ActorResponse, error)
 	DeleteActor(c *gin.Context, id int) error
 	ListActors(c *gin.Context) (dto.ActorsListResponse, error)
+	SearchActorsByName(c *gin.Context) (dto.ActorsListResponse, error)
+	SuggestActors(c *gin.Context) (dto.ActorSuggestionsResponse, error)
+	ListActorsByBirthMonth(c *gin.Context) (dto.ActorsListResponse, error)
 	GetAllActorsWithMovies(c *gin.Context) (dto.ActorsWithFilmsListResponse, error)
 	PartialUpdateActor(c *gin.Context, id int, update dto.ActorUpdate) (dto.ActorResponse, error)
+	MergeActors(c *gin.Context, keepID, dupID int) (dto.ActorMergeResponse, error)
+	ListOrphanActors(c *gin.Context) (dto.OrphanActorsResponse, error)
+	PurgeOrphanActors(c *gin.Context, req dto.PurgeOrphanActorsRequest) (dto.PurgeOrphanActorsResponse, error)
+	UploadActorPhoto(c *gin.Context, id int, data []byte) (dto.ActorResponse, error)
+	ExportActors(c *gin.Context, fn func(dto.ActorResponse) error) error
+	ImportActorsCSV(c *gin.Context, r io.Reader, dryRun bool) (dto.ActorImportResponse, error)
 }
 
 // MovieController описывает методы для работы с фильмами
 type MovieController interface {
 	CreateMovie(c *gin.Context, req dto.CreateMovieRequest) (dto.MovieResponse, error)
 	GetMovieByID(c *gin.Context, id int) (dto.MovieResponse, error)
+	GetMovieBySlug(c *gin.Context, slug string) (dto.MovieResponse, error)
+	GetMovieByIDAsOf(c *gin.Context, id int, asOf string) (dto.MovieResponse, error)
 	UpdateMovie(c *gin.Context, id int, req dto.UpdateMovieRequest) (dto.MovieResponse, error)
 	DeleteMovie(c *gin.Context, id int) error
+	DeleteCreatedMovie(c *gin.Context, movieID int, createdActorIDs []int) error
 	ListMovies(c *gin.Context) (dto.MoviesListResponse, error)
 	SearchMoviesByTitle(c *gin.Context) (dto.MoviesListResponse, error)
 	SearchMoviesByActorName(c *gin.Context) (dto.MoviesListResponse, error)
 	GetAllMoviesSorted(c *gin.Context) (dto.MoviesListResponse, error)
 	CreateMovieWithActors(c *gin.Context, req dto.MovieWithActorsRequest) (dto.MovieResponse, error)
+	CreateMovieFull(c *gin.Context, req dto.FullMovieRequest) (dto.FullMovieResponse, error)
 	UpdateMovieActors(c *gin.Context, movieID int, req dto.UpdateMovieActorsRequest) (dto.MovieActorsResponse, error)
-	AddActorToMovie(c *gin.Context, movieID, actorID int) (dto.MovieResponse, error)
+	ChangeMovieActors(c *gin.Context, movieID int, req dto.ChangeMovieActorsRequest) (dto.MovieActorsResponse, error)
+	ReorderMovieActors(c *gin.Context, movieID int, req dto.ReorderMovieActorsRequest) (dto.MovieActorsResponse, error)
+	AddActorToMovie(c *gin.Context, movieID, actorID int, req dto.AddActorToMovieRequest) (dto.MovieResponse, error)
 	RemoveActorFromMovie(c *gin.Context, movieID, actorID int) (dto.MovieResponse, error)
 	GetActorsForMovieByID(c *gin.Context, movieID int) (dto.MovieActorsResponse, error)
+	GetMovieCast(c *gin.Context, movieID int) (dto.MovieCastResponse, error)
+	GetRatingHistory(c *gin.Context, movieID int) (dto.RatingHistoryResponse, error)
+	GetMovieHistory(c *gin.Context, movieID int) (dto.MovieHistoryResponse, error)
+	GetMovieRatings(c *gin.Context, movieID int) (dto.MovieRatingsResponse, error)
+	SetMovieRating(c *gin.Context, movieID int, source string, req dto.MovieRatingRequest) (dto.MovieRatingsResponse, error)
+	DeleteMovieRating(c *gin.Context, movieID int, source string) error
+	GetMovieTags(c *gin.Context, movieID int) (dto.MovieTagsResponse, error)
+	AddMovieTag(c *gin.Context, movieID int, tag string) (dto.MovieTagsResponse, error)
+	RemoveMovieTag(c *gin.Context, movieID int, tag string) error
+	GetMoviesByTag(c *gin.Context, tag string) (dto.MoviesByTagResponse, error)
+	SuggestTags(c *gin.Context) (dto.TagSuggestionsResponse, error)
+	GetAvailability(c *gin.Context, movieID int) (dto.AvailabilityListResponse, error)
+	AddAvailability(c *gin.Context, movieID int, req dto.AvailabilityRequest) (dto.AvailabilityResponse, error)
+	UpdateAvailability(c *gin.Context, movieID, windowID int, req dto.AvailabilityRequest) (dto.AvailabilityResponse, error)
+	DeleteAvailability(c *gin.Context, movieID, windowID int) error
 	GetMoviesForActor(c *gin.Context, actorID int) (dto.ActorMoviesResponse, error)
-	PartialUpdateMovie(c *gin.Context, id int, update dto.MovieUpdate) error
+	PartialUpdateMovie(c *gin.Context, id int, update dto.MovieUpdate) (dto.MovieResponse, error)
+	MergeMovies(c *gin.Context, req dto.MergeMoviesRequest) (dto.MovieMergeResponse, error)
+	BulkDeleteMovies(c *gin.Context, req dto.BulkDeleteMoviesRequest) (dto.BulkOperationResponse, error)
+	BulkUpdateMovies(c *gin.Context, req dto.BulkUpdateMoviesRequest) (dto.BulkOperationResponse, error)
+	ResolveMergedMovieID(c *gin.Context, id int) (int, error)
+	GetUpcomingMovies(c *gin.Context) (dto.MoviesListResponse, error)
+	GetPopularMovies(c *gin.Context) (dto.MoviesListResponse, error)
+	GetMoviesByYear(c *gin.Context, year string) (dto.MoviesByPeriodResponse, error)
+	GetMoviesByDecade(c *gin.Context, decade string) (dto.MoviesByPeriodResponse, error)
+	ImportExternalMovie(c *gin.Context, imdbID string) (dto.MovieImportResponse, error)
+	UpsertMovieByExternalID(c *gin.Context, externalID string, req dto.CreateMovieRequest) (dto.MovieUpsertResponse, error)
+	ExportMovies(c *gin.Context, fn func(dto.MovieResponse) error) error
 }
 
 // Структуры
 type ActorHandler struct {
 	controller ActorController
+	bus        *eventbus.Bus
 }
 
 type MovieHandler struct {
 	controller   MovieController
-	producerPool *kafka.ProducerPool // Используем пул продюсеров
+	bus          *eventbus.Bus      // шина доменных событий; nil — события не публикуются
+	idempotency  *idempotency.Guard // ключи идемпотентности для создания фильмов; nil — выключены
+	strictEvents bool               // без события movie_created фильм не создаётся
 }
 
 // NewActorHandler создаёт обработчик (handler) для актёров
-func NewActorHandler(controller ActorController) *ActorHandler {
-	return &ActorHandler{controller: controller}
+func NewActorHandler(controller ActorController, bus *eventbus.Bus) *ActorHandler {
+	return &ActorHandler{controller: controller, bus: bus}
 }
 
 // NewMovieHandler создаёт обработчик (handler) для фильмов
-func NewMovieHandler(controller MovieController, producerPool *kafka.ProducerPool) *MovieHandler {
-	return &MovieHandler{controller: controller, producerPool: producerPool}
+func NewMovieHandler(controller MovieController, bus *eventbus.Bus) *MovieHandler {
+	return &MovieHandler{controller: controller, bus: bus}
+}
+
+// WithIdempotency включает заголовок Idempotency-Key для создания фильмов
+func (h *MovieHandler) WithIdempotency(guard *idempotency.Guard) *MovieHandler {
+	h.idempotency = guard
+	return h
+}
+
+// WithStrictEvents включает строгий режим событий: если событие movie_created не удалось
+// поставить в очередь, созданный фильм удаляется, а клиент получает 503
+func (h *MovieHandler) WithStrictEvents(enabled bool) *MovieHandler {
+	h.strictEvents = enabled
+	return h
+}
+
+// Ошибки разбора запроса, общие для обработчиков
+var (
+	errInvalidID      = apperror.Validation("invalid_id", "invalid id")
+	errInvalidRequest = apperror.Validation("invalid_request", "invalid request")
+)
+
+// respondError передаёт ошибку apperror.Middleware, который отвечает application/problem+json
+// со статусом и кодом, соответствующими типу ошибки
+func respondError(c *gin.Context, err error) {
+	_ = c.Error(err)
+}
+
+// respond завершает обработчик результатом вызова контроллера. Ошибка уходит в respondError,
+// и статус по её типу выбирает apperror.Middleware; без ошибки result отдаётся со статусом status,
+// а nil — пустым ответом (например, 204 No Content)
+func respond(c *gin.Context, status int, result interface{}, err error) {
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	if result == nil {
+		c.Status(status)
+		return
+	}
+	c.JSON(status, result)
 }
 
 // Методы ActorHandler ---
 // Create создаёт актёра
 func (h *ActorHandler) Create(c *gin.Context) {
 	var req dto.CreateActorRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request"})
+	if err := bindJSON(c, &req); err != nil {
+		respondError(c, err)
 		return
 	}
 
 	// Validate required fields
 	if req.Name == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Name is required"})
+		respondError(c, apperror.Validation("field_required", "Name is required"))
 		return
 	}
 	if req.Gender == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Gender is required"})
+		respondError(c, apperror.Validation("field_required", "Gender is required"))
 		return
 	}
 	if req.BirthDate == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "BirthDate is required"})
+		respondError(c, apperror.Validation("field_required", "BirthDate is required"))
 		return
 	}
 
 	resp, err := h.controller.CreateActor(c, req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, err)
 		return
 	}
+	publishCatalogChange(c.Request.Context(), h.bus, "actor", catalogActionCreated, resp.ID)
 	c.JSON(http.StatusCreated, resp)
 }
 
@@ -125,38 +217,73 @@ func (h *ActorHandler) Create(c *gin.Context) {
 func (h *ActorHandler) GetByID(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		respondError(c, errInvalidID)
 		return
 	}
 	resp, err := h.controller.GetActorByID(c, id)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		respondError(c, err)
 		return
 	}
-	c.JSON(http.StatusOK, resp)
+	if respondNotModifiedSince(c, resp.UpdatedAt) {
+		return
+	}
+	respondWithETag(c, resp)
+}
+
+// Stats возвращает сводку по фильмам актёра
+func (h *ActorHandler) Stats(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		respondError(c, errInvalidID)
+		return
+	}
+	resp, err := h.controller.GetActorStats(c, id)
+	respond(c, http.StatusOK, resp, err)
+}
+
+// History возвращает версии профиля актёра с отличиями между ними
+func (h *ActorHandler) History(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		respondError(c, errInvalidID)
+		return
+	}
+	resp, err := h.controller.GetActorHistory(c, id)
+	respond(c, http.StatusOK, resp, err)
+}
+
+// GetBySlug возвращает актёра по slug
+func (h *ActorHandler) GetBySlug(c *gin.Context) {
+	resp, err := h.controller.GetActorBySlug(c, c.Param("slug"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	if respondNotModifiedSince(c, resp.UpdatedAt) {
+		return
+	}
+	respondWithETag(c, resp)
 }
 
 // Update обновляет актёра
 func (h *ActorHandler) Update(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		respondError(c, errInvalidID)
 		return
 	}
 	var req dto.UpdateActorRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request"})
+	if err := bindJSON(c, &req); err != nil {
+		respondError(c, err)
 		return
 	}
 	resp, err := h.controller.UpdateActor(c, id, req)
 	if err != nil {
-		if errors.Is(err, domain.ErrActorNotFound) {
-			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
-		} else {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		}
+		respondError(c, err)
 		return
 	}
+	publishCatalogChange(c.Request.Context(), h.bus, "actor", catalogActionUpdated, id)
 	c.JSON(http.StatusOK, resp)
 }
 
@@ -167,49 +294,38 @@ func (h *ActorHandler) PartialUpdate(c *gin.Context) {
 	// Получаем ID актера из URL
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		errMsg := "invalid id"
-		log.Printf("Error: %s", errMsg)
-		c.JSON(http.StatusBadRequest, gin.H{"error": errMsg})
+		log.Printf("Error: %v", errInvalidID)
+		respondError(c, errInvalidID)
 		return
 	}
 	log.Printf("Updating actor with ID: %d", id)
 
 	// Парсим тело запроса
 	var update dto.ActorUpdate
-	if err := c.ShouldBindJSON(&update); err != nil {
-		errMsg := "invalid request body: " + err.Error()
-		log.Printf("Error: %s", errMsg)
-		c.JSON(http.StatusBadRequest, gin.H{"error": errMsg})
+	if err := bindJSON(c, &update); err != nil {
+		log.Printf("Error: invalid request body: %v", err)
+		respondError(c, err)
 		return
 	}
 	log.Printf("Update data: %+v", update)
 
 	// Проверяем, что хотя бы одно поле для обновления указано
-	if update.Name == nil && update.Gender == nil && update.BirthDate == nil {
-		errMsg := "no fields to update"
-		log.Printf("Error: %s", errMsg)
-		c.JSON(http.StatusBadRequest, gin.H{"error": errMsg})
+	if update.Name == nil && update.Gender == nil && update.BirthDate == nil && update.DeathDate == nil {
+		log.Printf("Error: %v", domain.ErrNoFieldsToUpdate)
+		respondError(c, domain.ErrNoFieldsToUpdate)
 		return
 	}
 
 	// Вызываем метод контроллера для обновления актера
 	updatedActor, err := h.controller.PartialUpdateActor(c, id, update)
 	if err != nil {
-		errMsg := fmt.Sprintf("Error updating actor: %v", err)
-		log.Printf("Error: %s", errMsg)
-
-		switch {
-		case errors.Is(err, domain.ErrActorNotFound):
-			c.JSON(http.StatusNotFound, gin.H{"error": "actor not found"})
-		case strings.Contains(err.Error(), "validation error"):
-			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		default:
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
-		}
+		log.Printf("Error updating actor: %v", err)
+		respondError(c, err)
 		return
 	}
 
 	log.Printf("Successfully updated actor with ID: %d", id)
+	publishCatalogChange(c.Request.Context(), h.bus, "actor", catalogActionUpdated, id)
 
 	// Возвращаем обновленные данные актера
 	c.JSON(http.StatusOK, updatedActor)
@@ -222,80 +338,123 @@ func (h *ActorHandler) Delete(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
 		log.Printf("Error parsing id: %v", err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		respondError(c, errInvalidID)
 		return
 	}
 
 	fmt.Printf("=== Starting Delete handler for actor ID: %d ===\n", id)
 	err = h.controller.DeleteActor(c, id)
 	if err != nil {
-		errMsg := err.Error()
-		fmt.Printf("=== Error in Delete handler: %v, type: %T ===\n", errMsg, err)
-
-		switch {
-		case errors.Is(err, domain.ErrActorNotFound):
-			fmt.Println("=== Returning 404 Not Found ===")
-			c.JSON(http.StatusNotFound, gin.H{"error": errMsg})
-		case strings.Contains(errMsg, "cannot delete actor: has") && strings.Contains(errMsg, "related movies"):
-			fmt.Println("=== Returning 409 Conflict ===")
-			c.JSON(http.StatusConflict, gin.H{"error": errMsg})
-		default:
-			fmt.Println("=== Returning 500 Internal Server Error ===")
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
-		}
+		fmt.Printf("=== Error in Delete handler: %v, type: %T ===\n", err, err)
+		respondError(c, err)
 		return
 	}
 
 	fmt.Println("=== Actor deleted successfully, returning 204 No Content ===")
+	publishCatalogChange(c.Request.Context(), h.bus, "actor", catalogActionDeleted, id)
 	c.Status(http.StatusNoContent)
 }
 
-// List возвращает всех актёров
-func (h *ActorHandler) List(c *gin.Context) {
-	resp, err := h.controller.ListActors(c)
+// UploadPhoto принимает фотографию актёра (multipart, поле photo)
+func (h *ActorHandler) UploadPhoto(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, errInvalidID)
 		return
 	}
-	c.JSON(http.StatusOK, resp)
+	file, err := c.FormFile("photo")
+	if err != nil {
+		respondError(c, apperror.Validation("field_required", "photo file is required"))
+		return
+	}
+	f, err := file.Open()
+	if err != nil {
+		respondError(c, apperror.Validation("invalid_request", "cannot read photo"))
+		return
+	}
+	defer f.Close()
+
+	// Читаем на байт больше лимита, чтобы контроллер мог отличить слишком большой файл
+	data, err := io.ReadAll(io.LimitReader(f, storage.MaxImageSize+1))
+	if err != nil {
+		respondError(c, apperror.Validation("invalid_request", "cannot read photo"))
+		return
+	}
+
+	resp, err := h.controller.UploadActorPhoto(c, id, data)
+	respond(c, http.StatusOK, resp, err)
+}
+
+// List возвращает всех актёров
+func (h *ActorHandler) List(c *gin.Context) {
+	resp, err := h.controller.ListActors(c)
+	respond(c, http.StatusOK, resp, err)
+}
+
+// Search ищет актёров по фрагменту имени
+func (h *ActorHandler) Search(c *gin.Context) {
+	resp, err := h.controller.SearchActorsByName(c)
+	respond(c, http.StatusOK, resp, err)
+}
+
+// Suggest возвращает подсказки имён актёров для автодополнения
+func (h *ActorHandler) Suggest(c *gin.Context) {
+	resp, err := h.controller.SuggestActors(c)
+	respond(c, http.StatusOK, resp, err)
+}
+
+// Birthdays возвращает актёров, родившихся в месяце ?month=
+func (h *ActorHandler) Birthdays(c *gin.Context) {
+	resp, err := h.controller.ListActorsByBirthMonth(c)
+	respond(c, http.StatusOK, resp, err)
 }
 
 // ListWithMovies возвращает актёров с фильмами
 func (h *ActorHandler) ListWithMovies(c *gin.Context) {
 	resp, err := h.controller.GetAllActorsWithMovies(c)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
-	}
-	c.JSON(http.StatusOK, resp)
+	respond(c, http.StatusOK, resp, err)
 }
 
 // --- Методы MovieHandler ---
 // Create создаёт фильм
 func (h *MovieHandler) Create(c *gin.Context) {
 	var req dto.CreateMovieRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request"})
+	if err := bindJSON(c, &req); err != nil {
+		respondError(c, err)
 		return
 	}
 
 	// Валидация обязательных полей
 	if req.Title == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Title is required"})
+		respondError(c, apperror.Validation("field_required", "Title is required"))
 		return
 	}
 	if req.Description == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Description is required"})
+		respondError(c, apperror.Validation("field_required", "Description is required"))
 		return
 	}
 	if req.Rating < 0 || req.Rating > 10 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Rating must be between 0 and 10"})
+		respondError(c, apperror.Validation("rating_out_of_range", "Rating must be between 0 and 10"))
 		return
 	}
 
+	// ?force=true отключает проверку на дубликаты
+	if force := c.Query("force"); force != "" {
+		parsed, err := strconv.ParseBool(force)
+		if err != nil {
+			respondError(c, apperror.Validation("invalid_parameter", "invalid force parameter"))
+			return
+		}
+		req.Force = parsed
+	}
+
 	resp, err := h.controller.CreateMovie(c, req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, err)
+		return
+	}
+	if err := publishMovieCreated(c, h.bus, h.strictEvents, h.controller, resp.ID, nil); err != nil {
+		respondError(c, err)
 		return
 	}
 	c.JSON(http.StatusCreated, resp)
@@ -305,48 +464,137 @@ func (h *MovieHandler) Create(c *gin.Context) {
 func (h *MovieHandler) GetByID(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		respondError(c, errInvalidID)
+		return
+	}
+	projection, err := movieProjection(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	if asOf := c.Query("as_of"); asOf != "" {
+		h.getByIDAsOf(c, id, asOf, projection)
 		return
 	}
 	resp, err := h.controller.GetMovieByID(c, id)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		// Фильм мог быть слит с другим — перенаправляем на актуальный ID
+		if errors.Is(err, domain.ErrMovieNotFound) {
+			if newID, resolveErr := h.controller.ResolveMergedMovieID(c, id); resolveErr == nil {
+				location := strings.TrimSuffix(c.Request.URL.Path, c.Param("id")) + strconv.Itoa(newID)
+				c.Redirect(http.StatusMovedPermanently, location)
+				return
+			}
+		}
+		respondError(c, err)
 		return
 	}
+	h.respondViewed(c, resp, projection)
+}
+
+// GetBySlug возвращает фильм по slug. Открытие по slug считается просмотром, как и по ID
+func (h *MovieHandler) GetBySlug(c *gin.Context) {
+	projection, err := movieProjection(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	resp, err := h.controller.GetMovieBySlug(c, c.Param("slug"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	h.respondViewed(c, resp, projection)
+}
+
+// respondViewed учитывает просмотр фильма и отдаёт его с ETag и Last-Modified
+func (h *MovieHandler) respondViewed(c *gin.Context, resp dto.MovieResponse, projection dto.Projection) {
 	moviesViewedTotal.Inc() // Увеличиваем счетчик при просмотре фильма
 
-	// Отправляем событие просмотра фильма в Kafka
-	event := map[string]interface{}{
-		"type":      "movie_viewed",
-		"movie_id":  id,
-		"timestamp": time.Now().Format(time.RFC3339),
+	// Публикуем событие просмотра фильма
+	publishEvent(c.Request.Context(), h.bus, "movie-views", []byte(strconv.Itoa(resp.ID)), events.NewMovieViewed(resp.ID))
+
+	if respondNotModifiedSince(c, resp.UpdatedAt) {
+		return
 	}
-	eventBytes, _ := json.Marshal(event)
-	h.producerPool.Produce("movie-views", []byte(strconv.Itoa(id)), eventBytes)
+	body, err := projection.Apply(resp)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	respondWithETag(c, body)
+}
 
-	c.JSON(http.StatusOK, resp)
+// getByIDAsOf возвращает фильм в состоянии на указанную дату (для редакционного аудита).
+// Исторический запрос не считается просмотром и не отправляет событие в Kafka
+func (h *MovieHandler) getByIDAsOf(c *gin.Context, id int, asOf string, projection dto.Projection) {
+	resp, err := h.controller.GetMovieByIDAsOf(c, id, asOf)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	body, err := projection.Apply(resp)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	respondWithETag(c, body)
+}
+
+// movieProjection разбирает параметр fields — список полей фильма, которые нужно вернуть
+func movieProjection(c *gin.Context) (dto.Projection, error) {
+	projection, err := dto.ParseProjection(c.Query("fields"), dto.MovieFields)
+	if err != nil {
+		return nil, fmt.Errorf("validation error: %w", err)
+	}
+	return projection, nil
 }
 
 // Update обновляет фильм
 func (h *MovieHandler) Update(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		respondError(c, errInvalidID)
 		return
 	}
 	var req dto.UpdateMovieRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request"})
+	if err := bindJSON(c, &req); err != nil {
+		respondError(c, err)
 		return
 	}
 	resp, err := h.controller.UpdateMovie(c, id, req)
 	if err != nil {
-		if err.Error() == "movie not found" {
-			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
-		} else {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, err)
+		return
+	}
+	publishCatalogChange(c.Request.Context(), h.bus, "movie", catalogActionUpdated, id)
+	c.JSON(http.StatusOK, resp)
+}
+
+// UpsertByExternalID создаёт или обновляет фильм по ID в каталоге-источнике. Созданный фильм
+// отдаётся с кодом 201, остальные — с 200; событие об изменении отправляется, только если
+// фильм действительно изменился
+func (h *MovieHandler) UpsertByExternalID(c *gin.Context) {
+	var req dto.CreateMovieRequest
+	if err := bindJSON(c, &req); err != nil {
+		respondError(c, err)
+		return
+	}
+	resp, err := h.controller.UpsertMovieByExternalID(c, c.Param("externalId"), req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	switch domain.MovieUpsertAction(resp.Action) {
+	case domain.MovieUpsertCreated:
+		if err := publishMovieCreated(c, h.bus, h.strictEvents, h.controller, resp.Movie.ID, nil); err != nil {
+			respondError(c, err)
+			return
 		}
+		c.JSON(http.StatusCreated, resp)
 		return
+	case domain.MovieUpsertUpdated:
+		publishCatalogChange(c.Request.Context(), h.bus, "movie", catalogActionUpdated, resp.Movie.ID)
 	}
 	c.JSON(http.StatusOK, resp)
 }
@@ -355,117 +603,207 @@ func (h *MovieHandler) Update(c *gin.Context) {
 func (h *MovieHandler) PartialUpdate(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		respondError(c, errInvalidID)
 		return
 	}
 	var update dto.MovieUpdate
-	if err := c.ShouldBindJSON(&update); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+	if err := bindJSON(c, &update); err != nil {
+		respondError(c, err)
 		return
 	}
-	if err := h.controller.PartialUpdateMovie(c, id, update); err != nil {
-		if err.Error() == "movie not found" {
-			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
-		} else {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		}
+	updatedMovie, err := h.controller.PartialUpdateMovie(c, id, update)
+	if err != nil {
+		respondError(c, err)
 		return
 	}
-	c.Status(http.StatusOK)
+	publishCatalogChange(c.Request.Context(), h.bus, "movie", catalogActionUpdated, id)
+	c.JSON(http.StatusOK, updatedMovie)
 }
 
 // Delete удаляет фильм
 func (h *MovieHandler) Delete(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		respondError(c, errInvalidID)
 		return
 	}
 	err = h.controller.DeleteMovie(c, id)
 	if err != nil {
-		if err.Error() == "movie not found" {
-			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
-		} else {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		}
+		respondError(c, err)
 		return
 	}
+	publishCatalogChange(c.Request.Context(), h.bus, "movie", catalogActionDeleted, id)
 	c.Status(http.StatusNoContent)
 }
 
 // List возвращает все фильмы
 func (h *MovieHandler) List(c *gin.Context) {
+	projection, err := movieProjection(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
 	resp, err := h.controller.ListMovies(c)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, err)
 		return
 	}
-	c.JSON(http.StatusOK, resp)
+	body, err := projection.ApplyMovies(resp)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	respondWithETag(c, body)
 }
 
-// Search ищет фильмы по названию или имени актёра
+// Search ищет фильмы по названию или имени актёра; language и country сужают результаты.
+// Поиск только по language и/или country возвращает все подходящие фильмы
 func (h *MovieHandler) Search(c *gin.Context) {
 	title := c.Query("title")
 	actorName := c.Query("actorName")
+	filtered := c.Query("language") != "" || c.Query("country") != "" ||
+		c.Query("runtime_min") != "" || c.Query("runtime_max") != ""
+	projection, err := movieProjection(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
 
 	var resp dto.MoviesListResponse
-	var err error
 
 	if title != "" {
 		resp, err = h.controller.SearchMoviesByTitle(c)
 	} else if actorName != "" {
 		resp, err = h.controller.SearchMoviesByActorName(c)
+	} else if filtered {
+		resp, err = h.controller.SearchMoviesByTitle(c)
 	} else {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "at least one search parameter (title or actorName) is required"})
+		respondError(c, apperror.Validation("search_parameter_required", "at least one search parameter (title, actorName, language, country, runtime_min or runtime_max) is required"))
 		return
 	}
 
 	if err != nil {
-		// Check for specific errors from the controller indicating missing parameters
-		if strings.Contains(err.Error(), "parameter is required") {
-			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		} else {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		}
+		respondError(c, err)
 		return
 	}
 
-	// Отправляем событие поиска фильма в Kafka
-	event := map[string]interface{}{
-		"type":      "movie_searched",
-		"query":     c.Request.URL.Query(),
-		"timestamp": time.Now().Format(time.RFC3339),
+	// Публикуем событие поиска фильма
+	event := events.NewMovieSearched(c.Request.URL.Query(), len(resp.Movies))
+	publishEvent(c.Request.Context(), h.bus, "movie-searches", []byte(c.Request.URL.RawQuery), event)
+
+	body, err := projection.ApplyMovies(resp)
+	respond(c, http.StatusOK, body, err)
+}
+
+// Upcoming возвращает фильмы с датой выхода в будущем
+func (h *MovieHandler) Upcoming(c *gin.Context) {
+	projection, err := movieProjection(c)
+	if err != nil {
+		respondError(c, err)
+		return
 	}
-	eventBytes, _ := json.Marshal(event)
-	h.producerPool.Produce("movie-searches", []byte(c.Request.URL.RawQuery), eventBytes)
+	resp, err := h.controller.GetUpcomingMovies(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	body, err := projection.ApplyMovies(resp)
+	respond(c, http.StatusOK, body, err)
+}
 
-	c.JSON(http.StatusOK, resp)
+// Popular возвращает самые просматриваемые фильмы
+func (h *MovieHandler) Popular(c *gin.Context) {
+	projection, err := movieProjection(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	resp, err := h.controller.GetPopularMovies(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	body, err := projection.ApplyMovies(resp)
+	respond(c, http.StatusOK, body, err)
+}
+
+// ByYear возвращает страницу фильмов, вышедших в году :year, и их общее число
+func (h *MovieHandler) ByYear(c *gin.Context) {
+	resp, err := h.controller.GetMoviesByYear(c, c.Param("year"))
+	respond(c, http.StatusOK, resp, err)
+}
+
+// ByDecade возвращает страницу фильмов десятилетия :decade (1990 или 1990s) и их общее число
+func (h *MovieHandler) ByDecade(c *gin.Context) {
+	resp, err := h.controller.GetMoviesByDecade(c, c.Param("decade"))
+	respond(c, http.StatusOK, resp, err)
 }
 
 // ListSorted возвращает отсортированные фильмы
 func (h *MovieHandler) ListSorted(c *gin.Context) {
+	projection, err := movieProjection(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
 	resp, err := h.controller.GetAllMoviesSorted(c)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, err)
 		return
 	}
-	c.JSON(http.StatusOK, resp)
+	body, err := projection.ApplyMovies(resp)
+	respond(c, http.StatusOK, body, err)
 }
 
 // CreateWithActors создаёт фильм с актёрами
 func (h *MovieHandler) CreateWithActors(c *gin.Context) {
 	var req dto.MovieWithActorsRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request"})
+	if err := bindJSON(c, &req); err != nil {
+		respondError(c, err)
 		return
 	}
 
 	resp, err := h.controller.CreateMovieWithActors(c, req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, err)
 		return
 	}
+	if err := publishMovieCreated(c, h.bus, h.strictEvents, h.controller, resp.ID, nil); err != nil {
+		respondError(c, err)
+		return
+	}
+	publishCastLinks(c, h.bus, resp.ID, castLinksFromPreviews(resp.Actors), nil)
+
+	c.JSON(http.StatusCreated, resp)
+}
+
+// CreateFull создаёт фильм вместе с составом: актёры передаются целиком, недостающие
+// в каталоге создаются в той же транзакции
+func (h *MovieHandler) CreateFull(c *gin.Context) {
+	var req dto.FullMovieRequest
+	if err := bindJSON(c, &req); err != nil {
+		respondError(c, err)
+		return
+	}
+	if force := c.Query("force"); force != "" {
+		parsed, err := strconv.ParseBool(force)
+		if err != nil {
+			respondError(c, apperror.Validation("invalid_parameter", "invalid force parameter"))
+			return
+		}
+		req.Force = parsed
+	}
 
+	resp, err := h.controller.CreateMovieFull(c, req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	if err := publishMovieCreated(c, h.bus, h.strictEvents, h.controller, resp.Movie.ID, resp.CreatedActorIDs); err != nil {
+		respondError(c, err)
+		return
+	}
+	publishCastLinks(c, h.bus, resp.Movie.ID, castLinksFromActors(resp.Actors), nil)
 	c.JSON(http.StatusCreated, resp)
 }
 
@@ -473,45 +811,118 @@ func (h *MovieHandler) CreateWithActors(c *gin.Context) {
 func (h *MovieHandler) UpdateMovieActors(c *gin.Context) {
 	movieID, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid movie id"})
+		respondError(c, apperror.Validation("invalid_id", "invalid movie id"))
 		return
 	}
 
 	var req dto.UpdateMovieActorsRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request"})
+	if err := bindJSON(c, &req); err != nil {
+		respondError(c, err)
 		return
 	}
 
 	resp, err := h.controller.UpdateMovieActors(c, movieID, req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, err)
+		return
+	}
+
+	publishCastChange(c, h.bus, movieID, resp)
+	c.JSON(http.StatusOK, resp)
+}
+
+// ChangeMovieActors добавляет актёров из add и убирает актёров из remove одной транзакцией,
+// не заменяя остальной состав фильма
+func (h *MovieHandler) ChangeMovieActors(c *gin.Context) {
+	movieID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		respondError(c, apperror.Validation("invalid_id", "invalid movie id"))
+		return
+	}
+
+	var req dto.ChangeMovieActorsRequest
+	if err := bindJSON(c, &req); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	resp, err := h.controller.ChangeMovieActors(c, movieID, req)
+	if err != nil {
+		respondError(c, err)
 		return
 	}
 
+	publishCastChange(c, h.bus, movieID, resp)
 	c.JSON(http.StatusOK, resp)
 }
 
+// publishCastChange публикует события по актёрам, которых изменение состава добавило и убрало
+func publishCastChange(c *gin.Context, bus *eventbus.Bus, movieID int, resp dto.MovieActorsResponse) {
+	added := make([]dto.ActorResponse, 0, len(resp.AddedActorIDs))
+	for _, actor := range resp.Actors {
+		if slices.Contains(resp.AddedActorIDs, actor.ID) {
+			added = append(added, actor)
+		}
+	}
+	publishCastLinks(c, bus, movieID, castLinksFromActors(added), resp.RemovedActorIDs)
+}
+
+// ReorderActors задаёт порядок актёров фильма в титрах
+func (h *MovieHandler) ReorderActors(c *gin.Context) {
+	movieID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		respondError(c, apperror.Validation("invalid_id", "invalid movie id"))
+		return
+	}
+
+	var req dto.ReorderMovieActorsRequest
+	if err := bindJSON(c, &req); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	resp, err := h.controller.ReorderMovieActors(c, movieID, req)
+	respond(c, http.StatusOK, resp, err)
+}
+
 // AddActorToMovie добавляет актёра в фильм
 func (h *MovieHandler) AddActorToMovie(c *gin.Context) {
 	movieID, err := strconv.Atoi(c.Param("movieId"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid movie id"})
+		respondError(c, apperror.Validation("invalid_id", "invalid movie id"))
 		return
 	}
 
 	actorID, err := strconv.Atoi(c.Param("actorId"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid actor id"})
+		respondError(c, apperror.Validation("invalid_id", "invalid actor id"))
 		return
 	}
 
-	resp, err := h.controller.AddActorToMovie(c, movieID, actorID)
+	// Тело с ролью и местом в титрах необязательно
+	var req dto.AddActorToMovieRequest
+	if c.Request.ContentLength != 0 {
+		if err := bindJSON(c, &req); err != nil {
+			respondError(c, err)
+			return
+		}
+	}
+
+	resp, err := h.controller.AddActorToMovie(c, movieID, actorID, req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, err)
 		return
 	}
 
+	// Роль берётся из сохранённого состава: повторное добавление актёра её не меняет
+	added := castLink{ActorID: actorID, CharacterName: strings.TrimSpace(req.CharacterName), BillingOrder: req.BillingOrder}
+	for _, actor := range resp.Actors {
+		if actor.ID == actorID {
+			added.CharacterName = actor.CharacterName
+		}
+	}
+	publishCastLinks(c, h.bus, movieID, []castLink{added}, nil)
+
 	c.JSON(http.StatusOK, resp)
 }
 
@@ -519,21 +930,22 @@ func (h *MovieHandler) AddActorToMovie(c *gin.Context) {
 func (h *MovieHandler) RemoveActorFromMovie(c *gin.Context) {
 	movieID, err := strconv.Atoi(c.Param("movieId"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid movie id"})
+		respondError(c, apperror.Validation("invalid_id", "invalid movie id"))
 		return
 	}
 
 	actorID, err := strconv.Atoi(c.Param("actorId"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid actor id"})
+		respondError(c, apperror.Validation("invalid_id", "invalid actor id"))
 		return
 	}
 
 	resp, err := h.controller.RemoveActorFromMovie(c, movieID, actorID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, err)
 		return
 	}
+	publishCastLinks(c, h.bus, movieID, nil, []int{actorID})
 
 	c.JSON(http.StatusOK, resp)
 }
@@ -542,38 +954,163 @@ func (h *MovieHandler) RemoveActorFromMovie(c *gin.Context) {
 func (h *MovieHandler) GetActorsForMovieByID(c *gin.Context) {
 	movieID, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid movie id"})
+		respondError(c, apperror.Validation("invalid_id", "invalid movie id"))
 		return
 	}
 
 	resp, err := h.controller.GetActorsForMovieByID(c, movieID)
+	respond(c, http.StatusOK, resp, err)
+}
+
+// Cast возвращает страницу состава фильма в порядке титров
+func (h *MovieHandler) Cast(c *gin.Context) {
+	movieID, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		if errors.Is(err, domain.ErrMovieNotFound) {
-			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
-		} else {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		}
+		respondError(c, errInvalidID)
 		return
 	}
+	resp, err := h.controller.GetMovieCast(c, movieID)
+	respond(c, http.StatusOK, resp, err)
+}
 
-	c.JSON(http.StatusOK, resp)
+// RatingHistory возвращает историю изменений рейтинга фильма
+func (h *MovieHandler) RatingHistory(c *gin.Context) {
+	movieID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		respondError(c, errInvalidID)
+		return
+	}
+	resp, err := h.controller.GetRatingHistory(c, movieID)
+	respond(c, http.StatusOK, resp, err)
+}
+
+// History возвращает все версии фильма с отличиями от предыдущей версии
+func (h *MovieHandler) History(c *gin.Context) {
+	movieID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		respondError(c, errInvalidID)
+		return
+	}
+	resp, err := h.controller.GetMovieHistory(c, movieID)
+	respond(c, http.StatusOK, resp, err)
+}
+
+// Ratings возвращает рейтинги фильма по источникам
+func (h *MovieHandler) Ratings(c *gin.Context) {
+	movieID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		respondError(c, errInvalidID)
+		return
+	}
+	resp, err := h.controller.GetMovieRatings(c, movieID)
+	respond(c, http.StatusOK, resp, err)
+}
+
+// SetRating сохраняет рейтинг фильма из внешнего источника
+func (h *MovieHandler) SetRating(c *gin.Context) {
+	movieID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		respondError(c, errInvalidID)
+		return
+	}
+	var req dto.MovieRatingRequest
+	if err := bindJSON(c, &req); err != nil {
+		respondError(c, err)
+		return
+	}
+	resp, err := h.controller.SetMovieRating(c, movieID, c.Param("source"), req)
+	respond(c, http.StatusOK, resp, err)
+}
+
+// DeleteRating удаляет рейтинг фильма из внешнего источника
+func (h *MovieHandler) DeleteRating(c *gin.Context) {
+	movieID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		respondError(c, errInvalidID)
+		return
+	}
+	err = h.controller.DeleteMovieRating(c, movieID, c.Param("source"))
+	respond(c, http.StatusNoContent, nil, err)
+}
+
+// availabilityIDs разбирает ID фильма и окна доступности из пути
+func availabilityIDs(c *gin.Context) (int, int, error) {
+	movieID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return 0, 0, err
+	}
+	windowID, err := strconv.Atoi(c.Param("windowId"))
+	if err != nil {
+		return 0, 0, err
+	}
+	return movieID, windowID, nil
+}
+
+// Availability возвращает окна доступности фильма по регионам
+func (h *MovieHandler) Availability(c *gin.Context) {
+	movieID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		respondError(c, errInvalidID)
+		return
+	}
+	resp, err := h.controller.GetAvailability(c, movieID)
+	respond(c, http.StatusOK, resp, err)
+}
+
+// AddAvailability добавляет фильму окно доступности
+func (h *MovieHandler) AddAvailability(c *gin.Context) {
+	movieID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		respondError(c, errInvalidID)
+		return
+	}
+	var req dto.AvailabilityRequest
+	if err := bindJSON(c, &req); err != nil {
+		respondError(c, err)
+		return
+	}
+	resp, err := h.controller.AddAvailability(c, movieID, req)
+	respond(c, http.StatusCreated, resp, err)
+}
+
+// UpdateAvailability заменяет регион и даты окна доступности
+func (h *MovieHandler) UpdateAvailability(c *gin.Context) {
+	movieID, windowID, err := availabilityIDs(c)
+	if err != nil {
+		respondError(c, errInvalidID)
+		return
+	}
+	var req dto.AvailabilityRequest
+	if err := bindJSON(c, &req); err != nil {
+		respondError(c, err)
+		return
+	}
+	resp, err := h.controller.UpdateAvailability(c, movieID, windowID, req)
+	respond(c, http.StatusOK, resp, err)
+}
+
+// DeleteAvailability удаляет окно доступности фильма
+func (h *MovieHandler) DeleteAvailability(c *gin.Context) {
+	movieID, windowID, err := availabilityIDs(c)
+	if err != nil {
+		respondError(c, errInvalidID)
+		return
+	}
+	err = h.controller.DeleteAvailability(c, movieID, windowID)
+	respond(c, http.StatusNoContent, nil, err)
 }
 
 // GetMoviesForActor возвращает фильмы по актёру
 func (h *MovieHandler) GetMoviesForActor(c *gin.Context) {
 	actorID, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid actor id"})
+		respondError(c, apperror.Validation("invalid_id", "invalid actor id"))
 		return
 	}
 
 	resp, err := h.controller.GetMoviesForActor(c, actorID)
 	if err != nil {
-		if errors.Is(err, domain.ErrActorNotFound) {
-			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
-		} else {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		}
+		respondError(c, err)
 		return
 	}
 
@@ -592,17 +1129,23 @@ func RegisterActorRoutes(router *gin.RouterGroup, handler *ActorHandler, _ gin.H
 
 	// Группа для методов чтения (доступны всем аутентифицированным)
 	r.GET("", handler.List)
+	r.GET("/search", handler.Search)
+	r.GET("/suggest", handler.Suggest)
+	r.GET("/birthdays", handler.Birthdays)
 	r.GET(":id", handler.GetByID)
+	r.GET(":id/stats", handler.Stats)
+	r.GET(":id/history", handler.History)
+	r.GET("/slug/:slug", handler.GetBySlug)
 	r.GET("/with-movies", handler.ListWithMovies)
 
-	// Группа для методов записи (требуются права администратора)
-	// JWTAuthMiddleware уже применен, поэтому проверяем только роль
-	r.Use(auth.OnlyAdminOrReadOnly())
-
-	r.POST("", handler.Create)
-	r.PUT(":id", handler.Update)
-	r.PATCH(":id", handler.PartialUpdate)
-	r.DELETE(":id", handler.Delete)
+	// Методы записи доступны модераторам и администраторам, удаление — только администраторам.
+	// JWTAuthMiddleware уже применен, поэтому проверяем только права роли
+	write := auth.RequirePermission(domain.PermissionCatalogWrite)
+	r.POST("", write, handler.Create)
+	r.PUT(":id", write, handler.Update)
+	r.PATCH(":id", write, handler.PartialUpdate)
+	r.DELETE(":id", auth.RequirePermission(domain.PermissionCatalogDelete), handler.Delete)
+	r.POST(":id/photo", write, handler.UploadPhoto)
 }
 
 // RegisterMovieRoutes регистрирует маршруты для фильмов
@@ -613,37 +1156,79 @@ func RegisterMovieRoutes(router *gin.RouterGroup, handler *MovieHandler) {
 	movies.GET("", handler.List)
 	movies.GET("/search", handler.Search)
 	movies.GET("/sorted", handler.ListSorted)
+	movies.GET("/upcoming", handler.Upcoming)
+	movies.GET("/popular", handler.Popular)
+	movies.GET("/year/:year", handler.ByYear)
+	movies.GET("/decade/:decade", handler.ByDecade)
 
 	// Маршрут для получения фильмов актёра
 	movies.GET("/actor/:id", handler.GetMoviesForActor)
+	movies.GET("/slug/:slug", handler.GetBySlug)
 
 	// Параметризованные маршруты идут после конкретных
 	movies.GET(":id", handler.GetByID)
 	movies.GET(":id/actors", handler.GetActorsForMovieByID)
-
-	// Группа для методов записи (требуются права администратора)
-	movies.Use(auth.OnlyAdminOrReadOnly())
-	movies.POST("", handler.Create)
-	movies.POST("/with-actors", handler.CreateWithActors)
-	movies.PUT(":id", handler.Update)
-	movies.PATCH(":id", handler.PartialUpdate)
-	movies.DELETE(":id", handler.Delete)
-	movies.POST(":id/actors", handler.UpdateMovieActors)
-	movies.POST("add-actor/:movieId/:actorId", handler.AddActorToMovie)
-	movies.DELETE("remove-actor/:movieId/:actorId", handler.RemoveActorFromMovie)
+	movies.GET(":id/cast", handler.Cast)
+	movies.GET(":id/rating-history", handler.RatingHistory)
+	movies.GET(":id/history", handler.History)
+	movies.GET(":id/ratings", handler.Ratings)
+	movies.GET(":id/availability", handler.Availability)
+	movies.GET(":id/tags", handler.Tags)
+
+	// Изменение фильмов и состава актёров доступно модераторам, удаление фильма — только администраторам
+	write := auth.RequirePermission(domain.PermissionCatalogWrite)
+	movies.POST("", write, handler.idempotency.Middleware(), handler.Create)
+	movies.POST("/with-actors", write, handler.idempotency.Middleware(), handler.CreateWithActors)
+	movies.POST("/full", write, handler.idempotency.Middleware(), handler.CreateFull)
+	movies.PUT(":id", write, handler.Update)
+	movies.PUT("external/:externalId", write, handler.UpsertByExternalID)
+	movies.PATCH(":id", write, handler.PartialUpdate)
+	movies.DELETE(":id", auth.RequirePermission(domain.PermissionCatalogDelete), handler.Delete)
+	movies.POST(":id/actors", write, handler.UpdateMovieActors)
+	movies.PATCH(":id/actors", write, handler.ChangeMovieActors)
+	movies.PATCH(":id/actors/order", write, handler.ReorderActors)
+	movies.POST("add-actor/:movieId/:actorId", write, handler.AddActorToMovie)
+	movies.DELETE("remove-actor/:movieId/:actorId", write, handler.RemoveActorFromMovie)
+	movies.POST(":id/availability", write, handler.AddAvailability)
+	movies.PUT(":id/availability/:windowId", write, handler.UpdateAvailability)
+	movies.DELETE(":id/availability/:windowId", write, handler.DeleteAvailability)
+	// Кроме catalog:write контроллер требует ratings:write и отвечает fields_forbidden, как при смене rating
+	movies.PUT(":id/ratings/:source", write, handler.SetRating)
+	movies.DELETE(":id/ratings/:source", write, handler.DeleteRating)
+
+	// Теги свободные, поэтому их назначение доступно только администраторам
+	manageTags := auth.RequirePermission(domain.PermissionManageTags)
+	movies.PUT(":id/tags/:tag", manageTags, handler.AddTag)
+	movies.DELETE(":id/tags/:tag", manageTags, handler.RemoveTag)
+
+	tags := router.Group("/tags")
+	tags.GET("/suggest", handler.SuggestTags)
+	tags.GET("/:tag/movies", handler.ByTag)
 }
 
 // RegisterAuthRoutes регистрирует маршруты для аутентификации
 func RegisterAuthRoutes(router *gin.RouterGroup, handler *AuthHandler) {
 	authGroup := router.Group("/auth")
 	{
-		authGroup.POST("/register", handler.Register)
+		authGroup.POST("/register", handler.idempotency.Middleware(), handler.Register)
 		authGroup.POST("/login", handler.Login)
 		authGroup.POST("/refresh", handler.Refresh) // Добавляем эндпоинт для обновления токена
 		authGroup.POST("/logout", handler.Logout)   // Добавляем эндпоинт для выхода
+		authGroup.POST("/forgot-password", handler.ForgotPassword)
+		authGroup.POST("/reset-password", handler.ResetPassword)
 	}
 }
 
+// RegisterAccountRoutes регистрирует маршруты учётной записи текущего пользователя
+func RegisterAccountRoutes(router *gin.RouterGroup, handler *AuthHandler) {
+	me := router.Group("/users/me")
+	me.GET("", handler.GetMe)
+	me.PATCH("", handler.UpdateMe)
+	me.DELETE("", handler.DeleteMe)
+
+	router.PUT("/users/:id/role", auth.RequirePermission(domain.PermissionManageUsers), handler.ChangeUserRole)
+}
+
 // RegisterRateLimitRoutes регистрирует маршруты для мониторинга rate limiting
 func RegisterRateLimitRoutes(router *gin.RouterGroup, handler *RateLimitHandler) {
 	if handler != nil {
@@ -654,23 +1239,79 @@ func RegisterRateLimitRoutes(router *gin.RouterGroup, handler *RateLimitHandler)
 	}
 }
 
-// RegisterAllRoutes регистрирует все маршруты
-func RegisterAllRoutes(router *gin.RouterGroup, actorHandler *ActorHandler, movieHandler *MovieHandler, authHandler *AuthHandler, rateLimitHandler *RateLimitHandler) {
-	// 1. Регистрируем публичные маршруты (без аутентификации)
-	RegisterAuthRoutes(router, authHandler)
+// RegisterDocsRoutes регистрирует маршруты документации API.
+// Токен необязателен: без него в спецификации видны только публичные маршруты
+func RegisterDocsRoutes(router *gin.RouterGroup, handler *DocsHandler, keycloakClient keycloak.KeycloakClient) {
+	docs := router.Group("/docs")
+	docs.Use(auth.OptionalAuthMiddleware(keycloakClient))
 
-	// 2. Создаем группу для защищенных маршрутов
-	protected := router.Group("/")
-	// 3. Применяем гибридный middleware (поддерживает JWT и Keycloak)
+	docs.GET("", handler.SwaggerUI)
+	docs.GET("/openapi.json", handler.OpenAPI)
+}
+
+// RegisterAdminRoutes регистрирует административные маршруты (только для администраторов)
+func RegisterAdminRoutes(router *gin.RouterGroup, handler *AdminHandler) {
+	if handler == nil {
+		return
+	}
+	admin := router.Group("/admin")
+	admin.Use(auth.RequireRole(domain.RoleAdmin))
+
+	admin.POST("/actors/:keepId/merge/:dupId", handler.MergeActors)
+	admin.GET("/actors/orphans", handler.ListOrphanActors)
+	admin.POST("/actors/orphans/purge", handler.PurgeOrphanActors)
+	admin.POST("/movies/merge", handler.MergeMovies)
+	admin.POST("/movies/bulk-delete", handler.BulkDeleteMovies)
+	admin.POST("/movies/bulk-update", handler.BulkUpdateMovies)
+	admin.POST("/movies/import-external", handler.ImportExternalMovie)
+
+	admin.GET("/export/movies", handler.ExportMovies)
+	admin.GET("/export/actors", handler.ExportActors)
+	admin.POST("/import/actors", handler.ImportActors)
+
+	admin.GET("/jobs/:id", handler.GetJob)
+	admin.GET("/jobs/:id/wait", handler.WaitJob)
+
+	admin.POST("/maintenance/reindex", handler.Reindex)
+	admin.GET("/maintenance/jobs/:id", handler.GetJob)
+}
+
+// RegisterAllRoutes регистрирует все маршруты. При publicReads маршруты чтения каталога
+// (актёры, фильмы, теги, подборки, статистика) доступны без токена, а запись в каталог,
+// учётная запись и администрирование по-прежнему требуют аутентификации
+func RegisterAllRoutes(router *gin.RouterGroup, publicReads bool, actorHandler *ActorHandler, movieHandler *MovieHandler, authHandler *AuthHandler, rateLimitHandler *RateLimitHandler, adminHandler *AdminHandler, collectionHandler *CollectionHandler, eventsHandler *EventsHandler, statsHandler *StatsHandler, searchHandler *SearchHandler) {
 	keycloakManager := keycloak.GetGlobalManager()
 	var keycloakClient keycloak.KeycloakClient
 	if keycloakManager.IsEnabled() {
 		keycloakClient = keycloakManager.GetDefaultClient()
 	}
+
+	// 1. Регистрируем публичные маршруты (без аутентификации)
+	RegisterAuthRoutes(router, authHandler)
+	docsHandler := NewDocsHandler(router.BasePath())
+	docsHandler.publicReads = publicReads
+	RegisterDocsRoutes(router, docsHandler, keycloakClient)
+
+	// 2. Каталог: гибридный middleware (JWT и Keycloak) на всех методах или только на записи
+	catalog := router.Group("/")
+	if publicReads {
+		catalog.Use(auth.PublicReadsMiddleware(keycloakClient))
+	} else {
+		catalog.Use(auth.HybridAuthMiddleware(keycloakClient))
+	}
+
+	RegisterActorRoutes(catalog, actorHandler, func(c *gin.Context) {})
+	RegisterMovieRoutes(catalog, movieHandler)
+	RegisterCollectionRoutes(catalog, collectionHandler)
+	RegisterStatsRoutes(catalog, statsHandler)
+	RegisterSearchRoutes(catalog, searchHandler)
+
+	// 3. Маршруты пользователя и администратора требуют токен в любом режиме
+	protected := router.Group("/")
 	protected.Use(auth.HybridAuthMiddleware(keycloakClient))
 
-	// 4. Регистрируем защищенные маршруты
-	RegisterActorRoutes(protected, actorHandler, func(c *gin.Context) {})
-	RegisterMovieRoutes(protected, movieHandler)
+	RegisterAccountRoutes(protected, authHandler)
 	RegisterRateLimitRoutes(protected, rateLimitHandler)
+	RegisterAdminRoutes(protected, adminHandler)
+	RegisterEventsRoutes(protected, eventsHandler)
 }
@@ -2,11 +2,16 @@ package handlers
 
 import (
 	"bytes"
+	"cinematique/internal/apperror"
+	"cinematique/internal/auth"
 	"cinematique/internal/controller/dto"
 	"cinematique/internal/domain"
 	"cinematique/internal/kafka"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
 	"strconv"
@@ -33,6 +38,21 @@ func (m *MockActorController) GetActorByID(c *gin.Context, id int) (dto.ActorRes
 	return args.Get(0).(dto.ActorResponse), args.Error(1)
 }
 
+func (m *MockActorController) GetActorBySlug(c *gin.Context, slug string) (dto.ActorResponse, error) {
+	args := m.Called(c, slug)
+	return args.Get(0).(dto.ActorResponse), args.Error(1)
+}
+
+func (m *MockActorController) GetActorStats(c *gin.Context, id int) (dto.ActorStatsResponse, error) {
+	args := m.Called(c, id)
+	return args.Get(0).(dto.ActorStatsResponse), args.Error(1)
+}
+
+func (m *MockActorController) GetActorHistory(c *gin.Context, id int) (dto.ActorHistoryResponse, error) {
+	args := m.Called(c, id)
+	return args.Get(0).(dto.ActorHistoryResponse), args.Error(1)
+}
+
 func (m *MockActorController) UpdateActor(c *gin.Context, id int, req dto.UpdateActorRequest) (dto.ActorResponse, error) {
 	args := m.Called(c, id, req)
 	return args.Get(0).(dto.ActorResponse), args.Error(1)
@@ -48,6 +68,21 @@ func (m *MockActorController) ListActors(c *gin.Context) (dto.ActorsListResponse
 	return args.Get(0).(dto.ActorsListResponse), args.Error(1)
 }
 
+func (m *MockActorController) SearchActorsByName(c *gin.Context) (dto.ActorsListResponse, error) {
+	args := m.Called(c)
+	return args.Get(0).(dto.ActorsListResponse), args.Error(1)
+}
+
+func (m *MockActorController) SuggestActors(c *gin.Context) (dto.ActorSuggestionsResponse, error) {
+	args := m.Called(c)
+	return args.Get(0).(dto.ActorSuggestionsResponse), args.Error(1)
+}
+
+func (m *MockActorController) ListActorsByBirthMonth(c *gin.Context) (dto.ActorsListResponse, error) {
+	args := m.Called(c)
+	return args.Get(0).(dto.ActorsListResponse), args.Error(1)
+}
+
 func (m *MockActorController) GetAllActorsWithMovies(c *gin.Context) (dto.ActorsWithFilmsListResponse, error) {
 	args := m.Called(c)
 	return args.Get(0).(dto.ActorsWithFilmsListResponse), args.Error(1)
@@ -58,7 +93,105 @@ func (m *MockActorController) PartialUpdateActor(c *gin.Context, id int, update
 	return args.Get(0).(dto.ActorResponse), args.Error(1)
 }
 
+func (m *MockActorController) MergeActors(c *gin.Context, keepID, dupID int) (dto.ActorMergeResponse, error) {
+	args := m.Called(c, keepID, dupID)
+	return args.Get(0).(dto.ActorMergeResponse), args.Error(1)
+}
+
+func (m *MockActorController) ListOrphanActors(c *gin.Context) (dto.OrphanActorsResponse, error) {
+	args := m.Called(c)
+	return args.Get(0).(dto.OrphanActorsResponse), args.Error(1)
+}
+
+func (m *MockActorController) PurgeOrphanActors(c *gin.Context, req dto.PurgeOrphanActorsRequest) (dto.PurgeOrphanActorsResponse, error) {
+	args := m.Called(c, req)
+	return args.Get(0).(dto.PurgeOrphanActorsResponse), args.Error(1)
+}
+
+func (m *MockActorController) ImportActorsCSV(c *gin.Context, r io.Reader, dryRun bool) (dto.ActorImportResponse, error) {
+	data, _ := io.ReadAll(r)
+	args := m.Called(c, string(data), dryRun)
+	return args.Get(0).(dto.ActorImportResponse), args.Error(1)
+}
+
+func (m *MockActorController) ExportActors(c *gin.Context, fn func(dto.ActorResponse) error) error {
+	args := m.Called(c)
+	for _, actor := range args.Get(0).([]dto.ActorResponse) {
+		if err := fn(actor); err != nil {
+			return err
+		}
+	}
+	return args.Error(1)
+}
+
+func (m *MockActorController) UploadActorPhoto(c *gin.Context, id int, data []byte) (dto.ActorResponse, error) {
+	args := m.Called(c, id, data)
+	return args.Get(0).(dto.ActorResponse), args.Error(1)
+}
+
 // TestActorHandler_Create tests the Create method of ActorHandler
+// problem возвращает ожидаемое тело ответа application/problem+json
+func problem(status int, code, detail string) string {
+	return string(problemJSON(status, code, detail, nil))
+}
+
+// validationProblem возвращает ожидаемое тело ответа 400 с ошибками по полям с ключами keys
+func validationProblem(keys ...string) string {
+	fieldErrs := make(dto.ValidationErrors, 0, len(keys))
+	for _, key := range keys {
+		fieldErrs.Add(key)
+	}
+	detail := "validation error: " + fieldErrs.Error()
+	return string(problemJSON(http.StatusBadRequest, dto.CodeValidationFailed, detail, fieldErrs))
+}
+
+func problemJSON(status int, code, detail string, fieldErrs dto.ValidationErrors) []byte {
+	body := map[string]interface{}{
+		"type":   "about:blank",
+		"title":  http.StatusText(status),
+		"status": status,
+		"code":   code,
+		"detail": detail,
+	}
+	if fieldErrs != nil {
+		body["errors"] = fieldErrs
+	}
+	data, _ := json.Marshal(body)
+	return data
+}
+
+func TestRespond(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	tests := []struct {
+		name         string
+		status       int
+		result       interface{}
+		err          error
+		expectedCode int
+		expectedBody string
+	}{
+		{"result", http.StatusCreated, gin.H{"id": 1}, nil, http.StatusCreated, `{"id":1}`},
+		{"no content", http.StatusNoContent, nil, nil, http.StatusNoContent, ""},
+		{"typed error", http.StatusOK, gin.H{"id": 1}, domain.ErrMovieNotFound, http.StatusNotFound, problem(http.StatusNotFound, "movie_not_found", domain.ErrMovieNotFound.Error())},
+		{"internal error", http.StatusOK, nil, errors.New("connection refused"), http.StatusInternalServerError, problem(http.StatusInternalServerError, apperror.CodeInternal, "Internal Server Error")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := gin.New()
+			r.Use(apperror.Middleware())
+			r.GET("/", func(c *gin.Context) {
+				respond(c, tt.status, tt.result, tt.err)
+			})
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			assert.Equal(t, tt.expectedBody, w.Body.String())
+		})
+	}
+}
+
 func TestActorHandler_Create(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -102,7 +235,7 @@ func TestActorHandler_Create(t *testing.T) {
 				// No mock setup needed as the handler should return before calling the controller
 			},
 			expectedStatus: http.StatusBadRequest,
-			expectedBody:   `{"error":"Name is required"}`,
+			expectedBody:   problem(http.StatusBadRequest, "field_required", "Name is required"),
 		},
 		{
 			name: "missing gender",
@@ -115,7 +248,7 @@ func TestActorHandler_Create(t *testing.T) {
 				// No mock setup needed as the handler should return before calling the controller
 			},
 			expectedStatus: http.StatusBadRequest,
-			expectedBody:   `{"error":"Gender is required"}`,
+			expectedBody:   problem(http.StatusBadRequest, "field_required", "Gender is required"),
 		},
 		{
 			name: "missing birth date",
@@ -128,7 +261,7 @@ func TestActorHandler_Create(t *testing.T) {
 				// No mock setup needed as the handler should return before calling the controller
 			},
 			expectedStatus: http.StatusBadRequest,
-			expectedBody:   `{"error":"BirthDate is required"}`,
+			expectedBody:   problem(http.StatusBadRequest, "field_required", "BirthDate is required"),
 		},
 		{
 			name: "controller error",
@@ -147,7 +280,7 @@ func TestActorHandler_Create(t *testing.T) {
 					Return(dto.ActorResponse{}, errors.New("database error"))
 			},
 			expectedStatus: http.StatusInternalServerError,
-			expectedBody:   `{"error":"database error"}`,
+			expectedBody:   problem(http.StatusInternalServerError, apperror.CodeInternal, "Internal Server Error"),
 		},
 	}
 
@@ -156,8 +289,9 @@ func TestActorHandler_Create(t *testing.T) {
 			// Setup
 			gin.SetMode(gin.TestMode)
 			r := gin.New()
+			r.Use(apperror.Middleware())
 			mockCtrl := new(MockActorController)
-			handler := NewActorHandler(mockCtrl)
+			handler := NewActorHandler(mockCtrl, nil)
 
 			tt.setupMock(mockCtrl)
 
@@ -210,17 +344,17 @@ func TestActorHandler_GetByID(t *testing.T) {
 				// No mock setup needed for this case
 			},
 			expectedStatus: http.StatusBadRequest,
-			expectedBody:   `{"error":"invalid id"}`,
+			expectedBody:   problem(http.StatusBadRequest, "invalid_id", "invalid id"),
 		},
 		{
 			name:    "not found",
 			actorID: "999",
 			setupMock: func(m *MockActorController, id int) {
 				m.On("GetActorByID", mock.Anything, id).
-					Return(dto.ActorResponse{}, errors.New("actor not found"))
+					Return(dto.ActorResponse{}, domain.ErrActorNotFound)
 			},
 			expectedStatus: http.StatusNotFound,
-			expectedBody:   `{"error":"actor not found"}`,
+			expectedBody:   problem(http.StatusNotFound, "actor_not_found", "actor not found"),
 		},
 	}
 
@@ -229,8 +363,9 @@ func TestActorHandler_GetByID(t *testing.T) {
 			// Setup
 			gin.SetMode(gin.TestMode)
 			r := gin.New()
+			r.Use(apperror.Middleware())
 			mockCtrl := new(MockActorController)
-			handler := NewActorHandler(mockCtrl)
+			handler := NewActorHandler(mockCtrl, nil)
 
 			actorID, _ := strconv.Atoi(tt.actorID)
 			tt.setupMock(mockCtrl, actorID)
@@ -251,6 +386,235 @@ func TestActorHandler_GetByID(t *testing.T) {
 	}
 }
 
+func TestActorHandler_Stats(t *testing.T) {
+	averageRating := 7.67
+	tests := []struct {
+		name           string
+		actorID        string
+		setupMock      func(*MockActorController)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:    "success",
+			actorID: "7",
+			setupMock: func(m *MockActorController) {
+				m.On("GetActorStats", mock.Anything, 7).
+					Return(dto.ActorStatsResponse{ActorID: 7, MovieCount: 3, AverageRating: &averageRating, FirstYear: 1972, LatestYear: 1995}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"actor_id":7,"movie_count":3,"average_rating":7.67,"first_year":1972,"latest_year":1995}`,
+		},
+		{
+			name:    "actor without movies",
+			actorID: "8",
+			setupMock: func(m *MockActorController) {
+				m.On("GetActorStats", mock.Anything, 8).Return(dto.ActorStatsResponse{ActorID: 8}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"actor_id":8,"movie_count":0,"average_rating":null}`,
+		},
+		{
+			name:           "invalid id",
+			actorID:        "invalid",
+			setupMock:      func(m *MockActorController) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   problem(http.StatusBadRequest, "invalid_id", "invalid id"),
+		},
+		{
+			name:    "not found",
+			actorID: "999",
+			setupMock: func(m *MockActorController) {
+				m.On("GetActorStats", mock.Anything, 999).Return(dto.ActorStatsResponse{}, domain.ErrActorNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   problem(http.StatusNotFound, "actor_not_found", "actor not found"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			r := gin.New()
+			r.Use(apperror.Middleware())
+			mockCtrl := new(MockActorController)
+			handler := NewActorHandler(mockCtrl, nil)
+			tt.setupMock(mockCtrl)
+
+			r.GET("/actors/:id/stats", handler.Stats)
+			req, _ := http.NewRequest("GET", "/actors/"+tt.actorID+"/stats", nil)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.JSONEq(t, tt.expectedBody, w.Body.String())
+			mockCtrl.AssertExpectations(t)
+		})
+	}
+}
+
+func TestActorHandler_History(t *testing.T) {
+	changedAt := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name           string
+		actorID        string
+		setupMock      func(*MockActorController)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:    "success",
+			actorID: "7",
+			setupMock: func(m *MockActorController) {
+				m.On("GetActorHistory", mock.Anything, 7).Return(dto.ActorHistoryResponse{
+					ActorID: 7,
+					Versions: []dto.ActorVersionResponse{
+						{
+							Version: 1, ChangedAt: changedAt,
+							Actor:   &dto.ActorResponse{ID: 7, Name: "Lance Reddick", Gender: "male", BirthDate: "1962-06-07"},
+							Changes: []dto.FieldChangeResponse{{Field: "name", New: "Lance Reddick"}},
+						},
+						{Version: 2, ChangedAt: changedAt.Add(time.Hour), Deleted: true, Changes: []dto.FieldChangeResponse{}},
+					},
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: `{"actor_id":7,"versions":[{"version":1,"changed_at":"2024-03-01T12:00:00Z",` +
+				`"actor":{"id":7,"name":"Lance Reddick","gender":"male","birth_date":"1962-06-07"},` +
+				`"changes":[{"field":"name","old":null,"new":"Lance Reddick"}]},` +
+				`{"version":2,"changed_at":"2024-03-01T13:00:00Z","deleted":true,"changes":[]}]}`,
+		},
+		{
+			name:           "invalid id",
+			actorID:        "invalid",
+			setupMock:      func(m *MockActorController) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   problem(http.StatusBadRequest, "invalid_id", "invalid id"),
+		},
+		{
+			name:    "not found",
+			actorID: "999",
+			setupMock: func(m *MockActorController) {
+				m.On("GetActorHistory", mock.Anything, 999).Return(dto.ActorHistoryResponse{}, domain.ErrActorNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   problem(http.StatusNotFound, "actor_not_found", "actor not found"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			r := gin.New()
+			r.Use(apperror.Middleware())
+			mockCtrl := new(MockActorController)
+			handler := NewActorHandler(mockCtrl, nil)
+			tt.setupMock(mockCtrl)
+
+			r.GET("/actors/:id/history", handler.History)
+			req, _ := http.NewRequest("GET", "/actors/"+tt.actorID+"/history", nil)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.JSONEq(t, tt.expectedBody, w.Body.String())
+			mockCtrl.AssertExpectations(t)
+		})
+	}
+}
+
+// TestActorHandler_UploadPhoto tests the UploadPhoto method of ActorHandler
+func TestActorHandler_UploadPhoto(t *testing.T) {
+	photo := []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+
+	tests := []struct {
+		name           string
+		actorID        string
+		field          string
+		setupMock      func(*MockActorController)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:    "success",
+			actorID: "1",
+			field:   "photo",
+			setupMock: func(m *MockActorController) {
+				m.On("UploadActorPhoto", mock.Anything, 1, photo).
+					Return(dto.ActorResponse{
+						ID:        1,
+						Name:      "Test Actor",
+						Gender:    "male",
+						BirthDate: "1990-01-01",
+						PhotoURL:  "/media/actors/1/photo-1.png",
+					}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"id":1,"name":"Test Actor","gender":"male","birth_date":"1990-01-01","photo_url":"/media/actors/1/photo-1.png"}`,
+		},
+		{
+			name:           "missing file",
+			actorID:        "1",
+			field:          "file",
+			setupMock:      func(m *MockActorController) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   problem(http.StatusBadRequest, "field_required", "photo file is required"),
+		},
+		{
+			name:    "unsupported format",
+			actorID: "1",
+			field:   "photo",
+			setupMock: func(m *MockActorController) {
+				m.On("UploadActorPhoto", mock.Anything, 1, photo).
+					Return(dto.ActorResponse{}, fmt.Errorf("validation error: %w",
+						dto.ValidationErrors{dto.NewFieldError(dto.KeyActorPhotoUnsupported)}))
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   validationProblem(dto.KeyActorPhotoUnsupported),
+		},
+		{
+			name:    "not found",
+			actorID: "999",
+			field:   "photo",
+			setupMock: func(m *MockActorController) {
+				m.On("UploadActorPhoto", mock.Anything, 999, photo).
+					Return(dto.ActorResponse{}, domain.ErrActorNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   problem(http.StatusNotFound, "actor_not_found", "actor not found"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			r := gin.New()
+			r.Use(apperror.Middleware())
+			mockCtrl := new(MockActorController)
+			handler := NewActorHandler(mockCtrl, nil)
+			tt.setupMock(mockCtrl)
+
+			r.POST("/actors/:id/photo", handler.UploadPhoto)
+
+			var body bytes.Buffer
+			mw := multipart.NewWriter(&body)
+			part, err := mw.CreateFormFile(tt.field, "headshot.png")
+			assert.NoError(t, err)
+			_, _ = part.Write(photo)
+			assert.NoError(t, mw.Close())
+
+			req, _ := http.NewRequest("POST", "/actors/"+tt.actorID+"/photo", &body)
+			req.Header.Set("Content-Type", mw.FormDataContentType())
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.JSONEq(t, tt.expectedBody, w.Body.String())
+			mockCtrl.AssertExpectations(t)
+		})
+	}
+}
+
 // TestActorHandler_List tests the List method of ActorHandler
 func TestActorHandler_List(t *testing.T) {
 	tests := []struct {
@@ -273,7 +637,7 @@ func TestActorHandler_List(t *testing.T) {
 				m.On("ListActors", mock.Anything).Return(dto.ActorsListResponse{}, errors.New("internal error"))
 			},
 			expectedStatus: http.StatusInternalServerError,
-			expectedBody:   `{"error":"internal error"}`,
+			expectedBody:   problem(http.StatusInternalServerError, apperror.CodeInternal, "Internal Server Error"),
 		},
 	}
 
@@ -281,8 +645,9 @@ func TestActorHandler_List(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			gin.SetMode(gin.TestMode)
 			r := gin.New()
+			r.Use(apperror.Middleware())
 			mockCtrl := new(MockActorController)
-			handler := NewActorHandler(mockCtrl)
+			handler := NewActorHandler(mockCtrl, nil)
 
 			tt.setupMock(mockCtrl)
 
@@ -297,6 +662,90 @@ func TestActorHandler_List(t *testing.T) {
 	}
 }
 
+func TestActorHandler_Suggest(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(apperror.Middleware())
+	mockCtrl := new(MockActorController)
+	handler := NewActorHandler(mockCtrl, nil)
+	mockCtrl.On("SuggestActors", mock.Anything).
+		Return(dto.ActorSuggestionsResponse{Actors: []dto.ActorSuggestion{{ID: 3, Name: "Leonardo DiCaprio"}}}, nil).Once()
+	mockCtrl.On("SuggestActors", mock.Anything).
+		Return(dto.ActorSuggestionsResponse{}, fmt.Errorf("validation error: %w", dto.ValidationErrors{dto.NewFieldError(dto.KeyActorSuggestQRequired)})).Once()
+	r.GET("/actors/suggest", handler.Suggest)
+
+	req, _ := http.NewRequest("GET", "/actors/suggest?q=leo", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"actors":[{"id":3,"name":"Leonardo DiCaprio"}]}`, w.Body.String())
+
+	req, _ = http.NewRequest("GET", "/actors/suggest", nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.JSONEq(t, validationProblem(dto.KeyActorSuggestQRequired), w.Body.String())
+	mockCtrl.AssertExpectations(t)
+}
+
+func TestActorHandler_Search(t *testing.T) {
+	tests := []struct {
+		name           string
+		setupMock      func(*MockActorController)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "success",
+			setupMock: func(m *MockActorController) {
+				m.On("SearchActorsByName", mock.Anything).Return(dto.ActorsListResponse{
+					Actors:     []dto.ActorResponse{{ID: 7, Name: "Keanu Reeves", Gender: "male", BirthDate: "1964-09-02"}},
+					Pagination: &dto.Pagination{Limit: 20, Offset: 0, Total: 1, TotalPages: 1},
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"actors":[{"id":7,"name":"Keanu Reeves","gender":"male","birth_date":"1964-09-02"}],"pagination":{"limit":20,"offset":0,"total":1,"total_pages":1}}`,
+		},
+		{
+			name: "missing name",
+			setupMock: func(m *MockActorController) {
+				err := fmt.Errorf("validation error: %w", dto.ValidationErrors{dto.NewFieldError(dto.KeyActorSearchNameRequired)})
+				m.On("SearchActorsByName", mock.Anything).Return(dto.ActorsListResponse{}, err)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   validationProblem(dto.KeyActorSearchNameRequired),
+		},
+		{
+			name: "controller error",
+			setupMock: func(m *MockActorController) {
+				m.On("SearchActorsByName", mock.Anything).Return(dto.ActorsListResponse{}, errors.New("internal error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   problem(http.StatusInternalServerError, apperror.CodeInternal, "Internal Server Error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			r := gin.New()
+			r.Use(apperror.Middleware())
+			mockCtrl := new(MockActorController)
+			handler := NewActorHandler(mockCtrl, nil)
+
+			tt.setupMock(mockCtrl)
+
+			r.GET("/actors/search", handler.Search)
+			req, _ := http.NewRequest("GET", "/actors/search?name=reev", nil)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.JSONEq(t, tt.expectedBody, w.Body.String())
+		})
+	}
+}
+
 // TestActorHandler_Update tests the Update method of ActorHandler
 func TestActorHandler_Update(t *testing.T) {
 	tests := []struct {
@@ -338,7 +787,7 @@ func TestActorHandler_Update(t *testing.T) {
 			requestBody:    `{"name":"Test"}`,
 			setupMock:      func(m *MockActorController, id int) {},
 			expectedStatus: http.StatusBadRequest,
-			expectedBody:   `{"error":"invalid id"}`,
+			expectedBody:   problem(http.StatusBadRequest, "invalid_id", "invalid id"),
 		},
 		{
 			name:        "not found",
@@ -349,7 +798,7 @@ func TestActorHandler_Update(t *testing.T) {
 					Return(dto.ActorResponse{}, domain.ErrActorNotFound)
 			},
 			expectedStatus: http.StatusNotFound,
-			expectedBody:   `{"error":"actor not found"}`,
+			expectedBody:   problem(http.StatusNotFound, "actor_not_found", "actor not found"),
 		},
 	}
 
@@ -357,8 +806,9 @@ func TestActorHandler_Update(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			gin.SetMode(gin.TestMode)
 			r := gin.New()
+			r.Use(apperror.Middleware())
 			mockCtrl := new(MockActorController)
-			handler := NewActorHandler(mockCtrl)
+			handler := NewActorHandler(mockCtrl, nil)
 
 			actorID, _ := strconv.Atoi(tt.actorID)
 			tt.setupMock(mockCtrl, actorID)
@@ -428,7 +878,7 @@ func TestActorHandler_PartialUpdate(t *testing.T) {
 			requestBody:    `{"name":"Updated"}`,
 			setupMock:      func(m *MockActorController, id int) {},
 			expectedStatus: http.StatusBadRequest,
-			expectedBody:   `{"error":"invalid id"}`,
+			expectedBody:   problem(http.StatusBadRequest, "invalid_id", "invalid id"),
 		},
 		{
 			name:        "not found",
@@ -439,7 +889,7 @@ func TestActorHandler_PartialUpdate(t *testing.T) {
 					Return(dto.ActorResponse{}, domain.ErrActorNotFound)
 			},
 			expectedStatus: http.StatusNotFound,
-			expectedBody:   `{"error":"actor not found"}`,
+			expectedBody:   problem(http.StatusNotFound, "actor_not_found", "actor not found"),
 		},
 	}
 
@@ -447,8 +897,9 @@ func TestActorHandler_PartialUpdate(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			gin.SetMode(gin.TestMode)
 			r := gin.New()
+			r.Use(apperror.Middleware())
 			mockCtrl := new(MockActorController)
-			handler := NewActorHandler(mockCtrl)
+			handler := NewActorHandler(mockCtrl, nil)
 
 			actorID, _ := strconv.Atoi(tt.actorID)
 			tt.setupMock(mockCtrl, actorID)
@@ -490,7 +941,7 @@ func TestActorHandler_Delete(t *testing.T) {
 			actorID:        "abc",
 			setupMock:      func(m *MockActorController, id int) {},
 			expectedStatus: http.StatusBadRequest,
-			expectedBody:   `{"error":"invalid id"}`,
+			expectedBody:   problem(http.StatusBadRequest, "invalid_id", "invalid id"),
 		},
 		{
 			name:    "not found",
@@ -499,7 +950,7 @@ func TestActorHandler_Delete(t *testing.T) {
 				m.On("DeleteActor", mock.Anything, id).Return(domain.ErrActorNotFound)
 			},
 			expectedStatus: http.StatusNotFound,
-			expectedBody:   `{"error":"actor not found"}`,
+			expectedBody:   problem(http.StatusNotFound, "actor_not_found", "actor not found"),
 		},
 	}
 
@@ -507,8 +958,9 @@ func TestActorHandler_Delete(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			gin.SetMode(gin.TestMode)
 			r := gin.New()
+			r.Use(apperror.Middleware())
 			mockCtrl := new(MockActorController)
-			handler := NewActorHandler(mockCtrl)
+			handler := NewActorHandler(mockCtrl, nil)
 
 			actorID, _ := strconv.Atoi(tt.actorID)
 			tt.setupMock(mockCtrl, actorID)
@@ -550,7 +1002,7 @@ func TestActorHandler_ListWithMovies(t *testing.T) {
 				m.On("GetAllActorsWithMovies", mock.Anything).Return(dto.ActorsWithFilmsListResponse{}, errors.New("internal error"))
 			},
 			expectedStatus: http.StatusInternalServerError,
-			expectedBody:   `{"error":"internal error"}`,
+			expectedBody:   problem(http.StatusInternalServerError, apperror.CodeInternal, "Internal Server Error"),
 		},
 	}
 
@@ -558,8 +1010,9 @@ func TestActorHandler_ListWithMovies(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			gin.SetMode(gin.TestMode)
 			r := gin.New()
+			r.Use(apperror.Middleware())
 			mockCtrl := new(MockActorController)
-			handler := NewActorHandler(mockCtrl)
+			handler := NewActorHandler(mockCtrl, nil)
 
 			tt.setupMock(mockCtrl)
 
@@ -591,8 +1044,13 @@ func (m *MockMovieController) GetMovieByID(c *gin.Context, id int) (dto.MovieRes
 	return args.Get(0).(dto.MovieResponse), args.Error(1)
 }
 
-func (m *MockMovieController) UpdateMovie(c *gin.Context, id int, req dto.UpdateMovieRequest) (dto.MovieResponse, error) {
-	args := m.Called(c, id, req)
+func (m *MockMovieController) GetMovieBySlug(c *gin.Context, slug string) (dto.MovieResponse, error) {
+	args := m.Called(c, slug)
+	return args.Get(0).(dto.MovieResponse), args.Error(1)
+}
+
+func (m *MockMovieController) UpdateMovie(c *gin.Context, id int, req dto.UpdateMovieRequest) (dto.MovieResponse, error) {
+	args := m.Called(c, id, req)
 	return args.Get(0).(dto.MovieResponse), args.Error(1)
 }
 
@@ -601,6 +1059,11 @@ func (m *MockMovieController) DeleteMovie(c *gin.Context, id int) error {
 	return args.Error(0)
 }
 
+func (m *MockMovieController) DeleteCreatedMovie(c *gin.Context, movieID int, createdActorIDs []int) error {
+	args := m.Called(c, movieID, createdActorIDs)
+	return args.Error(0)
+}
+
 func (m *MockMovieController) ListMovies(c *gin.Context) (dto.MoviesListResponse, error) {
 	args := m.Called(c)
 	return args.Get(0).(dto.MoviesListResponse), args.Error(1)
@@ -626,13 +1089,28 @@ func (m *MockMovieController) CreateMovieWithActors(c *gin.Context, req dto.Movi
 	return args.Get(0).(dto.MovieResponse), args.Error(1)
 }
 
+func (m *MockMovieController) CreateMovieFull(c *gin.Context, req dto.FullMovieRequest) (dto.FullMovieResponse, error) {
+	args := m.Called(c, req)
+	return args.Get(0).(dto.FullMovieResponse), args.Error(1)
+}
+
 func (m *MockMovieController) UpdateMovieActors(c *gin.Context, movieID int, req dto.UpdateMovieActorsRequest) (dto.MovieActorsResponse, error) {
 	args := m.Called(c, movieID, req)
 	return args.Get(0).(dto.MovieActorsResponse), args.Error(1)
 }
 
-func (m *MockMovieController) AddActorToMovie(c *gin.Context, movieID, actorID int) (dto.MovieResponse, error) {
-	args := m.Called(c, movieID, actorID)
+func (m *MockMovieController) ChangeMovieActors(c *gin.Context, movieID int, req dto.ChangeMovieActorsRequest) (dto.MovieActorsResponse, error) {
+	args := m.Called(c, movieID, req)
+	return args.Get(0).(dto.MovieActorsResponse), args.Error(1)
+}
+
+func (m *MockMovieController) ReorderMovieActors(c *gin.Context, movieID int, req dto.ReorderMovieActorsRequest) (dto.MovieActorsResponse, error) {
+	args := m.Called(c, movieID, req)
+	return args.Get(0).(dto.MovieActorsResponse), args.Error(1)
+}
+
+func (m *MockMovieController) AddActorToMovie(c *gin.Context, movieID, actorID int, req dto.AddActorToMovieRequest) (dto.MovieResponse, error) {
+	args := m.Called(c, movieID, actorID, req)
 	return args.Get(0).(dto.MovieResponse), args.Error(1)
 }
 
@@ -646,26 +1124,167 @@ func (m *MockMovieController) GetActorsForMovieByID(c *gin.Context, movieID int)
 	return args.Get(0).(dto.MovieActorsResponse), args.Error(1)
 }
 
+func (m *MockMovieController) GetMovieCast(c *gin.Context, movieID int) (dto.MovieCastResponse, error) {
+	args := m.Called(c, movieID)
+	return args.Get(0).(dto.MovieCastResponse), args.Error(1)
+}
+
+func (m *MockMovieController) GetRatingHistory(c *gin.Context, movieID int) (dto.RatingHistoryResponse, error) {
+	args := m.Called(c, movieID)
+	return args.Get(0).(dto.RatingHistoryResponse), args.Error(1)
+}
+
+func (m *MockMovieController) GetMovieHistory(c *gin.Context, movieID int) (dto.MovieHistoryResponse, error) {
+	args := m.Called(c, movieID)
+	return args.Get(0).(dto.MovieHistoryResponse), args.Error(1)
+}
+
+func (m *MockMovieController) GetMovieRatings(c *gin.Context, movieID int) (dto.MovieRatingsResponse, error) {
+	args := m.Called(c, movieID)
+	return args.Get(0).(dto.MovieRatingsResponse), args.Error(1)
+}
+
+func (m *MockMovieController) SetMovieRating(c *gin.Context, movieID int, source string, req dto.MovieRatingRequest) (dto.MovieRatingsResponse, error) {
+	args := m.Called(c, movieID, source, req)
+	return args.Get(0).(dto.MovieRatingsResponse), args.Error(1)
+}
+
+func (m *MockMovieController) DeleteMovieRating(c *gin.Context, movieID int, source string) error {
+	args := m.Called(c, movieID, source)
+	return args.Error(0)
+}
+
+func (m *MockMovieController) GetMovieTags(c *gin.Context, movieID int) (dto.MovieTagsResponse, error) {
+	args := m.Called(c, movieID)
+	return args.Get(0).(dto.MovieTagsResponse), args.Error(1)
+}
+
+func (m *MockMovieController) AddMovieTag(c *gin.Context, movieID int, tag string) (dto.MovieTagsResponse, error) {
+	args := m.Called(c, movieID, tag)
+	return args.Get(0).(dto.MovieTagsResponse), args.Error(1)
+}
+
+func (m *MockMovieController) RemoveMovieTag(c *gin.Context, movieID int, tag string) error {
+	args := m.Called(c, movieID, tag)
+	return args.Error(0)
+}
+
+func (m *MockMovieController) GetMoviesByTag(c *gin.Context, tag string) (dto.MoviesByTagResponse, error) {
+	args := m.Called(c, tag)
+	return args.Get(0).(dto.MoviesByTagResponse), args.Error(1)
+}
+
+func (m *MockMovieController) SuggestTags(c *gin.Context) (dto.TagSuggestionsResponse, error) {
+	args := m.Called(c)
+	return args.Get(0).(dto.TagSuggestionsResponse), args.Error(1)
+}
+
+func (m *MockMovieController) GetAvailability(c *gin.Context, movieID int) (dto.AvailabilityListResponse, error) {
+	args := m.Called(c, movieID)
+	return args.Get(0).(dto.AvailabilityListResponse), args.Error(1)
+}
+
+func (m *MockMovieController) AddAvailability(c *gin.Context, movieID int, req dto.AvailabilityRequest) (dto.AvailabilityResponse, error) {
+	args := m.Called(c, movieID, req)
+	return args.Get(0).(dto.AvailabilityResponse), args.Error(1)
+}
+
+func (m *MockMovieController) UpdateAvailability(c *gin.Context, movieID, windowID int, req dto.AvailabilityRequest) (dto.AvailabilityResponse, error) {
+	args := m.Called(c, movieID, windowID, req)
+	return args.Get(0).(dto.AvailabilityResponse), args.Error(1)
+}
+
+func (m *MockMovieController) DeleteAvailability(c *gin.Context, movieID, windowID int) error {
+	args := m.Called(c, movieID, windowID)
+	return args.Error(0)
+}
+
 func (m *MockMovieController) GetMoviesForActor(c *gin.Context, actorID int) (dto.ActorMoviesResponse, error) {
 	args := m.Called(c, actorID)
 	return args.Get(0).(dto.ActorMoviesResponse), args.Error(1)
 }
 
-func (m *MockMovieController) PartialUpdateMovie(c *gin.Context, id int, update dto.MovieUpdate) error {
+func (m *MockMovieController) PartialUpdateMovie(c *gin.Context, id int, update dto.MovieUpdate) (dto.MovieResponse, error) {
 	args := m.Called(c, id, update)
-	return args.Error(0)
+	return args.Get(0).(dto.MovieResponse), args.Error(1)
+}
+
+func (m *MockMovieController) MergeMovies(c *gin.Context, req dto.MergeMoviesRequest) (dto.MovieMergeResponse, error) {
+	args := m.Called(c, req)
+	return args.Get(0).(dto.MovieMergeResponse), args.Error(1)
+}
+
+func (m *MockMovieController) BulkDeleteMovies(c *gin.Context, req dto.BulkDeleteMoviesRequest) (dto.BulkOperationResponse, error) {
+	args := m.Called(c, req)
+	return args.Get(0).(dto.BulkOperationResponse), args.Error(1)
+}
+
+func (m *MockMovieController) BulkUpdateMovies(c *gin.Context, req dto.BulkUpdateMoviesRequest) (dto.BulkOperationResponse, error) {
+	args := m.Called(c, req)
+	return args.Get(0).(dto.BulkOperationResponse), args.Error(1)
+}
+
+func (m *MockMovieController) ResolveMergedMovieID(c *gin.Context, id int) (int, error) {
+	args := m.Called(c, id)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockMovieController) GetMovieByIDAsOf(c *gin.Context, id int, asOf string) (dto.MovieResponse, error) {
+	args := m.Called(c, id, asOf)
+	return args.Get(0).(dto.MovieResponse), args.Error(1)
+}
+
+func (m *MockMovieController) GetUpcomingMovies(c *gin.Context) (dto.MoviesListResponse, error) {
+	args := m.Called(c)
+	return args.Get(0).(dto.MoviesListResponse), args.Error(1)
+}
+
+func (m *MockMovieController) GetPopularMovies(c *gin.Context) (dto.MoviesListResponse, error) {
+	args := m.Called(c)
+	return args.Get(0).(dto.MoviesListResponse), args.Error(1)
+}
+
+func (m *MockMovieController) GetMoviesByYear(c *gin.Context, year string) (dto.MoviesByPeriodResponse, error) {
+	args := m.Called(c, year)
+	return args.Get(0).(dto.MoviesByPeriodResponse), args.Error(1)
+}
+
+func (m *MockMovieController) GetMoviesByDecade(c *gin.Context, decade string) (dto.MoviesByPeriodResponse, error) {
+	args := m.Called(c, decade)
+	return args.Get(0).(dto.MoviesByPeriodResponse), args.Error(1)
+}
+
+func (m *MockMovieController) ExportMovies(c *gin.Context, fn func(dto.MovieResponse) error) error {
+	args := m.Called(c)
+	for _, movie := range args.Get(0).([]dto.MovieResponse) {
+		if err := fn(movie); err != nil {
+			return err
+		}
+	}
+	return args.Error(1)
+}
+
+func (m *MockMovieController) ImportExternalMovie(c *gin.Context, imdbID string) (dto.MovieImportResponse, error) {
+	args := m.Called(c, imdbID)
+	return args.Get(0).(dto.MovieImportResponse), args.Error(1)
+}
+
+func (m *MockMovieController) UpsertMovieByExternalID(c *gin.Context, externalID string, req dto.CreateMovieRequest) (dto.MovieUpsertResponse, error) {
+	args := m.Called(c, externalID, req)
+	return args.Get(0).(dto.MovieUpsertResponse), args.Error(1)
 }
 
 // newTestMovieHandler создает новый MovieHandler с мок-зависимостями для тестирования
 func newTestMovieHandler(ctrl *MockMovieController, producer *kafka.MockProducer) *MovieHandler {
 	producerPool := kafka.NewProducerPool(producer, 1, 10)
-	return NewMovieHandler(ctrl, producerPool)
+	return NewMovieHandler(ctrl, kafkaBus(producerPool))
 }
 
 func TestMovieHandler_Create(t *testing.T) {
 	tests := []struct {
 		name           string
 		requestBody    map[string]interface{}
+		query          string
 		setupMock      func(*MockMovieController)
 		setupProducer  func(*kafka.MockProducer)
 		expectedStatus int
@@ -699,7 +1318,7 @@ func TestMovieHandler_Create(t *testing.T) {
 				p.On("Produce", mock.Anything, "movies", mock.Anything, mock.Anything).Return(nil)
 			},
 			expectedStatus: http.StatusCreated,
-			expectedBody:   `{"id":1,"title":"Test Movie","description":"Test Description","release_year":2023,"rating":8.5}`,
+			expectedBody:   `{"id":1,"title":"Test Movie","description":"Test Description","release_year":2023,"rating":8.5,"view_count":0}`,
 		},
 		{
 			name: "empty title",
@@ -714,7 +1333,7 @@ func TestMovieHandler_Create(t *testing.T) {
 				p.AssertNotCalled(t, "Produce", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
 			},
 			expectedStatus: http.StatusBadRequest,
-			expectedBody:   `{"error":"Title is required"}`,
+			expectedBody:   problem(http.StatusBadRequest, "field_required", "Title is required"),
 		},
 		{
 			name: "missing description",
@@ -729,7 +1348,7 @@ func TestMovieHandler_Create(t *testing.T) {
 				p.AssertNotCalled(t, "Produce", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
 			},
 			expectedStatus: http.StatusBadRequest,
-			expectedBody:   `{"error":"Description is required"}`,
+			expectedBody:   problem(http.StatusBadRequest, "field_required", "Description is required"),
 		},
 		{
 			name: "invalid rating",
@@ -744,7 +1363,7 @@ func TestMovieHandler_Create(t *testing.T) {
 				p.AssertNotCalled(t, "Produce", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
 			},
 			expectedStatus: http.StatusBadRequest,
-			expectedBody:   `{"error":"Rating must be between 0 and 10"}`,
+			expectedBody:   problem(http.StatusBadRequest, "rating_out_of_range", "Rating must be between 0 and 10"),
 		},
 		{
 			name: "controller error",
@@ -768,7 +1387,7 @@ func TestMovieHandler_Create(t *testing.T) {
 				p.AssertNotCalled(t, "Produce", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
 			},
 			expectedStatus: http.StatusInternalServerError,
-			expectedBody:   `{"error":"database error"}`,
+			expectedBody:   problem(http.StatusInternalServerError, apperror.CodeInternal, "Internal Server Error"),
 		},
 		{
 			name: "produce error",
@@ -798,7 +1417,50 @@ func TestMovieHandler_Create(t *testing.T) {
 				p.On("Produce", mock.Anything, "movies", mock.Anything, mock.Anything).Return(errors.New("kafka produce error"))
 			},
 			expectedStatus: http.StatusCreated,
-			expectedBody:   `{"id":1,"title":"Test Movie","description":"Test Description","release_year":2023,"rating":8.5}`,
+			expectedBody:   `{"id":1,"title":"Test Movie","description":"Test Description","release_year":2023,"rating":8.5,"view_count":0}`,
+		},
+		{
+			name: "likely duplicate",
+			requestBody: map[string]interface{}{
+				"title":        "The Matrix",
+				"description":  "Test Description",
+				"release_year": 1999,
+			},
+			setupMock: func(m *MockMovieController) {
+				expectedReq := dto.CreateMovieRequest{Title: "The Matrix", Description: "Test Description", ReleaseYear: 1999}
+				m.On("CreateMovie", mock.Anything, expectedReq).
+					Return(dto.MovieResponse{}, &domain.DuplicateMovieError{ExistingID: 7})
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody:   `{"type":"about:blank","title":"Conflict","status":409,"code":"duplicate_movie","detail":"movie looks like a duplicate of movie 7","existing_id":7}`,
+		},
+		{
+			name: "forced duplicate",
+			requestBody: map[string]interface{}{
+				"title":        "The Matrix",
+				"description":  "Test Description",
+				"release_year": 1999,
+			},
+			query: "?force=true",
+			setupMock: func(m *MockMovieController) {
+				expectedReq := dto.CreateMovieRequest{Title: "The Matrix", Description: "Test Description", ReleaseYear: 1999, Force: true}
+				m.On("CreateMovie", mock.Anything, expectedReq).
+					Return(dto.MovieResponse{ID: 8, Title: "The Matrix", Description: "Test Description", ReleaseYear: 1999}, nil)
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   `{"id":8,"title":"The Matrix","description":"Test Description","release_year":1999,"rating":0,"view_count":0}`,
+		},
+		{
+			name: "invalid force",
+			requestBody: map[string]interface{}{
+				"title":        "The Matrix",
+				"description":  "Test Description",
+				"release_year": 1999,
+			},
+			query:          "?force=maybe",
+			setupMock:      func(m *MockMovieController) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   problem(http.StatusBadRequest, "invalid_parameter", "invalid force parameter"),
 		},
 	}
 
@@ -807,6 +1469,7 @@ func TestMovieHandler_Create(t *testing.T) {
 			// Setup
 			gin.SetMode(gin.TestMode)
 			r := gin.New()
+			r.Use(apperror.Middleware())
 			mockCtrl := new(MockMovieController)
 
 			// Setup mocks
@@ -818,12 +1481,12 @@ func TestMovieHandler_Create(t *testing.T) {
 			}
 
 			producerPool := kafka.NewProducerPool(producer, 1, 10)
-			handler := NewMovieHandler(mockCtrl, producerPool)
+			handler := NewMovieHandler(mockCtrl, kafkaBus(producerPool))
 
 			r.POST("/movies", handler.Create)
 
 			jsonBody, _ := json.Marshal(tt.requestBody)
-			req, _ := http.NewRequest("POST", "/movies", bytes.NewBuffer(jsonBody))
+			req, _ := http.NewRequest("POST", "/movies"+tt.query, bytes.NewBuffer(jsonBody))
 			req.Header.Set("Content-Type", "application/json")
 
 			w := httptest.NewRecorder()
@@ -843,9 +1506,10 @@ func TestMovieHandler_GetByID(t *testing.T) {
 	tests := []struct {
 		name           string
 		movieID        string
-		setupMock      func(*MockMovieController, int)
-		expectedStatus int
-		expectedBody   string
+		setupMock        func(*MockMovieController, int)
+		expectedStatus   int
+		expectedBody     string
+		expectedLocation string
 	}{
 		{
 			name:    "success",
@@ -861,7 +1525,7 @@ func TestMovieHandler_GetByID(t *testing.T) {
 					}, nil)
 			},
 			expectedStatus: http.StatusOK,
-			expectedBody:   `{"id":1,"title":"Test Movie","description":"Test Description","release_year":2023,"rating":8.5}`,
+			expectedBody:   `{"id":1,"title":"Test Movie","description":"Test Description","release_year":2023,"rating":8.5,"view_count":0}`,
 		},
 		{
 			name:    "invalid id",
@@ -870,7 +1534,27 @@ func TestMovieHandler_GetByID(t *testing.T) {
 				// No mock setup needed for this case
 			},
 			expectedStatus: http.StatusBadRequest,
-			expectedBody:   `{"error":"invalid id"}`,
+			expectedBody:   problem(http.StatusBadRequest, "invalid_id", "invalid id"),
+		},
+		{
+			name:    "merged movie redirects",
+			movieID: "2",
+			setupMock: func(m *MockMovieController, id int) {
+				m.On("GetMovieByID", mock.Anything, id).Return(dto.MovieResponse{}, domain.ErrMovieNotFound)
+				m.On("ResolveMergedMovieID", mock.Anything, id).Return(1, nil)
+			},
+			expectedStatus:   http.StatusMovedPermanently,
+			expectedLocation: "/movies/1",
+		},
+		{
+			name:    "not found",
+			movieID: "999",
+			setupMock: func(m *MockMovieController, id int) {
+				m.On("GetMovieByID", mock.Anything, id).Return(dto.MovieResponse{}, domain.ErrMovieNotFound)
+				m.On("ResolveMergedMovieID", mock.Anything, id).Return(0, domain.ErrMovieNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   problem(http.StatusNotFound, "movie_not_found", "movie not found"),
 		},
 	}
 
@@ -879,6 +1563,7 @@ func TestMovieHandler_GetByID(t *testing.T) {
 			// Setup
 			gin.SetMode(gin.TestMode)
 			r := gin.New()
+			r.Use(apperror.Middleware())
 			mockCtrl := new(MockMovieController)
 			producer := kafka.NewMockProducer()
 			producer.On("Produce", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
@@ -902,6 +1587,9 @@ func TestMovieHandler_GetByID(t *testing.T) {
 			if tt.expectedBody != "" {
 				assert.JSONEq(t, tt.expectedBody, w.Body.String())
 			}
+			if tt.expectedLocation != "" {
+				assert.Equal(t, tt.expectedLocation, w.Header().Get("Location"))
+			}
 		})
 	}
 }
@@ -947,7 +1635,7 @@ func TestMovieHandler_List(t *testing.T) {
 					}, nil)
 			},
 			expectedStatus: http.StatusOK,
-			expectedBody:   `{"movies":[{"id":1,"title":"Movie 1","description":"Description 1","release_year":2023,"rating":8.5},{"id":2,"title":"Movie 2","description":"Description 2","release_year":2024,"rating":9}]}`,
+			expectedBody:   `{"movies":[{"id":1,"title":"Movie 1","description":"Description 1","release_year":2023,"rating":8.5,"view_count":0},{"id":2,"title":"Movie 2","description":"Description 2","release_year":2024,"rating":9,"view_count":0}]}`,
 		},
 		{
 			name: "controller error",
@@ -956,7 +1644,7 @@ func TestMovieHandler_List(t *testing.T) {
 					Return(dto.MoviesListResponse{}, errors.New("database error"))
 			},
 			expectedStatus: http.StatusInternalServerError,
-			expectedBody:   `{"error":"database error"}`,
+			expectedBody:   problem(http.StatusInternalServerError, apperror.CodeInternal, "Internal Server Error"),
 		},
 	}
 
@@ -964,6 +1652,7 @@ func TestMovieHandler_List(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			gin.SetMode(gin.TestMode)
 			r := gin.New()
+			r.Use(apperror.Middleware())
 			mockCtrl := new(MockMovieController)
 			producer := kafka.NewMockProducer()
 			producer.On("Produce", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
@@ -986,71 +1675,76 @@ func TestMovieHandler_List(t *testing.T) {
 	}
 }
 
-// TestMovieHandler_Search тестирует метод Search у MovieHandler
-func TestMovieHandler_Search(t *testing.T) {
+// TestMovieHandler_Fields тестирует выбор полей фильма параметром fields
+func TestMovieHandler_Fields(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(apperror.Middleware())
+	mockCtrl := new(MockMovieController)
+	producer := kafka.NewMockProducer()
+	producer.On("Produce", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	handler := newTestMovieHandler(mockCtrl, producer)
+	r.GET("/movies", handler.List)
+	r.GET("/movies/:id", handler.GetByID)
+
+	movie := dto.MovieResponse{ID: 1, Title: "Movie 1", Description: "Description 1", ReleaseYear: 2023, Rating: 8.5}
+	mockCtrl.On("ListMovies", mock.Anything).
+		Return(dto.MoviesListResponse{Movies: []dto.MovieResponse{movie}, NextCursor: "abc"}, nil)
+	mockCtrl.On("GetMovieByID", mock.Anything, 1).Return(movie, nil)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/movies?fields=id,title,rating", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"movies":[{"id":1,"title":"Movie 1","rating":8.5}],"next_cursor":"abc"}`, w.Body.String())
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/movies/1?fields=title", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"title":"Movie 1"}`, w.Body.String())
+
+	// Неизвестное поле отклоняется до обращения к контроллеру
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/movies?fields=id,budget", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), `"key":"list.fields.unknown"`)
+	assert.Contains(t, w.Body.String(), `"index":1`)
+	mockCtrl.AssertNumberOfCalls(t, "ListMovies", 1)
+}
+
+// TestMovieHandler_GetByIDAsOf тестирует исторический запрос фильма через as_of
+func TestMovieHandler_GetByIDAsOf(t *testing.T) {
 	tests := []struct {
 		name           string
-		titleQuery     string
-		actorQuery     string
 		setupMock      func(*MockMovieController)
 		expectedStatus int
 		expectedBody   string
 	}{
 		{
-			name:       "search by title",
-			titleQuery: "matrix",
+			name: "movie as of date",
 			setupMock: func(m *MockMovieController) {
-				m.On("SearchMoviesByTitle", mock.Anything).
-					Return(dto.MoviesListResponse{
-						Movies: []dto.MovieResponse{
-							{
-								ID:          1,
-								Title:       "The Matrix",
-								Description: "A computer hacker learns about the true nature of reality",
-								ReleaseYear: 1999,
-								Rating:      8.7,
-							},
-						},
-					}, nil)
+				m.On("GetMovieByIDAsOf", mock.Anything, 1, "2024-01-01").
+					Return(dto.MovieResponse{ID: 1, Title: "Old Title", ReleaseYear: 2010, Rating: 7.5}, nil)
 			},
 			expectedStatus: http.StatusOK,
-			expectedBody:   `{"movies":[{"id":1,"title":"The Matrix","description":"A computer hacker learns about the true nature of reality","release_year":1999,"rating":8.7}]}`,
+			expectedBody:   `{"id":1,"title":"Old Title","description":"","release_year":2010,"rating":7.5,"view_count":0}`,
 		},
 		{
-			name:       "search by actor",
-			actorQuery: "keanu",
+			name: "not existing at that time",
 			setupMock: func(m *MockMovieController) {
-				m.On("SearchMoviesByActorName", mock.Anything).
-					Return(dto.MoviesListResponse{
-						Movies: []dto.MovieResponse{
-							{
-								ID:          2,
-								Title:       "John Wick",
-								Description: "An ex-hit-man comes out of retirement to track down the gangsters that took everything from him.",
-								ReleaseYear: 2014,
-								Rating:      7.4,
-							},
-						},
-					}, nil)
+				m.On("GetMovieByIDAsOf", mock.Anything, 1, "2024-01-01").
+					Return(dto.MovieResponse{}, domain.ErrMovieNotFound)
 			},
-			expectedStatus: http.StatusBadRequest,
-			expectedBody:   `{"error":"at least one search parameter (title or actorName) is required"}`,
-		},
-		{
-			name:           "empty query",
-			setupMock:      func(m *MockMovieController) {},
-			expectedStatus: http.StatusBadRequest,
-			expectedBody:   `{"error":"at least one search parameter (title or actorName) is required"}`,
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   problem(http.StatusNotFound, "movie_not_found", "movie not found"),
 		},
 		{
-			name:       "controller error",
-			titleQuery: "error",
+			name: "invalid as_of",
 			setupMock: func(m *MockMovieController) {
-				m.On("SearchMoviesByTitle", mock.Anything).
-					Return(dto.MoviesListResponse{}, errors.New("search error"))
+				m.On("GetMovieByIDAsOf", mock.Anything, 1, "2024-01-01").
+					Return(dto.MovieResponse{}, fmt.Errorf("validation error: %w", dto.ValidationErrors{dto.NewFieldError(dto.KeyMovieAsOfInvalid)}))
 			},
-			expectedStatus: http.StatusInternalServerError,
-			expectedBody:   `{"error":"search error"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   validationProblem(dto.KeyMovieAsOfInvalid),
 		},
 	}
 
@@ -1058,94 +1752,405 @@ func TestMovieHandler_Search(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			gin.SetMode(gin.TestMode)
 			r := gin.New()
+			r.Use(apperror.Middleware())
 			mockCtrl := new(MockMovieController)
 			producer := kafka.NewMockProducer()
-			producer.On("Produce", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
 			handler := newTestMovieHandler(mockCtrl, producer)
 
-			producer.AssertNotCalled(t, "Produce", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
-
 			tt.setupMock(mockCtrl)
 
-			r.GET("/movies/search", handler.Search)
-
-			// Build URL with query parameters
-			url := "/movies/search?"
-			if tt.titleQuery != "" {
-				url += "title=" + tt.titleQuery
-			}
-			if tt.actorQuery != "" {
-				if tt.titleQuery != "" {
-					url += "&"
-				}
-				url += "actor=" + tt.actorQuery
-			}
-
-			req, _ := http.NewRequest("GET", url, nil)
+			r.GET("/movies/:id", handler.GetByID)
+			req, _ := http.NewRequest("GET", "/movies/1?as_of=2024-01-01", nil)
 			w := httptest.NewRecorder()
-
 			r.ServeHTTP(w, req)
 
 			assert.Equal(t, tt.expectedStatus, w.Code)
-			if tt.expectedBody != "" {
-				assert.JSONEq(t, tt.expectedBody, w.Body.String())
-			}
+			assert.JSONEq(t, tt.expectedBody, w.Body.String())
+			mockCtrl.AssertNotCalled(t, "GetMovieByID", mock.Anything, mock.Anything)
+			producer.AssertNotCalled(t, "Produce", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
 		})
 	}
 }
 
-// TestMovieHandler_CreateWithActors тестирует метод CreateWithActors у MovieHandler
-func TestMovieHandler_CreateWithActors(t *testing.T) {
+// TestMovieHandler_Upcoming тестирует метод Upcoming у MovieHandler
+func TestMovieHandler_Upcoming(t *testing.T) {
 	tests := []struct {
 		name           string
-		requestBody    string
 		setupMock      func(*MockMovieController)
 		expectedStatus int
 		expectedBody   string
 	}{
 		{
-			name: "success with actors",
-			requestBody: `{
-				"title": "Movie",
-				"description": "Description with actors",
-				"release_year": 2020,
-				"rating": 7.5,
-				"actor_ids": [1, 2, 3]
-			}`,
+			name: "upcoming movies",
 			setupMock: func(m *MockMovieController) {
-				expectedReq := dto.MovieWithActorsRequest{
-					Title:       "Movie",
-					Description: "Description with actors",
-					ReleaseYear: 2020,
-					Rating:      7.5,
-					ActorIDs:    []int{1, 2, 3},
-				}
-				m.On("CreateMovieWithActors", mock.Anything, expectedReq).
-					Return(dto.MovieResponse{
-						ID:          1,
-						Title:       "Movie",
-						Description: "Description with actors",
-						ReleaseYear: 2020,
-						Rating:      7.5,
+				m.On("GetUpcomingMovies", mock.Anything).
+					Return(dto.MoviesListResponse{
+						Movies: []dto.MovieResponse{
+							{ID: 3, Title: "Sequel", ReleaseYear: 2027, ReleaseDate: "2027-03-05"},
+						},
 					}, nil)
 			},
-			expectedStatus: http.StatusCreated,
-			expectedBody:   `{"id":1,"title":"Movie","description":"Description with actors","release_year":2020,"rating":7.5}`,
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"movies":[{"id":3,"title":"Sequel","description":"","release_year":2027,"release_date":"2027-03-05","rating":0,"view_count":0}]}`,
 		},
 		{
-			name: "missing required fields",
-			requestBody: `{
-				"description": "Missing title",
-				"release_year": 2020,
-				"actor_ids": [1, 2]
-			}`,
-			setupMock:      func(m *MockMovieController) {},
-			expectedStatus: http.StatusBadRequest,
-			expectedBody:   `{"error":"invalid request"}`,
+			name: "controller error",
+			setupMock: func(m *MockMovieController) {
+				m.On("GetUpcomingMovies", mock.Anything).
+					Return(dto.MoviesListResponse{}, errors.New("database error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   problem(http.StatusInternalServerError, apperror.CodeInternal, "Internal Server Error"),
 		},
-		{
-			name: "invalid actor ids",
-			requestBody: `{
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			r := gin.New()
+			r.Use(apperror.Middleware())
+			mockCtrl := new(MockMovieController)
+			handler := newTestMovieHandler(mockCtrl, kafka.NewMockProducer())
+
+			tt.setupMock(mockCtrl)
+
+			r.GET("/movies/upcoming", handler.Upcoming)
+			req, _ := http.NewRequest("GET", "/movies/upcoming", nil)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.JSONEq(t, tt.expectedBody, w.Body.String())
+		})
+	}
+}
+
+func TestMovieHandler_Popular(t *testing.T) {
+	tests := []struct {
+		name           string
+		setupMock      func(*MockMovieController)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "popular movies",
+			setupMock: func(m *MockMovieController) {
+				m.On("GetPopularMovies", mock.Anything).
+					Return(dto.MoviesListResponse{
+						Movies: []dto.MovieResponse{
+							{ID: 1, Title: "Inception", ReleaseYear: 2010, Rating: 8.8, ViewCount: 42},
+						},
+					}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"movies":[{"id":1,"title":"Inception","description":"","release_year":2010,"rating":8.8,"view_count":42}]}`,
+		},
+		{
+			name: "invalid limit",
+			setupMock: func(m *MockMovieController) {
+				m.On("GetPopularMovies", mock.Anything).
+					Return(dto.MoviesListResponse{}, fmt.Errorf("validation error: %w", dto.ValidationErrors{dto.NewFieldError(dto.KeyListLimitInvalid)}))
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   validationProblem(dto.KeyListLimitInvalid),
+		},
+		{
+			name: "controller error",
+			setupMock: func(m *MockMovieController) {
+				m.On("GetPopularMovies", mock.Anything).
+					Return(dto.MoviesListResponse{}, errors.New("database error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   problem(http.StatusInternalServerError, apperror.CodeInternal, "Internal Server Error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			r := gin.New()
+			r.Use(apperror.Middleware())
+			mockCtrl := new(MockMovieController)
+			handler := newTestMovieHandler(mockCtrl, kafka.NewMockProducer())
+
+			tt.setupMock(mockCtrl)
+
+			r.GET("/movies/popular", handler.Popular)
+			req, _ := http.NewRequest("GET", "/movies/popular", nil)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.JSONEq(t, tt.expectedBody, w.Body.String())
+		})
+	}
+}
+
+func TestMovieHandler_ByPeriod(t *testing.T) {
+	tests := []struct {
+		name           string
+		path           string
+		setupMock      func(*MockMovieController)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "movies of a year",
+			path: "/movies/year/2010",
+			setupMock: func(m *MockMovieController) {
+				m.On("GetMoviesByYear", mock.Anything, "2010").
+					Return(dto.MoviesByPeriodResponse{
+						FromYear:   2010,
+						ToYear:     2010,
+						Total:      1,
+						Movies:     []dto.MovieResponse{{ID: 1, Title: "Inception", ReleaseYear: 2010, Rating: 8.8}},
+						Pagination: &dto.Pagination{Limit: 20, Total: 1, TotalPages: 1},
+					}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"from_year":2010,"to_year":2010,"total":1,"movies":[{"id":1,"title":"Inception","description":"","release_year":2010,"rating":8.8,"view_count":0}],"pagination":{"limit":20,"offset":0,"total":1,"total_pages":1}}`,
+		},
+		{
+			name: "movies of a decade",
+			path: "/movies/decade/1990s",
+			setupMock: func(m *MockMovieController) {
+				m.On("GetMoviesByDecade", mock.Anything, "1990s").
+					Return(dto.MoviesByPeriodResponse{FromYear: 1990, ToYear: 1999, Movies: []dto.MovieResponse{}}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"from_year":1990,"to_year":1999,"total":0,"movies":[]}`,
+		},
+		{
+			name: "invalid decade",
+			path: "/movies/decade/1995",
+			setupMock: func(m *MockMovieController) {
+				m.On("GetMoviesByDecade", mock.Anything, "1995").
+					Return(dto.MoviesByPeriodResponse{}, fmt.Errorf("validation error: %w", dto.ValidationErrors{dto.NewFieldError(dto.KeyMovieDecadeInvalid)}))
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   validationProblem(dto.KeyMovieDecadeInvalid),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			r := gin.New()
+			r.Use(apperror.Middleware())
+			mockCtrl := new(MockMovieController)
+			handler := newTestMovieHandler(mockCtrl, kafka.NewMockProducer())
+
+			tt.setupMock(mockCtrl)
+
+			r.GET("/movies/year/:year", handler.ByYear)
+			r.GET("/movies/decade/:decade", handler.ByDecade)
+			req, _ := http.NewRequest("GET", tt.path, nil)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.JSONEq(t, tt.expectedBody, w.Body.String())
+			mockCtrl.AssertExpectations(t)
+		})
+	}
+}
+
+// TestMovieHandler_Search тестирует метод Search у MovieHandler
+func TestMovieHandler_Search(t *testing.T) {
+	tests := []struct {
+		name           string
+		titleQuery     string
+		actorQuery     string
+		countryQuery   string
+		runtimeQuery   string
+		setupMock      func(*MockMovieController)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:       "search by title",
+			titleQuery: "matrix",
+			setupMock: func(m *MockMovieController) {
+				m.On("SearchMoviesByTitle", mock.Anything).
+					Return(dto.MoviesListResponse{
+						Movies: []dto.MovieResponse{
+							{
+								ID:          1,
+								Title:       "The Matrix",
+								Description: "A computer hacker learns about the true nature of reality",
+								ReleaseYear: 1999,
+								Rating:      8.7,
+							},
+						},
+					}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"movies":[{"id":1,"title":"The Matrix","description":"A computer hacker learns about the true nature of reality","release_year":1999,"rating":8.7,"view_count":0}]}`,
+		},
+		{
+			name:       "search by actor",
+			actorQuery: "keanu",
+			setupMock: func(m *MockMovieController) {
+				m.On("SearchMoviesByActorName", mock.Anything).
+					Return(dto.MoviesListResponse{
+						Movies: []dto.MovieResponse{
+							{
+								ID:          2,
+								Title:       "John Wick",
+								Description: "An ex-hit-man comes out of retirement to track down the gangsters that took everything from him.",
+								ReleaseYear: 2014,
+								Rating:      7.4,
+							},
+						},
+					}, nil)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   problem(http.StatusBadRequest, "search_parameter_required", "at least one search parameter (title, actorName, language, country, runtime_min or runtime_max) is required"),
+		},
+		{
+			name:         "filter by country only",
+			countryQuery: "JP",
+			setupMock: func(m *MockMovieController) {
+				m.On("SearchMoviesByTitle", mock.Anything).
+					Return(dto.MoviesListResponse{
+						Movies: []dto.MovieResponse{{ID: 3, Title: "Spirited Away", ReleaseYear: 2001, OriginalLanguage: "ja", Country: "JP"}},
+					}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"movies":[{"id":3,"title":"Spirited Away","description":"","release_year":2001,"rating":0,"view_count":0,"original_language":"ja","country":"JP"}]}`,
+		},
+		{
+			name:         "filter by runtime only",
+			runtimeQuery: "100",
+			setupMock: func(m *MockMovieController) {
+				m.On("SearchMoviesByTitle", mock.Anything).
+					Return(dto.MoviesListResponse{
+						Movies: []dto.MovieResponse{{ID: 4, Title: "Rashomon", ReleaseYear: 1950, RuntimeMinutes: 88}},
+					}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"movies":[{"id":4,"title":"Rashomon","description":"","release_year":1950,"rating":0,"view_count":0,"runtime_minutes":88}]}`,
+		},
+		{
+			name:           "empty query",
+			setupMock:      func(m *MockMovieController) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   problem(http.StatusBadRequest, "search_parameter_required", "at least one search parameter (title, actorName, language, country, runtime_min or runtime_max) is required"),
+		},
+		{
+			name:       "controller error",
+			titleQuery: "error",
+			setupMock: func(m *MockMovieController) {
+				m.On("SearchMoviesByTitle", mock.Anything).
+					Return(dto.MoviesListResponse{}, errors.New("search error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   problem(http.StatusInternalServerError, apperror.CodeInternal, "Internal Server Error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			r := gin.New()
+			r.Use(apperror.Middleware())
+			mockCtrl := new(MockMovieController)
+			producer := kafka.NewMockProducer()
+			producer.On("Produce", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+			handler := newTestMovieHandler(mockCtrl, producer)
+
+			producer.AssertNotCalled(t, "Produce", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+
+			tt.setupMock(mockCtrl)
+
+			r.GET("/movies/search", handler.Search)
+
+			// Build URL with query parameters
+			url := "/movies/search?"
+			if tt.titleQuery != "" {
+				url += "title=" + tt.titleQuery
+			}
+			if tt.actorQuery != "" {
+				if tt.titleQuery != "" {
+					url += "&"
+				}
+				url += "actor=" + tt.actorQuery
+			}
+			if tt.countryQuery != "" {
+				url += "country=" + tt.countryQuery
+			}
+			if tt.runtimeQuery != "" {
+				url += "runtime_max=" + tt.runtimeQuery
+			}
+
+			req, _ := http.NewRequest("GET", url, nil)
+			w := httptest.NewRecorder()
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedBody != "" {
+				assert.JSONEq(t, tt.expectedBody, w.Body.String())
+			}
+		})
+	}
+}
+
+// TestMovieHandler_CreateWithActors тестирует метод CreateWithActors у MovieHandler
+func TestMovieHandler_CreateWithActors(t *testing.T) {
+	tests := []struct {
+		name           string
+		requestBody    string
+		setupMock      func(*MockMovieController)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "success with actors",
+			requestBody: `{
+				"title": "Movie",
+				"description": "Description with actors",
+				"release_year": 2020,
+				"rating": 7.5,
+				"actor_ids": [1, 2, 3]
+			}`,
+			setupMock: func(m *MockMovieController) {
+				expectedReq := dto.MovieWithActorsRequest{
+					Title:       "Movie",
+					Description: "Description with actors",
+					ReleaseYear: 2020,
+					Rating:      7.5,
+					ActorIDs:    []int{1, 2, 3},
+				}
+				m.On("CreateMovieWithActors", mock.Anything, expectedReq).
+					Return(dto.MovieResponse{
+						ID:          1,
+						Title:       "Movie",
+						Description: "Description with actors",
+						ReleaseYear: 2020,
+						Rating:      7.5,
+					}, nil)
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   `{"id":1,"title":"Movie","description":"Description with actors","release_year":2020,"rating":7.5,"view_count":0}`,
+		},
+		{
+			name: "missing required fields",
+			requestBody: `{
+				"description": "Missing title",
+				"release_year": 2020,
+				"actor_ids": [1, 2]
+			}`,
+			setupMock:      func(m *MockMovieController) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"type":"about:blank","title":"Bad Request","status":400,"code":"validation_failed",` +
+				`"detail":"validation error: title: is required; rating: is required","errors":[` +
+				`{"field":"title","key":"request.field.required","message":"is required"},` +
+				`{"field":"rating","key":"request.field.required","message":"is required"}]}`,
+		},
+		{
+			name: "invalid actor ids",
+			requestBody: `{
 				"title": "Invalid Actors",
 				"description": "Test",
 				"release_year": 2023,
@@ -1154,7 +2159,9 @@ func TestMovieHandler_CreateWithActors(t *testing.T) {
 			}`,
 			setupMock:      func(m *MockMovieController) {},
 			expectedStatus: http.StatusBadRequest,
-			expectedBody:   `{"error":"invalid request"}`,
+			expectedBody:   `{"type":"about:blank","title":"Bad Request","status":400,"code":"validation_failed",` +
+				`"detail":"validation error: actor_ids[0]: has the wrong type","errors":[` +
+				`{"field":"actor_ids","index":0,"expected":"integer","key":"request.field.invalid_type","message":"has the wrong type"}]}`,
 		},
 		{
 			name: "controller error",
@@ -1170,7 +2177,7 @@ func TestMovieHandler_CreateWithActors(t *testing.T) {
 					Return(dto.MovieResponse{}, errors.New("database error"))
 			},
 			expectedStatus: http.StatusInternalServerError,
-			expectedBody:   `{"error":"database error"}`,
+			expectedBody:   problem(http.StatusInternalServerError, apperror.CodeInternal, "Internal Server Error"),
 		},
 	}
 
@@ -1178,6 +2185,7 @@ func TestMovieHandler_CreateWithActors(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			gin.SetMode(gin.TestMode)
 			r := gin.New()
+			r.Use(apperror.Middleware())
 			mockCtrl := new(MockMovieController)
 			producer := kafka.NewMockProducer()
 			producer.On("Produce", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
@@ -1201,15 +2209,83 @@ func TestMovieHandler_CreateWithActors(t *testing.T) {
 	}
 }
 
+// TestMovieHandler_CreateFull тестирует создание фильма вместе с новыми актёрами
+func TestMovieHandler_CreateFull(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("success", func(t *testing.T) {
+		r := gin.New()
+		r.Use(apperror.Middleware())
+		mockCtrl := new(MockMovieController)
+		producer := kafka.NewMockProducer()
+		producer.On("Produce", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+		handler := newTestMovieHandler(mockCtrl, producer)
+
+		expectedReq := dto.FullMovieRequest{
+			Title:       "The Matrix",
+			ReleaseYear: 1999,
+			Actors:      []dto.NewCastMemberRequest{{Name: "Keanu Reeves", Gender: "male", BirthDate: "1964-09-02", CharacterName: "Neo", BillingOrder: 1}},
+			ActorIDs:    []int{5},
+			Force:       true,
+		}
+		mockCtrl.On("CreateMovieFull", mock.Anything, expectedReq).Return(dto.FullMovieResponse{
+			Movie:         dto.MovieResponse{ID: 10, Title: "The Matrix", ReleaseYear: 1999},
+			Actors:        []dto.ActorResponse{{ID: 9, Name: "Keanu Reeves", Gender: "male", BirthDate: "1964-09-02", CharacterName: "Neo", BillingOrder: 1}, {ID: 5, Name: "Carrie-Anne Moss"}},
+			ActorsCreated: 1,
+		}, nil)
+
+		r.POST("/movies/full", handler.CreateFull)
+		req, _ := http.NewRequest("POST", "/movies/full?force=true", bytes.NewBufferString(`{
+			"title": "The Matrix",
+			"release_year": 1999,
+			"actors": [{"name": "Keanu Reeves", "gender": "male", "birth_date": "1964-09-02", "character_name": "Neo", "billing_order": 1}],
+			"actor_ids": [5]
+		}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		var resp dto.FullMovieResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, 10, resp.Movie.ID)
+		assert.Len(t, resp.Actors, 2)
+		assert.Equal(t, 1, resp.ActorsCreated)
+		mockCtrl.AssertExpectations(t)
+	})
+
+	t.Run("inline actor missing field", func(t *testing.T) {
+		r := gin.New()
+		r.Use(apperror.Middleware())
+		mockCtrl := new(MockMovieController)
+		handler := newTestMovieHandler(mockCtrl, kafka.NewMockProducer())
+
+		r.POST("/movies/full", handler.CreateFull)
+		req, _ := http.NewRequest("POST", "/movies/full", bytes.NewBufferString(`{
+			"title": "The Matrix",
+			"release_year": 1999,
+			"actors": [{"name": "Keanu Reeves", "gender": "male"}]
+		}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), `"field":"actors[0].birth_date"`)
+		mockCtrl.AssertNotCalled(t, "CreateMovieFull", mock.Anything, mock.Anything)
+	})
+}
+
 // TestMovieHandler_Update тестирует метод Update у MovieHandler
 func TestMovieHandler_Update(t *testing.T) {
 	tests := []struct {
 		name           string
 		movieID        string
 		requestBody    string
-		setupMock      func(*MockMovieController, int)
-		expectedStatus int
-		expectedBody   string
+		setupMock        func(*MockMovieController, int)
+		expectedStatus   int
+		expectedBody     string
+		expectedLocation string
 	}{
 		{
 			name:    "success",
@@ -1243,7 +2319,7 @@ func TestMovieHandler_Update(t *testing.T) {
 				}, nil)
 			},
 			expectedStatus: http.StatusOK,
-			expectedBody:   `{"id":1,"title":"Updated Movie","description":"Updated description","release_year":2023,"rating":9}`,
+			expectedBody:   `{"id":1,"title":"Updated Movie","description":"Updated description","release_year":2023,"rating":9,"view_count":0}`,
 		},
 		{
 			name:           "invalid id",
@@ -1251,7 +2327,7 @@ func TestMovieHandler_Update(t *testing.T) {
 			requestBody:    `{"title":"Test"}`,
 			setupMock:      func(m *MockMovieController, id int) {},
 			expectedStatus: http.StatusBadRequest,
-			expectedBody:   `{"error":"invalid id"}`,
+			expectedBody:   problem(http.StatusBadRequest, "invalid_id", "invalid id"),
 		},
 		{
 			name:        "not found",
@@ -1259,10 +2335,10 @@ func TestMovieHandler_Update(t *testing.T) {
 			requestBody: `{"title":"Not Found"}`,
 			setupMock: func(m *MockMovieController, id int) {
 				m.On("UpdateMovie", mock.Anything, id, mock.Anything).
-					Return(dto.MovieResponse{}, errors.New("movie not found"))
+					Return(dto.MovieResponse{}, domain.ErrMovieNotFound)
 			},
 			expectedStatus: http.StatusNotFound,
-			expectedBody:   `{"error":"movie not found"}`,
+			expectedBody:   problem(http.StatusNotFound, "movie_not_found", "movie not found"),
 		},
 		{
 			name:        "invalid rating",
@@ -1272,10 +2348,10 @@ func TestMovieHandler_Update(t *testing.T) {
 				// The controller will be called and return a validation error
 				m.On("UpdateMovie", mock.Anything, id, mock.MatchedBy(func(req dto.UpdateMovieRequest) bool {
 					return *req.Rating == 11
-				})).Return(dto.MovieResponse{}, errors.New("validation error: rating: must be between 0 and 10"))
+				})).Return(dto.MovieResponse{}, fmt.Errorf("validation error: %w", dto.ValidationErrors{dto.NewFieldError(dto.KeyMovieRatingOutOfRange)}))
 			},
-			expectedStatus: http.StatusInternalServerError,
-			expectedBody:   `{"error":"validation error: rating: must be between 0 and 10"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   validationProblem(dto.KeyMovieRatingOutOfRange),
 		},
 	}
 
@@ -1283,6 +2359,7 @@ func TestMovieHandler_Update(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			gin.SetMode(gin.TestMode)
 			r := gin.New()
+			r.Use(apperror.Middleware())
 			mockCtrl := new(MockMovieController)
 			producer := kafka.NewMockProducer()
 			producer.On("Produce", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
@@ -1307,6 +2384,48 @@ func TestMovieHandler_Update(t *testing.T) {
 	}
 }
 
+func TestMovieHandler_UpsertByExternalID(t *testing.T) {
+	tests := []struct {
+		name           string
+		action         domain.MovieUpsertAction
+		expectedStatus int
+	}{
+		{name: "created", action: domain.MovieUpsertCreated, expectedStatus: http.StatusCreated},
+		{name: "updated", action: domain.MovieUpsertUpdated, expectedStatus: http.StatusOK},
+		{name: "unchanged", action: domain.MovieUpsertUnchanged, expectedStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			r := gin.New()
+			r.Use(apperror.Middleware())
+			mockCtrl := new(MockMovieController)
+			producer := kafka.NewMockProducer()
+			producer.On("Produce", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+			handler := newTestMovieHandler(mockCtrl, producer)
+
+			mockCtrl.On("UpsertMovieByExternalID", mock.Anything, "tt0133093",
+				dto.CreateMovieRequest{Title: "The Matrix", ReleaseYear: 1999, Rating: 8.7}).
+				Return(dto.MovieUpsertResponse{
+					Action: string(tt.action),
+					Movie:  dto.MovieResponse{ID: 5, Title: "The Matrix", ReleaseYear: 1999, Rating: 8.7, ExternalID: "tt0133093"},
+				}, nil)
+
+			r.PUT("/movies/external/:externalId", handler.UpsertByExternalID)
+			req, _ := http.NewRequest("PUT", "/movies/external/tt0133093",
+				bytes.NewBufferString(`{"title":"The Matrix","release_year":1999,"rating":8.7}`))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.JSONEq(t, `{"action":"`+string(tt.action)+`","movie":{"id":5,"title":"The Matrix","description":"","release_year":1999,`+
+				`"rating":8.7,"view_count":0,"external_id":"tt0133093"}}`, w.Body.String())
+		})
+	}
+}
+
 // TestMovieHandler_PartialUpdate тестирует метод PartialUpdate у MovieHandler
 func TestMovieHandler_PartialUpdate(t *testing.T) {
 	tests := []struct {
@@ -1330,9 +2449,10 @@ func TestMovieHandler_PartialUpdate(t *testing.T) {
 				}
 				m.On("PartialUpdateMovie", mock.Anything, id, mock.MatchedBy(func(update dto.MovieUpdate) bool {
 					return update.Title != nil && *update.Title == "New Title" && update.Description == nil && update.ReleaseYear == nil && update.Rating == nil
-				})).Return(nil)
+				})).Return(dto.MovieResponse{ID: id, Title: "New Title", ReleaseYear: 1999, Rating: 8.7}, nil)
 			},
 			expectedStatus: http.StatusOK,
+			expectedBody:   `{"id":1,"title":"New Title","description":"","release_year":1999,"rating":8.7,"view_count":0}`,
 		},
 		{
 			name:    "update rating only",
@@ -1347,7 +2467,7 @@ func TestMovieHandler_PartialUpdate(t *testing.T) {
 				}
 				m.On("PartialUpdateMovie", mock.Anything, id, mock.MatchedBy(func(update dto.MovieUpdate) bool {
 					return update.Title == nil && update.Description == nil && update.ReleaseYear == nil && update.Rating != nil && *update.Rating == rating
-				})).Return(nil)
+				})).Return(dto.MovieResponse{ID: id, Rating: rating}, nil)
 			},
 			expectedStatus: http.StatusOK,
 		},
@@ -1357,7 +2477,7 @@ func TestMovieHandler_PartialUpdate(t *testing.T) {
 			requestBody:    `{"title":"Test"}`,
 			setupMock:      func(m *MockMovieController, id int) {},
 			expectedStatus: http.StatusBadRequest,
-			expectedBody:   `{"error":"invalid id"}`,
+			expectedBody:   problem(http.StatusBadRequest, "invalid_id", "invalid id"),
 		},
 		{
 			name:        "not found",
@@ -1365,10 +2485,10 @@ func TestMovieHandler_PartialUpdate(t *testing.T) {
 			requestBody: `{"title":"Not Found"}`,
 			setupMock: func(m *MockMovieController, id int) {
 				m.On("PartialUpdateMovie", mock.Anything, id, mock.Anything).
-					Return(errors.New("movie not found"))
+					Return(dto.MovieResponse{}, domain.ErrMovieNotFound)
 			},
 			expectedStatus: http.StatusNotFound,
-			expectedBody:   `{"error":"movie not found"}`,
+			expectedBody:   problem(http.StatusNotFound, "movie_not_found", "movie not found"),
 		},
 		{
 			name:        "invalid rating",
@@ -1378,10 +2498,20 @@ func TestMovieHandler_PartialUpdate(t *testing.T) {
 				// The controller will be called and return a validation error
 				m.On("PartialUpdateMovie", mock.Anything, id, mock.MatchedBy(func(update dto.MovieUpdate) bool {
 					return update.Rating != nil && *update.Rating == 11
-				})).Return(errors.New("validation error: rating: must be between 0 and 10"))
+				})).Return(dto.MovieResponse{}, fmt.Errorf("validation error: %w", dto.ValidationErrors{dto.NewFieldError(dto.KeyMovieRatingOutOfRange)}))
 			},
-			expectedStatus: http.StatusInternalServerError,
-			expectedBody:   `{"error":"validation error: rating: must be between 0 and 10"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   validationProblem(dto.KeyMovieRatingOutOfRange),
+		},
+		{
+			name:           "wrong field type",
+			movieID:        "1",
+			requestBody:    `{"rating":"high"}`,
+			setupMock:      func(m *MockMovieController, id int) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: `{"type":"about:blank","title":"Bad Request","status":400,"code":"validation_failed",` +
+				`"detail":"validation error: rating: has the wrong type","errors":[` +
+				`{"field":"rating","expected":"number","key":"request.field.invalid_type","message":"has the wrong type"}]}`,
 		},
 	}
 
@@ -1389,6 +2519,7 @@ func TestMovieHandler_PartialUpdate(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			gin.SetMode(gin.TestMode)
 			r := gin.New()
+			r.Use(apperror.Middleware())
 			mockCtrl := new(MockMovieController)
 			producer := kafka.NewMockProducer()
 			producer.On("Produce", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
@@ -1418,9 +2549,10 @@ func TestMovieHandler_Delete(t *testing.T) {
 	tests := []struct {
 		name           string
 		movieID        string
-		setupMock      func(*MockMovieController, int)
-		expectedStatus int
-		expectedBody   string
+		setupMock        func(*MockMovieController, int)
+		expectedStatus   int
+		expectedBody     string
+		expectedLocation string
 	}{
 		{
 			name:    "success",
@@ -1435,17 +2567,29 @@ func TestMovieHandler_Delete(t *testing.T) {
 			movieID:        "abc",
 			setupMock:      func(m *MockMovieController, id int) {},
 			expectedStatus: http.StatusBadRequest,
-			expectedBody:   `{"error":"invalid id"}`,
+			expectedBody:   problem(http.StatusBadRequest, "invalid_id", "invalid id"),
 		},
 		{
 			name:    "not found",
 			movieID: "999",
 			setupMock: func(m *MockMovieController, id int) {
 				m.On("DeleteMovie", mock.Anything, id).
-					Return(errors.New("movie not found"))
+					Return(domain.ErrMovieNotFound)
 			},
 			expectedStatus: http.StatusNotFound,
-			expectedBody:   `{"error":"movie not found"}`,
+			expectedBody:   problem(http.StatusNotFound, "movie_not_found", "movie not found"),
+		},
+		{
+			name:    "referenced by collections",
+			movieID: "3",
+			setupMock: func(m *MockMovieController, id int) {
+				m.On("DeleteMovie", mock.Anything, id).
+					Return(&domain.MovieReferencedError{References: []domain.MovieReference{{Kind: "collections", Count: 2}}})
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody: `{"type":"about:blank","title":"Conflict","status":409,"code":"movie_referenced",` +
+				`"detail":"cannot delete movie: it is referenced by other records: collections (2)",` +
+				`"references":[{"kind":"collections","count":2}]}`,
 		},
 	}
 
@@ -1453,6 +2597,7 @@ func TestMovieHandler_Delete(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			gin.SetMode(gin.TestMode)
 			r := gin.New()
+			r.Use(apperror.Middleware())
 			mockCtrl := new(MockMovieController)
 			producer := kafka.NewMockProducer()
 			producer.On("Produce", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
@@ -1505,34 +2650,115 @@ func TestMovieHandler_UpdateMovieActors(t *testing.T) {
 				}, nil)
 			},
 			expectedStatus: http.StatusOK,
-			expectedBody:   `{"actors":[{"id":1,"name":"Actor 1","gender":"","birth_date":""},{"id":2,"name":"Actor 2","gender":"","birth_date":""}]}`,
+			expectedBody:   `{"actors":[{"id":1,"name":"Actor 1","gender":"","birth_date":""},{"id":2,"name":"Actor 2","gender":"","birth_date":""}]}`,
+		},
+		{
+			name:           "invalid movie id",
+			movieID:        "abc",
+			requestBody:    `{"actor_ids":[1,2]}`,
+			setupMock:      func(m *MockMovieController, id int, req dto.UpdateMovieActorsRequest) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   problem(http.StatusBadRequest, "invalid_id", "invalid movie id"),
+		},
+		{
+			name:        "movie not found",
+			movieID:     "999",
+			requestBody: `{"actor_ids":[1,2]}`,
+			setupMock: func(m *MockMovieController, id int, req dto.UpdateMovieActorsRequest) {
+				m.On("UpdateMovieActors", mock.Anything, id, mock.Anything).
+					Return(dto.MovieActorsResponse{}, domain.ErrMovieNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   problem(http.StatusNotFound, "movie_not_found", "movie not found"),
+		},
+		{
+			name:           "invalid request body",
+			movieID:        "1",
+			requestBody:    `{"actor_ids":["not_an_integer"]}`,
+			setupMock:      func(m *MockMovieController, id int, req dto.UpdateMovieActorsRequest) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"type":"about:blank","title":"Bad Request","status":400,"code":"validation_failed",` +
+				`"detail":"validation error: actor_ids[0]: has the wrong type","errors":[` +
+				`{"field":"actor_ids","index":0,"expected":"integer","key":"request.field.invalid_type","message":"has the wrong type"}]}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			r := gin.New()
+			r.Use(apperror.Middleware())
+			mockCtrl := new(MockMovieController)
+			producer := kafka.NewMockProducer()
+			producer.On("Produce", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+			handler := newTestMovieHandler(mockCtrl, producer)
+
+			producer.AssertNotCalled(t, "Produce", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+
+			movieID, _ := strconv.Atoi(tt.movieID)
+			var reqBody dto.UpdateMovieActorsRequest
+			_ = json.Unmarshal([]byte(tt.requestBody), &reqBody)
+			tt.setupMock(mockCtrl, movieID, reqBody)
+
+			r.PUT("/movies/:id/actors", handler.UpdateMovieActors)
+			req, _ := http.NewRequest("PUT", "/movies/"+tt.movieID+"/actors", bytes.NewBufferString(tt.requestBody))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedBody != "" {
+				assert.JSONEq(t, tt.expectedBody, w.Body.String())
+			}
+		})
+	}
+}
+
+// TestMovieHandler_ChangeMovieActors тестирует метод ChangeMovieActors у MovieHandler
+func TestMovieHandler_ChangeMovieActors(t *testing.T) {
+	tests := []struct {
+		name           string
+		movieID        string
+		requestBody    string
+		setupMock      func(*MockMovieController)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:        "add and remove",
+			movieID:     "1",
+			requestBody: `{"add": [{"actor_id": 3, "character_name": "Eames"}], "remove": [4]}`,
+			setupMock: func(m *MockMovieController) {
+				m.On("ChangeMovieActors", mock.Anything, 1, dto.ChangeMovieActorsRequest{
+					Add:    []dto.CastMemberRequest{{ActorID: 3, CharacterName: "Eames"}},
+					Remove: []int{4},
+				}).Return(dto.MovieActorsResponse{
+					Actors:          []dto.ActorResponse{{ID: 3, Name: "Tom Hardy", CharacterName: "Eames"}},
+					AddedActorIDs:   []int{3},
+					RemovedActorIDs: []int{4},
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"actors":[{"id":3,"name":"Tom Hardy","gender":"","birth_date":"","character_name":"Eames"}]}`,
 		},
 		{
 			name:           "invalid movie id",
 			movieID:        "abc",
-			requestBody:    `{"actor_ids":[1,2]}`,
-			setupMock:      func(m *MockMovieController, id int, req dto.UpdateMovieActorsRequest) {},
+			requestBody:    `{"remove":[4]}`,
+			setupMock:      func(m *MockMovieController) {},
 			expectedStatus: http.StatusBadRequest,
-			expectedBody:   `{"error":"invalid movie id"}`,
+			expectedBody:   problem(http.StatusBadRequest, "invalid_id", "invalid movie id"),
 		},
 		{
-			name:        "movie not found",
-			movieID:     "999",
-			requestBody: `{"actor_ids":[1,2]}`,
-			setupMock: func(m *MockMovieController, id int, req dto.UpdateMovieActorsRequest) {
-				m.On("UpdateMovieActors", mock.Anything, id, mock.Anything).
-					Return(dto.MovieActorsResponse{}, errors.New("movie not found"))
+			name:        "actor not found",
+			movieID:     "1",
+			requestBody: `{"add":[{"actor_id":999}]}`,
+			setupMock: func(m *MockMovieController) {
+				m.On("ChangeMovieActors", mock.Anything, 1, mock.Anything).
+					Return(dto.MovieActorsResponse{}, domain.ErrActorNotFound)
 			},
-			expectedStatus: http.StatusInternalServerError,
-			expectedBody:   `{"error":"movie not found"}`,
-		},
-		{
-			name:           "invalid request body",
-			movieID:        "1",
-			requestBody:    `{"actor_ids":["not_an_integer"]}`,
-			setupMock:      func(m *MockMovieController, id int, req dto.UpdateMovieActorsRequest) {},
-			expectedStatus: http.StatusBadRequest,
-			expectedBody:   `{"error":"invalid request"}`,
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   problem(http.StatusNotFound, "actor_not_found", "actor not found"),
 		},
 	}
 
@@ -1540,28 +2766,22 @@ func TestMovieHandler_UpdateMovieActors(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			gin.SetMode(gin.TestMode)
 			r := gin.New()
+			r.Use(apperror.Middleware())
 			mockCtrl := new(MockMovieController)
+			tt.setupMock(mockCtrl)
 			producer := kafka.NewMockProducer()
 			producer.On("Produce", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
 			handler := newTestMovieHandler(mockCtrl, producer)
 
-			producer.AssertNotCalled(t, "Produce", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
-
-			movieID, _ := strconv.Atoi(tt.movieID)
-			var reqBody dto.UpdateMovieActorsRequest
-			_ = json.Unmarshal([]byte(tt.requestBody), &reqBody)
-			tt.setupMock(mockCtrl, movieID, reqBody)
-
-			r.PUT("/movies/:id/actors", handler.UpdateMovieActors)
-			req, _ := http.NewRequest("PUT", "/movies/"+tt.movieID+"/actors", bytes.NewBufferString(tt.requestBody))
+			r.PATCH("/movies/:id/actors", handler.ChangeMovieActors)
+			req, _ := http.NewRequest(http.MethodPatch, "/movies/"+tt.movieID+"/actors", bytes.NewBufferString(tt.requestBody))
 			req.Header.Set("Content-Type", "application/json")
 			w := httptest.NewRecorder()
 			r.ServeHTTP(w, req)
 
 			assert.Equal(t, tt.expectedStatus, w.Code)
-			if tt.expectedBody != "" {
-				assert.JSONEq(t, tt.expectedBody, w.Body.String())
-			}
+			assert.JSONEq(t, tt.expectedBody, w.Body.String())
+			mockCtrl.AssertExpectations(t)
 		})
 	}
 }
@@ -1614,18 +2834,38 @@ func TestMovieHandler_ListSorted(t *testing.T) {
 						"title": "The Shawshank Redemption",
 						"description": "Two imprisoned men bond over a number of years...",
 						"release_year": 1994,
-						"rating": 9.3
+						"rating": 9.3,
+						"view_count": 0
 					},
 					{
 						"id": 2,
 						"title": "The Godfather",
 						"description": "The aging patriarch of an organized crime dynasty...",
 						"release_year": 1972,
-						"rating": 9.2
+						"rating": 9.2,
+						"view_count": 0
 					}
 				]
 			}`,
 		},
+		{
+			name: "invalid sort field",
+			setupMock: func(m *MockMovieController) {
+				m.On("GetAllMoviesSorted", mock.Anything).
+					Return(dto.MoviesListResponse{}, fmt.Errorf("%w: unknown sort field \"budget\"", domain.ErrInvalidSort))
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   problem(http.StatusBadRequest, "invalid_sort", `invalid sort parameter: unknown sort field "budget"`),
+		},
+		{
+			name: "invalid limit",
+			setupMock: func(m *MockMovieController) {
+				m.On("GetAllMoviesSorted", mock.Anything).
+					Return(dto.MoviesListResponse{}, fmt.Errorf("validation error: %w", dto.ValidationErrors{dto.NewFieldError(dto.KeyListLimitTooLarge)}))
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   validationProblem(dto.KeyListLimitTooLarge),
+		},
 		{
 			name: "controller error",
 			setupMock: func(m *MockMovieController) {
@@ -1633,7 +2873,7 @@ func TestMovieHandler_ListSorted(t *testing.T) {
 					Return(dto.MoviesListResponse{}, errors.New("database error"))
 			},
 			expectedStatus: http.StatusInternalServerError,
-			expectedBody:   `{"error":"database error"}`,
+			expectedBody:   problem(http.StatusInternalServerError, apperror.CodeInternal, "Internal Server Error"),
 		},
 	}
 
@@ -1641,6 +2881,7 @@ func TestMovieHandler_ListSorted(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			gin.SetMode(gin.TestMode)
 			r := gin.New()
+			r.Use(apperror.Middleware())
 			mockCtrl := new(MockMovieController)
 			producer := kafka.NewMockProducer()
 			producer.On("Produce", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
@@ -1678,11 +2919,11 @@ func TestMovieHandler_AddActorToMovie(t *testing.T) {
 			movieID: "1",
 			actorID: "2",
 			setupMock: func(m *MockMovieController, movieID, actorID int) {
-				m.On("AddActorToMovie", mock.Anything, movieID, actorID).
+				m.On("AddActorToMovie", mock.Anything, movieID, actorID, mock.Anything).
 					Return(dto.MovieResponse{ID: movieID, Title: "Movie", Description: "", ReleaseYear: 0, Rating: 0}, nil)
 			},
 			expectedStatus: http.StatusBadRequest,
-			expectedBody:   `{"error":"invalid movie id"}`,
+			expectedBody:   problem(http.StatusBadRequest, "invalid_id", "invalid movie id"),
 		},
 		{
 			name:           "invalid movie id",
@@ -1690,7 +2931,7 @@ func TestMovieHandler_AddActorToMovie(t *testing.T) {
 			actorID:        "2",
 			setupMock:      func(m *MockMovieController, movieID, actorID int) {},
 			expectedStatus: http.StatusBadRequest,
-			expectedBody:   `{"error":"invalid movie id"}`,
+			expectedBody:   problem(http.StatusBadRequest, "invalid_id", "invalid movie id"),
 		},
 		{
 			name:           "invalid actor id",
@@ -1698,18 +2939,18 @@ func TestMovieHandler_AddActorToMovie(t *testing.T) {
 			actorID:        "xyz",
 			setupMock:      func(m *MockMovieController, movieID, actorID int) {},
 			expectedStatus: http.StatusBadRequest,
-			expectedBody:   `{"error":"invalid movie id"}`,
+			expectedBody:   problem(http.StatusBadRequest, "invalid_id", "invalid movie id"),
 		},
 		{
 			name:    "controller error",
 			movieID: "1",
 			actorID: "2",
 			setupMock: func(m *MockMovieController, movieID, actorID int) {
-				m.On("AddActorToMovie", mock.Anything, movieID, actorID).
+				m.On("AddActorToMovie", mock.Anything, movieID, actorID, mock.Anything).
 					Return(dto.MovieResponse{}, errors.New("db error"))
 			},
 			expectedStatus: http.StatusBadRequest,
-			expectedBody:   `{"error":"invalid movie id"}`,
+			expectedBody:   problem(http.StatusBadRequest, "invalid_id", "invalid movie id"),
 		},
 	}
 
@@ -1717,6 +2958,7 @@ func TestMovieHandler_AddActorToMovie(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			gin.SetMode(gin.TestMode)
 			r := gin.New()
+			r.Use(apperror.Middleware())
 			mockCtrl := new(MockMovieController)
 			producer := kafka.NewMockProducer()
 			producer.On("Produce", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
@@ -1761,7 +3003,7 @@ func TestMovieHandler_RemoveActorFromMovie(t *testing.T) {
 					Return(dto.MovieResponse{ID: movieID, Title: "Movie", Description: "", ReleaseYear: 0, Rating: 0}, nil)
 			},
 			expectedStatus: http.StatusBadRequest,
-			expectedBody:   `{"error":"invalid movie id"}`,
+			expectedBody:   problem(http.StatusBadRequest, "invalid_id", "invalid movie id"),
 		},
 		{
 			name:           "invalid movie id",
@@ -1769,7 +3011,7 @@ func TestMovieHandler_RemoveActorFromMovie(t *testing.T) {
 			actorID:        "2",
 			setupMock:      func(m *MockMovieController, movieID, actorID int) {},
 			expectedStatus: http.StatusBadRequest,
-			expectedBody:   `{"error":"invalid movie id"}`,
+			expectedBody:   problem(http.StatusBadRequest, "invalid_id", "invalid movie id"),
 		},
 		{
 			name:           "invalid actor id",
@@ -1777,7 +3019,7 @@ func TestMovieHandler_RemoveActorFromMovie(t *testing.T) {
 			actorID:        "xyz",
 			setupMock:      func(m *MockMovieController, movieID, actorID int) {},
 			expectedStatus: http.StatusBadRequest,
-			expectedBody:   `{"error":"invalid movie id"}`,
+			expectedBody:   problem(http.StatusBadRequest, "invalid_id", "invalid movie id"),
 		},
 		{
 			name:    "controller error",
@@ -1788,7 +3030,7 @@ func TestMovieHandler_RemoveActorFromMovie(t *testing.T) {
 					Return(dto.MovieResponse{}, errors.New("db error"))
 			},
 			expectedStatus: http.StatusBadRequest,
-			expectedBody:   `{"error":"invalid movie id"}`,
+			expectedBody:   problem(http.StatusBadRequest, "invalid_id", "invalid movie id"),
 		},
 	}
 
@@ -1796,6 +3038,7 @@ func TestMovieHandler_RemoveActorFromMovie(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			gin.SetMode(gin.TestMode)
 			r := gin.New()
+			r.Use(apperror.Middleware())
 			mockCtrl := new(MockMovieController)
 			producer := kafka.NewMockProducer()
 			producer.On("Produce", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
@@ -1823,14 +3066,83 @@ func TestMovieHandler_RemoveActorFromMovie(t *testing.T) {
 	}
 }
 
+// TestMovieHandler_CastLinkEvents проверяет события movie-actor-links при изменении состава
+func TestMovieHandler_CastLinkEvents(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockCtrl := new(MockMovieController)
+	mockCtrl.On("RemoveActorFromMovie", mock.Anything, 1, 2).Return(dto.MovieResponse{ID: 1}, nil)
+	mockCtrl.On("UpdateMovieActors", mock.Anything, 1, mock.Anything).Return(dto.MovieActorsResponse{
+		Actors:          []dto.ActorResponse{{ID: 3, CharacterName: "Cobb", BillingOrder: 1}, {ID: 4}},
+		AddedActorIDs:   []int{3},
+		RemovedActorIDs: []int{5},
+	}, nil)
+	mockCtrl.On("ChangeMovieActors", mock.Anything, 1, dto.ChangeMovieActorsRequest{Remove: []int{4}}).Return(dto.MovieActorsResponse{
+		Actors:          []dto.ActorResponse{{ID: 3, CharacterName: "Cobb", BillingOrder: 1}},
+		RemovedActorIDs: []int{4},
+	}, nil)
+
+	var sent []map[string]interface{}
+	producer := kafka.NewMockProducer()
+	producer.On("Produce", mock.Anything, MovieActorLinksTopic, []byte("1"), mock.Anything).
+		Run(func(args mock.Arguments) {
+			var event map[string]interface{}
+			if err := json.Unmarshal(args.Get(3).([]byte), &event); err == nil {
+				sent = append(sent, event)
+			}
+		}).Return(nil)
+	producer.On("Close").Return(nil)
+	producerPool := kafka.NewProducerPool(producer, 1, 10)
+	handler := NewMovieHandler(mockCtrl, kafkaBus(producerPool))
+
+	r := gin.New()
+	r.Use(apperror.Middleware(), func(c *gin.Context) {
+		auth.SetUser(c, &auth.UserContext{AuthType: auth.AuthTypeJWT, UserID: "7", Username: "moderator", Role: domain.RoleModerator})
+	})
+	r.DELETE("/movies/remove-actor/:movieId/:actorId", handler.RemoveActorFromMovie)
+	r.POST("/movies/:id/actors", handler.UpdateMovieActors)
+	r.PATCH("/movies/:id/actors", handler.ChangeMovieActors)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/movies/remove-actor/1/2", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	req := httptest.NewRequest(http.MethodPost, "/movies/1/actors", bytes.NewBufferString(`{"actor_ids": [3, 4]}`))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotContains(t, w.Body.String(), "added_actor_ids")
+
+	req = httptest.NewRequest(http.MethodPatch, "/movies/1/actors", bytes.NewBufferString(`{"remove": [4]}`))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	producerPool.Close()
+	if assert.Len(t, sent, 4) {
+		assert.Equal(t, "movie_actor_removed", sent[0]["type"])
+		assert.Equal(t, float64(2), sent[0]["actor_id"])
+		assert.Equal(t, map[string]interface{}{"user_id": "7", "username": "moderator", "role": domain.RoleModerator}, sent[0]["performed_by"])
+		assert.Equal(t, "movie_actor_added", sent[1]["type"])
+		assert.Equal(t, float64(3), sent[1]["actor_id"])
+		assert.Equal(t, "Cobb", sent[1]["character_name"])
+		assert.Equal(t, "movie_actor_removed", sent[2]["type"])
+		assert.Equal(t, float64(5), sent[2]["actor_id"])
+		assert.Equal(t, "movie_actor_removed", sent[3]["type"])
+		assert.Equal(t, float64(4), sent[3]["actor_id"])
+	}
+}
+
 // TestMovieHandler_GetActorsForMovieByID тестирует метод GetActorsForMovieByID у MovieHandler
 func TestMovieHandler_GetActorsForMovieByID(t *testing.T) {
 	tests := []struct {
 		name           string
 		movieID        string
-		setupMock      func(*MockMovieController, int)
-		expectedStatus int
-		expectedBody   string
+		setupMock        func(*MockMovieController, int)
+		expectedStatus   int
+		expectedBody     string
+		expectedLocation string
 	}{
 		{
 			name:    "success",
@@ -1847,7 +3159,7 @@ func TestMovieHandler_GetActorsForMovieByID(t *testing.T) {
 			movieID:        "abc",
 			setupMock:      func(m *MockMovieController, movieID int) {},
 			expectedStatus: http.StatusBadRequest,
-			expectedBody:   `{"error":"invalid movie id"}`,
+			expectedBody:   problem(http.StatusBadRequest, "invalid_id", "invalid movie id"),
 		},
 		{
 			name:    "controller error",
@@ -1857,7 +3169,7 @@ func TestMovieHandler_GetActorsForMovieByID(t *testing.T) {
 					Return(dto.MovieActorsResponse{}, errors.New("db error"))
 			},
 			expectedStatus: http.StatusInternalServerError,
-			expectedBody:   `{"error":"db error"}`,
+			expectedBody:   problem(http.StatusInternalServerError, apperror.CodeInternal, "Internal Server Error"),
 		},
 	}
 
@@ -1865,6 +3177,7 @@ func TestMovieHandler_GetActorsForMovieByID(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			gin.SetMode(gin.TestMode)
 			r := gin.New()
+			r.Use(apperror.Middleware())
 			mockCtrl := new(MockMovieController)
 			producer := kafka.NewMockProducer()
 			producer.On("Produce", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
@@ -1890,6 +3203,343 @@ func TestMovieHandler_GetActorsForMovieByID(t *testing.T) {
 	}
 }
 
+func TestMovieHandler_RatingHistory(t *testing.T) {
+	oldRating := 8.5
+	changedAt := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name           string
+		movieID        string
+		setupMock      func(*MockMovieController)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:    "success",
+			movieID: "1",
+			setupMock: func(m *MockMovieController) {
+				m.On("GetRatingHistory", mock.Anything, 1).Return(dto.RatingHistoryResponse{
+					MovieID: 1,
+					History: []dto.RatingChangeResponse{
+						{NewRating: 8.5, ChangedAt: changedAt.Add(-time.Hour)},
+						{OldRating: &oldRating, NewRating: 8.8, ChangedAt: changedAt},
+					},
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: `{"movie_id":1,"history":[{"new_rating":8.5,"changed_at":"2024-03-01T11:00:00Z"},` +
+				`{"old_rating":8.5,"new_rating":8.8,"changed_at":"2024-03-01T12:00:00Z"}]}`,
+		},
+		{
+			name:           "invalid movie id",
+			movieID:        "abc",
+			setupMock:      func(m *MockMovieController) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:    "movie not found",
+			movieID: "999",
+			setupMock: func(m *MockMovieController) {
+				m.On("GetRatingHistory", mock.Anything, 999).Return(dto.RatingHistoryResponse{}, domain.ErrMovieNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			r := gin.New()
+			r.Use(apperror.Middleware())
+			mockCtrl := new(MockMovieController)
+			handler := newTestMovieHandler(mockCtrl, kafka.NewMockProducer())
+			tt.setupMock(mockCtrl)
+
+			r.GET("/movies/:id/rating-history", handler.RatingHistory)
+			req, _ := http.NewRequest("GET", "/movies/"+tt.movieID+"/rating-history", nil)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedBody != "" {
+				assert.JSONEq(t, tt.expectedBody, w.Body.String())
+			}
+			mockCtrl.AssertExpectations(t)
+		})
+	}
+}
+
+func TestMovieHandler_Cast(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(apperror.Middleware())
+	mockCtrl := new(MockMovieController)
+	handler := newTestMovieHandler(mockCtrl, kafka.NewMockProducer())
+	r.GET("/movies/:id/cast", handler.Cast)
+
+	mockCtrl.On("GetMovieCast", mock.Anything, 1).Return(dto.MovieCastResponse{
+		MovieID:    1,
+		CastCount:  3,
+		Cast:       []dto.CastMemberResponse{{ID: 3, Name: "Keanu Reeves", CharacterName: "Neo", BillingOrder: 1}},
+		Pagination: &dto.Pagination{Limit: 1, Offset: 0, Total: 3, TotalPages: 3},
+	}, nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/movies/1/cast?limit=1", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"movie_id":1,"cast_count":3,"cast":[{"id":3,"name":"Keanu Reeves","character_name":"Neo","billing_order":1}],`+
+		`"pagination":{"limit":1,"offset":0,"total":3,"total_pages":3}}`, w.Body.String())
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/movies/abc/cast", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockCtrl.AssertExpectations(t)
+}
+
+func TestMovieHandler_Availability(t *testing.T) {
+	window := dto.AvailabilityRequest{Region: "DE", AvailableFrom: "2024-01-01", AvailableUntil: "2024-06-30"}
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		body           string
+		setupMock      func(*MockMovieController)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:   "list",
+			method: http.MethodGet,
+			path:   "/movies/1/availability",
+			setupMock: func(m *MockMovieController) {
+				m.On("GetAvailability", mock.Anything, 1).Return(dto.AvailabilityListResponse{
+					MovieID:      1,
+					Availability: []dto.AvailabilityResponse{{ID: 2, Region: "US", AvailableFrom: "2024-01-01"}},
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"movie_id":1,"availability":[{"id":2,"region":"US","available_from":"2024-01-01"}]}`,
+		},
+		{
+			name:   "add",
+			method: http.MethodPost,
+			path:   "/movies/1/availability",
+			body:   `{"region":"DE","available_from":"2024-01-01","available_until":"2024-06-30"}`,
+			setupMock: func(m *MockMovieController) {
+				m.On("AddAvailability", mock.Anything, 1, window).
+					Return(dto.AvailabilityResponse{ID: 5, Region: "DE", AvailableFrom: "2024-01-01", AvailableUntil: "2024-06-30"}, nil)
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   `{"id":5,"region":"DE","available_from":"2024-01-01","available_until":"2024-06-30"}`,
+		},
+		{
+			name:           "add with malformed body",
+			method:         http.MethodPost,
+			path:           "/movies/1/availability",
+			body:           `{"region":`,
+			setupMock:      func(m *MockMovieController) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "update unknown window",
+			method: http.MethodPut,
+			path:   "/movies/1/availability/9",
+			body:   `{"region":"DE","available_from":"2024-01-01","available_until":"2024-06-30"}`,
+			setupMock: func(m *MockMovieController) {
+				m.On("UpdateAvailability", mock.Anything, 1, 9, window).Return(dto.AvailabilityResponse{}, domain.ErrAvailabilityNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:   "delete",
+			method: http.MethodDelete,
+			path:   "/movies/1/availability/9",
+			setupMock: func(m *MockMovieController) {
+				m.On("DeleteAvailability", mock.Anything, 1, 9).Return(nil)
+			},
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:           "delete with invalid window id",
+			method:         http.MethodDelete,
+			path:           "/movies/1/availability/abc",
+			setupMock:      func(m *MockMovieController) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			r := gin.New()
+			r.Use(apperror.Middleware())
+			mockCtrl := new(MockMovieController)
+			handler := newTestMovieHandler(mockCtrl, kafka.NewMockProducer())
+			tt.setupMock(mockCtrl)
+
+			r.GET("/movies/:id/availability", handler.Availability)
+			r.POST("/movies/:id/availability", handler.AddAvailability)
+			r.PUT("/movies/:id/availability/:windowId", handler.UpdateAvailability)
+			r.DELETE("/movies/:id/availability/:windowId", handler.DeleteAvailability)
+			req, _ := http.NewRequest(tt.method, tt.path, bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedBody != "" {
+				assert.JSONEq(t, tt.expectedBody, w.Body.String())
+			}
+			mockCtrl.AssertExpectations(t)
+		})
+	}
+}
+
+func TestMovieHandler_ReorderActors(t *testing.T) {
+	tests := []struct {
+		name           string
+		path           string
+		body           string
+		setupMock      func(*MockMovieController)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "reordered",
+			path: "/movies/1/actors/order",
+			body: `{"actor_ids":[3,2]}`,
+			setupMock: func(m *MockMovieController) {
+				m.On("ReorderMovieActors", mock.Anything, 1, dto.ReorderMovieActorsRequest{ActorIDs: []int{3, 2}}).Return(dto.MovieActorsResponse{
+					Actors: []dto.ActorResponse{{ID: 3, Name: "Tom Hardy", BillingOrder: 1}, {ID: 2, Name: "Leonardo DiCaprio", BillingOrder: 2}},
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "actor not in cast",
+			path: "/movies/1/actors/order",
+			body: `{"actor_ids":[9]}`,
+			setupMock: func(m *MockMovieController) {
+				m.On("ReorderMovieActors", mock.Anything, 1, dto.ReorderMovieActorsRequest{ActorIDs: []int{9}}).
+					Return(dto.MovieActorsResponse{}, domain.ErrActorNotInMovie)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   problem(http.StatusNotFound, "actor_not_in_movie", "actor is not in the movie"),
+		},
+		{
+			name:           "invalid id",
+			path:           "/movies/abc/actors/order",
+			body:           `{"actor_ids":[1]}`,
+			setupMock:      func(m *MockMovieController) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   problem(http.StatusBadRequest, "invalid_id", "invalid movie id"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			r := gin.New()
+			r.Use(apperror.Middleware())
+			mockCtrl := new(MockMovieController)
+			handler := newTestMovieHandler(mockCtrl, kafka.NewMockProducer())
+			tt.setupMock(mockCtrl)
+
+			r.PATCH("/movies/:id/actors/order", handler.ReorderActors)
+			req, _ := http.NewRequest(http.MethodPatch, tt.path, bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedBody != "" {
+				assert.JSONEq(t, tt.expectedBody, w.Body.String())
+			}
+			mockCtrl.AssertExpectations(t)
+		})
+	}
+}
+
+func TestMovieHandler_Ratings(t *testing.T) {
+	display := 8.8
+	imdb := 9.0
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		body           string
+		setupMock      func(*MockMovieController)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:   "list",
+			method: http.MethodGet,
+			path:   "/movies/1/ratings",
+			setupMock: func(m *MockMovieController) {
+				m.On("GetMovieRatings", mock.Anything, 1).Return(dto.MovieRatingsResponse{
+					MovieID: 1, Ratings: map[string]float64{"internal": 8, "imdb": 9}, DisplayRating: &display,
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"movie_id":1,"ratings":{"internal":8,"imdb":9},"display_rating":8.8}`,
+		},
+		{
+			name:   "set",
+			method: http.MethodPut,
+			path:   "/movies/1/ratings/imdb",
+			body:   `{"rating":9}`,
+			setupMock: func(m *MockMovieController) {
+				m.On("SetMovieRating", mock.Anything, 1, "imdb", dto.MovieRatingRequest{Rating: &imdb}).Return(dto.MovieRatingsResponse{
+					MovieID: 1, Ratings: map[string]float64{"imdb": 9},
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"movie_id":1,"ratings":{"imdb":9}}`,
+		},
+		{
+			name:           "set with wrong type",
+			method:         http.MethodPut,
+			path:           "/movies/1/ratings/imdb",
+			body:           `{"rating":"high"}`,
+			setupMock:      func(m *MockMovieController) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "delete unknown rating",
+			method: http.MethodDelete,
+			path:   "/movies/1/ratings/imdb",
+			setupMock: func(m *MockMovieController) {
+				m.On("DeleteMovieRating", mock.Anything, 1, "imdb").Return(domain.ErrRatingNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			r := gin.New()
+			r.Use(apperror.Middleware())
+			mockCtrl := new(MockMovieController)
+			handler := newTestMovieHandler(mockCtrl, kafka.NewMockProducer())
+			tt.setupMock(mockCtrl)
+
+			r.GET("/movies/:id/ratings", handler.Ratings)
+			r.PUT("/movies/:id/ratings/:source", handler.SetRating)
+			r.DELETE("/movies/:id/ratings/:source", handler.DeleteRating)
+			req, _ := http.NewRequest(tt.method, tt.path, bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedBody != "" {
+				assert.JSONEq(t, tt.expectedBody, w.Body.String())
+			}
+			mockCtrl.AssertExpectations(t)
+		})
+	}
+}
+
 // TestMovieHandler_GetMoviesForActor тестирует метод GetMoviesForActor у MovieHandler
 func TestMovieHandler_GetMoviesForActor(t *testing.T) {
 	tests := []struct {
@@ -1907,14 +3557,14 @@ func TestMovieHandler_GetMoviesForActor(t *testing.T) {
 					Return(dto.ActorMoviesResponse{Movies: []dto.MovieResponse{{ID: 1, Title: "Movie"}}}, nil)
 			},
 			expectedStatus: http.StatusOK,
-			expectedBody:   `{"movies":[{"id":1,"title":"Movie","description":"","release_year":0,"rating":0}]}`,
+			expectedBody:   `{"movies":[{"id":1,"title":"Movie","description":"","release_year":0,"rating":0,"view_count":0}]}`,
 		},
 		{
 			name:           "invalid actor id",
 			actorID:        "abc",
 			setupMock:      func(m *MockMovieController, actorID int) {},
 			expectedStatus: http.StatusBadRequest,
-			expectedBody:   `{"error":"invalid actor id"}`,
+			expectedBody:   problem(http.StatusBadRequest, "invalid_id", "invalid actor id"),
 		},
 		{
 			name:    "controller error",
@@ -1924,7 +3574,7 @@ func TestMovieHandler_GetMoviesForActor(t *testing.T) {
 					Return(dto.ActorMoviesResponse{}, errors.New("db error"))
 			},
 			expectedStatus: http.StatusInternalServerError,
-			expectedBody:   `{"error":"db error"}`,
+			expectedBody:   problem(http.StatusInternalServerError, apperror.CodeInternal, "Internal Server Error"),
 		},
 	}
 
@@ -1932,6 +3582,7 @@ func TestMovieHandler_GetMoviesForActor(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			gin.SetMode(gin.TestMode)
 			r := gin.New()
+			r.Use(apperror.Middleware())
 			mockCtrl := new(MockMovieController)
 			producer := kafka.NewMockProducer()
 			producer.On("Produce", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
@@ -1956,3 +3607,51 @@ func TestMovieHandler_GetMoviesForActor(t *testing.T) {
 		})
 	}
 }
+
+func TestHandlers_GetBySlug(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("movie counts a view", func(t *testing.T) {
+		r := gin.New()
+		r.Use(apperror.Middleware())
+		mockCtrl := new(MockMovieController)
+		producer := kafka.NewMockProducer()
+		producer.On("Produce", mock.Anything, "movie-views", []byte("1"), mock.Anything).Return(nil)
+		handler := newTestMovieHandler(mockCtrl, producer)
+		mockCtrl.On("GetMovieBySlug", mock.Anything, "the-matrix-1999").
+			Return(dto.MovieResponse{ID: 1, Title: "The Matrix", ReleaseYear: 1999, Slug: "the-matrix-1999"}, nil)
+		mockCtrl.On("GetMovieBySlug", mock.Anything, "missing").Return(dto.MovieResponse{}, domain.ErrMovieNotFound)
+		r.GET("/movies/slug/:slug", handler.GetBySlug)
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/movies/slug/the-matrix-1999", nil))
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"id":1,"title":"The Matrix","description":"","release_year":1999,"rating":0,"view_count":0,"slug":"the-matrix-1999"}`, w.Body.String())
+		assert.NotEmpty(t, w.Header().Get("ETag"))
+
+		w = httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/movies/slug/missing", nil))
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.JSONEq(t, problem(http.StatusNotFound, "movie_not_found", "movie not found"), w.Body.String())
+	})
+
+	t.Run("actor", func(t *testing.T) {
+		r := gin.New()
+		r.Use(apperror.Middleware())
+		mockCtrl := new(MockActorController)
+		handler := NewActorHandler(mockCtrl, nil)
+		mockCtrl.On("GetActorBySlug", mock.Anything, "keanu-reeves").
+			Return(dto.ActorResponse{ID: 7, Name: "Keanu Reeves", Gender: "male", BirthDate: "1964-09-02", Slug: "keanu-reeves"}, nil)
+		mockCtrl.On("GetActorBySlug", mock.Anything, "nobody").Return(dto.ActorResponse{}, domain.ErrActorNotFound)
+		r.GET("/actors/slug/:slug", handler.GetBySlug)
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/actors/slug/keanu-reeves", nil))
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"id":7,"name":"Keanu Reeves","gender":"male","birth_date":"1964-09-02","slug":"keanu-reeves"}`, w.Body.String())
+
+		w = httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/actors/slug/nobody", nil))
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"net/http"
+
+	"cinematique/internal/health"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HealthReporter возвращает сводное состояние подсистем сервиса
+type HealthReporter interface {
+	Report() health.Report
+}
+
+// HealthHandler обслуживает проверки живости и готовности
+type HealthHandler struct {
+	reporter HealthReporter
+}
+
+// NewHealthHandler создаёт обработчик проверок состояния
+func NewHealthHandler(reporter HealthReporter) *HealthHandler {
+	return &HealthHandler{reporter: reporter}
+}
+
+// Healthz — проверка живости: процесс запущен и обслуживает HTTP
+func (h *HealthHandler) Healthz(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": health.StatusOK})
+}
+
+// Readyz — проверка готовности с состоянием каждой подсистемы.
+// 503 возвращается, только если не работает критичная подсистема;
+// при деградации некритичных подсистем сервис остаётся готовым
+func (h *HealthHandler) Readyz(c *gin.Context) {
+	report := h.reporter.Report()
+	status := http.StatusOK
+	if report.Status == health.StatusDown {
+		status = http.StatusServiceUnavailable
+	}
+	c.JSON(status, report)
+}
+
+// RegisterHealthRoutes регистрирует /healthz и /readyz вне базового пути API
+func RegisterHealthRoutes(router gin.IRoutes, handler *HealthHandler) {
+	router.GET("/healthz", handler.Healthz)
+	router.GET("/readyz", handler.Readyz)
+}
@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"cinematique/internal/health"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type stubHealthReporter struct {
+	report health.Report
+}
+
+func (s stubHealthReporter) Report() health.Report { return s.report }
+
+func TestHealthHandler_Readyz(t *testing.T) {
+	tests := []struct {
+		name           string
+		report         health.Report
+		expectedStatus int
+	}{
+		{
+			name: "all subsystems healthy",
+			report: health.Report{Status: health.StatusOK, Score: 1, Subsystems: map[string]health.SubsystemReport{
+				"database": {Status: health.StatusOK, Critical: true},
+			}},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "degraded is still ready",
+			report: health.Report{Status: health.StatusDegraded, Score: 0.5, Subsystems: map[string]health.SubsystemReport{
+				"database": {Status: health.StatusOK, Critical: true},
+				"cache":    {Status: health.StatusDown, Error: "connection refused"},
+			}},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "critical subsystem down",
+			report: health.Report{Status: health.StatusDown, Score: 0, Subsystems: map[string]health.SubsystemReport{
+				"database": {Status: health.StatusDown, Critical: true, Error: "timeout"},
+			}},
+			expectedStatus: http.StatusServiceUnavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			r := gin.New()
+			RegisterHealthRoutes(r, NewHealthHandler(stubHealthReporter{report: tt.report}))
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, "/readyz", nil)
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.Contains(t, w.Body.String(), `"status":"`+string(tt.report.Status)+`"`)
+			for name := range tt.report.Subsystems {
+				assert.Contains(t, w.Body.String(), `"`+name+`"`)
+			}
+		})
+	}
+}
+
+func TestHealthHandler_Healthz(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	RegisterHealthRoutes(r, NewHealthHandler(stubHealthReporter{}))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/healthz", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"status":"ok"}`, w.Body.String())
+}
@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"cinematique/internal/apperror"
+	"cinematique/internal/controller/dto"
+	"cinematique/internal/jobs"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MaintenanceController описывает служебные операции над базой данных
+type MaintenanceController interface {
+	Reindex(ctx context.Context, progress func(done, total int, step string)) (dto.ReindexResponse, error)
+}
+
+// reindexJobKind — вид фоновой задачи перестроения индексов; одновременно выполняется одна такая задача
+const reindexJobKind = "reindex"
+
+var errMaintenanceUnavailable = apperror.Unavailable("maintenance_unavailable", "maintenance operations are not configured")
+
+// WithMaintenance подключает служебные операции над базой данных (POST /admin/maintenance/reindex)
+func (h *AdminHandler) WithMaintenance(controller MaintenanceController) *AdminHandler {
+	h.maintenance = controller
+	return h
+}
+
+// Reindex запускает фоновое перестроение индексов поиска и отвечает 202 со снимком задачи.
+// Ход перестроения виден в progress задачи (GET /admin/maintenance/jobs/:id). Пока перестроение
+// идёт, повторный запрос отклоняется с 409 и ID выполняющейся задачи
+func (h *AdminHandler) Reindex(c *gin.Context) {
+	if h.maintenance == nil {
+		respondError(c, errMaintenanceUnavailable)
+		return
+	}
+	job, started := h.jobs.StartExclusive(reindexJobKind, func(ctx context.Context) (interface{}, error) {
+		return h.maintenance.Reindex(ctx, func(done, total int, step string) {
+			jobs.ReportProgress(ctx, jobs.Progress{Done: done, Total: total, Step: step})
+		})
+	})
+	if !started {
+		conflict := apperror.Conflict("maintenance_in_progress", "reindex is already running")
+		conflict.Extensions = map[string]interface{}{"job_id": job.ID}
+		respondError(c, conflict)
+		return
+	}
+	c.JSON(http.StatusAccepted, job)
+}
@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"cinematique/internal/apperror"
+	"cinematique/internal/controller/dto"
+	"cinematique/internal/jobs"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// blockingReindex сообщает о первом шаге перестроения и ждёт release
+type blockingReindex struct {
+	reported chan struct{}
+	release  chan struct{}
+}
+
+func (b *blockingReindex) Reindex(_ context.Context, progress func(done, total int, step string)) (dto.ReindexResponse, error) {
+	progress(0, 2, "idx_films_title_trgm")
+	close(b.reported)
+	<-b.release
+	return dto.ReindexResponse{Indexes: []string{"idx_films_title_trgm"}, DurationMS: 15}, nil
+}
+
+func TestAdminHandler_Reindex(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	manager := jobs.NewManager(time.Hour)
+	defer manager.Close()
+
+	reindex := &blockingReindex{reported: make(chan struct{}), release: make(chan struct{})}
+	handler := NewAdminHandler(nil, nil, manager, nil).WithMaintenance(reindex)
+	r := gin.New()
+	r.Use(apperror.Middleware())
+	r.POST("/admin/maintenance/reindex", handler.Reindex)
+	r.GET("/admin/maintenance/jobs/:id", handler.GetJob)
+	do := func(method, url string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(method, url, nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := do(http.MethodPost, "/admin/maintenance/reindex")
+	require.Equal(t, http.StatusAccepted, w.Code)
+	var job jobs.Job
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &job))
+	assert.Equal(t, reindexJobKind, job.Kind)
+	assert.Equal(t, jobs.StatusRunning, job.Status)
+
+	<-reindex.reported
+	w = do(http.MethodGet, "/admin/maintenance/jobs/"+job.ID)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"progress":{"done":0,"total":2,"step":"idx_films_title_trgm"}`)
+
+	// Второе перестроение не запускается, пока идёт первое
+	w = do(http.MethodPost, "/admin/maintenance/reindex")
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Body.String(), `"code":"maintenance_in_progress"`)
+	assert.Contains(t, w.Body.String(), `"job_id":"`+job.ID+`"`)
+
+	close(reindex.release)
+	finished, err := manager.Wait(context.Background(), job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, jobs.StatusSucceeded, finished.Status)
+	assert.Equal(t, dto.ReindexResponse{Indexes: []string{"idx_films_title_trgm"}, DurationMS: 15}, finished.Result)
+}
+
+func TestAdminHandler_ReindexWithoutMaintenance(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	manager := jobs.NewManager(time.Hour)
+	defer manager.Close()
+
+	r := gin.New()
+	r.Use(apperror.Middleware())
+	r.POST("/admin/maintenance/reindex", NewAdminHandler(nil, nil, manager, nil).Reindex)
+	req, _ := http.NewRequest(http.MethodPost, "/admin/maintenance/reindex", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.JSONEq(t, problem(http.StatusServiceUnavailable, "maintenance_unavailable", "maintenance operations are not configured"), w.Body.String())
+}
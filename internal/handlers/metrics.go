@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"cinematique/internal/apperror"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MetricsAuth — учётные данные для доступа к /metrics. Можно задать Bearer-токен,
+// логин и пароль basic auth или и то и другое; без них эндпоинт открыт
+type MetricsAuth struct {
+	Username string
+	Password string
+	Token    string
+}
+
+// Enabled сообщает, что доступ к метрикам требует учётных данных
+func (a MetricsAuth) Enabled() bool {
+	return a.Token != "" || a.Username != ""
+}
+
+// RegisterMetricsRoutes регистрирует /metrics вне базового пути API, как и проверки состояния
+func RegisterMetricsRoutes(router gin.IRoutes, metrics http.Handler, credentials MetricsAuth) {
+	if credentials.Enabled() {
+		router.GET("/metrics", metricsAuthMiddleware(credentials), gin.WrapH(metrics))
+		return
+	}
+	router.GET("/metrics", gin.WrapH(metrics))
+}
+
+// metricsAuthMiddleware пропускает запросы с заданным Bearer-токеном или логином и паролем.
+// Сравнение за постоянное время, чтобы токен нельзя было подобрать по времени ответа
+func metricsAuthMiddleware(credentials MetricsAuth) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if credentials.Token != "" {
+			if token, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer "); ok && secureEqual(token, credentials.Token) {
+				c.Next()
+				return
+			}
+		}
+		if credentials.Username != "" {
+			if username, password, ok := c.Request.BasicAuth(); ok &&
+				secureEqual(username, credentials.Username) && secureEqual(password, credentials.Password) {
+				c.Next()
+				return
+			}
+			c.Header("WWW-Authenticate", `Basic realm="metrics"`)
+		} else {
+			c.Header("WWW-Authenticate", `Bearer realm="metrics"`)
+		}
+		respondError(c, apperror.Unauthorized("unauthorized", "authentication required"))
+		c.Abort()
+	}
+}
+
+func secureEqual(a, b string) bool {
+	return subtle.ConstantTimeCompare([]byte(a), []byte(b)) == 1
+}
@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"cinematique/internal/apperror"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegisterMetricsRoutes(t *testing.T) {
+	metrics := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("go_goroutines 7\n"))
+	})
+
+	tests := []struct {
+		name           string
+		credentials    MetricsAuth
+		setupRequest   func(*http.Request)
+		expectedStatus int
+		expectedHeader string
+	}{
+		{
+			name:           "open without credentials",
+			setupRequest:   func(*http.Request) {},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "valid bearer token",
+			credentials:    MetricsAuth{Token: "scrape-secret"},
+			setupRequest:   func(r *http.Request) { r.Header.Set("Authorization", "Bearer scrape-secret") },
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "wrong bearer token",
+			credentials:    MetricsAuth{Token: "scrape-secret"},
+			setupRequest:   func(r *http.Request) { r.Header.Set("Authorization", "Bearer guess") },
+			expectedStatus: http.StatusUnauthorized,
+			expectedHeader: `Bearer realm="metrics"`,
+		},
+		{
+			name:           "valid basic auth",
+			credentials:    MetricsAuth{Username: "prometheus", Password: "s3cret"},
+			setupRequest:   func(r *http.Request) { r.SetBasicAuth("prometheus", "s3cret") },
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "wrong basic auth password",
+			credentials:    MetricsAuth{Username: "prometheus", Password: "s3cret"},
+			setupRequest:   func(r *http.Request) { r.SetBasicAuth("prometheus", "wrong") },
+			expectedStatus: http.StatusUnauthorized,
+			expectedHeader: `Basic realm="metrics"`,
+		},
+		{
+			name:           "basic auth accepted when token is also configured",
+			credentials:    MetricsAuth{Username: "prometheus", Password: "s3cret", Token: "scrape-secret"},
+			setupRequest:   func(r *http.Request) { r.SetBasicAuth("prometheus", "s3cret") },
+			expectedStatus: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			r := gin.New()
+			r.Use(apperror.Middleware())
+			RegisterMetricsRoutes(r, metrics, tt.credentials)
+
+			req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+			tt.setupRequest(req)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus == http.StatusOK {
+				assert.Equal(t, "go_goroutines 7\n", w.Body.String())
+			} else {
+				assert.NotContains(t, w.Body.String(), "go_goroutines")
+			}
+			assert.Equal(t, tt.expectedHeader, w.Header().Get("WWW-Authenticate"))
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"net/http"
+
+	"cinematique/internal/controller/dto"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SearchController описывает общий поиск по фильмам и актёрам
+type SearchController interface {
+	Search(c *gin.Context) (dto.SearchResponse, error)
+}
+
+// SearchHandler обрабатывает запросы общей строки поиска
+type SearchHandler struct {
+	controller SearchController
+}
+
+// NewSearchHandler создаёт обработчик (handler) общего поиска
+func NewSearchHandler(controller SearchController) *SearchHandler {
+	return &SearchHandler{controller: controller}
+}
+
+// Search возвращает фильмы и актёров, найденные по ?q=, одним списком по убыванию score.
+// Поле type каждого результата (movie или actor) говорит, какие поля в нём заполнены
+func (h *SearchHandler) Search(c *gin.Context) {
+	resp, err := h.controller.Search(c)
+	respond(c, http.StatusOK, resp, err)
+}
+
+// RegisterSearchRoutes регистрирует маршрут общего поиска по каталогу
+func RegisterSearchRoutes(router *gin.RouterGroup, handler *SearchHandler) {
+	if handler == nil {
+		return
+	}
+	router.GET("/search", handler.Search)
+}
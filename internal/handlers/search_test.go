@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"cinematique/internal/apperror"
+	"cinematique/internal/controller/dto"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockSearchController мок контроллера общего поиска
+type MockSearchController struct {
+	mock.Mock
+}
+
+func (m *MockSearchController) Search(c *gin.Context) (dto.SearchResponse, error) {
+	args := m.Called(c)
+	return args.Get(0).(dto.SearchResponse), args.Error(1)
+}
+
+func TestSearchHandler_Search(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("ok", func(t *testing.T) {
+		ctrl := new(MockSearchController)
+		ctrl.On("Search", mock.Anything).Return(dto.SearchResponse{Query: "matrix", Results: []dto.SearchResult{
+			{Type: "movie", ID: 1, Title: "The Matrix", Slug: "the-matrix-1999", ReleaseYear: 1999, Score: 0.6},
+			{Type: "actor", ID: 7, Name: "Matrix Fan", Slug: "matrix-fan", Score: 0.4},
+		}}, nil)
+		r := gin.New()
+		RegisterSearchRoutes(r.Group(""), NewSearchHandler(ctrl))
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/search?q=matrix", nil))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"query":"matrix","results":[`+
+			`{"type":"movie","id":1,"title":"The Matrix","slug":"the-matrix-1999","release_year":1999,"score":0.6},`+
+			`{"type":"actor","id":7,"name":"Matrix Fan","slug":"matrix-fan","score":0.4}]}`, w.Body.String())
+		ctrl.AssertExpectations(t)
+	})
+
+	t.Run("validation error", func(t *testing.T) {
+		ctrl := new(MockSearchController)
+		ctrl.On("Search", mock.Anything).Return(dto.SearchResponse{},
+			fmt.Errorf("validation error: %w", dto.ValidationErrors{dto.NewFieldError(dto.KeySearchQRequired)}))
+		r := gin.New()
+		r.Use(apperror.Middleware())
+		RegisterSearchRoutes(r.Group(""), NewSearchHandler(ctrl))
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/search", nil))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), dto.KeySearchQRequired)
+		ctrl.AssertExpectations(t)
+	})
+}
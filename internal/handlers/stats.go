@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"net/http"
+
+	"cinematique/internal/auth"
+	"cinematique/internal/controller/dto"
+	"cinematique/internal/domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+// StatsController описывает получение статистики каталога и поисковой аналитики
+type StatsController interface {
+	GetStats(c *gin.Context) (dto.StatsResponse, error)
+	GetTopSearches(c *gin.Context) (dto.TopSearchesResponse, error)
+}
+
+// StatsHandler обрабатывает запросы статистики каталога
+type StatsHandler struct {
+	controller StatsController
+}
+
+// NewStatsHandler создаёт обработчик (handler) статистики
+func NewStatsHandler(controller StatsController) *StatsHandler {
+	return &StatsHandler{controller: controller}
+}
+
+// Get возвращает число фильмов и актёров, средний рейтинг, распределение фильмов
+// по десятилетиям и актёров с наибольшим числом фильмов
+func (h *StatsHandler) Get(c *gin.Context) {
+	resp, err := h.controller.GetStats(c)
+	respond(c, http.StatusOK, resp, err)
+}
+
+// TopSearches возвращает самые частые поисковые запросы за окно ?window= и запросы,
+// которые чаще всего не находили ничего. Статистику собирает консьюмер топика movie-searches
+func (h *StatsHandler) TopSearches(c *gin.Context) {
+	resp, err := h.controller.GetTopSearches(c)
+	respond(c, http.StatusOK, resp, err)
+}
+
+// RegisterStatsRoutes регистрирует маршруты статистики каталога и поисковой аналитики.
+// Поисковая аналитика раскрывает, что ищут пользователи, поэтому доступна только администраторам
+func RegisterStatsRoutes(router *gin.RouterGroup, handler *StatsHandler) {
+	if handler == nil {
+		return
+	}
+	router.GET("/stats", handler.Get)
+	router.GET("/admin/stats/top-searches", auth.RequireRole(domain.RoleAdmin), handler.TopSearches)
+}
@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"cinematique/internal/apperror"
+	"cinematique/internal/auth"
+	"cinematique/internal/controller/dto"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockStatsController мок контроллера статистики
+type MockStatsController struct {
+	mock.Mock
+}
+
+func (m *MockStatsController) GetStats(c *gin.Context) (dto.StatsResponse, error) {
+	args := m.Called(c)
+	return args.Get(0).(dto.StatsResponse), args.Error(1)
+}
+
+func (m *MockStatsController) GetTopSearches(c *gin.Context) (dto.TopSearchesResponse, error) {
+	args := m.Called(c)
+	return args.Get(0).(dto.TopSearchesResponse), args.Error(1)
+}
+
+func TestStatsHandler_Get(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("ok", func(t *testing.T) {
+		ctrl := new(MockStatsController)
+		ctrl.On("GetStats", mock.Anything).Return(dto.StatsResponse{
+			MovieCount:      2,
+			ActorCount:      3,
+			AverageRating:   8.1,
+			MoviesPerDecade: []dto.DecadeCount{{Decade: 1990, Movies: 2}},
+			TopActors:       []dto.ActorFilmCount{{ID: 1, Name: "Keanu Reeves", Movies: 2}},
+		}, nil)
+		r := gin.New()
+		RegisterStatsRoutes(r.Group(""), NewStatsHandler(ctrl))
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/stats", nil))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"movie_count":2,"actor_count":3,"average_rating":8.1,`+
+			`"movies_per_decade":[{"decade":1990,"movies":2}],`+
+			`"top_actors":[{"id":1,"name":"Keanu Reeves","movies":2}]}`, w.Body.String())
+		ctrl.AssertExpectations(t)
+	})
+
+	t.Run("storage error", func(t *testing.T) {
+		ctrl := new(MockStatsController)
+		ctrl.On("GetStats", mock.Anything).Return(dto.StatsResponse{}, errors.New("db down"))
+		r := gin.New()
+		r.Use(apperror.Middleware())
+		RegisterStatsRoutes(r.Group(""), NewStatsHandler(ctrl))
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/stats", nil))
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		ctrl.AssertExpectations(t)
+	})
+}
+
+func TestStatsHandler_TopSearches(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	newRouter := func(ctrl *MockStatsController, role string) *gin.Engine {
+		r := gin.New()
+		r.Use(apperror.Middleware())
+		r.Use(func(c *gin.Context) {
+			auth.SetUser(c, &auth.UserContext{Role: role})
+			c.Next()
+		})
+		RegisterStatsRoutes(r.Group(""), NewStatsHandler(ctrl))
+		return r
+	}
+
+	t.Run("admin", func(t *testing.T) {
+		ctrl := new(MockStatsController)
+		ctrl.On("GetTopSearches", mock.Anything).Return(dto.TopSearchesResponse{
+			Window:         "7d",
+			Since:          "2026-03-01",
+			Top:            []dto.SearchStat{{Query: "matrix", Searches: 42}},
+			TopZeroResults: []dto.SearchStat{{Query: "matrx", Searches: 7, ZeroResults: 7}},
+		}, nil)
+
+		w := httptest.NewRecorder()
+		newRouter(ctrl, "admin").ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/stats/top-searches?window=7d", nil))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"window":"7d","since":"2026-03-01",`+
+			`"top":[{"query":"matrix","searches":42,"zero_results":0}],`+
+			`"top_zero_results":[{"query":"matrx","searches":7,"zero_results":7}]}`, w.Body.String())
+		ctrl.AssertExpectations(t)
+	})
+
+	t.Run("invalid window", func(t *testing.T) {
+		ctrl := new(MockStatsController)
+		ctrl.On("GetTopSearches", mock.Anything).Return(dto.TopSearchesResponse{},
+			fmt.Errorf("validation error: %w", dto.ValidationErrors{dto.NewFieldError(dto.KeyStatsWindowInvalid)}))
+
+		w := httptest.NewRecorder()
+		newRouter(ctrl, "admin").ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/stats/top-searches?window=week", nil))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.JSONEq(t, validationProblem(dto.KeyStatsWindowInvalid), w.Body.String())
+	})
+
+	t.Run("non-admin is forbidden", func(t *testing.T) {
+		ctrl := new(MockStatsController)
+
+		w := httptest.NewRecorder()
+		newRouter(ctrl, "moderator").ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/stats/top-searches", nil))
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		ctrl.AssertNotCalled(t, "GetTopSearches", mock.Anything)
+	})
+}
@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strconv"
+
+	"cinematique/internal/apperror"
+	"cinematique/internal/eventbus"
+	"cinematique/internal/kafka/events"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// errMovieEventNotQueued возвращается в строгом режиме событий, когда событие movie_created не удалось
+// поставить в очередь: фильм удалён, и клиент может повторить запрос, когда Kafka снова доступна
+var errMovieEventNotQueued = apperror.Unavailable("event_not_queued", "movie was not created: its event could not be queued")
+
+var errNoEventBus = errors.New("event bus is not configured")
+
+// movieCreateCompensationsTotal считает откаты создания фильмов в строгом режиме событий.
+// result=failed — фильм или созданных с ним актёров удалить не удалось, и они остались в каталоге без события
+var movieCreateCompensationsTotal = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "movie_create_compensations_total",
+		Help: "Total number of movie creations rolled back because the movie_created event could not be queued.",
+	},
+	[]string{"result"},
+)
+
+func init() {
+	prometheus.MustRegister(movieCreateCompensationsTotal)
+}
+
+// publishMovieCreated публикует событие movie_created о созданном фильме. Без строгого режима
+// ошибка доставки только логируется. В строгом режиме событие обязательно: если его не принял
+// хотя бы один подписчик (например, пул продюсеров Kafka), фильм удаляется вместе с актёрами
+// createdActorIDs, созданными в том же запросе (компенсирующее действие), а вызывающий
+// обработчик отвечает 503
+func publishMovieCreated(c *gin.Context, bus *eventbus.Bus, strict bool, controller MovieController, movieID int, createdActorIDs []int) error {
+	if !strict {
+		publishCatalogChange(c.Request.Context(), bus, "movie", catalogActionCreated, movieID)
+		return nil
+	}
+
+	err := errNoEventBus
+	if bus != nil {
+		event := events.NewCatalogChanged("movie", catalogActionCreated, movieID)
+		err = publishEvent(c.Request.Context(), bus, CatalogChangesTopic, []byte("movie:"+strconv.Itoa(movieID)), event)
+	}
+	if err == nil {
+		return nil
+	}
+
+	log.Printf("Movie created event was not queued (id: %d), deleting the movie: %v", movieID, err)
+	// Отправка могла не удаться из-за отменённого запроса, а удалить фильм нужно всё равно
+	compensation := c.Copy()
+	compensation.Request = c.Request.WithContext(context.WithoutCancel(c.Request.Context()))
+	if delErr := controller.DeleteCreatedMovie(compensation, movieID, createdActorIDs); delErr != nil {
+		movieCreateCompensationsTotal.WithLabelValues("failed").Inc()
+		log.Printf("CRITICAL: movie %d or its new actors %v stay in the catalog without a created event: %v", movieID, createdActorIDs, delErr)
+	} else {
+		movieCreateCompensationsTotal.WithLabelValues("compensated").Inc()
+	}
+	return errMovieEventNotQueued
+}
@@ -0,0 +1,148 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"cinematique/internal/apperror"
+	"cinematique/internal/controller/dto"
+	"cinematique/internal/kafka"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestMovieHandler_Create_StrictEvents(t *testing.T) {
+	body := `{"title":"Heat","description":"Crime drama","release_year":1995,"rating":8.3}`
+	created := dto.MovieResponse{ID: 7, Title: "Heat", Description: "Crime drama", ReleaseYear: 1995, Rating: 8.3}
+
+	// closedPool отклоняет любые события, как пул при недоступной Kafka
+	closedPool := func() *kafka.ProducerPool {
+		producer := kafka.NewMockProducer()
+		producer.On("Close").Return(nil)
+		pool := kafka.NewProducerPool(producer, 1, 1)
+		pool.Close()
+		return pool
+	}
+
+	tests := []struct {
+		name           string
+		pool           func() *kafka.ProducerPool
+		strict         bool
+		deleteErr      error
+		expectDelete   bool
+		expectedStatus int
+		result         string
+	}{
+		{
+			name: "event queued",
+			pool: func() *kafka.ProducerPool {
+				producer := kafka.NewMockProducer()
+				producer.On("Produce", mock.Anything, CatalogChangesTopic, []byte("movie:7"), mock.Anything).Return(nil).Maybe()
+				return kafka.NewProducerPool(producer, 1, 10)
+			},
+			strict:         true,
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "best effort without strict mode",
+			pool:           closedPool,
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "movie deleted when event is lost",
+			pool:           closedPool,
+			strict:         true,
+			expectDelete:   true,
+			expectedStatus: http.StatusServiceUnavailable,
+			result:         "compensated",
+		},
+		{
+			name:           "compensation fails",
+			pool:           closedPool,
+			strict:         true,
+			deleteErr:      errors.New("connection refused"),
+			expectDelete:   true,
+			expectedStatus: http.StatusServiceUnavailable,
+			result:         "failed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			mockCtrl := new(MockMovieController)
+			mockCtrl.On("CreateMovie", mock.Anything, mock.Anything).Return(created, nil)
+			if tt.expectDelete {
+				mockCtrl.On("DeleteCreatedMovie", mock.Anything, 7, []int(nil)).Return(tt.deleteErr).Once()
+			}
+			handler := NewMovieHandler(mockCtrl, kafkaBus(tt.pool())).WithStrictEvents(tt.strict)
+
+			r := gin.New()
+			r.Use(apperror.Middleware())
+			r.POST("/movies", handler.Create)
+
+			var before float64
+			if tt.result != "" {
+				before = testutil.ToFloat64(movieCreateCompensationsTotal.WithLabelValues(tt.result))
+			}
+			req, _ := http.NewRequest(http.MethodPost, "/movies", bytes.NewBufferString(body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus == http.StatusServiceUnavailable {
+				assert.JSONEq(t, problem(http.StatusServiceUnavailable, "event_not_queued", errMovieEventNotQueued.Message), w.Body.String())
+				assert.Equal(t, before+1, testutil.ToFloat64(movieCreateCompensationsTotal.WithLabelValues(tt.result)))
+			}
+			mockCtrl.AssertExpectations(t)
+			if !tt.expectDelete {
+				mockCtrl.AssertNotCalled(t, "DeleteCreatedMovie", mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+// TestMovieHandler_CreateFull_StrictEvents проверяет, что откат удаляет и актёров, созданных вместе с фильмом
+func TestMovieHandler_CreateFull_StrictEvents(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	producer := kafka.NewMockProducer()
+	producer.On("Close").Return(nil)
+	pool := kafka.NewProducerPool(producer, 1, 1)
+	pool.Close()
+
+	mockCtrl := new(MockMovieController)
+	mockCtrl.On("CreateMovieFull", mock.Anything, mock.Anything).Return(dto.FullMovieResponse{
+		Movie:           dto.MovieResponse{ID: 10, Title: "The Matrix", ReleaseYear: 1999},
+		Actors:          []dto.ActorResponse{{ID: 11, Name: "Keanu Reeves"}, {ID: 12, Name: "Carrie-Anne Moss"}, {ID: 5, Name: "Laurence Fishburne"}},
+		ActorsCreated:   2,
+		CreatedActorIDs: []int{11, 12},
+	}, nil)
+	mockCtrl.On("DeleteCreatedMovie", mock.Anything, 10, []int{11, 12}).Return(nil).Once()
+	handler := NewMovieHandler(mockCtrl, kafkaBus(pool)).WithStrictEvents(true)
+
+	r := gin.New()
+	r.Use(apperror.Middleware())
+	r.POST("/movies/full", handler.CreateFull)
+	req, _ := http.NewRequest(http.MethodPost, "/movies/full", bytes.NewBufferString(`{
+		"title": "The Matrix",
+		"release_year": 1999,
+		"actors": [
+			{"name": "Keanu Reeves", "gender": "male", "birth_date": "1964-09-02"},
+			{"name": "Carrie-Anne Moss", "gender": "female", "birth_date": "1967-08-21"}
+		],
+		"actor_ids": [5]
+	}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	mockCtrl.AssertExpectations(t)
+}
@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Tags возвращает теги фильма
+func (h *MovieHandler) Tags(c *gin.Context) {
+	movieID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		respondError(c, errInvalidID)
+		return
+	}
+	resp, err := h.controller.GetMovieTags(c, movieID)
+	respond(c, http.StatusOK, resp, err)
+}
+
+// AddTag добавляет фильму тег :tag; повторное добавление не считается ошибкой
+func (h *MovieHandler) AddTag(c *gin.Context) {
+	movieID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		respondError(c, errInvalidID)
+		return
+	}
+	resp, err := h.controller.AddMovieTag(c, movieID, c.Param("tag"))
+	respond(c, http.StatusOK, resp, err)
+}
+
+// RemoveTag снимает с фильма тег :tag
+func (h *MovieHandler) RemoveTag(c *gin.Context) {
+	movieID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		respondError(c, errInvalidID)
+		return
+	}
+	err = h.controller.RemoveMovieTag(c, movieID, c.Param("tag"))
+	respond(c, http.StatusNoContent, nil, err)
+}
+
+// ByTag возвращает страницу фильмов с тегом :tag и их общее число
+func (h *MovieHandler) ByTag(c *gin.Context) {
+	resp, err := h.controller.GetMoviesByTag(c, c.Param("tag"))
+	respond(c, http.StatusOK, resp, err)
+}
+
+// SuggestTags возвращает подсказки для автодополнения тега
+func (h *MovieHandler) SuggestTags(c *gin.Context) {
+	resp, err := h.controller.SuggestTags(c)
+	respond(c, http.StatusOK, resp, err)
+}
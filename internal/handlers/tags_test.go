@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"cinematique/internal/apperror"
+	"cinematique/internal/auth"
+	"cinematique/internal/controller/dto"
+	"cinematique/internal/domain"
+	"cinematique/internal/kafka"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestMovieHandler_Tags(t *testing.T) {
+	tests := []struct {
+		name           string
+		role           string
+		method         string
+		url            string
+		setupMock      func(*MockMovieController)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:   "list movie tags",
+			role:   domain.RoleUser,
+			method: http.MethodGet,
+			url:    "/movies/1/tags",
+			setupMock: func(m *MockMovieController) {
+				m.On("GetMovieTags", mock.Anything, 1).Return(dto.MovieTagsResponse{MovieID: 1, Tags: []string{"cult-classic"}}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"movie_id":1,"tags":["cult-classic"]}`,
+		},
+		{
+			name:   "admin adds tag",
+			role:   domain.RoleAdmin,
+			method: http.MethodPut,
+			url:    "/movies/1/tags/neo-noir",
+			setupMock: func(m *MockMovieController) {
+				m.On("AddMovieTag", mock.Anything, 1, "neo-noir").Return(dto.MovieTagsResponse{MovieID: 1, Tags: []string{"neo-noir"}}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"movie_id":1,"tags":["neo-noir"]}`,
+		},
+		{
+			name:           "moderator cannot add tag",
+			role:           domain.RoleModerator,
+			method:         http.MethodPut,
+			url:            "/movies/1/tags/neo-noir",
+			setupMock:      func(m *MockMovieController) {},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:   "remove missing tag",
+			role:   domain.RoleAdmin,
+			method: http.MethodDelete,
+			url:    "/movies/1/tags/neo-noir",
+			setupMock: func(m *MockMovieController) {
+				m.On("RemoveMovieTag", mock.Anything, 1, "neo-noir").Return(domain.ErrTagNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:   "movies by tag",
+			role:   domain.RoleUser,
+			method: http.MethodGet,
+			url:    "/tags/neo-noir/movies",
+			setupMock: func(m *MockMovieController) {
+				m.On("GetMoviesByTag", mock.Anything, "neo-noir").Return(dto.MoviesByTagResponse{
+					Tag: "neo-noir", Total: 1, Movies: []dto.MovieResponse{{ID: 1, Title: "Chinatown"}},
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:   "suggest",
+			role:   domain.RoleUser,
+			method: http.MethodGet,
+			url:    "/tags/suggest?q=neo",
+			setupMock: func(m *MockMovieController) {
+				m.On("SuggestTags", mock.Anything).Return(dto.TagSuggestionsResponse{Tags: []dto.TagSuggestion{{Name: "neo-noir", MovieCount: 4}}}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"tags":[{"name":"neo-noir","movie_count":4}]}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			r := gin.New()
+			r.Use(apperror.Middleware())
+			r.Use(func(c *gin.Context) {
+				auth.SetUser(c, &auth.UserContext{Role: tt.role})
+				c.Next()
+			})
+			ctrl := new(MockMovieController)
+			tt.setupMock(ctrl)
+			RegisterMovieRoutes(r.Group(""), newTestMovieHandler(ctrl, kafka.NewMockProducer()))
+
+			req, _ := http.NewRequest(tt.method, tt.url, nil)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedBody != "" {
+				assert.JSONEq(t, tt.expectedBody, w.Body.String())
+			}
+			ctrl.AssertExpectations(t)
+		})
+	}
+}
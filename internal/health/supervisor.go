@@ -0,0 +1,259 @@
+package health
+
+import (
+	"context"
+	"log"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Status — состояние подсистемы или сервиса в целом
+type Status string
+
+const (
+	StatusOK         Status = "ok"
+	StatusDegraded   Status = "degraded"
+	StatusDown       Status = "down"
+	StatusRestarting Status = "restarting"
+)
+
+// CheckFunc проверяет подсистему; ошибка означает, что подсистема неработоспособна
+type CheckFunc func(ctx context.Context) error
+
+// RestartFunc перезапускает подсистему внутри процесса
+type RestartFunc func(ctx context.Context) error
+
+// Option настраивает регистрацию подсистемы
+type Option func(*subsystem)
+
+// WithRestart разрешает супервизору перезапускать подсистему после неудачной проверки
+func WithRestart(restart RestartFunc) Option {
+	return func(s *subsystem) { s.restart = restart }
+}
+
+// Critical помечает подсистему как критичную: пока она не работает, сервис не готов
+func Critical() Option {
+	return func(s *subsystem) { s.critical = true }
+}
+
+// WithWeight задаёт вес подсистемы в общей оценке (по умолчанию 1)
+func WithWeight(weight float64) Option {
+	return func(s *subsystem) { s.weight = weight }
+}
+
+// SubsystemReport — состояние подсистемы для /readyz
+type SubsystemReport struct {
+	Status        Status     `json:"status"`
+	Critical      bool       `json:"critical"`
+	Error         string     `json:"error,omitempty"`
+	LastCheck     time.Time  `json:"last_check"`
+	LastHealthy   *time.Time `json:"last_healthy,omitempty"`
+	Restarts      int        `json:"restarts"`
+	NextRestartAt *time.Time `json:"next_restart_at,omitempty"`
+}
+
+// Report — сводное состояние сервиса
+type Report struct {
+	Status     Status                     `json:"status"`
+	Score      float64                    `json:"score"` // доля работоспособных подсистем с учётом весов, от 0 до 1
+	Subsystems map[string]SubsystemReport `json:"subsystems"`
+}
+
+type subsystem struct {
+	name     string
+	check    CheckFunc
+	restart  RestartFunc
+	critical bool
+	weight   float64
+
+	status        Status
+	err           error
+	lastCheck     time.Time
+	lastHealthy   *time.Time
+	restarts      int // всего перезапусков
+	failedStreak  int // перезапусков подряд без восстановления; определяет задержку
+	nextRestartAt time.Time
+}
+
+// Supervisor периодически проверяет подсистемы (пул продюсеров Kafka, консьюмеры,
+// кэш и т.п.) и перезапускает упавшие с ограниченной экспоненциальной задержкой
+type Supervisor struct {
+	mu           sync.RWMutex
+	subsystems   map[string]*subsystem
+	interval     time.Duration
+	checkTimeout time.Duration
+	minBackoff   time.Duration
+	maxBackoff   time.Duration
+	now          func() time.Time
+}
+
+// NewSupervisor создаёт супервизор с периодом проверок interval.
+// Задержка между перезапусками растёт от minBackoff до maxBackoff
+func NewSupervisor(interval, minBackoff, maxBackoff time.Duration) *Supervisor {
+	return &Supervisor{
+		subsystems:   make(map[string]*subsystem),
+		interval:     interval,
+		checkTimeout: 5 * time.Second,
+		minBackoff:   minBackoff,
+		maxBackoff:   maxBackoff,
+		now:          time.Now,
+	}
+}
+
+// Register добавляет подсистему под наблюдение
+func (s *Supervisor) Register(name string, check CheckFunc, opts ...Option) {
+	sub := &subsystem{name: name, check: check, weight: 1, status: StatusOK}
+	for _, opt := range opts {
+		opt(sub)
+	}
+	s.mu.Lock()
+	s.subsystems[name] = sub
+	s.mu.Unlock()
+}
+
+// Run выполняет проверки каждые interval до отмены ctx
+func (s *Supervisor) Run(ctx context.Context) {
+	s.CheckNow(ctx)
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.CheckNow(ctx)
+		}
+	}
+}
+
+// CheckNow проверяет все подсистемы и перезапускает упавшие, если подошёл их срок
+func (s *Supervisor) CheckNow(ctx context.Context) {
+	s.mu.RLock()
+	subsystems := make([]*subsystem, 0, len(s.subsystems))
+	for _, sub := range s.subsystems {
+		subsystems = append(subsystems, sub)
+	}
+	s.mu.RUnlock()
+
+	for _, sub := range subsystems {
+		s.checkOne(ctx, sub)
+	}
+}
+
+func (s *Supervisor) checkOne(ctx context.Context, sub *subsystem) {
+	checkCtx, cancel := context.WithTimeout(ctx, s.checkTimeout)
+	err := sub.check(checkCtx)
+	cancel()
+
+	now := s.now()
+	s.mu.Lock()
+	sub.lastCheck = now
+	sub.err = err
+	if err == nil {
+		if sub.status != StatusOK {
+			log.Printf("Subsystem %s recovered", sub.name)
+		}
+		sub.status = StatusOK
+		sub.lastHealthy = &now
+		sub.failedStreak = 0
+		sub.nextRestartAt = time.Time{}
+		s.mu.Unlock()
+		return
+	}
+
+	if sub.status == StatusOK {
+		log.Printf("Subsystem %s is unhealthy: %v", sub.name, err)
+	}
+	sub.status = StatusDown
+	if sub.restart == nil {
+		s.mu.Unlock()
+		return
+	}
+	if sub.nextRestartAt.IsZero() {
+		// Первая неудача: перезапуск после минимальной задержки
+		sub.nextRestartAt = now.Add(s.backoff(sub.failedStreak))
+	}
+	if now.Before(sub.nextRestartAt) {
+		s.mu.Unlock()
+		return
+	}
+	sub.status = StatusRestarting
+	sub.restarts++
+	sub.failedStreak++
+	sub.nextRestartAt = now.Add(s.backoff(sub.failedStreak))
+	restart := sub.restart
+	s.mu.Unlock()
+
+	log.Printf("Restarting subsystem %s (attempt %d)", sub.name, sub.failedStreak)
+	if err := restart(ctx); err != nil {
+		log.Printf("Failed to restart subsystem %s: %v", sub.name, err)
+		s.mu.Lock()
+		sub.status = StatusDown
+		sub.err = err
+		s.mu.Unlock()
+	}
+}
+
+// backoff возвращает задержку перед следующим перезапуском после attempt неудачных попыток
+func (s *Supervisor) backoff(attempt int) time.Duration {
+	delay := s.minBackoff
+	for i := 0; i < attempt && delay < s.maxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > s.maxBackoff {
+		delay = s.maxBackoff
+	}
+	return delay
+}
+
+// Report возвращает состояние всех подсистем и общую оценку.
+// Сервис «down», если не работает хотя бы одна критичная подсистема,
+// и «degraded», если не работает какая-то некритичная
+func (s *Supervisor) Report() Report {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	report := Report{Status: StatusOK, Score: 1, Subsystems: make(map[string]SubsystemReport, len(s.subsystems))}
+	var total, healthy float64
+
+	names := make([]string, 0, len(s.subsystems))
+	for name := range s.subsystems {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		sub := s.subsystems[name]
+		item := SubsystemReport{
+			Status:      sub.status,
+			Critical:    sub.critical,
+			LastCheck:   sub.lastCheck,
+			LastHealthy: sub.lastHealthy,
+			Restarts:    sub.restarts,
+		}
+		if sub.err != nil {
+			item.Error = sub.err.Error()
+		}
+		if !sub.nextRestartAt.IsZero() {
+			next := sub.nextRestartAt
+			item.NextRestartAt = &next
+		}
+		report.Subsystems[name] = item
+
+		total += sub.weight
+		if sub.status == StatusOK {
+			healthy += sub.weight
+			continue
+		}
+		if sub.critical {
+			report.Status = StatusDown
+		} else if report.Status == StatusOK {
+			report.Status = StatusDegraded
+		}
+	}
+	if total > 0 {
+		report.Score = healthy / total
+	}
+	return report
+}
@@ -0,0 +1,115 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestSupervisor создаёт супервизор с управляемыми часами
+func newTestSupervisor(now *time.Time) *Supervisor {
+	s := NewSupervisor(time.Second, time.Second, 4*time.Second)
+	s.now = func() time.Time { return *now }
+	return s
+}
+
+func TestSupervisor_ReportStatuses(t *testing.T) {
+	now := time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)
+	s := newTestSupervisor(&now)
+
+	var cacheErr, dbErr error
+	s.Register("database", func(ctx context.Context) error { return dbErr }, Critical())
+	s.Register("cache", func(ctx context.Context) error { return cacheErr })
+
+	s.CheckNow(context.Background())
+	report := s.Report()
+	assert.Equal(t, StatusOK, report.Status)
+	assert.Equal(t, 1.0, report.Score)
+
+	cacheErr = errors.New("connection refused")
+	s.CheckNow(context.Background())
+	report = s.Report()
+	assert.Equal(t, StatusDegraded, report.Status)
+	assert.Equal(t, 0.5, report.Score)
+	assert.Equal(t, StatusDown, report.Subsystems["cache"].Status)
+	assert.Equal(t, "connection refused", report.Subsystems["cache"].Error)
+
+	dbErr = errors.New("timeout")
+	s.CheckNow(context.Background())
+	report = s.Report()
+	assert.Equal(t, StatusDown, report.Status)
+	assert.Equal(t, 0.0, report.Score)
+}
+
+func TestSupervisor_RestartsWithBoundedBackoff(t *testing.T) {
+	now := time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)
+	s := newTestSupervisor(&now)
+
+	healthy := false
+	restarts := 0
+	s.Register("consumer",
+		func(ctx context.Context) error {
+			if healthy {
+				return nil
+			}
+			return errors.New("stopped")
+		},
+		WithRestart(func(ctx context.Context) error {
+			restarts++
+			return nil
+		}))
+
+	// Первая неудача: перезапуск откладывается на минимальную задержку
+	s.CheckNow(context.Background())
+	assert.Equal(t, 0, restarts)
+
+	now = now.Add(time.Second)
+	s.CheckNow(context.Background())
+	assert.Equal(t, 1, restarts)
+	assert.Equal(t, StatusRestarting, s.Report().Subsystems["consumer"].Status)
+
+	// Следующая попытка — через удвоенную задержку
+	now = now.Add(time.Second)
+	s.CheckNow(context.Background())
+	assert.Equal(t, 1, restarts)
+	now = now.Add(time.Second)
+	s.CheckNow(context.Background())
+	assert.Equal(t, 2, restarts)
+
+	// Задержка не превышает максимальную
+	now = now.Add(4 * time.Second)
+	s.CheckNow(context.Background())
+	assert.Equal(t, 3, restarts)
+	next := s.Report().Subsystems["consumer"].NextRestartAt
+	if assert.NotNil(t, next) {
+		assert.Equal(t, now.Add(4*time.Second), *next)
+	}
+
+	// После восстановления задержка сбрасывается
+	healthy = true
+	s.CheckNow(context.Background())
+	report := s.Report().Subsystems["consumer"]
+	assert.Equal(t, StatusOK, report.Status)
+	assert.Equal(t, 3, report.Restarts)
+	assert.Nil(t, report.NextRestartAt)
+}
+
+func TestSupervisor_FailedRestartKeepsSubsystemDown(t *testing.T) {
+	now := time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)
+	s := newTestSupervisor(&now)
+	s.Register("pool",
+		func(ctx context.Context) error { return errors.New("workers stopped") },
+		WithRestart(func(ctx context.Context) error { return errors.New("pool is closed") }))
+
+	s.CheckNow(context.Background())
+	now = now.Add(time.Second)
+	s.CheckNow(context.Background())
+
+	report := s.Report().Subsystems["pool"]
+	assert.Equal(t, StatusDown, report.Status)
+	assert.Equal(t, "pool is closed", report.Error)
+	assert.Equal(t, 1, report.Restarts)
+}
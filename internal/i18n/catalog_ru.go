@@ -0,0 +1,168 @@
+package i18n
+
+// russian — русские сообщения по кодам ошибок API и ключам ошибок валидации полей
+var russian = map[string]string{
+	// Общие ошибки
+	"internal_error":            "внутренняя ошибка сервера",
+	"invalid_request":           "неверный запрос",
+	"invalid_body":              "не удалось прочитать тело запроса",
+	"invalid_id":                "некорректный идентификатор",
+	"invalid_parameter":         "некорректный параметр запроса",
+	"invalid_sort":              "некорректный параметр сортировки",
+	"invalid_export_format":     "формат должен быть ndjson или csv",
+	"field_required":            "не заполнено обязательное поле",
+	"rating_out_of_range":       "рейтинг должен быть от 0 до 10",
+	"search_parameter_required": "не указан параметр поиска",
+	"no_fields_to_update":       "нет полей для изменения",
+	"validation_failed":         "ошибка валидации запроса",
+	"request_body_too_large":    "тело запроса слишком большое",
+	"json_too_deep":             "слишком глубокая вложенность JSON",
+	"malformed_json":            "тело запроса не является корректным JSON",
+
+	// Ключи идемпотентности
+	"idempotency_key_too_long":        "Idempotency-Key не может быть длиннее 255 символов",
+	"idempotency_key_reused":          "Idempotency-Key уже использован с другим запросом",
+	"idempotency_request_in_progress": "запрос с этим Idempotency-Key ещё выполняется",
+
+	// Каталог
+	"movie_not_found":             "фильм не найден",
+	"actor_not_found":             "актёр не найден",
+	"actor_has_movies":            "нельзя удалить актёра, у которого есть фильмы",
+	"actor_already_in_movie":      "актёр уже есть в фильме",
+	"actor_not_in_movie":          "актёра нет в фильме",
+	"merge_same_actor":            "нельзя слить актёра с самим собой",
+	"merge_same_movie":            "нельзя слить фильм с самим собой",
+	"duplicate_movie":             "похоже, такой фильм уже есть",
+	"collection_not_found":        "подборка не найдена",
+	"availability_not_found":      "окно доступности не найдено",
+	"tag_not_found":               "у фильма нет такого тега",
+	"collection_duplicate_movie":  "фильм встречается в подборке больше одного раза",
+	"external_import_disabled":    "импорт из внешнего каталога не настроен",
+	"external_movie_not_found":    "фильм не найден во внешнем каталоге",
+	"external_source_unavailable": "внешний каталог фильмов недоступен",
+	"job_not_found":               "задача не найдена",
+	"orphan_purge_unconfirmed":    "актёров без фильмов больше порога: подтвердите удаление, передав их число в confirm_count",
+	"movie_referenced":            "нельзя удалить фильм: на него ссылаются другие записи",
+	"already_exists":              "такая запись уже существует",
+	"reference_not_found":         "запись, на которую ссылается запрос, не существует",
+
+	// Пользователи и аутентификация
+	"unauthorized":              "требуется аутентификация",
+	"missing_token":             "отсутствует или неверный токен",
+	"invalid_token":             "неверный токен",
+	"invalid_keycloak_token":    "неверный Keycloak токен",
+	"refresh_token_not_allowed": "refresh-токен не может быть использован для аутентификации",
+	"token_revoked":             "токен отозван",
+	"revocation_unavailable":    "проверка отзыва токена недоступна, повторите запрос позже",
+	"token_version_unavailable": "проверка роли в токене недоступна, повторите запрос позже",
+	"token_outdated":            "роль пользователя изменилась, обновите токен",
+	"missing_role":              "нет роли в токене",
+	"admin_only":                "только администратор может изменять данные",
+	"insufficient_permission":   "недостаточно прав: требуется %s",
+	"role_required":             "требуется роль %s",
+	"any_role_required":         "требуется одна из ролей: %v",
+	"fields_forbidden":          "роли не разрешено менять эти поля",
+	"registration_failed":       "не удалось зарегистрировать пользователя",
+	"invalid_credentials":       "неверное имя пользователя или пароль",
+	"invalid_refresh_token":     "неверный refresh-токен",
+	"logout_failed":             "не удалось выйти",
+	"event_publish_failed":      "не удалось отправить событие",
+	"event_not_queued":          "фильм не создан: не удалось поставить событие в очередь",
+	"weak_password":             "пароль не соответствует парольной политике",
+	"account_locked":            "учётная запись временно заблокирована",
+	"user_not_found":            "пользователь не найден",
+	"invalid_role":              "неизвестная роль пользователя",
+	"invalid_current_password":  "неверный текущий пароль",
+	"external_account":          "учётной записью управляет поставщик удостоверений",
+	"invalid_reset_token":       "ссылка для сброса пароля недействительна или устарела",
+	"password_reset_disabled":   "сброс пароля по email не настроен",
+	"user_already_exists":       "имя пользователя или email уже заняты",
+	"email_taken":               "email уже занят",
+
+	// Ошибки валидации полей (ключи dto.ValidationKeys)
+	"movie.title.required":                        "должно быть от 1 до 150 символов",
+	"movie.title.too_long":                        "должно быть от 1 до 150 символов",
+	"movie.description.too_long":                  "слишком длинное (не более 1000 символов)",
+	"movie.rating.out_of_range":                   "должен быть от 0 до 10",
+	"movie.release_date.invalid_format":           "должна быть в формате YYYY-MM-DD",
+	"movie.as_of.invalid_format":                  "должна быть датой (YYYY-MM-DD) или моментом времени RFC 3339",
+	"movie.imdb_id.invalid_format":                "должен быть идентификатором IMDb вида tt0133093",
+	"movie.original_language.invalid":             "должен быть кодом языка ISO 639-1, например en",
+	"movie.country.invalid":                       "должна быть кодом страны ISO 3166-1 alpha-2, например US",
+	"movie.search.language_invalid":               "должен быть кодом языка ISO 639-1, например en",
+	"movie.search.region_invalid":                 "должен быть кодом страны ISO 3166-1 alpha-2, например DE",
+	"movie.search.available_invalid":              "должен быть true или false",
+	"movie.runtime_minutes.invalid":               "должна быть от 1 до 999 минут",
+	"movie.search.runtime_min_invalid":            "должен быть целым числом минут от 1 до 999",
+	"movie.search.runtime_max_invalid":            "должен быть целым числом минут от 1 до 999",
+	"movie.search.runtime_range_invalid":          "не должен быть меньше runtime_min",
+	"movie.search.highlight_invalid":              "должен быть true или false",
+	"movie.search.title_too_short":                "должен содержать не меньше 2 символов",
+	"movie.search.actor_name_too_short":           "должен содержать не меньше 2 символов",
+	"movie.year.invalid":                          "должен быть годом от 1000 до 9999",
+	"movie.decade.invalid":                        "должен быть первым годом десятилетия, например 1990 или 1990s",
+	"movie.external_id.invalid":                   "должен состоять из 1-64 латинских букв, цифр и символов . _ : -",
+	"availability.region.invalid":                 "должен быть кодом страны ISO 3166-1 alpha-2, например DE",
+	"availability.available_from.invalid_format":  "должна быть в формате YYYY-MM-DD",
+	"availability.available_until.invalid_format": "должна быть в формате YYYY-MM-DD",
+	"availability.available_until.before_from":    "не может быть раньше available_from",
+	"bulk.ids.required":                           "нужен хотя бы один ID фильма",
+	"bulk.ids.too_many":                           "не более 500 ID",
+	"bulk.ids.invalid":                            "ID должны быть положительными",
+	"bulk.changes.required":                       "нужно указать хотя бы одно изменяемое поле",
+	"cast.required":                               "нужен хотя бы один актёр в actor_ids или cast",
+	"cast.actor_id.invalid":                       "должен быть положительным ID актёра",
+	"cast.character_name.too_long":                "слишком длинное (не более 255 символов)",
+	"cast.billing_order.invalid":                  "должен быть неотрицательным целым числом",
+	"cast.full.required":                          "нужен хотя бы один актёр в actors или actor_ids",
+	"cast.order.required":                         "нужен хотя бы один ID актёра",
+	"cast.order.invalid":                          "должен содержать только положительные ID актёров",
+	"cast.order.duplicate":                        "не должен содержать актёра дважды",
+	"cast.change.required":                        "нужен хотя бы один актёр в add или remove",
+	"cast.remove.invalid":                         "должен содержать только положительные ID актёров",
+	"cast.change.conflict":                        "не должен содержать актёра, который есть в add",
+	"actor.name.length":                           "должно быть от 1 до 100 символов",
+	"actor.gender.invalid":                        "должно быть 'male', 'female' или 'other'",
+	"actor.birth_date.invalid_format":             "должна быть в формате YYYY-MM-DD",
+	"actor.birth_date.in_future":                  "не может быть в будущем",
+	"actor.birth_date.too_early":                  "не может быть раньше 1900-01-01",
+	"actor.death_date.invalid_format":             "должна быть в формате YYYY-MM-DD",
+	"actor.death_date.in_future":                  "не может быть в будущем",
+	"actor.death_date.before_birth":               "должна быть позже даты рождения",
+	"actor.photo.too_large":                       "файл больше 5 МБ",
+	"actor.photo.unsupported_type":                "поддерживаются только JPEG, PNG и WebP",
+	"actor.search.name_required":                  "обязательный параметр поиска",
+	"actor.search.name_too_short":                 "должен содержать не меньше 2 символов",
+	"actor.list.living_invalid":                   "должен быть true или false",
+	"actor.birthdays.month_invalid":               "должен быть числом от 1 до 12",
+	"actor.suggest.q_required":                    "обязательный параметр поиска",
+	"actor.suggest.limit_invalid":                 "должен быть числом от 1 до 50",
+	"actor.orphans.confirm_count_invalid":         "должно быть неотрицательным целым числом",
+	"actor.with_movies.movies_per_actor_invalid":  "должно быть числом от 1 до 50",
+	"actor.import.file.invalid":                   "должен быть CSV-файлом со строкой заголовка, содержащей name, gender и birth_date",
+	"actor.import.file.empty":                     "должен содержать хотя бы одну строку с актёром",
+	"actor.import.file.too_many_rows":             "должен содержать не более 1000 строк с актёрами",
+	"actor.import.duplicate_row":                  "повторяет одну из предыдущих строк с тем же именем и датой рождения",
+	"actor.import.already_exists":                 "актёр с таким именем и датой рождения уже существует",
+	"stats.window.invalid":                        "должно быть числом дней от 1d до 365d",
+	"search.q.required":                           "обязательный параметр поиска",
+	"search.q.too_short":                          "должен содержать не меньше 2 символов",
+	"collection.name.length":                      "должно быть от 1 до 150 символов",
+	"collection.description.too_long":             "слишком длинное (не более 1000 символов)",
+	"tag.name.invalid":                            "должен содержать от 1 до 50 букв или цифр, слова разделяются дефисом",
+	"tag.suggest.q_required":                      "обязательный параметр поиска",
+	"tag.suggest.limit_invalid":                   "должен быть числом от 1 до 50",
+	"list.sort.empty_field":                       "пустое имя поля",
+	"list.limit.invalid":                          "должен быть неотрицательным целым числом",
+	"rating.source.invalid":                       "должен быть imdb или rotten_tomatoes",
+	"rating.value.required":                       "обязательное поле",
+	"rating.value.out_of_range":                   "должен быть от 0 до максимума шкалы источника",
+	"list.fields.unknown":                         "неизвестное поле",
+	"list.limit.too_large":                        "больше наибольшего размера страницы",
+	"list.offset.invalid":                         "должен быть неотрицательным целым числом",
+	"list.cursor.invalid":                         "должен быть значением next_cursor с предыдущей страницы",
+	"request.field.required":                      "обязательное поле",
+	"request.field.invalid_type":                  "неверный тип значения",
+	"request.field.out_of_range":                  "значение вне допустимого диапазона",
+	"request.field.invalid":                       "недопустимое значение",
+}
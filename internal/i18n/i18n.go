@@ -0,0 +1,93 @@
+// Package i18n выбирает язык сообщений об ошибках по заголовку Accept-Language.
+// Клиенты опираются на стабильные коды ошибок (movie_not_found, movie.title.too_long),
+// а текст сообщения служит только для показа человеку. Исходные сообщения пишутся
+// на английском там же, где объявлена ошибка; каталоги пакета содержат переводы
+package i18n
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Поддерживаемые языки
+const (
+	English = "en"
+	Russian = "ru"
+	// Default — язык ответа, если клиент не указал поддерживаемый язык
+	Default = English
+)
+
+// catalogs — переводы сообщений по коду ошибки. Английского каталога нет:
+// английские сообщения задаются при объявлении ошибки
+var catalogs = map[string]map[string]string{
+	Russian: russian,
+}
+
+// contextKey — ключ выбранного языка в gin.Context
+const contextKey = "i18n_lang"
+
+// Supported сообщает, есть ли сообщения на языке lang
+func Supported(lang string) bool {
+	if lang == English {
+		return true
+	}
+	_, ok := catalogs[lang]
+	return ok
+}
+
+// Negotiate выбирает язык из заголовка Accept-Language с учётом весов q.
+// Сравнивается только основной подтег (ru-RU → ru); если подходящего языка нет, возвращается Default
+func Negotiate(acceptLanguage string) string {
+	best, bestQ := Default, 0.0
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(part, ";")
+		q := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		lang, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(tag)), "-")
+		if q > bestQ && Supported(lang) {
+			best, bestQ = lang, q
+		}
+	}
+	return best
+}
+
+// FromContext возвращает язык ответа на запрос; результат разбора заголовка запоминается в контексте
+func FromContext(c *gin.Context) string {
+	if lang := c.GetString(contextKey); lang != "" {
+		return lang
+	}
+	lang := Negotiate(c.GetHeader("Accept-Language"))
+	c.Set(contextKey, lang)
+	return lang
+}
+
+// Translate возвращает сообщение для кода ошибки на языке lang. message — английское
+// сообщение, оно же используется, если перевода нет. С аргументами сообщение и перевод
+// считаются форматными строками fmt
+func Translate(lang, code, message string, args ...interface{}) string {
+	format := message
+	if translated, ok := catalogs[lang][code]; ok {
+		format = translated
+	}
+	if len(args) == 0 {
+		return format
+	}
+	return fmt.Sprintf(format, args...)
+}
+
+// ErrorJSON возвращает тело ответа об ошибке в формате {"error": сообщение, "code": код}
+// для обработчиков и middleware, которые отвечают не в формате RFC 7807
+func ErrorJSON(c *gin.Context, code, message string, args ...interface{}) gin.H {
+	lang := FromContext(c)
+	c.Header("Content-Language", lang)
+	return gin.H{"error": Translate(lang, code, message, args...), "code": code}
+}
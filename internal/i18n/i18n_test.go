@@ -0,0 +1,55 @@
+package i18n
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		header   string
+		expected string
+	}{
+		{"", English},
+		{"ru", Russian},
+		{"ru-RU,ru;q=0.9,en-US;q=0.8", Russian},
+		{"en-US,en;q=0.9,ru;q=0.8", English},
+		{"de-DE,ru;q=0.5", Russian},
+		{"ru;q=0.3,en;q=0.7", English},
+		{"de, fr;q=0.8", English},
+		{"ru;q=bad", English},
+		{"RU", Russian},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			assert.Equal(t, tt.expected, Negotiate(tt.header))
+		})
+	}
+}
+
+func TestTranslate(t *testing.T) {
+	assert.Equal(t, "фильм не найден", Translate(Russian, "movie_not_found", "movie not found"))
+	assert.Equal(t, "movie not found", Translate(English, "movie_not_found", "movie not found"))
+	// Для кода без перевода остаётся английское сообщение
+	assert.Equal(t, "unknown", Translate(Russian, "no_such_code", "unknown"))
+	assert.Equal(t, "требуется роль admin", Translate(Russian, "role_required", "role %s required", "admin"))
+	assert.Equal(t, "role admin required", Translate(English, "role_required", "role %s required", "admin"))
+}
+
+func TestErrorJSON(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+	c.Request.Header.Set("Accept-Language", "ru")
+
+	body := ErrorJSON(c, "token_revoked", "token has been revoked")
+
+	assert.Equal(t, gin.H{"error": "токен отозван", "code": "token_revoked"}, body)
+	assert.Equal(t, Russian, w.Header().Get("Content-Language"))
+}
@@ -0,0 +1,191 @@
+// Package idempotency реализует заголовок Idempotency-Key: повтор запроса с тем же ключом
+// возвращает сохранённый ответ вместо повторного создания ресурса
+package idempotency
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"cinematique/internal/apperror"
+	"cinematique/internal/auth"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	// HeaderName — заголовок с ключом идемпотентности, который выбирает клиент
+	HeaderName = "Idempotency-Key"
+	// ReplayedHeader выставляется в ответах, взятых из хранилища
+	ReplayedHeader = "Idempotency-Replayed"
+	// maxKeyLength ограничивает длину ключа, чтобы он не раздувал ключи хранилища
+	maxKeyLength = 255
+	// pendingTTL — сколько ключ считается занятым выполняющимся запросом. Если процесс
+	// упадёт посреди запроса, ключ освободится сам, а не через полный TTL
+	pendingTTL = time.Minute
+)
+
+var requestsTotal = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "idempotency_requests_total",
+		Help: "Total number of requests with an Idempotency-Key by outcome (new, replayed, in_progress, mismatch, store_error).",
+	},
+	[]string{"result"},
+)
+
+func init() {
+	prometheus.MustRegister(requestsTotal)
+}
+
+// Guard применяет ключи идемпотентности к маршрутам. Нулевой *Guard пропускает запросы без проверки
+type Guard struct {
+	store Store
+	ttl   time.Duration // сколько хранится ответ на запрос
+}
+
+// New создаёт Guard, хранящий ответы в store на ttl
+func New(store Store, ttl time.Duration) *Guard {
+	return &Guard{store: store, ttl: ttl}
+}
+
+// Middleware возвращает middleware для маршрута. Запросы без заголовка проходят как обычно.
+// Ключ действует в пределах пользователя (для анонимных запросов — IP клиента) и маршрута,
+// повтор с тем же ключом, но другим телом или параметрами отклоняется.
+// Сохраняются только успешные ответы: после ошибки запрос можно повторить с тем же ключом
+func (g *Guard) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(HeaderName)
+		if g == nil || g.store == nil || key == "" {
+			c.Next()
+			return
+		}
+		if len(key) > maxKeyLength {
+			c.Error(apperror.Validation("idempotency_key_too_long", "Idempotency-Key must not exceed 255 characters"))
+			c.Abort()
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.Error(apperror.Validation("invalid_body", "failed to read request body"))
+			c.Abort()
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		// Ответ сохраняется и после обрыва соединения клиентом — именно тогда он и повторит запрос
+		ctx := context.WithoutCancel(c.Request.Context())
+		storageKey := "idempotency:" + scope(c) + ":" + c.Request.Method + ":" + c.FullPath() + ":" + key
+		hash := requestHash(c.Request.Method, c.Request.URL.RequestURI(), body)
+
+		existing, reserved, err := g.store.Reserve(ctx, storageKey, Record{RequestHash: hash}, pendingTTL)
+		if err != nil {
+			// Недоступность хранилища не должна блокировать создание ресурсов
+			log.Printf("Idempotency store unavailable, processing request without key: %v", err)
+			requestsTotal.WithLabelValues("store_error").Inc()
+			c.Next()
+			return
+		}
+		if !reserved {
+			switch {
+			case existing.RequestHash != hash:
+				requestsTotal.WithLabelValues("mismatch").Inc()
+				c.Error(apperror.Validation("idempotency_key_reused", "Idempotency-Key was already used with a different request"))
+				c.Abort()
+			case !existing.Completed:
+				requestsTotal.WithLabelValues("in_progress").Inc()
+				c.Header("Retry-After", "1")
+				c.Error(apperror.Conflict("idempotency_request_in_progress", "a request with this Idempotency-Key is still being processed"))
+				c.Abort()
+			default:
+				requestsTotal.WithLabelValues("replayed").Inc()
+				replay(c, existing)
+			}
+			return
+		}
+		requestsTotal.WithLabelValues("new").Inc()
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+		c.Next()
+		c.Writer = recorder.ResponseWriter
+
+		status := c.Writer.Status()
+		if len(c.Errors) > 0 || status < 200 || status >= 300 {
+			if err := g.store.Release(ctx, storageKey); err != nil {
+				log.Printf("Failed to release idempotency key: %v", err)
+			}
+			return
+		}
+		record := Record{
+			RequestHash: hash,
+			Completed:   true,
+			Status:      status,
+			ContentType: c.Writer.Header().Get("Content-Type"),
+			Body:        recorder.body.Bytes(),
+		}
+		if err := g.store.Complete(ctx, storageKey, record, g.ttl); err != nil {
+			log.Printf("Failed to store idempotent response: %v", err)
+		}
+	}
+}
+
+// scope отделяет ключи разных клиентов: один и тот же ключ у разных пользователей не пересекается
+func scope(c *gin.Context) string {
+	if user, ok := auth.CurrentUser(c); ok && user.UserID != "" {
+		return "user:" + user.UserID
+	}
+	return "ip:" + c.ClientIP()
+}
+
+// requestHash определяет, тот же ли запрос пришёл повторно
+func requestHash(method, uri string, body []byte) string {
+	h := sha256.New()
+	io.WriteString(h, method)
+	h.Write([]byte{0})
+	io.WriteString(h, uri)
+	h.Write([]byte{0})
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// replay отдаёт сохранённый ответ и прерывает цепочку обработчиков
+func replay(c *gin.Context, record Record) {
+	c.Header(ReplayedHeader, "true")
+	if len(record.Body) == 0 {
+		c.Status(record.Status)
+	} else {
+		c.Data(record.Status, record.ContentType, record.Body)
+	}
+	c.Abort()
+}
+
+// responseRecorder копирует тело ответа, чтобы сохранить его после выполнения запроса
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *responseRecorder) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *responseRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// Unwrap позволяет добраться до исходного http.ResponseWriter
+func (w *responseRecorder) Unwrap() http.ResponseWriter {
+	if u, ok := w.ResponseWriter.(interface{ Unwrap() http.ResponseWriter }); ok {
+		return u.Unwrap()
+	}
+	return w.ResponseWriter
+}
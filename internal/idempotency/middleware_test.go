@@ -0,0 +1,138 @@
+package idempotency
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"cinematique/internal/apperror"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// failingStore имитирует недоступное хранилище
+type failingStore struct{}
+
+func (failingStore) Reserve(context.Context, string, Record, time.Duration) (Record, bool, error) {
+	return Record{}, false, errors.New("redis down")
+}
+func (failingStore) Complete(context.Context, string, Record, time.Duration) error { return nil }
+func (failingStore) Release(context.Context, string) error                         { return nil }
+
+// newRouter возвращает роутер, чей обработчик создаёт ресурс с новым ID на каждый вызов
+func newRouter(guard *Guard, status int) (*gin.Engine, *int) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(apperror.Middleware())
+	calls := 0
+	r.POST("/movies", guard.Middleware(), func(c *gin.Context) {
+		calls++
+		if status >= 400 {
+			c.Error(apperror.Unavailable("down", "try later"))
+			return
+		}
+		c.JSON(status, gin.H{"id": calls})
+	})
+	return r, &calls
+}
+
+func post(r http.Handler, key, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/movies", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if key != "" {
+		req.Header.Set(HeaderName, key)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestMiddleware_ReplaysCompletedRequest(t *testing.T) {
+	r, calls := newRouter(New(NewMemoryStore(), time.Hour), http.StatusCreated)
+
+	first := post(r, "abc", `{"title":"Heat"}`)
+	assert.Equal(t, http.StatusCreated, first.Code)
+	assert.Empty(t, first.Header().Get(ReplayedHeader))
+
+	second := post(r, "abc", `{"title":"Heat"}`)
+	assert.Equal(t, http.StatusCreated, second.Code)
+	assert.Equal(t, "true", second.Header().Get(ReplayedHeader))
+	assert.Equal(t, first.Body.String(), second.Body.String())
+	assert.Equal(t, "application/json; charset=utf-8", second.Header().Get("Content-Type"))
+	assert.Equal(t, 1, *calls)
+
+	// Другой ключ — новый ресурс
+	third := post(r, "def", `{"title":"Heat"}`)
+	assert.JSONEq(t, `{"id":2}`, third.Body.String())
+}
+
+func TestMiddleware_WithoutKeyPassesThrough(t *testing.T) {
+	r, calls := newRouter(New(NewMemoryStore(), time.Hour), http.StatusCreated)
+
+	post(r, "", `{}`)
+	post(r, "", `{}`)
+	assert.Equal(t, 2, *calls)
+}
+
+func TestMiddleware_NilGuardPassesThrough(t *testing.T) {
+	var guard *Guard
+	r, calls := newRouter(guard, http.StatusCreated)
+
+	post(r, "abc", `{}`)
+	post(r, "abc", `{}`)
+	assert.Equal(t, 2, *calls)
+}
+
+func TestMiddleware_RejectsKeyReuseWithDifferentBody(t *testing.T) {
+	r, calls := newRouter(New(NewMemoryStore(), time.Hour), http.StatusCreated)
+
+	post(r, "abc", `{"title":"Heat"}`)
+	w := post(r, "abc", `{"title":"Alien"}`)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "idempotency_key_reused")
+	assert.Equal(t, 1, *calls)
+}
+
+func TestMiddleware_ConflictWhileInProgress(t *testing.T) {
+	store := NewMemoryStore()
+	r, calls := newRouter(New(store, time.Hour), http.StatusCreated)
+	// Первый запрос занял ключ, но ещё не завершился
+	req := httptest.NewRequest(http.MethodPost, "/movies", strings.NewReader(`{}`))
+	store.Reserve(context.Background(), "idempotency:ip:192.0.2.1:POST:/movies:abc", Record{RequestHash: requestHash(http.MethodPost, "/movies", []byte(`{}`))}, time.Minute)
+	req.Header.Set(HeaderName, "abc")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Equal(t, "1", w.Header().Get("Retry-After"))
+	assert.Equal(t, 0, *calls)
+}
+
+func TestMiddleware_FailedRequestCanBeRetried(t *testing.T) {
+	r, calls := newRouter(New(NewMemoryStore(), time.Hour), http.StatusServiceUnavailable)
+
+	assert.Equal(t, http.StatusServiceUnavailable, post(r, "abc", `{}`).Code)
+	assert.Equal(t, http.StatusServiceUnavailable, post(r, "abc", `{}`).Code)
+	assert.Equal(t, 2, *calls)
+}
+
+func TestMiddleware_RejectsOverlongKey(t *testing.T) {
+	r, calls := newRouter(New(NewMemoryStore(), time.Hour), http.StatusCreated)
+
+	w := post(r, strings.Repeat("k", maxKeyLength+1), `{}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, 0, *calls)
+}
+
+func TestMiddleware_StoreUnavailableFailsOpen(t *testing.T) {
+	r, calls := newRouter(New(failingStore{}, time.Hour), http.StatusCreated)
+
+	assert.Equal(t, http.StatusCreated, post(r, "abc", `{}`).Code)
+	assert.Equal(t, 1, *calls)
+}
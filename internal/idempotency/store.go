@@ -0,0 +1,140 @@
+package idempotency
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Record — состояние запроса с данным ключом идемпотентности
+type Record struct {
+	RequestHash string `json:"request_hash"` // sha256 метода, URI и тела запроса
+	Completed   bool   `json:"completed"`    // false, пока первый запрос ещё выполняется
+	Status      int    `json:"status,omitempty"`
+	ContentType string `json:"content_type,omitempty"`
+	Body        []byte `json:"body,omitempty"`
+}
+
+// Store хранит записи по ключам идемпотентности
+type Store interface {
+	// Reserve атомарно занимает ключ под запрос. Если ключ уже занят,
+	// возвращает существующую запись и reserved == false
+	Reserve(ctx context.Context, key string, record Record, ttl time.Duration) (existing Record, reserved bool, err error)
+	// Complete сохраняет ответ на запрос
+	Complete(ctx context.Context, key string, record Record, ttl time.Duration) error
+	// Release освобождает ключ, чтобы запрос можно было повторить
+	Release(ctx context.Context, key string) error
+}
+
+// RedisClient — команды Redis, нужные хранилищу
+type RedisClient interface {
+	SetNX(ctx context.Context, key string, value interface{}, expiration time.Duration) *redis.BoolCmd
+	Set(ctx context.Context, key string, value interface{}, expiration time.Duration) *redis.StatusCmd
+	Get(ctx context.Context, key string) *redis.StringCmd
+	Del(ctx context.Context, keys ...string) *redis.IntCmd
+}
+
+// RedisStore хранит записи в Redis, общем для всех экземпляров сервиса
+type RedisStore struct {
+	client RedisClient
+}
+
+// NewRedisStore создаёт хранилище ключей идемпотентности в Redis
+func NewRedisStore(client RedisClient) *RedisStore {
+	return &RedisStore{client: client}
+}
+
+// Reserve занимает ключ через SETNX
+func (s *RedisStore) Reserve(ctx context.Context, key string, record Record, ttl time.Duration) (Record, bool, error) {
+	value, err := json.Marshal(record)
+	if err != nil {
+		return Record{}, false, err
+	}
+	// Ключ может истечь между SETNX и GET, тогда пробуем занять его ещё раз
+	for attempt := 0; attempt < 2; attempt++ {
+		reserved, err := s.client.SetNX(ctx, key, value, ttl).Result()
+		if err != nil {
+			return Record{}, false, fmt.Errorf("reserving idempotency key: %w", err)
+		}
+		if reserved {
+			return Record{}, true, nil
+		}
+
+		stored, err := s.client.Get(ctx, key).Bytes()
+		if errors.Is(err, redis.Nil) {
+			continue
+		}
+		if err != nil {
+			return Record{}, false, fmt.Errorf("reading idempotency key: %w", err)
+		}
+		var existing Record
+		if err := json.Unmarshal(stored, &existing); err != nil {
+			return Record{}, false, fmt.Errorf("decoding idempotency record: %w", err)
+		}
+		return existing, false, nil
+	}
+	return Record{}, false, errors.New("idempotency key expired while reserving")
+}
+
+// Complete перезаписывает запись ответом
+func (s *RedisStore) Complete(ctx context.Context, key string, record Record, ttl time.Duration) error {
+	value, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	return s.client.Set(ctx, key, value, ttl).Err()
+}
+
+// Release удаляет запись
+func (s *RedisStore) Release(ctx context.Context, key string) error {
+	return s.client.Del(ctx, key).Err()
+}
+
+// MemoryStore хранит записи в памяти процесса. Подходит для тестов и единственного экземпляра
+type MemoryStore struct {
+	mu      sync.Mutex
+	records map[string]memoryRecord
+	now     func() time.Time
+}
+
+type memoryRecord struct {
+	record  Record
+	expires time.Time
+}
+
+// NewMemoryStore создаёт хранилище в памяти
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{records: make(map[string]memoryRecord), now: time.Now}
+}
+
+// Reserve занимает ключ, если он свободен или истёк
+func (s *MemoryStore) Reserve(_ context.Context, key string, record Record, ttl time.Duration) (Record, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if existing, ok := s.records[key]; ok && s.now().Before(existing.expires) {
+		return existing.record, false, nil
+	}
+	s.records[key] = memoryRecord{record: record, expires: s.now().Add(ttl)}
+	return Record{}, true, nil
+}
+
+// Complete сохраняет ответ
+func (s *MemoryStore) Complete(_ context.Context, key string, record Record, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.records[key] = memoryRecord{record: record, expires: s.now().Add(ttl)}
+	return nil
+}
+
+// Release удаляет запись
+func (s *MemoryStore) Release(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.records, key)
+	return nil
+}
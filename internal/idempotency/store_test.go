@@ -0,0 +1,89 @@
+package idempotency
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockRedisClient мок для Redis клиента
+type MockRedisClient struct {
+	mock.Mock
+}
+
+func (m *MockRedisClient) SetNX(ctx context.Context, key string, value interface{}, expiration time.Duration) *redis.BoolCmd {
+	args := m.Called(ctx, key, value, expiration)
+	return args.Get(0).(*redis.BoolCmd)
+}
+
+func (m *MockRedisClient) Set(ctx context.Context, key string, value interface{}, expiration time.Duration) *redis.StatusCmd {
+	args := m.Called(ctx, key, value, expiration)
+	return args.Get(0).(*redis.StatusCmd)
+}
+
+func (m *MockRedisClient) Get(ctx context.Context, key string) *redis.StringCmd {
+	args := m.Called(ctx, key)
+	return args.Get(0).(*redis.StringCmd)
+}
+
+func (m *MockRedisClient) Del(ctx context.Context, keys ...string) *redis.IntCmd {
+	args := m.Called(ctx, keys)
+	return args.Get(0).(*redis.IntCmd)
+}
+
+func TestRedisStore_Reserve(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("free key is reserved", func(t *testing.T) {
+		client := new(MockRedisClient)
+		setCmd := redis.NewBoolCmd(ctx)
+		setCmd.SetVal(true)
+		client.On("SetNX", ctx, "k", mock.Anything, time.Minute).Return(setCmd).Once()
+
+		_, reserved, err := NewRedisStore(client).Reserve(ctx, "k", Record{RequestHash: "h"}, time.Minute)
+		require.NoError(t, err)
+		assert.True(t, reserved)
+		client.AssertExpectations(t)
+	})
+
+	t.Run("taken key returns stored record", func(t *testing.T) {
+		client := new(MockRedisClient)
+		setCmd := redis.NewBoolCmd(ctx)
+		setCmd.SetVal(false)
+		client.On("SetNX", ctx, "k", mock.Anything, time.Minute).Return(setCmd).Once()
+		stored, _ := json.Marshal(Record{RequestHash: "h", Completed: true, Status: 201, Body: []byte(`{"id":1}`)})
+		getCmd := redis.NewStringCmd(ctx)
+		getCmd.SetVal(string(stored))
+		client.On("Get", ctx, "k").Return(getCmd).Once()
+
+		existing, reserved, err := NewRedisStore(client).Reserve(ctx, "k", Record{RequestHash: "h"}, time.Minute)
+		require.NoError(t, err)
+		assert.False(t, reserved)
+		assert.Equal(t, Record{RequestHash: "h", Completed: true, Status: 201, Body: []byte(`{"id":1}`)}, existing)
+		client.AssertExpectations(t)
+	})
+
+	t.Run("key expiring between SETNX and GET is reserved on retry", func(t *testing.T) {
+		client := new(MockRedisClient)
+		taken := redis.NewBoolCmd(ctx)
+		taken.SetVal(false)
+		free := redis.NewBoolCmd(ctx)
+		free.SetVal(true)
+		client.On("SetNX", ctx, "k", mock.Anything, time.Minute).Return(taken).Once()
+		client.On("SetNX", ctx, "k", mock.Anything, time.Minute).Return(free).Once()
+		getCmd := redis.NewStringCmd(ctx)
+		getCmd.SetErr(redis.Nil)
+		client.On("Get", ctx, "k").Return(getCmd).Once()
+
+		_, reserved, err := NewRedisStore(client).Reserve(ctx, "k", Record{RequestHash: "h"}, time.Minute)
+		require.NoError(t, err)
+		assert.True(t, reserved)
+		client.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,205 @@
+// Package tmdb — клиент The Movie Database API v3 для импорта метаданных фильмов
+package tmdb
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"cinematique/internal/apperror"
+	"cinematique/internal/domain"
+	"cinematique/internal/isocode"
+)
+
+// DefaultBaseURL — адрес TMDB API v3
+const DefaultBaseURL = "https://api.themoviedb.org/3"
+
+// DefaultMaxCast — сколько актёров из начала титров импортируется вместе с фильмом
+const DefaultMaxCast = 10
+
+// dateLayout — формат дат в ответах TMDB
+const dateLayout = "2006-01-02"
+
+var (
+	// ErrNotFound возвращается, если в TMDB нет фильма с таким IMDb ID
+	ErrNotFound = apperror.NotFound("external_movie_not_found", "movie not found in external catalog")
+	// ErrUnavailable возвращается при сетевых ошибках и ошибочных ответах TMDB
+	ErrUnavailable = apperror.Unavailable("external_source_unavailable", "external movie catalog is unavailable")
+)
+
+// Client — клиент TMDB API. Аутентификация — ключом API v3 в параметре api_key
+type Client struct {
+	baseURL    string
+	apiKey     string
+	maxCast    int
+	httpClient *http.Client
+}
+
+// NewClient создаёт клиент TMDB; пустой baseURL означает DefaultBaseURL
+func NewClient(baseURL, apiKey string) *Client {
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+	return &Client{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		apiKey:  apiKey,
+		maxCast: DefaultMaxCast,
+		httpClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+	}
+}
+
+// WithMaxCast ограничивает число импортируемых актёров
+func (c *Client) WithMaxCast(maxCast int) *Client {
+	c.maxCast = maxCast
+	return c
+}
+
+type findResponse struct {
+	MovieResults []struct {
+		ID int `json:"id"`
+	} `json:"movie_results"`
+}
+
+type movieResponse struct {
+	Title            string   `json:"title"`
+	Overview         string   `json:"overview"`
+	ReleaseDate      string   `json:"release_date"`
+	VoteAverage      float64  `json:"vote_average"`
+	OriginalLanguage string   `json:"original_language"`
+	OriginCountry    []string `json:"origin_country"`
+	Runtime          int      `json:"runtime"` // в минутах; 0, если неизвестна
+}
+
+type creditsResponse struct {
+	Cast []struct {
+		ID    int `json:"id"`
+		Order int `json:"order"`
+	} `json:"cast"`
+}
+
+type personResponse struct {
+	Name     string `json:"name"`
+	Gender   int    `json:"gender"`
+	Birthday string `json:"birthday"`
+}
+
+// FindByIMDbID находит фильм по IMDb ID (tt0133093) и возвращает его вместе
+// с первыми maxCast актёрами из титров
+func (c *Client) FindByIMDbID(ctx context.Context, imdbID string) (domain.ExternalMovie, error) {
+	var found findResponse
+	if err := c.get(ctx, "/find/"+url.PathEscape(imdbID), url.Values{"external_source": {"imdb_id"}}, &found); err != nil {
+		return domain.ExternalMovie{}, err
+	}
+	if len(found.MovieResults) == 0 {
+		return domain.ExternalMovie{}, ErrNotFound
+	}
+	tmdbID := found.MovieResults[0].ID
+
+	var details movieResponse
+	if err := c.get(ctx, fmt.Sprintf("/movie/%d", tmdbID), nil, &details); err != nil {
+		return domain.ExternalMovie{}, err
+	}
+	movie := domain.Movie{
+		Title:       details.Title,
+		Description: details.Overview,
+		Rating:      details.VoteAverage,
+	}
+	if releaseDate, err := time.Parse(dateLayout, details.ReleaseDate); err == nil {
+		movie.ReleaseDate = &releaseDate
+		movie.ReleaseYear = releaseDate.Year()
+	}
+	// TMDB использует и собственные коды (например, xx — «без языка»), такие не сохраняем
+	if language := isocode.NormalizeLanguage(details.OriginalLanguage); isocode.ValidLanguage(language) {
+		movie.OriginalLanguage = language
+	}
+	if len(details.OriginCountry) > 0 {
+		if country := isocode.NormalizeCountry(details.OriginCountry[0]); isocode.ValidCountry(country) {
+			movie.Country = country
+		}
+	}
+	if details.Runtime > 0 && details.Runtime < 1000 {
+		movie.RuntimeMinutes = details.Runtime
+	}
+
+	var credits creditsResponse
+	if err := c.get(ctx, fmt.Sprintf("/movie/%d/credits", tmdbID), nil, &credits); err != nil {
+		return domain.ExternalMovie{}, err
+	}
+	sort.SliceStable(credits.Cast, func(i, j int) bool { return credits.Cast[i].Order < credits.Cast[j].Order })
+	if len(credits.Cast) > c.maxCast {
+		credits.Cast = credits.Cast[:c.maxCast]
+	}
+
+	// Дата рождения и пол есть только в профиле актёра
+	cast := make([]domain.Actor, 0, len(credits.Cast))
+	for _, member := range credits.Cast {
+		var person personResponse
+		if err := c.get(ctx, fmt.Sprintf("/person/%d", member.ID), nil, &person); err != nil {
+			return domain.ExternalMovie{}, err
+		}
+		actor := domain.Actor{Name: person.Name, Gender: gender(person.Gender)}
+		if birthDate, err := time.Parse(dateLayout, person.Birthday); err == nil {
+			actor.BirthDate = birthDate
+		}
+		cast = append(cast, actor)
+	}
+
+	return domain.ExternalMovie{Movie: movie, Cast: cast}, nil
+}
+
+// gender переводит код пола TMDB (0 — не указан, 1 — женский, 2 — мужской, 3 — небинарный)
+// в значения каталога
+func gender(code int) string {
+	switch code {
+	case 1:
+		return "female"
+	case 2:
+		return "male"
+	default:
+		return "other"
+	}
+}
+
+// get выполняет GET-запрос к API и декодирует JSON-ответ в out
+func (c *Client) get(ctx context.Context, path string, query url.Values, out interface{}) error {
+	if query == nil {
+		query = url.Values{}
+	}
+	query.Set("api_key", c.apiKey)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+path+"?"+query.Encode(), nil)
+	if err != nil {
+		return fmt.Errorf("building TMDB request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		// url.Error содержит полный адрес запроса вместе с api_key
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return fmt.Errorf("%w: %s: %v", ErrUnavailable, path, err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return ErrNotFound
+	case resp.StatusCode != http.StatusOK:
+		return fmt.Errorf("%w: %s: status %d", ErrUnavailable, path, resp.StatusCode)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("%w: %s: decoding response: %v", ErrUnavailable, path, err)
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package tmdb
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"cinematique/internal/apperror"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestServer отдаёт заранее заданные ответы по пути запроса и проверяет ключ API
+func newTestServer(t *testing.T, responses map[string]string) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("api_key") != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		body, ok := responses[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestClient_FindByIMDbID(t *testing.T) {
+	server := newTestServer(t, map[string]string{
+		"/find/tt0133093":    `{"movie_results":[{"id":603}]}`,
+		"/movie/603":         `{"title":"The Matrix","overview":"A hacker learns the truth.","release_date":"1999-03-30","vote_average":8.2,"original_language":"en","origin_country":["US","AU"],"runtime":136}`,
+		"/movie/603/credits": `{"cast":[{"id":2,"order":1},{"id":1,"order":0},{"id":3,"order":2}]}`,
+		"/person/1":          `{"name":"Keanu Reeves","gender":2,"birthday":"1964-09-02"}`,
+		"/person/2":          `{"name":"Carrie-Anne Moss","gender":1,"birthday":"1967-08-21"}`,
+	})
+
+	movie, err := NewClient(server.URL, "secret").WithMaxCast(2).FindByIMDbID(context.Background(), "tt0133093")
+	require.NoError(t, err)
+
+	assert.Equal(t, "The Matrix", movie.Movie.Title)
+	assert.Equal(t, "A hacker learns the truth.", movie.Movie.Description)
+	assert.Equal(t, 1999, movie.Movie.ReleaseYear)
+	assert.Equal(t, 8.2, movie.Movie.Rating)
+	require.NotNil(t, movie.Movie.ReleaseDate)
+	assert.Equal(t, time.Date(1999, 3, 30, 0, 0, 0, 0, time.UTC), *movie.Movie.ReleaseDate)
+	assert.Equal(t, "en", movie.Movie.OriginalLanguage)
+	assert.Equal(t, "US", movie.Movie.Country)
+	assert.Equal(t, 136, movie.Movie.RuntimeMinutes)
+
+	// Актёры идут в порядке титров, лишние отбрасываются
+	require.Len(t, movie.Cast, 2)
+	assert.Equal(t, "Keanu Reeves", movie.Cast[0].Name)
+	assert.Equal(t, "male", movie.Cast[0].Gender)
+	assert.Equal(t, time.Date(1964, 9, 2, 0, 0, 0, 0, time.UTC), movie.Cast[0].BirthDate)
+	assert.Equal(t, "Carrie-Anne Moss", movie.Cast[1].Name)
+	assert.Equal(t, "female", movie.Cast[1].Gender)
+}
+
+func TestClient_FindByIMDbID_NotFound(t *testing.T) {
+	server := newTestServer(t, map[string]string{
+		"/find/tt0000000": `{"movie_results":[]}`,
+	})
+
+	_, err := NewClient(server.URL, "secret").FindByIMDbID(context.Background(), "tt0000000")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestClient_FindByIMDbID_Unavailable(t *testing.T) {
+	server := newTestServer(t, nil)
+
+	_, err := NewClient(server.URL, "wrong").FindByIMDbID(context.Background(), "tt0133093")
+	assert.ErrorIs(t, err, ErrUnavailable)
+	assert.Equal(t, apperror.KindUnavailable, apperror.KindOf(err))
+	assert.NotContains(t, err.Error(), "wrong")
+}
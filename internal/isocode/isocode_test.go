@@ -0,0 +1,27 @@
+package isocode
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLanguages(t *testing.T) {
+	assert.Len(t, languages, 183)
+	assert.True(t, ValidLanguage("en"))
+	assert.True(t, ValidLanguage(NormalizeLanguage(" RU ")))
+	assert.False(t, ValidLanguage("EN"), "код должен быть нормализован")
+	assert.False(t, ValidLanguage("eng"))
+	assert.False(t, ValidLanguage("xx"))
+	assert.False(t, ValidLanguage(""))
+}
+
+func TestCountries(t *testing.T) {
+	assert.Len(t, countries, 249+5)
+	assert.True(t, ValidCountry("US"))
+	assert.True(t, ValidCountry(NormalizeCountry("fr")))
+	assert.True(t, ValidCountry("SU"), "фильмы, снятые в СССР")
+	assert.False(t, ValidCountry("us"))
+	assert.False(t, ValidCountry("USA"))
+	assert.False(t, ValidCountry("ZZ"))
+}
@@ -0,0 +1,122 @@
+// Package itest поднимает PostgreSQL и Kafka в одноразовых Docker-контейнерах для
+// интеграционных тестов. Тесты, которые им пользуются, собираются только с тегом
+// integration:
+//
+//	go test -tags integration ./internal/repository/... ./internal/kafka/...
+//
+// Без Docker тесты пропускаются. Вместо контейнера можно указать уже запущенный
+// сервис через INTEGRATION_DATABASE_DSN и INTEGRATION_KAFKA_BROKER
+package itest
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net"
+	"os/exec"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// startupTimeout ограничивает ожидание готовности контейнера
+const startupTimeout = 2 * time.Minute
+
+// container — запущенный контейнер и порт сервиса на localhost
+type container struct {
+	id   string
+	host string
+}
+
+// dockerAvailable пропускает тест, если Docker недоступен
+func dockerAvailable(tb testing.TB) {
+	tb.Helper()
+	if _, err := exec.LookPath("docker"); err != nil {
+		tb.Skip("docker is not installed")
+	}
+	if err := exec.Command("docker", "info").Run(); err != nil {
+		tb.Skip("docker daemon is not available")
+	}
+}
+
+// runContainer запускает образ в фоне и удаляет контейнер по завершении теста.
+// hostPort 0 означает случайный порт, выбранный Docker
+func runContainer(tb testing.TB, image string, containerPort, hostPort int, env map[string]string) container {
+	tb.Helper()
+	dockerAvailable(tb)
+
+	publish := fmt.Sprintf("127.0.0.1::%d", containerPort)
+	if hostPort != 0 {
+		publish = fmt.Sprintf("127.0.0.1:%d:%d", hostPort, containerPort)
+	}
+	args := []string{"run", "-d", "--rm", "-p", publish}
+	for key, value := range env {
+		args = append(args, "-e", key+"="+value)
+	}
+	args = append(args, image)
+
+	id, err := docker(args...)
+	if err != nil {
+		tb.Fatalf("starting %s: %v", image, err)
+	}
+	tb.Cleanup(func() {
+		if _, err := docker("rm", "-f", id); err != nil {
+			tb.Logf("removing container %s: %v", id, err)
+		}
+	})
+
+	binding, err := docker("port", id, strconv.Itoa(containerPort)+"/tcp")
+	if err != nil {
+		tb.Fatalf("resolving port of %s: %v", image, err)
+	}
+	// docker port может вернуть несколько привязок (IPv4 и IPv6), берём первую
+	binding, _, _ = strings.Cut(binding, "\n")
+	return container{id: id, host: binding}
+}
+
+// docker выполняет команду docker и возвращает её вывод без завершающего перевода строки
+func docker(args ...string) (string, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command("docker", args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("docker %s: %w: %s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+// freePort возвращает свободный порт на localhost. Нужен сервисам, которые должны
+// заранее знать внешний адрес, под которым их видят клиенты (Kafka)
+func freePort(tb testing.TB) int {
+	tb.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		tb.Fatalf("finding a free port: %v", err)
+	}
+	defer listener.Close()
+	return listener.Addr().(*net.TCPAddr).Port
+}
+
+// waitFor повторяет check, пока он не вернёт nil или не истечёт startupTimeout
+func waitFor(tb testing.TB, what string, check func(ctx context.Context) error) {
+	tb.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), startupTimeout)
+	defer cancel()
+
+	var lastErr error
+	for {
+		attemptCtx, attemptCancel := context.WithTimeout(ctx, 5*time.Second)
+		lastErr = check(attemptCtx)
+		attemptCancel()
+		if lastErr == nil {
+			return
+		}
+		select {
+		case <-ctx.Done():
+			tb.Fatalf("%s is not ready after %s: %v", what, startupTimeout, lastErr)
+		case <-time.After(500 * time.Millisecond):
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package itest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"testing"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// KafkaImage — образ Kafka в режиме KRaft: в отличие от docker-compose, ему не нужен ZooKeeper
+const KafkaImage = "apache/kafka:3.7.0"
+
+// StartKafka поднимает брокер Kafka и возвращает его адрес. Если задан
+// INTEGRATION_KAFKA_BROKER, используется указанный брокер
+func StartKafka(tb testing.TB) string {
+	tb.Helper()
+
+	broker := os.Getenv("INTEGRATION_KAFKA_BROKER")
+	if broker == "" {
+		// Брокер сообщает клиентам адрес из advertised.listeners, поэтому внешний порт
+		// выбирается заранее, а не назначается Docker
+		port := freePort(tb)
+		broker = "127.0.0.1:" + strconv.Itoa(port)
+		runContainer(tb, KafkaImage, 9092, port, map[string]string{
+			"KAFKA_NODE_ID":                                  "1",
+			"KAFKA_PROCESS_ROLES":                            "broker,controller",
+			"KAFKA_LISTENERS":                                "PLAINTEXT://:9092,CONTROLLER://:9093",
+			"KAFKA_ADVERTISED_LISTENERS":                     "PLAINTEXT://" + broker,
+			"KAFKA_CONTROLLER_LISTENER_NAMES":                "CONTROLLER",
+			"KAFKA_LISTENER_SECURITY_PROTOCOL_MAP":           "CONTROLLER:PLAINTEXT,PLAINTEXT:PLAINTEXT",
+			"KAFKA_CONTROLLER_QUORUM_VOTERS":                 "1@localhost:9093",
+			"KAFKA_OFFSETS_TOPIC_REPLICATION_FACTOR":         "1",
+			"KAFKA_TRANSACTION_STATE_LOG_REPLICATION_FACTOR": "1",
+			"KAFKA_TRANSACTION_STATE_LOG_MIN_ISR":            "1",
+			"KAFKA_GROUP_INITIAL_REBALANCE_DELAY_MS":         "0",
+		})
+	}
+
+	waitFor(tb, "kafka", func(ctx context.Context) error {
+		conn, err := kafka.DialContext(ctx, "tcp", broker)
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+		_, err = conn.Brokers()
+		return err
+	})
+	return broker
+}
+
+// CreateTopic создаёт тему с одной партицией
+func CreateTopic(tb testing.TB, broker, topic string) {
+	tb.Helper()
+	waitFor(tb, "topic "+topic, func(ctx context.Context) error {
+		conn, err := kafka.DialContext(ctx, "tcp", broker)
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+		controller, err := conn.Controller()
+		if err != nil {
+			return err
+		}
+		controllerConn, err := kafka.DialContext(ctx, "tcp", fmt.Sprintf("%s:%d", controller.Host, controller.Port))
+		if err != nil {
+			return err
+		}
+		defer controllerConn.Close()
+		err = controllerConn.CreateTopics(kafka.TopicConfig{Topic: topic, NumPartitions: 1, ReplicationFactor: 1})
+		if errors.Is(err, kafka.TopicAlreadyExists) {
+			return nil
+		}
+		return err
+	})
+}
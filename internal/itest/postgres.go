@@ -0,0 +1,94 @@
+package itest
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"testing"
+
+	_ "github.com/lib/pq"
+)
+
+// PostgresImage — образ PostgreSQL для интеграционных тестов, та же версия, что в docker-compose
+const PostgresImage = "postgres:13"
+
+// StartPostgres поднимает PostgreSQL, применяет миграции из каталога migrations
+// и возвращает подключение к базе. Если задан INTEGRATION_DATABASE_DSN, контейнер
+// не запускается, а миграции применяются к указанной (пустой) базе
+func StartPostgres(tb testing.TB) *sql.DB {
+	tb.Helper()
+
+	dsn := os.Getenv("INTEGRATION_DATABASE_DSN")
+	if dsn == "" {
+		c := runContainer(tb, PostgresImage, 5432, 0, map[string]string{
+			"POSTGRES_USER":     "postgres",
+			"POSTGRES_PASSWORD": "secret",
+			"POSTGRES_DB":       "cinematique",
+		})
+		dsn = fmt.Sprintf("postgres://postgres:secret@%s/cinematique?sslmode=disable", c.host)
+	}
+
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		tb.Fatalf("opening database: %v", err)
+	}
+	tb.Cleanup(func() { db.Close() })
+
+	waitFor(tb, "postgres", db.PingContext)
+	if err := ApplyMigrations(context.Background(), db); err != nil {
+		tb.Fatal(err)
+	}
+	return db
+}
+
+// ApplyMigrations применяет миграции в том же порядке, что и docker-entrypoint-initdb.d
+// в docker-compose: сначала create.sql, затем update_NNN_*.sql по возрастанию номера
+func ApplyMigrations(ctx context.Context, db *sql.DB) error {
+	files, err := migrationFiles(migrationsDir())
+	if err != nil {
+		return err
+	}
+
+	for _, path := range files {
+		script, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("reading migration: %w", err)
+		}
+		// Без параметров lib/pq отправляет текст простым запросом, поэтому файл
+		// с несколькими выражениями выполняется целиком
+		if _, err := db.ExecContext(ctx, string(script)); err != nil {
+			return fmt.Errorf("applying migration %s: %w", filepath.Base(path), err)
+		}
+	}
+	return nil
+}
+
+// migrationFiles возвращает файлы миграций dir в порядке применения
+func migrationFiles(dir string) ([]string, error) {
+	updates, err := filepath.Glob(filepath.Join(dir, "update_*.sql"))
+	if err != nil {
+		return nil, fmt.Errorf("listing migrations: %w", err)
+	}
+	sort.Strings(updates)
+	return append([]string{filepath.Join(dir, "create.sql")}, updates...), nil
+}
+
+// migrationsDir возвращает путь к каталогу migrations в корне репозитория
+func migrationsDir() string {
+	_, file, _, _ := runtime.Caller(0)
+	return filepath.Join(filepath.Dir(file), "..", "..", "migrations")
+}
+
+// Truncate очищает таблицы между тестами, сбрасывая последовательности ID
+func Truncate(tb testing.TB, db *sql.DB, tables ...string) {
+	tb.Helper()
+	for _, table := range tables {
+		if _, err := db.Exec("TRUNCATE " + table + " RESTART IDENTITY CASCADE"); err != nil {
+			tb.Fatalf("truncating %s: %v", table, err)
+		}
+	}
+}
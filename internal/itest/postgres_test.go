@@ -0,0 +1,39 @@
+package itest
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestMigrationFiles проверяет без Docker, что каждая миграция, которую применяют
+// интеграционные тесты, есть в репозитории и подключена в docker-compose в том же порядке
+func TestMigrationFiles(t *testing.T) {
+	files, err := migrationFiles(migrationsDir())
+	require.NoError(t, err)
+	require.Equal(t, "create.sql", filepath.Base(files[0]))
+
+	var names []string
+	for i, path := range files {
+		_, err := os.Stat(path)
+		require.NoError(t, err, "migration %s is missing", filepath.Base(path))
+		names = append(names, filepath.Base(path))
+		// Номера update_NNN идут подряд: пропуск выглядит как потерянная миграция
+		if i > 0 {
+			assert.Regexp(t, fmt.Sprintf(`^update_%03d_`, i), names[i])
+		}
+	}
+
+	compose, err := os.ReadFile(filepath.Join(migrationsDir(), "..", "docker-compose.yaml"))
+	require.NoError(t, err)
+	var mounted []string
+	for _, m := range regexp.MustCompile(`\./migrations/([\w.]+\.sql):`).FindAllStringSubmatch(string(compose), -1) {
+		mounted = append(mounted, m[1])
+	}
+	assert.Equal(t, names, mounted)
+}
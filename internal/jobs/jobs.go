@@ -0,0 +1,213 @@
+package jobs
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"log"
+	"sync"
+	"time"
+
+	"cinematique/internal/apperror"
+)
+
+// Status — состояние фоновой задачи
+type Status string
+
+const (
+	StatusRunning   Status = "running"
+	StatusSucceeded Status = "succeeded"
+	StatusFailed    Status = "failed"
+)
+
+// ErrJobNotFound возвращается, если задачи с таким ID нет (или она уже удалена по сроку хранения)
+var ErrJobNotFound = apperror.NotFound("job_not_found", "job not found")
+
+// Job — снимок состояния фоновой задачи (импорт, экспорт, пересчёт и т.п.)
+type Job struct {
+	ID         string      `json:"id"`
+	Kind       string      `json:"kind"`
+	Status     Status      `json:"status"`
+	Progress   *Progress   `json:"progress,omitempty"`
+	Result     interface{} `json:"result,omitempty"`
+	Error      string      `json:"error,omitempty"`
+	CreatedAt  time.Time   `json:"created_at"`
+	FinishedAt *time.Time  `json:"finished_at,omitempty"`
+}
+
+// Done сообщает, завершилась ли задача (успешно или с ошибкой)
+func (j Job) Done() bool {
+	return j.Status == StatusSucceeded || j.Status == StatusFailed
+}
+
+// Progress — ход выполнения задачи: Done из Total шагов, Step — текущий шаг
+type Progress struct {
+	Done  int    `json:"done"`
+	Total int    `json:"total"`
+	Step  string `json:"step,omitempty"`
+}
+
+// Func — тело фоновой задачи. Контекст отменяется при остановке менеджера;
+// через него задача сообщает о ходе выполнения (ReportProgress)
+type Func func(ctx context.Context) (interface{}, error)
+
+type progressKey struct{}
+
+// ReportProgress сохраняет ход выполнения задачи, которой передан ctx. Вне задачи ничего не делает
+func ReportProgress(ctx context.Context, progress Progress) {
+	if report, ok := ctx.Value(progressKey{}).(func(Progress)); ok {
+		report(progress)
+	}
+}
+
+type entry struct {
+	job  Job
+	done chan struct{}
+}
+
+// Manager запускает фоновые задачи и хранит их состояние в памяти
+type Manager struct {
+	mu        sync.RWMutex
+	jobs      map[string]*entry
+	retention time.Duration
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewManager создаёт менеджер задач. Завершённые задачи хранятся retention, затем удаляются
+func NewManager(retention time.Duration) *Manager {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Manager{
+		jobs:      make(map[string]*entry),
+		retention: retention,
+		ctx:       ctx,
+		cancel:    cancel,
+	}
+}
+
+// Start запускает задачу в отдельной горутине и сразу возвращает её снимок
+func (m *Manager) Start(kind string, fn Func) Job {
+	m.mu.Lock()
+	e := m.addLocked(kind)
+	job := e.job
+	m.mu.Unlock()
+	m.run(e, fn)
+	return job
+}
+
+// StartExclusive запускает задачу, только если задача вида kind ещё не выполняется.
+// Иначе возвращает выполняющуюся задачу и false
+func (m *Manager) StartExclusive(kind string, fn Func) (Job, bool) {
+	m.mu.Lock()
+	for _, e := range m.jobs {
+		if e.job.Kind == kind && e.job.Status == StatusRunning {
+			m.mu.Unlock()
+			return e.job, false
+		}
+	}
+	e := m.addLocked(kind)
+	job := e.job
+	m.mu.Unlock()
+	m.run(e, fn)
+	return job, true
+}
+
+// addLocked регистрирует новую задачу вида kind. Вызывается под m.mu
+func (m *Manager) addLocked(kind string) *entry {
+	e := &entry{
+		job: Job{
+			ID:        newJobID(),
+			Kind:      kind,
+			Status:    StatusRunning,
+			CreatedAt: time.Now(),
+		},
+		done: make(chan struct{}),
+	}
+	m.pruneLocked()
+	m.jobs[e.job.ID] = e
+	return e
+}
+
+// run выполняет задачу в отдельной горутине и сохраняет её результат
+func (m *Manager) run(e *entry, fn Func) {
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+		ctx := context.WithValue(m.ctx, progressKey{}, func(progress Progress) {
+			m.mu.Lock()
+			e.job.Progress = &progress
+			m.mu.Unlock()
+		})
+		result, err := fn(ctx)
+
+		m.mu.Lock()
+		finishedAt := time.Now()
+		e.job.FinishedAt = &finishedAt
+		if err != nil {
+			log.Printf("Job %s (%s) failed: %v", e.job.ID, e.job.Kind, err)
+			e.job.Status = StatusFailed
+			e.job.Error = err.Error()
+		} else {
+			e.job.Status = StatusSucceeded
+			e.job.Result = result
+		}
+		m.mu.Unlock()
+		close(e.done)
+	}()
+}
+
+// Get возвращает текущее состояние задачи
+func (m *Manager) Get(id string) (Job, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	e, ok := m.jobs[id]
+	if !ok {
+		return Job{}, ErrJobNotFound
+	}
+	return e.job, nil
+}
+
+// Wait ждёт завершения задачи или отмены ctx и возвращает состояние задачи на этот момент
+func (m *Manager) Wait(ctx context.Context, id string) (Job, error) {
+	m.mu.RLock()
+	e, ok := m.jobs[id]
+	m.mu.RUnlock()
+	if !ok {
+		return Job{}, ErrJobNotFound
+	}
+
+	select {
+	case <-e.done:
+	case <-ctx.Done():
+	}
+	return m.Get(id)
+}
+
+// Close отменяет контекст выполняющихся задач и дожидается их завершения
+func (m *Manager) Close() {
+	m.cancel()
+	m.wg.Wait()
+}
+
+// pruneLocked удаляет завершённые задачи старше срока хранения. Вызывается под m.mu
+func (m *Manager) pruneLocked() {
+	if m.retention <= 0 {
+		return
+	}
+	threshold := time.Now().Add(-m.retention)
+	for id, e := range m.jobs {
+		if e.job.FinishedAt != nil && e.job.FinishedAt.Before(threshold) {
+			delete(m.jobs, id)
+		}
+	}
+}
+
+func newJobID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return time.Now().Format("20060102150405.000000000")
+	}
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,147 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestManager_WaitReturnsWhenJobFinishes(t *testing.T) {
+	m := NewManager(time.Hour)
+	defer m.Close()
+
+	release := make(chan struct{})
+	job := m.Start("import", func(ctx context.Context) (interface{}, error) {
+		<-release
+		return map[string]int{"imported": 3}, nil
+	})
+	assert.Equal(t, StatusRunning, job.Status)
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		close(release)
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	got, err := m.Wait(ctx, job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, StatusSucceeded, got.Status)
+	assert.Equal(t, map[string]int{"imported": 3}, got.Result)
+	assert.NotNil(t, got.FinishedAt)
+	assert.True(t, got.Done())
+}
+
+func TestManager_WaitTimeout(t *testing.T) {
+	m := NewManager(time.Hour)
+
+	release := make(chan struct{})
+	job := m.Start("export", func(ctx context.Context) (interface{}, error) {
+		<-release
+		return nil, nil
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	got, err := m.Wait(ctx, job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, StatusRunning, got.Status)
+	assert.False(t, got.Done())
+
+	close(release)
+	m.Close()
+}
+
+func TestManager_FailedJob(t *testing.T) {
+	m := NewManager(time.Hour)
+	defer m.Close()
+
+	job := m.Start("recalculate", func(ctx context.Context) (interface{}, error) {
+		return nil, errors.New("boom")
+	})
+
+	got, err := m.Wait(context.Background(), job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, StatusFailed, got.Status)
+	assert.Equal(t, "boom", got.Error)
+}
+
+func TestManager_NotFound(t *testing.T) {
+	m := NewManager(time.Hour)
+	defer m.Close()
+
+	_, err := m.Get("missing")
+	assert.ErrorIs(t, err, ErrJobNotFound)
+
+	_, err = m.Wait(context.Background(), "missing")
+	assert.ErrorIs(t, err, ErrJobNotFound)
+}
+
+func TestManager_PrunesFinishedJobs(t *testing.T) {
+	m := NewManager(time.Millisecond)
+	defer m.Close()
+
+	first := m.Start("import", func(ctx context.Context) (interface{}, error) { return nil, nil })
+	_, err := m.Wait(context.Background(), first.ID)
+	require.NoError(t, err)
+	time.Sleep(5 * time.Millisecond)
+
+	// Запуск новой задачи удаляет устаревшие завершённые
+	m.Start("import", func(ctx context.Context) (interface{}, error) { return nil, nil })
+	_, err = m.Get(first.ID)
+	assert.ErrorIs(t, err, ErrJobNotFound)
+}
+
+func TestManager_ReportProgress(t *testing.T) {
+	m := NewManager(time.Hour)
+	defer m.Close()
+
+	reported := make(chan struct{})
+	release := make(chan struct{})
+	job := m.Start("reindex", func(ctx context.Context) (interface{}, error) {
+		ReportProgress(ctx, Progress{Done: 1, Total: 3, Step: "idx_films_title_trgm"})
+		close(reported)
+		<-release
+		return nil, nil
+	})
+	assert.Nil(t, job.Progress)
+
+	<-reported
+	got, err := m.Get(job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, &Progress{Done: 1, Total: 3, Step: "idx_films_title_trgm"}, got.Progress)
+	close(release)
+
+	// Вне задачи отчёт о ходе выполнения ничего не делает
+	ReportProgress(context.Background(), Progress{Done: 1})
+}
+
+func TestManager_StartExclusive(t *testing.T) {
+	m := NewManager(time.Hour)
+	defer m.Close()
+
+	release := make(chan struct{})
+	first, started := m.StartExclusive("reindex", func(ctx context.Context) (interface{}, error) {
+		<-release
+		return nil, nil
+	})
+	require.True(t, started)
+
+	running, started := m.StartExclusive("reindex", func(ctx context.Context) (interface{}, error) { return nil, nil })
+	assert.False(t, started)
+	assert.Equal(t, first.ID, running.ID)
+
+	// Задачи другого вида не мешают друг другу
+	_, started = m.StartExclusive("export", func(ctx context.Context) (interface{}, error) { return nil, nil })
+	assert.True(t, started)
+
+	close(release)
+	_, err := m.Wait(context.Background(), first.ID)
+	require.NoError(t, err)
+	_, started = m.StartExclusive("reindex", func(ctx context.Context) (interface{}, error) { return nil, nil })
+	assert.True(t, started)
+}
@@ -0,0 +1,80 @@
+package kafka
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// KafkaBroadcastDroppedTotal считает события, не доставленные подписчику из-за переполненного буфера
+var KafkaBroadcastDroppedTotal = prometheus.NewCounter(prometheus.CounterOpts{Name: "kafka_broadcast_dropped_total", Help: "Total number of events dropped for slow broadcast subscribers."})
+
+func init() {
+	prometheus.MustRegister(KafkaBroadcastDroppedTotal)
+}
+
+// Broadcaster рассылает события выбранных топиков всем подписчикам внутри процесса
+// (например, SSE-клиентам). Медленный подписчик не блокирует рассылку: если его буфер
+// заполнен, событие для него отбрасывается
+type Broadcaster struct {
+	topics map[string]struct{}
+
+	mu          sync.RWMutex
+	subscribers map[chan KafkaEvent]struct{}
+}
+
+// NewBroadcaster создаёт рассыльщик для указанных топиков; без топиков рассылаются все события
+func NewBroadcaster(topics ...string) *Broadcaster {
+	b := &Broadcaster{
+		topics:      make(map[string]struct{}, len(topics)),
+		subscribers: make(map[chan KafkaEvent]struct{}),
+	}
+	for _, topic := range topics {
+		b.topics[topic] = struct{}{}
+	}
+	return b
+}
+
+// Subscribe регистрирует подписчика с буфером на bufSize событий.
+// Возвращает канал событий и функцию отписки, которая закрывает канал
+func (b *Broadcaster) Subscribe(bufSize int) (<-chan KafkaEvent, func()) {
+	ch := make(chan KafkaEvent, bufSize)
+	b.mu.Lock()
+	b.subscribers[ch] = struct{}{}
+	b.mu.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			b.mu.Lock()
+			delete(b.subscribers, ch)
+			b.mu.Unlock()
+			close(ch)
+		})
+	}
+}
+
+// Publish отправляет событие всем подписчикам, если его топик рассылается
+func (b *Broadcaster) Publish(event KafkaEvent) {
+	if len(b.topics) > 0 {
+		if _, ok := b.topics[event.Topic]; !ok {
+			return
+		}
+	}
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	for ch := range b.subscribers {
+		select {
+		case ch <- event:
+		default:
+			KafkaBroadcastDroppedTotal.Inc()
+		}
+	}
+}
+
+// Subscribers возвращает текущее число подписчиков
+func (b *Broadcaster) Subscribers() int {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return len(b.subscribers)
+}
@@ -0,0 +1,68 @@
+package kafka
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBroadcaster_PublishFiltersTopics(t *testing.T) {
+	b := NewBroadcaster("catalog-changes")
+	events, unsubscribe := b.Subscribe(4)
+	defer unsubscribe()
+
+	b.Publish(KafkaEvent{Topic: "movie-views", Value: []byte("view")})
+	b.Publish(KafkaEvent{Topic: "catalog-changes", Value: []byte("created")})
+
+	require.Len(t, events, 1)
+	assert.Equal(t, "created", string((<-events).Value))
+}
+
+func TestBroadcaster_SlowSubscriberDoesNotBlock(t *testing.T) {
+	b := NewBroadcaster()
+	slow, unsubscribeSlow := b.Subscribe(1)
+	defer unsubscribeSlow()
+	fast, unsubscribeFast := b.Subscribe(3)
+	defer unsubscribeFast()
+
+	for i := 0; i < 3; i++ {
+		b.Publish(KafkaEvent{Topic: "catalog-changes"})
+	}
+
+	assert.Len(t, slow, 1)
+	assert.Len(t, fast, 3)
+}
+
+func TestBroadcaster_Unsubscribe(t *testing.T) {
+	b := NewBroadcaster()
+	events, unsubscribe := b.Subscribe(1)
+	assert.Equal(t, 1, b.Subscribers())
+
+	unsubscribe()
+	unsubscribe() // повторный вызов безопасен
+
+	assert.Equal(t, 0, b.Subscribers())
+	_, open := <-events
+	assert.False(t, open)
+	b.Publish(KafkaEvent{Topic: "catalog-changes"})
+}
+
+func TestProducerPool_WithBroadcaster(t *testing.T) {
+	mockProducer := &MockProducerInterface{}
+	mockProducer.On("Produce", mock.Anything, "catalog-changes", []byte("movie:1"), []byte("{}")).Return(nil).Maybe()
+	mockProducer.On("Close").Return(nil)
+
+	b := NewBroadcaster("catalog-changes")
+	events, unsubscribe := b.Subscribe(1)
+	defer unsubscribe()
+
+	pool := NewProducerPool(mockProducer, 1, 5, WithBroadcaster(b))
+	require.NoError(t, pool.Produce("catalog-changes", []byte("movie:1"), []byte("{}")))
+	pool.Close()
+
+	event := <-events
+	assert.Equal(t, "movie:1", string(event.Key))
+	mockProducer.AssertExpectations(t)
+}
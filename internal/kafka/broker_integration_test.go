@@ -0,0 +1,45 @@
+//go:build integration
+
+package kafka
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"cinematique/internal/itest"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestIntegration_ProducerWritesToBroker отправляет сообщение через Producer в настоящий
+// брокер и читает его обратно: моки не проверяют настройки writer (сжатие, подтверждения)
+func TestIntegration_ProducerWritesToBroker(t *testing.T) {
+	broker := itest.StartKafka(t)
+	topic := "integration-user-events"
+	itest.CreateTopic(t, broker, topic)
+
+	cfg := NewProducerConfig(broker)
+	cfg.DLQTopic = ""
+	producer := NewProducer(cfg)
+	defer producer.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	require.NoError(t, producer.Produce(ctx, topic, []byte("neo"), []byte(`{"username":"neo"}`)))
+
+	reader := kafka.NewReader(kafka.ReaderConfig{
+		Brokers:   []string{broker},
+		Topic:     topic,
+		Partition: 0,
+		MaxWait:   100 * time.Millisecond,
+	})
+	defer reader.Close()
+
+	message, err := reader.ReadMessage(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "neo", string(message.Key))
+	assert.JSONEq(t, `{"username":"neo"}`, string(message.Value))
+}
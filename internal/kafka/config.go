@@ -42,6 +42,7 @@ type ConsumerConfig struct {
 	MaxBytes      int
 	MinBytes      int
 	MaxWait       time.Duration
+	DLQTopic      string // Топик для сообщений, которые не удалось декодировать
 }
 
 // NewConsumerConfig создает конфиг консьюмера со значениями по умолчанию.
@@ -53,5 +54,6 @@ func NewConsumerConfig(brokerAddress, groupID, topic string) ConsumerConfig {
 		MinBytes:      10e3, // 10KB
 		MaxBytes:      10e6, // 10MB
 		MaxWait:       1 * time.Second,
+		DLQTopic:      "dead-letter-queue",
 	}
 }
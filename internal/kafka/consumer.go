@@ -2,17 +2,66 @@ package kafka
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log"
+	"strconv"
+	"sync/atomic"
 	"time"
 
+	"cinematique/internal/domain"
+
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/segmentio/kafka-go"
 )
 
+// EventHandler обрабатывает декодированное событие
+type EventHandler func(ctx context.Context, event Event) error
+
+// messageWriter — минимальный интерфейс kafka.Writer, нужный для DLQ
+type messageWriter interface {
+	WriteMessages(ctx context.Context, msgs ...kafka.Message) error
+	Close() error
+}
+
+// Ledger — журнал обработанных сообщений, защищающий обработчики от повторной доставки
+type Ledger interface {
+	// Claim отмечает сообщение как обработанное; false — сообщение уже было обработано
+	Claim(ctx context.Context, msg domain.ProcessedMessage) (bool, error)
+	// Release снимает отметку, чтобы сообщение обработалось при повторной доставке
+	Release(ctx context.Context, msg domain.ProcessedMessage) error
+}
+
+// Метрики идемпотентной обработки
+var (
+	KafkaMessagesDuplicateSkippedTotal = prometheus.NewCounterVec(prometheus.CounterOpts{Name: "kafka_messages_duplicate_skipped_total", Help: "Total number of consumed Kafka messages skipped because they were already processed."}, []string{"topic"})
+	KafkaLedgerErrorsTotal             = prometheus.NewCounterVec(prometheus.CounterOpts{Name: "kafka_ledger_errors_total", Help: "Total number of processed-message ledger errors."}, []string{"topic"})
+)
+
+func init() {
+	prometheus.MustRegister(KafkaMessagesDuplicateSkippedTotal)
+	prometheus.MustRegister(KafkaLedgerErrorsTotal)
+}
+
 // Consumer wraps a kafka.Reader for consuming messages.
 type Consumer struct {
-	reader *kafka.Reader
+	reader    *kafka.Reader
+	group     string
+	dlqWriter messageWriter // Опциональный writer для DLQ
+	decoder   *Decoder
+	handler   EventHandler
+	ledger    Ledger // Опциональный журнал обработанных сообщений
+
+	running     atomic.Bool
+	fetchErrors atomic.Int32 // ошибки чтения подряд
 }
 
+// maxConsecutiveFetchErrors — после стольких ошибок чтения подряд консьюмер считается неработоспособным
+const maxConsecutiveFetchErrors = 5
+
+// ErrConsumerStopped возвращается Healthy, если цикл чтения не запущен
+var ErrConsumerStopped = errors.New("consumer is not running")
+
 // NewConsumer creates a new Kafka consumer.
 func NewConsumer(cfg ConsumerConfig) *Consumer {
 	reader := kafka.NewReader(kafka.ReaderConfig{
@@ -28,11 +77,47 @@ func NewConsumer(cfg ConsumerConfig) *Consumer {
 		SessionTimeout:    30 * time.Second,
 		RebalanceTimeout:  30 * time.Second,
 	})
-	return &Consumer{reader: reader}
+
+	consumer := &Consumer{reader: reader, group: cfg.GroupID}
+	if cfg.DLQTopic != "" {
+		consumer.dlqWriter = &kafka.Writer{
+			Addr:         kafka.TCP(cfg.BrokerAddress),
+			Topic:        cfg.DLQTopic,
+			Balancer:     &kafka.LeastBytes{},
+			RequiredAcks: kafka.RequireAll,
+		}
+	}
+	return consumer
+}
+
+// WithDecoder включает декодирование входящих событий: сообщения, которые не удалось
+// разобрать или поднять до актуальной версии, отправляются в DLQ с диагностикой,
+// остальные передаются в handler (если он задан)
+func (c *Consumer) WithDecoder(decoder *Decoder, handler EventHandler) *Consumer {
+	c.decoder = decoder
+	c.handler = handler
+	return c
+}
+
+// WithLedger включает идемпотентную обработку: перед вызовом handler сообщение
+// записывается в журнал, и повторно доставленные сообщения пропускаются
+func (c *Consumer) WithLedger(ledger Ledger) *Consumer {
+	c.ledger = ledger
+	return c
 }
 
 // ConsumeMessages consumes messages from Kafka and logs them.
 func (c *Consumer) ConsumeMessages(ctx context.Context) {
+	if !c.running.CompareAndSwap(false, true) {
+		return // цикл чтения уже запущен
+	}
+	defer c.running.Store(false)
+	defer func() {
+		// Паника при обработке останавливает только этот цикл; его перезапускает супервизор
+		if r := recover(); r != nil {
+			log.Printf("Kafka consumer for topic %s stopped after panic: %v", c.reader.Config().Topic, r)
+		}
+	}()
 	log.Printf("Starting Kafka consumer for topic: %s, groupID: %s", c.reader.Config().Topic, c.reader.Config().GroupID)
 	defer log.Printf("Stopping consumer for topic: %s", c.reader.Config().Topic)
 
@@ -46,20 +131,134 @@ func (c *Consumer) ConsumeMessages(ctx context.Context) {
 			}
 			// Библиотека сама будет пытаться переподключиться, поэтому здесь просто логируем ошибку.
 			log.Printf("Error fetching message from Kafka: %v. Library will handle reconnect.", err)
+			c.fetchErrors.Add(1)
 			continue
 		}
+		c.fetchErrors.Store(0)
 
 		log.Printf("Получено сообщение Kafka - Тема: %s, Раздел: %d, Смещение: %d, Ключ: %s, Значение: %s\n",
 			m.Topic, m.Partition, m.Offset, string(m.Key), string(m.Value))
 
+		c.processMessage(ctx, m)
+
 		if err := c.reader.CommitMessages(ctx, m); err != nil {
 			log.Printf("Ошибка при подтверждении сообщения в Kafka: %v", err)
 		}
 	}
 }
 
+// processMessage декодирует сообщение и передаёт его обработчику.
+// Недекодируемые сообщения уходят в DLQ, чтобы не блокировать чтение топика
+func (c *Consumer) processMessage(ctx context.Context, m kafka.Message) {
+	if c.decoder == nil {
+		return
+	}
+
+	event, err := c.decoder.Decode(m.Value)
+	if err != nil {
+		log.Printf("Failed to decode message (topic: %s, partition: %d, offset: %d): %v", m.Topic, m.Partition, m.Offset, err)
+		c.sendToDLQ(ctx, m, err)
+		return
+	}
+
+	if c.handler == nil {
+		return
+	}
+
+	processed := domain.ProcessedMessage{Group: c.group, Topic: m.Topic, Partition: m.Partition, Offset: m.Offset}
+	if id, ok := event.Fields["event_id"].(string); ok {
+		processed.MessageID = id
+	}
+	claimed := false
+	if c.ledger != nil {
+		fresh, err := c.ledger.Claim(ctx, processed)
+		switch {
+		case err != nil:
+			// Журнал недоступен: обрабатываем без защиты от повторов, чтобы не терять события
+			KafkaLedgerErrorsTotal.WithLabelValues(m.Topic).Inc()
+			log.Printf("Failed to claim message in ledger (topic: %s, offset: %d): %v", m.Topic, m.Offset, err)
+		case !fresh:
+			KafkaMessagesDuplicateSkippedTotal.WithLabelValues(m.Topic).Inc()
+			log.Printf("Skipping already processed %s event (topic: %s, partition: %d, offset: %d)", event.Type, m.Topic, m.Partition, m.Offset)
+			return
+		default:
+			claimed = true
+		}
+	}
+
+	if err := c.handler(ctx, event); err != nil {
+		log.Printf("Failed to handle %s event (topic: %s, offset: %d): %v", event.Type, m.Topic, m.Offset, err)
+		if claimed {
+			if err := c.ledger.Release(ctx, processed); err != nil {
+				KafkaLedgerErrorsTotal.WithLabelValues(m.Topic).Inc()
+				log.Printf("Failed to release message in ledger (topic: %s, offset: %d): %v", m.Topic, m.Offset, err)
+			}
+		}
+	}
+}
+
+// sendToDLQ отправляет исходное сообщение в DLQ, добавляя диагностику в заголовки
+func (c *Consumer) sendToDLQ(ctx context.Context, m kafka.Message, cause error) {
+	if c.dlqWriter == nil {
+		return
+	}
+
+	dlqMessage := kafka.Message{
+		Key:   m.Key,
+		Value: m.Value,
+		Headers: append(m.Headers,
+			kafka.Header{Key: "x-original-topic", Value: []byte(m.Topic)},
+			kafka.Header{Key: "x-original-partition", Value: []byte(strconv.Itoa(m.Partition))},
+			kafka.Header{Key: "x-original-offset", Value: []byte(strconv.FormatInt(m.Offset, 10))},
+			kafka.Header{Key: "x-error-reason", Value: []byte(decodeErrorReason(cause))},
+			kafka.Header{Key: "x-error", Value: []byte(cause.Error())},
+		),
+	}
+	if err := c.dlqWriter.WriteMessages(ctx, dlqMessage); err != nil {
+		log.Printf("CRITICAL: Failed to write undecodable message to DLQ: %v", err)
+	}
+}
+
+// decodeErrorReason возвращает короткий код причины для заголовка DLQ
+func decodeErrorReason(err error) string {
+	switch {
+	case errors.Is(err, ErrMalformedEvent):
+		return "malformed"
+	case errors.Is(err, ErrMissingEventType):
+		return "missing_type"
+	case errors.Is(err, ErrUnsupportedVersion):
+		return "unsupported_version"
+	case errors.Is(err, ErrUpcastFailed):
+		return "upcast_failed"
+	default:
+		return "unknown"
+	}
+}
+
+// Topic возвращает тему, которую читает консьюмер
+func (c *Consumer) Topic() string {
+	return c.reader.Config().Topic
+}
+
+// Healthy сообщает, работает ли цикл чтения и нет ли затяжных ошибок чтения
+func (c *Consumer) Healthy() error {
+	if !c.running.Load() {
+		return ErrConsumerStopped
+	}
+	if n := c.fetchErrors.Load(); n >= maxConsecutiveFetchErrors {
+		return fmt.Errorf("%d consecutive fetch errors", n)
+	}
+	return nil
+}
+
 // Close закрывает потребитель Kafka.
 func (c *Consumer) Close() error {
 	log.Printf("Closing Kafka reader for topic: %s", c.reader.Config().Topic)
-	return c.reader.Close()
+	err := c.reader.Close()
+	if c.dlqWriter != nil {
+		if dlqErr := c.dlqWriter.Close(); dlqErr != nil && err == nil {
+			err = dlqErr
+		}
+	}
+	return err
 }
@@ -81,6 +81,19 @@ func TestNewConsumer(t *testing.T) {
 	assert.NotNil(t, consumer.reader)
 }
 
+func TestConsumer_Healthy(t *testing.T) {
+	consumer := NewConsumer(NewConsumerConfig("localhost:9092", "test-group", "test-topic"))
+	defer consumer.Close()
+
+	assert.ErrorIs(t, consumer.Healthy(), ErrConsumerStopped)
+
+	consumer.running.Store(true)
+	assert.NoError(t, consumer.Healthy())
+
+	consumer.fetchErrors.Store(maxConsecutiveFetchErrors)
+	assert.EqualError(t, consumer.Healthy(), "5 consecutive fetch errors")
+}
+
 func TestConsumer_ConsumeMessages_Success(t *testing.T) {
 	mockReader := &MockReader{}
 
@@ -0,0 +1,113 @@
+package kafka
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// Ошибки декодирования событий. Сообщения с такими ошибками отправляются в DLQ
+var (
+	ErrMalformedEvent     = errors.New("malformed event")
+	ErrMissingEventType   = errors.New("event type is missing")
+	ErrUnsupportedVersion = errors.New("unsupported event schema version")
+	ErrUpcastFailed       = errors.New("event upcast failed")
+)
+
+// Event — декодированное событие, приведённое к актуальной версии схемы
+type Event struct {
+	Type    string
+	Version int
+	Fields  map[string]interface{}
+	Known   bool // false, если для типа события не зарегистрирована схема
+}
+
+// Bind раскладывает поля события в структуру; неизвестные поля игнорируются
+func (e Event) Bind(out interface{}) error {
+	raw, err := json.Marshal(e.Fields)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(raw, out)
+}
+
+// Upcaster переводит поля события из версии N в версию N+1
+type Upcaster func(fields map[string]interface{}) (map[string]interface{}, error)
+
+type eventSchema struct {
+	current   int
+	upcasters map[int]Upcaster // ключ — версия, из которой выполняется переход
+}
+
+// Decoder разбирает входящие события и приводит их к актуальной версии схемы
+// с помощью цепочки явных upcaster-функций.
+//
+// Версия берётся из поля schema_version; события без него считаются версией 1.
+type Decoder struct {
+	schemas map[string]eventSchema
+}
+
+// NewDecoder создаёт пустой декодер
+func NewDecoder() *Decoder {
+	return &Decoder{schemas: make(map[string]eventSchema)}
+}
+
+// Register регистрирует тип события с актуальной версией схемы и upcaster-ами
+// для каждой предыдущей версии (ключ — версия, из которой выполняется переход)
+func (d *Decoder) Register(eventType string, currentVersion int, upcasters map[int]Upcaster) *Decoder {
+	if upcasters == nil {
+		upcasters = map[int]Upcaster{}
+	}
+	d.schemas[eventType] = eventSchema{current: currentVersion, upcasters: upcasters}
+	return d
+}
+
+// Decode разбирает сообщение и поднимает его до актуальной версии.
+// Неизвестные поля сохраняются как есть; незарегистрированные типы возвращаются без изменений
+func (d *Decoder) Decode(data []byte) (Event, error) {
+	var fields map[string]interface{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return Event{}, fmt.Errorf("%w: %v", ErrMalformedEvent, err)
+	}
+	if fields == nil {
+		return Event{}, fmt.Errorf("%w: not a JSON object", ErrMalformedEvent)
+	}
+
+	eventType, _ := fields["type"].(string)
+	if eventType == "" {
+		return Event{}, ErrMissingEventType
+	}
+
+	version := 1
+	if raw, ok := fields["schema_version"]; ok {
+		number, ok := raw.(float64)
+		if !ok || number < 1 || number != float64(int(number)) {
+			return Event{}, fmt.Errorf("%w: invalid schema_version %v", ErrMalformedEvent, raw)
+		}
+		version = int(number)
+	}
+
+	schema, ok := d.schemas[eventType]
+	if !ok {
+		return Event{Type: eventType, Version: version, Fields: fields}, nil
+	}
+	if version > schema.current {
+		return Event{}, fmt.Errorf("%w: %s v%d (supported up to v%d)", ErrUnsupportedVersion, eventType, version, schema.current)
+	}
+
+	for version < schema.current {
+		upcast, ok := schema.upcasters[version]
+		if !ok {
+			return Event{}, fmt.Errorf("%w: no upcaster for %s v%d", ErrUpcastFailed, eventType, version)
+		}
+		upcasted, err := upcast(fields)
+		if err != nil {
+			return Event{}, fmt.Errorf("%w: %s v%d: %v", ErrUpcastFailed, eventType, version, err)
+		}
+		fields = upcasted
+		version++
+	}
+	fields["schema_version"] = version
+
+	return Event{Type: eventType, Version: version, Fields: fields, Known: true}, nil
+}
@@ -0,0 +1,223 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"cinematique/internal/domain"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestDecoder регистрирует movie_viewed с тремя версиями схемы:
+// v1 {movie_id}, v2 {movie_id, source}, v3 {film_id, source}
+func newTestDecoder() *Decoder {
+	return NewDecoder().Register("movie_viewed", 3, map[int]Upcaster{
+		1: func(fields map[string]interface{}) (map[string]interface{}, error) {
+			fields["source"] = "web"
+			return fields, nil
+		},
+		2: func(fields map[string]interface{}) (map[string]interface{}, error) {
+			id, ok := fields["movie_id"]
+			if !ok {
+				return nil, errors.New("movie_id is missing")
+			}
+			fields["film_id"] = id
+			delete(fields, "movie_id")
+			return fields, nil
+		},
+	})
+}
+
+func TestDecoder_Decode(t *testing.T) {
+	decoder := newTestDecoder()
+
+	tests := []struct {
+		name        string
+		data        string
+		wantErr     error
+		wantVersion int
+		wantFields  map[string]interface{}
+		wantKnown   bool
+	}{
+		{
+			name:        "legacy event without version is upcast from v1",
+			data:        `{"type":"movie_viewed","movie_id":7}`,
+			wantVersion: 3,
+			wantFields:  map[string]interface{}{"type": "movie_viewed", "film_id": float64(7), "source": "web", "schema_version": 3},
+			wantKnown:   true,
+		},
+		{
+			name:        "v2 event keeps its source",
+			data:        `{"type":"movie_viewed","schema_version":2,"movie_id":7,"source":"app"}`,
+			wantVersion: 3,
+			wantFields:  map[string]interface{}{"type": "movie_viewed", "film_id": float64(7), "source": "app", "schema_version": 3},
+			wantKnown:   true,
+		},
+		{
+			name:        "unknown fields are preserved",
+			data:        `{"type":"movie_viewed","schema_version":3,"film_id":7,"source":"app","experiment":"b"}`,
+			wantVersion: 3,
+			wantFields:  map[string]interface{}{"type": "movie_viewed", "film_id": float64(7), "source": "app", "experiment": "b", "schema_version": 3},
+			wantKnown:   true,
+		},
+		{
+			name:        "unregistered type passes through",
+			data:        `{"type":"movie_rated","score":5}`,
+			wantVersion: 1,
+			wantFields:  map[string]interface{}{"type": "movie_rated", "score": float64(5)},
+		},
+		{name: "malformed json", data: `{"type":`, wantErr: ErrMalformedEvent},
+		{name: "not an object", data: `[1,2]`, wantErr: ErrMalformedEvent},
+		{name: "missing type", data: `{"movie_id":7}`, wantErr: ErrMissingEventType},
+		{name: "invalid version", data: `{"type":"movie_viewed","schema_version":"two"}`, wantErr: ErrMalformedEvent},
+		{name: "version from the future", data: `{"type":"movie_viewed","schema_version":4}`, wantErr: ErrUnsupportedVersion},
+		{name: "upcaster failure", data: `{"type":"movie_viewed","schema_version":2}`, wantErr: ErrUpcastFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event, err := decoder.Decode([]byte(tt.data))
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantVersion, event.Version)
+			assert.Equal(t, tt.wantFields, event.Fields)
+			assert.Equal(t, tt.wantKnown, event.Known)
+		})
+	}
+}
+
+func TestEvent_Bind(t *testing.T) {
+	event, err := newTestDecoder().Decode([]byte(`{"type":"movie_viewed","movie_id":7,"extra":true}`))
+	require.NoError(t, err)
+
+	var view struct {
+		FilmID int    `json:"film_id"`
+		Source string `json:"source"`
+	}
+	require.NoError(t, event.Bind(&view))
+	assert.Equal(t, 7, view.FilmID)
+	assert.Equal(t, "web", view.Source)
+}
+
+// fakeWriter запоминает сообщения, отправленные в DLQ
+type fakeWriter struct {
+	messages []kafka.Message
+}
+
+func (w *fakeWriter) WriteMessages(ctx context.Context, msgs ...kafka.Message) error {
+	w.messages = append(w.messages, msgs...)
+	return nil
+}
+
+func (w *fakeWriter) Close() error { return nil }
+
+func TestConsumer_ProcessMessage(t *testing.T) {
+	dlq := &fakeWriter{}
+	var handled []Event
+	consumer := (&Consumer{dlqWriter: dlq}).WithDecoder(newTestDecoder(), func(ctx context.Context, event Event) error {
+		handled = append(handled, event)
+		return nil
+	})
+
+	consumer.processMessage(context.Background(), kafka.Message{
+		Topic: "movie-views", Partition: 0, Offset: 1, Key: []byte("7"),
+		Value: []byte(`{"type":"movie_viewed","movie_id":7}`),
+	})
+	consumer.processMessage(context.Background(), kafka.Message{
+		Topic: "movie-views", Partition: 2, Offset: 42, Key: []byte("8"),
+		Value: []byte(`{"type":"movie_viewed","schema_version":9}`),
+	})
+
+	require.Len(t, handled, 1)
+	assert.Equal(t, 3, handled[0].Version)
+
+	require.Len(t, dlq.messages, 1)
+	dead := dlq.messages[0]
+	assert.Equal(t, []byte("8"), dead.Key)
+	assert.Equal(t, []byte(`{"type":"movie_viewed","schema_version":9}`), dead.Value)
+
+	headers := map[string]string{}
+	for _, h := range dead.Headers {
+		headers[h.Key] = string(h.Value)
+	}
+	assert.Equal(t, "movie-views", headers["x-original-topic"])
+	assert.Equal(t, "2", headers["x-original-partition"])
+	assert.Equal(t, "42", headers["x-original-offset"])
+	assert.Equal(t, "unsupported_version", headers["x-error-reason"])
+	assert.Contains(t, headers["x-error"], "supported up to v3")
+}
+
+// fakeLedger — журнал обработанных сообщений в памяти
+type fakeLedger struct {
+	seen     map[string]bool
+	released []domain.ProcessedMessage
+}
+
+func (l *fakeLedger) Claim(ctx context.Context, msg domain.ProcessedMessage) (bool, error) {
+	key := fmt.Sprintf("%s/%s/%d/%d", msg.Group, msg.Topic, msg.Partition, msg.Offset)
+	if msg.MessageID != "" {
+		key = msg.Group + "/" + msg.MessageID
+	}
+	if l.seen[key] {
+		return false, nil
+	}
+	l.seen[key] = true
+	return true, nil
+}
+
+func (l *fakeLedger) Release(ctx context.Context, msg domain.ProcessedMessage) error {
+	delete(l.seen, fmt.Sprintf("%s/%s/%d/%d", msg.Group, msg.Topic, msg.Partition, msg.Offset))
+	l.released = append(l.released, msg)
+	return nil
+}
+
+func TestConsumer_ProcessMessage_SkipsDuplicates(t *testing.T) {
+	ledger := &fakeLedger{seen: map[string]bool{}}
+	handled := 0
+	failNext := false
+	consumer := (&Consumer{group: "movie-events"}).
+		WithDecoder(newTestDecoder(), func(ctx context.Context, event Event) error {
+			handled++
+			if failNext {
+				failNext = false
+				return errors.New("db down")
+			}
+			return nil
+		}).
+		WithLedger(ledger)
+
+	view := kafka.Message{Topic: "movie-views", Partition: 0, Offset: 5, Value: []byte(`{"type":"movie_viewed","movie_id":7}`)}
+	before := testutil.ToFloat64(KafkaMessagesDuplicateSkippedTotal.WithLabelValues("movie-views"))
+
+	// Повторная доставка по той же позиции
+	consumer.processMessage(context.Background(), view)
+	consumer.processMessage(context.Background(), view)
+	assert.Equal(t, 1, handled)
+
+	// Та же копия события с другой позицией (повторная отправка продюсером)
+	withID := `{"type":"movie_viewed","movie_id":7,"event_id":"e-1"}`
+	consumer.processMessage(context.Background(), kafka.Message{Topic: "movie-views", Offset: 6, Value: []byte(withID)})
+	consumer.processMessage(context.Background(), kafka.Message{Topic: "movie-views", Offset: 9, Value: []byte(withID)})
+	assert.Equal(t, 2, handled)
+	assert.Equal(t, before+2, testutil.ToFloat64(KafkaMessagesDuplicateSkippedTotal.WithLabelValues("movie-views")))
+
+	// Ошибка обработчика снимает отметку, и сообщение можно обработать повторно
+	failNext = true
+	failed := kafka.Message{Topic: "movie-views", Offset: 7, Value: []byte(`{"type":"movie_viewed","movie_id":8}`)}
+	consumer.processMessage(context.Background(), failed)
+	require.Len(t, ledger.released, 1)
+	assert.Equal(t, int64(7), ledger.released[0].Offset)
+	assert.Equal(t, "movie-events", ledger.released[0].Group)
+
+	consumer.processMessage(context.Background(), failed)
+	assert.Equal(t, 4, handled)
+}
@@ -0,0 +1,414 @@
+// Package events описывает события, которые сервис публикует в Kafka.
+// У каждого типа события есть версия схемы: при несовместимом изменении полей версия
+// повышается, а в декодер консьюмеров добавляется upcaster с предыдущей версии
+// (см. kafka.Decoder). Перед отправкой событие проверяется на соответствие схеме,
+// чтобы потребители не получали сообщения без обязательных полей
+package events
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"cinematique/internal/kafka"
+)
+
+// ErrInvalidEvent возвращается, если событие не соответствует своей схеме
+var ErrInvalidEvent = errors.New("invalid event")
+
+// Типы событий (поле type)
+const (
+	TypeMovieViewed       = "movie_viewed"
+	TypeMovieSearched     = "movie_searched"
+	TypeMovieCreated      = "movie_created"
+	TypeMovieUpdated      = "movie_updated"
+	TypeMovieDeleted      = "movie_deleted"
+	TypeActorCreated      = "actor_created"
+	TypeActorUpdated      = "actor_updated"
+	TypeActorDeleted      = "actor_deleted"
+	TypeMovieMerged       = "movie_merged"
+	TypeActorMerged       = "actor_merged"
+	TypeMovieActorAdded   = "movie_actor_added"
+	TypeMovieActorRemoved = "movie_actor_removed"
+	TypeUserRegistered    = "user_registered"
+	TypeUserLoggedIn      = "user_logged_in"
+	TypeAccountLocked     = "account_locked"
+)
+
+// schemaVersions — актуальная версия схемы каждого типа события
+var schemaVersions = map[string]int{
+	TypeMovieViewed:       1,
+	TypeMovieSearched:     1,
+	TypeMovieCreated:      1,
+	TypeMovieUpdated:      1,
+	TypeMovieDeleted:      1,
+	TypeActorCreated:      1,
+	TypeActorUpdated:      1,
+	TypeActorDeleted:      1,
+	TypeMovieMerged:       1,
+	TypeActorMerged:       1,
+	TypeMovieActorAdded:   1,
+	TypeMovieActorRemoved: 1,
+	TypeUserRegistered:    2,
+	TypeUserLoggedIn:      2,
+	TypeAccountLocked:     1,
+}
+
+// schemaUpcasters — переходы с предыдущих версий схем (ключ — версия, из которой выполняется переход)
+var schemaUpcasters = map[string]map[int]kafka.Upcaster{
+	TypeUserRegistered: {1: upcastUserEventV1},
+	TypeUserLoggedIn:   {1: upcastUserEventV1},
+}
+
+// RegisterSchemas регистрирует в декодере все типы событий с их актуальными версиями
+func RegisterSchemas(decoder *kafka.Decoder) *kafka.Decoder {
+	for eventType, version := range schemaVersions {
+		decoder.Register(eventType, version, schemaUpcasters[eventType])
+	}
+	return decoder
+}
+
+// Event — событие, которое можно отправить в Kafka
+type Event interface {
+	header() *Header
+	// Validate проверяет поля, специфичные для типа события
+	Validate() error
+}
+
+// Header — поля, общие для всех событий
+type Header struct {
+	Type          string    `json:"type"`
+	SchemaVersion int       `json:"schema_version"`
+	EventID       string    `json:"event_id"`  // используется консьюмерами для защиты от повторов
+	Timestamp     time.Time `json:"timestamp"` // RFC 3339, UTC
+}
+
+func (h *Header) header() *Header { return h }
+
+// newHeader заполняет заголовок нового события
+func newHeader(eventType string) Header {
+	return Header{
+		Type:          eventType,
+		SchemaVersion: schemaVersions[eventType],
+		EventID:       kafka.NewEventID(),
+		Timestamp:     time.Now().UTC().Truncate(time.Second),
+	}
+}
+
+// validate проверяет заголовок
+func (h *Header) validate() error {
+	current, ok := schemaVersions[h.Type]
+	switch {
+	case !ok:
+		return fmt.Errorf("unknown event type %q", h.Type)
+	case h.SchemaVersion != current:
+		return fmt.Errorf("%s: schema_version %d, current is %d", h.Type, h.SchemaVersion, current)
+	case h.EventID == "":
+		return fmt.Errorf("%s: event_id is required", h.Type)
+	case h.Timestamp.IsZero():
+		return fmt.Errorf("%s: timestamp is required", h.Type)
+	}
+	return nil
+}
+
+// Check проверяет заголовок и поля события по его схеме
+func Check(event Event) error {
+	if event == nil {
+		return fmt.Errorf("%w: event is nil", ErrInvalidEvent)
+	}
+	if err := event.header().validate(); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidEvent, err)
+	}
+	if err := event.Validate(); err != nil {
+		return fmt.Errorf("%w: %s: %v", ErrInvalidEvent, event.header().Type, err)
+	}
+	return nil
+}
+
+// TypeOf возвращает тип события (поле type)
+func TypeOf(event Event) string {
+	return event.header().Type
+}
+
+// Marshal проверяет событие по схеме и сериализует его в JSON
+func Marshal(event Event) ([]byte, error) {
+	if err := Check(event); err != nil {
+		return nil, err
+	}
+	return json.Marshal(event)
+}
+
+// Bind раскладывает декодированное событие в структуру и проверяет её по схеме.
+// Декодер уже поднял событие до актуальной версии, поэтому структура всегда соответствует ей;
+// тип события вызывающий проверяет сам до вызова Bind
+func Bind(decoded kafka.Event, out Event) error {
+	if err := decoded.Bind(out); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidEvent, err)
+	}
+	if err := out.Validate(); err != nil {
+		return fmt.Errorf("%w: %s: %v", ErrInvalidEvent, decoded.Type, err)
+	}
+	return nil
+}
+
+// MovieViewed — просмотр карточки фильма
+type MovieViewed struct {
+	Header
+	MovieID int `json:"movie_id"`
+}
+
+// NewMovieViewed создаёт событие просмотра фильма
+func NewMovieViewed(movieID int) *MovieViewed {
+	return &MovieViewed{Header: newHeader(TypeMovieViewed), MovieID: movieID}
+}
+
+// Validate проверяет поля события
+func (e *MovieViewed) Validate() error {
+	if e.MovieID <= 0 {
+		return errors.New("movie_id must be positive")
+	}
+	return nil
+}
+
+// MovieSearched — поиск фильмов; query содержит параметры запроса как есть
+type MovieSearched struct {
+	Header
+	Query   map[string][]string `json:"query"`
+	Results int                 `json:"results"`
+}
+
+// NewMovieSearched создаёт событие поиска фильмов
+func NewMovieSearched(query map[string][]string, results int) *MovieSearched {
+	return &MovieSearched{Header: newHeader(TypeMovieSearched), Query: query, Results: results}
+}
+
+// Validate проверяет поля события
+func (e *MovieSearched) Validate() error {
+	if e.Results < 0 {
+		return errors.New("results must not be negative")
+	}
+	return nil
+}
+
+// Сущности и действия событий изменения каталога
+const (
+	EntityMovie = "movie"
+	EntityActor = "actor"
+
+	ActionCreated = "created"
+	ActionUpdated = "updated"
+	ActionDeleted = "deleted"
+)
+
+// CatalogChanged — создание, изменение или удаление фильма или актёра.
+// Тип события составляется из сущности и действия, например movie_created
+type CatalogChanged struct {
+	Header
+	Entity string `json:"entity"`
+	Action string `json:"action"`
+	ID     int    `json:"id"`
+}
+
+// NewCatalogChanged создаёт событие изменения каталога
+func NewCatalogChanged(entity, action string, id int) *CatalogChanged {
+	return &CatalogChanged{Header: newHeader(entity + "_" + action), Entity: entity, Action: action, ID: id}
+}
+
+// Validate проверяет поля события
+func (e *CatalogChanged) Validate() error {
+	if e.Entity != EntityMovie && e.Entity != EntityActor {
+		return fmt.Errorf("unknown entity %q", e.Entity)
+	}
+	if e.Action != ActionCreated && e.Action != ActionUpdated && e.Action != ActionDeleted {
+		return fmt.Errorf("unknown action %q", e.Action)
+	}
+	if e.Type != e.Entity+"_"+e.Action {
+		return fmt.Errorf("type %q does not match entity and action", e.Type)
+	}
+	if e.ID <= 0 {
+		return errors.New("id must be positive")
+	}
+	return nil
+}
+
+// MovieMerged — фильм-дубликат объединён с основным
+type MovieMerged struct {
+	Header
+	KeepID           int `json:"keep_id"`
+	DuplicateID      int `json:"duplicate_id"`
+	ActorsReassigned int `json:"actors_reassigned"`
+}
+
+// NewMovieMerged создаёт событие слияния фильмов
+func NewMovieMerged(keepID, duplicateID, actorsReassigned int) *MovieMerged {
+	return &MovieMerged{Header: newHeader(TypeMovieMerged), KeepID: keepID, DuplicateID: duplicateID, ActorsReassigned: actorsReassigned}
+}
+
+// Validate проверяет поля события
+func (e *MovieMerged) Validate() error {
+	return validateMerge(e.KeepID, e.DuplicateID, e.ActorsReassigned)
+}
+
+// ActorMerged — актёр-дубликат объединён с основным
+type ActorMerged struct {
+	Header
+	KeepID           int `json:"keep_id"`
+	DuplicateID      int `json:"duplicate_id"`
+	MoviesReassigned int `json:"movies_reassigned"`
+}
+
+// NewActorMerged создаёт событие слияния актёров
+func NewActorMerged(keepID, duplicateID, moviesReassigned int) *ActorMerged {
+	return &ActorMerged{Header: newHeader(TypeActorMerged), KeepID: keepID, DuplicateID: duplicateID, MoviesReassigned: moviesReassigned}
+}
+
+// Validate проверяет поля события
+func (e *ActorMerged) Validate() error {
+	return validateMerge(e.KeepID, e.DuplicateID, e.MoviesReassigned)
+}
+
+func validateMerge(keepID, duplicateID, reassigned int) error {
+	switch {
+	case keepID <= 0 || duplicateID <= 0:
+		return errors.New("keep_id and duplicate_id must be positive")
+	case keepID == duplicateID:
+		return errors.New("keep_id and duplicate_id must differ")
+	case reassigned < 0:
+		return errors.New("reassigned count must not be negative")
+	}
+	return nil
+}
+
+// Performer — пользователь, выполнивший действие; UserID — ID из токена (числовой для JWT, UUID для Keycloak)
+type Performer struct {
+	UserID   string `json:"user_id"`
+	Username string `json:"username,omitempty"`
+	Role     string `json:"role,omitempty"`
+}
+
+// MovieActorLink — актёр добавлен в состав фильма или убран из него. По этим событиям
+// внешние системы (например, рекомендательные) обновляют граф связей без полной перезагрузки
+type MovieActorLink struct {
+	Header
+	MovieID       int       `json:"movie_id"`
+	ActorID       int       `json:"actor_id"`
+	CharacterName string    `json:"character_name,omitempty"` // только для movie_actor_added
+	BillingOrder  int       `json:"billing_order,omitempty"`  // только для movie_actor_added
+	PerformedBy   Performer `json:"performed_by"`
+}
+
+// NewMovieActorAdded создаёт событие добавления актёра в фильм
+func NewMovieActorAdded(movieID, actorID int, characterName string, billingOrder int, by Performer) *MovieActorLink {
+	return &MovieActorLink{
+		Header:        newHeader(TypeMovieActorAdded),
+		MovieID:       movieID,
+		ActorID:       actorID,
+		CharacterName: characterName,
+		BillingOrder:  billingOrder,
+		PerformedBy:   by,
+	}
+}
+
+// NewMovieActorRemoved создаёт событие удаления актёра из фильма
+func NewMovieActorRemoved(movieID, actorID int, by Performer) *MovieActorLink {
+	return &MovieActorLink{Header: newHeader(TypeMovieActorRemoved), MovieID: movieID, ActorID: actorID, PerformedBy: by}
+}
+
+// Validate проверяет поля события
+func (e *MovieActorLink) Validate() error {
+	switch {
+	case e.Type != TypeMovieActorAdded && e.Type != TypeMovieActorRemoved:
+		return fmt.Errorf("unexpected type %q", e.Type)
+	case e.MovieID <= 0 || e.ActorID <= 0:
+		return errors.New("movie_id and actor_id must be positive")
+	case e.BillingOrder < 0:
+		return errors.New("billing_order must not be negative")
+	case e.PerformedBy.UserID == "":
+		return errors.New("performed_by.user_id is required")
+	}
+	return nil
+}
+
+// UserActivity — поля событий учётной записи: кто и откуда выполнил действие.
+// Имя пользователя и email в события не попадают: по user_id их можно получить из БД
+type UserActivity struct {
+	UserID    int    `json:"user_id"`
+	IP        string `json:"ip,omitempty"`
+	UserAgent string `json:"user_agent,omitempty"`
+}
+
+func (a UserActivity) validate() error {
+	if a.UserID <= 0 {
+		return errors.New("user_id must be positive")
+	}
+	return nil
+}
+
+// upcastUserEventV1 переводит user_registered и user_logged_in из версии 1, где было только
+// имя пользователя, в версию 2. Имя удаляется; user_id в таких событиях остаётся пустым
+func upcastUserEventV1(fields map[string]interface{}) (map[string]interface{}, error) {
+	delete(fields, "username")
+	return fields, nil
+}
+
+// UserRegistered — регистрация пользователя
+type UserRegistered struct {
+	Header
+	UserActivity
+}
+
+// NewUserRegistered создаёт событие регистрации
+func NewUserRegistered(activity UserActivity) *UserRegistered {
+	return &UserRegistered{Header: newHeader(TypeUserRegistered), UserActivity: activity}
+}
+
+// Validate проверяет поля события
+func (e *UserRegistered) Validate() error {
+	return e.UserActivity.validate()
+}
+
+// UserLoggedIn — успешный вход пользователя
+type UserLoggedIn struct {
+	Header
+	UserActivity
+}
+
+// NewUserLoggedIn создаёт событие входа
+func NewUserLoggedIn(activity UserActivity) *UserLoggedIn {
+	return &UserLoggedIn{Header: newHeader(TypeUserLoggedIn), UserActivity: activity}
+}
+
+// Validate проверяет поля события
+func (e *UserLoggedIn) Validate() error {
+	return e.UserActivity.validate()
+}
+
+// AccountLocked — учётная запись заблокирована после неудачных входов
+type AccountLocked struct {
+	Header
+	UserID      int       `json:"user_id"`
+	Username    string    `json:"username"`
+	LockedUntil time.Time `json:"locked_until"`
+}
+
+// NewAccountLocked создаёт событие блокировки учётной записи
+func NewAccountLocked(userID int, username string, until time.Time) *AccountLocked {
+	return &AccountLocked{
+		Header:      newHeader(TypeAccountLocked),
+		UserID:      userID,
+		Username:    username,
+		LockedUntil: until.UTC().Truncate(time.Second),
+	}
+}
+
+// Validate проверяет поля события
+func (e *AccountLocked) Validate() error {
+	switch {
+	case e.Username == "":
+		return errors.New("username is required")
+	case e.LockedUntil.IsZero():
+		return errors.New("locked_until is required")
+	}
+	return nil
+}
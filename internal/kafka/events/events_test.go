@@ -0,0 +1,129 @@
+package events
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"cinematique/internal/kafka"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarshal(t *testing.T) {
+	payload, err := Marshal(NewMovieViewed(7))
+	require.NoError(t, err)
+
+	var fields map[string]interface{}
+	require.NoError(t, json.Unmarshal(payload, &fields))
+	assert.Equal(t, "movie_viewed", fields["type"])
+	assert.Equal(t, float64(1), fields["schema_version"])
+	assert.Equal(t, float64(7), fields["movie_id"])
+	assert.NotEmpty(t, fields["event_id"])
+	_, err = time.Parse(time.RFC3339, fields["timestamp"].(string))
+	assert.NoError(t, err)
+}
+
+func TestMarshal_RejectsInvalidEvents(t *testing.T) {
+	withoutID := NewUserRegistered(UserActivity{UserID: 1})
+	withoutID.EventID = ""
+	staleVersion := NewMovieViewed(7)
+	staleVersion.SchemaVersion = 0
+
+	tests := []struct {
+		name  string
+		event Event
+	}{
+		{name: "missing movie id", event: NewMovieViewed(0)},
+		{name: "negative results", event: NewMovieSearched(nil, -1)},
+		{name: "unknown catalog entity", event: NewCatalogChanged("collection", ActionCreated, 1)},
+		{name: "unknown catalog action", event: NewCatalogChanged(EntityMovie, "archived", 1)},
+		{name: "merge with itself", event: NewActorMerged(3, 3, 0)},
+		{name: "login without user id", event: NewUserLoggedIn(UserActivity{IP: "10.0.0.1"})},
+		{name: "lockout without deadline", event: NewAccountLocked(1, "neo", time.Time{})},
+		{name: "cast link without performer", event: NewMovieActorAdded(1, 2, "Neo", 1, Performer{})},
+		{name: "cast link without actor", event: NewMovieActorRemoved(1, 0, Performer{UserID: "7"})},
+		{name: "missing event id", event: withoutID},
+		{name: "wrong schema version", event: staleVersion},
+		{name: "nil event", event: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Marshal(tt.event)
+			assert.ErrorIs(t, err, ErrInvalidEvent)
+		})
+	}
+}
+
+func TestCatalogChangedType(t *testing.T) {
+	event := NewCatalogChanged(EntityMovie, ActionCreated, 7)
+	assert.Equal(t, TypeMovieCreated, event.Type)
+	_, err := Marshal(event)
+	assert.NoError(t, err)
+}
+
+func TestMovieActorLink(t *testing.T) {
+	by := Performer{UserID: "7", Username: "moderator", Role: "moderator"}
+	payload, err := Marshal(NewMovieActorAdded(3, 9, "Neo", 1, by))
+	require.NoError(t, err)
+
+	var fields map[string]interface{}
+	require.NoError(t, json.Unmarshal(payload, &fields))
+	assert.Equal(t, TypeMovieActorAdded, fields["type"])
+	assert.Equal(t, "Neo", fields["character_name"])
+	assert.Equal(t, map[string]interface{}{"user_id": "7", "username": "moderator", "role": "moderator"}, fields["performed_by"])
+
+	payload, err = Marshal(NewMovieActorRemoved(3, 9, by))
+	require.NoError(t, err)
+	fields = nil
+	require.NoError(t, json.Unmarshal(payload, &fields))
+	assert.Equal(t, TypeMovieActorRemoved, fields["type"])
+	assert.NotContains(t, fields, "character_name")
+}
+
+func TestBind_RoundTripThroughDecoder(t *testing.T) {
+	decoder := RegisterSchemas(kafka.NewDecoder())
+	sent := NewMovieSearched(map[string][]string{"title": {"matrix"}}, 3)
+	payload, err := Marshal(sent)
+	require.NoError(t, err)
+
+	decoded, err := decoder.Decode(payload)
+	require.NoError(t, err)
+	assert.True(t, decoded.Known)
+
+	var received MovieSearched
+	require.NoError(t, Bind(decoded, &received))
+	assert.Equal(t, *sent, received)
+}
+
+func TestBind_RejectsInvalidPayload(t *testing.T) {
+	decoder := RegisterSchemas(kafka.NewDecoder())
+
+	// Событие до появления версий схем: без schema_version и без movie_id
+	decoded, err := decoder.Decode([]byte(`{"type":"movie_viewed","event_id":"e-1"}`))
+	require.NoError(t, err)
+	var viewed MovieViewed
+	assert.ErrorIs(t, Bind(decoded, &viewed), ErrInvalidEvent)
+}
+
+func TestUserEvents(t *testing.T) {
+	payload, err := Marshal(NewUserLoggedIn(UserActivity{UserID: 7, IP: "10.0.0.1", UserAgent: "curl/8.0"}))
+	require.NoError(t, err)
+
+	var fields map[string]interface{}
+	require.NoError(t, json.Unmarshal(payload, &fields))
+	assert.Equal(t, TypeUserLoggedIn, fields["type"])
+	assert.Equal(t, float64(2), fields["schema_version"])
+	assert.Equal(t, float64(7), fields["user_id"])
+	assert.Equal(t, "10.0.0.1", fields["ip"])
+	assert.Equal(t, "curl/8.0", fields["user_agent"])
+	assert.NotContains(t, fields, "username")
+
+	// Версия 1 содержала только имя пользователя: после подъёма до версии 2 его нет
+	decoded, err := RegisterSchemas(kafka.NewDecoder()).
+		Decode([]byte(`{"type":"user_registered","schema_version":1,"event_id":"e-1","username":"neo"}`))
+	require.NoError(t, err)
+	assert.Equal(t, 2, decoded.Version)
+	assert.NotContains(t, decoded.Fields, "username")
+}
@@ -6,6 +6,7 @@ import (
 	"log"
 
 	"github.com/segmentio/kafka-go"
+	"go.opentelemetry.io/otel"
 )
 
 // Producer wraps a kafka.Writer for sending messages.
@@ -48,6 +49,7 @@ func (p *Producer) Produce(ctx context.Context, topic string, key, value []byte)
 		Key:   key,
 		Value: value,
 	}
+	otel.GetTextMapPropagator().Inject(ctx, headerCarrier{headers: &message.Headers})
 
 	err := p.writer.WriteMessages(ctx, message)
 	if err != nil {
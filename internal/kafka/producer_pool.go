@@ -2,14 +2,28 @@ package kafka
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"log"
+	"strconv"
+	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 )
 
-var ErrBufferFull = errors.New("producer pool buffer is full")
+var (
+	ErrBufferFull = errors.New("producer pool buffer is full")
+	ErrPoolClosed = errors.New("producer pool is closed")
+)
 
 // Интерфейс для продюсера
 // ProducerInterface описывает методы для отправки сообщений и закрытия продюсера
@@ -21,70 +35,397 @@ type ProducerInterface interface {
 
 // KafkaEvent описывает событие для отправки в Kafka
 type KafkaEvent struct {
-	Topic string
-	Key   []byte
-	Value []byte
+	Topic  string
+	Key    []byte
+	Value  []byte
+	Parent trace.SpanContext // span запроса, поставившего событие в очередь
+}
+
+// NewEventID возвращает уникальный идентификатор события для поля event_id.
+// По нему консьюмеры отбрасывают повторно отправленные копии одного события
+func NewEventID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return strconv.FormatInt(time.Now().UnixNano(), 36)
+	}
+	return hex.EncodeToString(b)
 }
 
 // Метрики для мониторинга
 var (
-	KafkaProduceErrorsTotal    = prometheus.NewCounter(prometheus.CounterOpts{Name: "kafka_produce_errors_total", Help: "Total number of Kafka produce errors."})
-	KafkaMessagesProducedTotal = prometheus.NewCounter(prometheus.CounterOpts{Name: "kafka_messages_produced_total", Help: "Total number of Kafka messages produced."})
-	KafkaMessagesDroppedTotal  = prometheus.NewCounter(prometheus.CounterOpts{Name: "kafka_messages_dropped_total", Help: "Total number of Kafka messages dropped due to buffer full."})
+	KafkaProduceErrorsTotal        = prometheus.NewCounter(prometheus.CounterOpts{Name: "kafka_produce_errors_total", Help: "Total number of Kafka produce errors."})
+	KafkaMessagesProducedTotal     = prometheus.NewCounter(prometheus.CounterOpts{Name: "kafka_messages_produced_total", Help: "Total number of Kafka messages produced."})
+	KafkaMessagesDroppedTotal      = prometheus.NewCounter(prometheus.CounterOpts{Name: "kafka_messages_dropped_total", Help: "Total number of Kafka messages dropped due to buffer full."})
+	KafkaMessagesRetriedTotal      = prometheus.NewCounter(prometheus.CounterOpts{Name: "kafka_messages_retried_total", Help: "Total number of Kafka produce retries."})
+	KafkaMessagesDeadLetteredTotal = prometheus.NewCounter(prometheus.CounterOpts{Name: "kafka_messages_dead_lettered_total", Help: "Total number of Kafka messages sent to the dead-letter topic after exhausting retries."})
+	// KafkaProducerDroppedTotal уточняет kafka_messages_dropped_total причиной: политикой
+	// переполненного буфера (drop_new, drop_oldest), таймаутом ожидания (block) или закрытием пула
+	KafkaProducerDroppedTotal = prometheus.NewCounterVec(prometheus.CounterOpts{Name: "kafka_producer_dropped_messages_total", Help: "Total number of Kafka messages dropped by the producer pool, by reason."}, []string{"reason"})
+	KafkaProducerQueueDepth   = prometheus.NewGauge(prometheus.GaugeOpts{Name: "kafka_producer_queue_depth", Help: "Number of events waiting in the producer pool buffer."})
+	KafkaProducerPending      = prometheus.NewGauge(prometheus.GaugeOpts{Name: "kafka_producer_pending_messages", Help: "Number of events queued or being delivered by the producer pool."})
 )
 
 func init() {
 	prometheus.MustRegister(KafkaProduceErrorsTotal)
 	prometheus.MustRegister(KafkaMessagesProducedTotal)
 	prometheus.MustRegister(KafkaMessagesDroppedTotal)
+	prometheus.MustRegister(KafkaMessagesRetriedTotal)
+	prometheus.MustRegister(KafkaMessagesDeadLetteredTotal)
+	prometheus.MustRegister(KafkaProducerDroppedTotal)
+	prometheus.MustRegister(KafkaProducerQueueDepth)
+	prometheus.MustRegister(KafkaProducerPending)
+}
+
+// BackpressurePolicy определяет, что делает пул, когда буфер событий заполнен
+type BackpressurePolicy string
+
+const (
+	BackpressureDropNew    BackpressurePolicy = "drop_new"    // новое событие отбрасывается с ErrBufferFull
+	BackpressureDropOldest BackpressurePolicy = "drop_oldest" // из буфера вытесняется самое старое событие
+	BackpressureBlock      BackpressurePolicy = "block"       // отправитель ждёт места в буфере
+)
+
+// ParseBackpressurePolicy разбирает политику переполнения буфера; false — значение неизвестно
+func ParseBackpressurePolicy(value string) (BackpressurePolicy, bool) {
+	switch policy := BackpressurePolicy(strings.ToLower(strings.TrimSpace(value))); policy {
+	case BackpressureDropNew, BackpressureDropOldest, BackpressureBlock:
+		return policy, true
+	}
+	return "", false
+}
+
+// PoolOption настраивает ProducerPool
+type PoolOption func(*ProducerPool)
+
+// WithRetry включает повторные попытки отправки: до maxRetries повторов с задержкой,
+// которая удваивается от initialBackoff до maxBackoff
+func WithRetry(maxRetries int, initialBackoff, maxBackoff time.Duration) PoolOption {
+	return func(p *ProducerPool) {
+		p.maxRetries = maxRetries
+		p.initialBackoff = initialBackoff
+		p.maxBackoff = maxBackoff
+	}
+}
+
+// WithDeadLetterTopic задаёт топик, куда отправляются сообщения, которые не удалось
+// доставить после всех повторов
+func WithDeadLetterTopic(topic string) PoolOption {
+	return func(p *ProducerPool) { p.deadLetterTopic = topic }
+}
+
+// WithBroadcaster дублирует поставленные в очередь события в рассыльщик b,
+// чтобы подписчики внутри процесса получали их без чтения из Kafka
+func WithBroadcaster(b *Broadcaster) PoolOption {
+	return func(p *ProducerPool) { p.broadcaster = b }
+}
+
+// WithBackpressure задаёт политику переполненного буфера. Для BackpressureBlock blockTimeout
+// ограничивает ожидание места (0 — ждать, пока не отменён контекст отправителя); по его истечении
+// событие отбрасывается с ErrBufferFull. По умолчанию действует BackpressureDropNew
+func WithBackpressure(policy BackpressurePolicy, blockTimeout time.Duration) PoolOption {
+	return func(p *ProducerPool) {
+		p.policy = policy
+		p.blockTimeout = blockTimeout
+	}
+}
+
+// DeadLetter — сообщение, отправляемое в dead-letter топик пула
+type DeadLetter struct {
+	OriginalTopic string    `json:"original_topic"`
+	Key           string    `json:"key,omitempty"`
+	Payload       string    `json:"payload"`
+	Error         string    `json:"error"`
+	Attempts      int       `json:"attempts"`
+	FailedAt      time.Time `json:"failed_at"`
 }
 
 type ProducerPool struct {
 	producer ProducerInterface
 	events   chan KafkaEvent
 	wg       sync.WaitGroup
+	sendMu   sync.RWMutex // отправители держат на чтение; Close берёт на запись перед закрытием events
+	pending  atomic.Int64 // события в буфере и в доставке; Flush ждёт, пока их не останется
+
+	mu      sync.Mutex
+	closed  bool
+	done    chan struct{} // закрывается в Close, прерывает ожидание между повторами
+	workers int           // сколько воркеров должно работать
+	active  atomic.Int32  // сколько воркеров работает сейчас
+
+	maxRetries      int
+	initialBackoff  time.Duration
+	maxBackoff      time.Duration
+	deadLetterTopic string
+	broadcaster     *Broadcaster
+	policy          BackpressurePolicy
+	blockTimeout    time.Duration
 }
 
-func NewProducerPool(producer ProducerInterface, workers, bufSize int) *ProducerPool {
+func NewProducerPool(producer ProducerInterface, workers, bufSize int, opts ...PoolOption) *ProducerPool {
 	pool := &ProducerPool{
 		producer: producer,
 		events:   make(chan KafkaEvent, bufSize),
+		done:     make(chan struct{}),
+		workers:  workers,
+		policy:   BackpressureDropNew,
+	}
+	for _, opt := range opts {
+		opt(pool)
 	}
-	pool.wg.Add(workers)
 	for i := 0; i < workers; i++ {
-		go pool.worker()
+		pool.startWorker()
 	}
 	return pool
 }
 
+func (p *ProducerPool) startWorker() {
+	p.wg.Add(1)
+	p.active.Add(1)
+	go p.worker()
+}
+
 func (p *ProducerPool) worker() {
 	defer p.wg.Done()
+	defer p.active.Add(-1)
+	defer func() {
+		// Паника в продюсере останавливает только этот воркер; его перезапустит Restart
+		if r := recover(); r != nil {
+			log.Printf("Producer pool worker stopped after panic: %v", r)
+		}
+	}()
 	for event := range p.events {
-		// Используем встроенный в продюсер механизм ретраев и DLQ
-		if err := p.producer.Produce(context.Background(), event.Topic, event.Key, event.Value); err != nil {
-			// Ошибка уже залогирована в самом продюсере, здесь достаточно метрики
-			KafkaProduceErrorsTotal.Inc()
-		} else {
+		KafkaProducerQueueDepth.Set(float64(len(p.events)))
+		p.deliver(event)
+	}
+}
+
+// deliver отправляет событие с повторами; после исчерпания повторов событие уходит
+// в dead-letter топик (если он задан)
+func (p *ProducerPool) deliver(event KafkaEvent) {
+	defer p.settle()
+	ctx, span := startProduceSpan(event)
+	defer span.End()
+
+	var err error
+	attempts := 0
+	for {
+		attempts++
+		if err = p.producer.Produce(ctx, event.Topic, event.Key, event.Value); err == nil {
 			KafkaMessagesProducedTotal.Inc()
+			span.SetAttributes(attribute.Int("messaging.kafka.attempts", attempts))
+			return
+		}
+		KafkaProduceErrorsTotal.Inc()
+		if attempts > p.maxRetries || !p.waitBackoff(attempts) {
+			break
 		}
+		KafkaMessagesRetriedTotal.Inc()
+	}
+
+	log.Printf("Failed to produce message to topic %s after %d attempts: %v", event.Topic, attempts, err)
+	span.SetAttributes(attribute.Int("messaging.kafka.attempts", attempts))
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+	p.deadLetter(event, err, attempts)
+}
+
+// waitBackoff ждёт перед повтором номер attempt; false, если пул закрывается
+func (p *ProducerPool) waitBackoff(attempt int) bool {
+	delay := p.initialBackoff
+	for i := 1; i < attempt && delay < p.maxBackoff; i++ {
+		delay *= 2
+	}
+	if p.maxBackoff > 0 && delay > p.maxBackoff {
+		delay = p.maxBackoff
 	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-p.done:
+		return false
+	}
+}
+
+// deadLetter отправляет недоставленное событие в dead-letter топик вместе с причиной ошибки
+func (p *ProducerPool) deadLetter(event KafkaEvent, cause error, attempts int) {
+	if p.deadLetterTopic == "" {
+		return
+	}
+	payload, err := json.Marshal(DeadLetter{
+		OriginalTopic: event.Topic,
+		Key:           string(event.Key),
+		Payload:       string(event.Value),
+		Error:         cause.Error(),
+		Attempts:      attempts,
+		FailedAt:      time.Now().UTC(),
+	})
+	if err != nil {
+		log.Printf("Failed to encode dead letter for topic %s: %v", event.Topic, err)
+		return
+	}
+	if err := p.producer.Produce(context.Background(), p.deadLetterTopic, event.Key, payload); err != nil {
+		log.Printf("CRITICAL: Failed to write message to dead-letter topic %s: %v", p.deadLetterTopic, err)
+		return
+	}
+	KafkaMessagesDeadLetteredTotal.Inc()
 }
 
 func (p *ProducerPool) Produce(topic string, key, value []byte) error {
+	return p.ProduceContext(context.Background(), topic, key, value)
+}
+
+// ProduceContext ставит событие в очередь на отправку; span из ctx становится родителем
+// span отправки, так что событие попадает в трассу породившего его запроса.
+// При заполненном буфере поведение определяет политика пула (WithBackpressure)
+func (p *ProducerPool) ProduceContext(ctx context.Context, topic string, key, value []byte) error {
+	event := KafkaEvent{Topic: topic, Key: key, Value: value, Parent: trace.SpanContextFromContext(ctx)}
+
+	p.sendMu.RLock()
+	defer p.sendMu.RUnlock()
+	p.mu.Lock()
+	closed := p.closed
+	p.mu.Unlock()
+	if closed {
+		p.drop("closed")
+		return ErrPoolClosed
+	}
+
+	p.pending.Add(1)
+	KafkaProducerPending.Inc()
+	if err := p.enqueue(ctx, event); err != nil {
+		p.settle()
+		return err
+	}
+	KafkaProducerQueueDepth.Set(float64(len(p.events)))
+	if p.broadcaster != nil {
+		p.broadcaster.Publish(event)
+	}
+	return nil
+}
+
+// enqueue кладёт событие в буфер по политике пула
+func (p *ProducerPool) enqueue(ctx context.Context, event KafkaEvent) error {
 	select {
-	case p.events <- KafkaEvent{Topic: topic, Key: key, Value: value}:
+	case p.events <- event:
 		return nil
 	default:
-		KafkaMessagesDroppedTotal.Inc()
+	}
+
+	switch p.policy {
+	case BackpressureBlock:
+		var timeout <-chan time.Time
+		if p.blockTimeout > 0 {
+			timer := time.NewTimer(p.blockTimeout)
+			defer timer.Stop()
+			timeout = timer.C
+		}
+		select {
+		case p.events <- event:
+			return nil
+		case <-ctx.Done():
+			p.drop("block_timeout")
+			return ctx.Err()
+		case <-timeout:
+			p.drop("block_timeout")
+			log.Println("failed to queue message: buffer is still full after waiting")
+			return ErrBufferFull
+		case <-p.done:
+			p.drop("closed")
+			return ErrPoolClosed
+		}
+	case BackpressureDropOldest:
+		for {
+			select {
+			case p.events <- event:
+				return nil
+			default:
+			}
+			// Место могли освободить воркеры или другой отправитель — тогда вытеснять нечего
+			select {
+			case oldest := <-p.events:
+				p.settle()
+				p.drop("drop_oldest")
+				log.Printf("dropped oldest queued message to topic %s: buffer is full", oldest.Topic)
+			default:
+			}
+		}
+	default:
+		p.drop("drop_new")
 		log.Println("failed to queue message: buffer is full")
 		return ErrBufferFull
 	}
 }
 
+// drop учитывает отброшенное событие в метриках
+func (p *ProducerPool) drop(reason string) {
+	KafkaMessagesDroppedTotal.Inc()
+	KafkaProducerDroppedTotal.WithLabelValues(reason).Inc()
+}
+
+// settle отмечает, что событие доставлено или отброшено и больше не ожидает отправки
+func (p *ProducerPool) settle() {
+	p.pending.Add(-1)
+	KafkaProducerPending.Dec()
+}
+
+// Flush ждёт, пока воркеры отправят все поставленные в очередь события, или пока не отменён ctx.
+// Новые события во время Flush принимаются как обычно. Вызывается при остановке приложения перед Close,
+// чтобы события последних запросов не ушли в dead-letter топик из-за прерванных повторов
+func (p *ProducerPool) Flush(ctx context.Context) error {
+	ticker := time.NewTicker(10 * time.Millisecond)
+	defer ticker.Stop()
+	for p.pending.Load() > 0 {
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("flushing producer pool: %d events not delivered: %w", p.pending.Load(), ctx.Err())
+		case <-ticker.C:
+		}
+	}
+	return nil
+}
+
+// Healthy проверяет, что пул открыт, все воркеры работают и буфер не переполнен
+func (p *ProducerPool) Healthy() error {
+	p.mu.Lock()
+	closed := p.closed
+	p.mu.Unlock()
+	if closed {
+		return ErrPoolClosed
+	}
+	if active := int(p.active.Load()); active < p.workers {
+		return fmt.Errorf("%d of %d producer workers running", active, p.workers)
+	}
+	if len(p.events) == cap(p.events) {
+		return ErrBufferFull
+	}
+	return nil
+}
+
+// Restart запускает заново остановившихся воркеров
+func (p *ProducerPool) Restart() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.closed {
+		return ErrPoolClosed
+	}
+	for missing := p.workers - int(p.active.Load()); missing > 0; missing-- {
+		p.startWorker()
+	}
+	return nil
+}
+
 func (p *ProducerPool) Close() {
 	log.Println("Closing producer pool...")
+	p.mu.Lock()
+	p.closed = true
+	p.mu.Unlock()
+	close(p.done) // Прерываем ожидание повторов и места в буфере: оставшиеся события сразу уходят в dead-letter топик
+	p.sendMu.Lock()
 	close(p.events) // Закрываем канал, чтобы воркеры завершили работу после обработки оставшихся событий
-	p.wg.Wait()     // Ждем, пока все воркеры закончат
+	p.sendMu.Unlock()
+	p.wg.Wait() // Ждем, пока все воркеры закончат
 
 	if err := p.producer.Close(); err != nil {
 		log.Printf("Error closing producer: %v", err)
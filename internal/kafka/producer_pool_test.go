@@ -2,13 +2,20 @@ package kafka
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"sync"
 	"testing"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
 )
 
 // MockProducerInterface мокает ProducerInterface для тестирования
@@ -270,3 +277,230 @@ func TestProducerPool_Metrics_Dropped(t *testing.T) {
 
 	mockProducer.AssertExpectations(t)
 }
+
+func TestProducerPool_HealthyAndRestart(t *testing.T) {
+	mockProducer := &MockProducerInterface{}
+	mockProducer.On("Close").Return(nil).Maybe()
+	mockProducer.On("Produce", mock.Anything, "boom", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) { panic("producer crashed") }).Once()
+	delivered := make(chan struct{}, 1)
+	mockProducer.On("Produce", mock.Anything, "ok", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) { delivered <- struct{}{} }).Return(nil)
+
+	pool := NewProducerPool(mockProducer, 1, 4)
+	assert.NoError(t, pool.Healthy())
+
+	// Паника в продюсере останавливает воркера
+	assert.NoError(t, pool.Produce("boom", nil, nil))
+	assert.Eventually(t, func() bool { return pool.Healthy() != nil }, time.Second, 5*time.Millisecond)
+	assert.EqualError(t, pool.Healthy(), "0 of 1 producer workers running")
+
+	// Перезапуск возвращает воркера, и пул снова отправляет сообщения
+	assert.NoError(t, pool.Restart())
+	assert.NoError(t, pool.Healthy())
+	assert.NoError(t, pool.Produce("ok", nil, nil))
+	select {
+	case <-delivered:
+	case <-time.After(time.Second):
+		t.Fatal("message was not delivered after restart")
+	}
+
+	pool.Close()
+	assert.ErrorIs(t, pool.Healthy(), ErrPoolClosed)
+	assert.ErrorIs(t, pool.Restart(), ErrPoolClosed)
+}
+
+func TestProducerPool_RetryThenSuccess(t *testing.T) {
+	mockProducer := &MockProducerInterface{}
+	mockProducer.On("Close").Return(nil).Maybe()
+	mockProducer.On("Produce", mock.Anything, "test-topic", mock.Anything, mock.Anything).
+		Return(errors.New("leader not available")).Once()
+	delivered := make(chan struct{}, 1)
+	mockProducer.On("Produce", mock.Anything, "test-topic", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) { delivered <- struct{}{} }).Return(nil).Once()
+
+	pool := NewProducerPool(mockProducer, 1, 4,
+		WithRetry(2, time.Millisecond, 2*time.Millisecond),
+		WithDeadLetterTopic("dlq"))
+	defer pool.Close()
+
+	assert.NoError(t, pool.Produce("test-topic", []byte("key"), []byte("value")))
+	select {
+	case <-delivered:
+	case <-time.After(time.Second):
+		t.Fatal("message was not delivered after retry")
+	}
+
+	mockProducer.AssertNotCalled(t, "Produce", mock.Anything, "dlq", mock.Anything, mock.Anything)
+}
+
+func TestProducerPool_DeadLetterAfterRetries(t *testing.T) {
+	mockProducer := &MockProducerInterface{}
+	mockProducer.On("Close").Return(nil).Maybe()
+	mockProducer.On("Produce", mock.Anything, "test-topic", mock.Anything, mock.Anything).
+		Return(errors.New("leader not available")).Times(3)
+	deadLetters := make(chan []byte, 1)
+	mockProducer.On("Produce", mock.Anything, "dlq", []byte("key"), mock.Anything).
+		Run(func(args mock.Arguments) { deadLetters <- args.Get(3).([]byte) }).Return(nil).Once()
+
+	pool := NewProducerPool(mockProducer, 1, 4,
+		WithRetry(2, time.Millisecond, 2*time.Millisecond),
+		WithDeadLetterTopic("dlq"))
+	defer pool.Close()
+
+	assert.NoError(t, pool.Produce("test-topic", []byte("key"), []byte("value")))
+
+	var payload []byte
+	select {
+	case payload = <-deadLetters:
+	case <-time.After(time.Second):
+		t.Fatal("message was not sent to dead-letter topic")
+	}
+
+	var letter DeadLetter
+	assert.NoError(t, json.Unmarshal(payload, &letter))
+	assert.Equal(t, "test-topic", letter.OriginalTopic)
+	assert.Equal(t, "key", letter.Key)
+	assert.Equal(t, "value", letter.Payload)
+	assert.Equal(t, "leader not available", letter.Error)
+	assert.Equal(t, 3, letter.Attempts)
+	assert.False(t, letter.FailedAt.IsZero())
+}
+
+func TestProducerPool_ProduceContext_ContinuesTrace(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	otel.SetTracerProvider(provider)
+	t.Cleanup(func() { otel.SetTracerProvider(noop.NewTracerProvider()) })
+
+	produced := make(chan trace.SpanContext, 1)
+	mockProducer := &MockProducerInterface{}
+	mockProducer.On("Close").Return(nil).Maybe()
+	mockProducer.On("Produce", mock.Anything, "movie-views", []byte("7"), mock.Anything).
+		Run(func(args mock.Arguments) {
+			produced <- trace.SpanContextFromContext(args.Get(0).(context.Context))
+		}).Return(nil).Once()
+	pool := NewProducerPool(mockProducer, 1, 4)
+	defer pool.Close()
+
+	ctx, request := provider.Tracer("test").Start(context.Background(), "GET /movies/:id")
+	assert.NoError(t, pool.ProduceContext(ctx, "movie-views", []byte("7"), []byte("{}")))
+	request.End()
+
+	var producerSpan trace.SpanContext
+	select {
+	case producerSpan = <-produced:
+	case <-time.After(time.Second):
+		t.Fatal("message was not produced")
+	}
+	assert.Equal(t, request.SpanContext().TraceID(), producerSpan.TraceID())
+
+	assert.Eventually(t, func() bool { return len(recorder.Ended()) == 2 }, time.Second, 10*time.Millisecond)
+	span := recorder.Ended()[1]
+	assert.Equal(t, "publish movie-views", span.Name())
+	assert.Equal(t, trace.SpanKindProducer, span.SpanKind())
+	assert.Equal(t, request.SpanContext().SpanID(), span.Parent().SpanID())
+}
+
+// blockingProducer возвращает мок, воркер которого сообщает о начале отправки в started
+// и ждёт release, чтобы тест мог заполнить буфер пула
+func blockingProducer(started chan<- string, release <-chan struct{}) *MockProducerInterface {
+	mockProducer := &MockProducerInterface{}
+	mockProducer.On("Close").Return(nil).Maybe()
+	mockProducer.On("Produce", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			started <- args.String(1)
+			<-release
+		}).Return(nil)
+	return mockProducer
+}
+
+func TestProducerPool_Backpressure(t *testing.T) {
+	t.Run("drop_oldest", func(t *testing.T) {
+		started := make(chan string, 4)
+		release := make(chan struct{})
+		pool := NewProducerPool(blockingProducer(started, release), 1, 1, WithBackpressure(BackpressureDropOldest, 0))
+		defer pool.Close()
+
+		dropped := testutil.ToFloat64(KafkaProducerDroppedTotal.WithLabelValues("drop_oldest"))
+		assert.NoError(t, pool.Produce("first", nil, nil))
+		assert.Equal(t, "first", <-started)
+		assert.NoError(t, pool.Produce("second", nil, nil))
+		// Буфер занят вторым событием — его вытесняет третье
+		assert.NoError(t, pool.Produce("third", nil, nil))
+		assert.Equal(t, dropped+1, testutil.ToFloat64(KafkaProducerDroppedTotal.WithLabelValues("drop_oldest")))
+
+		close(release)
+		assert.Equal(t, "third", <-started)
+	})
+
+	t.Run("block until space", func(t *testing.T) {
+		started := make(chan string, 4)
+		release := make(chan struct{})
+		pool := NewProducerPool(blockingProducer(started, release), 1, 1, WithBackpressure(BackpressureBlock, 0))
+		defer pool.Close()
+
+		assert.NoError(t, pool.Produce("first", nil, nil))
+		assert.Equal(t, "first", <-started)
+		assert.NoError(t, pool.Produce("second", nil, nil))
+
+		queued := make(chan error, 1)
+		go func() { queued <- pool.Produce("third", nil, nil) }()
+		select {
+		case err := <-queued:
+			t.Fatalf("produce returned %v while the buffer was full", err)
+		case <-time.After(50 * time.Millisecond):
+		}
+		close(release)
+		assert.NoError(t, <-queued)
+	})
+
+	t.Run("block timeout", func(t *testing.T) {
+		started := make(chan string, 4)
+		release := make(chan struct{})
+		pool := NewProducerPool(blockingProducer(started, release), 1, 1, WithBackpressure(BackpressureBlock, 20*time.Millisecond))
+		defer pool.Close()
+		defer close(release)
+
+		dropped := testutil.ToFloat64(KafkaProducerDroppedTotal.WithLabelValues("block_timeout"))
+		assert.NoError(t, pool.Produce("first", nil, nil))
+		assert.Equal(t, "first", <-started)
+		assert.NoError(t, pool.Produce("second", nil, nil))
+		assert.ErrorIs(t, pool.Produce("third", nil, nil), ErrBufferFull)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		assert.ErrorIs(t, pool.ProduceContext(ctx, "fourth", nil, nil), context.Canceled)
+		assert.Equal(t, dropped+2, testutil.ToFloat64(KafkaProducerDroppedTotal.WithLabelValues("block_timeout")))
+	})
+}
+
+func TestParseBackpressurePolicy(t *testing.T) {
+	policy, ok := ParseBackpressurePolicy(" Drop_Oldest ")
+	assert.True(t, ok)
+	assert.Equal(t, BackpressureDropOldest, policy)
+
+	_, ok = ParseBackpressurePolicy("wait")
+	assert.False(t, ok)
+}
+
+func TestProducerPool_Flush(t *testing.T) {
+	started := make(chan string, 4)
+	release := make(chan struct{})
+	pool := NewProducerPool(blockingProducer(started, release), 1, 4)
+
+	assert.NoError(t, pool.Produce("first", nil, nil))
+	assert.NoError(t, pool.Produce("second", nil, nil))
+
+	// Пока воркер занят, Flush не дожидается очереди
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, pool.Flush(ctx), context.DeadlineExceeded)
+
+	close(release)
+	assert.NoError(t, pool.Flush(context.Background()))
+	assert.Len(t, started, 2)
+
+	pool.Close()
+	assert.ErrorIs(t, pool.Produce("late", nil, nil), ErrPoolClosed)
+}
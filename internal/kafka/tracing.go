@@ -0,0 +1,61 @@
+package kafka
+
+import (
+	"context"
+
+	"github.com/segmentio/kafka-go"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracer — трассировщик пакета из текущего глобального провайдера
+func tracer() trace.Tracer { return otel.Tracer("cinematique/internal/kafka") }
+
+// startProduceSpan открывает span отправки события в Kafka. Пул отправляет события
+// асинхронно, поэтому span продолжает трассу запроса через сохранённый event.Parent
+func startProduceSpan(event KafkaEvent) (context.Context, trace.Span) {
+	ctx := trace.ContextWithSpanContext(context.Background(), event.Parent)
+	return tracer().Start(ctx, "publish "+event.Topic,
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(
+			attribute.String("messaging.system", "kafka"),
+			attribute.String("messaging.operation.type", "publish"),
+			attribute.String("messaging.destination.name", event.Topic),
+			attribute.String("messaging.kafka.message.key", string(event.Key)),
+		),
+	)
+}
+
+// headerCarrier позволяет записать контекст трассировки в заголовки сообщения Kafka,
+// чтобы консьюмеры могли продолжить трассу
+type headerCarrier struct {
+	headers *[]kafka.Header
+}
+
+func (c headerCarrier) Get(key string) string {
+	for _, h := range *c.headers {
+		if h.Key == key {
+			return string(h.Value)
+		}
+	}
+	return ""
+}
+
+func (c headerCarrier) Set(key, value string) {
+	for i, h := range *c.headers {
+		if h.Key == key {
+			(*c.headers)[i].Value = []byte(value)
+			return
+		}
+	}
+	*c.headers = append(*c.headers, kafka.Header{Key: key, Value: []byte(value)})
+}
+
+func (c headerCarrier) Keys() []string {
+	keys := make([]string, 0, len(*c.headers))
+	for _, h := range *c.headers {
+		keys = append(keys, h.Key)
+	}
+	return keys
+}
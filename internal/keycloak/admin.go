@@ -0,0 +1,165 @@
+package keycloak
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// adminPageSize — сколько записей запрашивается у Admin API за один раз
+const adminPageSize = 100
+
+// ErrNoClientSecret возвращается при обращении к Admin API без секрета клиента
+var ErrNoClientSecret = errors.New("keycloak client secret is not configured")
+
+// RealmUser — включённый пользователь realm с локальной ролью, вычисленной по его ролям realm
+type RealmUser struct {
+	ID        string
+	Username  string
+	Email     string
+	LocalRole string
+}
+
+// ListUsers возвращает всех включённых пользователей realm через Admin API. Клиенту нужна
+// сервисная учётная запись (ClientSecret) с ролями view-users из realm-management
+func (c *Client) ListUsers(ctx context.Context) ([]RealmUser, error) {
+	if c.config.ClientSecret == "" {
+		return nil, ErrNoClientSecret
+	}
+	token, err := c.serviceAccountToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var users []RealmUser
+	index := make(map[string]int)
+	for first := 0; ; first += adminPageSize {
+		var page []struct {
+			ID       string `json:"id"`
+			Username string `json:"username"`
+			Email    string `json:"email"`
+			Enabled  bool   `json:"enabled"`
+		}
+		if err := c.adminGet(ctx, token, "/users", first, &page); err != nil {
+			return nil, fmt.Errorf("listing users: %w", err)
+		}
+		for _, user := range page {
+			if !user.Enabled {
+				continue
+			}
+			index[user.ID] = len(users)
+			users = append(users, RealmUser{ID: user.ID, Username: user.Username, Email: user.Email})
+		}
+		if len(page) < adminPageSize {
+			break
+		}
+	}
+
+	// Роли читаются по ролям, а не по пользователям: запросов столько, сколько ролей с локальным
+	// аналогом. Роли перебираются от старшей к младшей, поэтому LocalRole выбирает старшую
+	roles := make([][]string, len(users))
+	for _, name := range localRoleNames {
+		members, err := c.roleMembers(ctx, token, name.keycloak)
+		if err != nil {
+			return nil, fmt.Errorf("listing members of role %s: %w", name.keycloak, err)
+		}
+		for _, id := range members {
+			if i, ok := index[id]; ok {
+				roles[i] = append(roles[i], name.keycloak)
+			}
+		}
+	}
+	for i := range users {
+		users[i].LocalRole = LocalRole(roles[i])
+	}
+	return users, nil
+}
+
+// roleMembers возвращает ID пользователей с ролью realm role. Роли, которой нет в realm, соответствует пустой список
+func (c *Client) roleMembers(ctx context.Context, token, role string) ([]string, error) {
+	var ids []string
+	for first := 0; ; first += adminPageSize {
+		var page []struct {
+			ID string `json:"id"`
+		}
+		err := c.adminGet(ctx, token, "/roles/"+url.PathEscape(role)+"/users", first, &page)
+		if errors.Is(err, errAdminNotFound) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		for _, user := range page {
+			ids = append(ids, user.ID)
+		}
+		if len(page) < adminPageSize {
+			return ids, nil
+		}
+	}
+}
+
+// errAdminNotFound — Admin API ответил 404
+var errAdminNotFound = errors.New("not found")
+
+// adminGet читает страницу Admin API realm, начиная с записи first, в out
+func (c *Client) adminGet(ctx context.Context, token, path string, first int, out interface{}) error {
+	query := url.Values{
+		"first": {strconv.Itoa(first)},
+		"max":   {strconv.Itoa(adminPageSize)},
+	}
+	endpoint := fmt.Sprintf("%s/admin/realms/%s%s?%s", c.config.ServerURL, url.PathEscape(c.config.Realm), path, query.Encode())
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return errAdminNotFound
+	case resp.StatusCode != http.StatusOK:
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// serviceAccountToken получает токен сервисной учётной записи клиента (client credentials)
+func (c *Client) serviceAccountToken(ctx context.Context) (string, error) {
+	endpoint := fmt.Sprintf("%s/realms/%s/protocol/openid-connect/token", c.config.ServerURL, url.PathEscape(c.config.Realm))
+	form := url.Values{
+		"grant_type":    {"client_credentials"},
+		"client_id":     {c.config.ClientID},
+		"client_secret": {c.config.ClientSecret},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("requesting service account token: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("requesting service account token: unexpected status %d", resp.StatusCode)
+	}
+	var body struct {
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", fmt.Errorf("decoding service account token: %w", err)
+	}
+	return body.AccessToken, nil
+}
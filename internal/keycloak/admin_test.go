@@ -0,0 +1,56 @@
+package keycloak
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_ListUsers(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/realms/cinema/protocol/openid-connect/token", func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "client_credentials", r.PostForm.Get("grant_type"))
+		assert.Equal(t, "secret", r.PostForm.Get("client_secret"))
+		json.NewEncoder(w).Encode(map[string]string{"access_token": "service-token"})
+	})
+	mux.HandleFunc("/admin/realms/cinema/users", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer service-token", r.Header.Get("Authorization"))
+		json.NewEncoder(w).Encode([]map[string]interface{}{
+			{"id": "u1", "username": "alice", "email": "alice@example.com", "enabled": true},
+			{"id": "u2", "username": "bob", "email": "bob@example.com", "enabled": true},
+			{"id": "u3", "username": "carol", "email": "carol@example.com", "enabled": false},
+		})
+	})
+	mux.HandleFunc("/admin/realms/cinema/roles/", func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/admin/realms/cinema/roles/cinematique-admin/users":
+			json.NewEncoder(w).Encode([]map[string]string{{"id": "u1"}})
+		case "/admin/realms/cinema/roles/moderator/users":
+			json.NewEncoder(w).Encode([]map[string]string{{"id": "u1"}, {"id": "u2"}, {"id": "u3"}})
+		default:
+			// Остальных ролей в realm нет
+			http.NotFound(w, r)
+		}
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client := NewClient(Config{ServerURL: server.URL, Realm: "cinema", ClientID: "cinematique", ClientSecret: "secret"})
+	users, err := client.ListUsers(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []RealmUser{
+		{ID: "u1", Username: "alice", Email: "alice@example.com", LocalRole: "admin"},
+		{ID: "u2", Username: "bob", Email: "bob@example.com", LocalRole: "moderator"},
+	}, users)
+}
+
+func TestClient_ListUsersWithoutSecret(t *testing.T) {
+	_, err := NewClient(Config{ServerURL: "http://keycloak", Realm: "cinema"}).ListUsers(context.Background())
+	assert.ErrorIs(t, err, ErrNoClientSecret)
+}
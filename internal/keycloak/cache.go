@@ -0,0 +1,93 @@
+package keycloak
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// maxCachedVerifications ограничивает размер кэша проверок: при переполнении
+// сначала удаляются устаревшие записи, а если их нет — весь кэш
+const maxCachedVerifications = 10000
+
+// verificationCache хранит результаты успешной проверки токенов. Повторные запросы с тем же
+// токеном не разбирают подпись заново и продолжают работать, пока Keycloak недоступен для
+// обновления ключей. Запись живёт не дольше ttl и не дольше срока действия токена
+type verificationCache struct {
+	ttl     time.Duration
+	mu      sync.Mutex
+	entries map[string]verificationEntry
+	now     func() time.Time
+}
+
+type verificationEntry struct {
+	claims    KeycloakClaims
+	userInfo  UserInfo
+	expiresAt time.Time
+}
+
+// newVerificationCache создаёт кэш; при ttl <= 0 возвращает nil, и кэширование отключено
+func newVerificationCache(ttl time.Duration) *verificationCache {
+	if ttl <= 0 {
+		return nil
+	}
+	return &verificationCache{ttl: ttl, entries: make(map[string]verificationEntry), now: time.Now}
+}
+
+// verificationKey строит ключ кэша из хеша токена и опций, влияющих на результат проверки.
+// Сам токен в памяти не хранится
+func verificationKey(tokenString string, options ValidationOptions) string {
+	sum := sha256.Sum256([]byte(tokenString))
+	return fmt.Sprintf("%s|%t|%t|%t", hex.EncodeToString(sum[:]), options.ValidateAudience, options.ValidateIssuer, options.AllowExpired)
+}
+
+// get возвращает копии сохранённых claims и UserInfo, если запись ещё действительна
+func (c *verificationCache) get(key string) (*KeycloakClaims, *UserInfo, bool) {
+	if c == nil {
+		return nil, nil, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok {
+		return nil, nil, false
+	}
+	if !c.now().Before(entry.expiresAt) {
+		delete(c.entries, key)
+		return nil, nil, false
+	}
+	claims, userInfo := entry.claims, entry.userInfo
+	return &claims, &userInfo, true
+}
+
+// put сохраняет результат проверки; tokenExpiresAt — срок действия токена, нулевое значение его не учитывает
+func (c *verificationCache) put(key string, claims *KeycloakClaims, userInfo *UserInfo, tokenExpiresAt time.Time) {
+	if c == nil {
+		return
+	}
+	now := c.now()
+	expiresAt := now.Add(c.ttl)
+	if !tokenExpiresAt.IsZero() && tokenExpiresAt.Before(expiresAt) {
+		expiresAt = tokenExpiresAt
+	}
+	if !now.Before(expiresAt) {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.entries) >= maxCachedVerifications {
+		for k, entry := range c.entries {
+			if !now.Before(entry.expiresAt) {
+				delete(c.entries, k)
+			}
+		}
+		if len(c.entries) >= maxCachedVerifications {
+			c.entries = make(map[string]verificationEntry)
+		}
+	}
+	c.entries[key] = verificationEntry{claims: *claims, userInfo: *userInfo, expiresAt: expiresAt}
+}
@@ -0,0 +1,133 @@
+package keycloak
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/lestrrat-go/jwx/v2/jwa"
+	"github.com/lestrrat-go/jwx/v2/jwk"
+	"github.com/lestrrat-go/jwx/v2/jwt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestKeys создаёт ключ подписи RS256 и JWKS с его открытой частью
+func newTestKeys(t *testing.T) (jwk.Key, []byte) {
+	t.Helper()
+	raw, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	private, err := jwk.FromRaw(raw)
+	require.NoError(t, err)
+	require.NoError(t, private.Set(jwk.KeyIDKey, "test-key"))
+	require.NoError(t, private.Set(jwk.AlgorithmKey, jwa.RS256))
+	public, err := jwk.PublicKeyOf(private)
+	require.NoError(t, err)
+
+	set := jwk.NewSet()
+	require.NoError(t, set.AddKey(public))
+	jwks, err := json.Marshal(set)
+	require.NoError(t, err)
+	return private, jwks
+}
+
+// signTestToken выпускает токен realm test с заданным сроком действия
+func signTestToken(t *testing.T, key jwk.Key, issuer string, expiresAt time.Time) string {
+	t.Helper()
+	token, err := jwt.NewBuilder().
+		Issuer(issuer).
+		Subject("user-1").
+		Audience([]string{"api"}).
+		Expiration(expiresAt).
+		Claim("preferred_username", "neo").
+		Build()
+	require.NoError(t, err)
+	signed, err := jwt.Sign(token, jwt.WithKey(jwa.RS256, key))
+	require.NoError(t, err)
+	return string(signed)
+}
+
+func TestVerificationCache(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	cache := newVerificationCache(30 * time.Second)
+	cache.now = func() time.Time { return now }
+	claims, userInfo := &KeycloakClaims{Sub: "user-1"}, &UserInfo{ID: "user-1"}
+
+	cache.put("token", claims, userInfo, time.Time{})
+	gotClaims, gotInfo, ok := cache.get("token")
+	require.True(t, ok)
+	assert.Equal(t, "user-1", gotClaims.Sub)
+	assert.Equal(t, "user-1", gotInfo.ID)
+
+	// Запись не переживает ни TTL кэша, ни срок действия токена
+	now = now.Add(31 * time.Second)
+	_, _, ok = cache.get("token")
+	assert.False(t, ok)
+
+	cache.put("short", claims, userInfo, now.Add(5*time.Second))
+	now = now.Add(6 * time.Second)
+	_, _, ok = cache.get("short")
+	assert.False(t, ok)
+
+	// Без TTL кэш отключён
+	disabled := newVerificationCache(0)
+	disabled.put("token", claims, userInfo, time.Time{})
+	_, _, ok = disabled.get("token")
+	assert.False(t, ok)
+}
+
+func TestClient_ValidateTokenWithClaims(t *testing.T) {
+	key, jwks := newTestKeys(t)
+	var requests atomic.Int32
+	available := atomic.Bool{}
+	available.Store(true)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if !available.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(jwks)
+	}))
+	defer server.Close()
+
+	client := NewClient(Config{ServerURL: server.URL, Realm: "test", ClientID: "api", CacheTTL: time.Minute})
+	require.NoError(t, client.Initialize())
+	token := signTestToken(t, key, server.URL+"/realms/test", time.Now().Add(time.Hour))
+
+	claims, userInfo, err := client.ValidateTokenWithClaims(token, DefaultValidationOptions())
+	require.NoError(t, err)
+	assert.Equal(t, "user-1", claims.Sub)
+	assert.Equal(t, "neo", userInfo.Username)
+
+	// Повторная проверка берётся из кэша, даже если Keycloak перестал отвечать
+	available.Store(false)
+	client.keysMu.Lock()
+	client.keySet = nil
+	client.keysMu.Unlock()
+	_, userInfo, err = client.ValidateTokenWithClaims(token, DefaultValidationOptions())
+	require.NoError(t, err)
+	assert.Equal(t, "neo", userInfo.Username)
+
+	// Другие опции проверки — другой ключ кэша: без ключей токен не проверить,
+	// а повторный запрос JWKS сразу после предыдущего не выполняется
+	before := requests.Load()
+	_, _, err = client.ValidateTokenWithClaims(token, ValidationOptions{ValidateIssuer: true})
+	assert.ErrorIs(t, err, ErrJWKSNotFetched)
+	assert.Equal(t, before, requests.Load())
+
+	// Истёкший токен не кэшируется и отклоняется
+	available.Store(true)
+	client = NewClient(Config{ServerURL: server.URL, Realm: "test", ClientID: "api", CacheTTL: time.Minute})
+	require.NoError(t, client.Initialize())
+	expired := signTestToken(t, key, server.URL+"/realms/test", time.Now().Add(-time.Minute))
+	_, _, err = client.ValidateTokenWithClaims(expired, DefaultValidationOptions())
+	assert.Error(t, err)
+	assert.Empty(t, client.cache.entries)
+}
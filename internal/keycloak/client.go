@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/lestrrat-go/jwx/v2/jwk"
@@ -16,13 +17,45 @@ type Config struct {
 	ServerURL string `json:"server_url"`
 	Realm     string `json:"realm"`
 	ClientID  string `json:"client_id"`
+	// ClientSecret — секрет сервисной учётной записи клиента для Admin API (список пользователей);
+	// для проверки токенов не нужен
+	ClientSecret string `json:"-"`
+
+	Timeout    time.Duration `json:"timeout"`     // таймаут одного запроса к Keycloak; 0 — DefaultTimeout
+	MaxRetries int           `json:"max_retries"` // повторы запроса ключей после первой неудачи; отрицательное — без повторов
+	RetryDelay time.Duration `json:"retry_delay"` // задержка перед первым повтором, дальше растёт вдвое; 0 — DefaultRetryDelay
+	CacheTTL   time.Duration `json:"cache_ttl"`   // сколько помнить успешную проверку токена; 0 — без кэша
+}
+
+// Значения по умолчанию для пустых полей Config
+const (
+	DefaultTimeout    = 10 * time.Second
+	DefaultRetryDelay = 200 * time.Millisecond
+)
+
+// withDefaults заполняет незаданные таймаут и задержку повторов
+func (c Config) withDefaults() Config {
+	if c.Timeout <= 0 {
+		c.Timeout = DefaultTimeout
+	}
+	if c.RetryDelay <= 0 {
+		c.RetryDelay = DefaultRetryDelay
+	}
+	if c.MaxRetries < 0 {
+		c.MaxRetries = 0
+	}
+	return c
 }
 
 // Client представляет клиент для работы с Keycloak
 type Client struct {
 	config     Config
-	keySet     jwk.Set
 	httpClient *http.Client
+	cache      *verificationCache // nil, если CacheTTL не задан
+
+	keysMu        sync.RWMutex
+	keySet        jwk.Set
+	keysFetchedAt time.Time // момент последней попытки получить ключи; ограничивает частоту обновления
 }
 
 // KeycloakClaims представляет claims из Keycloak JWT токена
@@ -45,36 +78,63 @@ type KeycloakClaims struct {
 
 // NewClient создает новый клиент Keycloak
 func NewClient(config Config) *Client {
+	config = config.withDefaults()
 	return &Client{
 		config: config,
 		httpClient: &http.Client{
-			Timeout: 10 * time.Second,
+			Timeout: config.Timeout,
 		},
+		cache: newVerificationCache(config.CacheTTL),
 	}
 }
 
 // Initialize инициализирует клиент, получая публичные ключи от Keycloak
 func (c *Client) Initialize() error {
-	return c.fetchJWKS()
+	return c.fetchJWKS(context.Background())
 }
 
-// fetchJWKS получает JWKS от Keycloak
-func (c *Client) fetchJWKS() error {
+// fetchJWKS получает JWKS от Keycloak. Неудачный запрос повторяется до MaxRetries раз
+// с растущей задержкой и случайным разбросом, чтобы экземпляры сервиса после сбоя
+// Keycloak не обращались к нему одновременно
+func (c *Client) fetchJWKS(ctx context.Context) error {
 	jwksURL := fmt.Sprintf("%s/realms/%s/protocol/openid_connect/certs",
 		c.config.ServerURL, c.config.Realm)
 
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
+	c.keysMu.Lock()
+	c.keysFetchedAt = time.Now()
+	c.keysMu.Unlock()
+
+	var keySet jwk.Set
+	err := retry(ctx, c.config.MaxRetries, c.config.RetryDelay, func(ctx context.Context) error {
+		ctx, cancel := context.WithTimeout(ctx, c.config.Timeout)
+		defer cancel()
 
-	keySet, err := jwk.Fetch(ctx, jwksURL)
+		fetched, err := jwk.Fetch(ctx, jwksURL, jwk.WithHTTPClient(c.httpClient))
+		if err != nil {
+			jwksFetchesTotal.WithLabelValues("failure").Inc()
+			return err
+		}
+		jwksFetchesTotal.WithLabelValues("success").Inc()
+		keySet = fetched
+		return nil
+	})
 	if err != nil {
 		return fmt.Errorf("failed to fetch JWKS: %w", err)
 	}
 
+	c.keysMu.Lock()
 	c.keySet = keySet
+	c.keysMu.Unlock()
 	return nil
 }
 
+// keys возвращает текущий набор ключей; nil, если ключи ещё не получены
+func (c *Client) keys() jwk.Set {
+	c.keysMu.RLock()
+	defer c.keysMu.RUnlock()
+	return c.keySet
+}
+
 // ValidateToken проверяет Keycloak JWT токен с стандартными опциями
 func (c *Client) ValidateToken(tokenString string) (*KeycloakClaims, error) {
 	claims, _, err := c.ValidateTokenWithClaims(tokenString, DefaultValidationOptions())
@@ -128,19 +188,30 @@ func (c *Client) HasRole(claims *KeycloakClaims, role string) bool {
 
 // MapKeycloakRoleToLocal маппит роли Keycloak на локальные роли
 func (c *Client) MapKeycloakRoleToLocal(claims *KeycloakClaims) string {
-	roles := c.GetUserRoles(claims)
+	return LocalRole(c.GetUserRoles(claims))
+}
+
+// localRoleNames — роли Keycloak, у которых есть локальный аналог, от старшей к младшей
+var localRoleNames = []struct{ keycloak, local string }{
+	{"admin", "admin"},
+	{"administrator", "admin"},
+	{"cinematique-admin", "admin"},
+	{"moderator", "moderator"},
+	{"cinematique-moderator", "moderator"},
+	{"user", "user"},
+	{"cinematique-user", "user"},
+}
 
-	// Проверяем роли в порядке приоритета
+// LocalRole возвращает локальную роль по первой роли Keycloak из roles, у которой есть аналог.
+// Без таких ролей пользователь получает роль user
+func LocalRole(roles []string) string {
 	for _, role := range roles {
-		switch role {
-		case "admin", "administrator", "cinematique-admin":
-			return "admin"
-		case "user", "cinematique-user":
-			return "user"
+		for _, name := range localRoleNames {
+			if role == name.keycloak {
+				return name.local
+			}
 		}
 	}
-
-	// По умолчанию возвращаем роль пользователя
 	return "user"
 }
 
@@ -83,6 +83,17 @@ func TestMapKeycloakRoleToLocal(t *testing.T) {
 			},
 			expected: "admin",
 		},
+		{
+			name: "moderator role",
+			claims: &KeycloakClaims{
+				RealmAccess: struct {
+					Roles []string `json:"roles"`
+				}{
+					Roles: []string{"cinematique-moderator"},
+				},
+			},
+			expected: "moderator",
+		},
 		{
 			name: "user role",
 			claims: &KeycloakClaims{
@@ -0,0 +1,54 @@
+package keycloak
+
+import (
+	"errors"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	tokenVerificationDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "keycloak_token_verification_duration_seconds",
+			Help:    "Duration of Keycloak token verification by result (valid, invalid, cached).",
+			Buckets: prometheus.DefBuckets,
+		},
+		[]string{"result"},
+	)
+	tokenVerificationFailuresTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "keycloak_token_verification_failures_total",
+			Help: "Total number of rejected Keycloak tokens by reason.",
+		},
+		[]string{"reason"},
+	)
+	jwksFetchesTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "keycloak_jwks_fetches_total",
+			Help: "Total number of Keycloak JWKS fetch attempts by result (success, failure).",
+		},
+		[]string{"result"},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(tokenVerificationDuration, tokenVerificationFailuresTotal, jwksFetchesTotal)
+}
+
+// failureReason возвращает метку причины отказа для tokenVerificationFailuresTotal
+func failureReason(err error) string {
+	switch {
+	case errors.Is(err, ErrTokenExpired):
+		return "expired"
+	case errors.Is(err, ErrInvalidIssuer):
+		return "issuer"
+	case errors.Is(err, ErrInvalidAudience):
+		return "audience"
+	case errors.Is(err, ErrJWKSNotFetched):
+		return "keys_unavailable"
+	case errors.Is(err, ErrTokenInvalid):
+		return "invalid"
+	default:
+		return "other"
+	}
+}
@@ -0,0 +1,33 @@
+package keycloak
+
+import (
+	"context"
+	"math/rand"
+	"time"
+)
+
+// retry вызывает fn, пока она не завершится успешно, но не больше 1+retries раз.
+// Перед k-м повтором ждёт случайное время от половины до полной задержки delay*2^(k-1):
+// разброс не даёт экземплярам сервиса повторять запросы синхронно. Возвращает последнюю
+// ошибку fn или ошибку контекста, если он отменён во время ожидания
+func retry(ctx context.Context, retries int, delay time.Duration, fn func(context.Context) error) error {
+	var err error
+	for attempt := 0; ; attempt++ {
+		if err = fn(ctx); err == nil {
+			return nil
+		}
+		if attempt >= retries {
+			return err
+		}
+
+		backoff := delay << attempt
+		wait := backoff/2 + time.Duration(rand.Int63n(int64(backoff/2)+1))
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
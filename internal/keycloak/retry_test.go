@@ -0,0 +1,73 @@
+package keycloak
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetry(t *testing.T) {
+	errUnavailable := errors.New("unavailable")
+
+	calls := 0
+	err := retry(context.Background(), 3, time.Millisecond, func(context.Context) error {
+		calls++
+		if calls < 3 {
+			return errUnavailable
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 3, calls)
+
+	calls = 0
+	err = retry(context.Background(), 2, time.Millisecond, func(context.Context) error {
+		calls++
+		return errUnavailable
+	})
+	assert.ErrorIs(t, err, errUnavailable)
+	assert.Equal(t, 3, calls, "first attempt plus two retries")
+
+	// Отмена контекста прерывает ожидание перед повтором
+	ctx, cancel := context.WithCancel(context.Background())
+	calls = 0
+	err = retry(ctx, 5, time.Hour, func(context.Context) error {
+		calls++
+		cancel()
+		return errUnavailable
+	})
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 1, calls)
+}
+
+func TestClient_InitializeRetries(t *testing.T) {
+	_, jwks := newTestKeys(t)
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(jwks)
+	}))
+	defer server.Close()
+
+	client := NewClient(Config{ServerURL: server.URL, Realm: "test", ClientID: "api", MaxRetries: 2, RetryDelay: time.Millisecond})
+	require.NoError(t, client.Initialize())
+	assert.EqualValues(t, 3, requests.Load())
+	assert.NotNil(t, client.keys())
+
+	// Без повторов первая же ошибка Keycloak возвращается сразу
+	requests.Store(0)
+	client = NewClient(Config{ServerURL: server.URL, Realm: "test", ClientID: "api", MaxRetries: -1})
+	assert.Error(t, client.Initialize())
+	assert.EqualValues(t, 1, requests.Load())
+}
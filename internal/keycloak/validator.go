@@ -3,8 +3,11 @@ package keycloak
 import (
 	"context"
 	"fmt"
+	"log"
 	"time"
 
+	"github.com/lestrrat-go/jwx/v2/jwk"
+	"github.com/lestrrat-go/jwx/v2/jws"
 	"github.com/lestrrat-go/jwx/v2/jwt"
 )
 
@@ -46,14 +49,75 @@ func (c *Client) ValidateTokenWithOptions(tokenString string, options Validation
 	return userInfo, nil
 }
 
-// ValidateTokenWithClaims валидирует токен и возвращает как claims, так и UserInfo
+// keysRefreshInterval — как часто можно запрашивать ключи заново, встретив токен с неизвестным ключом
+const keysRefreshInterval = 30 * time.Second
+
+// ValidateTokenWithClaims валидирует токен и возвращает как claims, так и UserInfo.
+// Успешный результат кэшируется на Config.CacheTTL
 func (c *Client) ValidateTokenWithClaims(tokenString string, options ValidationOptions) (*KeycloakClaims, *UserInfo, error) {
-	if c.keySet == nil {
+	start := time.Now()
+	key := verificationKey(tokenString, options)
+	if claims, userInfo, ok := c.cache.get(key); ok {
+		tokenVerificationDuration.WithLabelValues("cached").Observe(time.Since(start).Seconds())
+		return claims, userInfo, nil
+	}
+
+	claims, userInfo, err := c.verifyToken(tokenString, options)
+	if err != nil {
+		tokenVerificationDuration.WithLabelValues("invalid").Observe(time.Since(start).Seconds())
+		tokenVerificationFailuresTotal.WithLabelValues(failureReason(err)).Inc()
+		return nil, nil, err
+	}
+	var expiresAt time.Time
+	if !options.AllowExpired && claims.Exp > 0 {
+		expiresAt = time.Unix(claims.Exp, 0)
+	}
+	c.cache.put(key, claims, userInfo, expiresAt)
+	tokenVerificationDuration.WithLabelValues("valid").Observe(time.Since(start).Seconds())
+	return claims, userInfo, nil
+}
+
+// keySetFor возвращает ключи для проверки токена. Если ключи не получены при запуске
+// или в них нет ключа токена (Keycloak сменил ключи), запрашивает их заново, но не чаще
+// keysRefreshInterval: поток поддельных токенов не должен превращаться в поток запросов к Keycloak
+func (c *Client) keySetFor(tokenString string) jwk.Set {
+	keySet := c.keys()
+	if keySet != nil {
+		msg, err := jws.Parse([]byte(tokenString))
+		if err != nil || len(msg.Signatures()) == 0 {
+			return keySet
+		}
+		kid := msg.Signatures()[0].ProtectedHeaders().KeyID()
+		if _, found := keySet.LookupKeyID(kid); found || kid == "" {
+			return keySet
+		}
+	}
+
+	c.keysMu.RLock()
+	recent := time.Since(c.keysFetchedAt) < keysRefreshInterval
+	c.keysMu.RUnlock()
+	if recent {
+		return keySet
+	}
+
+	// Запрос идёт в ходе проверки токена, поэтому все повторы укладываются в один таймаут
+	ctx, cancel := context.WithTimeout(context.Background(), c.config.Timeout)
+	defer cancel()
+	if err := c.fetchJWKS(ctx); err != nil {
+		log.Printf("Keycloak: failed to refresh JWKS: %v", err)
+	}
+	return c.keys()
+}
+
+// verifyToken проверяет подпись и claims токена
+func (c *Client) verifyToken(tokenString string, options ValidationOptions) (*KeycloakClaims, *UserInfo, error) {
+	keySet := c.keySetFor(tokenString)
+	if keySet == nil {
 		return nil, nil, ErrJWKSNotFetched
 	}
 
 	// Парсим токен с использованием JWKS
-	token, err := jwt.Parse([]byte(tokenString), jwt.WithKeySet(c.keySet))
+	token, err := jwt.Parse([]byte(tokenString), jwt.WithKeySet(keySet))
 	if err != nil {
 		return nil, nil, fmt.Errorf("%w: %v", ErrTokenInvalid, err)
 	}
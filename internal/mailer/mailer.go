@@ -0,0 +1,37 @@
+// Package mailer отправляет письма пользователям: ссылки сброса пароля и другие
+// уведомления учётной записи. Сервисы зависят от интерфейса Mailer, а SMTPMailer
+// подключается в cmd только при заданном SMTP-сервере
+package mailer
+
+import (
+	"context"
+	"errors"
+	"strings"
+)
+
+// ErrInvalidHeader возвращается для адреса или темы с переводом строки:
+// такое значение позволило бы дописать в письмо собственные заголовки
+var ErrInvalidHeader = errors.New("mail header contains a line break")
+
+// Message — письмо с текстом без разметки
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Validate проверяет, что адрес получателя задан, а заголовки не содержат переводов строки
+func (m Message) Validate() error {
+	if strings.TrimSpace(m.To) == "" {
+		return errors.New("mail recipient is empty")
+	}
+	if strings.ContainsAny(m.To, "\r\n") || strings.ContainsAny(m.Subject, "\r\n") {
+		return ErrInvalidHeader
+	}
+	return nil
+}
+
+// Mailer отправляет письма
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
@@ -0,0 +1,23 @@
+package mailer
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// MockMailer реализует Mailer для тестов
+type MockMailer struct {
+	mock.Mock
+}
+
+// NewMockMailer создаёт новый экземпляр MockMailer
+func NewMockMailer() *MockMailer {
+	return &MockMailer{}
+}
+
+// Send реализует мок-метод отправки письма
+func (m *MockMailer) Send(ctx context.Context, msg Message) error {
+	args := m.Called(ctx, msg)
+	return args.Error(0)
+}
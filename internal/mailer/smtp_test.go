@@ -0,0 +1,91 @@
+package mailer
+
+import (
+	"context"
+	"mime"
+	"net"
+	"net/textproto"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSMTPServer принимает одно письмо без TLS и аутентификации и возвращает
+// полученные команды и текст письма
+func fakeSMTPServer(t *testing.T) (host, port string, received <-chan []string) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { ln.Close() })
+
+	out := make(chan []string, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		tp := textproto.NewConn(conn)
+		var lines []string
+		tp.PrintfLine("220 localhost ESMTP")
+		for {
+			line, err := tp.ReadLine()
+			if err != nil {
+				out <- lines
+				return
+			}
+			lines = append(lines, line)
+			switch {
+			case strings.HasPrefix(line, "EHLO"):
+				tp.PrintfLine("250 localhost")
+			case line == "DATA":
+				tp.PrintfLine("354 go ahead")
+				body, _ := tp.ReadDotLines()
+				lines = append(lines, body...)
+				tp.PrintfLine("250 queued")
+			case line == "QUIT":
+				tp.PrintfLine("221 bye")
+				out <- lines
+				return
+			default:
+				tp.PrintfLine("250 OK")
+			}
+		}
+	}()
+	host, port, _ = net.SplitHostPort(ln.Addr().String())
+	return host, port, out
+}
+
+func TestSMTPMailer_Send(t *testing.T) {
+	host, port, received := fakeSMTPServer(t)
+	m := NewSMTP(SMTPConfig{Host: host, Port: port, From: "Cinematique <no-reply@cinematique.local>"})
+	m.now = func() time.Time { return time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC) }
+
+	err := m.Send(context.Background(), Message{To: "neo@zion.io", Subject: "Сброс пароля", Body: "line one\nline two"})
+	require.NoError(t, err)
+
+	lines := <-received
+	assert.Contains(t, lines, "MAIL FROM:<no-reply@cinematique.local>")
+	assert.Contains(t, lines, "RCPT TO:<neo@zion.io>")
+	assert.Contains(t, lines, "To: neo@zion.io")
+	var subject string
+	for _, line := range lines {
+		if encoded, ok := strings.CutPrefix(line, "Subject: "); ok {
+			subject, err = new(mime.WordDecoder).DecodeHeader(encoded)
+			require.NoError(t, err)
+		}
+	}
+	assert.Equal(t, "Сброс пароля", subject)
+	assert.Contains(t, lines, "Date: Wed, 01 May 2024 12:00:00 +0000")
+	assert.Contains(t, lines, "line two")
+}
+
+func TestSMTPMailer_SendRejectsHeaderInjection(t *testing.T) {
+	m := NewSMTP(SMTPConfig{Host: "127.0.0.1", Port: "1", From: "no-reply@cinematique.local"})
+
+	err := m.Send(context.Background(), Message{To: "neo@zion.io\r\nBcc: everyone@zion.io", Subject: "hi"})
+	assert.ErrorIs(t, err, ErrInvalidHeader)
+}
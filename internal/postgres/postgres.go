@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"strconv"
 	"time"
 
 	"cinematique/internal/config"
@@ -15,6 +16,15 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 )
 
+// Настройки пула подключений по умолчанию. Простаивающих соединений держится столько же,
+// сколько открытых: иначе при всплесках нагрузки соединения постоянно закрываются и открываются заново
+const (
+	DefaultMaxOpenConns    = 25
+	DefaultMaxIdleConns    = DefaultMaxOpenConns
+	DefaultConnMaxLifetime = 30 * time.Minute
+	DefaultConnMaxIdleTime = 5 * time.Minute
+)
+
 var (
 	ErrEmptyPassword = errors.New("database password not set")
 	ErrEnvNotLoaded  = errors.New("environment variables could not be loaded")
@@ -41,9 +51,21 @@ func GetConfig() (config.Config, error) {
 	password := getEnv("DB_PASSWORD", "")
 	dbName := getEnv("DB_NAME", "cinematheque")
 	sslMode := getEnv("DB_SSLMODE", "disable")
+	replicaHost := getEnv("DB_REPLICA_HOST", "")
+	replicaPort := getEnv("DB_REPLICA_PORT", port)
+	preparedStatements, err := strconv.ParseBool(getEnv("DB_PREPARED_STATEMENTS", "true"))
+	if err != nil {
+		log.Printf("Warning: invalid DB_PREPARED_STATEMENTS, prepared statements disabled: %v", err)
+	}
+	readTimeout := getEnvDuration("DB_READ_TIMEOUT", 2*time.Second)
+	writeTimeout := getEnvDuration("DB_WRITE_TIMEOUT", 5*time.Second)
+	maxOpenConns := getEnvInt("DB_MAX_OPEN_CONNS", DefaultMaxOpenConns)
+	maxIdleConns := getEnvInt("DB_MAX_IDLE_CONNS", DefaultMaxIdleConns)
+	connMaxLifetime := getEnvDuration("DB_CONN_MAX_LIFETIME", DefaultConnMaxLifetime)
+	connMaxIdleTime := getEnvDuration("DB_CONN_MAX_IDLE_TIME", DefaultConnMaxIdleTime)
 
-	log.Printf("DB Config - Host: %s, Port: %s, User: %s, DBName: %s, SSLMode: %s", 
-		host, port, user, dbName, sslMode)
+	log.Printf("DB Config - Host: %s, Port: %s, User: %s, DBName: %s, SSLMode: %s, Replica: %s",
+		host, port, user, dbName, sslMode, replicaHost)
 
 	cfg := config.Config{
 		Host:     host,
@@ -52,6 +74,19 @@ func GetConfig() (config.Config, error) {
 		Password: password,
 		DBName:   dbName,
 		SSLMode:  sslMode,
+
+		ReplicaHost: replicaHost,
+		ReplicaPort: replicaPort,
+
+		PreparedStatements: preparedStatements,
+
+		ReadTimeout:  readTimeout,
+		WriteTimeout: writeTimeout,
+
+		MaxOpenConns:    maxOpenConns,
+		MaxIdleConns:    maxIdleConns,
+		ConnMaxLifetime: connMaxLifetime,
+		ConnMaxIdleTime: connMaxIdleTime,
 	}
 
 	if cfg.Password == "" {
@@ -69,32 +104,65 @@ func getEnv(key, defaultValue string) string {
 	return defaultValue
 }
 
-// Connect устанавливает соединение с базой данных PostgreSQL
+// getEnvInt возвращает целое число из переменной окружения; неверное значение
+// заменяется значением по умолчанию
+func getEnvInt(key string, defaultValue int) int {
+	value, exists := os.LookupEnv(key)
+	if !exists {
+		return defaultValue
+	}
+	number, err := strconv.Atoi(value)
+	if err != nil {
+		log.Printf("Warning: invalid %s, using %d: %v", key, defaultValue, err)
+		return defaultValue
+	}
+	return number
+}
+
+// getEnvDuration возвращает длительность из переменной окружения; неверное значение
+// заменяется значением по умолчанию
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	value, exists := os.LookupEnv(key)
+	if !exists {
+		return defaultValue
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		log.Printf("Warning: invalid %s, using %s: %v", key, defaultValue, err)
+		return defaultValue
+	}
+	return duration
+}
+
+// Connect устанавливает соединение с базой данных по конфигурации из окружения
 func Connect() (*sql.DB, error) {
 	cfg, err := GetConfig()
 	if err != nil {
 		return nil, fmt.Errorf("database configuration failed: %w", err)
 	}
+	return Open(cfg)
+}
 
-	// Логируем параметры подключения (без пароля)
-	log.Printf("Connecting to database: host=%s, port=%s, user=%s, dbname=%s, sslmode=%s",
-		cfg.Host, cfg.Port, cfg.User, cfg.DBName, cfg.SSLMode)
-
-	connStr := fmt.Sprintf(
+// DSN возвращает строку подключения к PostgreSQL
+func DSN(cfg config.Config) string {
+	return fmt.Sprintf(
 		"host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
 		cfg.Host, cfg.Port, cfg.User, cfg.Password, cfg.DBName, cfg.SSLMode,
 	)
+}
+
+// Open устанавливает соединение с PostgreSQL по готовой конфигурации
+func Open(cfg config.Config) (*sql.DB, error) {
+	// Логируем параметры подключения (без пароля)
+	log.Printf("Connecting to database: host=%s, port=%s, user=%s, dbname=%s, sslmode=%s",
+		cfg.Host, cfg.Port, cfg.User, cfg.DBName, cfg.SSLMode)
 
-	db, err := sql.Open("postgres", connStr)
+	db, err := sql.Open("postgres", DSN(cfg))
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database connection: %w", err)
 	}
 
-	// Настройки пула подключений
-	db.SetMaxOpenConns(25)
-	db.SetMaxIdleConns(5)
-	db.SetConnMaxLifetime(5 * time.Minute)
-	db.SetConnMaxIdleTime(2 * time.Minute)
+	ConfigurePool(db, cfg)
 
 	// Проверка подключения
 	if err := db.Ping(); err != nil {
@@ -106,6 +174,49 @@ func Connect() (*sql.DB, error) {
 	return db, nil
 }
 
+// ConfigurePool применяет к db настройки пула из cfg. Неположительные значения заменяются
+// значениями по умолчанию; MaxIdleConns ограничивается MaxOpenConns
+func ConfigurePool(db *sql.DB, cfg config.Config) {
+	maxOpen := cfg.MaxOpenConns
+	if maxOpen <= 0 {
+		maxOpen = DefaultMaxOpenConns
+	}
+	maxIdle := cfg.MaxIdleConns
+	if maxIdle <= 0 {
+		maxIdle = DefaultMaxIdleConns
+	}
+	maxIdle = min(maxIdle, maxOpen)
+	lifetime := cfg.ConnMaxLifetime
+	if lifetime <= 0 {
+		lifetime = DefaultConnMaxLifetime
+	}
+	idleTime := cfg.ConnMaxIdleTime
+	if idleTime <= 0 {
+		idleTime = DefaultConnMaxIdleTime
+	}
+
+	db.SetMaxOpenConns(maxOpen)
+	db.SetMaxIdleConns(maxIdle)
+	db.SetConnMaxLifetime(lifetime)
+	db.SetConnMaxIdleTime(idleTime)
+	log.Printf("Database pool: max_open=%d, max_idle=%d, max_lifetime=%s, max_idle_time=%s",
+		maxOpen, maxIdle, lifetime, idleTime)
+}
+
+// OpenReplica подключается к реплике для чтения из cfg.ReplicaHost.
+// Возвращает nil без ошибки, если реплика не настроена
+func OpenReplica(cfg config.Config) (*sql.DB, error) {
+	if cfg.ReplicaHost == "" {
+		return nil, nil
+	}
+	cfg.Host, cfg.Port = cfg.ReplicaHost, cfg.ReplicaPort
+	db, err := Open(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("read replica: %w", err)
+	}
+	return db, nil
+}
+
 // DBStatsCollector реализует интерфейс prometheus.Collector.
 type DBStatsCollector struct {
 	db *sql.DB
@@ -5,6 +5,8 @@ import (
 	"testing"
 	"time"
 
+	"cinematique/internal/config"
+
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -14,7 +16,7 @@ import (
 func TestGetConfig(t *testing.T) {
 	// Сохраняем оригинальные переменные окружения
 	oldEnv := make(map[string]string)
-	for _, key := range []string{"DB_HOST", "DB_PORT", "DB_USER", "DB_PASSWORD", "DB_NAME", "DB_SSLMODE"} {
+	for _, key := range []string{"DB_HOST", "DB_PORT", "DB_USER", "DB_PASSWORD", "DB_NAME", "DB_SSLMODE", "DB_REPLICA_HOST", "DB_REPLICA_PORT", "DB_PREPARED_STATEMENTS"} {
 		value := os.Getenv(key)
 		if value != "" {
 			oldEnv[key] = value
@@ -57,7 +59,58 @@ func TestGetConfig(t *testing.T) {
 		assert.Equal(t, "test-password", cfg.Password)
 		assert.Equal(t, "test-db", cfg.DBName)
 		assert.Equal(t, "require", cfg.SSLMode)
+		assert.Empty(t, cfg.ReplicaHost)
+		assert.True(t, cfg.PreparedStatements)
 	})
+
+	// Подготовленные выражения выключаются для PgBouncer в режиме transaction pooling
+	t.Run("without_prepared_statements", func(t *testing.T) {
+		os.Setenv("DB_PASSWORD", "test-password")
+		os.Setenv("DB_PREPARED_STATEMENTS", "false")
+		defer os.Unsetenv("DB_PREPARED_STATEMENTS")
+
+		cfg, err := GetConfig()
+		require.NoError(t, err)
+		assert.False(t, cfg.PreparedStatements)
+	})
+
+	// Настройки пула задаются переменными окружения
+	t.Run("with_pool_settings", func(t *testing.T) {
+		os.Setenv("DB_PASSWORD", "test-password")
+		os.Setenv("DB_MAX_OPEN_CONNS", "60")
+		os.Setenv("DB_MAX_IDLE_CONNS", "invalid")
+		os.Setenv("DB_CONN_MAX_LIFETIME", "1h")
+		defer func() {
+			os.Unsetenv("DB_MAX_OPEN_CONNS")
+			os.Unsetenv("DB_MAX_IDLE_CONNS")
+			os.Unsetenv("DB_CONN_MAX_LIFETIME")
+		}()
+
+		cfg, err := GetConfig()
+		require.NoError(t, err)
+		assert.Equal(t, 60, cfg.MaxOpenConns)
+		assert.Equal(t, DefaultMaxIdleConns, cfg.MaxIdleConns)
+		assert.Equal(t, time.Hour, cfg.ConnMaxLifetime)
+		assert.Equal(t, DefaultConnMaxIdleTime, cfg.ConnMaxIdleTime)
+	})
+
+	// Реплика по умолчанию слушает тот же порт, что и основная БД
+	t.Run("with_replica", func(t *testing.T) {
+		os.Setenv("DB_PASSWORD", "test-password")
+		os.Setenv("DB_PORT", "5433")
+		os.Setenv("DB_REPLICA_HOST", "replica-host")
+
+		cfg, err := GetConfig()
+		require.NoError(t, err)
+		assert.Equal(t, "replica-host", cfg.ReplicaHost)
+		assert.Equal(t, "5433", cfg.ReplicaPort)
+	})
+}
+
+func TestOpenReplica_NotConfigured(t *testing.T) {
+	db, err := OpenReplica(config.Config{Host: "primary"})
+	assert.NoError(t, err)
+	assert.Nil(t, db)
 }
 
 // TestConnect тестирует подключение к базе данных
@@ -97,15 +150,20 @@ func TestConnectPoolSettings(t *testing.T) {
 	require.NoError(t, err)
 	defer db.Close()
 
-	// Устанавливаем настройки пула
-	db.SetMaxOpenConns(25)
-	db.SetMaxIdleConns(5)
-	db.SetConnMaxLifetime(5 * time.Minute)
-	db.SetConnMaxIdleTime(2 * time.Minute)
+	// Незаданные значения заменяются значениями по умолчанию
+	ConfigurePool(db, config.Config{})
+	assert.Equal(t, DefaultMaxOpenConns, db.Stats().MaxOpenConnections)
 
 	// Проверяем только MaxOpenConnections, так как это единственная надежная проверка с sqlmock
-	assert.Equal(t, 25, db.Stats().MaxOpenConnections)
+	ConfigurePool(db, config.Config{MaxOpenConns: 50, MaxIdleConns: 80, ConnMaxLifetime: time.Hour})
+	assert.Equal(t, 50, db.Stats().MaxOpenConnections)
 
 	// Проверяем, что нет неожиданных вызовов
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
+
+// TestDSN проверяет строку подключения
+func TestDSN(t *testing.T) {
+	cfg := config.Config{Host: "db", Port: "5432", User: "u", Password: "p", DBName: "films", SSLMode: "disable"}
+	assert.Equal(t, "host=db port=5432 user=u password=p dbname=films sslmode=disable", DSN(cfg))
+}
@@ -83,9 +83,11 @@ func Middleware(limiter RateLimiter, config Config) gin.HandlerFunc {
 			// Получаем текущий счетчик для информативности
 			currentCount, _ := limiter.GetCurrentCount(ctx, userID, ip, endpoint)
 
+			retryAfter := int(limiter.GetWindow().Seconds())
 			c.Header("X-RateLimit-Limit", strconv.Itoa(limiter.GetLimit()))
 			c.Header("X-RateLimit-Remaining", "0")
 			c.Header("X-RateLimit-Reset", strconv.FormatInt(time.Now().Add(limiter.GetWindow()).Unix(), 10))
+			c.Header("Retry-After", strconv.Itoa(retryAfter))
 
 			c.JSON(http.StatusTooManyRequests, gin.H{
 				"error":         "Too many requests",
@@ -93,7 +95,7 @@ func Middleware(limiter RateLimiter, config Config) gin.HandlerFunc {
 				"current_count": currentCount,
 				"limit":         limiter.GetLimit(),
 				"window":        limiter.GetWindow().String(),
-				"retry_after":   int(limiter.GetWindow().Seconds()),
+				"retry_after":   retryAfter,
 			})
 			c.Abort()
 			return
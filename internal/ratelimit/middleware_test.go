@@ -0,0 +1,53 @@
+package ratelimit
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestMiddleware_SearchBucket(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctx := context.Background()
+	mockClient := new(MockRedisClient)
+	limiter := NewRedisRateLimiter(mockClient, 2, time.Minute).WithKeyPrefix("ratelimit:search")
+
+	// Третий запрос к поиску за минуту превышает лимит корзины
+	incrCmd := redis.NewIntCmd(ctx)
+	incrCmd.SetVal(3)
+	mockClient.On("Incr", mock.Anything, mock.MatchedBy(func(key string) bool {
+		return strings.HasPrefix(key, "ratelimit:search:user-1:")
+	})).Return(incrCmd)
+	getCmd := redis.NewStringCmd(ctx)
+	getCmd.SetVal("3")
+	mockClient.On("Get", mock.Anything, mock.Anything).Return(getCmd)
+
+	r := gin.New()
+	r.Use(Middleware(limiter, Config{
+		Enabled:             true,
+		RestrictedEndpoints: []string{"/api/movies/search", "/api/actors/search"},
+		GetUserID:           func(c *gin.Context) string { return "user-1" },
+	}))
+	r.GET("/api/movies/search", func(c *gin.Context) { c.Status(http.StatusOK) })
+	r.GET("/api/movies/sorted", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/movies/search?title=ma", nil))
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "60", w.Header().Get("Retry-After"))
+	assert.Equal(t, "2", w.Header().Get("X-RateLimit-Limit"))
+
+	// Эндпоинты вне корзины поиска ею не ограничиваются
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/movies/sorted", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockClient.AssertNumberOfCalls(t, "Incr", 1)
+}
@@ -22,23 +22,38 @@ type RedisRateLimiter struct {
 	client RedisClient
 	limit  int
 	window time.Duration
+	prefix string // префикс ключей; у каждой корзины лимитов свой
 }
 
+// DefaultKeyPrefix — префикс ключей общей корзины лимитов
+const DefaultKeyPrefix = "ratelimit"
+
 // NewRedisRateLimiter создает новый rate limiter
 func NewRedisRateLimiter(client RedisClient, limit int, window time.Duration) *RedisRateLimiter {
 	return &RedisRateLimiter{
 		client: client,
 		limit:  limit,
 		window: window,
+		prefix: DefaultKeyPrefix,
 	}
 }
 
+// WithKeyPrefix задаёт префикс ключей. Отдельная корзина (например, для поиска) должна иметь
+// свой префикс, иначе запросы к одному эндпоинту будут считаться в обеих корзинах дважды
+func (r *RedisRateLimiter) WithKeyPrefix(prefix string) *RedisRateLimiter {
+	r.prefix = prefix
+	return r
+}
+
+// key возвращает ключ счётчика: {prefix}:{user_id}:{ip}:{endpoint}:{timestamp_minute}
+func (r *RedisRateLimiter) key(userID, ip, endpoint string) string {
+	timestampMinute := time.Now().Truncate(time.Minute).Unix()
+	return fmt.Sprintf("%s:%s:%s:%s:%d", r.prefix, userID, ip, endpoint, timestampMinute)
+}
+
 // IsAllowed проверяет, разрешен ли запрос
 func (r *RedisRateLimiter) IsAllowed(ctx context.Context, userID, ip, endpoint string) (bool, error) {
-	// Формируем ключ: ratelimit:{user_id}:{ip}:{endpoint}:{timestamp_minute}
-	now := time.Now()
-	timestampMinute := now.Truncate(time.Minute).Unix()
-	key := fmt.Sprintf("ratelimit:%s:%s:%s:%d", userID, ip, endpoint, timestampMinute)
+	key := r.key(userID, ip, endpoint)
 
 	// Увеличиваем счетчик
 	count, err := r.client.Incr(ctx, key).Result()
@@ -59,9 +74,7 @@ func (r *RedisRateLimiter) IsAllowed(ctx context.Context, userID, ip, endpoint s
 
 // GetCurrentCount возвращает текущее количество запросов
 func (r *RedisRateLimiter) GetCurrentCount(ctx context.Context, userID, ip, endpoint string) (int, error) {
-	now := time.Now()
-	timestampMinute := now.Truncate(time.Minute).Unix()
-	key := fmt.Sprintf("ratelimit:%s:%s:%s:%d", userID, ip, endpoint, timestampMinute)
+	key := r.key(userID, ip, endpoint)
 
 	result, err := r.client.Get(ctx, key).Result()
 	if err != nil {
@@ -2,17 +2,21 @@ package repository
 
 import (
 	"cinematique/internal/domain"
+	"context"
 	"database/sql"
 	"errors"
 	"fmt"
 	sq "github.com/Masterminds/squirrel"
 	"log"
+	"strings"
 	"time"
 )
 
 // actor реализует репозиторий для актёров
 type actor struct {
-	db *sql.DB // соединение с базой данных
+	db      *sql.DB      // соединение с базой данных
+	replica *ReadReplica // реплика для списков и поиска; nil — всё читается из db
+	stmts   *stmtCache   // подготовленные выражения горячих запросов; nil — без подготовки
 }
 
 // NewActor создаёт репозиторий актёров
@@ -20,24 +24,59 @@ func NewActor(db *sql.DB) *actor {
 	return &actor{db: db}
 }
 
+// WithReadReplica направляет списки и поиск актёров в реплику
+func (a *actor) WithReadReplica(replica *ReadReplica) *actor {
+	a.replica = replica
+	return a
+}
+
+// WithPreparedStatements включает подготовленные выражения для получения актёра по ID и поиска по имени
+func (a *actor) WithPreparedStatements() *actor {
+	a.stmts = newStmtCache()
+	return a
+}
+
+// Close закрывает подготовленные выражения репозитория
+func (a *actor) Close() error {
+	return a.stmts.Close()
+}
+
+// actorColumns — колонки таблицы actors в порядке сканирования scanActor
+var actorColumns = []string{"id", "name", "gender", "birth_date", "death_date", "photo_key", "slug"}
+
+// scanActor читает строку, выбранную по actorColumns, в domain.Actor
+func scanActor(row rowScanner) (domain.Actor, error) {
+	var actor domain.Actor
+	err := row.Scan(&actor.ID, &actor.Name, &actor.Gender, &actor.BirthDate, &actor.DeathDate, &actor.PhotoKey, &actor.Slug)
+	return actor, err
+}
+
+// actorDetailColumns — колонки актёра вместе с моментом последнего изменения для чтения одного актёра
+var actorDetailColumns = append(append([]string{}, actorColumns...), "updated_at")
+
+// scanActorDetail читает строку, выбранную по actorDetailColumns, в domain.Actor
+func scanActorDetail(row rowScanner) (domain.Actor, error) {
+	var actor domain.Actor
+	err := row.Scan(&actor.ID, &actor.Name, &actor.Gender, &actor.BirthDate, &actor.DeathDate, &actor.PhotoKey, &actor.Slug, &actor.UpdatedAt)
+	return actor, err
+}
+
 // Create создаёт актёра
-func (a *actor) Create(actor domain.Actor) (int, error) {
+func (a *actor) Create(ctx context.Context, actor domain.Actor) (int, error) {
 	start := time.Now()
 	operation := "create_actor"
 	queryType := "INSERT"
+	ctx, span := startSpan(ctx, operation, queryType)
+	defer span.End()
 
-	query, args, err := sq.Insert("actors").
-		Columns("name", "gender", "birth_date").
-		Values(actor.Name, actor.Gender, actor.BirthDate).
-		Suffix("RETURNING id").
-		PlaceholderFormat(sq.Dollar).
-		ToSql()
+	actorSlug, err := freeSlug(ctx, a.db, "actors", actorSlugBase(actor))
 	if err != nil {
 		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
 		return 0, err
 	}
-	var id int
-	err = a.db.QueryRow(query, args...).Scan(&id)
+	id, err := insertReturningID(ctx, a.db, sq.Insert("actors").
+		Columns("name", "gender", "birth_date", "death_date", "slug").
+		Values(actor.Name, actor.Gender, actor.BirthDate, actor.DeathDate, actorSlug))
 	if err != nil {
 		log.Printf("Error creating actor: %v", err)
 		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
@@ -49,12 +88,14 @@ func (a *actor) Create(actor domain.Actor) (int, error) {
 }
 
 // GetByID возвращает актёра по ID
-func (a *actor) GetByID(id int) (domain.Actor, error) {
+func (a *actor) GetByID(ctx context.Context, id int) (domain.Actor, error) {
 	start := time.Now()
 	operation := "get_actor_by_id"
 	queryType := "SELECT"
+	ctx, span := startSpan(ctx, operation, queryType)
+	defer span.End()
 
-	query, args, err := sq.Select("id", "name", "gender", "birth_date").
+	query, args, err := sq.Select(actorDetailColumns...).
 		From("actors").
 		Where(sq.Eq{"id": id}).
 		PlaceholderFormat(sq.Dollar).
@@ -64,8 +105,7 @@ func (a *actor) GetByID(id int) (domain.Actor, error) {
 		return domain.Actor{}, fmt.Errorf("building query: %w", err)
 	}
 
-	var actor domain.Actor
-	err = a.db.QueryRow(query, args...).Scan(&actor.ID, &actor.Name, &actor.Gender, &actor.BirthDate)
+	actor, err := scanActorDetail(a.stmts.queryRowContext(ctx, a.db, query, args...))
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
@@ -80,15 +120,18 @@ func (a *actor) GetByID(id int) (domain.Actor, error) {
 }
 
 // Update обновляет актёра
-func (a *actor) Update(actor domain.Actor) error {
+func (a *actor) Update(ctx context.Context, actor domain.Actor) error {
 	start := time.Now()
 	operation := "update_actor"
 	queryType := "UPDATE"
+	ctx, span := startSpan(ctx, operation, queryType)
+	defer span.End()
 
 	query, args, err := sq.Update("actors").
 		Set("name", actor.Name).
 		Set("gender", actor.Gender).
 		Set("birth_date", actor.BirthDate).
+		Set("death_date", actor.DeathDate).
 		Where(sq.Eq{"id": actor.ID}).
 		PlaceholderFormat(sq.Dollar).
 		ToSql()
@@ -97,7 +140,7 @@ func (a *actor) Update(actor domain.Actor) error {
 		return fmt.Errorf("building query: %w", err)
 	}
 
-	result, err := a.db.Exec(query, args...)
+	result, err := a.db.ExecContext(ctx, query, args...)
 	if err != nil {
 		log.Printf("Error updating actor: %v", err)
 		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
@@ -121,13 +164,15 @@ func (a *actor) Update(actor domain.Actor) error {
 }
 
 // Delete удаляет актёра по ID
-func (a *actor) Delete(id int) error {
+func (a *actor) Delete(ctx context.Context, id int) error {
 	start := time.Now()
 	operation := "delete_actor"
 	queryType := "DELETE"
+	ctx, span := startSpan(ctx, operation, queryType)
+	defer span.End()
 
 	// Сначала проверяем существование актёра
-	_, err := a.GetByID(id)
+	_, err := a.GetByID(ctx, id)
 	if err != nil {
 		if errors.Is(err, domain.ErrActorNotFound) {
 			dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
@@ -137,7 +182,7 @@ func (a *actor) Delete(id int) error {
 		return fmt.Errorf("checking actor existence: %w", err)
 	}
 
-	tx, err := a.db.Begin()
+	tx, err := a.db.BeginTx(ctx, nil)
 	if err != nil {
 		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
 		return fmt.Errorf("failed to begin transaction: %w", err)
@@ -154,7 +199,7 @@ func (a *actor) Delete(id int) error {
 		return fmt.Errorf("failed to build delete film_actor query: %w", err)
 	}
 
-	if _, err = tx.Exec(delFilmActor, args...); err != nil {
+	if _, err = tx.ExecContext(ctx, delFilmActor, args...); err != nil {
 		log.Printf("Error deleting film_actor relations: %v", err)
 		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
 		return fmt.Errorf("failed to delete film_actor relations: %w", err)
@@ -170,7 +215,7 @@ func (a *actor) Delete(id int) error {
 		return fmt.Errorf("failed to build delete actor query: %w", err)
 	}
 
-	if _, err = tx.Exec(delActor, args...); err != nil {
+	if _, err = tx.ExecContext(ctx, delActor, args...); err != nil {
 		log.Printf("Error deleting actor: %v", err)
 		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
 		return fmt.Errorf("failed to delete actor: %w", err)
@@ -186,47 +231,284 @@ func (a *actor) Delete(id int) error {
 	return nil
 }
 
-// GetAll возвращает всех актёров
-func (a *actor) GetAll() ([]domain.Actor, error) {
+// GetAll возвращает всех актёров, подходящих под filter
+func (a *actor) GetAll(ctx context.Context, filter domain.ActorFilter) ([]domain.Actor, error) {
 	start := time.Now()
 	operation := "get_all_actors"
 	queryType := "SELECT"
+	ctx, span := startSpan(ctx, operation, queryType)
+	defer span.End()
 
-	query, args, err := sq.Select("id", "name", "gender", "birth_date").
-		From("actors").
+	query, args, err := applyActorFilter(sq.Select(actorColumns...).From("actors"), filter).
 		PlaceholderFormat(sq.Dollar).
 		ToSql()
 	if err != nil {
 		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
 		return nil, err
 	}
-	rows, err := a.db.Query(query, args...)
+	rows, err := a.replica.pick(a.db).QueryContext(ctx, query, args...)
 	if err != nil {
 		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
 		return nil, err
 	}
 	defer rows.Close()
 	var actors []domain.Actor
+	for rows.Next() {
+		actor, err := scanActor(rows)
+		if err != nil {
+			dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+			return nil, err
+		}
+		actors = append(actors, actor)
+	}
+	dbQueryDurationSeconds.WithLabelValues(operation, queryType).Observe(time.Since(start).Seconds())
+	dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+	return actors, nil
+}
+
+// ForEachActor вызывает fn для каждого актёра по возрастанию ID, не загружая весь список в память.
+// Ошибка fn прекращает чтение и возвращается как есть
+func (a *actor) ForEachActor(ctx context.Context, fn func(domain.Actor) error) error {
+	start := time.Now()
+	operation := "for_each_actor"
+	queryType := "SELECT"
+	ctx, span := startStreamSpan(ctx, operation, queryType)
+	defer span.End()
+
+	query, args, err := sq.Select(actorColumns...).
+		From("actors").
+		OrderBy("id ASC").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return err
+	}
+	rows, err := a.replica.pick(a.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		actor, err := scanActor(rows)
+		if err != nil {
+			dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+			return err
+		}
+		if err := fn(actor); err != nil {
+			dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return err
+	}
+	dbQueryDurationSeconds.WithLabelValues(operation, queryType).Observe(time.Since(start).Seconds())
+	dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+	return nil
+}
+
+// applyActorFilter добавляет к выборке из actors условия filter
+func applyActorFilter(builder sq.SelectBuilder, filter domain.ActorFilter) sq.SelectBuilder {
+	if filter.Living {
+		builder = builder.Where(sq.Eq{"death_date": nil})
+	}
+	return builder
+}
+
+// SearchActorsByName ищет актёров по фрагменту имени без учёта регистра среди подходящих
+// под filter; пустой фрагмент подходит всем. Результаты упорядочены по имени; limit и offset
+// задают страницу (limit = 0 — без ограничения)
+func (a *actor) SearchActorsByName(ctx context.Context, nameFragment string, filter domain.ActorFilter, limit, offset int) ([]domain.Actor, error) {
+	start := time.Now()
+	operation := "search_actors_by_name"
+	queryType := "SELECT"
+	ctx, span := startSpan(ctx, operation, queryType)
+	defer span.End()
+
+	builder := sq.Select(actorColumns...).
+		From("actors").
+		OrderBy("name ASC", "id ASC").
+		PlaceholderFormat(sq.Dollar)
+	if nameFragment != "" {
+		builder = builder.Where("name ILIKE ?", "%"+nameFragment+"%") // использует триграммный индекс idx_actors_name_trgm
+	}
+	builder = applyActorFilter(builder, filter)
+	// LIMIT и OFFSET передаются параметрами, а не подставляются в текст: иначе каждая
+	// страница была бы отдельным подготовленным выражением
+	if limit > 0 {
+		builder = builder.Suffix("LIMIT ?", limit)
+	}
+	if offset > 0 {
+		builder = builder.Suffix("OFFSET ?", offset)
+	}
+	query, args, err := builder.ToSql()
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return nil, err
+	}
+	rows, err := a.stmts.queryContext(ctx, a.replica.pick(a.db), query, args...)
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return nil, err
+	}
+	defer rows.Close()
+	actors := []domain.Actor{}
+	for rows.Next() {
+		actor, err := scanActor(rows)
+		if err != nil {
+			dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+			return nil, err
+		}
+		actors = append(actors, actor)
+	}
+	if err := rows.Err(); err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return nil, err
+	}
+	dbQueryDurationSeconds.WithLabelValues(operation, queryType).Observe(time.Since(start).Seconds())
+	dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+	return actors, nil
+}
+
+// CountActors возвращает число актёров, чьё имя содержит nameFragment (без учёта регистра)
+// и которые подходят под filter. Пустой nameFragment не ограничивает имя
+func (a *actor) CountActors(ctx context.Context, nameFragment string, filter domain.ActorFilter) (int, error) {
+	start := time.Now()
+	operation := "count_actors"
+	queryType := "SELECT"
+	ctx, span := startSpan(ctx, operation, queryType)
+	defer span.End()
+
+	builder := sq.Select("COUNT(*)").From("actors")
+	if nameFragment != "" {
+		builder = builder.Where("name ILIKE ?", "%"+nameFragment+"%")
+	}
+	query, args, err := applyActorFilter(builder, filter).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return 0, err
+	}
+	var count int
+	if err := a.replica.pick(a.db).QueryRowContext(ctx, query, args...).Scan(&count); err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return 0, err
+	}
+	dbQueryDurationSeconds.WithLabelValues(operation, queryType).Observe(time.Since(start).Seconds())
+	dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+	return count, nil
+}
+
+// likeEscaper экранирует символы шаблона LIKE, чтобы они искались буквально
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// SuggestActors подбирает актёров для автодополнения: имя или одно из слов имени начинается
+// с prefix (без учёта регистра). Возвращаются только ID и имя; сначала идут совпадения
+// с начала имени, затем по алфавиту
+func (a *actor) SuggestActors(ctx context.Context, prefix string, limit int) ([]domain.Actor, error) {
+	start := time.Now()
+	operation := "suggest_actors"
+	queryType := "SELECT"
+	ctx, span := startSpan(ctx, operation, queryType)
+	defer span.End()
+
+	// Шаблон приводится к нижнему регистру заранее: так условие совпадает с выражением индекса
+	pattern := strings.ToLower(likeEscaper.Replace(prefix)) + "%"
+	query, args, err := sq.Select("id", "name").
+		From("actors").
+		Where(sq.Or{
+			sq.Expr("LOWER(name) LIKE ?", pattern), // индекс idx_actors_name_prefix
+			sq.Expr("name ILIKE ?", "% "+pattern),  // начало фамилии: триграммный индекс idx_actors_name_trgm
+		}).
+		OrderByClause("LOWER(name) LIKE ? DESC", pattern).
+		OrderBy("name ASC", "id ASC").
+		Suffix("LIMIT ?", limit).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return nil, err
+	}
+	rows, err := a.stmts.queryContext(ctx, a.replica.pick(a.db), query, args...)
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return nil, err
+	}
+	defer rows.Close()
+	actors := []domain.Actor{}
 	for rows.Next() {
 		var actor domain.Actor
-		if err := rows.Scan(&actor.ID, &actor.Name, &actor.Gender, &actor.BirthDate); err != nil {
+		if err := rows.Scan(&actor.ID, &actor.Name); err != nil {
+			dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+			return nil, err
+		}
+		actors = append(actors, actor)
+	}
+	if err := rows.Err(); err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return nil, err
+	}
+	dbQueryDurationSeconds.WithLabelValues(operation, queryType).Observe(time.Since(start).Seconds())
+	dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+	return actors, nil
+}
+
+// GetActorsBornInMonth возвращает актёров, родившихся в указанном месяце (1–12),
+// упорядоченных по дню рождения
+func (a *actor) GetActorsBornInMonth(ctx context.Context, month int) ([]domain.Actor, error) {
+	start := time.Now()
+	operation := "get_actors_born_in_month"
+	queryType := "SELECT"
+	ctx, span := startSpan(ctx, operation, queryType)
+	defer span.End()
+
+	query, args, err := sq.Select(actorColumns...).
+		From("actors").
+		Where("EXTRACT(MONTH FROM birth_date) = ?", month).
+		OrderBy("EXTRACT(DAY FROM birth_date) ASC", "name ASC", "id ASC").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return nil, fmt.Errorf("building query: %w", err)
+	}
+	rows, err := a.replica.pick(a.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return nil, fmt.Errorf("querying actors by birth month: %w", err)
+	}
+	defer rows.Close()
+	actors := []domain.Actor{}
+	for rows.Next() {
+		actor, err := scanActor(rows)
+		if err != nil {
 			dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
 			return nil, err
 		}
 		actors = append(actors, actor)
 	}
+	if err := rows.Err(); err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return nil, err
+	}
 	dbQueryDurationSeconds.WithLabelValues(operation, queryType).Observe(time.Since(start).Seconds())
 	dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
 	return actors, nil
 }
 
 // GetMovies возвращает фильмы актёра
-func (a *actor) GetMovies(actorID int) ([]domain.Movie, error) {
+func (a *actor) GetMovies(ctx context.Context, actorID int) ([]domain.Movie, error) {
 	start := time.Now()
 	operation := "get_movies_for_actor"
 	queryType := "SELECT"
+	ctx, span := startSpan(ctx, operation, queryType)
+	defer span.End()
 
-	query, args, err := sq.Select("f.id", "f.title", "f.description", "f.release_year", "f.rating").
+	query, args, err := sq.Select(prefixedMovieColumns("f")...).
 		From("films f").
 		Join("film_actor fa ON f.id = fa.film_id").
 		Where(sq.Eq{"fa.actor_id": actorID}).
@@ -236,7 +518,7 @@ func (a *actor) GetMovies(actorID int) ([]domain.Movie, error) {
 		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
 		return []domain.Movie{}, err
 	}
-	rows, err := a.db.Query(query, args...)
+	rows, err := a.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
 		return []domain.Movie{}, err
@@ -244,8 +526,8 @@ func (a *actor) GetMovies(actorID int) ([]domain.Movie, error) {
 	defer rows.Close()
 	movies := []domain.Movie{}
 	for rows.Next() {
-		var movie domain.Movie
-		if err := rows.Scan(&movie.ID, &movie.Title, &movie.Description, &movie.ReleaseYear, &movie.Rating); err != nil {
+		movie, err := scanMovie(rows)
+		if err != nil {
 			dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
 			return []domain.Movie{}, err
 		}
@@ -256,21 +538,51 @@ func (a *actor) GetMovies(actorID int) ([]domain.Movie, error) {
 	return movies, nil
 }
 
-// GetAllActorsWithMovies возвращает актёров с их фильмами
-func (a *actor) GetAllActorsWithMovies() ([]domain.Actor, error) {
+// GetAllActorsWithMovies возвращает страницу актёров с их фильмами. Страница выбирается
+// подзапросом по актёрам, поэтому LIMIT и OFFSET не режут фильмы актёра на границе страницы;
+// фильмы присоединяются через LATERAL, что позволяет ограничить их число для каждого актёра.
+// Фильмы актёра идут от новых к старым
+func (a *actor) GetAllActorsWithMovies(ctx context.Context, q domain.ActorsWithMoviesQuery) ([]domain.Actor, error) {
 	start := time.Now()
 	operation := "get_all_actors_with_movies"
 	queryType := "SELECT"
+	ctx, span := startSpan(ctx, operation, queryType)
+	defer span.End()
+
+	page := sq.Select("id", "name", "gender", "birth_date").
+		From("actors").
+		OrderBy("id")
+	if q.Name != "" {
+		page = page.Where("name ILIKE ?", "%"+q.Name+"%")
+	}
+	if q.Limit > 0 {
+		page = page.Suffix("LIMIT ?", q.Limit)
+	}
+	if q.Offset > 0 {
+		page = page.Suffix("OFFSET ?", q.Offset)
+	}
+
+	films := sq.Select("f.id", "f.title", "f.description", "f.release_year", "f.rating").
+		From("film_actor fa").
+		Join("films f ON f.id = fa.film_id").
+		Where("fa.actor_id = a.id").
+		OrderBy("f.release_year DESC", "f.id DESC")
+	if q.MoviesPerActor > 0 {
+		films = films.Suffix("LIMIT ?", q.MoviesPerActor)
+	}
+	filmsQuery, filmsArgs, err := films.ToSql()
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
 
-	// Используем один запрос с JOIN вместо N+1 запросов
 	query, args, err := sq.Select(
 		"a.id", "a.name", "a.gender", "a.birth_date",
-		"f.id", "f.title", "f.description", "f.release_year", "f.rating",
+		"m.id", "m.title", "m.description", "m.release_year", "m.rating",
 	).
-		From("actors a").
-		LeftJoin("film_actor fa ON a.id = fa.actor_id").
-		LeftJoin("films f ON fa.film_id = f.id").
-		OrderBy("a.id", "f.id").
+		FromSelect(page, "a").
+		JoinClause("LEFT JOIN LATERAL ("+filmsQuery+") m ON TRUE", filmsArgs...).
+		OrderBy("a.id", "m.release_year DESC", "m.id DESC").
 		PlaceholderFormat(sq.Dollar).
 		ToSql()
 
@@ -279,7 +591,7 @@ func (a *actor) GetAllActorsWithMovies() ([]domain.Actor, error) {
 		return nil, fmt.Errorf("failed to build query: %w", err)
 	}
 
-	rows, err := a.db.Query(query, args...)
+	rows, err := a.replica.pick(a.db).QueryContext(ctx, query, args...)
 	if err != nil {
 		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
 		return nil, fmt.Errorf("failed to execute query: %w", err)
@@ -352,10 +664,12 @@ func (a *actor) GetAllActorsWithMovies() ([]domain.Actor, error) {
 }
 
 // PartialUpdateActor частично обновляет актёра
-func (a *actor) PartialUpdateActor(id int, update domain.ActorUpdate) error {
+func (a *actor) PartialUpdateActor(ctx context.Context, id int, update domain.ActorUpdate) error {
 	start := time.Now()
 	operation := "partial_update_actor"
 	queryType := "UPDATE"
+	ctx, span := startSpan(ctx, operation, queryType)
+	defer span.End()
 
 	// Проверяем, что есть хотя бы одно поле для обновления
 	if update.Name == nil && update.Gender == nil && update.BirthDate == nil {
@@ -364,7 +678,7 @@ func (a *actor) PartialUpdateActor(id int, update domain.ActorUpdate) error {
 	}
 
 	// Проверяем существование актёра
-	_, err := a.GetByID(id)
+	_, err := a.GetByID(ctx, id)
 	if err != nil {
 		if errors.Is(err, domain.ErrActorNotFound) {
 			dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
@@ -390,7 +704,7 @@ func (a *actor) PartialUpdateActor(id int, update domain.ActorUpdate) error {
 	}
 
 	// Добавляем updated_at, если поле существует в таблице
-	hasUpdatedAt, err := a.columnExists("actors", "updated_at")
+	hasUpdatedAt, err := a.columnExists(ctx, "actors", "updated_at")
 	if err != nil {
 		log.Printf("Warning: failed to check updated_at column: %v", err)
 	}
@@ -404,7 +718,7 @@ func (a *actor) PartialUpdateActor(id int, update domain.ActorUpdate) error {
 		return fmt.Errorf("failed to build update query: %w", err)
 	}
 
-	result, err := a.db.Exec(query, args...)
+	result, err := a.db.ExecContext(ctx, query, args...)
 	if err != nil {
 		log.Printf("Error partially updating actor: %v", err)
 		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
@@ -426,20 +740,28 @@ func (a *actor) PartialUpdateActor(id int, update domain.ActorUpdate) error {
 }
 
 // columnExists проверяет существование колонки в таблице
-func (a *actor) columnExists(tableName, columnName string) (bool, error) {
+func (a *actor) columnExists(ctx context.Context, tableName, columnName string) (bool, error) {
 	start := time.Now()
 	operation := "column_exists"
 	queryType := "SELECT"
-
-	query := `
-		SELECT EXISTS (
-			SELECT 1
-			FROM information_schema.columns
-			WHERE table_name = $1 AND column_name = $2
-		)`
+	ctx, span := startSpan(ctx, operation, queryType)
+	defer span.End()
+
+	query, args, err := sq.Select("1").
+		Prefix("SELECT EXISTS (").
+		From("information_schema.columns").
+		Where(sq.Eq{"table_name": tableName}).
+		Where(sq.Eq{"column_name": columnName}).
+		Suffix(")").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return false, fmt.Errorf("building query: %w", err)
+	}
 
 	var exists bool
-	err := a.db.QueryRow(query, tableName, columnName).Scan(&exists)
+	err = a.db.QueryRowContext(ctx, query, args...).Scan(&exists)
 	if err != nil {
 		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
 		return false, fmt.Errorf("failed to check column existence: %w", err)
@@ -448,4 +770,198 @@ func (a *actor) columnExists(tableName, columnName string) (bool, error) {
 	dbQueryDurationSeconds.WithLabelValues(operation, queryType).Observe(time.Since(start).Seconds())
 	dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
 	return exists, nil
-}
\ No newline at end of file
+}
+
+// MergeActors переносит связи актёра-дубликата на основного актёра,
+// дополняет пустые поля профиля и удаляет дубликат в одной транзакции
+func (a *actor) MergeActors(ctx context.Context, keepID, dupID int) (domain.ActorMergeResult, error) {
+	start := time.Now()
+	operation := "merge_actors"
+	queryType := "UPDATE"
+	ctx, span := startSpan(ctx, operation, queryType)
+	defer span.End()
+
+	tx, err := a.db.BeginTx(ctx, nil)
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return domain.ActorMergeResult{}, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Блокируем обе записи до конца транзакции
+	selectActor := func(id int) (domain.Actor, error) {
+		query, args, err := sq.Select(actorColumns...).
+			From("actors").
+			Where(sq.Eq{"id": id}).
+			Suffix("FOR UPDATE").
+			PlaceholderFormat(sq.Dollar).
+			ToSql()
+		if err != nil {
+			return domain.Actor{}, fmt.Errorf("building query: %w", err)
+		}
+		actor, err := scanActor(tx.QueryRowContext(ctx, query, args...))
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return domain.Actor{}, domain.ErrActorNotFound
+			}
+			return domain.Actor{}, fmt.Errorf("scanning actor: %w", err)
+		}
+		return actor, nil
+	}
+
+	keep, err := selectActor(keepID)
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return domain.ActorMergeResult{}, err
+	}
+	dup, err := selectActor(dupID)
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return domain.ActorMergeResult{}, err
+	}
+
+	// Дополняем незаполненные поля основного актёра данными дубликата
+	if keep.Name == "" {
+		keep.Name = dup.Name
+	}
+	if keep.Gender == "" {
+		keep.Gender = dup.Gender
+	}
+	if keep.BirthDate.IsZero() {
+		keep.BirthDate = dup.BirthDate
+	}
+	if keep.DeathDate == nil {
+		keep.DeathDate = dup.DeathDate
+	}
+	// Фото дубликата переходит основному актёру, если у того фото нет
+	var unusedPhotoKey string
+	if keep.PhotoKey == "" {
+		keep.PhotoKey = dup.PhotoKey
+	} else {
+		unusedPhotoKey = dup.PhotoKey
+	}
+
+	updQuery, updArgs, err := sq.Update("actors").
+		Set("name", keep.Name).
+		Set("gender", keep.Gender).
+		Set("birth_date", keep.BirthDate).
+		Set("death_date", keep.DeathDate).
+		Set("photo_key", keep.PhotoKey).
+		Where(sq.Eq{"id": keepID}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return domain.ActorMergeResult{}, fmt.Errorf("failed to build update actor query: %w", err)
+	}
+	if _, err = tx.ExecContext(ctx, updQuery, updArgs...); err != nil {
+		log.Printf("Error updating merged actor: %v", err)
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return domain.ActorMergeResult{}, fmt.Errorf("failed to update actor: %w", err)
+	}
+
+	// Переносим связи с фильмами; совпадающие пары пропускаются
+	moveQuery, moveArgs, err := sq.Insert("film_actor").
+		Columns("film_id", "actor_id", "character_name", "billing_order").
+		Select(sq.Select("film_id").
+			Column(sq.Expr("?", keepID)).
+			Columns("character_name", "billing_order").
+			From("film_actor").
+			Where(sq.Eq{"actor_id": dupID})).
+		Suffix("ON CONFLICT DO NOTHING").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return domain.ActorMergeResult{}, fmt.Errorf("failed to build reassign film_actor query: %w", err)
+	}
+	result, err := tx.ExecContext(ctx, moveQuery, moveArgs...)
+	if err != nil {
+		log.Printf("Error reassigning film_actor relations: %v", err)
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return domain.ActorMergeResult{}, fmt.Errorf("failed to reassign film_actor relations: %w", err)
+	}
+	reassigned, err := result.RowsAffected()
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return domain.ActorMergeResult{}, fmt.Errorf("getting rows affected: %w", err)
+	}
+
+	delLinks, delLinksArgs, err := sq.Delete("film_actor").
+		Where(sq.Eq{"actor_id": dupID}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return domain.ActorMergeResult{}, fmt.Errorf("failed to build delete film_actor query: %w", err)
+	}
+	if _, err = tx.ExecContext(ctx, delLinks, delLinksArgs...); err != nil {
+		log.Printf("Error deleting duplicate film_actor relations: %v", err)
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return domain.ActorMergeResult{}, fmt.Errorf("failed to delete film_actor relations: %w", err)
+	}
+
+	delActor, delActorArgs, err := sq.Delete("actors").
+		Where(sq.Eq{"id": dupID}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return domain.ActorMergeResult{}, fmt.Errorf("failed to build delete actor query: %w", err)
+	}
+	if _, err = tx.ExecContext(ctx, delActor, delActorArgs...); err != nil {
+		log.Printf("Error deleting duplicate actor: %v", err)
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return domain.ActorMergeResult{}, fmt.Errorf("failed to delete duplicate actor: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return domain.ActorMergeResult{}, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	dbQueryDurationSeconds.WithLabelValues(operation, queryType).Observe(time.Since(start).Seconds())
+	dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+	return domain.ActorMergeResult{
+		Actor:            keep,
+		DuplicateID:      dupID,
+		MoviesReassigned: int(reassigned),
+		UnusedPhotoKey:   unusedPhotoKey,
+	}, nil
+}
+
+// SetPhotoKey сохраняет ключ фотографии актёра в хранилище объектов; пустой ключ удаляет фото
+func (a *actor) SetPhotoKey(ctx context.Context, id int, key string) error {
+	start := time.Now()
+	operation := "set_actor_photo"
+	queryType := "UPDATE"
+	ctx, span := startSpan(ctx, operation, queryType)
+	defer span.End()
+
+	query, args, err := sq.Update("actors").
+		Set("photo_key", key).
+		Where(sq.Eq{"id": id}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return fmt.Errorf("building query: %w", err)
+	}
+	result, err := a.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return fmt.Errorf("updating actor photo: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return fmt.Errorf("getting rows affected: %w", err)
+	}
+	if affected == 0 {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return domain.ErrActorNotFound
+	}
+	dbQueryDurationSeconds.WithLabelValues(operation, queryType).Observe(time.Since(start).Seconds())
+	dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+	return nil
+}
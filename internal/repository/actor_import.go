@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"cinematique/internal/domain"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+// FindActorsByNames возвращает актёров, имя которых без учёта регистра совпадает с одним из names
+func (a *actor) FindActorsByNames(ctx context.Context, names []string) ([]domain.Actor, error) {
+	start := time.Now()
+	operation := "find_actors_by_names"
+	queryType := "SELECT"
+	ctx, span := startSpan(ctx, operation, queryType)
+	defer span.End()
+
+	if len(names) == 0 {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return []domain.Actor{}, nil
+	}
+	lowered := make([]string, 0, len(names))
+	for _, name := range names {
+		lowered = append(lowered, strings.ToLower(name))
+	}
+	query, args, err := sq.Select(actorColumns...).
+		From("actors").
+		Where(sq.Eq{"LOWER(name)": lowered}).
+		OrderBy("id ASC").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return nil, err
+	}
+	// Проверка дубликатов предшествует записи, поэтому читаем из основной базы, а не из отстающей реплики
+	rows, err := a.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return nil, err
+	}
+	defer rows.Close()
+	actors := make([]domain.Actor, 0)
+	for rows.Next() {
+		actor, err := scanActor(rows)
+		if err != nil {
+			dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+			return nil, err
+		}
+		actors = append(actors, actor)
+	}
+	if err := rows.Err(); err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return nil, err
+	}
+	dbQueryDurationSeconds.WithLabelValues(operation, queryType).Observe(time.Since(start).Seconds())
+	dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+	return actors, nil
+}
+
+// ImportActors создаёт актёров в одной транзакции и возвращает их ID в порядке входного списка.
+// Ошибка на любом актёре откатывает импорт целиком
+func (a *actor) ImportActors(ctx context.Context, actors []domain.Actor) ([]int, error) {
+	start := time.Now()
+	operation := "import_actors"
+	queryType := "INSERT"
+	ctx, span := startSpan(ctx, operation, queryType)
+	defer span.End()
+
+	tx, err := a.db.BeginTx(ctx, nil)
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	ids := make([]int, 0, len(actors))
+	for i, actor := range actors {
+		// Slug выбирается внутри транзакции: он должен учитывать актёров, созданных раньше в этом же импорте
+		actorSlug, err := freeSlug(ctx, tx, "actors", actorSlugBase(actor))
+		if err != nil {
+			dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+			return nil, fmt.Errorf("choosing slug for row %d: %w", i+1, err)
+		}
+		id, err := insertReturningID(ctx, tx, sq.Insert("actors").
+			Columns("name", "gender", "birth_date", "slug").
+			Values(actor.Name, actor.Gender, actor.BirthDate, actorSlug))
+		if err != nil {
+			dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+			return nil, fmt.Errorf("failed to import actor from row %d: %w", i+1, constraintError(err))
+		}
+		ids = append(ids, id)
+	}
+
+	if err := tx.Commit(); err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	dbQueryDurationSeconds.WithLabelValues(operation, queryType).Observe(time.Since(start).Seconds())
+	dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+	return ids, nil
+}
@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"cinematique/internal/domain"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestActorRepository_FindActorsByNames(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	birth := time.Date(1964, 9, 2, 0, 0, 0, 0, time.UTC)
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, name, gender, birth_date, death_date, photo_key, slug FROM actors WHERE LOWER(name) IN ($1,$2) ORDER BY id ASC")).
+		WithArgs("keanu reeves", "carrie-anne moss").
+		WillReturnRows(sqlmock.NewRows(actorColumns).AddRow(1, "Keanu Reeves", "male", birth, nil, "", "keanu-reeves"))
+
+	actors, err := NewActor(db).FindActorsByNames(context.Background(), []string{"Keanu Reeves", "Carrie-Anne Moss"})
+	require.NoError(t, err)
+	require.Len(t, actors, 1)
+	assert.Equal(t, 1, actors[0].ID)
+
+	// Без имён запрос не выполняется
+	actors, err = NewActor(db).FindActorsByNames(context.Background(), nil)
+	require.NoError(t, err)
+	assert.Empty(t, actors)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestActorRepository_ImportActors(t *testing.T) {
+	slugQuery := regexp.QuoteMeta("SELECT slug FROM actors WHERE (slug = $1 OR slug LIKE $2)")
+	insertQuery := regexp.QuoteMeta("INSERT INTO actors (name,gender,birth_date,slug) VALUES ($1,$2,$3,$4) RETURNING id")
+	birth := time.Date(1964, 9, 2, 0, 0, 0, 0, time.UTC)
+	actors := []domain.Actor{
+		{Name: "Keanu Reeves", Gender: "male", BirthDate: birth},
+		{Name: "Laurence Fishburne", Gender: "male", BirthDate: birth},
+	}
+
+	t.Run("commits all actors", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(slugQuery).WithArgs("keanu-reeves", "keanu-reeves-%").
+			WillReturnRows(sqlmock.NewRows([]string{"slug"}).AddRow("keanu-reeves"))
+		mock.ExpectQuery(insertQuery).WithArgs("Keanu Reeves", "male", birth, "keanu-reeves-2").
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(10))
+		mock.ExpectQuery(slugQuery).WithArgs("laurence-fishburne", "laurence-fishburne-%").
+			WillReturnRows(sqlmock.NewRows([]string{"slug"}))
+		mock.ExpectQuery(insertQuery).WithArgs("Laurence Fishburne", "male", birth, "laurence-fishburne").
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(11))
+		mock.ExpectCommit()
+
+		ids, err := NewActor(db).ImportActors(context.Background(), actors)
+		require.NoError(t, err)
+		assert.Equal(t, []int{10, 11}, ids)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("rolls back on failure", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(slugQuery).WillReturnRows(sqlmock.NewRows([]string{"slug"}))
+		mock.ExpectQuery(insertQuery).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(10))
+		mock.ExpectQuery(slugQuery).WillReturnRows(sqlmock.NewRows([]string{"slug"}))
+		mock.ExpectQuery(insertQuery).WillReturnError(&pq.Error{Code: pqUniqueViolation, Constraint: "actors_slug_key"})
+		mock.ExpectRollback()
+
+		_, err = NewActor(db).ImportActors(context.Background(), actors)
+		assert.ErrorIs(t, err, domain.ErrAlreadyExists)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -0,0 +1,168 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cinematique/internal/domain"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+// orphanActorCond отбирает актёров без связей с фильмами (анти-соединение с film_actor)
+const orphanActorCond = "NOT EXISTS (SELECT 1 FROM film_actor fa WHERE fa.actor_id = actors.id)"
+
+// GetOrphanActors возвращает страницу актёров, не связанных ни с одним фильмом, по возрастанию ID
+func (a *actor) GetOrphanActors(ctx context.Context, limit, offset int) ([]domain.Actor, error) {
+	start := time.Now()
+	operation := "get_orphan_actors"
+	queryType := "SELECT"
+	ctx, span := startSpan(ctx, operation, queryType)
+	defer span.End()
+
+	query, args, err := sq.Select(actorColumns...).
+		From("actors").
+		Where(orphanActorCond).
+		OrderBy("id ASC").
+		Limit(uint64(limit)).
+		Offset(uint64(offset)).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return nil, err
+	}
+	rows, err := a.replica.pick(a.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return nil, err
+	}
+	defer rows.Close()
+	actors := make([]domain.Actor, 0)
+	for rows.Next() {
+		actor, err := scanActor(rows)
+		if err != nil {
+			dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+			return nil, err
+		}
+		actors = append(actors, actor)
+	}
+	if err := rows.Err(); err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return nil, err
+	}
+	dbQueryDurationSeconds.WithLabelValues(operation, queryType).Observe(time.Since(start).Seconds())
+	dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+	return actors, nil
+}
+
+// CountOrphanActors возвращает число актёров без фильмов
+func (a *actor) CountOrphanActors(ctx context.Context) (int, error) {
+	start := time.Now()
+	operation := "count_orphan_actors"
+	queryType := "SELECT"
+	ctx, span := startSpan(ctx, operation, queryType)
+	defer span.End()
+
+	query, args, err := sq.Select("COUNT(*)").
+		From("actors").
+		Where(orphanActorCond).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return 0, err
+	}
+	var count int
+	if err := a.replica.pick(a.db).QueryRowContext(ctx, query, args...).Scan(&count); err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return 0, err
+	}
+	dbQueryDurationSeconds.WithLabelValues(operation, queryType).Observe(time.Since(start).Seconds())
+	dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+	return count, nil
+}
+
+// PurgeOrphanActors удаляет всех актёров без фильмов одной транзакцией и возвращает удалённых.
+// Если таких актёров больше maxCount, ничего не удаляется и возвращается *domain.OrphanPurgeError.
+// Отобранные строки блокируются до конца транзакции: проверка внешнего ключа при добавлении
+// актёра в фильм ждёт блокировку, поэтому актёр не получит фильм между отбором и удалением
+func (a *actor) PurgeOrphanActors(ctx context.Context, maxCount int) ([]domain.Actor, error) {
+	start := time.Now()
+	operation := "purge_orphan_actors"
+	queryType := "DELETE"
+	ctx, span := startSpan(ctx, operation, queryType)
+	defer span.End()
+
+	tx, err := a.db.BeginTx(ctx, nil)
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	selectQuery, selectArgs, err := sq.Select(actorColumns...).
+		From("actors").
+		Where(orphanActorCond).
+		OrderBy("id ASC").
+		Suffix("FOR UPDATE").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return nil, fmt.Errorf("building query: %w", err)
+	}
+	rows, err := tx.QueryContext(ctx, selectQuery, selectArgs...)
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return nil, fmt.Errorf("selecting orphan actors: %w", err)
+	}
+	orphans := make([]domain.Actor, 0)
+	ids := make([]int, 0)
+	for rows.Next() {
+		actor, err := scanActor(rows)
+		if err != nil {
+			rows.Close()
+			dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+			return nil, fmt.Errorf("scanning actor: %w", err)
+		}
+		orphans = append(orphans, actor)
+		ids = append(ids, actor.ID)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return nil, fmt.Errorf("selecting orphan actors: %w", err)
+	}
+	if len(orphans) > maxCount {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return nil, &domain.OrphanPurgeError{Count: len(orphans)}
+	}
+	if len(orphans) == 0 {
+		dbQueryDurationSeconds.WithLabelValues(operation, queryType).Observe(time.Since(start).Seconds())
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return orphans, nil
+	}
+
+	deleteQuery, deleteArgs, err := sq.Delete("actors").
+		Where(sq.Eq{"id": ids}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return nil, fmt.Errorf("building query: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, deleteQuery, deleteArgs...); err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return nil, fmt.Errorf("deleting orphan actors: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	dbQueryDurationSeconds.WithLabelValues(operation, queryType).Observe(time.Since(start).Seconds())
+	dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+	return orphans, nil
+}
@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"cinematique/internal/domain"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestActorRepository_GetOrphanActors(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewActor(db)
+	birthDate := time.Date(1970, time.May, 1, 0, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, name, gender, birth_date, death_date, photo_key, slug FROM actors WHERE NOT EXISTS (SELECT 1 FROM film_actor fa WHERE fa.actor_id = actors.id) ORDER BY id ASC LIMIT 20 OFFSET 40")).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "gender", "birth_date", "death_date", "photo_key", "slug"}).
+			AddRow(3, "Uncast Actor", "female", birthDate, nil, "", ""))
+	actors, err := repo.GetOrphanActors(context.Background(), 20, 40)
+	require.NoError(t, err)
+	require.Len(t, actors, 1)
+	assert.Equal(t, "Uncast Actor", actors[0].Name)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM actors WHERE NOT EXISTS (SELECT 1 FROM film_actor fa WHERE fa.actor_id = actors.id)")).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(41))
+	count, err := repo.CountOrphanActors(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 41, count)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestActorRepository_PurgeOrphanActors(t *testing.T) {
+	selectQuery := regexp.QuoteMeta("SELECT id, name, gender, birth_date, death_date, photo_key, slug FROM actors WHERE NOT EXISTS (SELECT 1 FROM film_actor fa WHERE fa.actor_id = actors.id) ORDER BY id ASC FOR UPDATE")
+	orphanRows := func() *sqlmock.Rows {
+		return sqlmock.NewRows([]string{"id", "name", "gender", "birth_date", "death_date", "photo_key", "slug"}).
+			AddRow(3, "First", "male", time.Time{}, nil, "actors/3/photo.jpg", "").
+			AddRow(8, "Second", "female", time.Time{}, nil, "", "")
+	}
+
+	t.Run("deletes selected actors", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(selectQuery).WillReturnRows(orphanRows())
+		mock.ExpectExec(regexp.QuoteMeta("DELETE FROM actors WHERE id IN ($1,$2)")).
+			WithArgs(3, 8).
+			WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectCommit()
+
+		purged, err := NewActor(db).PurgeOrphanActors(context.Background(), 2)
+		require.NoError(t, err)
+		require.Len(t, purged, 2)
+		assert.Equal(t, "actors/3/photo.jpg", purged[0].PhotoKey)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("rolls back above the limit", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(selectQuery).WillReturnRows(orphanRows())
+		mock.ExpectRollback()
+
+		_, err = NewActor(db).PurgeOrphanActors(context.Background(), 1)
+		var purgeErr *domain.OrphanPurgeError
+		require.ErrorAs(t, err, &purgeErr)
+		assert.Equal(t, 2, purgeErr.Count)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"cinematique/internal/domain"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+// actorRevisionData — профиль актёра в колонке data таблицы actor_revisions
+type actorRevisionData struct {
+	Name      string     `json:"name"`
+	Gender    string     `json:"gender"`
+	BirthDate time.Time  `json:"birth_date"`
+	DeathDate *time.Time `json:"death_date,omitempty"`
+}
+
+// AddActorRevision сохраняет версию актёра. У записи об удалении профиль не хранится
+func (a *actor) AddActorRevision(ctx context.Context, revision domain.ActorRevision) error {
+	start := time.Now()
+	operation := "add_actor_revision"
+	queryType := "INSERT"
+	ctx, span := startSpan(ctx, operation, queryType)
+	defer span.End()
+
+	data := []byte("{}")
+	if !revision.Deleted {
+		var err error
+		data, err = json.Marshal(actorRevisionData{
+			Name:      revision.Actor.Name,
+			Gender:    revision.Actor.Gender,
+			BirthDate: revision.Actor.BirthDate,
+			DeathDate: revision.Actor.DeathDate,
+		})
+		if err != nil {
+			dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+			return err
+		}
+	}
+	query, args, err := sq.Insert("actor_revisions").
+		Columns("actor_id", "data", "deleted").
+		Values(revision.ActorID, data, revision.Deleted).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return err
+	}
+	if _, err := a.db.ExecContext(ctx, query, args...); err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return err
+	}
+	dbQueryDurationSeconds.WithLabelValues(operation, queryType).Observe(time.Since(start).Seconds())
+	dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+	return nil
+}
+
+// GetActorRevisions возвращает версии актёра в хронологическом порядке
+func (a *actor) GetActorRevisions(ctx context.Context, actorID int) ([]domain.ActorRevision, error) {
+	start := time.Now()
+	operation := "get_actor_revisions"
+	queryType := "SELECT"
+	ctx, span := startSpan(ctx, operation, queryType)
+	defer span.End()
+
+	query, args, err := sq.Select("id", "actor_id", "changed_at", "data", "deleted").
+		From("actor_revisions").
+		Where(sq.Eq{"actor_id": actorID}).
+		OrderBy("changed_at ASC", "id ASC").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return nil, err
+	}
+	rows, err := a.replica.pick(a.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return nil, err
+	}
+	defer rows.Close()
+	revisions := make([]domain.ActorRevision, 0)
+	for rows.Next() {
+		var revision domain.ActorRevision
+		var raw []byte
+		if err := rows.Scan(&revision.ID, &revision.ActorID, &revision.ChangedAt, &raw, &revision.Deleted); err != nil {
+			dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+			return nil, err
+		}
+		if !revision.Deleted {
+			var data actorRevisionData
+			if err := json.Unmarshal(raw, &data); err != nil {
+				dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+				return nil, err
+			}
+			revision.Actor = domain.Actor{
+				ID:        revision.ActorID,
+				Name:      data.Name,
+				Gender:    data.Gender,
+				BirthDate: data.BirthDate,
+				DeathDate: data.DeathDate,
+			}
+		}
+		revisions = append(revisions, revision)
+	}
+	if err := rows.Err(); err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return nil, err
+	}
+	dbQueryDurationSeconds.WithLabelValues(operation, queryType).Observe(time.Since(start).Seconds())
+	dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+	return revisions, nil
+}
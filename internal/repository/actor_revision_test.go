@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"testing"
+	"time"
+
+	"cinematique/internal/domain"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestActorRepository_AddActorRevision(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewActor(db)
+	query := regexp.QuoteMeta("INSERT INTO actor_revisions (actor_id,data,deleted) VALUES ($1,$2,$3)")
+	birth := time.Date(1964, time.September, 2, 0, 0, 0, 0, time.UTC)
+
+	mock.ExpectExec(query).
+		WithArgs(1, []byte(`{"name":"Keanu Reeves","gender":"male","birth_date":"1964-09-02T00:00:00Z"}`), false).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	err = repo.AddActorRevision(context.Background(), domain.ActorRevision{
+		ActorID: 1,
+		Actor:   domain.Actor{ID: 1, Name: "Keanu Reeves", Gender: "male", BirthDate: birth, Slug: "keanu-reeves"},
+	})
+	assert.NoError(t, err)
+
+	mock.ExpectExec(query).
+		WithArgs(2, []byte(`{}`), true).
+		WillReturnError(sql.ErrConnDone)
+	err = repo.AddActorRevision(context.Background(), domain.ActorRevision{ActorID: 2, Deleted: true})
+	assert.Error(t, err)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestActorRepository_GetActorRevisions(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewActor(db)
+	query := regexp.QuoteMeta("SELECT id, actor_id, changed_at, data, deleted FROM actor_revisions WHERE actor_id = $1 ORDER BY changed_at ASC, id ASC")
+	created := time.Date(2023, time.May, 1, 10, 0, 0, 0, time.UTC)
+	deleted := time.Date(2023, time.June, 1, 10, 0, 0, 0, time.UTC)
+	death := time.Date(2023, time.April, 3, 0, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery(query).WithArgs(7).WillReturnRows(sqlmock.NewRows([]string{"id", "actor_id", "changed_at", "data", "deleted"}).
+		AddRow(1, 7, created, []byte(`{"name":"Lance Reddick","gender":"male","birth_date":"1962-06-07T00:00:00Z","death_date":"2023-04-03T00:00:00Z"}`), false).
+		AddRow(2, 7, deleted, []byte(`{}`), true))
+	revisions, err := repo.GetActorRevisions(context.Background(), 7)
+	require.NoError(t, err)
+	assert.Equal(t, []domain.ActorRevision{
+		{ID: 1, ActorID: 7, ChangedAt: created, Actor: domain.Actor{
+			ID: 7, Name: "Lance Reddick", Gender: "male",
+			BirthDate: time.Date(1962, time.June, 7, 0, 0, 0, 0, time.UTC), DeathDate: &death,
+		}},
+		{ID: 2, ActorID: 7, ChangedAt: deleted, Deleted: true},
+	}, revisions)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
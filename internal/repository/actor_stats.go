@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"cinematique/internal/domain"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+// GetActorStats считает число фильмов актёра, средний рейтинг, первый и последний год выхода
+// одним агрегирующим запросом. LEFT JOIN оставляет строку актёра без фильмов, поэтому
+// отсутствие строки означает, что актёра нет. Фильмы без года (0) в диапазон лет не входят
+func (a *actor) GetActorStats(ctx context.Context, actorID int) (domain.ActorStats, error) {
+	start := time.Now()
+	operation := "get_actor_stats"
+	queryType := "SELECT"
+	ctx, span := startSpan(ctx, operation, queryType)
+	defer span.End()
+
+	query, args, err := sq.Select(
+		"a.id",
+		"COUNT(f.id)",
+		"AVG(f.rating)",
+		"MIN(NULLIF(f.release_year, 0))",
+		"MAX(NULLIF(f.release_year, 0))",
+	).
+		From("actors a").
+		LeftJoin("film_actor fa ON fa.actor_id = a.id").
+		LeftJoin("films f ON f.id = fa.film_id").
+		Where(sq.Eq{"a.id": actorID}).
+		GroupBy("a.id").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return domain.ActorStats{}, err
+	}
+
+	var stats domain.ActorStats
+	var averageRating sql.NullFloat64
+	var firstYear, latestYear sql.NullInt64
+	err = a.replica.pick(a.db).QueryRowContext(ctx, query, args...).
+		Scan(&stats.ActorID, &stats.MovieCount, &averageRating, &firstYear, &latestYear)
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.ActorStats{}, domain.ErrActorNotFound
+		}
+		return domain.ActorStats{}, err
+	}
+	if averageRating.Valid {
+		stats.AverageRating = &averageRating.Float64
+	}
+	stats.FirstYear = int(firstYear.Int64)
+	stats.LatestYear = int(latestYear.Int64)
+
+	dbQueryDurationSeconds.WithLabelValues(operation, queryType).Observe(time.Since(start).Seconds())
+	dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+	return stats, nil
+}
@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"testing"
+
+	"cinematique/internal/domain"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestActorRepository_GetActorStats(t *testing.T) {
+	query := regexp.QuoteMeta("SELECT a.id, COUNT(f.id), AVG(f.rating), MIN(NULLIF(f.release_year, 0)), MAX(NULLIF(f.release_year, 0)) " +
+		"FROM actors a LEFT JOIN film_actor fa ON fa.actor_id = a.id LEFT JOIN films f ON f.id = fa.film_id WHERE a.id = $1 GROUP BY a.id")
+	columns := []string{"id", "count", "avg", "min", "max"}
+
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	repo := NewActor(db)
+
+	mock.ExpectQuery(query).WithArgs(1).WillReturnRows(sqlmock.NewRows(columns).AddRow(1, 3, 8.1, 1994, 2019))
+	stats, err := repo.GetActorStats(context.Background(), 1)
+	require.NoError(t, err)
+	averageRating := 8.1
+	assert.Equal(t, domain.ActorStats{ActorID: 1, MovieCount: 3, AverageRating: &averageRating, FirstYear: 1994, LatestYear: 2019}, stats)
+
+	// Актёр без фильмов: агрегаты по пустому набору равны NULL
+	mock.ExpectQuery(query).WithArgs(2).WillReturnRows(sqlmock.NewRows(columns).AddRow(2, 0, nil, nil, nil))
+	stats, err = repo.GetActorStats(context.Background(), 2)
+	require.NoError(t, err)
+	assert.Equal(t, domain.ActorStats{ActorID: 2}, stats)
+
+	mock.ExpectQuery(query).WithArgs(999).WillReturnError(sql.ErrNoRows)
+	_, err = repo.GetActorStats(context.Background(), 999)
+	assert.ErrorIs(t, err, domain.ErrActorNotFound)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
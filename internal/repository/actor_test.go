@@ -2,8 +2,10 @@ package repository
 
 import (
 	"cinematique/internal/domain"
+	"context"
 	"database/sql"
 	"github.com/stretchr/testify/assert"
+	"regexp"
 	"testing"
 	"time"
 
@@ -34,8 +36,11 @@ func TestActorRepository_Create(t *testing.T) {
 				BirthDate: birthDate,
 			},
 			setup: func() {
-				mock.ExpectQuery(`INSERT INTO actors \(name,gender,birth_date\) VALUES \(\$1,\$2,\$3\) RETURNING id`).
-					WithArgs("Leonardo DiCaprio", "male", birthDate).
+				mock.ExpectQuery(`SELECT slug FROM actors WHERE \(slug = \$1 OR slug LIKE \$2\)`).
+					WithArgs("leonardo-dicaprio", "leonardo-dicaprio-%").
+					WillReturnRows(sqlmock.NewRows([]string{"slug"}).AddRow("leonardo-dicaprio").AddRow("leonardo-dicaprio-jr"))
+				mock.ExpectQuery(`INSERT INTO actors \(name,gender,birth_date,death_date,slug\) VALUES \(\$1,\$2,\$3,\$4,\$5\) RETURNING id`).
+					WithArgs("Leonardo DiCaprio", "male", birthDate, nil, "leonardo-dicaprio-2").
 					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
 			},
 			wantID: 1,
@@ -48,7 +53,7 @@ func TestActorRepository_Create(t *testing.T) {
 				tt.setup()
 			}
 
-			gotID, err := repo.Create(tt.actor)
+			gotID, err := repo.Create(context.Background(), tt.actor)
 
 			if tt.wantErr {
 				assert.Error(t, err)
@@ -69,6 +74,7 @@ func TestActorRepository_GetByID(t *testing.T) {
 
 	repo := NewActor(db)
 	birthDate, _ := time.Parse("2006-01-02", "1980-01-01")
+	updatedAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
 
 	tests := []struct {
 		name    string
@@ -81,9 +87,9 @@ func TestActorRepository_GetByID(t *testing.T) {
 			name: "actor found",
 			id:   1,
 			setup: func() {
-				rows := sqlmock.NewRows([]string{"id", "name", "gender", "birth_date"}).
-					AddRow(1, "Leonardo DiCaprio", "male", birthDate)
-				mock.ExpectQuery(`^SELECT id, name, gender, birth_date FROM actors WHERE id = \$1$`).
+				rows := sqlmock.NewRows([]string{"id", "name", "gender", "birth_date", "death_date", "photo_key", "slug", "updated_at"}).
+					AddRow(1, "Leonardo DiCaprio", "male", birthDate, nil, "", "", updatedAt)
+				mock.ExpectQuery(`^SELECT id, name, gender, birth_date, death_date, photo_key, slug, updated_at FROM actors WHERE id = \$1$`).
 					WithArgs(1).
 					WillReturnRows(rows)
 			},
@@ -92,6 +98,7 @@ func TestActorRepository_GetByID(t *testing.T) {
 				Name:      "Leonardo DiCaprio",
 				Gender:    "male",
 				BirthDate: birthDate,
+				UpdatedAt: updatedAt,
 			},
 		},
 		{
@@ -112,7 +119,7 @@ func TestActorRepository_GetByID(t *testing.T) {
 				tt.setup()
 			}
 
-			got, err := repo.GetByID(tt.id)
+			got, err := repo.GetByID(context.Background(), tt.id)
 
 			if tt.wantErr {
 				assert.Error(t, err)
@@ -149,8 +156,8 @@ func TestActorRepository_Update(t *testing.T) {
 				BirthDate: birthDate,
 			},
 			setup: func() {
-				mock.ExpectExec(`UPDATE actors SET name = \$1, gender = \$2, birth_date = \$3 WHERE id = \$4`).
-					WithArgs("Leonardo DiCaprio Updated", "male", birthDate, 1).
+				mock.ExpectExec(`UPDATE actors SET name = \$1, gender = \$2, birth_date = \$3, death_date = \$4 WHERE id = \$5`).
+					WithArgs("Leonardo DiCaprio Updated", "male", birthDate, nil, 1).
 					WillReturnResult(sqlmock.NewResult(0, 1))
 			},
 		},
@@ -160,8 +167,8 @@ func TestActorRepository_Update(t *testing.T) {
 				ID: 999,
 			},
 			setup: func() {
-				mock.ExpectExec(`UPDATE actors SET name = \$1, gender = \$2, birth_date = \$3 WHERE id = \$4`).
-					WithArgs("", "", time.Time{}, 999).
+				mock.ExpectExec(`UPDATE actors SET name = \$1, gender = \$2, birth_date = \$3, death_date = \$4 WHERE id = \$5`).
+					WithArgs("", "", time.Time{}, nil, 999).
 					WillReturnResult(sqlmock.NewResult(0, 0))
 			},
 			wantErr: true,
@@ -174,7 +181,7 @@ func TestActorRepository_Update(t *testing.T) {
 				tt.setup()
 			}
 
-			err := repo.Update(tt.actor)
+			err := repo.Update(context.Background(), tt.actor)
 
 			if tt.wantErr {
 				assert.Error(t, err)
@@ -205,10 +212,10 @@ func TestActorRepository_Delete(t *testing.T) {
 			id:   1,
 			setup: func() {
 				// Мок для проверки существования актёра
-				mock.ExpectQuery(`^SELECT id, name, gender, birth_date FROM actors WHERE id = \$1$`).
+				mock.ExpectQuery(`^SELECT id, name, gender, birth_date, death_date, photo_key, slug, updated_at FROM actors WHERE id = \$1$`).
 					WithArgs(1).
-					WillReturnRows(sqlmock.NewRows([]string{"id", "name", "gender", "birth_date"}).
-						AddRow(1, "Test Actor", "male", time.Now()))
+					WillReturnRows(sqlmock.NewRows(actorDetailColumns).
+						AddRow(1, "Test Actor", "male", time.Now(), nil, "", "", time.Now()))
 
 				mock.ExpectBegin()
 				mock.ExpectExec(`^DELETE FROM film_actor WHERE actor_id = \$1$`).
@@ -225,7 +232,7 @@ func TestActorRepository_Delete(t *testing.T) {
 			id:   999,
 			setup: func() {
 				// Мок для проверки несуществующего актёра
-				mock.ExpectQuery(`^SELECT id, name, gender, birth_date FROM actors WHERE id = \$1$`).
+				mock.ExpectQuery(`^SELECT id, name, gender, birth_date, death_date, photo_key, slug, updated_at FROM actors WHERE id = \$1$`).
 					WithArgs(999).
 					WillReturnError(sql.ErrNoRows)
 			},
@@ -239,7 +246,7 @@ func TestActorRepository_Delete(t *testing.T) {
 				tt.setup()
 			}
 
-			err := repo.Delete(tt.id)
+			err := repo.Delete(context.Background(), tt.id)
 
 			if tt.wantErr {
 				assert.Error(t, err)
@@ -270,10 +277,10 @@ func TestActorRepository_GetAll(t *testing.T) {
 		{
 			name: "get all actors",
 			setup: func() {
-				rows := sqlmock.NewRows([]string{"id", "name", "gender", "birth_date"}).
-					AddRow(1, "Leonardo DiCaprio", "male", birthDate1).
-					AddRow(2, "Scarlett Johansson", "female", birthDate2)
-				mock.ExpectQuery(`^SELECT id, name, gender, birth_date FROM actors$`).
+				rows := sqlmock.NewRows([]string{"id", "name", "gender", "birth_date", "death_date", "photo_key", "slug"}).
+					AddRow(1, "Leonardo DiCaprio", "male", birthDate1, nil, "", "").
+					AddRow(2, "Scarlett Johansson", "female", birthDate2, nil, "", "")
+				mock.ExpectQuery(`^SELECT id, name, gender, birth_date, death_date, photo_key, slug FROM actors$`).
 					WillReturnRows(rows)
 			},
 			want: []domain.Actor{
@@ -299,7 +306,7 @@ func TestActorRepository_GetAll(t *testing.T) {
 				tt.setup()
 			}
 
-			got, err := repo.GetAll()
+			got, err := repo.GetAll(context.Background(), domain.ActorFilter{})
 
 			if tt.wantErr {
 				assert.Error(t, err)
@@ -313,6 +320,152 @@ func TestActorRepository_GetAll(t *testing.T) {
 	}
 }
 
+func TestActorRepository_SearchActorsByName(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewActor(db)
+	birthDate, _ := time.Parse("2006-01-02", "1964-09-02")
+
+	t.Run("paginated matches", func(t *testing.T) {
+		rows := sqlmock.NewRows([]string{"id", "name", "gender", "birth_date", "death_date", "photo_key", "slug"}).
+			AddRow(7, "Keanu Reeves", "male", birthDate, nil, "", "")
+		mock.ExpectQuery(`^SELECT id, name, gender, birth_date, death_date, photo_key, slug FROM actors WHERE name ILIKE \$1 ORDER BY name ASC, id ASC LIMIT \$2 OFFSET \$3$`).
+			WithArgs("%reev%", 10, 20).
+			WillReturnRows(rows)
+
+		got, err := repo.SearchActorsByName(context.Background(), "reev", domain.ActorFilter{}, 10, 20)
+		require.NoError(t, err)
+		assert.Equal(t, []domain.Actor{{ID: 7, Name: "Keanu Reeves", Gender: "male", BirthDate: birthDate}}, got)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("no matches returns empty slice", func(t *testing.T) {
+		mock.ExpectQuery(`^SELECT id, name, gender, birth_date, death_date, photo_key, slug FROM actors WHERE name ILIKE \$1 ORDER BY name ASC, id ASC$`).
+			WithArgs("%zzz%").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name", "gender", "birth_date", "death_date", "photo_key", "slug"}))
+
+		got, err := repo.SearchActorsByName(context.Background(), "zzz", domain.ActorFilter{}, 0, 0)
+		require.NoError(t, err)
+		assert.NotNil(t, got)
+		assert.Empty(t, got)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("empty fragment pages through all actors", func(t *testing.T) {
+		mock.ExpectQuery(`^SELECT id, name, gender, birth_date, death_date, photo_key, slug FROM actors ORDER BY name ASC, id ASC LIMIT \$1$`).
+			WithArgs(20).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name", "gender", "birth_date", "death_date", "photo_key", "slug"}).
+				AddRow(7, "Keanu Reeves", "male", birthDate, nil, "", ""))
+
+		got, err := repo.SearchActorsByName(context.Background(), "", domain.ActorFilter{}, 20, 0)
+		require.NoError(t, err)
+		assert.Len(t, got, 1)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("living actors only", func(t *testing.T) {
+		deathDate := time.Date(2021, time.June, 1, 0, 0, 0, 0, time.UTC)
+		mock.ExpectQuery(`^SELECT id, name, gender, birth_date, death_date, photo_key, slug FROM actors WHERE name ILIKE \$1 AND death_date IS NULL ORDER BY name ASC, id ASC LIMIT \$2$`).
+			WithArgs("%reev%", 10).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name", "gender", "birth_date", "death_date", "photo_key", "slug"}).
+				AddRow(7, "Keanu Reeves", "male", birthDate, nil, "", ""))
+
+		got, err := repo.SearchActorsByName(context.Background(), "reev", domain.ActorFilter{Living: true}, 10, 0)
+		require.NoError(t, err)
+		assert.Len(t, got, 1)
+		assert.NoError(t, mock.ExpectationsWereMet())
+
+		// Без фильтра дата смерти читается в ответ
+		mock.ExpectQuery(`^SELECT .* FROM actors WHERE name ILIKE \$1 ORDER BY`).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name", "gender", "birth_date", "death_date", "photo_key", "slug"}).
+				AddRow(8, "Keanu Reeves Sr", "male", birthDate, deathDate, "", ""))
+		got, err = repo.SearchActorsByName(context.Background(), "reev", domain.ActorFilter{}, 10, 0)
+		require.NoError(t, err)
+		require.Len(t, got, 1)
+		assert.Equal(t, &deathDate, got[0].DeathDate)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("query error", func(t *testing.T) {
+		mock.ExpectQuery(`^SELECT .* FROM actors WHERE name ILIKE`).
+			WillReturnError(sql.ErrConnDone)
+
+		_, err := repo.SearchActorsByName(context.Background(), "reev", domain.ActorFilter{}, 10, 0)
+		assert.ErrorIs(t, err, sql.ErrConnDone)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestActorRepository_CountActors(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewActor(db)
+
+	mock.ExpectQuery(`^SELECT COUNT\(\*\) FROM actors$`).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(42))
+	total, err := repo.CountActors(context.Background(), "", domain.ActorFilter{})
+	require.NoError(t, err)
+	assert.Equal(t, 42, total)
+
+	mock.ExpectQuery(`^SELECT COUNT\(\*\) FROM actors WHERE name ILIKE \$1 AND death_date IS NULL$`).
+		WithArgs("%reev%").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+	total, err = repo.CountActors(context.Background(), "reev", domain.ActorFilter{Living: true})
+	require.NoError(t, err)
+	assert.Equal(t, 3, total)
+
+	mock.ExpectQuery(`^SELECT COUNT\(\*\) FROM actors`).WillReturnError(sql.ErrConnDone)
+	_, err = repo.CountActors(context.Background(), "", domain.ActorFilter{})
+	assert.ErrorIs(t, err, sql.ErrConnDone)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestActorRepository_SuggestActors(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewActor(db)
+
+	t.Run("prefix of name or word", func(t *testing.T) {
+		mock.ExpectQuery(`^SELECT id, name FROM actors WHERE \(LOWER\(name\) LIKE \$1 OR name ILIKE \$2\) ORDER BY LOWER\(name\) LIKE \$3 DESC, name ASC, id ASC LIMIT \$4$`).
+			WithArgs("leo%", "% leo%", "leo%", 10).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).
+				AddRow(3, "Leonardo DiCaprio").
+				AddRow(9, "Jean Leo"))
+
+		got, err := repo.SuggestActors(context.Background(), "Leo", 10)
+		require.NoError(t, err)
+		assert.Equal(t, []domain.Actor{{ID: 3, Name: "Leonardo DiCaprio"}, {ID: 9, Name: "Jean Leo"}}, got)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("wildcards are matched literally", func(t *testing.T) {
+		mock.ExpectQuery(`^SELECT id, name FROM actors`).
+			WithArgs(`50\%%`, `% 50\%%`, `50\%%`, 5).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name"}))
+
+		got, err := repo.SuggestActors(context.Background(), "50%", 5)
+		require.NoError(t, err)
+		assert.NotNil(t, got)
+		assert.Empty(t, got)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("query error", func(t *testing.T) {
+		mock.ExpectQuery(`^SELECT id, name FROM actors`).
+			WillReturnError(sql.ErrConnDone)
+
+		_, err := repo.SuggestActors(context.Background(), "leo", 10)
+		assert.ErrorIs(t, err, sql.ErrConnDone)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
 func TestActorRepository_PartialUpdateActor(t *testing.T) {
 	newName := "Brad Pitt"
 	birthDate, _ := time.Parse("2006-01-02", "1980-01-01")
@@ -330,9 +483,9 @@ func TestActorRepository_PartialUpdateActor(t *testing.T) {
 			update: domain.ActorUpdate{Name: &newName},
 			setup: func(mock sqlmock.Sqlmock) {
 				// First expect the actor existence check
-				mock.ExpectQuery(`^SELECT id, name, gender, birth_date FROM actors WHERE id = \$1$`).
+				mock.ExpectQuery(`^SELECT id, name, gender, birth_date, death_date, photo_key, slug, updated_at FROM actors WHERE id = \$1$`).
 					WithArgs(1).
-					WillReturnRows(sqlmock.NewRows([]string{"id", "name", "gender", "birth_date"}).AddRow(1, "Old Name", "male", birthDate))
+					WillReturnRows(sqlmock.NewRows(actorDetailColumns).AddRow(1, "Old Name", "male", birthDate, nil, "", "", birthDate))
 
 				// Then expect the column existence check with a flexible regex pattern
 				expectedSQL := `SELECT EXISTS \(\s*SELECT 1\s+FROM information_schema\.columns\s+WHERE table_name = \$1 AND column_name = \$2\s*\)`
@@ -351,7 +504,7 @@ func TestActorRepository_PartialUpdateActor(t *testing.T) {
 			id:     999,
 			update: domain.ActorUpdate{Name: &newName},
 			setup: func(mock sqlmock.Sqlmock) {
-				mock.ExpectQuery(`^SELECT id, name, gender, birth_date FROM actors WHERE id = \$1$`).
+				mock.ExpectQuery(`^SELECT id, name, gender, birth_date, death_date, photo_key, slug, updated_at FROM actors WHERE id = \$1$`).
 					WithArgs(999).
 					WillReturnError(sql.ErrNoRows)
 			},
@@ -362,7 +515,7 @@ func TestActorRepository_PartialUpdateActor(t *testing.T) {
 			id:     1,
 			update: domain.ActorUpdate{Name: &newName},
 			setup: func(mock sqlmock.Sqlmock) {
-				mock.ExpectQuery(`^SELECT id, name, gender, birth_date FROM actors WHERE id = \$1$`).
+				mock.ExpectQuery(`^SELECT id, name, gender, birth_date, death_date, photo_key, slug, updated_at FROM actors WHERE id = \$1$`).
 					WithArgs(1).
 					WillReturnError(sql.ErrConnDone)
 			},
@@ -382,7 +535,7 @@ func TestActorRepository_PartialUpdateActor(t *testing.T) {
 				tt.setup(mock)
 			}
 
-			err = repo.PartialUpdateActor(tt.id, tt.update)
+			err = repo.PartialUpdateActor(context.Background(), tt.id, tt.update)
 
 			if tt.wantErr {
 				require.Error(t, err)
@@ -412,11 +565,11 @@ func TestActorRepository_GetMovies(t *testing.T) {
 			name:    "get movies for actor",
 			actorID: 1,
 			setup: func() {
-				rows := sqlmock.NewRows([]string{"id", "title", "description", "release_year", "rating"}).
-					AddRow(1, "Inception", "A thief who steals corporate secrets...", 2010, 8.8).
-					AddRow(2, "The Revenant", "A frontiersman on a fur trading...", 2015, 8.0)
+				rows := sqlmock.NewRows([]string{"id", "title", "description", "release_year", "rating", "release_date", "view_count", "original_language", "country", "runtime_minutes", "slug"}).
+					AddRow(1, "Inception", "A thief who steals corporate secrets...", 2010, 8.8, nil, 0, "", "", 0, "").
+					AddRow(2, "The Revenant", "A frontiersman on a fur trading...", 2015, 8.0, nil, 0, "", "", 0, "")
 
-				mock.ExpectQuery(`^SELECT f\.id, f\.title, f\.description, f\.release_year, f\.rating FROM films f JOIN film_actor fa ON f\.id = fa\.film_id WHERE fa\.actor_id = \$1$`).
+				mock.ExpectQuery(`^SELECT f\.id, f\.title, f\.description, f\.release_year, f\.rating, f\.release_date, f\.view_count, f\.original_language, f\.country, f\.runtime_minutes, f\.slug FROM films f JOIN film_actor fa ON f\.id = fa\.film_id WHERE fa\.actor_id = \$1$`).
 					WithArgs(1).
 					WillReturnRows(rows)
 			},
@@ -443,7 +596,7 @@ func TestActorRepository_GetMovies(t *testing.T) {
 			setup: func() {
 				mock.ExpectQuery(`^SELECT`).
 					WithArgs(2).
-					WillReturnRows(sqlmock.NewRows([]string{"id", "title", "description", "release_year", "rating"}))
+					WillReturnRows(sqlmock.NewRows([]string{"id", "title", "description", "release_year", "rating", "release_date", "view_count", "original_language", "country", "runtime_minutes", "slug"}))
 			},
 			want: []domain.Movie{},
 		},
@@ -465,7 +618,7 @@ func TestActorRepository_GetMovies(t *testing.T) {
 				tt.setup()
 			}
 
-			got, err := repo.GetMovies(tt.actorID)
+			got, err := repo.GetMovies(context.Background(), tt.actorID)
 
 			if tt.wantErr {
 				assert.Error(t, err)
@@ -490,6 +643,7 @@ func TestActorRepository_GetAllActorsWithMovies(t *testing.T) {
 
 	tests := []struct {
 		name    string
+		query   domain.ActorsWithMoviesQuery
 		setup   func()
 		want    []domain.Actor
 		wantErr bool
@@ -501,11 +655,16 @@ func TestActorRepository_GetAllActorsWithMovies(t *testing.T) {
 					"a.id", "a.name", "a.gender", "a.birth_date",
 					"f.id", "f.title", "f.description", "f.release_year", "f.rating",
 				}).
-					AddRow(1, "Leonardo DiCaprio", "male", birthDate1, 1, "Inception", "A thief...", 2010, 8.8).
 					AddRow(1, "Leonardo DiCaprio", "male", birthDate1, 2, "The Revenant", "A frontiersman...", 2015, 8.0).
-					AddRow(2, "Scarlett Johansson", "female", birthDate2, 3, "Lost in Translation", "A faded movie star...", 2003, 7.7)
-
-				mock.ExpectQuery(`^SELECT a\.id, a\.name, a\.gender, a\.birth_date, f\.id, f\.title, f\.description, f\.release_year, f\.rating FROM actors a LEFT JOIN film_actor fa ON a\.id = fa\.actor_id LEFT JOIN films f ON fa\.film_id = f\.id ORDER BY a\.id, f\.id$`).
+					AddRow(1, "Leonardo DiCaprio", "male", birthDate1, 1, "Inception", "A thief...", 2010, 8.8).
+					AddRow(2, "Scarlett Johansson", "female", birthDate2, 3, "Lost in Translation", "A faded movie star...", 2003, 7.7).
+					AddRow(3, "Newcomer", "female", birthDate2, nil, nil, nil, nil, nil)
+
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT a.id, a.name, a.gender, a.birth_date, m.id, m.title, m.description, m.release_year, m.rating " +
+					"FROM (SELECT id, name, gender, birth_date FROM actors ORDER BY id) AS a " +
+					"LEFT JOIN LATERAL (SELECT f.id, f.title, f.description, f.release_year, f.rating FROM film_actor fa JOIN films f ON f.id = fa.film_id " +
+					"WHERE fa.actor_id = a.id ORDER BY f.release_year DESC, f.id DESC) m ON TRUE " +
+					"ORDER BY a.id, m.release_year DESC, m.id DESC")).
 					WillReturnRows(rows)
 			},
 			want: []domain.Actor{
@@ -515,13 +674,6 @@ func TestActorRepository_GetAllActorsWithMovies(t *testing.T) {
 					Gender:    "male",
 					BirthDate: birthDate1,
 					Movies: []domain.Movie{
-						{
-							ID:          1,
-							Title:       "Inception",
-							Description: "A thief...",
-							ReleaseYear: 2010,
-							Rating:      8.8,
-						},
 						{
 							ID:          2,
 							Title:       "The Revenant",
@@ -529,6 +681,13 @@ func TestActorRepository_GetAllActorsWithMovies(t *testing.T) {
 							ReleaseYear: 2015,
 							Rating:      8.0,
 						},
+						{
+							ID:          1,
+							Title:       "Inception",
+							Description: "A thief...",
+							ReleaseYear: 2010,
+							Rating:      8.8,
+						},
 					},
 				},
 				{
@@ -546,6 +705,40 @@ func TestActorRepository_GetAllActorsWithMovies(t *testing.T) {
 						},
 					},
 				},
+				{
+					ID:        3,
+					Name:      "Newcomer",
+					Gender:    "female",
+					BirthDate: birthDate2,
+					Movies:    []domain.Movie{},
+				},
+			},
+		},
+		{
+			name:  "page of actors filtered by name with latest movies",
+			query: domain.ActorsWithMoviesQuery{Name: "leo", Limit: 10, Offset: 20, MoviesPerActor: 1},
+			setup: func() {
+				rows := sqlmock.NewRows([]string{
+					"a.id", "a.name", "a.gender", "a.birth_date",
+					"f.id", "f.title", "f.description", "f.release_year", "f.rating",
+				}).
+					AddRow(1, "Leonardo DiCaprio", "male", birthDate1, 2, "The Revenant", "A frontiersman...", 2015, 8.0)
+
+				// Страница выбирается по актёрам, число фильмов ограничивается внутри LATERAL
+				mock.ExpectQuery(regexp.QuoteMeta("FROM (SELECT id, name, gender, birth_date FROM actors WHERE name ILIKE $1 ORDER BY id LIMIT $2 OFFSET $3) AS a "+
+					"LEFT JOIN LATERAL (SELECT f.id, f.title, f.description, f.release_year, f.rating FROM film_actor fa JOIN films f ON f.id = fa.film_id "+
+					"WHERE fa.actor_id = a.id ORDER BY f.release_year DESC, f.id DESC LIMIT $4) m ON TRUE")).
+					WithArgs("%leo%", 10, 20, 1).
+					WillReturnRows(rows)
+			},
+			want: []domain.Actor{
+				{
+					ID:        1,
+					Name:      "Leonardo DiCaprio",
+					Gender:    "male",
+					BirthDate: birthDate1,
+					Movies:    []domain.Movie{{ID: 2, Title: "The Revenant", Description: "A frontiersman...", ReleaseYear: 2015, Rating: 8.0}},
+				},
 			},
 		},
 		{
@@ -564,7 +757,7 @@ func TestActorRepository_GetAllActorsWithMovies(t *testing.T) {
 				tt.setup()
 			}
 
-			got, err := repo.GetAllActorsWithMovies()
+			got, err := repo.GetAllActorsWithMovies(context.Background(), tt.query)
 
 			if tt.wantErr {
 				assert.Error(t, err)
@@ -577,3 +770,130 @@ func TestActorRepository_GetAllActorsWithMovies(t *testing.T) {
 		})
 	}
 }
+
+func TestActorRepository_MergeActors(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewActor(db)
+	birthDate, _ := time.Parse("2006-01-02", "1974-11-11")
+	deathDate := time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		keepID  int
+		dupID   int
+		setup   func()
+		want    domain.ActorMergeResult
+		wantErr error
+	}{
+		{
+			name:   "successful merge fills empty fields",
+			keepID: 1,
+			dupID:  2,
+			setup: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery(`^SELECT id, name, gender, birth_date, death_date, photo_key, slug FROM actors WHERE id = \$1 FOR UPDATE$`).
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "name", "gender", "birth_date", "death_date", "photo_key", "slug"}).
+						AddRow(1, "Leonardo DiCaprio", "", time.Time{}, nil, "", ""))
+				mock.ExpectQuery(`^SELECT id, name, gender, birth_date, death_date, photo_key, slug FROM actors WHERE id = \$1 FOR UPDATE$`).
+					WithArgs(2).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "name", "gender", "birth_date", "death_date", "photo_key", "slug"}).
+						AddRow(2, "Leo DiCaprio", "male", birthDate, deathDate, "actors/2/photo.jpg", ""))
+				mock.ExpectExec(`^UPDATE actors SET name = \$1, gender = \$2, birth_date = \$3, death_date = \$4, photo_key = \$5 WHERE id = \$6$`).
+					WithArgs("Leonardo DiCaprio", "male", birthDate, deathDate, "actors/2/photo.jpg", 1).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(`^INSERT INTO film_actor \(film_id,actor_id,character_name,billing_order\) SELECT film_id, \$1, character_name, billing_order FROM film_actor WHERE actor_id = \$2 ON CONFLICT DO NOTHING$`).
+					WithArgs(1, 2).
+					WillReturnResult(sqlmock.NewResult(0, 3))
+				mock.ExpectExec(`^DELETE FROM film_actor WHERE actor_id = \$1$`).
+					WithArgs(2).
+					WillReturnResult(sqlmock.NewResult(0, 4))
+				mock.ExpectExec(`^DELETE FROM actors WHERE id = \$1$`).
+					WithArgs(2).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+			want: domain.ActorMergeResult{
+				Actor:            domain.Actor{ID: 1, Name: "Leonardo DiCaprio", Gender: "male", BirthDate: birthDate, DeathDate: &deathDate, PhotoKey: "actors/2/photo.jpg"},
+				DuplicateID:      2,
+				MoviesReassigned: 3,
+			},
+		},
+		{
+			name:   "duplicate not found",
+			keepID: 1,
+			dupID:  999,
+			setup: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery(`^SELECT id, name, gender, birth_date, death_date, photo_key, slug FROM actors WHERE id = \$1 FOR UPDATE$`).
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "name", "gender", "birth_date", "death_date", "photo_key", "slug"}).
+						AddRow(1, "Leonardo DiCaprio", "male", birthDate, nil, "", ""))
+				mock.ExpectQuery(`^SELECT id, name, gender, birth_date, death_date, photo_key, slug FROM actors WHERE id = \$1 FOR UPDATE$`).
+					WithArgs(999).
+					WillReturnError(sql.ErrNoRows)
+				mock.ExpectRollback()
+			},
+			wantErr: domain.ErrActorNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.setup != nil {
+				tt.setup()
+			}
+
+			got, err := repo.MergeActors(context.Background(), tt.keepID, tt.dupID)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestActorRepository_SetPhotoKey(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewActor(db)
+	query := `^UPDATE actors SET photo_key = \$1 WHERE id = \$2$`
+
+	mock.ExpectExec(query).WithArgs("actors/1/photo.jpg", 1).WillReturnResult(sqlmock.NewResult(0, 1))
+	assert.NoError(t, repo.SetPhotoKey(context.Background(), 1, "actors/1/photo.jpg"))
+
+	mock.ExpectExec(query).WithArgs("", 999).WillReturnResult(sqlmock.NewResult(0, 0))
+	assert.ErrorIs(t, repo.SetPhotoKey(context.Background(), 999, ""), domain.ErrActorNotFound)
+
+	mock.ExpectExec(query).WithArgs("actors/1/photo.jpg", 1).WillReturnError(sql.ErrConnDone)
+	assert.Error(t, repo.SetPhotoKey(context.Background(), 1, "actors/1/photo.jpg"))
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestActorRepository_GetActorsBornInMonth(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	birthDate, _ := time.Parse("2006-01-02", "1964-09-02")
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, name, gender, birth_date, death_date, photo_key, slug FROM actors WHERE EXTRACT(MONTH FROM birth_date) = $1 ORDER BY EXTRACT(DAY FROM birth_date) ASC, name ASC, id ASC")).
+		WithArgs(9).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "gender", "birth_date", "death_date", "photo_key", "slug"}).
+			AddRow(7, "Keanu Reeves", "male", birthDate, nil, "", ""))
+
+	got, err := NewActor(db).GetActorsBornInMonth(context.Background(), 9)
+	require.NoError(t, err)
+	assert.Equal(t, []domain.Actor{{ID: 7, Name: "Keanu Reeves", Gender: "male", BirthDate: birthDate}}, got)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,141 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"cinematique/internal/domain"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+// AddAvailability сохраняет окно доступности фильма и возвращает его ID
+func (m *movie) AddAvailability(ctx context.Context, window domain.Availability) (int, error) {
+	start := time.Now()
+	operation := "add_availability"
+	queryType := "INSERT"
+	ctx, span := startSpan(ctx, operation, queryType)
+	defer span.End()
+
+	id, err := insertReturningID(ctx, m.db, sq.Insert("movie_availability").
+		Columns("film_id", "region", "available_from", "available_until").
+		Values(window.MovieID, window.Region, window.AvailableFrom, window.AvailableUntil))
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return 0, err
+	}
+	dbQueryDurationSeconds.WithLabelValues(operation, queryType).Observe(time.Since(start).Seconds())
+	dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+	return id, nil
+}
+
+// GetAvailability возвращает окна доступности фильма по регионам и датам начала
+func (m *movie) GetAvailability(ctx context.Context, movieID int) ([]domain.Availability, error) {
+	start := time.Now()
+	operation := "get_availability"
+	queryType := "SELECT"
+	ctx, span := startSpan(ctx, operation, queryType)
+	defer span.End()
+
+	query, args, err := sq.Select("id", "film_id", "region", "available_from", "available_until").
+		From("movie_availability").
+		Where(sq.Eq{"film_id": movieID}).
+		OrderBy("region ASC", "available_from ASC", "id ASC").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return nil, err
+	}
+	rows, err := m.replica.pick(m.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return nil, err
+	}
+	defer rows.Close()
+	windows := make([]domain.Availability, 0)
+	for rows.Next() {
+		var window domain.Availability
+		var until sql.NullTime
+		if err := rows.Scan(&window.ID, &window.MovieID, &window.Region, &window.AvailableFrom, &until); err != nil {
+			dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+			return nil, err
+		}
+		if until.Valid {
+			window.AvailableUntil = &until.Time
+		}
+		windows = append(windows, window)
+	}
+	if err := rows.Err(); err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return nil, err
+	}
+	dbQueryDurationSeconds.WithLabelValues(operation, queryType).Observe(time.Since(start).Seconds())
+	dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+	return windows, nil
+}
+
+// UpdateAvailability заменяет регион и даты окна. Окно ищется по ID и фильму, поэтому
+// окно другого фильма не изменится, даже если его ID указан в адресе этого
+func (m *movie) UpdateAvailability(ctx context.Context, window domain.Availability) error {
+	start := time.Now()
+	operation := "update_availability"
+	queryType := "UPDATE"
+	ctx, span := startSpan(ctx, operation, queryType)
+	defer span.End()
+
+	query, args, err := sq.Update("movie_availability").
+		Set("region", window.Region).
+		Set("available_from", window.AvailableFrom).
+		Set("available_until", window.AvailableUntil).
+		Where(sq.Eq{"id": window.ID, "film_id": window.MovieID}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return fmt.Errorf("building query: %w", err)
+	}
+	return m.execAvailability(ctx, operation, queryType, start, query, args)
+}
+
+// DeleteAvailability удаляет окно доступности фильма
+func (m *movie) DeleteAvailability(ctx context.Context, movieID, windowID int) error {
+	start := time.Now()
+	operation := "delete_availability"
+	queryType := "DELETE"
+	ctx, span := startSpan(ctx, operation, queryType)
+	defer span.End()
+
+	query, args, err := sq.Delete("movie_availability").
+		Where(sq.Eq{"id": windowID, "film_id": movieID}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return fmt.Errorf("building query: %w", err)
+	}
+	return m.execAvailability(ctx, operation, queryType, start, query, args)
+}
+
+// execAvailability выполняет изменение одного окна; ErrAvailabilityNotFound, если окна нет
+func (m *movie) execAvailability(ctx context.Context, operation, queryType string, start time.Time, query string, args []interface{}) error {
+	result, err := m.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return fmt.Errorf("getting rows affected: %w", err)
+	}
+	if affected == 0 {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return domain.ErrAvailabilityNotFound
+	}
+	dbQueryDurationSeconds.WithLabelValues(operation, queryType).Observe(time.Since(start).Seconds())
+	dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+	return nil
+}
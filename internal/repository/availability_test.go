@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"testing"
+	"time"
+
+	"cinematique/internal/domain"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMovieRepository_AddAvailability(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewMovie(db)
+	from := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	until := time.Date(2024, time.June, 30, 0, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery(regexp.QuoteMeta("INSERT INTO movie_availability (film_id,region,available_from,available_until) VALUES ($1,$2,$3,$4) RETURNING id")).
+		WithArgs(1, "DE", from, &until).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+
+	id, err := repo.AddAvailability(context.Background(), domain.Availability{MovieID: 1, Region: "DE", AvailableFrom: from, AvailableUntil: &until})
+	require.NoError(t, err)
+	assert.Equal(t, 7, id)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMovieRepository_GetAvailability(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewMovie(db)
+	from := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	until := time.Date(2024, time.June, 30, 0, 0, 0, 0, time.UTC)
+	query := regexp.QuoteMeta("SELECT id, film_id, region, available_from, available_until FROM movie_availability WHERE film_id = $1 ORDER BY region ASC, available_from ASC, id ASC")
+
+	mock.ExpectQuery(query).WithArgs(1).WillReturnRows(
+		sqlmock.NewRows([]string{"id", "film_id", "region", "available_from", "available_until"}).
+			AddRow(1, 1, "DE", from, until).
+			AddRow(2, 1, "US", from, nil))
+
+	windows, err := repo.GetAvailability(context.Background(), 1)
+	require.NoError(t, err)
+	require.Len(t, windows, 2)
+	require.NotNil(t, windows[0].AvailableUntil)
+	assert.True(t, until.Equal(*windows[0].AvailableUntil))
+	assert.Equal(t, "US", windows[1].Region)
+	assert.Nil(t, windows[1].AvailableUntil)
+
+	mock.ExpectQuery(query).WithArgs(2).WillReturnError(sql.ErrConnDone)
+	_, err = repo.GetAvailability(context.Background(), 2)
+	assert.Error(t, err)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMovieRepository_UpdateAvailability(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewMovie(db)
+	from := time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC)
+	query := regexp.QuoteMeta("UPDATE movie_availability SET region = $1, available_from = $2, available_until = $3 WHERE film_id = $4 AND id = $5")
+
+	mock.ExpectExec(query).
+		WithArgs("FR", from, nil, 1, 3).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	err = repo.UpdateAvailability(context.Background(), domain.Availability{ID: 3, MovieID: 1, Region: "FR", AvailableFrom: from})
+	assert.NoError(t, err)
+
+	mock.ExpectExec(query).
+		WithArgs("FR", from, nil, 2, 3).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	err = repo.UpdateAvailability(context.Background(), domain.Availability{ID: 3, MovieID: 2, Region: "FR", AvailableFrom: from})
+	assert.ErrorIs(t, err, domain.ErrAvailabilityNotFound)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMovieRepository_DeleteAvailability(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewMovie(db)
+	query := regexp.QuoteMeta("DELETE FROM movie_availability WHERE film_id = $1 AND id = $2")
+
+	mock.ExpectExec(query).WithArgs(1, 3).WillReturnResult(sqlmock.NewResult(0, 1))
+	assert.NoError(t, repo.DeleteAvailability(context.Background(), 1, 3))
+
+	mock.ExpectExec(query).WithArgs(1, 4).WillReturnResult(sqlmock.NewResult(0, 0))
+	assert.ErrorIs(t, repo.DeleteAvailability(context.Background(), 1, 4), domain.ErrAvailabilityNotFound)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
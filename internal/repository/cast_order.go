@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cinematique/internal/domain"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+// SetCastOrder записывает места в титрах по порядку actorIDs: первый актёр получает billing_order 1.
+// Все строки обновляются в одной транзакции; если актёра нет в составе, возвращается ErrActorNotInMovie
+func (m *movie) SetCastOrder(ctx context.Context, movieID int, actorIDs []int) error {
+	start := time.Now()
+	operation := "set_cast_order"
+	queryType := "UPDATE"
+	ctx, span := startSpan(ctx, operation, queryType)
+	defer span.End()
+
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for i, actorID := range actorIDs {
+		query, args, err := sq.Update("film_actor").
+			Set("billing_order", i+1).
+			Where(sq.Eq{"film_id": movieID, "actor_id": actorID}).
+			PlaceholderFormat(sq.Dollar).
+			ToSql()
+		if err != nil {
+			dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+			return fmt.Errorf("building query: %w", err)
+		}
+		result, err := tx.ExecContext(ctx, query, args...)
+		if err != nil {
+			dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+			return fmt.Errorf("updating billing order: %w", err)
+		}
+		affected, err := result.RowsAffected()
+		if err != nil {
+			dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+			return fmt.Errorf("getting rows affected: %w", err)
+		}
+		if affected == 0 {
+			dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+			return fmt.Errorf("actor with ID %d, movie %d: %w", actorID, movieID, domain.ErrActorNotInMovie)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	dbQueryDurationSeconds.WithLabelValues(operation, queryType).Observe(time.Since(start).Seconds())
+	dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+	return nil
+}
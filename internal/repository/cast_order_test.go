@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"cinematique/internal/domain"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMovieRepository_SetCastOrder(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewMovie(db)
+	update := regexp.QuoteMeta("UPDATE film_actor SET billing_order = $1 WHERE actor_id = $2 AND film_id = $3")
+
+	mock.ExpectBegin()
+	mock.ExpectExec(update).WithArgs(1, 7, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(update).WithArgs(2, 3, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	require.NoError(t, repo.SetCastOrder(context.Background(), 1, []int{7, 3}))
+
+	// Актёра нет в составе — транзакция откатывается
+	mock.ExpectBegin()
+	mock.ExpectExec(update).WithArgs(1, 9, 1).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+	assert.ErrorIs(t, repo.SetCastOrder(context.Background(), 1, []int{9}), domain.ErrActorNotInMovie)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
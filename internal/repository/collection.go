@@ -0,0 +1,324 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"cinematique/internal/domain"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+// collection реализует репозиторий подборок фильмов
+type collection struct {
+	db      *sql.DB      // соединение с базой данных
+	replica *ReadReplica // реплика для списка подборок; nil — всё читается из db
+}
+
+// NewCollection создаёт репозиторий подборок
+func NewCollection(db *sql.DB) *collection {
+	return &collection{db: db}
+}
+
+// WithReadReplica направляет список подборок в реплику
+func (r *collection) WithReadReplica(replica *ReadReplica) *collection {
+	r.replica = replica
+	return r
+}
+
+// collectionColumns — колонки таблицы collections в порядке сканирования
+var collectionColumns = []string{"id", "name", "description"}
+
+// Create создаёт подборку с фильмами movieIDs в заданном порядке
+func (r *collection) Create(ctx context.Context, c domain.Collection, movieIDs []int) (int, error) {
+	start := time.Now()
+	operation := "create_collection"
+	queryType := "INSERT"
+	ctx, span := startSpan(ctx, operation, queryType)
+	defer span.End()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	id, err := insertReturningID(ctx, tx, sq.Insert("collections").
+		Columns("name", "description").
+		Values(c.Name, c.Description))
+	if err != nil {
+		log.Printf("Error creating collection: %v", err)
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return 0, fmt.Errorf("failed to create collection: %w", err)
+	}
+	if err := r.insertMovies(ctx, tx, id, movieIDs); err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	dbQueryDurationSeconds.WithLabelValues(operation, queryType).Observe(time.Since(start).Seconds())
+	dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+	return id, nil
+}
+
+// GetByID возвращает подборку без фильмов
+func (r *collection) GetByID(ctx context.Context, id int) (domain.Collection, error) {
+	start := time.Now()
+	operation := "get_collection_by_id"
+	queryType := "SELECT"
+	ctx, span := startSpan(ctx, operation, queryType)
+	defer span.End()
+
+	query, args, err := sq.Select(collectionColumns...).
+		From("collections").
+		Where(sq.Eq{"id": id}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return domain.Collection{}, fmt.Errorf("building query: %w", err)
+	}
+
+	var c domain.Collection
+	if err := r.db.QueryRowContext(ctx, query, args...).Scan(&c.ID, &c.Name, &c.Description); err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.Collection{}, domain.ErrCollectionNotFound
+		}
+		return domain.Collection{}, err
+	}
+	dbQueryDurationSeconds.WithLabelValues(operation, queryType).Observe(time.Since(start).Seconds())
+	dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+	return c, nil
+}
+
+// GetAll возвращает все подборки без фильмов, упорядоченные по названию
+func (r *collection) GetAll(ctx context.Context) ([]domain.Collection, error) {
+	start := time.Now()
+	operation := "get_all_collections"
+	queryType := "SELECT"
+	ctx, span := startSpan(ctx, operation, queryType)
+	defer span.End()
+
+	query, args, err := sq.Select(collectionColumns...).
+		From("collections").
+		OrderBy("name ASC", "id ASC").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return nil, fmt.Errorf("building query: %w", err)
+	}
+	rows, err := r.replica.pick(r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return nil, err
+	}
+	defer rows.Close()
+
+	collections := make([]domain.Collection, 0)
+	for rows.Next() {
+		var c domain.Collection
+		if err := rows.Scan(&c.ID, &c.Name, &c.Description); err != nil {
+			dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+			return nil, err
+		}
+		collections = append(collections, c)
+	}
+	if err := rows.Err(); err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return nil, err
+	}
+	dbQueryDurationSeconds.WithLabelValues(operation, queryType).Observe(time.Since(start).Seconds())
+	dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+	return collections, nil
+}
+
+// Update обновляет название и описание подборки
+func (r *collection) Update(ctx context.Context, c domain.Collection) error {
+	start := time.Now()
+	operation := "update_collection"
+	queryType := "UPDATE"
+	ctx, span := startSpan(ctx, operation, queryType)
+	defer span.End()
+
+	query, args, err := sq.Update("collections").
+		Set("name", c.Name).
+		Set("description", c.Description).
+		Where(sq.Eq{"id": c.ID}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return fmt.Errorf("building query: %w", err)
+	}
+	result, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		log.Printf("Error updating collection: %v", err)
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return fmt.Errorf("getting rows affected: %w", err)
+	}
+	if affected == 0 {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return domain.ErrCollectionNotFound
+	}
+	dbQueryDurationSeconds.WithLabelValues(operation, queryType).Observe(time.Since(start).Seconds())
+	dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+	return nil
+}
+
+// Delete удаляет подборку; связи с фильмами удаляются каскадно
+func (r *collection) Delete(ctx context.Context, id int) error {
+	start := time.Now()
+	operation := "delete_collection"
+	queryType := "DELETE"
+	ctx, span := startSpan(ctx, operation, queryType)
+	defer span.End()
+
+	query, args, err := sq.Delete("collections").
+		Where(sq.Eq{"id": id}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return fmt.Errorf("building query: %w", err)
+	}
+	result, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		log.Printf("Error deleting collection: %v", err)
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return fmt.Errorf("getting rows affected: %w", err)
+	}
+	if affected == 0 {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return domain.ErrCollectionNotFound
+	}
+	dbQueryDurationSeconds.WithLabelValues(operation, queryType).Observe(time.Since(start).Seconds())
+	dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+	return nil
+}
+
+// GetMovies возвращает фильмы подборки в порядке просмотра
+func (r *collection) GetMovies(ctx context.Context, collectionID int) ([]domain.Movie, error) {
+	start := time.Now()
+	operation := "get_collection_movies"
+	queryType := "SELECT"
+	ctx, span := startSpan(ctx, operation, queryType)
+	defer span.End()
+
+	query, args, err := sq.Select(prefixedMovieColumns("f")...).
+		From("films f").
+		Join("collection_movies cm ON cm.film_id = f.id").
+		Where(sq.Eq{"cm.collection_id": collectionID}).
+		OrderBy("cm.position ASC").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return nil, fmt.Errorf("building query: %w", err)
+	}
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return nil, err
+	}
+	defer rows.Close()
+
+	movies := make([]domain.Movie, 0)
+	for rows.Next() {
+		movie, err := scanMovie(rows)
+		if err != nil {
+			dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+			return nil, err
+		}
+		movies = append(movies, movie)
+	}
+	if err := rows.Err(); err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return nil, err
+	}
+	dbQueryDurationSeconds.WithLabelValues(operation, queryType).Observe(time.Since(start).Seconds())
+	dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+	return movies, nil
+}
+
+// SetMovies заменяет фильмы подборки списком movieIDs; позиция фильма — его место в списке
+func (r *collection) SetMovies(ctx context.Context, collectionID int, movieIDs []int) error {
+	start := time.Now()
+	operation := "set_collection_movies"
+	queryType := "UPDATE"
+	ctx, span := startSpan(ctx, operation, queryType)
+	defer span.End()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query, args, err := sq.Delete("collection_movies").
+		Where(sq.Eq{"collection_id": collectionID}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return fmt.Errorf("building query: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		log.Printf("Error clearing collection movies: %v", err)
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return fmt.Errorf("failed to clear collection movies: %w", err)
+	}
+	if err := r.insertMovies(ctx, tx, collectionID, movieIDs); err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	dbQueryDurationSeconds.WithLabelValues(operation, queryType).Observe(time.Since(start).Seconds())
+	dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+	return nil
+}
+
+// insertMovies добавляет фильмы подборки с позициями 1..len(movieIDs) внутри транзакции tx
+func (r *collection) insertMovies(ctx context.Context, tx *sql.Tx, collectionID int, movieIDs []int) error {
+	if len(movieIDs) == 0 {
+		return nil
+	}
+	builder := sq.Insert("collection_movies").Columns("collection_id", "film_id", "position")
+	for i, movieID := range movieIDs {
+		builder = builder.Values(collectionID, movieID, i+1)
+	}
+	query, args, err := builder.PlaceholderFormat(sq.Dollar).ToSql()
+	if err != nil {
+		return fmt.Errorf("building query: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		log.Printf("Error adding movies to collection: %v", err)
+		return fmt.Errorf("failed to add movies to collection: %w", constraintError(err))
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"testing"
+
+	"cinematique/internal/domain"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCollectionRepository_Create(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("INSERT INTO collections (name,description) VALUES ($1,$2) RETURNING id")).
+		WithArgs("The Matrix Trilogy", "").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO collection_movies (collection_id,film_id,position) VALUES ($1,$2,$3),($4,$5,$6)")).
+		WithArgs(3, 10, 1, 3, 11, 2).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+
+	id, err := NewCollection(db).Create(context.Background(), domain.Collection{Name: "The Matrix Trilogy"}, []int{10, 11})
+	require.NoError(t, err)
+	assert.Equal(t, 3, id)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCollectionRepository_GetByID(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	repo := NewCollection(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, name, description FROM collections WHERE id = $1")).
+		WithArgs(3).
+		WillReturnRows(sqlmock.NewRows(collectionColumns).AddRow(3, "The Matrix Trilogy", "Neo"))
+	c, err := repo.GetByID(context.Background(), 3)
+	require.NoError(t, err)
+	assert.Equal(t, domain.Collection{ID: 3, Name: "The Matrix Trilogy", Description: "Neo"}, c)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, name, description FROM collections WHERE id = $1")).
+		WithArgs(4).
+		WillReturnError(sql.ErrNoRows)
+	_, err = repo.GetByID(context.Background(), 4)
+	assert.ErrorIs(t, err, domain.ErrCollectionNotFound)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCollectionRepository_GetMovies(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT f.id, f.title, f.description, f.release_year, f.rating, f.release_date, f.view_count, f.original_language, f.country, f.runtime_minutes, f.slug FROM films f JOIN collection_movies cm ON cm.film_id = f.id WHERE cm.collection_id = $1 ORDER BY cm.position ASC")).
+		WithArgs(3).
+		WillReturnRows(sqlmock.NewRows(movieColumns).
+			AddRow(10, "The Matrix", "", 1999, 8.7, nil, 0, "", "", 0, "").
+			AddRow(11, "The Matrix Reloaded", "", 2003, 7.2, nil, 0, "", "", 0, ""))
+
+	movies, err := NewCollection(db).GetMovies(context.Background(), 3)
+	require.NoError(t, err)
+	require.Len(t, movies, 2)
+	assert.Equal(t, "The Matrix", movies[0].Title)
+	assert.Equal(t, "The Matrix Reloaded", movies[1].Title)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCollectionRepository_SetMovies(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM collection_movies WHERE collection_id = $1")).
+		WithArgs(3).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO collection_movies (collection_id,film_id,position) VALUES ($1,$2,$3),($4,$5,$6)")).
+		WithArgs(3, 11, 1, 3, 10, 2).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+
+	require.NoError(t, NewCollection(db).SetMovies(context.Background(), 3, []int{11, 10}))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCollectionRepository_Delete_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM collections WHERE id = $1")).
+		WithArgs(9).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	assert.ErrorIs(t, NewCollection(db).Delete(context.Background(), 9), domain.ErrCollectionNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+
+	"cinematique/internal/domain"
+
+	"github.com/lib/pq"
+)
+
+// Коды SQLSTATE нарушений ограничений PostgreSQL
+const (
+	pqUniqueViolation     pq.ErrorCode = "23505"
+	pqForeignKeyViolation pq.ErrorCode = "23503"
+)
+
+// constraintError переводит нарушение ограничения PostgreSQL в доменную ошибку: уникальность —
+// domain.ErrAlreadyExists (409), внешний ключ — domain.ErrReferenceNotFound (422). Имя ограничения
+// остаётся в тексте ошибки для логов. Прочие ошибки не меняются
+func constraintError(err error) error {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return err
+	}
+	switch pqErr.Code {
+	case pqUniqueViolation:
+		return fmt.Errorf("%w: %s", domain.ErrAlreadyExists, pqErr.Constraint)
+	case pqForeignKeyViolation:
+		return fmt.Errorf("%w: %s", domain.ErrReferenceNotFound, pqErr.Constraint)
+	}
+	return err
+}
+
+// uniqueViolation заменяет нарушение уникальности ошибкой target, понятной вызывающему
+// (например, «имя пользователя занято»); остальные ошибки обрабатывает constraintError
+func uniqueViolation(err error, target error) error {
+	err = constraintError(err)
+	if errors.Is(err, domain.ErrAlreadyExists) {
+		return target
+	}
+	return err
+}
@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"cinematique/internal/apperror"
+	"cinematique/internal/domain"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConstraintError(t *testing.T) {
+	unique := constraintError(&pq.Error{Code: "23505", Constraint: "film_actor_pkey"})
+	assert.ErrorIs(t, unique, domain.ErrAlreadyExists)
+	assert.Contains(t, unique.Error(), "film_actor_pkey")
+	assert.Equal(t, apperror.KindConflict, apperror.KindOf(unique))
+
+	foreignKey := constraintError(&pq.Error{Code: "23503", Constraint: "film_actor_actor_id_fkey"})
+	assert.ErrorIs(t, foreignKey, domain.ErrReferenceNotFound)
+	assert.Equal(t, apperror.KindUnprocessable, apperror.KindOf(foreignKey))
+
+	// Другие нарушения и ошибки не из PostgreSQL возвращаются как есть
+	notNull := &pq.Error{Code: "23502"}
+	assert.Same(t, notNull, constraintError(notNull))
+	assert.Equal(t, sql.ErrConnDone, constraintError(sql.ErrConnDone))
+
+	assert.ErrorIs(t, uniqueViolation(&pq.Error{Code: "23505"}, domain.ErrEmailTaken), domain.ErrEmailTaken)
+	assert.ErrorIs(t, uniqueViolation(&pq.Error{Code: "23503"}, domain.ErrEmailTaken), domain.ErrReferenceNotFound)
+}
+
+func TestMovieRepository_CreateMovieWithActors_Constraints(t *testing.T) {
+	tests := []struct {
+		name    string
+		dbErr   error
+		wantErr error
+	}{
+		{name: "same actor twice", dbErr: &pq.Error{Code: "23505", Constraint: "film_actor_pkey"}, wantErr: domain.ErrAlreadyExists},
+		{name: "unknown actor", dbErr: &pq.Error{Code: "23503", Constraint: "film_actor_actor_id_fkey"}, wantErr: domain.ErrReferenceNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			require.NoError(t, err)
+			defer db.Close()
+
+			mock.ExpectBegin()
+			mock.ExpectQuery(`SELECT slug FROM films`).WillReturnRows(sqlmock.NewRows([]string{"slug"}))
+			mock.ExpectQuery(`INSERT INTO films`).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+			mock.ExpectExec(`INSERT INTO film_actor`).WillReturnError(tt.dbErr)
+			mock.ExpectRollback()
+
+			_, err = NewMovie(db).CreateMovieWithActors(context.Background(), domain.Movie{Title: "The Matrix", ReleaseYear: 1999}, []int{2, 2})
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
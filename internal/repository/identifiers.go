@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"cinematique/internal/domain"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Все идентификаторы, которые попадают в SQL из пользовательского ввода (поля сортировки,
+// фильтры по колонкам), проходят через реестры ниже. В запрос подставляется только значение
+// из реестра, а не сама строка пользователя, поэтому пути «сырой» конкатенации нет.
+
+var sqlIdentifierRejectionsTotal = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "sql_identifier_rejections_total",
+		Help: "Total number of user-supplied SQL identifiers rejected by the whitelist.",
+	},
+	[]string{"context"},
+)
+
+func init() {
+	prometheus.MustRegister(sqlIdentifierRejectionsTotal)
+}
+
+// identifierRegistry — белый список идентификаторов для одного контекста запроса
+type identifierRegistry struct {
+	context string
+	columns map[string]string // внешнее имя → колонка в SQL
+}
+
+// movieSortColumns — поля, по которым разрешено сортировать фильмы
+var movieSortColumns = identifierRegistry{
+	context: "movie_sort",
+	columns: map[string]string{
+		"id":              "id",
+		"title":           "title",
+		"rating":          "rating",
+		"release_year":    "release_year",
+		"release_date":    "release_date",
+		"view_count":      "view_count",
+		"runtime_minutes": "runtime_minutes",
+	},
+}
+
+// defaultMovieSort применяется, если поля сортировки не заданы
+var defaultMovieSort = []domain.SortField{{Field: "rating", Order: "desc"}}
+
+// identifierRegistries перечисляет все реестры пакета; тесты проверяют каждый из них
+var identifierRegistries = []identifierRegistry{movieSortColumns}
+
+// column возвращает колонку для внешнего имени. Отклонённые имена учитываются в метрике аудита
+func (r identifierRegistry) column(name string) (string, bool) {
+	if column, ok := r.columns[name]; ok {
+		return column, true
+	}
+	sqlIdentifierRejectionsTotal.WithLabelValues(r.context).Inc()
+	log.Printf("Rejected SQL identifier for %s: %q", r.context, name)
+	return "", false
+}
+
+// orderBy собирает выражения для ORDER BY только из значений реестра. Для стабильного
+// порядка страниц в конец добавляется сортировка по tiebreaker, если её ещё нет
+func (r identifierRegistry) orderBy(fields []domain.SortField, tiebreaker string) ([]string, error) {
+	clauses := make([]string, 0, len(fields)+1)
+	used := make(map[string]bool, len(fields))
+	for _, field := range fields {
+		column, ok := r.column(field.Field)
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown sort field %q", domain.ErrInvalidSort, field.Field)
+		}
+		direction, ok := sortDirection(field.Order)
+		if !ok {
+			sqlIdentifierRejectionsTotal.WithLabelValues(r.context).Inc()
+			return nil, fmt.Errorf("%w: unknown sort order %q", domain.ErrInvalidSort, field.Order)
+		}
+		if used[column] {
+			return nil, fmt.Errorf("%w: duplicate sort field %q", domain.ErrInvalidSort, field.Field)
+		}
+		used[column] = true
+		clauses = append(clauses, column+" "+direction)
+	}
+	if !used[tiebreaker] {
+		clauses = append(clauses, tiebreaker+" ASC")
+	}
+	return clauses, nil
+}
+
+// sortDirection переводит направление сортировки в ASC или DESC; пустое значение — ASC
+func sortDirection(order string) (string, bool) {
+	switch strings.ToUpper(order) {
+	case "", "ASC":
+		return "ASC", true
+	case "DESC":
+		return "DESC", true
+	}
+	return "", false
+}
@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"cinematique/internal/domain"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var plainIdentifier = regexp.MustCompile(`^[a-z_][a-z0-9_]*(\.[a-z_][a-z0-9_]*)?$`)
+
+func TestIdentifierRegistries_OnlyPlainIdentifiers(t *testing.T) {
+	for _, registry := range identifierRegistries {
+		for name, column := range registry.columns {
+			assert.Regexp(t, plainIdentifier, column, "%s: %s", registry.context, name)
+		}
+	}
+}
+
+func TestIdentifierRegistry_OrderBy(t *testing.T) {
+	tests := []struct {
+		name    string
+		fields  []domain.SortField
+		want    []string
+		wantErr bool
+	}{
+		{"known field", []domain.SortField{{Field: "title", Order: "ASC"}}, []string{"title ASC", "id ASC"}, false},
+		{"lowercase order", []domain.SortField{{Field: "release_year", Order: "desc"}}, []string{"release_year DESC", "id ASC"}, false},
+		{"empty order", []domain.SortField{{Field: "rating"}}, []string{"rating ASC", "id ASC"}, false},
+		{"runtime", []domain.SortField{{Field: "runtime_minutes", Order: "asc"}}, []string{"runtime_minutes ASC", "id ASC"}, false},
+		{"unknown field", []domain.SortField{{Field: "id; DROP TABLE films"}}, nil, true},
+		{"unknown order", []domain.SortField{{Field: "title", Order: "ASC; --"}}, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := movieSortColumns.orderBy(tt.fields, "id")
+			if tt.wantErr {
+				assert.ErrorIs(t, err, domain.ErrInvalidSort)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+// FuzzGetAllMoviesSorted проверяет, что никакие значения параметров сортировки не попадают
+// в SQL как есть: запрос либо отклоняется, либо собран только из значений реестра
+func FuzzGetAllMoviesSorted(f *testing.F) {
+	clause := `(id|title|rating|release_year|release_date|view_count|runtime_minutes) (ASC|DESC)`
+	allowed := regexp.MustCompile(`^SELECT id, title, description, release_year, rating, release_date, view_count, original_language, country, runtime_minutes, slug FROM films ORDER BY ` +
+		clause + `(, ` + clause + `)*( LIMIT \d+)?( OFFSET \d+)?$`)
+
+	f.Add("title", "ASC", "rating", "desc", 10, 0)
+	f.Add("title; DROP TABLE films; --", "ASC", "", "", 0, 0)
+	f.Add("(SELECT password FROM users)", "DESC, 1", "id", "asc", 5, 5)
+	f.Add("release_year", "ASC NULLS FIRST", "title", "", 0, 3)
+	f.Add("", "", "", "", 0, 0)
+
+	f.Fuzz(func(t *testing.T, firstField, firstOrder, secondField, secondOrder string, limit, offset int) {
+		var executed string
+		matcher := sqlmock.QueryMatcherFunc(func(_, actual string) error {
+			executed = actual
+			return nil
+		})
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(matcher))
+		require.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectQuery("").WillReturnRows(sqlmock.NewRows(movieColumns))
+		_, err = NewMovie(db).GetAllMoviesSorted(context.Background(), domain.MovieListQuery{
+			Sort: []domain.SortField{
+				{Field: firstField, Order: firstOrder},
+				{Field: secondField, Order: secondOrder},
+			},
+			Limit:  limit,
+			Offset: offset,
+		})
+		if err != nil {
+			require.ErrorIs(t, err, domain.ErrInvalidSort)
+			require.Empty(t, executed)
+			return
+		}
+		if !allowed.MatchString(executed) {
+			t.Fatalf("unexpected query for sort=%q:%q,%q:%q: %s", firstField, firstOrder, secondField, secondOrder, executed)
+		}
+	})
+}
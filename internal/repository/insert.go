@@ -0,0 +1,28 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+// queryExecer — общая часть *sql.DB и *sql.Tx, нужная для вставки
+type queryExecer interface {
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+// insertReturningID выполняет вставку и возвращает ID новой строки (RETURNING id)
+func insertReturningID(ctx context.Context, db queryExecer, builder sq.InsertBuilder) (int, error) {
+	query, args, err := builder.Suffix("RETURNING id").PlaceholderFormat(sq.Dollar).ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("building query: %w", err)
+	}
+	var id int
+	if err := db.QueryRowContext(ctx, query, args...).Scan(&id); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
@@ -0,0 +1,359 @@
+//go:build integration
+
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"cinematique/internal/domain"
+	"cinematique/internal/itest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestIntegration_Repositories проверяет репозитории на настоящем PostgreSQL с применёнными
+// миграциями: sqlmock сверяет только текст запроса и не замечает синтаксических ошибок,
+// несовпадения столбцов со схемой и нарушений ограничений
+func TestIntegration_Repositories(t *testing.T) {
+	db := itest.StartPostgres(t)
+	ctx := context.Background()
+	movies := NewMovie(db)
+	actors := NewActor(db)
+
+	reset := func(t *testing.T) {
+		itest.Truncate(t, db, "movie_merges", "rating_history", "movie_availability", "movie_tags", "tags", "film_actor", "films", "actors")
+	}
+	createActor := func(t *testing.T, name string) int {
+		id, err := actors.Create(ctx, domain.Actor{Name: name, Gender: "male", BirthDate: time.Date(1964, 9, 2, 0, 0, 0, 0, time.UTC)})
+		require.NoError(t, err)
+		return id
+	}
+
+	t.Run("movie CRUD", func(t *testing.T) {
+		reset(t)
+		releaseDate := time.Date(1999, 3, 31, 0, 0, 0, 0, time.UTC)
+		id, err := movies.Create(ctx, domain.Movie{
+			Title: "The Matrix", Description: "Neo", ReleaseYear: 1999, ReleaseDate: &releaseDate,
+			Rating: 8.7, OriginalLanguage: "en", Country: "US",
+		})
+		require.NoError(t, err)
+
+		movie, err := movies.GetByID(ctx, id)
+		require.NoError(t, err)
+		assert.Equal(t, "The Matrix", movie.Title)
+		assert.Equal(t, "en", movie.OriginalLanguage)
+		require.NotNil(t, movie.ReleaseDate)
+		assert.True(t, releaseDate.Equal(movie.ReleaseDate.UTC()))
+
+		movie.Rating = 9.1
+		require.NoError(t, movies.Update(ctx, movie))
+		movie, err = movies.GetByID(ctx, id)
+		require.NoError(t, err)
+		assert.Equal(t, 9.1, movie.Rating)
+
+		found, err := movies.SearchMoviesByTitle(ctx, "matr", domain.MovieFilter{Country: "US"})
+		require.NoError(t, err)
+		require.Len(t, found, 1)
+		assert.Equal(t, id, found[0].ID)
+
+		require.NoError(t, movies.Delete(ctx, id, domain.MovieDeleteCascade))
+		_, err = movies.GetByID(ctx, id)
+		assert.ErrorIs(t, err, domain.ErrMovieNotFound)
+	})
+
+	t.Run("cast keeps billing order and ignores duplicates", func(t *testing.T) {
+		reset(t)
+		movieID, err := movies.Create(ctx, domain.Movie{Title: "Heat", ReleaseYear: 1995})
+		require.NoError(t, err)
+		pacino := createActor(t, "Al Pacino")
+		deNiro := createActor(t, "Robert De Niro")
+		kilmer := createActor(t, "Val Kilmer")
+
+		require.NoError(t, movies.UpdateMovieActors(ctx, movieID, []domain.CastMember{
+			{ActorID: kilmer},
+			{ActorID: deNiro, CharacterName: "Neil McCauley", BillingOrder: 2},
+			{ActorID: pacino, CharacterName: "Vincent Hanna", BillingOrder: 1},
+		}))
+		// Повторное добавление того же актёра не нарушает первичный ключ film_actor
+		require.NoError(t, movies.AddActor(ctx, movieID, domain.CastMember{ActorID: pacino}))
+
+		cast, err := movies.GetActorsForMovieByID(ctx, movieID)
+		require.NoError(t, err)
+		require.Len(t, cast, 3)
+		assert.Equal(t, []int{pacino, deNiro, kilmer}, []int{cast[0].ID, cast[1].ID, cast[2].ID})
+		assert.Equal(t, "Vincent Hanna", cast[0].CharacterName)
+		assert.Equal(t, 0, cast[2].BillingOrder)
+
+		actorMovies, err := actors.GetMovies(ctx, deNiro)
+		require.NoError(t, err)
+		require.Len(t, actorMovies, 1)
+		assert.Equal(t, "Heat", actorMovies[0].Title)
+	})
+
+	t.Run("cast order is rewritten", func(t *testing.T) {
+		reset(t)
+		movieID, err := movies.Create(ctx, domain.Movie{Title: "Casino", ReleaseYear: 1995})
+		require.NoError(t, err)
+		deNiro := createActor(t, "Robert De Niro")
+		stone := createActor(t, "Sharon Stone")
+		pesci := createActor(t, "Joe Pesci")
+		require.NoError(t, movies.UpdateMovieActors(ctx, movieID, []domain.CastMember{
+			{ActorID: deNiro, BillingOrder: 1}, {ActorID: pesci, BillingOrder: 2}, {ActorID: stone},
+		}))
+
+		require.NoError(t, movies.SetCastOrder(ctx, movieID, []int{stone, deNiro, pesci}))
+		cast, err := movies.GetActorsForMovieByID(ctx, movieID)
+		require.NoError(t, err)
+		assert.Equal(t, []int{stone, deNiro, pesci}, []int{cast[0].ID, cast[1].ID, cast[2].ID})
+		assert.Equal(t, 1, cast[0].BillingOrder)
+
+		err = movies.SetCastOrder(ctx, movieID, []int{pesci, createActor(t, "James Woods")})
+		assert.ErrorIs(t, err, domain.ErrActorNotInMovie)
+		// Транзакция откатилась: порядок прежний
+		cast, err = movies.GetActorsForMovieByID(ctx, movieID)
+		require.NoError(t, err)
+		assert.Equal(t, stone, cast[0].ID)
+	})
+
+	t.Run("cast rejects unknown actor", func(t *testing.T) {
+		reset(t)
+		movieID, err := movies.Create(ctx, domain.Movie{Title: "Ronin", ReleaseYear: 1998})
+		require.NoError(t, err)
+
+		// Внешний ключ film_actor.actor_id не даёт сослаться на несуществующего актёра
+		err = movies.AddActor(ctx, movieID, domain.CastMember{ActorID: 999})
+		assert.Error(t, err)
+	})
+
+	t.Run("deleting actor removes cast entries", func(t *testing.T) {
+		reset(t)
+		movieID, err := movies.Create(ctx, domain.Movie{Title: "Speed", ReleaseYear: 1994})
+		require.NoError(t, err)
+		keanu := createActor(t, "Keanu Reeves")
+		require.NoError(t, movies.AddActor(ctx, movieID, domain.CastMember{ActorID: keanu, CharacterName: "Jack Traven"}))
+
+		require.NoError(t, actors.Delete(ctx, keanu))
+		_, err = actors.GetByID(ctx, keanu)
+		assert.ErrorIs(t, err, domain.ErrActorNotFound)
+
+		cast, err := movies.GetActorsForMovieByID(ctx, movieID)
+		require.NoError(t, err)
+		assert.Empty(t, cast)
+	})
+
+	t.Run("orphan actors purge keeps cast", func(t *testing.T) {
+		reset(t)
+		movieID, err := movies.Create(ctx, domain.Movie{Title: "Fargo", ReleaseYear: 1996})
+		require.NoError(t, err)
+		macy := createActor(t, "William H. Macy")
+		orphan := createActor(t, "Nobody In Particular")
+		require.NoError(t, movies.AddActor(ctx, movieID, domain.CastMember{ActorID: macy}))
+
+		count, err := actors.CountOrphanActors(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 1, count)
+
+		purged, err := actors.PurgeOrphanActors(ctx, 1)
+		require.NoError(t, err)
+		require.Len(t, purged, 1)
+		assert.Equal(t, orphan, purged[0].ID)
+		_, err = actors.GetByID(ctx, macy)
+		assert.NoError(t, err)
+	})
+
+	t.Run("actor stats aggregate cast movies", func(t *testing.T) {
+		reset(t)
+		pacino := createActor(t, "Al Pacino")
+		for _, movie := range []domain.Movie{
+			{Title: "The Godfather", ReleaseYear: 1972, Rating: 9.2},
+			{Title: "Heat", ReleaseYear: 1995, Rating: 8.3},
+			{Title: "Untitled Project", Rating: 5.5},
+		} {
+			movieID, err := movies.Create(ctx, movie)
+			require.NoError(t, err)
+			require.NoError(t, movies.AddActor(ctx, movieID, domain.CastMember{ActorID: pacino}))
+		}
+
+		stats, err := actors.GetActorStats(ctx, pacino)
+		require.NoError(t, err)
+		assert.Equal(t, 3, stats.MovieCount)
+		require.NotNil(t, stats.AverageRating)
+		assert.InDelta(t, 7.667, *stats.AverageRating, 0.001)
+		// Фильм без года не сдвигает первый год к нулю
+		assert.Equal(t, 1972, stats.FirstYear)
+		assert.Equal(t, 1995, stats.LatestYear)
+
+		stats, err = actors.GetActorStats(ctx, createActor(t, "Newcomer"))
+		require.NoError(t, err)
+		assert.Zero(t, stats.MovieCount)
+		assert.Nil(t, stats.AverageRating)
+
+		_, err = actors.GetActorStats(ctx, 999999)
+		assert.ErrorIs(t, err, domain.ErrActorNotFound)
+	})
+
+	t.Run("actors with movies paginate by actor", func(t *testing.T) {
+		reset(t)
+		nolan := createActor(t, "Anna Nolan")
+		createActor(t, "Bob Stone")
+		hanks := createActor(t, "Anna Hanks")
+		for _, movie := range []domain.Movie{
+			{Title: "First", ReleaseYear: 2001, Rating: 7},
+			{Title: "Second", ReleaseYear: 2002, Rating: 7},
+			{Title: "Third", ReleaseYear: 2003, Rating: 7},
+		} {
+			movieID, err := movies.Create(ctx, movie)
+			require.NoError(t, err)
+			require.NoError(t, movies.AddActor(ctx, movieID, domain.CastMember{ActorID: nolan}))
+		}
+
+		// Фильмы первого актёра не занимают места на странице: она считается по актёрам
+		page, err := actors.GetAllActorsWithMovies(ctx, domain.ActorsWithMoviesQuery{Name: "anna", Limit: 1, MoviesPerActor: 2})
+		require.NoError(t, err)
+		require.Len(t, page, 1)
+		assert.Equal(t, nolan, page[0].ID)
+		require.Len(t, page[0].Movies, 2)
+		assert.Equal(t, 2003, page[0].Movies[0].ReleaseYear)
+		assert.Equal(t, 2002, page[0].Movies[1].ReleaseYear)
+
+		page, err = actors.GetAllActorsWithMovies(ctx, domain.ActorsWithMoviesQuery{Name: "anna", Limit: 1, Offset: 1})
+		require.NoError(t, err)
+		require.Len(t, page, 1)
+		assert.Equal(t, hanks, page[0].ID)
+		assert.Empty(t, page[0].Movies)
+	})
+
+	t.Run("movie tags", func(t *testing.T) {
+		reset(t)
+		godfather, err := movies.Create(ctx, domain.Movie{Title: "The Godfather", ReleaseYear: 1972, Rating: 9.2})
+		require.NoError(t, err)
+		heat, err := movies.Create(ctx, domain.Movie{Title: "Heat", ReleaseYear: 1995, Rating: 8.3})
+		require.NoError(t, err)
+
+		require.NoError(t, movies.AddMovieTag(ctx, godfather, "oscar-winner"))
+		require.NoError(t, movies.AddMovieTag(ctx, godfather, "oscar-winner")) // повтор ничего не меняет
+		require.NoError(t, movies.AddMovieTag(ctx, godfather, "crime"))
+		require.NoError(t, movies.AddMovieTag(ctx, heat, "crime"))
+
+		tags, err := movies.GetMovieTags(ctx, godfather)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"crime", "oscar-winner"}, tags)
+
+		tagged, err := movies.GetMoviesByTag(ctx, "crime", 10, 0)
+		require.NoError(t, err)
+		require.Len(t, tagged, 2)
+		assert.Equal(t, godfather, tagged[0].ID)
+		count, err := movies.CountMoviesByTag(ctx, "crime")
+		require.NoError(t, err)
+		assert.Equal(t, 2, count)
+
+		require.NoError(t, movies.RemoveMovieTag(ctx, heat, "crime"))
+		assert.ErrorIs(t, movies.RemoveMovieTag(ctx, heat, "crime"), domain.ErrTagNotFound)
+
+		suggestions, err := movies.SuggestTags(ctx, "", 10)
+		require.NoError(t, err)
+		assert.Equal(t, []domain.Tag{{Name: "crime", MovieCount: 1}, {Name: "oscar-winner", MovieCount: 1}}, suggestions)
+	})
+
+	t.Run("search indexes exist and rebuild", func(t *testing.T) {
+		maintenance := NewMaintenance(db)
+		// Список индексов должен совпадать с миграциями: неизвестный индекс REINDEX не найдёт
+		for _, index := range maintenance.SearchIndexes() {
+			require.NoError(t, maintenance.RebuildIndex(ctx, index), index)
+		}
+		require.NoError(t, maintenance.AnalyzeCatalog(ctx))
+	})
+
+	t.Run("availability filter uses date ranges", func(t *testing.T) {
+		reset(t)
+		day := func(month time.Month, d int) time.Time { return time.Date(2024, month, d, 0, 0, 0, 0, time.UTC) }
+		ended := day(time.March, 31)
+		streaming, err := movies.Create(ctx, domain.Movie{Title: "Run Lola Run", ReleaseYear: 1998})
+		require.NoError(t, err)
+		expired, err := movies.Create(ctx, domain.Movie{Title: "Good Bye, Lenin!", ReleaseYear: 2003})
+		require.NoError(t, err)
+		_, err = movies.AddAvailability(ctx, domain.Availability{MovieID: streaming, Region: "DE", AvailableFrom: day(time.January, 1)})
+		require.NoError(t, err)
+		_, err = movies.AddAvailability(ctx, domain.Availability{MovieID: expired, Region: "DE", AvailableFrom: day(time.January, 1), AvailableUntil: &ended})
+		require.NoError(t, err)
+
+		available := true
+		found, err := movies.SearchMoviesByTitle(ctx, "", domain.MovieFilter{Region: "DE", Available: &available, AvailableOn: day(time.March, 31)})
+		require.NoError(t, err)
+		assert.Len(t, found, 2, "available_until is inclusive")
+
+		found, err = movies.SearchMoviesByTitle(ctx, "", domain.MovieFilter{Region: "DE", Available: &available, AvailableOn: day(time.April, 1)})
+		require.NoError(t, err)
+		require.Len(t, found, 1)
+		assert.Equal(t, streaming, found[0].ID)
+
+		// Ограничение CHECK не даёт сохранить окно, которое заканчивается раньше начала
+		_, err = movies.AddAvailability(ctx, domain.Availability{MovieID: streaming, Region: "FR", AvailableFrom: day(time.May, 1), AvailableUntil: &ended})
+		assert.Error(t, err)
+	})
+
+	t.Run("movie ratings per source", func(t *testing.T) {
+		reset(t)
+		movieID, err := movies.Create(ctx, domain.Movie{Title: "Heat", ReleaseYear: 1995})
+		require.NoError(t, err)
+
+		require.NoError(t, movies.SetMovieRating(ctx, domain.MovieRating{MovieID: movieID, Source: domain.RatingSourceIMDb, Rating: 8.2}))
+		require.NoError(t, movies.SetMovieRating(ctx, domain.MovieRating{MovieID: movieID, Source: domain.RatingSourceIMDb, Rating: 8.3}))
+		require.NoError(t, movies.SetMovieRating(ctx, domain.MovieRating{MovieID: movieID, Source: domain.RatingSourceRottenTomatoes, Rating: 88}))
+
+		ratings, err := movies.GetMovieRatings(ctx, movieID)
+		require.NoError(t, err)
+		require.Len(t, ratings, 2)
+		assert.Equal(t, domain.RatingSourceIMDb, ratings[0].Source)
+		assert.Equal(t, 8.3, ratings[0].Rating)
+
+		// Ограничение CHECK не пропускает неизвестный источник
+		err = movies.SetMovieRating(ctx, domain.MovieRating{MovieID: movieID, Source: "metacritic", Rating: 80})
+		assert.Error(t, err)
+
+		require.NoError(t, movies.DeleteMovieRating(ctx, movieID, domain.RatingSourceIMDb))
+		assert.ErrorIs(t, movies.DeleteMovieRating(ctx, movieID, domain.RatingSourceIMDb), domain.ErrRatingNotFound)
+	})
+
+	t.Run("merge movies moves cast and relations", func(t *testing.T) {
+		reset(t)
+		keepID, err := movies.Create(ctx, domain.Movie{Title: "Alien", ReleaseYear: 1979})
+		require.NoError(t, err)
+		dupID, _, err := movies.UpsertByExternalID(ctx, domain.Movie{Title: "Alien (1979)", ReleaseYear: 1979, ExternalID: "tt0078748"})
+		require.NoError(t, err)
+		weaver := createActor(t, "Sigourney Weaver")
+		require.NoError(t, movies.AddActor(ctx, dupID, domain.CastMember{ActorID: weaver, CharacterName: "Ripley", BillingOrder: 1}))
+		require.NoError(t, movies.SetMovieRating(ctx, domain.MovieRating{MovieID: dupID, Source: domain.RatingSourceIMDb, Rating: 8.5}))
+		dup, err := movies.GetByID(ctx, dupID)
+		require.NoError(t, err)
+
+		result, err := movies.MergeMovies(ctx, keepID, dupID, domain.MovieDeleteCascade)
+		require.NoError(t, err)
+		assert.Equal(t, 1, result.ActorsReassigned)
+
+		cast, err := movies.GetActorsForMovieByID(ctx, keepID)
+		require.NoError(t, err)
+		require.Len(t, cast, 1)
+		assert.Equal(t, "Ripley", cast[0].CharacterName)
+
+		ratings, err := movies.GetMovieRatings(ctx, keepID)
+		require.NoError(t, err)
+		require.Len(t, ratings, 1)
+		assert.Equal(t, domain.RatingSourceIMDb, ratings[0].Source)
+
+		kept, err := movies.GetByID(ctx, keepID)
+		require.NoError(t, err)
+		assert.Equal(t, "tt0078748", kept.ExternalID)
+
+		redirect, err := movies.GetMergedMovieID(ctx, dupID)
+		require.NoError(t, err)
+		assert.Equal(t, keepID, redirect)
+
+		resolved, err := movies.ResolveSlug(ctx, dup.Slug)
+		require.NoError(t, err)
+		assert.Equal(t, keepID, resolved)
+	})
+}
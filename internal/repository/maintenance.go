@@ -0,0 +1,131 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// searchIndexes — индексы поиска по названию и имени в порядке перестроения (миграции update_008, update_015)
+var searchIndexes = []string{
+	"idx_films_title_trgm",
+	"idx_actors_name_trgm",
+	"idx_actors_name_prefix",
+	"idx_search_stats_query_trgm",
+}
+
+// maintenance — репозиторий служебных операций над схемой: перестроение индексов и статистики планировщика
+type maintenance struct {
+	db *sql.DB // соединение с базой данных
+}
+
+// NewMaintenance создаёт репозиторий служебных операций
+func NewMaintenance(db *sql.DB) *maintenance {
+	return &maintenance{db: db}
+}
+
+// SearchIndexes возвращает индексы поиска, которые перестраивает RebuildIndex
+func (r *maintenance) SearchIndexes() []string {
+	return append([]string(nil), searchIndexes...)
+}
+
+// RebuildIndex перестраивает индекс поиска без блокировки записи (REINDEX CONCURRENTLY).
+// Имя подставляется в запрос, поэтому принимаются только индексы из SearchIndexes.
+// Перестроение большого индекса длится дольше таймаута записи, поэтому его ограничивает
+// только контекст вызывающего. Прерванный REINDEX оставляет невалидную копию <index>_ccnew,
+// которая замедляет запись в таблицу, — такие копии удаляются сразу после ошибки
+func (r *maintenance) RebuildIndex(ctx context.Context, index string) error {
+	start := time.Now()
+	operation := "rebuild_index"
+	queryType := "REINDEX"
+	ctx, span := startStreamSpan(ctx, operation, queryType)
+	defer span.End()
+
+	if !r.isSearchIndex(index) {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return fmt.Errorf("unknown search index %q", index)
+	}
+	if _, err := r.db.ExecContext(ctx, "REINDEX INDEX CONCURRENTLY "+index); err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		// Отмена перестроения не должна отменять уборку за ним
+		if cleanupErr := r.dropInvalidCopies(context.WithoutCancel(ctx), index); cleanupErr != nil {
+			return errors.Join(err, fmt.Errorf("drop invalid copies of %s: %w", index, cleanupErr))
+		}
+		return err
+	}
+	dbQueryDurationSeconds.WithLabelValues(operation, queryType).Observe(time.Since(start).Seconds())
+	dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+	return nil
+}
+
+// dropInvalidCopies удаляет невалидные копии индекса, оставшиеся от прерванного REINDEX CONCURRENTLY
+func (r *maintenance) dropInvalidCopies(ctx context.Context, index string) error {
+	start := time.Now()
+	operation := "drop_invalid_index"
+	queryType := "DROP"
+	ctx, span := startSpan(ctx, operation, queryType)
+	defer span.End()
+
+	rows, err := r.db.QueryContext(ctx, `SELECT c.relname FROM pg_index i
+		JOIN pg_class c ON c.oid = i.indexrelid
+		WHERE NOT i.indisvalid AND c.relname ~ ('^' || $1 || '_ccnew[0-9]*$')`, index)
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return err
+	}
+	var copies []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			rows.Close()
+			dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+			return err
+		}
+		copies = append(copies, name)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return err
+	}
+
+	for _, name := range copies {
+		if _, err := r.db.ExecContext(ctx, "DROP INDEX CONCURRENTLY IF EXISTS "+pq.QuoteIdentifier(name)); err != nil {
+			dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+			return err
+		}
+	}
+	dbQueryDurationSeconds.WithLabelValues(operation, queryType).Observe(time.Since(start).Seconds())
+	dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+	return nil
+}
+
+// AnalyzeCatalog обновляет статистику планировщика по таблицам каталога
+func (r *maintenance) AnalyzeCatalog(ctx context.Context) error {
+	start := time.Now()
+	operation := "analyze_catalog"
+	queryType := "ANALYZE"
+	ctx, span := startStreamSpan(ctx, operation, queryType)
+	defer span.End()
+
+	if _, err := r.db.ExecContext(ctx, "ANALYZE films, actors, film_actor"); err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return err
+	}
+	dbQueryDurationSeconds.WithLabelValues(operation, queryType).Observe(time.Since(start).Seconds())
+	dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+	return nil
+}
+
+func (r *maintenance) isSearchIndex(index string) bool {
+	for _, name := range r.SearchIndexes() {
+		if name == index {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMaintenanceRepository_RebuildIndex(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewMaintenance(db)
+	mock.ExpectExec(regexp.QuoteMeta("REINDEX INDEX CONCURRENTLY idx_films_title_trgm")).WillReturnResult(sqlmock.NewResult(0, 0))
+	require.NoError(t, repo.RebuildIndex(context.Background(), "idx_films_title_trgm"))
+
+	// Имена не из списка индексов поиска в запрос не попадают
+	assert.Error(t, repo.RebuildIndex(context.Background(), "films; DROP TABLE films"))
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMaintenanceRepository_RebuildIndexDropsInvalidCopies(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	SetQueryTimeouts(QueryTimeouts{Write: 5 * time.Millisecond})
+	defer SetQueryTimeouts(QueryTimeouts{})
+
+	reindexErr := errors.New("canceling statement due to user request")
+	mock.ExpectExec(regexp.QuoteMeta("REINDEX INDEX CONCURRENTLY idx_films_title_trgm")).
+		WillDelayFor(30 * time.Millisecond).
+		WillReturnError(reindexErr)
+	mock.ExpectQuery("SELECT c.relname FROM pg_index").
+		WithArgs("idx_films_title_trgm").
+		WillReturnRows(sqlmock.NewRows([]string{"relname"}).AddRow("idx_films_title_trgm_ccnew"))
+	mock.ExpectExec(regexp.QuoteMeta(`DROP INDEX CONCURRENTLY IF EXISTS "idx_films_title_trgm_ccnew"`)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	// Таймаут записи не обрывает перестроение: ошибка приходит от базы, а не от контекста
+	err = NewMaintenance(db).RebuildIndex(context.Background(), "idx_films_title_trgm")
+	assert.ErrorIs(t, err, reindexErr)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMaintenanceRepository_AnalyzeCatalog(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectExec(regexp.QuoteMeta("ANALYZE films, actors, film_actor")).WillReturnResult(sqlmock.NewResult(0, 0))
+	require.NoError(t, NewMaintenance(db).AnalyzeCatalog(context.Background()))
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMaintenanceRepository_SearchIndexes(t *testing.T) {
+	assert.Contains(t, NewMaintenance(nil).SearchIndexes(), "idx_films_title_trgm")
+}
@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"cinematique/internal/domain"
+	"context"
+	"database/sql"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+// MessageLedger — журнал обработанных сообщений Kafka в таблице processed_messages
+type MessageLedger struct {
+	db *sql.DB
+}
+
+// NewMessageLedger создаёт журнал обработанных сообщений
+func NewMessageLedger(db *sql.DB) *MessageLedger {
+	return &MessageLedger{db: db}
+}
+
+// Claim записывает сообщение в журнал. Возвращает false, если сообщение с той же
+// позицией в топике или тем же event_id уже обработано этой группой
+func (l *MessageLedger) Claim(ctx context.Context, msg domain.ProcessedMessage) (bool, error) {
+	start := time.Now()
+	operation := "claim_message"
+	queryType := "INSERT"
+
+	query, args, err := sq.Insert("processed_messages").
+		Columns("consumer_group", "topic", "partition", "kafka_offset", "message_id").
+		Values(msg.Group, msg.Topic, msg.Partition, msg.Offset, msg.MessageID).
+		Suffix("ON CONFLICT DO NOTHING").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return false, err
+	}
+	res, err := l.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return false, err
+	}
+	inserted, err := res.RowsAffected()
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return false, err
+	}
+	dbQueryDurationSeconds.WithLabelValues(operation, queryType).Observe(time.Since(start).Seconds())
+	dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+	return inserted > 0, nil
+}
+
+// Release удаляет запись о сообщении, чтобы при повторной доставке оно обработалось снова.
+// Вызывается, если обработчик завершился ошибкой
+func (l *MessageLedger) Release(ctx context.Context, msg domain.ProcessedMessage) error {
+	start := time.Now()
+	operation := "release_message"
+	queryType := "DELETE"
+
+	query, args, err := sq.Delete("processed_messages").
+		Where(sq.Eq{
+			"consumer_group": msg.Group,
+			"topic":          msg.Topic,
+			"partition":      msg.Partition,
+			"kafka_offset":   msg.Offset,
+		}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return err
+	}
+	if _, err := l.db.ExecContext(ctx, query, args...); err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return err
+	}
+	dbQueryDurationSeconds.WithLabelValues(operation, queryType).Observe(time.Since(start).Seconds())
+	dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+	return nil
+}
@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"cinematique/internal/domain"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMessageLedger_Claim(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	ledger := NewMessageLedger(db)
+	msg := domain.ProcessedMessage{Group: "movie-events", Topic: "movie-views", Partition: 1, Offset: 42, MessageID: "abc"}
+	insert := `INSERT INTO processed_messages \(consumer_group,topic,partition,kafka_offset,message_id\) VALUES \(\$1,\$2,\$3,\$4,\$5\) ON CONFLICT DO NOTHING`
+
+	// Первая доставка
+	mock.ExpectExec(insert).
+		WithArgs("movie-events", "movie-views", 1, int64(42), "abc").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	claimed, err := ledger.Claim(context.Background(), msg)
+	require.NoError(t, err)
+	assert.True(t, claimed)
+
+	// Повторная доставка: конфликт по уникальному индексу, строка не вставлена
+	mock.ExpectExec(insert).
+		WithArgs("movie-events", "movie-views", 1, int64(42), "abc").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	claimed, err = ledger.Claim(context.Background(), msg)
+	require.NoError(t, err)
+	assert.False(t, claimed)
+
+	mock.ExpectExec(insert).WillReturnError(errors.New("db down"))
+	_, err = ledger.Claim(context.Background(), msg)
+	assert.Error(t, err)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMessageLedger_Release(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	ledger := NewMessageLedger(db)
+	mock.ExpectExec(`DELETE FROM processed_messages WHERE consumer_group = \$1 AND kafka_offset = \$2 AND partition = \$3 AND topic = \$4`).
+		WithArgs("movie-events", int64(42), 1, "movie-views").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err = ledger.Release(context.Background(), domain.ProcessedMessage{Group: "movie-events", Topic: "movie-views", Partition: 1, Offset: 42})
+	require.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -2,12 +2,15 @@ package repository
 
 import (
 	"cinematique/internal/domain"
+	"context"
 	"database/sql"
 	"errors"
 	"fmt"
 	sq "github.com/Masterminds/squirrel"
 	"log"
+	"strings"
 	"time" // Добавляем импорт time
+	"unicode"
 
 	"github.com/prometheus/client_golang/prometheus" // Добавляем импорт prometheus
 )
@@ -39,7 +42,9 @@ func init() {
 
 // movie представляет репозиторий фильмов.
 type movie struct {
-	db *sql.DB // соединение с базой данных
+	db      *sql.DB      // соединение с базой данных
+	replica *ReadReplica // реплика для списков и поиска; nil — всё читается из db
+	stmts   *stmtCache   // подготовленные выражения горячих запросов; nil — без подготовки
 }
 
 // NewMovie создаёт новый репозиторий фильмов.
@@ -47,24 +52,130 @@ func NewMovie(db *sql.DB) *movie {
 	return &movie{db: db}
 }
 
+// WithReadReplica направляет списки, сортировки и поиск в реплику.
+func (m *movie) WithReadReplica(replica *ReadReplica) *movie {
+	m.replica = replica
+	return m
+}
+
+// WithPreparedStatements включает подготовленные выражения для горячих запросов:
+// получения по ID, привязки актёра и поиска.
+func (m *movie) WithPreparedStatements() *movie {
+	m.stmts = newStmtCache()
+	return m
+}
+
+// Close закрывает подготовленные выражения репозитория.
+func (m *movie) Close() error {
+	return m.stmts.Close()
+}
+
+// movieColumns — колонки таблицы films в порядке сканирования scanMovie.
+var movieColumns = []string{"id", "title", "description", "release_year", "rating", "release_date", "view_count", "original_language", "country", "runtime_minutes", "slug"}
+
+// prefixedMovieColumns возвращает колонки фильма с алиасом таблицы (f.id, f.title, ...).
+func prefixedMovieColumns(alias string) []string {
+	columns := make([]string, len(movieColumns))
+	for i, column := range movieColumns {
+		columns[i] = alias + "." + column
+	}
+	return columns
+}
+
+// applyMovieFilter добавляет в запрос условия фильтра поиска; prefix — алиас таблицы films с точкой
+func applyMovieFilter(builder sq.SelectBuilder, prefix string, filter domain.MovieFilter) sq.SelectBuilder {
+	if filter.OriginalLanguage != "" {
+		builder = builder.Where(sq.Eq{prefix + "original_language": filter.OriginalLanguage})
+	}
+	if filter.Country != "" {
+		builder = builder.Where(sq.Eq{prefix + "country": filter.Country})
+	}
+	if filter.Region != "" || filter.Available != nil {
+		builder = builder.Where(availabilityCondition(prefix, filter))
+	}
+	// Продолжительность 0 означает «неизвестна»: такие фильмы не попадают ни в один диапазон
+	if filter.RuntimeMin > 0 || filter.RuntimeMax > 0 {
+		builder = builder.Where(sq.Gt{prefix + "runtime_minutes": 0})
+	}
+	if filter.RuntimeMin > 0 {
+		builder = builder.Where(sq.GtOrEq{prefix + "runtime_minutes": filter.RuntimeMin})
+	}
+	if filter.RuntimeMax > 0 {
+		builder = builder.Where(sq.LtOrEq{prefix + "runtime_minutes": filter.RuntimeMax})
+	}
+	return builder
+}
+
+// availabilityCondition строит условие [NOT] EXISTS по окнам доступности фильма.
+// Без алиаса films.id указывается полностью, иначе id разрешился бы в колонку movie_availability
+func availabilityCondition(prefix string, filter domain.MovieFilter) sq.Sqlizer {
+	filmID := prefix + "id"
+	if prefix == "" {
+		filmID = "films.id"
+	}
+	windows := sq.Select("1").
+		From("movie_availability ma").
+		Where("ma.film_id = " + filmID)
+	if filter.Region != "" {
+		windows = windows.Where(sq.Eq{"ma.region": filter.Region})
+	}
+	if filter.Available == nil {
+		return sq.Expr("EXISTS (?)", windows)
+	}
+	// Даты окна включительные, поэтому окно покрывает день, если он попадает между ними
+	day := filter.AvailableOn.Format("2006-01-02")
+	windows = windows.
+		Where(sq.LtOrEq{"ma.available_from": day}).
+		Where(sq.Or{sq.Eq{"ma.available_until": nil}, sq.GtOrEq{"ma.available_until": day}})
+	if *filter.Available {
+		return sq.Expr("EXISTS (?)", windows)
+	}
+	return sq.Expr("NOT EXISTS (?)", windows)
+}
+
+// rowScanner — общий интерфейс *sql.Row и *sql.Rows.
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanMovie читает строку, выбранную по movieColumns, в domain.Movie.
+func scanMovie(row rowScanner) (domain.Movie, error) {
+	var movie domain.Movie
+	err := row.Scan(&movie.ID, &movie.Title, &movie.Description, &movie.ReleaseYear, &movie.Rating, &movie.ReleaseDate, &movie.ViewCount,
+		&movie.OriginalLanguage, &movie.Country, &movie.RuntimeMinutes, &movie.Slug)
+	return movie, err
+}
+
+// movieDetailColumns — колонки фильма вместе с моментом последнего изменения и внешним ID.
+// Выбираются только при чтении одного фильма: списки не отдают заголовок Last-Modified
+var movieDetailColumns = append(append([]string{}, movieColumns...), "updated_at", "external_id")
+
+// scanMovieDetail читает строку, выбранную по movieDetailColumns, в domain.Movie
+func scanMovieDetail(row rowScanner) (domain.Movie, error) {
+	var movie domain.Movie
+	var externalID sql.NullString
+	err := row.Scan(&movie.ID, &movie.Title, &movie.Description, &movie.ReleaseYear, &movie.Rating, &movie.ReleaseDate, &movie.ViewCount,
+		&movie.OriginalLanguage, &movie.Country, &movie.RuntimeMinutes, &movie.Slug, &movie.UpdatedAt, &externalID)
+	movie.ExternalID = externalID.String
+	return movie, err
+}
+
 // Create создаёт новый фильм в базе данных.
-func (m *movie) Create(movie domain.Movie) (int, error) {
+func (m *movie) Create(ctx context.Context, movie domain.Movie) (int, error) {
 	start := time.Now()
 	operation := "create_movie"
 	queryType := "INSERT"
+	ctx, span := startSpan(ctx, operation, queryType)
+	defer span.End()
 
-	query, args, err := sq.Insert("films").
-		Columns("title", "description", "release_year", "rating").
-		Values(movie.Title, movie.Description, movie.ReleaseYear, movie.Rating).
-		Suffix("RETURNING id").
-		PlaceholderFormat(sq.Dollar).
-		ToSql()
+	movieSlug, err := freeSlug(ctx, m.db, "films", movieSlugBase(movie))
 	if err != nil {
 		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
 		return 0, err
 	}
-	var id int
-	err = m.db.QueryRow(query, args...).Scan(&id)
+	id, err := insertReturningID(ctx, m.db, sq.Insert("films").
+		Columns("title", "description", "release_year", "rating", "release_date", "original_language", "country", "runtime_minutes", "slug").
+		Values(movie.Title, movie.Description, movie.ReleaseYear, movie.Rating, movie.ReleaseDate, movie.OriginalLanguage, movie.Country, movie.RuntimeMinutes, movieSlug))
 	if err != nil {
 		log.Printf("Error creating movie: %v", err)
 		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
@@ -76,12 +187,14 @@ func (m *movie) Create(movie domain.Movie) (int, error) {
 }
 
 // GetByID возвращает фильм по заданному ID.
-func (m *movie) GetByID(id int) (domain.Movie, error) {
+func (m *movie) GetByID(ctx context.Context, id int) (domain.Movie, error) {
 	start := time.Now()
 	operation := "get_movie_by_id"
 	queryType := "SELECT"
+	ctx, span := startSpan(ctx, operation, queryType)
+	defer span.End()
 
-	query, args, err := sq.Select("id", "title", "description", "release_year", "rating").
+	query, args, err := sq.Select(movieDetailColumns...).
 		From("films").
 		Where(sq.Eq{"id": id}).
 		PlaceholderFormat(sq.Dollar).
@@ -90,12 +203,11 @@ func (m *movie) GetByID(id int) (domain.Movie, error) {
 		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
 		return domain.Movie{}, err
 	}
-	var movie domain.Movie
-	err = m.db.QueryRow(query, args...).Scan(&movie.ID, &movie.Title, &movie.Description, &movie.ReleaseYear, &movie.Rating)
+	movie, err := scanMovieDetail(m.stmts.queryRowContext(ctx, m.db, query, args...))
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
-			return domain.Movie{}, errors.New("movie not found")
+			return domain.Movie{}, domain.ErrMovieNotFound
 		}
 		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
 		return domain.Movie{}, err
@@ -105,17 +217,69 @@ func (m *movie) GetByID(id int) (domain.Movie, error) {
 	return movie, nil
 }
 
+// normalizedTitleSQL приводит название к виду, в котором сравниваются возможные дубликаты:
+// нижний регистр, знаки препинания и повторяющиеся пробелы заменены одним пробелом.
+// Должно совпадать с normalizeTitle
+const normalizedTitleSQL = `btrim(regexp_replace(lower(title), '[^[:alnum:]]+', ' ', 'g'))`
+
+// normalizeTitle нормализует название фильма так же, как normalizedTitleSQL
+func normalizeTitle(title string) string {
+	return strings.Join(strings.FieldsFunc(strings.ToLower(title), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}), " ")
+}
+
+// FindByNormalizedTitle ищет фильм с тем же нормализованным названием и годом выпуска.
+// Если таких несколько, возвращается фильм с наименьшим ID
+func (m *movie) FindByNormalizedTitle(ctx context.Context, title string, releaseYear int) (domain.Movie, error) {
+	start := time.Now()
+	operation := "find_movie_by_normalized_title"
+	queryType := "SELECT"
+	ctx, span := startSpan(ctx, operation, queryType)
+	defer span.End()
+
+	query, args, err := sq.Select(movieColumns...).
+		From("films").
+		Where(sq.Eq{"release_year": releaseYear}).
+		Where(sq.Expr(normalizedTitleSQL+" = ?", normalizeTitle(title))).
+		OrderBy("id ASC").
+		Limit(1).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return domain.Movie{}, err
+	}
+	movie, err := scanMovie(m.db.QueryRowContext(ctx, query, args...))
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.Movie{}, domain.ErrMovieNotFound
+		}
+		return domain.Movie{}, err
+	}
+	dbQueryDurationSeconds.WithLabelValues(operation, queryType).Observe(time.Since(start).Seconds())
+	dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+	return movie, nil
+}
+
 // Update обновляет информацию о фильме.
-func (m *movie) Update(movie domain.Movie) error {
+func (m *movie) Update(ctx context.Context, movie domain.Movie) error {
 	start := time.Now()
 	operation := "update_movie"
 	queryType := "UPDATE"
+	ctx, span := startSpan(ctx, operation, queryType)
+	defer span.End()
 
 	query, args, err := sq.Update("films").
 		Set("title", movie.Title).
 		Set("description", movie.Description).
 		Set("release_year", movie.ReleaseYear).
 		Set("rating", movie.Rating).
+		Set("release_date", movie.ReleaseDate).
+		Set("original_language", movie.OriginalLanguage).
+		Set("country", movie.Country).
+		Set("runtime_minutes", movie.RuntimeMinutes).
 		Where(sq.Eq{"id": movie.ID}).
 		PlaceholderFormat(sq.Dollar).
 		ToSql()
@@ -123,7 +287,7 @@ func (m *movie) Update(movie domain.Movie) error {
 		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
 		return err
 	}
-	result, err := m.db.Exec(query, args...)
+	result, err := m.db.ExecContext(ctx, query, args...)
 	if err != nil {
 		log.Printf("Error updating movie: %v", err)
 		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
@@ -143,49 +307,32 @@ func (m *movie) Update(movie domain.Movie) error {
 	return nil
 }
 
-// Delete удаляет фильм по заданному ID.
-func (m *movie) Delete(id int) error {
+// Delete удаляет фильм по заданному ID. При политике restrict фильм, на который есть ссылки,
+// не удаляется: возвращается *domain.MovieReferencedError.
+func (m *movie) Delete(ctx context.Context, id int, policy domain.MovieDeletePolicy) error {
 	start := time.Now()
 	operation := "delete_movie"
 	queryType := "DELETE"
+	ctx, span := startSpan(ctx, operation, queryType)
+	defer span.End()
 
-	tx, err := m.db.Begin()
+	tx, err := m.db.BeginTx(ctx, nil)
 	if err != nil {
 		dbQueriesTotal.WithLabelValues(operation, queryType).Inc() // Increment even on transaction begin error
 		return fmt.Errorf("failed to begin transaction: %w", err)
 	}
 	defer tx.Rollback()
 
-	// Удаляем связи с актёрами
-	delFilmActor, args, err := sq.Delete("film_actor").
-		Where(sq.Eq{"film_id": id}).
-		PlaceholderFormat(sq.Dollar).
-		ToSql()
-	if err != nil {
-		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
-		return fmt.Errorf("failed to build delete film_actor query: %w", err)
-	}
-
-	if _, err = tx.Exec(delFilmActor, args...); err != nil {
-		log.Printf("Error deleting film_actor relations: %v", err)
-		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
-		return fmt.Errorf("failed to delete film_actor relations: %w", err)
-	}
-
-	// Удаляем фильм
-	delFilm, args, err := sq.Delete("films").
-		Where(sq.Eq{"id": id}).
-		PlaceholderFormat(sq.Dollar).
-		ToSql()
-	if err != nil {
+	// Проверка ссылок и удаление идут под одной блокировкой фильма: ссылка, добавленная
+	// между ними, не будет молча удалена каскадом
+	if err := lockMovieTx(ctx, tx, id); err != nil {
 		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
-		return fmt.Errorf("failed to build delete film query: %w", err)
+		return err
 	}
-
-	if _, err = tx.Exec(delFilm, args...); err != nil {
+	if err := deleteLockedMovieTx(ctx, tx, id, policy); err != nil {
 		log.Printf("Error deleting film: %v", err)
 		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
-		return fmt.Errorf("failed to delete film: %w", err)
+		return err
 	}
 
 	if err = tx.Commit(); err != nil {
@@ -199,12 +346,14 @@ func (m *movie) Delete(id int) error {
 }
 
 // GetAll возвращает все фильмы.
-func (m *movie) GetAll() ([]domain.Movie, error) {
+func (m *movie) GetAll(ctx context.Context) ([]domain.Movie, error) {
 	start := time.Now()
 	operation := "get_all_movies"
 	queryType := "SELECT"
+	ctx, span := startSpan(ctx, operation, queryType)
+	defer span.End()
 
-	query, args, err := sq.Select("id", "title", "description", "release_year", "rating").
+	query, args, err := sq.Select(movieColumns...).
 		From("films").
 		PlaceholderFormat(sq.Dollar).
 		ToSql()
@@ -212,7 +361,7 @@ func (m *movie) GetAll() ([]domain.Movie, error) {
 		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
 		return nil, err
 	}
-	rows, err := m.db.Query(query, args...)
+	rows, err := m.replica.pick(m.db).QueryContext(ctx, query, args...)
 	if err != nil {
 		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
 		return nil, err
@@ -220,8 +369,8 @@ func (m *movie) GetAll() ([]domain.Movie, error) {
 	defer rows.Close()
 	movies := make([]domain.Movie, 0)
 	for rows.Next() {
-		var movie domain.Movie
-		if err := rows.Scan(&movie.ID, &movie.Title, &movie.Description, &movie.ReleaseYear, &movie.Rating); err != nil {
+		movie, err := scanMovie(rows)
+		if err != nil {
 			dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
 			return nil, err
 		}
@@ -239,15 +388,114 @@ func (m *movie) GetAll() ([]domain.Movie, error) {
 	return movies, nil
 }
 
-// AddActor добавляет актёра к фильму.
-func (m *movie) AddActor(movieID, actorID int) error {
+// ForEachMovie вызывает fn для каждого фильма каталога по возрастанию ID.
+// Строки читаются курсором базы по одной, так что память не растёт с размером каталога.
+// Ошибка fn прекращает чтение и возвращается как есть
+func (m *movie) ForEachMovie(ctx context.Context, fn func(domain.Movie) error) error {
+	start := time.Now()
+	operation := "for_each_movie"
+	queryType := "SELECT"
+	ctx, span := startStreamSpan(ctx, operation, queryType)
+	defer span.End()
+
+	query, args, err := sq.Select(movieColumns...).
+		From("films").
+		OrderBy("id ASC").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return err
+	}
+	rows, err := m.replica.pick(m.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		movie, err := scanMovie(rows)
+		if err != nil {
+			dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+			return err
+		}
+		if err := fn(movie); err != nil {
+			dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return err
+	}
+	dbQueryDurationSeconds.WithLabelValues(operation, queryType).Observe(time.Since(start).Seconds())
+	dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+	return nil
+}
+
+// GetMoviesAfterID возвращает до limit фильмов с ID больше afterID по возрастанию ID.
+// Keyset-пагинация читает только нужные строки по первичному ключу, сколько бы страниц ни было пройдено
+func (m *movie) GetMoviesAfterID(ctx context.Context, afterID, limit int) ([]domain.Movie, error) {
+	start := time.Now()
+	operation := "get_movies_after_id"
+	queryType := "SELECT"
+	ctx, span := startSpan(ctx, operation, queryType)
+	defer span.End()
+
+	query, args, err := sq.Select(movieColumns...).
+		From("films").
+		Where(sq.Gt{"id": afterID}).
+		OrderBy("id ASC").
+		Limit(uint64(limit)).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return nil, err
+	}
+	rows, err := m.replica.pick(m.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return nil, err
+	}
+	defer rows.Close()
+	movies := make([]domain.Movie, 0, limit)
+	for rows.Next() {
+		movie, err := scanMovie(rows)
+		if err != nil {
+			dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+			return nil, err
+		}
+		movies = append(movies, movie)
+	}
+	if err := rows.Err(); err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return nil, err
+	}
+	dbQueryDurationSeconds.WithLabelValues(operation, queryType).Observe(time.Since(start).Seconds())
+	dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+	return movies, nil
+}
+
+// billingOrderValue возвращает значение billing_order: незаданный порядок хранится как NULL
+func billingOrderValue(order int) interface{} {
+	if order <= 0 {
+		return nil
+	}
+	return order
+}
+
+// AddActor добавляет актёра к фильму с ролью и местом в титрах.
+func (m *movie) AddActor(ctx context.Context, movieID int, member domain.CastMember) error {
 	start := time.Now()
 	operation := "add_actor_to_movie"
 	queryType := "INSERT"
+	ctx, span := startSpan(ctx, operation, queryType)
+	defer span.End()
 
 	query, args, err := sq.Insert("film_actor").
-		Columns("film_id", "actor_id").
-		Values(movieID, actorID).
+		Columns("film_id", "actor_id", "character_name", "billing_order").
+		Values(movieID, member.ActorID, member.CharacterName, billingOrderValue(member.BillingOrder)).
 		Suffix("ON CONFLICT DO NOTHING").
 		PlaceholderFormat(sq.Dollar).
 		ToSql()
@@ -256,11 +504,11 @@ func (m *movie) AddActor(movieID, actorID int) error {
 		return fmt.Errorf("failed to build add actor query: %w", err)
 	}
 
-	_, err = m.db.Exec(query, args...)
+	_, err = m.stmts.execContext(ctx, m.db, query, args...)
 	if err != nil {
 		log.Printf("Error adding actor to movie: %v", err)
 		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
-		return fmt.Errorf("failed to add actor to movie: %w", err)
+		return fmt.Errorf("failed to add actor to movie: %w", constraintError(err))
 	}
 	dbQueryDurationSeconds.WithLabelValues(operation, queryType).Observe(time.Since(start).Seconds())
 	dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
@@ -268,10 +516,12 @@ func (m *movie) AddActor(movieID, actorID int) error {
 }
 
 // RemoveActor удаляет актёра из фильма.
-func (m *movie) RemoveActor(movieID, actorID int) error {
+func (m *movie) RemoveActor(ctx context.Context, movieID, actorID int) error {
 	start := time.Now()
 	operation := "remove_actor_from_movie"
 	queryType := "DELETE"
+	ctx, span := startSpan(ctx, operation, queryType)
+	defer span.End()
 
 	query, args, err := sq.Delete("film_actor").
 		Where(sq.Eq{"film_id": movieID, "actor_id": actorID}).
@@ -282,7 +532,7 @@ func (m *movie) RemoveActor(movieID, actorID int) error {
 		return err
 	}
 
-	_, err = m.db.Exec(query, args...)
+	_, err = m.db.ExecContext(ctx, query, args...)
 	if err != nil {
 		log.Printf("Error removing actor from movie: %v", err)
 		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
@@ -293,16 +543,16 @@ func (m *movie) RemoveActor(movieID, actorID int) error {
 	return nil
 }
 
-// GetActorsForMovieByID возвращает актёров фильма.
-func (m *movie) GetActorsForMovieByID(movieID int) ([]domain.Actor, error) {
+// GetActorsForMovieByID возвращает состав фильма в порядке титров с именами персонажей.
+// Актёры без заданного места в титрах идут последними, по имени.
+func (m *movie) GetActorsForMovieByID(ctx context.Context, movieID int) ([]domain.Actor, error) {
 	start := time.Now()
 	operation := "get_actors_for_movie_by_id"
 	queryType := "SELECT"
+	ctx, span := startSpan(ctx, operation, queryType)
+	defer span.End()
 
-	query, args, err := sq.Select("a.id", "a.name", "a.gender", "a.birth_date").
-		From("actors a").
-		Join("film_actor fa ON a.id = fa.actor_id").
-		Where(sq.Eq{"fa.film_id": movieID}).
+	query, args, err := movieCastQuery(movieID).
 		PlaceholderFormat(sq.Dollar).
 		ToSql()
 
@@ -311,30 +561,15 @@ func (m *movie) GetActorsForMovieByID(movieID int) ([]domain.Actor, error) {
 		return nil, err
 	}
 
-	rows, err := m.db.Query(query, args...)
+	rows, err := m.stmts.queryContext(ctx, m.db, query, args...)
 	if err != nil {
 		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
 		return nil, err
 	}
 	defer rows.Close()
 
-	var actors []domain.Actor
-	for rows.Next() {
-		var actor domain.Actor
-		err := rows.Scan(
-			&actor.ID,
-			&actor.Name,
-			&actor.Gender,
-			&actor.BirthDate,
-		)
-		if err != nil {
-			dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
-			return nil, err
-		}
-		actors = append(actors, actor)
-	}
-
-	if err = rows.Err(); err != nil {
+	actors, err := scanCast(rows)
+	if err != nil {
 		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
 		return nil, err
 	}
@@ -345,10 +580,12 @@ func (m *movie) GetActorsForMovieByID(movieID int) ([]domain.Actor, error) {
 }
 
 // RemoveAllActors удаляет всех актёров из фильма.
-func (m *movie) RemoveAllActors(movieID int) error {
+func (m *movie) RemoveAllActors(ctx context.Context, movieID int) error {
 	start := time.Now()
 	operation := "remove_all_actors_from_movie"
 	queryType := "DELETE"
+	ctx, span := startSpan(ctx, operation, queryType)
+	defer span.End()
 
 	query, args, err := sq.Delete("film_actor").
 		Where(sq.Eq{"film_id": movieID}).
@@ -359,7 +596,7 @@ func (m *movie) RemoveAllActors(movieID int) error {
 		return err
 	}
 
-	_, err = m.db.Exec(query, args...)
+	_, err = m.db.ExecContext(ctx, query, args...)
 	if err != nil {
 		log.Printf("Error removing all actors from movie: %v", err)
 		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
@@ -371,12 +608,14 @@ func (m *movie) RemoveAllActors(movieID int) error {
 }
 
 // CreateMovieWithActors создаёт фильм с актёрами.
-func (m *movie) CreateMovieWithActors(movie domain.Movie, actorIDs []int) (int, error) {
+func (m *movie) CreateMovieWithActors(ctx context.Context, movie domain.Movie, actorIDs []int) (int, error) {
 	start := time.Now()
 	operation := "create_movie_with_actors"
 	queryType := "INSERT"
+	ctx, span := startSpan(ctx, operation, queryType)
+	defer span.End()
 
-	tx, err := m.db.Begin()
+	tx, err := m.db.BeginTx(ctx, nil)
 	if err != nil {
 		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
 		return 0, fmt.Errorf("failed to begin transaction: %w", err)
@@ -384,19 +623,14 @@ func (m *movie) CreateMovieWithActors(movie domain.Movie, actorIDs []int) (int,
 	defer tx.Rollback()
 
 	// Создаём фильм
-	query, args, err := sq.Insert("films").
-		Columns("title", "description", "release_year", "rating").
-		Values(movie.Title, movie.Description, movie.ReleaseYear, movie.Rating).
-		Suffix("RETURNING id").
-		PlaceholderFormat(sq.Dollar).
-		ToSql()
+	movieSlug, err := freeSlug(ctx, tx, "films", movieSlugBase(movie))
 	if err != nil {
 		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
-		return 0, fmt.Errorf("failed to build create movie query: %w", err)
+		return 0, err
 	}
-
-	var movieID int
-	err = tx.QueryRow(query, args...).Scan(&movieID)
+	movieID, err := insertReturningID(ctx, tx, sq.Insert("films").
+		Columns("title", "description", "release_year", "rating", "release_date", "original_language", "country", "runtime_minutes", "slug").
+		Values(movie.Title, movie.Description, movie.ReleaseYear, movie.Rating, movie.ReleaseDate, movie.OriginalLanguage, movie.Country, movie.RuntimeMinutes, movieSlug))
 	if err != nil {
 		log.Printf("Error creating movie: %v", err)
 		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
@@ -410,15 +644,15 @@ func (m *movie) CreateMovieWithActors(movie domain.Movie, actorIDs []int) (int,
 			insertBuilder = insertBuilder.Values(movieID, actorID)
 		}
 
-		query, args, err = insertBuilder.PlaceholderFormat(sq.Dollar).ToSql()
+		query, args, err := insertBuilder.PlaceholderFormat(sq.Dollar).ToSql()
 		if err != nil {
 			dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
 			return 0, fmt.Errorf("failed to build add actors query: %w", err)
 		}
 
-		if _, err = tx.Exec(query, args...); err != nil {
+		if _, err = tx.ExecContext(ctx, query, args...); err != nil {
 			log.Printf("Error adding actors to movie: %v", err)
-			return 0, fmt.Errorf("failed to add actors to movie: %w", err)
+			return 0, fmt.Errorf("failed to add actors to movie: %w", constraintError(err))
 		}
 	}
 
@@ -432,13 +666,134 @@ func (m *movie) CreateMovieWithActors(movie domain.Movie, actorIDs []int) (int,
 	return movieID, nil
 }
 
-// UpdateMovieActors обновляет актёров фильма.
-func (m *movie) UpdateMovieActors(movieID int, actorIDs []int) error {
+// ImportMovie создаёт фильм вместе с составом в одной транзакции: актёры, которых
+// ещё нет в каталоге (по имени без учёта регистра и дате рождения, если она известна),
+// создаются, остальные переиспользуются. Актёр с заданным ID не ищется, а сразу
+// связывается с фильмом; роль и место в титрах берутся из CharacterName и BillingOrder.
+func (m *movie) ImportMovie(ctx context.Context, movie domain.Movie, cast []domain.Actor) (domain.MovieImportResult, error) {
+	start := time.Now()
+	operation := "import_movie"
+	queryType := "INSERT"
+	ctx, span := startSpan(ctx, operation, queryType)
+	defer span.End()
+
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return domain.MovieImportResult{}, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result := domain.MovieImportResult{ActorIDs: make([]int, 0, len(cast))}
+	members := make([]domain.CastMember, 0, len(cast))
+	linked := make(map[int]bool, len(cast))
+	for _, actor := range cast {
+		actorID, created := actor.ID, false
+		if actorID == 0 {
+			actorID, created, err = m.findOrCreateActor(ctx, tx, actor)
+			if err != nil {
+				log.Printf("Error importing actor %q: %v", actor.Name, err)
+				dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+				return domain.MovieImportResult{}, fmt.Errorf("failed to import actor: %w", err)
+			}
+		}
+		if created {
+			result.ActorsCreated++
+			result.CreatedActorIDs = append(result.CreatedActorIDs, actorID)
+		}
+		if !linked[actorID] {
+			linked[actorID] = true
+			result.ActorIDs = append(result.ActorIDs, actorID)
+			members = append(members, domain.CastMember{ActorID: actorID, CharacterName: actor.CharacterName, BillingOrder: actor.BillingOrder})
+		}
+	}
+
+	movieSlug, err := freeSlug(ctx, tx, "films", movieSlugBase(movie))
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return domain.MovieImportResult{}, err
+	}
+	result.MovieID, err = insertReturningID(ctx, tx, sq.Insert("films").
+		Columns("title", "description", "release_year", "rating", "release_date", "original_language", "country", "runtime_minutes", "slug").
+		Values(movie.Title, movie.Description, movie.ReleaseYear, movie.Rating, movie.ReleaseDate, movie.OriginalLanguage, movie.Country, movie.RuntimeMinutes, movieSlug))
+	if err != nil {
+		log.Printf("Error creating imported movie: %v", err)
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return domain.MovieImportResult{}, fmt.Errorf("failed to create movie: %w", err)
+	}
+
+	if len(members) > 0 {
+		insertBuilder := sq.Insert("film_actor").Columns("film_id", "actor_id", "character_name", "billing_order")
+		for _, member := range members {
+			insertBuilder = insertBuilder.Values(result.MovieID, member.ActorID, member.CharacterName, billingOrderValue(member.BillingOrder))
+		}
+		query, args, err := insertBuilder.PlaceholderFormat(sq.Dollar).ToSql()
+		if err != nil {
+			dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+			return domain.MovieImportResult{}, fmt.Errorf("failed to build add actors query: %w", err)
+		}
+		if _, err = tx.ExecContext(ctx, query, args...); err != nil {
+			log.Printf("Error adding actors to imported movie: %v", err)
+			dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+			return domain.MovieImportResult{}, fmt.Errorf("failed to add actors to movie: %w", constraintError(err))
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return domain.MovieImportResult{}, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	dbQueryDurationSeconds.WithLabelValues(operation, queryType).Observe(time.Since(start).Seconds())
+	dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+	return result, nil
+}
+
+// findOrCreateActor возвращает ID существующего актёра или создаёт нового внутри транзакции tx.
+func (m *movie) findOrCreateActor(ctx context.Context, tx *sql.Tx, actor domain.Actor) (id int, created bool, err error) {
+	builder := sq.Select("id").
+		From("actors").
+		Where("LOWER(name) = LOWER(?)", actor.Name).
+		OrderBy("id ASC").
+		Limit(1)
+	if !actor.BirthDate.IsZero() {
+		builder = builder.Where(sq.Eq{"birth_date": actor.BirthDate})
+	}
+	query, args, err := builder.PlaceholderFormat(sq.Dollar).ToSql()
+	if err != nil {
+		return 0, false, fmt.Errorf("building query: %w", err)
+	}
+
+	err = tx.QueryRowContext(ctx, query, args...).Scan(&id)
+	if err == nil {
+		return id, false, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return 0, false, err
+	}
+
+	actorSlug, err := freeSlug(ctx, tx, "actors", actorSlugBase(actor))
+	if err != nil {
+		return 0, false, err
+	}
+	id, err = insertReturningID(ctx, tx, sq.Insert("actors").
+		Columns("name", "gender", "birth_date", "slug").
+		Values(actor.Name, actor.Gender, actor.BirthDate, actorSlug))
+	if err != nil {
+		return 0, false, err
+	}
+	return id, true, nil
+}
+
+// UpdateMovieActors заменяет состав фильма.
+func (m *movie) UpdateMovieActors(ctx context.Context, movieID int, cast []domain.CastMember) error {
 	start := time.Now()
 	operation := "update_movie_actors"
 	queryType := "UPDATE"
+	ctx, span := startSpan(ctx, operation, queryType)
+	defer span.End()
 
-	tx, err := m.db.Begin()
+	tx, err := m.db.BeginTx(ctx, nil)
 	if err != nil {
 		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
 		return fmt.Errorf("failed to begin transaction: %w", err)
@@ -455,17 +810,17 @@ func (m *movie) UpdateMovieActors(movieID int, actorIDs []int) error {
 		return fmt.Errorf("failed to build delete film_actor query: %w", err)
 	}
 
-	if _, err = tx.Exec(delQuery, delArgs...); err != nil {
+	if _, err = tx.ExecContext(ctx, delQuery, delArgs...); err != nil {
 		log.Printf("Error deleting film_actor relations: %v", err)
 		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
 		return fmt.Errorf("failed to delete film_actor relations: %w", err)
 	}
 
 	// Добавляем новые связи, если они есть
-	if len(actorIDs) > 0 {
-		insertBuilder := sq.Insert("film_actor").Columns("film_id", "actor_id")
-		for _, actorID := range actorIDs {
-			insertBuilder = insertBuilder.Values(movieID, actorID)
+	if len(cast) > 0 {
+		insertBuilder := sq.Insert("film_actor").Columns("film_id", "actor_id", "character_name", "billing_order")
+		for _, member := range cast {
+			insertBuilder = insertBuilder.Values(movieID, member.ActorID, member.CharacterName, billingOrderValue(member.BillingOrder))
 		}
 
 		insertQuery, insertArgs, err := insertBuilder.PlaceholderFormat(sq.Dollar).ToSql()
@@ -474,9 +829,9 @@ func (m *movie) UpdateMovieActors(movieID int, actorIDs []int) error {
 			return fmt.Errorf("failed to build insert film_actor query: %w", err)
 		}
 
-		if _, err = tx.Exec(insertQuery, insertArgs...); err != nil {
+		if _, err = tx.ExecContext(ctx, insertQuery, insertArgs...); err != nil {
 			log.Printf("Error adding actors to movie: %v", err)
-			return fmt.Errorf("failed to add actors to movie: %w", err)
+			return fmt.Errorf("failed to add actors to movie: %w", constraintError(err))
 		}
 	}
 
@@ -491,12 +846,14 @@ func (m *movie) UpdateMovieActors(movieID int, actorIDs []int) error {
 }
 
 // GetMoviesForActor возвращает фильмы по актёру.
-func (m *movie) GetMoviesForActor(actorID int) ([]domain.Movie, error) {
+func (m *movie) GetMoviesForActor(ctx context.Context, actorID int) ([]domain.Movie, error) {
 	start := time.Now()
 	operation := "get_movies_for_actor"
 	queryType := "SELECT"
+	ctx, span := startSpan(ctx, operation, queryType)
+	defer span.End()
 
-	query, args, err := sq.Select("f.id", "f.title", "f.description", "f.release_year", "f.rating").
+	query, args, err := sq.Select(prefixedMovieColumns("f")...).
 		From("films f").
 		Join("film_actor fa ON f.id = fa.film_id").
 		Where(sq.Eq{"fa.actor_id": actorID}).
@@ -508,7 +865,7 @@ func (m *movie) GetMoviesForActor(actorID int) ([]domain.Movie, error) {
 		return nil, err
 	}
 
-	rows, err := m.db.Query(query, args...)
+	rows, err := m.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
 		return nil, err
@@ -517,14 +874,8 @@ func (m *movie) GetMoviesForActor(actorID int) ([]domain.Movie, error) {
 
 	var movies []domain.Movie
 	for rows.Next() {
-		var movie domain.Movie
-		if err := rows.Scan(
-			&movie.ID,
-			&movie.Title,
-			&movie.Description,
-			&movie.ReleaseYear,
-			&movie.Rating,
-		); err != nil {
+		movie, err := scanMovie(rows)
+		if err != nil {
 			dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
 			return nil, err
 		}
@@ -541,22 +892,25 @@ func (m *movie) GetMoviesForActor(actorID int) ([]domain.Movie, error) {
 	return movies, nil
 }
 
-// SearchMoviesByTitle ищет фильмы по названию.
-func (m *movie) SearchMoviesByTitle(titleFragment string) ([]domain.Movie, error) {
+// SearchMoviesByTitle ищет фильмы по названию с учётом фильтра по языку и стране.
+func (m *movie) SearchMoviesByTitle(ctx context.Context, titleFragment string, filter domain.MovieFilter) ([]domain.Movie, error) {
 	start := time.Now()
 	operation := "search_movies_by_title"
 	queryType := "SELECT"
+	ctx, span := startSpan(ctx, operation, queryType)
+	defer span.End()
 
-	query, args, err := sq.Select("id", "title", "description", "release_year", "rating").
+	builder := sq.Select(movieColumns...).
 		From("films").
-		Where("title ILIKE $1", "%"+titleFragment+"%"). // PostgreSQL ILIKE для case-insensitive поиска
+		Where("title ILIKE ?", "%"+titleFragment+"%") // регистронезависимый поиск
+	query, args, err := applyMovieFilter(builder, "", filter).
 		PlaceholderFormat(sq.Dollar).
 		ToSql()
 	if err != nil {
 		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
 		return nil, err
 	}
-	rows, err := m.db.Query(query, args...)
+	rows, err := m.stmts.queryContext(ctx, m.replica.pick(m.db), query, args...)
 	if err != nil {
 		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
 		return nil, err
@@ -564,8 +918,8 @@ func (m *movie) SearchMoviesByTitle(titleFragment string) ([]domain.Movie, error
 	defer rows.Close()
 	var movies []domain.Movie
 	for rows.Next() {
-		var movie domain.Movie
-		if err := rows.Scan(&movie.ID, &movie.Title, &movie.Description, &movie.ReleaseYear, &movie.Rating); err != nil {
+		movie, err := scanMovie(rows)
+		if err != nil {
 			dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
 			return nil, err
 		}
@@ -583,33 +937,41 @@ func (m *movie) SearchMoviesByTitle(titleFragment string) ([]domain.Movie, error
 	return movies, nil
 }
 
-// SearchMoviesByActorName ищет фильмы по имени актёра.
-func (m *movie) SearchMoviesByActorName(actorNameFragment string) ([]domain.Movie, error) {
+// SearchMoviesByTitleTrigram ищет фильмы по подстроке названия или по триграммной похожести
+// (опечатки), отсортированные по убыванию похожести. Новая реализация поиска по названию,
+// проверяется канареечным запуском (см. MovieService.WithTitleSearchCanary)
+func (m *movie) SearchMoviesByTitleTrigram(ctx context.Context, titleFragment string, filter domain.MovieFilter) ([]domain.Movie, error) {
 	start := time.Now()
-	operation := "search_movies_by_actor_name"
+	operation := "search_movies_by_title_trigram"
 	queryType := "SELECT"
+	ctx, span := startSpan(ctx, operation, queryType)
+	defer span.End()
 
-	query, args, err := sq.Select("f.id", "f.title", "f.description", "f.release_year", "f.rating").
-		From("films f").
-		Join("film_actor fa ON f.id = fa.film_id").
-		Join("actors a ON fa.actor_id = a.id").
-		Where("a.name ILIKE $1", "%"+actorNameFragment+"%").
+	builder := sq.Select(movieColumns...).
+		From("films").
+		Where(sq.Or{
+			sq.Expr("title ILIKE ?", "%"+titleFragment+"%"),
+			sq.Expr("title % ?", titleFragment),
+		})
+	query, args, err := applyMovieFilter(builder, "", filter).
+		OrderByClause("similarity(title, ?) DESC", titleFragment).
+		OrderBy("id ASC").
 		PlaceholderFormat(sq.Dollar).
 		ToSql()
 	if err != nil {
 		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
 		return nil, err
 	}
-	rows, err := m.db.Query(query, args...)
+	rows, err := m.replica.pick(m.db).QueryContext(ctx, query, args...)
 	if err != nil {
 		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
 		return nil, err
 	}
 	defer rows.Close()
-	var movies []domain.Movie
+	movies := make([]domain.Movie, 0)
 	for rows.Next() {
-		var movie domain.Movie
-		if err := rows.Scan(&movie.ID, &movie.Title, &movie.Description, &movie.ReleaseYear, &movie.Rating); err != nil {
+		movie, err := scanMovie(rows)
+		if err != nil {
 			dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
 			return nil, err
 		}
@@ -619,38 +981,32 @@ func (m *movie) SearchMoviesByActorName(actorNameFragment string) ([]domain.Movi
 		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
 		return nil, err
 	}
-	if movies == nil {
-		movies = []domain.Movie{}
-	}
 	dbQueryDurationSeconds.WithLabelValues(operation, queryType).Observe(time.Since(start).Seconds())
 	dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
 	return movies, nil
 }
 
-// GetAllMoviesSorted возвращает фильмы с сортировкой.
-func (m *movie) GetAllMoviesSorted(sortField, sortOrder string) ([]domain.Movie, error) {
+// SearchMoviesByActorName ищет фильмы по имени актёра с учётом фильтра по языку и стране.
+func (m *movie) SearchMoviesByActorName(ctx context.Context, actorNameFragment string, filter domain.MovieFilter) ([]domain.Movie, error) {
 	start := time.Now()
-	operation := "get_all_movies_sorted"
+	operation := "search_movies_by_actor_name"
 	queryType := "SELECT"
+	ctx, span := startSpan(ctx, operation, queryType)
+	defer span.End()
 
-	// Валидация поля сортировки
-	allowedFields := map[string]bool{"title": true, "rating": true, "release_year": true}
-	if !allowedFields[sortField] {
-		sortField = "rating"
-	}
-	if sortOrder != "ASC" && sortOrder != "DESC" {
-		sortOrder = "DESC"
-	}
-	query := sq.Select("id", "title", "description", "release_year", "rating").
-		From("films").
-		OrderBy(sortField + " " + sortOrder).
-		PlaceholderFormat(sq.Dollar)
-	qstr, args, err := query.ToSql()
+	builder := sq.Select(prefixedMovieColumns("f")...).
+		From("films f").
+		Join("film_actor fa ON f.id = fa.film_id").
+		Join("actors a ON fa.actor_id = a.id").
+		Where("a.name ILIKE ?", "%"+actorNameFragment+"%")
+	query, args, err := applyMovieFilter(builder, "f.", filter).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
 	if err != nil {
 		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
 		return nil, err
 	}
-	rows, err := m.db.Query(qstr, args...)
+	rows, err := m.stmts.queryContext(ctx, m.replica.pick(m.db), query, args...)
 	if err != nil {
 		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
 		return nil, err
@@ -658,8 +1014,8 @@ func (m *movie) GetAllMoviesSorted(sortField, sortOrder string) ([]domain.Movie,
 	defer rows.Close()
 	var movies []domain.Movie
 	for rows.Next() {
-		var movie domain.Movie
-		if err := rows.Scan(&movie.ID, &movie.Title, &movie.Description, &movie.ReleaseYear, &movie.Rating); err != nil {
+		movie, err := scanMovie(rows)
+		if err != nil {
 			dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
 			return nil, err
 		}
@@ -677,35 +1033,169 @@ func (m *movie) GetAllMoviesSorted(sortField, sortOrder string) ([]domain.Movie,
 	return movies, nil
 }
 
-// PartialUpdateMovie частично обновляет фильм.
-func (m *movie) PartialUpdateMovie(id int, update domain.MovieUpdate) error {
+// GetMatchingActors возвращает актёров фильмов movieIDs, в имени которых встречается nameFragment,
+// сгруппированных по ID фильма. Нужен для подсветки совпадений в результатах поиска по актёру
+func (m *movie) GetMatchingActors(ctx context.Context, movieIDs []int, nameFragment string) (map[int][]domain.Actor, error) {
 	start := time.Now()
-	operation := "partial_update_movie"
-	queryType := "UPDATE"
+	operation := "get_matching_actors"
+	queryType := "SELECT"
+	ctx, span := startSpan(ctx, operation, queryType)
+	defer span.End()
 
-	builder := sq.Update("films").Where(sq.Eq{"id": id}).PlaceholderFormat(sq.Dollar)
-	if update.Title != nil {
-		builder = builder.Set("title", *update.Title)
-	}
-	if update.Description != nil {
-		builder = builder.Set("description", *update.Description)
-	}
-	if update.ReleaseYear != nil {
-		builder = builder.Set("release_year", *update.ReleaseYear)
-	}
-	if update.Rating != nil {
-		builder = builder.Set("rating", *update.Rating)
-	}
-	query, args, err := builder.ToSql()
+	matches := make(map[int][]domain.Actor)
+	if len(movieIDs) == 0 {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return matches, nil
+	}
+	query, args, err := sq.Select("fa.film_id", "a.id", "a.name").
+		From("film_actor fa").
+		Join("actors a ON a.id = fa.actor_id").
+		Where(sq.Eq{"fa.film_id": movieIDs}).
+		Where("a.name ILIKE ?", "%"+nameFragment+"%").
+		OrderBy("fa.film_id", "a.name", "a.id").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
 	if err != nil {
 		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
-		return err
+		return nil, err
 	}
-	result, err := m.db.Exec(query, args...)
+	rows, err := m.replica.pick(m.db).QueryContext(ctx, query, args...)
 	if err != nil {
-		log.Printf("Error partial updating movie: %v", err)
 		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
-		return err
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var movieID int
+		var actor domain.Actor
+		if err := rows.Scan(&movieID, &actor.ID, &actor.Name); err != nil {
+			dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+			return nil, err
+		}
+		matches[movieID] = append(matches[movieID], actor)
+	}
+	if err := rows.Err(); err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return nil, err
+	}
+	dbQueryDurationSeconds.WithLabelValues(operation, queryType).Observe(time.Since(start).Seconds())
+	dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+	return matches, nil
+}
+
+// GetAllMoviesSorted возвращает страницу фильмов, отсортированных по нескольким полям.
+// Поля проверяются по белому списку; при совпадении значений порядок определяется по id.
+func (m *movie) GetAllMoviesSorted(ctx context.Context, listQuery domain.MovieListQuery) ([]domain.Movie, error) {
+	start := time.Now()
+	operation := "get_all_movies_sorted"
+	queryType := "SELECT"
+	ctx, span := startSpan(ctx, operation, queryType)
+	defer span.End()
+
+	sortFields := listQuery.Sort
+	if len(sortFields) == 0 {
+		sortFields = defaultMovieSort
+	}
+	// Поля и направления сортировки берутся только из реестра безопасных идентификаторов
+	orderBy, err := movieSortColumns.orderBy(sortFields, "id")
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return nil, err
+	}
+	query := sq.Select(movieColumns...).
+		From("films").
+		OrderBy(orderBy...).
+		PlaceholderFormat(sq.Dollar)
+	if listQuery.Limit > 0 {
+		query = query.Limit(uint64(listQuery.Limit))
+	}
+	if listQuery.Offset > 0 {
+		query = query.Offset(uint64(listQuery.Offset))
+	}
+	qstr, args, err := query.ToSql()
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return nil, err
+	}
+	rows, err := m.replica.pick(m.db).QueryContext(ctx, qstr, args...)
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return nil, err
+	}
+	defer rows.Close()
+	var movies []domain.Movie
+	for rows.Next() {
+		movie, err := scanMovie(rows)
+		if err != nil {
+			dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+			return nil, err
+		}
+		movies = append(movies, movie)
+	}
+	if err := rows.Err(); err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return nil, err
+	}
+	if movies == nil {
+		movies = []domain.Movie{}
+	}
+	dbQueryDurationSeconds.WithLabelValues(operation, queryType).Observe(time.Since(start).Seconds())
+	dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+	return movies, nil
+}
+
+// CountMovies возвращает число фильмов, подходящих под filter, — всего для метаданных пагинации.
+// Пустой фильтр считает все фильмы
+func (m *movie) CountMovies(ctx context.Context, filter domain.MovieFilter) (int, error) {
+	start := time.Now()
+	operation := "count_movies"
+	queryType := "SELECT"
+	ctx, span := startSpan(ctx, operation, queryType)
+	defer span.End()
+
+	query, args, err := applyMovieFilter(sq.Select("COUNT(*)").From("films"), "", filter).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return 0, err
+	}
+	var count int
+	if err := m.replica.pick(m.db).QueryRowContext(ctx, query, args...).Scan(&count); err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return 0, err
+	}
+	dbQueryDurationSeconds.WithLabelValues(operation, queryType).Observe(time.Since(start).Seconds())
+	dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+	return count, nil
+}
+
+// PartialUpdateMovie обновляет одним UPDATE только переданные поля фильма, не перезаписывая
+// остальные. Если передана дата выхода без года, а год у фильма не заполнен, год берётся из даты
+func (m *movie) PartialUpdateMovie(ctx context.Context, id int, update domain.MovieUpdate) error {
+	start := time.Now()
+	operation := "partial_update_movie"
+	queryType := "UPDATE"
+	ctx, span := startSpan(ctx, operation, queryType)
+	defer span.End()
+
+	if update.IsEmpty() {
+		return domain.ErrNoFieldsToUpdate
+	}
+
+	query, args, err := setMovieUpdate(sq.Update("films"), update).
+		Where(sq.Eq{"id": id}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return err
+	}
+	result, err := m.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		log.Printf("Error partial updating movie: %v", err)
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return err
 	}
 	rowsAffected, err := result.RowsAffected()
 	if err != nil {
@@ -714,9 +1204,572 @@ func (m *movie) PartialUpdateMovie(id int, update domain.MovieUpdate) error {
 	}
 	if rowsAffected == 0 {
 		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
-		return errors.New("no rows updated")
+		return domain.ErrMovieNotFound
+	}
+	dbQueryDurationSeconds.WithLabelValues(operation, queryType).Observe(time.Since(start).Seconds())
+	dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+	return nil
+}
+
+// setMovieUpdate добавляет в UPDATE переданные поля обновления
+func setMovieUpdate(builder sq.UpdateBuilder, update domain.MovieUpdate) sq.UpdateBuilder {
+	if update.Title != nil {
+		builder = builder.Set("title", *update.Title)
+	}
+	if update.Description != nil {
+		builder = builder.Set("description", *update.Description)
+	}
+	if update.ReleaseYear != nil {
+		builder = builder.Set("release_year", *update.ReleaseYear)
+	}
+	if update.Rating != nil {
+		builder = builder.Set("rating", *update.Rating)
+	}
+	if update.ReleaseDate != nil {
+		builder = builder.Set("release_date", *update.ReleaseDate)
+		if update.ReleaseYear == nil {
+			builder = builder.Set("release_year", sq.Expr("CASE WHEN release_year = 0 THEN ? ELSE release_year END", update.ReleaseDate.Year()))
+		}
+	} else if update.ClearReleaseDate {
+		builder = builder.Set("release_date", nil)
+	}
+	if update.OriginalLanguage != nil {
+		builder = builder.Set("original_language", *update.OriginalLanguage)
+	}
+	if update.Country != nil {
+		builder = builder.Set("country", *update.Country)
+	}
+	if update.RuntimeMinutes != nil {
+		builder = builder.Set("runtime_minutes", *update.RuntimeMinutes)
+	}
+	return builder
+}
+
+// BulkDeleteMovies удаляет фильмы вместе со связями с актёрами в одной транзакции.
+// Отсутствующий фильм или фильм со ссылками при политике restrict не прерывает операцию,
+// а отмечается в результате ошибкой ErrMovieNotFound или *domain.MovieReferencedError
+func (m *movie) BulkDeleteMovies(ctx context.Context, ids []int, policy domain.MovieDeletePolicy) ([]domain.BulkItemResult, error) {
+	return m.bulkMovies(ctx, "bulk_delete_movies", "DELETE", ids, func(tx *sql.Tx, id int) error {
+		if err := lockMovieTx(ctx, tx, id); err != nil {
+			return err
+		}
+		return deleteLockedMovieTx(ctx, tx, id, policy)
+	})
+}
+
+// BulkUpdateMovies применяет одно и то же обновление к фильмам в одной транзакции.
+// Отсутствующий фильм не прерывает операцию, а отмечается в результате ошибкой ErrMovieNotFound
+func (m *movie) BulkUpdateMovies(ctx context.Context, ids []int, update domain.MovieUpdate) ([]domain.BulkItemResult, error) {
+	if update.IsEmpty() {
+		return nil, domain.ErrNoFieldsToUpdate
+	}
+	return m.bulkMovies(ctx, "bulk_update_movies", "UPDATE", ids, func(tx *sql.Tx, id int) error {
+		query, args, err := setMovieUpdate(sq.Update("films"), update).
+			Where(sq.Eq{"id": id}).
+			PlaceholderFormat(sq.Dollar).
+			ToSql()
+		if err != nil {
+			return fmt.Errorf("failed to build update movie query: %w", err)
+		}
+		return execAffectingMovie(ctx, tx, query, args)
+	})
+}
+
+// execAffectingMovie выполняет запрос и возвращает ErrMovieNotFound, если он не затронул ни одной строки
+func execAffectingMovie(ctx context.Context, tx *sql.Tx, query string, args []interface{}) error {
+	result, err := tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return domain.ErrMovieNotFound
+	}
+	return nil
+}
+
+// bulkSavepoint — точка сохранения, под которой обрабатывается очередной фильм массовой операции
+const bulkSavepoint = "bulk_item"
+
+// bulkMovies выполняет fn для каждого фильма в одной транзакции. Каждый фильм обрабатывается
+// под своей точкой сохранения: ошибка откатывает изменения только этого фильма и попадает
+// в его результат, а изменения остальных фиксируются вместе
+func (m *movie) bulkMovies(ctx context.Context, operation, queryType string, ids []int, fn func(tx *sql.Tx, id int) error) ([]domain.BulkItemResult, error) {
+	start := time.Now()
+	ctx, span := startSpan(ctx, operation, queryType)
+	defer span.End()
+
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	results := make([]domain.BulkItemResult, 0, len(ids))
+	for _, id := range ids {
+		if _, err := tx.ExecContext(ctx, "SAVEPOINT "+bulkSavepoint); err != nil {
+			dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+			return nil, fmt.Errorf("failed to create savepoint: %w", err)
+		}
+		itemErr := fn(tx, id)
+		if itemErr != nil {
+			log.Printf("Error in %s for movie (ID: %d): %v", operation, id, itemErr)
+			_, err = tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT "+bulkSavepoint)
+		} else {
+			_, err = tx.ExecContext(ctx, "RELEASE SAVEPOINT "+bulkSavepoint)
+		}
+		if err != nil {
+			dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+			return nil, fmt.Errorf("failed to finish savepoint: %w", err)
+		}
+		results = append(results, domain.BulkItemResult{ID: id, Err: itemErr})
+	}
+
+	if err = tx.Commit(); err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	dbQueryDurationSeconds.WithLabelValues(operation, queryType).Observe(time.Since(start).Seconds())
+	dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+	return results, nil
+}
+
+// MergeMovies переносит связи фильма-дубликата на основной фильм (актёров, подборки, теги,
+// окна доступности, рейтинги источников и внешний ID), дополняет пустые поля, сохраняет
+// соответствие старого ID новому и удаляет дубликат в одной транзакции.
+// Дубликат удаляется по политике policy, как при обычном удалении
+func (m *movie) MergeMovies(ctx context.Context, keepID, dupID int, policy domain.MovieDeletePolicy) (domain.MovieMergeResult, error) {
+	start := time.Now()
+	operation := "merge_movies"
+	queryType := "UPDATE"
+	ctx, span := startSpan(ctx, operation, queryType)
+	defer span.End()
+
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return domain.MovieMergeResult{}, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Блокируем обе записи до конца транзакции
+	selectMovie := func(id int) (domain.Movie, error) {
+		query, args, err := sq.Select(movieDetailColumns...).
+			From("films").
+			Where(sq.Eq{"id": id}).
+			Suffix("FOR UPDATE").
+			PlaceholderFormat(sq.Dollar).
+			ToSql()
+		if err != nil {
+			return domain.Movie{}, fmt.Errorf("building query: %w", err)
+		}
+		movie, err := scanMovieDetail(tx.QueryRowContext(ctx, query, args...))
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return domain.Movie{}, domain.ErrMovieNotFound
+			}
+			return domain.Movie{}, fmt.Errorf("scanning movie: %w", err)
+		}
+		return movie, nil
+	}
+
+	keep, err := selectMovie(keepID)
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return domain.MovieMergeResult{}, err
+	}
+	dup, err := selectMovie(dupID)
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return domain.MovieMergeResult{}, err
+	}
+
+	// Дополняем незаполненные поля основного фильма данными дубликата
+	if keep.Description == "" {
+		keep.Description = dup.Description
+	}
+	if keep.ReleaseYear == 0 {
+		keep.ReleaseYear = dup.ReleaseYear
+	}
+	if keep.Rating == 0 {
+		keep.Rating = dup.Rating
+	}
+	if keep.ReleaseDate == nil {
+		keep.ReleaseDate = dup.ReleaseDate
+	}
+	if keep.OriginalLanguage == "" {
+		keep.OriginalLanguage = dup.OriginalLanguage
+	}
+	if keep.Country == "" {
+		keep.Country = dup.Country
+	}
+	if keep.RuntimeMinutes == 0 {
+		keep.RuntimeMinutes = dup.RuntimeMinutes
+	}
+	// Просмотры дубликата засчитываются основному фильму
+	keep.ViewCount += dup.ViewCount
+
+	update := sq.Update("films").
+		Set("description", keep.Description).
+		Set("release_year", keep.ReleaseYear).
+		Set("rating", keep.Rating).
+		Set("release_date", keep.ReleaseDate).
+		Set("view_count", keep.ViewCount).
+		Set("original_language", keep.OriginalLanguage).
+		Set("country", keep.Country).
+		Set("runtime_minutes", keep.RuntimeMinutes)
+
+	// Внешний ID дубликата переходит основному фильму, если у того его нет. Уникальный индекс
+	// не допускает двух фильмов с одним внешним ID, поэтому сначала он снимается с дубликата
+	if keep.ExternalID == "" && dup.ExternalID != "" {
+		clearQuery, clearArgs, err := sq.Update("films").
+			Set("external_id", nil).
+			Where(sq.Eq{"id": dupID}).
+			PlaceholderFormat(sq.Dollar).
+			ToSql()
+		if err != nil {
+			dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+			return domain.MovieMergeResult{}, fmt.Errorf("failed to build clear external id query: %w", err)
+		}
+		if _, err = tx.ExecContext(ctx, clearQuery, clearArgs...); err != nil {
+			log.Printf("Error clearing duplicate external id: %v", err)
+			dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+			return domain.MovieMergeResult{}, fmt.Errorf("failed to clear duplicate external id: %w", err)
+		}
+		keep.ExternalID = dup.ExternalID
+		update = update.Set("external_id", keep.ExternalID)
+	}
+
+	// updated_at меняет триггер, поэтому новое значение возвращает сам запрос
+	updQuery, updArgs, err := update.
+		Where(sq.Eq{"id": keepID}).
+		Suffix("RETURNING updated_at").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return domain.MovieMergeResult{}, fmt.Errorf("failed to build update movie query: %w", err)
+	}
+	if err = tx.QueryRowContext(ctx, updQuery, updArgs...).Scan(&keep.UpdatedAt); err != nil {
+		log.Printf("Error updating kept movie: %v", err)
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return domain.MovieMergeResult{}, fmt.Errorf("failed to update kept movie: %w", err)
+	}
+
+	// Переносим актёров дубликата, пропуская тех, кто уже связан с основным фильмом
+	moveQuery, moveArgs, err := sq.Insert("film_actor").
+		Columns("film_id", "actor_id", "character_name", "billing_order").
+		Select(sq.Select().
+			Column(sq.Expr("?", keepID)).
+			Columns("actor_id", "character_name", "billing_order").
+			From("film_actor").
+			Where(sq.Eq{"film_id": dupID})).
+		Suffix("ON CONFLICT DO NOTHING").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return domain.MovieMergeResult{}, fmt.Errorf("failed to build reassign film_actor query: %w", err)
+	}
+	result, err := tx.ExecContext(ctx, moveQuery, moveArgs...)
+	if err != nil {
+		log.Printf("Error reassigning film_actor relations: %v", err)
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return domain.MovieMergeResult{}, fmt.Errorf("failed to reassign film_actor relations: %w", err)
 	}
+	reassigned, err := result.RowsAffected()
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return domain.MovieMergeResult{}, fmt.Errorf("getting rows affected: %w", err)
+	}
+
+	if err := moveMovieRelationsTx(ctx, tx, keepID, dupID); err != nil {
+		log.Printf("Error moving duplicate film relations: %v", err)
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return domain.MovieMergeResult{}, err
+	}
+
+	// Ранее слитые в дубликат фильмы теперь указывают на основной фильм
+	remapQuery, remapArgs, err := sq.Update("movie_merges").
+		Set("new_id", keepID).
+		Where(sq.Eq{"new_id": dupID}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return domain.MovieMergeResult{}, fmt.Errorf("failed to build remap merges query: %w", err)
+	}
+	if _, err = tx.ExecContext(ctx, remapQuery, remapArgs...); err != nil {
+		log.Printf("Error remapping previous movie merges: %v", err)
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return domain.MovieMergeResult{}, fmt.Errorf("failed to remap movie merges: %w", err)
+	}
+
+	// Дубликат заблокирован выше, при выборке его полей
+	if err := deleteLockedMovieTx(ctx, tx, dupID, policy); err != nil {
+		log.Printf("Error deleting duplicate film: %v", err)
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return domain.MovieMergeResult{}, err
+	}
+
+	// Сохраняем соответствие для редиректа со старого ID и slug
+	mapQuery, mapArgs, err := sq.Insert("movie_merges").
+		Columns("old_id", "new_id", "old_slug").
+		Values(dupID, keepID, dup.Slug).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return domain.MovieMergeResult{}, fmt.Errorf("failed to build insert merge query: %w", err)
+	}
+	if _, err = tx.ExecContext(ctx, mapQuery, mapArgs...); err != nil {
+		log.Printf("Error recording movie merge: %v", err)
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return domain.MovieMergeResult{}, fmt.Errorf("failed to record movie merge: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return domain.MovieMergeResult{}, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
 	dbQueryDurationSeconds.WithLabelValues(operation, queryType).Observe(time.Since(start).Seconds())
 	dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+	return domain.MovieMergeResult{
+		Movie:            keep,
+		DuplicateID:      dupID,
+		ActorsReassigned: int(reassigned),
+	}, nil
+}
+
+// mergedMovieRelations — таблицы, строки которых слияние копирует с дубликата на основной фильм.
+// Строка, которая у основного фильма уже есть (тот же тег, рейтинг того же источника), не
+// копируется. Строка internal в movie_ratings повторяет films.rating и остаётся за основным фильмом
+var mergedMovieRelations = []struct {
+	table   string
+	columns []string
+	where   sq.Sqlizer
+}{
+	{table: "movie_tags", columns: []string{"tag_id", "created_at"}},
+	{table: "movie_availability", columns: []string{"region", "available_from", "available_until"}},
+	{table: "movie_ratings", columns: []string{"source", "rating", "updated_at"},
+		where: sq.NotEq{"source": domain.RatingSourceInternal}},
+}
+
+// moveMovieRelationsTx переносит подборки, теги, окна доступности и рейтинги источников
+// дубликата dupID на основной фильм keepID и удаляет оставшиеся у дубликата строки
+func moveMovieRelationsTx(ctx context.Context, tx *sql.Tx, keepID, dupID int) error {
+	// Место в подборке уникально, поэтому копия строки с той же позицией не вставилась бы:
+	// строка дубликата переводится на основной фильм, если его ещё нет в этой подборке
+	query, args, err := sq.Update("collection_movies").
+		Set("film_id", keepID).
+		Where(sq.Eq{"film_id": dupID}).
+		Where(sq.Expr("collection_id NOT IN (SELECT collection_id FROM collection_movies WHERE film_id = ?)", keepID)).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build move collection_movies query: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("failed to move collection_movies: %w", err)
+	}
+	if err := deleteMovieRowsTx(ctx, tx, "collection_movies", dupID); err != nil {
+		return err
+	}
+
+	for _, relation := range mergedMovieRelations {
+		copied := sq.Select().
+			Column(sq.Expr("?", keepID)).
+			Columns(relation.columns...).
+			From(relation.table).
+			Where(sq.Eq{"film_id": dupID})
+		if relation.where != nil {
+			copied = copied.Where(relation.where)
+		}
+		query, args, err := sq.Insert(relation.table).
+			Columns(append([]string{"film_id"}, relation.columns...)...).
+			Select(copied).
+			Suffix("ON CONFLICT DO NOTHING").
+			PlaceholderFormat(sq.Dollar).
+			ToSql()
+		if err != nil {
+			return fmt.Errorf("failed to build move %s query: %w", relation.table, err)
+		}
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+			return fmt.Errorf("failed to move %s: %w", relation.table, err)
+		}
+		if err := deleteMovieRowsTx(ctx, tx, relation.table, dupID); err != nil {
+			return err
+		}
+	}
 	return nil
-}
\ No newline at end of file
+}
+
+// deleteMovieRowsTx удаляет строки таблицы table, относящиеся к фильму movieID
+func deleteMovieRowsTx(ctx context.Context, tx *sql.Tx, table string, movieID int) error {
+	query, args, err := sq.Delete(table).
+		Where(sq.Eq{"film_id": movieID}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build delete %s query: %w", table, err)
+	}
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("failed to delete %s: %w", table, err)
+	}
+	return nil
+}
+
+// GetMergedMovieID возвращает ID фильма, в который был слит фильм с заданным ID.
+func (m *movie) GetMergedMovieID(ctx context.Context, oldID int) (int, error) {
+	start := time.Now()
+	operation := "get_merged_movie_id"
+	queryType := "SELECT"
+	ctx, span := startSpan(ctx, operation, queryType)
+	defer span.End()
+
+	query, args, err := sq.Select("new_id").
+		From("movie_merges").
+		Where(sq.Eq{"old_id": oldID}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return 0, err
+	}
+	var newID int
+	if err := m.db.QueryRowContext(ctx, query, args...).Scan(&newID); err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, domain.ErrMovieNotFound
+		}
+		return 0, err
+	}
+	dbQueryDurationSeconds.WithLabelValues(operation, queryType).Observe(time.Since(start).Seconds())
+	dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+	return newID, nil
+}
+
+// GetUpcomingMovies возвращает фильмы с датой выхода позже указанной, от ближайших к дальним.
+func (m *movie) GetUpcomingMovies(ctx context.Context, after time.Time) ([]domain.Movie, error) {
+	start := time.Now()
+	operation := "get_upcoming_movies"
+	queryType := "SELECT"
+	ctx, span := startSpan(ctx, operation, queryType)
+	defer span.End()
+
+	query, args, err := sq.Select(movieColumns...).
+		From("films").
+		Where(sq.Gt{"release_date": after}).
+		OrderBy("release_date ASC", "id ASC").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return nil, err
+	}
+	rows, err := m.replica.pick(m.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return nil, err
+	}
+	defer rows.Close()
+	movies := make([]domain.Movie, 0)
+	for rows.Next() {
+		movie, err := scanMovie(rows)
+		if err != nil {
+			dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+			return nil, err
+		}
+		movies = append(movies, movie)
+	}
+	if err := rows.Err(); err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return nil, err
+	}
+	dbQueryDurationSeconds.WithLabelValues(operation, queryType).Observe(time.Since(start).Seconds())
+	dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+	return movies, nil
+}
+
+// IncrementViewCount увеличивает счётчик просмотров фильма на delta.
+func (m *movie) IncrementViewCount(ctx context.Context, movieID int, delta int64) error {
+	start := time.Now()
+	operation := "increment_view_count"
+	queryType := "UPDATE"
+	ctx, span := startSpan(ctx, operation, queryType)
+	defer span.End()
+
+	query, args, err := sq.Update("films").
+		Set("view_count", sq.Expr("view_count + ?", delta)).
+		Where(sq.Eq{"id": movieID}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return err
+	}
+	result, err := m.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return err
+	}
+	if affected == 0 {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return domain.ErrMovieNotFound
+	}
+	dbQueryDurationSeconds.WithLabelValues(operation, queryType).Observe(time.Since(start).Seconds())
+	dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+	return nil
+}
+
+// GetPopularMovies возвращает limit самых просматриваемых фильмов.
+func (m *movie) GetPopularMovies(ctx context.Context, limit int) ([]domain.Movie, error) {
+	start := time.Now()
+	operation := "get_popular_movies"
+	queryType := "SELECT"
+	ctx, span := startSpan(ctx, operation, queryType)
+	defer span.End()
+
+	query, args, err := sq.Select(movieColumns...).
+		From("films").
+		OrderBy("view_count DESC", "id ASC").
+		Limit(uint64(limit)).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return nil, err
+	}
+	rows, err := m.replica.pick(m.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return nil, err
+	}
+	defer rows.Close()
+	movies := make([]domain.Movie, 0)
+	for rows.Next() {
+		movie, err := scanMovie(rows)
+		if err != nil {
+			dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+			return nil, err
+		}
+		movies = append(movies, movie)
+	}
+	if err := rows.Err(); err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return nil, err
+	}
+	dbQueryDurationSeconds.WithLabelValues(operation, queryType).Observe(time.Since(start).Seconds())
+	dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+	return movies, nil
+}
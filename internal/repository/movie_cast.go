@@ -0,0 +1,153 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"cinematique/internal/domain"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+// movieCastQuery выбирает состав фильма в порядке титров. Актёры без места в титрах идут
+// последними, по имени; a.id делает порядок однозначным для постраничного чтения
+func movieCastQuery(movieID int) sq.SelectBuilder {
+	return sq.Select("a.id", "a.name", "a.gender", "a.birth_date", "fa.character_name", "fa.billing_order").
+		From("actors a").
+		Join("film_actor fa ON a.id = fa.actor_id").
+		Where(sq.Eq{"fa.film_id": movieID}).
+		OrderBy("fa.billing_order IS NULL", "fa.billing_order", "a.name", "a.id")
+}
+
+// scanCast читает строки movieCastQuery; незаданное место в титрах становится 0
+func scanCast(rows *sql.Rows) ([]domain.Actor, error) {
+	var actors []domain.Actor
+	for rows.Next() {
+		var actor domain.Actor
+		var billingOrder sql.NullInt64
+		if err := rows.Scan(&actor.ID, &actor.Name, &actor.Gender, &actor.BirthDate, &actor.CharacterName, &billingOrder); err != nil {
+			return nil, err
+		}
+		actor.BillingOrder = int(billingOrder.Int64)
+		actors = append(actors, actor)
+	}
+	return actors, rows.Err()
+}
+
+// GetMovieCastPage возвращает страницу состава фильма в порядке титров
+func (m *movie) GetMovieCastPage(ctx context.Context, movieID, limit, offset int) ([]domain.Actor, error) {
+	start := time.Now()
+	operation := "get_movie_cast_page"
+	queryType := "SELECT"
+	ctx, span := startSpan(ctx, operation, queryType)
+	defer span.End()
+
+	query, args, err := movieCastQuery(movieID).
+		Limit(uint64(limit)).
+		Offset(uint64(offset)).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return nil, err
+	}
+	rows, err := m.replica.pick(m.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return nil, err
+	}
+	defer rows.Close()
+	actors, err := scanCast(rows)
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return nil, err
+	}
+	dbQueryDurationSeconds.WithLabelValues(operation, queryType).Observe(time.Since(start).Seconds())
+	dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+	return actors, nil
+}
+
+// CountMovieCast возвращает число актёров в составе фильма
+func (m *movie) CountMovieCast(ctx context.Context, movieID int) (int, error) {
+	start := time.Now()
+	operation := "count_movie_cast"
+	queryType := "SELECT"
+	ctx, span := startSpan(ctx, operation, queryType)
+	defer span.End()
+
+	query, args, err := sq.Select("COUNT(*)").
+		From("film_actor").
+		Where(sq.Eq{"film_id": movieID}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return 0, err
+	}
+	var count int
+	if err := m.replica.pick(m.db).QueryRowContext(ctx, query, args...).Scan(&count); err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return 0, err
+	}
+	dbQueryDurationSeconds.WithLabelValues(operation, queryType).Observe(time.Since(start).Seconds())
+	dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+	return count, nil
+}
+
+// ChangeMovieActors убирает из состава фильма актёров remove и добавляет актёров add в одной
+// транзакции: при ошибке состав остаётся прежним. Уже входящие в состав актёры из add
+// не меняются, отсутствующие в составе актёры из remove пропускаются
+func (m *movie) ChangeMovieActors(ctx context.Context, movieID int, add []domain.CastMember, remove []int) error {
+	start := time.Now()
+	operation := "change_movie_actors"
+	queryType := "UPDATE"
+	ctx, span := startSpan(ctx, operation, queryType)
+	defer span.End()
+
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if len(remove) > 0 {
+		query, args, err := sq.Delete("film_actor").
+			Where(sq.Eq{"film_id": movieID, "actor_id": remove}).
+			PlaceholderFormat(sq.Dollar).
+			ToSql()
+		if err != nil {
+			dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+			return fmt.Errorf("building query: %w", err)
+		}
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+			dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+			return fmt.Errorf("removing actors from movie: %w", err)
+		}
+	}
+	if len(add) > 0 {
+		builder := sq.Insert("film_actor").Columns("film_id", "actor_id", "character_name", "billing_order")
+		for _, member := range add {
+			builder = builder.Values(movieID, member.ActorID, member.CharacterName, billingOrderValue(member.BillingOrder))
+		}
+		query, args, err := builder.Suffix("ON CONFLICT DO NOTHING").PlaceholderFormat(sq.Dollar).ToSql()
+		if err != nil {
+			dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+			return fmt.Errorf("building query: %w", err)
+		}
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+			dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+			return fmt.Errorf("adding actors to movie: %w", constraintError(err))
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	dbQueryDurationSeconds.WithLabelValues(operation, queryType).Observe(time.Since(start).Seconds())
+	dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+	return nil
+}
@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"cinematique/internal/domain"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMovieRepository_GetMovieCastPage(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	birthDate := time.Date(1964, 9, 2, 0, 0, 0, 0, time.UTC)
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT a.id, a.name, a.gender, a.birth_date, fa.character_name, fa.billing_order " +
+		"FROM actors a JOIN film_actor fa ON a.id = fa.actor_id WHERE fa.film_id = $1 " +
+		"ORDER BY fa.billing_order IS NULL, fa.billing_order, a.name, a.id LIMIT 2 OFFSET 2")).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "gender", "birth_date", "character_name", "billing_order"}).
+			AddRow(3, "Keanu Reeves", "male", birthDate, "Neo", 3).
+			AddRow(7, "Gloria Foster", "female", birthDate, "Oracle", nil))
+
+	cast, err := NewMovie(db).GetMovieCastPage(context.Background(), 1, 2, 2)
+	require.NoError(t, err)
+	assert.Equal(t, []domain.Actor{
+		{ID: 3, Name: "Keanu Reeves", Gender: "male", BirthDate: birthDate, CharacterName: "Neo", BillingOrder: 3},
+		{ID: 7, Name: "Gloria Foster", Gender: "female", BirthDate: birthDate, CharacterName: "Oracle"},
+	}, cast)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMovieRepository_CountMovieCast(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM film_actor WHERE film_id = $1")).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(42))
+
+	count, err := NewMovie(db).CountMovieCast(context.Background(), 1)
+	require.NoError(t, err)
+	assert.Equal(t, 42, count)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMovieRepository_ChangeMovieActors(t *testing.T) {
+	deleteQuery := regexp.QuoteMeta("DELETE FROM film_actor WHERE actor_id IN ($1,$2) AND film_id = $3")
+	insertQuery := regexp.QuoteMeta("INSERT INTO film_actor (film_id,actor_id,character_name,billing_order) " +
+		"VALUES ($1,$2,$3,$4),($5,$6,$7,$8) ON CONFLICT DO NOTHING")
+	add := []domain.CastMember{{ActorID: 3, CharacterName: "Neo", BillingOrder: 1}, {ActorID: 4}}
+
+	t.Run("removes and adds in one transaction", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectExec(deleteQuery).WithArgs(5, 6, 1).WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectExec(insertQuery).
+			WithArgs(1, 3, "Neo", 1, 1, 4, "", nil).
+			WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectCommit()
+
+		require.NoError(t, NewMovie(db).ChangeMovieActors(context.Background(), 1, add, []int{5, 6}))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("unknown actor rolls back the removal", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectExec(deleteQuery).WithArgs(5, 6, 1).WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectExec(insertQuery).
+			WillReturnError(&pq.Error{Code: pqForeignKeyViolation, Constraint: "film_actor_actor_id_fkey"})
+		mock.ExpectRollback()
+
+		err = NewMovie(db).ChangeMovieActors(context.Background(), 1, add, []int{5, 6})
+		assert.ErrorIs(t, err, domain.ErrReferenceNotFound)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"time"
+
+	"cinematique/internal/domain"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+// GetByExternalID возвращает фильм по ID в каталоге-источнике
+func (m *movie) GetByExternalID(ctx context.Context, externalID string) (domain.Movie, error) {
+	start := time.Now()
+	operation := "get_movie_by_external_id"
+	queryType := "SELECT"
+	ctx, span := startSpan(ctx, operation, queryType)
+	defer span.End()
+
+	query, args, err := sq.Select(movieDetailColumns...).
+		From("films").
+		Where(sq.Eq{"external_id": externalID}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return domain.Movie{}, err
+	}
+	movie, err := scanMovieDetail(m.db.QueryRowContext(ctx, query, args...))
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.Movie{}, domain.ErrMovieNotFound
+		}
+		return domain.Movie{}, err
+	}
+	dbQueryDurationSeconds.WithLabelValues(operation, queryType).Observe(time.Since(start).Seconds())
+	dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+	return movie, nil
+}
+
+// UpsertByExternalID одним запросом создаёт фильм с внешним ID movie.ExternalID или
+// перезаписывает поля уже созданного. Slug задаётся только при создании, чтобы адрес
+// фильма не менялся от синхронизации. Возвращает ID фильма и true, если он создан.
+// Только для PostgreSQL
+func (m *movie) UpsertByExternalID(ctx context.Context, movie domain.Movie) (int, bool, error) {
+	start := time.Now()
+	operation := "upsert_movie_by_external_id"
+	queryType := "UPSERT"
+	ctx, span := startSpan(ctx, operation, queryType)
+	defer span.End()
+
+	movieSlug, err := freeSlug(ctx, m.db, "films", movieSlugBase(movie))
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return 0, false, err
+	}
+	// xmax = 0 только у строки, вставленной этим запросом: обновлённая строка — новая
+	// версия, которую пометила наша транзакция
+	query, args, err := sq.Insert("films").
+		Columns("title", "description", "release_year", "rating", "release_date", "original_language", "country", "runtime_minutes", "slug", "external_id").
+		Values(movie.Title, movie.Description, movie.ReleaseYear, movie.Rating, movie.ReleaseDate, movie.OriginalLanguage, movie.Country, movie.RuntimeMinutes, movieSlug, movie.ExternalID).
+		Suffix("ON CONFLICT (external_id) DO UPDATE SET title = EXCLUDED.title, description = EXCLUDED.description, " +
+			"release_year = EXCLUDED.release_year, rating = EXCLUDED.rating, release_date = EXCLUDED.release_date, " +
+			"original_language = EXCLUDED.original_language, country = EXCLUDED.country, runtime_minutes = EXCLUDED.runtime_minutes " +
+			"RETURNING id, (xmax = 0) AS created").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return 0, false, err
+	}
+	var id int
+	var created bool
+	if err := m.db.QueryRowContext(ctx, query, args...).Scan(&id, &created); err != nil {
+		log.Printf("Error upserting movie by external ID %s: %v", movie.ExternalID, err)
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return 0, false, err
+	}
+	dbQueryDurationSeconds.WithLabelValues(operation, queryType).Observe(time.Since(start).Seconds())
+	dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+	return id, created, nil
+}
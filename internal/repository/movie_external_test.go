@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"testing"
+	"time"
+
+	"cinematique/internal/domain"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMovieRepository_GetByExternalID(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewMovie(db)
+	query := regexp.QuoteMeta("SELECT id, title, description, release_year, rating, release_date, view_count, original_language, country, runtime_minutes, slug, updated_at, external_id " +
+		"FROM films WHERE external_id = $1")
+	updatedAt := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery(query).WithArgs("tt0133093").
+		WillReturnRows(sqlmock.NewRows(movieDetailColumns).
+			AddRow(3, "The Matrix", "", 1999, 8.7, nil, 0, "en", "US", 136, "the-matrix-1999", updatedAt, "tt0133093"))
+	movie, err := repo.GetByExternalID(context.Background(), "tt0133093")
+	require.NoError(t, err)
+	assert.Equal(t, domain.Movie{
+		ID: 3, Title: "The Matrix", ReleaseYear: 1999, Rating: 8.7, OriginalLanguage: "en", Country: "US",
+		RuntimeMinutes: 136, Slug: "the-matrix-1999", UpdatedAt: updatedAt, ExternalID: "tt0133093",
+	}, movie)
+
+	mock.ExpectQuery(query).WithArgs("tt0000000").WillReturnError(sql.ErrNoRows)
+	_, err = repo.GetByExternalID(context.Background(), "tt0000000")
+	assert.ErrorIs(t, err, domain.ErrMovieNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMovieRepository_UpsertByExternalID(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewMovie(db)
+	slugQuery := regexp.QuoteMeta("SELECT slug FROM films WHERE (slug = $1 OR slug LIKE $2)")
+	upsertQuery := regexp.QuoteMeta("INSERT INTO films (title,description,release_year,rating,release_date,original_language,country,runtime_minutes,slug,external_id) " +
+		"VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10) ON CONFLICT (external_id) DO UPDATE SET title = EXCLUDED.title, description = EXCLUDED.description, " +
+		"release_year = EXCLUDED.release_year, rating = EXCLUDED.rating, release_date = EXCLUDED.release_date, " +
+		"original_language = EXCLUDED.original_language, country = EXCLUDED.country, runtime_minutes = EXCLUDED.runtime_minutes " +
+		"RETURNING id, (xmax = 0) AS created")
+	movie := domain.Movie{Title: "The Matrix", ReleaseYear: 1999, Rating: 8.7, OriginalLanguage: "en", ExternalID: "tt0133093"}
+
+	t.Run("creates movie", func(t *testing.T) {
+		mock.ExpectQuery(slugQuery).WithArgs("the-matrix-1999", "the-matrix-1999-%").
+			WillReturnRows(sqlmock.NewRows([]string{"slug"}))
+		mock.ExpectQuery(upsertQuery).
+			WithArgs("The Matrix", "", 1999, 8.7, nil, "en", "", 0, "the-matrix-1999", "tt0133093").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "created"}).AddRow(3, true))
+
+		id, created, err := repo.UpsertByExternalID(context.Background(), movie)
+		require.NoError(t, err)
+		assert.Equal(t, 3, id)
+		assert.True(t, created)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("updates existing movie", func(t *testing.T) {
+		// Слаг уже занят самим фильмом; при конфликте он не перезаписывается
+		mock.ExpectQuery(slugQuery).WithArgs("the-matrix-1999", "the-matrix-1999-%").
+			WillReturnRows(sqlmock.NewRows([]string{"slug"}).AddRow("the-matrix-1999"))
+		mock.ExpectQuery(upsertQuery).
+			WithArgs("The Matrix", "", 1999, 8.7, nil, "en", "", 0, "the-matrix-1999-2", "tt0133093").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "created"}).AddRow(3, false))
+
+		id, created, err := repo.UpsertByExternalID(context.Background(), movie)
+		require.NoError(t, err)
+		assert.Equal(t, 3, id)
+		assert.False(t, created)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("query error", func(t *testing.T) {
+		mock.ExpectQuery(slugQuery).WillReturnRows(sqlmock.NewRows([]string{"slug"}))
+		mock.ExpectQuery(upsertQuery).WillReturnError(sql.ErrConnDone)
+
+		_, _, err := repo.UpsertByExternalID(context.Background(), movie)
+		assert.ErrorIs(t, err, sql.ErrConnDone)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"cinematique/internal/domain"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+// releaseYearsCond отбирает фильмы, вышедшие с fromYear по toYear включительно
+func releaseYearsCond(fromYear, toYear int) sq.And {
+	return sq.And{sq.GtOrEq{"release_year": fromYear}, sq.LtOrEq{"release_year": toYear}}
+}
+
+// GetMoviesByYears возвращает страницу фильмов, вышедших с fromYear по toYear включительно:
+// по годам, внутри года — от высокого рейтинга к низкому. Порядок совпадает с индексом idx_films_release_year
+func (m *movie) GetMoviesByYears(ctx context.Context, fromYear, toYear, limit, offset int) ([]domain.Movie, error) {
+	start := time.Now()
+	operation := "get_movies_by_years"
+	queryType := "SELECT"
+	ctx, span := startSpan(ctx, operation, queryType)
+	defer span.End()
+
+	query, args, err := sq.Select(movieColumns...).
+		From("films").
+		Where(releaseYearsCond(fromYear, toYear)).
+		OrderBy("release_year ASC", "rating DESC", "id ASC").
+		Limit(uint64(limit)).
+		Offset(uint64(offset)).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return nil, err
+	}
+	rows, err := m.replica.pick(m.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return nil, err
+	}
+	defer rows.Close()
+	movies := make([]domain.Movie, 0)
+	for rows.Next() {
+		movie, err := scanMovie(rows)
+		if err != nil {
+			dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+			return nil, err
+		}
+		movies = append(movies, movie)
+	}
+	if err := rows.Err(); err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return nil, err
+	}
+	dbQueryDurationSeconds.WithLabelValues(operation, queryType).Observe(time.Since(start).Seconds())
+	dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+	return movies, nil
+}
+
+// CountMoviesByYears возвращает число фильмов, вышедших с fromYear по toYear включительно
+func (m *movie) CountMoviesByYears(ctx context.Context, fromYear, toYear int) (int, error) {
+	start := time.Now()
+	operation := "count_movies_by_years"
+	queryType := "SELECT"
+	ctx, span := startSpan(ctx, operation, queryType)
+	defer span.End()
+
+	query, args, err := sq.Select("COUNT(*)").
+		From("films").
+		Where(releaseYearsCond(fromYear, toYear)).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return 0, err
+	}
+	var count int
+	if err := m.replica.pick(m.db).QueryRowContext(ctx, query, args...).Scan(&count); err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return 0, err
+	}
+	dbQueryDurationSeconds.WithLabelValues(operation, queryType).Observe(time.Since(start).Seconds())
+	dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+	return count, nil
+}
@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"testing"
+
+	"cinematique/internal/domain"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMovieRepository_GetMoviesByYears(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewMovie(db)
+	query := regexp.QuoteMeta("SELECT id, title, description, release_year, rating, release_date, view_count, original_language, country, runtime_minutes, slug FROM films " +
+		"WHERE (release_year >= $1 AND release_year <= $2) ORDER BY release_year ASC, rating DESC, id ASC LIMIT 2 OFFSET 4")
+
+	rows := sqlmock.NewRows([]string{"id", "title", "description", "release_year", "rating", "release_date", "view_count", "original_language", "country", "runtime_minutes", "slug"}).
+		AddRow(4, "Alien", "", 1979, 8.5, nil, 0, "", "", 0, "").
+		AddRow(9, "Apocalypse Now", "", 1979, 8.4, nil, 0, "", "", 0, "")
+	mock.ExpectQuery(query).WithArgs(1970, 1979).WillReturnRows(rows)
+
+	movies, err := repo.GetMoviesByYears(context.Background(), 1970, 1979, 2, 4)
+	require.NoError(t, err)
+	assert.Equal(t, []domain.Movie{
+		{ID: 4, Title: "Alien", ReleaseYear: 1979, Rating: 8.5},
+		{ID: 9, Title: "Apocalypse Now", ReleaseYear: 1979, Rating: 8.4},
+	}, movies)
+
+	mock.ExpectQuery(query).WithArgs(1970, 1979).WillReturnError(sql.ErrConnDone)
+	_, err = repo.GetMoviesByYears(context.Background(), 1970, 1979, 2, 4)
+	assert.ErrorIs(t, err, sql.ErrConnDone)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMovieRepository_CountMoviesByYears(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewMovie(db)
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM films WHERE (release_year >= $1 AND release_year <= $2)")).
+		WithArgs(1999, 1999).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(12))
+
+	count, err := repo.CountMoviesByYears(context.Background(), 1999, 1999)
+	require.NoError(t, err)
+	assert.Equal(t, 12, count)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,137 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cinematique/internal/domain"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+// SetMovieRating сохраняет рейтинг фильма из источника, заменяя прежний. UPSERT есть
+// не во всех поддерживаемых СУБД, поэтому строка сначала обновляется, а если её нет — вставляется
+func (m *movie) SetMovieRating(ctx context.Context, rating domain.MovieRating) error {
+	start := time.Now()
+	operation := "set_movie_rating"
+	queryType := "UPSERT"
+	ctx, span := startSpan(ctx, operation, queryType)
+	defer span.End()
+
+	query, args, err := sq.Update("movie_ratings").
+		Set("rating", rating.Rating).
+		Set("updated_at", sq.Expr("CURRENT_TIMESTAMP")).
+		Where(sq.Eq{"film_id": rating.MovieID, "source": rating.Source}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return fmt.Errorf("building query: %w", err)
+	}
+	result, err := m.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return fmt.Errorf("getting rows affected: %w", err)
+	}
+	if affected == 0 {
+		query, args, err = sq.Insert("movie_ratings").
+			Columns("film_id", "source", "rating").
+			Values(rating.MovieID, rating.Source, rating.Rating).
+			PlaceholderFormat(sq.Dollar).
+			ToSql()
+		if err != nil {
+			dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+			return fmt.Errorf("building query: %w", err)
+		}
+		if _, err := m.db.ExecContext(ctx, query, args...); err != nil {
+			dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+			return err
+		}
+	}
+	dbQueryDurationSeconds.WithLabelValues(operation, queryType).Observe(time.Since(start).Seconds())
+	dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+	return nil
+}
+
+// GetMovieRatings возвращает рейтинги фильма по источникам в алфавитном порядке источников
+func (m *movie) GetMovieRatings(ctx context.Context, movieID int) ([]domain.MovieRating, error) {
+	start := time.Now()
+	operation := "get_movie_ratings"
+	queryType := "SELECT"
+	ctx, span := startSpan(ctx, operation, queryType)
+	defer span.End()
+
+	query, args, err := sq.Select("film_id", "source", "rating", "updated_at").
+		From("movie_ratings").
+		Where(sq.Eq{"film_id": movieID}).
+		OrderBy("source ASC").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return nil, err
+	}
+	rows, err := m.replica.pick(m.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return nil, err
+	}
+	defer rows.Close()
+	ratings := make([]domain.MovieRating, 0)
+	for rows.Next() {
+		var rating domain.MovieRating
+		if err := rows.Scan(&rating.MovieID, &rating.Source, &rating.Rating, &rating.UpdatedAt); err != nil {
+			dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+			return nil, err
+		}
+		ratings = append(ratings, rating)
+	}
+	if err := rows.Err(); err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return nil, err
+	}
+	dbQueryDurationSeconds.WithLabelValues(operation, queryType).Observe(time.Since(start).Seconds())
+	dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+	return ratings, nil
+}
+
+// DeleteMovieRating удаляет рейтинг фильма из источника; ErrRatingNotFound, если его нет
+func (m *movie) DeleteMovieRating(ctx context.Context, movieID int, source string) error {
+	start := time.Now()
+	operation := "delete_movie_rating"
+	queryType := "DELETE"
+	ctx, span := startSpan(ctx, operation, queryType)
+	defer span.End()
+
+	query, args, err := sq.Delete("movie_ratings").
+		Where(sq.Eq{"film_id": movieID, "source": source}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return fmt.Errorf("building query: %w", err)
+	}
+	result, err := m.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return fmt.Errorf("getting rows affected: %w", err)
+	}
+	if affected == 0 {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return domain.ErrRatingNotFound
+	}
+	dbQueryDurationSeconds.WithLabelValues(operation, queryType).Observe(time.Since(start).Seconds())
+	dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+	return nil
+}
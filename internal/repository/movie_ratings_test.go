@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"cinematique/internal/domain"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMovieRepository_SetMovieRating(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewMovie(db)
+	update := regexp.QuoteMeta("UPDATE movie_ratings SET rating = $1, updated_at = CURRENT_TIMESTAMP WHERE film_id = $2 AND source = $3")
+	insert := regexp.QuoteMeta("INSERT INTO movie_ratings (film_id,source,rating) VALUES ($1,$2,$3)")
+
+	// Рейтинг источника уже есть — он заменяется без вставки
+	mock.ExpectExec(update).WithArgs(8.7, 1, domain.RatingSourceIMDb).WillReturnResult(sqlmock.NewResult(0, 1))
+	require.NoError(t, repo.SetMovieRating(context.Background(), domain.MovieRating{MovieID: 1, Source: domain.RatingSourceIMDb, Rating: 8.7}))
+
+	// Первый рейтинг источника вставляется
+	mock.ExpectExec(update).WithArgs(88.0, 1, domain.RatingSourceRottenTomatoes).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(insert).WithArgs(1, domain.RatingSourceRottenTomatoes, 88.0).WillReturnResult(sqlmock.NewResult(0, 1))
+	require.NoError(t, repo.SetMovieRating(context.Background(), domain.MovieRating{MovieID: 1, Source: domain.RatingSourceRottenTomatoes, Rating: 88}))
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMovieRepository_GetMovieRatings(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewMovie(db)
+	updatedAt := time.Date(2024, time.May, 10, 12, 0, 0, 0, time.UTC)
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT film_id, source, rating, updated_at FROM movie_ratings WHERE film_id = $1 ORDER BY source ASC")).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"film_id", "source", "rating", "updated_at"}).
+			AddRow(1, "imdb", 8.7, updatedAt).
+			AddRow(1, "internal", 9.0, updatedAt))
+
+	ratings, err := repo.GetMovieRatings(context.Background(), 1)
+	require.NoError(t, err)
+	assert.Equal(t, []domain.MovieRating{
+		{MovieID: 1, Source: "imdb", Rating: 8.7, UpdatedAt: updatedAt},
+		{MovieID: 1, Source: "internal", Rating: 9.0, UpdatedAt: updatedAt},
+	}, ratings)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMovieRepository_DeleteMovieRating(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewMovie(db)
+	query := regexp.QuoteMeta("DELETE FROM movie_ratings WHERE film_id = $1 AND source = $2")
+
+	mock.ExpectExec(query).WithArgs(1, "imdb").WillReturnResult(sqlmock.NewResult(0, 1))
+	require.NoError(t, repo.DeleteMovieRating(context.Background(), 1, "imdb"))
+
+	mock.ExpectExec(query).WithArgs(1, "imdb").WillReturnResult(sqlmock.NewResult(0, 0))
+	assert.ErrorIs(t, repo.DeleteMovieRating(context.Background(), 1, "imdb"), domain.ErrRatingNotFound)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
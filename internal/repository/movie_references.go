@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"cinematique/internal/domain"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+// movieReferenceSources — таблицы, которые ссылаются на фильм по film_id и удаляются вместе
+// с ним (ON DELETE CASCADE). При политике restrict любая такая запись блокирует удаление.
+// Сюда не входят состав (film_actor — часть самого фильма), строка internal в movie_ratings
+// (копия films.rating), переадресации movie_merges на удаляемый фильм (без него им некуда
+// вести) и история — ревизии и изменения рейтинга хранятся и после удаления.
+// Новые виды ссылок (отзывы, списки просмотра) добавляются в этот список
+var movieReferenceSources = []struct {
+	kind  string
+	table string
+	where sq.Sqlizer // дополнительное условие отбора; nil — все строки фильма
+}{
+	{kind: "collections", table: "collection_movies"},
+	{kind: "tags", table: "movie_tags"},
+	{kind: "availability", table: "movie_availability"},
+	{kind: "ratings", table: "movie_ratings", where: sq.NotEq{"source": domain.RatingSourceInternal}},
+}
+
+// countMovieReferences возвращает число ссылок на фильм по видам через db — соединение или
+// транзакцию; виды без ссылок не включаются
+func countMovieReferences(ctx context.Context, db queryExecer, movieID int) ([]domain.MovieReference, error) {
+	references := make([]domain.MovieReference, 0, len(movieReferenceSources))
+	for _, source := range movieReferenceSources {
+		builder := sq.Select("COUNT(*)").
+			From(source.table).
+			Where(sq.Eq{"film_id": movieID})
+		if source.where != nil {
+			builder = builder.Where(source.where)
+		}
+		query, args, err := builder.PlaceholderFormat(sq.Dollar).ToSql()
+		if err != nil {
+			return nil, err
+		}
+		var count int
+		if err := db.QueryRowContext(ctx, query, args...).Scan(&count); err != nil {
+			return nil, err
+		}
+		if count > 0 {
+			references = append(references, domain.MovieReference{Kind: source.kind, Count: count})
+		}
+	}
+	return references, nil
+}
+
+// lockMovieTx блокирует строку фильма до конца транзакции tx. Пока блокировка держится,
+// вставка новой ссылки на фильм ждёт её снятия: проверка внешнего ключа берёт
+// FOR KEY SHARE, несовместимую с FOR UPDATE
+func lockMovieTx(ctx context.Context, tx *sql.Tx, id int) error {
+	query, args, err := sq.Select("id").
+		From("films").
+		Where(sq.Eq{"id": id}).
+		Suffix("FOR UPDATE").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build lock film query: %w", err)
+	}
+	var lockedID int
+	if err := tx.QueryRowContext(ctx, query, args...).Scan(&lockedID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.ErrMovieNotFound
+		}
+		return fmt.Errorf("failed to lock film: %w", err)
+	}
+	return nil
+}
+
+// deleteLockedMovieTx удаляет заблокированный lockMovieTx фильм вместе с составом. При политике
+// restrict ссылки считаются в той же транзакции, и найденные возвращаются как
+// *domain.MovieReferencedError; при cascade ссылки удаляет внешний ключ
+func deleteLockedMovieTx(ctx context.Context, tx *sql.Tx, id int, policy domain.MovieDeletePolicy) error {
+	if policy == domain.MovieDeleteRestrict {
+		references, err := countMovieReferences(ctx, tx, id)
+		if err != nil {
+			return fmt.Errorf("failed to count movie references: %w", err)
+		}
+		if len(references) > 0 {
+			return &domain.MovieReferencedError{References: references}
+		}
+	}
+
+	query, args, err := sq.Delete("film_actor").
+		Where(sq.Eq{"film_id": id}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build delete film_actor query: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("failed to delete film_actor relations: %w", err)
+	}
+
+	query, args, err = sq.Delete("films").
+		Where(sq.Eq{"id": id}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build delete film query: %w", err)
+	}
+	if err := execAffectingMovie(ctx, tx, query, args); err != nil {
+		return fmt.Errorf("failed to delete film: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"testing"
+
+	"cinematique/internal/domain"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	lockMovieQuery     = regexp.QuoteMeta("SELECT id FROM films WHERE id = $1 FOR UPDATE")
+	countCollections   = regexp.QuoteMeta("SELECT COUNT(*) FROM collection_movies WHERE film_id = $1")
+	countTags          = regexp.QuoteMeta("SELECT COUNT(*) FROM movie_tags WHERE film_id = $1")
+	countAvailability  = regexp.QuoteMeta("SELECT COUNT(*) FROM movie_availability WHERE film_id = $1")
+	countSourceRatings = regexp.QuoteMeta("SELECT COUNT(*) FROM movie_ratings WHERE film_id = $1 AND source <> $2")
+)
+
+// expectMovieReferences ожидает подсчёт ссылок на фильм id с заданным числом по видам
+func expectMovieReferences(mock sqlmock.Sqlmock, id, collections, tags, availability, ratings int) {
+	count := func(n int) *sqlmock.Rows { return sqlmock.NewRows([]string{"count"}).AddRow(n) }
+	mock.ExpectQuery(countCollections).WithArgs(id).WillReturnRows(count(collections))
+	mock.ExpectQuery(countTags).WithArgs(id).WillReturnRows(count(tags))
+	mock.ExpectQuery(countAvailability).WithArgs(id).WillReturnRows(count(availability))
+	mock.ExpectQuery(countSourceRatings).WithArgs(id, domain.RatingSourceInternal).WillReturnRows(count(ratings))
+}
+
+func TestMovieRepository_Delete_Restrict(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewMovie(db)
+	locked := func(id int) *sqlmock.Rows { return sqlmock.NewRows([]string{"id"}).AddRow(id) }
+
+	t.Run("references block deletion", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(lockMovieQuery).WithArgs(1).WillReturnRows(locked(1))
+		expectMovieReferences(mock, 1, 2, 3, 0, 1)
+		mock.ExpectRollback()
+
+		err := repo.Delete(context.Background(), 1, domain.MovieDeleteRestrict)
+		var refErr *domain.MovieReferencedError
+		require.ErrorAs(t, err, &refErr)
+		assert.Equal(t, []domain.MovieReference{
+			{Kind: "collections", Count: 2},
+			{Kind: "tags", Count: 3},
+			{Kind: "ratings", Count: 1},
+		}, refErr.References)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("unreferenced movie is deleted", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(lockMovieQuery).WithArgs(2).WillReturnRows(locked(2))
+		expectMovieReferences(mock, 2, 0, 0, 0, 0)
+		mock.ExpectExec(regexp.QuoteMeta("DELETE FROM film_actor WHERE film_id = $1")).WithArgs(2).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(regexp.QuoteMeta("DELETE FROM films WHERE id = $1")).WithArgs(2).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		require.NoError(t, repo.Delete(context.Background(), 2, domain.MovieDeleteRestrict))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("count failure aborts deletion", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(lockMovieQuery).WithArgs(3).WillReturnRows(locked(3))
+		mock.ExpectQuery(countCollections).WithArgs(3).WillReturnError(sql.ErrConnDone)
+		mock.ExpectRollback()
+
+		err := repo.Delete(context.Background(), 3, domain.MovieDeleteRestrict)
+		assert.ErrorIs(t, err, sql.ErrConnDone)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"cinematique/internal/domain"
+	"context"
+	"encoding/json"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+// AddMovieRevision сохраняет запись истории изменений фильма.
+func (m *movie) AddMovieRevision(ctx context.Context, revision domain.MovieRevision) error {
+	start := time.Now()
+	operation := "add_movie_revision"
+	queryType := "INSERT"
+	ctx, span := startSpan(ctx, operation, queryType)
+	defer span.End()
+
+	changes, err := json.Marshal(revision.Changes)
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return err
+	}
+	query, args, err := sq.Insert("movie_revisions").
+		Columns("film_id", "changes", "deleted").
+		Values(revision.MovieID, changes, revision.Deleted).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return err
+	}
+	if _, err := m.db.ExecContext(ctx, query, args...); err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return err
+	}
+	dbQueryDurationSeconds.WithLabelValues(operation, queryType).Observe(time.Since(start).Seconds())
+	dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+	return nil
+}
+
+// GetMovieRevisions возвращает ревизии фильма, сделанные не позже until, в хронологическом порядке.
+func (m *movie) GetMovieRevisions(ctx context.Context, movieID int, until time.Time) ([]domain.MovieRevision, error) {
+	start := time.Now()
+	operation := "get_movie_revisions"
+	queryType := "SELECT"
+	ctx, span := startSpan(ctx, operation, queryType)
+	defer span.End()
+
+	query, args, err := sq.Select("id", "film_id", "changed_at", "changes", "deleted").
+		From("movie_revisions").
+		Where(sq.Eq{"film_id": movieID}).
+		Where(sq.LtOrEq{"changed_at": until}).
+		OrderBy("changed_at ASC", "id ASC").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return nil, err
+	}
+	rows, err := m.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return nil, err
+	}
+	defer rows.Close()
+	revisions := make([]domain.MovieRevision, 0)
+	for rows.Next() {
+		var revision domain.MovieRevision
+		var changes []byte
+		if err := rows.Scan(&revision.ID, &revision.MovieID, &revision.ChangedAt, &changes, &revision.Deleted); err != nil {
+			dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+			return nil, err
+		}
+		if err := json.Unmarshal(changes, &revision.Changes); err != nil {
+			dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+			return nil, err
+		}
+		revisions = append(revisions, revision)
+	}
+	if err := rows.Err(); err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return nil, err
+	}
+	dbQueryDurationSeconds.WithLabelValues(operation, queryType).Observe(time.Since(start).Seconds())
+	dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+	return revisions, nil
+}
@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"testing"
+	"time"
+
+	"cinematique/internal/domain"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMovieRepository_AddMovieRevision(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewMovie(db)
+	query := regexp.QuoteMeta("INSERT INTO movie_revisions (film_id,changes,deleted) VALUES ($1,$2,$3)")
+	title := "Inception"
+
+	mock.ExpectExec(query).
+		WithArgs(1, []byte(`{"title":"Inception"}`), false).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	err = repo.AddMovieRevision(context.Background(), domain.MovieRevision{MovieID: 1, Changes: domain.MovieUpdate{Title: &title}})
+	assert.NoError(t, err)
+
+	mock.ExpectExec(query).
+		WithArgs(2, []byte(`{}`), true).
+		WillReturnError(sql.ErrConnDone)
+	err = repo.AddMovieRevision(context.Background(), domain.MovieRevision{MovieID: 2, Deleted: true})
+	assert.Error(t, err)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMovieRepository_GetMovieRevisions(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewMovie(db)
+	query := regexp.QuoteMeta("SELECT id, film_id, changed_at, changes, deleted FROM movie_revisions WHERE film_id = $1 AND changed_at <= $2 ORDER BY changed_at ASC, id ASC")
+	until := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	created := time.Date(2023, time.May, 1, 10, 0, 0, 0, time.UTC)
+	edited := time.Date(2023, time.June, 1, 10, 0, 0, 0, time.UTC)
+	title, rating := "Inception", 8.8
+
+	rows := sqlmock.NewRows([]string{"id", "film_id", "changed_at", "changes", "deleted"}).
+		AddRow(1, 1, created, []byte(`{"title":"Inception","rating":8.5}`), false).
+		AddRow(2, 1, edited, []byte(`{"rating":8.8}`), false)
+	mock.ExpectQuery(query).WithArgs(1, until).WillReturnRows(rows)
+
+	revisions, err := repo.GetMovieRevisions(context.Background(), 1, until)
+	require.NoError(t, err)
+	require.Len(t, revisions, 2)
+	assert.Equal(t, &title, revisions[0].Changes.Title)
+	assert.Equal(t, created, revisions[0].ChangedAt)
+	assert.Nil(t, revisions[1].Changes.Title)
+	assert.Equal(t, &rating, revisions[1].Changes.Rating)
+
+	mock.ExpectQuery(query).WithArgs(1, until).WillReturnError(sql.ErrConnDone)
+	_, err = repo.GetMovieRevisions(context.Background(), 1, until)
+	assert.Error(t, err)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,284 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cinematique/internal/domain"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+// AddMovieTag добавляет фильму тег, создавая тег при первом использовании. Повторное
+// добавление того же тега ничего не меняет. Тег должен быть уже нормализован
+func (m *movie) AddMovieTag(ctx context.Context, movieID int, tag string) error {
+	start := time.Now()
+	operation := "add_movie_tag"
+	queryType := "INSERT"
+	ctx, span := startSpan(ctx, operation, queryType)
+	defer span.End()
+
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Тег мог создать параллельный запрос, поэтому вставка пропускает конфликт, а ID читается отдельно
+	query, args, err := sq.Insert("tags").Columns("name").Values(tag).
+		Suffix("ON CONFLICT DO NOTHING").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return fmt.Errorf("building query: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return fmt.Errorf("failed to create tag: %w", err)
+	}
+
+	query, args, err = sq.Select("id").
+		From("tags").
+		Where(sq.Eq{"name": tag}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return fmt.Errorf("building query: %w", err)
+	}
+	var tagID int
+	if err := tx.QueryRowContext(ctx, query, args...).Scan(&tagID); err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return fmt.Errorf("failed to get tag: %w", err)
+	}
+
+	query, args, err = sq.Insert("movie_tags").Columns("film_id", "tag_id").Values(movieID, tagID).
+		Suffix("ON CONFLICT DO NOTHING").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return fmt.Errorf("building query: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return fmt.Errorf("failed to tag movie: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	dbQueryDurationSeconds.WithLabelValues(operation, queryType).Observe(time.Since(start).Seconds())
+	dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+	return nil
+}
+
+// RemoveMovieTag снимает тег с фильма; ErrTagNotFound, если у фильма его нет.
+// Сам тег остаётся в таблице tags, но без фильмов не попадает в подсказки
+func (m *movie) RemoveMovieTag(ctx context.Context, movieID int, tag string) error {
+	start := time.Now()
+	operation := "remove_movie_tag"
+	queryType := "DELETE"
+	ctx, span := startSpan(ctx, operation, queryType)
+	defer span.End()
+
+	tagID := sq.Select("id").From("tags").Where(sq.Eq{"name": tag})
+	tagIDQuery, tagIDArgs, err := tagID.ToSql()
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return fmt.Errorf("building query: %w", err)
+	}
+	query, args, err := sq.Delete("movie_tags").
+		Where(sq.Eq{"film_id": movieID}).
+		Where("tag_id = ("+tagIDQuery+")", tagIDArgs...).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return fmt.Errorf("building query: %w", err)
+	}
+	result, err := m.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return fmt.Errorf("getting rows affected: %w", err)
+	}
+	if affected == 0 {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return domain.ErrTagNotFound
+	}
+	dbQueryDurationSeconds.WithLabelValues(operation, queryType).Observe(time.Since(start).Seconds())
+	dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+	return nil
+}
+
+// GetMovieTags возвращает теги фильма в алфавитном порядке
+func (m *movie) GetMovieTags(ctx context.Context, movieID int) ([]string, error) {
+	start := time.Now()
+	operation := "get_movie_tags"
+	queryType := "SELECT"
+	ctx, span := startSpan(ctx, operation, queryType)
+	defer span.End()
+
+	query, args, err := sq.Select("t.name").
+		From("tags t").
+		Join("movie_tags mt ON mt.tag_id = t.id").
+		Where(sq.Eq{"mt.film_id": movieID}).
+		OrderBy("t.name ASC").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return nil, err
+	}
+	rows, err := m.replica.pick(m.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return nil, err
+	}
+	defer rows.Close()
+	tags := make([]string, 0)
+	for rows.Next() {
+		var tag string
+		if err := rows.Scan(&tag); err != nil {
+			dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+			return nil, err
+		}
+		tags = append(tags, tag)
+	}
+	if err := rows.Err(); err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return nil, err
+	}
+	dbQueryDurationSeconds.WithLabelValues(operation, queryType).Observe(time.Since(start).Seconds())
+	dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+	return tags, nil
+}
+
+// GetMoviesByTag возвращает страницу фильмов с тегом: от высокого рейтинга к низкому
+func (m *movie) GetMoviesByTag(ctx context.Context, tag string, limit, offset int) ([]domain.Movie, error) {
+	start := time.Now()
+	operation := "get_movies_by_tag"
+	queryType := "SELECT"
+	ctx, span := startSpan(ctx, operation, queryType)
+	defer span.End()
+
+	query, args, err := sq.Select(prefixedMovieColumns("f")...).
+		From("films f").
+		Join("movie_tags mt ON mt.film_id = f.id").
+		Join("tags t ON t.id = mt.tag_id").
+		Where(sq.Eq{"t.name": tag}).
+		OrderBy("f.rating DESC", "f.id ASC").
+		Limit(uint64(limit)).
+		Offset(uint64(offset)).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return nil, err
+	}
+	rows, err := m.replica.pick(m.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return nil, err
+	}
+	defer rows.Close()
+	movies := make([]domain.Movie, 0)
+	for rows.Next() {
+		movie, err := scanMovie(rows)
+		if err != nil {
+			dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+			return nil, err
+		}
+		movies = append(movies, movie)
+	}
+	if err := rows.Err(); err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return nil, err
+	}
+	dbQueryDurationSeconds.WithLabelValues(operation, queryType).Observe(time.Since(start).Seconds())
+	dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+	return movies, nil
+}
+
+// CountMoviesByTag возвращает число фильмов с тегом
+func (m *movie) CountMoviesByTag(ctx context.Context, tag string) (int, error) {
+	start := time.Now()
+	operation := "count_movies_by_tag"
+	queryType := "SELECT"
+	ctx, span := startSpan(ctx, operation, queryType)
+	defer span.End()
+
+	query, args, err := sq.Select("COUNT(*)").
+		From("movie_tags mt").
+		Join("tags t ON t.id = mt.tag_id").
+		Where(sq.Eq{"t.name": tag}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return 0, err
+	}
+	var count int
+	if err := m.replica.pick(m.db).QueryRowContext(ctx, query, args...).Scan(&count); err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return 0, err
+	}
+	dbQueryDurationSeconds.WithLabelValues(operation, queryType).Observe(time.Since(start).Seconds())
+	dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+	return count, nil
+}
+
+// SuggestTags возвращает теги, начинающиеся с prefix, у которых есть хотя бы один фильм:
+// сначала самые используемые. Условие по префиксу использует индекс idx_tags_name_prefix
+func (m *movie) SuggestTags(ctx context.Context, prefix string, limit int) ([]domain.Tag, error) {
+	start := time.Now()
+	operation := "suggest_tags"
+	queryType := "SELECT"
+	ctx, span := startSpan(ctx, operation, queryType)
+	defer span.End()
+
+	query, args, err := sq.Select("t.name", "COUNT(*) AS movie_count").
+		From("tags t").
+		Join("movie_tags mt ON mt.tag_id = t.id").
+		Where("t.name LIKE ?", likeEscaper.Replace(prefix)+"%").
+		GroupBy("t.name").
+		OrderBy("movie_count DESC", "t.name ASC").
+		Suffix("LIMIT ?", limit).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return nil, err
+	}
+	rows, err := m.replica.pick(m.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return nil, err
+	}
+	defer rows.Close()
+	tags := make([]domain.Tag, 0)
+	for rows.Next() {
+		var tag domain.Tag
+		if err := rows.Scan(&tag.Name, &tag.MovieCount); err != nil {
+			dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+			return nil, err
+		}
+		tags = append(tags, tag)
+	}
+	if err := rows.Err(); err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return nil, err
+	}
+	dbQueryDurationSeconds.WithLabelValues(operation, queryType).Observe(time.Since(start).Seconds())
+	dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+	return tags, nil
+}
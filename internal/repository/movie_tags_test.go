@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"cinematique/internal/domain"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMovieRepository_AddMovieTag(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO tags (name) VALUES ($1) ON CONFLICT DO NOTHING")).
+		WithArgs("cult-classic").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id FROM tags WHERE name = $1")).
+		WithArgs("cult-classic").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO movie_tags (film_id,tag_id) VALUES ($1,$2) ON CONFLICT DO NOTHING")).
+		WithArgs(1, 3).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	require.NoError(t, NewMovie(db).AddMovieTag(context.Background(), 1, "cult-classic"))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMovieRepository_RemoveMovieTag(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewMovie(db)
+	query := regexp.QuoteMeta("DELETE FROM movie_tags WHERE film_id = $1 AND tag_id = (SELECT id FROM tags WHERE name = $2)")
+
+	mock.ExpectExec(query).WithArgs(1, "cult-classic").WillReturnResult(sqlmock.NewResult(0, 1))
+	require.NoError(t, repo.RemoveMovieTag(context.Background(), 1, "cult-classic"))
+
+	mock.ExpectExec(query).WithArgs(1, "cult-classic").WillReturnResult(sqlmock.NewResult(0, 0))
+	assert.ErrorIs(t, repo.RemoveMovieTag(context.Background(), 1, "cult-classic"), domain.ErrTagNotFound)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMovieRepository_GetMovieTags(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT t.name FROM tags t JOIN movie_tags mt ON mt.tag_id = t.id WHERE mt.film_id = $1 ORDER BY t.name ASC")).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow("cult-classic").AddRow("oscar-winner"))
+
+	tags, err := NewMovie(db).GetMovieTags(context.Background(), 1)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"cult-classic", "oscar-winner"}, tags)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMovieRepository_GetMoviesByTag(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewMovie(db)
+	mock.ExpectQuery(regexp.QuoteMeta("FROM films f JOIN movie_tags mt ON mt.film_id = f.id JOIN tags t ON t.id = mt.tag_id WHERE t.name = $1 ORDER BY f.rating DESC, f.id ASC LIMIT 20 OFFSET 40")).
+		WithArgs("oscar-winner").
+		WillReturnRows(sqlmock.NewRows(movieColumns).
+			AddRow(7, "The Godfather", "", 1972, 9.2, nil, 0, "en", "US", 0, "the-godfather-1972"))
+
+	movies, err := repo.GetMoviesByTag(context.Background(), "oscar-winner", 20, 40)
+	require.NoError(t, err)
+	require.Len(t, movies, 1)
+	assert.Equal(t, "The Godfather", movies[0].Title)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM movie_tags mt JOIN tags t ON t.id = mt.tag_id WHERE t.name = $1")).
+		WithArgs("oscar-winner").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(41))
+
+	count, err := repo.CountMoviesByTag(context.Background(), "oscar-winner")
+	require.NoError(t, err)
+	assert.Equal(t, 41, count)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMovieRepository_SuggestTags(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	// Символы шаблона LIKE в префиксе ищутся буквально
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT t.name, COUNT(*) AS movie_count FROM tags t JOIN movie_tags mt ON mt.tag_id = t.id "+
+		"WHERE t.name LIKE $1 GROUP BY t.name ORDER BY movie_count DESC, t.name ASC LIMIT $2")).
+		WithArgs(`o\_%`, 10).
+		WillReturnRows(sqlmock.NewRows([]string{"name", "movie_count"}).AddRow("o_scar", 2))
+
+	tags, err := NewMovie(db).SuggestTags(context.Background(), "o_", 10)
+	require.NoError(t, err)
+	assert.Equal(t, []domain.Tag{{Name: "o_scar", MovieCount: 2}}, tags)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -2,7 +2,9 @@ package repository
 
 import (
 	"cinematique/internal/domain"
+	"context"
 	"database/sql"
+	"errors"
 	"testing"
 
 	"github.com/DATA-DOG/go-sqlmock"
@@ -29,14 +31,20 @@ func TestMovieRepository_Create(t *testing.T) {
 		{
 			name: "successful movie creation",
 			movie: domain.Movie{
-				Title:       "Inception",
-				Description: "A mind-bending movie",
-				ReleaseYear: 2010,
-				Rating:      8.8,
+				Title:            "Inception",
+				Description:      "A mind-bending movie",
+				ReleaseYear:      2010,
+				Rating:           8.8,
+				OriginalLanguage: "en",
+				Country:          "US",
+				RuntimeMinutes:   148,
 			},
 			setup: func() {
-				mock.ExpectQuery(`INSERT INTO films \(title,description,release_year,rating\) VALUES \(\$1,\$2,\$3,\$4\) RETURNING id`).
-					WithArgs("Inception", "A mind-bending movie", 2010, 8.8).
+				mock.ExpectQuery(`SELECT slug FROM films WHERE \(slug = \$1 OR slug LIKE \$2\)`).
+					WithArgs("inception-2010", "inception-2010-%").
+					WillReturnRows(sqlmock.NewRows([]string{"slug"}))
+				mock.ExpectQuery(`INSERT INTO films \(title,description,release_year,rating,release_date,original_language,country,runtime_minutes,slug\) VALUES \(\$1,\$2,\$3,\$4,\$5,\$6,\$7,\$8,\$9\) RETURNING id`).
+					WithArgs("Inception", "A mind-bending movie", 2010, 8.8, nil, "en", "US", 148, "inception-2010").
 					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
 			},
 			wantID: 1,
@@ -49,7 +57,7 @@ func TestMovieRepository_Create(t *testing.T) {
 				tt.setup()
 			}
 
-			gotID, err := repo.Create(tt.movie)
+			gotID, err := repo.Create(context.Background(), tt.movie)
 
 			if tt.wantErr {
 				assert.Error(t, err)
@@ -69,6 +77,7 @@ func TestMovieRepository_GetByID(t *testing.T) {
 	defer db.Close()
 
 	repo := NewMovie(db)
+	updatedAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
 
 	tests := []struct {
 		name    string
@@ -81,8 +90,8 @@ func TestMovieRepository_GetByID(t *testing.T) {
 			name: "movie found",
 			id:   1,
 			setup: func() {
-				rows := sqlmock.NewRows([]string{"id", "title", "description", "release_year", "rating"}).
-					AddRow(1, "Inception", "A mind-bending movie", 2010, 8.8)
+				rows := sqlmock.NewRows(movieDetailColumns).
+					AddRow(1, "Inception", "A mind-bending movie", 2010, 8.8, nil, 0, "", "", 0, "", updatedAt, nil)
 				mock.ExpectQuery(`SELECT.* FROM films WHERE id = \$1`).
 					WithArgs(1).
 					WillReturnRows(rows)
@@ -93,6 +102,7 @@ func TestMovieRepository_GetByID(t *testing.T) {
 				Description: "A mind-bending movie",
 				ReleaseYear: 2010,
 				Rating:      8.8,
+				UpdatedAt:   updatedAt,
 			},
 		},
 		{
@@ -113,7 +123,7 @@ func TestMovieRepository_GetByID(t *testing.T) {
 				tt.setup()
 			}
 
-			got, err := repo.GetByID(tt.id)
+			got, err := repo.GetByID(context.Background(), tt.id)
 
 			if tt.wantErr {
 				assert.Error(t, err)
@@ -150,8 +160,8 @@ func TestMovieRepository_Update(t *testing.T) {
 				Rating:      9.0,
 			},
 			setup: func() {
-				mock.ExpectExec(`UPDATE films SET title = \$1, description = \$2, release_year = \$3, rating = \$4 WHERE id = \$5`).
-					WithArgs("Inception Updated", "Updated description", 2011, 9.0, 1).
+				mock.ExpectExec(`UPDATE films SET title = \$1, description = \$2, release_year = \$3, rating = \$4, release_date = \$5, original_language = \$6, country = \$7, runtime_minutes = \$8 WHERE id = \$9`).
+					WithArgs("Inception Updated", "Updated description", 2011, 9.0, nil, "", "", 0, 1).
 					WillReturnResult(sqlmock.NewResult(0, 1))
 			},
 		},
@@ -162,7 +172,7 @@ func TestMovieRepository_Update(t *testing.T) {
 			},
 			setup: func() {
 				mock.ExpectExec(`UPDATE films SET .*`).
-					WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), 999).
+					WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), 999).
 					WillReturnResult(sqlmock.NewResult(0, 0))
 			},
 			wantErr: true,
@@ -175,7 +185,7 @@ func TestMovieRepository_Update(t *testing.T) {
 				tt.setup()
 			}
 
-			err := repo.Update(tt.movie)
+			err := repo.Update(context.Background(), tt.movie)
 
 			if tt.wantErr {
 				assert.Error(t, err)
@@ -206,6 +216,9 @@ func TestMovieRepository_Delete(t *testing.T) {
 			id:   1,
 			setup: func() {
 				mock.ExpectBegin()
+				mock.ExpectQuery(lockMovieQuery).
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
 				mock.ExpectExec(`DELETE FROM film_actor WHERE film_id = \$1`).
 					WithArgs(1).
 					WillReturnResult(sqlmock.NewResult(0, 1))
@@ -220,9 +233,9 @@ func TestMovieRepository_Delete(t *testing.T) {
 			id:   999,
 			setup: func() {
 				mock.ExpectBegin()
-				mock.ExpectExec(`DELETE FROM film_actor WHERE film_id = \$1`).
+				mock.ExpectQuery(lockMovieQuery).
 					WithArgs(999).
-					WillReturnResult(sqlmock.NewResult(0, 0))
+					WillReturnError(sql.ErrNoRows)
 				mock.ExpectRollback()
 			},
 			wantErr: true,
@@ -235,7 +248,7 @@ func TestMovieRepository_Delete(t *testing.T) {
 				tt.setup()
 			}
 
-			err := repo.Delete(tt.id)
+			err := repo.Delete(context.Background(), tt.id, domain.MovieDeleteCascade)
 
 			if tt.wantErr {
 				assert.Error(t, err)
@@ -264,10 +277,10 @@ func TestMovieRepository_GetAll(t *testing.T) {
 		{
 			name: "get all movies",
 			setup: func() {
-				rows := sqlmock.NewRows([]string{"id", "title", "description", "release_year", "rating"}).
-					AddRow(1, "Inception", "A mind-bending movie", 2010, 8.8).
-					AddRow(2, "The Revenant", "A survival story", 2015, 8.0)
-				mock.ExpectQuery(`SELECT id, title, description, release_year, rating FROM films`).WillReturnRows(rows)
+				rows := sqlmock.NewRows([]string{"id", "title", "description", "release_year", "rating", "release_date", "view_count", "original_language", "country", "runtime_minutes", "slug"}).
+					AddRow(1, "Inception", "A mind-bending movie", 2010, 8.8, nil, 0, "", "", 0, "").
+					AddRow(2, "The Revenant", "A survival story", 2015, 8.0, nil, 0, "", "", 0, "")
+				mock.ExpectQuery(`SELECT id, title, description, release_year, rating, release_date, view_count, original_language, country, runtime_minutes, slug FROM films`).WillReturnRows(rows)
 			},
 			want: []domain.Movie{
 				{ID: 1, Title: "Inception", Description: "A mind-bending movie", ReleaseYear: 2010, Rating: 8.8},
@@ -284,7 +297,7 @@ func TestMovieRepository_GetAll(t *testing.T) {
 		{
 			name: "no movies",
 			setup: func() {
-				rows := sqlmock.NewRows([]string{"id", "title", "description", "release_year", "rating"})
+				rows := sqlmock.NewRows([]string{"id", "title", "description", "release_year", "rating", "release_date", "view_count", "original_language", "country", "runtime_minutes", "slug"})
 				mock.ExpectQuery(`SELECT`).WillReturnRows(rows)
 			},
 			want: []domain.Movie{},
@@ -296,7 +309,7 @@ func TestMovieRepository_GetAll(t *testing.T) {
 			if tt.setup != nil {
 				tt.setup()
 			}
-			got, err := repo.GetAll()
+			got, err := repo.GetAll(context.Background())
 			if tt.wantErr {
 				assert.Error(t, err)
 			} else {
@@ -330,7 +343,8 @@ func TestMovieRepository_AddActor(t *testing.T) {
 			movieID: 1,
 			actorID: 2,
 			setup: func() {
-				mock.ExpectExec(`INSERT INTO film_actor`).WithArgs(1, 2).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(`INSERT INTO film_actor \(film_id,actor_id,character_name,billing_order\)`).
+					WithArgs(1, 2, "Neo", 1).WillReturnResult(sqlmock.NewResult(0, 1))
 			},
 		},
 		{
@@ -338,7 +352,7 @@ func TestMovieRepository_AddActor(t *testing.T) {
 			movieID: 1,
 			actorID: 2,
 			setup: func() {
-				mock.ExpectExec(`INSERT INTO film_actor`).WithArgs(1, 2).WillReturnError(sql.ErrConnDone)
+				mock.ExpectExec(`INSERT INTO film_actor`).WithArgs(1, 2, "Neo", 1).WillReturnError(sql.ErrConnDone)
 			},
 			wantErr: true,
 		},
@@ -348,7 +362,7 @@ func TestMovieRepository_AddActor(t *testing.T) {
 			if tt.setup != nil {
 				tt.setup()
 			}
-			err := repo.AddActor(tt.movieID, tt.actorID)
+			err := repo.AddActor(context.Background(), tt.movieID, domain.CastMember{ActorID: tt.actorID, CharacterName: "Neo", BillingOrder: 1})
 			if tt.wantErr {
 				assert.Error(t, err)
 			} else {
@@ -391,7 +405,7 @@ func TestMovieRepository_RemoveActor(t *testing.T) {
 			if tt.setup != nil {
 				tt.setup()
 			}
-			err := repo.RemoveActor(movieID, actorID)
+			err := repo.RemoveActor(context.Background(), movieID, actorID)
 			if tt.wantErr {
 				assert.Error(t, err)
 			} else {
@@ -421,18 +435,22 @@ func TestMovieRepository_GetActorsForMovieByID(t *testing.T) {
 			setup: func() {
 				// birth_date: используем корректный sql.NullTime
 				birth := time.Date(1974, 11, 11, 0, 0, 0, 0, time.UTC)
-				rows := sqlmock.NewRows([]string{"id", "name", "gender", "birth_date"}).
-					AddRow(1, "Leonardo DiCaprio", "male", sql.NullTime{Time: birth, Valid: true})
-				mock.ExpectQuery(regexp.QuoteMeta("SELECT a.id, a.name, a.gender, a.birth_date FROM actors a JOIN film_actor fa ON a.id = fa.actor_id WHERE fa.film_id = $1")).WithArgs(1).WillReturnRows(rows)
+				rows := sqlmock.NewRows([]string{"id", "name", "gender", "birth_date", "character_name", "billing_order"}).
+					AddRow(1, "Leonardo DiCaprio", "male", sql.NullTime{Time: birth, Valid: true}, "Cobb", 1).
+					AddRow(2, "Tom Hardy", "male", sql.NullTime{Time: birth, Valid: true}, "", nil)
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT a.id, a.name, a.gender, a.birth_date, fa.character_name, fa.billing_order FROM actors a JOIN film_actor fa ON a.id = fa.actor_id WHERE fa.film_id = $1 ORDER BY fa.billing_order IS NULL, fa.billing_order, a.name, a.id")).WithArgs(1).WillReturnRows(rows)
+			},
+			want: []domain.Actor{
+				{ID: 1, Name: "Leonardo DiCaprio", Gender: "male", BirthDate: time.Date(1974, 11, 11, 0, 0, 0, 0, time.UTC), CharacterName: "Cobb", BillingOrder: 1},
+				{ID: 2, Name: "Tom Hardy", Gender: "male", BirthDate: time.Date(1974, 11, 11, 0, 0, 0, 0, time.UTC)},
 			},
-			want: []domain.Actor{{ID: 1, Name: "Leonardo DiCaprio", Gender: "male", BirthDate: time.Date(1974, 11, 11, 0, 0, 0, 0, time.UTC)}},
 		},
 		{
 			name:    "no actors",
 			movieID: 2,
 			setup: func() {
-				rows := sqlmock.NewRows([]string{"id", "name", "gender", "birth_date"})
-				mock.ExpectQuery(regexp.QuoteMeta("SELECT a.id, a.name, a.gender, a.birth_date FROM actors a JOIN film_actor fa ON a.id = fa.actor_id WHERE fa.film_id = $1")).WithArgs(2).WillReturnRows(rows)
+				rows := sqlmock.NewRows([]string{"id", "name", "gender", "birth_date", "character_name", "billing_order"})
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT a.id, a.name, a.gender, a.birth_date, fa.character_name, fa.billing_order FROM actors a JOIN film_actor fa ON a.id = fa.actor_id WHERE fa.film_id = $1 ORDER BY fa.billing_order IS NULL, fa.billing_order, a.name, a.id")).WithArgs(2).WillReturnRows(rows)
 			},
 			want: []domain.Actor{},
 		},
@@ -440,7 +458,7 @@ func TestMovieRepository_GetActorsForMovieByID(t *testing.T) {
 			name:    "db error",
 			movieID: 3,
 			setup: func() {
-				mock.ExpectQuery(regexp.QuoteMeta("SELECT a.id, a.name, a.gender, a.birth_date FROM actors a JOIN film_actor fa ON a.id = fa.actor_id WHERE fa.film_id = $1")).WithArgs(3).WillReturnError(sql.ErrConnDone)
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT a.id, a.name, a.gender, a.birth_date, fa.character_name, fa.billing_order FROM actors a JOIN film_actor fa ON a.id = fa.actor_id WHERE fa.film_id = $1 ORDER BY fa.billing_order IS NULL, fa.billing_order, a.name, a.id")).WithArgs(3).WillReturnError(sql.ErrConnDone)
 			},
 			wantErr: true,
 		},
@@ -450,7 +468,7 @@ func TestMovieRepository_GetActorsForMovieByID(t *testing.T) {
 			if tt.setup != nil {
 				tt.setup()
 			}
-			got, err := repo.GetActorsForMovieByID(tt.movieID)
+			got, err := repo.GetActorsForMovieByID(context.Background(), tt.movieID)
 			if tt.wantErr {
 				assert.Error(t, err)
 			} else {
@@ -499,7 +517,7 @@ func TestMovieRepository_RemoveAllActors(t *testing.T) {
 			if tt.setup != nil {
 				tt.setup()
 			}
-			err := repo.RemoveAllActors(tt.movieID)
+			err := repo.RemoveAllActors(context.Background(), tt.movieID)
 			if tt.wantErr {
 				assert.Error(t, err)
 			} else {
@@ -526,8 +544,11 @@ func TestMovieRepository_CreateMovieWithActors(t *testing.T) {
 			name: "success",
 			setup: func() {
 				mock.ExpectBegin()
-				mock.ExpectQuery(regexp.QuoteMeta("INSERT INTO films (title,description,release_year,rating) VALUES ($1,$2,$3,$4) RETURNING id")).
-					WithArgs("Test Movie", "desc", 2020, 7.5).
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT slug FROM films WHERE (slug = $1 OR slug LIKE $2)")).
+					WithArgs("test-movie-2020", "test-movie-2020-%").
+					WillReturnRows(sqlmock.NewRows([]string{"slug"}).AddRow("test-movie-2020"))
+				mock.ExpectQuery(regexp.QuoteMeta("INSERT INTO films (title,description,release_year,rating,release_date,original_language,country,runtime_minutes,slug) VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9) RETURNING id")).
+					WithArgs("Test Movie", "desc", 2020, 7.5, nil, "", "", 0, "test-movie-2020-2").
 					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(10))
 				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO film_actor (film_id,actor_id) VALUES ($1,$2)")).
 					WithArgs(10, 1).
@@ -540,8 +561,11 @@ func TestMovieRepository_CreateMovieWithActors(t *testing.T) {
 			name: "db error",
 			setup: func() {
 				mock.ExpectBegin()
-				mock.ExpectQuery(regexp.QuoteMeta("INSERT INTO films (title,description,release_year,rating) VALUES ($1,$2,$3,$4) RETURNING id")).
-					WithArgs("Test Movie", "desc", 2020, 7.5).
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT slug FROM films WHERE (slug = $1 OR slug LIKE $2)")).
+					WithArgs("test-movie-2020", "test-movie-2020-%").
+					WillReturnRows(sqlmock.NewRows([]string{"slug"}).AddRow("test-movie-2020"))
+				mock.ExpectQuery(regexp.QuoteMeta("INSERT INTO films (title,description,release_year,rating,release_date,original_language,country,runtime_minutes,slug) VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9) RETURNING id")).
+					WithArgs("Test Movie", "desc", 2020, 7.5, nil, "", "", 0, "test-movie-2020-2").
 					WillReturnError(sql.ErrConnDone)
 				mock.ExpectRollback()
 			},
@@ -553,7 +577,7 @@ func TestMovieRepository_CreateMovieWithActors(t *testing.T) {
 			if tt.setup != nil {
 				tt.setup()
 			}
-			id, err := repo.CreateMovieWithActors(domain.Movie{
+			id, err := repo.CreateMovieWithActors(context.Background(), domain.Movie{
 				Title:       "Test Movie",
 				Description: "desc",
 				ReleaseYear: 2020,
@@ -570,6 +594,80 @@ func TestMovieRepository_CreateMovieWithActors(t *testing.T) {
 	}
 }
 
+func TestMovieRepository_ImportMovie(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewMovie(db)
+	keanuBirth := time.Date(1964, 9, 2, 0, 0, 0, 0, time.UTC)
+	cast := []domain.Actor{
+		{Name: "Keanu Reeves", Gender: "male", BirthDate: keanuBirth},
+		{Name: "Unknown Extra", Gender: "other"},
+	}
+
+	t.Run("reuses existing actors and creates missing ones", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT id FROM actors WHERE LOWER(name) = LOWER($1) AND birth_date = $2 ORDER BY id ASC LIMIT 1")).
+			WithArgs("Keanu Reeves", keanuBirth).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(4))
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT id FROM actors WHERE LOWER(name) = LOWER($1) ORDER BY id ASC LIMIT 1")).
+			WithArgs("Unknown Extra").
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT slug FROM actors WHERE (slug = $1 OR slug LIKE $2)")).
+			WithArgs("unknown-extra", "unknown-extra-%").
+			WillReturnRows(sqlmock.NewRows([]string{"slug"}))
+		mock.ExpectQuery(regexp.QuoteMeta("INSERT INTO actors (name,gender,birth_date,slug) VALUES ($1,$2,$3,$4) RETURNING id")).
+			WithArgs("Unknown Extra", "other", time.Time{}, "unknown-extra").
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(9))
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT slug FROM films WHERE (slug = $1 OR slug LIKE $2)")).
+			WithArgs("the-matrix-1999", "the-matrix-1999-%").
+			WillReturnRows(sqlmock.NewRows([]string{"slug"}))
+		mock.ExpectQuery(regexp.QuoteMeta("INSERT INTO films (title,description,release_year,rating,release_date,original_language,country,runtime_minutes,slug) VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9) RETURNING id")).
+			WithArgs("The Matrix", "", 1999, 8.2, nil, "", "", 0, "the-matrix-1999").
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(20))
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO film_actor (film_id,actor_id,character_name,billing_order) VALUES ($1,$2,$3,$4),($5,$6,$7,$8)")).
+			WithArgs(20, 4, "", nil, 20, 9, "", nil).
+			WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectCommit()
+
+		result, err := repo.ImportMovie(context.Background(), domain.Movie{Title: "The Matrix", ReleaseYear: 1999, Rating: 8.2}, cast)
+		require.NoError(t, err)
+		assert.Equal(t, domain.MovieImportResult{MovieID: 20, ActorIDs: []int{4, 9}, ActorsCreated: 1, CreatedActorIDs: []int{9}}, result)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("links actors given by ID with roles", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT slug FROM films WHERE (slug = $1 OR slug LIKE $2)")).
+			WithArgs("john-wick-2014", "john-wick-2014-%").
+			WillReturnRows(sqlmock.NewRows([]string{"slug"}))
+		mock.ExpectQuery(regexp.QuoteMeta("INSERT INTO films (title,description,release_year,rating,release_date,original_language,country,runtime_minutes,slug) VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9) RETURNING id")).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(21))
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO film_actor (film_id,actor_id,character_name,billing_order) VALUES ($1,$2,$3,$4)")).
+			WithArgs(21, 4, "John Wick", 1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		result, err := repo.ImportMovie(context.Background(), domain.Movie{Title: "John Wick", ReleaseYear: 2014},
+			[]domain.Actor{{ID: 4, CharacterName: "John Wick", BillingOrder: 1}, {ID: 4}})
+		require.NoError(t, err)
+		assert.Equal(t, domain.MovieImportResult{MovieID: 21, ActorIDs: []int{4}}, result)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("rolls back on failure", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT id FROM actors")).
+			WillReturnError(sql.ErrConnDone)
+		mock.ExpectRollback()
+
+		_, err := repo.ImportMovie(context.Background(), domain.Movie{Title: "The Matrix"}, cast[:1])
+		assert.Error(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
 func TestMovieRepository_UpdateMovieActors(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	require.NoError(t, err)
@@ -588,8 +686,8 @@ func TestMovieRepository_UpdateMovieActors(t *testing.T) {
 				mock.ExpectExec(regexp.QuoteMeta("DELETE FROM film_actor WHERE film_id = $1")).
 					WithArgs(1).
 					WillReturnResult(sqlmock.NewResult(0, 1))
-				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO film_actor (film_id,actor_id) VALUES ($1,$2),($3,$4)")).
-					WithArgs(1, 1, 1, 2).
+				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO film_actor (film_id,actor_id,character_name,billing_order) VALUES ($1,$2,$3,$4),($5,$6,$7,$8)")).
+					WithArgs(1, 1, "Neo", 1, 1, 2, "", nil).
 					WillReturnResult(sqlmock.NewResult(0, 2))
 				mock.ExpectCommit()
 			},
@@ -611,7 +709,7 @@ func TestMovieRepository_UpdateMovieActors(t *testing.T) {
 			if tt.setup != nil {
 				tt.setup()
 			}
-			err := repo.UpdateMovieActors(1, []int{1, 2})
+			err := repo.UpdateMovieActors(context.Background(), 1, []domain.CastMember{{ActorID: 1, CharacterName: "Neo", BillingOrder: 1}, {ActorID: 2}})
 			if tt.wantErr {
 				assert.Error(t, err)
 			} else {
@@ -638,24 +736,24 @@ func TestMovieRepository_GetMoviesForActor(t *testing.T) {
 		{
 			name: "get movies for actor",
 			setup: func() {
-				rows := sqlmock.NewRows([]string{"id", "title", "description", "release_year", "rating"}).
-					AddRow(1, "Inception", "A mind-bending movie", 2010, 8.8)
-				mock.ExpectQuery(regexp.QuoteMeta("SELECT f.id, f.title, f.description, f.release_year, f.rating FROM films f JOIN film_actor fa ON f.id = fa.film_id WHERE fa.actor_id = $1")).WithArgs(actorID).WillReturnRows(rows)
+				rows := sqlmock.NewRows([]string{"id", "title", "description", "release_year", "rating", "release_date", "view_count", "original_language", "country", "runtime_minutes", "slug"}).
+					AddRow(1, "Inception", "A mind-bending movie", 2010, 8.8, nil, 0, "", "", 0, "")
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT f.id, f.title, f.description, f.release_year, f.rating, f.release_date, f.view_count, f.original_language, f.country, f.runtime_minutes, f.slug FROM films f JOIN film_actor fa ON f.id = fa.film_id WHERE fa.actor_id = $1")).WithArgs(actorID).WillReturnRows(rows)
 			},
 			want: []domain.Movie{{ID: 1, Title: "Inception", Description: "A mind-bending movie", ReleaseYear: 2010, Rating: 8.8}},
 		},
 		{
 			name: "no movies",
 			setup: func() {
-				rows := sqlmock.NewRows([]string{"id", "title", "description", "release_year", "rating"})
-				mock.ExpectQuery(regexp.QuoteMeta("SELECT f.id, f.title, f.description, f.release_year, f.rating FROM films f JOIN film_actor fa ON f.id = fa.film_id WHERE fa.actor_id = $1")).WithArgs(actorID).WillReturnRows(rows)
+				rows := sqlmock.NewRows([]string{"id", "title", "description", "release_year", "rating", "release_date", "view_count", "original_language", "country", "runtime_minutes", "slug"})
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT f.id, f.title, f.description, f.release_year, f.rating, f.release_date, f.view_count, f.original_language, f.country, f.runtime_minutes, f.slug FROM films f JOIN film_actor fa ON f.id = fa.film_id WHERE fa.actor_id = $1")).WithArgs(actorID).WillReturnRows(rows)
 			},
 			want: []domain.Movie{},
 		},
 		{
 			name: "db error",
 			setup: func() {
-				mock.ExpectQuery(regexp.QuoteMeta("SELECT f.id, f.title, f.description, f.release_year, f.rating FROM films f JOIN film_actor fa ON f.id = fa.film_id WHERE fa.actor_id = $1")).WithArgs(actorID).WillReturnError(sql.ErrConnDone)
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT f.id, f.title, f.description, f.release_year, f.rating, f.release_date, f.view_count, f.original_language, f.country, f.runtime_minutes, f.slug FROM films f JOIN film_actor fa ON f.id = fa.film_id WHERE fa.actor_id = $1")).WithArgs(actorID).WillReturnError(sql.ErrConnDone)
 			},
 			wantErr: true,
 		},
@@ -665,7 +763,7 @@ func TestMovieRepository_GetMoviesForActor(t *testing.T) {
 			if tt.setup != nil {
 				tt.setup()
 			}
-			got, err := repo.GetMoviesForActor(actorID)
+			got, err := repo.GetMoviesForActor(context.Background(), actorID)
 			if tt.wantErr {
 				assert.Error(t, err)
 			} else {
@@ -697,24 +795,24 @@ func TestMovieRepository_SearchMoviesByTitle(t *testing.T) {
 		{
 			name: "find movies by title",
 			setup: func() {
-				rows := sqlmock.NewRows([]string{"id", "title", "description", "release_year", "rating"}).
-					AddRow(1, "Inception", "A mind-bending movie", 2010, 8.8)
-				mock.ExpectQuery(regexp.QuoteMeta("SELECT id, title, description, release_year, rating FROM films WHERE title ILIKE $1")).WithArgs("%incep%").WillReturnRows(rows)
+				rows := sqlmock.NewRows([]string{"id", "title", "description", "release_year", "rating", "release_date", "view_count", "original_language", "country", "runtime_minutes", "slug"}).
+					AddRow(1, "Inception", "A mind-bending movie", 2010, 8.8, nil, 0, "", "", 0, "")
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT id, title, description, release_year, rating, release_date, view_count, original_language, country, runtime_minutes, slug FROM films WHERE title ILIKE $1")).WithArgs("%incep%").WillReturnRows(rows)
 			},
 			want: []domain.Movie{{ID: 1, Title: "Inception", Description: "A mind-bending movie", ReleaseYear: 2010, Rating: 8.8}},
 		},
 		{
 			name: "no movies found",
 			setup: func() {
-				rows := sqlmock.NewRows([]string{"id", "title", "description", "release_year", "rating"})
-				mock.ExpectQuery(regexp.QuoteMeta("SELECT id, title, description, release_year, rating FROM films WHERE title ILIKE $1")).WithArgs("%incep%").WillReturnRows(rows)
+				rows := sqlmock.NewRows([]string{"id", "title", "description", "release_year", "rating", "release_date", "view_count", "original_language", "country", "runtime_minutes", "slug"})
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT id, title, description, release_year, rating, release_date, view_count, original_language, country, runtime_minutes, slug FROM films WHERE title ILIKE $1")).WithArgs("%incep%").WillReturnRows(rows)
 			},
 			want: []domain.Movie{},
 		},
 		{
 			name: "db error",
 			setup: func() {
-				mock.ExpectQuery(regexp.QuoteMeta("SELECT id, title, description, release_year, rating FROM films WHERE title ILIKE $1")).WithArgs("%incep%").WillReturnError(sql.ErrConnDone)
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT id, title, description, release_year, rating, release_date, view_count, original_language, country, runtime_minutes, slug FROM films WHERE title ILIKE $1")).WithArgs("%incep%").WillReturnError(sql.ErrConnDone)
 			},
 			wantErr: true,
 		},
@@ -724,7 +822,7 @@ func TestMovieRepository_SearchMoviesByTitle(t *testing.T) {
 			if tt.setup != nil {
 				tt.setup()
 			}
-			got, err := repo.SearchMoviesByTitle(titleFragment)
+			got, err := repo.SearchMoviesByTitle(context.Background(), titleFragment, domain.MovieFilter{})
 			if tt.wantErr {
 				assert.Error(t, err)
 			} else {
@@ -740,28 +838,175 @@ func TestMovieRepository_SearchMoviesByTitle(t *testing.T) {
 	}
 }
 
+func TestMovieRepository_SearchMoviesWithFilter(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewMovie(db)
+	columns := []string{"id", "title", "description", "release_year", "rating", "release_date", "view_count", "original_language", "country", "runtime_minutes", "slug"}
+	filter := domain.MovieFilter{OriginalLanguage: "fr", Country: "FR"}
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, title, description, release_year, rating, release_date, view_count, original_language, country, runtime_minutes, slug FROM films "+
+		"WHERE title ILIKE $1 AND original_language = $2 AND country = $3")).
+		WithArgs("%%", "fr", "FR").
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(3, "Amélie", "", 2001, 8.3, nil, 0, "fr", "FR", 0, ""))
+	movies, err := repo.SearchMoviesByTitle(context.Background(), "", filter)
+	require.NoError(t, err)
+	assert.Equal(t, []domain.Movie{{ID: 3, Title: "Amélie", ReleaseYear: 2001, Rating: 8.3, OriginalLanguage: "fr", Country: "FR"}}, movies)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT f.id, f.title, f.description, f.release_year, f.rating, f.release_date, f.view_count, f.original_language, f.country, f.runtime_minutes, f.slug FROM films f "+
+		"JOIN film_actor fa ON f.id = fa.film_id JOIN actors a ON fa.actor_id = a.id WHERE a.name ILIKE $1 AND f.country = $2")).
+		WithArgs("%tautou%", "FR").
+		WillReturnRows(sqlmock.NewRows(columns))
+	_, err = repo.SearchMoviesByActorName(context.Background(), "tautou", domain.MovieFilter{Country: "FR"})
+	require.NoError(t, err)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, title, description, release_year, rating, release_date, view_count, original_language, country, runtime_minutes, slug FROM films "+
+		"WHERE (title ILIKE $1 OR title % $2) AND original_language = $3 ORDER BY similarity(title, $4) DESC, id ASC")).
+		WithArgs("%amelie%", "amelie", "fr", "amelie").
+		WillReturnRows(sqlmock.NewRows(columns))
+	_, err = repo.SearchMoviesByTitleTrigram(context.Background(), "amelie", domain.MovieFilter{OriginalLanguage: "fr"})
+	require.NoError(t, err)
+
+	// Окна доступности проверяются подзапросом; без алиаса фильм указывается как films.id
+	available, unavailable := true, false
+	today := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, title, description, release_year, rating, release_date, view_count, original_language, country, runtime_minutes, slug FROM films "+
+		"WHERE title ILIKE $1 AND EXISTS (SELECT 1 FROM movie_availability ma WHERE ma.film_id = films.id AND ma.region = $2 "+
+		"AND ma.available_from <= $3 AND (ma.available_until IS NULL OR ma.available_until >= $4))")).
+		WithArgs("%%", "DE", "2026-03-01", "2026-03-01").
+		WillReturnRows(sqlmock.NewRows(columns))
+	_, err = repo.SearchMoviesByTitle(context.Background(), "", domain.MovieFilter{Region: "DE", Available: &available, AvailableOn: today})
+	require.NoError(t, err)
+
+	mock.ExpectQuery(regexp.QuoteMeta("FROM films f JOIN film_actor fa ON f.id = fa.film_id JOIN actors a ON fa.actor_id = a.id WHERE a.name ILIKE $1 "+
+		"AND NOT EXISTS (SELECT 1 FROM movie_availability ma WHERE ma.film_id = f.id "+
+		"AND ma.available_from <= $2 AND (ma.available_until IS NULL OR ma.available_until >= $3))")).
+		WithArgs("%tautou%", "2026-03-01", "2026-03-01").
+		WillReturnRows(sqlmock.NewRows(columns))
+	_, err = repo.SearchMoviesByActorName(context.Background(), "tautou", domain.MovieFilter{Available: &unavailable, AvailableOn: today})
+	require.NoError(t, err)
+
+	mock.ExpectQuery(regexp.QuoteMeta("FROM films WHERE title ILIKE $1 AND EXISTS (SELECT 1 FROM movie_availability ma WHERE ma.film_id = films.id AND ma.region = $2)")).
+		WithArgs("%amelie%", "FR").
+		WillReturnRows(sqlmock.NewRows(columns))
+	_, err = repo.SearchMoviesByTitle(context.Background(), "amelie", domain.MovieFilter{Region: "FR"})
+	require.NoError(t, err)
+
+	// Фильмы с неизвестной продолжительностью (0) в диапазон не попадают
+	mock.ExpectQuery(regexp.QuoteMeta("FROM films WHERE title ILIKE $1 AND runtime_minutes > $2 AND runtime_minutes >= $3 AND runtime_minutes <= $4")).
+		WithArgs("%%", 0, 90, 120).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(3, "Amélie", "", 2001, 8.3, nil, 0, "fr", "FR", 117, ""))
+	_, err = repo.SearchMoviesByTitle(context.Background(), "", domain.MovieFilter{RuntimeMin: 90, RuntimeMax: 120})
+	require.NoError(t, err)
+
+	mock.ExpectQuery(regexp.QuoteMeta("WHERE a.name ILIKE $1 AND f.runtime_minutes > $2 AND f.runtime_minutes <= $3")).
+		WithArgs("%tautou%", 0, 100).
+		WillReturnRows(sqlmock.NewRows(columns))
+	_, err = repo.SearchMoviesByActorName(context.Background(), "tautou", domain.MovieFilter{RuntimeMax: 100})
+	require.NoError(t, err)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMovieRepository_CountMovies(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewMovie(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM films WHERE original_language = $1 AND country = $2")).
+		WithArgs("fr", "FR").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(12))
+	total, err := repo.CountMovies(context.Background(), domain.MovieFilter{OriginalLanguage: "fr", Country: "FR"})
+	require.NoError(t, err)
+	assert.Equal(t, 12, total)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM films")).WillReturnError(sql.ErrConnDone)
+	_, err = repo.CountMovies(context.Background(), domain.MovieFilter{})
+	assert.ErrorIs(t, err, sql.ErrConnDone)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMovieRepository_ForEachMovie(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	repo := NewMovie(db)
+	columns := []string{"id", "title", "description", "release_year", "rating", "release_date", "view_count", "original_language", "country", "runtime_minutes", "slug"}
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT id, title, description, release_year, rating, release_date, view_count, original_language, country, runtime_minutes, slug FROM films ORDER BY id ASC`)).
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow(1, "Inception", "", 2010, 8.8, nil, 0, "", "", 0, "").
+			AddRow(2, "The Revenant", "", 2015, 8.0, nil, 0, "", "", 0, ""))
+	var titles []string
+	err = repo.ForEachMovie(context.Background(), func(m domain.Movie) error {
+		titles = append(titles, m.Title)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Inception", "The Revenant"}, titles)
+
+	// Ошибка обработчика прекращает чтение
+	stop := errors.New("client disconnected")
+	mock.ExpectQuery(`SELECT .* FROM films ORDER BY id ASC`).
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow(1, "Inception", "", 2010, 8.8, nil, 0, "", "", 0, "").
+			AddRow(2, "The Revenant", "", 2015, 8.0, nil, 0, "", "", 0, ""))
+	calls := 0
+	err = repo.ForEachMovie(context.Background(), func(domain.Movie) error {
+		calls++
+		return stop
+	})
+	assert.ErrorIs(t, err, stop)
+	assert.Equal(t, 1, calls)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMovieRepository_GetMoviesAfterID(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT id, title, description, release_year, rating, release_date, view_count, original_language, country, runtime_minutes, slug FROM films WHERE id > $1 ORDER BY id ASC LIMIT 3`)).
+		WithArgs(10).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title", "description", "release_year", "rating", "release_date", "view_count", "original_language", "country", "runtime_minutes", "slug"}).
+			AddRow(11, "Inception", "", 2010, 8.8, nil, 0, "", "", 0, "").
+			AddRow(14, "The Revenant", "", 2015, 8.0, nil, 0, "", "", 0, ""))
+
+	movies, err := NewMovie(db).GetMoviesAfterID(context.Background(), 10, 3)
+	require.NoError(t, err)
+	assert.Equal(t, []domain.Movie{
+		{ID: 11, Title: "Inception", ReleaseYear: 2010, Rating: 8.8},
+		{ID: 14, Title: "The Revenant", ReleaseYear: 2015, Rating: 8.0},
+	}, movies)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestMovieRepository_GetAllMoviesSorted(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	require.NoError(t, err)
 	defer db.Close()
 
 	repo := NewMovie(db)
-	sortField := "title"
+	selectMovies := "SELECT id, title, description, release_year, rating, release_date, view_count, original_language, country, runtime_minutes, slug FROM films "
 	tests := []struct {
-		name      string
-		sortOrder string
-		setup     func()
-		want      []domain.Movie
-		wantErr   bool
+		name    string
+		query   domain.MovieListQuery
+		setup   func()
+		want    []domain.Movie
+		wantErr error
 	}{
 		{
-			name:      "sorted movies ASC",
-			sortOrder: "ASC",
+			name:  "sorted movies ASC",
+			query: domain.MovieListQuery{Sort: []domain.SortField{{Field: "title", Order: "asc"}}},
 			setup: func() {
-				rows := sqlmock.NewRows([]string{"id", "title", "description", "release_year", "rating"}).
-					AddRow(1, "A", "desc", 2010, 7.1).
-					AddRow(2, "B", "desc2", 2011, 8.1)
-				mock.ExpectQuery(regexp.QuoteMeta("SELECT id, title, description, release_year, rating FROM films ORDER BY " + sortField + " ASC")).WillReturnRows(rows)
+				rows := sqlmock.NewRows([]string{"id", "title", "description", "release_year", "rating", "release_date", "view_count", "original_language", "country", "runtime_minutes", "slug"}).
+					AddRow(1, "A", "desc", 2010, 7.1, nil, 0, "", "", 0, "").
+					AddRow(2, "B", "desc2", 2011, 8.1, nil, 0, "", "", 0, "")
+				mock.ExpectQuery(regexp.QuoteMeta(selectMovies + "ORDER BY title ASC, id ASC")).WillReturnRows(rows)
 			},
 			want: []domain.Movie{
 				{ID: 1, Title: "A", Description: "desc", ReleaseYear: 2010, Rating: 7.1},
@@ -769,13 +1014,13 @@ func TestMovieRepository_GetAllMoviesSorted(t *testing.T) {
 			},
 		},
 		{
-			name:      "sorted movies DESC",
-			sortOrder: "DESC",
+			name:  "sorted movies DESC",
+			query: domain.MovieListQuery{Sort: []domain.SortField{{Field: "title", Order: "desc"}}},
 			setup: func() {
-				rows := sqlmock.NewRows([]string{"id", "title", "description", "release_year", "rating"}).
-					AddRow(2, "B", "desc2", 2011, 8.1).
-					AddRow(1, "A", "desc", 2010, 7.1)
-				mock.ExpectQuery(regexp.QuoteMeta("SELECT id, title, description, release_year, rating FROM films ORDER BY " + sortField + " DESC")).WillReturnRows(rows)
+				rows := sqlmock.NewRows([]string{"id", "title", "description", "release_year", "rating", "release_date", "view_count", "original_language", "country", "runtime_minutes", "slug"}).
+					AddRow(2, "B", "desc2", 2011, 8.1, nil, 0, "", "", 0, "").
+					AddRow(1, "A", "desc", 2010, 7.1, nil, 0, "", "", 0, "")
+				mock.ExpectQuery(regexp.QuoteMeta(selectMovies + "ORDER BY title DESC, id ASC")).WillReturnRows(rows)
 			},
 			want: []domain.Movie{
 				{ID: 2, Title: "B", Description: "desc2", ReleaseYear: 2011, Rating: 8.1},
@@ -783,12 +1028,59 @@ func TestMovieRepository_GetAllMoviesSorted(t *testing.T) {
 			},
 		},
 		{
-			name:      "db error",
-			sortOrder: "ASC",
+			name: "multiple fields with pagination",
+			query: domain.MovieListQuery{
+				Sort:   []domain.SortField{{Field: "rating", Order: "desc"}, {Field: "title", Order: "asc"}},
+				Limit:  2,
+				Offset: 4,
+			},
 			setup: func() {
-				mock.ExpectQuery(regexp.QuoteMeta("SELECT id, title, description, release_year, rating FROM films ORDER BY " + sortField + " ASC")).WillReturnError(sql.ErrConnDone)
+				rows := sqlmock.NewRows([]string{"id", "title", "description", "release_year", "rating", "release_date", "view_count", "original_language", "country", "runtime_minutes", "slug"}).
+					AddRow(5, "C", "desc", 2012, 7.5, nil, 0, "", "", 0, "")
+				mock.ExpectQuery(regexp.QuoteMeta(selectMovies + "ORDER BY rating DESC, title ASC, id ASC LIMIT 2 OFFSET 4")).WillReturnRows(rows)
 			},
-			wantErr: true,
+			want: []domain.Movie{{ID: 5, Title: "C", Description: "desc", ReleaseYear: 2012, Rating: 7.5}},
+		},
+		{
+			name:  "default sort",
+			query: domain.MovieListQuery{},
+			setup: func() {
+				rows := sqlmock.NewRows([]string{"id", "title", "description", "release_year", "rating", "release_date", "view_count", "original_language", "country", "runtime_minutes", "slug"})
+				mock.ExpectQuery(regexp.QuoteMeta(selectMovies + "ORDER BY rating DESC, id ASC")).WillReturnRows(rows)
+			},
+			want: []domain.Movie{},
+		},
+		{
+			name:  "explicit id sort is not duplicated",
+			query: domain.MovieListQuery{Sort: []domain.SortField{{Field: "id", Order: "desc"}}},
+			setup: func() {
+				rows := sqlmock.NewRows([]string{"id", "title", "description", "release_year", "rating", "release_date", "view_count", "original_language", "country", "runtime_minutes", "slug"})
+				mock.ExpectQuery(regexp.QuoteMeta(selectMovies + "ORDER BY id DESC")).WillReturnRows(rows)
+			},
+			want: []domain.Movie{},
+		},
+		{
+			name:    "unknown field",
+			query:   domain.MovieListQuery{Sort: []domain.SortField{{Field: "password"}}},
+			wantErr: domain.ErrInvalidSort,
+		},
+		{
+			name:    "unknown order",
+			query:   domain.MovieListQuery{Sort: []domain.SortField{{Field: "title", Order: "sideways"}}},
+			wantErr: domain.ErrInvalidSort,
+		},
+		{
+			name:    "duplicate field",
+			query:   domain.MovieListQuery{Sort: []domain.SortField{{Field: "title"}, {Field: "title", Order: "desc"}}},
+			wantErr: domain.ErrInvalidSort,
+		},
+		{
+			name:  "db error",
+			query: domain.MovieListQuery{Sort: []domain.SortField{{Field: "title", Order: "asc"}}},
+			setup: func() {
+				mock.ExpectQuery(regexp.QuoteMeta(selectMovies + "ORDER BY title ASC, id ASC")).WillReturnError(sql.ErrConnDone)
+			},
+			wantErr: sql.ErrConnDone,
 		},
 	}
 	for _, tt := range tests {
@@ -796,16 +1088,12 @@ func TestMovieRepository_GetAllMoviesSorted(t *testing.T) {
 			if tt.setup != nil {
 				tt.setup()
 			}
-			got, err := repo.GetAllMoviesSorted(sortField, tt.sortOrder)
-			if tt.wantErr {
-				assert.Error(t, err)
+			got, err := repo.GetAllMoviesSorted(context.Background(), tt.query)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
 			} else {
 				assert.NoError(t, err)
-				if len(tt.want) == 0 {
-					assert.Empty(t, got)
-				} else {
-					assert.Equal(t, tt.want, got)
-				}
+				assert.Equal(t, tt.want, got)
 			}
 			assert.NoError(t, mock.ExpectationsWereMet())
 		})
@@ -819,25 +1107,64 @@ func TestMovieRepository_PartialUpdateMovie(t *testing.T) {
 
 	repo := NewMovie(db)
 	title := "NewTitle"
-	update := domain.MovieUpdate{Title: &title}
+	releaseDate := time.Date(1999, 3, 31, 0, 0, 0, 0, time.UTC)
+	language, country := "ja", "JP"
 	id := 1
 	tests := []struct {
 		name    string
+		update  domain.MovieUpdate
 		setup   func()
-		wantErr bool
+		wantErr error
 	}{
 		{
-			name: "partial update success",
+			name:   "partial update success",
+			update: domain.MovieUpdate{Title: &title},
 			setup: func() {
 				mock.ExpectExec(`UPDATE films SET title = \$1 WHERE id = \$2`).WithArgs("NewTitle", id).WillReturnResult(sqlmock.NewResult(0, 1))
 			},
 		},
 		{
-			name: "db error",
+			name:   "release date fills missing year",
+			update: domain.MovieUpdate{ReleaseDate: &releaseDate},
+			setup: func() {
+				mock.ExpectExec(`UPDATE films SET release_date = \$1, release_year = CASE WHEN release_year = 0 THEN \$2 ELSE release_year END WHERE id = \$3`).
+					WithArgs(releaseDate, 1999, id).WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name:   "clear release date",
+			update: domain.MovieUpdate{ClearReleaseDate: true},
+			setup: func() {
+				mock.ExpectExec(`UPDATE films SET release_date = \$1 WHERE id = \$2`).WithArgs(nil, id).WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name:   "language and country",
+			update: domain.MovieUpdate{OriginalLanguage: &language, Country: &country},
+			setup: func() {
+				mock.ExpectExec(`UPDATE films SET original_language = \$1, country = \$2 WHERE id = \$3`).WithArgs("ja", "JP", id).WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name:    "no fields",
+			update:  domain.MovieUpdate{},
+			wantErr: domain.ErrNoFieldsToUpdate,
+		},
+		{
+			name:   "not found",
+			update: domain.MovieUpdate{Title: &title},
+			setup: func() {
+				mock.ExpectExec(`UPDATE films SET title = \$1 WHERE id = \$2`).WithArgs("NewTitle", id).WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			wantErr: domain.ErrMovieNotFound,
+		},
+		{
+			name:   "db error",
+			update: domain.MovieUpdate{Title: &title},
 			setup: func() {
 				mock.ExpectExec(`UPDATE films SET title = \$1 WHERE id = \$2`).WithArgs("NewTitle", id).WillReturnError(sql.ErrConnDone)
 			},
-			wantErr: true,
+			wantErr: sql.ErrConnDone,
 		},
 	}
 	for _, tt := range tests {
@@ -845,9 +1172,9 @@ func TestMovieRepository_PartialUpdateMovie(t *testing.T) {
 			if tt.setup != nil {
 				tt.setup()
 			}
-			err := repo.PartialUpdateMovie(id, update)
-			if tt.wantErr {
-				assert.Error(t, err)
+			err := repo.PartialUpdateMovie(context.Background(), id, tt.update)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
 			} else {
 				assert.NoError(t, err)
 			}
@@ -856,6 +1183,97 @@ func TestMovieRepository_PartialUpdateMovie(t *testing.T) {
 	}
 }
 
+func TestMovieRepository_BulkDeleteMovies(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewMovie(db)
+
+	t.Run("missing movie does not abort the others", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(`SAVEPOINT bulk_item`).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery(lockMovieQuery).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+		mock.ExpectExec(`DELETE FROM film_actor WHERE film_id = \$1`).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectExec(`DELETE FROM films WHERE id = \$1`).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(`RELEASE SAVEPOINT bulk_item`).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(`SAVEPOINT bulk_item`).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery(lockMovieQuery).WithArgs(999).WillReturnError(sql.ErrNoRows)
+		mock.ExpectExec(`ROLLBACK TO SAVEPOINT bulk_item`).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectCommit()
+
+		results, err := repo.BulkDeleteMovies(context.Background(), []int{1, 999}, domain.MovieDeleteCascade)
+		require.NoError(t, err)
+		require.Len(t, results, 2)
+		assert.Equal(t, 1, results[0].ID)
+		assert.NoError(t, results[0].Err)
+		assert.Equal(t, 999, results[1].ID)
+		assert.ErrorIs(t, results[1].Err, domain.ErrMovieNotFound)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("restrict keeps referenced movies", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(`SAVEPOINT bulk_item`).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery(lockMovieQuery).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+		expectMovieReferences(mock, 1, 1, 0, 0, 0)
+		mock.ExpectExec(`ROLLBACK TO SAVEPOINT bulk_item`).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(`SAVEPOINT bulk_item`).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery(lockMovieQuery).WithArgs(2).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+		expectMovieReferences(mock, 2, 0, 0, 0, 0)
+		mock.ExpectExec(`DELETE FROM film_actor WHERE film_id = \$1`).WithArgs(2).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(`DELETE FROM films WHERE id = \$1`).WithArgs(2).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(`RELEASE SAVEPOINT bulk_item`).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectCommit()
+
+		results, err := repo.BulkDeleteMovies(context.Background(), []int{1, 2}, domain.MovieDeleteRestrict)
+		require.NoError(t, err)
+		require.Len(t, results, 2)
+		assert.ErrorIs(t, results[0].Err, domain.ErrMovieReferenced)
+		assert.NoError(t, results[1].Err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("savepoint failure rolls back everything", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(`SAVEPOINT bulk_item`).WillReturnError(sql.ErrConnDone)
+		mock.ExpectRollback()
+
+		results, err := repo.BulkDeleteMovies(context.Background(), []int{1}, domain.MovieDeleteCascade)
+		assert.ErrorIs(t, err, sql.ErrConnDone)
+		assert.Nil(t, results)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestMovieRepository_BulkUpdateMovies(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewMovie(db)
+	rating := 7.5
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`SAVEPOINT bulk_item`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`UPDATE films SET rating = \$1 WHERE id = \$2`).WithArgs(rating, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`RELEASE SAVEPOINT bulk_item`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`SAVEPOINT bulk_item`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`UPDATE films SET rating = \$1 WHERE id = \$2`).WithArgs(rating, 2).WillReturnError(errors.New("check constraint violated"))
+	mock.ExpectExec(`ROLLBACK TO SAVEPOINT bulk_item`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	results, err := repo.BulkUpdateMovies(context.Background(), []int{1, 2}, domain.MovieUpdate{Rating: &rating})
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	assert.NoError(t, results[0].Err)
+	assert.EqualError(t, results[1].Err, "check constraint violated")
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	_, err = repo.BulkUpdateMovies(context.Background(), []int{1}, domain.MovieUpdate{})
+	assert.ErrorIs(t, err, domain.ErrNoFieldsToUpdate)
+}
+
 func TestMovieRepository_SearchMoviesByActorName(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	require.NoError(t, err)
@@ -873,24 +1291,24 @@ func TestMovieRepository_SearchMoviesByActorName(t *testing.T) {
 		{
 			name: "find movies by actor name",
 			setup: func() {
-				rows := sqlmock.NewRows([]string{"id", "title", "description", "release_year", "rating"}).
-					AddRow(1, "Inception", "A mind-bending movie", 2010, 8.8)
-				mock.ExpectQuery(regexp.QuoteMeta("SELECT f.id, f.title, f.description, f.release_year, f.rating FROM films f JOIN film_actor fa ON f.id = fa.film_id JOIN actors a ON fa.actor_id = a.id WHERE a.name ILIKE $1")).WithArgs(arg).WillReturnRows(rows)
+				rows := sqlmock.NewRows([]string{"id", "title", "description", "release_year", "rating", "release_date", "view_count", "original_language", "country", "runtime_minutes", "slug"}).
+					AddRow(1, "Inception", "A mind-bending movie", 2010, 8.8, nil, 0, "", "", 0, "")
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT f.id, f.title, f.description, f.release_year, f.rating, f.release_date, f.view_count, f.original_language, f.country, f.runtime_minutes, f.slug FROM films f JOIN film_actor fa ON f.id = fa.film_id JOIN actors a ON fa.actor_id = a.id WHERE a.name ILIKE $1")).WithArgs(arg).WillReturnRows(rows)
 			},
 			want: []domain.Movie{{ID: 1, Title: "Inception", Description: "A mind-bending movie", ReleaseYear: 2010, Rating: 8.8}},
 		},
 		{
 			name: "no movies found",
 			setup: func() {
-				rows := sqlmock.NewRows([]string{"id", "title", "description", "release_year", "rating"})
-				mock.ExpectQuery(regexp.QuoteMeta("SELECT f.id, f.title, f.description, f.release_year, f.rating FROM films f JOIN film_actor fa ON f.id = fa.film_id JOIN actors a ON fa.actor_id = a.id WHERE a.name ILIKE $1")).WithArgs(arg).WillReturnRows(rows)
+				rows := sqlmock.NewRows([]string{"id", "title", "description", "release_year", "rating", "release_date", "view_count", "original_language", "country", "runtime_minutes", "slug"})
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT f.id, f.title, f.description, f.release_year, f.rating, f.release_date, f.view_count, f.original_language, f.country, f.runtime_minutes, f.slug FROM films f JOIN film_actor fa ON f.id = fa.film_id JOIN actors a ON fa.actor_id = a.id WHERE a.name ILIKE $1")).WithArgs(arg).WillReturnRows(rows)
 			},
 			want: []domain.Movie{},
 		},
 		{
 			name: "db error",
 			setup: func() {
-				mock.ExpectQuery(regexp.QuoteMeta("SELECT f.id, f.title, f.description, f.release_year, f.rating FROM films f JOIN film_actor fa ON f.id = fa.film_id JOIN actors a ON fa.actor_id = a.id WHERE a.name ILIKE $1")).WithArgs(arg).WillReturnError(sql.ErrConnDone)
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT f.id, f.title, f.description, f.release_year, f.rating, f.release_date, f.view_count, f.original_language, f.country, f.runtime_minutes, f.slug FROM films f JOIN film_actor fa ON f.id = fa.film_id JOIN actors a ON fa.actor_id = a.id WHERE a.name ILIKE $1")).WithArgs(arg).WillReturnError(sql.ErrConnDone)
 			},
 			wantErr: true,
 		},
@@ -900,7 +1318,7 @@ func TestMovieRepository_SearchMoviesByActorName(t *testing.T) {
 			if tt.setup != nil {
 				tt.setup()
 			}
-			got, err := repo.SearchMoviesByActorName(actorNameFragment)
+			got, err := repo.SearchMoviesByActorName(context.Background(), actorNameFragment, domain.MovieFilter{})
 			if tt.wantErr {
 				assert.Error(t, err)
 			} else {
@@ -915,3 +1333,341 @@ func TestMovieRepository_SearchMoviesByActorName(t *testing.T) {
 		})
 	}
 }
+
+func TestMovieRepository_GetMatchingActors(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewMovie(db)
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT fa.film_id, a.id, a.name FROM film_actor fa JOIN actors a ON a.id = fa.actor_id "+
+		"WHERE fa.film_id IN ($1,$2) AND a.name ILIKE $3 ORDER BY fa.film_id, a.name, a.id")).
+		WithArgs(1, 2, "%ee%").
+		WillReturnRows(sqlmock.NewRows([]string{"film_id", "id", "name"}).
+			AddRow(1, 7, "Keanu Reeves").
+			AddRow(2, 7, "Keanu Reeves").
+			AddRow(2, 9, "Reese Witherspoon"))
+
+	matches, err := repo.GetMatchingActors(context.Background(), []int{1, 2}, "ee")
+	require.NoError(t, err)
+	assert.Equal(t, map[int][]domain.Actor{
+		1: {{ID: 7, Name: "Keanu Reeves"}},
+		2: {{ID: 7, Name: "Keanu Reeves"}, {ID: 9, Name: "Reese Witherspoon"}},
+	}, matches)
+
+	// Без фильмов запрос не выполняется
+	matches, err = repo.GetMatchingActors(context.Background(), nil, "ee")
+	require.NoError(t, err)
+	assert.Empty(t, matches)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMovieRepository_MergeMovies(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewMovie(db)
+	selectQuery := regexp.QuoteMeta("SELECT id, title, description, release_year, rating, release_date, view_count, original_language, country, runtime_minutes, slug, updated_at, external_id FROM films WHERE id = $1 FOR UPDATE")
+	columns := []string{"id", "title", "description", "release_year", "rating", "release_date", "view_count", "original_language", "country", "runtime_minutes", "slug", "updated_at", "external_id"}
+	releaseDate := time.Date(2010, time.July, 16, 0, 0, 0, 0, time.UTC)
+	updatedAt := time.Date(2026, time.March, 1, 12, 0, 0, 0, time.UTC)
+	moveCollections := regexp.QuoteMeta("UPDATE collection_movies SET film_id = $1 WHERE film_id = $2 AND collection_id NOT IN (SELECT collection_id FROM collection_movies WHERE film_id = $3)")
+	moveTags := regexp.QuoteMeta("INSERT INTO movie_tags (film_id,tag_id,created_at) SELECT $1, tag_id, created_at FROM movie_tags WHERE film_id = $2 ON CONFLICT DO NOTHING")
+	moveAvailability := regexp.QuoteMeta("INSERT INTO movie_availability (film_id,region,available_from,available_until) SELECT $1, region, available_from, available_until FROM movie_availability WHERE film_id = $2 ON CONFLICT DO NOTHING")
+	moveRatings := regexp.QuoteMeta("INSERT INTO movie_ratings (film_id,source,rating,updated_at) SELECT $1, source, rating, updated_at FROM movie_ratings WHERE film_id = $2 AND source <> $3 ON CONFLICT DO NOTHING")
+	deleteRows := func(table string) string {
+		return regexp.QuoteMeta("DELETE FROM " + table + " WHERE film_id = $1")
+	}
+	// expectMovedRelations ожидает перенос подборок, тегов, окон доступности и рейтингов источников
+	expectMovedRelations := func() {
+		mock.ExpectExec(moveCollections).WithArgs(1, 2, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(deleteRows("collection_movies")).WithArgs(2).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(moveTags).WithArgs(1, 2).WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectExec(deleteRows("movie_tags")).WithArgs(2).WillReturnResult(sqlmock.NewResult(0, 3))
+		mock.ExpectExec(moveAvailability).WithArgs(1, 2).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(deleteRows("movie_availability")).WithArgs(2).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(moveRatings).WithArgs(1, 2, domain.RatingSourceInternal).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(deleteRows("movie_ratings")).WithArgs(2).WillReturnResult(sqlmock.NewResult(0, 2))
+	}
+
+	tests := []struct {
+		name    string
+		setup   func()
+		want    domain.MovieMergeResult
+		wantErr error
+	}{
+		{
+			name: "successful merge fills empty fields",
+			setup: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery(selectQuery).WithArgs(1).
+					WillReturnRows(sqlmock.NewRows(columns).AddRow(1, "Inception", "", 2010, 8.8, nil, 40, "", "", 0, "", updatedAt, nil))
+				mock.ExpectQuery(selectQuery).WithArgs(2).
+					WillReturnRows(sqlmock.NewRows(columns).AddRow(2, "Inception (2010)", "A mind-bending movie", 2010, 8.7, releaseDate, 2, "en", "US", 148, "inception-2010", updatedAt, "tt1375666"))
+				mock.ExpectExec(regexp.QuoteMeta("UPDATE films SET external_id = $1 WHERE id = $2")).
+					WithArgs(nil, 2).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery(regexp.QuoteMeta("UPDATE films SET description = $1, release_year = $2, rating = $3, release_date = $4, view_count = $5, original_language = $6, country = $7, runtime_minutes = $8, external_id = $9 WHERE id = $10 RETURNING updated_at")).
+					WithArgs("A mind-bending movie", 2010, 8.8, releaseDate, int64(42), "en", "US", 148, "tt1375666", 1).
+					WillReturnRows(sqlmock.NewRows([]string{"updated_at"}).AddRow(updatedAt))
+				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO film_actor (film_id,actor_id,character_name,billing_order) SELECT $1, actor_id, character_name, billing_order FROM film_actor WHERE film_id = $2 ON CONFLICT DO NOTHING")).
+					WithArgs(1, 2).
+					WillReturnResult(sqlmock.NewResult(0, 2))
+				expectMovedRelations()
+				mock.ExpectExec(regexp.QuoteMeta("UPDATE movie_merges SET new_id = $1 WHERE new_id = $2")).
+					WithArgs(1, 2).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				expectMovieReferences(mock, 2, 0, 0, 0, 0)
+				mock.ExpectExec(regexp.QuoteMeta("DELETE FROM film_actor WHERE film_id = $1")).
+					WithArgs(2).
+					WillReturnResult(sqlmock.NewResult(0, 3))
+				mock.ExpectExec(regexp.QuoteMeta("DELETE FROM films WHERE id = $1")).
+					WithArgs(2).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO movie_merges (old_id,new_id,old_slug) VALUES ($1,$2,$3)")).
+					WithArgs(2, 1, "inception-2010").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+			want: domain.MovieMergeResult{
+				Movie: domain.Movie{ID: 1, Title: "Inception", Description: "A mind-bending movie", ReleaseYear: 2010, ReleaseDate: &releaseDate, Rating: 8.8, ViewCount: 42,
+					OriginalLanguage: "en", Country: "US", RuntimeMinutes: 148, UpdatedAt: updatedAt, ExternalID: "tt1375666"},
+				DuplicateID:      2,
+				ActorsReassigned: 2,
+			},
+		},
+		{
+			name: "kept movie keeps its external id",
+			setup: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery(selectQuery).WithArgs(1).
+					WillReturnRows(sqlmock.NewRows(columns).AddRow(1, "Inception", "A mind-bending movie", 2010, 8.8, releaseDate, 40, "en", "US", 148, "", updatedAt, "tt1375666"))
+				mock.ExpectQuery(selectQuery).WithArgs(2).
+					WillReturnRows(sqlmock.NewRows(columns).AddRow(2, "Inception (2010)", "", 2010, 8.7, nil, 2, "", "", 0, "inception-2010", updatedAt, "tt9999999"))
+				mock.ExpectQuery(regexp.QuoteMeta("UPDATE films SET description = $1, release_year = $2, rating = $3, release_date = $4, view_count = $5, original_language = $6, country = $7, runtime_minutes = $8 WHERE id = $9 RETURNING updated_at")).
+					WithArgs("A mind-bending movie", 2010, 8.8, releaseDate, int64(42), "en", "US", 148, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"updated_at"}).AddRow(updatedAt))
+				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO film_actor (film_id,actor_id,character_name,billing_order) SELECT $1, actor_id, character_name, billing_order FROM film_actor WHERE film_id = $2 ON CONFLICT DO NOTHING")).
+					WithArgs(1, 2).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				expectMovedRelations()
+				mock.ExpectExec(regexp.QuoteMeta("UPDATE movie_merges SET new_id = $1 WHERE new_id = $2")).
+					WithArgs(1, 2).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				expectMovieReferences(mock, 2, 0, 0, 0, 0)
+				mock.ExpectExec(regexp.QuoteMeta("DELETE FROM film_actor WHERE film_id = $1")).
+					WithArgs(2).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec(regexp.QuoteMeta("DELETE FROM films WHERE id = $1")).
+					WithArgs(2).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO movie_merges (old_id,new_id,old_slug) VALUES ($1,$2,$3)")).
+					WithArgs(2, 1, "inception-2010").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+			want: domain.MovieMergeResult{
+				Movie: domain.Movie{ID: 1, Title: "Inception", Description: "A mind-bending movie", ReleaseYear: 2010, ReleaseDate: &releaseDate, Rating: 8.8, ViewCount: 42,
+					OriginalLanguage: "en", Country: "US", RuntimeMinutes: 148, UpdatedAt: updatedAt, ExternalID: "tt1375666"},
+				DuplicateID: 2,
+			},
+		},
+		{
+			name: "failed relation move rolls back",
+			setup: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery(selectQuery).WithArgs(1).
+					WillReturnRows(sqlmock.NewRows(columns).AddRow(1, "Inception", "A mind-bending movie", 2010, 8.8, releaseDate, 40, "en", "US", 148, "", updatedAt, nil))
+				mock.ExpectQuery(selectQuery).WithArgs(2).
+					WillReturnRows(sqlmock.NewRows(columns).AddRow(2, "Inception (2010)", "", 2010, 8.7, nil, 2, "", "", 0, "", updatedAt, nil))
+				mock.ExpectQuery(regexp.QuoteMeta("UPDATE films SET description = $1, release_year = $2, rating = $3, release_date = $4, view_count = $5, original_language = $6, country = $7, runtime_minutes = $8 WHERE id = $9 RETURNING updated_at")).
+					WithArgs("A mind-bending movie", 2010, 8.8, releaseDate, int64(42), "en", "US", 148, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"updated_at"}).AddRow(updatedAt))
+				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO film_actor (film_id,actor_id,character_name,billing_order) SELECT $1, actor_id, character_name, billing_order FROM film_actor WHERE film_id = $2 ON CONFLICT DO NOTHING")).
+					WithArgs(1, 2).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec(moveCollections).WithArgs(1, 2, 1).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec(deleteRows("collection_movies")).WithArgs(2).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec(moveTags).WithArgs(1, 2).WillReturnError(sql.ErrConnDone)
+				mock.ExpectRollback()
+			},
+			wantErr: sql.ErrConnDone,
+		},
+		{
+			name: "duplicate not found",
+			setup: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery(selectQuery).WithArgs(1).
+					WillReturnRows(sqlmock.NewRows(columns).AddRow(1, "Inception", "", 2010, 8.8, nil, 0, "", "", 0, "", updatedAt, nil))
+				mock.ExpectQuery(selectQuery).WithArgs(2).
+					WillReturnError(sql.ErrNoRows)
+				mock.ExpectRollback()
+			},
+			wantErr: domain.ErrMovieNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup()
+
+			got, err := repo.MergeMovies(context.Background(), 1, 2, domain.MovieDeleteRestrict)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestMovieRepository_GetMergedMovieID(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewMovie(db)
+	query := regexp.QuoteMeta("SELECT new_id FROM movie_merges WHERE old_id = $1")
+
+	mock.ExpectQuery(query).WithArgs(2).WillReturnRows(sqlmock.NewRows([]string{"new_id"}).AddRow(1))
+	newID, err := repo.GetMergedMovieID(context.Background(), 2)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, newID)
+
+	mock.ExpectQuery(query).WithArgs(3).WillReturnError(sql.ErrNoRows)
+	_, err = repo.GetMergedMovieID(context.Background(), 3)
+	assert.ErrorIs(t, err, domain.ErrMovieNotFound)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMovieRepository_GetUpcomingMovies(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewMovie(db)
+	query := regexp.QuoteMeta("SELECT id, title, description, release_year, rating, release_date, view_count, original_language, country, runtime_minutes, slug FROM films WHERE release_date > $1 ORDER BY release_date ASC, id ASC")
+	today := time.Date(2026, time.October, 16, 0, 0, 0, 0, time.UTC)
+	premiere := time.Date(2026, time.December, 18, 0, 0, 0, 0, time.UTC)
+
+	rows := sqlmock.NewRows([]string{"id", "title", "description", "release_year", "rating", "release_date", "view_count", "original_language", "country", "runtime_minutes", "slug"}).
+		AddRow(7, "Dune: Part Three", "", 2026, 0.0, premiere, 0, "", "", 0, "")
+	mock.ExpectQuery(query).WithArgs(today).WillReturnRows(rows)
+
+	movies, err := repo.GetUpcomingMovies(context.Background(), today)
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.Movie{{ID: 7, Title: "Dune: Part Three", ReleaseYear: 2026, ReleaseDate: &premiere}}, movies)
+
+	mock.ExpectQuery(query).WithArgs(today).WillReturnError(sql.ErrConnDone)
+	_, err = repo.GetUpcomingMovies(context.Background(), today)
+	assert.Error(t, err)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMovieRepository_IncrementViewCount(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewMovie(db)
+	query := regexp.QuoteMeta("UPDATE films SET view_count = view_count + $1 WHERE id = $2")
+
+	mock.ExpectExec(query).WithArgs(int64(3), 1).WillReturnResult(sqlmock.NewResult(0, 1))
+	assert.NoError(t, repo.IncrementViewCount(context.Background(), 1, 3))
+
+	mock.ExpectExec(query).WithArgs(int64(1), 999).WillReturnResult(sqlmock.NewResult(0, 0))
+	assert.ErrorIs(t, repo.IncrementViewCount(context.Background(), 999, 1), domain.ErrMovieNotFound)
+
+	mock.ExpectExec(query).WithArgs(int64(1), 1).WillReturnError(sql.ErrConnDone)
+	assert.Error(t, repo.IncrementViewCount(context.Background(), 1, 1))
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMovieRepository_GetPopularMovies(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewMovie(db)
+	query := regexp.QuoteMeta("SELECT id, title, description, release_year, rating, release_date, view_count, original_language, country, runtime_minutes, slug FROM films ORDER BY view_count DESC, id ASC LIMIT 2")
+
+	rows := sqlmock.NewRows([]string{"id", "title", "description", "release_year", "rating", "release_date", "view_count", "original_language", "country", "runtime_minutes", "slug"}).
+		AddRow(3, "Inception", "", 2010, 8.8, nil, 120, "", "", 0, "").
+		AddRow(1, "Alien", "", 1979, 8.5, nil, 75, "", "", 0, "")
+	mock.ExpectQuery(query).WillReturnRows(rows)
+
+	movies, err := repo.GetPopularMovies(context.Background(), 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.Movie{
+		{ID: 3, Title: "Inception", ReleaseYear: 2010, Rating: 8.8, ViewCount: 120},
+		{ID: 1, Title: "Alien", ReleaseYear: 1979, Rating: 8.5, ViewCount: 75},
+	}, movies)
+
+	mock.ExpectQuery(query).WillReturnError(sql.ErrConnDone)
+	_, err = repo.GetPopularMovies(context.Background(), 2)
+	assert.Error(t, err)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestNormalizeTitle(t *testing.T) {
+	assert.Equal(t, "the matrix", normalizeTitle("  The   Matrix "))
+	assert.Equal(t, "spider man no way home", normalizeTitle("Spider-Man: No Way Home"))
+	assert.Equal(t, "брат 2", normalizeTitle("Брат-2!"))
+}
+
+func TestMovieRepository_FindByNormalizedTitle(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewMovie(db)
+	query := regexp.QuoteMeta("SELECT id, title, description, release_year, rating, release_date, view_count, original_language, country, runtime_minutes, slug FROM films " +
+		"WHERE release_year = $1 AND btrim(regexp_replace(lower(title), '[^[:alnum:]]+', ' ', 'g')) = $2 ORDER BY id ASC LIMIT 1")
+
+	rows := sqlmock.NewRows([]string{"id", "title", "description", "release_year", "rating", "release_date", "view_count", "original_language", "country", "runtime_minutes", "slug"}).
+		AddRow(7, "The Matrix", "", 1999, 8.7, nil, 0, "", "", 0, "")
+	mock.ExpectQuery(query).WithArgs(1999, "the matrix").WillReturnRows(rows)
+
+	movie, err := repo.FindByNormalizedTitle(context.Background(), "the  Matrix.", 1999)
+	assert.NoError(t, err)
+	assert.Equal(t, 7, movie.ID)
+
+	mock.ExpectQuery(query).WithArgs(2003, "the matrix").WillReturnError(sql.ErrNoRows)
+	_, err = repo.FindByNormalizedTitle(context.Background(), "The Matrix", 2003)
+	assert.ErrorIs(t, err, domain.ErrMovieNotFound)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMovieRepository_SearchMoviesByTitleTrigram(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewMovie(db)
+	query := regexp.QuoteMeta("SELECT id, title, description, release_year, rating, release_date, view_count, original_language, country, runtime_minutes, slug FROM films " +
+		"WHERE (title ILIKE $1 OR title % $2) ORDER BY similarity(title, $3) DESC, id ASC")
+
+	rows := sqlmock.NewRows([]string{"id", "title", "description", "release_year", "rating", "release_date", "view_count", "original_language", "country", "runtime_minutes", "slug"}).
+		AddRow(7, "The Matrix", "", 1999, 8.7, nil, 0, "", "", 0, "")
+	mock.ExpectQuery(query).WithArgs("%matrx%", "matrx", "matrx").WillReturnRows(rows)
+
+	movies, err := repo.SearchMoviesByTitleTrigram(context.Background(), "matrx", domain.MovieFilter{})
+	assert.NoError(t, err)
+	require.Len(t, movies, 1)
+	assert.Equal(t, "The Matrix", movies[0].Title)
+
+	mock.ExpectQuery(query).WillReturnError(sql.ErrConnDone)
+	_, err = repo.SearchMoviesByTitleTrigram(context.Background(), "matrx", domain.MovieFilter{})
+	assert.Error(t, err)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
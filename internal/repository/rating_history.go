@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"cinematique/internal/domain"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+// AddRatingChange сохраняет изменение рейтинга фильма.
+func (m *movie) AddRatingChange(ctx context.Context, change domain.RatingChange) error {
+	start := time.Now()
+	operation := "add_rating_change"
+	queryType := "INSERT"
+	ctx, span := startSpan(ctx, operation, queryType)
+	defer span.End()
+
+	query, args, err := sq.Insert("rating_history").
+		Columns("film_id", "old_rating", "new_rating").
+		Values(change.MovieID, change.OldRating, change.NewRating).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return err
+	}
+	if _, err := m.db.ExecContext(ctx, query, args...); err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return err
+	}
+	dbQueryDurationSeconds.WithLabelValues(operation, queryType).Observe(time.Since(start).Seconds())
+	dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+	return nil
+}
+
+// GetRatingHistory возвращает изменения рейтинга фильма в хронологическом порядке.
+func (m *movie) GetRatingHistory(ctx context.Context, movieID int) ([]domain.RatingChange, error) {
+	start := time.Now()
+	operation := "get_rating_history"
+	queryType := "SELECT"
+	ctx, span := startSpan(ctx, operation, queryType)
+	defer span.End()
+
+	query, args, err := sq.Select("id", "film_id", "old_rating", "new_rating", "changed_at").
+		From("rating_history").
+		Where(sq.Eq{"film_id": movieID}).
+		OrderBy("changed_at ASC", "id ASC").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return nil, err
+	}
+	rows, err := m.replica.pick(m.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return nil, err
+	}
+	defer rows.Close()
+	history := make([]domain.RatingChange, 0)
+	for rows.Next() {
+		var change domain.RatingChange
+		var oldRating sql.NullFloat64
+		if err := rows.Scan(&change.ID, &change.MovieID, &oldRating, &change.NewRating, &change.ChangedAt); err != nil {
+			dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+			return nil, err
+		}
+		if oldRating.Valid {
+			change.OldRating = &oldRating.Float64
+		}
+		history = append(history, change)
+	}
+	if err := rows.Err(); err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return nil, err
+	}
+	dbQueryDurationSeconds.WithLabelValues(operation, queryType).Observe(time.Since(start).Seconds())
+	dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+	return history, nil
+}
@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"testing"
+	"time"
+
+	"cinematique/internal/domain"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMovieRepository_AddRatingChange(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewMovie(db)
+	query := regexp.QuoteMeta("INSERT INTO rating_history (film_id,old_rating,new_rating) VALUES ($1,$2,$3)")
+	oldRating := 8.5
+
+	mock.ExpectExec(query).
+		WithArgs(1, &oldRating, 8.8).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	err = repo.AddRatingChange(context.Background(), domain.RatingChange{MovieID: 1, OldRating: &oldRating, NewRating: 8.8})
+	assert.NoError(t, err)
+
+	mock.ExpectExec(query).
+		WithArgs(2, nil, 7.0).
+		WillReturnError(sql.ErrConnDone)
+	err = repo.AddRatingChange(context.Background(), domain.RatingChange{MovieID: 2, NewRating: 7.0})
+	assert.Error(t, err)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMovieRepository_GetRatingHistory(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewMovie(db)
+	query := regexp.QuoteMeta("SELECT id, film_id, old_rating, new_rating, changed_at FROM rating_history WHERE film_id = $1 ORDER BY changed_at ASC, id ASC")
+	seeded := time.Date(2023, time.May, 1, 10, 0, 0, 0, time.UTC)
+	edited := time.Date(2023, time.June, 1, 10, 0, 0, 0, time.UTC)
+
+	rows := sqlmock.NewRows([]string{"id", "film_id", "old_rating", "new_rating", "changed_at"}).
+		AddRow(1, 1, nil, 8.5, seeded).
+		AddRow(2, 1, 8.5, 8.8, edited)
+	mock.ExpectQuery(query).WithArgs(1).WillReturnRows(rows)
+
+	history, err := repo.GetRatingHistory(context.Background(), 1)
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	assert.Nil(t, history[0].OldRating)
+	assert.Equal(t, 8.5, history[0].NewRating)
+	require.NotNil(t, history[1].OldRating)
+	assert.Equal(t, 8.5, *history[1].OldRating)
+	assert.Equal(t, edited, history[1].ChangedAt)
+
+	mock.ExpectQuery(query).WithArgs(1).WillReturnError(sql.ErrConnDone)
+	_, err = repo.GetRatingHistory(context.Background(), 1)
+	assert.Error(t, err)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
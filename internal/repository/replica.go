@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"sync/atomic"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var dbReplicaHealthy = prometheus.NewGauge(
+	prometheus.GaugeOpts{
+		Name: "db_replica_healthy",
+		Help: "Whether read queries are served by the replica (1) or fall back to the primary (0).",
+	},
+)
+
+func init() {
+	prometheus.MustRegister(dbReplicaHealthy)
+}
+
+// ReadReplica — соединение с репликой для тяжёлых чтений (списки, поиск, сортировки).
+// Пока последняя проверка Check не прошла, чтения автоматически идут в основную БД.
+// Чтение по ID и всё, что выполняется после записи в том же запросе, остаётся на основной БД,
+// чтобы клиент сразу видел свои изменения несмотря на задержку репликации
+type ReadReplica struct {
+	db      *sql.DB
+	healthy atomic.Bool
+}
+
+// NewReadReplica оборачивает соединение с репликой; до первой проверки реплика считается доступной
+func NewReadReplica(db *sql.DB) *ReadReplica {
+	r := &ReadReplica{db: db}
+	r.healthy.Store(true)
+	dbReplicaHealthy.Set(1)
+	return r
+}
+
+// Check проверяет доступность реплики и переключает чтения между репликой и основной БД.
+// Предназначен для регистрации в health.Supervisor
+func (r *ReadReplica) Check(ctx context.Context) error {
+	err := r.db.PingContext(ctx)
+	healthy := err == nil
+	if r.healthy.Swap(healthy) != healthy {
+		if healthy {
+			log.Println("Read replica is available again, routing reads to replica")
+		} else {
+			log.Printf("Read replica is unavailable, falling back to primary: %v", err)
+		}
+	}
+	if healthy {
+		dbReplicaHealthy.Set(1)
+	} else {
+		dbReplicaHealthy.Set(0)
+	}
+	return err
+}
+
+// pick возвращает соединение для чтения: реплику, если она задана и доступна, иначе primary
+func (r *ReadReplica) pick(primary *sql.DB) *sql.DB {
+	if r == nil || !r.healthy.Load() {
+		return primary
+	}
+	return r.db
+}
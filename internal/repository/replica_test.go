@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var replicaMovieColumns = []string{"id", "title", "description", "release_year", "rating", "release_date", "view_count", "original_language", "country", "runtime_minutes", "slug"}
+
+func TestReadReplica_RoutesListingsToReplica(t *testing.T) {
+	primary, primaryMock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer primary.Close()
+	replicaDB, replicaMock, err := sqlmock.New(sqlmock.MonitorPingsOption(true))
+	require.NoError(t, err)
+	defer replicaDB.Close()
+
+	replica := NewReadReplica(replicaDB)
+	repo := NewMovie(primary).WithReadReplica(replica)
+
+	// Списки идут в реплику
+	replicaMock.ExpectQuery(`SELECT .* FROM films`).
+		WillReturnRows(sqlmock.NewRows(replicaMovieColumns).AddRow(1, "The Matrix", "", 1999, 8.7, nil, 0, "", "", 0, ""))
+	movies, err := repo.GetAll(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, movies, 1)
+
+	// Чтение по ID остаётся на основной БД
+	primaryMock.ExpectQuery(`SELECT .* FROM films WHERE id = \$1`).WithArgs(1).
+		WillReturnRows(sqlmock.NewRows(movieDetailColumns).AddRow(1, "The Matrix", "", 1999, 8.7, nil, 0, "", "", 0, "", time.Now(), nil))
+	_, err = repo.GetByID(context.Background(), 1)
+	require.NoError(t, err)
+
+	// Реплика недоступна — списки переключаются на основную БД
+	replicaMock.ExpectPing().WillReturnError(errors.New("connection refused"))
+	assert.Error(t, replica.Check(context.Background()))
+	primaryMock.ExpectQuery(`SELECT .* FROM films`).
+		WillReturnRows(sqlmock.NewRows(replicaMovieColumns))
+	_, err = repo.GetAll(context.Background())
+	require.NoError(t, err)
+
+	// Реплика вернулась
+	replicaMock.ExpectPing()
+	assert.NoError(t, replica.Check(context.Background()))
+	replicaMock.ExpectQuery(`SELECT .* FROM films`).
+		WillReturnRows(sqlmock.NewRows(replicaMovieColumns))
+	_, err = repo.GetAll(context.Background())
+	require.NoError(t, err)
+
+	assert.NoError(t, primaryMock.ExpectationsWereMet())
+	assert.NoError(t, replicaMock.ExpectationsWereMet())
+}
+
+func TestReadReplica_NilFallsBackToPrimary(t *testing.T) {
+	primary, primaryMock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer primary.Close()
+
+	var replica *ReadReplica
+	assert.Same(t, primary, replica.pick(primary))
+
+	primaryMock.ExpectQuery(`SELECT id, name, description FROM collections`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "description"}))
+	_, err = NewCollection(primary).WithReadReplica(nil).GetAll(context.Background())
+	require.NoError(t, err)
+	assert.NoError(t, primaryMock.ExpectationsWereMet())
+}
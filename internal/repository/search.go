@@ -0,0 +1,275 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"cinematique/internal/domain"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+// search — репозиторий поисковых подсказок и поисковой аналитики
+type search struct {
+	db      *sql.DB
+	replica *ReadReplica // реплика для подсказок; nil — всё читается из db
+}
+
+// NewSearch создаёт репозиторий поиска
+func NewSearch(db *sql.DB) *search {
+	return &search{db: db}
+}
+
+// WithReadReplica направляет запросы подсказок в реплику; запись статистики остаётся в db
+func (s *search) WithReadReplica(replica *ReadReplica) *search {
+	s.replica = replica
+	return s
+}
+
+// SimilarMovieTitles возвращает названия фильмов, похожие на query по триграммам
+func (s *search) SimilarMovieTitles(ctx context.Context, query string, limit int) ([]string, error) {
+	return s.similar(ctx, "similar_movie_titles", "films", "title", query, limit)
+}
+
+// SimilarActorNames возвращает имена актёров, похожие на query по триграммам
+func (s *search) SimilarActorNames(ctx context.Context, query string, limit int) ([]string, error) {
+	return s.similar(ctx, "similar_actor_names", "actors", "name", query, limit)
+}
+
+// similar выбирает различающиеся значения column, похожие на query (оператор % из pg_trgm,
+// порог pg_trgm.similarity_threshold, по умолчанию 0.3), по убыванию похожести
+func (s *search) similar(ctx context.Context, operation, table, column, query string, limit int) ([]string, error) {
+	start := time.Now()
+	queryType := "SELECT"
+	ctx, span := startSpan(ctx, operation, queryType)
+	defer span.End()
+
+	sqlQuery, args, err := sq.Select(column).
+		From(table).
+		Where(sq.Expr(column+" % ?", query)).
+		GroupBy(column).
+		OrderByClause("similarity("+column+", ?) DESC", query).
+		OrderBy(column).
+		Limit(uint64(limit)).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return nil, err
+	}
+	values, err := s.queryStrings(ctx, sqlQuery, args)
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return nil, err
+	}
+	dbQueryDurationSeconds.WithLabelValues(operation, queryType).Observe(time.Since(start).Seconds())
+	dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+	return values, nil
+}
+
+// SearchCatalog ищет фильмы по названию и актёров по имени одним запросом: подстрока или
+// триграммная похожесть, как в поиске фильмов по названию. Результаты обоих видов
+// упорядочены вместе по убыванию похожести на query
+func (s *search) SearchCatalog(ctx context.Context, query string, limit int) ([]domain.SearchHit, error) {
+	start := time.Now()
+	operation := "search_catalog"
+	queryType := "SELECT"
+	ctx, span := startSpan(ctx, operation, queryType)
+	defer span.End()
+
+	actorsQuery, actorsArgs, err := catalogHits(domain.SearchHitActor, "actors", "name", "NULL AS release_year", query).ToSql()
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return nil, err
+	}
+	hits := catalogHits(domain.SearchHitMovie, "films", "title", "release_year", query).
+		Suffix("UNION ALL "+actorsQuery, actorsArgs...)
+	sqlQuery, args, err := sq.Select("kind", "id", "name", "slug", "release_year", "score").
+		FromSelect(hits, "hits").
+		OrderBy("score DESC", "kind", "id").
+		Limit(uint64(limit)).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return nil, err
+	}
+	rows, err := s.replica.pick(s.db).QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return nil, err
+	}
+	defer rows.Close()
+	results := []domain.SearchHit{}
+	for rows.Next() {
+		var hit domain.SearchHit
+		var releaseYear sql.NullInt64
+		if err := rows.Scan(&hit.Kind, &hit.ID, &hit.Name, &hit.Slug, &releaseYear, &hit.Score); err != nil {
+			dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+			return nil, err
+		}
+		hit.ReleaseYear = int(releaseYear.Int64)
+		results = append(results, hit)
+	}
+	if err := rows.Err(); err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return nil, err
+	}
+	dbQueryDurationSeconds.WithLabelValues(operation, queryType).Observe(time.Since(start).Seconds())
+	dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+	return results, nil
+}
+
+// catalogHits выбирает из table записи, у которых column содержит query или похожа на него,
+// в общем для всех видов наборе колонок; yearColumn — колонка release_year или выражение с этим именем
+func catalogHits(kind, table, column, yearColumn, query string) sq.SelectBuilder {
+	return sq.Select("'"+kind+"' AS kind", "id", column+" AS name", "slug", yearColumn).
+		Column(sq.Expr("similarity("+column+", ?) AS score", query)).
+		From(table).
+		Where(sq.Or{
+			sq.Expr(column+" ILIKE ?", "%"+query+"%"),
+			sq.Expr(column+" % ?", query),
+		})
+}
+
+// RelatedQueries возвращает популярные запросы, похожие на query, которые хотя бы раз
+// вернули результаты. Сам query в список не входит
+func (s *search) RelatedQueries(ctx context.Context, query string, limit int) ([]string, error) {
+	start := time.Now()
+	operation := "related_search_queries"
+	queryType := "SELECT"
+	ctx, span := startSpan(ctx, operation, queryType)
+	defer span.End()
+
+	sqlQuery, args, err := sq.Select("query").
+		From("search_stats").
+		Where(sq.Expr("query % ?", query)).
+		Where(sq.NotEq{"query": query}).
+		GroupBy("query").
+		Having("SUM(searches) > SUM(zero_results)").
+		OrderBy("SUM(searches) DESC", "query").
+		Limit(uint64(limit)).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return nil, err
+	}
+	values, err := s.queryStrings(ctx, sqlQuery, args)
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return nil, err
+	}
+	dbQueryDurationSeconds.WithLabelValues(operation, queryType).Observe(time.Since(start).Seconds())
+	dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+	return values, nil
+}
+
+// RecordSearch учитывает поиск по запросу query в статистике за день at
+func (s *search) RecordSearch(ctx context.Context, query string, results int, at time.Time) error {
+	start := time.Now()
+	operation := "record_search"
+	queryType := "INSERT"
+	ctx, span := startSpan(ctx, operation, queryType)
+	defer span.End()
+
+	zero := 0
+	if results == 0 {
+		zero = 1
+	}
+	sqlQuery, args, err := sq.Insert("search_stats").
+		Columns("query", "day", "searches", "zero_results").
+		Values(query, at.UTC().Format("2006-01-02"), 1, zero).
+		Suffix("ON CONFLICT (query, day) DO UPDATE SET searches = search_stats.searches + 1, zero_results = search_stats.zero_results + EXCLUDED.zero_results").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return err
+	}
+	if _, err := s.db.ExecContext(ctx, sqlQuery, args...); err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return err
+	}
+	dbQueryDurationSeconds.WithLabelValues(operation, queryType).Observe(time.Since(start).Seconds())
+	dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+	return nil
+}
+
+// TopSearches возвращает самые частые запросы начиная с дня since
+func (s *search) TopSearches(ctx context.Context, since time.Time, limit int) ([]domain.SearchStat, error) {
+	return s.topSearches(ctx, "top_searches", since, limit, false)
+}
+
+// TopZeroResultSearches возвращает запросы, чаще всего не дававшие результатов, начиная с дня since
+func (s *search) TopZeroResultSearches(ctx context.Context, since time.Time, limit int) ([]domain.SearchStat, error) {
+	return s.topSearches(ctx, "top_zero_result_searches", since, limit, true)
+}
+
+// topSearches суммирует дневную статистику запросов за период. При zeroResults в выборку
+// попадают только запросы, которые хотя бы раз вернули пустой результат, и сортируются
+// они по числу таких поисков
+func (s *search) topSearches(ctx context.Context, operation string, since time.Time, limit int, zeroResults bool) ([]domain.SearchStat, error) {
+	start := time.Now()
+	queryType := "SELECT"
+	ctx, span := startSpan(ctx, operation, queryType)
+	defer span.End()
+
+	builder := sq.Select("query", "SUM(searches)", "SUM(zero_results)").
+		From("search_stats").
+		Where(sq.GtOrEq{"day": since.UTC().Format("2006-01-02")}).
+		GroupBy("query")
+	if zeroResults {
+		builder = builder.Having("SUM(zero_results) > 0").OrderBy("SUM(zero_results) DESC")
+	}
+	sqlQuery, args, err := builder.
+		OrderBy("SUM(searches) DESC", "query").
+		Limit(uint64(limit)).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return nil, err
+	}
+	rows, err := s.replica.pick(s.db).QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return nil, err
+	}
+	defer rows.Close()
+	stats := []domain.SearchStat{}
+	for rows.Next() {
+		var stat domain.SearchStat
+		if err := rows.Scan(&stat.Query, &stat.Searches, &stat.ZeroResults); err != nil {
+			dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+			return nil, err
+		}
+		stats = append(stats, stat)
+	}
+	if err := rows.Err(); err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return nil, err
+	}
+	dbQueryDurationSeconds.WithLabelValues(operation, queryType).Observe(time.Since(start).Seconds())
+	dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+	return stats, nil
+}
+
+// queryStrings выполняет запрос с одной текстовой колонкой
+func (s *search) queryStrings(ctx context.Context, query string, args []interface{}) ([]string, error) {
+	rows, err := s.replica.pick(s.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	values := make([]string, 0)
+	for rows.Next() {
+		var value string
+		if err := rows.Scan(&value); err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+	return values, rows.Err()
+}
@@ -0,0 +1,153 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"testing"
+	"time"
+
+	"cinematique/internal/domain"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearchRepository_SimilarMovieTitles(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewSearch(db)
+	query := regexp.QuoteMeta("SELECT title FROM films WHERE title % $1 GROUP BY title ORDER BY similarity(title, $2) DESC, title LIMIT 5")
+
+	mock.ExpectQuery(query).
+		WithArgs("matrx", "matrx").
+		WillReturnRows(sqlmock.NewRows([]string{"title"}).AddRow("The Matrix").AddRow("The Matrix Reloaded"))
+	titles, err := repo.SimilarMovieTitles(context.Background(), "matrx", 5)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"The Matrix", "The Matrix Reloaded"}, titles)
+
+	mock.ExpectQuery(query).WillReturnError(sql.ErrConnDone)
+	_, err = repo.SimilarMovieTitles(context.Background(), "matrx", 5)
+	assert.Error(t, err)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSearchRepository_SimilarActorNames(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewSearch(db)
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT name FROM actors WHERE name % $1 GROUP BY name ORDER BY similarity(name, $2) DESC, name LIMIT 3")).
+		WithArgs("keanu reevs", "keanu reevs").
+		WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow("Keanu Reeves"))
+
+	names, err := repo.SimilarActorNames(context.Background(), "keanu reevs", 3)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Keanu Reeves"}, names)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSearchRepository_RelatedQueries(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewSearch(db)
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT query FROM search_stats WHERE query % $1 AND query <> $2 "+
+		"GROUP BY query HAVING SUM(searches) > SUM(zero_results) ORDER BY SUM(searches) DESC, query LIMIT 5")).
+		WithArgs("matrx", "matrx").
+		WillReturnRows(sqlmock.NewRows([]string{"query"}).AddRow("matrix").AddRow("the matrix"))
+
+	related, err := repo.RelatedQueries(context.Background(), "matrx", 5)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"matrix", "the matrix"}, related)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSearchRepository_SearchCatalog(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewSearch(db)
+	query := regexp.QuoteMeta("SELECT kind, id, name, slug, release_year, score FROM (" +
+		"SELECT 'movie' AS kind, id, title AS name, slug, release_year, similarity(title, $1) AS score " +
+		"FROM films WHERE (title ILIKE $2 OR title % $3) " +
+		"UNION ALL SELECT 'actor' AS kind, id, name AS name, slug, NULL AS release_year, similarity(name, $4) AS score " +
+		"FROM actors WHERE (name ILIKE $5 OR name % $6)" +
+		") AS hits ORDER BY score DESC, kind, id LIMIT 10")
+
+	mock.ExpectQuery(query).
+		WithArgs("matrix", "%matrix%", "matrix", "matrix", "%matrix%", "matrix").
+		WillReturnRows(sqlmock.NewRows([]string{"kind", "id", "name", "slug", "release_year", "score"}).
+			AddRow("movie", 1, "The Matrix", "the-matrix-1999", 1999, 0.6).
+			AddRow("actor", 7, "Matrix Fan", "matrix-fan", nil, 0.4))
+	hits, err := repo.SearchCatalog(context.Background(), "matrix", 10)
+	require.NoError(t, err)
+	assert.Equal(t, []domain.SearchHit{
+		{Kind: domain.SearchHitMovie, ID: 1, Name: "The Matrix", Slug: "the-matrix-1999", ReleaseYear: 1999, Score: 0.6},
+		{Kind: domain.SearchHitActor, ID: 7, Name: "Matrix Fan", Slug: "matrix-fan", Score: 0.4},
+	}, hits)
+
+	mock.ExpectQuery(query).WillReturnError(sql.ErrConnDone)
+	_, err = repo.SearchCatalog(context.Background(), "matrix", 10)
+	assert.ErrorIs(t, err, sql.ErrConnDone)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSearchRepository_TopSearches(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewSearch(db)
+	since := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT query, SUM(searches), SUM(zero_results) FROM search_stats WHERE day >= $1 " +
+		"GROUP BY query ORDER BY SUM(searches) DESC, query LIMIT 10")).
+		WithArgs("2026-03-01").
+		WillReturnRows(sqlmock.NewRows([]string{"query", "sum", "sum"}).AddRow("matrix", 42, 0).AddRow("matrx", 7, 7))
+	top, err := repo.TopSearches(context.Background(), since, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []domain.SearchStat{{Query: "matrix", Searches: 42}, {Query: "matrx", Searches: 7, ZeroResults: 7}}, top)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT query, SUM(searches), SUM(zero_results) FROM search_stats WHERE day >= $1 " +
+		"GROUP BY query HAVING SUM(zero_results) > 0 ORDER BY SUM(zero_results) DESC, SUM(searches) DESC, query LIMIT 10")).
+		WithArgs("2026-03-01").
+		WillReturnRows(sqlmock.NewRows([]string{"query", "sum", "sum"}))
+	zero, err := repo.TopZeroResultSearches(context.Background(), since, 10)
+	require.NoError(t, err)
+	assert.NotNil(t, zero)
+	assert.Empty(t, zero)
+
+	mock.ExpectQuery("FROM search_stats").WillReturnError(sql.ErrConnDone)
+	_, err = repo.TopSearches(context.Background(), since, 10)
+	assert.ErrorIs(t, err, sql.ErrConnDone)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSearchRepository_RecordSearch(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewSearch(db)
+	insert := regexp.QuoteMeta("INSERT INTO search_stats (query,day,searches,zero_results) VALUES ($1,$2,$3,$4) " +
+		"ON CONFLICT (query, day) DO UPDATE SET searches = search_stats.searches + 1, zero_results = search_stats.zero_results + EXCLUDED.zero_results")
+	at := time.Date(2026, 3, 1, 23, 30, 0, 0, time.UTC)
+
+	mock.ExpectExec(insert).WithArgs("matrix", "2026-03-01", 1, 0).WillReturnResult(sqlmock.NewResult(0, 1))
+	require.NoError(t, repo.RecordSearch(context.Background(), "matrix", 4, at))
+
+	mock.ExpectExec(insert).WithArgs("matrx", "2026-03-01", 1, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+	require.NoError(t, repo.RecordSearch(context.Background(), "matrx", 0, at))
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,158 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"cinematique/internal/domain"
+	"cinematique/internal/slug"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+// slugQueryer — общая часть *sql.DB и *sql.Tx для подбора свободного slug
+type slugQueryer interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// movieSlugBase строит slug фильма из названия и года выпуска
+func movieSlugBase(movie domain.Movie) string {
+	year := ""
+	if movie.ReleaseYear > 0 {
+		year = strconv.Itoa(movie.ReleaseYear)
+	}
+	if base := slug.Make(movie.Title, year); base != "" {
+		return base
+	}
+	return "movie"
+}
+
+// actorSlugBase строит slug актёра из имени
+func actorSlugBase(actor domain.Actor) string {
+	if base := slug.Make(actor.Name); base != "" {
+		return base
+	}
+	return "actor"
+}
+
+// freeSlug возвращает base, если такой slug в table ещё не занят, иначе первый свободный
+// вариант base-2, base-3, ... Проверка выполняется до вставки, поэтому при одновременном
+// создании двух записей с одним slug вторую вставку отклонит уникальный индекс
+func freeSlug(ctx context.Context, db slugQueryer, table, base string) (string, error) {
+	query, args, err := sq.Select("slug").
+		From(table).
+		Where(sq.Or{sq.Eq{"slug": base}, sq.Like{"slug": base + "-%"}}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return "", fmt.Errorf("building slug query: %w", err)
+	}
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return "", fmt.Errorf("selecting slugs: %w", err)
+	}
+	defer rows.Close()
+	taken := make(map[string]bool)
+	for rows.Next() {
+		var existing string
+		if err := rows.Scan(&existing); err != nil {
+			return "", fmt.Errorf("scanning slug: %w", err)
+		}
+		taken[existing] = true
+	}
+	if err := rows.Err(); err != nil {
+		return "", fmt.Errorf("iterating slugs: %w", err)
+	}
+	if !taken[base] {
+		return base, nil
+	}
+	for n := 2; ; n++ {
+		candidate := base + "-" + strconv.Itoa(n)
+		if !taken[candidate] {
+			return candidate, nil
+		}
+	}
+}
+
+// ResolveSlug возвращает ID фильма по slug. Поиск идёт по уникальному индексу idx_films_slug;
+// если фильма с таким slug нет, slug ищется среди слитых фильмов и ведёт на фильм, в который
+// слит дубликат
+func (m *movie) ResolveSlug(ctx context.Context, movieSlug string) (int, error) {
+	id, err := resolveSlug(ctx, m.db, "resolve_movie_slug", "films", movieSlug)
+	if errors.Is(err, sql.ErrNoRows) {
+		id, err = m.resolveMergedSlug(ctx, movieSlug)
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, domain.ErrMovieNotFound
+	}
+	return id, err
+}
+
+// resolveMergedSlug возвращает ID фильма, в который слит фильм со slug movieSlug. Один slug
+// мог принадлежать нескольким слитым фильмам по очереди, тогда берётся последнее слияние
+func (m *movie) resolveMergedSlug(ctx context.Context, movieSlug string) (int, error) {
+	start := time.Now()
+	operation := "resolve_merged_movie_slug"
+	queryType := "SELECT"
+	ctx, span := startSpan(ctx, operation, queryType)
+	defer span.End()
+
+	query, args, err := sq.Select("new_id").
+		From("movie_merges").
+		Where(sq.Eq{"old_slug": movieSlug}).
+		OrderBy("merged_at DESC").
+		Limit(1).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return 0, fmt.Errorf("building query: %w", err)
+	}
+	var id int
+	if err := m.db.QueryRowContext(ctx, query, args...).Scan(&id); err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return 0, err
+	}
+	dbQueryDurationSeconds.WithLabelValues(operation, queryType).Observe(time.Since(start).Seconds())
+	dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+	return id, nil
+}
+
+// ResolveSlug возвращает ID актёра по slug. Поиск идёт по уникальному индексу idx_actors_slug
+func (a *actor) ResolveSlug(ctx context.Context, actorSlug string) (int, error) {
+	id, err := resolveSlug(ctx, a.db, "resolve_actor_slug", "actors", actorSlug)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, domain.ErrActorNotFound
+	}
+	return id, err
+}
+
+// resolveSlug выбирает ID строки table с заданным slug; sql.ErrNoRows, если такой нет
+func resolveSlug(ctx context.Context, db queryExecer, operation, table, value string) (int, error) {
+	start := time.Now()
+	queryType := "SELECT"
+	ctx, span := startSpan(ctx, operation, queryType)
+	defer span.End()
+
+	query, args, err := sq.Select("id").
+		From(table).
+		Where(sq.Eq{"slug": value}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return 0, fmt.Errorf("building query: %w", err)
+	}
+	var id int
+	if err := db.QueryRowContext(ctx, query, args...).Scan(&id); err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return 0, err
+	}
+	dbQueryDurationSeconds.WithLabelValues(operation, queryType).Observe(time.Since(start).Seconds())
+	dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+	return id, nil
+}
@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"testing"
+
+	"cinematique/internal/domain"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFreeSlug(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	query := regexp.QuoteMeta("SELECT slug FROM films WHERE (slug = $1 OR slug LIKE $2)")
+
+	mock.ExpectQuery(query).WithArgs("heat-1995", "heat-1995-%").
+		WillReturnRows(sqlmock.NewRows([]string{"slug"}))
+	got, err := freeSlug(context.Background(), db, "films", "heat-1995")
+	require.NoError(t, err)
+	assert.Equal(t, "heat-1995", got)
+
+	// Суффиксы занимаются по порядку, пропуски заполняются
+	mock.ExpectQuery(query).WithArgs("heat-1995", "heat-1995-%").
+		WillReturnRows(sqlmock.NewRows([]string{"slug"}).AddRow("heat-1995").AddRow("heat-1995-2").AddRow("heat-1995-4"))
+	got, err = freeSlug(context.Background(), db, "films", "heat-1995")
+	require.NoError(t, err)
+	assert.Equal(t, "heat-1995-3", got)
+
+	mock.ExpectQuery(query).WillReturnError(sql.ErrConnDone)
+	_, err = freeSlug(context.Background(), db, "films", "heat-1995")
+	assert.ErrorIs(t, err, sql.ErrConnDone)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSlugBase(t *testing.T) {
+	assert.Equal(t, "the-matrix-1999", movieSlugBase(domain.Movie{Title: "The Matrix", ReleaseYear: 1999}))
+	assert.Equal(t, "untitled", movieSlugBase(domain.Movie{Title: "Untitled"}))
+	assert.Equal(t, "movie", movieSlugBase(domain.Movie{Title: "?!"}))
+	assert.Equal(t, "keanu-reeves", actorSlugBase(domain.Actor{Name: "Keanu Reeves"}))
+	assert.Equal(t, "actor", actorSlugBase(domain.Actor{Name: "..."}))
+}
+
+func TestMovieRepository_ResolveSlug(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewMovie(db)
+	query := regexp.QuoteMeta("SELECT id FROM films WHERE slug = $1")
+
+	mock.ExpectQuery(query).WithArgs("the-matrix-1999").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+	id, err := repo.ResolveSlug(context.Background(), "the-matrix-1999")
+	require.NoError(t, err)
+	assert.Equal(t, 3, id)
+
+	// Slug слитого фильма ведёт на фильм, в который его слили
+	mergedQuery := regexp.QuoteMeta("SELECT new_id FROM movie_merges WHERE old_slug = $1 ORDER BY merged_at DESC LIMIT 1")
+	mock.ExpectQuery(query).WithArgs("the-matrix-1999-2").WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectQuery(mergedQuery).WithArgs("the-matrix-1999-2").WillReturnRows(sqlmock.NewRows([]string{"new_id"}).AddRow(3))
+	id, err = repo.ResolveSlug(context.Background(), "the-matrix-1999-2")
+	require.NoError(t, err)
+	assert.Equal(t, 3, id)
+
+	mock.ExpectQuery(query).WithArgs("missing").WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectQuery(mergedQuery).WithArgs("missing").WillReturnRows(sqlmock.NewRows([]string{"new_id"}))
+	_, err = repo.ResolveSlug(context.Background(), "missing")
+	assert.ErrorIs(t, err, domain.ErrMovieNotFound)
+
+	mock.ExpectQuery(query).WithArgs("lost").WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectQuery(mergedQuery).WithArgs("lost").WillReturnError(sql.ErrConnDone)
+	_, err = repo.ResolveSlug(context.Background(), "lost")
+	assert.ErrorIs(t, err, sql.ErrConnDone)
+
+	mock.ExpectQuery(query).WithArgs("broken").WillReturnError(sql.ErrConnDone)
+	_, err = repo.ResolveSlug(context.Background(), "broken")
+	assert.ErrorIs(t, err, sql.ErrConnDone)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestActorRepository_ResolveSlug(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewActor(db)
+	query := regexp.QuoteMeta("SELECT id FROM actors WHERE slug = $1")
+
+	mock.ExpectQuery(query).WithArgs("keanu-reeves").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+	id, err := repo.ResolveSlug(context.Background(), "keanu-reeves")
+	require.NoError(t, err)
+	assert.Equal(t, 7, id)
+
+	mock.ExpectQuery(query).WithArgs("nobody").WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	_, err = repo.ResolveSlug(context.Background(), "nobody")
+	assert.ErrorIs(t, err, domain.ErrActorNotFound)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
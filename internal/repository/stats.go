@@ -0,0 +1,147 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"cinematique/internal/domain"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+// stats — репозиторий агрегированных показателей каталога
+type stats struct {
+	db      *sql.DB      // соединение с базой данных
+	replica *ReadReplica // реплика для агрегатов; nil — всё читается из db
+}
+
+// NewStats создаёт репозиторий статистики
+func NewStats(db *sql.DB) *stats {
+	return &stats{db: db}
+}
+
+// WithReadReplica направляет агрегирующие запросы в реплику
+func (r *stats) WithReadReplica(replica *ReadReplica) *stats {
+	r.replica = replica
+	return r
+}
+
+// CatalogTotals возвращает число фильмов, число актёров и средний рейтинг фильмов
+func (r *stats) CatalogTotals(ctx context.Context) (movies, actors int, averageRating float64, err error) {
+	start := time.Now()
+	operation := "catalog_totals"
+	queryType := "SELECT"
+	ctx, span := startSpan(ctx, operation, queryType)
+	defer span.End()
+
+	query, args, err := sq.Select(
+		"(SELECT COUNT(*) FROM films)",
+		"(SELECT COUNT(*) FROM actors)",
+		"(SELECT COALESCE(AVG(rating), 0) FROM films)",
+	).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return 0, 0, 0, fmt.Errorf("building query: %w", err)
+	}
+	if err := r.replica.pick(r.db).QueryRowContext(ctx, query, args...).Scan(&movies, &actors, &averageRating); err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return 0, 0, 0, err
+	}
+	dbQueryDurationSeconds.WithLabelValues(operation, queryType).Observe(time.Since(start).Seconds())
+	dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+	return movies, actors, averageRating, nil
+}
+
+// MoviesPerDecade возвращает число фильмов по десятилетиям выхода; фильмы без года пропускаются
+func (r *stats) MoviesPerDecade(ctx context.Context) ([]domain.DecadeCount, error) {
+	start := time.Now()
+	operation := "movies_per_decade"
+	queryType := "SELECT"
+	ctx, span := startSpan(ctx, operation, queryType)
+	defer span.End()
+
+	query, args, err := sq.Select("(release_year / 10) * 10 AS decade", "COUNT(*)").
+		From("films").
+		Where(sq.Gt{"release_year": 0}).
+		GroupBy("decade").
+		OrderBy("decade ASC").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return nil, fmt.Errorf("building query: %w", err)
+	}
+	rows, err := r.replica.pick(r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return nil, err
+	}
+	defer rows.Close()
+
+	decades := make([]domain.DecadeCount, 0)
+	for rows.Next() {
+		var d domain.DecadeCount
+		if err := rows.Scan(&d.Decade, &d.Movies); err != nil {
+			dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+			return nil, err
+		}
+		decades = append(decades, d)
+	}
+	if err := rows.Err(); err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return nil, err
+	}
+	dbQueryDurationSeconds.WithLabelValues(operation, queryType).Observe(time.Since(start).Seconds())
+	dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+	return decades, nil
+}
+
+// TopActorsByFilmCount возвращает limit актёров с наибольшим числом фильмов;
+// при равенстве выше тот, кто раньше добавлен в каталог
+func (r *stats) TopActorsByFilmCount(ctx context.Context, limit int) ([]domain.ActorFilmCount, error) {
+	start := time.Now()
+	operation := "top_actors_by_film_count"
+	queryType := "SELECT"
+	ctx, span := startSpan(ctx, operation, queryType)
+	defer span.End()
+
+	query, args, err := sq.Select("a.id", "a.name", "COUNT(fa.film_id) AS movies").
+		From("actors a").
+		Join("film_actor fa ON fa.actor_id = a.id").
+		GroupBy("a.id", "a.name").
+		OrderBy("movies DESC", "a.id ASC").
+		Limit(uint64(limit)).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return nil, fmt.Errorf("building query: %w", err)
+	}
+	rows, err := r.replica.pick(r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return nil, err
+	}
+	defer rows.Close()
+
+	actors := make([]domain.ActorFilmCount, 0, limit)
+	for rows.Next() {
+		var a domain.ActorFilmCount
+		if err := rows.Scan(&a.ActorID, &a.Name, &a.Movies); err != nil {
+			dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+			return nil, err
+		}
+		actors = append(actors, a)
+	}
+	if err := rows.Err(); err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return nil, err
+	}
+	dbQueryDurationSeconds.WithLabelValues(operation, queryType).Observe(time.Since(start).Seconds())
+	dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+	return actors, nil
+}
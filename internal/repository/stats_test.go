@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"cinematique/internal/domain"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatsRepository_CatalogTotals(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT (SELECT COUNT(*) FROM films), (SELECT COUNT(*) FROM actors), (SELECT COALESCE(AVG(rating), 0) FROM films)")).
+		WillReturnRows(sqlmock.NewRows([]string{"movies", "actors", "avg"}).AddRow(12, 40, 7.25))
+
+	movies, actors, avg, err := NewStats(db).CatalogTotals(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 12, movies)
+	assert.Equal(t, 40, actors)
+	assert.Equal(t, 7.25, avg)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestStatsRepository_MoviesPerDecade(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT (release_year / 10) * 10 AS decade, COUNT(*) FROM films WHERE release_year > $1 GROUP BY decade ORDER BY decade ASC")).
+		WithArgs(0).
+		WillReturnRows(sqlmock.NewRows([]string{"decade", "count"}).AddRow(1990, 3).AddRow(2000, 5))
+
+	decades, err := NewStats(db).MoviesPerDecade(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []domain.DecadeCount{{Decade: 1990, Movies: 3}, {Decade: 2000, Movies: 5}}, decades)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestStatsRepository_TopActorsByFilmCount(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT a.id, a.name, COUNT(fa.film_id) AS movies FROM actors a JOIN film_actor fa ON fa.actor_id = a.id GROUP BY a.id, a.name ORDER BY movies DESC, a.id ASC LIMIT 10")).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "movies"}).AddRow(1, "Keanu Reeves", 4).AddRow(2, "Carrie-Anne Moss", 3))
+
+	actors, err := NewStats(db).TopActorsByFilmCount(context.Background(), 10)
+	require.NoError(t, err)
+	assert.Equal(t, []domain.ActorFilmCount{
+		{ActorID: 1, Name: "Keanu Reeves", Movies: 4},
+		{ActorID: 2, Name: "Carrie-Anne Moss", Movies: 3},
+	}, actors)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
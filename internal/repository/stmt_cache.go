@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// maxCachedStmts ограничивает число подготовленных выражений в одном кэше. Горячие запросы
+// собираются из небольшого числа вариантов (фильтры поиска включаются по отдельности),
+// поэтому лимит срабатывает только при ошибке, когда в текст запроса попадают значения
+const maxCachedStmts = 128
+
+var dbPreparedStatements = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "db_prepared_statements_total",
+		Help: "Lookups in the prepared statement cache by result (hit, prepared, skipped).",
+	},
+	[]string{"result"},
+)
+
+func init() {
+	prometheus.MustRegister(dbPreparedStatements)
+}
+
+// stmtCache хранит подготовленные выражения горячих запросов, чтобы СУБД не разбирала
+// и не планировала их заново при каждом вызове. Выражение готовится отдельно для каждого
+// соединения (основной БД и реплики), database/sql сам переподготавливает его на новых
+// соединениях пула. Нулевой *stmtCache выполняет запросы без подготовки
+type stmtCache struct {
+	mu    sync.RWMutex
+	stmts map[stmtKey]*sql.Stmt
+}
+
+type stmtKey struct {
+	db    *sql.DB
+	query string
+}
+
+func newStmtCache() *stmtCache {
+	return &stmtCache{stmts: make(map[stmtKey]*sql.Stmt)}
+}
+
+// prepared возвращает подготовленное выражение для query или nil, если кэш выключен,
+// заполнен или выражение не удалось подготовить: тогда запрос выполняется как обычно
+func (c *stmtCache) prepared(ctx context.Context, db *sql.DB, query string) *sql.Stmt {
+	if c == nil {
+		return nil
+	}
+	key := stmtKey{db: db, query: query}
+	c.mu.RLock()
+	stmt, ok := c.stmts[key]
+	c.mu.RUnlock()
+	if ok {
+		dbPreparedStatements.WithLabelValues("hit").Inc()
+		return stmt
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if stmt, ok := c.stmts[key]; ok {
+		dbPreparedStatements.WithLabelValues("hit").Inc()
+		return stmt
+	}
+	if len(c.stmts) >= maxCachedStmts {
+		dbPreparedStatements.WithLabelValues("skipped").Inc()
+		return nil
+	}
+	stmt, err := db.PrepareContext(ctx, query)
+	if err != nil {
+		log.Printf("Error preparing statement, executing without preparation: %v", err)
+		dbPreparedStatements.WithLabelValues("skipped").Inc()
+		return nil
+	}
+	c.stmts[key] = stmt
+	dbPreparedStatements.WithLabelValues("prepared").Inc()
+	return stmt
+}
+
+// queryContext выполняет запрос, возвращающий строки, через подготовленное выражение
+func (c *stmtCache) queryContext(ctx context.Context, db *sql.DB, query string, args ...interface{}) (*sql.Rows, error) {
+	if stmt := c.prepared(ctx, db, query); stmt != nil {
+		return stmt.QueryContext(ctx, args...)
+	}
+	return db.QueryContext(ctx, query, args...)
+}
+
+// queryRowContext выполняет запрос, возвращающий одну строку, через подготовленное выражение
+func (c *stmtCache) queryRowContext(ctx context.Context, db *sql.DB, query string, args ...interface{}) *sql.Row {
+	if stmt := c.prepared(ctx, db, query); stmt != nil {
+		return stmt.QueryRowContext(ctx, args...)
+	}
+	return db.QueryRowContext(ctx, query, args...)
+}
+
+// execContext выполняет изменяющий запрос через подготовленное выражение
+func (c *stmtCache) execContext(ctx context.Context, db *sql.DB, query string, args ...interface{}) (sql.Result, error) {
+	if stmt := c.prepared(ctx, db, query); stmt != nil {
+		return stmt.ExecContext(ctx, args...)
+	}
+	return db.ExecContext(ctx, query, args...)
+}
+
+// Close закрывает все подготовленные выражения
+func (c *stmtCache) Close() error {
+	if c == nil {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var firstErr error
+	for key, stmt := range c.stmts {
+		if err := stmt.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+		delete(c.stmts, key)
+	}
+	return firstErr
+}
@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var dbQueryTimeoutsTotal = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "db_query_timeouts_total",
+		Help: "Total number of database queries cancelled by the repository query timeout.",
+	},
+	[]string{"operation", "query_type"},
+)
+
+func init() {
+	prometheus.MustRegister(dbQueryTimeoutsTotal)
+}
+
+// QueryTimeouts — предельное время одного вызова репозитория: Read для SELECT,
+// Write для INSERT, UPDATE и DELETE (вместе с транзакцией, если она есть). 0 — без ограничения.
+// Без таймаута медленная БД держит горутины запросов, пока клиент не отключится
+type QueryTimeouts struct {
+	Read  time.Duration
+	Write time.Duration
+}
+
+var queryTimeouts atomic.Pointer[QueryTimeouts]
+
+func init() {
+	queryTimeouts.Store(&QueryTimeouts{})
+}
+
+// SetQueryTimeouts задаёт таймауты запросов для всех репозиториев
+func SetQueryTimeouts(timeouts QueryTimeouts) {
+	queryTimeouts.Store(&timeouts)
+}
+
+func queryTimeout(queryType string) time.Duration {
+	timeouts := queryTimeouts.Load()
+	if queryType == "SELECT" {
+		return timeouts.Read
+	}
+	return timeouts.Write
+}
+
+// querySpan — span запроса, который при завершении снимает таймаут запроса
+// и учитывает запрос в db_query_timeouts_total, если таймаут истёк
+type querySpan struct {
+	trace.Span
+	ctx       context.Context
+	parent    context.Context
+	cancel    context.CancelFunc
+	operation string
+	queryType string
+}
+
+func (s *querySpan) End(options ...trace.SpanEndOption) {
+	// Отмену или истечение срока вызывающего запроса таймаутом репозитория не считаем
+	if errors.Is(s.ctx.Err(), context.DeadlineExceeded) && s.parent.Err() == nil {
+		dbQueryTimeoutsTotal.WithLabelValues(s.operation, s.queryType).Inc()
+		s.Span.SetStatus(codes.Error, "query timeout")
+	}
+	s.cancel()
+	s.Span.End(options...)
+}
+
+// withQueryTimeout ограничивает контекст запроса таймаутом для queryType
+func withQueryTimeout(ctx context.Context, span trace.Span, operation, queryType string) (context.Context, trace.Span) {
+	timeout := queryTimeout(queryType)
+	if timeout <= 0 {
+		return ctx, span
+	}
+	timeoutCtx, cancel := context.WithTimeout(ctx, timeout)
+	return timeoutCtx, &querySpan{
+		Span:      span,
+		ctx:       timeoutCtx,
+		parent:    ctx,
+		cancel:    cancel,
+		operation: operation,
+		queryType: queryType,
+	}
+}
@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryTimeouts(t *testing.T) {
+	SetQueryTimeouts(QueryTimeouts{Read: 20 * time.Millisecond, Write: time.Second})
+	defer SetQueryTimeouts(QueryTimeouts{})
+
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	repo := NewMovie(db)
+	timeouts := dbQueryTimeoutsTotal.WithLabelValues("get_movie_by_id", "SELECT")
+	before := testutil.ToFloat64(timeouts)
+
+	mock.ExpectQuery(`SELECT .* FROM films WHERE id = \$1`).WithArgs(1).
+		WillDelayFor(time.Second).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+
+	started := time.Now()
+	_, err = repo.GetByID(context.Background(), 1)
+	assert.Error(t, err)
+	assert.Less(t, time.Since(started), 500*time.Millisecond, "query is cancelled by the read timeout")
+	assert.Equal(t, before+1, testutil.ToFloat64(timeouts))
+}
+
+func TestQueryTimeouts_CallerCancelIsNotCounted(t *testing.T) {
+	SetQueryTimeouts(QueryTimeouts{Read: time.Second})
+	defer SetQueryTimeouts(QueryTimeouts{})
+
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	timeouts := dbQueryTimeoutsTotal.WithLabelValues("get_movie_by_id", "SELECT")
+	before := testutil.ToFloat64(timeouts)
+
+	mock.ExpectQuery(`SELECT .* FROM films WHERE id = \$1`).WithArgs(1).
+		WillDelayFor(time.Second).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err = NewMovie(db).GetByID(ctx, 1)
+	assert.Error(t, err)
+	assert.Equal(t, before, testutil.ToFloat64(timeouts))
+}
+
+func TestQueryTimeout_ByQueryType(t *testing.T) {
+	SetQueryTimeouts(QueryTimeouts{Read: 2 * time.Second, Write: 5 * time.Second})
+	defer SetQueryTimeouts(QueryTimeouts{})
+
+	assert.Equal(t, 2*time.Second, queryTimeout("SELECT"))
+	assert.Equal(t, 5*time.Second, queryTimeout("UPDATE"))
+
+	// Без таймаута контекст вызывающего не меняется
+	SetQueryTimeouts(QueryTimeouts{})
+	ctx, span := startSpan(context.Background(), "get_movie_by_id", "SELECT")
+	defer span.End()
+	_, hasDeadline := ctx.Deadline()
+	assert.False(t, hasDeadline)
+}
@@ -0,0 +1,33 @@
+package repository
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracer берётся из глобального провайдера при каждом вызове, а не кэшируется
+// при инициализации пакета, чтобы замена провайдера (в тестах) вступала в силу
+func tracer() trace.Tracer { return otel.Tracer("cinematique/internal/repository") }
+
+// startSpan открывает span запроса к БД с теми же operation и queryType, что и в метриках,
+// и ограничивает возвращённый контекст таймаутом запроса (см. SetQueryTimeouts).
+// Таймаут снимается в span.End, поэтому вызывающий обязан завершить span
+func startSpan(ctx context.Context, operation, queryType string) (context.Context, trace.Span) {
+	ctx, span := startStreamSpan(ctx, operation, queryType)
+	return withQueryTimeout(ctx, span, operation, queryType)
+}
+
+// startStreamSpan открывает span без таймаута запроса — для курсоров по всей таблице,
+// время чтения которых зависит от скорости потребителя, а не от базы
+func startStreamSpan(ctx context.Context, operation, queryType string) (context.Context, trace.Span) {
+	return tracer().Start(ctx, "db."+operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("db.operation.name", queryType),
+			attribute.String("db.query.summary", operation),
+		),
+	)
+}
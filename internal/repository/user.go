@@ -19,7 +19,7 @@ func NewUserRepository(db *sql.DB) *UserRepository {
 	return &UserRepository{db: db}
 }
 
-// CreateUser создаёт нового пользователя.
+// CreateUser создаёт нового пользователя. Занятые имя или email возвращают domain.ErrUserAlreadyExists
 func (r *UserRepository) CreateUser(user domain.User) (int, error) {
 	start := time.Now()
 	operation := "create_user"
@@ -42,7 +42,7 @@ func (r *UserRepository) CreateUser(user domain.User) (int, error) {
 	if err != nil {
 		log.Printf("Error creating user: %v", err)
 		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
-		return 0, err
+		return 0, uniqueViolation(err, domain.ErrUserAlreadyExists)
 	}
 	dbQueryDurationSeconds.WithLabelValues(operation, queryType).Observe(time.Since(start).Seconds())
 	dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
@@ -56,8 +56,9 @@ func (r *UserRepository) GetByUsername(username string) (domain.User, error) {
 	queryType := "SELECT"
 
 	var user domain.User
-	
-	query, args, err := sq.Select("id", "username", "email", "password_hash", "role").
+	var lockedUntil sql.NullTime
+
+	query, args, err := sq.Select("id", "username", "email", "password_hash", "role", "failed_logins", "locked_until", "token_version").
 		From("users").
 		Where(sq.Eq{"username": username}).
 		PlaceholderFormat(sq.Dollar).
@@ -69,7 +70,7 @@ func (r *UserRepository) GetByUsername(username string) (domain.User, error) {
 	}
 
 	err = r.db.QueryRow(query, args...).
-		Scan(&user.ID, &user.Username, &user.Email, &user.PasswordHash, &user.Role)
+		Scan(&user.ID, &user.Username, &user.Email, &user.PasswordHash, &user.Role, &user.FailedLogins, &lockedUntil, &user.TokenVersion)
 
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -80,6 +81,9 @@ func (r *UserRepository) GetByUsername(username string) (domain.User, error) {
 		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
 		return domain.User{}, err
 	}
+	if lockedUntil.Valid {
+		user.LockedUntil = &lockedUntil.Time
+	}
 
 	dbQueryDurationSeconds.WithLabelValues(operation, queryType).Observe(time.Since(start).Seconds())
 	dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
@@ -93,8 +97,9 @@ func (r *UserRepository) GetByID(id int) (domain.User, error) {
 	queryType := "SELECT"
 
 	var user domain.User
+	var lockedUntil sql.NullTime
 
-	query, args, err := sq.Select("id", "username", "email", "password_hash", "role").
+	query, args, err := sq.Select("id", "username", "email", "password_hash", "role", "failed_logins", "locked_until", "token_version").
 		From("users").
 		Where(sq.Eq{"id": id}).
 		PlaceholderFormat(sq.Dollar).
@@ -106,7 +111,7 @@ func (r *UserRepository) GetByID(id int) (domain.User, error) {
 	}
 
 	err = r.db.QueryRow(query, args...).
-		Scan(&user.ID, &user.Username, &user.Email, &user.PasswordHash, &user.Role)
+		Scan(&user.ID, &user.Username, &user.Email, &user.PasswordHash, &user.Role, &user.FailedLogins, &lockedUntil, &user.TokenVersion)
 
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -117,8 +122,378 @@ func (r *UserRepository) GetByID(id int) (domain.User, error) {
 		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
 		return domain.User{}, err
 	}
+	if lockedUntil.Valid {
+		user.LockedUntil = &lockedUntil.Time
+	}
 
 	dbQueryDurationSeconds.WithLabelValues(operation, queryType).Observe(time.Since(start).Seconds())
 	dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
 	return user, nil
 }
+
+// SetRole меняет роль пользователя и увеличивает версию его токенов, чтобы токены доступа
+// со старой ролью перестали приниматься. Возвращает новую версию или sql.ErrNoRows
+func (r *UserRepository) SetRole(id int, role string) (int, error) {
+	start := time.Now()
+	operation := "set_user_role"
+	queryType := "UPDATE"
+
+	query, args, err := sq.Update("users").
+		Set("role", role).
+		Set("token_version", sq.Expr("token_version + 1")).
+		Where(sq.Eq{"id": id}).
+		Suffix("RETURNING token_version").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return 0, err
+	}
+
+	var version int
+	if err := r.db.QueryRow(query, args...).Scan(&version); err != nil {
+		if err != sql.ErrNoRows {
+			log.Printf("Error setting user role: %v", err)
+		}
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return 0, err
+	}
+
+	dbQueryDurationSeconds.WithLabelValues(operation, queryType).Observe(time.Since(start).Seconds())
+	dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+	return version, nil
+}
+
+// GetTokenVersion возвращает текущую версию токенов пользователя или sql.ErrNoRows
+func (r *UserRepository) GetTokenVersion(id int) (int, error) {
+	start := time.Now()
+	operation := "get_user_token_version"
+	queryType := "SELECT"
+
+	query, args, err := sq.Select("token_version").
+		From("users").
+		Where(sq.Eq{"id": id}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return 0, err
+	}
+
+	var version int
+	if err := r.db.QueryRow(query, args...).Scan(&version); err != nil {
+		if err != sql.ErrNoRows {
+			log.Printf("Error getting user token version: %v", err)
+		}
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return 0, err
+	}
+
+	dbQueryDurationSeconds.WithLabelValues(operation, queryType).Observe(time.Since(start).Seconds())
+	dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+	return version, nil
+}
+
+// RegisterFailedLogin увеличивает счётчик неудачных входов. Когда счётчик достигает
+// maxFailures, учётная запись блокируется до lockUntil, а счётчик обнуляется.
+// Счётчик и блокировка меняются одним запросом, поэтому параллельные попытки не теряются.
+// Возвращает новое значение счётчика и момент окончания блокировки (nil, если её нет)
+func (r *UserRepository) RegisterFailedLogin(id, maxFailures int, lockUntil time.Time) (int, *time.Time, error) {
+	start := time.Now()
+	operation := "register_failed_login"
+	queryType := "UPDATE"
+
+	query, args, err := sq.Update("users").
+		Set("failed_logins", sq.Expr("CASE WHEN failed_logins + 1 >= ? THEN 0 ELSE failed_logins + 1 END", maxFailures)).
+		Set("locked_until", sq.Expr("CASE WHEN failed_logins + 1 >= ? THEN ? ELSE locked_until END", maxFailures, lockUntil)).
+		Where(sq.Eq{"id": id}).
+		Suffix("RETURNING failed_logins, locked_until").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return 0, nil, err
+	}
+
+	var failed int
+	var lockedUntil sql.NullTime
+	if err := r.db.QueryRow(query, args...).Scan(&failed, &lockedUntil); err != nil {
+		log.Printf("Error registering failed login: %v", err)
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return 0, nil, err
+	}
+
+	dbQueryDurationSeconds.WithLabelValues(operation, queryType).Observe(time.Since(start).Seconds())
+	dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+	if !lockedUntil.Valid {
+		return failed, nil, nil
+	}
+	return failed, &lockedUntil.Time, nil
+}
+
+// ResetFailedLogins обнуляет счётчик неудачных входов и снимает блокировку
+func (r *UserRepository) ResetFailedLogins(id int) error {
+	start := time.Now()
+	operation := "reset_failed_logins"
+	queryType := "UPDATE"
+
+	query, args, err := sq.Update("users").
+		Set("failed_logins", 0).
+		Set("locked_until", nil).
+		Where(sq.Eq{"id": id}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return err
+	}
+
+	if _, err := r.db.Exec(query, args...); err != nil {
+		log.Printf("Error resetting failed logins: %v", err)
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return err
+	}
+
+	dbQueryDurationSeconds.WithLabelValues(operation, queryType).Observe(time.Since(start).Seconds())
+	dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+	return nil
+}
+
+// UpdatePasswordHash заменяет хэш пароля, если он не менялся с момента чтения (oldHash):
+// пересчитанный при входе хэш не должен затереть пароль, сменённый параллельным запросом.
+// Если хэш уже другой, ничего не меняется и ошибка не возвращается
+func (r *UserRepository) UpdatePasswordHash(id int, oldHash, newHash string) error {
+	start := time.Now()
+	operation := "update_password_hash"
+	queryType := "UPDATE"
+
+	query, args, err := sq.Update("users").
+		Set("password_hash", newHash).
+		Where(sq.Eq{"id": id, "password_hash": oldHash}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return err
+	}
+
+	if _, err := r.db.Exec(query, args...); err != nil {
+		log.Printf("Error updating password hash: %v", err)
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return err
+	}
+
+	dbQueryDurationSeconds.WithLabelValues(operation, queryType).Observe(time.Since(start).Seconds())
+	dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+	return nil
+}
+
+// UpdateUser сохраняет изменения учётной записи. Возвращает sql.ErrNoRows, если пользователя нет,
+// и domain.ErrEmailTaken, если email уже занят другой учётной записью
+func (r *UserRepository) UpdateUser(id int, update domain.UserUpdate) error {
+	start := time.Now()
+	operation := "update_user"
+	queryType := "UPDATE"
+
+	builder := sq.Update("users").Where(sq.Eq{"id": id}).PlaceholderFormat(sq.Dollar)
+	if update.Email != nil {
+		builder = builder.Set("email", *update.Email)
+	}
+	if update.PasswordHash != nil {
+		builder = builder.Set("password_hash", *update.PasswordHash)
+	}
+	query, args, err := builder.ToSql()
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return err
+	}
+
+	result, err := r.db.Exec(query, args...)
+	if err != nil {
+		log.Printf("Error updating user: %v", err)
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return uniqueViolation(err, domain.ErrEmailTaken)
+	}
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return sql.ErrNoRows
+	}
+
+	dbQueryDurationSeconds.WithLabelValues(operation, queryType).Observe(time.Since(start).Seconds())
+	dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+	return nil
+}
+
+// DeleteUser удаляет пользователя. Возвращает sql.ErrNoRows, если пользователя нет
+func (r *UserRepository) DeleteUser(id int) error {
+	start := time.Now()
+	operation := "delete_user"
+	queryType := "DELETE"
+
+	query, args, err := sq.Delete("users").
+		Where(sq.Eq{"id": id}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return err
+	}
+
+	result, err := r.db.Exec(query, args...)
+	if err != nil {
+		log.Printf("Error deleting user: %v", err)
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return err
+	}
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return sql.ErrNoRows
+	}
+
+	dbQueryDurationSeconds.WithLabelValues(operation, queryType).Observe(time.Since(start).Seconds())
+	dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+	return nil
+}
+
+// GetByEmail возвращает пользователя по email без учёта регистра.
+// Если адрес указан у нескольких пользователей, возвращается зарегистрированный первым
+func (r *UserRepository) GetByEmail(email string) (domain.User, error) {
+	start := time.Now()
+	operation := "get_user_by_email"
+	queryType := "SELECT"
+
+	var user domain.User
+	var lockedUntil sql.NullTime
+
+	query, args, err := sq.Select("id", "username", "email", "password_hash", "role", "failed_logins", "locked_until", "token_version").
+		From("users").
+		Where("LOWER(email) = LOWER(?)", email).
+		OrderBy("id").
+		Limit(1).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return domain.User{}, err
+	}
+
+	err = r.db.QueryRow(query, args...).
+		Scan(&user.ID, &user.Username, &user.Email, &user.PasswordHash, &user.Role, &user.FailedLogins, &lockedUntil, &user.TokenVersion)
+	if err != nil {
+		if err != sql.ErrNoRows {
+			log.Printf("Error getting user by email: %v", err)
+		}
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return domain.User{}, err
+	}
+	if lockedUntil.Valid {
+		user.LockedUntil = &lockedUntil.Time
+	}
+
+	dbQueryDurationSeconds.WithLabelValues(operation, queryType).Observe(time.Since(start).Seconds())
+	dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+	return user, nil
+}
+
+// CreatePasswordResetToken сохраняет хэш токена сброса пароля, действующего до expiresAt
+func (r *UserRepository) CreatePasswordResetToken(userID int, tokenHash string, expiresAt time.Time) error {
+	start := time.Now()
+	operation := "create_password_reset_token"
+	queryType := "INSERT"
+
+	query, args, err := sq.Insert("password_reset_tokens").
+		Columns("user_id", "token_hash", "expires_at").
+		Values(userID, tokenHash, expiresAt).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return err
+	}
+
+	if _, err := r.db.Exec(query, args...); err != nil {
+		log.Printf("Error creating password reset token: %v", err)
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return err
+	}
+
+	dbQueryDurationSeconds.WithLabelValues(operation, queryType).Observe(time.Since(start).Seconds())
+	dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+	return nil
+}
+
+// ResetPassword в одной транзакции гасит токен сброса, меняет хэш пароля, снимает
+// блокировку входа, увеличивает версию JWT пользователя (выпущенные ранее токены перестают
+// приниматься) и гасит остальные токены сброса. Возвращает ID пользователя
+// или sql.ErrNoRows, если токена нет, он истёк к моменту now или уже использован
+func (r *UserRepository) ResetPassword(tokenHash, passwordHash string, now time.Time) (int, error) {
+	start := time.Now()
+	operation := "reset_password"
+	queryType := "UPDATE"
+
+	userID, err := r.resetPasswordTx(tokenHash, passwordHash, now)
+	if err != nil {
+		if err != sql.ErrNoRows {
+			log.Printf("Error resetting password: %v", err)
+		}
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return 0, err
+	}
+
+	dbQueryDurationSeconds.WithLabelValues(operation, queryType).Observe(time.Since(start).Seconds())
+	dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+	return userID, nil
+}
+
+func (r *UserRepository) resetPasswordTx(tokenHash, passwordHash string, now time.Time) (int, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	// Условие по used_at в самом UPDATE не даёт двум параллельным запросам использовать один токен
+	query, args, err := sq.Update("password_reset_tokens").
+		Set("used_at", now).
+		Where(sq.Eq{"token_hash": tokenHash, "used_at": nil}).
+		Where(sq.Gt{"expires_at": now}).
+		Suffix("RETURNING user_id").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return 0, err
+	}
+	var userID int
+	if err := tx.QueryRow(query, args...).Scan(&userID); err != nil {
+		return 0, err
+	}
+
+	query, args, err = sq.Update("users").
+		Set("password_hash", passwordHash).
+		Set("failed_logins", 0).
+		Set("locked_until", nil).
+		Set("token_version", sq.Expr("token_version + 1")).
+		Where(sq.Eq{"id": userID}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return 0, err
+	}
+	if _, err := tx.Exec(query, args...); err != nil {
+		return 0, err
+	}
+
+	query, args, err = sq.Update("password_reset_tokens").
+		Set("used_at", now).
+		Where(sq.Eq{"user_id": userID, "used_at": nil}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return 0, err
+	}
+	if _, err := tx.Exec(query, args...); err != nil {
+		return 0, err
+	}
+
+	return userID, tx.Commit()
+}
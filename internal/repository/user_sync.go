@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"log"
+	"time"
+
+	"cinematique/internal/domain"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+// SyncExternalUser создаёт или обновляет локальную запись пользователя Keycloak, связанную
+// с ним по keycloak_id, и возвращает её ID. Новая запись создаётся без пароля. Имя или email,
+// занятые другой учётной записью, возвращают domain.ErrUserAlreadyExists
+func (r *UserRepository) SyncExternalUser(user domain.ExternalUser) (int, domain.UserSyncAction, error) {
+	start := time.Now()
+	operation := "sync_external_user"
+	queryType := "UPSERT"
+
+	query, args, err := sq.Select("id", "username", "email", "role").
+		From("users").
+		Where(sq.Eq{"keycloak_id": user.ExternalID}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return 0, "", err
+	}
+	var existing domain.User
+	err = r.db.QueryRow(query, args...).Scan(&existing.ID, &existing.Username, &existing.Email, &existing.Role)
+	if errors.Is(err, sql.ErrNoRows) {
+		id, err := r.createExternalUser(user)
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		if err != nil {
+			return 0, "", err
+		}
+		dbQueryDurationSeconds.WithLabelValues(operation, queryType).Observe(time.Since(start).Seconds())
+		return id, domain.UserSyncCreated, nil
+	}
+	if err != nil {
+		log.Printf("Error getting user by keycloak id: %v", err)
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return 0, "", err
+	}
+
+	if existing.Username == user.Username && existing.Email == user.Email && existing.Role == user.Role {
+		dbQueryDurationSeconds.WithLabelValues(operation, queryType).Observe(time.Since(start).Seconds())
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return existing.ID, domain.UserSyncUnchanged, nil
+	}
+	query, args, err = sq.Update("users").
+		Set("username", user.Username).
+		Set("email", user.Email).
+		Set("role", user.Role).
+		Where(sq.Eq{"id": existing.ID}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return 0, "", err
+	}
+	if _, err := r.db.Exec(query, args...); err != nil {
+		log.Printf("Error updating external user: %v", err)
+		dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+		return 0, "", uniqueViolation(err, domain.ErrUserAlreadyExists)
+	}
+	dbQueryDurationSeconds.WithLabelValues(operation, queryType).Observe(time.Since(start).Seconds())
+	dbQueriesTotal.WithLabelValues(operation, queryType).Inc()
+	return existing.ID, domain.UserSyncUpdated, nil
+}
+
+// createExternalUser добавляет запись пользователя Keycloak. Пустой хэш пароля не подходит
+// ни к одному паролю, поэтому войти по логину и паролю нельзя
+func (r *UserRepository) createExternalUser(user domain.ExternalUser) (int, error) {
+	query, args, err := sq.Insert("users").
+		Columns("username", "email", "password_hash", "role", "keycloak_id").
+		Values(user.Username, user.Email, "", user.Role, user.ExternalID).
+		Suffix("RETURNING id").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return 0, err
+	}
+	var id int
+	if err := r.db.QueryRow(query, args...).Scan(&id); err != nil {
+		log.Printf("Error creating external user: %v", err)
+		return 0, uniqueViolation(err, domain.ErrUserAlreadyExists)
+	}
+	return id, nil
+}
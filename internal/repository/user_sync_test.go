@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"regexp"
+	"testing"
+
+	"cinematique/internal/domain"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserRepository_SyncExternalUser(t *testing.T) {
+	selectQuery := regexp.QuoteMeta("SELECT id, username, email, role FROM users WHERE keycloak_id = $1")
+	insertQuery := regexp.QuoteMeta("INSERT INTO users (username,email,password_hash,role,keycloak_id) VALUES ($1,$2,$3,$4,$5) RETURNING id")
+	updateQuery := regexp.QuoteMeta("UPDATE users SET username = $1, email = $2, role = $3 WHERE id = $4")
+	user := domain.ExternalUser{ExternalID: "kc-1", Username: "alice", Email: "alice@example.com", Role: "moderator"}
+	columns := []string{"id", "username", "email", "role"}
+
+	t.Run("creates missing user", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectQuery(selectQuery).WithArgs("kc-1").WillReturnRows(sqlmock.NewRows(columns))
+		mock.ExpectQuery(insertQuery).WithArgs("alice", "alice@example.com", "", "moderator", "kc-1").
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(5))
+
+		id, action, err := NewUserRepository(db).SyncExternalUser(user)
+		require.NoError(t, err)
+		assert.Equal(t, 5, id)
+		assert.Equal(t, domain.UserSyncCreated, action)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("leaves matching user unchanged", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectQuery(selectQuery).WithArgs("kc-1").
+			WillReturnRows(sqlmock.NewRows(columns).AddRow(5, "alice", "alice@example.com", "moderator"))
+
+		id, action, err := NewUserRepository(db).SyncExternalUser(user)
+		require.NoError(t, err)
+		assert.Equal(t, 5, id)
+		assert.Equal(t, domain.UserSyncUnchanged, action)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("updates changed role", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectQuery(selectQuery).WithArgs("kc-1").
+			WillReturnRows(sqlmock.NewRows(columns).AddRow(5, "alice", "alice@example.com", "user"))
+		mock.ExpectExec(updateQuery).WithArgs("alice", "alice@example.com", "moderator", 5).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		_, action, err := NewUserRepository(db).SyncExternalUser(user)
+		require.NoError(t, err)
+		assert.Equal(t, domain.UserSyncUpdated, action)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("taken username", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectQuery(selectQuery).WithArgs("kc-1").WillReturnRows(sqlmock.NewRows(columns))
+		mock.ExpectQuery(insertQuery).WillReturnError(&pq.Error{Code: pqUniqueViolation, Constraint: "users_username_key"})
+
+		_, _, err = NewUserRepository(db).SyncExternalUser(user)
+		assert.ErrorIs(t, err, domain.ErrUserAlreadyExists)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -4,9 +4,11 @@ import (
 	"cinematique/internal/domain"
 	"database/sql"
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"testing"
+	"time"
 )
 
 func TestUserRepository_CreateUser(t *testing.T) {
@@ -17,11 +19,12 @@ func TestUserRepository_CreateUser(t *testing.T) {
 	repo := NewUserRepository(db)
 
 	tests := []struct {
-		name    string
-		user    domain.User
-		setup   func()
-		want    int
-		wantErr bool
+		name      string
+		user      domain.User
+		setup     func()
+		want      int
+		wantErr   bool
+		wantErrIs error
 	}{
 		{
 			name: "successful user creation",
@@ -49,9 +52,10 @@ func TestUserRepository_CreateUser(t *testing.T) {
 			setup: func() {
 				mock.ExpectQuery(`INSERT INTO users \(username,email,password_hash,role\)`).
 					WithArgs("existinguser", "existing@example.com", "hashedpassword", "user").
-					WillReturnError(sql.ErrNoRows)
+					WillReturnError(&pq.Error{Code: "23505", Constraint: "users_username_key"})
 			},
-			wantErr: true,
+			wantErr:   true,
+			wantErrIs: domain.ErrUserAlreadyExists,
 		},
 	}
 
@@ -65,6 +69,9 @@ func TestUserRepository_CreateUser(t *testing.T) {
 
 			if tt.wantErr {
 				assert.Error(t, err)
+				if tt.wantErrIs != nil {
+					assert.ErrorIs(t, err, tt.wantErrIs)
+				}
 			} else {
 				assert.NoError(t, err)
 				assert.Equal(t, tt.want, got)
@@ -94,9 +101,9 @@ func TestUserRepository_GetByUsername(t *testing.T) {
 			name:     "user found",
 			username: "testuser",
 			setup: func() {
-				rows := sqlmock.NewRows([]string{"id", "username", "email", "password_hash", "role"}).
-					AddRow(1, "testuser", "test@example.com", "hashedpass", "user")
-				mock.ExpectQuery(`SELECT id, username, email, password_hash, role FROM users WHERE username = \$1`).
+				rows := sqlmock.NewRows([]string{"id", "username", "email", "password_hash", "role", "failed_logins", "locked_until", "token_version"}).
+					AddRow(1, "testuser", "test@example.com", "hashedpass", "user", 0, nil, 0)
+				mock.ExpectQuery(`SELECT id, username, email, password_hash, role, failed_logins, locked_until, token_version FROM users WHERE username = \$1`).
 					WithArgs("testuser").
 					WillReturnRows(rows)
 			},
@@ -158,9 +165,9 @@ func TestUserRepository_GetByID(t *testing.T) {
 			name: "user found",
 			id:   1,
 			setup: func() {
-				rows := sqlmock.NewRows([]string{"id", "username", "email", "password_hash", "role"}).
-					AddRow(1, "testuser", "admin@example.com", "hashedpass", "admin")
-				mock.ExpectQuery(`^SELECT id, username, email, password_hash, role FROM users WHERE id = \$1$`).
+				rows := sqlmock.NewRows([]string{"id", "username", "email", "password_hash", "role", "failed_logins", "locked_until", "token_version"}).
+					AddRow(1, "testuser", "admin@example.com", "hashedpass", "admin", 0, nil, 2)
+				mock.ExpectQuery(`^SELECT id, username, email, password_hash, role, failed_logins, locked_until, token_version FROM users WHERE id = \$1$`).
 					WithArgs(1).
 					WillReturnRows(rows)
 			},
@@ -170,6 +177,7 @@ func TestUserRepository_GetByID(t *testing.T) {
 				Email:        "admin@example.com",
 				PasswordHash: "hashedpass",
 				Role:         "admin",
+				TokenVersion: 2,
 			},
 		},
 		{
@@ -203,3 +211,244 @@ func TestUserRepository_GetByID(t *testing.T) {
 		})
 	}
 }
+
+func TestUserRepository_GetByUsername_Locked(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	lockedUntil := time.Date(2024, 5, 1, 12, 15, 0, 0, time.UTC)
+	mock.ExpectQuery(`SELECT id, username, email, password_hash, role, failed_logins, locked_until, token_version FROM users`).
+		WithArgs("testuser").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "username", "email", "password_hash", "role", "failed_logins", "locked_until", "token_version"}).
+			AddRow(1, "testuser", "test@example.com", "hashedpass", "user", 2, lockedUntil, 0))
+
+	got, err := NewUserRepository(db).GetByUsername("testuser")
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, got.FailedLogins)
+	require.NotNil(t, got.LockedUntil)
+	assert.True(t, lockedUntil.Equal(*got.LockedUntil))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUserRepository_RegisterFailedLogin(t *testing.T) {
+	lockUntil := time.Date(2024, 5, 1, 12, 15, 0, 0, time.UTC)
+	query := `UPDATE users SET failed_logins = CASE WHEN failed_logins \+ 1 >= \$1 THEN 0 ELSE failed_logins \+ 1 END, ` +
+		`locked_until = CASE WHEN failed_logins \+ 1 >= \$2 THEN \$3 ELSE locked_until END WHERE id = \$4 RETURNING failed_logins, locked_until`
+
+	t.Run("below threshold", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectQuery(query).
+			WithArgs(5, 5, lockUntil, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"failed_logins", "locked_until"}).AddRow(3, nil))
+
+		failed, lockedUntil, err := NewUserRepository(db).RegisterFailedLogin(1, 5, lockUntil)
+
+		require.NoError(t, err)
+		assert.Equal(t, 3, failed)
+		assert.Nil(t, lockedUntil)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("locks account", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectQuery(query).
+			WithArgs(5, 5, lockUntil, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"failed_logins", "locked_until"}).AddRow(0, lockUntil))
+
+		failed, lockedUntil, err := NewUserRepository(db).RegisterFailedLogin(1, 5, lockUntil)
+
+		require.NoError(t, err)
+		assert.Equal(t, 0, failed)
+		require.NotNil(t, lockedUntil)
+		assert.True(t, lockUntil.Equal(*lockedUntil))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestUserRepository_SetRole(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	repo := NewUserRepository(db)
+	query := `UPDATE users SET role = \$1, token_version = token_version \+ 1 WHERE id = \$2 RETURNING token_version`
+
+	mock.ExpectQuery(query).WithArgs("moderator", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"token_version"}).AddRow(3))
+	version, err := repo.SetRole(1, "moderator")
+	require.NoError(t, err)
+	assert.Equal(t, 3, version)
+
+	mock.ExpectQuery(query).WithArgs("moderator", 2).WillReturnError(sql.ErrNoRows)
+	_, err = repo.SetRole(2, "moderator")
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUserRepository_GetTokenVersion(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery(`SELECT token_version FROM users WHERE id = \$1`).WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"token_version"}).AddRow(4))
+	version, err := NewUserRepository(db).GetTokenVersion(1)
+	require.NoError(t, err)
+	assert.Equal(t, 4, version)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUserRepository_ResetFailedLogins(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectExec(`UPDATE users SET failed_logins = \$1, locked_until = \$2 WHERE id = \$3`).
+		WithArgs(0, nil, 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	require.NoError(t, NewUserRepository(db).ResetFailedLogins(1))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUserRepository_UpdatePasswordHash(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectExec(`UPDATE users SET password_hash = \$1 WHERE id = \$2 AND password_hash = \$3`).
+		WithArgs("new", 1, "old").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	require.NoError(t, NewUserRepository(db).UpdatePasswordHash(1, "old", "new"))
+
+	// Пароль уже сменили — строка не обновляется, но это не ошибка
+	mock.ExpectExec(`UPDATE users SET password_hash`).
+		WithArgs("new", 1, "old").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	require.NoError(t, NewUserRepository(db).UpdatePasswordHash(1, "old", "new"))
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUserRepository_UpdateUser(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	repo := NewUserRepository(db)
+	email, hash := "neo@zion.io", "hashed"
+
+	mock.ExpectExec(`UPDATE users SET email = \$1, password_hash = \$2 WHERE id = \$3`).
+		WithArgs(email, hash, 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	require.NoError(t, repo.UpdateUser(1, domain.UserUpdate{Email: &email, PasswordHash: &hash}))
+
+	mock.ExpectExec(`UPDATE users SET email = \$1 WHERE id = \$2`).
+		WithArgs(email, 2).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	assert.ErrorIs(t, repo.UpdateUser(2, domain.UserUpdate{Email: &email}), sql.ErrNoRows)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUserRepository_DeleteUser(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	repo := NewUserRepository(db)
+
+	mock.ExpectExec(`DELETE FROM users WHERE id = \$1`).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+	require.NoError(t, repo.DeleteUser(1))
+
+	mock.ExpectExec(`DELETE FROM users WHERE id = \$1`).WithArgs(2).WillReturnResult(sqlmock.NewResult(0, 0))
+	assert.ErrorIs(t, repo.DeleteUser(2), sql.ErrNoRows)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUserRepository_GetByEmail(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	repo := NewUserRepository(db)
+	query := `SELECT id, username, email, password_hash, role, failed_logins, locked_until, token_version FROM users WHERE LOWER\(email\) = LOWER\(\$1\) ORDER BY id LIMIT 1`
+
+	mock.ExpectQuery(query).WithArgs("Neo@Zion.io").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "username", "email", "password_hash", "role", "failed_logins", "locked_until", "token_version"}).
+			AddRow(1, "neo", "neo@zion.io", "hashed", "user", 0, nil, 0))
+	user, err := repo.GetByEmail("Neo@Zion.io")
+	require.NoError(t, err)
+	assert.Equal(t, "neo", user.Username)
+
+	mock.ExpectQuery(query).WithArgs("smith@matrix.io").WillReturnError(sql.ErrNoRows)
+	_, err = repo.GetByEmail("smith@matrix.io")
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUserRepository_CreatePasswordResetToken(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	repo := NewUserRepository(db)
+	expiresAt := time.Date(2024, 5, 1, 13, 0, 0, 0, time.UTC)
+
+	mock.ExpectExec(`INSERT INTO password_reset_tokens \(user_id,token_hash,expires_at\) VALUES \(\$1,\$2,\$3\)`).
+		WithArgs(1, "abc123", expiresAt).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	require.NoError(t, repo.CreatePasswordResetToken(1, "abc123", expiresAt))
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUserRepository_ResetPassword(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	consume := `UPDATE password_reset_tokens SET used_at = \$1 WHERE token_hash = \$2 AND used_at IS NULL AND expires_at > \$3 RETURNING user_id`
+
+	t.Run("valid token", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+		repo := NewUserRepository(db)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(consume).WithArgs(now, "abc123", now).
+			WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(7))
+		mock.ExpectExec(`UPDATE users SET password_hash = \$1, failed_logins = \$2, locked_until = \$3, token_version = token_version \+ 1 WHERE id = \$4`).
+			WithArgs("new-hash", 0, nil, 7).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(`UPDATE password_reset_tokens SET used_at = \$1 WHERE used_at IS NULL AND user_id = \$2`).
+			WithArgs(now, 7).
+			WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectCommit()
+
+		userID, err := repo.ResetPassword("abc123", "new-hash", now)
+		require.NoError(t, err)
+		assert.Equal(t, 7, userID)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("expired or used token", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+		repo := NewUserRepository(db)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(consume).WithArgs(now, "abc123", now).
+			WillReturnRows(sqlmock.NewRows([]string{"user_id"}))
+		mock.ExpectRollback()
+
+		_, err = repo.ResetPassword("abc123", "new-hash", now)
+		assert.ErrorIs(t, err, sql.ErrNoRows)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
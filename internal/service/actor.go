@@ -1,42 +1,79 @@
 package service
 
 import (
+	"bytes"
 	"cinematique/internal/domain"
+	"cinematique/internal/storage"
+	"context"
 	"errors"
 	"fmt"
 	"log"
+	"time"
 )
 
 // StoreActor определяет интерфейс для работы с хранилищем актёров
 type StoreActor interface {
-	Create(actor domain.Actor) (int, error)                    // создать актёра
-	GetByID(id int) (domain.Actor, error)                      // получить актёра по ID
-	Update(actor domain.Actor) error                           // обновить актёра
-	Delete(id int) error                                       // удалить актёра
-	GetAll() ([]domain.Actor, error)                           // получить всех актёров
-	GetMovies(actorID int) ([]domain.Movie, error)             // фильмы по актёру
-	PartialUpdateActor(id int, update domain.ActorUpdate) error // частичное обновление
-	GetAllActorsWithMovies() ([]domain.Actor, error)           // актёры с фильмами
+	Create(ctx context.Context, actor domain.Actor) (int, error)                                                                       // создать актёра
+	GetByID(ctx context.Context, id int) (domain.Actor, error)                                                                         // получить актёра по ID
+	Update(ctx context.Context, actor domain.Actor) error                                                                              // обновить актёра
+	Delete(ctx context.Context, id int) error                                                                                          // удалить актёра
+	GetAll(ctx context.Context, filter domain.ActorFilter) ([]domain.Actor, error)                                                     // получить всех актёров
+	GetMovies(ctx context.Context, actorID int) ([]domain.Movie, error)                                                                // фильмы по актёру
+	PartialUpdateActor(ctx context.Context, id int, update domain.ActorUpdate) error                                                   // частичное обновление
+	GetAllActorsWithMovies(ctx context.Context, query domain.ActorsWithMoviesQuery) ([]domain.Actor, error)                            // страница актёров с фильмами
+	MergeActors(ctx context.Context, keepID, dupID int) (domain.ActorMergeResult, error)                                               // слияние дубликатов
+	SetPhotoKey(ctx context.Context, id int, key string) error                                                                         // сохранить ключ фотографии
+	SearchActorsByName(ctx context.Context, nameFragment string, filter domain.ActorFilter, limit, offset int) ([]domain.Actor, error) // поиск по имени
+	CountActors(ctx context.Context, nameFragment string, filter domain.ActorFilter) (int, error)                                      // число актёров по фрагменту имени и фильтру
+	SuggestActors(ctx context.Context, prefix string, limit int) ([]domain.Actor, error)                                               // подсказки по началу имени
+	ForEachActor(ctx context.Context, fn func(domain.Actor) error) error                                                               // обойти всех актёров без загрузки в память
+	GetActorsBornInMonth(ctx context.Context, month int) ([]domain.Actor, error)                                                       // актёры, родившиеся в месяце
+	GetOrphanActors(ctx context.Context, limit, offset int) ([]domain.Actor, error)                                                    // актёры без фильмов
+	CountOrphanActors(ctx context.Context) (int, error)                                                                                // число актёров без фильмов
+	PurgeOrphanActors(ctx context.Context, maxCount int) ([]domain.Actor, error)                                                       // удалить актёров без фильмов
+	ResolveSlug(ctx context.Context, slug string) (int, error)                                                                         // ID актёра по slug
+	GetActorStats(ctx context.Context, actorID int) (domain.ActorStats, error)                                                         // сводка по фильмам актёра
+	FindActorsByNames(ctx context.Context, names []string) ([]domain.Actor, error)                                                     // актёры с одним из имён
+	ImportActors(ctx context.Context, actors []domain.Actor) ([]int, error)                                                            // создать актёров в одной транзакции
+	AddActorRevision(ctx context.Context, revision domain.ActorRevision) error                                                         // сохранить версию актёра
+	GetActorRevisions(ctx context.Context, actorID int) ([]domain.ActorRevision, error)                                                // версии актёра по времени
 }
 
 // ActorService реализует бизнес-логику для актёров
 type ActorService struct {
-	store StoreActor
+	store       StoreActor
+	photos      storage.Storage
+	actorsCache *ActorsWithMoviesCache // опционально: кэш GetAllActorsWithMovies
+
+	orphanThreshold int // порог подтверждения удаления актёров без фильмов
 }
 
-// NewActor создаёт сервис актёров
-func NewActor(store StoreActor) *ActorService {
-	return &ActorService{store: store}
+// NewActor создаёт сервис актёров; photos — хранилище фотографий актёров
+func NewActor(store StoreActor, photos storage.Storage) *ActorService {
+	return &ActorService{store: store, photos: photos}
 }
 
 // Create создаёт нового актёра
-func (s *ActorService) Create(actor domain.Actor) (int, error) {
-	return s.store.Create(actor)
+func (s *ActorService) Create(ctx context.Context, actor domain.Actor) (int, error) {
+	ctx, span := tracer().Start(ctx, "ActorService.Create")
+	defer span.End()
+
+	defer s.actorsCache.Invalidate()
+	id, err := s.store.Create(ctx, actor)
+	if err != nil {
+		return 0, err
+	}
+	actor.ID = id
+	s.recordActorRevision(ctx, actor)
+	return id, nil
 }
 
 // GetByID возвращает актёра по ID
-func (s *ActorService) GetByID(id int) (domain.Actor, error) {
-	actor, err := s.store.GetByID(id)
+func (s *ActorService) GetByID(ctx context.Context, id int) (domain.Actor, error) {
+	ctx, span := tracer().Start(ctx, "ActorService.GetByID")
+	defer span.End()
+
+	actor, err := s.store.GetByID(ctx, id)
 	if err != nil {
 		if errors.Is(err, domain.ErrActorNotFound) {
 			return domain.Actor{}, domain.ErrActorNotFound
@@ -46,23 +83,47 @@ func (s *ActorService) GetByID(id int) (domain.Actor, error) {
 	return actor, nil
 }
 
+// GetBySlug возвращает актёра по slug
+func (s *ActorService) GetBySlug(ctx context.Context, slug string) (domain.Actor, error) {
+	ctx, span := tracer().Start(ctx, "ActorService.GetBySlug")
+	defer span.End()
+
+	id, err := s.store.ResolveSlug(ctx, slug)
+	if err != nil {
+		if errors.Is(err, domain.ErrActorNotFound) {
+			return domain.Actor{}, domain.ErrActorNotFound
+		}
+		return domain.Actor{}, fmt.Errorf("resolving actor slug: %w", err)
+	}
+	return s.GetByID(ctx, id)
+}
+
 // Update обновляет данные актёра
-func (s *ActorService) Update(actor domain.Actor) error {
-	if err := s.store.Update(actor); err != nil {
+func (s *ActorService) Update(ctx context.Context, actor domain.Actor) error {
+	ctx, span := tracer().Start(ctx, "ActorService.Update")
+	defer span.End()
+
+	defer s.actorsCache.Invalidate()
+	if err := s.store.Update(ctx, actor); err != nil {
 		if errors.Is(err, domain.ErrActorNotFound) {
 			return domain.ErrActorNotFound
 		}
 		return fmt.Errorf("updating actor: %w", err)
 	}
+	s.recordActorRevision(ctx, actor)
 	return nil
 }
 
 // Delete удаляет актёра
-func (s *ActorService) Delete(id int) error {
+func (s *ActorService) Delete(ctx context.Context, id int) error {
+	ctx, span := tracer().Start(ctx, "ActorService.Delete")
+	defer span.End()
+
+	defer s.actorsCache.Invalidate()
 	log.Printf("Starting deletion of actor with ID: %d", id)
 
 	// Проверяем существование актёра
-	_, err := s.store.GetByID(id)
+	actor, err := s.store.GetByID(ctx, id)
 	if err != nil {
 		if errors.Is(err, domain.ErrActorNotFound) {
 			log.Printf("Cannot delete: actor with ID %d not found", id)
@@ -74,22 +135,21 @@ func (s *ActorService) Delete(id int) error {
 	}
 
 	// Проверяем, есть ли у актёра связанные фильмы
-	movies, err := s.store.GetMovies(id)
+	movies, err := s.store.GetMovies(ctx, id)
 	if err != nil {
 		log.Printf("Error getting movies for actor (ID: %d): %v", id, err)
 		return fmt.Errorf("getting actor movies: %w", err)
 	}
-	
+
 	log.Printf("Found %d related movies for actor (ID: %d)", len(movies), id)
 	if len(movies) > 0 {
-		errMsg := fmt.Sprintf("cannot delete actor: has %d related movies. Remove movies first", len(movies))
-		log.Printf("Cannot delete actor (ID: %d): %s", id, errMsg)
-		return errors.New(errMsg)
+		log.Printf("Cannot delete actor (ID: %d): has %d related movies", id, len(movies))
+		return fmt.Errorf("%w (%d), remove movies first", domain.ErrActorHasMovies, len(movies))
 	}
 
 	// Удаляем актёра
 	log.Printf("Deleting actor with ID: %d from store", id)
-	if err := s.store.Delete(id); err != nil {
+	if err := s.store.Delete(ctx, id); err != nil {
 		log.Printf("Error deleting actor (ID: %d): %v", id, err)
 		if errors.Is(err, domain.ErrActorNotFound) {
 			// На случай, если актёр был удалён между проверкой и удалением
@@ -98,23 +158,87 @@ func (s *ActorService) Delete(id int) error {
 		}
 		return fmt.Errorf("deleting actor: %w", err)
 	}
-	
+
+	s.recordActorDeletion(ctx, id)
+	s.deletePhoto(actor.PhotoKey)
 	log.Printf("Successfully deleted actor with ID: %d", id)
 	return nil
 }
 
-// GetAll возвращает всех актёров
-func (s *ActorService) GetAll() ([]domain.Actor, error) {
-	actors, err := s.store.GetAll()
+// GetAll возвращает всех актёров, подходящих под filter
+func (s *ActorService) GetAll(ctx context.Context, filter domain.ActorFilter) ([]domain.Actor, error) {
+	ctx, span := tracer().Start(ctx, "ActorService.GetAll")
+	defer span.End()
+
+	actors, err := s.store.GetAll(ctx, filter)
 	if err != nil {
 		return nil, fmt.Errorf("getting all actors: %w", err)
 	}
 	return actors, nil
 }
 
+// ExportActors передаёт fn всех актёров по возрастанию ID
+func (s *ActorService) ExportActors(ctx context.Context, fn func(domain.Actor) error) error {
+	ctx, span := tracer().Start(ctx, "ActorService.ExportActors")
+	defer span.End()
+	return s.store.ForEachActor(ctx, fn)
+}
+
+// SearchActorsByName ищет актёров по фрагменту имени с пагинацией
+func (s *ActorService) SearchActorsByName(ctx context.Context, nameFragment string, filter domain.ActorFilter, limit, offset int) ([]domain.Actor, error) {
+	ctx, span := tracer().Start(ctx, "ActorService.SearchActorsByName")
+	defer span.End()
+
+	actors, err := s.store.SearchActorsByName(ctx, nameFragment, filter, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("searching actors by name: %w", err)
+	}
+	return actors, nil
+}
+
+// CountActors возвращает число актёров, найденных SearchActorsByName без пагинации;
+// пустой nameFragment считает всех актёров под filter
+func (s *ActorService) CountActors(ctx context.Context, nameFragment string, filter domain.ActorFilter) (int, error) {
+	ctx, span := tracer().Start(ctx, "ActorService.CountActors")
+	defer span.End()
+
+	total, err := s.store.CountActors(ctx, nameFragment, filter)
+	if err != nil {
+		return 0, fmt.Errorf("counting actors: %w", err)
+	}
+	return total, nil
+}
+
+// SuggestActors возвращает ID и имена актёров, чьё имя или фамилия начинается с prefix
+func (s *ActorService) SuggestActors(ctx context.Context, prefix string, limit int) ([]domain.Actor, error) {
+	ctx, span := tracer().Start(ctx, "ActorService.SuggestActors")
+	defer span.End()
+
+	actors, err := s.store.SuggestActors(ctx, prefix, limit)
+	if err != nil {
+		return nil, fmt.Errorf("suggesting actors: %w", err)
+	}
+	return actors, nil
+}
+
+// GetActorsBornInMonth возвращает актёров, родившихся в указанном месяце
+func (s *ActorService) GetActorsBornInMonth(ctx context.Context, month int) ([]domain.Actor, error) {
+	ctx, span := tracer().Start(ctx, "ActorService.GetActorsBornInMonth")
+	defer span.End()
+
+	actors, err := s.store.GetActorsBornInMonth(ctx, month)
+	if err != nil {
+		return nil, fmt.Errorf("getting actors born in month %d: %w", month, err)
+	}
+	return actors, nil
+}
+
 // GetMovies возвращает фильмы актёра
-func (s *ActorService) GetMovies(actorID int) ([]domain.Movie, error) {
-	movies, err := s.store.GetMovies(actorID)
+func (s *ActorService) GetMovies(ctx context.Context, actorID int) ([]domain.Movie, error) {
+	ctx, span := tracer().Start(ctx, "ActorService.GetMovies")
+	defer span.End()
+
+	movies, err := s.store.GetMovies(ctx, actorID)
 	if err != nil {
 		if errors.Is(err, domain.ErrActorNotFound) {
 			return nil, domain.ErrActorNotFound
@@ -124,22 +248,127 @@ func (s *ActorService) GetMovies(actorID int) ([]domain.Movie, error) {
 	return movies, nil
 }
 
+// GetStats возвращает число фильмов актёра, их средний рейтинг, первый и последний год выхода
+func (s *ActorService) GetStats(ctx context.Context, actorID int) (domain.ActorStats, error) {
+	ctx, span := tracer().Start(ctx, "ActorService.GetStats")
+	defer span.End()
+
+	stats, err := s.store.GetActorStats(ctx, actorID)
+	if err != nil {
+		if errors.Is(err, domain.ErrActorNotFound) {
+			return domain.ActorStats{}, domain.ErrActorNotFound
+		}
+		return domain.ActorStats{}, fmt.Errorf("getting actor stats: %w", err)
+	}
+	return stats, nil
+}
+
 // PartialUpdateActor обновляет только переданные поля актёра
-func (s *ActorService) PartialUpdateActor(id int, update domain.ActorUpdate) error {
-	if err := s.store.PartialUpdateActor(id, update); err != nil {
+func (s *ActorService) PartialUpdateActor(ctx context.Context, id int, update domain.ActorUpdate) error {
+	ctx, span := tracer().Start(ctx, "ActorService.PartialUpdateActor")
+	defer span.End()
+
+	defer s.actorsCache.Invalidate()
+	if err := s.store.PartialUpdateActor(ctx, id, update); err != nil {
 		if errors.Is(err, domain.ErrActorNotFound) {
 			return domain.ErrActorNotFound
 		}
 		return fmt.Errorf("partially updating actor: %w", err)
 	}
+	s.recordCurrentActor(ctx, id)
 	return nil
 }
 
-// GetAllActorsWithMovies возвращает актёров с фильмами
-func (s *ActorService) GetAllActorsWithMovies() ([]domain.Actor, error) {
-	actors, err := s.store.GetAllActorsWithMovies()
+// WithActorsCache включает кэширование GetAllActorsWithMovies
+func (s *ActorService) WithActorsCache(cache *ActorsWithMoviesCache) *ActorService {
+	s.actorsCache = cache
+	return s
+}
+
+// GetAllActorsWithMovies возвращает страницу актёров с фильмами
+func (s *ActorService) GetAllActorsWithMovies(ctx context.Context, query domain.ActorsWithMoviesQuery) ([]domain.Actor, error) {
+	ctx, span := tracer().Start(ctx, "ActorService.GetAllActorsWithMovies")
+	defer span.End()
+
+	actors, err := s.actorsCache.Get(query, func() ([]domain.Actor, error) {
+		return s.store.GetAllActorsWithMovies(ctx, query)
+	})
 	if err != nil {
 		return nil, fmt.Errorf("getting all actors with movies: %w", err)
 	}
 	return actors, nil
 }
+
+// MergeActors объединяет актёра-дубликата с основным актёром
+func (s *ActorService) MergeActors(ctx context.Context, keepID, dupID int) (domain.ActorMergeResult, error) {
+	ctx, span := tracer().Start(ctx, "ActorService.MergeActors")
+	defer span.End()
+
+	defer s.actorsCache.Invalidate()
+	if keepID == dupID {
+		return domain.ActorMergeResult{}, domain.ErrMergeSameActor
+	}
+
+	log.Printf("Merging actor (ID: %d) into actor (ID: %d)", dupID, keepID)
+	result, err := s.store.MergeActors(ctx, keepID, dupID)
+	if err != nil {
+		if errors.Is(err, domain.ErrActorNotFound) {
+			return domain.ActorMergeResult{}, domain.ErrActorNotFound
+		}
+		return domain.ActorMergeResult{}, fmt.Errorf("merging actors: %w", err)
+	}
+
+	s.recordActorRevision(ctx, result.Actor)
+	s.recordActorDeletion(ctx, dupID)
+	s.deletePhoto(result.UnusedPhotoKey)
+	log.Printf("Successfully merged actor (ID: %d) into actor (ID: %d), movies reassigned: %d",
+		dupID, keepID, result.MoviesReassigned)
+	return result, nil
+}
+
+// SetPhoto сохраняет фотографию актёра и заменяет ею предыдущую.
+// Каждая загрузка получает новый ключ, чтобы закэшированные клиентами адреса не отдавали старое фото
+func (s *ActorService) SetPhoto(ctx context.Context, id int, data []byte, contentType, ext string) (domain.Actor, error) {
+	ctx, span := tracer().Start(ctx, "ActorService.SetPhoto")
+	defer span.End()
+
+	defer s.actorsCache.Invalidate()
+	actor, err := s.GetByID(ctx, id)
+	if err != nil {
+		return domain.Actor{}, err
+	}
+
+	key := fmt.Sprintf("actors/%d/photo-%d%s", id, time.Now().UnixNano(), ext)
+	if err := s.photos.Put(ctx, key, bytes.NewReader(data), contentType); err != nil {
+		return domain.Actor{}, fmt.Errorf("storing actor photo: %w", err)
+	}
+	if err := s.store.SetPhotoKey(ctx, id, key); err != nil {
+		// Запись в БД не удалась — загруженный файл больше никому не нужен
+		s.deletePhoto(key)
+		if errors.Is(err, domain.ErrActorNotFound) {
+			return domain.Actor{}, domain.ErrActorNotFound
+		}
+		return domain.Actor{}, fmt.Errorf("saving actor photo: %w", err)
+	}
+
+	s.deletePhoto(actor.PhotoKey)
+	actor.PhotoKey = key
+	s.recordCurrentActor(ctx, id)
+	return actor, nil
+}
+
+// PhotoURL возвращает публичный адрес фотографии по её ключу
+func (s *ActorService) PhotoURL(key string) string {
+	return s.photos.URL(key)
+}
+
+// deletePhoto удаляет файл фотографии. Ошибка не прерывает операцию: осиротевший
+// файл не влияет на данные, поэтому она только логируется
+func (s *ActorService) deletePhoto(key string) {
+	if key == "" {
+		return
+	}
+	if err := s.photos.Delete(context.Background(), key); err != nil {
+		log.Printf("Failed to delete actor photo %s: %v", key, err)
+	}
+}
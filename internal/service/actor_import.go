@@ -0,0 +1,61 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"cinematique/internal/domain"
+)
+
+// MatchExistingActors ищет в каталоге актёров с тем же именем (без учёта регистра) и датой
+// рождения. Возвращает ID совпавшего актёра для каждого элемента actors; 0 — совпадения нет
+func (s *ActorService) MatchExistingActors(ctx context.Context, actors []domain.Actor) ([]int, error) {
+	ctx, span := tracer().Start(ctx, "ActorService.MatchExistingActors")
+	defer span.End()
+
+	names := make([]string, 0, len(actors))
+	for _, actor := range actors {
+		names = append(names, actor.Name)
+	}
+	existing, err := s.store.FindActorsByNames(ctx, names)
+	if err != nil {
+		return nil, fmt.Errorf("finding actors by names: %w", err)
+	}
+	byKey := make(map[string]int, len(existing))
+	for _, actor := range existing {
+		if _, ok := byKey[actorMatchKey(actor)]; !ok {
+			byKey[actorMatchKey(actor)] = actor.ID
+		}
+	}
+	ids := make([]int, len(actors))
+	for i, actor := range actors {
+		ids[i] = byKey[actorMatchKey(actor)]
+	}
+	return ids, nil
+}
+
+// actorMatchKey — ключ сравнения актёров при импорте: имя в нижнем регистре и дата рождения
+func actorMatchKey(actor domain.Actor) string {
+	return strings.ToLower(strings.TrimSpace(actor.Name)) + "|" + actor.BirthDate.Format("2006-01-02")
+}
+
+// ImportActors создаёт актёров в одной транзакции и возвращает их ID в порядке actors
+func (s *ActorService) ImportActors(ctx context.Context, actors []domain.Actor) ([]int, error) {
+	ctx, span := tracer().Start(ctx, "ActorService.ImportActors")
+	defer span.End()
+
+	ids, err := s.store.ImportActors(ctx, actors)
+	if err != nil {
+		return nil, fmt.Errorf("importing actors: %w", err)
+	}
+	s.actorsCache.Invalidate()
+	for i, id := range ids {
+		actor := actors[i]
+		actor.ID = id
+		s.recordActorRevision(ctx, actor)
+	}
+	log.Printf("Imported %d actors", len(ids))
+	return ids, nil
+}
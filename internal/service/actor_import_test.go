@@ -0,0 +1,29 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"cinematique/internal/domain"
+	"cinematique/internal/testutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestActorService_MatchExistingActors(t *testing.T) {
+	birth := time.Date(1964, 9, 2, 0, 0, 0, 0, time.UTC)
+	catalog := testutil.NewCatalog()
+	keanu := catalog.SeedActor(domain.Actor{Name: "Keanu Reeves", BirthDate: birth})
+	catalog.SeedActor(domain.Actor{Name: "Keanu Reeves", BirthDate: birth.AddDate(-20, 0, 0)})
+	svc := NewActor(catalog.Actors(), nil)
+
+	ids, err := svc.MatchExistingActors(context.Background(), []domain.Actor{
+		{Name: "keanu reeves ", BirthDate: birth},
+		{Name: "Keanu Reeves", BirthDate: birth.AddDate(1, 0, 0)},
+		{Name: "Carrie-Anne Moss", BirthDate: birth},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []int{keanu, 0, 0}, ids)
+}
@@ -0,0 +1,76 @@
+package service
+
+import (
+	"cinematique/internal/domain"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+)
+
+// DefaultOrphanPurgeThreshold — сколько актёров без фильмов можно удалить без подтверждения
+const DefaultOrphanPurgeThreshold = 20
+
+// WithOrphanPurgeThreshold задаёт порог, выше которого удаление актёров без фильмов
+// нужно подтвердить их числом; неположительное значение оставляет порог по умолчанию
+func (s *ActorService) WithOrphanPurgeThreshold(threshold int) *ActorService {
+	if threshold > 0 {
+		s.orphanThreshold = threshold
+	}
+	return s
+}
+
+// OrphanPurgeThreshold возвращает порог подтверждения удаления актёров без фильмов
+func (s *ActorService) OrphanPurgeThreshold() int {
+	if s.orphanThreshold <= 0 {
+		return DefaultOrphanPurgeThreshold
+	}
+	return s.orphanThreshold
+}
+
+// ListOrphans возвращает страницу актёров без фильмов и их общее число
+func (s *ActorService) ListOrphans(ctx context.Context, limit, offset int) ([]domain.Actor, int, error) {
+	ctx, span := tracer().Start(ctx, "ActorService.ListOrphans")
+	defer span.End()
+
+	actors, err := s.store.GetOrphanActors(ctx, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("getting orphan actors: %w", err)
+	}
+	total, err := s.store.CountOrphanActors(ctx)
+	if err != nil {
+		return nil, 0, fmt.Errorf("counting orphan actors: %w", err)
+	}
+	return actors, total, nil
+}
+
+// PurgeOrphans удаляет всех актёров без фильмов. Если их больше порога, удаление
+// выполняется только при confirmCount не меньше их числа — так случайный запрос
+// не сотрёт массово импортированных, но ещё не привязанных к фильмам актёров
+func (s *ActorService) PurgeOrphans(ctx context.Context, confirmCount int) ([]domain.Actor, error) {
+	ctx, span := tracer().Start(ctx, "ActorService.PurgeOrphans")
+	defer span.End()
+
+	maxCount := s.OrphanPurgeThreshold()
+	if confirmCount > maxCount {
+		maxCount = confirmCount
+	}
+	purged, err := s.store.PurgeOrphanActors(ctx, maxCount)
+	if err != nil {
+		if errors.Is(err, domain.ErrOrphanPurgeTooLarge) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("purging orphan actors: %w", err)
+	}
+	if len(purged) == 0 {
+		return purged, nil
+	}
+
+	s.actorsCache.Invalidate()
+	for _, actor := range purged {
+		s.recordActorDeletion(ctx, actor.ID)
+		s.deletePhoto(actor.PhotoKey)
+	}
+	log.Printf("Purged %d actors without movies", len(purged))
+	return purged, nil
+}
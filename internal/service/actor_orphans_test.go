@@ -0,0 +1,103 @@
+package service
+
+import (
+	"cinematique/internal/domain"
+	"cinematique/internal/testutil"
+	"context"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingStorage запоминает ключи удалённых объектов
+type recordingStorage struct {
+	deleted []string
+}
+
+func (s *recordingStorage) Put(context.Context, string, io.Reader, string) error { return nil }
+
+func (s *recordingStorage) Delete(_ context.Context, key string) error {
+	s.deleted = append(s.deleted, key)
+	return nil
+}
+
+func (s *recordingStorage) URL(key string) string { return "/media/" + key }
+
+func TestActorService_PurgeOrphans(t *testing.T) {
+	ctx := context.Background()
+	// seedOrphans заполняет каталог count актёрами без фильмов (у первого есть фото) и одним актёром
+	// с фильмом и возвращает ID сирот и актёра с фильмом
+	seedOrphans := func(catalog *testutil.Catalog, count int) ([]int, int) {
+		var orphans []int
+		for i := 0; i < count; i++ {
+			actor := domain.Actor{Name: fmt.Sprintf("Extra %d", i+1)}
+			if i == 0 {
+				actor.PhotoKey = "actors/extra-1.jpg"
+			}
+			orphans = append(orphans, catalog.SeedActor(actor))
+		}
+		movieID := catalog.SeedMovie(domain.Movie{Title: "The Matrix", ReleaseYear: 1999})
+		keanu := catalog.SeedActor(domain.Actor{Name: "Keanu Reeves"})
+		catalog.SeedCast(movieID, keanu)
+		return orphans, keanu
+	}
+	deletedActors := func(catalog *testutil.Catalog, ids []int) []int {
+		var deleted []int
+		for _, id := range ids {
+			for _, revision := range catalog.ActorRevisions(id) {
+				if revision.Deleted {
+					deleted = append(deleted, id)
+				}
+			}
+		}
+		return deleted
+	}
+
+	t.Run("above threshold requires confirmation", func(t *testing.T) {
+		catalog := testutil.NewCatalog()
+		orphans, keanu := seedOrphans(catalog, 3)
+		photos := &recordingStorage{}
+		svc := NewActor(catalog.Actors(), photos).WithOrphanPurgeThreshold(2)
+
+		_, err := svc.PurgeOrphans(ctx, 0)
+		var purgeErr *domain.OrphanPurgeError
+		require.ErrorAs(t, err, &purgeErr)
+		assert.Equal(t, 3, purgeErr.Count)
+		assert.ErrorIs(t, err, domain.ErrOrphanPurgeTooLarge)
+		assert.Empty(t, photos.deleted)
+
+		// Подтверждение меньшего числа не снимает ограничение
+		_, err = svc.PurgeOrphans(ctx, 2)
+		assert.ErrorIs(t, err, domain.ErrOrphanPurgeTooLarge)
+
+		purged, err := svc.PurgeOrphans(ctx, purgeErr.Count)
+		require.NoError(t, err)
+		assert.Len(t, purged, 3)
+		assert.Equal(t, []string{"actors/extra-1.jpg"}, photos.deleted)
+		assert.Equal(t, orphans, deletedActors(catalog, orphans))
+		_, ok := catalog.Actor(keanu)
+		assert.True(t, ok)
+	})
+
+	t.Run("within threshold needs no confirmation", func(t *testing.T) {
+		catalog := testutil.NewCatalog()
+		seedOrphans(catalog, DefaultOrphanPurgeThreshold)
+		svc := NewActor(catalog.Actors(), &recordingStorage{})
+
+		purged, err := svc.PurgeOrphans(ctx, 0)
+		require.NoError(t, err)
+		assert.Len(t, purged, DefaultOrphanPurgeThreshold)
+	})
+
+	t.Run("default threshold", func(t *testing.T) {
+		catalog := testutil.NewCatalog()
+		seedOrphans(catalog, DefaultOrphanPurgeThreshold+1)
+		svc := NewActor(catalog.Actors(), &recordingStorage{})
+
+		_, err := svc.PurgeOrphans(ctx, 0)
+		assert.ErrorIs(t, err, domain.ErrOrphanPurgeTooLarge)
+	})
+}
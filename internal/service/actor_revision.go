@@ -0,0 +1,86 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"cinematique/internal/domain"
+)
+
+// recordActorRevision сохраняет актёра как новую версию его истории. Запись истории
+// не должна откатывать уже сохранённое изменение, поэтому сбой только логируется
+func (s *ActorService) recordActorRevision(ctx context.Context, actor domain.Actor) {
+	revision := domain.ActorRevision{ActorID: actor.ID, Actor: actor}
+	if err := s.store.AddActorRevision(ctx, revision); err != nil {
+		log.Printf("Error recording revision for actor (ID: %d): %v", actor.ID, err)
+	}
+}
+
+// recordActorDeletion отмечает в истории удаление актёра
+func (s *ActorService) recordActorDeletion(ctx context.Context, id int) {
+	if err := s.store.AddActorRevision(ctx, domain.ActorRevision{ActorID: id, Deleted: true}); err != nil {
+		log.Printf("Error recording deletion of actor (ID: %d): %v", id, err)
+	}
+}
+
+// recordCurrentActor перечитывает актёра после частичного изменения или смены фото и сохраняет его версию
+func (s *ActorService) recordCurrentActor(ctx context.Context, id int) {
+	actor, err := s.store.GetByID(ctx, id)
+	if err != nil {
+		log.Printf("Error reading actor (ID: %d) for history: %v", id, err)
+		return
+	}
+	s.recordActorRevision(ctx, actor)
+}
+
+// GetHistory возвращает версии актёра от старых к новым с отличиями от предыдущей версии.
+// История хранится и после удаления актёра; ErrActorNotFound — если нет ни актёра, ни истории
+func (s *ActorService) GetHistory(ctx context.Context, actorID int) ([]domain.ActorVersion, error) {
+	ctx, span := tracer().Start(ctx, "ActorService.GetHistory")
+	defer span.End()
+
+	revisions, err := s.store.GetActorRevisions(ctx, actorID)
+	if err != nil {
+		return nil, fmt.Errorf("getting actor revisions: %w", err)
+	}
+	if len(revisions) == 0 {
+		if _, err := s.store.GetByID(ctx, actorID); err != nil {
+			if errors.Is(err, domain.ErrActorNotFound) {
+				return nil, domain.ErrActorNotFound
+			}
+			return nil, fmt.Errorf("checking actor existence: %w", err)
+		}
+		return []domain.ActorVersion{}, nil
+	}
+
+	versions := make([]domain.ActorVersion, 0, len(revisions))
+	var previous *domain.Actor
+	for i, revision := range revisions {
+		version := domain.ActorVersion{Version: i + 1, ChangedAt: revision.ChangedAt, Deleted: revision.Deleted}
+		if revision.Deleted {
+			version.Actor = domain.Actor{ID: actorID}
+			previous = nil
+			versions = append(versions, version)
+			continue
+		}
+		version.Actor = revision.Actor
+		if previous == nil {
+			version.Changes = initialChanges(domain.Actor{}.Diff(revision.Actor))
+		} else {
+			version.Changes = previous.Diff(revision.Actor)
+		}
+		previous = &revisions[i].Actor
+		versions = append(versions, version)
+	}
+	return versions, nil
+}
+
+// initialChanges оформляет поля первой версии записи как изменения без прежнего значения
+func initialChanges(changes []domain.FieldChange) []domain.FieldChange {
+	for i := range changes {
+		changes[i].Old = nil
+	}
+	return changes
+}
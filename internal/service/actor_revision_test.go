@@ -0,0 +1,69 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"cinematique/internal/domain"
+	"cinematique/internal/testutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestActorService_GetHistory(t *testing.T) {
+	catalog := testutil.NewCatalog()
+	svc := NewActor(catalog.Actors(), nil)
+	ctx := context.Background()
+	birth := time.Date(1962, time.June, 7, 0, 0, 0, 0, time.UTC)
+
+	id, err := svc.Create(ctx, domain.Actor{Name: "Lance Redick", Gender: "male", BirthDate: birth})
+	require.NoError(t, err)
+	name := "Lance Reddick"
+	require.NoError(t, svc.PartialUpdateActor(ctx, id, domain.ActorUpdate{Name: &name}))
+	require.NoError(t, svc.Delete(ctx, id))
+
+	versions, err := svc.GetHistory(ctx, id)
+	require.NoError(t, err)
+	require.Len(t, versions, 3)
+	assert.Equal(t, []domain.FieldChange{
+		{Field: "name", New: "Lance Redick"},
+		{Field: "gender", New: "male"},
+		{Field: "birth_date", New: birth},
+	}, versions[0].Changes)
+	assert.Equal(t, []domain.FieldChange{{Field: "name", Old: "Lance Redick", New: "Lance Reddick"}}, versions[1].Changes)
+	assert.Equal(t, "Lance Reddick", versions[1].Actor.Name)
+	assert.True(t, versions[2].Deleted)
+	assert.Empty(t, versions[2].Changes)
+
+	// Актёр без истории, например созданный в обход сервиса, получает пустую историю
+	seeded := catalog.SeedActor(domain.Actor{Name: "Keanu Reeves"})
+	versions, err = svc.GetHistory(ctx, seeded)
+	require.NoError(t, err)
+	assert.Empty(t, versions)
+
+	_, err = svc.GetHistory(ctx, 999)
+	assert.ErrorIs(t, err, domain.ErrActorNotFound)
+}
+
+func TestActorService_SetPhotoRecordsRevision(t *testing.T) {
+	catalog := testutil.NewCatalog()
+	photos := &recordingStorage{}
+	svc := NewActor(catalog.Actors(), photos)
+	ctx := context.Background()
+
+	id, err := svc.Create(ctx, domain.Actor{Name: "Keanu Reeves", Gender: "male"})
+	require.NoError(t, err)
+	first, err := svc.SetPhoto(ctx, id, []byte("jpeg"), "image/jpeg", ".jpg")
+	require.NoError(t, err)
+	second, err := svc.SetPhoto(ctx, id, []byte("png"), "image/png", ".png")
+	require.NoError(t, err)
+
+	versions, err := svc.GetHistory(ctx, id)
+	require.NoError(t, err)
+	require.Len(t, versions, 3)
+	assert.Equal(t, []domain.FieldChange{{Field: "photo", Old: "", New: first.PhotoKey}}, versions[1].Changes)
+	assert.Equal(t, []domain.FieldChange{{Field: "photo", Old: first.PhotoKey, New: second.PhotoKey}}, versions[2].Changes)
+	assert.Equal(t, []string{first.PhotoKey}, photos.deleted)
+}
@@ -0,0 +1,139 @@
+package service
+
+import (
+	"cinematique/internal/domain"
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var actorsCacheRequestsTotal = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "actors_with_movies_cache_requests_total",
+		Help: "Total number of actors-with-movies cache lookups by result (hit, miss).",
+	},
+	[]string{"result"},
+)
+
+func init() {
+	prometheus.MustRegister(actorsCacheRequestsTotal)
+}
+
+// ActorsWithMoviesCacheKey — имя кэша актёров с фильмами в сообщениях о сбросе кэшей
+const ActorsWithMoviesCacheKey = "actors_with_movies"
+
+// invalidationPublishTimeout ограничивает ожидание Redis при рассылке сброса: запись
+// в каталог уже выполнена, а другие экземпляры в худшем случае дождутся истечения TTL
+const invalidationPublishTimeout = time.Second
+
+// InvalidationPublisher сообщает другим экземплярам сервиса, что кэш key нужно сбросить
+type InvalidationPublisher interface {
+	Publish(ctx context.Context, key string) error
+}
+
+// ActorsWithMoviesCache — TTL-кэш страниц актёров с фильмами (GET /actors/with-movies),
+// по записи на каждый набор параметров страницы. Общий для ActorService и MovieService:
+// любое изменение актёров, фильмов или связей фильм–актёр сбрасывает его целиком.
+// Методы безопасны для nil-кэша (кэширование выключено)
+type ActorsWithMoviesCache struct {
+	ttl       time.Duration
+	now       func() time.Time
+	publisher InvalidationPublisher // опционально: рассылка сбросов другим экземплярам
+
+	mu         sync.Mutex
+	pages      map[domain.ActorsWithMoviesQuery]actorsCacheEntry
+	generation uint64 // увеличивается при каждом сбросе
+}
+
+// actorsCacheEntry — закэшированная страница и момент её устаревания
+type actorsCacheEntry struct {
+	actors  []domain.Actor
+	expires time.Time
+}
+
+// NewActorsWithMoviesCache создаёт кэш со временем жизни ttl
+func NewActorsWithMoviesCache(ttl time.Duration) *ActorsWithMoviesCache {
+	return &ActorsWithMoviesCache{ttl: ttl, now: time.Now}
+}
+
+// Get возвращает страницу query из кэша или загружает её через load.
+// Возвращаемый срез общий для всех вызывающих и не должен изменяться
+func (c *ActorsWithMoviesCache) Get(query domain.ActorsWithMoviesQuery, load func() ([]domain.Actor, error)) ([]domain.Actor, error) {
+	if c == nil {
+		return load()
+	}
+
+	c.mu.Lock()
+	if entry, ok := c.pages[query]; ok && c.now().Before(entry.expires) {
+		actors := entry.actors
+		c.mu.Unlock()
+		actorsCacheRequestsTotal.WithLabelValues("hit").Inc()
+		return actors, nil
+	}
+	generation := c.generation
+	c.mu.Unlock()
+	actorsCacheRequestsTotal.WithLabelValues("miss").Inc()
+
+	actors, err := load()
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	// Если кэш сбросили во время загрузки, результат мог устареть — не сохраняем его
+	if c.generation == generation {
+		now := c.now()
+		// Устаревшие страницы вычищаются при записи, чтобы редкие наборы параметров не копились
+		for key, entry := range c.pages {
+			if !now.Before(entry.expires) {
+				delete(c.pages, key)
+			}
+		}
+		if c.pages == nil {
+			c.pages = make(map[domain.ActorsWithMoviesQuery]actorsCacheEntry)
+		}
+		c.pages[query] = actorsCacheEntry{actors: actors, expires: now.Add(c.ttl)}
+	}
+	c.mu.Unlock()
+	return actors, nil
+}
+
+// WithPublisher включает рассылку сбросов: без неё изменение на одном экземпляре
+// оставляет устаревшие данные в кэшах остальных до истечения TTL
+func (c *ActorsWithMoviesCache) WithPublisher(publisher InvalidationPublisher) *ActorsWithMoviesCache {
+	if c != nil {
+		c.publisher = publisher
+	}
+	return c
+}
+
+// Invalidate сбрасывает кэш и сообщает о сбросе другим экземплярам
+func (c *ActorsWithMoviesCache) Invalidate() {
+	if c == nil {
+		return
+	}
+	c.InvalidateLocal()
+	if c.publisher == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), invalidationPublishTimeout)
+	defer cancel()
+	if err := c.publisher.Publish(ctx, ActorsWithMoviesCacheKey); err != nil {
+		log.Printf("Failed to broadcast actors cache invalidation: %v", err)
+	}
+}
+
+// InvalidateLocal сбрасывает кэш только этого экземпляра. Вызывается по сообщению
+// о сбросе от другого экземпляра
+func (c *ActorsWithMoviesCache) InvalidateLocal() {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	c.pages = nil
+	c.generation++
+	c.mu.Unlock()
+}
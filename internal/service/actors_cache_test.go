@@ -0,0 +1,130 @@
+package service
+
+import (
+	"cinematique/internal/domain"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// firstPage — первая страница актёров с фильмами без фильтров
+var firstPage = domain.ActorsWithMoviesQuery{Limit: 20}
+
+func TestActorsWithMoviesCache(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	cache := NewActorsWithMoviesCache(time.Minute)
+	cache.now = func() time.Time { return now }
+
+	loads := 0
+	load := func() ([]domain.Actor, error) {
+		loads++
+		return []domain.Actor{{ID: loads}}, nil
+	}
+
+	actors, err := cache.Get(firstPage, load)
+	require.NoError(t, err)
+	assert.Equal(t, 1, actors[0].ID)
+
+	// Повторный запрос в пределах TTL обслуживается из кэша
+	actors, _ = cache.Get(firstPage, load)
+	assert.Equal(t, 1, actors[0].ID)
+	assert.Equal(t, 1, loads)
+
+	// Сброс
+	cache.Invalidate()
+	actors, _ = cache.Get(firstPage, load)
+	assert.Equal(t, 2, actors[0].ID)
+
+	// Истечение TTL
+	now = now.Add(2 * time.Minute)
+	actors, _ = cache.Get(firstPage, load)
+	assert.Equal(t, 3, actors[0].ID)
+
+	// Ошибки загрузки не кэшируются
+	cache.Invalidate()
+	_, err = cache.Get(firstPage, func() ([]domain.Actor, error) { return nil, errors.New("db down") })
+	assert.Error(t, err)
+	actors, _ = cache.Get(firstPage, load)
+	assert.Equal(t, 4, actors[0].ID)
+}
+
+func TestActorsWithMoviesCache_Pages(t *testing.T) {
+	cache := NewActorsWithMoviesCache(time.Minute)
+	secondPage := domain.ActorsWithMoviesQuery{Limit: 20, Offset: 20}
+
+	_, _ = cache.Get(firstPage, func() ([]domain.Actor, error) { return []domain.Actor{{ID: 1}}, nil })
+	// Страница с другими параметрами загружается отдельно и не затирает первую
+	actors, _ := cache.Get(secondPage, func() ([]domain.Actor, error) { return []domain.Actor{{ID: 21}}, nil })
+	assert.Equal(t, 21, actors[0].ID)
+	actors, _ = cache.Get(firstPage, func() ([]domain.Actor, error) { return []domain.Actor{{ID: 99}}, nil })
+	assert.Equal(t, 1, actors[0].ID)
+
+	// Сброс касается всех страниц
+	cache.Invalidate()
+	actors, _ = cache.Get(secondPage, func() ([]domain.Actor, error) { return []domain.Actor{{ID: 22}}, nil })
+	assert.Equal(t, 22, actors[0].ID)
+}
+
+func TestActorsWithMoviesCache_InvalidateDuringLoad(t *testing.T) {
+	cache := NewActorsWithMoviesCache(time.Minute)
+
+	// Данные, загруженные до сброса, не должны попасть в кэш
+	_, err := cache.Get(firstPage, func() ([]domain.Actor, error) {
+		cache.Invalidate()
+		return []domain.Actor{{ID: 1}}, nil
+	})
+	require.NoError(t, err)
+
+	actors, _ := cache.Get(firstPage, func() ([]domain.Actor, error) { return []domain.Actor{{ID: 2}}, nil })
+	assert.Equal(t, 2, actors[0].ID)
+}
+
+// recordingPublisher запоминает опубликованные ключи сброса
+type recordingPublisher struct {
+	keys []string
+	err  error
+}
+
+func (p *recordingPublisher) Publish(_ context.Context, key string) error {
+	p.keys = append(p.keys, key)
+	return p.err
+}
+
+func TestActorsWithMoviesCache_Publisher(t *testing.T) {
+	publisher := &recordingPublisher{}
+	cache := NewActorsWithMoviesCache(time.Minute).WithPublisher(publisher)
+	loads := 0
+	load := func() ([]domain.Actor, error) {
+		loads++
+		return []domain.Actor{{ID: loads}}, nil
+	}
+	_, _ = cache.Get(firstPage, load)
+
+	// Сброс по сообщению другого экземпляра не рассылается повторно
+	cache.InvalidateLocal()
+	actors, _ := cache.Get(firstPage, load)
+	assert.Equal(t, 2, actors[0].ID)
+	assert.Empty(t, publisher.keys)
+
+	// Собственный сброс рассылается; ошибка Redis не мешает сбросить локальный кэш
+	publisher.err = errors.New("redis down")
+	cache.Invalidate()
+	actors, _ = cache.Get(firstPage, load)
+	assert.Equal(t, 3, actors[0].ID)
+	assert.Equal(t, []string{ActorsWithMoviesCacheKey}, publisher.keys)
+}
+
+func TestActorsWithMoviesCache_Nil(t *testing.T) {
+	var cache *ActorsWithMoviesCache
+	cache.Invalidate()
+	cache.InvalidateLocal()
+	assert.Nil(t, cache.WithPublisher(&recordingPublisher{}))
+
+	actors, err := cache.Get(firstPage, func() ([]domain.Actor, error) { return []domain.Actor{{ID: 1}}, nil })
+	require.NoError(t, err)
+	assert.Len(t, actors, 1)
+}
@@ -3,22 +3,80 @@ package service
 import (
 	"cinematique/internal/domain"
 	"cinematique/internal/auth"
+	"cinematique/internal/mailer"
 	"cinematique/internal/repository"
+	"context"
+	"database/sql"
+	"errors"
 	"fmt"
-	"golang.org/x/crypto/bcrypt"
+	"log"
+	"time"
 )
 
+// LockoutNotifier вызывается, когда учётная запись блокируется после серии неудачных входов
+type LockoutNotifier func(user domain.User, until time.Time)
+
 type AuthService struct {
-	repo *repository.UserRepository
+	repo   *repository.UserRepository
+	policy PasswordPolicy
+	hasher PasswordHasher
+
+	maxFailedLogins int // 0 — блокировка выключена
+	lockoutDuration time.Duration
+	onLockout       LockoutNotifier
+
+	mailer   mailer.Mailer // nil — сброс пароля по email выключен
+	resetURL string
+	resetTTL time.Duration
+
+	now func() time.Time
 }
 
 func NewAuthService(repo *repository.UserRepository) *AuthService {
-	return &AuthService{repo: repo}
+	return &AuthService{
+		repo:   repo,
+		policy: DefaultPasswordPolicy,
+		hasher: DefaultPasswordHasher,
+		now:    time.Now,
+	}
+}
+
+// WithPasswordPolicy задаёт требования к паролю при регистрации
+func (s *AuthService) WithPasswordPolicy(policy PasswordPolicy) *AuthService {
+	s.policy = policy
+	return s
+}
+
+// WithBcryptCost задаёт стоимость bcrypt для новых хэшей; значения вне
+// [bcrypt.MinCost, bcrypt.MaxCost] заменяются на bcrypt.DefaultCost
+func (s *AuthService) WithBcryptCost(cost int) *AuthService {
+	s.hasher.BcryptCost = cost
+	s.hasher = s.hasher.normalize()
+	return s
+}
+
+// WithPasswordHasher задаёт алгоритм и параметры хэширования паролей. Хэши других
+// алгоритмов продолжают проверяться и пересчитываются при следующем входе пользователя
+func (s *AuthService) WithPasswordHasher(hasher PasswordHasher) *AuthService {
+	s.hasher = hasher.normalize()
+	return s
+}
+
+// WithLockout включает блокировку учётной записи на duration после maxFailures
+// неудачных входов подряд; notify (может быть nil) вызывается при каждой блокировке
+func (s *AuthService) WithLockout(maxFailures int, duration time.Duration, notify LockoutNotifier) *AuthService {
+	s.maxFailedLogins = maxFailures
+	s.lockoutDuration = duration
+	s.onLockout = notify
+	return s
 }
 
 // Register регистрирует пользователя
 func (s *AuthService) Register(username, email, password, role string) (int, error) {
-	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err := s.policy.Check(password); err != nil {
+		return 0, err
+	}
+	hash, err := s.hasher.Hash(password)
 	if err != nil {
 		return 0, err
 	}
@@ -28,7 +86,7 @@ func (s *AuthService) Register(username, email, password, role string) (int, err
 	user := domain.User{
 		Username:     username,
 		Email:        email,
-		PasswordHash: string(hash),
+		PasswordHash: hash,
 		Role:         role,
 	}
 	return s.repo.CreateUser(user)
@@ -42,14 +100,31 @@ func (s *AuthService) Login(username, password string) (*auth.TokenPair, error)
 		return nil, fmt.Errorf("invalid credentials")
 	}
 
+	// Заблокированная учётная запись не проверяет пароль, чтобы перебор не продолжался
+	now := s.now()
+	if user.LockedUntil != nil && user.LockedUntil.After(now) {
+		return nil, &domain.AccountLockedError{Until: *user.LockedUntil}
+	}
+
 	// Проверяем пароль
-	err = bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password))
+	err = s.hasher.Verify(user.PasswordHash, password)
 	if err != nil {
+		if lockErr := s.registerFailedLogin(user, now); lockErr != nil {
+			return nil, lockErr
+		}
 		return nil, fmt.Errorf("invalid credentials")
 	}
 
+	if user.FailedLogins > 0 || user.LockedUntil != nil {
+		if err := s.repo.ResetFailedLogins(user.ID); err != nil {
+			// Вход не срываем: счётчик обнулится при следующем успешном входе
+			log.Printf("Failed to reset failed logins for user %d: %v", user.ID, err)
+		}
+	}
+	s.rehashPassword(user, password)
+
 	// Генерируем JWT токены
-	tokenPair, err := auth.GenerateJWT(user.ID, user.Username, user.Role)
+	tokenPair, err := auth.GenerateJWTWithVersion(user.ID, user.Username, user.Role, user.TokenVersion)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate token: %v", err)
 	}
@@ -57,6 +132,48 @@ func (s *AuthService) Login(username, password string) (*auth.TokenPair, error)
 	return tokenPair, nil
 }
 
+// rehashPassword пересчитывает устаревший хэш пароля текущим алгоритмом. Пароль
+// в открытом виде есть только при входе, поэтому хэши переходят на новый алгоритм
+// постепенно; ошибка пересчёта вход не срывает
+func (s *AuthService) rehashPassword(user domain.User, password string) {
+	if !s.hasher.NeedsRehash(user.PasswordHash) {
+		return
+	}
+	hash, err := s.hasher.Hash(password)
+	if err != nil {
+		log.Printf("Failed to rehash password for user %d: %v", user.ID, err)
+		return
+	}
+	if err := s.repo.UpdatePasswordHash(user.ID, user.PasswordHash, hash); err != nil {
+		log.Printf("Failed to save rehashed password for user %d: %v", user.ID, err)
+		return
+	}
+	passwordRehashesTotal.WithLabelValues(passwordHashAlgorithm(user.PasswordHash), s.hasher.Algorithm).Inc()
+}
+
+// registerFailedLogin учитывает неудачный вход и возвращает *domain.AccountLockedError,
+// если учётная запись оказалась заблокирована
+func (s *AuthService) registerFailedLogin(user domain.User, now time.Time) error {
+	if s.maxFailedLogins <= 0 {
+		return nil
+	}
+	// PostgreSQL хранит время с точностью до микросекунд; по совпадению с until
+	// отличаем собственную блокировку от установленной параллельным запросом
+	until := now.Add(s.lockoutDuration).Truncate(time.Microsecond)
+	_, lockedUntil, err := s.repo.RegisterFailedLogin(user.ID, s.maxFailedLogins, until)
+	if err != nil {
+		log.Printf("Failed to register failed login for user %d: %v", user.ID, err)
+		return nil
+	}
+	if lockedUntil == nil || !lockedUntil.After(now) {
+		return nil
+	}
+	if lockedUntil.Equal(until) && s.onLockout != nil {
+		s.onLockout(user, until)
+	}
+	return &domain.AccountLockedError{Until: *lockedUntil}
+}
+
 // RefreshToken обновляет access token с помощью refresh token
 func (s *AuthService) RefreshToken(refreshToken string) (*auth.TokenPair, error) {
 	// Валидируем refresh token и получаем claims
@@ -64,6 +181,9 @@ func (s *AuthService) RefreshToken(refreshToken string) (*auth.TokenPair, error)
 	if err != nil {
 		return nil, fmt.Errorf("invalid refresh token")
 	}
+	if err := auth.CheckRevoked(context.Background(), claims); err != nil {
+		return nil, fmt.Errorf("invalid refresh token")
+	}
 
 	// Получаем пользователя по ID из токена
 	user, err := s.repo.GetByID(claims.UserID)
@@ -71,8 +191,8 @@ func (s *AuthService) RefreshToken(refreshToken string) (*auth.TokenPair, error)
 		return nil, fmt.Errorf("user not found")
 	}
 
-	// Генерируем новую пару токенов
-	newTokenPair, err := auth.GenerateJWT(user.ID, user.Username, user.Role)
+	// Генерируем новую пару токенов с текущей ролью и версией из учётной записи
+	newTokenPair, err := auth.GenerateJWTWithVersion(user.ID, user.Username, user.Role, user.TokenVersion)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate new token pair: %v", err)
 	}
@@ -93,3 +213,100 @@ func (s *AuthService) Logout(refreshToken string) error {
 
 	return nil
 }
+
+// GetProfile возвращает учётную запись пользователя
+func (s *AuthService) GetProfile(userID int) (domain.User, error) {
+	user, err := s.repo.GetByID(userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.User{}, domain.ErrUserNotFound
+	}
+	return user, err
+}
+
+// UpdateProfile меняет email и (или) пароль пользователя после проверки текущего пароля.
+// После смены пароля все ранее выпущенные токены отзываются, и пользователь входит заново
+func (s *AuthService) UpdateProfile(ctx context.Context, userID int, currentPassword string, email, newPassword *string) (domain.User, error) {
+	user, err := s.GetProfile(userID)
+	if err != nil {
+		return domain.User{}, err
+	}
+	if err := s.verifyPassword(user, currentPassword); err != nil {
+		return domain.User{}, err
+	}
+
+	update := domain.UserUpdate{Email: email}
+	if newPassword != nil {
+		if err := s.policy.Check(*newPassword); err != nil {
+			return domain.User{}, err
+		}
+		passwordHash, err := s.hasher.Hash(*newPassword)
+		if err != nil {
+			return domain.User{}, err
+		}
+		update.PasswordHash = &passwordHash
+	}
+	if update.Email == nil && update.PasswordHash == nil {
+		return domain.User{}, domain.ErrNoFieldsToUpdate
+	}
+
+	if err := s.repo.UpdateUser(userID, update); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.User{}, domain.ErrUserNotFound
+		}
+		return domain.User{}, fmt.Errorf("updating user: %w", err)
+	}
+	if update.PasswordHash != nil {
+		if err := auth.RevokeUserTokens(ctx, userID); err != nil {
+			return domain.User{}, fmt.Errorf("revoking tokens: %w", err)
+		}
+	}
+	if email != nil {
+		user.Email = *email
+	}
+	return user, nil
+}
+
+// DeleteAccount удаляет учётную запись пользователя после проверки пароля и отзывает её токены
+func (s *AuthService) DeleteAccount(ctx context.Context, userID int, currentPassword string) error {
+	user, err := s.GetProfile(userID)
+	if err != nil {
+		return err
+	}
+	if err := s.verifyPassword(user, currentPassword); err != nil {
+		return err
+	}
+
+	// Токены отзываются до удаления: если удаление не пройдёт, пользователь просто войдёт заново
+	if err := auth.RevokeUserTokens(ctx, userID); err != nil {
+		return fmt.Errorf("revoking tokens: %w", err)
+	}
+	if err := s.repo.DeleteUser(userID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.ErrUserNotFound
+		}
+		return fmt.Errorf("deleting user: %w", err)
+	}
+	log.Printf("User %d deleted their account", userID)
+	return nil
+}
+
+// verifyPassword проверяет текущий пароль пользователя. Неудачные попытки учитываются
+// так же, как при входе, чтобы украденный токен не позволял подбирать пароль
+func (s *AuthService) verifyPassword(user domain.User, password string) error {
+	now := s.now()
+	if user.LockedUntil != nil && user.LockedUntil.After(now) {
+		return &domain.AccountLockedError{Until: *user.LockedUntil}
+	}
+	if err := s.hasher.Verify(user.PasswordHash, password); err != nil {
+		if lockErr := s.registerFailedLogin(user, now); lockErr != nil {
+			return lockErr
+		}
+		return domain.ErrWrongPassword
+	}
+	if user.FailedLogins > 0 {
+		if err := s.repo.ResetFailedLogins(user.ID); err != nil {
+			log.Printf("Failed to reset failed logins for user %d: %v", user.ID, err)
+		}
+	}
+	return nil
+}
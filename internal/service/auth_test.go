@@ -0,0 +1,406 @@
+package service
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"net/url"
+	"regexp"
+	"testing"
+	"time"
+
+	"cinematique/internal/auth"
+	"cinematique/internal/domain"
+	"cinematique/internal/mailer"
+	"cinematique/internal/repository"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	testifymock "github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var userColumns = []string{"id", "username", "email", "password_hash", "role", "failed_logins", "locked_until", "token_version"}
+
+// newTestAuthService создаёт сервис с репозиторием поверх sqlmock и фиксированным временем
+func newTestAuthService(t *testing.T, now time.Time) (*AuthService, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	svc := NewAuthService(repository.NewUserRepository(db)).WithBcryptCost(bcrypt.MinCost)
+	svc.now = func() time.Time { return now }
+	return svc, mock
+}
+
+func TestPasswordPolicy_Check(t *testing.T) {
+	strict := PasswordPolicy{MinLength: 10, RequireUpper: true, RequireLower: true, RequireDigit: true, RequireSymbol: true}
+
+	assert.NoError(t, strict.Check("Correct-Horse-42"))
+
+	var weak *domain.WeakPasswordError
+	require.ErrorAs(t, strict.Check("horse"), &weak)
+	assert.Equal(t, []string{"at least 10 characters", "an uppercase letter", "a digit", "a special character"}, weak.Violations)
+	assert.ErrorIs(t, weak, domain.ErrWeakPassword)
+
+	assert.NoError(t, DefaultPasswordPolicy.Check("password123"))
+}
+
+func TestAuthService_Register_RejectsWeakPassword(t *testing.T) {
+	svc, mock := newTestAuthService(t, time.Now())
+	svc.WithPasswordPolicy(PasswordPolicy{MinLength: 8, RequireDigit: true})
+
+	_, err := svc.Register("neo", "neo@example.com", "whiterabbit", "")
+
+	assert.ErrorIs(t, err, domain.ErrWeakPassword)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAuthService_Login_Lockout(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	hash, err := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.MinCost)
+	require.NoError(t, err)
+
+	t.Run("wrong password below threshold", func(t *testing.T) {
+		svc, mock := newTestAuthService(t, now)
+		svc.WithLockout(3, 15*time.Minute, func(domain.User, time.Time) { t.Fatal("unexpected lockout") })
+		mock.ExpectQuery(`SELECT .* FROM users WHERE username = \$1`).WithArgs("neo").
+			WillReturnRows(sqlmock.NewRows(userColumns).AddRow(1, "neo", "neo@example.com", string(hash), "user", 0, nil, 0))
+		mock.ExpectQuery(`UPDATE users SET failed_logins`).
+			WithArgs(3, 3, now.Add(15*time.Minute), 1).
+			WillReturnRows(sqlmock.NewRows([]string{"failed_logins", "locked_until"}).AddRow(1, nil))
+
+		_, err := svc.Login("neo", "wrong")
+
+		assert.EqualError(t, err, "invalid credentials")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("wrong password locks account", func(t *testing.T) {
+		svc, mock := newTestAuthService(t, now)
+		var locked []string
+		svc.WithLockout(3, 15*time.Minute, func(user domain.User, until time.Time) {
+			locked = append(locked, user.Username+" "+until.Format(time.RFC3339))
+		})
+		until := now.Add(15 * time.Minute)
+		mock.ExpectQuery(`SELECT .* FROM users WHERE username = \$1`).WithArgs("neo").
+			WillReturnRows(sqlmock.NewRows(userColumns).AddRow(1, "neo", "neo@example.com", string(hash), "user", 2, nil, 0))
+		mock.ExpectQuery(`UPDATE users SET failed_logins`).
+			WithArgs(3, 3, until, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"failed_logins", "locked_until"}).AddRow(0, until))
+
+		_, err := svc.Login("neo", "wrong")
+
+		var lockedErr *domain.AccountLockedError
+		require.ErrorAs(t, err, &lockedErr)
+		assert.True(t, until.Equal(lockedErr.Until))
+		assert.Equal(t, []string{"neo 2024-05-01T12:15:00Z"}, locked)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("locked account rejects correct password", func(t *testing.T) {
+		svc, mock := newTestAuthService(t, now)
+		svc.WithLockout(3, 15*time.Minute, nil)
+		mock.ExpectQuery(`SELECT .* FROM users WHERE username = \$1`).WithArgs("neo").
+			WillReturnRows(sqlmock.NewRows(userColumns).AddRow(1, "neo", "neo@example.com", string(hash), "user", 0, now.Add(time.Minute), 0))
+
+		_, err := svc.Login("neo", "password123")
+
+		assert.ErrorIs(t, err, domain.ErrAccountLocked)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("successful login resets counter", func(t *testing.T) {
+		svc, mock := newTestAuthService(t, now)
+		svc.WithLockout(3, 15*time.Minute, nil)
+		mock.ExpectQuery(`SELECT .* FROM users WHERE username = \$1`).WithArgs("neo").
+			WillReturnRows(sqlmock.NewRows(userColumns).AddRow(1, "neo", "neo@example.com", string(hash), "user", 2, now.Add(-time.Minute), 0))
+		mock.ExpectExec(`UPDATE users SET failed_logins = \$1, locked_until = \$2 WHERE id = \$3`).
+			WithArgs(0, nil, 1).
+			WillReturnError(errors.New("connection reset"))
+
+		tokens, err := svc.Login("neo", "password123")
+
+		require.NoError(t, err)
+		assert.NotEmpty(t, tokens.AccessToken)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestAuthService_Login_RehashesPassword(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	legacy, err := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.MinCost)
+	require.NoError(t, err)
+	userRow := func(hash string) *sqlmock.Rows {
+		return sqlmock.NewRows(userColumns).AddRow(1, "neo", "neo@example.com", hash, "user", 0, nil, 0)
+	}
+
+	svc, mock := newTestAuthService(t, now)
+	svc.WithPasswordHasher(PasswordHasher{Algorithm: PasswordHashArgon2id, Argon2: testArgon2Params})
+
+	// Хэш bcrypt проверяется и после входа заменяется на argon2id
+	var rehashed capturedArg
+	mock.ExpectQuery(`SELECT .* FROM users WHERE username = \$1`).WithArgs("neo").WillReturnRows(userRow(string(legacy)))
+	mock.ExpectExec(`UPDATE users SET password_hash = \$1 WHERE id = \$2 AND password_hash = \$3`).
+		WithArgs(&rehashed, 1, string(legacy)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	_, err = svc.Login("neo", "password123")
+	require.NoError(t, err)
+	assert.NoError(t, svc.hasher.Verify(rehashed.value, "password123"))
+	assert.False(t, svc.hasher.NeedsRehash(rehashed.value))
+
+	// Актуальный хэш не пересчитывается
+	mock.ExpectQuery(`SELECT .* FROM users WHERE username = \$1`).WithArgs("neo").WillReturnRows(userRow(rehashed.value))
+	_, err = svc.Login("neo", "password123")
+	require.NoError(t, err)
+
+	// Неверный пароль не пересчитывает хэш
+	mock.ExpectQuery(`SELECT .* FROM users WHERE username = \$1`).WithArgs("neo").WillReturnRows(userRow(string(legacy)))
+	_, err = svc.Login("neo", "wrong")
+	assert.EqualError(t, err, "invalid credentials")
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAuthService_UpdateProfile(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	hash, err := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.MinCost)
+	require.NoError(t, err)
+	userRow := func() *sqlmock.Rows {
+		return sqlmock.NewRows(userColumns).AddRow(1, "neo", "neo@example.com", string(hash), "user", 0, nil, 0)
+	}
+
+	t.Run("wrong current password", func(t *testing.T) {
+		svc, mock := newTestAuthService(t, now)
+		svc.WithLockout(3, 15*time.Minute, nil)
+		email := "neo@zion.io"
+		mock.ExpectQuery(`SELECT .* FROM users WHERE id = \$1`).WithArgs(1).WillReturnRows(userRow())
+		mock.ExpectQuery(`UPDATE users SET failed_logins`).
+			WithArgs(3, 3, now.Add(15*time.Minute), 1).
+			WillReturnRows(sqlmock.NewRows([]string{"failed_logins", "locked_until"}).AddRow(1, nil))
+
+		_, err := svc.UpdateProfile(context.Background(), 1, "wrong", &email, nil)
+
+		assert.ErrorIs(t, err, domain.ErrWrongPassword)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("email change keeps tokens", func(t *testing.T) {
+		store := auth.NewMemoryRevocationStore()
+		auth.SetRevocationStore(store)
+		defer auth.SetRevocationStore(auth.NewMemoryRevocationStore())
+
+		svc, mock := newTestAuthService(t, now)
+		email := "neo@zion.io"
+		mock.ExpectQuery(`SELECT .* FROM users WHERE id = \$1`).WithArgs(1).WillReturnRows(userRow())
+		mock.ExpectExec(`UPDATE users SET email = \$1 WHERE id = \$2`).WithArgs(email, 1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		user, err := svc.UpdateProfile(context.Background(), 1, "password123", &email, nil)
+
+		require.NoError(t, err)
+		assert.Equal(t, email, user.Email)
+		_, revoked, _ := store.RevokedAt(context.Background(), 1)
+		assert.False(t, revoked)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("password change revokes tokens", func(t *testing.T) {
+		store := auth.NewMemoryRevocationStore()
+		auth.SetRevocationStore(store)
+		defer auth.SetRevocationStore(auth.NewMemoryRevocationStore())
+
+		svc, mock := newTestAuthService(t, now)
+		newPassword := "correct-horse-42"
+		mock.ExpectQuery(`SELECT .* FROM users WHERE id = \$1`).WithArgs(1).WillReturnRows(userRow())
+		mock.ExpectExec(`UPDATE users SET password_hash = \$1 WHERE id = \$2`).WithArgs(sqlmock.AnyArg(), 1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		_, err := svc.UpdateProfile(context.Background(), 1, "password123", nil, &newPassword)
+
+		require.NoError(t, err)
+		_, revoked, _ := store.RevokedAt(context.Background(), 1)
+		assert.True(t, revoked)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("nothing to change", func(t *testing.T) {
+		svc, mock := newTestAuthService(t, now)
+		mock.ExpectQuery(`SELECT .* FROM users WHERE id = \$1`).WithArgs(1).WillReturnRows(userRow())
+
+		_, err := svc.UpdateProfile(context.Background(), 1, "password123", nil, nil)
+
+		assert.ErrorIs(t, err, domain.ErrNoFieldsToUpdate)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestAuthService_DeleteAccount(t *testing.T) {
+	store := auth.NewMemoryRevocationStore()
+	auth.SetRevocationStore(store)
+	defer auth.SetRevocationStore(auth.NewMemoryRevocationStore())
+
+	hash, err := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.MinCost)
+	require.NoError(t, err)
+	svc, mock := newTestAuthService(t, time.Now())
+	mock.ExpectQuery(`SELECT .* FROM users WHERE id = \$1`).WithArgs(1).
+		WillReturnRows(sqlmock.NewRows(userColumns).AddRow(1, "neo", "neo@example.com", string(hash), "user", 0, nil, 0))
+	mock.ExpectExec(`DELETE FROM users WHERE id = \$1`).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	require.NoError(t, svc.DeleteAccount(context.Background(), 1, "password123"))
+
+	_, revoked, _ := store.RevokedAt(context.Background(), 1)
+	assert.True(t, revoked)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// capturedArg принимает любой строковый аргумент запроса и запоминает его
+type capturedArg struct{ value string }
+
+func (a *capturedArg) Match(v driver.Value) bool {
+	a.value, _ = v.(string)
+	return true
+}
+
+func TestAuthService_ForgotPassword(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	userByEmail := `SELECT .* FROM users WHERE LOWER\(email\) = LOWER\(\$1\)`
+
+	t.Run("disabled without mailer", func(t *testing.T) {
+		svc, _ := newTestAuthService(t, now)
+
+		assert.ErrorIs(t, svc.ForgotPassword(context.Background(), "neo@example.com"), domain.ErrPasswordResetOff)
+	})
+
+	t.Run("unknown email sends nothing", func(t *testing.T) {
+		svc, mock := newTestAuthService(t, now)
+		mailbox := mailer.NewMockMailer()
+		svc.WithPasswordReset(mailbox, "https://cinematique.example/reset", time.Hour)
+		mock.ExpectQuery(userByEmail).WithArgs("smith@example.com").WillReturnRows(sqlmock.NewRows(userColumns))
+
+		require.NoError(t, svc.ForgotPassword(context.Background(), "smith@example.com"))
+		mailbox.AssertNotCalled(t, "Send", testifymock.Anything, testifymock.Anything)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("keycloak account without password sends nothing", func(t *testing.T) {
+		svc, mock := newTestAuthService(t, now)
+		mailbox := mailer.NewMockMailer()
+		svc.WithPasswordReset(mailbox, "https://cinematique.example/reset", time.Hour)
+		mock.ExpectQuery(userByEmail).WithArgs("alice@example.com").
+			WillReturnRows(sqlmock.NewRows(userColumns).AddRow(2, "alice", "alice@example.com", "", "user", 0, nil, 0))
+
+		require.NoError(t, svc.ForgotPassword(context.Background(), "alice@example.com"))
+		mailbox.AssertNotCalled(t, "Send", testifymock.Anything, testifymock.Anything)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("stores token hash and emails the link", func(t *testing.T) {
+		svc, mock := newTestAuthService(t, now)
+		mailbox := mailer.NewMockMailer()
+		svc.WithPasswordReset(mailbox, "https://cinematique.example/reset?lang=ru", 30*time.Minute)
+		var savedHash capturedArg
+		mock.ExpectQuery(userByEmail).WithArgs("Neo@Example.com").
+			WillReturnRows(sqlmock.NewRows(userColumns).AddRow(1, "neo", "neo@example.com", "hash", "user", 0, nil, 0))
+		mock.ExpectExec(`INSERT INTO password_reset_tokens`).
+			WithArgs(1, &savedHash, now.Add(30*time.Minute)).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		var sent mailer.Message
+		mailbox.On("Send", testifymock.Anything, testifymock.Anything).
+			Run(func(args testifymock.Arguments) { sent = args.Get(1).(mailer.Message) }).
+			Return(nil)
+
+		require.NoError(t, svc.ForgotPassword(context.Background(), "Neo@Example.com"))
+
+		assert.Equal(t, "neo@example.com", sent.To)
+		link := regexp.MustCompile(`https://\S+`).FindString(sent.Body)
+		u, err := url.Parse(link)
+		require.NoError(t, err)
+		assert.Equal(t, "ru", u.Query().Get("lang"))
+		token := u.Query().Get("token")
+		assert.Len(t, token, 43)
+		// В базе только хэш токена из письма, сам токен не сохраняется
+		assert.Equal(t, hashResetToken(token), savedHash.value)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestAuthService_ResetPassword(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	consume := `UPDATE password_reset_tokens SET used_at = \$1 WHERE token_hash = \$2 AND used_at IS NULL AND expires_at > \$3 RETURNING user_id`
+
+	t.Run("valid token sets password and revokes tokens", func(t *testing.T) {
+		store := auth.NewMemoryRevocationStore()
+		auth.SetRevocationStore(store)
+		defer auth.SetRevocationStore(auth.NewMemoryRevocationStore())
+
+		svc, mock := newTestAuthService(t, now)
+		mock.ExpectBegin()
+		mock.ExpectQuery(consume).WithArgs(now, hashResetToken("reset-token"), now).
+			WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(1))
+		mock.ExpectExec(`UPDATE users SET password_hash .* token_version = token_version \+ 1`).WithArgs(sqlmock.AnyArg(), 0, nil, 1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(`UPDATE password_reset_tokens SET used_at`).WithArgs(now, 1).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectCommit()
+
+		require.NoError(t, svc.ResetPassword(context.Background(), "reset-token", "correct-horse-42"))
+
+		_, revoked, _ := store.RevokedAt(context.Background(), 1)
+		assert.True(t, revoked)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("revocation failure does not fail the reset", func(t *testing.T) {
+		auth.SetRevocationStore(unavailableRevocations{})
+		defer auth.SetRevocationStore(auth.NewMemoryRevocationStore())
+
+		svc, mock := newTestAuthService(t, now)
+		mock.ExpectBegin()
+		mock.ExpectQuery(consume).WithArgs(now, hashResetToken("reset-token"), now).
+			WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(1))
+		mock.ExpectExec(`UPDATE users SET password_hash .* token_version = token_version \+ 1`).WithArgs(sqlmock.AnyArg(), 0, nil, 1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(`UPDATE password_reset_tokens SET used_at`).WithArgs(now, 1).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectCommit()
+
+		// Пароль и версия токенов уже сменены, поэтому клиент получает успех
+		require.NoError(t, svc.ResetPassword(context.Background(), "reset-token", "correct-horse-42"))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("used or expired token", func(t *testing.T) {
+		svc, mock := newTestAuthService(t, now)
+		mock.ExpectBegin()
+		mock.ExpectQuery(consume).WithArgs(now, hashResetToken("reset-token"), now).
+			WillReturnRows(sqlmock.NewRows([]string{"user_id"}))
+		mock.ExpectRollback()
+
+		err := svc.ResetPassword(context.Background(), "reset-token", "correct-horse-42")
+
+		assert.ErrorIs(t, err, domain.ErrInvalidResetToken)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("weak password keeps the token", func(t *testing.T) {
+		svc, mock := newTestAuthService(t, now)
+		svc.WithPasswordPolicy(PasswordPolicy{MinLength: 12})
+
+		err := svc.ResetPassword(context.Background(), "reset-token", "short")
+
+		assert.ErrorIs(t, err, domain.ErrWeakPassword)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+// unavailableRevocations — хранилище отзыва, которое не отвечает
+type unavailableRevocations struct{}
+
+func (unavailableRevocations) RevokeUserTokens(context.Context, int, time.Time) error {
+	return auth.ErrRevocationUnavailable
+}
+
+func (unavailableRevocations) RevokedAt(context.Context, int) (time.Time, bool, error) {
+	return time.Time{}, false, auth.ErrRevocationUnavailable
+}
@@ -0,0 +1,85 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"cinematique/internal/domain"
+)
+
+// checkMovieExists возвращает domain.ErrMovieNotFound, если фильма нет
+func (s *MovieService) checkMovieExists(ctx context.Context, movieID int) error {
+	if _, err := s.store.GetByID(ctx, movieID); err != nil {
+		if errors.Is(err, domain.ErrMovieNotFound) {
+			return domain.ErrMovieNotFound
+		}
+		return fmt.Errorf("checking movie existence: %w", err)
+	}
+	return nil
+}
+
+// GetAvailability возвращает окна доступности фильма по регионам
+func (s *MovieService) GetAvailability(ctx context.Context, movieID int) ([]domain.Availability, error) {
+	ctx, span := tracer().Start(ctx, "MovieService.GetAvailability")
+	defer span.End()
+
+	if err := s.checkMovieExists(ctx, movieID); err != nil {
+		return nil, err
+	}
+	windows, err := s.store.GetAvailability(ctx, movieID)
+	if err != nil {
+		return nil, fmt.Errorf("getting availability: %w", err)
+	}
+	return windows, nil
+}
+
+// AddAvailability добавляет фильму окно доступности и возвращает его с присвоенным ID
+func (s *MovieService) AddAvailability(ctx context.Context, window domain.Availability) (domain.Availability, error) {
+	ctx, span := tracer().Start(ctx, "MovieService.AddAvailability")
+	defer span.End()
+
+	if err := s.checkMovieExists(ctx, window.MovieID); err != nil {
+		return domain.Availability{}, err
+	}
+	id, err := s.store.AddAvailability(ctx, window)
+	if err != nil {
+		return domain.Availability{}, fmt.Errorf("adding availability: %w", err)
+	}
+	window.ID = id
+	return window, nil
+}
+
+// UpdateAvailability заменяет регион и даты окна доступности фильма
+func (s *MovieService) UpdateAvailability(ctx context.Context, window domain.Availability) error {
+	ctx, span := tracer().Start(ctx, "MovieService.UpdateAvailability")
+	defer span.End()
+
+	if err := s.checkMovieExists(ctx, window.MovieID); err != nil {
+		return err
+	}
+	if err := s.store.UpdateAvailability(ctx, window); err != nil {
+		if errors.Is(err, domain.ErrAvailabilityNotFound) {
+			return domain.ErrAvailabilityNotFound
+		}
+		return fmt.Errorf("updating availability: %w", err)
+	}
+	return nil
+}
+
+// DeleteAvailability удаляет окно доступности фильма
+func (s *MovieService) DeleteAvailability(ctx context.Context, movieID, windowID int) error {
+	ctx, span := tracer().Start(ctx, "MovieService.DeleteAvailability")
+	defer span.End()
+
+	if err := s.checkMovieExists(ctx, movieID); err != nil {
+		return err
+	}
+	if err := s.store.DeleteAvailability(ctx, movieID, windowID); err != nil {
+		if errors.Is(err, domain.ErrAvailabilityNotFound) {
+			return domain.ErrAvailabilityNotFound
+		}
+		return fmt.Errorf("deleting availability: %w", err)
+	}
+	return nil
+}
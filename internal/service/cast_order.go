@@ -0,0 +1,55 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"cinematique/internal/domain"
+)
+
+// ReorderActors задаёт порядок актёров в титрах фильма. actorIDs — ведущие актёры в нужном порядке;
+// не перечисленные сохраняют прежний взаимный порядок и идут после них. Возвращает состав в новом порядке
+func (s *MovieService) ReorderActors(ctx context.Context, movieID int, actorIDs []int) ([]domain.Actor, error) {
+	ctx, span := tracer().Start(ctx, "MovieService.ReorderActors")
+	defer span.End()
+
+	if err := s.checkMovieExists(ctx, movieID); err != nil {
+		return nil, err
+	}
+	cast, err := s.store.GetActorsForMovieByID(ctx, movieID)
+	if err != nil {
+		return nil, fmt.Errorf("getting movie actors: %w", err)
+	}
+
+	inCast := make(map[int]bool, len(cast))
+	for _, actor := range cast {
+		inCast[actor.ID] = true
+	}
+	listed := make(map[int]bool, len(actorIDs))
+	order := make([]int, 0, len(cast))
+	for _, actorID := range actorIDs {
+		if !inCast[actorID] {
+			return nil, fmt.Errorf("actor with ID %d, movie %d: %w", actorID, movieID, domain.ErrActorNotInMovie)
+		}
+		listed[actorID] = true
+		order = append(order, actorID)
+	}
+	for _, actor := range cast {
+		if !listed[actor.ID] {
+			order = append(order, actor.ID)
+		}
+	}
+
+	if err := s.store.SetCastOrder(ctx, movieID, order); err != nil {
+		return nil, fmt.Errorf("setting cast order: %w", err)
+	}
+	s.actorsCache.Invalidate()
+	log.Printf("Reordered cast of movie (ID: %d)", movieID)
+
+	cast, err = s.store.GetActorsForMovieByID(ctx, movieID)
+	if err != nil {
+		return nil, fmt.Errorf("getting movie actors: %w", err)
+	}
+	return cast, nil
+}
@@ -0,0 +1,58 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"cinematique/internal/domain"
+	"cinematique/internal/testutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func castIDs(cast []domain.Actor) []int {
+	ids := make([]int, 0, len(cast))
+	for _, actor := range cast {
+		ids = append(ids, actor.ID)
+	}
+	return ids
+}
+
+func TestMovieService_ReorderActors(t *testing.T) {
+	// newCatalog возвращает каталог с фильмом и четырьмя актёрами в его титрах
+	newCatalog := func() (*testutil.Catalog, int, []int) {
+		catalog := testutil.NewCatalog()
+		movieID := catalog.SeedMovie(domain.Movie{Title: "Heat", ReleaseYear: 1995})
+		var actors []int
+		for _, name := range []string{"Al Pacino", "Robert De Niro", "Val Kilmer", "Jon Voight"} {
+			actors = append(actors, catalog.SeedActor(domain.Actor{Name: name}))
+		}
+		catalog.SeedCast(movieID, actors...)
+		return catalog, movieID, actors
+	}
+
+	t.Run("listed actors lead, the rest keep their order", func(t *testing.T) {
+		catalog, movieID, a := newCatalog()
+		cast, err := NewMovie(catalog.Movies(), nil).ReorderActors(context.Background(), movieID, []int{a[2], a[0]})
+		require.NoError(t, err)
+		assert.Equal(t, []int{a[2], a[0], a[1], a[3]}, castIDs(cast))
+		assert.Equal(t, 1, cast[0].BillingOrder)
+		assert.Equal(t, 4, cast[3].BillingOrder)
+		assert.Equal(t, castIDs(cast), catalog.CastIDs(movieID))
+	})
+
+	t.Run("actor not in cast", func(t *testing.T) {
+		catalog, movieID, a := newCatalog()
+		outsider := catalog.SeedActor(domain.Actor{Name: "Keanu Reeves"})
+		_, err := NewMovie(catalog.Movies(), nil).ReorderActors(context.Background(), movieID, []int{a[1], outsider})
+		assert.ErrorIs(t, err, domain.ErrActorNotInMovie)
+		assert.Equal(t, a, catalog.CastIDs(movieID))
+	})
+
+	t.Run("movie not found", func(t *testing.T) {
+		catalog, _, a := newCatalog()
+		_, err := NewMovie(catalog.Movies(), nil).ReorderActors(context.Background(), 404, []int{a[0]})
+		assert.ErrorIs(t, err, domain.ErrMovieNotFound)
+	})
+}
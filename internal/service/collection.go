@@ -0,0 +1,139 @@
+package service
+
+import (
+	"cinematique/internal/domain"
+	"context"
+	"errors"
+	"fmt"
+)
+
+// StoreCollection определяет интерфейс для работы с хранилищем подборок
+type StoreCollection interface {
+	Create(ctx context.Context, collection domain.Collection, movieIDs []int) (int, error) // создать подборку с фильмами
+	GetByID(ctx context.Context, id int) (domain.Collection, error)                        // получить подборку без фильмов
+	GetAll(ctx context.Context) ([]domain.Collection, error)                               // получить все подборки
+	Update(ctx context.Context, collection domain.Collection) error                        // обновить название и описание
+	Delete(ctx context.Context, id int) error                                              // удалить подборку
+	GetMovies(ctx context.Context, collectionID int) ([]domain.Movie, error)               // фильмы подборки по порядку
+	SetMovies(ctx context.Context, collectionID int, movieIDs []int) error                 // заменить фильмы подборки
+}
+
+// CollectionService реализует бизнес-логику подборок фильмов
+type CollectionService struct {
+	store      StoreCollection
+	movieStore StoreMovie
+}
+
+// NewCollection создаёт сервис подборок
+func NewCollection(store StoreCollection, movieStore StoreMovie) *CollectionService {
+	return &CollectionService{store: store, movieStore: movieStore}
+}
+
+// Create создаёт подборку с фильмами movieIDs в порядке просмотра
+func (s *CollectionService) Create(ctx context.Context, collection domain.Collection, movieIDs []int) (int, error) {
+	ctx, span := tracer().Start(ctx, "CollectionService.Create")
+	defer span.End()
+
+	if err := s.checkMovies(ctx, movieIDs); err != nil {
+		return 0, err
+	}
+	id, err := s.store.Create(ctx, collection, movieIDs)
+	if err != nil {
+		return 0, fmt.Errorf("creating collection: %w", err)
+	}
+	return id, nil
+}
+
+// GetByID возвращает подборку вместе с фильмами в порядке просмотра
+func (s *CollectionService) GetByID(ctx context.Context, id int) (domain.Collection, error) {
+	ctx, span := tracer().Start(ctx, "CollectionService.GetByID")
+	defer span.End()
+
+	collection, err := s.store.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, domain.ErrCollectionNotFound) {
+			return domain.Collection{}, domain.ErrCollectionNotFound
+		}
+		return domain.Collection{}, fmt.Errorf("getting collection: %w", err)
+	}
+	movies, err := s.store.GetMovies(ctx, id)
+	if err != nil {
+		return domain.Collection{}, fmt.Errorf("getting collection movies: %w", err)
+	}
+	collection.Movies = movies
+	return collection, nil
+}
+
+// GetAll возвращает все подборки без фильмов
+func (s *CollectionService) GetAll(ctx context.Context) ([]domain.Collection, error) {
+	ctx, span := tracer().Start(ctx, "CollectionService.GetAll")
+	defer span.End()
+
+	return s.store.GetAll(ctx)
+}
+
+// Update обновляет название и описание подборки
+func (s *CollectionService) Update(ctx context.Context, collection domain.Collection) error {
+	ctx, span := tracer().Start(ctx, "CollectionService.Update")
+	defer span.End()
+
+	if err := s.store.Update(ctx, collection); err != nil {
+		if errors.Is(err, domain.ErrCollectionNotFound) {
+			return domain.ErrCollectionNotFound
+		}
+		return fmt.Errorf("updating collection: %w", err)
+	}
+	return nil
+}
+
+// Delete удаляет подборку; сами фильмы не удаляются
+func (s *CollectionService) Delete(ctx context.Context, id int) error {
+	ctx, span := tracer().Start(ctx, "CollectionService.Delete")
+	defer span.End()
+
+	if err := s.store.Delete(ctx, id); err != nil {
+		if errors.Is(err, domain.ErrCollectionNotFound) {
+			return domain.ErrCollectionNotFound
+		}
+		return fmt.Errorf("deleting collection: %w", err)
+	}
+	return nil
+}
+
+// SetMovies заменяет фильмы подборки; порядок movieIDs становится порядком просмотра
+func (s *CollectionService) SetMovies(ctx context.Context, collectionID int, movieIDs []int) error {
+	ctx, span := tracer().Start(ctx, "CollectionService.SetMovies")
+	defer span.End()
+
+	if _, err := s.store.GetByID(ctx, collectionID); err != nil {
+		if errors.Is(err, domain.ErrCollectionNotFound) {
+			return domain.ErrCollectionNotFound
+		}
+		return fmt.Errorf("checking collection existence: %w", err)
+	}
+	if err := s.checkMovies(ctx, movieIDs); err != nil {
+		return err
+	}
+	if err := s.store.SetMovies(ctx, collectionID, movieIDs); err != nil {
+		return fmt.Errorf("setting collection movies: %w", err)
+	}
+	return nil
+}
+
+// checkMovies проверяет, что фильмы существуют и не повторяются
+func (s *CollectionService) checkMovies(ctx context.Context, movieIDs []int) error {
+	seen := make(map[int]bool, len(movieIDs))
+	for _, movieID := range movieIDs {
+		if seen[movieID] {
+			return domain.ErrCollectionDuplicate
+		}
+		seen[movieID] = true
+		if _, err := s.movieStore.GetByID(ctx, movieID); err != nil {
+			if errors.Is(err, domain.ErrMovieNotFound) {
+				return fmt.Errorf("movie %d: %w", movieID, domain.ErrMovieNotFound)
+			}
+			return fmt.Errorf("checking movie %d: %w", movieID, err)
+		}
+	}
+	return nil
+}
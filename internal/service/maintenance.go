@@ -0,0 +1,56 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cinematique/internal/domain"
+)
+
+// StoreMaintenance определяет служебные операции над схемой базы данных
+type StoreMaintenance interface {
+	SearchIndexes() []string                              // индексы поиска, которые можно перестроить
+	RebuildIndex(ctx context.Context, index string) error // перестроить индекс без блокировки записи
+	AnalyzeCatalog(ctx context.Context) error             // обновить статистику планировщика по каталогу
+}
+
+// analyzeStep — последний шаг перестроения: обновление статистики планировщика
+const analyzeStep = "analyze"
+
+// MaintenanceService выполняет служебные операции над базой данных
+type MaintenanceService struct {
+	store StoreMaintenance
+}
+
+// NewMaintenance создаёт сервис служебных операций
+func NewMaintenance(store StoreMaintenance) *MaintenanceService {
+	return &MaintenanceService{store: store}
+}
+
+// Reindex по очереди перестраивает индексы поиска и обновляет статистику планировщика.
+// Перед каждым шагом progress получает число выполненных шагов, их общее число и текущий шаг.
+// Первая ошибка останавливает перестроение: уже перестроенные индексы остаются рабочими
+func (s *MaintenanceService) Reindex(ctx context.Context, progress func(done, total int, step string)) (domain.ReindexResult, error) {
+	ctx, span := tracer().Start(ctx, "MaintenanceService.Reindex")
+	defer span.End()
+
+	start := time.Now()
+	indexes := s.store.SearchIndexes()
+	total := len(indexes) + 1
+	result := domain.ReindexResult{Indexes: make([]string, 0, len(indexes))}
+	for i, index := range indexes {
+		progress(i, total, index)
+		if err := s.store.RebuildIndex(ctx, index); err != nil {
+			return domain.ReindexResult{}, fmt.Errorf("rebuilding index %s: %w", index, err)
+		}
+		result.Indexes = append(result.Indexes, index)
+	}
+	progress(len(indexes), total, analyzeStep)
+	if err := s.store.AnalyzeCatalog(ctx); err != nil {
+		return domain.ReindexResult{}, fmt.Errorf("analyzing catalog tables: %w", err)
+	}
+	progress(total, total, "")
+	result.Duration = time.Since(start)
+	return result, nil
+}
@@ -0,0 +1,65 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeMaintenanceStore записывает выполненные шаги и падает на индексе failOn
+type fakeMaintenanceStore struct {
+	indexes []string
+	failOn  string
+	steps   []string
+}
+
+func (f *fakeMaintenanceStore) SearchIndexes() []string { return f.indexes }
+
+func (f *fakeMaintenanceStore) RebuildIndex(_ context.Context, index string) error {
+	if index == f.failOn {
+		return errors.New("lock timeout")
+	}
+	f.steps = append(f.steps, index)
+	return nil
+}
+
+func (f *fakeMaintenanceStore) AnalyzeCatalog(context.Context) error {
+	f.steps = append(f.steps, analyzeStep)
+	return nil
+}
+
+func TestMaintenanceService_Reindex(t *testing.T) {
+	t.Run("rebuilds indexes and reports progress", func(t *testing.T) {
+		store := &fakeMaintenanceStore{indexes: []string{"idx_a", "idx_b"}}
+		var reports []string
+		result, err := NewMaintenance(store).Reindex(context.Background(), func(done, total int, step string) {
+			reports = append(reports, fmt.Sprintf("%d/%d %s", done, total, step))
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"idx_a", "idx_b"}, result.Indexes)
+		assert.Equal(t, []string{"idx_a", "idx_b", analyzeStep}, store.steps)
+		assert.Equal(t, []string{"0/3 idx_a", "1/3 idx_b", "2/3 analyze", "3/3 "}, reports)
+	})
+
+	t.Run("stops at first failure", func(t *testing.T) {
+		store := &fakeMaintenanceStore{indexes: []string{"idx_a", "idx_b", "idx_c"}, failOn: "idx_b"}
+		_, err := NewMaintenance(store).Reindex(context.Background(), func(int, int, string) {})
+
+		assert.ErrorContains(t, err, "rebuilding index idx_b")
+		assert.Equal(t, []string{"idx_a"}, store.steps)
+	})
+
+	t.Run("without search indexes only analyzes", func(t *testing.T) {
+		store := &fakeMaintenanceStore{}
+		result, err := NewMaintenance(store).Reindex(context.Background(), func(int, int, string) {})
+
+		require.NoError(t, err)
+		assert.Empty(t, result.Indexes)
+		assert.Equal(t, []string{analyzeStep}, store.steps)
+	})
+}
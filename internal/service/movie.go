@@ -1,61 +1,136 @@
 package service
 
 import (
+	"cinematique/internal/canary"
+	"context"
 	"cinematique/internal/domain"
 	"errors"
 	"fmt"
 	"log"
+	"sort"
+	"time"
 )
 
 // StoreMovie определяет интерфейс для работы с хранилищем фильмов
 type StoreMovie interface {
-	Create(movie domain.Movie) (int, error)                                   // создать фильм
-	GetByID(id int) (domain.Movie, error)                                     // получить фильм по ID
-	Update(movie domain.Movie) error                                          // обновить фильм
-	Delete(id int) error                                                      // удалить фильм
-	GetAll() ([]domain.Movie, error)                                          // получить все фильмы
-	AddActor(movieID, actorID int) error                                      // добавить актёра к фильму
-	RemoveActor(movieID, actorID int) error                                   // удалить актёра из фильма
-	GetActorsForMovieByID(movieID int) ([]domain.Actor, error)                // получить актёров фильма
-	RemoveAllActors(movieID int) error                                        // удалить всех актёров из фильма
-	SearchMoviesByTitle(titleFragment string) ([]domain.Movie, error)         // поиск по названию
-	SearchMoviesByActorName(actorNameFragment string) ([]domain.Movie, error) // поиск по актёру
-	GetAllMoviesSorted(sortField, sortOrder string) ([]domain.Movie, error)   // сортировка
-	CreateMovieWithActors(movie domain.Movie, actorIDs []int) (int, error)    // создать фильм с актёрами
-	UpdateMovieActors(movieID int, actorIDs []int) error                      // обновить актёров фильма
-	GetMoviesForActor(actorID int) ([]domain.Movie, error)                    // фильмы по актёру
-	PartialUpdateMovie(id int, update domain.MovieUpdate) error               // частичное обновление фильма
+	Create(ctx context.Context, movie domain.Movie) (int, error)                                                              // создать фильм
+	GetByID(ctx context.Context, id int) (domain.Movie, error)                                                                // получить фильм по ID
+	Update(ctx context.Context, movie domain.Movie) error                                                                     // обновить фильм
+	Delete(ctx context.Context, id int, policy domain.MovieDeletePolicy) error                                                // удалить фильм; при restrict фильм со ссылками не удаляется
+	GetAll(ctx context.Context) ([]domain.Movie, error)                                                                       // получить все фильмы
+	GetMoviesAfterID(ctx context.Context, afterID, limit int) ([]domain.Movie, error)                                         // страница фильмов после afterID
+	ForEachMovie(ctx context.Context, fn func(domain.Movie) error) error                                                      // обойти все фильмы без загрузки в память
+	AddActor(ctx context.Context, movieID int, member domain.CastMember) error                                                // добавить актёра к фильму
+	RemoveActor(ctx context.Context, movieID, actorID int) error                                                              // удалить актёра из фильма
+	GetActorsForMovieByID(ctx context.Context, movieID int) ([]domain.Actor, error)                                           // получить актёров фильма
+	RemoveAllActors(ctx context.Context, movieID int) error                                                                   // удалить всех актёров из фильма
+	SearchMoviesByTitle(ctx context.Context, titleFragment string, filter domain.MovieFilter) ([]domain.Movie, error)         // поиск по названию
+	SearchMoviesByTitleTrigram(ctx context.Context, titleFragment string, filter domain.MovieFilter) ([]domain.Movie, error)  // поиск по названию с учётом опечаток
+	SearchMoviesByActorName(ctx context.Context, actorNameFragment string, filter domain.MovieFilter) ([]domain.Movie, error) // поиск по актёру
+	GetMatchingActors(ctx context.Context, movieIDs []int, nameFragment string) (map[int][]domain.Actor, error)               // актёры фильмов с подходящим именем
+	GetAllMoviesSorted(ctx context.Context, query domain.MovieListQuery) ([]domain.Movie, error)                              // сортировка и пагинация
+	CountMovies(ctx context.Context, filter domain.MovieFilter) (int, error)                                                  // число фильмов под фильтром
+	CreateMovieWithActors(ctx context.Context, movie domain.Movie, actorIDs []int) (int, error)                               // создать фильм с актёрами
+	UpdateMovieActors(ctx context.Context, movieID int, cast []domain.CastMember) error                                       // заменить состав фильма
+	GetMoviesForActor(ctx context.Context, actorID int) ([]domain.Movie, error)                                               // фильмы по актёру
+	PartialUpdateMovie(ctx context.Context, id int, update domain.MovieUpdate) error                                          // частичное обновление фильма
+	MergeMovies(ctx context.Context, keepID, dupID int, policy domain.MovieDeletePolicy) (domain.MovieMergeResult, error)     // слияние дубликатов
+	BulkDeleteMovies(ctx context.Context, ids []int, policy domain.MovieDeletePolicy) ([]domain.BulkItemResult, error)        // удалить фильмы в одной транзакции
+	BulkUpdateMovies(ctx context.Context, ids []int, update domain.MovieUpdate) ([]domain.BulkItemResult, error)              // обновить фильмы в одной транзакции
+	GetMergedMovieID(ctx context.Context, oldID int) (int, error)                                                             // ID фильма, в который слит дубликат
+	GetUpcomingMovies(ctx context.Context, after time.Time) ([]domain.Movie, error)                                           // фильмы с датой выхода позже after
+	AddMovieRevision(ctx context.Context, revision domain.MovieRevision) error                                                // сохранить ревизию фильма
+	GetMovieRevisions(ctx context.Context, movieID int, until time.Time) ([]domain.MovieRevision, error)                      // ревизии фильма до момента until
+	AddRatingChange(ctx context.Context, change domain.RatingChange) error                                                    // сохранить изменение рейтинга
+	GetRatingHistory(ctx context.Context, movieID int) ([]domain.RatingChange, error)                                         // история рейтинга фильма
+	AddAvailability(ctx context.Context, window domain.Availability) (int, error)                                             // добавить окно доступности
+	GetAvailability(ctx context.Context, movieID int) ([]domain.Availability, error)                                          // окна доступности фильма
+	UpdateAvailability(ctx context.Context, window domain.Availability) error                                                 // изменить окно доступности
+	DeleteAvailability(ctx context.Context, movieID, windowID int) error                                                      // удалить окно доступности
+	IncrementViewCount(ctx context.Context, movieID int, delta int64) error                                                   // увеличить счётчик просмотров
+	GetPopularMovies(ctx context.Context, limit int) ([]domain.Movie, error)                                                  // самые просматриваемые фильмы
+	FindByNormalizedTitle(ctx context.Context, title string, releaseYear int) (domain.Movie, error)                           // фильм с тем же названием и годом
+	ImportMovie(ctx context.Context, movie domain.Movie, cast []domain.Actor) (domain.MovieImportResult, error)               // создать фильм и недостающих актёров
+	ResolveSlug(ctx context.Context, slug string) (int, error)                                                                // ID фильма по slug
+	SetMovieRating(ctx context.Context, rating domain.MovieRating) error                                                      // сохранить рейтинг из источника
+	GetMovieRatings(ctx context.Context, movieID int) ([]domain.MovieRating, error)                                           // рейтинги фильма по источникам
+	DeleteMovieRating(ctx context.Context, movieID int, source string) error                                                  // удалить рейтинг из источника
+	SetCastOrder(ctx context.Context, movieID int, actorIDs []int) error                                                      // записать порядок актёров в титрах
+	GetMoviesByYears(ctx context.Context, fromYear, toYear, limit, offset int) ([]domain.Movie, error)                        // страница фильмов, вышедших в эти годы
+	CountMoviesByYears(ctx context.Context, fromYear, toYear int) (int, error)                                                // число фильмов, вышедших в эти годы
+	AddMovieTag(ctx context.Context, movieID int, tag string) error                                                           // добавить фильму тег
+	RemoveMovieTag(ctx context.Context, movieID int, tag string) error                                                        // снять тег с фильма
+	GetMovieTags(ctx context.Context, movieID int) ([]string, error)                                                          // теги фильма
+	GetMoviesByTag(ctx context.Context, tag string, limit, offset int) ([]domain.Movie, error)                                // страница фильмов с тегом
+	CountMoviesByTag(ctx context.Context, tag string) (int, error)                                                            // число фильмов с тегом
+	SuggestTags(ctx context.Context, prefix string, limit int) ([]domain.Tag, error)                                          // подсказки тегов по началу названия
+	GetByExternalID(ctx context.Context, externalID string) (domain.Movie, error)                                             // фильм по ID в каталоге-источнике
+	UpsertByExternalID(ctx context.Context, movie domain.Movie) (int, bool, error)                                            // создать или перезаписать фильм по внешнему ID
+	GetMovieCastPage(ctx context.Context, movieID, limit, offset int) ([]domain.Actor, error)                                 // страница состава фильма в порядке титров
+	CountMovieCast(ctx context.Context, movieID int) (int, error)                                                             // число актёров в составе фильма
+	ChangeMovieActors(ctx context.Context, movieID int, add []domain.CastMember, remove []int) error                          // добавить и убрать актёров одной транзакцией
+}
+
+// ExternalMovieSource — внешний каталог фильмов (TMDB), из которого импортируются метаданные
+type ExternalMovieSource interface {
+	FindByIMDbID(ctx context.Context, imdbID string) (domain.ExternalMovie, error)
 }
 
 // MovieService реализует бизнес-логику для фильмов
 type MovieService struct {
-	store      StoreMovie
-	actorStore StoreActor
+	store       StoreMovie
+	actorStore  StoreActor
+	titleSearch *canary.Experiment[[]domain.Movie] // опционально: канареечный запуск нового поиска по названию
+	actorsCache *ActorsWithMoviesCache             // опционально: сбрасывается при изменении фильмов и их актёров
+	external    ExternalMovieSource                // опционально: источник импорта фильмов
+	deletePolicy domain.MovieDeletePolicy          // что делать со ссылками на удаляемый фильм
+	ratingWeights domain.RatingWeights             // веса источников в отображаемом рейтинге
 }
 
 // NewMovie создаёт сервис фильмов
 func NewMovie(store StoreMovie, actorStore StoreActor) *MovieService {
-	return &MovieService{store: store, actorStore: actorStore}
+	return &MovieService{store: store, actorStore: actorStore, deletePolicy: domain.MovieDeleteCascade, ratingWeights: DefaultRatingWeights}
 }
 
-// Create создаёт фильм с актёрами
-func (s *MovieService) Create(movie domain.Movie, actorIDs []int) (int, error) {
-	id, err := s.store.Create(movie)
+// Create создаёт фильм с актёрами. Без force проверяет, нет ли уже фильма с тем же
+// нормализованным названием и годом выпуска, и возвращает *domain.DuplicateMovieError
+func (s *MovieService) Create(ctx context.Context, movie domain.Movie, actorIDs []int, force bool) (int, error) {
+	ctx, span := tracer().Start(ctx, "MovieService.Create")
+	defer span.End()
+
+	defer s.actorsCache.Invalidate()
+	if !force {
+		existing, err := s.store.FindByNormalizedTitle(ctx, movie.Title, movie.ReleaseYear)
+		if err == nil {
+			return 0, &domain.DuplicateMovieError{ExistingID: existing.ID}
+		}
+		if !errors.Is(err, domain.ErrMovieNotFound) {
+			return 0, fmt.Errorf("checking for duplicate movie: %w", err)
+		}
+	}
+
+	id, err := s.store.Create(ctx, movie)
 	if err != nil {
 		return 0, err
 	}
 	for _, actorID := range actorIDs {
-		if err := s.store.AddActor(id, actorID); err != nil {
-			_ = s.store.Delete(id)
+		if err := s.store.AddActor(ctx, id, domain.CastMember{ActorID: actorID}); err != nil {
+			// Только что созданный фильм удаляется целиком: ссылок на него ещё нет
+			_ = s.store.Delete(ctx, id, domain.MovieDeleteCascade)
 			return 0, err
 		}
 	}
+	s.recordRevision(ctx, id, movieSnapshot(movie), false)
+	s.syncInternalRating(ctx, id, movie.Rating)
 	return id, nil
 }
 
 // GetByID возвращает фильм с актёрами
-func (s *MovieService) GetByID(id int) (domain.Movie, error) {
-	movie, err := s.store.GetByID(id)
+func (s *MovieService) GetByID(ctx context.Context, id int) (domain.Movie, error) {
+	ctx, span := tracer().Start(ctx, "MovieService.GetByID")
+	defer span.End()
+
+	movie, err := s.store.GetByID(ctx, id)
 	if err != nil {
 		if errors.Is(err, domain.ErrMovieNotFound) {
 			return domain.Movie{}, domain.ErrMovieNotFound
@@ -63,7 +138,7 @@ func (s *MovieService) GetByID(id int) (domain.Movie, error) {
 		return domain.Movie{}, fmt.Errorf("getting movie by ID: %w", err)
 	}
 
-	actors, err := s.store.GetActorsForMovieByID(id)
+	actors, err := s.store.GetActorsForMovieByID(ctx, id)
 	if err != nil {
 		// Don't fail if we can't get actors, just log and continue with empty actors list
 		// This is a design decision - we might want to handle this differently
@@ -73,13 +148,40 @@ func (s *MovieService) GetByID(id int) (domain.Movie, error) {
 
 	movie.Actors = make([]domain.Actor, len(actors))
 	copy(movie.Actors, actors)
+
+	// Без рейтингов по источникам фильм всё равно отдаётся: остаётся его внутренний рейтинг
+	ratings, display, err := s.movieRatings(ctx, movie)
+	if err != nil {
+		log.Printf("Error getting ratings for movie (ID: %d): %v", id, err)
+	} else {
+		movie.Ratings, movie.DisplayRating = ratings, display
+	}
 	return movie, nil
 }
 
+// GetBySlug возвращает фильм с актёрами по slug
+func (s *MovieService) GetBySlug(ctx context.Context, slug string) (domain.Movie, error) {
+	ctx, span := tracer().Start(ctx, "MovieService.GetBySlug")
+	defer span.End()
+
+	id, err := s.store.ResolveSlug(ctx, slug)
+	if err != nil {
+		if errors.Is(err, domain.ErrMovieNotFound) {
+			return domain.Movie{}, domain.ErrMovieNotFound
+		}
+		return domain.Movie{}, fmt.Errorf("resolving movie slug: %w", err)
+	}
+	return s.GetByID(ctx, id)
+}
+
 // Update обновляет фильм и связи с актёрами
-func (s *MovieService) Update(movie domain.Movie, actorIDs []int) error {
+func (s *MovieService) Update(ctx context.Context, movie domain.Movie, actorIDs []int) error {
+	ctx, span := tracer().Start(ctx, "MovieService.Update")
+	defer span.End()
+
+	defer s.actorsCache.Invalidate()
 	// Проверяем существование фильма
-	_, err := s.store.GetByID(movie.ID)
+	current, err := s.store.GetByID(ctx, movie.ID)
 	if err != nil {
 		if errors.Is(err, domain.ErrMovieNotFound) {
 			return domain.ErrMovieNotFound
@@ -87,19 +189,21 @@ func (s *MovieService) Update(movie domain.Movie, actorIDs []int) error {
 		return fmt.Errorf("checking movie existence: %w", err)
 	}
 
-	if err := s.store.Update(movie); err != nil {
+	if err := s.store.Update(ctx, movie); err != nil {
 		if errors.Is(err, domain.ErrMovieNotFound) {
 			return domain.ErrMovieNotFound
 		}
 		return fmt.Errorf("updating movie: %w", err)
 	}
+	s.recordRevision(ctx, movie.ID, movieSnapshot(movie), false)
+	s.recordRatingChange(ctx, movie.ID, current.Rating, movie.Rating)
 
-	if err := s.store.RemoveAllActors(movie.ID); err != nil {
+	if err := s.store.RemoveAllActors(ctx, movie.ID); err != nil {
 		return fmt.Errorf("removing actors from movie: %w", err)
 	}
 
 	for _, actorID := range actorIDs {
-		if err := s.store.AddActor(movie.ID, actorID); err != nil {
+		if err := s.store.AddActor(ctx, movie.ID, domain.CastMember{ActorID: actorID}); err != nil {
 			if errors.Is(err, domain.ErrActorNotFound) {
 				return domain.ErrActorNotFound
 			}
@@ -112,53 +216,112 @@ func (s *MovieService) Update(movie domain.Movie, actorIDs []int) error {
 
 // MovieService methods
 
-// Delete удаляет фильм
-func (s *MovieService) Delete(id int) error {
+// Delete удаляет фильм. Ссылки на фильм проверяются по политике удаления в той же
+// транзакции, что и удаление
+func (s *MovieService) Delete(ctx context.Context, id int) error {
+	ctx, span := tracer().Start(ctx, "MovieService.Delete")
+	defer span.End()
+
+	defer s.actorsCache.Invalidate()
 	log.Printf("Starting deletion of movie with ID: %d", id)
 
-	// Проверяем существование фильма
-	_, err := s.store.GetByID(id)
-	if err != nil {
+	if err := s.store.Delete(ctx, id, s.deletePolicy); err != nil {
 		if errors.Is(err, domain.ErrMovieNotFound) {
 			log.Printf("Cannot delete: movie with ID %d not found", id)
 			return domain.ErrMovieNotFound
 		}
-		log.Printf("Error getting movie (ID: %d): %v", id, err)
-		return fmt.Errorf("checking movie existence: %w", err)
+		if errors.Is(err, domain.ErrMovieReferenced) {
+			log.Printf("Cannot delete: movie with ID %d is still referenced: %v", id, err)
+			return err
+		}
+		log.Printf("Error deleting movie (ID: %d): %v", id, err)
+		return fmt.Errorf("deleting movie: %w", err)
 	}
+	s.recordRevision(ctx, id, domain.MovieUpdate{}, true)
 
-	// Удаляем все связи с актёрами
-	log.Printf("Removing all actors from movie (ID: %d)", id)
-	if err := s.store.RemoveAllActors(id); err != nil {
-		log.Printf("Error removing actors from movie (ID: %d): %v", id, err)
-		return fmt.Errorf("removing actors from movie: %w", err)
+	log.Printf("Successfully deleted movie with ID: %d", id)
+	return nil
+}
+
+// DeleteCreated откатывает создание фильма: удаляет фильм и актёров, созданных вместе с ним.
+// Актёр, которого за это время добавили в другой фильм, остаётся в каталоге
+func (s *MovieService) DeleteCreated(ctx context.Context, movieID int, createdActorIDs []int) error {
+	ctx, span := tracer().Start(ctx, "MovieService.DeleteCreated")
+	defer span.End()
+
+	if err := s.Delete(ctx, movieID); err != nil {
+		return err
+	}
+	if len(createdActorIDs) == 0 {
+		return nil
 	}
+	defer s.actorsCache.Invalidate()
 
-	// Удаляем фильм
-	log.Printf("Deleting movie with ID: %d", id)
-	if err := s.store.Delete(id); err != nil {
-		log.Printf("Error deleting movie (ID: %d): %v", id, err)
-		if errors.Is(err, domain.ErrMovieNotFound) {
-			// Это не должно происходить, так как мы уже проверили существование
-			log.Printf("Movie (ID: %d) was deleted by another process", id)
-			return domain.ErrMovieNotFound
+	var errs []error
+	for _, actorID := range createdActorIDs {
+		movies, err := s.actorStore.GetMovies(ctx, actorID)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("getting movies of actor %d: %w", actorID, err))
+			continue
+		}
+		if len(movies) > 0 {
+			log.Printf("Keeping actor %d created with movie %d: it has %d other movies", actorID, movieID, len(movies))
+			continue
+		}
+		if err := s.actorStore.Delete(ctx, actorID); err != nil && !errors.Is(err, domain.ErrActorNotFound) {
+			errs = append(errs, fmt.Errorf("deleting actor %d: %w", actorID, err))
 		}
-		return fmt.Errorf("deleting movie: %w", err)
 	}
-
-	log.Printf("Successfully deleted movie with ID: %d", id)
+	if err := errors.Join(errs...); err != nil {
+		log.Printf("Error deleting actors created with movie %d: %v", movieID, err)
+		return err
+	}
+	log.Printf("Deleted %d actors created with movie %d", len(createdActorIDs), movieID)
 	return nil
 }
 
 // GetAll возвращает все фильмы
-func (s *MovieService) GetAll() ([]domain.Movie, error) { return s.store.GetAll() }
+func (s *MovieService) GetAll(ctx context.Context) ([]domain.Movie, error) {
+	ctx, span := tracer().Start(ctx, "MovieService.GetAll")
+	defer span.End()
+	return s.store.GetAll(ctx)
+}
+
+// GetMoviesAfterID возвращает до limit фильмов с ID больше afterID по возрастанию ID.
+// hasMore сообщает, есть ли фильмы после последнего из возвращённых
+func (s *MovieService) GetMoviesAfterID(ctx context.Context, afterID, limit int) (movies []domain.Movie, hasMore bool, err error) {
+	ctx, span := tracer().Start(ctx, "MovieService.GetMoviesAfterID")
+	defer span.End()
+
+	// Лишняя строка показывает, что следующая страница не пуста, без отдельного COUNT
+	movies, err = s.store.GetMoviesAfterID(ctx, afterID, limit+1)
+	if err != nil {
+		return nil, false, fmt.Errorf("listing movies after %d: %w", afterID, err)
+	}
+	if len(movies) > limit {
+		return movies[:limit], true, nil
+	}
+	return movies, false, nil
+}
+
+// ExportMovies передаёт fn все фильмы каталога по возрастанию ID
+func (s *MovieService) ExportMovies(ctx context.Context, fn func(domain.Movie) error) error {
+	ctx, span := tracer().Start(ctx, "MovieService.ExportMovies")
+	defer span.End()
+	return s.store.ForEachMovie(ctx, fn)
+}
+
+// AddActor добавляет актёра к фильму с ролью и местом в титрах
+func (s *MovieService) AddActor(ctx context.Context, movieID int, member domain.CastMember) error {
+	ctx, span := tracer().Start(ctx, "MovieService.AddActor")
+	defer span.End()
 
-// AddActor добавляет актёра к фильму
-func (s *MovieService) AddActor(movieID, actorID int) error {
+	actorID := member.ActorID
+	defer s.actorsCache.Invalidate()
 	log.Printf("Adding actor (ID: %d) to movie (ID: %d)", actorID, movieID)
 
 	// Проверяем существование фильма
-	_, err := s.store.GetByID(movieID)
+	_, err := s.store.GetByID(ctx, movieID)
 	if err != nil {
 		if errors.Is(err, domain.ErrMovieNotFound) {
 			log.Printf("Cannot add actor: movie with ID %d not found", movieID)
@@ -171,7 +334,7 @@ func (s *MovieService) AddActor(movieID, actorID int) error {
 	// TODO: Проверка существования актёра, когда будет доступен сервис актёров
 
 	// Проверяем, не добавлен ли уже актёр
-	actors, err := s.store.GetActorsForMovieByID(movieID)
+	actors, err := s.store.GetActorsForMovieByID(ctx, movieID)
 	if err != nil {
 		log.Printf("Error getting actors for movie (ID: %d): %v", movieID, err)
 		return fmt.Errorf("getting movie actors: %w", err)
@@ -179,14 +342,13 @@ func (s *MovieService) AddActor(movieID, actorID int) error {
 
 	for _, actor := range actors {
 		if actor.ID == actorID {
-			errMsg := fmt.Sprintf("actor with ID %d is already in the movie", actorID)
-			log.Printf("Cannot add actor: %s", errMsg)
-			return errors.New(errMsg)
+			log.Printf("Cannot add actor: actor with ID %d is already in the movie", actorID)
+			return fmt.Errorf("actor with ID %d: %w", actorID, domain.ErrActorAlreadyInMovie)
 		}
 	}
 
 	// Добавляем актёра к фильму
-	if err := s.store.AddActor(movieID, actorID); err != nil {
+	if err := s.store.AddActor(ctx, movieID, member); err != nil {
 		log.Printf("Error adding actor (ID: %d) to movie (ID: %d): %v", actorID, movieID, err)
 		if errors.Is(err, domain.ErrActorNotFound) {
 			return fmt.Errorf("actor with ID %d not found: %w", actorID, domain.ErrActorNotFound)
@@ -199,11 +361,15 @@ func (s *MovieService) AddActor(movieID, actorID int) error {
 }
 
 // RemoveActor удаляет актёра из фильма
-func (s *MovieService) RemoveActor(movieID, actorID int) error {
+func (s *MovieService) RemoveActor(ctx context.Context, movieID, actorID int) error {
+	ctx, span := tracer().Start(ctx, "MovieService.RemoveActor")
+	defer span.End()
+
+	defer s.actorsCache.Invalidate()
 	log.Printf("Removing actor (ID: %d) from movie (ID: %d)", actorID, movieID)
 
 	// Проверяем существование фильма
-	_, err := s.store.GetByID(movieID)
+	_, err := s.store.GetByID(ctx, movieID)
 	if err != nil {
 		if errors.Is(err, domain.ErrMovieNotFound) {
 			log.Printf("Cannot remove actor: movie with ID %d not found", movieID)
@@ -214,7 +380,7 @@ func (s *MovieService) RemoveActor(movieID, actorID int) error {
 	}
 
 	// Проверяем, есть ли актёр в фильме
-	actors, err := s.store.GetActorsForMovieByID(movieID)
+	actors, err := s.store.GetActorsForMovieByID(ctx, movieID)
 	if err != nil {
 		log.Printf("Error getting actors for movie (ID: %d): %v", movieID, err)
 		return fmt.Errorf("getting movie actors: %w", err)
@@ -229,13 +395,12 @@ func (s *MovieService) RemoveActor(movieID, actorID int) error {
 	}
 
 	if !actorFound {
-		errMsg := fmt.Sprintf("actor with ID %d is not in the movie (ID: %d)", actorID, movieID)
-		log.Printf("Cannot remove actor: %s", errMsg)
-		return errors.New(errMsg)
+		log.Printf("Cannot remove actor: actor with ID %d is not in the movie (ID: %d)", actorID, movieID)
+		return fmt.Errorf("actor with ID %d, movie %d: %w", actorID, movieID, domain.ErrActorNotInMovie)
 	}
 
 	// Удаляем актёра из фильма
-	if err := s.store.RemoveActor(movieID, actorID); err != nil {
+	if err := s.store.RemoveActor(ctx, movieID, actorID); err != nil {
 		log.Printf("Error removing actor (ID: %d) from movie (ID: %d): %v", actorID, movieID, err)
 		if errors.Is(err, domain.ErrActorNotFound) {
 			return fmt.Errorf("actor with ID %d not found: %w", actorID, domain.ErrActorNotFound)
@@ -248,9 +413,12 @@ func (s *MovieService) RemoveActor(movieID, actorID int) error {
 }
 
 // GetActors возвращает актёров фильма
-func (s *MovieService) GetActors(movieID int) ([]domain.Actor, error) {
+func (s *MovieService) GetActors(ctx context.Context, movieID int) ([]domain.Actor, error) {
+	ctx, span := tracer().Start(ctx, "MovieService.GetActors")
+	defer span.End()
+
 	// Проверяем существование фильма
-	_, err := s.store.GetByID(movieID)
+	_, err := s.store.GetByID(ctx, movieID)
 	if err != nil {
 		if errors.Is(err, domain.ErrMovieNotFound) {
 			return nil, domain.ErrMovieNotFound
@@ -258,7 +426,7 @@ func (s *MovieService) GetActors(movieID int) ([]domain.Actor, error) {
 		return nil, fmt.Errorf("checking movie existence: %w", err)
 	}
 
-	actors, err := s.store.GetActorsForMovieByID(movieID)
+	actors, err := s.store.GetActorsForMovieByID(ctx, movieID)
 	if err != nil {
 		// Возвращаем пустой список, если не удалось получить актёров
 		return []domain.Actor{}, nil
@@ -268,41 +436,209 @@ func (s *MovieService) GetActors(movieID int) ([]domain.Actor, error) {
 }
 
 // GetActorsForMovieByID возвращает актёров фильма (алиас для GetActors)
-func (s *MovieService) GetActorsForMovieByID(movieID int) ([]domain.Actor, error) {
-	return s.store.GetActorsForMovieByID(movieID)
+func (s *MovieService) GetActorsForMovieByID(ctx context.Context, movieID int) ([]domain.Actor, error) {
+	ctx, span := tracer().Start(ctx, "MovieService.GetActorsForMovieByID")
+	defer span.End()
+
+	return s.store.GetActorsForMovieByID(ctx, movieID)
 }
 
 // RemoveAllActors удаляет всех актёров из фильма
-func (s *MovieService) RemoveAllActors(movieID int) error {
-    return s.store.RemoveAllActors(movieID)
+func (s *MovieService) RemoveAllActors(ctx context.Context, movieID int) error {
+	ctx, span := tracer().Start(ctx, "MovieService.RemoveAllActors")
+	defer span.End()
+
+	defer s.actorsCache.Invalidate()
+    return s.store.RemoveAllActors(ctx, movieID)
+}
+
+// WithActorsCache подключает кэш актёров с фильмами, который нужно сбрасывать
+// при изменении фильмов и связей фильм–актёр
+func (s *MovieService) WithActorsCache(cache *ActorsWithMoviesCache) *MovieService {
+	s.actorsCache = cache
+	return s
+}
+
+// WithDeletePolicy задаёт политику удаления фильмов, на которые ссылаются другие записи.
+// По умолчанию ссылки удаляются вместе с фильмом (domain.MovieDeleteCascade)
+func (s *MovieService) WithDeletePolicy(policy domain.MovieDeletePolicy) *MovieService {
+	s.deletePolicy = policy
+	return s
+}
+
+// WithExternalSource подключает внешний каталог для импорта фильмов
+func (s *MovieService) WithExternalSource(source ExternalMovieSource) *MovieService {
+	s.external = source
+	return s
+}
+
+// ImportExternal импортирует фильм по IMDb ID из внешнего каталога вместе с актёрами.
+// Фильм с тем же нормализованным названием и годом не импортируется повторно:
+// возвращается *domain.DuplicateMovieError
+func (s *MovieService) ImportExternal(ctx context.Context, imdbID string) (domain.MovieImportResult, error) {
+	ctx, span := tracer().Start(ctx, "MovieService.ImportExternal")
+	defer span.End()
+
+	if s.external == nil {
+		return domain.MovieImportResult{}, domain.ErrExternalImportOff
+	}
+	external, err := s.external.FindByIMDbID(ctx, imdbID)
+	if err != nil {
+		return domain.MovieImportResult{}, fmt.Errorf("fetching external movie %s: %w", imdbID, err)
+	}
+
+	existing, err := s.store.FindByNormalizedTitle(ctx, external.Movie.Title, external.Movie.ReleaseYear)
+	if err == nil {
+		return domain.MovieImportResult{}, &domain.DuplicateMovieError{ExistingID: existing.ID}
+	}
+	if !errors.Is(err, domain.ErrMovieNotFound) {
+		return domain.MovieImportResult{}, fmt.Errorf("checking for duplicate movie: %w", err)
+	}
+
+	defer s.actorsCache.Invalidate()
+	result, err := s.store.ImportMovie(ctx, external.Movie, external.Cast)
+	if err != nil {
+		return domain.MovieImportResult{}, fmt.Errorf("importing movie %s: %w", imdbID, err)
+	}
+	s.recordRevision(ctx, result.MovieID, movieSnapshot(external.Movie), false)
+	s.syncInternalRating(ctx, result.MovieID, external.Movie.Rating)
+	return result, nil
+}
+
+// WithTitleSearchCanary направляет percent процентов поисков по названию в триграммный поиск
+// вместо ILIKE, сравнивая результаты и время обеих реализаций
+func (s *MovieService) WithTitleSearchCanary(percent int) *MovieService {
+	s.titleSearch = canary.New("movie_title_search", percent, diffMovieIDs)
+	return s
+}
+
+// SearchMoviesByTitle ищет фильмы по названию среди фильмов, подходящих под фильтр
+func (s *MovieService) SearchMoviesByTitle(ctx context.Context, titleFragment string, filter domain.MovieFilter) ([]domain.Movie, error) {
+	ctx, span := tracer().Start(ctx, "MovieService.SearchMoviesByTitle")
+	defer span.End()
+
+	if s.titleSearch == nil {
+		return s.store.SearchMoviesByTitle(ctx, titleFragment, filter)
+	}
+	return s.titleSearch.Run(
+		func() ([]domain.Movie, error) { return s.store.SearchMoviesByTitle(ctx, titleFragment, filter) },
+		func() ([]domain.Movie, error) { return s.store.SearchMoviesByTitleTrigram(ctx, titleFragment, filter) },
+	)
+}
+
+// diffMovieIDs сравнивает наборы найденных фильмов без учёта порядка
+func diffMovieIDs(control, candidate []domain.Movie) string {
+	seen := make(map[int]bool, len(control))
+	for _, movie := range control {
+		seen[movie.ID] = true
+	}
+	var added []int
+	for _, movie := range candidate {
+		if !seen[movie.ID] {
+			added = append(added, movie.ID)
+		}
+		delete(seen, movie.ID)
+	}
+	if len(added) == 0 && len(seen) == 0 {
+		return ""
+	}
+	missing := make([]int, 0, len(seen))
+	for id := range seen {
+		missing = append(missing, id)
+	}
+	sort.Ints(missing)
+	return fmt.Sprintf("control %d movies, candidate %d; only in candidate %v, only in control %v",
+		len(control), len(candidate), added, missing)
 }
 
-// SearchMoviesByTitle ищет фильмы по названию
-func (s *MovieService) SearchMoviesByTitle(titleFragment string) ([]domain.Movie, error) {
-	return s.store.SearchMoviesByTitle(titleFragment)
+// SearchMoviesByActorName ищет фильмы по имени актёра среди фильмов, подходящих под фильтр
+func (s *MovieService) SearchMoviesByActorName(ctx context.Context, actorNameFragment string, filter domain.MovieFilter) ([]domain.Movie, error) {
+	ctx, span := tracer().Start(ctx, "MovieService.SearchMoviesByActorName")
+	defer span.End()
+
+	return s.store.SearchMoviesByActorName(ctx, actorNameFragment, filter)
 }
 
-// SearchMoviesByActorName ищет фильмы по имени актёра
-func (s *MovieService) SearchMoviesByActorName(actorNameFragment string) ([]domain.Movie, error) {
-	return s.store.SearchMoviesByActorName(actorNameFragment)
+// GetMatchingActors возвращает актёров найденных фильмов, имя которых содержит nameFragment
+func (s *MovieService) GetMatchingActors(ctx context.Context, movieIDs []int, nameFragment string) (map[int][]domain.Actor, error) {
+	ctx, span := tracer().Start(ctx, "MovieService.GetMatchingActors")
+	defer span.End()
+
+	return s.store.GetMatchingActors(ctx, movieIDs, nameFragment)
 }
 
-// GetAllMoviesSorted возвращает фильмы с сортировкой
-func (s *MovieService) GetAllMoviesSorted(sortField, sortOrder string) ([]domain.Movie, error) {
-	return s.store.GetAllMoviesSorted(sortField, sortOrder)
+// GetAllMoviesSorted возвращает страницу фильмов с сортировкой по нескольким полям
+func (s *MovieService) GetAllMoviesSorted(ctx context.Context, query domain.MovieListQuery) ([]domain.Movie, error) {
+	ctx, span := tracer().Start(ctx, "MovieService.GetAllMoviesSorted")
+	defer span.End()
+
+	return s.store.GetAllMoviesSorted(ctx, query)
+}
+
+// CountMovies возвращает число фильмов под фильтром для метаданных пагинации
+func (s *MovieService) CountMovies(ctx context.Context, filter domain.MovieFilter) (int, error) {
+	ctx, span := tracer().Start(ctx, "MovieService.CountMovies")
+	defer span.End()
+
+	total, err := s.store.CountMovies(ctx, filter)
+	if err != nil {
+		return 0, fmt.Errorf("counting movies: %w", err)
+	}
+	return total, nil
 }
 
 // CreateMovieWithActors создаёт фильм с актёрами
-func (s *MovieService) CreateMovieWithActors(movie domain.Movie, actorIDs []int) (int, error) {
-	return s.store.CreateMovieWithActors(movie, actorIDs)
+func (s *MovieService) CreateMovieWithActors(ctx context.Context, movie domain.Movie, actorIDs []int) (int, error) {
+	ctx, span := tracer().Start(ctx, "MovieService.CreateMovieWithActors")
+	defer span.End()
+
+	defer s.actorsCache.Invalidate()
+	id, err := s.store.CreateMovieWithActors(ctx, movie, actorIDs)
+	if err != nil {
+		return 0, err
+	}
+	s.recordRevision(ctx, id, movieSnapshot(movie), false)
+	s.syncInternalRating(ctx, id, movie.Rating)
+	return id, nil
 }
 
-// UpdateMovieActors обновляет актёров фильма
-func (s *MovieService) UpdateMovieActors(movieID int, actorIDs []int) error {
+// CreateFull создаёт фильм вместе с составом в одной транзакции. Актёры без ID ищутся
+// в каталоге по имени и дате рождения, недостающие создаются. Без force, как и Create,
+// возвращает *domain.DuplicateMovieError для фильма с тем же названием и годом
+func (s *MovieService) CreateFull(ctx context.Context, movie domain.Movie, cast []domain.Actor, force bool) (domain.MovieImportResult, error) {
+	ctx, span := tracer().Start(ctx, "MovieService.CreateFull")
+	defer span.End()
+
+	if !force {
+		existing, err := s.store.FindByNormalizedTitle(ctx, movie.Title, movie.ReleaseYear)
+		if err == nil {
+			return domain.MovieImportResult{}, &domain.DuplicateMovieError{ExistingID: existing.ID}
+		}
+		if !errors.Is(err, domain.ErrMovieNotFound) {
+			return domain.MovieImportResult{}, fmt.Errorf("checking for duplicate movie: %w", err)
+		}
+	}
+
+	defer s.actorsCache.Invalidate()
+	result, err := s.store.ImportMovie(ctx, movie, cast)
+	if err != nil {
+		return domain.MovieImportResult{}, fmt.Errorf("creating movie with cast: %w", err)
+	}
+	s.recordRevision(ctx, result.MovieID, movieSnapshot(movie), false)
+	s.syncInternalRating(ctx, result.MovieID, movie.Rating)
+	return result, nil
+}
+
+// UpdateMovieActors заменяет состав фильма
+func (s *MovieService) UpdateMovieActors(ctx context.Context, movieID int, cast []domain.CastMember) error {
+	ctx, span := tracer().Start(ctx, "MovieService.UpdateMovieActors")
+	defer span.End()
+
+	defer s.actorsCache.Invalidate()
 	log.Printf("Updating actors for movie (ID: %d)", movieID)
 
 	// Проверяем существование фильма
-	_, err := s.store.GetByID(movieID)
+	_, err := s.store.GetByID(ctx, movieID)
 	if err != nil {
 		if errors.Is(err, domain.ErrMovieNotFound) {
 			log.Printf("Cannot update actors: movie with ID %d not found", movieID)
@@ -314,18 +650,18 @@ func (s *MovieService) UpdateMovieActors(movieID int, actorIDs []int) error {
 
 	// Удаляем всех текущих актёров фильма
 	log.Printf("Removing all actors from movie (ID: %d)", movieID)
-	if err := s.store.RemoveAllActors(movieID); err != nil {
+	if err := s.store.RemoveAllActors(ctx, movieID); err != nil {
 		log.Printf("Error removing actors from movie (ID: %d): %v", movieID, err)
 		return fmt.Errorf("removing actors from movie: %w", err)
 	}
 
 	// Добавляем новых актёров
-	for _, actorID := range actorIDs {
-		log.Printf("Adding actor (ID: %d) to movie (ID: %d)", actorID, movieID)
-		if err := s.store.AddActor(movieID, actorID); err != nil {
-			log.Printf("Error adding actor (ID: %d) to movie (ID: %d): %v", actorID, movieID, err)
+	for _, member := range cast {
+		log.Printf("Adding actor (ID: %d) to movie (ID: %d)", member.ActorID, movieID)
+		if err := s.store.AddActor(ctx, movieID, member); err != nil {
+			log.Printf("Error adding actor (ID: %d) to movie (ID: %d): %v", member.ActorID, movieID, err)
 			if errors.Is(err, domain.ErrActorNotFound) {
-				return fmt.Errorf("actor with ID %d not found: %w", actorID, domain.ErrActorNotFound)
+				return fmt.Errorf("actor with ID %d not found: %w", member.ActorID, domain.ErrActorNotFound)
 			}
 			return fmt.Errorf("adding actor to movie: %w", err)
 		}
@@ -335,9 +671,12 @@ func (s *MovieService) UpdateMovieActors(movieID int, actorIDs []int) error {
 	return nil
 }
 
-func (s *MovieService) GetMoviesForActor(actorID int) ([]domain.Movie, error) {
+func (s *MovieService) GetMoviesForActor(ctx context.Context, actorID int) ([]domain.Movie, error) {
+	ctx, span := tracer().Start(ctx, "MovieService.GetMoviesForActor")
+	defer span.End()
+
 	// Проверяем существование актёра
-	_, err := s.actorStore.GetByID(actorID)
+	_, err := s.actorStore.GetByID(ctx, actorID)
 	if err != nil {
 		if errors.Is(err, domain.ErrActorNotFound) {
 			return nil, domain.ErrActorNotFound
@@ -346,7 +685,7 @@ func (s *MovieService) GetMoviesForActor(actorID int) ([]domain.Movie, error) {
 	}
 
 	// Получаем фильмы актёра
-	movies, err := s.store.GetMoviesForActor(actorID)
+	movies, err := s.store.GetMoviesForActor(ctx, actorID)
 	if err != nil {
 		return nil, fmt.Errorf("getting movies for actor: %w", err)
 	}
@@ -359,69 +698,170 @@ func (s *MovieService) GetMoviesForActor(actorID int) ([]domain.Movie, error) {
 	return movies, nil
 }
 
-// PartialUpdateMovie частично обновляет фильм
-func (s *MovieService) PartialUpdateMovie(id int, update domain.MovieUpdate) error {
-	log.Printf("Starting partial update of movie (ID: %d)", id)
+// PartialUpdateMovie обновляет только переданные поля фильма
+func (s *MovieService) PartialUpdateMovie(ctx context.Context, id int, update domain.MovieUpdate) error {
+	ctx, span := tracer().Start(ctx, "MovieService.PartialUpdateMovie")
+	defer span.End()
 
-	// Проверяем существование фильма
-	movie, err := s.store.GetByID(id)
+	if update.IsEmpty() {
+		log.Printf("Cannot update movie (ID: %d): no fields to update", id)
+		return domain.ErrNoFieldsToUpdate
+	}
+
+	defer s.actorsCache.Invalidate()
+	previousRatings, err := s.ratingsBeforeUpdate(ctx, []int{id}, update)
 	if err != nil {
+		return err
+	}
+	if err := s.store.PartialUpdateMovie(ctx, id, update); err != nil {
 		if errors.Is(err, domain.ErrMovieNotFound) {
 			log.Printf("Cannot update: movie with ID %d not found", id)
 			return domain.ErrMovieNotFound
 		}
-		log.Printf("Error getting movie (ID: %d): %v", id, err)
-		return fmt.Errorf("getting movie: %w", err)
+		log.Printf("Error updating movie (ID: %d): %v", id, err)
+		return fmt.Errorf("updating movie: %w", err)
+	}
+	s.recordRevision(ctx, id, update, false)
+	if previous, ok := previousRatings[id]; ok {
+		s.recordRatingChange(ctx, id, previous, *update.Rating)
 	}
 
-	// Проверяем, что есть хотя бы одно поле для обновления
-	if update.Title == nil && update.Description == nil && update.ReleaseYear == nil && update.Rating == nil {
-		errMsg := "no fields to update"
-		log.Printf("Cannot update movie (ID: %d): %s", id, errMsg)
-		return errors.New(errMsg)
+	log.Printf("Successfully updated movie (ID: %d)", id)
+	return nil
+}
+
+// BulkDeleteMovies удаляет фильмы в одной транзакции и возвращает результат по каждому фильму
+func (s *MovieService) BulkDeleteMovies(ctx context.Context, ids []int) ([]domain.BulkItemResult, error) {
+	ctx, span := tracer().Start(ctx, "MovieService.BulkDeleteMovies")
+	defer span.End()
+
+	defer s.actorsCache.Invalidate()
+	log.Printf("Bulk deleting %d movies", len(ids))
+	results, err := s.store.BulkDeleteMovies(ctx, ids, s.deletePolicy)
+	if err != nil {
+		return nil, fmt.Errorf("bulk deleting movies: %w", err)
+	}
+	for _, result := range results {
+		if result.Err == nil {
+			s.recordRevision(ctx, result.ID, domain.MovieUpdate{}, true)
+		}
 	}
+	return results, nil
+}
 
-	// Логируем обновляемые поля
-	updatedFields := []string{}
+// BulkUpdateMovies применяет одни и те же изменения к фильмам в одной транзакции
+// и возвращает результат по каждому фильму
+func (s *MovieService) BulkUpdateMovies(ctx context.Context, ids []int, update domain.MovieUpdate) ([]domain.BulkItemResult, error) {
+	ctx, span := tracer().Start(ctx, "MovieService.BulkUpdateMovies")
+	defer span.End()
 
-	// Обновляем только переданные поля
-	if update.Title != nil {
-		updatedFields = append(updatedFields, fmt.Sprintf("Title: %s -> %s", movie.Title, *update.Title))
-		movie.Title = *update.Title
+	if update.IsEmpty() {
+		return nil, domain.ErrNoFieldsToUpdate
 	}
-	if update.Description != nil {
-		descPreview := ""
-		if movie.Description != "" {
-			descPreview = movie.Description
-			if len(descPreview) > 20 {
-				descPreview = descPreview[:20] + "..."
+
+	defer s.actorsCache.Invalidate()
+	log.Printf("Bulk updating %d movies", len(ids))
+	previousRatings, err := s.ratingsBeforeUpdate(ctx, ids, update)
+	if err != nil {
+		return nil, err
+	}
+	results, err := s.store.BulkUpdateMovies(ctx, ids, update)
+	if err != nil {
+		return nil, fmt.Errorf("bulk updating movies: %w", err)
+	}
+	for _, result := range results {
+		if result.Err == nil {
+			s.recordRevision(ctx, result.ID, update, false)
+			if previous, ok := previousRatings[result.ID]; ok {
+				s.recordRatingChange(ctx, result.ID, previous, *update.Rating)
 			}
 		}
-		updatedFields = append(updatedFields, "Description")
-		movie.Description = *update.Description
 	}
-	if update.ReleaseYear != nil {
-		updatedFields = append(updatedFields, fmt.Sprintf("ReleaseYear: %d -> %d", movie.ReleaseYear, *update.ReleaseYear))
-		movie.ReleaseYear = *update.ReleaseYear
+	return results, nil
+}
+
+// MergeMovies объединяет фильм-дубликат с основным фильмом
+func (s *MovieService) MergeMovies(ctx context.Context, keepID, dupID int) (domain.MovieMergeResult, error) {
+	ctx, span := tracer().Start(ctx, "MovieService.MergeMovies")
+	defer span.End()
+
+	defer s.actorsCache.Invalidate()
+	if keepID == dupID {
+		return domain.MovieMergeResult{}, domain.ErrMergeSameMovie
+	}
+
+	log.Printf("Merging movie (ID: %d) into movie (ID: %d)", dupID, keepID)
+	result, err := s.store.MergeMovies(ctx, keepID, dupID, s.deletePolicy)
+	if err != nil {
+		if errors.Is(err, domain.ErrMovieNotFound) {
+			return domain.MovieMergeResult{}, domain.ErrMovieNotFound
+		}
+		if errors.Is(err, domain.ErrMovieReferenced) {
+			return domain.MovieMergeResult{}, err
+		}
+		return domain.MovieMergeResult{}, fmt.Errorf("merging movies: %w", err)
 	}
-	if update.Rating != nil {
-		updatedFields = append(updatedFields, fmt.Sprintf("Rating: %.1f -> %.1f", movie.Rating, *update.Rating))
-		movie.Rating = *update.Rating
+	s.recordRevision(ctx, keepID, movieSnapshot(result.Movie), false)
+	s.recordRevision(ctx, dupID, domain.MovieUpdate{}, true)
+
+	actors, err := s.store.GetActorsForMovieByID(ctx, keepID)
+	if err == nil {
+		result.Movie.Actors = actors
 	}
 
-	log.Printf("Updating movie (ID: %d) fields: %v", id, updatedFields)
+	log.Printf("Successfully merged movie (ID: %d) into movie (ID: %d), actors reassigned: %d",
+		dupID, keepID, result.ActorsReassigned)
+	return result, nil
+}
 
-	// Обновляем фильм
-	if err := s.store.Update(movie); err != nil {
-		log.Printf("Error updating movie (ID: %d): %v", id, err)
+// ResolveMergedMovieID возвращает актуальный ID для фильма, слитого с другим фильмом
+func (s *MovieService) ResolveMergedMovieID(ctx context.Context, id int) (int, error) {
+	ctx, span := tracer().Start(ctx, "MovieService.ResolveMergedMovieID")
+	defer span.End()
+
+	newID, err := s.store.GetMergedMovieID(ctx, id)
+	if err != nil {
 		if errors.Is(err, domain.ErrMovieNotFound) {
-			// Это не должно происходить, так как мы уже проверили существование
-			log.Printf("Movie (ID: %d) was deleted by another process", id)
-			return domain.ErrMovieNotFound
+			return 0, domain.ErrMovieNotFound
 		}
-		return fmt.Errorf("updating movie: %w", err)
+		return 0, fmt.Errorf("resolving merged movie: %w", err)
 	}
+	return newID, nil
+}
 
-	log.Printf("Successfully updated movie (ID: %d)", id)
+// GetUpcomingMovies возвращает фильмы с датой выхода после сегодняшнего дня
+func (s *MovieService) GetUpcomingMovies(ctx context.Context) ([]domain.Movie, error) {
+	ctx, span := tracer().Start(ctx, "MovieService.GetUpcomingMovies")
+	defer span.End()
+
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	movies, err := s.store.GetUpcomingMovies(ctx, today)
+	if err != nil {
+		return nil, fmt.Errorf("getting upcoming movies: %w", err)
+	}
+	return movies, nil
+}
+
+// RecordMovieView засчитывает просмотр фильма. Вызывается консьюмером событий movie_viewed
+func (s *MovieService) RecordMovieView(ctx context.Context, movieID int) error {
+	ctx, span := tracer().Start(ctx, "MovieService.RecordMovieView")
+	defer span.End()
+
+	if err := s.store.IncrementViewCount(ctx, movieID, 1); err != nil {
+		return fmt.Errorf("recording view of movie %d: %w", movieID, err)
+	}
 	return nil
 }
+
+// GetPopularMovies возвращает limit фильмов с наибольшим числом просмотров
+func (s *MovieService) GetPopularMovies(ctx context.Context, limit int) ([]domain.Movie, error) {
+	ctx, span := tracer().Start(ctx, "MovieService.GetPopularMovies")
+	defer span.End()
+
+	movies, err := s.store.GetPopularMovies(ctx, limit)
+	if err != nil {
+		return nil, fmt.Errorf("getting popular movies: %w", err)
+	}
+	return movies, nil
+}
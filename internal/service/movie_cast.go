@@ -0,0 +1,59 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"cinematique/internal/domain"
+)
+
+// ListCast возвращает страницу состава фильма в порядке титров и число актёров во всём составе.
+// Пустой состав отличается от несуществующего фильма: для него возвращается ErrMovieNotFound
+func (s *MovieService) ListCast(ctx context.Context, movieID, limit, offset int) ([]domain.Actor, int, error) {
+	ctx, span := tracer().Start(ctx, "MovieService.ListCast")
+	defer span.End()
+
+	total, err := s.store.CountMovieCast(ctx, movieID)
+	if err != nil {
+		return nil, 0, fmt.Errorf("counting movie cast: %w", err)
+	}
+	if total == 0 {
+		if _, err := s.store.GetByID(ctx, movieID); err != nil {
+			if errors.Is(err, domain.ErrMovieNotFound) {
+				return nil, 0, domain.ErrMovieNotFound
+			}
+			return nil, 0, fmt.Errorf("getting movie by ID: %w", err)
+		}
+		return []domain.Actor{}, 0, nil
+	}
+	if offset >= total {
+		return []domain.Actor{}, total, nil
+	}
+	cast, err := s.store.GetMovieCastPage(ctx, movieID, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("getting movie cast: %w", err)
+	}
+	return cast, total, nil
+}
+
+// ChangeMovieActors добавляет в состав фильма актёров add и убирает актёров remove, не трогая
+// остальных. Изменение атомарно; если актёра из add нет, возвращается ErrActorNotFound
+func (s *MovieService) ChangeMovieActors(ctx context.Context, movieID int, add []domain.CastMember, remove []int) error {
+	ctx, span := tracer().Start(ctx, "MovieService.ChangeMovieActors")
+	defer span.End()
+
+	if err := s.checkMovieExists(ctx, movieID); err != nil {
+		return err
+	}
+	if err := s.store.ChangeMovieActors(ctx, movieID, add, remove); err != nil {
+		if errors.Is(err, domain.ErrReferenceNotFound) {
+			return fmt.Errorf("adding actors to movie %d: %w", movieID, domain.ErrActorNotFound)
+		}
+		return fmt.Errorf("changing movie actors: %w", err)
+	}
+	s.actorsCache.Invalidate()
+	log.Printf("Changed cast of movie (ID: %d): %d added, %d removed", movieID, len(add), len(remove))
+	return nil
+}
@@ -0,0 +1,74 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"cinematique/internal/domain"
+	"cinematique/internal/testutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMovieService_ListCast(t *testing.T) {
+	catalog := testutil.NewCatalog()
+	matrix := catalog.SeedMovie(domain.Movie{Title: "The Matrix", ReleaseYear: 1999})
+	empty := catalog.SeedMovie(domain.Movie{Title: "Untitled", ReleaseYear: 2030})
+	keanu := catalog.SeedActor(domain.Actor{Name: "Keanu Reeves"})
+	carrie := catalog.SeedActor(domain.Actor{Name: "Carrie-Anne Moss"})
+	laurence := catalog.SeedActor(domain.Actor{Name: "Laurence Fishburne"})
+	catalog.SeedCast(matrix, keanu, carrie, laurence)
+	movies := catalog.Movies()
+	svc := NewMovie(movies, nil)
+	ctx := context.Background()
+
+	cast, total, err := svc.ListCast(ctx, matrix, 2, 1)
+	require.NoError(t, err)
+	assert.Equal(t, 3, total)
+	assert.Equal(t, []int{carrie, laurence}, castIDs(cast))
+
+	// Страница за концом состава не читается из базы
+	cast, total, err = svc.ListCast(ctx, matrix, 2, 10)
+	require.NoError(t, err)
+	assert.Equal(t, 3, total)
+	assert.Empty(t, cast)
+	assert.Equal(t, 1, movies.Calls("GetMovieCastPage"))
+
+	cast, total, err = svc.ListCast(ctx, empty, 2, 0)
+	require.NoError(t, err)
+	assert.Zero(t, total)
+	assert.Empty(t, cast)
+
+	_, _, err = svc.ListCast(ctx, 404, 2, 0)
+	assert.ErrorIs(t, err, domain.ErrMovieNotFound)
+}
+
+func TestMovieService_ChangeMovieActors(t *testing.T) {
+	ctx := context.Background()
+	catalog := testutil.NewCatalog()
+	movieID := catalog.SeedMovie(domain.Movie{Title: "The Matrix", ReleaseYear: 1999})
+	keanu := catalog.SeedActor(domain.Actor{Name: "Keanu Reeves"})
+	carrie := catalog.SeedActor(domain.Actor{Name: "Carrie-Anne Moss"})
+	hugo := catalog.SeedActor(domain.Actor{Name: "Hugo Weaving"})
+	catalog.SeedCast(movieID, keanu, carrie)
+	svc := NewMovie(catalog.Movies(), catalog.Actors())
+
+	err := svc.ChangeMovieActors(ctx, movieID, []domain.CastMember{{ActorID: hugo, CharacterName: "Agent Smith"}, {ActorID: keanu}}, []int{carrie})
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []int{keanu, hugo}, catalog.CastIDs(movieID))
+
+	// Неизвестный актёр отменяет всё изменение, в том числе удаление
+	err = svc.ChangeMovieActors(ctx, movieID, []domain.CastMember{{ActorID: 999}}, []int{keanu})
+	assert.ErrorIs(t, err, domain.ErrActorNotFound)
+	assert.ElementsMatch(t, []int{keanu, hugo}, catalog.CastIDs(movieID))
+
+	movies := catalog.Movies()
+	movies.FailOn("ChangeMovieActors", errors.New("connection reset"))
+	err = NewMovie(movies, catalog.Actors()).ChangeMovieActors(ctx, movieID, nil, []int{hugo})
+	assert.EqualError(t, err, "changing movie actors: connection reset")
+
+	err = svc.ChangeMovieActors(ctx, 404, nil, []int{keanu})
+	assert.ErrorIs(t, err, domain.ErrMovieNotFound)
+}
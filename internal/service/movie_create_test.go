@@ -0,0 +1,70 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"cinematique/internal/domain"
+	"cinematique/internal/testutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	_ StoreMovie = (*testutil.MovieStore)(nil)
+	_ StoreActor = (*testutil.ActorStore)(nil)
+)
+
+func TestMovieService_Create_RemovesMovieWhenCastFails(t *testing.T) {
+	catalog := testutil.NewCatalog()
+	first := catalog.SeedActor(domain.Actor{Name: "Keanu Reeves"})
+	second := catalog.SeedActor(domain.Actor{Name: "Carrie-Anne Moss"})
+	movies := catalog.Movies()
+	svc := NewMovie(movies, catalog.Actors())
+	ctx := context.Background()
+
+	// Первый актёр добавляется, на втором соединение с базой обрывается
+	errConn := errors.New("connection reset")
+	movies.FailAfter("AddActor", 1, errConn)
+	_, err := svc.Create(ctx, domain.Movie{Title: "The Matrix", ReleaseYear: 1999}, []int{first, second}, false)
+	require.ErrorIs(t, err, errConn)
+	assert.Equal(t, 1, movies.Calls("Delete"))
+	assert.Zero(t, catalog.MovieCount())
+
+	// После сбоя фильм создаётся заново с тем же slug и полным составом
+	id, err := svc.Create(ctx, domain.Movie{Title: "The Matrix", ReleaseYear: 1999}, []int{first, second}, false)
+	require.NoError(t, err)
+	movie, ok := catalog.Movie(id)
+	require.True(t, ok)
+	assert.Equal(t, "the-matrix-1999", movie.Slug)
+	assert.ElementsMatch(t, []int{first, second}, catalog.CastIDs(id))
+}
+
+func TestMovieService_DeleteCreated_RemovesNewActors(t *testing.T) {
+	catalog := testutil.NewCatalog()
+	existing := catalog.SeedActor(domain.Actor{Name: "Laurence Fishburne"})
+	other := catalog.SeedMovie(domain.Movie{Title: "John Wick", ReleaseYear: 2014})
+	svc := NewMovie(catalog.Movies(), catalog.Actors())
+	ctx := context.Background()
+
+	result, err := svc.CreateFull(ctx, domain.Movie{Title: "The Matrix", ReleaseYear: 1999},
+		[]domain.Actor{{Name: "Keanu Reeves"}, {Name: "Carrie-Anne Moss"}, {ID: existing}}, false)
+	require.NoError(t, err)
+	require.Len(t, result.CreatedActorIDs, 2)
+	keanu, carrie := result.CreatedActorIDs[0], result.CreatedActorIDs[1]
+	// Пока шёл откат, одного из новых актёров добавили в другой фильм
+	catalog.SeedCast(other, keanu)
+
+	require.NoError(t, svc.DeleteCreated(ctx, result.MovieID, result.CreatedActorIDs))
+
+	_, ok := catalog.Movie(result.MovieID)
+	assert.False(t, ok)
+	_, ok = catalog.Actor(carrie)
+	assert.False(t, ok, "actor created with the movie is deleted")
+	_, ok = catalog.Actor(keanu)
+	assert.True(t, ok, "actor cast in another movie is kept")
+	_, ok = catalog.Actor(existing)
+	assert.True(t, ok, "existing actor is kept")
+}
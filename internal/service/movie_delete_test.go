@@ -0,0 +1,82 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"cinematique/internal/domain"
+	"cinematique/internal/testutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMovieService_DeletePolicy(t *testing.T) {
+	collections := []domain.MovieReference{{Kind: "collections", Count: 2}}
+	// newCatalog возвращает каталог с одним фильмом и заданными ссылками на него
+	newCatalog := func(references ...domain.MovieReference) (*testutil.Catalog, int) {
+		catalog := testutil.NewCatalog()
+		id := catalog.SeedMovie(domain.Movie{Title: "The Matrix", ReleaseYear: 1999})
+		catalog.SetReferences(id, references...)
+		return catalog, id
+	}
+
+	t.Run("cascade deletes referenced movie", func(t *testing.T) {
+		catalog, id := newCatalog(collections...)
+		svc := NewMovie(catalog.Movies(), nil)
+
+		require.NoError(t, svc.Delete(context.Background(), id))
+		assert.Zero(t, catalog.MovieCount())
+	})
+
+	t.Run("restrict lists blocking references", func(t *testing.T) {
+		catalog, id := newCatalog(collections...)
+		svc := NewMovie(catalog.Movies(), nil).WithDeletePolicy(domain.MovieDeleteRestrict)
+
+		err := svc.Delete(context.Background(), id)
+
+		var refErr *domain.MovieReferencedError
+		require.ErrorAs(t, err, &refErr)
+		assert.Equal(t, collections, refErr.References)
+		assert.ErrorIs(t, err, domain.ErrMovieReferenced)
+		assert.Equal(t, 1, catalog.MovieCount())
+	})
+
+	t.Run("restrict deletes unreferenced movie", func(t *testing.T) {
+		catalog, id := newCatalog()
+		svc := NewMovie(catalog.Movies(), nil).WithDeletePolicy(domain.MovieDeleteRestrict)
+
+		require.NoError(t, svc.Delete(context.Background(), id))
+		assert.Zero(t, catalog.MovieCount())
+	})
+
+	t.Run("missing movie", func(t *testing.T) {
+		catalog, _ := newCatalog()
+		err := NewMovie(catalog.Movies(), nil).WithDeletePolicy(domain.MovieDeleteRestrict).Delete(context.Background(), 404)
+		assert.ErrorIs(t, err, domain.ErrMovieNotFound)
+		assert.Equal(t, 1, catalog.MovieCount())
+	})
+
+	t.Run("restrict applies to bulk delete", func(t *testing.T) {
+		catalog, referenced := newCatalog(collections...)
+		free := catalog.SeedMovie(domain.Movie{Title: "Heat", ReleaseYear: 1995})
+		svc := NewMovie(catalog.Movies(), nil).WithDeletePolicy(domain.MovieDeleteRestrict)
+
+		results, err := svc.BulkDeleteMovies(context.Background(), []int{referenced, free})
+		require.NoError(t, err)
+		require.Len(t, results, 2)
+		assert.ErrorIs(t, results[0].Err, domain.ErrMovieReferenced)
+		assert.NoError(t, results[1].Err)
+		assert.Equal(t, 1, catalog.MovieCount())
+	})
+
+	t.Run("restrict keeps referenced duplicate on merge", func(t *testing.T) {
+		catalog, dupID := newCatalog(collections...)
+		keepID := catalog.SeedMovie(domain.Movie{Title: "The Matrix", ReleaseYear: 1999})
+		svc := NewMovie(catalog.Movies(), nil).WithDeletePolicy(domain.MovieDeleteRestrict)
+
+		_, err := svc.MergeMovies(context.Background(), keepID, dupID)
+		assert.ErrorIs(t, err, domain.ErrMovieReferenced)
+		assert.Equal(t, 2, catalog.MovieCount())
+	})
+}
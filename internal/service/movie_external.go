@@ -0,0 +1,73 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"cinematique/internal/domain"
+)
+
+// UpsertByExternalID создаёт фильм с внешним ID movie.ExternalID или перезаписывает его поля,
+// если фильм уже синхронизирован. Повтор с теми же данными ничего не пишет и не добавляет
+// ревизий, поэтому плановая синхронизация каталога безопасна. actorIDs == nil оставляет
+// состав как есть, иначе заменяет его. Проверки на похожие фильмы нет: внешний ID и есть
+// признак того же фильма
+func (s *MovieService) UpsertByExternalID(ctx context.Context, movie domain.Movie, actorIDs []int) (int, domain.MovieUpsertAction, error) {
+	ctx, span := tracer().Start(ctx, "MovieService.UpsertByExternalID")
+	defer span.End()
+
+	defer s.actorsCache.Invalidate()
+	existing, err := s.store.GetByExternalID(ctx, movie.ExternalID)
+	found := err == nil
+	if err != nil && !errors.Is(err, domain.ErrMovieNotFound) {
+		return 0, "", fmt.Errorf("getting movie by external ID: %w", err)
+	}
+
+	id, action := existing.ID, domain.MovieUpsertUnchanged
+	if !found || len(existing.ChangedFields(movie)) > 0 {
+		var created bool
+		id, created, err = s.store.UpsertByExternalID(ctx, movie)
+		if err != nil {
+			return 0, "", fmt.Errorf("upserting movie: %w", err)
+		}
+		s.recordRevision(ctx, id, movieSnapshot(movie), false)
+		if created {
+			action = domain.MovieUpsertCreated
+			s.syncInternalRating(ctx, id, movie.Rating)
+		} else {
+			action = domain.MovieUpsertUpdated
+			// Фильм мог создать параллельный запрос после нашей проверки: тогда прежнего рейтинга нет
+			if found {
+				s.recordRatingChange(ctx, id, existing.Rating, movie.Rating)
+			} else {
+				s.syncInternalRating(ctx, id, movie.Rating)
+			}
+		}
+	}
+
+	if actorIDs != nil {
+		cast := make([]domain.CastMember, len(actorIDs))
+		for i, actorID := range actorIDs {
+			cast[i] = domain.CastMember{ActorID: actorID}
+		}
+		if err := s.store.UpdateMovieActors(ctx, id, cast); err != nil {
+			log.Printf("Error replacing cast of movie (ID: %d, external ID: %s): %v", id, movie.ExternalID, err)
+			return 0, "", fmt.Errorf("replacing movie actors: %w", err)
+		}
+	}
+	return id, action, nil
+}
+
+// GetByExternalID возвращает фильм по ID в каталоге-источнике
+func (s *MovieService) GetByExternalID(ctx context.Context, externalID string) (domain.Movie, error) {
+	ctx, span := tracer().Start(ctx, "MovieService.GetByExternalID")
+	defer span.End()
+
+	movie, err := s.store.GetByExternalID(ctx, externalID)
+	if err != nil {
+		return domain.Movie{}, fmt.Errorf("getting movie by external ID: %w", err)
+	}
+	return movie, nil
+}
@@ -0,0 +1,46 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"cinematique/internal/domain"
+	"cinematique/internal/testutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMovieService_UpsertByExternalID(t *testing.T) {
+	catalog := testutil.NewCatalog()
+	keanu := catalog.SeedActor(domain.Actor{Name: "Keanu Reeves"})
+	carrie := catalog.SeedActor(domain.Actor{Name: "Carrie-Anne Moss"})
+	movies := catalog.Movies()
+	svc := NewMovie(movies, nil)
+	ctx := context.Background()
+	movie := domain.Movie{Title: "The Matrix", ReleaseYear: 1999, Rating: 8.5, ExternalID: "tt0133093"}
+
+	id, action, err := svc.UpsertByExternalID(ctx, movie, nil)
+	require.NoError(t, err)
+	assert.Equal(t, domain.MovieUpsertCreated, action)
+	assert.Equal(t, 1, catalog.MovieCount())
+
+	// Те же данные ещё раз: ни записи, ни новой ревизии
+	again, action, err := svc.UpsertByExternalID(ctx, movie, nil)
+	require.NoError(t, err)
+	assert.Equal(t, id, again)
+	assert.Equal(t, domain.MovieUpsertUnchanged, action)
+	assert.Equal(t, 1, movies.Calls("UpsertByExternalID"))
+	assert.Len(t, catalog.Revisions(id), 1)
+
+	movie.Rating = 8.7
+	again, action, err = svc.UpsertByExternalID(ctx, movie, []int{keanu, carrie})
+	require.NoError(t, err)
+	assert.Equal(t, id, again)
+	assert.Equal(t, domain.MovieUpsertUpdated, action)
+	assert.Len(t, catalog.Revisions(id), 2)
+	changes := catalog.RatingChanges(id)
+	require.Len(t, changes, 1)
+	assert.Equal(t, 8.5, *changes[0].OldRating)
+	assert.ElementsMatch(t, []int{keanu, carrie}, catalog.CastIDs(id))
+}
@@ -0,0 +1,50 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"cinematique/internal/domain"
+	"cinematique/internal/testutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMovieService_MergeMovesRelations(t *testing.T) {
+	catalog := testutil.NewCatalog()
+	store := catalog.Movies()
+	ctx := context.Background()
+	keepID := catalog.SeedMovie(domain.Movie{Title: "Alien", ReleaseYear: 1979})
+	dupID := catalog.SeedMovie(domain.Movie{Title: "Alien (1979)", ReleaseYear: 1979, ExternalID: "tt0078748"})
+
+	require.NoError(t, store.AddMovieTag(ctx, dupID, "sci-fi"))
+	_, err := store.AddAvailability(ctx, domain.Availability{MovieID: dupID, Region: "US", AvailableFrom: time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)})
+	require.NoError(t, err)
+	require.NoError(t, store.SetMovieRating(ctx, domain.MovieRating{MovieID: keepID, Source: domain.RatingSourceIMDb, Rating: 8.5}))
+	require.NoError(t, store.SetMovieRating(ctx, domain.MovieRating{MovieID: dupID, Source: domain.RatingSourceIMDb, Rating: 8.1}))
+	require.NoError(t, store.SetMovieRating(ctx, domain.MovieRating{MovieID: dupID, Source: domain.RatingSourceRottenTomatoes, Rating: 98}))
+
+	// Под restrict слияние проходит: всё, что ссылалось на дубликат, переезжает на основной фильм
+	svc := NewMovie(store, nil).WithDeletePolicy(domain.MovieDeleteRestrict)
+	result, err := svc.MergeMovies(ctx, keepID, dupID)
+	require.NoError(t, err)
+	assert.Equal(t, "tt0078748", result.Movie.ExternalID)
+
+	tags, err := store.GetMovieTags(ctx, keepID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"sci-fi"}, tags)
+
+	windows, err := store.GetAvailability(ctx, keepID)
+	require.NoError(t, err)
+	require.Len(t, windows, 1)
+	assert.Equal(t, "US", windows[0].Region)
+
+	// Рейтинг источника, который у основного фильма уже есть, остаётся прежним
+	ratings, err := store.GetMovieRatings(ctx, keepID)
+	require.NoError(t, err)
+	require.Len(t, ratings, 2)
+	assert.Equal(t, 8.5, ratings[0].Rating)
+	assert.Equal(t, domain.RatingSourceRottenTomatoes, ratings[1].Source)
+}
@@ -0,0 +1,25 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"cinematique/internal/domain"
+)
+
+// ListByYears возвращает страницу фильмов, вышедших с fromYear по toYear включительно,
+// и их общее число. Для одного года fromYear и toYear совпадают
+func (s *MovieService) ListByYears(ctx context.Context, fromYear, toYear, limit, offset int) ([]domain.Movie, int, error) {
+	ctx, span := tracer().Start(ctx, "MovieService.ListByYears")
+	defer span.End()
+
+	movies, err := s.store.GetMoviesByYears(ctx, fromYear, toYear, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("getting movies by years: %w", err)
+	}
+	total, err := s.store.CountMoviesByYears(ctx, fromYear, toYear)
+	if err != nil {
+		return nil, 0, fmt.Errorf("counting movies by years: %w", err)
+	}
+	return movies, total, nil
+}
@@ -0,0 +1,104 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"cinematique/internal/domain"
+)
+
+// DefaultRatingWeights — веса источников, с которыми все рейтинги учитываются одинаково
+var DefaultRatingWeights = domain.RatingWeights{
+	domain.RatingSourceInternal:       1,
+	domain.RatingSourceIMDb:           1,
+	domain.RatingSourceRottenTomatoes: 1,
+}
+
+// WithRatingWeights задаёт веса источников в отображаемом рейтинге фильма
+func (s *MovieService) WithRatingWeights(weights domain.RatingWeights) *MovieService {
+	s.ratingWeights = weights
+	return s
+}
+
+// movieRatings возвращает рейтинги фильма по источникам и отображаемый рейтинг.
+// Внутренний рейтинг берётся из самого фильма: строка internal в movie_ratings
+// может отстать, если её запись после изменения фильма не удалась
+func (s *MovieService) movieRatings(ctx context.Context, movie domain.Movie) ([]domain.MovieRating, *float64, error) {
+	stored, err := s.store.GetMovieRatings(ctx, movie.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	ratings := make([]domain.MovieRating, 0, len(stored)+1)
+	ratings = append(ratings, domain.MovieRating{MovieID: movie.ID, Source: domain.RatingSourceInternal, Rating: movie.Rating, UpdatedAt: movie.UpdatedAt})
+	for _, rating := range stored {
+		if rating.Source != domain.RatingSourceInternal {
+			ratings = append(ratings, rating)
+		}
+	}
+	display, ok := s.ratingWeights.DisplayRating(ratings)
+	if !ok {
+		return ratings, nil, nil
+	}
+	return ratings, &display, nil
+}
+
+// GetRatings возвращает рейтинги фильма по источникам и взвешенный отображаемый рейтинг
+func (s *MovieService) GetRatings(ctx context.Context, movieID int) ([]domain.MovieRating, *float64, error) {
+	ctx, span := tracer().Start(ctx, "MovieService.GetRatings")
+	defer span.End()
+
+	movie, err := s.store.GetByID(ctx, movieID)
+	if err != nil {
+		if errors.Is(err, domain.ErrMovieNotFound) {
+			return nil, nil, domain.ErrMovieNotFound
+		}
+		return nil, nil, fmt.Errorf("getting movie by ID: %w", err)
+	}
+	ratings, display, err := s.movieRatings(ctx, movie)
+	if err != nil {
+		return nil, nil, fmt.Errorf("getting movie ratings: %w", err)
+	}
+	return ratings, display, nil
+}
+
+// SetRating сохраняет рейтинг фильма из внешнего источника
+func (s *MovieService) SetRating(ctx context.Context, rating domain.MovieRating) error {
+	ctx, span := tracer().Start(ctx, "MovieService.SetRating")
+	defer span.End()
+
+	if err := s.checkMovieExists(ctx, rating.MovieID); err != nil {
+		return err
+	}
+	if err := s.store.SetMovieRating(ctx, rating); err != nil {
+		return fmt.Errorf("setting movie rating: %w", err)
+	}
+	return nil
+}
+
+// DeleteRating удаляет рейтинг фильма из внешнего источника
+func (s *MovieService) DeleteRating(ctx context.Context, movieID int, source string) error {
+	ctx, span := tracer().Start(ctx, "MovieService.DeleteRating")
+	defer span.End()
+
+	if err := s.checkMovieExists(ctx, movieID); err != nil {
+		return err
+	}
+	if err := s.store.DeleteMovieRating(ctx, movieID, source); err != nil {
+		if errors.Is(err, domain.ErrRatingNotFound) {
+			return domain.ErrRatingNotFound
+		}
+		return fmt.Errorf("deleting movie rating: %w", err)
+	}
+	return nil
+}
+
+// syncInternalRating переносит рейтинг фильма в строку internal таблицы movie_ratings.
+// Ответы API берут внутренний рейтинг из фильма, поэтому ошибка только логируется
+func (s *MovieService) syncInternalRating(ctx context.Context, movieID int, rating float64) {
+	internal := domain.MovieRating{MovieID: movieID, Source: domain.RatingSourceInternal, Rating: rating}
+	if err := s.store.SetMovieRating(ctx, internal); err != nil {
+		log.Printf("Error syncing internal rating for movie (ID: %d): %v", movieID, err)
+	}
+}
@@ -0,0 +1,103 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"cinematique/internal/domain"
+	"cinematique/internal/testutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// seedRatings возвращает каталог с фильмом с внутренним рейтингом 8 и его рейтингами по источникам
+func seedRatings(t *testing.T, ratings ...domain.MovieRating) (*testutil.Catalog, int) {
+	t.Helper()
+	catalog := testutil.NewCatalog()
+	id := catalog.SeedMovie(domain.Movie{Title: "The Matrix", ReleaseYear: 1999, Rating: 8})
+	movies := catalog.Movies()
+	for _, rating := range ratings {
+		rating.MovieID = id
+		require.NoError(t, movies.SetMovieRating(context.Background(), rating))
+	}
+	return catalog, id
+}
+
+func TestMovieService_GetRatings(t *testing.T) {
+	catalog, id := seedRatings(t,
+		domain.MovieRating{Source: domain.RatingSourceIMDb, Rating: 9},
+		// Устаревшая строка internal заменяется рейтингом самого фильма
+		domain.MovieRating{Source: domain.RatingSourceInternal, Rating: 5},
+		domain.MovieRating{Source: domain.RatingSourceRottenTomatoes, Rating: 70},
+	)
+	store := catalog.Movies()
+
+	t.Run("equal weights", func(t *testing.T) {
+		ratings, display, err := NewMovie(store, nil).GetRatings(context.Background(), id)
+		require.NoError(t, err)
+		require.Len(t, ratings, 3)
+		assert.Equal(t, domain.RatingSourceInternal, ratings[0].Source)
+		assert.Equal(t, 8.0, ratings[0].Rating)
+		require.NotNil(t, display)
+		assert.Equal(t, 8.0, *display) // (8 + 9 + 7) / 3
+	})
+
+	t.Run("zero weight excludes source", func(t *testing.T) {
+		svc := NewMovie(store, nil).WithRatingWeights(domain.RatingWeights{
+			domain.RatingSourceInternal: 1,
+			domain.RatingSourceIMDb:     3,
+		})
+		_, display, err := svc.GetRatings(context.Background(), id)
+		require.NoError(t, err)
+		require.NotNil(t, display)
+		assert.Equal(t, 8.8, *display) // (8 + 3*9) / 4
+	})
+
+	t.Run("no weighted sources", func(t *testing.T) {
+		svc := NewMovie(store, nil).WithRatingWeights(domain.RatingWeights{})
+		_, display, err := svc.GetRatings(context.Background(), id)
+		require.NoError(t, err)
+		assert.Nil(t, display)
+	})
+
+	t.Run("movie not found", func(t *testing.T) {
+		_, _, err := NewMovie(store, nil).GetRatings(context.Background(), 404)
+		assert.ErrorIs(t, err, domain.ErrMovieNotFound)
+	})
+}
+
+func TestMovieService_GetByID_Ratings(t *testing.T) {
+	catalog, id := seedRatings(t, domain.MovieRating{Source: domain.RatingSourceIMDb, Rating: 9})
+	store := catalog.Movies()
+	movie, err := NewMovie(store, nil).GetByID(context.Background(), id)
+	require.NoError(t, err)
+	require.Len(t, movie.Ratings, 2)
+	require.NotNil(t, movie.DisplayRating)
+	assert.Equal(t, 8.5, *movie.DisplayRating)
+
+	// Ошибка чтения рейтингов не мешает отдать фильм
+	store.FailOn("GetMovieRatings", errors.New("connection reset"))
+	movie, err = NewMovie(store, nil).GetByID(context.Background(), id)
+	require.NoError(t, err)
+	assert.Empty(t, movie.Ratings)
+	assert.Nil(t, movie.DisplayRating)
+}
+
+func TestMovieService_SetRating(t *testing.T) {
+	catalog, id := seedRatings(t)
+	store := catalog.Movies()
+	svc := NewMovie(store, nil)
+
+	rating := domain.MovieRating{MovieID: id, Source: domain.RatingSourceIMDb, Rating: 8.7}
+	require.NoError(t, svc.SetRating(context.Background(), rating))
+	saved, err := store.GetMovieRatings(context.Background(), id)
+	require.NoError(t, err)
+	require.Len(t, saved, 1)
+	assert.Equal(t, rating.Rating, saved[0].Rating)
+	assert.Equal(t, rating.Source, saved[0].Source)
+
+	err = svc.SetRating(context.Background(), domain.MovieRating{MovieID: 404, Source: domain.RatingSourceIMDb, Rating: 5})
+	assert.ErrorIs(t, err, domain.ErrMovieNotFound)
+}
@@ -0,0 +1,134 @@
+package service
+
+import (
+	"cinematique/internal/domain"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+)
+
+// movieSnapshot возвращает все поля фильма в виде набора изменений для первой ревизии
+func movieSnapshot(movie domain.Movie) domain.MovieUpdate {
+	return domain.MovieUpdate{
+		Title:            &movie.Title,
+		Description:      &movie.Description,
+		ReleaseYear:      &movie.ReleaseYear,
+		ReleaseDate:      movie.ReleaseDate,
+		Rating:           &movie.Rating,
+		OriginalLanguage: &movie.OriginalLanguage,
+		Country:          &movie.Country,
+		RuntimeMinutes:   &movie.RuntimeMinutes,
+	}
+}
+
+// recordRevision сохраняет ревизию фильма. Ошибка записи истории не отменяет
+// уже выполненное изменение, поэтому только логируется
+func (s *MovieService) recordRevision(ctx context.Context, movieID int, changes domain.MovieUpdate, deleted bool) {
+	revision := domain.MovieRevision{MovieID: movieID, Changes: changes, Deleted: deleted}
+	if err := s.store.AddMovieRevision(ctx, revision); err != nil {
+		log.Printf("Error recording revision for movie (ID: %d): %v", movieID, err)
+	}
+}
+
+// applyMovieChanges применяет изменения ревизии к фильму
+func applyMovieChanges(movie *domain.Movie, changes domain.MovieUpdate) {
+	if changes.Title != nil {
+		movie.Title = *changes.Title
+	}
+	if changes.Description != nil {
+		movie.Description = *changes.Description
+	}
+	if changes.ReleaseYear != nil {
+		movie.ReleaseYear = *changes.ReleaseYear
+	}
+	if changes.ReleaseDate != nil {
+		movie.ReleaseDate = changes.ReleaseDate
+	} else if changes.ClearReleaseDate {
+		movie.ReleaseDate = nil
+	}
+	if changes.Rating != nil {
+		movie.Rating = *changes.Rating
+	}
+	if changes.OriginalLanguage != nil {
+		movie.OriginalLanguage = *changes.OriginalLanguage
+	}
+	if changes.Country != nil {
+		movie.Country = *changes.Country
+	}
+	if changes.RuntimeMinutes != nil {
+		movie.RuntimeMinutes = *changes.RuntimeMinutes
+	}
+}
+
+// GetMovieAsOf восстанавливает состояние фильма на момент asOf, последовательно применяя
+// его ревизии. Состав актёров в истории не хранится, поэтому не восстанавливается
+func (s *MovieService) GetMovieAsOf(ctx context.Context, id int, asOf time.Time) (domain.Movie, error) {
+	ctx, span := tracer().Start(ctx, "MovieService.GetMovieAsOf")
+	defer span.End()
+
+	revisions, err := s.store.GetMovieRevisions(ctx, id, asOf)
+	if err != nil {
+		return domain.Movie{}, fmt.Errorf("getting movie revisions: %w", err)
+	}
+	versions := movieVersions(id, revisions)
+	if len(versions) == 0 || versions[len(versions)-1].Deleted {
+		return domain.Movie{}, domain.ErrMovieNotFound
+	}
+	return versions[len(versions)-1].Movie, nil
+}
+
+// historyUntil — верхняя граница ревизий для полной истории. Берётся с запасом, а не
+// time.Now(): момент ревизии ставят часы базы, которые могут спешить относительно приложения
+var historyUntil = time.Date(9999, time.December, 31, 0, 0, 0, 0, time.UTC)
+
+// GetMovieHistory возвращает версии фильма от старых к новым с отличиями от предыдущей версии.
+// История переживает удаление фильма; ErrMovieNotFound — если нет ни фильма, ни истории
+func (s *MovieService) GetMovieHistory(ctx context.Context, id int) ([]domain.MovieVersion, error) {
+	ctx, span := tracer().Start(ctx, "MovieService.GetMovieHistory")
+	defer span.End()
+
+	revisions, err := s.store.GetMovieRevisions(ctx, id, historyUntil)
+	if err != nil {
+		return nil, fmt.Errorf("getting movie revisions: %w", err)
+	}
+	if len(revisions) == 0 {
+		if _, err := s.store.GetByID(ctx, id); err != nil {
+			if errors.Is(err, domain.ErrMovieNotFound) {
+				return nil, domain.ErrMovieNotFound
+			}
+			return nil, fmt.Errorf("checking movie existence: %w", err)
+		}
+	}
+	return movieVersions(id, revisions), nil
+}
+
+// movieVersions собирает версии фильма, применяя ревизии по порядку. Ревизия после
+// удаления начинает фильм заново, поэтому её поля считаются впервые заданными
+func movieVersions(id int, revisions []domain.MovieRevision) []domain.MovieVersion {
+	versions := make([]domain.MovieVersion, 0, len(revisions))
+	movie := domain.Movie{ID: id}
+	exists := false
+	for i, revision := range revisions {
+		version := domain.MovieVersion{Version: i + 1, ChangedAt: revision.ChangedAt, Deleted: revision.Deleted}
+		if revision.Deleted {
+			movie = domain.Movie{ID: id}
+			exists = false
+			version.Movie = movie
+			versions = append(versions, version)
+			continue
+		}
+		previous := movie
+		applyMovieChanges(&movie, revision.Changes)
+		if exists {
+			version.Changes = previous.Diff(movie)
+		} else {
+			version.Changes = initialChanges(previous.Diff(movie))
+		}
+		exists = true
+		version.Movie = movie
+		versions = append(versions, version)
+	}
+	return versions
+}
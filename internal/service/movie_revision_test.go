@@ -0,0 +1,48 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"cinematique/internal/domain"
+	"cinematique/internal/testutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMovieService_GetMovieHistory(t *testing.T) {
+	catalog := testutil.NewCatalog()
+	svc := NewMovie(catalog.Movies(), catalog.Actors())
+	ctx := context.Background()
+
+	id, err := svc.Create(ctx, domain.Movie{Title: "The Matrix", ReleaseYear: 1999, Rating: 8.7}, nil, true)
+	require.NoError(t, err)
+	released := time.Date(1999, time.March, 31, 0, 0, 0, 0, time.UTC)
+	title := "The Matrix"
+	// Повторно переданное название не попадает в отличия версии
+	require.NoError(t, svc.PartialUpdateMovie(ctx, id, domain.MovieUpdate{Title: &title, ReleaseDate: &released}))
+
+	versions, err := svc.GetMovieHistory(ctx, id)
+	require.NoError(t, err)
+	require.Len(t, versions, 2)
+	assert.Equal(t, 1, versions[0].Version)
+	assert.Equal(t, []domain.FieldChange{
+		{Field: "title", New: "The Matrix"},
+		{Field: "release_year", New: 1999},
+		{Field: "rating", New: 8.7},
+	}, versions[0].Changes)
+	assert.Equal(t, []domain.FieldChange{{Field: "release_date", Old: (*time.Time)(nil), New: &released}}, versions[1].Changes)
+	assert.Equal(t, &released, versions[1].Movie.ReleaseDate)
+
+	// История остаётся доступной после удаления фильма
+	require.NoError(t, svc.Delete(ctx, id))
+	versions, err = svc.GetMovieHistory(ctx, id)
+	require.NoError(t, err)
+	require.Len(t, versions, 3)
+	assert.True(t, versions[2].Deleted)
+
+	_, err = svc.GetMovieHistory(ctx, 999)
+	assert.ErrorIs(t, err, domain.ErrMovieNotFound)
+}
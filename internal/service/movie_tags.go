@@ -0,0 +1,87 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"cinematique/internal/domain"
+)
+
+// AddTag добавляет фильму тег. Тег приходит уже нормализованным из контроллера
+func (s *MovieService) AddTag(ctx context.Context, movieID int, tag string) ([]string, error) {
+	ctx, span := tracer().Start(ctx, "MovieService.AddTag")
+	defer span.End()
+
+	if err := s.checkMovieExists(ctx, movieID); err != nil {
+		return nil, err
+	}
+	if err := s.store.AddMovieTag(ctx, movieID, tag); err != nil {
+		return nil, fmt.Errorf("adding movie tag: %w", err)
+	}
+	return s.getTags(ctx, movieID)
+}
+
+// RemoveTag снимает тег с фильма
+func (s *MovieService) RemoveTag(ctx context.Context, movieID int, tag string) error {
+	ctx, span := tracer().Start(ctx, "MovieService.RemoveTag")
+	defer span.End()
+
+	if err := s.checkMovieExists(ctx, movieID); err != nil {
+		return err
+	}
+	if err := s.store.RemoveMovieTag(ctx, movieID, tag); err != nil {
+		if errors.Is(err, domain.ErrTagNotFound) {
+			return domain.ErrTagNotFound
+		}
+		return fmt.Errorf("removing movie tag: %w", err)
+	}
+	return nil
+}
+
+// GetTags возвращает теги фильма в алфавитном порядке
+func (s *MovieService) GetTags(ctx context.Context, movieID int) ([]string, error) {
+	ctx, span := tracer().Start(ctx, "MovieService.GetTags")
+	defer span.End()
+
+	if err := s.checkMovieExists(ctx, movieID); err != nil {
+		return nil, err
+	}
+	return s.getTags(ctx, movieID)
+}
+
+func (s *MovieService) getTags(ctx context.Context, movieID int) ([]string, error) {
+	tags, err := s.store.GetMovieTags(ctx, movieID)
+	if err != nil {
+		return nil, fmt.Errorf("getting movie tags: %w", err)
+	}
+	return tags, nil
+}
+
+// ListByTag возвращает страницу фильмов с тегом и их общее число
+func (s *MovieService) ListByTag(ctx context.Context, tag string, limit, offset int) ([]domain.Movie, int, error) {
+	ctx, span := tracer().Start(ctx, "MovieService.ListByTag")
+	defer span.End()
+
+	movies, err := s.store.GetMoviesByTag(ctx, tag, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("getting movies by tag: %w", err)
+	}
+	total, err := s.store.CountMoviesByTag(ctx, tag)
+	if err != nil {
+		return nil, 0, fmt.Errorf("counting movies by tag: %w", err)
+	}
+	return movies, total, nil
+}
+
+// SuggestTags возвращает подсказки тегов, начинающихся с prefix
+func (s *MovieService) SuggestTags(ctx context.Context, prefix string, limit int) ([]domain.Tag, error) {
+	ctx, span := tracer().Start(ctx, "MovieService.SuggestTags")
+	defer span.End()
+
+	tags, err := s.store.SuggestTags(ctx, prefix, limit)
+	if err != nil {
+		return nil, fmt.Errorf("suggesting tags: %w", err)
+	}
+	return tags, nil
+}
@@ -0,0 +1,31 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"cinematique/internal/domain"
+	"cinematique/internal/testutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMovieService_Tags(t *testing.T) {
+	catalog := testutil.NewCatalog()
+	id := catalog.SeedMovie(domain.Movie{Title: "The Matrix", ReleaseYear: 1999})
+	svc := NewMovie(catalog.Movies(), nil)
+	ctx := context.Background()
+
+	tags, err := svc.AddTag(ctx, id, "cult-classic")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"cult-classic"}, tags)
+
+	require.NoError(t, svc.RemoveTag(ctx, id, "cult-classic"))
+	assert.ErrorIs(t, svc.RemoveTag(ctx, id, "cult-classic"), domain.ErrTagNotFound)
+
+	_, err = svc.AddTag(ctx, 404, "cult-classic")
+	assert.ErrorIs(t, err, domain.ErrMovieNotFound)
+	_, err = svc.GetTags(ctx, 404)
+	assert.ErrorIs(t, err, domain.ErrMovieNotFound)
+}
@@ -0,0 +1,188 @@
+package service
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Алгоритмы хэширования паролей. Хэш хранит свой алгоритм и параметры в префиксе
+// ($2a$10$... для bcrypt, $argon2id$v=19$m=...,t=...,p=...$ для argon2id), поэтому
+// у каждого пользователя он свой и старые хэши проверяются после смены алгоритма
+const (
+	PasswordHashBcrypt   = "bcrypt"
+	PasswordHashArgon2id = "argon2id"
+)
+
+// errPasswordMismatch — пароль не совпадает с хэшем
+var errPasswordMismatch = errors.New("password does not match")
+
+var passwordRehashesTotal = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "password_rehashes_total",
+		Help: "Password hashes upgraded on login, by previous and new algorithm.",
+	},
+	[]string{"from", "to"},
+)
+
+func init() {
+	prometheus.MustRegister(passwordRehashesTotal)
+}
+
+// Argon2Params — параметры argon2id. Memory задаётся в КиБ
+type Argon2Params struct {
+	Memory      uint32
+	Iterations  uint32
+	Parallelism uint8
+	SaltLength  uint32
+	KeyLength   uint32
+}
+
+// DefaultArgon2Params — параметры argon2id по умолчанию: 64 МиБ памяти, 3 прохода, 2 потока
+var DefaultArgon2Params = Argon2Params{Memory: 64 * 1024, Iterations: 3, Parallelism: 2, SaltLength: 16, KeyLength: 32}
+
+// PasswordHasher хэширует новые пароли алгоритмом Algorithm и проверяет хэши любого
+// поддерживаемого алгоритма. Хэш, созданный другим алгоритмом или с другими параметрами,
+// считается устаревшим и пересчитывается при входе
+type PasswordHasher struct {
+	Algorithm  string
+	BcryptCost int
+	Argon2     Argon2Params
+}
+
+// DefaultPasswordHasher — bcrypt со стоимостью по умолчанию, как до появления argon2id
+var DefaultPasswordHasher = PasswordHasher{Algorithm: PasswordHashBcrypt, BcryptCost: bcrypt.DefaultCost, Argon2: DefaultArgon2Params}
+
+// ParsePasswordHashAlgorithm проверяет название алгоритма из настроек
+func ParsePasswordHashAlgorithm(value string) (string, bool) {
+	switch value {
+	case PasswordHashBcrypt, PasswordHashArgon2id:
+		return value, true
+	}
+	return "", false
+}
+
+// normalize заменяет недопустимые значения значениями по умолчанию
+func (h PasswordHasher) normalize() PasswordHasher {
+	if _, ok := ParsePasswordHashAlgorithm(h.Algorithm); !ok {
+		h.Algorithm = DefaultPasswordHasher.Algorithm
+	}
+	if h.BcryptCost < bcrypt.MinCost || h.BcryptCost > bcrypt.MaxCost {
+		h.BcryptCost = bcrypt.DefaultCost
+	}
+	if h.Argon2.Memory == 0 {
+		h.Argon2.Memory = DefaultArgon2Params.Memory
+	}
+	if h.Argon2.Iterations == 0 {
+		h.Argon2.Iterations = DefaultArgon2Params.Iterations
+	}
+	if h.Argon2.Parallelism == 0 {
+		h.Argon2.Parallelism = DefaultArgon2Params.Parallelism
+	}
+	if h.Argon2.SaltLength == 0 {
+		h.Argon2.SaltLength = DefaultArgon2Params.SaltLength
+	}
+	if h.Argon2.KeyLength == 0 {
+		h.Argon2.KeyLength = DefaultArgon2Params.KeyLength
+	}
+	return h
+}
+
+// Hash хэширует пароль текущим алгоритмом
+func (h PasswordHasher) Hash(password string) (string, error) {
+	if h.Algorithm == PasswordHashArgon2id {
+		return h.hashArgon2id(password)
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), h.BcryptCost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+// Verify проверяет пароль по хэшу любого поддерживаемого алгоритма. Несовпадение
+// пароля и повреждённый хэш одинаково возвращают ошибку
+func (h PasswordHasher) Verify(hash, password string) error {
+	if passwordHashAlgorithm(hash) != PasswordHashArgon2id {
+		return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
+	}
+	params, salt, key, err := decodeArgon2id(hash)
+	if err != nil {
+		return err
+	}
+	computed := argon2.IDKey([]byte(password), salt, params.Iterations, params.Memory, params.Parallelism, uint32(len(key)))
+	if subtle.ConstantTimeCompare(computed, key) != 1 {
+		return errPasswordMismatch
+	}
+	return nil
+}
+
+// NeedsRehash сообщает, что хэш создан другим алгоритмом или с другими параметрами
+func (h PasswordHasher) NeedsRehash(hash string) bool {
+	if passwordHashAlgorithm(hash) != h.Algorithm {
+		return true
+	}
+	if h.Algorithm == PasswordHashBcrypt {
+		cost, err := bcrypt.Cost([]byte(hash))
+		return err != nil || cost != h.BcryptCost
+	}
+	params, salt, key, err := decodeArgon2id(hash)
+	if err != nil {
+		return true
+	}
+	params.SaltLength = uint32(len(salt))
+	params.KeyLength = uint32(len(key))
+	return params != h.Argon2
+}
+
+func (h PasswordHasher) hashArgon2id(password string) (string, error) {
+	salt := make([]byte, h.Argon2.SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", fmt.Errorf("generating salt: %w", err)
+	}
+	key := argon2.IDKey([]byte(password), salt, h.Argon2.Iterations, h.Argon2.Memory, h.Argon2.Parallelism, h.Argon2.KeyLength)
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s", argon2.Version,
+		h.Argon2.Memory, h.Argon2.Iterations, h.Argon2.Parallelism,
+		base64.RawStdEncoding.EncodeToString(salt), base64.RawStdEncoding.EncodeToString(key)), nil
+}
+
+// passwordHashAlgorithm определяет алгоритм хэша по префиксу
+func passwordHashAlgorithm(hash string) string {
+	if strings.HasPrefix(hash, "$argon2id$") {
+		return PasswordHashArgon2id
+	}
+	return PasswordHashBcrypt
+}
+
+// decodeArgon2id разбирает хэш в формате PHC: $argon2id$v=19$m=65536,t=3,p=2$соль$ключ.
+// SaltLength и KeyLength в возвращаемых параметрах не заполняются
+func decodeArgon2id(hash string) (Argon2Params, []byte, []byte, error) {
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 {
+		return Argon2Params{}, nil, nil, errors.New("malformed argon2id hash")
+	}
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return Argon2Params{}, nil, nil, errors.New("unsupported argon2id version")
+	}
+	var params Argon2Params
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.Memory, &params.Iterations, &params.Parallelism); err != nil {
+		return Argon2Params{}, nil, nil, fmt.Errorf("malformed argon2id parameters: %w", err)
+	}
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return Argon2Params{}, nil, nil, fmt.Errorf("malformed argon2id salt: %w", err)
+	}
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil || len(key) == 0 {
+		return Argon2Params{}, nil, nil, errors.New("malformed argon2id key")
+	}
+	return params, salt, key, nil
+}
@@ -0,0 +1,71 @@
+package service
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// testArgon2Params — лёгкие параметры argon2id, чтобы тесты не тратили 64 МиБ на каждый хэш
+var testArgon2Params = Argon2Params{Memory: 64, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32}
+
+func TestPasswordHasher_Argon2id(t *testing.T) {
+	hasher := PasswordHasher{Algorithm: PasswordHashArgon2id, BcryptCost: bcrypt.MinCost, Argon2: testArgon2Params}
+
+	hash, err := hasher.Hash("password123")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(hash, "$argon2id$v=19$m=64,t=1,p=1$"), hash)
+
+	assert.NoError(t, hasher.Verify(hash, "password123"))
+	assert.Error(t, hasher.Verify(hash, "password124"))
+	assert.False(t, hasher.NeedsRehash(hash))
+
+	// Соль случайная: одинаковые пароли дают разные хэши
+	again, err := hasher.Hash("password123")
+	require.NoError(t, err)
+	assert.NotEqual(t, hash, again)
+
+	// Хэш с другими параметрами проверяется, но считается устаревшим
+	stronger := hasher
+	stronger.Argon2.Iterations = 2
+	assert.NoError(t, stronger.Verify(hash, "password123"))
+	assert.True(t, stronger.NeedsRehash(hash))
+
+	for _, malformed := range []string{"$argon2id$", "$argon2id$v=18$m=64,t=1,p=1$c2FsdA$a2V5", "$argon2id$v=19$m=64$c2FsdA$a2V5", "$argon2id$v=19$m=64,t=1,p=1$c2FsdA$"} {
+		assert.Error(t, hasher.Verify(malformed, "password123"), malformed)
+		assert.True(t, hasher.NeedsRehash(malformed), malformed)
+	}
+}
+
+func TestPasswordHasher_MigratesBcrypt(t *testing.T) {
+	legacy, err := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.MinCost)
+	require.NoError(t, err)
+
+	argon := PasswordHasher{Algorithm: PasswordHashArgon2id, BcryptCost: bcrypt.MinCost, Argon2: testArgon2Params}
+	assert.NoError(t, argon.Verify(string(legacy), "password123"))
+	assert.Error(t, argon.Verify(string(legacy), "wrong"))
+	assert.True(t, argon.NeedsRehash(string(legacy)))
+
+	bcryptHasher := PasswordHasher{Algorithm: PasswordHashBcrypt, BcryptCost: bcrypt.MinCost}
+	assert.False(t, bcryptHasher.NeedsRehash(string(legacy)))
+	bcryptHasher.BcryptCost = bcrypt.MinCost + 1
+	assert.True(t, bcryptHasher.NeedsRehash(string(legacy)))
+
+	argonHash, err := argon.Hash("password123")
+	require.NoError(t, err)
+	assert.NoError(t, bcryptHasher.Verify(argonHash, "password123"))
+	assert.True(t, bcryptHasher.NeedsRehash(argonHash))
+}
+
+func TestPasswordHasher_Normalize(t *testing.T) {
+	hasher := PasswordHasher{Algorithm: "md5", BcryptCost: 100}.normalize()
+	assert.Equal(t, DefaultPasswordHasher, hasher)
+
+	hasher = PasswordHasher{Algorithm: PasswordHashArgon2id, Argon2: Argon2Params{Memory: 32 * 1024}}.normalize()
+	assert.Equal(t, PasswordHashArgon2id, hasher.Algorithm)
+	assert.Equal(t, uint32(32*1024), hasher.Argon2.Memory)
+	assert.Equal(t, DefaultArgon2Params.Iterations, hasher.Argon2.Iterations)
+}
@@ -0,0 +1,59 @@
+package service
+
+import (
+	"fmt"
+	"unicode"
+	"unicode/utf8"
+
+	"cinematique/internal/domain"
+)
+
+// PasswordPolicy задаёт требования к сложности пароля при регистрации
+type PasswordPolicy struct {
+	MinLength     int  // минимальная длина в символах
+	RequireUpper  bool // хотя бы одна заглавная буква
+	RequireLower  bool // хотя бы одна строчная буква
+	RequireDigit  bool // хотя бы одна цифра
+	RequireSymbol bool // хотя бы один символ, не являющийся буквой или цифрой
+}
+
+// DefaultPasswordPolicy требует только минимальную длину
+var DefaultPasswordPolicy = PasswordPolicy{MinLength: 8}
+
+// Check проверяет пароль и возвращает *domain.WeakPasswordError со всеми нарушенными правилами
+func (p PasswordPolicy) Check(password string) error {
+	var hasUpper, hasLower, hasDigit, hasSymbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			hasUpper = true
+		case unicode.IsLower(r):
+			hasLower = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		case !unicode.IsLetter(r) && !unicode.IsSpace(r):
+			hasSymbol = true
+		}
+	}
+
+	var violations []string
+	if utf8.RuneCountInString(password) < p.MinLength {
+		violations = append(violations, fmt.Sprintf("at least %d characters", p.MinLength))
+	}
+	if p.RequireUpper && !hasUpper {
+		violations = append(violations, "an uppercase letter")
+	}
+	if p.RequireLower && !hasLower {
+		violations = append(violations, "a lowercase letter")
+	}
+	if p.RequireDigit && !hasDigit {
+		violations = append(violations, "a digit")
+	}
+	if p.RequireSymbol && !hasSymbol {
+		violations = append(violations, "a special character")
+	}
+	if len(violations) > 0 {
+		return &domain.WeakPasswordError{Violations: violations}
+	}
+	return nil
+}
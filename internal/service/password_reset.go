@@ -0,0 +1,134 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"time"
+
+	"cinematique/internal/auth"
+	"cinematique/internal/domain"
+	"cinematique/internal/mailer"
+)
+
+// DefaultPasswordResetTTL — срок действия ссылки сброса пароля по умолчанию
+const DefaultPasswordResetTTL = time.Hour
+
+// WithPasswordReset включает сброс пароля по email. Ссылка в письме — resetURL
+// с параметром token; ttl <= 0 заменяется на DefaultPasswordResetTTL
+func (s *AuthService) WithPasswordReset(m mailer.Mailer, resetURL string, ttl time.Duration) *AuthService {
+	if ttl <= 0 {
+		ttl = DefaultPasswordResetTTL
+	}
+	s.mailer = m
+	s.resetURL = resetURL
+	s.resetTTL = ttl
+	return s
+}
+
+// ForgotPassword отправляет на email ссылку сброса пароля. Для неизвестного адреса
+// ошибки нет, и ошибка отправки письма только логируется: по ответу нельзя узнать,
+// зарегистрирован ли адрес
+func (s *AuthService) ForgotPassword(ctx context.Context, email string) error {
+	if s.mailer == nil {
+		return domain.ErrPasswordResetOff
+	}
+	user, err := s.repo.GetByEmail(email)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("getting user by email: %w", err)
+	}
+	// У записей пользователей Keycloak нет пароля: сброс создал бы вход в обход Keycloak
+	if user.PasswordHash == "" {
+		return nil
+	}
+
+	token, err := newResetToken()
+	if err != nil {
+		return fmt.Errorf("generating reset token: %w", err)
+	}
+	expiresAt := s.now().Add(s.resetTTL)
+	if err := s.repo.CreatePasswordResetToken(user.ID, hashResetToken(token), expiresAt); err != nil {
+		return fmt.Errorf("saving reset token: %w", err)
+	}
+
+	if err := s.mailer.Send(ctx, s.resetMessage(user, token)); err != nil {
+		log.Printf("Failed to send password reset email to user %d: %v", user.ID, err)
+	}
+	return nil
+}
+
+// ResetPassword задаёт новый пароль по токену из письма. Токен одноразовый; вместе с
+// паролем растёт версия JWT пользователя, и выпущенные ранее токены доступа перестают
+// приниматься. Затем токены пользователя отзываются; сбой отзыва только логируется,
+// потому что пароль к этому моменту уже сменён. Блокировка входа снимается
+func (s *AuthService) ResetPassword(ctx context.Context, token, newPassword string) error {
+	if token == "" {
+		return domain.ErrInvalidResetToken
+	}
+	if err := s.policy.Check(newPassword); err != nil {
+		return err
+	}
+	hash, err := s.hasher.Hash(newPassword)
+	if err != nil {
+		return err
+	}
+
+	userID, err := s.repo.ResetPassword(hashResetToken(token), hash, s.now())
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.ErrInvalidResetToken
+	}
+	if err != nil {
+		return fmt.Errorf("resetting password: %w", err)
+	}
+	auth.ForgetTokenVersion(userID)
+	if err := auth.RevokeUserTokens(ctx, userID); err != nil {
+		log.Printf("Failed to revoke tokens of user %d after password reset: %v", userID, err)
+	}
+	log.Printf("User %d reset their password by email", userID)
+	return nil
+}
+
+func (s *AuthService) resetMessage(user domain.User, token string) mailer.Message {
+	// Токен добавляется к уже имеющимся параметрам адреса, а не затирает их
+	link := s.resetURL + "?token=" + url.QueryEscape(token)
+	if u, err := url.Parse(s.resetURL); err == nil {
+		query := u.Query()
+		query.Set("token", token)
+		u.RawQuery = query.Encode()
+		link = u.String()
+	}
+	return mailer.Message{
+		To:      user.Email,
+		Subject: "Cinematique password reset",
+		Body: fmt.Sprintf("Hello, %s!\n\n"+
+			"To choose a new password, open this link:\n%s\n\n"+
+			"The link works once and expires in %s. If you did not ask to reset your password, ignore this email.\n",
+			user.Username, link, s.resetTTL),
+	}
+}
+
+// newResetToken возвращает случайный токен сброса (256 бит) в виде base64url
+func newResetToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// hashResetToken возвращает SHA-256 токена: в базе хранится только он.
+// Токен случайный и длинный, поэтому медленный хэш вроде bcrypt не нужен
+func hashResetToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
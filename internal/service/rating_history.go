@@ -0,0 +1,61 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"cinematique/internal/domain"
+)
+
+// ratingsBeforeUpdate возвращает текущие рейтинги фильмов, если update меняет рейтинг.
+// Фильмы, которых нет, пропускаются: их изменение всё равно не состоится
+func (s *MovieService) ratingsBeforeUpdate(ctx context.Context, ids []int, update domain.MovieUpdate) (map[int]float64, error) {
+	if update.Rating == nil {
+		return nil, nil
+	}
+	ratings := make(map[int]float64, len(ids))
+	for _, id := range ids {
+		movie, err := s.store.GetByID(ctx, id)
+		if errors.Is(err, domain.ErrMovieNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("getting current rating of movie %d: %w", id, err)
+		}
+		ratings[id] = movie.Rating
+	}
+	return ratings, nil
+}
+
+// recordRatingChange сохраняет изменение рейтинга, если он действительно изменился.
+// Как и ревизия, запись истории не отменяет выполненное обновление, поэтому ошибка только логируется
+func (s *MovieService) recordRatingChange(ctx context.Context, movieID int, oldRating, newRating float64) {
+	if oldRating == newRating {
+		return
+	}
+	change := domain.RatingChange{MovieID: movieID, OldRating: &oldRating, NewRating: newRating}
+	if err := s.store.AddRatingChange(ctx, change); err != nil {
+		log.Printf("Error recording rating change for movie (ID: %d): %v", movieID, err)
+	}
+	s.syncInternalRating(ctx, movieID, newRating)
+}
+
+// GetRatingHistory возвращает историю рейтинга фильма от старых изменений к новым
+func (s *MovieService) GetRatingHistory(ctx context.Context, movieID int) ([]domain.RatingChange, error) {
+	ctx, span := tracer().Start(ctx, "MovieService.GetRatingHistory")
+	defer span.End()
+
+	if _, err := s.store.GetByID(ctx, movieID); err != nil {
+		if errors.Is(err, domain.ErrMovieNotFound) {
+			return nil, domain.ErrMovieNotFound
+		}
+		return nil, fmt.Errorf("checking movie existence: %w", err)
+	}
+	history, err := s.store.GetRatingHistory(ctx, movieID)
+	if err != nil {
+		return nil, fmt.Errorf("getting rating history: %w", err)
+	}
+	return history, nil
+}
@@ -0,0 +1,112 @@
+package service
+
+import (
+	"cinematique/internal/domain"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// StoreSearch определяет интерфейс хранилища поисковых подсказок и поисковой аналитики
+type StoreSearch interface {
+	SimilarMovieTitles(ctx context.Context, query string, limit int) ([]string, error)                  // похожие названия фильмов
+	SimilarActorNames(ctx context.Context, query string, limit int) ([]string, error)                   // похожие имена актёров
+	RelatedQueries(ctx context.Context, query string, limit int) ([]string, error)                      // похожие популярные запросы
+	RecordSearch(ctx context.Context, query string, results int, at time.Time) error                    // учесть поиск в статистике
+	TopSearches(ctx context.Context, since time.Time, limit int) ([]domain.SearchStat, error)           // самые частые запросы
+	TopZeroResultSearches(ctx context.Context, since time.Time, limit int) ([]domain.SearchStat, error) // частые запросы без результатов
+	SearchCatalog(ctx context.Context, query string, limit int) ([]domain.SearchHit, error)             // фильмы и актёры по запросу
+}
+
+// suggestionLimit — сколько подсказок каждого вида возвращается
+const suggestionLimit = 5
+
+// SearchService строит подсказки для поиска без результатов и ведёт поисковую статистику
+type SearchService struct {
+	store StoreSearch
+	now   func() time.Time
+}
+
+// NewSearch создаёт сервис поиска
+func NewSearch(store StoreSearch) *SearchService {
+	return &SearchService{store: store, now: time.Now}
+}
+
+// SuggestForTitle возвращает похожие названия фильмов и связанные популярные запросы
+func (s *SearchService) SuggestForTitle(ctx context.Context, query string) (domain.SearchSuggestions, error) {
+	ctx, span := tracer().Start(ctx, "SearchService.SuggestForTitle")
+	defer span.End()
+
+	return s.suggest(ctx, query, s.store.SimilarMovieTitles)
+}
+
+// SuggestForActorName возвращает похожие имена актёров и связанные популярные запросы
+func (s *SearchService) SuggestForActorName(ctx context.Context, query string) (domain.SearchSuggestions, error) {
+	ctx, span := tracer().Start(ctx, "SearchService.SuggestForActorName")
+	defer span.End()
+
+	return s.suggest(ctx, query, s.store.SimilarActorNames)
+}
+
+func (s *SearchService) suggest(ctx context.Context, query string, similar func(context.Context, string, int) ([]string, error)) (domain.SearchSuggestions, error) {
+	query = normalizeSearchQuery(query)
+	didYouMean, err := similar(ctx, query, suggestionLimit)
+	if err != nil {
+		return domain.SearchSuggestions{}, fmt.Errorf("finding similar values: %w", err)
+	}
+	related, err := s.store.RelatedQueries(ctx, query, suggestionLimit)
+	if err != nil {
+		return domain.SearchSuggestions{}, fmt.Errorf("finding related queries: %w", err)
+	}
+	return domain.SearchSuggestions{DidYouMean: didYouMean, RelatedQueries: related}, nil
+}
+
+// Search ищет фильмы и актёров по запросу общей строки поиска. Лишние пробелы в запросе
+// не учитываются; результаты упорядочены по убыванию похожести
+func (s *SearchService) Search(ctx context.Context, query string, limit int) ([]domain.SearchHit, error) {
+	ctx, span := tracer().Start(ctx, "SearchService.Search")
+	defer span.End()
+
+	hits, err := s.store.SearchCatalog(ctx, strings.Join(strings.Fields(query), " "), limit)
+	if err != nil {
+		return nil, fmt.Errorf("searching catalog: %w", err)
+	}
+	return hits, nil
+}
+
+// RecordSearch учитывает выполненный поиск в статистике
+func (s *SearchService) RecordSearch(ctx context.Context, query string, results int, at time.Time) error {
+	ctx, span := tracer().Start(ctx, "SearchService.RecordSearch")
+	defer span.End()
+
+	query = normalizeSearchQuery(query)
+	if query == "" {
+		return nil
+	}
+	return s.store.RecordSearch(ctx, query, results, at)
+}
+
+// TopSearches возвращает самые частые запросы за последние days дней, включая текущий
+// (по UTC, как и дни в статистике), и самые частые среди не давших результатов
+func (s *SearchService) TopSearches(ctx context.Context, days, limit int) (domain.SearchAnalytics, error) {
+	ctx, span := tracer().Start(ctx, "SearchService.TopSearches")
+	defer span.End()
+
+	since := s.now().UTC().Truncate(24*time.Hour).AddDate(0, 0, 1-days)
+	top, err := s.store.TopSearches(ctx, since, limit)
+	if err != nil {
+		return domain.SearchAnalytics{}, fmt.Errorf("getting top searches: %w", err)
+	}
+	zero, err := s.store.TopZeroResultSearches(ctx, since, limit)
+	if err != nil {
+		return domain.SearchAnalytics{}, fmt.Errorf("getting top zero-result searches: %w", err)
+	}
+	return domain.SearchAnalytics{Since: since, Top: top, TopZeroResults: zero}, nil
+}
+
+// normalizeSearchQuery приводит запрос к виду, в котором он хранится в статистике:
+// нижний регистр, без лишних пробелов
+func normalizeSearchQuery(query string) string {
+	return strings.Join(strings.Fields(strings.ToLower(query)), " ")
+}
@@ -0,0 +1,70 @@
+package service
+
+import (
+	"cinematique/internal/domain"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSearchStore запоминает начало периода и возвращает заданную статистику
+type fakeSearchStore struct {
+	StoreSearch
+	since    time.Time
+	limit    int
+	top      []domain.SearchStat
+	zero     []domain.SearchStat
+	errOnTop error
+	query    string
+	hits     []domain.SearchHit
+}
+
+func (f *fakeSearchStore) SearchCatalog(_ context.Context, query string, limit int) ([]domain.SearchHit, error) {
+	f.query, f.limit = query, limit
+	return f.hits, nil
+}
+
+func (f *fakeSearchStore) TopSearches(_ context.Context, since time.Time, limit int) ([]domain.SearchStat, error) {
+	f.since, f.limit = since, limit
+	return f.top, f.errOnTop
+}
+
+func (f *fakeSearchStore) TopZeroResultSearches(_ context.Context, since time.Time, limit int) ([]domain.SearchStat, error) {
+	return f.zero, nil
+}
+
+func TestSearchService_TopSearches(t *testing.T) {
+	store := &fakeSearchStore{
+		top:  []domain.SearchStat{{Query: "matrix", Searches: 42}},
+		zero: []domain.SearchStat{{Query: "matrx", Searches: 7, ZeroResults: 7}},
+	}
+	svc := NewSearch(store)
+	svc.now = func() time.Time { return time.Date(2026, 3, 7, 23, 30, 0, 0, time.FixedZone("MSK", 3*3600)) }
+
+	analytics, err := svc.TopSearches(context.Background(), 7, 10)
+	require.NoError(t, err)
+	// 7 марта 23:30 MSK — это 7 марта по UTC, поэтому семь дней начинаются 1 марта
+	want := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, want, store.since)
+	assert.Equal(t, 10, store.limit)
+	assert.Equal(t, domain.SearchAnalytics{Since: want, Top: store.top, TopZeroResults: store.zero}, analytics)
+
+	store.errOnTop = errors.New("db down")
+	_, err = svc.TopSearches(context.Background(), 7, 10)
+	assert.ErrorIs(t, err, store.errOnTop)
+}
+
+func TestSearchService_Search(t *testing.T) {
+	store := &fakeSearchStore{hits: []domain.SearchHit{{Kind: domain.SearchHitMovie, ID: 1, Name: "The Matrix", Score: 0.6}}}
+	svc := NewSearch(store)
+
+	hits, err := svc.Search(context.Background(), "  the   Matrix ", 10)
+	require.NoError(t, err)
+	assert.Equal(t, "the Matrix", store.query)
+	assert.Equal(t, 10, store.limit)
+	assert.Equal(t, store.hits, hits)
+}
@@ -0,0 +1,36 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"cinematique/internal/domain"
+	"cinematique/internal/testutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMovieService_GetBySlug(t *testing.T) {
+	catalog := testutil.NewCatalog()
+	id := catalog.SeedMovie(domain.Movie{Title: "The Matrix", ReleaseYear: 1999})
+	catalog.SeedCast(id, catalog.SeedActor(domain.Actor{Name: "Keanu Reeves"}))
+	svc := NewMovie(catalog.Movies(), nil)
+
+	movie, err := svc.GetBySlug(context.Background(), "the-matrix-1999")
+	require.NoError(t, err)
+	assert.Equal(t, id, movie.ID)
+	require.Len(t, movie.Actors, 1)
+	assert.Equal(t, "Keanu Reeves", movie.Actors[0].Name)
+
+	_, err = svc.GetBySlug(context.Background(), "the-matrix-2000")
+	assert.ErrorIs(t, err, domain.ErrMovieNotFound)
+
+	// Slug слитого дубликата открывает фильм, в который его слили
+	dupID := catalog.SeedMovie(domain.Movie{Title: "The Matrix", ReleaseYear: 1999})
+	_, err = svc.MergeMovies(context.Background(), id, dupID)
+	require.NoError(t, err)
+	movie, err = svc.GetBySlug(context.Background(), "the-matrix-1999-2")
+	require.NoError(t, err)
+	assert.Equal(t, id, movie.ID)
+}
@@ -0,0 +1,96 @@
+package service
+
+import (
+	"cinematique/internal/domain"
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var statsCacheRequestsTotal = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "catalog_stats_cache_requests_total",
+		Help: "Total number of catalog statistics cache lookups by result (hit, miss).",
+	},
+	[]string{"result"},
+)
+
+func init() {
+	prometheus.MustRegister(statsCacheRequestsTotal)
+}
+
+// StoreStats определяет интерфейс агрегирующих запросов по каталогу
+type StoreStats interface {
+	CatalogTotals(ctx context.Context) (movies, actors int, averageRating float64, err error) // число фильмов, актёров и средний рейтинг
+	MoviesPerDecade(ctx context.Context) ([]domain.DecadeCount, error)                        // фильмы по десятилетиям
+	TopActorsByFilmCount(ctx context.Context, limit int) ([]domain.ActorFilmCount, error)     // актёры с наибольшим числом фильмов
+}
+
+// topActorsLimit — сколько актёров попадает в рейтинг по числу фильмов
+const topActorsLimit = 10
+
+// StatsService считает агрегированную статистику каталога.
+// Агрегаты дорогие и не обязаны быть точными до секунды, поэтому результат
+// кэшируется на ttl и не сбрасывается при изменениях каталога
+type StatsService struct {
+	store StoreStats
+	ttl   time.Duration // 0 — статистика считается при каждом запросе
+	now   func() time.Time
+
+	mu      sync.Mutex
+	cached  *domain.CatalogStats
+	expires time.Time
+}
+
+// NewStats создаёт сервис статистики с кэшем на ttl
+func NewStats(store StoreStats, ttl time.Duration) *StatsService {
+	return &StatsService{store: store, ttl: ttl, now: time.Now}
+}
+
+// Get возвращает статистику каталога из кэша или пересчитывает её
+func (s *StatsService) Get(ctx context.Context) (domain.CatalogStats, error) {
+	ctx, span := tracer().Start(ctx, "StatsService.Get")
+	defer span.End()
+
+	s.mu.Lock()
+	if s.cached != nil && s.now().Before(s.expires) {
+		stats := *s.cached
+		s.mu.Unlock()
+		statsCacheRequestsTotal.WithLabelValues("hit").Inc()
+		return stats, nil
+	}
+	s.mu.Unlock()
+	statsCacheRequestsTotal.WithLabelValues("miss").Inc()
+
+	stats, err := s.load(ctx)
+	if err != nil {
+		return domain.CatalogStats{}, err
+	}
+	if s.ttl > 0 {
+		s.mu.Lock()
+		s.cached = &stats
+		s.expires = s.now().Add(s.ttl)
+		s.mu.Unlock()
+	}
+	return stats, nil
+}
+
+// load выполняет агрегирующие запросы
+func (s *StatsService) load(ctx context.Context) (domain.CatalogStats, error) {
+	var stats domain.CatalogStats
+	var err error
+	stats.MovieCount, stats.ActorCount, stats.AverageRating, err = s.store.CatalogTotals(ctx)
+	if err != nil {
+		return domain.CatalogStats{}, fmt.Errorf("counting catalog totals: %w", err)
+	}
+	if stats.MoviesPerDecade, err = s.store.MoviesPerDecade(ctx); err != nil {
+		return domain.CatalogStats{}, fmt.Errorf("counting movies per decade: %w", err)
+	}
+	if stats.TopActors, err = s.store.TopActorsByFilmCount(ctx, topActorsLimit); err != nil {
+		return domain.CatalogStats{}, fmt.Errorf("finding top actors: %w", err)
+	}
+	return stats, nil
+}
@@ -0,0 +1,76 @@
+package service
+
+import (
+	"cinematique/internal/domain"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeStatsStore считает обращения к агрегатам и возвращает число фильмов, равное номеру вызова
+type fakeStatsStore struct {
+	calls int
+	err   error
+}
+
+func (f *fakeStatsStore) CatalogTotals(context.Context) (int, int, float64, error) {
+	f.calls++
+	return f.calls, 2, 7.5, f.err
+}
+
+func (f *fakeStatsStore) MoviesPerDecade(context.Context) ([]domain.DecadeCount, error) {
+	return []domain.DecadeCount{{Decade: 1990, Movies: 1}}, nil
+}
+
+func (f *fakeStatsStore) TopActorsByFilmCount(_ context.Context, limit int) ([]domain.ActorFilmCount, error) {
+	return []domain.ActorFilmCount{{ActorID: 1, Name: "Keanu Reeves", Movies: limit}}, nil
+}
+
+func TestStatsService_Get(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	store := &fakeStatsStore{}
+	svc := NewStats(store, time.Minute)
+	svc.now = func() time.Time { return now }
+
+	stats, err := svc.Get(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, domain.CatalogStats{
+		MovieCount:      1,
+		ActorCount:      2,
+		AverageRating:   7.5,
+		MoviesPerDecade: []domain.DecadeCount{{Decade: 1990, Movies: 1}},
+		TopActors:       []domain.ActorFilmCount{{ActorID: 1, Name: "Keanu Reeves", Movies: topActorsLimit}},
+	}, stats)
+
+	// В пределах TTL агрегаты не пересчитываются
+	stats, _ = svc.Get(context.Background())
+	assert.Equal(t, 1, stats.MovieCount)
+	assert.Equal(t, 1, store.calls)
+
+	// Истечение TTL
+	now = now.Add(2 * time.Minute)
+	stats, _ = svc.Get(context.Background())
+	assert.Equal(t, 2, stats.MovieCount)
+
+	// Ошибки не кэшируются
+	now = now.Add(2 * time.Minute)
+	store.err = errors.New("db down")
+	_, err = svc.Get(context.Background())
+	assert.Error(t, err)
+	store.err = nil
+	stats, _ = svc.Get(context.Background())
+	assert.Equal(t, 4, stats.MovieCount)
+}
+
+func TestStatsService_GetWithoutCache(t *testing.T) {
+	store := &fakeStatsStore{}
+	svc := NewStats(store, 0)
+
+	svc.Get(context.Background())
+	svc.Get(context.Background())
+	assert.Equal(t, 2, store.calls)
+}
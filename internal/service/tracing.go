@@ -0,0 +1,9 @@
+package service
+
+import (
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracer возвращает трассировщик текущего глобального провайдера
+func tracer() trace.Tracer { return otel.Tracer("cinematique/internal/service") }
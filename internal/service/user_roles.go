@@ -0,0 +1,49 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+
+	"cinematique/internal/auth"
+	"cinematique/internal/domain"
+)
+
+// ChangeRole назначает пользователю роль. Версия токенов пользователя увеличивается, поэтому
+// токены доступа со старой ролью отклоняются и клиент получает новую роль через refresh
+// без повторного входа
+func (s *AuthService) ChangeRole(ctx context.Context, userID int, role string) (domain.User, error) {
+	_, span := tracer().Start(ctx, "AuthService.ChangeRole")
+	defer span.End()
+
+	if !domain.IsKnownRole(role) {
+		return domain.User{}, domain.ErrInvalidRole
+	}
+	version, err := s.repo.SetRole(userID, role)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.User{}, domain.ErrUserNotFound
+		}
+		return domain.User{}, fmt.Errorf("setting role: %w", err)
+	}
+	auth.ForgetTokenVersion(userID)
+
+	user, err := s.GetProfile(userID)
+	if err != nil {
+		return domain.User{}, err
+	}
+	log.Printf("User %d role changed to %s (token version %d)", userID, role, version)
+	return user, nil
+}
+
+// TokenVersion возвращает текущую версию токенов пользователя (auth.TokenVersionSource).
+// Для удалённого пользователя возвращается 0: его токены отзываются при удалении
+func (s *AuthService) TokenVersion(ctx context.Context, userID int) (int, error) {
+	version, err := s.repo.GetTokenVersion(userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, nil
+	}
+	return version, err
+}
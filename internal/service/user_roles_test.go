@@ -0,0 +1,93 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"cinematique/internal/auth"
+	"cinematique/internal/domain"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// staticTokenVersions отдаёт одну и ту же версию и считает обращения
+type staticTokenVersions struct {
+	version int
+	calls   int
+}
+
+func (s *staticTokenVersions) TokenVersion(context.Context, int) (int, error) {
+	s.calls++
+	return s.version, nil
+}
+
+func TestAuthService_ChangeRole(t *testing.T) {
+	setRole := `UPDATE users SET role = \$1, token_version = token_version \+ 1 WHERE id = \$2 RETURNING token_version`
+
+	t.Run("unknown role", func(t *testing.T) {
+		svc, mock := newTestAuthService(t, time.Now())
+
+		_, err := svc.ChangeRole(context.Background(), 1, "superuser")
+
+		assert.ErrorIs(t, err, domain.ErrInvalidRole)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("user not found", func(t *testing.T) {
+		svc, mock := newTestAuthService(t, time.Now())
+		mock.ExpectQuery(setRole).WithArgs(domain.RoleModerator, 2).WillReturnError(sql.ErrNoRows)
+
+		_, err := svc.ChangeRole(context.Background(), 2, domain.RoleModerator)
+
+		assert.ErrorIs(t, err, domain.ErrUserNotFound)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("bumps token version and forgets cached one", func(t *testing.T) {
+		source := &staticTokenVersions{version: 0}
+		cached := auth.NewCachedTokenVersions(source, time.Minute)
+		auth.SetTokenVersionSource(cached)
+		defer auth.SetTokenVersionSource(nil)
+		_, err := cached.TokenVersion(context.Background(), 1)
+		require.NoError(t, err)
+
+		svc, mock := newTestAuthService(t, time.Now())
+		mock.ExpectQuery(setRole).WithArgs(domain.RoleModerator, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"token_version"}).AddRow(1))
+		mock.ExpectQuery(`SELECT .* FROM users WHERE id = \$1`).WithArgs(1).
+			WillReturnRows(sqlmock.NewRows(userColumns).AddRow(1, "neo", "neo@example.com", "hash", domain.RoleModerator, 0, nil, 1))
+
+		user, err := svc.ChangeRole(context.Background(), 1, domain.RoleModerator)
+
+		require.NoError(t, err)
+		assert.Equal(t, domain.RoleModerator, user.Role)
+		assert.Equal(t, 1, user.TokenVersion)
+		// Следующая проверка токена читает версию из источника, а не из кэша
+		source.version = 1
+		version, err := cached.TokenVersion(context.Background(), 1)
+		require.NoError(t, err)
+		assert.Equal(t, 1, version)
+		assert.Equal(t, 2, source.calls)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestAuthService_TokenVersion(t *testing.T) {
+	svc, mock := newTestAuthService(t, time.Now())
+	query := `SELECT token_version FROM users WHERE id = \$1`
+	mock.ExpectQuery(query).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"token_version"}).AddRow(3))
+	mock.ExpectQuery(query).WithArgs(2).WillReturnError(sql.ErrNoRows)
+
+	version, err := svc.TokenVersion(context.Background(), 1)
+	require.NoError(t, err)
+	assert.Equal(t, 3, version)
+
+	version, err = svc.TokenVersion(context.Background(), 2)
+	require.NoError(t, err)
+	assert.Zero(t, version)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,115 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"cinematique/internal/domain"
+)
+
+// UserDirectory возвращает включённых пользователей внешнего провайдера (Keycloak) с локальными ролями
+type UserDirectory interface {
+	ListUsers(ctx context.Context) ([]domain.ExternalUser, error)
+}
+
+// StoreUserSync сохраняет пользователей провайдера в таблице users
+type StoreUserSync interface {
+	SyncExternalUser(user domain.ExternalUser) (int, domain.UserSyncAction, error)
+}
+
+// UserSync переносит пользователей Keycloak в таблицу users, чтобы у них были локальные записи.
+// Keycloak — источник истины: имя, email и роль локальной записи перезаписываются при каждом
+// проходе. Пользователи, отключённые или удалённые в Keycloak, остаются в таблице, но их токены
+// Keycloak уже не принимает
+type UserSync struct {
+	directory UserDirectory
+	store     StoreUserSync
+	interval  time.Duration
+
+	mu       sync.RWMutex
+	localIDs map[string]int // ID пользователя Keycloak → ID в users по итогам последнего прохода
+}
+
+// NewUserSync создаёт синхронизацию, которая в Run повторяется каждые interval
+func NewUserSync(directory UserDirectory, store StoreUserSync, interval time.Duration) *UserSync {
+	return &UserSync{directory: directory, store: store, interval: interval, localIDs: make(map[string]int)}
+}
+
+// Sync выполняет один проход. Пользователь без email или с именем либо email, занятыми
+// локальной учётной записью, пропускается: связывать его с чужой записью небезопасно.
+// Ошибка остальных пользователей прерывает проход
+func (s *UserSync) Sync(ctx context.Context) (domain.UserSyncResult, error) {
+	ctx, span := tracer().Start(ctx, "UserSync.Sync")
+	defer span.End()
+
+	users, err := s.directory.ListUsers(ctx)
+	if err != nil {
+		return domain.UserSyncResult{}, fmt.Errorf("listing keycloak users: %w", err)
+	}
+
+	var result domain.UserSyncResult
+	localIDs := make(map[string]int, len(users))
+	for _, user := range users {
+		if user.Email == "" {
+			result.Skipped++
+			continue
+		}
+		id, action, err := s.store.SyncExternalUser(user)
+		if errors.Is(err, domain.ErrUserAlreadyExists) {
+			log.Printf("Keycloak user %s (%s) skipped: username or email belongs to a local account", user.ExternalID, user.Username)
+			result.Skipped++
+			continue
+		}
+		if err != nil {
+			return result, fmt.Errorf("syncing keycloak user %s: %w", user.ExternalID, err)
+		}
+		localIDs[user.ExternalID] = id
+		switch action {
+		case domain.UserSyncCreated:
+			result.Created++
+		case domain.UserSyncUpdated:
+			result.Updated++
+		default:
+			result.Unchanged++
+		}
+	}
+
+	s.mu.Lock()
+	s.localIDs = localIDs
+	s.mu.Unlock()
+	return result, nil
+}
+
+// Run синхронизирует пользователей сразу и затем каждые interval, пока не отменён ctx.
+// Неудачный проход только логируется: следующий повторит его целиком
+func (s *UserSync) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	for {
+		result, err := s.Sync(ctx)
+		if err != nil {
+			log.Printf("Keycloak user sync failed: %v", err)
+		} else {
+			log.Printf("Keycloak user sync: %d created, %d updated, %d unchanged, %d skipped",
+				result.Created, result.Updated, result.Unchanged, result.Skipped)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// LocalUserID возвращает ID записи в users для пользователя Keycloak (auth.KeycloakUserSource).
+// Пользователь, созданный в Keycloak после последнего прохода, появится после следующего
+func (s *UserSync) LocalUserID(keycloakID string) (int, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	id, ok := s.localIDs[keycloakID]
+	return id, ok
+}
@@ -0,0 +1,79 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"cinematique/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeUserDirectory struct {
+	users []domain.ExternalUser
+	err   error
+}
+
+func (f fakeUserDirectory) ListUsers(context.Context) ([]domain.ExternalUser, error) {
+	return f.users, f.err
+}
+
+// fakeUserSyncStore хранит записи по ID Keycloak; имя "taken" занято локальной учётной записью
+type fakeUserSyncStore struct {
+	rows map[string]domain.ExternalUser
+	ids  map[string]int
+}
+
+func (f *fakeUserSyncStore) SyncExternalUser(user domain.ExternalUser) (int, domain.UserSyncAction, error) {
+	if user.Username == "taken" {
+		return 0, "", domain.ErrUserAlreadyExists
+	}
+	existing, ok := f.rows[user.ExternalID]
+	f.rows[user.ExternalID] = user
+	switch {
+	case !ok:
+		f.ids[user.ExternalID] = len(f.ids) + 1
+		return f.ids[user.ExternalID], domain.UserSyncCreated, nil
+	case existing != user:
+		return f.ids[user.ExternalID], domain.UserSyncUpdated, nil
+	}
+	return f.ids[user.ExternalID], domain.UserSyncUnchanged, nil
+}
+
+func TestUserSync_Sync(t *testing.T) {
+	store := &fakeUserSyncStore{rows: map[string]domain.ExternalUser{}, ids: map[string]int{}}
+	directory := fakeUserDirectory{users: []domain.ExternalUser{
+		{ExternalID: "kc-1", Username: "alice", Email: "alice@example.com", Role: "admin"},
+		{ExternalID: "kc-2", Username: "bob", Email: "bob@example.com", Role: "user"},
+		{ExternalID: "kc-3", Username: "noemail", Role: "user"},
+		{ExternalID: "kc-4", Username: "taken", Email: "taken@example.com", Role: "user"},
+	}}
+	sync := NewUserSync(directory, store, time.Minute)
+
+	result, err := sync.Sync(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, domain.UserSyncResult{Created: 2, Skipped: 2}, result)
+
+	id, ok := sync.LocalUserID("kc-2")
+	assert.True(t, ok)
+	assert.Equal(t, 2, id)
+	_, ok = sync.LocalUserID("kc-4")
+	assert.False(t, ok)
+
+	// Повторный проход обновляет изменённую роль и не трогает остальных
+	directory.users[1].Role = "moderator"
+	sync.directory = directory
+	result, err = sync.Sync(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, domain.UserSyncResult{Updated: 1, Unchanged: 1, Skipped: 2}, result)
+}
+
+func TestUserSync_SyncDirectoryError(t *testing.T) {
+	sync := NewUserSync(fakeUserDirectory{err: errors.New("keycloak down")}, &fakeUserSyncStore{}, time.Minute)
+
+	_, err := sync.Sync(context.Background())
+	assert.ErrorContains(t, err, "keycloak down")
+}
@@ -0,0 +1,42 @@
+// Package slug строит человекочитаемые идентификаторы для адресов (/movies/the-matrix-1999)
+package slug
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// MaxLength — наибольшая длина slug в байтах; колонка slug вмещает 255 символов,
+// остаток оставлен под числовой суффикс, которым разрешаются совпадения
+const MaxLength = 200
+
+// Make склеивает части через дефис: буквы приводятся к нижнему регистру, цифры
+// сохраняются, любые другие последовательности символов заменяются одним дефисом.
+// Буквы не транслитерируются, поэтому у «Брат 1997» slug «брат-1997»
+func Make(parts ...string) string {
+	var b strings.Builder
+	pendingDash := false
+	for _, part := range parts {
+		for _, r := range part {
+			if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+				pendingDash = true
+				continue
+			}
+			if pendingDash && b.Len() > 0 {
+				if b.Len()+1+utf8.RuneLen(r) > MaxLength {
+					return b.String()
+				}
+				b.WriteByte('-')
+			}
+			pendingDash = false
+			r = unicode.ToLower(r)
+			if b.Len()+utf8.RuneLen(r) > MaxLength {
+				return b.String()
+			}
+			b.WriteRune(r)
+		}
+		pendingDash = true
+	}
+	return b.String()
+}
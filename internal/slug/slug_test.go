@@ -0,0 +1,38 @@
+package slug
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMake(t *testing.T) {
+	tests := []struct {
+		parts []string
+		want  string
+	}{
+		{[]string{"The Matrix", "1999"}, "the-matrix-1999"},
+		{[]string{"  Alien: Resurrection!  "}, "alien-resurrection"},
+		{[]string{"Good Bye, Lenin!", "2003"}, "good-bye-lenin-2003"},
+		{[]string{"Брат", "1997"}, "брат-1997"},
+		{[]string{"Amélie"}, "amélie"},
+		{[]string{"---"}, ""},
+		{[]string{"", "1999"}, "1999"},
+		{nil, ""},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, Make(tt.parts...), "parts %q", tt.parts)
+	}
+}
+
+func TestMake_Length(t *testing.T) {
+	long := Make(strings.Repeat("ab ", 200))
+	assert.LessOrEqual(t, len(long), MaxLength)
+	assert.False(t, strings.HasSuffix(long, "-"))
+
+	// Многобайтная буква не разрезается на границе
+	cyrillic := Make(strings.Repeat("я", MaxLength))
+	assert.Equal(t, MaxLength, len(cyrillic))
+	assert.True(t, strings.HasPrefix(cyrillic, "яя"))
+}
@@ -0,0 +1,30 @@
+package storage
+
+import (
+	"errors"
+	"net/http"
+)
+
+// MaxImageSize — максимальный размер загружаемого изображения (фото актёра, постер)
+const MaxImageSize = 5 << 20
+
+// ErrUnsupportedImage возвращается, если содержимое не является изображением поддерживаемого формата
+var ErrUnsupportedImage = errors.New("unsupported image format")
+
+// imageExtensions — поддерживаемые форматы изображений и расширения файлов для них
+var imageExtensions = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/webp": ".webp",
+}
+
+// DetectImage определяет формат изображения по содержимому (а не по имени файла
+// или заголовку клиента) и возвращает MIME-тип и расширение
+func DetectImage(data []byte) (contentType, ext string, err error) {
+	contentType = http.DetectContentType(data)
+	ext, ok := imageExtensions[contentType]
+	if !ok {
+		return "", "", ErrUnsupportedImage
+	}
+	return contentType, ext, nil
+}
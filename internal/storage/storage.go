@@ -0,0 +1,22 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+// ErrInvalidKey возвращается для ключей, которые выходят за пределы хранилища
+// (абсолютные пути, «..») или пусты
+var ErrInvalidKey = errors.New("invalid object key")
+
+// Storage — хранилище бинарных объектов (фотографии актёров, постеры фильмов).
+// Ключ — относительный путь вида actors/42/photo-1700000000.jpg
+type Storage interface {
+	// Put сохраняет объект под ключом key, перезаписывая существующий
+	Put(ctx context.Context, key string, r io.Reader, contentType string) error
+	// Delete удаляет объект; отсутствие объекта ошибкой не считается
+	Delete(ctx context.Context, key string) error
+	// URL возвращает публичный адрес объекта
+	URL(key string) string
+}
@@ -0,0 +1,66 @@
+package storage
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocal_PutAndDelete(t *testing.T) {
+	root := t.TempDir()
+	store, err := NewLocal(root, "/media/")
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	require.NoError(t, store.Put(ctx, "actors/1/photo.jpg", strings.NewReader("first"), "image/jpeg"))
+	require.NoError(t, store.Put(ctx, "actors/1/photo.jpg", strings.NewReader("second"), "image/jpeg"))
+
+	data, err := os.ReadFile(filepath.Join(root, "actors", "1", "photo.jpg"))
+	require.NoError(t, err)
+	assert.Equal(t, "second", string(data))
+	assert.Equal(t, "/media/actors/1/photo.jpg", store.URL("actors/1/photo.jpg"))
+
+	// Временные файлы не остаются в каталоге
+	entries, err := os.ReadDir(filepath.Join(root, "actors", "1"))
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+
+	require.NoError(t, store.Delete(ctx, "actors/1/photo.jpg"))
+	_, err = os.Stat(filepath.Join(root, "actors", "1", "photo.jpg"))
+	assert.True(t, os.IsNotExist(err))
+
+	// Повторное удаление не считается ошибкой
+	assert.NoError(t, store.Delete(ctx, "actors/1/photo.jpg"))
+}
+
+func TestLocal_RejectsKeysOutsideRoot(t *testing.T) {
+	store, err := NewLocal(t.TempDir(), "/media")
+	require.NoError(t, err)
+
+	for _, key := range []string{"", "/etc/passwd", "../secret", "actors/../../secret", "actors//photo.jpg", `actors\photo.jpg`} {
+		err := store.Put(context.Background(), key, strings.NewReader("x"), "image/png")
+		assert.ErrorIs(t, err, ErrInvalidKey, key)
+		assert.ErrorIs(t, store.Delete(context.Background(), key), ErrInvalidKey, key)
+	}
+}
+
+func TestDetectImage(t *testing.T) {
+	png := []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+	contentType, ext, err := DetectImage(png)
+	require.NoError(t, err)
+	assert.Equal(t, "image/png", contentType)
+	assert.Equal(t, ".png", ext)
+
+	jpeg := []byte{0xFF, 0xD8, 0xFF, 0xE0, 0x00, 0x10, 'J', 'F', 'I', 'F'}
+	_, ext, err = DetectImage(jpeg)
+	require.NoError(t, err)
+	assert.Equal(t, ".jpg", ext)
+
+	_, _, err = DetectImage([]byte("<svg xmlns='http://www.w3.org/2000/svg'></svg>"))
+	assert.ErrorIs(t, err, ErrUnsupportedImage)
+}
@@ -0,0 +1,513 @@
+package testutil
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"cinematique/internal/domain"
+)
+
+// ActorStore — in-memory реализация service.StoreActor поверх того же Catalog, что и MovieStore
+type ActorStore struct {
+	catalog *Catalog
+	faults  *faults
+}
+
+// FailOn заставляет метод method возвращать err при каждом вызове до ClearFailures
+func (s *ActorStore) FailOn(method string, err error) {
+	s.faults.set(method, &faultRule{err: err})
+}
+
+// FailAfter пропускает первые n вызовов метода method, а следующий вызов завершает ошибкой err
+func (s *ActorStore) FailAfter(method string, n int, err error) {
+	s.faults.set(method, &faultRule{after: n, times: 1, err: err})
+}
+
+// ClearFailures отменяет все внедрённые сбои
+func (s *ActorStore) ClearFailures() {
+	s.faults.clear()
+}
+
+// Calls возвращает число вызовов метода method, включая завершившиеся сбоем
+func (s *ActorStore) Calls(method string) int {
+	return s.faults.count(method)
+}
+
+func (s *ActorStore) Create(_ context.Context, actor domain.Actor) (int, error) {
+	if err := s.faults.check("Create"); err != nil {
+		return 0, err
+	}
+	var id int
+	err := s.catalog.tx(func(st *state, now time.Time) error {
+		id = st.insertActor(actor, now)
+		return nil
+	})
+	return id, err
+}
+
+func (s *ActorStore) GetByID(_ context.Context, id int) (domain.Actor, error) {
+	if err := s.faults.check("GetByID"); err != nil {
+		return domain.Actor{}, err
+	}
+	var actor domain.Actor
+	var ok bool
+	s.catalog.read(func(st *state) { actor, ok = st.actors[id] })
+	if !ok {
+		return domain.Actor{}, domain.ErrActorNotFound
+	}
+	return actor, nil
+}
+
+func (s *ActorStore) Update(_ context.Context, actor domain.Actor) error {
+	if err := s.faults.check("Update"); err != nil {
+		return err
+	}
+	return s.catalog.tx(func(st *state, now time.Time) error {
+		current, ok := st.actors[actor.ID]
+		if !ok {
+			return domain.ErrActorNotFound
+		}
+		current.Name = actor.Name
+		current.Gender = actor.Gender
+		current.BirthDate = actor.BirthDate
+		current.DeathDate = actor.DeathDate
+		current.UpdatedAt = now
+		st.actors[actor.ID] = current
+		return nil
+	})
+}
+
+func (s *ActorStore) Delete(_ context.Context, id int) error {
+	if err := s.faults.check("Delete"); err != nil {
+		return err
+	}
+	return s.catalog.tx(func(st *state, _ time.Time) error {
+		if _, ok := st.actors[id]; !ok {
+			return domain.ErrActorNotFound
+		}
+		st.deleteActor(id)
+		return nil
+	})
+}
+
+func (s *ActorStore) GetAll(_ context.Context, filter domain.ActorFilter) ([]domain.Actor, error) {
+	if err := s.faults.check("GetAll"); err != nil {
+		return nil, err
+	}
+	return s.matching("", filter), nil
+}
+
+func (s *ActorStore) GetMovies(_ context.Context, actorID int) ([]domain.Movie, error) {
+	if err := s.faults.check("GetMovies"); err != nil {
+		return nil, err
+	}
+	movies := []domain.Movie{}
+	s.catalog.read(func(st *state) { movies = append(movies, st.moviesOfActor(actorID)...) })
+	return movies, nil
+}
+
+func (s *ActorStore) PartialUpdateActor(_ context.Context, id int, update domain.ActorUpdate) error {
+	if err := s.faults.check("PartialUpdateActor"); err != nil {
+		return err
+	}
+	if update.Name == nil && update.Gender == nil && update.BirthDate == nil {
+		return fmt.Errorf("no fields to update")
+	}
+	return s.catalog.tx(func(st *state, now time.Time) error {
+		actor, ok := st.actors[id]
+		if !ok {
+			return domain.ErrActorNotFound
+		}
+		if update.Name != nil {
+			actor.Name = *update.Name
+		}
+		if update.Gender != nil {
+			actor.Gender = *update.Gender
+		}
+		if update.BirthDate != nil {
+			birthDate, err := time.Parse("2006-01-02", *update.BirthDate)
+			if err != nil {
+				return fmt.Errorf("failed to update actor: %w", err)
+			}
+			actor.BirthDate = birthDate
+		}
+		actor.UpdatedAt = now
+		st.actors[id] = actor
+		return nil
+	})
+}
+
+func (s *ActorStore) GetAllActorsWithMovies(_ context.Context, query domain.ActorsWithMoviesQuery) ([]domain.Actor, error) {
+	if err := s.faults.check("GetAllActorsWithMovies"); err != nil {
+		return nil, err
+	}
+	var actors []domain.Actor
+	s.catalog.read(func(st *state) {
+		for _, actor := range st.sortedActors() {
+			if query.Name != "" && !containsFold(actor.Name, query.Name) {
+				continue
+			}
+			// Фильмы актёра от новых к старым, как в LATERAL-подзапросе репозитория
+			movies := st.moviesOfActor(actor.ID)
+			sort.SliceStable(movies, func(i, j int) bool {
+				if movies[i].ReleaseYear != movies[j].ReleaseYear {
+					return movies[i].ReleaseYear > movies[j].ReleaseYear
+				}
+				return movies[i].ID > movies[j].ID
+			})
+			actor.Movies = append([]domain.Movie{}, page(movies, query.MoviesPerActor, 0)...)
+			actors = append(actors, actor)
+		}
+	})
+	return page(actors, query.Limit, query.Offset), nil
+}
+
+func (s *ActorStore) MergeActors(_ context.Context, keepID, dupID int) (domain.ActorMergeResult, error) {
+	if err := s.faults.check("MergeActors"); err != nil {
+		return domain.ActorMergeResult{}, err
+	}
+	var result domain.ActorMergeResult
+	err := s.catalog.tx(func(st *state, now time.Time) error {
+		keep, ok := st.actors[keepID]
+		if !ok {
+			return domain.ErrActorNotFound
+		}
+		dup, ok := st.actors[dupID]
+		if !ok {
+			return domain.ErrActorNotFound
+		}
+		if keep.Name == "" {
+			keep.Name = dup.Name
+		}
+		if keep.Gender == "" {
+			keep.Gender = dup.Gender
+		}
+		if keep.BirthDate.IsZero() {
+			keep.BirthDate = dup.BirthDate
+		}
+		if keep.DeathDate == nil {
+			keep.DeathDate = dup.DeathDate
+		}
+		var unusedPhotoKey string
+		if keep.PhotoKey == "" {
+			keep.PhotoKey = dup.PhotoKey
+		} else {
+			unusedPhotoKey = dup.PhotoKey
+		}
+		keep.UpdatedAt = now
+		st.actors[keepID] = keep
+
+		reassigned := 0
+		for movieID, members := range st.cast {
+			for _, member := range members {
+				if member.ActorID != dupID {
+					continue
+				}
+				if !st.inCast(movieID, keepID) {
+					member.ActorID = keepID
+					st.cast[movieID] = append(st.cast[movieID], member)
+					reassigned++
+				}
+			}
+		}
+		st.deleteActor(dupID)
+		result = domain.ActorMergeResult{Actor: keep, DuplicateID: dupID, MoviesReassigned: reassigned, UnusedPhotoKey: unusedPhotoKey}
+		return nil
+	})
+	return result, err
+}
+
+func (s *ActorStore) SetPhotoKey(_ context.Context, id int, key string) error {
+	if err := s.faults.check("SetPhotoKey"); err != nil {
+		return err
+	}
+	return s.catalog.tx(func(st *state, _ time.Time) error {
+		actor, ok := st.actors[id]
+		if !ok {
+			return domain.ErrActorNotFound
+		}
+		actor.PhotoKey = key
+		st.actors[id] = actor
+		return nil
+	})
+}
+
+func (s *ActorStore) SearchActorsByName(_ context.Context, nameFragment string, filter domain.ActorFilter, limit, offset int) ([]domain.Actor, error) {
+	if err := s.faults.check("SearchActorsByName"); err != nil {
+		return nil, err
+	}
+	actors := s.matching(nameFragment, filter)
+	sort.SliceStable(actors, func(i, j int) bool { return actors[i].Name < actors[j].Name })
+	return page(actors, limit, offset), nil
+}
+
+func (s *ActorStore) CountActors(_ context.Context, nameFragment string, filter domain.ActorFilter) (int, error) {
+	if err := s.faults.check("CountActors"); err != nil {
+		return 0, err
+	}
+	return len(s.matching(nameFragment, filter)), nil
+}
+
+// matching возвращает актёров, чьё имя содержит nameFragment и которые подходят под filter, по возрастанию ID
+func (s *ActorStore) matching(nameFragment string, filter domain.ActorFilter) []domain.Actor {
+	actors := []domain.Actor{}
+	s.catalog.read(func(st *state) {
+		for _, actor := range st.sortedActors() {
+			if filter.Living && actor.DeathDate != nil {
+				continue
+			}
+			if containsFold(actor.Name, nameFragment) {
+				actors = append(actors, actor)
+			}
+		}
+	})
+	return actors
+}
+
+func (s *ActorStore) SuggestActors(_ context.Context, prefix string, limit int) ([]domain.Actor, error) {
+	if err := s.faults.check("SuggestActors"); err != nil {
+		return nil, err
+	}
+	prefix = strings.ToLower(prefix)
+	var fromStart, byWord []domain.Actor
+	s.catalog.read(func(st *state) {
+		for _, actor := range st.sortedActors() {
+			name := strings.ToLower(actor.Name)
+			suggestion := domain.Actor{ID: actor.ID, Name: actor.Name}
+			switch {
+			case strings.HasPrefix(name, prefix):
+				fromStart = append(fromStart, suggestion)
+			case strings.Contains(name, " "+prefix):
+				byWord = append(byWord, suggestion)
+			}
+		}
+	})
+	byName := func(actors []domain.Actor) {
+		sort.SliceStable(actors, func(i, j int) bool { return actors[i].Name < actors[j].Name })
+	}
+	byName(fromStart)
+	byName(byWord)
+	return page(append(fromStart, byWord...), limit, 0), nil
+}
+
+func (s *ActorStore) ForEachActor(_ context.Context, fn func(domain.Actor) error) error {
+	if err := s.faults.check("ForEachActor"); err != nil {
+		return err
+	}
+	var actors []domain.Actor
+	s.catalog.read(func(st *state) { actors = st.sortedActors() })
+	for _, actor := range actors {
+		if err := fn(actor); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *ActorStore) GetActorsBornInMonth(_ context.Context, month int) ([]domain.Actor, error) {
+	if err := s.faults.check("GetActorsBornInMonth"); err != nil {
+		return nil, err
+	}
+	actors := []domain.Actor{}
+	s.catalog.read(func(st *state) {
+		for _, actor := range st.sortedActors() {
+			if !actor.BirthDate.IsZero() && int(actor.BirthDate.Month()) == month {
+				actors = append(actors, actor)
+			}
+		}
+	})
+	sort.SliceStable(actors, func(i, j int) bool {
+		if actors[i].BirthDate.Day() != actors[j].BirthDate.Day() {
+			return actors[i].BirthDate.Day() < actors[j].BirthDate.Day()
+		}
+		return actors[i].Name < actors[j].Name
+	})
+	return actors, nil
+}
+
+func (s *ActorStore) GetOrphanActors(_ context.Context, limit, offset int) ([]domain.Actor, error) {
+	if err := s.faults.check("GetOrphanActors"); err != nil {
+		return nil, err
+	}
+	var orphans []domain.Actor
+	s.catalog.read(func(st *state) { orphans = st.orphanActors() })
+	return page(orphans, limit, offset), nil
+}
+
+func (s *ActorStore) CountOrphanActors(_ context.Context) (int, error) {
+	if err := s.faults.check("CountOrphanActors"); err != nil {
+		return 0, err
+	}
+	count := 0
+	s.catalog.read(func(st *state) { count = len(st.orphanActors()) })
+	return count, nil
+}
+
+func (s *ActorStore) PurgeOrphanActors(_ context.Context, maxCount int) ([]domain.Actor, error) {
+	if err := s.faults.check("PurgeOrphanActors"); err != nil {
+		return nil, err
+	}
+	var orphans []domain.Actor
+	err := s.catalog.tx(func(st *state, _ time.Time) error {
+		orphans = st.orphanActors()
+		if len(orphans) > maxCount {
+			return &domain.OrphanPurgeError{Count: len(orphans)}
+		}
+		for _, actor := range orphans {
+			delete(st.actors, actor.ID)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return orphans, nil
+}
+
+func (s *ActorStore) ResolveSlug(_ context.Context, actorSlug string) (int, error) {
+	if err := s.faults.check("ResolveSlug"); err != nil {
+		return 0, err
+	}
+	id := 0
+	s.catalog.read(func(st *state) {
+		for _, actor := range st.actors {
+			if actor.Slug == actorSlug {
+				id = actor.ID
+			}
+		}
+	})
+	if id == 0 {
+		return 0, domain.ErrActorNotFound
+	}
+	return id, nil
+}
+
+func (s *ActorStore) GetActorStats(_ context.Context, actorID int) (domain.ActorStats, error) {
+	if err := s.faults.check("GetActorStats"); err != nil {
+		return domain.ActorStats{}, err
+	}
+	var stats domain.ActorStats
+	var ok bool
+	s.catalog.read(func(st *state) {
+		if _, ok = st.actors[actorID]; !ok {
+			return
+		}
+		stats.ActorID = actorID
+		var sum float64
+		for _, movie := range st.moviesOfActor(actorID) {
+			stats.MovieCount++
+			sum += movie.Rating
+			if movie.ReleaseYear == 0 {
+				continue
+			}
+			if stats.FirstYear == 0 || movie.ReleaseYear < stats.FirstYear {
+				stats.FirstYear = movie.ReleaseYear
+			}
+			if movie.ReleaseYear > stats.LatestYear {
+				stats.LatestYear = movie.ReleaseYear
+			}
+		}
+		if stats.MovieCount > 0 {
+			average := sum / float64(stats.MovieCount)
+			stats.AverageRating = &average
+		}
+	})
+	if !ok {
+		return domain.ActorStats{}, domain.ErrActorNotFound
+	}
+	return stats, nil
+}
+
+func (s *ActorStore) FindActorsByNames(_ context.Context, names []string) ([]domain.Actor, error) {
+	if err := s.faults.check("FindActorsByNames"); err != nil {
+		return nil, err
+	}
+	wanted := make(map[string]bool, len(names))
+	for _, name := range names {
+		wanted[strings.ToLower(name)] = true
+	}
+	actors := []domain.Actor{}
+	s.catalog.read(func(st *state) {
+		for _, actor := range st.sortedActors() {
+			if wanted[strings.ToLower(actor.Name)] {
+				actors = append(actors, actor)
+			}
+		}
+	})
+	return actors, nil
+}
+
+func (s *ActorStore) ImportActors(_ context.Context, actors []domain.Actor) ([]int, error) {
+	if err := s.faults.check("ImportActors"); err != nil {
+		return nil, err
+	}
+	var ids []int
+	err := s.catalog.tx(func(st *state, now time.Time) error {
+		ids = make([]int, 0, len(actors))
+		for _, actor := range actors {
+			ids = append(ids, st.insertActor(actor, now))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+func (s *ActorStore) AddActorRevision(_ context.Context, revision domain.ActorRevision) error {
+	if err := s.faults.check("AddActorRevision"); err != nil {
+		return err
+	}
+	return s.catalog.tx(func(st *state, now time.Time) error {
+		revision.ID = st.newID()
+		if revision.ChangedAt.IsZero() {
+			revision.ChangedAt = now
+		}
+		st.actorHistory = append(st.actorHistory, revision)
+		return nil
+	})
+}
+
+func (s *ActorStore) GetActorRevisions(_ context.Context, actorID int) ([]domain.ActorRevision, error) {
+	if err := s.faults.check("GetActorRevisions"); err != nil {
+		return nil, err
+	}
+	revisions := []domain.ActorRevision{}
+	s.catalog.read(func(st *state) {
+		for _, revision := range st.actorHistory {
+			if revision.ActorID == actorID {
+				revisions = append(revisions, revision)
+			}
+		}
+	})
+	return revisions, nil
+}
+
+// deleteActor удаляет актёра и убирает его из составов фильмов
+func (st *state) deleteActor(id int) {
+	delete(st.actors, id)
+	for movieID := range st.cast {
+		st.removeFromCast(movieID, id)
+	}
+}
+
+// orphanActors возвращает актёров, не занятых ни в одном фильме, по возрастанию ID
+func (st *state) orphanActors() []domain.Actor {
+	busy := map[int]bool{}
+	for _, members := range st.cast {
+		for _, member := range members {
+			busy[member.ActorID] = true
+		}
+	}
+	orphans := []domain.Actor{}
+	for _, actor := range st.sortedActors() {
+		if !busy[actor.ID] {
+			orphans = append(orphans, actor)
+		}
+	}
+	return orphans
+}
@@ -0,0 +1,62 @@
+package testutil
+
+import "sync"
+
+// faultRule — сбой метода: первые after вызовов проходят, следующие возвращают err.
+// times ограничивает число сбоев; 0 — метод падает до ClearFailures
+type faultRule struct {
+	after int
+	times int
+	err   error
+}
+
+// faults считает вызовы методов хранилища и решает, какой из них должен упасть
+type faults struct {
+	mu    sync.Mutex
+	rules map[string]*faultRule
+	calls map[string]int
+}
+
+func newFaults() *faults {
+	return &faults{rules: map[string]*faultRule{}, calls: map[string]int{}}
+}
+
+// check учитывает вызов метода и возвращает внедрённую ошибку, если вызов должен упасть
+func (f *faults) check(method string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls[method]++
+	rule, ok := f.rules[method]
+	if !ok {
+		return nil
+	}
+	if rule.after > 0 {
+		rule.after--
+		return nil
+	}
+	if rule.times > 0 {
+		rule.times--
+		if rule.times == 0 {
+			delete(f.rules, method)
+		}
+	}
+	return rule.err
+}
+
+func (f *faults) set(method string, rule *faultRule) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.rules[method] = rule
+}
+
+func (f *faults) count(method string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.calls[method]
+}
+
+func (f *faults) clear() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.rules = map[string]*faultRule{}
+}
@@ -0,0 +1,1205 @@
+package testutil
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"cinematique/internal/domain"
+)
+
+// MovieStore — in-memory реализация service.StoreMovie. Поиск и фильтры повторяют
+// смысл SQL-запросов репозитория, но не их производительность и не триграммное сходство
+type MovieStore struct {
+	catalog *Catalog
+	faults  *faults
+}
+
+// FailOn заставляет метод method возвращать err при каждом вызове до ClearFailures
+func (s *MovieStore) FailOn(method string, err error) {
+	s.faults.set(method, &faultRule{err: err})
+}
+
+// FailAfter пропускает первые n вызовов метода method, а следующий вызов завершает ошибкой err
+func (s *MovieStore) FailAfter(method string, n int, err error) {
+	s.faults.set(method, &faultRule{after: n, times: 1, err: err})
+}
+
+// ClearFailures отменяет все внедрённые сбои
+func (s *MovieStore) ClearFailures() {
+	s.faults.clear()
+}
+
+// Calls возвращает число вызовов метода method, включая завершившиеся сбоем
+func (s *MovieStore) Calls(method string) int {
+	return s.faults.count(method)
+}
+
+func (s *MovieStore) Create(_ context.Context, movie domain.Movie) (int, error) {
+	if err := s.faults.check("Create"); err != nil {
+		return 0, err
+	}
+	var id int
+	err := s.catalog.tx(func(st *state, now time.Time) error {
+		if movie.ExternalID != "" {
+			if _, ok := st.movieByExternalID(movie.ExternalID); ok {
+				return fmt.Errorf("%w: idx_films_external_id", domain.ErrAlreadyExists)
+			}
+		}
+		id = st.insertMovie(movie, now)
+		return nil
+	})
+	return id, err
+}
+
+func (s *MovieStore) GetByID(_ context.Context, id int) (domain.Movie, error) {
+	if err := s.faults.check("GetByID"); err != nil {
+		return domain.Movie{}, err
+	}
+	var movie domain.Movie
+	var ok bool
+	s.catalog.read(func(st *state) { movie, ok = st.movies[id] })
+	if !ok {
+		return domain.Movie{}, domain.ErrMovieNotFound
+	}
+	return movie, nil
+}
+
+func (s *MovieStore) Update(_ context.Context, movie domain.Movie) error {
+	if err := s.faults.check("Update"); err != nil {
+		return err
+	}
+	return s.catalog.tx(func(st *state, now time.Time) error {
+		current, ok := st.movies[movie.ID]
+		if !ok {
+			return domain.ErrMovieNotFound
+		}
+		current.Title = movie.Title
+		current.Description = movie.Description
+		current.ReleaseYear = movie.ReleaseYear
+		current.Rating = movie.Rating
+		current.ReleaseDate = movie.ReleaseDate
+		current.OriginalLanguage = movie.OriginalLanguage
+		current.Country = movie.Country
+		current.RuntimeMinutes = movie.RuntimeMinutes
+		current.UpdatedAt = now
+		st.movies[movie.ID] = current
+		return nil
+	})
+}
+
+func (s *MovieStore) Delete(_ context.Context, id int, policy domain.MovieDeletePolicy) error {
+	if err := s.faults.check("Delete"); err != nil {
+		return err
+	}
+	return s.catalog.tx(func(st *state, _ time.Time) error {
+		return st.deleteMovieByPolicy(id, policy)
+	})
+}
+
+func (s *MovieStore) GetAll(_ context.Context) ([]domain.Movie, error) {
+	if err := s.faults.check("GetAll"); err != nil {
+		return nil, err
+	}
+	var movies []domain.Movie
+	s.catalog.read(func(st *state) { movies = st.sortedMovies() })
+	return movies, nil
+}
+
+func (s *MovieStore) GetMoviesAfterID(_ context.Context, afterID, limit int) ([]domain.Movie, error) {
+	if err := s.faults.check("GetMoviesAfterID"); err != nil {
+		return nil, err
+	}
+	var movies []domain.Movie
+	s.catalog.read(func(st *state) {
+		for _, movie := range st.sortedMovies() {
+			if movie.ID > afterID {
+				movies = append(movies, movie)
+			}
+		}
+	})
+	return page(movies, limit, 0), nil
+}
+
+func (s *MovieStore) ForEachMovie(_ context.Context, fn func(domain.Movie) error) error {
+	if err := s.faults.check("ForEachMovie"); err != nil {
+		return err
+	}
+	var movies []domain.Movie
+	s.catalog.read(func(st *state) { movies = st.sortedMovies() })
+	for _, movie := range movies {
+		if err := fn(movie); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *MovieStore) AddActor(_ context.Context, movieID int, member domain.CastMember) error {
+	if err := s.faults.check("AddActor"); err != nil {
+		return err
+	}
+	return s.catalog.tx(func(st *state, _ time.Time) error {
+		if err := st.checkCastRefs(movieID, member.ActorID); err != nil {
+			return err
+		}
+		// Как INSERT ... ON CONFLICT DO NOTHING: повторное добавление ничего не меняет
+		if !st.inCast(movieID, member.ActorID) {
+			st.cast[movieID] = append(st.cast[movieID], member)
+		}
+		return nil
+	})
+}
+
+func (s *MovieStore) RemoveActor(_ context.Context, movieID, actorID int) error {
+	if err := s.faults.check("RemoveActor"); err != nil {
+		return err
+	}
+	return s.catalog.tx(func(st *state, _ time.Time) error {
+		if !st.inCast(movieID, actorID) {
+			return domain.ErrActorNotInMovie
+		}
+		st.removeFromCast(movieID, actorID)
+		return nil
+	})
+}
+
+func (s *MovieStore) GetActorsForMovieByID(_ context.Context, movieID int) ([]domain.Actor, error) {
+	if err := s.faults.check("GetActorsForMovieByID"); err != nil {
+		return nil, err
+	}
+	var actors []domain.Actor
+	s.catalog.read(func(st *state) { actors = st.castActors(movieID) })
+	return actors, nil
+}
+
+func (s *MovieStore) RemoveAllActors(_ context.Context, movieID int) error {
+	if err := s.faults.check("RemoveAllActors"); err != nil {
+		return err
+	}
+	return s.catalog.tx(func(st *state, _ time.Time) error {
+		delete(st.cast, movieID)
+		return nil
+	})
+}
+
+func (s *MovieStore) SearchMoviesByTitle(_ context.Context, titleFragment string, filter domain.MovieFilter) ([]domain.Movie, error) {
+	if err := s.faults.check("SearchMoviesByTitle"); err != nil {
+		return nil, err
+	}
+	return s.searchByTitle(titleFragment, filter), nil
+}
+
+// SearchMoviesByTitleTrigram ищет так же, как SearchMoviesByTitle: сходство с опечатками не моделируется
+func (s *MovieStore) SearchMoviesByTitleTrigram(_ context.Context, titleFragment string, filter domain.MovieFilter) ([]domain.Movie, error) {
+	if err := s.faults.check("SearchMoviesByTitleTrigram"); err != nil {
+		return nil, err
+	}
+	return s.searchByTitle(titleFragment, filter), nil
+}
+
+func (s *MovieStore) searchByTitle(titleFragment string, filter domain.MovieFilter) []domain.Movie {
+	movies := []domain.Movie{}
+	s.catalog.read(func(st *state) {
+		for _, movie := range st.sortedMovies() {
+			if containsFold(movie.Title, titleFragment) && st.matchFilter(movie, filter) {
+				movies = append(movies, movie)
+			}
+		}
+	})
+	return movies
+}
+
+func (s *MovieStore) SearchMoviesByActorName(_ context.Context, actorNameFragment string, filter domain.MovieFilter) ([]domain.Movie, error) {
+	if err := s.faults.check("SearchMoviesByActorName"); err != nil {
+		return nil, err
+	}
+	movies := []domain.Movie{}
+	s.catalog.read(func(st *state) {
+		for _, movie := range st.sortedMovies() {
+			if !st.matchFilter(movie, filter) {
+				continue
+			}
+			for _, actor := range st.castActors(movie.ID) {
+				if containsFold(actor.Name, actorNameFragment) {
+					movies = append(movies, movie)
+					break
+				}
+			}
+		}
+	})
+	return movies, nil
+}
+
+func (s *MovieStore) GetMatchingActors(_ context.Context, movieIDs []int, nameFragment string) (map[int][]domain.Actor, error) {
+	if err := s.faults.check("GetMatchingActors"); err != nil {
+		return nil, err
+	}
+	matches := map[int][]domain.Actor{}
+	s.catalog.read(func(st *state) {
+		for _, movieID := range movieIDs {
+			for _, actor := range st.castActors(movieID) {
+				if containsFold(actor.Name, nameFragment) {
+					matches[movieID] = append(matches[movieID], actor)
+				}
+			}
+		}
+	})
+	return matches, nil
+}
+
+func (s *MovieStore) GetAllMoviesSorted(_ context.Context, query domain.MovieListQuery) ([]domain.Movie, error) {
+	if err := s.faults.check("GetAllMoviesSorted"); err != nil {
+		return nil, err
+	}
+	var movies []domain.Movie
+	s.catalog.read(func(st *state) { movies = st.sortedMovies() })
+	sort.SliceStable(movies, func(i, j int) bool {
+		for _, field := range query.Sort {
+			if cmp := compareMovies(movies[i], movies[j], field.Field); cmp != 0 {
+				if strings.EqualFold(field.Order, "desc") {
+					return cmp > 0
+				}
+				return cmp < 0
+			}
+		}
+		return movies[i].ID < movies[j].ID
+	})
+	return page(movies, query.Limit, query.Offset), nil
+}
+
+func (s *MovieStore) CountMovies(_ context.Context, filter domain.MovieFilter) (int, error) {
+	if err := s.faults.check("CountMovies"); err != nil {
+		return 0, err
+	}
+	count := 0
+	s.catalog.read(func(st *state) {
+		for _, movie := range st.movies {
+			if st.matchFilter(movie, filter) {
+				count++
+			}
+		}
+	})
+	return count, nil
+}
+
+func (s *MovieStore) CreateMovieWithActors(_ context.Context, movie domain.Movie, actorIDs []int) (int, error) {
+	if err := s.faults.check("CreateMovieWithActors"); err != nil {
+		return 0, err
+	}
+	var id int
+	err := s.catalog.tx(func(st *state, now time.Time) error {
+		id = st.insertMovie(movie, now)
+		for _, actorID := range actorIDs {
+			if _, ok := st.actors[actorID]; !ok {
+				return domain.ErrActorNotFound
+			}
+			if !st.inCast(id, actorID) {
+				st.cast[id] = append(st.cast[id], domain.CastMember{ActorID: actorID})
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+func (s *MovieStore) UpdateMovieActors(_ context.Context, movieID int, cast []domain.CastMember) error {
+	if err := s.faults.check("UpdateMovieActors"); err != nil {
+		return err
+	}
+	return s.catalog.tx(func(st *state, _ time.Time) error {
+		delete(st.cast, movieID)
+		for _, member := range cast {
+			if err := st.checkCastRefs(movieID, member.ActorID); err != nil {
+				return err
+			}
+			if !st.inCast(movieID, member.ActorID) {
+				st.cast[movieID] = append(st.cast[movieID], member)
+			}
+		}
+		return nil
+	})
+}
+
+func (s *MovieStore) ChangeMovieActors(_ context.Context, movieID int, add []domain.CastMember, remove []int) error {
+	if err := s.faults.check("ChangeMovieActors"); err != nil {
+		return err
+	}
+	return s.catalog.tx(func(st *state, _ time.Time) error {
+		for _, actorID := range remove {
+			st.removeFromCast(movieID, actorID)
+		}
+		for _, member := range add {
+			if err := st.checkCastRefs(movieID, member.ActorID); err != nil {
+				return err
+			}
+			if !st.inCast(movieID, member.ActorID) {
+				st.cast[movieID] = append(st.cast[movieID], member)
+			}
+		}
+		return nil
+	})
+}
+
+func (s *MovieStore) GetMoviesForActor(_ context.Context, actorID int) ([]domain.Movie, error) {
+	if err := s.faults.check("GetMoviesForActor"); err != nil {
+		return nil, err
+	}
+	var movies []domain.Movie
+	s.catalog.read(func(st *state) { movies = st.moviesOfActor(actorID) })
+	return movies, nil
+}
+
+func (s *MovieStore) PartialUpdateMovie(_ context.Context, id int, update domain.MovieUpdate) error {
+	if err := s.faults.check("PartialUpdateMovie"); err != nil {
+		return err
+	}
+	if update.IsEmpty() {
+		return domain.ErrNoFieldsToUpdate
+	}
+	return s.catalog.tx(func(st *state, now time.Time) error {
+		return st.applyUpdate(id, update, now)
+	})
+}
+
+func (s *MovieStore) MergeMovies(_ context.Context, keepID, dupID int, policy domain.MovieDeletePolicy) (domain.MovieMergeResult, error) {
+	if err := s.faults.check("MergeMovies"); err != nil {
+		return domain.MovieMergeResult{}, err
+	}
+	var result domain.MovieMergeResult
+	err := s.catalog.tx(func(st *state, now time.Time) error {
+		keep, ok := st.movies[keepID]
+		if !ok {
+			return domain.ErrMovieNotFound
+		}
+		dup, ok := st.movies[dupID]
+		if !ok {
+			return domain.ErrMovieNotFound
+		}
+		reassigned := 0
+		for _, member := range st.cast[dupID] {
+			if !st.inCast(keepID, member.ActorID) {
+				st.cast[keepID] = append(st.cast[keepID], member)
+				reassigned++
+			}
+		}
+		for _, tag := range st.tags[dupID] {
+			st.addTag(keepID, tag)
+		}
+		for source, rating := range st.ratings[dupID] {
+			if _, ok := st.ratings[keepID][source]; ok || source == domain.RatingSourceInternal {
+				continue
+			}
+			if st.ratings[keepID] == nil {
+				st.ratings[keepID] = map[string]domain.MovieRating{}
+			}
+			rating.MovieID = keepID
+			st.ratings[keepID][source] = rating
+		}
+		delete(st.ratings, dupID)
+		for windowID, window := range st.availability {
+			if window.MovieID == dupID {
+				window.MovieID = keepID
+				st.availability[windowID] = window
+			}
+		}
+		if keep.ExternalID == "" {
+			keep.ExternalID = dup.ExternalID
+		}
+		if keep.Description == "" {
+			keep.Description = dup.Description
+		}
+		if keep.ReleaseDate == nil {
+			keep.ReleaseDate = dup.ReleaseDate
+		}
+		if keep.OriginalLanguage == "" {
+			keep.OriginalLanguage = dup.OriginalLanguage
+		}
+		if keep.Country == "" {
+			keep.Country = dup.Country
+		}
+		if keep.RuntimeMinutes == 0 {
+			keep.RuntimeMinutes = dup.RuntimeMinutes
+		}
+		keep.ViewCount += dup.ViewCount
+		keep.UpdatedAt = now
+		st.movies[keepID] = keep
+		delete(st.tags, dupID) // теги перенесены выше
+		if err := st.deleteMovieByPolicy(dupID, policy); err != nil {
+			return err
+		}
+		for oldID, newID := range st.merged {
+			if newID == dupID {
+				st.merged[oldID] = keepID
+			}
+		}
+		for oldSlug, newID := range st.mergedSlugs {
+			if newID == dupID {
+				st.mergedSlugs[oldSlug] = keepID
+			}
+		}
+		st.merged[dupID] = keepID
+		st.mergedSlugs[dup.Slug] = keepID
+		result = domain.MovieMergeResult{Movie: keep, DuplicateID: dupID, ActorsReassigned: reassigned}
+		return nil
+	})
+	return result, err
+}
+
+func (s *MovieStore) BulkDeleteMovies(_ context.Context, ids []int, policy domain.MovieDeletePolicy) ([]domain.BulkItemResult, error) {
+	if err := s.faults.check("BulkDeleteMovies"); err != nil {
+		return nil, err
+	}
+	var results []domain.BulkItemResult
+	err := s.catalog.tx(func(st *state, _ time.Time) error {
+		results = make([]domain.BulkItemResult, 0, len(ids))
+		for _, id := range ids {
+			results = append(results, domain.BulkItemResult{ID: id, Err: st.deleteMovieByPolicy(id, policy)})
+		}
+		return nil
+	})
+	return results, err
+}
+
+func (s *MovieStore) BulkUpdateMovies(_ context.Context, ids []int, update domain.MovieUpdate) ([]domain.BulkItemResult, error) {
+	if err := s.faults.check("BulkUpdateMovies"); err != nil {
+		return nil, err
+	}
+	if update.IsEmpty() {
+		return nil, domain.ErrNoFieldsToUpdate
+	}
+	var results []domain.BulkItemResult
+	err := s.catalog.tx(func(st *state, now time.Time) error {
+		results = make([]domain.BulkItemResult, 0, len(ids))
+		for _, id := range ids {
+			results = append(results, domain.BulkItemResult{ID: id, Err: st.applyUpdate(id, update, now)})
+		}
+		return nil
+	})
+	return results, err
+}
+
+func (s *MovieStore) GetMergedMovieID(_ context.Context, oldID int) (int, error) {
+	if err := s.faults.check("GetMergedMovieID"); err != nil {
+		return 0, err
+	}
+	var newID int
+	var ok bool
+	s.catalog.read(func(st *state) { newID, ok = st.merged[oldID] })
+	if !ok {
+		return 0, domain.ErrMovieNotFound
+	}
+	return newID, nil
+}
+
+func (s *MovieStore) GetUpcomingMovies(_ context.Context, after time.Time) ([]domain.Movie, error) {
+	if err := s.faults.check("GetUpcomingMovies"); err != nil {
+		return nil, err
+	}
+	movies := []domain.Movie{}
+	s.catalog.read(func(st *state) {
+		for _, movie := range st.sortedMovies() {
+			if movie.ReleaseDate != nil && movie.ReleaseDate.After(after) {
+				movies = append(movies, movie)
+			}
+		}
+	})
+	sort.SliceStable(movies, func(i, j int) bool { return movies[i].ReleaseDate.Before(*movies[j].ReleaseDate) })
+	return movies, nil
+}
+
+func (s *MovieStore) AddMovieRevision(_ context.Context, revision domain.MovieRevision) error {
+	if err := s.faults.check("AddMovieRevision"); err != nil {
+		return err
+	}
+	return s.catalog.tx(func(st *state, now time.Time) error {
+		revision.ID = st.newID()
+		if revision.ChangedAt.IsZero() {
+			revision.ChangedAt = now
+		}
+		st.revisions = append(st.revisions, revision)
+		return nil
+	})
+}
+
+func (s *MovieStore) GetMovieRevisions(_ context.Context, movieID int, until time.Time) ([]domain.MovieRevision, error) {
+	if err := s.faults.check("GetMovieRevisions"); err != nil {
+		return nil, err
+	}
+	var revisions []domain.MovieRevision
+	s.catalog.read(func(st *state) {
+		for _, revision := range st.revisions {
+			if revision.MovieID == movieID && !revision.ChangedAt.After(until) {
+				revisions = append(revisions, revision)
+			}
+		}
+	})
+	return revisions, nil
+}
+
+func (s *MovieStore) AddRatingChange(_ context.Context, change domain.RatingChange) error {
+	if err := s.faults.check("AddRatingChange"); err != nil {
+		return err
+	}
+	return s.catalog.tx(func(st *state, now time.Time) error {
+		change.ID = st.newID()
+		if change.ChangedAt.IsZero() {
+			change.ChangedAt = now
+		}
+		st.ratingHistory = append(st.ratingHistory, change)
+		return nil
+	})
+}
+
+func (s *MovieStore) GetRatingHistory(_ context.Context, movieID int) ([]domain.RatingChange, error) {
+	if err := s.faults.check("GetRatingHistory"); err != nil {
+		return nil, err
+	}
+	history := []domain.RatingChange{}
+	s.catalog.read(func(st *state) {
+		for _, change := range st.ratingHistory {
+			if change.MovieID == movieID {
+				history = append(history, change)
+			}
+		}
+	})
+	return history, nil
+}
+
+func (s *MovieStore) AddAvailability(_ context.Context, window domain.Availability) (int, error) {
+	if err := s.faults.check("AddAvailability"); err != nil {
+		return 0, err
+	}
+	var id int
+	err := s.catalog.tx(func(st *state, _ time.Time) error {
+		if _, ok := st.movies[window.MovieID]; !ok {
+			return fmt.Errorf("%w: availability_film_id_fkey", domain.ErrReferenceNotFound)
+		}
+		window.ID = st.newID()
+		st.availability[window.ID] = window
+		id = window.ID
+		return nil
+	})
+	return id, err
+}
+
+func (s *MovieStore) GetAvailability(_ context.Context, movieID int) ([]domain.Availability, error) {
+	if err := s.faults.check("GetAvailability"); err != nil {
+		return nil, err
+	}
+	windows := []domain.Availability{}
+	s.catalog.read(func(st *state) { windows = st.windowsOf(movieID) })
+	return windows, nil
+}
+
+func (s *MovieStore) UpdateAvailability(_ context.Context, window domain.Availability) error {
+	if err := s.faults.check("UpdateAvailability"); err != nil {
+		return err
+	}
+	return s.catalog.tx(func(st *state, _ time.Time) error {
+		if current, ok := st.availability[window.ID]; !ok || current.MovieID != window.MovieID {
+			return domain.ErrAvailabilityNotFound
+		}
+		st.availability[window.ID] = window
+		return nil
+	})
+}
+
+func (s *MovieStore) DeleteAvailability(_ context.Context, movieID, windowID int) error {
+	if err := s.faults.check("DeleteAvailability"); err != nil {
+		return err
+	}
+	return s.catalog.tx(func(st *state, _ time.Time) error {
+		if current, ok := st.availability[windowID]; !ok || current.MovieID != movieID {
+			return domain.ErrAvailabilityNotFound
+		}
+		delete(st.availability, windowID)
+		return nil
+	})
+}
+
+func (s *MovieStore) IncrementViewCount(_ context.Context, movieID int, delta int64) error {
+	if err := s.faults.check("IncrementViewCount"); err != nil {
+		return err
+	}
+	return s.catalog.tx(func(st *state, _ time.Time) error {
+		movie, ok := st.movies[movieID]
+		if !ok {
+			return domain.ErrMovieNotFound
+		}
+		movie.ViewCount += delta
+		st.movies[movieID] = movie
+		return nil
+	})
+}
+
+func (s *MovieStore) GetPopularMovies(_ context.Context, limit int) ([]domain.Movie, error) {
+	if err := s.faults.check("GetPopularMovies"); err != nil {
+		return nil, err
+	}
+	var movies []domain.Movie
+	s.catalog.read(func(st *state) { movies = st.sortedMovies() })
+	sort.SliceStable(movies, func(i, j int) bool { return movies[i].ViewCount > movies[j].ViewCount })
+	return page(movies, limit, 0), nil
+}
+
+func (s *MovieStore) FindByNormalizedTitle(_ context.Context, title string, releaseYear int) (domain.Movie, error) {
+	if err := s.faults.check("FindByNormalizedTitle"); err != nil {
+		return domain.Movie{}, err
+	}
+	var found domain.Movie
+	var ok bool
+	s.catalog.read(func(st *state) {
+		for _, movie := range st.sortedMovies() {
+			if movie.ReleaseYear == releaseYear && normalizeTitle(movie.Title) == normalizeTitle(title) {
+				found, ok = movie, true
+				return
+			}
+		}
+	})
+	if !ok {
+		return domain.Movie{}, domain.ErrMovieNotFound
+	}
+	return found, nil
+}
+
+func (s *MovieStore) ImportMovie(_ context.Context, movie domain.Movie, cast []domain.Actor) (domain.MovieImportResult, error) {
+	if err := s.faults.check("ImportMovie"); err != nil {
+		return domain.MovieImportResult{}, err
+	}
+	var result domain.MovieImportResult
+	err := s.catalog.tx(func(st *state, now time.Time) error {
+		result = domain.MovieImportResult{MovieID: st.insertMovie(movie, now)}
+		for _, actor := range cast {
+			actorID := actor.ID
+			if actorID == 0 {
+				actorID = st.findActor(actor)
+			}
+			if actorID == 0 {
+				actorID = st.insertActor(actor, now)
+				result.ActorsCreated++
+				result.CreatedActorIDs = append(result.CreatedActorIDs, actorID)
+			} else if _, ok := st.actors[actorID]; !ok {
+				return domain.ErrActorNotFound
+			}
+			member := domain.CastMember{ActorID: actorID, CharacterName: actor.CharacterName, BillingOrder: actor.BillingOrder}
+			st.cast[result.MovieID] = append(st.cast[result.MovieID], member)
+			result.ActorIDs = append(result.ActorIDs, actorID)
+		}
+		return nil
+	})
+	return result, err
+}
+
+func (s *MovieStore) ResolveSlug(_ context.Context, movieSlug string) (int, error) {
+	if err := s.faults.check("ResolveSlug"); err != nil {
+		return 0, err
+	}
+	id := 0
+	s.catalog.read(func(st *state) {
+		for _, movie := range st.movies {
+			if movie.Slug == movieSlug {
+				id = movie.ID
+			}
+		}
+		if id == 0 {
+			id = st.mergedSlugs[movieSlug]
+		}
+	})
+	if id == 0 {
+		return 0, domain.ErrMovieNotFound
+	}
+	return id, nil
+}
+
+func (s *MovieStore) SetMovieRating(_ context.Context, rating domain.MovieRating) error {
+	if err := s.faults.check("SetMovieRating"); err != nil {
+		return err
+	}
+	return s.catalog.tx(func(st *state, now time.Time) error {
+		if _, ok := st.movies[rating.MovieID]; !ok {
+			return fmt.Errorf("%w: movie_ratings_film_id_fkey", domain.ErrReferenceNotFound)
+		}
+		if st.ratings[rating.MovieID] == nil {
+			st.ratings[rating.MovieID] = map[string]domain.MovieRating{}
+		}
+		rating.UpdatedAt = now
+		st.ratings[rating.MovieID][rating.Source] = rating
+		return nil
+	})
+}
+
+func (s *MovieStore) GetMovieRatings(_ context.Context, movieID int) ([]domain.MovieRating, error) {
+	if err := s.faults.check("GetMovieRatings"); err != nil {
+		return nil, err
+	}
+	ratings := []domain.MovieRating{}
+	s.catalog.read(func(st *state) {
+		for _, rating := range st.ratings[movieID] {
+			ratings = append(ratings, rating)
+		}
+	})
+	sort.Slice(ratings, func(i, j int) bool { return ratings[i].Source < ratings[j].Source })
+	return ratings, nil
+}
+
+func (s *MovieStore) DeleteMovieRating(_ context.Context, movieID int, source string) error {
+	if err := s.faults.check("DeleteMovieRating"); err != nil {
+		return err
+	}
+	return s.catalog.tx(func(st *state, _ time.Time) error {
+		if _, ok := st.ratings[movieID][source]; !ok {
+			return domain.ErrRatingNotFound
+		}
+		delete(st.ratings[movieID], source)
+		return nil
+	})
+}
+
+func (s *MovieStore) SetCastOrder(_ context.Context, movieID int, actorIDs []int) error {
+	if err := s.faults.check("SetCastOrder"); err != nil {
+		return err
+	}
+	return s.catalog.tx(func(st *state, _ time.Time) error {
+		for i, actorID := range actorIDs {
+			found := false
+			for j := range st.cast[movieID] {
+				if st.cast[movieID][j].ActorID == actorID {
+					st.cast[movieID][j].BillingOrder = i + 1
+					found = true
+				}
+			}
+			if !found {
+				return fmt.Errorf("actor with ID %d, movie %d: %w", actorID, movieID, domain.ErrActorNotInMovie)
+			}
+		}
+		return nil
+	})
+}
+
+func (s *MovieStore) GetMoviesByYears(_ context.Context, fromYear, toYear, limit, offset int) ([]domain.Movie, error) {
+	if err := s.faults.check("GetMoviesByYears"); err != nil {
+		return nil, err
+	}
+	return page(s.moviesByYears(fromYear, toYear), limit, offset), nil
+}
+
+func (s *MovieStore) CountMoviesByYears(_ context.Context, fromYear, toYear int) (int, error) {
+	if err := s.faults.check("CountMoviesByYears"); err != nil {
+		return 0, err
+	}
+	return len(s.moviesByYears(fromYear, toYear)), nil
+}
+
+// moviesByYears возвращает фильмы, вышедшие в годы [fromYear, toYear], от высокого рейтинга к низкому
+func (s *MovieStore) moviesByYears(fromYear, toYear int) []domain.Movie {
+	var movies []domain.Movie
+	s.catalog.read(func(st *state) {
+		for _, movie := range st.sortedMovies() {
+			if movie.ReleaseYear >= fromYear && movie.ReleaseYear <= toYear {
+				movies = append(movies, movie)
+			}
+		}
+	})
+	sort.SliceStable(movies, func(i, j int) bool { return movies[i].Rating > movies[j].Rating })
+	return movies
+}
+
+func (s *MovieStore) AddMovieTag(_ context.Context, movieID int, tag string) error {
+	if err := s.faults.check("AddMovieTag"); err != nil {
+		return err
+	}
+	return s.catalog.tx(func(st *state, _ time.Time) error {
+		if _, ok := st.movies[movieID]; !ok {
+			return fmt.Errorf("%w: movie_tags_film_id_fkey", domain.ErrReferenceNotFound)
+		}
+		st.addTag(movieID, tag)
+		return nil
+	})
+}
+
+func (s *MovieStore) RemoveMovieTag(_ context.Context, movieID int, tag string) error {
+	if err := s.faults.check("RemoveMovieTag"); err != nil {
+		return err
+	}
+	return s.catalog.tx(func(st *state, _ time.Time) error {
+		tags := st.tags[movieID]
+		for i, t := range tags {
+			if t == tag {
+				st.tags[movieID] = append(tags[:i], tags[i+1:]...)
+				return nil
+			}
+		}
+		return domain.ErrTagNotFound
+	})
+}
+
+func (s *MovieStore) GetMovieTags(_ context.Context, movieID int) ([]string, error) {
+	if err := s.faults.check("GetMovieTags"); err != nil {
+		return nil, err
+	}
+	tags := []string{}
+	s.catalog.read(func(st *state) { tags = append(tags, st.tags[movieID]...) })
+	return tags, nil
+}
+
+func (s *MovieStore) GetMoviesByTag(_ context.Context, tag string, limit, offset int) ([]domain.Movie, error) {
+	if err := s.faults.check("GetMoviesByTag"); err != nil {
+		return nil, err
+	}
+	return page(s.moviesByTag(tag), limit, offset), nil
+}
+
+func (s *MovieStore) CountMoviesByTag(_ context.Context, tag string) (int, error) {
+	if err := s.faults.check("CountMoviesByTag"); err != nil {
+		return 0, err
+	}
+	return len(s.moviesByTag(tag)), nil
+}
+
+// moviesByTag возвращает фильмы с тегом от высокого рейтинга к низкому
+func (s *MovieStore) moviesByTag(tag string) []domain.Movie {
+	var movies []domain.Movie
+	s.catalog.read(func(st *state) {
+		for _, movie := range st.sortedMovies() {
+			for _, t := range st.tags[movie.ID] {
+				if t == tag {
+					movies = append(movies, movie)
+				}
+			}
+		}
+	})
+	sort.SliceStable(movies, func(i, j int) bool { return movies[i].Rating > movies[j].Rating })
+	return movies
+}
+
+func (s *MovieStore) SuggestTags(_ context.Context, prefix string, limit int) ([]domain.Tag, error) {
+	if err := s.faults.check("SuggestTags"); err != nil {
+		return nil, err
+	}
+	counts := map[string]int{}
+	s.catalog.read(func(st *state) {
+		for _, tags := range st.tags {
+			for _, tag := range tags {
+				if strings.HasPrefix(tag, prefix) {
+					counts[tag]++
+				}
+			}
+		}
+	})
+	suggestions := make([]domain.Tag, 0, len(counts))
+	for name, count := range counts {
+		suggestions = append(suggestions, domain.Tag{Name: name, MovieCount: count})
+	}
+	sort.Slice(suggestions, func(i, j int) bool {
+		if suggestions[i].MovieCount != suggestions[j].MovieCount {
+			return suggestions[i].MovieCount > suggestions[j].MovieCount
+		}
+		return suggestions[i].Name < suggestions[j].Name
+	})
+	return page(suggestions, limit, 0), nil
+}
+
+func (s *MovieStore) GetByExternalID(_ context.Context, externalID string) (domain.Movie, error) {
+	if err := s.faults.check("GetByExternalID"); err != nil {
+		return domain.Movie{}, err
+	}
+	var movie domain.Movie
+	var ok bool
+	s.catalog.read(func(st *state) { movie, ok = st.movieByExternalID(externalID) })
+	if !ok {
+		return domain.Movie{}, domain.ErrMovieNotFound
+	}
+	return movie, nil
+}
+
+func (s *MovieStore) UpsertByExternalID(_ context.Context, movie domain.Movie) (int, bool, error) {
+	if err := s.faults.check("UpsertByExternalID"); err != nil {
+		return 0, false, err
+	}
+	var id int
+	var created bool
+	err := s.catalog.tx(func(st *state, now time.Time) error {
+		existing, ok := st.movieByExternalID(movie.ExternalID)
+		if !ok {
+			id, created = st.insertMovie(movie, now), true
+			return nil
+		}
+		// Slug и счётчик просмотров при перезаписи не меняются
+		movie.ID, movie.Slug, movie.ViewCount, movie.UpdatedAt = existing.ID, existing.Slug, existing.ViewCount, now
+		movie.Actors = nil
+		st.movies[existing.ID] = movie
+		id = existing.ID
+		return nil
+	})
+	return id, created, err
+}
+
+func (s *MovieStore) GetMovieCastPage(_ context.Context, movieID, limit, offset int) ([]domain.Actor, error) {
+	if err := s.faults.check("GetMovieCastPage"); err != nil {
+		return nil, err
+	}
+	var actors []domain.Actor
+	s.catalog.read(func(st *state) { actors = st.castActors(movieID) })
+	return page(actors, limit, offset), nil
+}
+
+func (s *MovieStore) CountMovieCast(_ context.Context, movieID int) (int, error) {
+	if err := s.faults.check("CountMovieCast"); err != nil {
+		return 0, err
+	}
+	count := 0
+	s.catalog.read(func(st *state) { count = len(st.cast[movieID]) })
+	return count, nil
+}
+
+// deleteMovie удаляет фильм вместе с составом, тегами, рейтингами и окнами доступности
+func (st *state) deleteMovie(id int) {
+	delete(st.movies, id)
+	delete(st.cast, id)
+	delete(st.tags, id)
+	delete(st.ratings, id)
+	delete(st.references, id)
+	for windowID, window := range st.availability {
+		if window.MovieID == id {
+			delete(st.availability, windowID)
+		}
+	}
+}
+
+// movieReferences возвращает ссылки на фильм, как их считает репозиторий: заданные SetReferences,
+// теги, окна доступности и рейтинги источников, кроме internal
+func (st *state) movieReferences(id int) []domain.MovieReference {
+	references := append([]domain.MovieReference{}, st.references[id]...)
+	add := func(kind string, count int) {
+		if count > 0 {
+			references = append(references, domain.MovieReference{Kind: kind, Count: count})
+		}
+	}
+	add("tags", len(st.tags[id]))
+	add("availability", len(st.windowsOf(id)))
+	ratings := 0
+	for source := range st.ratings[id] {
+		if source != domain.RatingSourceInternal {
+			ratings++
+		}
+	}
+	add("ratings", ratings)
+	return references
+}
+
+// deleteMovieByPolicy удаляет фильм; при политике restrict фильм со ссылками не удаляется
+func (st *state) deleteMovieByPolicy(id int, policy domain.MovieDeletePolicy) error {
+	if _, ok := st.movies[id]; !ok {
+		return domain.ErrMovieNotFound
+	}
+	if policy == domain.MovieDeleteRestrict {
+		if references := st.movieReferences(id); len(references) > 0 {
+			return &domain.MovieReferencedError{References: references}
+		}
+	}
+	st.deleteMovie(id)
+	return nil
+}
+
+// checkCastRefs проверяет внешние ключи film_actor
+func (st *state) checkCastRefs(movieID, actorID int) error {
+	if _, ok := st.movies[movieID]; !ok {
+		return fmt.Errorf("%w: film_actor_film_id_fkey", domain.ErrReferenceNotFound)
+	}
+	if _, ok := st.actors[actorID]; !ok {
+		return fmt.Errorf("%w: film_actor_actor_id_fkey", domain.ErrReferenceNotFound)
+	}
+	return nil
+}
+
+// removeFromCast убирает актёра из состава фильма
+func (st *state) removeFromCast(movieID, actorID int) {
+	members := st.cast[movieID][:0]
+	for _, member := range st.cast[movieID] {
+		if member.ActorID != actorID {
+			members = append(members, member)
+		}
+	}
+	st.cast[movieID] = members
+}
+
+// applyUpdate применяет частичное обновление к фильму
+func (st *state) applyUpdate(id int, update domain.MovieUpdate, now time.Time) error {
+	movie, ok := st.movies[id]
+	if !ok {
+		return domain.ErrMovieNotFound
+	}
+	if update.Title != nil {
+		movie.Title = *update.Title
+	}
+	if update.Description != nil {
+		movie.Description = *update.Description
+	}
+	if update.ReleaseYear != nil {
+		movie.ReleaseYear = *update.ReleaseYear
+	}
+	if update.ReleaseDate != nil {
+		movie.ReleaseDate = update.ReleaseDate
+	}
+	if update.ClearReleaseDate {
+		movie.ReleaseDate = nil
+	}
+	if update.Rating != nil {
+		movie.Rating = *update.Rating
+	}
+	if update.OriginalLanguage != nil {
+		movie.OriginalLanguage = *update.OriginalLanguage
+	}
+	if update.Country != nil {
+		movie.Country = *update.Country
+	}
+	if update.RuntimeMinutes != nil {
+		movie.RuntimeMinutes = *update.RuntimeMinutes
+	}
+	movie.UpdatedAt = now
+	st.movies[id] = movie
+	return nil
+}
+
+// addTag добавляет фильму тег, сохраняя алфавитный порядок; повтор ничего не меняет
+func (st *state) addTag(movieID int, tag string) {
+	tags := st.tags[movieID]
+	i := sort.SearchStrings(tags, tag)
+	if i < len(tags) && tags[i] == tag {
+		return
+	}
+	st.tags[movieID] = append(tags[:i], append([]string{tag}, tags[i:]...)...)
+}
+
+// movieByExternalID ищет фильм по ID в каталоге-источнике
+func (st *state) movieByExternalID(externalID string) (domain.Movie, bool) {
+	for _, movie := range st.movies {
+		if movie.ExternalID == externalID {
+			return movie, true
+		}
+	}
+	return domain.Movie{}, false
+}
+
+// findActor ищет актёра по имени без учёта регистра и дате рождения, если она известна
+func (st *state) findActor(actor domain.Actor) int {
+	for _, existing := range st.sortedActors() {
+		if strings.EqualFold(existing.Name, actor.Name) &&
+			(actor.BirthDate.IsZero() || existing.BirthDate.Equal(actor.BirthDate)) {
+			return existing.ID
+		}
+	}
+	return 0
+}
+
+// windowsOf возвращает окна доступности фильма по возрастанию ID
+func (st *state) windowsOf(movieID int) []domain.Availability {
+	windows := []domain.Availability{}
+	for _, window := range st.availability {
+		if window.MovieID == movieID {
+			windows = append(windows, window)
+		}
+	}
+	sort.Slice(windows, func(i, j int) bool { return windows[i].ID < windows[j].ID })
+	return windows
+}
+
+// matchFilter проверяет фильм по условиям domain.MovieFilter
+func (st *state) matchFilter(movie domain.Movie, filter domain.MovieFilter) bool {
+	if filter.OriginalLanguage != "" && movie.OriginalLanguage != filter.OriginalLanguage {
+		return false
+	}
+	if filter.Country != "" && movie.Country != filter.Country {
+		return false
+	}
+	if (filter.RuntimeMin > 0 || filter.RuntimeMax > 0) && movie.RuntimeMinutes == 0 {
+		return false
+	}
+	if filter.RuntimeMin > 0 && movie.RuntimeMinutes < filter.RuntimeMin {
+		return false
+	}
+	if filter.RuntimeMax > 0 && movie.RuntimeMinutes > filter.RuntimeMax {
+		return false
+	}
+	if filter.Region == "" && filter.Available == nil {
+		return true
+	}
+	inRegion, available := false, false
+	for _, window := range st.windowsOf(movie.ID) {
+		if filter.Region != "" && window.Region != filter.Region {
+			continue
+		}
+		inRegion = true
+		if !window.AvailableFrom.After(filter.AvailableOn) &&
+			(window.AvailableUntil == nil || window.AvailableUntil.After(filter.AvailableOn)) {
+			available = true
+		}
+	}
+	if filter.Available == nil {
+		return inRegion
+	}
+	return available == *filter.Available
+}
+
+// compareMovies сравнивает фильмы по полю сортировки GET /movies/sorted
+func compareMovies(a, b domain.Movie, field string) int {
+	switch field {
+	case "id":
+		return compareInts(a.ID, b.ID)
+	case "title":
+		return strings.Compare(a.Title, b.Title)
+	case "rating":
+		switch {
+		case a.Rating < b.Rating:
+			return -1
+		case a.Rating > b.Rating:
+			return 1
+		}
+	case "release_year":
+		return compareInts(a.ReleaseYear, b.ReleaseYear)
+	case "release_date":
+		switch {
+		case a.ReleaseDate == nil && b.ReleaseDate == nil:
+			return 0
+		case a.ReleaseDate == nil:
+			return 1
+		case b.ReleaseDate == nil:
+			return -1
+		}
+		return a.ReleaseDate.Compare(*b.ReleaseDate)
+	case "view_count":
+		return compareInts(int(a.ViewCount), int(b.ViewCount))
+	case "runtime_minutes":
+		return compareInts(a.RuntimeMinutes, b.RuntimeMinutes)
+	}
+	return 0
+}
+
+func compareInts(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// containsFold сообщает, входит ли fragment в s без учёта регистра, как ILIKE '%fragment%'
+func containsFold(s, fragment string) bool {
+	return strings.Contains(strings.ToLower(s), strings.ToLower(fragment))
+}
+
+// normalizeTitle нормализует название так же, как репозиторий при поиске дубликатов
+func normalizeTitle(title string) string {
+	return strings.Join(strings.FieldsFunc(strings.ToLower(title), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}), " ")
+}
@@ -0,0 +1,403 @@
+// Package testutil содержит in-memory реализации хранилищ фильмов и актёров для тестов
+// сервисного слоя. MovieStore и ActorStore работают с общим Catalog, поэтому связи
+// фильмов и актёров видны из обоих хранилищ, как в базе.
+//
+// Операции, которые в репозитории выполняются одной транзакцией (слияние, массовые
+// изменения, импорт, замена состава), меняют каталог целиком или не меняют вовсе: так
+// тесты могут проверять откат и компенсирующие действия сервиса. Сбои внедряются
+// методами FailOn и FailAfter:
+//
+//	catalog := testutil.NewCatalog()
+//	movies := catalog.Movies()
+//	movies.FailOn("AddActor", errors.New("connection reset"))
+//	_, err := service.NewMovie(movies, nil).Create(ctx, movie, []int{1}, true)
+package testutil
+
+import (
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"cinematique/internal/domain"
+	"cinematique/internal/slug"
+)
+
+// Catalog — общее состояние фейковых хранилищ. Методы безопасны для параллельных вызовов
+type Catalog struct {
+	mu    sync.Mutex
+	state *state
+	now   func() time.Time
+}
+
+// state — содержимое каталога; транзакция работает с копией и подменяет её при успехе
+type state struct {
+	movies        map[int]domain.Movie // без состава: он хранится в cast
+	actors        map[int]domain.Actor
+	cast          map[int][]domain.CastMember // состав фильма в порядке добавления
+	revisions     []domain.MovieRevision
+	actorHistory  []domain.ActorRevision
+	ratingHistory []domain.RatingChange
+	ratings       map[int]map[string]domain.MovieRating
+	tags          map[int][]string // теги фильма по алфавиту
+	availability  map[int]domain.Availability
+	merged        map[int]int    // ID слитого фильма -> ID фильма, в который он слит
+	mergedSlugs   map[string]int // slug слитого фильма -> ID фильма, в который он слит
+	references    map[int][]domain.MovieReference
+	nextID        int // общий счётчик ID: так ID фильма не совпадает с ID актёра
+}
+
+// NewCatalog создаёт пустой каталог
+func NewCatalog() *Catalog {
+	return &Catalog{
+		state: &state{
+			movies:       map[int]domain.Movie{},
+			actors:       map[int]domain.Actor{},
+			cast:         map[int][]domain.CastMember{},
+			ratings:      map[int]map[string]domain.MovieRating{},
+			tags:         map[int][]string{},
+			availability: map[int]domain.Availability{},
+			merged:       map[int]int{},
+			mergedSlugs:  map[string]int{},
+			references:   map[int][]domain.MovieReference{},
+		},
+		now: time.Now,
+	}
+}
+
+// WithClock задаёт часы, по которым проставляются UpdatedAt и моменты ревизий
+func (c *Catalog) WithClock(now func() time.Time) *Catalog {
+	c.now = now
+	return c
+}
+
+// Movies возвращает хранилище фильмов поверх каталога
+func (c *Catalog) Movies() *MovieStore {
+	return &MovieStore{catalog: c, faults: newFaults()}
+}
+
+// Actors возвращает хранилище актёров поверх каталога
+func (c *Catalog) Actors() *ActorStore {
+	return &ActorStore{catalog: c, faults: newFaults()}
+}
+
+// SeedMovie добавляет фильм в обход хранилища и возвращает его ID. Пустой slug
+// строится из названия и года, как в репозитории
+func (c *Catalog) SeedMovie(movie domain.Movie) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.state.insertMovie(movie, c.now())
+}
+
+// SeedActor добавляет актёра в обход хранилища и возвращает его ID
+func (c *Catalog) SeedActor(actor domain.Actor) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.state.insertActor(actor, c.now())
+}
+
+// SeedCast добавляет актёров actorIDs в состав фильма в порядке титров
+func (c *Catalog) SeedCast(movieID int, actorIDs ...int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, actorID := range actorIDs {
+		order := len(c.state.cast[movieID]) + 1
+		c.state.cast[movieID] = append(c.state.cast[movieID], domain.CastMember{ActorID: actorID, BillingOrder: order})
+	}
+}
+
+// SetReferences задаёт ссылки на фильм из подборок и других записей, которые не моделирует каталог.
+// Вместе с тегами, окнами доступности и рейтингами они блокируют удаление при политике restrict
+func (c *Catalog) SetReferences(movieID int, references ...domain.MovieReference) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.state.references[movieID] = references
+}
+
+// Movie возвращает фильм без состава, как он лежит в каталоге
+func (c *Catalog) Movie(id int) (domain.Movie, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	movie, ok := c.state.movies[id]
+	return movie, ok
+}
+
+// Actor возвращает актёра, как он лежит в каталоге
+func (c *Catalog) Actor(id int) (domain.Actor, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	actor, ok := c.state.actors[id]
+	return actor, ok
+}
+
+// MovieCount возвращает число фильмов в каталоге
+func (c *Catalog) MovieCount() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.state.movies)
+}
+
+// CastIDs возвращает ID актёров фильма в порядке титров
+func (c *Catalog) CastIDs(movieID int) []int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	members := c.state.sortedCast(movieID)
+	ids := make([]int, 0, len(members))
+	for _, member := range members {
+		ids = append(ids, member.ActorID)
+	}
+	return ids
+}
+
+// Revisions возвращает ревизии фильма в порядке записи
+func (c *Catalog) Revisions(movieID int) []domain.MovieRevision {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var revisions []domain.MovieRevision
+	for _, revision := range c.state.revisions {
+		if revision.MovieID == movieID {
+			revisions = append(revisions, revision)
+		}
+	}
+	return revisions
+}
+
+// ActorRevisions возвращает версии актёра в порядке записи
+func (c *Catalog) ActorRevisions(actorID int) []domain.ActorRevision {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var revisions []domain.ActorRevision
+	for _, revision := range c.state.actorHistory {
+		if revision.ActorID == actorID {
+			revisions = append(revisions, revision)
+		}
+	}
+	return revisions
+}
+
+// RatingChanges возвращает историю рейтинга фильма в порядке записи
+func (c *Catalog) RatingChanges(movieID int) []domain.RatingChange {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var changes []domain.RatingChange
+	for _, change := range c.state.ratingHistory {
+		if change.MovieID == movieID {
+			changes = append(changes, change)
+		}
+	}
+	return changes
+}
+
+// read выполняет fn над текущим состоянием без изменения
+func (c *Catalog) read(fn func(st *state)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	fn(c.state)
+}
+
+// tx выполняет fn над копией состояния и сохраняет копию, только если fn не вернула ошибку
+func (c *Catalog) tx(fn func(st *state, now time.Time) error) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	draft := c.state.clone()
+	if err := fn(draft, c.now()); err != nil {
+		return err
+	}
+	c.state = draft
+	return nil
+}
+
+// clone возвращает копию состояния, изменения которой не видны в исходном
+func (st *state) clone() *state {
+	cp := *st
+	cp.movies = make(map[int]domain.Movie, len(st.movies))
+	for id, movie := range st.movies {
+		cp.movies[id] = movie
+	}
+	cp.actors = make(map[int]domain.Actor, len(st.actors))
+	for id, actor := range st.actors {
+		cp.actors[id] = actor
+	}
+	cp.cast = make(map[int][]domain.CastMember, len(st.cast))
+	for id, members := range st.cast {
+		cp.cast[id] = append([]domain.CastMember(nil), members...)
+	}
+	cp.revisions = append([]domain.MovieRevision(nil), st.revisions...)
+	cp.actorHistory = append([]domain.ActorRevision(nil), st.actorHistory...)
+	cp.ratingHistory = append([]domain.RatingChange(nil), st.ratingHistory...)
+	cp.ratings = make(map[int]map[string]domain.MovieRating, len(st.ratings))
+	for id, bySource := range st.ratings {
+		cp.ratings[id] = make(map[string]domain.MovieRating, len(bySource))
+		for source, rating := range bySource {
+			cp.ratings[id][source] = rating
+		}
+	}
+	cp.tags = make(map[int][]string, len(st.tags))
+	for id, tags := range st.tags {
+		cp.tags[id] = append([]string(nil), tags...)
+	}
+	cp.availability = make(map[int]domain.Availability, len(st.availability))
+	for id, window := range st.availability {
+		cp.availability[id] = window
+	}
+	cp.merged = make(map[int]int, len(st.merged))
+	for oldID, newID := range st.merged {
+		cp.merged[oldID] = newID
+	}
+	cp.mergedSlugs = make(map[string]int, len(st.mergedSlugs))
+	for oldSlug, newID := range st.mergedSlugs {
+		cp.mergedSlugs[oldSlug] = newID
+	}
+	cp.references = make(map[int][]domain.MovieReference, len(st.references))
+	for id, references := range st.references {
+		cp.references[id] = append([]domain.MovieReference(nil), references...)
+	}
+	return &cp
+}
+
+// newID выдаёт следующий свободный ID
+func (st *state) newID() int {
+	st.nextID++
+	return st.nextID
+}
+
+// insertMovie сохраняет фильм под новым ID и подбирает ему свободный slug
+func (st *state) insertMovie(movie domain.Movie, now time.Time) int {
+	movie.ID = st.newID()
+	movie.Actors = nil
+	movie.UpdatedAt = now
+	if movie.Slug == "" {
+		year := ""
+		if movie.ReleaseYear > 0 {
+			year = strconv.Itoa(movie.ReleaseYear)
+		}
+		movie.Slug = st.freeSlug(slug.Make(movie.Title, year), "movie", func(s string) bool {
+			for _, m := range st.movies {
+				if m.Slug == s {
+					return true
+				}
+			}
+			return false
+		})
+	}
+	st.movies[movie.ID] = movie
+	return movie.ID
+}
+
+// insertActor сохраняет актёра под новым ID и подбирает ему свободный slug
+func (st *state) insertActor(actor domain.Actor, now time.Time) int {
+	actor.ID = st.newID()
+	actor.Movies = nil
+	actor.CharacterName, actor.BillingOrder = "", 0
+	actor.UpdatedAt = now
+	if actor.Slug == "" {
+		actor.Slug = st.freeSlug(slug.Make(actor.Name), "actor", func(s string) bool {
+			for _, a := range st.actors {
+				if a.Slug == s {
+					return true
+				}
+			}
+			return false
+		})
+	}
+	st.actors[actor.ID] = actor
+	return actor.ID
+}
+
+// freeSlug возвращает base или первый свободный вариант base-2, base-3, ...
+func (st *state) freeSlug(base, fallback string, taken func(string) bool) string {
+	if base == "" {
+		base = fallback
+	}
+	candidate := base
+	for n := 2; taken(candidate); n++ {
+		candidate = base + "-" + strconv.Itoa(n)
+	}
+	return candidate
+}
+
+// sortedCast возвращает состав фильма в порядке титров: без места в титрах — последними,
+// по имени и ID, как в репозитории
+func (st *state) sortedCast(movieID int) []domain.CastMember {
+	members := append([]domain.CastMember(nil), st.cast[movieID]...)
+	sort.SliceStable(members, func(i, j int) bool {
+		a, b := members[i], members[j]
+		if (a.BillingOrder == 0) != (b.BillingOrder == 0) {
+			return b.BillingOrder == 0
+		}
+		if a.BillingOrder != b.BillingOrder {
+			return a.BillingOrder < b.BillingOrder
+		}
+		nameA, nameB := st.actors[a.ActorID].Name, st.actors[b.ActorID].Name
+		if nameA != nameB {
+			return nameA < nameB
+		}
+		return a.ActorID < b.ActorID
+	})
+	return members
+}
+
+// castActors возвращает актёров фильма в порядке титров с ролью и местом в титрах
+func (st *state) castActors(movieID int) []domain.Actor {
+	var actors []domain.Actor
+	for _, member := range st.sortedCast(movieID) {
+		actor := st.actors[member.ActorID]
+		actor.CharacterName = member.CharacterName
+		actor.BillingOrder = member.BillingOrder
+		actors = append(actors, actor)
+	}
+	return actors
+}
+
+// inCast сообщает, есть ли актёр в составе фильма
+func (st *state) inCast(movieID, actorID int) bool {
+	for _, member := range st.cast[movieID] {
+		if member.ActorID == actorID {
+			return true
+		}
+	}
+	return false
+}
+
+// moviesOfActor возвращает фильмы актёра по возрастанию ID
+func (st *state) moviesOfActor(actorID int) []domain.Movie {
+	var movies []domain.Movie
+	for _, movie := range st.sortedMovies() {
+		if st.inCast(movie.ID, actorID) {
+			movies = append(movies, movie)
+		}
+	}
+	return movies
+}
+
+// sortedMovies возвращает все фильмы по возрастанию ID
+func (st *state) sortedMovies() []domain.Movie {
+	movies := make([]domain.Movie, 0, len(st.movies))
+	for _, movie := range st.movies {
+		movies = append(movies, movie)
+	}
+	sort.Slice(movies, func(i, j int) bool { return movies[i].ID < movies[j].ID })
+	return movies
+}
+
+// sortedActors возвращает всех актёров по возрастанию ID
+func (st *state) sortedActors() []domain.Actor {
+	actors := make([]domain.Actor, 0, len(st.actors))
+	for _, actor := range st.actors {
+		actors = append(actors, actor)
+	}
+	sort.Slice(actors, func(i, j int) bool { return actors[i].ID < actors[j].ID })
+	return actors
+}
+
+// page возвращает элементы items[offset:offset+limit]; limit <= 0 — без ограничения
+func page[T any](items []T, limit, offset int) []T {
+	if offset >= len(items) {
+		return []T{}
+	}
+	items = items[offset:]
+	if limit > 0 && len(items) > limit {
+		items = items[:limit]
+	}
+	return items
+}
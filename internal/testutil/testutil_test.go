@@ -0,0 +1,50 @@
+package testutil
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"cinematique/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMovieStore_UpdateMovieActorsRollsBack(t *testing.T) {
+	catalog := NewCatalog()
+	movieID := catalog.SeedMovie(domain.Movie{Title: "Heat", ReleaseYear: 1995})
+	pacino := catalog.SeedActor(domain.Actor{Name: "Al Pacino"})
+	deNiro := catalog.SeedActor(domain.Actor{Name: "Robert De Niro"})
+	catalog.SeedCast(movieID, pacino)
+	movies := catalog.Movies()
+
+	// Неизвестный актёр в середине списка отменяет замену состава целиком
+	err := movies.UpdateMovieActors(context.Background(), movieID, []domain.CastMember{{ActorID: deNiro}, {ActorID: 999}})
+	require.ErrorIs(t, err, domain.ErrReferenceNotFound)
+	assert.Equal(t, []int{pacino}, catalog.CastIDs(movieID))
+}
+
+func TestMovieStore_FailOn(t *testing.T) {
+	catalog := NewCatalog()
+	movieID := catalog.SeedMovie(domain.Movie{Title: "Heat", ReleaseYear: 1995})
+	movies := catalog.Movies()
+	ctx := context.Background()
+
+	errTimeout := errors.New("statement timeout")
+	movies.FailOn("GetByID", errTimeout)
+	for i := 0; i < 2; i++ {
+		_, err := movies.GetByID(ctx, movieID)
+		assert.ErrorIs(t, err, errTimeout)
+	}
+
+	movies.ClearFailures()
+	movie, err := movies.GetByID(ctx, movieID)
+	require.NoError(t, err)
+	assert.Equal(t, "heat-1995", movie.Slug)
+	assert.Equal(t, 3, movies.Calls("GetByID"))
+
+	// Сбои настраиваются для каждого хранилища отдельно
+	_, err = catalog.Actors().GetByID(ctx, movieID)
+	assert.ErrorIs(t, err, domain.ErrActorNotFound)
+}
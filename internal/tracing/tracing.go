@@ -0,0 +1,53 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+)
+
+// Config содержит настройки экспорта трасс
+type Config struct {
+	Enabled     bool
+	ServiceName string
+	Endpoint    string  // URL OTLP/HTTP коллектора, например http://jaeger:4318; пустой — из OTEL_EXPORTER_OTLP_*
+	SampleRatio float64 // доля трасс, начинаемых в этом сервисе (0–1)
+}
+
+// Setup настраивает глобальный TracerProvider с экспортом по OTLP/HTTP (Jaeger принимает его
+// напрямую) и пропагацию W3C Trace Context. Возвращает функцию, которая отправляет
+// накопленные span и останавливает экспорт. Если трассировка выключена, span
+// создаются no-op провайдером и никуда не отправляются
+func Setup(ctx context.Context, cfg Config) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+	if !cfg.Enabled {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	var opts []otlptracehttp.Option
+	if cfg.Endpoint != "" {
+		opts = append(opts, otlptracehttp.WithEndpointURL(cfg.Endpoint))
+	}
+	exporter, err := otlptracehttp.New(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("creating OTLP exporter: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceName(cfg.ServiceName))),
+		// Решение о записи принимает сервис, начавший трассу; входящий traceparent его соблюдает
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
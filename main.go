@@ -2,11 +2,20 @@ package main
 
 import (
 	"log"
+	"os"
 
 	"cinematique/cmd"
 )
 
 func main() {
+	// cinematique seed заполняет базу тестовыми данными вместо запуска сервера
+	if len(os.Args) > 1 && os.Args[1] == "seed" {
+		if err := cmd.Seed(os.Args[2:]); err != nil {
+			log.Fatalf("Seed error: %v", err)
+		}
+		return
+	}
+
 	// Запускаем приложение
 	if err := cmd.Run(); err != nil {
 		log.Fatalf("Application error: %v", err)
@@ -0,0 +1,37 @@
+-- Базовая схема каталога: фильмы, актёры, состав фильмов и пользователи.
+-- Применяется первой (docker-entrypoint-initdb.d, itest.ApplyMigrations); колонки и таблицы,
+-- появившиеся позже, добавляют миграции update_NNN_*.sql
+CREATE TABLE IF NOT EXISTS films (
+    id SERIAL PRIMARY KEY,
+    title VARCHAR(150) NOT NULL,
+    description VARCHAR(1000) NOT NULL DEFAULT '',
+    release_year INTEGER NOT NULL,
+    rating DOUBLE PRECISION NOT NULL DEFAULT 0 CHECK (rating >= 0 AND rating <= 10)
+);
+
+CREATE TABLE IF NOT EXISTS actors (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    gender VARCHAR(10) NOT NULL,
+    birth_date DATE NOT NULL
+);
+
+-- Состав фильма: удаление фильма или актёра удаляет и его участие в фильмах
+CREATE TABLE IF NOT EXISTS film_actor (
+    film_id INTEGER NOT NULL REFERENCES films(id) ON DELETE CASCADE,
+    actor_id INTEGER NOT NULL REFERENCES actors(id) ON DELETE CASCADE,
+    PRIMARY KEY (film_id, actor_id)
+);
+
+-- Фильмы актёра (GET /api/actors/:id/movies); поиск по film_id покрывает первичный ключ
+CREATE INDEX IF NOT EXISTS idx_film_actor_actor_id ON film_actor(actor_id);
+
+-- Пользователи с локальным входом; роль — user, moderator или admin
+CREATE TABLE IF NOT EXISTS users (
+    id SERIAL PRIMARY KEY,
+    username VARCHAR(255) NOT NULL UNIQUE,
+    email VARCHAR(255) NOT NULL UNIQUE,
+    password_hash VARCHAR(255) NOT NULL,
+    role VARCHAR(20) NOT NULL DEFAULT 'user',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
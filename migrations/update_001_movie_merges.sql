@@ -0,0 +1,9 @@
+-- Таблица соответствий для слитых фильмов: old_id удалён и слит в new_id.
+-- Используется для редиректа со старых идентификаторов.
+CREATE TABLE IF NOT EXISTS movie_merges (
+    old_id    INTEGER PRIMARY KEY,
+    new_id    INTEGER NOT NULL REFERENCES films(id) ON DELETE CASCADE,
+    merged_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_movie_merges_new_id ON movie_merges(new_id);
@@ -0,0 +1,11 @@
+-- Каталоги (tenants) сняты с разработки, см. docs/OUT_OF_SCOPE.md. Первая версия этой миграции
+-- добавляла колонки tenant_id, которые ни один запрос не читал. Номер сохранён, чтобы нумерация
+-- миграций не прерывалась; на базах, где первая версия уже применена, колонки и индексы удаляются,
+-- на новых базах миграция ничего не меняет.
+DROP INDEX IF EXISTS idx_films_tenant_id;
+DROP INDEX IF EXISTS idx_actors_tenant_id;
+DROP INDEX IF EXISTS idx_users_tenant_id;
+
+ALTER TABLE films  DROP COLUMN IF EXISTS tenant_id;
+ALTER TABLE actors DROP COLUMN IF EXISTS tenant_id;
+ALTER TABLE users  DROP COLUMN IF EXISTS tenant_id;
@@ -0,0 +1,4 @@
+-- Точная дата выхода фильма. release_year остаётся для обратной совместимости
+ALTER TABLE films ADD COLUMN IF NOT EXISTS release_date DATE;
+
+CREATE INDEX IF NOT EXISTS idx_films_release_date ON films(release_date);
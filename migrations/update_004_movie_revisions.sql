@@ -0,0 +1,24 @@
+-- История изменений фильмов: каждая запись хранит изменённые поля (JSON) и момент изменения.
+-- По ней восстанавливается состояние фильма на прошлую дату (GET /api/movies/:id?as_of=...).
+-- Внешнего ключа нет: история должна переживать удаление фильма.
+CREATE TABLE IF NOT EXISTS movie_revisions (
+    id SERIAL PRIMARY KEY,
+    film_id INTEGER NOT NULL,
+    changed_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    changes JSONB NOT NULL,
+    deleted BOOLEAN NOT NULL DEFAULT FALSE
+);
+
+CREATE INDEX IF NOT EXISTS idx_movie_revisions_film_id ON movie_revisions(film_id, changed_at);
+
+-- Исходное состояние уже существующих фильмов: история для них известна с момента миграции
+INSERT INTO movie_revisions (film_id, changes)
+SELECT f.id, jsonb_strip_nulls(jsonb_build_object(
+    'title', f.title,
+    'description', f.description,
+    'release_year', f.release_year,
+    'rating', f.rating,
+    'release_date', to_char(f.release_date, 'YYYY-MM-DD"T00:00:00Z"')
+))
+FROM films f
+WHERE NOT EXISTS (SELECT 1 FROM movie_revisions r WHERE r.film_id = f.id);
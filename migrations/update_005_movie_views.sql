@@ -0,0 +1,4 @@
+-- Счётчик просмотров фильма. Увеличивается асинхронно консьюмером топика movie-views
+ALTER TABLE films ADD COLUMN IF NOT EXISTS view_count BIGINT NOT NULL DEFAULT 0;
+
+CREATE INDEX IF NOT EXISTS idx_films_view_count ON films(view_count DESC, id);
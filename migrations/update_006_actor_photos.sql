@@ -0,0 +1,2 @@
+-- Фотография актёра: ключ объекта в хранилище (см. internal/storage). Пустая строка — фото нет
+ALTER TABLE actors ADD COLUMN IF NOT EXISTS photo_key TEXT NOT NULL DEFAULT '';
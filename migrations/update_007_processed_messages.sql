@@ -0,0 +1,18 @@
+-- Журнал обработанных сообщений Kafka: повторная доставка (падение до коммита смещения,
+-- перемотка группы, повторная отправка продюсером) не должна обрабатываться дважды.
+-- Сообщение идентифицируется позицией в топике и, если есть, event_id из тела события
+CREATE TABLE IF NOT EXISTS processed_messages (
+    id BIGSERIAL PRIMARY KEY,
+    consumer_group TEXT NOT NULL,
+    topic TEXT NOT NULL,
+    partition INTEGER NOT NULL,
+    kafka_offset BIGINT NOT NULL,
+    message_id TEXT NOT NULL DEFAULT '',
+    processed_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_processed_messages_position
+    ON processed_messages(consumer_group, topic, partition, kafka_offset);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_processed_messages_message_id
+    ON processed_messages(consumer_group, message_id) WHERE message_id <> '';
@@ -0,0 +1,17 @@
+-- Подсказки «возможно, вы искали»: похожие названия фильмов и имена актёров ищутся по триграммам
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX IF NOT EXISTS idx_films_title_trgm ON films USING GIN (title gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_actors_name_trgm ON actors USING GIN (name gin_trgm_ops);
+
+-- Поисковая аналитика: число поисков по нормализованному запросу за день и сколько из них
+-- вернули пустой результат. Заполняется консьюмером топика movie-searches
+CREATE TABLE IF NOT EXISTS search_stats (
+    query TEXT NOT NULL,
+    day DATE NOT NULL,
+    searches BIGINT NOT NULL DEFAULT 0,
+    zero_results BIGINT NOT NULL DEFAULT 0,
+    PRIMARY KEY (query, day)
+);
+
+CREATE INDEX IF NOT EXISTS idx_search_stats_query_trgm ON search_stats USING GIN (query gin_trgm_ops);
@@ -0,0 +1,17 @@
+-- Подборки и франшизы («Трилогия Матрицы»): упорядоченные списки фильмов
+CREATE TABLE IF NOT EXISTS collections (
+    id          SERIAL PRIMARY KEY,
+    name        VARCHAR(150) NOT NULL,
+    description TEXT NOT NULL DEFAULT ''
+);
+
+-- Фильмы подборки; position задаёт порядок просмотра (с 1)
+CREATE TABLE IF NOT EXISTS collection_movies (
+    collection_id INTEGER NOT NULL REFERENCES collections(id) ON DELETE CASCADE,
+    film_id       INTEGER NOT NULL REFERENCES films(id) ON DELETE CASCADE,
+    position      INTEGER NOT NULL,
+    PRIMARY KEY (collection_id, film_id),
+    UNIQUE (collection_id, position)
+);
+
+CREATE INDEX IF NOT EXISTS idx_collection_movies_film_id ON collection_movies(film_id);
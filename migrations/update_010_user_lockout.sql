@@ -0,0 +1,4 @@
+-- Блокировка учётной записи после серии неудачных входов (см. service.AuthService.Login).
+-- failed_logins сбрасывается при успешном входе и при установке блокировки
+ALTER TABLE users ADD COLUMN IF NOT EXISTS failed_logins INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS locked_until TIMESTAMPTZ;
@@ -0,0 +1,8 @@
+-- Язык оригинала (ISO 639-1) и страна производства (ISO 3166-1 alpha-2) фильма.
+-- Пустая строка означает, что значение неизвестно; коды проверяются в API (пакет isocode)
+ALTER TABLE films ADD COLUMN IF NOT EXISTS original_language VARCHAR(2) NOT NULL DEFAULT '';
+ALTER TABLE films ADD COLUMN IF NOT EXISTS country VARCHAR(2) NOT NULL DEFAULT '';
+
+-- Фильтры поиска /movies/search?language=...&country=...
+CREATE INDEX IF NOT EXISTS idx_films_original_language ON films (original_language);
+CREATE INDEX IF NOT EXISTS idx_films_country ON films (country);
@@ -0,0 +1,4 @@
+-- Роль актёра в фильме и порядок в титрах. NULL в billing_order — порядок не задан,
+-- такие актёры выводятся в составе после упорядоченных
+ALTER TABLE film_actor ADD COLUMN IF NOT EXISTS character_name VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE film_actor ADD COLUMN IF NOT EXISTS billing_order INTEGER;
@@ -0,0 +1,18 @@
+-- История рейтинга фильмов для графика его изменения (GET /api/movies/:id/rating-history).
+-- Запись добавляется при каждом изменении рейтинга. Внешнего ключа нет, как и у movie_revisions:
+-- история переживает удаление фильма.
+CREATE TABLE IF NOT EXISTS rating_history (
+    id SERIAL PRIMARY KEY,
+    film_id INTEGER NOT NULL,
+    old_rating DOUBLE PRECISION,
+    new_rating DOUBLE PRECISION NOT NULL,
+    changed_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_rating_history_film_id ON rating_history(film_id, changed_at);
+
+-- Текущий рейтинг уже существующих фильмов — начальная точка истории
+INSERT INTO rating_history (film_id, new_rating)
+SELECT f.id, f.rating
+FROM films f
+WHERE NOT EXISTS (SELECT 1 FROM rating_history h WHERE h.film_id = f.id);
@@ -0,0 +1,48 @@
+-- Момент последнего изменения фильмов и актёров для заголовков Last-Modified и If-Modified-Since.
+-- Колонки поддерживаются триггерами, поэтому их не нужно обновлять в каждом запросе репозитория.
+ALTER TABLE films ADD COLUMN IF NOT EXISTS updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW();
+ALTER TABLE actors ADD COLUMN IF NOT EXISTS updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW();
+
+-- Счётчик просмотров меняется при каждом открытии страницы фильма и изменением фильма не считается
+CREATE OR REPLACE FUNCTION films_touch_updated_at() RETURNS TRIGGER AS $$
+BEGIN
+    IF (to_jsonb(NEW) - 'view_count' - 'updated_at') IS DISTINCT FROM (to_jsonb(OLD) - 'view_count' - 'updated_at') THEN
+        NEW.updated_at = NOW();
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS films_updated_at ON films;
+CREATE TRIGGER films_updated_at BEFORE UPDATE ON films
+    FOR EACH ROW EXECUTE FUNCTION films_touch_updated_at();
+
+CREATE OR REPLACE FUNCTION actors_touch_updated_at() RETURNS TRIGGER AS $$
+BEGIN
+    IF (to_jsonb(NEW) - 'updated_at') IS DISTINCT FROM (to_jsonb(OLD) - 'updated_at') THEN
+        NEW.updated_at = NOW();
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS actors_updated_at ON actors;
+CREATE TRIGGER actors_updated_at BEFORE UPDATE ON actors
+    FOR EACH ROW EXECUTE FUNCTION actors_touch_updated_at();
+
+-- Состав фильма входит в ответ GET /api/movies/:id, поэтому его изменение тоже меняет фильм
+CREATE OR REPLACE FUNCTION film_actor_touch_film() RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP IN ('INSERT', 'UPDATE') THEN
+        UPDATE films SET updated_at = NOW() WHERE id = NEW.film_id;
+    END IF;
+    IF TG_OP IN ('DELETE', 'UPDATE') THEN
+        UPDATE films SET updated_at = NOW() WHERE id = OLD.film_id;
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS film_actor_touch_film ON film_actor;
+CREATE TRIGGER film_actor_touch_film AFTER INSERT OR UPDATE OR DELETE ON film_actor
+    FOR EACH ROW EXECUTE FUNCTION film_actor_touch_film();
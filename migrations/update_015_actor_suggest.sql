@@ -0,0 +1,4 @@
+-- Индекс для автодополнения имён актёров (GET /actors/suggest). Триграммный индекс
+-- idx_actors_name_trgm не помогает запросам короче трёх символов, а подсказки
+-- запрашиваются с первой буквы, поэтому совпадение с начала имени ищется по B-дереву
+CREATE INDEX IF NOT EXISTS idx_actors_name_prefix ON actors (LOWER(name) text_pattern_ops);
@@ -0,0 +1,15 @@
+-- Окна доступности фильмов (права на показ): в каком регионе и с какой по какую дату
+-- фильм можно смотреть. available_until включительно; NULL — бессрочно.
+-- Окна одного региона могут пересекаться: фильм доступен, если его покрывает хотя бы одно
+CREATE TABLE IF NOT EXISTS movie_availability (
+    id SERIAL PRIMARY KEY,
+    film_id INTEGER NOT NULL REFERENCES films(id) ON DELETE CASCADE,
+    region CHAR(2) NOT NULL,
+    available_from DATE NOT NULL,
+    available_until DATE,
+    CHECK (available_until IS NULL OR available_until >= available_from)
+);
+
+CREATE INDEX IF NOT EXISTS idx_movie_availability_film_id ON movie_availability(film_id);
+-- Фильтр поиска region=..&available=true: регион и диапазон дат
+CREATE INDEX IF NOT EXISTS idx_movie_availability_region ON movie_availability(region, available_from, available_until);
@@ -0,0 +1,13 @@
+-- Одноразовые токены сброса пароля (см. service.AuthService.ForgotPassword).
+-- Хранится только SHA-256 токена: утечка таблицы не даёт сбросить чужой пароль.
+-- used_at проставляется при сбросе, а также всем остальным токенам пользователя
+CREATE TABLE IF NOT EXISTS password_reset_tokens (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    token_hash CHAR(64) NOT NULL UNIQUE,
+    expires_at TIMESTAMPTZ NOT NULL,
+    used_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_password_reset_tokens_user_id ON password_reset_tokens (user_id);
@@ -0,0 +1,3 @@
+-- Версия токенов пользователя (см. auth.CheckTokenVersion). Увеличивается при смене роли:
+-- токены доступа с меньшей версией отклоняются, и новая роль действует без повторного входа
+ALTER TABLE users ADD COLUMN IF NOT EXISTS token_version INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,27 @@
+-- Человекочитаемые адреса фильмов и актёров (GET /api/movies/slug/:slug, GET /api/actors/slug/:slug).
+-- Новые slug строит репозиторий (пакет slug) и при совпадении добавляет суффикс -2, -3, ...;
+-- уникальный индекс не даёт двум записям получить один slug при одновременной вставке
+ALTER TABLE films ADD COLUMN IF NOT EXISTS slug VARCHAR(255);
+ALTER TABLE actors ADD COLUMN IF NOT EXISTS slug VARCHAR(255);
+
+-- Уже существующие записи: название и год фильма, имя актёра
+UPDATE films
+SET slug = COALESCE(NULLIF(trim(both '-' from regexp_replace(lower(title || ' ' || release_year), '[^[:alnum:]]+', '-', 'g')), ''), 'movie')
+WHERE slug IS NULL;
+UPDATE actors
+SET slug = COALESCE(NULLIF(trim(both '-' from regexp_replace(lower(name), '[^[:alnum:]]+', '-', 'g')), ''), 'actor')
+WHERE slug IS NULL;
+
+-- Совпавшие slug получают суффикс с ID; первая по ID запись сохраняет slug без суффикса
+UPDATE films f
+SET slug = f.slug || '-' || f.id
+WHERE EXISTS (SELECT 1 FROM films o WHERE o.slug = f.slug AND o.id < f.id);
+UPDATE actors a
+SET slug = a.slug || '-' || a.id
+WHERE EXISTS (SELECT 1 FROM actors o WHERE o.slug = a.slug AND o.id < a.id);
+
+ALTER TABLE films ALTER COLUMN slug SET NOT NULL;
+ALTER TABLE actors ALTER COLUMN slug SET NOT NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_films_slug ON films (slug);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_actors_slug ON actors (slug);
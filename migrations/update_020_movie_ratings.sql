@@ -0,0 +1,18 @@
+-- Рейтинги фильмов по источникам (GET /api/movies/:id/ratings): внутренний, IMDb и Rotten Tomatoes.
+-- Каждый рейтинг хранится в шкале своего источника — баллы до 10 или проценты Rotten Tomatoes;
+-- отображаемый рейтинг сервис считает по весам источников из конфигурации.
+-- Строка internal повторяет films.rating и обновляется вместе с ним
+CREATE TABLE IF NOT EXISTS movie_ratings (
+    film_id INTEGER NOT NULL REFERENCES films(id) ON DELETE CASCADE,
+    source VARCHAR(32) NOT NULL CHECK (source IN ('internal', 'imdb', 'rotten_tomatoes')),
+    rating DOUBLE PRECISION NOT NULL CHECK (rating >= 0),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (film_id, source)
+);
+
+-- Текущий рейтинг уже существующих фильмов — их внутренний рейтинг
+INSERT INTO movie_ratings (film_id, source, rating)
+SELECT f.id, 'internal', f.rating
+FROM films f
+WHERE f.rating IS NOT NULL
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,3 @@
+-- Каталог по годам и десятилетиям (GET /api/movies/year/:year, /movies/decade/:decade).
+-- Индекс покрывает фильтр по диапазону лет, подсчёт и порядок страницы внутри года
+CREATE INDEX IF NOT EXISTS idx_films_release_year ON films (release_year, rating DESC, id);
@@ -0,0 +1,20 @@
+-- Свободные теги фильмов (oscar-winner, cult-classic), отдельно от жанров.
+-- Теги хранятся нормализованными: в нижнем регистре, слова через дефис
+CREATE TABLE IF NOT EXISTS tags (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(50) NOT NULL UNIQUE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS movie_tags (
+    film_id INTEGER NOT NULL REFERENCES films(id) ON DELETE CASCADE,
+    tag_id INTEGER NOT NULL REFERENCES tags(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (film_id, tag_id)
+);
+
+-- Фильмы по тегу (GET /api/tags/:tag/movies) и число фильмов тега в подсказках
+CREATE INDEX IF NOT EXISTS idx_movie_tags_tag ON movie_tags (tag_id, film_id);
+
+-- Подсказки тегов по началу названия (GET /api/tags/suggest)
+CREATE INDEX IF NOT EXISTS idx_tags_name_prefix ON tags (name text_pattern_ops);
@@ -0,0 +1,7 @@
+-- Продолжительность фильма в минутах. 0 означает, что она неизвестна; диапазон проверяется
+-- и в API, и ограничением таблицы
+ALTER TABLE films ADD COLUMN IF NOT EXISTS runtime_minutes INTEGER NOT NULL DEFAULT 0
+    CHECK (runtime_minutes >= 0 AND runtime_minutes < 1000);
+
+-- Фильтр поиска /movies/search?runtime_min=...&runtime_max=... и сортировка по продолжительности
+CREATE INDEX IF NOT EXISTS idx_films_runtime_minutes ON films (runtime_minutes);
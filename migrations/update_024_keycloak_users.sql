@@ -0,0 +1,6 @@
+-- Связь локальной записи пользователя с пользователем Keycloak (см. service.UserSync).
+-- У записей, созданных синхронизацией, пустой password_hash: вход только через Keycloak
+ALTER TABLE users ADD COLUMN IF NOT EXISTS keycloak_id VARCHAR(36);
+
+-- Одна локальная запись на пользователя Keycloak; у локальных учётных записей keycloak_id пустой
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_keycloak_id ON users (keycloak_id);
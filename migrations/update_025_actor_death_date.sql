@@ -0,0 +1,5 @@
+-- Дата смерти актёра; NULL — актёр жив или дата неизвестна
+ALTER TABLE actors ADD COLUMN IF NOT EXISTS death_date DATE;
+
+-- Фильтр ?living=true отбирает актёров без даты смерти
+CREATE INDEX IF NOT EXISTS idx_actors_living ON actors (name) WHERE death_date IS NULL;
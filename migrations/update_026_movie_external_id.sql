@@ -0,0 +1,6 @@
+-- Внешний ID фильма в каталоге-источнике, например IMDb ID tt0133093. По нему
+-- PUT /movies/external/:externalId находит фильм при повторной синхронизации
+ALTER TABLE films ADD COLUMN IF NOT EXISTS external_id VARCHAR(64);
+
+-- У фильмов, созданных вручную, внешнего ID нет: NULL уникальности не нарушает
+CREATE UNIQUE INDEX IF NOT EXISTS idx_films_external_id ON films (external_id);
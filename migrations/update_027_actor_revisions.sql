@@ -0,0 +1,23 @@
+-- История изменений актёров (GET /api/actors/:id/history). В отличие от movie_revisions каждая
+-- запись хранит профиль актёра целиком, а не только изменённые поля: так версию не нужно
+-- собирать из предыдущих. Внешнего ключа нет: история переживает удаление актёра.
+CREATE TABLE IF NOT EXISTS actor_revisions (
+    id SERIAL PRIMARY KEY,
+    actor_id INTEGER NOT NULL,
+    changed_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    data JSONB NOT NULL,
+    deleted BOOLEAN NOT NULL DEFAULT FALSE
+);
+
+CREATE INDEX IF NOT EXISTS idx_actor_revisions_actor_id ON actor_revisions(actor_id, changed_at);
+
+-- Текущий профиль уже существующих актёров — первая версия их истории
+INSERT INTO actor_revisions (actor_id, data)
+SELECT a.id, jsonb_strip_nulls(jsonb_build_object(
+    'name', a.name,
+    'gender', a.gender,
+    'birth_date', to_char(a.birth_date, 'YYYY-MM-DD"T00:00:00Z"'),
+    'death_date', to_char(a.death_date, 'YYYY-MM-DD"T00:00:00Z"')
+))
+FROM actors a
+WHERE NOT EXISTS (SELECT 1 FROM actor_revisions r WHERE r.actor_id = a.id);
@@ -0,0 +1,6 @@
+-- Slug слитого фильма: по нему GET /api/movies/slug/:slug находит фильм, в который слит дубликат,
+-- как movie_merges.old_id делает это для ID. У слияний до этой миграции slug не сохранился: NULL
+ALTER TABLE movie_merges ADD COLUMN IF NOT EXISTS old_slug VARCHAR(255);
+
+-- Освободившийся slug может достаться новому фильму и быть слит ещё раз, поэтому индекс не уникальный
+CREATE INDEX IF NOT EXISTS idx_movie_merges_old_slug ON movie_merges(old_slug);